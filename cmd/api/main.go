@@ -5,37 +5,57 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
-	"github.com/gofiber/fiber/v2/middleware/session"
 	"github.com/gofiber/template/html/v2"
 	"github.com/tmidb/tmidb-core/internal/config"
 
 	"github.com/tmidb/tmidb-core/internal/api/handlers"
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
 	"github.com/tmidb/tmidb-core/internal/api/routes"
 	"github.com/tmidb/tmidb-core/internal/database"
 	"github.com/tmidb/tmidb-core/internal/migration"
+	"github.com/tmidb/tmidb-core/internal/profiling"
+	"github.com/tmidb/tmidb-core/internal/ratelimit"
+	"github.com/tmidb/tmidb-core/internal/usage"
 )
 
 func main() {
 	log.Println("🌐 Starting tmiDB API Server...")
 
 	// 설정 로드
-	cfg, err := config.Load()
+	cfg, err := config.LoadArgs(os.Args[1:])
 	if err != nil {
 		log.Fatalf("❌ Failed to load config: %v", err)
 	}
 
+	// 프로파일링 엔드포인트 (슈퍼바이저가 TMIDB_PPROF_ADDR를 넘긴 경우에만 열림)
+	if pprofServer := profiling.StartFromEnv("api"); pprofServer != nil {
+		defer pprofServer.Stop()
+	}
+
 	// 데이터베이스 연결 초기화
 	if err := database.InitDatabase(cfg); err != nil {
 		log.Fatalf("❌ Failed to initialize database: %v", err)
 	}
 	defer database.Close()
 
+	// 읽기 복제본 연결 (DB_REPLICA_HOSTS, 조회와 내보내기를 복제본으로 보냄)
+	if err := database.InitReplicas(cfg); err != nil {
+		log.Fatalf("❌ Failed to initialize read replicas: %v", err)
+	}
+	if len(cfg.DatabaseReplicaURLs) > 0 {
+		log.Printf("📚 읽기 복제본 %d개 설정", len(cfg.DatabaseReplicaURLs))
+	}
+
+	// API 토큰 암호화 키 설정
+	if err := database.InitCrypto(cfg.EncryptionKey); err != nil {
+		log.Fatalf("❌ Failed to initialize token encryption: %v", err)
+	}
+
 	// 스키마 초기화 (API 서버에서만 수행)
 	if err := database.InitializeSchema(); err != nil {
 		log.Fatalf("❌ Failed to initialize schema: %v", err)
@@ -43,9 +63,35 @@ func main() {
 	log.Println("🗃️ 데이터베이스 스키마 초기화 완료")
 
 	// 캐시 시스템 초기화
-	handlers.InitDataCache()
+	if err := handlers.InitDataCache(cfg); err != nil {
+		log.Printf("⚠️ %s 캐시 열기 실패, 메모리 캐시 사용: %v", cfg.CacheBackend, err)
+	}
 	log.Println("💾 데이터 캐시 시스템 초기화 완료")
 
+	// 첨부 파일 저장소 초기화
+	handlers.InitFileStorage(cfg)
+	log.Printf("📎 첨부 파일 저장소: %s", cfg.SeaweedFSFilerURL)
+
+	// GraphQL 엔드포인트 (GRAPHQL_ENABLED=true일 때만 등록)
+	handlers.InitGraphQL(cfg)
+	if cfg.GraphQLEnabled {
+		log.Println("🧬 GraphQL 엔드포인트 활성화: /api/graphql")
+	}
+
+	// SQL 콘솔 (SQL_CONSOLE_ENABLED=true일 때만 등록)
+	handlers.InitSQLConsole(cfg)
+	if cfg.SQLConsoleEnabled {
+		log.Printf("🧮 SQL 콘솔 활성화: /api/v1/admin/sql (제한 시간 %s, 최대 %d행)", cfg.SQLConsoleTimeout, cfg.SQLConsoleMaxRows)
+	}
+
+	// 다른 컴포넌트의 쓰기에 맞춰 캐시 무효화 (CDC 이벤트 구독)
+	if nc, err := handlers.StartCacheInvalidation(cfg.NatsURL); err != nil {
+		log.Printf("⚠️ CDC 캐시 무효화 비활성화: %v", err)
+	} else {
+		defer nc.Close()
+		log.Println("🔔 CDC 캐시 무효화 구독 시작")
+	}
+
 	// 마이그레이션 시스템 초기화
 	migrationManager := migration.NewMigrationManager(database.GetDB())
 	if err := migrationManager.InitializeMigrationTable(); err != nil {
@@ -53,23 +99,64 @@ func main() {
 	}
 	log.Println("🔧 마이그레이션 시스템 초기화 완료")
 
-	// 세션 스토어 초기화
-	sessionStore := session.New(session.Config{
-		KeyLookup:      "cookie:session_id",
-		CookieDomain:   "",
-		CookiePath:     "/",
-		CookieSecure:   false,
-		CookieHTTPOnly: true,
-		CookieSameSite: "Lax",
-		Expiration:     24 * time.Hour,
-	})
+	// 파일 마이그레이션 동기화 및 실행 (적용된 파일이 수정되었거나 실행이 실패하면 시작하지 않음)
+	applied, err := migrationManager.RunFileMigrations(context.Background(), cfg.MigrationsDir)
+	if err != nil {
+		log.Fatalf("❌ File migrations failed: %v", err)
+	}
+	if len(applied) > 0 {
+		log.Printf("🔧 파일 마이그레이션 %d개 적용: %s", len(applied), strings.Join(applied, ", "))
+	}
+
+	// 만료된 API 토큰 비활성화 (1분 간격)
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	database.StartTokenExpiryJob(jobCtx, database.GetDB(), time.Minute)
+
+	// 레이트 리밋과 일일 수집 할당량 (NATS KV를 쓰면 여러 인스턴스가 카운터를 공유)
+	limitStore, err := ratelimit.OpenStore(cfg)
+	if err != nil {
+		log.Printf("⚠️ 레이트 리밋 저장소 열기 실패, 메모리 저장소 사용: %v", err)
+		limitStore = ratelimit.NewMemoryStore()
+	}
+	middleware.InitRateLimiting(ratelimit.New(cfg, limitStore))
+
+	// 조직별 API 요청 수와 수집량 기록 (사용량 보고용)
+	usageRecorder := usage.NewRecorder(database.GetDB())
+	usageRecorder.Start(jobCtx, usage.FlushInterval)
+	middleware.InitUsageAccounting(usageRecorder)
+
+	// 데이터 API 필터가 거르는 JSONB 경로 기록 (tmidb-cli db indexes advise)
+	filterRecorder := usage.NewFilterRecorder(database.GetDB())
+	filterRecorder.Start(jobCtx, usage.FlushInterval)
+	handlers.InitFilterUsage(filterRecorder)
+
+	// SLOW_QUERY_MS보다 오래 걸린 쿼리 기록 (GET /api/v1/admin/slow-queries)
+	database.StartSlowQueryLog(jobCtx, database.GetDB(), cfg.SlowQueryThreshold, cfg.SlowQueryParams, "api")
+
+	// 같은 event_id나 target_id+ts+payload는 INGEST_DEDUP_WINDOW_HOURS 동안 한 번만 저장
+	database.StartIngestDedup(jobCtx, database.GetDB(), cfg.IngestDedupWindow)
+
+	// SOFT_DELETE_RETENTION_DAYS가 있으면 삭제한 타겟과 문서를 휴지통에 두었다가 기간이 지나면 영구 삭제
+	handlers.StartRecycleBin(jobCtx, cfg.SoftDeleteRetention)
+
+	// OIDC SSO 로그인 (OIDC_ISSUER_URL과 OIDC_CLIENT_ID가 있으면 켜짐)
+	handlers.InitSSO(cfg)
+	if handlers.SSOEnabled() {
+		log.Printf("🔑 OIDC SSO 로그인 활성화: %s", cfg.OIDCIssuerURL)
+	}
+
+	// 세션 스토어 초기화 (SESSION_STORE=postgres이면 재시작 후에도 유지되고 인스턴스 간 공유)
+	sessionStore := middleware.NewSessionStore(cfg, database.GetDB())
+	log.Printf("🍪 웹 콘솔 세션 저장소: %s", cfg.SessionStore)
 
 	// 웹 콘솔 템플릿 엔진 초기화
 	engine := html.New("/app/cmd/api/views", ".html")
 
 	// Fiber 앱 생성
 	app := fiber.New(fiber.Config{
-		Views: engine,
+		Views:     engine,
+		BodyLimit: handlers.MaxUploadBodySize(cfg),
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			// 기본 500 에러
 			code := fiber.StatusInternalServerError
@@ -103,15 +190,10 @@ func main() {
 	})
 
 	// 미들웨어 설정
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization,X-Request-ID",
-	}))
+	app.Use(middleware.CORS(cfg))
 
-	app.Use(logger.New(logger.Config{
-		Format: "[${time}] ${status} - ${method} ${path} - ${latency}\n",
-	}))
+	// 요청 ID 부여 및 구조화 접근 로그 (X-Request-ID)
+	app.Use(middleware.RequestLogger())
 
 	// 세션 스토어를 전역으로 설정
 	app.Use(func(c *fiber.Ctx) error {
@@ -119,14 +201,14 @@ func main() {
 		return c.Next()
 	})
 
+	// 웹 콘솔 CSRF 보호 (세션 쿠키 경로만, 토큰 인증 API 경로는 제외)
+	app.Use(middleware.CSRFProtection(cfg))
+
 	// 새로운 라우팅 시스템 사용
 	routes.SetupRoutes(app, sessionStore)
 
 	// 서버 시작
-	port := os.Getenv("API_PORT")
-	if port == "" {
-		port = "8020"
-	}
+	port := cfg.APIPort
 
 	go func() {
 		log.Printf("🌐 API Server listening on :%s", port)
@@ -138,6 +220,8 @@ func main() {
 	// 종료 시그널 대기
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	// 슈퍼바이저가 재시작되는 동안 stdout/stderr 파이프가 닫혀도 종료되지 않도록 (재시작한 슈퍼바이저가 이어서 관리)
+	signal.Ignore(syscall.SIGPIPE)
 	<-quit
 
 	log.Println("🛑 Shutting down API Server...")
@@ -149,6 +233,9 @@ func main() {
 	if err := app.ShutdownWithContext(ctx); err != nil {
 		log.Printf("❌ Server forced to shutdown: %v", err)
 	}
+	if err := usageRecorder.Flush(); err != nil {
+		log.Printf("⚠️ 사용량 기록 실패: %v", err)
+	}
 
 	log.Println("✅ API Server stopped")
 }
@@ -0,0 +1,49 @@
+// 웹 콘솔 CSRF 토큰 전달
+// 상태를 바꾸는 같은 출처 fetch 요청에는 X-CSRF-Token 헤더를, 폼 제출에는 _csrf 필드를 붙입니다.
+(function () {
+  if (window.__tmidbCsrf) return;
+  window.__tmidbCsrf = true;
+
+  function csrfToken() {
+    const match = document.cookie.match(/(?:^|;\s*)csrf_token=([^;]+)/);
+    return match ? decodeURIComponent(match[1]) : '';
+  }
+
+  function isSafe(method) {
+    return ['GET', 'HEAD', 'OPTIONS', 'TRACE'].includes((method || 'GET').toUpperCase());
+  }
+
+  function isSameOrigin(url) {
+    try {
+      return new URL(url, window.location.href).origin === window.location.origin;
+    } catch (e) {
+      return false;
+    }
+  }
+
+  const originalFetch = window.fetch;
+  window.fetch = function (input, init) {
+    init = init || {};
+    const url = typeof input === 'string' ? input : input.url;
+    const method = init.method || (typeof input === 'string' ? 'GET' : input.method);
+    if (!isSafe(method) && isSameOrigin(url)) {
+      const headers = new Headers(init.headers || (typeof input === 'string' ? undefined : input.headers));
+      headers.set('X-CSRF-Token', csrfToken());
+      init = Object.assign({}, init, { headers: headers });
+    }
+    return originalFetch.call(this, input, init);
+  };
+
+  document.addEventListener('submit', function (event) {
+    const form = event.target;
+    if (!(form instanceof HTMLFormElement) || isSafe(form.method) || !isSameOrigin(form.action)) return;
+    let field = form.querySelector('input[name="_csrf"]');
+    if (!field) {
+      field = document.createElement('input');
+      field.type = 'hidden';
+      field.name = '_csrf';
+      form.appendChild(field);
+    }
+    field.value = csrfToken();
+  }, true);
+})();
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tmidb/tmidb-core/internal/alerting"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+
+	"github.com/spf13/cobra"
+)
+
+// 알림 명령어
+var alertCmd = &cobra.Command{
+	Use:   "alert",
+	Short: "Manage alert rules and notification channels",
+	Long:  "List active alerts and manage the rules and channels used to notify about them",
+}
+
+var alertListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List active alerts",
+	Run: func(cmd *cobra.Command, args []string) {
+		var alerts []alerting.Alert
+		alertRequest(ipc.MessageTypeAlertList, nil, &alerts)
+
+		if len(alerts) == 0 {
+			fmt.Println("✅ No active alerts")
+			return
+		}
+
+		fmt.Printf("🚨 Active Alerts (%d):\n\n", len(alerts))
+		fmt.Printf("%-20s %-15s %-10s %-10s %-20s %s\n", "RULE", "COMPONENT", "SEVERITY", "STATUS", "SINCE", "MESSAGE")
+		fmt.Println(strings.Repeat("-", 100))
+		for _, alert := range alerts {
+			component := alert.Component
+			if component == "" {
+				component = "-"
+			}
+			fmt.Printf("%-20s %-15s %-10s %-10s %-20s %s\n",
+				alert.Rule, component, alert.Severity, alert.Status,
+				alert.Since.Format("2006-01-02 15:04:05"), alert.Message)
+		}
+	},
+}
+
+var alertRuleCmd = &cobra.Command{
+	Use:   "rule",
+	Short: "Manage alert rules",
+}
+
+var alertRuleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List alert rules",
+	Run: func(cmd *cobra.Command, args []string) {
+		var rules []alerting.Rule
+		alertRequest(ipc.MessageTypeAlertRuleList, nil, &rules)
+
+		if len(rules) == 0 {
+			fmt.Println("📋 No alert rules configured")
+			return
+		}
+
+		fmt.Printf("📋 Alert Rules (%d):\n\n", len(rules))
+		fmt.Printf("%-20s %-10s %-10s %-40s %s\n", "NAME", "SEVERITY", "REPEAT", "CONDITION", "CHANNELS")
+		fmt.Println(strings.Repeat("-", 100))
+		for _, rule := range rules {
+			name := rule.Name
+			if rule.Disabled {
+				name += " (off)"
+			}
+			channels := "all"
+			if len(rule.Channels) > 0 {
+				channels = strings.Join(rule.Channels, ",")
+			}
+			fmt.Printf("%-20s %-10s %-10s %-40s %s\n", name, rule.Severity, rule.Repeat, rule.Describe(), channels)
+		}
+	},
+}
+
+var alertRuleAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add or replace an alert rule",
+	Long: `Add an alert rule, replacing any existing rule with the same name.
+
+Metrics: cpu, memory, disk (host usage %), process_cpu (%), process_memory (bytes),
+down (component not running) and event (supervisor event, e.g. backup.failed).
+
+Examples:
+  # CPU above 90% for 5 minutes
+  tmidb-cli alert rule add high-cpu --metric cpu --op '>' --threshold 90 --for 5m
+
+  # Any component down for 30 seconds
+  tmidb-cli alert rule add component-down --metric down --for 30s --severity critical
+
+  # Disk usage above 85%, notify only the ops channel
+  tmidb-cli alert rule add disk-full --metric disk --op '>' --threshold 85 --channels ops
+
+  # Failed backups
+  tmidb-cli alert rule add backup-failed --metric event --event backup.failed`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		metric, _ := cmd.Flags().GetString("metric")
+		operator, _ := cmd.Flags().GetString("op")
+		threshold, _ := cmd.Flags().GetFloat64("threshold")
+		forDuration, _ := cmd.Flags().GetDuration("for")
+		repeat, _ := cmd.Flags().GetDuration("repeat")
+		component, _ := cmd.Flags().GetString("component")
+		event, _ := cmd.Flags().GetString("event")
+		severity, _ := cmd.Flags().GetString("severity")
+		channels, _ := cmd.Flags().GetStringSlice("channels")
+		disabled, _ := cmd.Flags().GetBool("disabled")
+
+		rule := alerting.Rule{
+			Name:      args[0],
+			Metric:    alerting.Metric(metric),
+			Component: component,
+			Operator:  operator,
+			Threshold: threshold,
+			Event:     event,
+			For:       forDuration,
+			Repeat:    repeat,
+			Severity:  alerting.Severity(severity),
+			Channels:  channels,
+			Disabled:  disabled,
+		}
+		if err := rule.Validate(); err != nil {
+			fmt.Printf("❌ Invalid rule: %v\n", err)
+			exit(1)
+		}
+
+		alertRequest(ipc.MessageTypeAlertRuleSet, map[string]interface{}{"rule": rule}, nil)
+		fmt.Printf("✅ Alert rule %s saved: %s\n", rule.Name, rule.Describe())
+	},
+}
+
+var alertRuleRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove an alert rule",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		alertRequest(ipc.MessageTypeAlertRuleDelete, map[string]interface{}{"name": args[0]}, nil)
+		fmt.Printf("✅ Alert rule %s removed\n", args[0])
+	},
+}
+
+var alertChannelCmd = &cobra.Command{
+	Use:   "channel",
+	Short: "Manage notification channels",
+}
+
+var alertChannelListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List notification channels",
+	Run: func(cmd *cobra.Command, args []string) {
+		var channels []alerting.Channel
+		alertRequest(ipc.MessageTypeAlertChannelList, nil, &channels)
+
+		if len(channels) == 0 {
+			fmt.Println("📋 No notification channels configured")
+			return
+		}
+
+		fmt.Printf("📋 Notification Channels (%d):\n\n", len(channels))
+		fmt.Printf("%-20s %-10s %s\n", "NAME", "TYPE", "TARGET")
+		fmt.Println(strings.Repeat("-", 80))
+		for _, channel := range channels {
+			target := channel.URL
+			if channel.Type == alerting.ChannelEmail {
+				target = fmt.Sprintf("%s via %s:%d", strings.Join(channel.To, ","), channel.SMTPHost, channel.SMTPPort)
+			}
+			fmt.Printf("%-20s %-10s %s\n", channel.Name, channel.Type, target)
+		}
+	},
+}
+
+var alertChannelAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add or replace a notification channel",
+	Long: `Add a notification channel, replacing any existing channel with the same name.
+
+Examples:
+  # Generic webhook (alerts are POSTed as JSON)
+  tmidb-cli alert channel add ops-hook --type webhook --url https://example.com/hooks/tmidb
+
+  # Slack-compatible incoming webhook
+  tmidb-cli alert channel add ops --type slack --url https://hooks.slack.com/services/...
+
+  # Email via SMTP
+  tmidb-cli alert channel add oncall --type email --smtp-host smtp.example.com --smtp-port 587 \
+    --username alerts --password-file /etc/tmidb/smtp-pass --from tmidb@example.com --to oncall@example.com`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		channelType, _ := cmd.Flags().GetString("type")
+		url, _ := cmd.Flags().GetString("url")
+		smtpHost, _ := cmd.Flags().GetString("smtp-host")
+		smtpPort, _ := cmd.Flags().GetInt("smtp-port")
+		username, _ := cmd.Flags().GetString("username")
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetStringSlice("to")
+
+		password, err := readPasswordFlag(cmd)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+
+		channel := alerting.Channel{
+			Name:     args[0],
+			Type:     alerting.ChannelType(channelType),
+			URL:      url,
+			SMTPHost: smtpHost,
+			SMTPPort: smtpPort,
+			Username: username,
+			Password: password,
+			From:     from,
+			To:       to,
+		}
+		if err := channel.Validate(); err != nil {
+			fmt.Printf("❌ Invalid channel: %v\n", err)
+			exit(1)
+		}
+
+		alertRequest(ipc.MessageTypeAlertChannelSet, map[string]interface{}{"channel": channel}, nil)
+		fmt.Printf("✅ Notification channel %s saved\n", channel.Name)
+	},
+}
+
+var alertChannelRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a notification channel",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		alertRequest(ipc.MessageTypeAlertChannelDelete, map[string]interface{}{"name": args[0]}, nil)
+		fmt.Printf("✅ Notification channel %s removed\n", args[0])
+	},
+}
+
+var alertChannelTestCmd = &cobra.Command{
+	Use:   "test <name>",
+	Short: "Send a test notification",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Printf("📨 Sending test notification to %s...\n", args[0])
+		alertRequest(ipc.MessageTypeAlertTest, map[string]interface{}{"channel": args[0]}, nil)
+		fmt.Println("✅ Test notification sent")
+	},
+}
+
+// alertRequest 요청을 보내고 실패 시 종료, out이 주어지면 응답 데이터를 디코딩
+func alertRequest(msgType ipc.MessageType, data map[string]interface{}, out interface{}) {
+	resp, err := client.SendMessage(msgType, data)
+	if err != nil {
+		fmt.Printf("❌ Failed to communicate with supervisor: %v\n", err)
+		exit(1)
+	}
+
+	if !resp.Success {
+		fmt.Printf("❌ Error: %s\n", resp.Error)
+		exit(1)
+	}
+
+	if out == nil {
+		return
+	}
+	raw, _ := json.Marshal(resp.Data)
+	if err := json.Unmarshal(raw, out); err != nil {
+		fmt.Printf("❌ Failed to parse response: %v\n", err)
+		exit(1)
+	}
+}
+
+// readPasswordFlag --password-file 플래그가 지정된 경우 파일에서 비밀번호를 읽음
+func readPasswordFlag(cmd *cobra.Command) (string, error) {
+	path, _ := cmd.Flags().GetString("password-file")
+	if path == "" {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password file: %v", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func init() {
+	alertRuleAddCmd.Flags().String("metric", "", "Metric to watch (cpu, memory, disk, process_cpu, process_memory, down, event)")
+	alertRuleAddCmd.Flags().String("op", ">", "Comparison operator (>, >=, <, <=, ==, !=)")
+	alertRuleAddCmd.Flags().Float64("threshold", 0, "Threshold value")
+	alertRuleAddCmd.Flags().Duration("for", 0, "How long the condition must hold before firing")
+	alertRuleAddCmd.Flags().Duration("repeat", 0, "Minimum interval between repeated notifications (default 1h)")
+	alertRuleAddCmd.Flags().String("component", "", "Only watch this component (default: all)")
+	alertRuleAddCmd.RegisterFlagCompletionFunc("component", completeComponentFlag)
+	alertRuleAddCmd.Flags().String("event", "", "Event type for event rules (e.g. backup.failed, process.*)")
+	alertRuleAddCmd.Flags().String("severity", "warning", "Severity (info, warning, critical)")
+	alertRuleAddCmd.Flags().StringSlice("channels", nil, "Channels to notify (default: all)")
+	alertRuleAddCmd.Flags().Bool("disabled", false, "Save the rule without enabling it")
+	alertRuleAddCmd.MarkFlagRequired("metric")
+
+	alertChannelAddCmd.Flags().String("type", "", "Channel type (webhook, slack, email)")
+	alertChannelAddCmd.Flags().String("url", "", "Webhook URL (webhook, slack)")
+	alertChannelAddCmd.Flags().String("smtp-host", "", "SMTP server host (email)")
+	alertChannelAddCmd.Flags().Int("smtp-port", 25, "SMTP server port (email)")
+	alertChannelAddCmd.Flags().String("username", "", "SMTP username (email)")
+	alertChannelAddCmd.Flags().String("password-file", "", "File containing the SMTP password (email)")
+	alertChannelAddCmd.Flags().String("from", "", "Sender address (email)")
+	alertChannelAddCmd.Flags().StringSlice("to", nil, "Recipient addresses (email)")
+	alertChannelAddCmd.MarkFlagRequired("type")
+
+	alertRuleCmd.AddCommand(alertRuleListCmd)
+	alertRuleCmd.AddCommand(alertRuleAddCmd)
+	alertRuleCmd.AddCommand(alertRuleRemoveCmd)
+
+	alertChannelCmd.AddCommand(alertChannelListCmd)
+	alertChannelCmd.AddCommand(alertChannelAddCmd)
+	alertChannelCmd.AddCommand(alertChannelRemoveCmd)
+	alertChannelCmd.AddCommand(alertChannelTestCmd)
+
+	alertCmd.AddCommand(alertListCmd)
+	alertCmd.AddCommand(alertRuleCmd)
+	alertCmd.AddCommand(alertChannelCmd)
+
+	rootCmd.AddCommand(alertCmd)
+}
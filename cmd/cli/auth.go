@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+
+	"github.com/spf13/cobra"
+)
+
+// 인증 명령어
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect IPC authentication",
+	Long: `Show the role the supervisor grants this client and generate auth tokens.
+
+Clients are authorized by the uid/gid of the connecting process (root and the
+supervisor user are always admin). A token from --token-file or $TMIDB_IPC_TOKEN
+can grant a higher role.`,
+}
+
+var authWhoAmICmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the identity and role seen by the supervisor",
+	Run: func(cmd *cobra.Command, args []string) {
+		resp, err := client.SendMessage(ipc.MessageTypeAuthWhoAmI, nil)
+		if err != nil {
+			fmt.Printf("❌ Failed to communicate with supervisor: %v\n", err)
+			exit(1)
+		}
+		if !resp.Success {
+			fmt.Printf("❌ Error: %s\n", resp.Error)
+			exit(1)
+		}
+
+		raw, _ := json.Marshal(resp.Data)
+		var info struct {
+			Peer        *ipc.PeerCredentials `json:"peer"`
+			Role        ipc.Role             `json:"role"`
+			Token       string               `json:"token"`
+			AuthEnabled bool                 `json:"auth_enabled"`
+		}
+		if err := json.Unmarshal(raw, &info); err != nil {
+			fmt.Printf("❌ Failed to parse response: %v\n", err)
+			exit(1)
+		}
+
+		fmt.Println("🔐 IPC Identity:")
+		if info.Peer != nil {
+			fmt.Printf("  UID/GID: %d/%d (pid %d)\n", info.Peer.UID, info.Peer.GID, info.Peer.PID)
+		} else {
+			fmt.Println("  UID/GID: unknown")
+		}
+		if info.Token != "" {
+			fmt.Printf("  Token:   %s\n", info.Token)
+		}
+		fmt.Printf("  Role:    %s\n", info.Role)
+		if !info.AuthEnabled {
+			fmt.Println("  ⚠️ Authorization is disabled on the supervisor")
+		}
+	},
+}
+
+var authTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage IPC auth tokens",
+}
+
+var authTokenGenerateCmd = &cobra.Command{
+	Use:   "generate <name>",
+	Short: "Generate a new auth token",
+	Long: `Generate a random token and print the entry to add under ipc_auth.tokens in
+the supervisor config. Only the SHA-256 hash is stored in the config; keep the
+token itself in a file readable by the client and pass it with --token-file.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		role, _ := cmd.Flags().GetString("role")
+		if role != string(ipc.RoleReadOnly) && role != string(ipc.RoleAdmin) {
+			fmt.Printf("❌ Invalid role %q (use readonly or admin)\n", role)
+			exit(1)
+		}
+
+		token, err := ipc.GenerateToken()
+		if err != nil {
+			fmt.Printf("❌ Failed to generate token: %v\n", err)
+			exit(1)
+		}
+
+		entry, _ := json.MarshalIndent(ipc.AuthToken{
+			Name:   args[0],
+			SHA256: ipc.HashToken(token),
+			Role:   ipc.Role(role),
+		}, "", "  ")
+
+		fmt.Printf("🔑 Token: %s\n\n", token)
+		fmt.Println("Add this entry to ipc_auth.tokens in the supervisor config, then run 'tmidb-cli config reload':")
+		fmt.Println(string(entry))
+	},
+}
+
+// readTokenFlag --token-file 플래그 또는 TMIDB_IPC_TOKEN 환경변수에서 토큰을 읽음
+func readTokenFlag(cmd *cobra.Command) (string, error) {
+	path, _ := cmd.Flags().GetString("token-file")
+	if path == "" {
+		return os.Getenv("TMIDB_IPC_TOKEN"), nil
+	}
+	return readTokenFile(path)
+}
+
+// readTokenFile 파일에서 IPC 토큰을 읽음
+func readTokenFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %v", err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", path)
+	}
+	return token, nil
+}
+
+func init() {
+	authTokenGenerateCmd.Flags().String("role", string(ipc.RoleReadOnly), "Role granted by the token (readonly, admin)")
+
+	authTokenCmd.AddCommand(authTokenGenerateCmd)
+
+	authCmd.AddCommand(authWhoAmICmd)
+	authCmd.AddCommand(authTokenCmd)
+
+	rootCmd.AddCommand(authCmd)
+}
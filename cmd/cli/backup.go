@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -29,7 +30,10 @@ Examples:
   tmidb-cli backup create production-backup
   
   # Create backup with specific components
-  tmidb-cli backup create --components=database,config`,
+  tmidb-cli backup create --components=database,config
+  
+  # Create an encrypted backup (.tar.gz.enc)
+  tmidb-cli backup create --encrypt --passphrase-file=/etc/tmidb/backup.key`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		name := ""
@@ -43,6 +47,17 @@ Examples:
 		components, _ := cmd.Flags().GetStringSlice("components")
 		compress, _ := cmd.Flags().GetBool("compress")
 		outputDir, _ := cmd.Flags().GetString("output")
+		encrypt, _ := cmd.Flags().GetBool("encrypt")
+
+		passphrase, err := readPassphraseFlag(cmd)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		if encrypt && passphrase == "" {
+			fmt.Println("❌ --encrypt requires --passphrase-file")
+			return
+		}
 
 		fmt.Printf("🔐 Creating backup: %s\n", name)
 		fmt.Printf("   Components: %s\n", strings.Join(components, ", "))
@@ -50,6 +65,9 @@ Examples:
 		if compress {
 			fmt.Println("   Compression: enabled")
 		}
+		if encrypt {
+			fmt.Println("   Encryption: AES-256-GCM")
+		}
 
 		// 백업 시작 전 확인
 		if !cmd.Flag("yes").Changed {
@@ -68,6 +86,8 @@ Examples:
 			"components": components,
 			"compress":   compress,
 			"output_dir": outputDir,
+			"encrypt":    encrypt,
+			"passphrase": passphrase,
 		})
 		if err != nil {
 			fmt.Printf("❌ Failed to create backup: %v\n", err)
@@ -84,7 +104,7 @@ Examples:
 			backupID := backupInfo["id"].(string)
 
 			// 백업 진행 상황 모니터링
-			if err := monitorBackupProgress(backupID); err != nil {
+			if err := monitorBackupProgress(client, backupID); err != nil {
 				fmt.Printf("❌ Backup monitoring error: %v\n", err)
 				return
 			}
@@ -110,11 +130,35 @@ Examples:
   tmidb-cli backup restore /path/to/backup.tar.gz
   
   # Restore specific components
-  tmidb-cli backup restore backup-123 --components=database`,
+  tmidb-cli backup restore backup-123 --components=database
+  
+  # Restore an encrypted backup
+  tmidb-cli backup restore ./backups/backup.tar.gz.enc --passphrase-file=/etc/tmidb/backup.key
+
+  # Show what restoring one organization's category would replace, then restore it
+  tmidb-cli backup restore backup-123 --org acme --category sensors --preview
+  tmidb-cli backup restore backup-123 --org acme --category sensors`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		backup := args[0]
 		components, _ := cmd.Flags().GetStringSlice("components")
+		org, _ := cmd.Flags().GetString("org")
+		category, _ := cmd.Flags().GetString("category")
+
+		passphrase, err := readPassphraseFlag(cmd)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+
+		if category != "" && org == "" {
+			fmt.Println("❌ --category requires --org")
+			return
+		}
+		if org != "" {
+			restoreScoped(cmd, backup, passphrase, org, category)
+			return
+		}
 
 		fmt.Printf("🔓 Restoring from backup: %s\n", backup)
 
@@ -138,6 +182,7 @@ Examples:
 		resp, err := client.SendMessage(ipc.MessageTypeBackupRestore, map[string]interface{}{
 			"backup":     backup,
 			"components": components,
+			"passphrase": passphrase,
 		})
 		if err != nil {
 			fmt.Printf("❌ Failed to restore backup: %v\n", err)
@@ -153,7 +198,7 @@ Examples:
 		if restoreInfo, ok := resp.Data.(map[string]interface{}); ok {
 			restoreID := restoreInfo["id"].(string)
 
-			if err := monitorRestoreProgress(restoreID); err != nil {
+			if err := monitorRestoreProgress(client, restoreID); err != nil {
 				fmt.Printf("❌ Restore monitoring error: %v\n", err)
 				return
 			}
@@ -169,6 +214,106 @@ Examples:
 	},
 }
 
+// restoreScoped 조직/카테고리 단위 복원: 덮어쓸 행을 먼저 보여 주고 확인 후 복원
+func restoreScoped(cmd *cobra.Command, backup, passphrase, org, category string) {
+	formatter := getFormatter(cmd)
+	preview, _ := cmd.Flags().GetBool("preview")
+
+	// 미리 보기는 덤프 전체를 읽으므로 요청 제한 시간을 늘림
+	c, err := newCommandClient(cmd, deepVerifyTimeout)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+	defer c.Close()
+
+	request := map[string]interface{}{
+		"backup":     backup,
+		"passphrase": passphrase,
+		"org":        org,
+		"category":   category,
+		"preview":    true,
+	}
+	resp, err := c.SendMessage(ipc.MessageTypeBackupRestore, request)
+	if err != nil {
+		fmt.Printf("❌ Failed to preview restore: %v\n", err)
+		return
+	}
+	if !resp.Success {
+		fmt.Printf("❌ Error: %s\n", resp.Error)
+		return
+	}
+
+	if preview && formatter.Structured() {
+		formatter.Output(resp.Data)
+		return
+	}
+	if result, ok := resp.Data.(map[string]interface{}); ok {
+		printRestorePreview(result)
+	}
+	if preview {
+		return
+	}
+
+	fmt.Println("\n⚠️  WARNING: The rows marked 'replace' will be deleted and replaced by the backup's rows.")
+	fmt.Println("   Other organizations and categories are not touched.")
+	if !cmd.Flag("yes").Changed {
+		fmt.Print("\nAre you SURE you want to continue? (yes/no): ")
+		var response string
+		fmt.Scanln(&response)
+		if response != "yes" {
+			fmt.Println("❌ Restore cancelled")
+			return
+		}
+	}
+
+	request["preview"] = false
+	resp, err = c.SendMessage(ipc.MessageTypeBackupRestore, request)
+	if err != nil {
+		fmt.Printf("❌ Failed to restore backup: %v\n", err)
+		return
+	}
+	if !resp.Success {
+		fmt.Printf("❌ Error: %s\n", resp.Error)
+		return
+	}
+
+	if restoreInfo, ok := resp.Data.(map[string]interface{}); ok {
+		if err := monitorRestoreProgress(c, restoreInfo["id"].(string)); err != nil {
+			fmt.Printf("❌ Restore monitoring error: %v\n", err)
+			return
+		}
+		fmt.Println("\n✅ Restore completed successfully")
+	}
+}
+
+// printRestorePreview 백업의 행 수와 현재 덮어쓸 행 수를 테이블별로 표시
+func printRestorePreview(result map[string]interface{}) {
+	scope := fmt.Sprintf("organization %v (%v)", result["org"], result["org_id"])
+	if category, _ := result["category"].(string); category != "" {
+		scope += ", category " + category
+	}
+	fmt.Printf("\n🔎 Restore preview for %s: %v targets in the backup\n", scope, result["targets"])
+
+	fmt.Printf("\n%-28s %-12s %-12s %-12s\n", "TABLE", "BACKUP", "CURRENT", "ACTION")
+	fmt.Println(strings.Repeat("-", 66))
+	tables, _ := result["tables"].([]interface{})
+	for _, item := range tables {
+		table, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		current := "-"
+		if n, ok := table["current"]; ok {
+			current = fmt.Sprintf("%d", jsonInt(n))
+		}
+		fmt.Printf("%-28s %-12d %-12s %-12s\n", table["table"], jsonInt(table["backup"]), current, table["action"])
+	}
+	if msg, _ := result["current_error"].(string); msg != "" {
+		fmt.Printf("\n⚠️  Could not count the current rows: %s\n", msg)
+	}
+}
+
 var backupListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available backups",
@@ -194,8 +339,8 @@ var backupListCmd = &cobra.Command{
 				return
 			}
 
-			fmt.Printf("\n%-30s %-20s %-15s %-20s\n", "ID", "CREATED", "SIZE", "COMPONENTS")
-			fmt.Println(strings.Repeat("-", 85))
+			fmt.Printf("\n%-30s %-20s %-12s %-10s %-12s %-20s\n", "ID", "CREATED", "SIZE", "VERSION", "STATUS", "COMPONENTS")
+			fmt.Println(strings.Repeat("-", 110))
 
 			for _, backup := range backups {
 				if b, ok := backup.(map[string]interface{}); ok {
@@ -203,8 +348,13 @@ var backupListCmd = &cobra.Command{
 					created := b["created"].(string)
 					size := formatBytes(int64(b["size"].(float64)))
 					components := strings.Join(toStringSlice(b["components"].([]interface{})), ", ")
+					version, _ := b["source_version"].(string)
+					if version == "" {
+						version = "-"
+					}
+					status, _ := b["status"].(string)
 
-					fmt.Printf("%-30s %-20s %-15s %-20s\n", id, created, size, components)
+					fmt.Printf("%-30s %-20s %-12s %-10s %-12s %-20s\n", id, created, size, version, status, components)
 				}
 			}
 		}
@@ -251,15 +401,51 @@ var backupDeleteCmd = &cobra.Command{
 var backupVerifyCmd = &cobra.Command{
 	Use:   "verify <backup-id|path>",
 	Short: "Verify backup integrity",
-	Long:  "Check backup file integrity and contents",
-	Args:  cobra.ExactArgs(1),
+	Long: `Check backup file integrity and contents.
+
+By default only the archive structure is read. --deep also compares the file with
+its stored SHA-256 checksum, checks the header and completion trailer of the SQL
+dump, and compares the entries and table row counts with the manifest written at
+backup time. --test-restore (implies --deep) restores the dump into a throwaway
+database, compares the restored row counts and drops the database again.
+
+Examples:
+  tmidb-cli backup verify ./backups/nightly.tar.gz
+  tmidb-cli backup verify ./backups/nightly.tar.gz --deep
+  tmidb-cli backup verify ./backups/nightly.tar.gz --test-restore`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		backup := args[0]
+		formatter := getFormatter(cmd)
+		deep, _ := cmd.Flags().GetBool("deep")
+		testRestore, _ := cmd.Flags().GetBool("test-restore")
 
-		fmt.Printf("🔍 Verifying backup: %s\n", backup)
+		passphrase, err := readPassphraseFlag(cmd)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+
+		// deep 검증은 백업 전체를 읽고 복원까지 하므로 요청 제한 시간을 늘림
+		c := client
+		if deep || testRestore {
+			c, err = newCommandClient(cmd, deepVerifyTimeout)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				return
+			}
+			defer c.Close()
+		}
+
+		if !formatter.Structured() {
+			fmt.Printf("🔍 Verifying backup: %s\n", backup)
+		}
 
-		resp, err := client.SendMessage(ipc.MessageTypeBackupVerify, map[string]interface{}{
-			"backup": backup,
+		resp, err := c.SendMessage(ipc.MessageTypeBackupVerify, map[string]interface{}{
+			"backup":       backup,
+			"passphrase":   passphrase,
+			"deep":         deep,
+			"test_restore": testRestore,
 		})
 		if err != nil {
 			fmt.Printf("❌ Failed to verify backup: %v\n", err)
@@ -271,15 +457,34 @@ var backupVerifyCmd = &cobra.Command{
 			return
 		}
 
+		if formatter.Structured() {
+			formatter.Output(resp.Data)
+			return
+		}
+
 		// 검증 결과 표시
 		if result, ok := resp.Data.(map[string]interface{}); ok {
 			fmt.Println("\n📊 Verification Results:")
 			fmt.Printf("   Status: %s\n", result["status"])
 			fmt.Printf("   Integrity: %s\n", result["integrity"])
+			if encryption, ok := result["encryption"].(map[string]interface{}); ok {
+				fmt.Printf("   Encryption: %s (%s %s)\n", encryption["algorithm"], encryption["kdf"], encryption["kdf_params"])
+			}
+			if result["deep"] == true {
+				printDeepVerify(result)
+			}
 
 			if components, ok := result["components"].(map[string]interface{}); ok {
 				fmt.Println("\n   Components:")
 				for comp, status := range components {
+					if check, ok := status.(map[string]interface{}); ok {
+						icon := "✅"
+						if check["status"] != "valid" {
+							icon = "❌"
+						}
+						fmt.Printf("     %s %s: %v entries, %s\n", icon, comp, check["entries"], formatBytes(jsonInt(check["bytes"])))
+						continue
+					}
 					icon := "✅"
 					if status != "valid" {
 						icon = "❌"
@@ -288,6 +493,10 @@ var backupVerifyCmd = &cobra.Command{
 				}
 			}
 
+			if tables, ok := result["tables"].([]interface{}); ok && len(tables) > 0 {
+				printTableChecks(tables)
+			}
+
 			if errors, ok := result["errors"].([]interface{}); ok && len(errors) > 0 {
 				fmt.Println("\n   Errors:")
 				for _, err := range errors {
@@ -298,8 +507,79 @@ var backupVerifyCmd = &cobra.Command{
 	},
 }
 
+// deepVerifyTimeout bounds a deep verification, which reads the whole backup and may restore it
+const deepVerifyTimeout = 2 * time.Hour
+
+// printDeepVerify 체크섬, 덤프, 매니페스트, test restore 결과 표시
+func printDeepVerify(result map[string]interface{}) {
+	if checksum, ok := result["checksum"].(map[string]interface{}); ok {
+		switch checksum["status"] {
+		case "match":
+			fmt.Println("   Checksum: ✅ matches the stored SHA-256")
+		case "mismatch":
+			fmt.Printf("   Checksum: ❌ stored %v, file %v\n", checksum["expected"], checksum["actual"])
+		default:
+			fmt.Printf("   Checksum: ⚠️  no stored checksum (file %v)\n", checksum["actual"])
+		}
+	}
+
+	if db, ok := result["database"].(map[string]interface{}); ok {
+		state := "✅ complete"
+		if db["complete"] != true {
+			state = "❌ truncated"
+		}
+		fmt.Printf("   Database dump: %s, %s, %v tables (PostgreSQL %v, pg_dump %v)\n", state,
+			formatBytes(jsonInt(db["bytes"])), db["tables"], db["server_version"], db["pg_dump_version"])
+	}
+
+	if manifest, ok := result["manifest"].(map[string]interface{}); ok {
+		fmt.Printf("   Manifest: written %v by tmiDB %v\n", manifest["created"], manifest["tmidb_version"])
+	} else {
+		fmt.Println("   Manifest: none (backup predates manifests, counts not compared)")
+	}
+
+	if restore, ok := result["test_restore"].(map[string]interface{}); ok {
+		if restore["status"] == "passed" {
+			fmt.Printf("   Test restore: ✅ passed in %v (database %v dropped)\n", restore["duration"], restore["database"])
+		} else {
+			fmt.Printf("   Test restore: ❌ %v\n", restore["error"])
+		}
+	}
+}
+
+// jsonInt JSON 숫자(float64)를 정수로 변환 (없으면 0)
+func jsonInt(v interface{}) int64 {
+	f, _ := v.(float64)
+	return int64(f)
+}
+
+// printTableChecks 테이블별 행 수 비교 표시
+func printTableChecks(tables []interface{}) {
+	count := func(v interface{}) string {
+		if v == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%d", jsonInt(v))
+	}
+
+	fmt.Println("\n   Tables:")
+	fmt.Printf("     %-40s %12s %12s %12s  %s\n", "TABLE", "MANIFEST", "DUMP", "RESTORED", "STATUS")
+	for _, t := range tables {
+		table, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		icon := "✅"
+		if table["status"] != "ok" {
+			icon = "❌"
+		}
+		fmt.Printf("     %-40v %12s %12s %12s  %s %v\n", table["table"],
+			count(table["manifest"]), count(table["dump"]), count(table["restored"]), icon, table["status"])
+	}
+}
+
 // 백업 진행 상황 모니터링
-func monitorBackupProgress(backupID string) error {
+func monitorBackupProgress(c *ipc.Client, backupID string) error {
 	fmt.Println("\n📊 Backup Progress:")
 
 	ticker := time.NewTicker(1 * time.Second)
@@ -308,7 +588,7 @@ func monitorBackupProgress(backupID string) error {
 	for {
 		select {
 		case <-ticker.C:
-			resp, err := client.SendMessage(ipc.MessageTypeBackupProgress, map[string]interface{}{
+			resp, err := c.SendMessage(ipc.MessageTypeBackupProgress, map[string]interface{}{
 				"id": backupID,
 			})
 			if err != nil {
@@ -332,8 +612,15 @@ func monitorBackupProgress(backupID string) error {
 					}
 				}
 				fmt.Printf("] %d%%", percent)
+				if written, ok := progress["bytes_written"].(float64); ok && written > 0 {
+					fmt.Printf(" (%s)", formatBytes(int64(written)))
+				}
 
-				if status == "completed" || status == "failed" {
+				if status == "failed" {
+					fmt.Println()
+					return fmt.Errorf("%v", progress["error"])
+				}
+				if status == "completed" {
 					fmt.Println()
 					return nil
 				}
@@ -343,7 +630,7 @@ func monitorBackupProgress(backupID string) error {
 }
 
 // 복구 진행 상황 모니터링
-func monitorRestoreProgress(restoreID string) error {
+func monitorRestoreProgress(c *ipc.Client, restoreID string) error {
 	fmt.Println("\n📊 Restore Progress:")
 
 	ticker := time.NewTicker(1 * time.Second)
@@ -352,7 +639,7 @@ func monitorRestoreProgress(restoreID string) error {
 	for {
 		select {
 		case <-ticker.C:
-			resp, err := client.SendMessage(ipc.MessageTypeRestoreProgress, map[string]interface{}{
+			resp, err := c.SendMessage(ipc.MessageTypeRestoreProgress, map[string]interface{}{
 				"id": restoreID,
 			})
 			if err != nil {
@@ -377,7 +664,11 @@ func monitorRestoreProgress(restoreID string) error {
 				}
 				fmt.Printf("] %d%%", percent)
 
-				if status == "completed" || status == "failed" {
+				if status == "failed" {
+					fmt.Println()
+					return fmt.Errorf("%v", progress["error"])
+				}
+				if status == "completed" {
 					fmt.Println()
 					return nil
 				}
@@ -386,6 +677,26 @@ func monitorRestoreProgress(restoreID string) error {
 	}
 }
 
+// readPassphraseFlag --passphrase-file 플래그가 지정된 경우 파일에서 암호를 읽음
+func readPassphraseFlag(cmd *cobra.Command) (string, error) {
+	path, _ := cmd.Flags().GetString("passphrase-file")
+	if path == "" {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase file: %v", err)
+	}
+
+	passphrase := strings.TrimRight(string(data), "\r\n")
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase file %s is empty", path)
+	}
+
+	return passphrase, nil
+}
+
 // Helper function
 func toStringSlice(slice []interface{}) []string {
 	result := make([]string, len(slice))
@@ -401,9 +712,19 @@ func init() {
 	backupCreateCmd.Flags().Bool("compress", true, "Compress backup file")
 	backupCreateCmd.Flags().String("output", "./backups", "Output directory")
 	backupCreateCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+	backupCreateCmd.Flags().Bool("encrypt", false, "Encrypt backup file with AES-256-GCM")
+	backupCreateCmd.Flags().String("passphrase-file", "", "File containing the encryption passphrase")
 
 	backupRestoreCmd.Flags().StringSlice("components", []string{}, "Components to restore (default: all)")
 	backupRestoreCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+	backupRestoreCmd.Flags().String("passphrase-file", "", "File containing the passphrase of an encrypted backup")
+	backupRestoreCmd.Flags().String("org", "", "Restore only the data of this organization (name or ID)")
+	backupRestoreCmd.Flags().String("category", "", "Restore only this category of the organization")
+	backupRestoreCmd.Flags().Bool("preview", false, "Show what an --org restore would replace without restoring")
+
+	backupVerifyCmd.Flags().String("passphrase-file", "", "File containing the passphrase of an encrypted backup")
+	backupVerifyCmd.Flags().Bool("deep", false, "Check the checksum, SQL dump and row counts against the manifest")
+	backupVerifyCmd.Flags().Bool("test-restore", false, "Also restore the dump into a throwaway database (implies --deep)")
 
 	backupDeleteCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
 
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// walStatus WAL 보관 상태 응답
+type walStatus struct {
+	Enabled          bool   `json:"enabled"`
+	Archive          string `json:"archive"`
+	ArchiverError    string `json:"archiver_error"`
+	BaseBackupsError string `json:"base_backups_error"`
+	Archiver         *struct {
+		ArchiveMode      string     `json:"archive_mode"`
+		ArchivedCount    int64      `json:"archived_count"`
+		FailedCount      int64      `json:"failed_count"`
+		LastArchivedWAL  string     `json:"last_archived_wal"`
+		LastArchivedTime *time.Time `json:"last_archived_time"`
+		LastFailedWAL    string     `json:"last_failed_wal"`
+		LastFailedTime   *time.Time `json:"last_failed_time"`
+	} `json:"archiver"`
+	BaseBackups []struct {
+		Label      string    `json:"label"`
+		StartedAt  time.Time `json:"started_at"`
+		FinishedAt time.Time `json:"finished_at"`
+		Size       int64     `json:"size"`
+	} `json:"base_backups"`
+}
+
+var backupBaseCmd = &cobra.Command{
+	Use:   "base [label]",
+	Short: "Create a base backup in the WAL archive",
+	Long: `Create a physical base backup of PostgreSQL in the configured WAL archive.
+
+Point-in-time restores start from the newest base backup taken before the
+target time and replay the archived WAL from there, so take base backups
+regularly (e.g. daily) to keep restores short.
+
+Examples:
+  tmidb-cli config set wal_archive http://localhost:8888/buckets/tmidb-wal
+  tmidb-cli backup base
+  tmidb-cli backup base before-upgrade`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data := map[string]interface{}{}
+		if len(args) > 0 {
+			data["label"] = args[0]
+		}
+
+		var started struct {
+			ID      string `json:"id"`
+			Label   string `json:"label"`
+			Archive string `json:"archive"`
+		}
+		alertRequest(ipc.MessageTypeBackupBase, data, &started)
+
+		fmt.Printf("🗄️  Creating base backup %s in %s\n", started.Label, started.Archive)
+		if err := monitorBackupProgress(client, started.ID); err != nil {
+			fmt.Printf("❌ Base backup failed: %v\n", err)
+			return
+		}
+		fmt.Printf("\n✅ Base backup %s created\n", started.Label)
+	},
+}
+
+var backupPITRCmd = &cobra.Command{
+	Use:   "pitr <target-time>",
+	Short: "Restore PostgreSQL to a point in time",
+	Long: `Restore the database to its state at the given time from the WAL archive.
+
+The supervisor stops PostgreSQL, moves the data directory aside, restores the
+newest base backup taken before the target and replays the archived WAL up to
+the target time. The previous data directory is kept next to the new one
+(/data/postgresql.pre-pitr-<time>) until you remove it.
+
+The target is an RFC 3339 timestamp or a local "YYYY-MM-DD HH:MM:SS" time.
+
+Examples:
+  tmidb-cli backup pitr "2024-05-01 09:30:00"
+  tmidb-cli backup pitr 2024-05-01T00:30:00Z --base base-20240430T020000Z`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		target := args[0]
+		base, _ := cmd.Flags().GetString("base")
+
+		fmt.Printf("⏪ Point-in-time restore to: %s\n", target)
+		fmt.Println("\n⚠️  WARNING: This will replace the current database!")
+		fmt.Println("   - PostgreSQL will be stopped during the restore")
+		fmt.Println("   - Changes made after the target time will not be in the restored database")
+		fmt.Println("   - The current data directory is kept aside, not deleted")
+
+		if !cmd.Flag("yes").Changed {
+			fmt.Print("\nAre you SURE you want to continue? (yes/no): ")
+			var response string
+			fmt.Scanln(&response)
+			if response != "yes" {
+				fmt.Println("❌ Restore cancelled")
+				return
+			}
+		}
+
+		var started struct {
+			ID     string `json:"id"`
+			Base   string `json:"base"`
+			Target string `json:"target"`
+		}
+		alertRequest(ipc.MessageTypeBackupPITR, map[string]interface{}{
+			"target": target,
+			"base":   base,
+		}, &started)
+
+		fmt.Printf("   Base backup: %s\n", started.Base)
+		if err := monitorRestoreProgress(client, started.ID); err != nil {
+			fmt.Printf("❌ Point-in-time restore failed: %v\n", err)
+			return
+		}
+
+		fmt.Printf("\n✅ Database restored to %s\n", started.Target)
+		fmt.Println("🔄 Restarting services...")
+
+		// 내부 컴포넌트가 새 DB에 다시 연결하도록 재시작
+		client.SendMessage(ipc.MessageTypeProcessRestart, map[string]interface{}{
+			"component": "all",
+		})
+	},
+}
+
+var backupWALCmd = &cobra.Command{
+	Use:   "wal",
+	Short: "Show WAL archiving status and base backups",
+	Run: func(cmd *cobra.Command, args []string) {
+		var status walStatus
+		alertRequest(ipc.MessageTypeBackupWALStatus, nil, &status)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(status)
+			return
+		}
+
+		if !status.Enabled {
+			fmt.Println("📭 WAL archiving is not configured (tmidb-cli config set wal_archive <url>)")
+			return
+		}
+
+		fmt.Printf("🗄️  WAL archive: %s\n", status.Archive)
+		if archiver := status.Archiver; archiver != nil {
+			if archiver.ArchiveMode != "on" {
+				fmt.Printf("   ⚠️  archive_mode is %s; restart PostgreSQL to start archiving\n", archiver.ArchiveMode)
+			}
+			fmt.Printf("   Archived: %d segments", archiver.ArchivedCount)
+			if archiver.LastArchivedTime != nil {
+				fmt.Printf(" (last %s at %s)", archiver.LastArchivedWAL, archiver.LastArchivedTime.Local().Format("2006-01-02 15:04:05"))
+			}
+			fmt.Println()
+			if archiver.FailedCount > 0 {
+				fmt.Printf("   Failed:   %d attempts", archiver.FailedCount)
+				if archiver.LastFailedTime != nil {
+					fmt.Printf(" (last %s at %s)", archiver.LastFailedWAL, archiver.LastFailedTime.Local().Format("2006-01-02 15:04:05"))
+				}
+				fmt.Println()
+			}
+		} else if status.ArchiverError != "" {
+			fmt.Printf("   ⚠️  Archiver status unavailable: %s\n", status.ArchiverError)
+		}
+
+		if status.BaseBackupsError != "" {
+			fmt.Printf("   ⚠️  Base backups unavailable: %s\n", status.BaseBackupsError)
+			return
+		}
+		if len(status.BaseBackups) == 0 {
+			fmt.Println("\n   No base backups yet (tmidb-cli backup base)")
+			return
+		}
+
+		fmt.Printf("\n%-32s %-20s %-12s\n", "BASE BACKUP", "FINISHED", "SIZE")
+		for _, backup := range status.BaseBackups {
+			fmt.Printf("%-32s %-20s %-12s\n", backup.Label, backup.FinishedAt.Local().Format("2006-01-02 15:04:05"), formatBytes(backup.Size))
+		}
+		fmt.Printf("\n   Restorable from %s\n", status.BaseBackups[0].FinishedAt.Local().Format("2006-01-02 15:04:05"))
+	},
+}
+
+func init() {
+	backupPITRCmd.Flags().String("base", "", "Base backup label to restore from (default: newest before the target)")
+	backupPITRCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+
+	backupCmd.AddCommand(backupBaseCmd)
+	backupCmd.AddCommand(backupPITRCmd)
+	backupCmd.AddCommand(backupWALCmd)
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// benchLatency 지연 시간 요약 (ms)
+type benchLatency struct {
+	P50 float64 `json:"p50_ms"`
+	P95 float64 `json:"p95_ms"`
+	P99 float64 `json:"p99_ms"`
+	Max float64 `json:"max_ms"`
+}
+
+// benchResult 수집 벤치마크 결과
+type benchResult struct {
+	OrgID        string       `json:"org_id"`
+	Category     string       `json:"category"`
+	Via          string       `json:"via"`
+	Targets      int          `json:"targets"`
+	Workers      int          `json:"workers"`
+	Batch        int          `json:"batch"`
+	TargetRate   int          `json:"target_rate"`
+	Duration     float64      `json:"duration_seconds"`
+	Sent         int          `json:"sent"`
+	Accepted     int          `json:"accepted"`
+	Failed       int          `json:"failed"`
+	Errors       []string     `json:"errors"`
+	AchievedRate float64      `json:"achieved_rate"`
+	PayloadBytes int          `json:"payload_bytes"`
+	Latency      benchLatency `json:"latency"`
+	LatencyOf    string       `json:"latency_of"`
+	Written      int64        `json:"written"`
+	WriteRate    float64      `json:"write_rate"`
+	InsertLag    benchLatency `json:"insert_lag"`
+	Drain        float64      `json:"drain_seconds"`
+	Drained      bool         `json:"drained"`
+	Kept         []string     `json:"kept_targets"`
+	Samples      []struct {
+		Elapsed float64 `json:"elapsed_seconds"`
+		Sent    int     `json:"sent"`
+		Written int64   `json:"written"`
+		Lag     float64 `json:"lag_ms"`
+	} `json:"samples"`
+}
+
+// 벤치마크 명령어
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Measure how much load this installation handles",
+}
+
+var benchIngestCmd = &cobra.Command{
+	Use:   "ingest",
+	Short: "Push synthetic records at a fixed rate and measure throughput and lag",
+	Long: `Generate records that match the category's active schema and send them
+through NATS (data-consumer) or the bulk API at the given rate. Reports the
+achieved rate, p50/p95/p99 send latency and how far the database inserts fall
+behind.
+
+The records go to temporary targets named tmidb-bench-<time>-<n> that are
+deleted with their data afterwards, unless --keep is given. Run it against a
+test installation or at a quiet time: the load is real.
+
+The bulk API needs a token with write access to the category (--api-token or
+$TMIDB_API_TOKEN).
+
+Examples:
+  tmidb-cli bench ingest --category sensor --rate 5000 --duration 60s
+  tmidb-cli bench ingest --category sensor --via bulk --batch 1000 --rate 20000
+  tmidb-cli bench ingest --category sensor --org acme --samples`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		category, _ := cmd.Flags().GetString("category")
+		org, _ := cmd.Flags().GetString("org")
+		via, _ := cmd.Flags().GetString("via")
+		rate, _ := cmd.Flags().GetInt("rate")
+		duration, _ := cmd.Flags().GetDuration("duration")
+		targets, _ := cmd.Flags().GetInt("targets")
+		batch, _ := cmd.Flags().GetInt("batch")
+		workers, _ := cmd.Flags().GetInt("workers")
+		keep, _ := cmd.Flags().GetBool("keep")
+		showSamples, _ := cmd.Flags().GetBool("samples")
+
+		data := map[string]interface{}{
+			"category": category,
+			"org":      org,
+			"via":      via,
+			"rate":     rate,
+			"duration": duration.String(),
+			"targets":  targets,
+			"batch":    batch,
+			"workers":  workers,
+			"keep":     keep,
+		}
+		if via == "bulk" {
+			token, _ := cmd.Flags().GetString("api-token")
+			if token == "" {
+				token = os.Getenv("TMIDB_API_TOKEN")
+			}
+			data["token"] = token
+		}
+
+		formatter := getFormatter(cmd)
+		if !formatter.Structured() {
+			fmt.Printf("🏁 Sending %d records/s to %s via %s for %s...\n", rate, category, via, duration)
+		}
+
+		// 전송 시간에 남은 행을 기다리는 시간을 더함
+		var result benchResult
+		commandRequest(cmd, duration+5*time.Minute, ipc.MessageTypeBenchIngest, data, &result)
+
+		if formatter.Structured() {
+			formatter.Output(result)
+			return
+		}
+		printBenchResult(result, showSamples)
+		if result.Failed > 0 || !result.Drained {
+			exit(1)
+		}
+	},
+}
+
+// printBenchResult 벤치마크 결과 출력
+func printBenchResult(r benchResult, showSamples bool) {
+	fmt.Printf("\n📊 Ingest benchmark: %s via %s (%d targets, %d workers", r.Category, r.Via, r.Targets, r.Workers)
+	if r.Via == "bulk" {
+		fmt.Printf(", %d records per request", r.Batch)
+	}
+	fmt.Println(")")
+
+	fmt.Println("\nSend")
+	fmt.Printf("   Target rate:    %d records/s\n", r.TargetRate)
+	fmt.Printf("   Achieved rate:  %.0f records/s", r.AchievedRate)
+	if r.AchievedRate < float64(r.TargetRate)*0.95 {
+		fmt.Print("  ⚠️  below target")
+	}
+	fmt.Println()
+	fmt.Printf("   Records:        %d sent, %d accepted, %d failed in %.1fs\n", r.Sent, r.Accepted, r.Failed, r.Duration)
+	fmt.Printf("   Record size:    %s\n", formatBytes(int64(r.PayloadBytes)))
+	fmt.Printf("   Latency (%s): p50 %.1fms  p95 %.1fms  p99 %.1fms  max %.1fms\n",
+		r.LatencyOf, r.Latency.P50, r.Latency.P95, r.Latency.P99, r.Latency.Max)
+	for _, e := range r.Errors {
+		fmt.Printf("   ❌ %s\n", e)
+	}
+
+	fmt.Println("\nDatabase")
+	fmt.Printf("   Rows written:   %d (%.0f rows/s)\n", r.Written, r.WriteRate)
+	fmt.Printf("   Insert lag:     p50 %.0fms  p99 %.0fms  max %.0fms\n", r.InsertLag.P50, r.InsertLag.P99, r.InsertLag.Max)
+	if r.Drained {
+		fmt.Printf("   Caught up %.1fs after sending stopped\n", r.Drain)
+	} else {
+		fmt.Printf("   ⚠️  %d accepted records were not written %.0fs after sending stopped\n", int64(r.Accepted)-r.Written, r.Drain)
+		if r.Via == "nats" {
+			fmt.Println("      Check that data-consumer is running: tmidb-cli nats streams TMIDB_INGEST")
+		}
+	}
+
+	if showSamples && len(r.Samples) > 0 {
+		fmt.Printf("\n%-10s %-12s %-12s %s\n", "ELAPSED", "SENT", "WRITTEN", "LAG")
+		fmt.Println(strings.Repeat("-", 48))
+		for _, s := range r.Samples {
+			fmt.Printf("%-10s %-12d %-12d %.0fms\n", fmt.Sprintf("%.0fs", s.Elapsed), s.Sent, s.Written, s.Lag)
+		}
+	}
+	if len(r.Kept) > 0 {
+		fmt.Printf("\nKept %d benchmark targets and their data\n", len(r.Kept))
+	}
+}
+
+func init() {
+	benchIngestCmd.Flags().String("category", "", "Category to ingest into (required)")
+	benchIngestCmd.Flags().String("org", "", "Organization name or ID (needed when several use the category)")
+	benchIngestCmd.Flags().String("via", "nats", "Ingest path: nats or bulk")
+	benchIngestCmd.Flags().Int("rate", 1000, "Records per second to send")
+	benchIngestCmd.Flags().Duration("duration", time.Minute, "How long to send")
+	benchIngestCmd.Flags().Int("targets", 10, "Number of synthetic targets to spread records over")
+	benchIngestCmd.Flags().Int("batch", 500, "Records per bulk API request")
+	benchIngestCmd.Flags().Int("workers", 8, "Concurrent publishers or requests")
+	benchIngestCmd.Flags().String("api-token", "", "API token for --via bulk (default $TMIDB_API_TOKEN)")
+	benchIngestCmd.Flags().Bool("keep", false, "Keep the benchmark targets and their data")
+	benchIngestCmd.Flags().Bool("samples", false, "Show the per-second measurements")
+	benchIngestCmd.MarkFlagRequired("category")
+
+	benchCmd.AddCommand(benchIngestCmd)
+	rootCmd.AddCommand(benchCmd)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/cluster"
+	"github.com/tmidb/tmidb-core/internal/ha"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+
+	"github.com/spf13/cobra"
+)
+
+// 클러스터 명령어
+var clusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Show the status of every supervisor in the cluster and the failover role",
+}
+
+var clusterStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show process health, versions and resource usage for every node",
+	Long: `Show every node known to this supervisor: static peers polled over remote IPC
+and nodes discovered via NATS. Nodes that stop reporting are shown as stale,
+and peers that cannot be reached as unreachable.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var status cluster.Status
+		alertRequest(ipc.MessageTypeClusterStatus, nil, &status)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(status)
+			return
+		}
+
+		fmt.Printf("🌐 Cluster Nodes (%d):\n\n", len(status.Nodes))
+		fmt.Printf("%-20s %-12s %-10s %-10s %-7s %-7s %-7s %s\n", "NODE", "STATE", "VERSION", "PROCESSES", "CPU", "MEM", "DISK", "LAST SEEN")
+		fmt.Println(strings.Repeat("-", 100))
+		for _, node := range status.Nodes {
+			name := node.Node
+			if node.Self {
+				name += " *"
+			}
+			version := node.Version
+			if version == "" {
+				version = "-"
+			}
+			fmt.Printf("%-20s %-12s %-10s %-10s %-7s %-7s %-7s %s\n",
+				name, node.State, version,
+				fmt.Sprintf("%d/%d", node.Running(), len(node.Processes)),
+				fmt.Sprintf("%.1f%%", node.Resources.CPUUsage),
+				fmt.Sprintf("%.1f%%", node.Resources.MemoryUsage),
+				fmt.Sprintf("%.1f%%", node.Resources.DiskUsage),
+				lastSeen(node))
+		}
+
+		if showProcesses, _ := cmd.Flags().GetBool("processes"); showProcesses {
+			for _, node := range status.Nodes {
+				printNodeProcesses(node)
+			}
+		}
+
+		for _, node := range status.Nodes {
+			if node.Error != "" {
+				fmt.Printf("\n⚠️  %s: %s", node.Node, node.Error)
+			}
+		}
+		fmt.Println()
+	},
+}
+
+var clusterHACmd = &cobra.Command{
+	Use:   "ha",
+	Short: "Show the active/standby role of this supervisor",
+	Long: `Show whether this supervisor holds the failover lease, which node is active,
+and on the standby the process table last replicated from the active.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var report ha.Report
+		alertRequest(ipc.MessageTypeHAStatus, nil, &report)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(report)
+			return
+		}
+
+		icon := "💤"
+		if report.Role == ha.RoleActive {
+			icon = "👑"
+		}
+		fmt.Printf("%s %s is %s (since %s)\n", icon, report.Node, report.Role, report.Since.Format(time.RFC3339))
+		leader := report.Leader
+		if leader == "" {
+			leader = "-"
+		}
+		fmt.Printf("   Leader:  %s\n", leader)
+		fmt.Printf("   Backend: %s (group %s, lease %s)\n", report.Backend, report.Group, report.LeaseTTL)
+		if report.Error != "" {
+			fmt.Printf("\n⚠️  %s\n", report.Error)
+		}
+
+		if report.Replica != nil {
+			fmt.Printf("\n🔁 Replicated from %s %s ago\n", report.Replica.Node, formatDuration(time.Since(report.Replica.UpdatedAt).Truncate(time.Second)))
+			printNodeProcesses(cluster.NodeStatus{Node: report.Replica.Node, Processes: report.Replica.Processes})
+		}
+	},
+}
+
+// printNodeProcesses 노드의 프로세스 목록 출력
+func printNodeProcesses(node cluster.NodeStatus) {
+	fmt.Printf("\n📋 %s\n", node.Node)
+	if len(node.Processes) == 0 {
+		fmt.Println("   (no process information)")
+		return
+	}
+
+	fmt.Printf("   %-20s %-12s %-10s %-12s %-10s %s\n", "NAME", "STATUS", "HEALTH", "UPTIME", "MEMORY", "CPU")
+	for _, p := range node.Processes {
+		health := "-"
+		if p.Health != nil {
+			switch {
+			case p.Health.Degraded:
+				health = "degraded"
+			case p.Health.Healthy:
+				health = "healthy"
+			default:
+				health = "unhealthy"
+			}
+		}
+		fmt.Printf("   %-20s %-12s %-10s %-12s %-10s %.1f%%\n",
+			p.Name, p.Status, health, formatDuration(p.Uptime), formatBytes(p.Memory), p.CPU)
+	}
+}
+
+func lastSeen(node cluster.NodeStatus) string {
+	if node.Self {
+		return "now"
+	}
+	if node.LastSeen.IsZero() {
+		return "never"
+	}
+	return formatDuration(time.Since(node.LastSeen).Truncate(time.Second)) + " ago"
+}
+
+func init() {
+	clusterStatusCmd.Flags().BoolP("processes", "p", false, "Also list the processes on every node")
+
+	clusterCmd.AddCommand(clusterStatusCmd)
+	clusterCmd.AddCommand(clusterHACmd)
+	rootCmd.AddCommand(clusterCmd)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// componentCompletionTimeout 탭 완성 중 슈퍼바이저 응답을 기다리는 시간 (꺼져 있어도 셸이 멈추지 않도록)
+const componentCompletionTimeout = 2 * time.Second
+
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|fish>",
+	Short: "Generate shell completion scripts",
+	Long: `Generate a completion script for bash, zsh or fish.
+
+Component names are completed from the running supervisor.
+
+Examples:
+  # bash (current shell / permanently)
+  source <(tmidb-cli completion bash)
+  tmidb-cli completion bash > /etc/bash_completion.d/tmidb-cli
+
+  # zsh
+  tmidb-cli completion zsh > "${fpath[1]}/_tmidb-cli"
+
+  # fish
+  tmidb-cli completion fish > ~/.config/fish/completions/tmidb-cli.fish`,
+	ValidArgs:             []string{"bash", "zsh", "fish"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		switch args[0] {
+		case "bash":
+			err = rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			err = rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			err = rootCmd.GenFishCompletion(os.Stdout, true)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to generate completion: %v\n", err)
+			exit(1)
+		}
+	},
+}
+
+// componentNames는 슈퍼바이저가 관리하는 컴포넌트 이름을 반환합니다
+func componentNames() ([]string, error) {
+	if client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), componentCompletionTimeout)
+	defer cancel()
+
+	resp, err := client.SendMessageContext(ctx, ipc.MessageTypeProcessList, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Error)
+	}
+
+	items, _ := resp.Data.([]interface{})
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		if process, ok := item.(map[string]interface{}); ok {
+			if name, ok := process["name"].(string); ok && name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// completeComponents는 첫 번째 인자로 컴포넌트 이름을 완성합니다
+func completeComponents(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeComponentFlag(cmd, args, toComplete)
+}
+
+// completeComponentFlag는 위치와 관계없이 컴포넌트 이름을 완성합니다 (--component 플래그 등)
+func completeComponentFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names, err := componentNames()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	matches := []string{}
+	for _, name := range names {
+		if strings.HasPrefix(name, toComplete) {
+			matches = append(matches, name)
+		}
+	}
+	return matches, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeLogLevel은 logs level의 컴포넌트와 레벨을 완성합니다
+func completeLogLevel(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return completeComponentFlag(cmd, args, toComplete)
+	case 1:
+		return []string{"debug", "info", "warn", "error", "default"}, cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeServiceControl은 service control의 동작과 컴포넌트를 완성합니다
+func completeServiceControl(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return []string{"start", "stop", "restart"}, cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		return completeComponentFlag(cmd, args, toComplete)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	// 컴포넌트 이름을 받는 명령어
+	for _, cmd := range []*cobra.Command{
+		processStatusCmd, processRestartCmd, processResetRestartsCmd, processOutputCmd, processStopCmd, processStartCmd,
+		logsCmd, logsEnableCmd, logsDisableCmd, logsFilterCmd, logsPolicyGetCmd, logsPolicySetCmd,
+		serviceLogsCmd,
+	} {
+		cmd.ValidArgsFunction = completeComponents
+	}
+	logsLevelCmd.ValidArgsFunction = completeLogLevel
+	serviceControlCmd.ValidArgsFunction = completeServiceControl
+
+	// cobra 기본 completion 명령어 대신 사용
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+}
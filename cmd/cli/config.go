@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -38,11 +39,14 @@ Examples:
 			key = args[0]
 		}
 
-		fmt.Printf("📋 Getting configuration")
-		if key != "" {
-			fmt.Printf(" for key: %s", key)
+		formatter := getFormatter(cmd)
+		if !formatter.Structured() {
+			fmt.Printf("📋 Getting configuration")
+			if key != "" {
+				fmt.Printf(" for key: %s", key)
+			}
+			fmt.Println()
 		}
-		fmt.Println()
 
 		// 설정 요청
 		resp, err := client.SendMessage(ipc.MessageTypeConfigGet, map[string]interface{}{
@@ -59,12 +63,8 @@ Examples:
 		}
 
 		// 설정 출력
-		if output, _ := cmd.Flags().GetString("output"); output == "json" {
-			data, _ := json.MarshalIndent(resp.Data, "", "  ")
-			fmt.Println(string(data))
-		} else if output == "yaml" {
-			data, _ := yaml.Marshal(resp.Data)
-			fmt.Println(string(data))
+		if formatter.Structured() {
+			formatter.Output(resp.Data)
 		} else {
 			// 기본 형식
 			printConfig(resp.Data, 0)
@@ -85,7 +85,13 @@ Examples:
   tmidb-cli config set api.port 8080
   
   # Enable feature
-  tmidb-cli config set features.hot_reload true`,
+  tmidb-cli config set features.hot_reload true
+
+  # Tune PostgreSQL (small, medium, large, auto or a memory target such as 8GB)
+  tmidb-cli config set postgres_profile medium
+
+  # Archive WAL for point-in-time recovery (SeaweedFS filer URL or s3://bucket/prefix)
+  tmidb-cli config set wal_archive s3://tmidb-wal/prod`,
 	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		key := args[0]
@@ -95,8 +101,8 @@ Examples:
 		var typedValue interface{}
 		if value == "true" || value == "false" {
 			typedValue = value == "true"
-		} else if num, err := fmt.Sscanf(value, "%d", new(int)); err == nil && num == 1 {
-			fmt.Sscanf(value, "%d", &typedValue)
+		} else if num, err := strconv.Atoi(value); err == nil {
+			typedValue = num
 		} else {
 			typedValue = value
 		}
@@ -120,6 +126,16 @@ Examples:
 
 		fmt.Printf("✅ Configuration updated successfully\n")
 
+		// postgres_profile은 렌더링된 PostgreSQL 설정을 돌려줌
+		if settings, ok := resp.Data.(map[string]interface{})["settings"].([]interface{}); ok {
+			fmt.Println("🐘 PostgreSQL settings:")
+			for _, item := range settings {
+				if setting, ok := item.(map[string]interface{}); ok {
+					fmt.Printf("   %s = %v\n", setting["name"], setting["value"])
+				}
+			}
+		}
+
 		// 재시작 필요 여부 확인
 		if needsRestart, ok := resp.Data.(map[string]interface{})["needs_restart"].(bool); ok && needsRestart {
 			fmt.Printf("⚠️  This change requires a restart to take effect\n")
@@ -338,14 +354,7 @@ Examples:
 		}
 
 		fmt.Println("✅ Configuration imported successfully")
-
-		// 변경 사항 표시
-		if changes, ok := resp.Data.(map[string]interface{})["changes"].([]interface{}); ok && len(changes) > 0 {
-			fmt.Printf("\n📝 Applied %d changes:\n", len(changes))
-			for _, change := range changes {
-				fmt.Printf("   - %v\n", change)
-			}
-		}
+		printConfigImportResult(resp.Data)
 	},
 }
 
@@ -401,22 +410,122 @@ Examples:
 		}
 
 		if !resp.Success {
-			fmt.Printf("❌ Validation failed: %s\n", resp.Error)
+			fmt.Println("❌ Validation failed")
+			printValidationResult(resp.Data, resp.Error)
 			return
 		}
 
 		fmt.Println("✅ Configuration is valid")
+		printValidationResult(resp.Data, "")
+	},
+}
 
-		// 경고 표시
-		if warnings, ok := resp.Data.(map[string]interface{})["warnings"].([]interface{}); ok && len(warnings) > 0 {
-			fmt.Printf("\n⚠️  %d warnings:\n", len(warnings))
-			for _, warning := range warnings {
-				fmt.Printf("   - %v\n", warning)
-			}
+var configReloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Reload configuration from the config file",
+	Long: `Re-read the supervisor config file and apply the changes.
+
+Settings that can be changed at runtime (log level, metrics address, timeouts)
+are applied immediately. Components affected by other changes are notified and
+can be restarted with --restart.
+
+Examples:
+  # Reload and show what changed
+  tmidb-cli config reload
+
+  # Reload and restart affected components
+  tmidb-cli config reload --restart`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		restart, _ := cmd.Flags().GetBool("restart")
+
+		fmt.Println("🔄 Reloading configuration...")
+
+		resp, err := client.SendMessage(ipc.MessageTypeConfigReload, map[string]interface{}{
+			"restart": restart,
+		})
+		if err != nil {
+			fmt.Printf("❌ Failed to reload configuration: %v\n", err)
+			return
+		}
+
+		if !resp.Success {
+			fmt.Printf("❌ Error: %s\n", resp.Error)
+			return
+		}
+
+		data, _ := resp.Data.(map[string]interface{})
+		fmt.Printf("✅ Configuration reloaded from %v\n", data["path"])
+
+		if !printConfigChanges(data) {
+			return
+		}
+
+		if restarted, ok := data["restarted"].([]interface{}); ok && len(restarted) > 0 {
+			fmt.Printf("\n🔄 Restarted: %v\n", restarted)
+		} else if notified, ok := data["notified"].([]interface{}); ok && len(notified) > 0 {
+			fmt.Printf("\n⚠️  Affected components need a restart: %v\n", notified)
+			fmt.Println("   Run: tmidb-cli config reload --restart")
 		}
 	},
 }
 
+// printConfigChanges는 reload/import 응답의 변경 목록을 출력하고, 변경이 있었는지 반환합니다
+func printConfigChanges(data map[string]interface{}) bool {
+	changes, _ := data["changes"].([]interface{})
+	if len(changes) == 0 {
+		fmt.Println("   No changes")
+		return false
+	}
+
+	fmt.Printf("\n📝 %d changes:\n", len(changes))
+	for _, change := range changes {
+		c, ok := change.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		status := "applied"
+		if applied, _ := c["applied"].(bool); !applied {
+			status = "restart required"
+		}
+		fmt.Printf("   - %v: %v → %v (%s)\n", c["key"], c["old_value"], c["new_value"], status)
+	}
+	return true
+}
+
+// printConfigImportResult는 import 응답의 변경 목록과 재시작이 필요한 컴포넌트를 출력합니다
+func printConfigImportResult(respData interface{}) {
+	data, _ := respData.(map[string]interface{})
+	if !printConfigChanges(data) {
+		return
+	}
+	if notified, ok := data["notified"].([]interface{}); ok && len(notified) > 0 {
+		fmt.Printf("\n⚠️  Affected components need a restart: %v\n", notified)
+		fmt.Println("   Run: tmidb-cli process restart <component>")
+	}
+}
+
+// printValidationResult는 validate 응답의 오류와 경고를 출력합니다
+func printValidationResult(respData interface{}, fallback string) {
+	data, _ := respData.(map[string]interface{})
+	errs, _ := data["errors"].([]interface{})
+	if len(errs) == 0 && fallback != "" {
+		fmt.Printf("   %s\n", fallback)
+	}
+	if len(errs) > 0 {
+		fmt.Printf("\n❌ %d errors:\n", len(errs))
+		for _, e := range errs {
+			fmt.Printf("   - %v\n", e)
+		}
+	}
+	if warnings, ok := data["warnings"].([]interface{}); ok && len(warnings) > 0 {
+		fmt.Printf("\n⚠️  %d warnings:\n", len(warnings))
+		for _, warning := range warnings {
+			fmt.Printf("   - %v\n", warning)
+		}
+	}
+}
+
 // 설정 출력 헬퍼
 func printConfig(data interface{}, indent int) {
 	prefix := strings.Repeat("  ", indent)
@@ -438,8 +547,9 @@ func printConfig(data interface{}, indent int) {
 
 func init() {
 	// 플래그 추가
-	configGetCmd.Flags().StringP("output", "o", "text", "Output format (text, json, yaml)")
+	configGetCmd.Flags().StringP("output", "o", "text", outputFlagUsage)
 	configResetCmd.Flags().Bool("all", false, "Reset all configuration")
+	configReloadCmd.Flags().Bool("restart", false, "Restart components affected by the changes")
 
 	// 서브커맨드 추가
 	configCmd.AddCommand(configGetCmd)
@@ -449,6 +559,7 @@ func init() {
 	configCmd.AddCommand(configExportCmd)
 	configCmd.AddCommand(configImportCmd)
 	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configReloadCmd)
 
 	// 루트 명령어에 추가
 	rootCmd.AddCommand(configCmd)
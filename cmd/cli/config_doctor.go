@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+var configDoctorCmd = &cobra.Command{
+	Use:   "doctor [component] [-- flags...]",
+	Short: "Show the effective component configuration and where each value comes from",
+	Long: `Show the settings a component loads on its next start, after merging the
+layers in order: defaults < config file < environment variables < command-line
+flags. Each value is shown with the layer it came from. Secret values are masked.
+
+The config file is .env in the supervisor's working directory, or the file
+named by $TMIDB_CONFIG_FILE or --config. Flags after -- are checked as if they
+were passed to the component.
+
+Exits with 1 if any value is invalid.
+
+Examples:
+  tmidb-cli config doctor
+  tmidb-cli config doctor data-consumer --changed
+  tmidb-cli config doctor api -- --db-port=5433`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"api", "data-manager", "data-consumer"},
+	Run: func(cmd *cobra.Command, args []string) {
+		formatter := getFormatter(cmd)
+		changed, _ := cmd.Flags().GetBool("changed")
+
+		component := "api"
+		var flags []string
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			flags = args[dash:]
+			args = args[:dash]
+		}
+		if len(args) > 1 {
+			fmt.Println("❌ Only one component can be checked at a time")
+			exit(1)
+		}
+		if len(args) == 1 {
+			component = args[0]
+		}
+
+		resp, err := client.SendMessage(ipc.MessageTypeConfigDoctor, map[string]interface{}{
+			"component": component,
+			"args":      flags,
+		})
+		if err != nil {
+			fmt.Printf("❌ Failed to check configuration: %v\n", err)
+			exit(1)
+		}
+		if !resp.Success {
+			fmt.Printf("❌ Error: %s\n", resp.Error)
+			exit(1)
+		}
+
+		result, _ := resp.Data.(map[string]interface{})
+		errs, _ := result["errors"].([]interface{})
+		if formatter.Structured() {
+			formatter.Output(resp.Data)
+		} else {
+			printConfigDoctor(result, changed)
+		}
+		if len(errs) > 0 {
+			exit(1)
+		}
+	},
+}
+
+// printConfigDoctor 병합된 설정을 출처와 함께 표로 출력
+func printConfigDoctor(result map[string]interface{}, changed bool) {
+	fmt.Printf("🩺 Effective configuration for %v\n", result["component"])
+	if file, _ := result["file"].(string); file != "" {
+		fmt.Printf("   Config file: %s\n", file)
+	} else {
+		fmt.Println("   Config file: none")
+	}
+	fmt.Println()
+
+	fmt.Printf("%-36s %-40s %s\n", "KEY", "VALUE", "SOURCE")
+	fmt.Println(strings.Repeat("-", 90))
+	settings, _ := result["settings"].([]interface{})
+	for _, item := range settings {
+		setting, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		source, _ := setting["source"].(string)
+		if changed && source == "default" {
+			continue
+		}
+		if origin, _ := setting["origin"].(string); origin != "" {
+			source += " (" + origin + ")"
+		}
+		value, _ := setting["value"].(string)
+		if value == "" {
+			value = `""`
+		}
+		fmt.Printf("%-36v %-40s %s\n", setting["key"], value, source)
+	}
+
+	errs, _ := result["errors"].([]interface{})
+	if len(errs) == 0 {
+		fmt.Println("\n✅ Configuration is valid")
+		return
+	}
+	fmt.Printf("\n❌ %d invalid value(s):\n", len(errs))
+	for _, e := range errs {
+		fmt.Printf("   • %v\n", e)
+	}
+}
+
+func init() {
+	configDoctorCmd.Flags().Bool("changed", false, "Hide values left at their defaults")
+	configCmd.AddCommand(configDoctorCmd)
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"gopkg.in/yaml.v3"
+)
+
+// configEditHeader 편집 파일 맨 위에 붙는 안내
+const configEditHeader = `# tmiDB supervisor configuration
+# Edit the values below, save and close the editor to review the changes.
+# Removing a key keeps its current value. Save an empty file to cancel.
+`
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the configuration in an editor",
+	Long: `Open the full configuration in $VISUAL or $EDITOR, validate the result,
+show a diff and apply all changed keys in one step.
+
+Hot-reloadable keys take effect immediately; components affected by the
+other keys are listed so they can be restarted.
+
+Examples:
+  # Edit in $EDITOR (vi if unset)
+  tmidb-cli config edit
+
+  # Prompt for each value instead of opening an editor
+  tmidb-cli config edit --tui
+
+  # Apply without the confirmation prompt
+  EDITOR=nano tmidb-cli config edit --yes`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		resp, err := client.SendMessage(ipc.MessageTypeConfigGet, map[string]interface{}{"key": ""})
+		if err != nil {
+			fmt.Printf("❌ Failed to get configuration: %v\n", err)
+			exit(1)
+		}
+		if !resp.Success {
+			fmt.Printf("❌ Error: %s\n", resp.Error)
+			exit(1)
+		}
+		current, ok := resp.Data.(map[string]interface{})
+		if !ok {
+			fmt.Println("❌ Unexpected configuration format")
+			exit(1)
+		}
+
+		useTUI, _ := cmd.Flags().GetBool("tui")
+		reader := bufio.NewReader(os.Stdin)
+
+		var edit func() (map[string]interface{}, error)
+		if useTUI {
+			edit = func() (map[string]interface{}, error) { return promptConfigValues(reader, current) }
+		} else {
+			file, err := writeConfigEditFile(current)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				exit(1)
+			}
+			defer os.Remove(file)
+			edit = func() (map[string]interface{}, error) { return editConfigFile(file) }
+		}
+
+		var changed map[string]interface{}
+		for {
+			edited, err := edit()
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				if askYesNo(reader, "Edit again?") {
+					continue
+				}
+				exit(1)
+			}
+			if len(edited) == 0 {
+				fmt.Println("Edit cancelled")
+				return
+			}
+
+			changed = changedConfigValues(current, edited)
+			if len(changed) == 0 {
+				fmt.Println("✅ No changes")
+				return
+			}
+
+			// 적용 전에 바뀐 키들을 함께 검증
+			resp, err := client.SendMessage(ipc.MessageTypeConfigValidate, map[string]interface{}{"config": changed})
+			if err != nil {
+				fmt.Printf("❌ Failed to validate configuration: %v\n", err)
+				exit(1)
+			}
+			if !resp.Success {
+				fmt.Println("❌ Validation failed")
+				printValidationResult(resp.Data, resp.Error)
+				if askYesNo(reader, "Edit again?") {
+					continue
+				}
+				exit(1)
+			}
+			printValidationResult(resp.Data, "")
+			break
+		}
+
+		fmt.Printf("\n📝 %d keys changed:\n", len(changed))
+		printConfigDiff(current, changed)
+
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes && !askYesNo(reader, "Apply these changes?") {
+			fmt.Println("Edit cancelled")
+			return
+		}
+
+		resp, err = client.SendMessage(ipc.MessageTypeConfigImport, map[string]interface{}{"config": changed})
+		if err != nil {
+			fmt.Printf("❌ Failed to apply configuration: %v\n", err)
+			exit(1)
+		}
+		if !resp.Success {
+			fmt.Printf("❌ Error: %s\n", resp.Error)
+			exit(1)
+		}
+
+		fmt.Println("✅ Configuration updated")
+		printConfigImportResult(resp.Data)
+	},
+}
+
+// writeConfigEditFile는 설정을 YAML 임시 파일로 씁니다
+func writeConfigEditFile(config map[string]interface{}) (string, error) {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+	file, err := os.CreateTemp("", "tmidb-config-*.yaml")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(configEditHeader + string(data)); err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// editConfigFile는 편집기를 열고 저장된 파일을 다시 읽습니다
+func editConfigFile(file string) (map[string]interface{}, error) {
+	if err := runEditor(file); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edited file: %w", err)
+	}
+	var edited map[string]interface{}
+	if err := yaml.Unmarshal(data, &edited); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+	return edited, nil
+}
+
+// runEditor는 $VISUAL, $EDITOR 또는 vi로 파일을 열고 닫힐 때까지 기다립니다
+func runEditor(file string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// "code --wait"처럼 인자가 붙은 편집기 지원
+	parts := strings.Fields(editor)
+	editorCmd := exec.Command(parts[0], append(parts[1:], file)...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return fmt.Errorf("editor %s failed: %w", parts[0], err)
+	}
+	return nil
+}
+
+// promptConfigValues는 키마다 새 값을 묻습니다. 빈 입력은 현재 값을 유지하고,
+// 중첩된 값(nats_streams 등)은 편집기로만 바꿀 수 있습니다.
+func promptConfigValues(reader *bufio.Reader, current map[string]interface{}) (map[string]interface{}, error) {
+	fmt.Println("Enter a new value for each key, or press Enter to keep the current value.")
+	edited := make(map[string]interface{})
+	for _, key := range sortedKeys(current) {
+		value := current[key]
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			continue
+		}
+
+		fmt.Printf("  %s [%v]: ", key, value)
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return nil, fmt.Errorf("failed to read input: %w", err)
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			edited[key] = value
+			continue
+		}
+
+		// YAML 스칼라로 해석해서 숫자는 숫자로 전달
+		var parsed interface{}
+		if err := yaml.Unmarshal([]byte(line), &parsed); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		edited[key] = parsed
+	}
+	return edited, nil
+}
+
+// changedConfigValues는 현재 값과 다른 키만 반환합니다. 값은 JSON으로 정규화해서 비교합니다.
+func changedConfigValues(current, edited map[string]interface{}) map[string]interface{} {
+	changed := make(map[string]interface{})
+	for key, value := range edited {
+		if old, ok := current[key]; ok && reflect.DeepEqual(normalizeConfigValue(old), normalizeConfigValue(value)) {
+			continue
+		}
+		changed[key] = value
+	}
+	return changed
+}
+
+// normalizeConfigValue는 YAML과 JSON에서 읽은 값을 같은 타입으로 맞춥니다
+func normalizeConfigValue(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+// printConfigDiff는 바뀐 키를 -이전/+새 값으로 출력합니다
+func printConfigDiff(current, changed map[string]interface{}) {
+	for _, key := range sortedKeys(changed) {
+		fmt.Println()
+		if old, ok := current[key]; ok {
+			printDiffLines("-", key, old)
+		}
+		printDiffLines("+", key, changed[key])
+	}
+}
+
+func printDiffLines(sign, key string, value interface{}) {
+	data, err := yaml.Marshal(map[string]interface{}{key: value})
+	if err != nil {
+		fmt.Printf("   %s %s: %v\n", sign, key, value)
+		return
+	}
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		fmt.Printf("   %s %s\n", sign, line)
+	}
+}
+
+// askYesNo는 yes/no 질문을 하고 yes이면 true를 반환합니다
+func askYesNo(reader *bufio.Reader, question string) bool {
+	fmt.Printf("%s (yes/no): ", question)
+	response, _ := reader.ReadString('\n')
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "yes" || response == "y"
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func init() {
+	configEditCmd.Flags().Bool("tui", false, "Prompt for each value instead of opening an editor")
+	configEditCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+	configCmd.AddCommand(configEditCmd)
+}
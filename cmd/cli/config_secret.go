@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+var configSecretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Manage encrypted configuration values",
+	Long: `Secrets such as database passwords are stored encrypted in the supervisor
+configuration and shown as ******** by config get, list and export. The
+supervisor decrypts a secret only to hand it to the components that use it,
+as an environment variable of the same name.
+
+The key comes from secrets_key in the configuration ("env:NAME", "file:PATH"
+or "exec:COMMAND" for a KMS or vault client), else from $TMIDB_SECRETS_KEY,
+else from ./config/secrets.key, which is generated on first use.`,
+}
+
+var configSecretSetCmd = &cobra.Command{
+	Use:   "set <NAME>",
+	Short: "Encrypt and store a secret",
+	Long: `Encrypt a value and store it under NAME. The value is read from --from-file,
+from standard input, or prompted for without echo.
+
+Components receive the new value when they are next started.
+
+Examples:
+  tmidb-cli config secret set POSTGRES_PASSWORD
+  tmidb-cli config secret set TMIDB_PASSWORD --from-file /run/secrets/tmidb
+  echo -n "$SMTP_PASSWORD" | tmidb-cli config secret set SMTP_PASSWORD --component api`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		components, _ := cmd.Flags().GetStringSlice("component")
+
+		value, err := readSecretValue(cmd, name)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+
+		resp, err := client.SendMessage(ipc.MessageTypeConfigSecretSet, map[string]interface{}{
+			"name":       name,
+			"value":      value,
+			"components": components,
+		})
+		if err != nil {
+			fmt.Printf("❌ Failed to set secret: %v\n", err)
+			exit(1)
+		}
+		if !resp.Success {
+			fmt.Printf("❌ Error: %s\n", resp.Error)
+			exit(1)
+		}
+
+		fmt.Printf("✅ Secret %s stored encrypted\n", name)
+		printSecretRestart(resp.Data)
+	},
+}
+
+var configSecretListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List secrets without their values",
+	Run: func(cmd *cobra.Command, args []string) {
+		formatter := getFormatter(cmd)
+
+		resp, err := client.SendMessage(ipc.MessageTypeConfigSecretList, nil)
+		if err != nil {
+			fmt.Printf("❌ Failed to list secrets: %v\n", err)
+			exit(1)
+		}
+		if !resp.Success {
+			fmt.Printf("❌ Error: %s\n", resp.Error)
+			exit(1)
+		}
+
+		if formatter.Structured() {
+			formatter.Output(resp.Data)
+			return
+		}
+
+		secrets, _ := resp.Data.([]interface{})
+		if len(secrets) == 0 {
+			fmt.Println("No secrets configured")
+			return
+		}
+		fmt.Printf("%-28s %-36s %s\n", "NAME", "COMPONENTS", "UPDATED")
+		fmt.Println(strings.Repeat("-", 90))
+		for _, item := range secrets {
+			secret, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			components, _ := secret["components"].([]interface{})
+			fmt.Printf("%-28s %-36s %v\n", secret["name"], strings.Join(toStringSlice(components), ", "), secret["updated"])
+		}
+	},
+}
+
+var configSecretDeleteCmd = &cobra.Command{
+	Use:   "delete <NAME>",
+	Short: "Remove a secret",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		resp, err := client.SendMessage(ipc.MessageTypeConfigSecretDelete, map[string]interface{}{
+			"name": args[0],
+		})
+		if err != nil {
+			fmt.Printf("❌ Failed to delete secret: %v\n", err)
+			exit(1)
+		}
+		if !resp.Success {
+			fmt.Printf("❌ Error: %s\n", resp.Error)
+			exit(1)
+		}
+
+		fmt.Printf("✅ Secret %s deleted\n", args[0])
+		printSecretRestart(resp.Data)
+	},
+}
+
+// readSecretValue --from-file, 파이프된 표준 입력, 또는 화면에 보이지 않는 프롬프트에서 값을 읽음
+func readSecretValue(cmd *cobra.Command, name string) (string, error) {
+	if path, _ := cmd.Flags().GetString("from-file"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %v", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !isTerminal(fd) {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret from stdin: %v", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	fmt.Printf("Value for %s: ", name)
+	restore, err := makeRaw(fd)
+	if err != nil {
+		return "", err
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\r')
+	restore()
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret: %v", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// printSecretRestart 새 값을 받으려면 재시작해야 하는 컴포넌트 안내
+func printSecretRestart(data interface{}) {
+	result, _ := data.(map[string]interface{})
+	restart, _ := result["needs_restart"].([]interface{})
+	if len(restart) == 0 {
+		return
+	}
+	fmt.Println("⚠️  Restart these components to pass them the change:")
+	for _, component := range restart {
+		fmt.Printf("   tmidb-cli process restart %v\n", component)
+	}
+}
+
+func init() {
+	configSecretSetCmd.Flags().String("from-file", "", "Read the value from this file")
+	configSecretSetCmd.Flags().StringSlice("component", nil, "Pass the secret only to these components (default: api, data-manager, data-consumer)")
+
+	configSecretCmd.AddCommand(configSecretSetCmd)
+	configSecretCmd.AddCommand(configSecretListCmd)
+	configSecretCmd.AddCommand(configSecretDeleteCmd)
+	configCmd.AddCommand(configSecretCmd)
+}
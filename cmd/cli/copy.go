@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -20,27 +19,39 @@ var copyCmd = &cobra.Command{
 }
 
 var copyReceiveCmd = &cobra.Command{
-	Use:   "receive [--port PORT] [--path PATH]",
+	Use:   "receive [--port PORT] [--path PATH] [--tls] [--token]",
 	Short: "Start copy receiver to accept incoming files",
-	Long:  "Start a copy receiver that listens on specified port and saves files to specified path",
+	Long: `Start a copy receiver that listens on specified port and saves files to specified path.
+
+With --tls the receiver uses the given certificate or a generated self-signed one;
+senders pin the printed fingerprint. With --token the sender must present the
+printed one-time session token.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		port, _ := cmd.Flags().GetInt("port")
 		path, _ := cmd.Flags().GetString("path")
+		useTLS, _ := cmd.Flags().GetBool("tls")
+		certFile, _ := cmd.Flags().GetString("cert")
+		keyFile, _ := cmd.Flags().GetString("key")
+		requireToken, _ := cmd.Flags().GetBool("token")
 
 		data := map[string]interface{}{
-			"port": port,
-			"path": path,
+			"port":          port,
+			"path":          path,
+			"tls":           useTLS,
+			"cert_file":     certFile,
+			"key_file":      keyFile,
+			"require_token": requireToken,
 		}
 
 		resp, err := client.SendMessage(ipc.MessageTypeCopyReceive, data)
 		if err != nil {
 			fmt.Printf("❌ Failed to start copy receiver: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if !resp.Success {
 			fmt.Printf("❌ Error: %s\n", resp.Error)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if sessionData, ok := resp.Data.(map[string]interface{}); ok {
@@ -52,7 +63,17 @@ var copyReceiveCmd = &cobra.Command{
 			fmt.Printf("📡 Session ID: %s\n", sessionID)
 			fmt.Printf("🔌 Listening on port: %d\n", actualPort)
 			fmt.Printf("📁 Saving files to: %s\n", actualPath)
-			fmt.Printf("💡 Use 'tmidb-cli copy send <file> <host>:%d' to send files\n", actualPort)
+
+			sendFlags := ""
+			if fingerprint := getCopyString(sessionData, "tls_fingerprint"); fingerprint != "" {
+				fmt.Printf("🔒 TLS fingerprint: %s\n", fingerprint)
+				sendFlags += " --tls --tls-fingerprint " + fingerprint
+			}
+			if token := getCopyString(sessionData, "token"); token != "" {
+				fmt.Printf("🔑 Session token (one-time): %s\n", token)
+				sendFlags += " --token " + token
+			}
+			fmt.Printf("💡 Use 'tmidb-cli copy send <file> <host>:%d%s' to send files\n", actualPort, sendFlags)
 		}
 	},
 }
@@ -70,31 +91,42 @@ var copySendCmd = &cobra.Command{
 		parts := strings.Split(target, ":")
 		if len(parts) != 2 {
 			fmt.Printf("❌ Invalid target format. Use host:port\n")
-			os.Exit(1)
+			exit(1)
 		}
 
 		targetHost := parts[0]
 		targetPort, err := strconv.Atoi(parts[1])
 		if err != nil {
 			fmt.Printf("❌ Invalid port number: %s\n", parts[1])
-			os.Exit(1)
+			exit(1)
 		}
 
+		useTLS, _ := cmd.Flags().GetBool("tls")
+		fingerprint, _ := cmd.Flags().GetString("tls-fingerprint")
+		caFile, _ := cmd.Flags().GetString("ca-file")
+		insecure, _ := cmd.Flags().GetBool("insecure")
+		token, _ := cmd.Flags().GetString("token")
+
 		data := map[string]interface{}{
-			"file_path":   filePath,
-			"target_host": targetHost,
-			"target_port": targetPort,
+			"file_path":       filePath,
+			"target_host":     targetHost,
+			"target_port":     targetPort,
+			"tls":             useTLS || fingerprint != "" || caFile != "",
+			"tls_fingerprint": fingerprint,
+			"ca_file":         caFile,
+			"insecure":        insecure,
+			"token":           token,
 		}
 
 		resp, err := client.SendMessage(ipc.MessageTypeCopySend, data)
 		if err != nil {
 			fmt.Printf("❌ Failed to send file: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if !resp.Success {
 			fmt.Printf("❌ Error: %s\n", resp.Error)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if sessionData, ok := resp.Data.(map[string]interface{}); ok {
@@ -129,12 +161,12 @@ var copyStatusCmd = &cobra.Command{
 		resp, err := client.SendMessage(ipc.MessageTypeCopyStatus, data)
 		if err != nil {
 			fmt.Printf("❌ Failed to get copy status: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if !resp.Success {
 			fmt.Printf("❌ Error: %s\n", resp.Error)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// 단일 세션 상태 표시
@@ -175,12 +207,12 @@ var copyListCmd = &cobra.Command{
 		resp, err := client.SendMessage(ipc.MessageTypeCopyList, nil)
 		if err != nil {
 			fmt.Printf("❌ Failed to list copy sessions: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if !resp.Success {
 			fmt.Printf("❌ Error: %s\n", resp.Error)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if sessions, ok := resp.Data.([]interface{}); ok {
@@ -216,12 +248,12 @@ var copyStopCmd = &cobra.Command{
 		resp, err := client.SendMessage(ipc.MessageTypeCopyStop, data)
 		if err != nil {
 			fmt.Printf("❌ Failed to stop copy session: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if !resp.Success {
 			fmt.Printf("❌ Error: %s\n", resp.Error)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Printf("✅ Copy session %s stopped successfully\n", sessionID)
@@ -245,6 +277,16 @@ func displaySingleSession(sessionData map[string]interface{}) {
 	fmt.Printf("🆔 Session ID: %s\n", id)
 	fmt.Printf("🔄 Mode: %s\n", mode)
 	fmt.Printf("📊 Status: %s\n", getCopyStatusIcon(status)+status)
+	if tlsEnabled, _ := sessionData["tls"].(bool); tlsEnabled {
+		fmt.Printf("🔒 TLS: enabled")
+		if fingerprint := getCopyString(sessionData, "tls_fingerprint"); fingerprint != "" {
+			fmt.Printf(" (fingerprint %s)", fingerprint)
+		}
+		fmt.Println()
+	}
+	if tokenAuth, _ := sessionData["token_auth"].(bool); tokenAuth {
+		fmt.Printf("🔑 Token authentication: required\n")
+	}
 
 	if mode == "receive" {
 		fmt.Printf("🔌 Port: %d\n", port)
@@ -381,6 +423,17 @@ func init() {
 	// copy receive 플래그
 	copyReceiveCmd.Flags().IntP("port", "p", 8080, "Port to listen on")
 	copyReceiveCmd.Flags().StringP("path", "d", "/tmp/received", "Directory to save received files")
+	copyReceiveCmd.Flags().Bool("tls", false, "Accept connections over TLS")
+	copyReceiveCmd.Flags().String("cert", "", "TLS certificate file (default: generate self-signed)")
+	copyReceiveCmd.Flags().String("key", "", "TLS private key file")
+	copyReceiveCmd.Flags().Bool("token", false, "Require a one-time session token from the sender")
+
+	// copy send 플래그
+	copySendCmd.Flags().Bool("tls", false, "Connect to the receiver over TLS")
+	copySendCmd.Flags().String("tls-fingerprint", "", "Expected SHA-256 fingerprint of the receiver certificate")
+	copySendCmd.Flags().String("ca-file", "", "CA certificate file used to verify the receiver")
+	copySendCmd.Flags().Bool("insecure", false, "Skip receiver certificate verification")
+	copySendCmd.Flags().String("token", "", "Session token printed by the receiver")
 
 	// copy 하위 명령어 추가
 	copyCmd.AddCommand(copyReceiveCmd)
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+
+	"github.com/spf13/cobra"
+)
+
+// 데이터베이스 관리 명령어
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Database management",
+}
+
+var dbPolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Manage TimescaleDB compression, retention and continuous aggregates",
+	Long: `Manage TimescaleDB policies for time-series data.
+
+A policy named ts_obs or geo_trace applies to the whole hypertable (compression
+and chunk retention). A policy named after a category applies to that category's
+rows in ts_obs (retention and a continuous aggregate).`,
+}
+
+var dbPolicyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show policies with their TimescaleDB jobs and compression stats",
+	Run: func(cmd *cobra.Command, args []string) {
+		var statuses []database.TimeseriesPolicyStatus
+		alertRequest(ipc.MessageTypeDBPolicyList, nil, &statuses)
+		printDBPolicies(cmd, statuses)
+	},
+}
+
+var dbPolicyGetCmd = &cobra.Command{
+	Use:   "get <ts_obs|geo_trace|category>",
+	Short: "Show one policy",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var statuses []database.TimeseriesPolicyStatus
+		alertRequest(ipc.MessageTypeDBPolicyList, nil, &statuses)
+		for _, status := range statuses {
+			if status.Name == args[0] {
+				printDBPolicies(cmd, []database.TimeseriesPolicyStatus{status})
+				return
+			}
+		}
+		fmt.Printf("❌ No policy for %s\n", args[0])
+		exit(1)
+	},
+}
+
+var dbPolicySetCmd = &cobra.Command{
+	Use:   "set <ts_obs|geo_trace|category>",
+	Short: "Change a policy and apply it",
+	Long: `Change a policy and apply it right away. Only the given flags are changed;
+pass an empty value (e.g. --retain "") to clear a setting.
+
+Examples:
+  tmidb-cli db policy set ts_obs --compress-after 7d --retain 365d
+  tmidb-cli db policy set geo_trace --compress-after 1d
+  tmidb-cli db policy set temperature --retain 30d --aggregate-bucket 1h --aggregate-fields temp,humidity`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data := map[string]interface{}{"name": args[0]}
+		flags := cmd.Flags()
+
+		for flag, key := range map[string]string{
+			"compress-after":   "compress_after",
+			"retain":           "retain_for",
+			"aggregate-bucket": "aggregate_bucket",
+		} {
+			if flags.Changed(flag) {
+				value, _ := flags.GetString(flag)
+				if _, err := database.NormalizeInterval(value); err != nil {
+					fmt.Printf("❌ Invalid --%s: %v\n", flag, err)
+					exit(1)
+				}
+				data[key] = value
+			}
+		}
+		if flags.Changed("aggregate-fields") {
+			fields, _ := flags.GetStringSlice("aggregate-fields")
+			data["aggregate_fields"] = fields
+		}
+		if len(data) == 1 {
+			fmt.Println("❌ Nothing to change; see --help for policy flags")
+			exit(1)
+		}
+
+		var policy database.TimeseriesPolicy
+		alertRequest(ipc.MessageTypeDBPolicySet, data, &policy)
+		fmt.Printf("✅ Policy for %s applied\n\n", args[0])
+		printDBPolicies(cmd, []database.TimeseriesPolicyStatus{{TimeseriesPolicy: policy}})
+	},
+}
+
+var dbPolicyRemoveCmd = &cobra.Command{
+	Use:   "remove <ts_obs|geo_trace|category>",
+	Short: "Remove a policy, its jobs and its continuous aggregate",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		alertRequest(ipc.MessageTypeDBPolicyRemove, map[string]interface{}{"name": args[0]}, nil)
+		fmt.Printf("✅ Policy for %s removed\n", args[0])
+	},
+}
+
+var dbPolicyApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Re-apply all stored policies (e.g. after a restore)",
+	Run: func(cmd *cobra.Command, args []string) {
+		var statuses []database.TimeseriesPolicyStatus
+		alertRequest(ipc.MessageTypeDBPolicyApply, nil, &statuses)
+		fmt.Printf("✅ %d policies applied\n\n", len(statuses))
+		printDBPolicies(cmd, statuses)
+	},
+}
+
+// printDBPolicies 정책 목록 출력
+func printDBPolicies(cmd *cobra.Command, statuses []database.TimeseriesPolicyStatus) {
+	formatter := getFormatter(cmd)
+	if formatter.Structured() {
+		formatter.Output(statuses)
+		return
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println("📭 No timeseries policies configured")
+		return
+	}
+
+	orNone := func(value string) string {
+		if value == "" {
+			return "-"
+		}
+		return value
+	}
+
+	for _, status := range statuses {
+		scope := "category"
+		if status.IsTable() {
+			scope = "hypertable"
+		}
+		fmt.Printf("🗄️  %s (%s)\n", status.Name, scope)
+		if status.IsTable() {
+			fmt.Printf("   Compress after:  %s\n", orNone(status.CompressAfter))
+		}
+		fmt.Printf("   Retain for:      %s\n", orNone(status.RetainFor))
+		if !status.IsTable() {
+			aggregate := "-"
+			if view := status.AggregateView(); view != "" {
+				aggregate = fmt.Sprintf("%s every %s", view, status.AggregateBucket)
+				if len(status.AggregateFields) > 0 {
+					aggregate += " (" + strings.Join(status.AggregateFields, ", ") + ")"
+				}
+			}
+			fmt.Printf("   Aggregate:       %s\n", aggregate)
+		}
+		if status.TotalChunks > 0 {
+			fmt.Printf("   Chunks:          %d compressed of %d\n", status.CompressedChunks, status.TotalChunks)
+		}
+		if status.BytesBefore > 0 {
+			fmt.Printf("   Compression:     %s -> %s\n", formatBytes(status.BytesBefore), formatBytes(status.BytesAfter))
+		}
+		for _, job := range status.Jobs {
+			next := "-"
+			if job.NextStart != nil {
+				next = job.NextStart.Local().Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("   Job %-6d %-38s every %-10s last: %-8s next: %s\n",
+				job.ID, job.Proc, job.Schedule, orNone(job.LastRunStatus), next)
+		}
+		fmt.Println()
+	}
+}
+
+func init() {
+	dbPolicySetCmd.Flags().String("compress-after", "", "Compress chunks older than this (hypertables only, e.g. 7d)")
+	dbPolicySetCmd.Flags().String("retain", "", "Delete data older than this (e.g. 90d, 2w)")
+	dbPolicySetCmd.Flags().String("aggregate-bucket", "", "Continuous aggregate bucket size (categories only, e.g. 1h)")
+	dbPolicySetCmd.Flags().StringSlice("aggregate-fields", nil, "Numeric payload fields to aggregate (avg/min/max)")
+
+	dbPolicyCmd.AddCommand(dbPolicyListCmd)
+	dbPolicyCmd.AddCommand(dbPolicyGetCmd)
+	dbPolicyCmd.AddCommand(dbPolicySetCmd)
+	dbPolicyCmd.AddCommand(dbPolicyRemoveCmd)
+	dbPolicyCmd.AddCommand(dbPolicyApplyCmd)
+	dbCmd.AddCommand(dbPolicyCmd)
+	rootCmd.AddCommand(dbCmd)
+}
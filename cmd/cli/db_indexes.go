@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+
+	"github.com/spf13/cobra"
+)
+
+// dbIndexTimeout 인덱스 생성은 큰 테이블에서 오래 걸림
+const dbIndexTimeout = 6 * time.Hour
+
+var dbIndexesCmd = &cobra.Command{
+	Use:   "indexes",
+	Short: "Suggest and create indexes for frequently filtered JSONB fields",
+}
+
+var dbIndexesAdviseCmd = &cobra.Command{
+	Use:   "advise",
+	Short: "Suggest indexes from recorded filters and slow queries",
+	Long: `Suggest indexes on JSONB fields that queries filter on often.
+
+The API records which data fields its filters use (?filter=data.temp>25) per
+category. The slow query log (SLOW_QUERY_MS) adds ts_obs payload conditions and
+JSONB containment (@>). Each field is checked against the active category
+schemas: fields a schema does not declare, and numeric comparisons on fields
+not declared as numbers, are skipped.
+
+For every suggestion the advisor samples the table and estimates how many rows a
+filtered query reads now and with the index, and how large the index will be.
+Nothing is created; use 'db indexes apply'.
+
+Examples:
+  tmidb-cli db indexes advise
+  tmidb-cli db indexes advise --min-calls 10 --skipped`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		minCalls, _ := cmd.Flags().GetInt("min-calls")
+		showSkipped, _ := cmd.Flags().GetBool("skipped")
+
+		var advice database.IndexAdvice
+		alertRequest(ipc.MessageTypeDBIndexAdvise, map[string]interface{}{"min_calls": minCalls}, &advice)
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(advice)
+			return
+		}
+
+		if len(advice.Suggestions) == 0 {
+			fmt.Println("✅ No index suggestions")
+		} else {
+			fmt.Printf("💡 Index suggestions (%d):\n", len(advice.Suggestions))
+			for _, s := range advice.Suggestions {
+				printIndexSuggestion(s)
+			}
+			fmt.Println("\n💡 Use 'tmidb-cli db indexes apply <name>' or '--all' to create them")
+		}
+
+		if len(advice.Skipped) > 0 {
+			if !showSkipped {
+				fmt.Printf("\n%d recorded fields were not suggested; see --skipped\n", len(advice.Skipped))
+				return
+			}
+			fmt.Printf("\nNot suggested (%d):\n", len(advice.Skipped))
+			fmt.Printf("%-18s %-28s %-8s %s\n", "TABLE", "FIELD", "USES", "REASON")
+			fmt.Println(strings.Repeat("-", 100))
+			for _, s := range advice.Skipped {
+				fmt.Printf("%-18s %-28s %-8d %s\n", s.Table, truncateText(s.Path, 28), s.Calls, s.Reason)
+			}
+		}
+	},
+}
+
+// printIndexSuggestion 추천 하나와 추정 효과 출력
+func printIndexSuggestion(s database.IndexSuggestion) {
+	field := s.Path
+	if s.Kind == database.IndexGIN {
+		field = s.Expression + " (@> containment)"
+	}
+	fmt.Printf("\n🗂️  %s\n", s.Name)
+	fmt.Printf("   Field:      %s.%s", s.Table, field)
+	if len(s.FieldTypes) > 0 {
+		fmt.Printf(" (%s)", strings.Join(s.FieldTypes, ", "))
+	}
+	fmt.Println()
+	if len(s.Categories) > 0 && s.Kind != database.IndexGIN {
+		fmt.Printf("   Categories: %s\n", strings.Join(s.Categories, ", "))
+	}
+	source := "API filters"
+	if s.Source == "slow_queries" {
+		source = fmt.Sprintf("slow queries, mean %s", formatMillis(s.ObservedMs))
+	}
+	fmt.Printf("   Used:       %d times (%s), last %s\n", s.Calls, source, s.LastUsed.Local().Format("2006-01-02 15:04"))
+	fmt.Printf("   Benefit:    reads ~%s of %d rows per query (%.2f%%)\n",
+		formatRowCount(s.RowsPerQuery), s.Rows, s.Selectivity*100)
+	fmt.Printf("   Size:       ~%s\n", formatBytes(s.EstimatedBytes))
+	fmt.Printf("   SQL:        %s\n", s.SQL)
+}
+
+// formatRowCount 추정 행 수 (1보다 작으면 <1)
+func formatRowCount(n float64) string {
+	if n < 1 {
+		return "<1"
+	}
+	return fmt.Sprintf("%.0f", n)
+}
+
+var dbIndexesApplyCmd = &cobra.Command{
+	Use:   "apply [name...]",
+	Short: "Create suggested indexes",
+	Long: `Create indexes suggested by 'db indexes advise', one at a time.
+
+target_categories indexes are built with CREATE INDEX CONCURRENTLY, so reads and
+writes continue. On a TimescaleDB hypertable (ts_obs) the index is built chunk
+by chunk; writes to a chunk wait while its index is built. A failed build is
+removed so it can be tried again.
+
+Examples:
+  tmidb-cli db indexes apply idx_tc_vitals_bp_num_3fa2c1
+  tmidb-cli db indexes apply --all --yes`,
+	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+		yes, _ := cmd.Flags().GetBool("yes")
+		minCalls, _ := cmd.Flags().GetInt("min-calls")
+		if all == (len(args) > 0) {
+			fmt.Println("❌ Give index names or --all")
+			exit(1)
+		}
+
+		// 만들 인덱스와 추정 효과를 먼저 보여 줌
+		var advice database.IndexAdvice
+		alertRequest(ipc.MessageTypeDBIndexAdvise, map[string]interface{}{"min_calls": minCalls}, &advice)
+		wanted := map[string]bool{}
+		for _, name := range args {
+			wanted[name] = true
+		}
+		var selected []database.IndexSuggestion
+		for _, s := range advice.Suggestions {
+			if all || wanted[s.Name] {
+				selected = append(selected, s)
+				delete(wanted, s.Name)
+			}
+		}
+		for name := range wanted {
+			fmt.Printf("❌ %s is not a current suggestion (see 'tmidb-cli db indexes advise')\n", name)
+			exit(1)
+		}
+		if len(selected) == 0 {
+			fmt.Println("✅ No index suggestions")
+			return
+		}
+
+		formatter := getFormatter(cmd)
+		if !yes {
+			var total int64
+			for _, s := range selected {
+				printIndexSuggestion(s)
+				total += s.EstimatedBytes
+			}
+			fmt.Printf("\n⚠️  Create %d index(es), about %s? (yes/no): ", len(selected), formatBytes(total))
+			var response string
+			fmt.Scanln(&response)
+			if response != "yes" {
+				fmt.Println("❌ Cancelled")
+				return
+			}
+		}
+
+		names := make([]string, len(selected))
+		for i, s := range selected {
+			names[i] = s.Name
+		}
+		var results []struct {
+			Name    string  `json:"name"`
+			Table   string  `json:"table"`
+			SQL     string  `json:"sql"`
+			Seconds float64 `json:"seconds"`
+			Error   string  `json:"error"`
+		}
+		commandRequest(cmd, dbIndexTimeout, ipc.MessageTypeDBIndexApply, map[string]interface{}{
+			"names": names, "min_calls": minCalls,
+		}, &results)
+		if formatter.Structured() {
+			formatter.Output(results)
+			return
+		}
+
+		failed := 0
+		for _, r := range results {
+			if r.Error != "" {
+				failed++
+				fmt.Printf("❌ %s: %s\n", r.Name, r.Error)
+				continue
+			}
+			fmt.Printf("✅ %s created on %s in %.1fs\n", r.Name, r.Table, r.Seconds)
+		}
+		if failed > 0 {
+			exit(1)
+		}
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{dbIndexesAdviseCmd, dbIndexesApplyCmd} {
+		c.Flags().Int("min-calls", 100, "Only suggest fields filtered at least this many times")
+	}
+	dbIndexesAdviseCmd.Flags().Bool("skipped", false, "Also list recorded fields that were not suggested, with the reason")
+	dbIndexesApplyCmd.Flags().Bool("all", false, "Create all suggested indexes")
+	dbIndexesApplyCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+
+	dbIndexesCmd.AddCommand(dbIndexesAdviseCmd)
+	dbIndexesCmd.AddCommand(dbIndexesApplyCmd)
+	dbCmd.AddCommand(dbIndexesCmd)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+
+	"github.com/spf13/cobra"
+)
+
+var dbRollupCmd = &cobra.Command{
+	Use:   "rollup",
+	Short: "Manage per-category rollups of time-series data",
+	Long: `Manage rollup jobs. The data manager summarizes a category's numeric ts_obs
+fields into buckets per resolution (samples, sum, min, max and last value per
+target). The time-series API reads the coarsest rollup that fits the requested
+range and falls back to the raw observations.`,
+}
+
+var dbRollupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show rollup jobs and how far each resolution is rolled up",
+	Run: func(cmd *cobra.Command, args []string) {
+		var jobs []database.RollupJob
+		alertRequest(ipc.MessageTypeDBRollupList, nil, &jobs)
+		printDBRollups(cmd, jobs)
+	},
+}
+
+var dbRollupSetCmd = &cobra.Command{
+	Use:   "set <category>",
+	Short: "Create or change a rollup job",
+	Long: `Create or change a rollup job. Only the given flags are changed. A new job
+rolls up hourly and daily. Without --fields, every field that the category's
+schema declares as a number or integer is rolled up. Adding a field rolls the
+category up again from its oldest observation.
+
+Examples:
+  tmidb-cli db rollup set temperature
+  tmidb-cli db rollup set temperature --resolutions 15m,1h,1d --fields temp,vitals.hr
+  tmidb-cli db rollup set temperature --active=false`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data := map[string]interface{}{"category": args[0]}
+		flags := cmd.Flags()
+
+		if flags.Changed("resolutions") {
+			resolutions, _ := flags.GetStringSlice("resolutions")
+			for _, value := range resolutions {
+				if _, err := database.NormalizeInterval(value); err != nil {
+					fmt.Printf("❌ Invalid --resolutions: %v\n", err)
+					exit(1)
+				}
+			}
+			data["resolutions"] = resolutions
+		}
+		if flags.Changed("fields") {
+			fields, _ := flags.GetStringSlice("fields")
+			data["fields"] = fields
+		}
+		if flags.Changed("active") {
+			active, _ := flags.GetBool("active")
+			data["is_active"] = active
+		}
+
+		var job database.RollupJob
+		alertRequest(ipc.MessageTypeDBRollupSet, data, &job)
+		fmt.Printf("✅ Rollup for %s saved\n\n", args[0])
+		printDBRollups(cmd, []database.RollupJob{job})
+	},
+}
+
+var dbRollupRemoveCmd = &cobra.Command{
+	Use:   "remove <category>",
+	Short: "Remove a rollup job and its summaries",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		alertRequest(ipc.MessageTypeDBRollupRemove, map[string]interface{}{"category": args[0]}, nil)
+		fmt.Printf("✅ Rollup for %s removed\n", args[0])
+	},
+}
+
+// printDBRollups 롤업 작업 목록 출력
+func printDBRollups(cmd *cobra.Command, jobs []database.RollupJob) {
+	formatter := getFormatter(cmd)
+	if formatter.Structured() {
+		formatter.Output(jobs)
+		return
+	}
+
+	if len(jobs) == 0 {
+		fmt.Println("📭 No rollup jobs configured")
+		return
+	}
+
+	for _, job := range jobs {
+		state := "active"
+		if !job.IsActive {
+			state = "paused"
+		}
+		fields := "numeric fields in the schema"
+		if len(job.Fields) > 0 {
+			fields = strings.Join(job.Fields, ", ")
+		}
+		fmt.Printf("📉 %s (%s)\n", job.Category, state)
+		fmt.Printf("   Resolutions:  %s\n", strings.Join(job.Resolutions, ", "))
+		fmt.Printf("   Fields:       %s\n", fields)
+		for _, p := range job.Progress {
+			status := fmt.Sprintf("%d rows", p.LastRows)
+			if p.LastError != "" {
+				status = "error: " + p.LastError
+			}
+			fmt.Printf("   %-12s  rolled up to %s, last run %s (%s)\n", p.Resolution,
+				p.RolledUpTo.Local().Format("2006-01-02 15:04"), p.LastRunAt.Local().Format(time.DateTime), status)
+		}
+		fmt.Println()
+	}
+}
+
+func init() {
+	dbRollupSetCmd.Flags().StringSlice("resolutions", nil, "Bucket sizes to roll up to (e.g. 1h,1d)")
+	dbRollupSetCmd.Flags().StringSlice("fields", nil, "Numeric payload fields, dotted for nested fields (default: numeric fields in the schema)")
+	dbRollupSetCmd.Flags().Bool("active", true, "Run the job (false pauses it and the API reads raw data)")
+
+	dbRollupCmd.AddCommand(dbRollupListCmd)
+	dbRollupCmd.AddCommand(dbRollupSetCmd)
+	dbRollupCmd.AddCommand(dbRollupRemoveCmd)
+	dbCmd.AddCommand(dbRollupCmd)
+}
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/tmidb/tmidb-core/internal/database"
 	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/profiling"
 )
 
 // 진단 명령어
@@ -567,12 +572,339 @@ func monitorDiagnosticProgress(diagID string, duration time.Duration) error {
 	}
 }
 
+var diagnoseCrashesCmd = &cobra.Command{
+	Use:   "crashes [id]",
+	Short: "List or show component crash dumps",
+	Long: `List the crash dumps the supervisor wrote when a component exited unexpectedly,
+or show one of them.
+
+Each dump is a directory on the supervisor host (crash_dir, default ./crashes)
+with the last stdout/stderr lines, recent log entries, the last /proc status and
+limits of the process, its environment with secrets redacted, recent supervisor
+events and, when the kernel wrote one, the core file.
+
+Examples:
+  tmidb-cli diagnose crashes
+  tmidb-cli diagnose crashes --component data-consumer
+  tmidb-cli diagnose crashes data-consumer-20261016-101500-4242 --lines 50`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		formatter := getFormatter(cmd)
+
+		if len(args) == 1 {
+			lines, _ := cmd.Flags().GetInt("lines")
+			var detail struct {
+				Crash  crashDump `json:"crash"`
+				Output []string  `json:"output"`
+			}
+			alertRequest(ipc.MessageTypeDiagnoseCrashes, map[string]interface{}{
+				"id": args[0], "lines": lines,
+			}, &detail)
+			if formatter.Structured() {
+				formatter.Output(detail)
+				return
+			}
+
+			c := detail.Crash
+			fmt.Printf("💥 %s crashed at %s\n", c.Component, c.Time.Local().Format("2006-01-02 15:04:05"))
+			fmt.Printf("   PID:       %d\n", c.PID)
+			fmt.Printf("   Exit:      %s\n", crashExit(c))
+			if c.Error != "" {
+				fmt.Printf("   Error:     %s\n", c.Error)
+			}
+			fmt.Printf("   Uptime:    %s\n", c.Uptime)
+			fmt.Printf("   Restarts:  %d\n", c.Restarts)
+			fmt.Printf("   Command:   %s %s\n", c.Command, strings.Join(c.Args, " "))
+			switch {
+			case c.CoreFile != "":
+				fmt.Printf("   Core:      %s\n", c.CoreFile)
+			case c.CoreNote != "":
+				fmt.Printf("   Core:      %s\n", c.CoreNote)
+			}
+			fmt.Printf("   Directory: %s\n", c.Dir)
+			fmt.Printf("   Files:     %s\n", strings.Join(c.Files, ", "))
+
+			if len(detail.Output) > 0 {
+				fmt.Printf("\n📜 Last output:\n")
+				for _, line := range detail.Output {
+					fmt.Printf("   %s\n", line)
+				}
+			}
+			return
+		}
+
+		component, _ := cmd.Flags().GetString("component")
+		var dumps []crashDump
+		alertRequest(ipc.MessageTypeDiagnoseCrashes, map[string]interface{}{"component": component}, &dumps)
+		if formatter.Structured() {
+			formatter.Output(dumps)
+			return
+		}
+		if len(dumps) == 0 {
+			fmt.Println("✅ No crash dumps")
+			return
+		}
+
+		fmt.Printf("💥 Crash dumps (%d):\n\n", len(dumps))
+		fmt.Printf("%-44s %-15s %-19s %-20s %s\n", "ID", "COMPONENT", "TIME", "EXIT", "UPTIME")
+		fmt.Println(strings.Repeat("-", 110))
+		for _, c := range dumps {
+			fmt.Printf("%-44s %-15s %-19s %-20s %s\n", c.ID, c.Component,
+				c.Time.Local().Format("2006-01-02 15:04:05"), crashExit(c), c.Uptime)
+		}
+		fmt.Println("\n💡 Use 'tmidb-cli diagnose crashes <id>' for details")
+	},
+}
+
+var diagnoseProfileCmd = &cobra.Command{
+	Use:   "profile <component>",
+	Short: "Capture a pprof profile of a component",
+	Long: `Capture a pprof profile of the supervisor, api, data-manager or data-consumer.
+
+The supervisor fetches the profile from the component's token-protected pprof
+endpoint, keeps a copy in profile_dir (default ./profiles) and returns it, and the
+CLI writes it to --file (default: the supervisor's file name in the current
+directory). Profiling must be enabled with "profiling": true in the supervisor config.
+
+cpu and trace profiles are collected for --duration; the other types are snapshots.
+
+Examples:
+  tmidb-cli diagnose profile api --type heap
+  tmidb-cli diagnose profile data-consumer --type cpu --duration 30s
+  tmidb-cli diagnose profile supervisor --type goroutine -f goroutines.pb.gz`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"supervisor", "api", "data-manager", "data-consumer"},
+	Run: func(cmd *cobra.Command, args []string) {
+		formatter := getFormatter(cmd)
+		component := args[0]
+		profileType, _ := cmd.Flags().GetString("type")
+		duration, _ := cmd.Flags().GetDuration("duration")
+		output, _ := cmd.Flags().GetString("file")
+
+		if _, err := profiling.Path(profileType, duration); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+		timed := profileType == "cpu" || profileType == "trace"
+		if !timed {
+			duration = 0
+		}
+
+		// 수집 시간 동안 응답을 기다려야 하므로 요청 제한 시간을 늘린 클라이언트 사용
+		c, err := newCommandClient(cmd, duration+30*time.Second)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+		defer c.Close()
+
+		if !formatter.Structured() {
+			if timed {
+				fmt.Printf("🔬 Collecting %s profile of %s for %s...\n", profileType, component, duration)
+			} else {
+				fmt.Printf("🔬 Collecting %s profile of %s...\n", profileType, component)
+			}
+		}
+		resp, err := c.SendMessage(ipc.MessageTypeDiagnoseProfile, map[string]interface{}{
+			"component": component,
+			"type":      profileType,
+			"duration":  duration.Seconds(),
+		})
+		if err != nil {
+			fmt.Printf("❌ Failed to communicate with supervisor: %v\n", err)
+			exit(1)
+		}
+		if !resp.Success {
+			fmt.Printf("❌ Error: %s\n", resp.Error)
+			exit(1)
+		}
+		var info profileInfo
+		raw, _ := json.Marshal(resp.Data)
+		if err := json.Unmarshal(raw, &info); err != nil {
+			fmt.Printf("❌ Failed to parse response: %v\n", err)
+			exit(1)
+		}
+
+		if output == "" {
+			output = filepath.Base(info.Path)
+		}
+		if err := os.WriteFile(output, info.Data, 0600); err != nil {
+			fmt.Printf("❌ Failed to write profile: %v\n", err)
+			exit(1)
+		}
+		info.File = output
+		info.Data = nil
+
+		if formatter.Structured() {
+			formatter.Output(info)
+			return
+		}
+		fmt.Printf("✅ Saved %s profile of %s to %s (%s)\n", info.Type, info.Component, output, formatBytes(info.Size))
+		fmt.Printf("   Supervisor copy: %s\n", info.Path)
+		tool := "pprof"
+		if info.Type == "trace" {
+			tool = "trace"
+		}
+		fmt.Printf("\n💡 Analyze with: go tool %s %s\n", tool, output)
+	},
+}
+
+// profileInfo 슈퍼바이저의 ProfileInfo
+type profileInfo struct {
+	Component string    `json:"component"`
+	Type      string    `json:"type"`
+	Duration  string    `json:"duration,omitempty"`
+	Time      time.Time `json:"time"`
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	Data      []byte    `json:"data,omitempty"`
+	File      string    `json:"file,omitempty"` // 로컬에 저장한 경로
+}
+
+// crashDump 슈퍼바이저의 CrashDump
+type crashDump struct {
+	ID         string    `json:"id"`
+	Component  string    `json:"component"`
+	Time       time.Time `json:"time"`
+	PID        int       `json:"pid"`
+	ExitCode   int       `json:"exit_code"`
+	Signal     string    `json:"signal,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Uptime     string    `json:"uptime"`
+	Restarts   int       `json:"restarts"`
+	Command    string    `json:"command"`
+	Args       []string  `json:"args,omitempty"`
+	CoreDumped bool      `json:"core_dumped"`
+	CoreFile   string    `json:"core_file,omitempty"`
+	CoreNote   string    `json:"core_note,omitempty"`
+	Dir        string    `json:"dir"`
+	Files      []string  `json:"files"`
+}
+
+// crashExit 종료 코드 또는 시그널 설명
+func crashExit(c crashDump) string {
+	if c.Signal == "" {
+		return fmt.Sprintf("code %d", c.ExitCode)
+	}
+	exit := c.Signal
+	if c.CoreDumped {
+		exit += " (core)"
+	}
+	return exit
+}
+
+var diagnoseSlowQueriesCmd = &cobra.Command{
+	Use:   "slow-queries",
+	Short: "Show the slowest database queries",
+	Long: `Show the queries that took longer than SLOW_QUERY_MS (default 500ms) in any
+component, grouped by query shape with literals removed. Each entry keeps the
+parameters of its slowest run, so --explain can show the plan PostgreSQL picks
+for it. A sequential scan on a JSONB path (->, ->>, @>) usually means the
+category needs an index on that path.
+
+The plan comes from EXPLAIN without ANALYZE; the query itself is not run again.
+
+Examples:
+  tmidb-cli diagnose slow-queries
+  tmidb-cli diagnose slow-queries --sort max --limit 5 --explain
+  tmidb-cli diagnose slow-queries --reset`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		formatter := getFormatter(cmd)
+
+		if reset, _ := cmd.Flags().GetBool("reset"); reset {
+			var result struct {
+				Deleted int64 `json:"deleted"`
+			}
+			alertRequest(ipc.MessageTypeDiagnoseSlowQueriesReset, nil, &result)
+			if formatter.Structured() {
+				formatter.Output(result)
+				return
+			}
+			fmt.Printf("✅ Cleared %d recorded queries\n", result.Deleted)
+			return
+		}
+
+		sort, _ := cmd.Flags().GetString("sort")
+		limit, _ := cmd.Flags().GetInt("limit")
+		explain, _ := cmd.Flags().GetBool("explain")
+		var queries []database.SlowQuery
+		alertRequest(ipc.MessageTypeDiagnoseSlowQueries, map[string]interface{}{
+			"sort": sort, "limit": limit, "explain": explain,
+		}, &queries)
+		if formatter.Structured() {
+			formatter.Output(queries)
+			return
+		}
+		if len(queries) == 0 {
+			fmt.Println("✅ No slow queries recorded")
+			return
+		}
+
+		fmt.Printf("🐢 Slow queries by %s (%d):\n\n", sort, len(queries))
+		if !explain {
+			fmt.Printf("%-16s %-8s %-10s %-10s %-10s %s\n", "FINGERPRINT", "CALLS", "MEAN", "MAX", "TOTAL", "QUERY")
+			fmt.Println(strings.Repeat("-", 110))
+			for _, q := range queries {
+				fmt.Printf("%-16s %-8d %-10s %-10s %-10s %s\n", q.Fingerprint, q.Calls, formatMillis(q.MeanMs),
+					formatMillis(q.MaxMs), formatMillis(q.TotalMs), truncateText(q.Query, 50))
+			}
+			fmt.Println("\n💡 Use --explain to see the plan of each query's slowest run")
+			return
+		}
+
+		for i, q := range queries {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("── %s  %d calls, mean %s, max %s, total %s (last: %s, %s)\n", q.Fingerprint, q.Calls,
+				formatMillis(q.MeanMs), formatMillis(q.MaxMs), formatMillis(q.TotalMs),
+				q.Component, q.LastSeen.Local().Format("2006-01-02 15:04:05"))
+			fmt.Printf("   %s\n", q.SampleQuery)
+			if len(q.SampleParams) > 0 {
+				params, _ := json.Marshal(q.SampleParams)
+				fmt.Printf("   Params: %s\n", params)
+			}
+			if q.PlanError != "" {
+				fmt.Printf("   ⚠️  No plan: %s\n", q.PlanError)
+				continue
+			}
+			for _, line := range strings.Split(q.Plan, "\n") {
+				fmt.Printf("   │ %s\n", line)
+			}
+			if strings.Contains(q.Plan, "Seq Scan") && (strings.Contains(q.Query, "->") || strings.Contains(q.Query, "@>")) {
+				fmt.Println("   💡 Sequential scan while filtering on a JSONB path: an index on that path may help")
+			}
+		}
+	},
+}
+
+// formatMillis ms를 읽기 쉬운 시간으로
+func formatMillis(ms float64) string {
+	if ms >= 1000 {
+		return fmt.Sprintf("%.2fs", ms/1000)
+	}
+	return fmt.Sprintf("%.0fms", ms)
+}
+
 func init() {
 	// 플래그 설정
 	diagnosePerformanceCmd.Flags().Duration("duration", 30*time.Second, "Duration for performance diagnostics")
 	diagnoseLogsCmd.Flags().Int("hours", 24, "Number of hours to analyze")
 	diagnoseFixCmd.Flags().Bool("dry-run", false, "Show what would be fixed without making changes")
 	diagnoseFixCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+	diagnoseCrashesCmd.Flags().String("component", "", "Only list crashes of this component")
+	diagnoseCrashesCmd.Flags().Int("lines", 20, "Output lines to show for a single crash")
+	diagnoseCrashesCmd.RegisterFlagCompletionFunc("component", completeComponentFlag)
+	diagnoseProfileCmd.Flags().String("type", "heap", "Profile type: "+strings.Join(profiling.Types, ", "))
+	diagnoseProfileCmd.Flags().Duration("duration", profiling.DefaultDuration, "How long to collect cpu and trace profiles")
+	diagnoseProfileCmd.Flags().StringP("file", "f", "", "File to write the profile to (default: the supervisor's file name)")
+	diagnoseSlowQueriesCmd.Flags().String("sort", "total", "Order by: "+strings.Join(database.SlowQuerySorts, ", "))
+	diagnoseSlowQueriesCmd.Flags().Int("limit", 20, "Number of queries to show")
+	diagnoseSlowQueriesCmd.Flags().Bool("explain", false, "Show the plan of each query's slowest run")
+	diagnoseSlowQueriesCmd.Flags().Bool("reset", false, "Clear the recorded queries")
+	diagnoseSlowQueriesCmd.RegisterFlagCompletionFunc("sort", cobra.FixedCompletions(database.SlowQuerySorts, cobra.ShellCompDirectiveNoFileComp))
+	diagnoseProfileCmd.RegisterFlagCompletionFunc("type", cobra.FixedCompletions(profiling.Types, cobra.ShellCompDirectiveNoFileComp))
 
 	// 서브커맨드 추가
 	diagnoseCmd.AddCommand(diagnoseAllCmd)
@@ -581,6 +913,9 @@ func init() {
 	diagnoseCmd.AddCommand(diagnosePerformanceCmd)
 	diagnoseCmd.AddCommand(diagnoseLogsCmd)
 	diagnoseCmd.AddCommand(diagnoseFixCmd)
+	diagnoseCmd.AddCommand(diagnoseCrashesCmd)
+	diagnoseCmd.AddCommand(diagnoseProfileCmd)
+	diagnoseCmd.AddCommand(diagnoseSlowQueriesCmd)
 
 	// 루트 명령어에 추가
 	rootCmd.AddCommand(diagnoseCmd)
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// dlqTimeout 대량 재처리/삭제 요청 대기 시간
+const dlqTimeout = 30 * time.Minute
+
+// dlqMessage 데드레터 메시지 응답
+type dlqMessage struct {
+	Seq        uint64            `json:"seq"`
+	Time       time.Time         `json:"time"`
+	Subject    string            `json:"subject"`
+	Org        string            `json:"org"`
+	Category   string            `json:"category"`
+	TargetID   string            `json:"target_id"`
+	Error      string            `json:"error"`
+	Deliveries string            `json:"deliveries"`
+	ReplayOf   string            `json:"replay_of"`
+	Size       int               `json:"size"`
+	Preview    string            `json:"preview"`
+	Body       string            `json:"body"`
+	Headers    map[string]string `json:"headers"`
+}
+
+// dlqReplayResult 재처리 결과
+type dlqReplayResult struct {
+	Replayed int      `json:"replayed"`
+	Failed   []string `json:"failed"`
+	DryRun   bool     `json:"dry_run"`
+}
+
+// DLQ 명령어
+var dlqCmd = &cobra.Command{
+	Use:   "dlq",
+	Short: "Inspect, fix and replay ingest messages that failed",
+	Long: `Ingest messages that fail validation or cannot be written to the database
+are moved to the TMIDB_INGEST_DLQ stream with the reason. These commands list
+them, fix a payload in an editor and send messages back through the ingest
+pipeline.
+
+Replayed messages are removed from the queue. A message that fails again is
+dead-lettered anew with a new sequence and a Tmidb-Ingest-Replay-Of header.`,
+}
+
+var dlqListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List failed ingest messages with their error",
+	Long: `List dead-lettered ingest messages, oldest first.
+
+Examples:
+  tmidb-cli dlq list
+  tmidb-cli dlq list --category sensor --error "schema validation"
+  tmidb-cli dlq list --since 1h -o json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		data := dlqFilterFlags(cmd)
+		if limit, _ := cmd.Flags().GetInt("limit"); limit > 0 {
+			data["limit"] = limit
+		}
+
+		var result struct {
+			Messages []dlqMessage `json:"messages"`
+			Total    int          `json:"total"`
+		}
+		commandRequest(cmd, dlqTimeout, ipc.MessageTypeDLQList, data, &result)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(result)
+			return
+		}
+
+		if result.Total == 0 {
+			fmt.Println("✅ No failed ingest messages")
+			return
+		}
+		fmt.Printf("📭 Dead-lettered ingest messages (%d):\n\n", result.Total)
+		fmt.Printf("%-8s %-20s %-20s %-16s %-20s %s\n", "SEQ", "TIME", "ORG", "CATEGORY", "TARGET", "ERROR")
+		fmt.Println(strings.Repeat("-", 110))
+		for _, m := range result.Messages {
+			fmt.Printf("%-8d %-20s %-20s %-16s %-20s %s\n",
+				m.Seq, m.Time.Local().Format("2006-01-02 15:04:05"), m.Org, m.Category, m.TargetID, m.Error)
+		}
+		if result.Total > len(result.Messages) {
+			fmt.Printf("\n... %d more (use --limit or narrow the filter)\n", result.Total-len(result.Messages))
+		}
+	},
+}
+
+var dlqShowCmd = &cobra.Command{
+	Use:   "show <seq>",
+	Short: "Show a failed ingest message with its payload and headers",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		m := dlqShow(cmd, args[0])
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(m)
+			return
+		}
+
+		fmt.Printf("📄 Dead-letter message %d\n", m.Seq)
+		fmt.Printf("   Time:       %s\n", m.Time.Local().Format("2006-01-02 15:04:05"))
+		fmt.Printf("   Subject:    %s\n", m.Subject)
+		if m.TargetID != "" {
+			fmt.Printf("   Target:     %s\n", m.TargetID)
+		}
+		fmt.Printf("   Error:      %s\n", m.Error)
+		if m.Deliveries != "" {
+			fmt.Printf("   Deliveries: %s\n", m.Deliveries)
+		}
+		if m.ReplayOf != "" {
+			fmt.Printf("   Replay of:  %s\n", m.ReplayOf)
+		}
+		if len(m.Headers) > 0 {
+			fmt.Println("   Headers:")
+			keys := make([]string, 0, len(m.Headers))
+			for key := range m.Headers {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				fmt.Printf("     %s: %s\n", key, m.Headers[key])
+			}
+		}
+		fmt.Println("\n" + indentJSON(m.Body))
+	},
+}
+
+var dlqEditCmd = &cobra.Command{
+	Use:   "edit <seq>",
+	Short: "Fix a failed message's payload in an editor and replay it",
+	Long: `Open the payload of a dead-lettered message in $VISUAL or $EDITOR and
+replay the saved version through the ingest pipeline. Nothing is replayed if
+the payload is left unchanged.
+
+Examples:
+  tmidb-cli dlq edit 42
+  EDITOR=nano tmidb-cli dlq edit 42`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		m := dlqShow(cmd, args[0])
+		fmt.Printf("Error: %s\n", m.Error)
+
+		file, err := os.CreateTemp("", "tmidb-dlq-*.json")
+		if err != nil {
+			fmt.Printf("❌ Failed to create temp file: %v\n", err)
+			exit(1)
+		}
+		defer os.Remove(file.Name())
+		original := indentJSON(m.Body) + "\n"
+		if _, err := file.WriteString(original); err != nil {
+			file.Close()
+			fmt.Printf("❌ Failed to write temp file: %v\n", err)
+			exit(1)
+		}
+		file.Close()
+
+		for {
+			if err := runEditor(file.Name()); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				exit(1)
+			}
+			edited, err := os.ReadFile(file.Name())
+			if err != nil {
+				fmt.Printf("❌ Failed to read edited file: %v\n", err)
+				exit(1)
+			}
+			if string(edited) == original {
+				fmt.Println("No changes, nothing replayed")
+				return
+			}
+
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, edited); err != nil {
+				fmt.Printf("❌ Invalid JSON: %v\n", err)
+				fmt.Print("Edit again? (y/n): ")
+				var response string
+				fmt.Scanln(&response)
+				if response == "y" || response == "yes" {
+					continue
+				}
+				fmt.Println("❌ Edit cancelled")
+				return
+			}
+
+			var result dlqReplayResult
+			commandRequest(cmd, dlqTimeout, ipc.MessageTypeDLQReplay, map[string]interface{}{
+				"seqs": []uint64{m.Seq},
+				"body": compact.String(),
+			}, &result)
+			printDLQReplay(result)
+			return
+		}
+	},
+}
+
+var dlqReplayCmd = &cobra.Command{
+	Use:   "replay [seq...]",
+	Short: "Send failed messages back through the ingest pipeline",
+	Long: `Republish dead-lettered messages to their original ingest subjects and
+remove them from the queue. Select messages by sequence, by filter, or all of
+them with --all.
+
+Examples:
+  tmidb-cli dlq replay 42 43
+  tmidb-cli dlq replay --category sensor --error "connection refused"
+  tmidb-cli dlq replay --all --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		data := dlqSelection(cmd, args)
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		keep, _ := cmd.Flags().GetBool("keep")
+		data["dry_run"] = dryRun
+		data["keep"] = keep
+
+		var result dlqReplayResult
+		commandRequest(cmd, dlqTimeout, ipc.MessageTypeDLQReplay, data, &result)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(result)
+			return
+		}
+		printDLQReplay(result)
+		if len(result.Failed) > 0 {
+			exit(1)
+		}
+	},
+}
+
+var dlqDeleteCmd = &cobra.Command{
+	Use:   "delete [seq...]",
+	Short: "Drop failed messages without replaying them",
+	Long: `Remove dead-lettered messages from the queue. Select messages by sequence,
+by filter, or all of them with --all.
+
+Examples:
+  tmidb-cli dlq delete 42
+  tmidb-cli dlq delete --org acme --since 24h --yes`,
+	Run: func(cmd *cobra.Command, args []string) {
+		data := dlqSelection(cmd, args)
+
+		if len(args) == 0 && !cmd.Flag("yes").Changed {
+			var preview struct {
+				Total int `json:"total"`
+			}
+			commandRequest(cmd, dlqTimeout, ipc.MessageTypeDLQList, dlqFilterFlags(cmd), &preview)
+			if preview.Total == 0 {
+				fmt.Println("✅ No matching messages")
+				return
+			}
+			fmt.Printf("⚠️  %d message(s) will be deleted and cannot be replayed. Continue? (yes/no): ", preview.Total)
+			var response string
+			fmt.Scanln(&response)
+			if response != "yes" {
+				fmt.Println("❌ Delete cancelled")
+				return
+			}
+		}
+
+		var result struct {
+			Deleted int `json:"deleted"`
+		}
+		commandRequest(cmd, dlqTimeout, ipc.MessageTypeDLQDelete, data, &result)
+		fmt.Printf("🗑️  %d message(s) deleted\n", result.Deleted)
+	},
+}
+
+// dlqFilterFlags 필터 플래그를 요청 데이터로 변환
+func dlqFilterFlags(cmd *cobra.Command) map[string]interface{} {
+	data := map[string]interface{}{}
+	for _, name := range []string{"org", "category", "error", "since"} {
+		if value, _ := cmd.Flags().GetString(name); value != "" {
+			data[name] = value
+		}
+	}
+	return data
+}
+
+// dlqSelection 시퀀스 인자 또는 필터로 대상 메시지를 지정
+func dlqSelection(cmd *cobra.Command, args []string) map[string]interface{} {
+	data := dlqFilterFlags(cmd)
+	all, _ := cmd.Flags().GetBool("all")
+	if len(args) > 0 {
+		if len(data) > 0 || all {
+			fmt.Println("❌ Give sequences or filters, not both")
+			exit(1)
+		}
+		seqs := make([]uint64, 0, len(args))
+		for _, arg := range args {
+			seq, err := strconv.ParseUint(arg, 10, 64)
+			if err != nil || seq == 0 {
+				fmt.Printf("❌ Invalid sequence: %s\n", arg)
+				exit(1)
+			}
+			seqs = append(seqs, seq)
+		}
+		data["seqs"] = seqs
+		return data
+	}
+	if len(data) == 0 && !all {
+		fmt.Println("❌ Give message sequences, a filter (--org, --category, --error, --since) or --all")
+		exit(1)
+	}
+	data["all"] = all
+	return data
+}
+
+// dlqShow 메시지 하나를 본문과 함께 가져옴
+func dlqShow(cmd *cobra.Command, arg string) dlqMessage {
+	seq, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil || seq == 0 {
+		fmt.Printf("❌ Invalid sequence: %s\n", arg)
+		exit(1)
+	}
+	var m dlqMessage
+	commandRequest(cmd, dlqTimeout, ipc.MessageTypeDLQShow, map[string]interface{}{"seqs": []uint64{seq}}, &m)
+	return m
+}
+
+// printDLQReplay 재처리 결과 출력
+func printDLQReplay(result dlqReplayResult) {
+	if result.DryRun {
+		fmt.Printf("🔍 %d message(s) would be replayed\n", result.Replayed)
+		return
+	}
+	fmt.Printf("🔁 %d message(s) replayed\n", result.Replayed)
+	for _, failure := range result.Failed {
+		fmt.Printf("   ❌ %s\n", failure)
+	}
+	if result.Replayed > 0 {
+		fmt.Println("   Messages that fail again show up in 'tmidb-cli dlq list' with a new sequence.")
+	}
+}
+
+// indentJSON 본문이 JSON이면 들여쓰기해서 반환
+func indentJSON(body string) string {
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(body), "", "  "); err != nil {
+		return body
+	}
+	return out.String()
+}
+
+func init() {
+	for _, c := range []*cobra.Command{dlqListCmd, dlqReplayCmd, dlqDeleteCmd} {
+		c.Flags().String("org", "", "Only messages of this organization ID")
+		c.Flags().String("category", "", "Only messages of this category")
+		c.Flags().String("error", "", "Only messages whose error contains this text")
+		c.Flags().String("since", "", "Only messages dead-lettered within this duration (e.g. 1h)")
+	}
+	dlqListCmd.Flags().Int("limit", 100, "Maximum number of messages to show")
+	for _, c := range []*cobra.Command{dlqReplayCmd, dlqDeleteCmd} {
+		c.Flags().Bool("all", false, "Select every message in the queue")
+	}
+	dlqReplayCmd.Flags().Bool("dry-run", false, "Only count the messages that would be replayed")
+	dlqReplayCmd.Flags().Bool("keep", false, "Keep replayed messages in the queue")
+	dlqDeleteCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+
+	dlqCmd.AddCommand(dlqListCmd)
+	dlqCmd.AddCommand(dlqShowCmd)
+	dlqCmd.AddCommand(dlqEditCmd)
+	dlqCmd.AddCommand(dlqReplayCmd)
+	dlqCmd.AddCommand(dlqDeleteCmd)
+	rootCmd.AddCommand(dlqCmd)
+}
@@ -0,0 +1,461 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Manage whole tmiDB environments",
+	Long:  "Operations that span several tmiDB instances, such as refreshing staging from production",
+}
+
+var envCloneCmd = &cobra.Command{
+	Use:   "clone --to <host:port>",
+	Short: "Copy the data of this environment onto another one",
+	Long: `Back up the source supervisor, send the backup to the target supervisor and
+restore it there, all in one step.
+
+The source is the supervisor the CLI normally talks to (the local socket, or
+--addr). The target is given with --to and is reached over mutual TLS with the
+certificates in --to-tls-dir. The backup travels from the source to a one-time,
+token-protected TLS copy receiver on the target, so the source must be able to
+reach --copy-host:--copy-port.
+
+With --org only the given organizations (ID or name) are kept on the target;
+every other organization is deleted there after the restore. Config is not
+cloned by default so the target keeps its own ports and secrets.
+
+Examples:
+  tmidb-cli env clone --addr prod:7443 --to staging:7443
+  tmidb-cli env clone --to staging:7443 --org "Acme Corp" --org Globex --yes
+  tmidb-cli env clone --to staging:7443 --passphrase-file /etc/tmidb/clone.key`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		to, _ := cmd.Flags().GetString("to")
+		if to == "" {
+			fmt.Println("❌ --to is required")
+			exit(1)
+		}
+		if addr, _ := cmd.Flags().GetString("addr"); addr == to {
+			fmt.Println("❌ Source and target are the same supervisor")
+			exit(1)
+		}
+
+		copyHost, _ := cmd.Flags().GetString("copy-host")
+		if copyHost == "" {
+			host, _, err := net.SplitHostPort(to)
+			if err != nil {
+				fmt.Printf("❌ Invalid --to address: %v\n", err)
+				exit(1)
+			}
+			copyHost = host
+		}
+
+		passphrase, err := readPassphraseFlag(cmd)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+
+		target, err := envTargetClient(cmd, to)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+		defer target.Close()
+		client.KeepAlive()
+		defer client.Close()
+
+		clone := &envClone{
+			source:     client,
+			target:     target,
+			targetAddr: to,
+			copyHost:   copyHost,
+			passphrase: passphrase,
+		}
+		clone.components, _ = cmd.Flags().GetStringSlice("components")
+		clone.orgs, _ = cmd.Flags().GetStringSlice("org")
+		clone.copyPort, _ = cmd.Flags().GetInt("copy-port")
+		clone.path, _ = cmd.Flags().GetString("path")
+		clone.keepBackup, _ = cmd.Flags().GetBool("keep-backup")
+
+		if err := clone.preflight(); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+
+		fmt.Printf("🧬 Cloning environment to %s\n", to)
+		fmt.Printf("   Components: %s\n", strings.Join(clone.components, ", "))
+		if len(clone.keep) > 0 {
+			names := make([]string, len(clone.keep))
+			for i, org := range clone.keep {
+				names[i] = org.Name
+			}
+			fmt.Printf("   Organizations: %s\n", strings.Join(names, ", "))
+		} else {
+			fmt.Println("   Organizations: all")
+		}
+		fmt.Printf("   Transfer: %s:%d (TLS, one-time token)\n", copyHost, clone.copyPort)
+
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+			fmt.Printf("\n⚠️  WARNING: The data on %s will be replaced. This cannot be undone.\n", to)
+			fmt.Print("Are you SURE you want to continue? (yes/no): ")
+			var response string
+			fmt.Scanln(&response)
+			if response != "yes" {
+				fmt.Println("❌ Clone cancelled")
+				return
+			}
+		}
+
+		started := time.Now()
+		if err := clone.run(); err != nil {
+			fmt.Printf("\n❌ Clone failed: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("\n✅ %s now has a copy of the source data (%s)\n", to, formatDuration(time.Since(started)))
+	},
+}
+
+// envTargetClient는 --to 슈퍼바이저용 클라이언트를 소스와 같은 연결 설정으로 만듭니다
+func envTargetClient(cmd *cobra.Command, addr string) (*ipc.Client, error) {
+	opts := ipc.DefaultClientOptions()
+	opts.RequestTimeout, _ = cmd.Flags().GetDuration("timeout")
+	opts.ConnectTimeout, _ = cmd.Flags().GetDuration("connect-timeout")
+	opts.MaxRetries, _ = cmd.Flags().GetInt("retries")
+
+	token, err := readTokenFlag(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if path, _ := cmd.Flags().GetString("to-token-file"); path != "" {
+		if token, err = readTokenFile(path); err != nil {
+			return nil, err
+		}
+	}
+	opts.Token = token
+
+	tlsDir, _ := cmd.Flags().GetString("to-tls-dir")
+	if tlsDir == "" {
+		tlsDir, _ = cmd.Flags().GetString("tls-dir")
+	}
+	c, err := newRemoteClient(addr, tlsDir, opts)
+	if err != nil {
+		return nil, err
+	}
+	c.KeepAlive()
+	return c, nil
+}
+
+// envClone 소스 백업 → 대상으로 전송 → 대상 복원 → 조직 정리 순서의 복제 작업
+type envClone struct {
+	source, target *ipc.Client
+	targetAddr     string
+	copyHost       string
+	copyPort       int
+	path           string
+	components     []string
+	orgs           []string
+	passphrase     string
+	keepBackup     bool
+
+	keep     []database.Organization // 대상에 남길 조직 (비어 있으면 전부)
+	backupID string
+}
+
+// envRequest는 c로 요청을 보내고 응답 데이터를 out에 디코딩합니다
+func envRequest(c *ipc.Client, msgType ipc.MessageType, data map[string]interface{}, out interface{}) error {
+	resp, err := c.SendMessage(msgType, data)
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return fmt.Errorf("%s", resp.Error)
+	}
+	if out == nil {
+		return nil
+	}
+	raw, _ := json.Marshal(resp.Data)
+	return json.Unmarshal(raw, out)
+}
+
+// preflight는 두 슈퍼바이저에 연결되는지 확인하고 --org를 소스의 조직으로 해석합니다
+func (e *envClone) preflight() error {
+	if err := e.source.Ping(); err != nil {
+		return fmt.Errorf("source supervisor is not responding: %v", err)
+	}
+	if err := e.target.Ping(); err != nil {
+		return fmt.Errorf("target supervisor %s is not responding: %v", e.targetAddr, err)
+	}
+	if len(e.orgs) == 0 {
+		return nil
+	}
+
+	var orgs []database.Organization
+	if err := envRequest(e.source, ipc.MessageTypeOrgList, nil, &orgs); err != nil {
+		return fmt.Errorf("failed to list source organizations: %v", err)
+	}
+	keep, err := selectOrganizations(orgs, e.orgs)
+	if err != nil {
+		return err
+	}
+	e.keep = keep
+	return nil
+}
+
+// selectOrganizations는 ID나 이름으로 지정된 조직을 찾습니다
+func selectOrganizations(orgs []database.Organization, refs []string) ([]database.Organization, error) {
+	var selected []database.Organization
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		found := false
+		for _, org := range orgs {
+			if org.OrgID != ref && org.Name != ref {
+				continue
+			}
+			found = true
+			if !seen[org.OrgID] {
+				seen[org.OrgID] = true
+				selected = append(selected, org)
+			}
+			break
+		}
+		if !found {
+			return nil, fmt.Errorf("organization %q not found on the source", ref)
+		}
+	}
+	return selected, nil
+}
+
+// pruneOrganizations는 orgs 중 keep에 없는 조직을 돌려줍니다
+func pruneOrganizations(orgs, keep []database.Organization) []database.Organization {
+	kept := make(map[string]bool, len(keep))
+	for _, org := range keep {
+		kept[org.OrgID] = true
+	}
+	var prune []database.Organization
+	for _, org := range orgs {
+		if !kept[org.OrgID] {
+			prune = append(prune, org)
+		}
+	}
+	return prune
+}
+
+func (e *envClone) run() (err error) {
+	steps := 5
+	if len(e.keep) > 0 {
+		steps++
+	}
+	step := 0
+	next := func(format string, args ...interface{}) {
+		step++
+		fmt.Printf("\n[%d/%d] %s\n", step, steps, fmt.Sprintf(format, args...))
+	}
+
+	next("Creating backup on the source")
+	backupPath, err := e.backup()
+	if err != nil {
+		return fmt.Errorf("backup failed: %v", err)
+	}
+	// 실패해도 복제용으로 만든 백업은 소스에서 정리
+	defer func() {
+		if err != nil {
+			e.cleanup()
+		}
+	}()
+
+	next("Sending backup to %s", e.targetAddr)
+	received, err := e.transfer(backupPath)
+	if err != nil {
+		return fmt.Errorf("transfer failed: %v", err)
+	}
+
+	next("Restoring on the target")
+	if err := e.restore(received); err != nil {
+		return fmt.Errorf("restore failed: %v", err)
+	}
+
+	if len(e.keep) > 0 {
+		next("Removing organizations that were not selected")
+		if err := e.filterOrganizations(); err != nil {
+			return fmt.Errorf("organization filtering failed: %v", err)
+		}
+	}
+
+	next("Restarting services on the target")
+	if err := envRequest(e.target, ipc.MessageTypeProcessRestart, map[string]interface{}{
+		"component": "all",
+	}, nil); err != nil {
+		return fmt.Errorf("failed to restart services: %v", err)
+	}
+	fmt.Println("   🔄 Restart requested")
+
+	next("Cleaning up")
+	e.cleanup()
+	fmt.Printf("   📁 Backup kept on the target: %s\n", received)
+	return nil
+}
+
+func (e *envClone) backup() (string, error) {
+	var started struct {
+		ID   string `json:"id"`
+		Path string `json:"path"`
+	}
+	if err := envRequest(e.source, ipc.MessageTypeBackupCreate, map[string]interface{}{
+		"name":       "tmidb-clone-" + time.Now().Format("20060102-150405"),
+		"components": e.components,
+		"compress":   true,
+		"encrypt":    e.passphrase != "",
+		"passphrase": e.passphrase,
+	}, &started); err != nil {
+		return "", err
+	}
+	e.backupID = started.ID
+
+	if err := monitorBackupProgress(e.source, started.ID); err != nil {
+		return "", err
+	}
+	return started.Path, nil
+}
+
+// transfer는 대상에 일회용 수신기를 열고 소스가 백업을 보내게 한 뒤, 대상에 저장된 파일 경로를 반환합니다
+func (e *envClone) transfer(backupPath string) (string, error) {
+	var receiver struct {
+		ID          string `json:"id"`
+		Fingerprint string `json:"tls_fingerprint"`
+		Token       string `json:"token"`
+	}
+	if err := envRequest(e.target, ipc.MessageTypeCopyReceive, map[string]interface{}{
+		"port":          e.copyPort,
+		"path":          e.path,
+		"tls":           true,
+		"require_token": true,
+	}, &receiver); err != nil {
+		return "", fmt.Errorf("failed to start receiver on the target: %v", err)
+	}
+	fmt.Printf("   🔒 Receiver %s (TLS fingerprint %s)\n", receiver.ID, receiver.Fingerprint)
+
+	var sender struct {
+		ID string `json:"id"`
+	}
+	if err := envRequest(e.source, ipc.MessageTypeCopySend, map[string]interface{}{
+		"file_path":       backupPath,
+		"target_host":     e.copyHost,
+		"target_port":     e.copyPort,
+		"tls":             true,
+		"tls_fingerprint": receiver.Fingerprint,
+		"token":           receiver.Token,
+	}, &sender); err != nil {
+		envRequest(e.target, ipc.MessageTypeCopyStop, map[string]interface{}{"session_id": receiver.ID}, nil)
+		return "", err
+	}
+
+	return monitorCopyTransfer(e.source, e.target, sender.ID, receiver.ID)
+}
+
+// monitorCopyTransfer는 전송이 끝날 때까지 진행 상황을 표시하고 수신된 파일 경로를 반환합니다
+func monitorCopyTransfer(source, target *ipc.Client, sendID, receiveID string) (string, error) {
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		var sent, received ipc.CopySession
+		if err := envRequest(source, ipc.MessageTypeCopyStatus, map[string]interface{}{"session_id": sendID}, &sent); err != nil {
+			return "", err
+		}
+		if err := envRequest(target, ipc.MessageTypeCopyStatus, map[string]interface{}{"session_id": receiveID}, &received); err != nil {
+			return "", err
+		}
+
+		percent := 0
+		if sent.FileSize > 0 {
+			percent = int(sent.Transferred * 100 / sent.FileSize)
+		}
+		filled := 30 * percent / 100
+		fmt.Printf("\r   %s%s %3d%% %s / %s (%.1f MB/s)", strings.Repeat("█", filled), strings.Repeat("░", 30-filled),
+			percent, formatBytes(sent.Transferred), formatBytes(sent.FileSize), sent.Speed)
+
+		switch {
+		case sent.Status == "failed":
+			fmt.Println()
+			return "", fmt.Errorf("%s", sent.Error)
+		case received.Status == "failed" || received.Status == "stopped":
+			fmt.Println()
+			if received.Error == "" {
+				received.Error = "receiver " + received.Status
+			}
+			return "", fmt.Errorf("%s", received.Error)
+		case received.Status == "completed":
+			fmt.Println()
+			return received.File, nil
+		}
+	}
+	return "", nil
+}
+
+func (e *envClone) restore(backupPath string) error {
+	var started struct {
+		ID string `json:"id"`
+	}
+	if err := envRequest(e.target, ipc.MessageTypeBackupRestore, map[string]interface{}{
+		"backup":     backupPath,
+		"components": e.components,
+		"passphrase": e.passphrase,
+	}, &started); err != nil {
+		return err
+	}
+	return monitorRestoreProgress(e.target, started.ID)
+}
+
+func (e *envClone) filterOrganizations() error {
+	var orgs []database.Organization
+	if err := envRequest(e.target, ipc.MessageTypeOrgList, nil, &orgs); err != nil {
+		return err
+	}
+	for _, org := range pruneOrganizations(orgs, e.keep) {
+		if err := envRequest(e.target, ipc.MessageTypeOrgDelete, map[string]interface{}{"org": org.OrgID}, nil); err != nil {
+			return fmt.Errorf("failed to delete %s: %v", org.Name, err)
+		}
+		fmt.Printf("   🗑️  %s (%s)\n", org.Name, org.OrgID)
+	}
+	return nil
+}
+
+// cleanup은 --keep-backup이 아니면 소스에 만든 복제용 백업을 지웁니다
+func (e *envClone) cleanup() {
+	if e.backupID == "" || e.keepBackup {
+		return
+	}
+	if err := envRequest(e.source, ipc.MessageTypeBackupDelete, map[string]interface{}{"id": e.backupID}, nil); err != nil {
+		fmt.Printf("   ⚠️  Failed to delete the source backup %s: %v\n", e.backupID, err)
+		return
+	}
+	fmt.Printf("   🧹 Source backup %s deleted\n", e.backupID)
+}
+
+func init() {
+	envCloneCmd.Flags().String("to", "", "Target supervisor address (host:port)")
+	envCloneCmd.Flags().String("to-tls-dir", "", "Directory with ca.crt, client.crt and client.key for --to (default: --tls-dir)")
+	envCloneCmd.Flags().String("to-token-file", "", "File containing an IPC auth token for --to (default: --token-file)")
+	envCloneCmd.Flags().StringSlice("org", nil, "Keep only these organizations on the target (ID or name, repeatable)")
+	envCloneCmd.Flags().StringSlice("components", []string{"database", "files"}, "Components to clone")
+	envCloneCmd.Flags().String("copy-host", "", "Host the source uses to reach the target's copy receiver (default: host of --to)")
+	envCloneCmd.Flags().Int("copy-port", 8080, "Port of the copy receiver on the target")
+	envCloneCmd.Flags().String("path", "./backups", "Directory on the target where the backup is received")
+	envCloneCmd.Flags().String("passphrase-file", "", "Encrypt the backup in transit and at rest with this passphrase")
+	envCloneCmd.Flags().Bool("keep-backup", false, "Keep the backup on the source after cloning")
+	envCloneCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+
+	envCmd.AddCommand(envCloneCmd)
+	rootCmd.AddCommand(envCmd)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+)
+
+func TestCloneOrganizationFilter(t *testing.T) {
+	orgs := []database.Organization{
+		{OrgID: "org-1", Name: "Acme Corp"},
+		{OrgID: "org-2", Name: "Globex"},
+		{OrgID: "org-3", Name: "Initech"},
+	}
+
+	// ID와 이름 모두 허용, 같은 조직을 두 번 지정해도 한 번만
+	keep, err := selectOrganizations(orgs, []string{"Acme Corp", "org-3", "org-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keep) != 2 || keep[0].OrgID != "org-1" || keep[1].OrgID != "org-3" {
+		t.Fatalf("unexpected selection: %+v", keep)
+	}
+
+	prune := pruneOrganizations(orgs, keep)
+	if len(prune) != 1 || prune[0].OrgID != "org-2" {
+		t.Fatalf("unexpected prune list: %+v", prune)
+	}
+
+	if _, err := selectOrganizations(orgs, []string{"Umbrella"}); err == nil {
+		t.Fatal("expected an error for an unknown organization")
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+
+	"github.com/spf13/cobra"
+)
+
+var eventsCmd = &cobra.Command{
+	Use:   "events [--type TYPE...] [-o FORMAT]",
+	Short: "Stream supervisor lifecycle events",
+	Long: `Subscribe to supervisor events such as process starts, crashes, completed
+backups and configuration changes. Types may end in '*' to match a prefix
+(e.g. --type 'process.*'). With -o json (or --json) each event is printed as
+one JSON line; -o yaml and -o template='...' print each event the same way.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		types, _ := cmd.Flags().GetStringSlice("type")
+		formatter := getFormatter(cmd)
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			formatter = NewOutputFormatter("json")
+		}
+
+		if err := followEvents(types, formatter); err != nil {
+			fmt.Printf("❌ Failed to subscribe to events: %v\n", err)
+			exit(1)
+		}
+	},
+}
+
+// followEvents 이벤트를 Ctrl+C 또는 스트림 종료까지 출력
+func followEvents(types []string, formatter *OutputFormatter) error {
+	eventChan, err := client.SubscribeEvents(types)
+	if err != nil {
+		return err
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	structured := formatter.Structured()
+	if !structured {
+		fmt.Println("📣 Listening for supervisor events (Press Ctrl+C to stop)")
+	}
+
+	for {
+		select {
+		case event, ok := <-eventChan:
+			if !ok {
+				if !structured {
+					fmt.Println("📣 Event stream ended")
+				}
+				return nil
+			}
+			if structured {
+				formatter.Output(event)
+				continue
+			}
+			printEvent(event)
+		case <-sigChan:
+			client.Close()
+			return nil
+		}
+	}
+}
+
+// printEvent 이벤트 한 줄 출력
+func printEvent(event ipc.Event) {
+	icon := "🔹"
+	switch event.Type {
+	case ipc.EventProcessStarted:
+		icon = "🚀"
+	case ipc.EventProcessStopped:
+		icon = "🛑"
+	case ipc.EventProcessCrashed, ipc.EventBackupFailed, ipc.EventUpgradeFailed:
+		icon = "❌"
+	case ipc.EventHealthDegraded:
+		icon = "⚠️"
+	case ipc.EventBackupCompleted, ipc.EventUpgradeComplete:
+		icon = "✅"
+	case ipc.EventConfigChanged:
+		icon = "⚙️"
+	}
+
+	line := fmt.Sprintf("[%s] %s %-18s", event.Timestamp.Format("15:04:05"), icon, event.Type)
+	if event.Component != "" {
+		line += " " + event.Component
+	}
+	if event.Message != "" {
+		line += ": " + event.Message
+	}
+
+	if len(event.Data) > 0 {
+		keys := make([]string, 0, len(event.Data))
+		for key := range event.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		pairs := make([]string, len(keys))
+		for i, key := range keys {
+			pairs[i] = fmt.Sprintf("%s=%v", key, event.Data[key])
+		}
+		line += " (" + strings.Join(pairs, ", ") + ")"
+	}
+
+	fmt.Println(line)
+}
+
+func init() {
+	eventsCmd.Flags().StringSliceP("type", "t", nil, "Event types to receive (default: all)")
+	eventsCmd.Flags().Bool("json", false, "Print events as JSON lines (same as -o json)")
+
+	rootCmd.AddCommand(eventsCmd)
+}
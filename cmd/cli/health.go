@@ -0,0 +1,278 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// 전체 상태와 종료 코드 (0 healthy, 1 degraded, 2 down)
+const (
+	healthHealthy  = "healthy"
+	healthDegraded = "degraded"
+	healthDown     = "down"
+)
+
+var monitorHealthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Check overall system health",
+	Long: `Perform a quick health check of all components.
+
+The overall state is:
+  healthy   every component is running and no health probe is failing
+  degraded  some components are stopped or failing their health probes
+  down      the supervisor is unreachable or no component is running
+
+The exit code reflects the state (0 healthy, 1 degraded, 2 down), so scripts
+and schedulers can gate on it. With --fail-on only states at least as bad as
+the given one exit non-zero. With --watch the check repeats every --interval;
+combined with --fail-on it exits as soon as that state is reached.
+
+Examples:
+  tmidb-cli monitor health
+  tmidb-cli monitor health --fail-on down && deploy.sh
+  tmidb-cli monitor health --watch --interval 10s
+  tmidb-cli monitor health --watch --fail-on degraded -o json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		watch, _ := cmd.Flags().GetBool("watch")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		failOn, _ := cmd.Flags().GetString("fail-on")
+		switch failOn {
+		case "", healthDegraded, healthDown:
+		default:
+			fmt.Printf("❌ Invalid --fail-on %q (use degraded or down)\n", failOn)
+			exit(1)
+		}
+		if watch && interval < time.Second {
+			fmt.Println("❌ --interval must be at least 1s")
+			exit(1)
+		}
+
+		formatter := getFormatter(cmd)
+		structured := formatter.Structured()
+
+		report := func(summary map[string]interface{}, previous string) {
+			if structured {
+				formatter.Output(summary)
+				return
+			}
+			if watch {
+				printHealthLine(summary, previous)
+			} else {
+				printHealthSummary(summary)
+			}
+		}
+
+		if !watch {
+			summary := checkHealth()
+			report(summary, "")
+			if code := healthExitCode(summary["status"].(string), failOn); code != 0 {
+				exit(code)
+			}
+			return
+		}
+
+		// 감시 중에는 매번 다시 연결하지 않도록 연결 유지
+		client.KeepAlive()
+		defer client.Close()
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(sigChan)
+
+		if !structured {
+			fmt.Printf("🏥 Watching health every %s (Press Ctrl+C to stop)\n", interval)
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		last := ""
+		for {
+			summary := checkHealth()
+			status := summary["status"].(string)
+			report(summary, last)
+			last = status
+
+			if failOn != "" && healthFailed(status, failOn) {
+				exit(healthExitCode(status, failOn))
+				return
+			}
+
+			select {
+			case <-ticker.C:
+			case <-sigChan:
+				if !structured {
+					fmt.Println("\n🏥 Health watch stopped")
+				}
+				if code := healthExitCode(last, failOn); code != 0 {
+					exit(code)
+				}
+				return
+			}
+		}
+	},
+}
+
+// checkHealth는 슈퍼바이저와 컴포넌트 상태를 확인해 요약을 반환합니다
+func checkHealth() map[string]interface{} {
+	summary := map[string]interface{}{
+		"checked_at": time.Now().UTC().Format(time.RFC3339),
+	}
+
+	if err := client.Ping(); err != nil {
+		summary["status"] = healthDown
+		summary["supervisor_status"] = "unreachable"
+		summary["problems"] = []string{fmt.Sprintf("supervisor is not responding: %v", err)}
+		return summary
+	}
+
+	processes, err := client.GetProcessList()
+	if err != nil {
+		summary["status"] = healthDown
+		summary["supervisor_status"] = "error"
+		summary["problems"] = []string{fmt.Sprintf("failed to get process status: %v", err)}
+		return summary
+	}
+
+	status, running, problems := evaluateHealth(processes)
+	total := len(processes)
+	percentage := 100.0
+	if total > 0 {
+		percentage = float64(running) / float64(total) * 100
+	}
+
+	summary["status"] = status
+	summary["supervisor_status"] = "healthy"
+	summary["total_components"] = total
+	summary["healthy_components"] = running
+	summary["unhealthy_components"] = total - running
+	summary["health_percentage"] = percentage
+	summary["problems"] = problems
+	summary["components"] = processes
+	return summary
+}
+
+// evaluateHealth는 프로세스 목록으로 전체 상태, 실행 중인 컴포넌트 수, 문제 목록을 계산합니다
+func evaluateHealth(processes []ipc.ProcessInfo) (string, int, []string) {
+	running := 0
+	problems := []string{}
+	for _, process := range processes {
+		if process.Status != "running" {
+			problems = append(problems, fmt.Sprintf("%s is %s", process.Name, process.Status))
+			continue
+		}
+		running++
+
+		if health := process.Health; health != nil && (health.Degraded || !health.Healthy) {
+			problem := fmt.Sprintf("%s %s probe is %s", process.Name, health.Type, topHealth(health))
+			if health.Message != "" {
+				problem += ": " + health.Message
+			}
+			problems = append(problems, problem)
+		}
+	}
+
+	switch {
+	case len(processes) > 0 && running == 0:
+		return healthDown, running, problems
+	case len(problems) > 0:
+		return healthDegraded, running, problems
+	}
+	return healthHealthy, running, problems
+}
+
+// healthFailed는 상태가 failOn 이상으로 나쁜지 확인합니다
+func healthFailed(status, failOn string) bool {
+	switch failOn {
+	case healthDegraded:
+		return status == healthDegraded || status == healthDown
+	case healthDown:
+		return status == healthDown
+	}
+	return status != healthHealthy
+}
+
+// healthExitCode는 상태의 종료 코드를 반환합니다. failOn보다 덜 나쁜 상태는 0입니다.
+func healthExitCode(status, failOn string) int {
+	if !healthFailed(status, failOn) {
+		return 0
+	}
+	switch status {
+	case healthDegraded:
+		return 1
+	case healthDown:
+		return 2
+	}
+	return 0
+}
+
+// printHealthSummary는 한 번 확인한 결과를 출력합니다
+func printHealthSummary(summary map[string]interface{}) {
+	fmt.Println("🏥 Performing health check...")
+	if summary["supervisor_status"] != "healthy" {
+		for _, problem := range summary["problems"].([]string) {
+			fmt.Printf("❌ %s\n", problem)
+		}
+		fmt.Println("🔴 Status: down")
+		return
+	}
+
+	fmt.Println("✅ Supervisor is healthy")
+	fmt.Printf("📊 System Health: %d/%d components running\n",
+		summary["healthy_components"], summary["total_components"])
+
+	problems := summary["problems"].([]string)
+	if len(problems) == 0 {
+		fmt.Println("✅ All components are healthy")
+	} else {
+		fmt.Printf("⚠️ %d problems need attention\n", len(problems))
+		for _, problem := range problems {
+			fmt.Printf("   • %s\n", problem)
+		}
+	}
+	fmt.Printf("%s Status: %s\n", healthIcon(summary["status"].(string)), summary["status"])
+}
+
+// printHealthLine은 감시 중 한 줄 결과를 출력하고, 상태가 바뀌었을 때만 문제 목록을 보여줍니다
+func printHealthLine(summary map[string]interface{}, previous string) {
+	status := summary["status"].(string)
+	line := fmt.Sprintf("%s %s %-8s", time.Now().Format("15:04:05"), healthIcon(status), status)
+	if summary["supervisor_status"] == "healthy" {
+		line += fmt.Sprintf("  %d/%d components running", summary["healthy_components"], summary["total_components"])
+	}
+	if previous != "" && previous != status {
+		line += fmt.Sprintf("  (was %s)", previous)
+	}
+	fmt.Println(line)
+
+	if status != previous {
+		for _, problem := range summary["problems"].([]string) {
+			fmt.Printf("         • %s\n", problem)
+		}
+	}
+}
+
+func healthIcon(status string) string {
+	switch status {
+	case healthHealthy:
+		return "🟢"
+	case healthDegraded:
+		return "🟡"
+	}
+	return "🔴"
+}
+
+func init() {
+	monitorHealthCmd.Flags().BoolP("watch", "w", false, "Keep checking every --interval")
+	monitorHealthCmd.Flags().Duration("interval", 10*time.Second, "Check interval for --watch")
+	monitorHealthCmd.Flags().String("fail-on", "", "Exit non-zero only at this state or worse (degraded, down)")
+	monitorHealthCmd.RegisterFlagCompletionFunc("fail-on", cobra.FixedCompletions(
+		[]string{healthDegraded, healthDown}, cobra.ShellCompDirectiveNoFileComp))
+}
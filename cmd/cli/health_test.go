@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+func TestEvaluateHealth(t *testing.T) {
+	failing := &ipc.ProbeResult{Type: "http", Healthy: false, Message: "status 503"}
+
+	tests := []struct {
+		name      string
+		processes []ipc.ProcessInfo
+		want      string
+		problems  int
+	}{
+		{"all running", []ipc.ProcessInfo{{Name: "api", Status: "running"}, {Name: "nats", Status: "running"}}, healthHealthy, 0},
+		{"one stopped", []ipc.ProcessInfo{{Name: "api", Status: "running"}, {Name: "nats", Status: "stopped"}}, healthDegraded, 1},
+		{"failing probe", []ipc.ProcessInfo{{Name: "api", Status: "running", Health: failing}}, healthDegraded, 1},
+		{"none running", []ipc.ProcessInfo{{Name: "api", Status: "error"}, {Name: "nats", Status: "stopped"}}, healthDown, 2},
+	}
+	for _, tt := range tests {
+		status, _, problems := evaluateHealth(tt.processes)
+		if status != tt.want || len(problems) != tt.problems {
+			t.Errorf("%s: got %s with %v, want %s with %d problems", tt.name, status, problems, tt.want, tt.problems)
+		}
+	}
+}
+
+func TestHealthExitCode(t *testing.T) {
+	tests := []struct {
+		status, failOn string
+		want           int
+	}{
+		{healthHealthy, "", 0},
+		{healthDegraded, "", 1},
+		{healthDown, "", 2},
+		{healthDegraded, healthDown, 0},
+		{healthDown, healthDown, 2},
+		{healthDegraded, healthDegraded, 1},
+		{healthHealthy, healthDegraded, 0},
+	}
+	for _, tt := range tests {
+		if got := healthExitCode(tt.status, tt.failOn); got != tt.want {
+			t.Errorf("healthExitCode(%s, %q) = %d, want %d", tt.status, tt.failOn, got, tt.want)
+		}
+	}
+}
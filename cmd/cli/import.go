@@ -0,0 +1,377 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+
+	"github.com/spf13/cobra"
+)
+
+// 가져오기 명령어 (supervisor가 아니라 API 서버의 가져오기 API를 API 토큰으로 호출)
+var importCmd = &cobra.Command{
+	Use:   "import <category> [file]",
+	Short: "Import historical observations from a CSV or NDJSON file",
+	Long: `Upload a CSV or NDJSON file to the API server and import it into a
+category's time series. Each row is checked against the category schema of its
+target; rows that fail are reported with their line number and the rest are
+imported.
+
+The file is uploaded in chunks. If the upload is interrupted, run the same
+command with --resume <job-id> to continue from the last chunk the server
+received. With --source the server reads a file that is already in SeaweedFS.
+
+The API token is read from --api-token-file or $TMIDB_API_TOKEN and needs
+write access to the category.
+
+Examples:
+  tmidb-cli import vitals history.csv
+  tmidb-cli import vitals history.ndjson --mapping '{"target_id":"device","ts":"time"}'
+  tmidb-cli import vitals history.csv --resume 0b7e...
+  tmidb-cli import vitals --source exports/<org-id>/<job-id>/vitals.ndjson --format ndjson`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		api, err := newImportAPI(cmd)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+		category := args[0]
+		source, _ := cmd.Flags().GetString("source")
+		resume, _ := cmd.Flags().GetString("resume")
+		format, _ := cmd.Flags().GetString("format")
+		if (len(args) == 2) == (source != "") {
+			fmt.Println("❌ Give either a file or --source")
+			exit(1)
+		}
+
+		var file *os.File
+		var size int64
+		if len(args) == 2 {
+			if file, err = os.Open(args[1]); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				exit(1)
+			}
+			defer file.Close()
+			info, err := file.Stat()
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				exit(1)
+			}
+			size = info.Size()
+			if format == "" {
+				format = importFormatFromName(args[1])
+			}
+		}
+
+		var job database.ImportJob
+		if resume != "" {
+			if err := api.do(http.MethodGet, "/import/jobs/"+url.PathEscape(resume), nil, "", &job); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				exit(1)
+			}
+			fmt.Printf("🔁 Resuming import %s at %s of %s\n", job.JobID, formatBytes(job.ReceivedBytes), formatBytes(size))
+		} else {
+			request := map[string]interface{}{"format": format}
+			if mapping, _ := cmd.Flags().GetString("mapping"); mapping != "" {
+				raw, err := readMappingFlag(mapping)
+				if err != nil {
+					fmt.Printf("❌ %v\n", err)
+					exit(1)
+				}
+				request["mapping"] = raw
+			}
+			if source != "" {
+				request["source_path"] = source
+			} else {
+				request["size"] = size
+			}
+			body, _ := json.Marshal(request)
+			var created struct {
+				Job database.ImportJob `json:"job"`
+			}
+			if err := api.do(http.MethodPost, "/import/"+url.PathEscape(category), bytes.NewReader(body), "application/json", &created); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				exit(1)
+			}
+			job = created.Job
+			fmt.Printf("📥 Import job %s created\n", job.JobID)
+		}
+
+		if file != nil && job.Status == database.ImportStatusUploading {
+			chunkSize, _ := cmd.Flags().GetInt64("chunk-size")
+			if err := uploadImportFile(api, &job, file, size, chunkSize); err != nil {
+				fmt.Printf("\n❌ Upload stopped: %v\n", err)
+				fmt.Printf("💡 Resume with 'tmidb-cli import %s %s --resume %s'\n", category, args[1], job.JobID)
+				exit(1)
+			}
+			if err := api.do(http.MethodPost, "/import/jobs/"+job.JobID+"/complete", nil, "", nil); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				exit(1)
+			}
+		}
+
+		if wait, _ := cmd.Flags().GetBool("wait"); !wait {
+			fmt.Printf("💡 Check progress with 'tmidb-cli import status %s'\n", job.JobID)
+			return
+		}
+		finished := waitImportJob(api, job.JobID)
+		printImportJob(cmd, finished)
+		if finished.Status == database.ImportStatusFailed {
+			exit(1)
+		}
+	},
+}
+
+var importStatusCmd = &cobra.Command{
+	Use:   "status <job-id>",
+	Short: "Show the progress and row errors of an import",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		api, err := newImportAPI(cmd)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+		var job database.ImportJob
+		if err := api.do(http.MethodGet, "/import/jobs/"+url.PathEscape(args[0]), nil, "", &job); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+		printImportJob(cmd, &job)
+	},
+}
+
+// importAPI는 API 서버의 데이터 API 클라이언트입니다
+type importAPI struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func newImportAPI(cmd *cobra.Command) (*importAPI, error) {
+	baseURL, _ := cmd.Flags().GetString("api")
+	token := os.Getenv("TMIDB_API_TOKEN")
+	if path, _ := cmd.Flags().GetString("api-token-file"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read API token file: %v", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token == "" {
+		return nil, fmt.Errorf("an API token is required (--api-token-file or $TMIDB_API_TOKEN)")
+	}
+	return &importAPI{
+		baseURL: strings.TrimRight(baseURL, "/") + "/api/v1",
+		token:   token,
+		http:    &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+// apiError는 데이터 API의 에러 응답입니다
+type apiError struct {
+	Status  int
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details"`
+}
+
+func (e *apiError) Error() string {
+	if e.Details != "" {
+		return fmt.Sprintf("%s (%s)", e.Message, e.Details)
+	}
+	return e.Message
+}
+
+// do는 요청을 보내고 응답의 data를 out에 읽습니다. 실패 응답은 *apiError입니다.
+func (a *importAPI) do(method, path string, body io.Reader, contentType string, out interface{}) error {
+	req, err := http.NewRequest(method, a.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		Data  json.RawMessage `json:"data"`
+		Error *apiError       `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("unexpected response from API server (HTTP %d)", resp.StatusCode)
+	}
+	if resp.StatusCode >= 300 || envelope.Error != nil {
+		if envelope.Error == nil {
+			envelope.Error = &apiError{Message: resp.Status}
+		}
+		envelope.Error.Status = resp.StatusCode
+		return envelope.Error
+	}
+	if out == nil {
+		return nil
+	}
+	// 작업 조회 응답은 {"job": {...}}
+	if job, ok := out.(*database.ImportJob); ok {
+		var wrapped struct {
+			Job *database.ImportJob `json:"job"`
+		}
+		wrapped.Job = job
+		return json.Unmarshal(envelope.Data, &wrapped)
+	}
+	return json.Unmarshal(envelope.Data, out)
+}
+
+// uploadImportFile은 받은 크기부터 파일을 조각으로 올립니다.
+// 조각이 거절되거나 연결이 끊기면 서버가 받은 크기를 다시 물어 그 위치부터 이어 올립니다.
+func uploadImportFile(api *importAPI, job *database.ImportJob, file *os.File, size, chunkSize int64) error {
+	const maxRetries = 5
+	buf := make([]byte, chunkSize)
+	offset, retries := job.ReceivedBytes, 0
+	for offset < size {
+		n, err := file.ReadAt(buf, offset)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		var result struct {
+			ReceivedBytes int64 `json:"received_bytes"`
+		}
+		err = api.do(http.MethodPut, fmt.Sprintf("/import/jobs/%s/chunks?offset=%d", job.JobID, offset),
+			bytes.NewReader(buf[:n]), "application/octet-stream", &result)
+		if err != nil {
+			if apiErr, ok := err.(*apiError); ok && apiErr.Code != "UPLOAD_CONFLICT" {
+				return err
+			}
+			if retries++; retries > maxRetries {
+				return err
+			}
+			time.Sleep(time.Duration(retries) * time.Second)
+			if err := api.do(http.MethodGet, "/import/jobs/"+job.JobID, nil, "", job); err != nil {
+				continue
+			}
+			if job.Status != database.ImportStatusUploading {
+				return fmt.Errorf("import job is %s", job.Status)
+			}
+			offset = job.ReceivedBytes
+			continue
+		}
+		offset, retries = result.ReceivedBytes, 0
+		fmt.Printf("\r⬆️  Uploaded %s of %s (%.0f%%)", formatBytes(offset), formatBytes(size), float64(offset)*100/float64(size))
+	}
+	fmt.Println()
+	return nil
+}
+
+// waitImportJob은 가져오기가 끝날 때까지 진행 상황을 보여줍니다
+func waitImportJob(api *importAPI, jobID string) *database.ImportJob {
+	for {
+		var job database.ImportJob
+		if err := api.do(http.MethodGet, "/import/jobs/"+jobID, nil, "", &job); err != nil {
+			fmt.Printf("\n❌ %v\n", err)
+			exit(1)
+		}
+		if job.Status == database.ImportStatusCompleted || job.Status == database.ImportStatusFailed {
+			fmt.Println()
+			return &job
+		}
+		fmt.Printf("\r⏳ %s: %d rows read, %d imported, %d failed", job.Status, job.RowsRead, job.RowsImported, job.RowsFailed)
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// printImportJob은 작업 결과와 줄별 오류를 출력합니다
+func printImportJob(cmd *cobra.Command, job *database.ImportJob) {
+	formatter := getFormatter(cmd)
+	if formatter.Structured() {
+		formatter.Output(job)
+		return
+	}
+
+	icon := "⏳"
+	switch job.Status {
+	case database.ImportStatusCompleted:
+		icon = "✅"
+	case database.ImportStatusFailed:
+		icon = "❌"
+	}
+	fmt.Printf("%s Import %s (%s): %s\n", icon, job.JobID, job.Category, job.Status)
+	if job.Status == database.ImportStatusUploading {
+		fmt.Printf("   Received: %s\n", formatBytes(job.ReceivedBytes))
+	}
+	fmt.Printf("   Rows: %d read, %d imported, %d failed\n", job.RowsRead, job.RowsImported, job.RowsFailed)
+	if job.Error != "" {
+		fmt.Printf("   Error: %s\n", job.Error)
+	}
+
+	const shown = 20
+	for i, rowErr := range job.RowErrors {
+		if i == shown {
+			fmt.Printf("   ... %d more (use -o json for all stored errors)\n", len(job.RowErrors)-shown)
+			break
+		}
+		fmt.Printf("   line %d: %s\n", rowErr.Line, rowErr.Error)
+		for _, f := range rowErr.Fields {
+			fmt.Printf("      %s: %s\n", f.Path, f.Message)
+		}
+	}
+}
+
+// importFormatFromName은 파일 확장자로 형식을 정합니다
+func importFormatFromName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".ndjson", ".jsonl":
+		return "ndjson"
+	default:
+		return "csv"
+	}
+}
+
+// readMappingFlag는 --mapping 값(JSON 또는 @파일)을 읽습니다
+func readMappingFlag(value string) (json.RawMessage, error) {
+	data := []byte(value)
+	if strings.HasPrefix(value, "@") {
+		var err error
+		if data, err = os.ReadFile(value[1:]); err != nil {
+			return nil, fmt.Errorf("failed to read mapping file: %v", err)
+		}
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("mapping is not valid JSON")
+	}
+	return data, nil
+}
+
+func init() {
+	defaultAPI := os.Getenv("TMIDB_API_URL")
+	if defaultAPI == "" {
+		defaultAPI = "http://localhost:8020"
+	}
+	for _, cmd := range []*cobra.Command{importCmd, importStatusCmd} {
+		cmd.Flags().String("api", defaultAPI, "API server URL (default: $TMIDB_API_URL)")
+		cmd.Flags().String("api-token-file", "", "File containing an API token (default: $TMIDB_API_TOKEN)")
+	}
+	importCmd.Flags().String("format", "", "File format: csv or ndjson (default: from the file extension)")
+	importCmd.Flags().String("mapping", "", `Column mapping as JSON or @file, e.g. {"target_id":"device","ts":"time","fields":{"temp":"t"}}`)
+	importCmd.Flags().String("source", "", "Import a file that is already in SeaweedFS instead of uploading one")
+	importCmd.Flags().String("resume", "", "Continue the upload of an existing import job")
+	importCmd.Flags().Int64("chunk-size", 8*1024*1024, "Upload chunk size in bytes")
+	importCmd.Flags().Bool("wait", true, "Wait for the import to finish and show the result")
+
+	importCmd.AddCommand(importStatusCmd)
+	rootCmd.AddCommand(importCmd)
+}
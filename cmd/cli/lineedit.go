@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// errInterrupted 입력 중 Ctrl+C
+var errInterrupted = errors.New("interrupted")
+
+// maxHistory 셸 히스토리에 남기는 줄 수
+const maxHistory = 1000
+
+// lineEditor 셸용 한 줄 편집기 (히스토리, 탭 완성, 기본 커서 이동).
+// 터미널이 아니거나 raw 모드를 지원하지 않으면 줄 단위로 읽기만 합니다.
+type lineEditor struct {
+	in       *bufio.Reader
+	out      io.Writer
+	fd       int
+	terminal bool
+
+	history []string
+	// complete는 커서 앞까지의 입력으로 완성 후보와 완성할 단어를 반환합니다
+	complete func(line string) (candidates []string, word string)
+}
+
+func newLineEditor(in *os.File, out io.Writer) *lineEditor {
+	fd := int(in.Fd())
+	return &lineEditor{
+		in:       bufio.NewReader(in),
+		out:      out,
+		fd:       fd,
+		terminal: isTerminal(fd),
+	}
+}
+
+// ReadLine은 프롬프트를 출력하고 한 줄을 읽습니다. 입력이 끝나면 io.EOF를 반환합니다.
+func (e *lineEditor) ReadLine(prompt string) (string, error) {
+	if !e.terminal {
+		return e.readPlain(prompt)
+	}
+	restore, err := makeRaw(e.fd)
+	if err != nil {
+		return e.readPlain(prompt)
+	}
+	defer restore()
+	return e.edit(prompt)
+}
+
+func (e *lineEditor) readPlain(prompt string) (string, error) {
+	if e.terminal {
+		fmt.Fprint(e.out, prompt)
+	}
+	line, err := e.in.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// AddHistory는 줄을 히스토리에 추가합니다 (직전과 같은 줄은 건너뜀)
+func (e *lineEditor) AddHistory(line string) {
+	if line == "" || (len(e.history) > 0 && e.history[len(e.history)-1] == line) {
+		return
+	}
+	e.history = append(e.history, line)
+	if len(e.history) > maxHistory {
+		e.history = e.history[len(e.history)-maxHistory:]
+	}
+}
+
+// edit는 raw 모드 입력을 처리합니다
+func (e *lineEditor) edit(prompt string) (string, error) {
+	var buf []rune
+	pos := 0
+	histIdx := len(e.history)
+	pending := "" // 히스토리를 넘기기 전의 입력
+
+	refresh := func() {
+		fmt.Fprintf(e.out, "\r%s%s\x1b[K", prompt, string(buf))
+		if back := len(buf) - pos; back > 0 {
+			fmt.Fprintf(e.out, "\x1b[%dD", back)
+		}
+	}
+	setLine := func(line string) {
+		buf = []rune(line)
+		pos = len(buf)
+		refresh()
+	}
+	bell := func() { fmt.Fprint(e.out, "\a") }
+
+	fmt.Fprint(e.out, prompt)
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(buf), nil
+		case 3: // Ctrl+C
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", errInterrupted
+		case 4: // Ctrl+D
+			if len(buf) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			if pos < len(buf) {
+				buf = append(buf[:pos], buf[pos+1:]...)
+				refresh()
+			}
+		case 127, 8: // Backspace
+			if pos > 0 {
+				buf = append(buf[:pos-1], buf[pos:]...)
+				pos--
+				refresh()
+			}
+		case 1: // Ctrl+A
+			pos = 0
+			refresh()
+		case 5: // Ctrl+E
+			pos = len(buf)
+			refresh()
+		case 2: // Ctrl+B
+			if pos > 0 {
+				pos--
+				refresh()
+			}
+		case 6: // Ctrl+F
+			if pos < len(buf) {
+				pos++
+				refresh()
+			}
+		case 11: // Ctrl+K
+			buf = buf[:pos]
+			refresh()
+		case 21: // Ctrl+U
+			buf = buf[pos:]
+			pos = 0
+			refresh()
+		case 23: // Ctrl+W
+			start := pos
+			for start > 0 && buf[start-1] == ' ' {
+				start--
+			}
+			for start > 0 && buf[start-1] != ' ' {
+				start--
+			}
+			buf = append(buf[:start], buf[pos:]...)
+			pos = start
+			refresh()
+		case 12: // Ctrl+L
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+			refresh()
+		case 16: // Ctrl+P
+			histIdx, pending = e.moveHistory('A', histIdx, pending, string(buf), setLine, bell)
+		case 14: // Ctrl+N
+			histIdx, pending = e.moveHistory('B', histIdx, pending, string(buf), setLine, bell)
+		case '\t':
+			if e.complete == nil {
+				bell()
+				continue
+			}
+			candidates, word := e.complete(string(buf[:pos]))
+			insert, list := completionResult(candidates, word)
+			if insert == "" && len(list) == 0 {
+				bell()
+				continue
+			}
+			if insert != "" {
+				rest := append([]rune(insert), buf[pos:]...)
+				buf = append(buf[:pos], rest...)
+				pos += len([]rune(insert))
+			}
+			if len(list) > 0 {
+				fmt.Fprint(e.out, "\r\n"+strings.Join(list, "  ")+"\r\n")
+			}
+			refresh()
+		case 27: // ESC 시퀀스 (방향키, Home/End, Delete)
+			key := e.readEscape()
+			switch key {
+			case 'A', 'B':
+				histIdx, pending = e.moveHistory(key, histIdx, pending, string(buf), setLine, bell)
+			case 'C':
+				if pos < len(buf) {
+					pos++
+					refresh()
+				}
+			case 'D':
+				if pos > 0 {
+					pos--
+					refresh()
+				}
+			case 'H':
+				pos = 0
+				refresh()
+			case 'F':
+				pos = len(buf)
+				refresh()
+			case '~':
+				if pos < len(buf) {
+					buf = append(buf[:pos], buf[pos+1:]...)
+					refresh()
+				}
+			}
+		default:
+			if r < 32 {
+				continue
+			}
+			buf = append(buf[:pos], append([]rune{r}, buf[pos:]...)...)
+			pos++
+			refresh()
+		}
+	}
+}
+
+// moveHistory는 위('A')/아래('B')로 히스토리를 이동합니다
+func (e *lineEditor) moveHistory(key rune, idx int, pending, current string, setLine func(string), bell func()) (int, string) {
+	switch key {
+	case 'A':
+		if idx == 0 {
+			bell()
+			return idx, pending
+		}
+		if idx == len(e.history) {
+			pending = current
+		}
+		idx--
+		setLine(e.history[idx])
+	case 'B':
+		if idx >= len(e.history) {
+			bell()
+			return idx, pending
+		}
+		idx++
+		if idx == len(e.history) {
+			setLine(pending)
+		} else {
+			setLine(e.history[idx])
+		}
+	}
+	return idx, pending
+}
+
+// readEscape는 ESC 다음 시퀀스를 읽어 키를 반환합니다.
+// 방향키는 'A'-'D', Home/End는 'H'/'F', Delete는 '~'로 돌려줍니다.
+func (e *lineEditor) readEscape() rune {
+	r, _, err := e.in.ReadRune()
+	if err != nil || (r != '[' && r != 'O') {
+		return 0
+	}
+	r, _, err = e.in.ReadRune()
+	if err != nil {
+		return 0
+	}
+	if r < '0' || r > '9' {
+		return r
+	}
+
+	// ESC [ 숫자 ~ 형식
+	code := r
+	for {
+		next, _, err := e.in.ReadRune()
+		if err != nil || next == '~' {
+			break
+		}
+		if next < '0' || next > '9' {
+			return 0
+		}
+	}
+	switch code {
+	case '1', '7':
+		return 'H'
+	case '4', '8':
+		return 'F'
+	case '3':
+		return '~'
+	}
+	return 0
+}
+
+// completionResult는 후보가 하나면 나머지와 공백을, 여러 개면 공통 접두사를 채우고
+// 더 채울 것이 없으면 후보 목록을 반환합니다
+func completionResult(candidates []string, word string) (insert string, list []string) {
+	if len(candidates) == 0 {
+		return "", nil
+	}
+	if len(candidates) == 1 {
+		return strings.TrimPrefix(candidates[0], word) + " ", nil
+	}
+
+	prefix := candidates[0]
+	for _, c := range candidates[1:] {
+		for !strings.HasPrefix(c, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if len(prefix) > len(word) && strings.HasPrefix(prefix, word) {
+		return prefix[len(word):], nil
+	}
+
+	list = append([]string(nil), candidates...)
+	sort.Strings(list)
+	return "", list
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/logger"
+
+	"github.com/spf13/cobra"
+)
+
+// 로그 보관 정책 명령어
+var logsPolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Show or change log retention policies",
+}
+
+var logsPolicyGetCmd = &cobra.Command{
+	Use:   "get <component>",
+	Short: "Show the retention policy for a component",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var policy logger.RetentionPolicy
+		alertRequest(ipc.MessageTypeLogConfig, map[string]interface{}{"component": args[0]}, &policy)
+		printLogPolicy(cmd, policy)
+	},
+}
+
+var logsPolicySetCmd = &cobra.Command{
+	Use:   "set <component>",
+	Short: "Change the retention policy for a component",
+	Long: `Change the retention policy for a component. Only the given flags are changed.
+The policy is saved in the log directory and old files are cleaned up right away.
+
+Examples:
+  tmidb-cli logs policy set api --max-total-size 2048 --compress
+  tmidb-cli logs policy set data-consumer --max-files 20 --max-age 14d`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data := map[string]interface{}{"component": args[0]}
+		flags := cmd.Flags()
+
+		if flags.Changed("max-file-size") {
+			data["max_file_size"], _ = flags.GetInt64("max-file-size")
+		}
+		if flags.Changed("max-files") {
+			data["max_files"], _ = flags.GetInt("max-files")
+		}
+		if flags.Changed("max-total-size") {
+			data["max_total_size"], _ = flags.GetInt64("max-total-size")
+		}
+		if flags.Changed("max-age") {
+			value, _ := flags.GetString("max-age")
+			age, err := parseDuration(value)
+			if err != nil {
+				fmt.Printf("❌ Invalid --max-age: %v\n", err)
+				exit(1)
+			}
+			data["max_age"] = age.String()
+		}
+		if flags.Changed("compress") {
+			data["compress"], _ = flags.GetBool("compress")
+		}
+		if flags.Changed("enabled") {
+			data["enabled"], _ = flags.GetBool("enabled")
+		}
+		if len(data) == 1 {
+			fmt.Println("❌ Nothing to change; see --help for policy flags")
+			exit(1)
+		}
+
+		var policy logger.RetentionPolicy
+		alertRequest(ipc.MessageTypeLogPolicySet, data, &policy)
+		fmt.Printf("✅ Retention policy for %s updated\n\n", args[0])
+		printLogPolicy(cmd, policy)
+	},
+}
+
+// printLogPolicy 보관 정책 출력
+func printLogPolicy(cmd *cobra.Command, policy logger.RetentionPolicy) {
+	formatter := getFormatter(cmd)
+	if formatter.Structured() {
+		formatter.Output(policy)
+		return
+	}
+
+	totalSize := "unlimited"
+	if policy.MaxTotalSize > 0 {
+		totalSize = fmt.Sprintf("%d MB", policy.MaxTotalSize)
+	}
+	level := policy.Level
+	if level == "" {
+		level = "global"
+	}
+
+	fmt.Printf("📝 Retention policy: %s\n", policy.Component)
+	fmt.Printf("   Cleanup enabled: %t\n", policy.Enabled)
+	fmt.Printf("   Max file size:   %d MB\n", policy.MaxFileSize)
+	fmt.Printf("   Max files:       %d\n", policy.MaxFiles)
+	fmt.Printf("   Max total size:  %s\n", totalSize)
+	fmt.Printf("   Max age:         %s\n", formatDuration(policy.MaxAge))
+	fmt.Printf("   Compress:        %t\n", policy.Compress)
+	fmt.Printf("   Log level:       %s\n", level)
+}
+
+func init() {
+	logsPolicySetCmd.Flags().Int64("max-file-size", 0, "Rotate the current file at this size (MB)")
+	logsPolicySetCmd.Flags().Int("max-files", 0, "Maximum number of log files to keep")
+	logsPolicySetCmd.Flags().Int64("max-total-size", 0, "Maximum size of all files incl. compressed rotations (MB, 0 = unlimited)")
+	logsPolicySetCmd.Flags().String("max-age", "", "Delete files older than this (e.g., 7d, 12h)")
+	logsPolicySetCmd.Flags().Bool("compress", false, "Compress rotated files")
+	logsPolicySetCmd.Flags().Bool("enabled", true, "Enable cleanup for this component")
+
+	logsPolicyCmd.AddCommand(logsPolicyGetCmd)
+	logsPolicyCmd.AddCommand(logsPolicySetCmd)
+	logsCmd.AddCommand(logsPolicyCmd)
+}
@@ -5,12 +5,14 @@ import (
 	"os"
 	"os/signal"
 	"regexp"
+	"sort"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/logger"
 )
 
 var (
@@ -19,7 +21,6 @@ var (
 	logUntil   string
 	logPattern string
 	logLines   int
-	logOutput  string
 )
 
 // 로그 관련 명령어들
@@ -40,33 +41,10 @@ var logsCmd = &cobra.Command{
 			// Follow 모드
 			fmt.Printf("📄 Following logs for: %s (Press Ctrl+C to stop)\n", component)
 
-			// 로그 스트림 시작
-			logChan, err := client.StreamLogs(component)
-			if err != nil {
+			lines, _ := cmd.Flags().GetInt("lines")
+			if err := followLogs(component, lines); err != nil {
 				fmt.Printf("❌ Failed to start log stream: %v\n", err)
-				os.Exit(1)
-			}
-
-			// 신호 처리
-			sigChan := make(chan os.Signal, 1)
-			signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-
-			// 로그 출력 루프
-			for {
-				select {
-				case logEntry, ok := <-logChan:
-					if !ok {
-						fmt.Println("📄 Log stream ended")
-						return
-					}
-					fmt.Printf("[%s] %s: %s\n",
-						logEntry.Timestamp.Format("15:04:05"),
-						logEntry.Process,
-						logEntry.Message)
-				case <-sigChan:
-					fmt.Println("\n📄 Log following stopped")
-					return
-				}
+				exit(1)
 			}
 		} else {
 			// 일반 로그 표시 (최근 로그)
@@ -79,12 +57,12 @@ var logsCmd = &cobra.Command{
 			})
 			if err != nil {
 				fmt.Printf("❌ Failed to get logs: %v\n", err)
-				os.Exit(1)
+				exit(1)
 			}
 
 			if !resp.Success {
 				fmt.Printf("❌ Error: %s\n", resp.Error)
-				os.Exit(1)
+				exit(1)
 			}
 
 			// 로그 출력
@@ -113,7 +91,7 @@ var logsEnableCmd = &cobra.Command{
 
 		if err := client.EnableLogs(component); err != nil {
 			fmt.Printf("❌ Failed to enable logs for %s: %v\n", component, err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Printf("✅ Logs enabled for %s\n", component)
@@ -131,7 +109,7 @@ var logsDisableCmd = &cobra.Command{
 
 		if err := client.DisableLogs(component); err != nil {
 			fmt.Printf("❌ Failed to disable logs for %s: %v\n", component, err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Printf("✅ Logs disabled for %s\n", component)
@@ -151,7 +129,7 @@ var logsStatusCmd = &cobra.Command{
 		status, err := client.GetLogStatus()
 		if err != nil {
 			fmt.Printf("❌ Failed to get log status: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// 정렬된 순서로 출력
@@ -176,6 +154,76 @@ var logsStatusCmd = &cobra.Command{
 	},
 }
 
+// 로그 레벨 명령어
+var logsLevelCmd = &cobra.Command{
+	Use:   "level [component] [level]",
+	Short: "Show or set per-component log levels",
+	Long: `Show the global log level and per-component overrides, or set the minimum
+level for one component without restarting anything. Use "default" to go back
+to the global level. Overrides are kept across supervisor restarts.
+
+Examples:
+  tmidb-cli logs level                        # Show all levels
+  tmidb-cli logs level data-consumer debug    # DEBUG for data-consumer only
+  tmidb-cli logs level data-consumer default  # Back to the global level`,
+	Args: cobra.MaximumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 2 {
+			var result struct {
+				Level    string `json:"level"`
+				Override bool   `json:"override"`
+			}
+			alertRequest(ipc.MessageTypeLogLevelSet, map[string]interface{}{
+				"component": args[0],
+				"level":     args[1],
+			}, &result)
+
+			if result.Override {
+				fmt.Printf("✅ Log level for %s set to %s\n", args[0], result.Level)
+			} else {
+				fmt.Printf("✅ %s now uses the global log level (%s)\n", args[0], result.Level)
+			}
+			return
+		}
+
+		var status logger.LevelStatus
+		alertRequest(ipc.MessageTypeLogLevelGet, nil, &status)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(status)
+			return
+		}
+
+		if len(args) == 1 {
+			level, override := status.Components[args[0]]
+			if !override {
+				level = status.Global + " (global)"
+			}
+			fmt.Printf("📝 %s: %s\n", args[0], level)
+			return
+		}
+
+		fmt.Printf("📝 Global log level: %s\n", status.Global)
+		if len(status.Components) == 0 {
+			fmt.Println("   No per-component overrides")
+			return
+		}
+
+		components := make([]string, 0, len(status.Components))
+		for component := range status.Components {
+			components = append(components, component)
+		}
+		sort.Strings(components)
+
+		fmt.Println()
+		fmt.Printf("%-18s %s\n", "COMPONENT", "LEVEL")
+		for _, component := range components {
+			fmt.Printf("%-18s %s\n", component, status.Components[component])
+		}
+	},
+}
+
 // 로그 필터 명령어
 var logsFilterCmd = &cobra.Command{
 	Use:   "filter [component]",
@@ -201,20 +249,26 @@ Examples:
 			component = args[0]
 		}
 
-		fmt.Printf("📋 Filtering logs for: %s\n", component)
+		// json/yaml/template 출력에서는 로그 항목만 출력
+		formatter := getFormatter(cmd)
+		structured := formatter.Structured()
 
-		// 필터 옵션 표시
-		if logLevel != "" {
-			fmt.Printf("  Level: %s and above\n", strings.ToUpper(logLevel))
-		}
-		if logSince != "" {
-			fmt.Printf("  Since: %s ago\n", logSince)
-		}
-		if logUntil != "" {
-			fmt.Printf("  Until: %s ago\n", logUntil)
-		}
-		if logPattern != "" {
-			fmt.Printf("  Pattern: %s\n", logPattern)
+		if !structured {
+			fmt.Printf("📋 Filtering logs for: %s\n", component)
+
+			// 필터 옵션 표시
+			if logLevel != "" {
+				fmt.Printf("  Level: %s and above\n", strings.ToUpper(logLevel))
+			}
+			if logSince != "" {
+				fmt.Printf("  Since: %s ago\n", logSince)
+			}
+			if logUntil != "" {
+				fmt.Printf("  Until: %s ago\n", logUntil)
+			}
+			if logPattern != "" {
+				fmt.Printf("  Pattern: %s\n", logPattern)
+			}
 		}
 
 		// 시간 파싱
@@ -296,9 +350,8 @@ Examples:
 					process := logMap["process"].(string)
 					level := logMap["level"].(string)
 
-					if logOutput == "json" {
-						fmt.Printf(`{"timestamp":"%s","process":"%s","level":"%s","message":"%s"}`+"\n",
-							timestamp, process, level, message)
+					if structured {
+						formatter.Output(FormatLogEntry(logMap))
 					} else {
 						levelColor := getLogLevelColor(level)
 						fmt.Printf("[%s] %s%s%s %s: %s\n",
@@ -307,72 +360,180 @@ Examples:
 					filteredCount++
 				}
 			}
-			fmt.Printf("\n📊 Displayed %d logs (filtered from %d)\n", filteredCount, len(logs))
+			if !structured {
+				fmt.Printf("\n📊 Displayed %d logs (filtered from %d)\n", filteredCount, len(logs))
+			}
 		}
 	},
 }
 
 // 로그 검색 명령어
 var logsSearchCmd = &cobra.Command{
-	Use:   "search <pattern> [component]",
-	Short: "Search logs with regex pattern",
-	Long:  "Search through logs using regular expression patterns",
-	Args:  cobra.RangeArgs(1, 2),
+	Use:   "search [pattern] [component]",
+	Short: "Search current and rotated logs on the supervisor",
+	Long: `Search current, rotated and compressed log files on the supervisor.
+Matching entries are returned newest first, one page at a time.
+
+Examples:
+  # Errors containing "timeout" from the API in the last 2 hours
+  tmidb-cli logs search --component api --since 2h --level error --grep "timeout"
+
+  # Follow one API request across components
+  tmidb-cli logs search --field request_id=3f2a9c1e0b7d4e65
+
+  # Next page of results
+  tmidb-cli logs search --grep "connection failed" --page 2
+
+  # Positional form
+  tmidb-cli logs search "connection failed" api`,
+	Args: cobra.MaximumNArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
-		pattern := args[0]
-		component := "all"
-		if len(args) > 1 {
+		component, _ := cmd.Flags().GetString("component")
+		pattern, _ := cmd.Flags().GetString("grep")
+		if len(args) > 0 && pattern == "" {
+			pattern = args[0]
+		}
+		if len(args) > 1 && !cmd.Flags().Changed("component") {
 			component = args[1]
 		}
 
-		fmt.Printf("🔍 Searching logs in %s for pattern: %s\n", component, pattern)
+		// 패턴 검증 (하이라이트에도 사용)
+		var patternRegex *regexp.Regexp
+		if pattern != "" {
+			var err error
+			if patternRegex, err = regexp.Compile(pattern); err != nil {
+				fmt.Printf("❌ Invalid regex pattern: %v\n", err)
+				exit(1)
+			}
+		}
 
-		// 패턴 컴파일
-		patternRegex, err := regexp.Compile(pattern)
-		if err != nil {
-			fmt.Printf("❌ Invalid regex pattern: %v\n", err)
-			return
+		limit, _ := cmd.Flags().GetInt("limit")
+		page, _ := cmd.Flags().GetInt("page")
+		if limit <= 0 || page <= 0 {
+			fmt.Println("❌ --limit and --page must be positive")
+			exit(1)
 		}
 
-		// 로그 요청
-		resp, err := client.SendMessage(ipc.MessageTypeGetLogs, map[string]interface{}{
+		data := map[string]interface{}{
+			"search":    true,
 			"component": component,
-			"lines":     1000, // 검색을 위해 더 많은 로그 가져오기
-		})
-		if err != nil {
-			fmt.Printf("❌ Failed to get logs: %v\n", err)
+			"grep":      pattern,
+			"offset":    (page - 1) * limit,
+			"limit":     limit,
+		}
+		if level, _ := cmd.Flags().GetString("level"); level != "" {
+			data["level"] = level
+		}
+		if pairs, _ := cmd.Flags().GetStringArray("field"); len(pairs) > 0 {
+			fields := make(map[string]string, len(pairs))
+			for _, pair := range pairs {
+				key, value, ok := strings.Cut(pair, "=")
+				if !ok || key == "" {
+					fmt.Printf("❌ Invalid --field %q (expected key=value)\n", pair)
+					exit(1)
+				}
+				fields[key] = value
+			}
+			data["fields"] = fields
+		}
+		for _, name := range []string{"since", "until"} {
+			value, _ := cmd.Flags().GetString(name)
+			if value == "" {
+				continue
+			}
+			t, err := parseSearchTime(value)
+			if err != nil {
+				fmt.Printf("❌ Invalid --%s: %v\n", name, err)
+				exit(1)
+			}
+			data[name] = t.Format(time.RFC3339)
+		}
+
+		var result logger.SearchResult
+		alertRequest(ipc.MessageTypeGetLogs, data, &result)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(result)
 			return
 		}
 
-		if !resp.Success {
-			fmt.Printf("❌ Error: %s\n", resp.Error)
+		for _, entry := range result.Entries {
+			message := entry.Message
+			if patternRegex != nil {
+				// 매칭된 부분 하이라이트
+				message = patternRegex.ReplaceAllString(message, "\033[1;33m$0\033[0m")
+			}
+			if len(entry.Fields) > 0 {
+				message += " " + colorGray + logger.FormatFields(entry.Fields) + colorReset
+			}
+			levelColor := getLogLevelColor(entry.Level)
+			fmt.Printf("[%s] %s%s%s %s: %s\n",
+				entry.Timestamp.Format("2006-01-02 15:04:05"),
+				levelColor, entry.Level, colorReset, entry.Process, message)
+		}
+
+		if len(result.Entries) == 0 {
+			fmt.Printf("\n📊 No matches on page %d (%d total, %d files scanned)\n", page, result.Total, result.FilesScanned)
 			return
 		}
+		pages := (result.Total + limit - 1) / limit
+		fmt.Printf("\n📊 Showing %d-%d of %d matches (page %d/%d, %d files scanned)\n",
+			result.Offset+1, result.Offset+len(result.Entries), result.Total, page, pages, result.FilesScanned)
+		if result.HasMore {
+			fmt.Printf("   Use --page %d for more\n", page+1)
+		}
+	},
+}
 
-		// 검색 및 출력
-		if logs, ok := resp.Data.([]interface{}); ok {
-			matches := 0
-			for _, log := range logs {
-				if logMap, ok := log.(map[string]interface{}); ok {
-					message := logMap["message"].(string)
-					if patternRegex.MatchString(message) {
-						timestamp := logMap["timestamp"].(string)
-						process := logMap["process"].(string)
-						level := logMap["level"].(string)
+// parseSearchTime 상대 시간(2h, 30m, 1d) 또는 RFC3339 시각을 해석
+func parseSearchTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	duration, err := parseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a duration like 2h or an RFC3339 time")
+	}
+	return time.Now().Add(-duration), nil
+}
 
-						// 매칭된 부분 하이라이트
-						highlighted := patternRegex.ReplaceAllString(message, "\033[1;33m$0\033[0m")
+// followLogs 로그 스트림을 열고 Ctrl+C 또는 스트림 종료까지 출력
+func followLogs(component string, lines int) error {
+	logChan, err := client.StreamLogs(component, lines)
+	if err != nil {
+		return err
+	}
 
-						levelColor := getLogLevelColor(level)
-						fmt.Printf("[%s] %s%s%s %s: %s\n",
-							timestamp, levelColor, level, colorReset, process, highlighted)
-						matches++
-					}
-				}
+	// 신호 처리
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	// 로그 출력 루프
+	for {
+		select {
+		case logEntry, ok := <-logChan:
+			if !ok {
+				fmt.Println("📄 Log stream ended")
+				return nil
 			}
-			fmt.Printf("\n📊 Found %d matches\n", matches)
+			message := logEntry.Message
+			if len(logEntry.Fields) > 0 {
+				message += " " + colorGray + logger.FormatFields(logEntry.Fields) + colorReset
+			}
+			levelColor := getLogLevelColor(logEntry.Level)
+			fmt.Printf("[%s] %s%s%s %s: %s\n",
+				logEntry.Timestamp.Format("15:04:05"),
+				levelColor, logEntry.Level, colorReset,
+				logEntry.Process,
+				message)
+		case <-sigChan:
+			fmt.Println("\n📄 Log following stopped")
+			client.Close()
+			return nil
 		}
-	},
+	}
 }
 
 // 로그 레벨 색상
@@ -452,9 +613,11 @@ func parseDuration(s string) (time.Duration, error) {
 func init() {
 	// 로그 명령어 구성
 	logsCmd.Flags().BoolP("follow", "f", false, "Follow log output (similar to tail -f)")
+	logsCmd.Flags().IntP("lines", "n", 0, "Number of recent lines to show before following")
 	logsCmd.AddCommand(logsEnableCmd)
 	logsCmd.AddCommand(logsDisableCmd)
 	logsCmd.AddCommand(logsStatusCmd)
+	logsCmd.AddCommand(logsLevelCmd)
 
 	// filter 명령어 플래그
 	logsFilterCmd.Flags().StringVar(&logLevel, "level", "", "Minimum log level (debug, info, warn, error)")
@@ -462,11 +625,17 @@ func init() {
 	logsFilterCmd.Flags().StringVar(&logUntil, "until", "", "Show logs until duration ago")
 	logsFilterCmd.Flags().StringVar(&logPattern, "pattern", "", "Filter logs by regex pattern")
 	logsFilterCmd.Flags().IntVar(&logLines, "lines", 100, "Number of log lines to retrieve")
-	logsFilterCmd.Flags().StringVar(&logOutput, "output", "text", "Output format (text, json)")
 
 	// search 명령어 플래그
-	logsSearchCmd.Flags().IntVar(&logLines, "lines", 1000, "Number of log lines to search through")
-	logsSearchCmd.Flags().StringVar(&logOutput, "output", "text", "Output format (text, json)")
+	logsSearchCmd.Flags().StringP("component", "c", "all", "Component to search")
+	logsSearchCmd.RegisterFlagCompletionFunc("component", completeComponentFlag)
+	logsSearchCmd.Flags().StringP("grep", "g", "", "Regex pattern to match in log messages")
+	logsSearchCmd.Flags().String("since", "", "Only entries after this time (e.g., 2h, 30m, 1d or RFC3339)")
+	logsSearchCmd.Flags().String("until", "", "Only entries before this time (e.g., 1h or RFC3339)")
+	logsSearchCmd.Flags().String("level", "", "Minimum log level (debug, info, warn, error)")
+	logsSearchCmd.Flags().StringArray("field", nil, "Match a structured field, e.g. request_id=<id> (repeatable)")
+	logsSearchCmd.Flags().Int("limit", 100, "Entries per page (max 1000)")
+	logsSearchCmd.Flags().Int("page", 1, "Page of results to show")
 
 	// logs 명령어에 추가
 	logsCmd.AddCommand(logsFilterCmd)
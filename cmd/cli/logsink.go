@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/logger"
+
+	"github.com/spf13/cobra"
+)
+
+// 외부 로그 싱크 명령어
+var logsSinkCmd = &cobra.Command{
+	Use:   "sink",
+	Short: "Manage external log sinks (syslog, Loki, HTTP)",
+	Long: `Show and toggle the external log sinks configured in log_sinks.
+Enabling or disabling a sink, or a component for a sink, lasts until the
+supervisor restarts.`,
+}
+
+var logsSinkListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List log sinks and their delivery status",
+	Run: func(cmd *cobra.Command, args []string) {
+		var sinks []logger.SinkStatus
+		alertRequest(ipc.MessageTypeLogSinkList, nil, &sinks)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(sinks)
+			return
+		}
+
+		if len(sinks) == 0 {
+			fmt.Println("📤 No log sinks configured")
+			return
+		}
+
+		fmt.Printf("📤 Log Sinks (%d):\n\n", len(sinks))
+		fmt.Printf("%-16s %-8s %-9s %-20s %-9s %-10s %-9s %s\n", "NAME", "TYPE", "STATE", "COMPONENTS", "PENDING", "SENT", "DROPPED", "LAST ERROR")
+		fmt.Println(strings.Repeat("-", 100))
+		for _, sink := range sinks {
+			state := "enabled"
+			if !sink.Enabled {
+				state = "disabled"
+			} else if sink.Failures > 0 {
+				state = "failing"
+			}
+
+			components := "all"
+			if len(sink.Components) > 0 {
+				components = strings.Join(sink.Components, ",")
+			}
+			if len(sink.DisabledComponents) > 0 {
+				components += " -" + strings.Join(sink.DisabledComponents, ",-")
+			}
+
+			lastError := "-"
+			if sink.LastError != "" {
+				lastError = fmt.Sprintf("%s (%s)", sink.LastError, sink.LastErrorAt.Format("15:04:05"))
+			}
+
+			fmt.Printf("%-16s %-8s %-9s %-20s %-9d %-10d %-9d %s\n",
+				sink.Name, sink.Type, state, components, sink.Pending, sink.Sent, sink.Dropped, lastError)
+		}
+	},
+}
+
+var logsSinkEnableCmd = &cobra.Command{
+	Use:   "enable <sink> [component]",
+	Short: "Resume forwarding to a sink, or for one component",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogSinkEnabled(args, true)
+	},
+}
+
+var logsSinkDisableCmd = &cobra.Command{
+	Use:   "disable <sink> [component]",
+	Short: "Stop forwarding to a sink, or for one component",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogSinkEnabled(args, false)
+	},
+}
+
+func setLogSinkEnabled(args []string, enabled bool) {
+	data := map[string]interface{}{"sink": args[0]}
+	target := args[0]
+	if len(args) > 1 {
+		data["component"] = args[1]
+		target = fmt.Sprintf("%s for %s", args[0], args[1])
+	}
+
+	msgType := ipc.MessageTypeLogSinkDisable
+	action := "disabled"
+	if enabled {
+		msgType = ipc.MessageTypeLogSinkEnable
+		action = "enabled"
+	}
+
+	alertRequest(msgType, data, nil)
+	fmt.Printf("✅ Log sink %s %s\n", target, action)
+}
+
+func init() {
+	logsSinkCmd.AddCommand(logsSinkListCmd)
+	logsSinkCmd.AddCommand(logsSinkEnableCmd)
+	logsSinkCmd.AddCommand(logsSinkDisableCmd)
+	logsCmd.AddCommand(logsSinkCmd)
+}
@@ -2,9 +2,11 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
@@ -15,19 +17,95 @@ import (
 // Global IPC client
 var client *ipc.Client
 
+// exit 명령어 종료 (셸 안에서는 셸을 끝내지 않고 명령어만 중단)
+var exit = os.Exit
+
 var rootCmd = &cobra.Command{
 	Use:   "tmidb-cli",
 	Short: "tmiDB CLI tool for managing tmiDB-Core components",
 	Long: `tmiDB CLI is a command-line tool for managing and monitoring 
 tmiDB-Core components including logging, process control, and system monitoring.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// 셸 안에서는 셸을 시작할 때 만든 클라이언트(연결 유지)를 계속 사용
+		if shellActive {
+			return
+		}
+
 		// IPC 클라이언트 초기화 (연결은 SendMessage에서 개별적으로 수행)
-		socketPath := os.Getenv("TMIDB_SOCKET_PATH")
-		client = ipc.NewClient(socketPath)
+		c, err := newCommandClient(cmd, 0)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+		client = c
 	},
 	// PersistentPostRun 제거 (연결은 SendMessage에서 개별적으로 관리)
 }
 
+// newCommandClient는 전역 플래그(--addr, --timeout, --token-file 등)로 클라이언트를 만듭니다.
+// minTimeout이 --timeout보다 길면 요청 제한 시간을 minTimeout으로 늘립니다 (오래 걸리는 요청용).
+func newCommandClient(cmd *cobra.Command, minTimeout time.Duration) (*ipc.Client, error) {
+	opts := ipc.DefaultClientOptions()
+	opts.RequestTimeout, _ = cmd.Flags().GetDuration("timeout")
+	opts.ConnectTimeout, _ = cmd.Flags().GetDuration("connect-timeout")
+	opts.MaxRetries, _ = cmd.Flags().GetInt("retries")
+	if opts.RequestTimeout < minTimeout {
+		opts.RequestTimeout = minTimeout
+	}
+
+	token, err := readTokenFlag(cmd)
+	if err != nil {
+		return nil, err
+	}
+	opts.Token = token
+
+	// 원격 노드 관리 (mTLS)
+	if addr, _ := cmd.Flags().GetString("addr"); addr != "" {
+		tlsDir, _ := cmd.Flags().GetString("tls-dir")
+		return newRemoteClient(addr, tlsDir, opts)
+	}
+
+	return ipc.NewClientWithOptions(os.Getenv("TMIDB_SOCKET_PATH"), opts), nil
+}
+
+// commandRequest 최소 timeout을 보장하는 클라이언트로 요청하고 응답을 out에 디코딩
+func commandRequest(cmd *cobra.Command, timeout time.Duration, msgType ipc.MessageType, data map[string]interface{}, out interface{}) {
+	c, err := newCommandClient(cmd, timeout)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		exit(1)
+	}
+	defer c.Close()
+
+	resp, err := c.SendMessage(msgType, data)
+	if err != nil {
+		fmt.Printf("❌ Failed to communicate with supervisor: %v\n", err)
+		exit(1)
+	}
+	if !resp.Success {
+		fmt.Printf("❌ Error: %s\n", resp.Error)
+		exit(1)
+	}
+	raw, _ := json.Marshal(resp.Data)
+	if err := json.Unmarshal(raw, out); err != nil {
+		fmt.Printf("❌ Failed to parse response: %v\n", err)
+		exit(1)
+	}
+}
+
+// newRemoteClient는 tlsDir의 클라이언트 인증서로 원격 슈퍼바이저에 연결하는 클라이언트를 만듭니다
+func newRemoteClient(addr, tlsDir string, opts ipc.ClientOptions) (*ipc.Client, error) {
+	tlsConfig, err := ipc.ClientTLSConfig(
+		filepath.Join(tlsDir, "client.crt"),
+		filepath.Join(tlsDir, "client.key"),
+		filepath.Join(tlsDir, "ca.crt"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return ipc.NewRemoteClient(addr, tlsConfig, opts), nil
+}
+
 // 모니터링 관련 명령어들
 var monitorCmd = &cobra.Command{
 	Use:   "monitor",
@@ -110,12 +188,12 @@ var monitorServicesCmd = &cobra.Command{
 		resp, err := client.SendMessage(ipc.MessageTypeSystemHealth, nil)
 		if err != nil {
 			fmt.Printf("❌ Failed to get system health: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if !resp.Success {
 			fmt.Printf("❌ Error: %s\n", resp.Error)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// JSON을 SystemHealth로 변환
@@ -123,18 +201,15 @@ var monitorServicesCmd = &cobra.Command{
 		var health ipc.SystemHealth
 		if err := json.Unmarshal(healthData, &health); err != nil {
 			fmt.Printf("❌ Failed to parse health data: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// 출력 포맷터 가져오기
 		formatter := getFormatter(cmd)
 
 		// JSON/YAML 출력인 경우
-		if format, _ := cmd.Flags().GetString("output"); format == "json" || format == "json-pretty" || format == "yaml" {
-			if err := formatter.Print(health); err != nil {
-				fmt.Printf("❌ Failed to format output: %v\n", err)
-				os.Exit(1)
-			}
+		if formatter.Structured() {
+			formatter.Output(health)
 			return
 		}
 
@@ -162,66 +237,6 @@ var monitorServicesCmd = &cobra.Command{
 	},
 }
 
-var monitorHealthCmd = &cobra.Command{
-	Use:   "health",
-	Short: "Check overall system health",
-	Long:  "Perform a quick health check of all components",
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := client.Ping(); err != nil {
-			fmt.Printf("❌ Supervisor is not responding: %v\n", err)
-			os.Exit(1)
-		}
-
-		// 프로세스 상태 확인
-		processes, err := client.GetProcessList()
-		if err != nil {
-			fmt.Printf("❌ Failed to get process status: %v\n", err)
-			os.Exit(1)
-		}
-
-		healthy := 0
-		total := len(processes)
-
-		for _, process := range processes {
-			if process.Status == "running" {
-				healthy++
-			}
-		}
-
-		healthSummary := map[string]interface{}{
-			"supervisor_status":    "healthy",
-			"total_components":     total,
-			"healthy_components":   healthy,
-			"unhealthy_components": total - healthy,
-			"health_percentage":    float64(healthy) / float64(total) * 100,
-			"components":           processes,
-		}
-
-		// 출력 포맷터 가져오기
-		formatter := getFormatter(cmd)
-
-		// JSON/YAML 출력인 경우
-		if format, _ := cmd.Flags().GetString("output"); format == "json" || format == "json-pretty" || format == "yaml" {
-			if err := formatter.Print(healthSummary); err != nil {
-				fmt.Printf("❌ Failed to format output: %v\n", err)
-				os.Exit(1)
-			}
-			return
-		}
-
-		// 기본 텍스트 출력
-		fmt.Println("🏥 Performing health check...")
-		fmt.Println("✅ Supervisor is healthy")
-		fmt.Printf("📊 System Health: %d/%d components running\n", healthy, total)
-
-		if healthy == total {
-			fmt.Println("✅ All components are healthy")
-		} else {
-			fmt.Printf("⚠️ %d components need attention\n", total-healthy)
-		}
-	},
-}
-
 // 시스템 상태 명령어
 var statusCmd = &cobra.Command{
 	Use:   "status",
@@ -231,7 +246,7 @@ var statusCmd = &cobra.Command{
 		processes, err := client.GetProcessList()
 		if err != nil {
 			fmt.Printf("❌ Failed to get process list: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// 기본 컴포넌트 목록 (실제 프로세스가 없어도 표시)
@@ -247,7 +262,7 @@ var statusCmd = &cobra.Command{
 		formatter := getFormatter(cmd)
 
 		// JSON/YAML 출력인 경우 구조화된 데이터 출력
-		if format, _ := cmd.Flags().GetString("output"); format == "json" || format == "json-pretty" || format == "yaml" {
+		if formatter.Structured() {
 			statusData := make(map[string]interface{})
 			for _, component := range components {
 				if process, exists := processMap[component]; exists {
@@ -269,10 +284,7 @@ var statusCmd = &cobra.Command{
 					}
 				}
 			}
-			if err := formatter.Print(statusData); err != nil {
-				fmt.Printf("❌ Failed to format output: %v\n", err)
-				os.Exit(1)
-			}
+			formatter.Output(statusData)
 			return
 		}
 
@@ -316,14 +328,14 @@ var serviceListCmd = &cobra.Command{
 		processes, err := client.GetProcessList()
 		if err != nil {
 			fmt.Printf("❌ Failed to get process list: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// 출력 포맷터 가져오기
 		formatter := getFormatter(cmd)
 
 		// JSON/YAML 출력인 경우
-		if format, _ := cmd.Flags().GetString("output"); format == "json" || format == "json-pretty" || format == "yaml" {
+		if formatter.Structured() {
 			serviceData := make(map[string]interface{})
 			for _, proc := range processes {
 				serviceData[proc.Name] = map[string]interface{}{
@@ -342,10 +354,7 @@ var serviceListCmd = &cobra.Command{
 					"start_time": proc.StartTime,
 				}
 			}
-			if err := formatter.Print(serviceData); err != nil {
-				fmt.Printf("❌ Failed to format output: %v\n", err)
-				os.Exit(1)
-			}
+			formatter.Output(serviceData)
 			return
 		}
 
@@ -383,7 +392,7 @@ var serviceControlCmd = &cobra.Command{
 			err := startService(serviceName)
 			if err != nil {
 				fmt.Printf("❌ Failed to start service %s: %v\n", serviceName, err)
-				os.Exit(1)
+				exit(1)
 			}
 			fmt.Printf("✅ Service %s started successfully\n", serviceName)
 
@@ -391,7 +400,7 @@ var serviceControlCmd = &cobra.Command{
 			err := stopService(serviceName)
 			if err != nil {
 				fmt.Printf("❌ Failed to stop service %s: %v\n", serviceName, err)
-				os.Exit(1)
+				exit(1)
 			}
 			fmt.Printf("✅ Service %s stopped successfully\n", serviceName)
 
@@ -399,13 +408,13 @@ var serviceControlCmd = &cobra.Command{
 			err := restartService(serviceName)
 			if err != nil {
 				fmt.Printf("❌ Failed to restart service %s: %v\n", serviceName, err)
-				os.Exit(1)
+				exit(1)
 			}
 			fmt.Printf("✅ Service %s restarted successfully\n", serviceName)
 
 		default:
 			fmt.Printf("❌ Invalid action: %s. Use start, stop, or restart\n", action)
-			os.Exit(1)
+			exit(1)
 		}
 	},
 }
@@ -423,15 +432,15 @@ var serviceLogsCmd = &cobra.Command{
 		if follow {
 			fmt.Printf("📜 Following logs for %s (Press Ctrl+C to stop):\n", serviceName)
 			// 실시간 로그 스트리밍 구현
-			if err := streamServiceLogs(serviceName); err != nil {
+			if err := streamServiceLogs(serviceName, lines); err != nil {
 				fmt.Printf("❌ Failed to stream logs: %v\n", err)
-				os.Exit(1)
+				exit(1)
 			}
 		} else {
 			fmt.Printf("📜 Recent logs for %s:\n", serviceName)
 			if err := getServiceLogs(serviceName, lines); err != nil {
 				fmt.Printf("❌ Failed to get logs: %v\n", err)
-				os.Exit(1)
+				exit(1)
 			}
 		}
 	},
@@ -558,7 +567,7 @@ func startService(serviceName string) error {
 		return err
 	}
 	if !resp.Success {
-		return fmt.Errorf(resp.Error)
+		return errors.New(resp.Error)
 	}
 	return nil
 }
@@ -570,7 +579,7 @@ func stopService(serviceName string) error {
 		return err
 	}
 	if !resp.Success {
-		return fmt.Errorf(resp.Error)
+		return errors.New(resp.Error)
 	}
 	return nil
 }
@@ -582,7 +591,7 @@ func restartService(serviceName string) error {
 		return err
 	}
 	if !resp.Success {
-		return fmt.Errorf(resp.Error)
+		return errors.New(resp.Error)
 	}
 	return nil
 }
@@ -597,7 +606,7 @@ func getServiceLogs(serviceName string, lines int) error {
 		return err
 	}
 	if !resp.Success {
-		return fmt.Errorf(resp.Error)
+		return errors.New(resp.Error)
 	}
 
 	// 로그 출력
@@ -613,29 +622,23 @@ func getServiceLogs(serviceName string, lines int) error {
 	return nil
 }
 
-func streamServiceLogs(serviceName string) error {
-	// 실시간 로그 스트리밍 구현
-	data := map[string]interface{}{
-		"component": serviceName,
-		"action":    "start",
-	}
-	resp, err := client.SendMessage(ipc.MessageTypeLogStream, data)
-	if err != nil {
-		return err
-	}
-	if !resp.Success {
-		return fmt.Errorf(resp.Error)
-	}
-
-	// 실제 스트리밍은 IPC 연결을 통해 구현해야 함
-	fmt.Println("Log streaming started (simplified implementation)")
-	return nil
+func streamServiceLogs(serviceName string, lines int) error {
+	return followLogs(serviceName, lines)
 }
 
 func init() {
+	// 슈퍼바이저 연결 플래그
+	defaults := ipc.DefaultClientOptions()
+	rootCmd.PersistentFlags().Duration("timeout", defaults.RequestTimeout, "Timeout for each request to the supervisor")
+	rootCmd.PersistentFlags().Duration("connect-timeout", defaults.ConnectTimeout, "Timeout for each connection attempt to the supervisor socket")
+	rootCmd.PersistentFlags().Int("retries", defaults.MaxRetries, "Retries while the supervisor socket is unavailable")
+	rootCmd.PersistentFlags().String("token-file", "", "File containing an IPC auth token (default: $TMIDB_IPC_TOKEN)")
+	rootCmd.PersistentFlags().String("addr", os.Getenv("TMIDB_SUPERVISOR_ADDR"), "Remote supervisor address (host:port) to manage over mutual TLS")
+	rootCmd.PersistentFlags().String("tls-dir", defaultTLSDir(), "Directory with ca.crt, client.crt and client.key for --addr")
+
 	// 모든 명령어에 output 플래그 추가
 	addOutputFlag := func(cmd *cobra.Command) {
-		cmd.Flags().StringP("output", "o", "default", "Output format (default, json, json-pretty, yaml)")
+		cmd.Flags().StringP("output", "o", "default", outputFlagUsage)
 	}
 
 	// 모니터링 명령어에 플래그 추가
@@ -669,6 +672,6 @@ func init() {
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Printf("❌ Error: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 }
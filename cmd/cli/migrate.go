@@ -0,0 +1,387 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/migration"
+
+	"github.com/spf13/cobra"
+)
+
+// 카테고리 데이터 마이그레이션 명령어
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade category data to a new schema version",
+	Long: `Move target_categories rows that still use an old category schema version
+to a newer one.
+
+The usual workflow is: check what changed between the versions, generate a
+plan, check it against real rows, then run it.
+A generated plan guesses renames (one removed and one added field of the same
+type) and fills new required fields with their schema default. Save the plan
+with --save, edit it, and pass it back with --plan-file if the guess is wrong.
+
+Examples:
+  tmidb-cli db migrate diff sensor
+  tmidb-cli db migrate plan sensor --save plan.json
+  tmidb-cli db migrate preview --plan-file plan.json
+  tmidb-cli db migrate run --plan-file plan.json
+  tmidb-cli db migrate status 12
+  tmidb-cli db migrate rollback 12`,
+}
+
+var dbMigrateDiffCmd = &cobra.Command{
+	Use:   "diff <category>",
+	Short: "Compare two schema versions and show breaking changes",
+	Long: `Compare two schema versions of a category and classify every change.
+
+A change is breaking when data that matched the old version can fail the new
+one: a new required field, a type change, fewer enum values, tighter bounds or a
+removed field when additional fields are not allowed. Adding optional fields and
+loosening constraints are backward compatible.
+
+Examples:
+  tmidb-cli db migrate diff sensor
+  tmidb-cli db migrate diff sensor --from 1 --to 3 --fail-on-breaking`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var diff migration.CategorySchemaDiff
+		alertRequest(ipc.MessageTypeCategorySchemaDiff, migrateRequest(cmd, args), &diff)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(diff)
+		} else {
+			printSchemaDiff(&diff)
+		}
+		if failOnBreaking, _ := cmd.Flags().GetBool("fail-on-breaking"); failOnBreaking && !diff.Compatible {
+			exit(1)
+		}
+	},
+}
+
+var dbMigratePlanCmd = &cobra.Command{
+	Use:   "plan <category>",
+	Short: "Generate a data migration plan between two schema versions",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var plan migration.CategoryPlan
+		alertRequest(ipc.MessageTypeCategoryMigrationPlan, migrateRequest(cmd, args), &plan)
+
+		if path, _ := cmd.Flags().GetString("save"); path != "" {
+			data, _ := json.MarshalIndent(plan, "", "  ")
+			if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+				fmt.Printf("❌ Failed to save plan: %v\n", err)
+				exit(1)
+			}
+			fmt.Printf("💾 Plan saved to %s\n\n", path)
+		}
+		printMigrationPlan(cmd, &plan)
+	},
+}
+
+var dbMigratePreviewCmd = &cobra.Command{
+	Use:   "preview [category]",
+	Short: "Show affected rows and how they look after the upgrade",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data := migrateRequest(cmd, args)
+		limit, _ := cmd.Flags().GetInt("limit")
+		data["limit"] = limit
+
+		var preview migration.CategoryPreview
+		alertRequest(ipc.MessageTypeCategoryMigrationPreview, data, &preview)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(preview)
+			return
+		}
+
+		printMigrationPlan(cmd, preview.Plan)
+		fmt.Printf("📊 %d rows on v%d\n", preview.Affected, preview.Plan.FromVersion)
+		for _, row := range preview.Samples {
+			before, _ := json.Marshal(row.Before)
+			after, _ := json.Marshal(row.After)
+			status := "✅"
+			if row.Error != "" {
+				status = "❌"
+			}
+			fmt.Printf("\n%s %s\n   before: %s\n   after:  %s\n", status, row.TargetID, before, after)
+			if row.Error != "" {
+				fmt.Printf("   error:  %s\n", row.Error)
+			}
+		}
+		if preview.Invalid > 0 {
+			fmt.Printf("\n⚠️  %d of %d sampled rows fail the new schema and would stay on v%d\n",
+				preview.Invalid, len(preview.Samples), preview.Plan.FromVersion)
+		}
+	},
+}
+
+var dbMigrateRunCmd = &cobra.Command{
+	Use:   "run [category]",
+	Short: "Upgrade the rows in batches and record the result",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data := migrateRequest(cmd, args)
+		if name, _ := cmd.Flags().GetString("name"); name != "" {
+			data["name"] = name
+		}
+
+		var mig migration.Migration
+		alertRequest(ipc.MessageTypeCategoryMigrationRun, data, &mig)
+		fmt.Printf("🚀 Migration %d (%s) started\n", mig.ID, mig.Name)
+
+		if detach, _ := cmd.Flags().GetBool("detach"); detach {
+			fmt.Printf("   Follow it with: tmidb-cli db migrate status %d\n", mig.ID)
+			return
+		}
+
+		ticker := time.NewTicker(1 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			alertRequest(ipc.MessageTypeCategoryMigrationStatus, map[string]interface{}{"id": mig.ID}, &mig)
+			progress := migrationProgress(&mig)
+			if progress != nil {
+				fmt.Printf("\r   %d/%d migrated, %d failed", progress.Migrated, progress.Total, progress.Failed)
+			}
+			if mig.Status != "running" && mig.Status != "pending" {
+				fmt.Println()
+				break
+			}
+		}
+		printMigrationStatus(cmd, &mig)
+		if mig.Status != "completed" {
+			exit(1)
+		}
+	},
+}
+
+var dbMigrateStatusCmd = &cobra.Command{
+	Use:   "status <id>",
+	Short: "Show the status and progress of a migration",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var id int
+		if _, err := fmt.Sscanf(args[0], "%d", &id); err != nil || id <= 0 {
+			fmt.Printf("❌ Invalid migration id: %s\n", args[0])
+			exit(1)
+		}
+
+		var mig migration.Migration
+		alertRequest(ipc.MessageTypeCategoryMigrationStatus, map[string]interface{}{"id": id}, &mig)
+		printMigrationStatus(cmd, &mig)
+	},
+}
+
+var dbMigrateRollbackCmd = &cobra.Command{
+	Use:   "rollback [id]",
+	Short: "Roll back a migration, or the most recently completed one with --latest",
+	Long: `Roll back a migration in one transaction.
+
+Category migrations restore the rows they changed from the backups taken during
+the upgrade. Rows written again after the upgrade are skipped so newer data is
+not lost. SQL migrations run their down_sql.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data := map[string]interface{}{}
+		latest, _ := cmd.Flags().GetBool("latest")
+		switch {
+		case len(args) == 1:
+			var id int
+			if _, err := fmt.Sscanf(args[0], "%d", &id); err != nil || id <= 0 {
+				fmt.Printf("❌ Invalid migration id: %s\n", args[0])
+				exit(1)
+			}
+			data["id"] = id
+		case !latest:
+			fmt.Println("❌ Give a migration id or --latest")
+			exit(1)
+		}
+
+		var result struct {
+			Migration migration.Migration       `json:"migration"`
+			Result    migration.MigrationResult `json:"result"`
+		}
+		alertRequest(ipc.MessageTypeMigrationRollback, data, &result)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(result)
+			return
+		}
+		fmt.Printf("↩️  Migration %d (%s) rolled back: %d rows restored\n",
+			result.Migration.ID, result.Migration.Name, result.Result.Changes)
+		if skipped, ok := result.Result.Details["skipped"].(float64); ok && skipped > 0 {
+			fmt.Printf("   ⚠️  %d rows were changed after the migration and were left as they are\n", int(skipped))
+		}
+	},
+}
+
+// migrateRequest 플래그와 인자로 계획 요청 데이터를 만듦 (--plan-file이 있으면 파일의 계획을 사용)
+func migrateRequest(cmd *cobra.Command, args []string) map[string]interface{} {
+	data := map[string]interface{}{}
+
+	if path, _ := cmd.Flags().GetString("plan-file"); path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Printf("❌ Failed to read plan file: %v\n", err)
+			exit(1)
+		}
+		var plan map[string]interface{}
+		if err := json.Unmarshal(raw, &plan); err != nil {
+			fmt.Printf("❌ Invalid plan file: %v\n", err)
+			exit(1)
+		}
+		data["plan"] = plan
+		return data
+	}
+
+	if len(args) == 0 {
+		fmt.Println("❌ A category or --plan-file is required")
+		exit(1)
+	}
+	data["category"] = args[0]
+	if org, _ := cmd.Flags().GetString("org"); org != "" {
+		data["org_id"] = org
+	}
+	if from, _ := cmd.Flags().GetInt("from"); from > 0 {
+		data["from_version"] = from
+	}
+	if to, _ := cmd.Flags().GetInt("to"); to > 0 {
+		data["to_version"] = to
+	}
+	return data
+}
+
+// migrationProgress 마이그레이션 결과에서 진행 상황을 읽음
+func migrationProgress(mig *migration.Migration) *migration.CategoryProgress {
+	if len(mig.Result) == 0 {
+		return nil
+	}
+	var progress migration.CategoryProgress
+	if err := json.Unmarshal(mig.Result, &progress); err != nil {
+		return nil
+	}
+	return &progress
+}
+
+// printMigrationPlan 계획 출력
+func printMigrationPlan(cmd *cobra.Command, plan *migration.CategoryPlan) {
+	formatter := getFormatter(cmd)
+	if formatter.Structured() {
+		formatter.Output(plan)
+		return
+	}
+
+	fmt.Printf("🧭 %s: v%d -> v%d\n", plan.Category, plan.FromVersion, plan.ToVersion)
+	if len(plan.Renames) == 0 && len(plan.Drops) == 0 && len(plan.Defaults) == 0 {
+		fmt.Println("   No data changes; rows only move to the new version")
+	}
+
+	renames := make([]string, 0, len(plan.Renames))
+	for from := range plan.Renames {
+		renames = append(renames, from)
+	}
+	sort.Strings(renames)
+	for _, from := range renames {
+		fmt.Printf("   rename   %s -> %s\n", from, plan.Renames[from])
+	}
+	for _, name := range plan.Drops {
+		fmt.Printf("   drop     %s\n", name)
+	}
+
+	defaults := make([]string, 0, len(plan.Defaults))
+	for name := range plan.Defaults {
+		defaults = append(defaults, name)
+	}
+	sort.Strings(defaults)
+	for _, name := range defaults {
+		value, _ := json.Marshal(plan.Defaults[name])
+		fmt.Printf("   default  %s = %s\n", name, value)
+	}
+
+	for _, warning := range plan.Warnings {
+		fmt.Printf("   ⚠️  %s\n", warning)
+	}
+	fmt.Println()
+}
+
+// printSchemaDiff 스키마 비교 결과 출력
+func printSchemaDiff(diff *migration.CategorySchemaDiff) {
+	fmt.Printf("🔍 %s: v%d -> v%d\n", diff.Category, diff.FromVersion, diff.ToVersion)
+	if len(diff.Changes) == 0 {
+		fmt.Println("   No schema changes")
+	}
+	for _, change := range diff.Changes {
+		icon := "✅"
+		if change.Breaking {
+			icon = "❌"
+		}
+		fmt.Printf("   %s %-26s %s\n", icon, change.Kind, change.Message)
+	}
+
+	if diff.Compatible {
+		fmt.Println("\n✅ Backward compatible")
+	} else {
+		fmt.Printf("\n⚠️  %d breaking changes\n", len(diff.Breaking()))
+	}
+}
+
+// printMigrationStatus 마이그레이션 상태 출력
+func printMigrationStatus(cmd *cobra.Command, mig *migration.Migration) {
+	formatter := getFormatter(cmd)
+	if formatter.Structured() {
+		formatter.Output(mig)
+		return
+	}
+
+	icon := map[string]string{"completed": "✅", "failed": "❌", "running": "⏳", "pending": "🕓"}[mig.Status]
+	fmt.Printf("%s Migration %d: %s (%s)\n", icon, mig.ID, mig.Name, mig.Status)
+	if mig.Plan != nil {
+		fmt.Printf("   Category:  %s v%d -> v%d\n", mig.Plan.Category, mig.Plan.FromVersion, mig.Plan.ToVersion)
+	}
+	if progress := migrationProgress(mig); progress != nil {
+		fmt.Printf("   Rows:      %d migrated, %d failed of %d (%d batches)\n",
+			progress.Migrated, progress.Failed, progress.Total, progress.Batches)
+		if progress.FinishedAt != nil {
+			fmt.Printf("   Duration:  %s\n", progress.FinishedAt.Sub(progress.StartedAt).Round(time.Millisecond))
+		}
+		for _, failure := range progress.Failures {
+			fmt.Printf("   ❌ %s: %s\n", failure.TargetID, failure.Error)
+		}
+	}
+	if mig.Error != "" {
+		fmt.Printf("   Error:     %s\n", mig.Error)
+	}
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{dbMigrateDiffCmd, dbMigratePlanCmd, dbMigratePreviewCmd, dbMigrateRunCmd} {
+		cmd.Flags().String("org", "", "Organization ID (needed when several organizations have the category)")
+		cmd.Flags().Int("from", 0, "Schema version to migrate from (default: the one before --to)")
+		cmd.Flags().Int("to", 0, "Schema version to migrate to (default: latest)")
+	}
+	dbMigrateDiffCmd.Flags().Bool("fail-on-breaking", false, "Exit with status 1 if any change is breaking")
+	dbMigratePreviewCmd.Flags().String("plan-file", "", "Use a saved (edited) plan instead of generating one")
+	dbMigrateRunCmd.Flags().String("plan-file", "", "Use a saved (edited) plan instead of generating one")
+	dbMigratePlanCmd.Flags().String("save", "", "Write the plan as JSON to this file for editing")
+	dbMigratePreviewCmd.Flags().Int("limit", 10, "Number of rows to show")
+	dbMigrateRunCmd.Flags().String("name", "", "Migration name (default: <category>_v<from>_to_v<to>_<time>)")
+	dbMigrateRunCmd.Flags().Bool("detach", false, "Start the migration and return without waiting")
+	dbMigrateRollbackCmd.Flags().Bool("latest", false, "Roll back the most recently completed migration")
+
+	dbMigrateCmd.AddCommand(dbMigrateDiffCmd)
+	dbMigrateCmd.AddCommand(dbMigratePlanCmd)
+	dbMigrateCmd.AddCommand(dbMigratePreviewCmd)
+	dbMigrateCmd.AddCommand(dbMigrateRunCmd)
+	dbMigrateCmd.AddCommand(dbMigrateStatusCmd)
+	dbMigrateCmd.AddCommand(dbMigrateRollbackCmd)
+	dbCmd.AddCommand(dbMigrateCmd)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// natsStreamsStatus JetStream 스트림 상태 응답
+type natsStreamsStatus struct {
+	ReconciledAt *time.Time `json:"reconciled_at"`
+	Streams      []struct {
+		Name           string     `json:"name"`
+		Subjects       []string   `json:"subjects"`
+		Storage        string     `json:"storage"`
+		MaxAge         string     `json:"max_age"`
+		Messages       uint64     `json:"messages"`
+		Bytes          uint64     `json:"bytes"`
+		LastSeq        uint64     `json:"last_seq"`
+		LastMessage    *time.Time `json:"last_message"`
+		Managed        bool       `json:"managed"`
+		Error          string     `json:"error"`
+		ConsumersError string     `json:"consumers_error"`
+		Consumers      []struct {
+			Name          string     `json:"name"`
+			FilterSubject string     `json:"filter_subject"`
+			Pending       uint64     `json:"pending"`
+			AckPending    int        `json:"ack_pending"`
+			Redelivered   int        `json:"redelivered"`
+			Waiting       int        `json:"waiting"`
+			Health        string     `json:"health"`
+			LastDelivered *time.Time `json:"last_delivered"`
+		} `json:"consumers"`
+	} `json:"streams"`
+	Missing []struct {
+		Name     string   `json:"name"`
+		Subjects []string `json:"subjects"`
+		Error    string   `json:"error"`
+	} `json:"missing"`
+}
+
+// NATS 명령어
+var natsCmd = &cobra.Command{
+	Use:   "nats",
+	Short: "Inspect the JetStream streams tmiDB uses",
+}
+
+var natsStreamsCmd = &cobra.Command{
+	Use:   "streams [stream]",
+	Short: "Show streams with consumer lag, pending messages and health",
+	Long: `Show every JetStream stream with its consumers.
+
+PENDING is the number of messages a consumer has not received yet (its lag),
+ACK PENDING the messages it received but has not acknowledged. A consumer is
+lagging when it is more than 10000 messages behind and stalled when it has
+work but received nothing for 5 minutes.
+
+Streams declared in nats_streams (by default TMIDB_INGEST, TMIDB_INGEST_DLQ
+and TMIDB_EVENTS) are created and reconciled by the supervisor and marked
+with *.
+
+Examples:
+  tmidb-cli nats streams
+  tmidb-cli nats streams TMIDB_INGEST
+  tmidb-cli nats streams -o json`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data := map[string]interface{}{}
+		if len(args) > 0 {
+			data["stream"] = args[0]
+		}
+
+		var status natsStreamsStatus
+		alertRequest(ipc.MessageTypeNATSStreams, data, &status)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(status)
+			return
+		}
+
+		fmt.Printf("📦 JetStream Streams (%d):\n\n", len(status.Streams))
+		fmt.Printf("%-22s %-10s %-12s %-12s %-10s %s\n", "STREAM", "STORAGE", "MESSAGES", "SIZE", "MAX AGE", "LAST MESSAGE")
+		fmt.Println(strings.Repeat("-", 90))
+		for _, stream := range status.Streams {
+			name := stream.Name
+			if stream.Managed {
+				name += " *"
+			}
+			last := "-"
+			if stream.LastMessage != nil {
+				last = stream.LastMessage.Local().Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%-22s %-10s %-12d %-12s %-10s %s\n",
+				name, stream.Storage, stream.Messages, formatBytes(int64(stream.Bytes)), stream.MaxAge, last)
+		}
+
+		for _, stream := range status.Streams {
+			fmt.Printf("\n📡 %s (%s)\n", stream.Name, strings.Join(stream.Subjects, ", "))
+			if stream.Error != "" {
+				fmt.Printf("   ⚠️  Reconcile failed: %s\n", stream.Error)
+			}
+			if stream.ConsumersError != "" {
+				fmt.Printf("   ⚠️  Consumers unavailable: %s\n", stream.ConsumersError)
+				continue
+			}
+			if len(stream.Consumers) == 0 {
+				fmt.Println("   (no consumers)")
+				continue
+			}
+
+			fmt.Printf("   %-28s %-10s %-12s %-12s %-12s %s\n", "CONSUMER", "HEALTH", "PENDING", "ACK PENDING", "REDELIVERED", "LAST DELIVERY")
+			for _, consumer := range stream.Consumers {
+				last := "never"
+				if consumer.LastDelivered != nil {
+					last = formatDuration(time.Since(*consumer.LastDelivered)) + " ago"
+				}
+				fmt.Printf("   %-28s %-10s %-12d %-12d %-12d %s\n",
+					consumer.Name, consumer.Health,
+					consumer.Pending, consumer.AckPending, consumer.Redelivered, last)
+			}
+		}
+
+		for _, missing := range status.Missing {
+			fmt.Printf("\n❌ %s (%s) has not been created", missing.Name, strings.Join(missing.Subjects, ", "))
+			if missing.Error != "" {
+				fmt.Printf(": %s", missing.Error)
+			}
+			fmt.Println()
+		}
+
+		if status.ReconciledAt != nil {
+			fmt.Printf("\n* managed by the supervisor, last reconciled %s\n", status.ReconciledAt.Local().Format("2006-01-02 15:04:05"))
+		}
+	},
+}
+
+var natsReconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Create missing streams and consumers and restore their configuration",
+	Long: `Apply the streams and durable consumers declared in nats_streams now.
+
+The supervisor does this whenever it starts, when NATS restarts and when
+nats_streams changes on config reload. Run it after someone changed or deleted
+a stream by hand. Streams that are not declared are left alone.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var result struct {
+			Streams int `json:"streams"`
+		}
+		alertRequest(ipc.MessageTypeNATSReconcile, nil, &result)
+		fmt.Printf("✅ %d streams reconciled\n", result.Streams)
+	},
+}
+
+func init() {
+	natsCmd.AddCommand(natsStreamsCmd)
+	natsCmd.AddCommand(natsReconcileCmd)
+	rootCmd.AddCommand(natsCmd)
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+
+	"github.com/spf13/cobra"
+)
+
+// 조직 관리 명령어
+var orgCmd = &cobra.Command{
+	Use:   "org",
+	Short: "Manage organizations",
+	Long: `Create, list and delete organizations.
+
+Deleting an organization also deletes its users, API and user access tokens,
+category schemas, category data and observations, listeners and attachments.
+Targets that are not linked to another organization are deleted too.
+
+Examples:
+  tmidb-cli org list
+  tmidb-cli org create "Acme Corp"
+  tmidb-cli org delete "Acme Corp"
+  tmidb-cli org usage --from 2026-02-01 --to 2026-03-01`,
+}
+
+var orgListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List organizations",
+	Run: func(cmd *cobra.Command, args []string) {
+		var orgs []database.Organization
+		alertRequest(ipc.MessageTypeOrgList, nil, &orgs)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(orgs)
+			return
+		}
+		if len(orgs) == 0 {
+			fmt.Println("🏢 No organizations")
+			return
+		}
+
+		fmt.Printf("🏢 Organizations (%d):\n\n", len(orgs))
+		fmt.Printf("%-36s %-24s %6s %10s %8s %7s %s\n", "ID", "NAME", "USERS", "CATEGORIES", "TARGETS", "TOKENS", "CREATED")
+		fmt.Println(strings.Repeat("-", 115))
+		for _, o := range orgs {
+			fmt.Printf("%-36s %-24s %6d %10d %8d %7d %s\n", o.OrgID, o.Name, o.Users, o.Categories, o.Targets, o.Tokens,
+				o.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+		}
+	},
+}
+
+var orgCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create an organization",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var org database.Organization
+		alertRequest(ipc.MessageTypeOrgCreate, map[string]interface{}{"name": args[0]}, &org)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(org)
+			return
+		}
+		fmt.Printf("✅ Organization %q created\n", org.Name)
+		fmt.Printf("   ID: %s\n", org.OrgID)
+	},
+}
+
+var orgDeleteCmd = &cobra.Command{
+	Use:   "delete <org-id|name>",
+	Short: "Delete an organization and everything in it",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !cmd.Flag("yes").Changed {
+			var orgs []database.Organization
+			alertRequest(ipc.MessageTypeOrgList, nil, &orgs)
+			var org *database.Organization
+			for i := range orgs {
+				if orgs[i].OrgID == args[0] || orgs[i].Name == args[0] {
+					org = &orgs[i]
+				}
+			}
+			if org == nil {
+				fmt.Printf("❌ Organization %q not found\n", args[0])
+				exit(1)
+			}
+
+			fmt.Printf("⚠️  Deleting organization %q (%s) also deletes:\n", org.Name, org.OrgID)
+			fmt.Printf("   %d users, %d tokens, %d categories and the data of %d targets\n",
+				org.Users, org.Tokens, org.Categories, org.Targets)
+			fmt.Print("Type the organization name to confirm: ")
+			// 조직 이름에 공백이 있을 수 있으므로 한 줄 전체를 읽음
+			response, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if strings.TrimSpace(response) != org.Name {
+				fmt.Println("❌ Delete cancelled")
+				return
+			}
+		}
+
+		var result struct {
+			Organization       database.OrganizationDeletion `json:"organization"`
+			StorageFailedFiles int                           `json:"storage_failed_files"`
+		}
+		alertRequest(ipc.MessageTypeOrgDelete, map[string]interface{}{"org": args[0]}, &result)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(result)
+			return
+		}
+		d := result.Organization
+		fmt.Printf("🗑️  Organization %q deleted\n", d.Name)
+		fmt.Printf("   Users: %d, tokens: %d, categories: %d, targets: %d, listeners: %d, attachments: %d\n",
+			d.Users, d.Tokens, d.Categories, d.Targets, d.Listeners, d.Attachments)
+		if result.StorageFailedFiles > 0 {
+			fmt.Printf("   ⚠️  %d attachment files could not be removed from storage\n", result.StorageFailedFiles)
+		}
+	},
+}
+
+var orgUsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Report API requests, ingest volume and storage per organization",
+	Long: `Report each organization's API requests, ingested records and bytes, average
+and peak ingest rate for a period, and the data it stores now.
+
+The period defaults to the current month (UTC). --from and --to take a date
+(2006-01-02) or an RFC3339 time. Measuring storage reads every observation,
+so use --storage=false for a quick report on large deployments.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		org, _ := cmd.Flags().GetString("org")
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		withStorage, _ := cmd.Flags().GetBool("storage")
+
+		var report []database.OrgUsage
+		alertRequest(ipc.MessageTypeOrgUsage, map[string]interface{}{
+			"org": org, "from": from, "to": to, "storage": withStorage,
+		}, &report)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(report)
+			return
+		}
+		if len(report) == 0 {
+			fmt.Println("📊 No usage")
+			return
+		}
+
+		fmt.Printf("📊 Usage %s - %s (UTC):\n\n", report[0].From.Format("2006-01-02 15:04"), report[0].To.Format("2006-01-02 15:04"))
+		fmt.Printf("%-24s %12s %12s %10s %10s %12s %10s %10s\n",
+			"ORGANIZATION", "REQUESTS", "INGESTED", "IN BYTES", "AVG REC/S", "PEAK REC/H", "ROWS", "STORED")
+		fmt.Println(strings.Repeat("-", 110))
+		for _, u := range report {
+			name := u.Name
+			if name == "" {
+				name = u.OrgID + " (deleted)"
+			}
+			rows, stored := "-", "-"
+			if u.Storage != nil {
+				rows = fmt.Sprintf("%d", u.Storage.CategoryDocuments+u.Storage.Observations)
+				stored = formatBytes(u.Storage.TotalBytes)
+			}
+			fmt.Printf("%-24s %12d %12d %10s %10.2f %12d %10s %10s\n", name, u.APIRequests, u.IngestedRecords,
+				formatBytes(u.IngestedBytes), u.IngestRate, u.PeakHourlyRecords, rows, stored)
+		}
+	},
+}
+
+func init() {
+	orgUsageCmd.Flags().String("org", "", "Only report this organization (ID or name)")
+	orgUsageCmd.Flags().String("from", "", "Start of the period (default: first day of this month, UTC)")
+	orgUsageCmd.Flags().String("to", "", "End of the period (default: now)")
+	orgUsageCmd.Flags().Bool("storage", true, "Measure the data each organization stores now")
+	orgDeleteCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+
+	orgCmd.AddCommand(orgListCmd)
+	orgCmd.AddCommand(orgCreateCmd)
+	orgCmd.AddCommand(orgDeleteCmd)
+	orgCmd.AddCommand(orgUsageCmd)
+	rootCmd.AddCommand(orgCmd)
+}
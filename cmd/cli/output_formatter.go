@@ -1,74 +1,256 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"text/template"
 	"time"
+	"unicode"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var outputFormat string
 
+// outputFlagUsage -o 플래그 설명 (모든 명령어 공통)
+const outputFlagUsage = "Output format (text, json, json-pretty, yaml, template='{{.Field}}')"
+
 // OutputFormatter 출력 형식을 관리하는 구조체
 type OutputFormatter struct {
-	format string
+	format  string
+	out     io.Writer
+	printed int // 출력한 문서 수 (YAML 문서 구분자용)
 }
 
 // NewOutputFormatter 새로운 출력 포맷터 생성
 func NewOutputFormatter(format string) *OutputFormatter {
 	return &OutputFormatter{
 		format: format,
+		out:    os.Stdout,
 	}
 }
 
+// Structured 텍스트 대신 데이터 그대로 출력하는 형식인지 (json, yaml, template)
+func (f *OutputFormatter) Structured() bool {
+	switch f.format {
+	case "json", "json-pretty", "yaml":
+		return true
+	}
+	_, ok := f.template()
+	return ok
+}
+
+// template -o template=... 또는 -o go-template=...의 템플릿 문자열
+func (f *OutputFormatter) template() (string, bool) {
+	for _, prefix := range []string{"template=", "go-template="} {
+		if strings.HasPrefix(f.format, prefix) {
+			return strings.TrimPrefix(f.format, prefix), true
+		}
+	}
+	return "", false
+}
+
 // Print 데이터를 지정된 형식으로 출력
 func (f *OutputFormatter) Print(data interface{}) error {
+	defer func() { f.printed++ }()
+
+	if text, ok := f.template(); ok {
+		return f.printTemplate(text, data)
+	}
 	switch f.format {
 	case "json":
 		return f.printJSON(data)
 	case "json-pretty":
 		return f.printJSONPretty(data)
+	case "yaml":
+		return f.printYAML(data)
 	default:
 		// 기본은 구조체에 따라 다르게 처리
 		return f.printDefault(data)
 	}
 }
 
+// Output 데이터를 출력하고, 실패하면 (잘못된 템플릿 등) 오류를 보여주고 종료합니다
+func (f *OutputFormatter) Output(data interface{}) {
+	if err := f.Print(data); err != nil {
+		fmt.Printf("❌ Failed to format output: %v\n", err)
+		exit(1)
+	}
+}
+
 // printJSON JSON 형식으로 출력 (한 줄)
 func (f *OutputFormatter) printJSON(data interface{}) error {
-	encoder := json.NewEncoder(os.Stdout)
+	encoder := json.NewEncoder(f.out)
 	encoder.SetEscapeHTML(false)
 	return encoder.Encode(data)
 }
 
 // printJSONPretty JSON 형식으로 출력 (들여쓰기)
 func (f *OutputFormatter) printJSONPretty(data interface{}) error {
-	encoder := json.NewEncoder(os.Stdout)
+	encoder := json.NewEncoder(f.out)
 	encoder.SetEscapeHTML(false)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(data)
 }
 
+// printYAML YAML 형식으로 출력. 키 이름이 JSON 출력과 같도록 JSON을 거쳐 변환합니다.
+// 여러 번 출력하면 (감시 모드 등) 문서 사이에 ---를 넣습니다.
+func (f *OutputFormatter) printYAML(data interface{}) error {
+	generic, err := toGeneric(data)
+	if err != nil {
+		return err
+	}
+	if f.printed > 0 {
+		fmt.Fprintln(f.out, "---")
+	}
+	encoder := yaml.NewEncoder(f.out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(generic); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// printTemplate Go 템플릿으로 출력 (kubectl -o go-template처럼).
+// 필드는 JSON 이름(.status)이나 CamelCase 이름(.Status)으로 참조할 수 있습니다.
+func (f *OutputFormatter) printTemplate(text string, data interface{}) error {
+	tmpl, err := template.New("output").Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid output template: %w", err)
+	}
+	generic, err := toGeneric(data)
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, templateData(generic)); err != nil {
+		return fmt.Errorf("failed to execute output template: %w", err)
+	}
+	if out.Len() > 0 && !bytes.HasSuffix(out.Bytes(), []byte("\n")) {
+		out.WriteByte('\n')
+	}
+	_, err = f.out.Write(out.Bytes())
+	return err
+}
+
+// templateFuncs 출력 템플릿에서 쓸 수 있는 함수
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join": func(sep string, items []interface{}) string {
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, sep)
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// toGeneric 데이터를 JSON을 거쳐 map/slice/기본 타입으로 변환합니다.
+// 큰 정수(나노초 단위 uptime 등)가 지수 표기로 바뀌지 않도록 정수는 int64로 읽습니다.
+func toGeneric(data interface{}) (interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+	return convertNumbers(generic), nil
+}
+
+// convertNumbers json.Number를 int64 또는 float64로 바꿉니다
+func convertNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = convertNumbers(value)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertNumbers(item)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return v
+}
+
+// templateData 맵 키마다 CamelCase 별칭을 추가합니다 (health_percentage → HealthPercentage)
+func templateData(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v)*2)
+		for key, value := range v {
+			value = templateData(value)
+			out[camelCase(key)] = value
+			out[key] = value
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = templateData(item)
+		}
+		return out
+	}
+	return v
+}
+
+func camelCase(key string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range key {
+		if r == '_' || r == '-' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
 // printDefault 기본 형식으로 출력
 func (f *OutputFormatter) printDefault(data interface{}) error {
 	// 데이터 타입에 따라 다르게 처리
 	switch v := data.(type) {
 	case string:
-		fmt.Println(v)
+		fmt.Fprintln(f.out, v)
 	case []byte:
-		fmt.Println(string(v))
+		fmt.Fprintln(f.out, string(v))
 	default:
 		// 그 외는 %+v로 출력
-		fmt.Printf("%+v\n", v)
+		fmt.Fprintf(f.out, "%+v\n", v)
 	}
 	return nil
 }
 
 // setupGlobalFlags 전역 플래그 설정
 func setupGlobalFlags(cmd *cobra.Command) {
-	cmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format (text, json, json-pretty)")
+	cmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", outputFlagUsage)
+	cmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(
+		[]string{"text", "json", "json-pretty", "yaml", "template="}, cobra.ShellCompDirectiveNoFileComp|cobra.ShellCompDirectiveNoSpace))
 }
 
 // getFormatter 현재 명령의 출력 포맷터 반환
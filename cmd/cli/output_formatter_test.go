@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+func formatTo(t *testing.T, format string, data ...interface{}) string {
+	t.Helper()
+	var out bytes.Buffer
+	formatter := NewOutputFormatter(format)
+	formatter.out = &out
+	for _, item := range data {
+		if err := formatter.Print(item); err != nil {
+			t.Fatalf("Print(%s): %v", format, err)
+		}
+	}
+	return out.String()
+}
+
+func TestOutputFormatterYAML(t *testing.T) {
+	process := ipc.ProcessInfo{Name: "api", Status: "running", Uptime: 90 * time.Minute}
+
+	// 키 이름은 JSON 출력과 같고 큰 정수는 지수 표기가 아니어야 함
+	got := formatTo(t, "yaml", process)
+	for _, want := range []string{"name: api\n", "status: running\n", "uptime: 5400000000000\n"} {
+		if !bytes.Contains([]byte(got), []byte(want)) {
+			t.Errorf("yaml output missing %q:\n%s", want, got)
+		}
+	}
+
+	// 여러 번 출력하면 문서 구분자
+	got = formatTo(t, "yaml", map[string]int{"a": 1}, map[string]int{"a": 2})
+	if got != "a: 1\n---\na: 2\n" {
+		t.Errorf("unexpected multi-document yaml:\n%s", got)
+	}
+}
+
+func TestOutputFormatterTemplate(t *testing.T) {
+	processes := []ipc.ProcessInfo{
+		{Name: "api", Status: "running", Restarts: 4},
+		{Name: "data-consumer", Status: "stopped"},
+	}
+	tests := []struct {
+		format string
+		data   interface{}
+		want   string
+	}{
+		{"template={{.Status}}", processes[0], "running\n"},
+		{"template={{.status}}", processes[0], "running\n"},
+		{"go-template={{.HealthPercentage}}", map[string]interface{}{"health_percentage": 50.5}, "50.5\n"},
+		{`template={{range .}}{{.Name}} {{if gt .Restarts 3}}flapping{{else}}ok{{end}}{{"\n"}}{{end}}`, processes, "api flapping\ndata-consumer ok\n"},
+		{`template={{upper .Name}} {{json .Limits}}`, processes[0], "API null\n"},
+	}
+	for _, tt := range tests {
+		if got := formatTo(t, tt.format, tt.data); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.format, got, tt.want)
+		}
+	}
+
+	if err := NewOutputFormatter("template={{.Status").Print(processes[0]); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+}
+
+func TestOutputFormatterStructured(t *testing.T) {
+	for format, want := range map[string]bool{
+		"text": false, "default": false, "": false,
+		"json": true, "json-pretty": true, "yaml": true, "template={{.Name}}": true, "go-template={{.Name}}": true,
+	} {
+		if got := NewOutputFormatter(format).Structured(); got != want {
+			t.Errorf("Structured(%q) = %v, want %v", format, got, want)
+		}
+	}
+}
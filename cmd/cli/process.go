@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
@@ -25,14 +24,14 @@ var processListCmd = &cobra.Command{
 		processes, err := client.GetProcessList()
 		if err != nil {
 			fmt.Printf("❌ Failed to get process list: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// 출력 형식 확인
 		formatter := getFormatter(cmd)
 		
 		// JSON 출력인 경우
-		if formatter.format == "json" || formatter.format == "json-pretty" {
+		if formatter.Structured() {
 			// ProcessInfo를 JSON 호환 형식으로 변환
 			var processData []interface{}
 			for _, process := range processes {
@@ -50,7 +49,7 @@ var processListCmd = &cobra.Command{
 			}
 			
 			formatted := FormatProcessList(processData)
-			formatter.Print(formatted)
+			formatter.Output(formatted)
 			return
 		}
 
@@ -87,7 +86,7 @@ var processStatusCmd = &cobra.Command{
 		processes, err := client.GetProcessList()
 		if err != nil {
 			fmt.Printf("❌ Failed to get process list: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		var found *ipc.ProcessInfo
@@ -100,7 +99,7 @@ var processStatusCmd = &cobra.Command{
 
 		if found == nil {
 			fmt.Printf("❌ Component %s not found\n", component)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Printf("  Status: %s\n", found.Status)
@@ -110,6 +109,39 @@ var processStatusCmd = &cobra.Command{
 		fmt.Printf("  CPU: %.1f%%\n", found.CPU)
 		fmt.Printf("  Auto Restart: %t\n", found.Enabled)
 		fmt.Printf("  Start Time: %s\n", found.StartTime.Format("2006-01-02 15:04:05"))
+		fmt.Printf("  Restarts: %d\n", found.Restarts)
+		if found.NextRestart != nil {
+			fmt.Printf("  Next Restart: %s (in %s)\n", found.NextRestart.Format("2006-01-02 15:04:05"),
+				time.Until(*found.NextRestart).Round(time.Second))
+		}
+
+		if probe := found.Health; probe != nil {
+			state := "✅ healthy"
+			switch {
+			case !probe.Healthy:
+				state = fmt.Sprintf("❌ unhealthy (%d consecutive failures)", probe.Failures)
+			case probe.Degraded:
+				state = "⚠️ degraded"
+			}
+			fmt.Printf("  Health (%s probe): %s\n", probe.Type, state)
+			if probe.Message != "" {
+				fmt.Printf("    Last Result: %s\n", probe.Message)
+			}
+			fmt.Printf("    Checked: %s (%s)\n", probe.CheckedAt.Format("2006-01-02 15:04:05"), probe.Latency.Round(time.Millisecond))
+		}
+
+		if limits := found.Limits; limits != nil {
+			fmt.Printf("  Resource Limits (%s):\n", limits.Enforcement)
+			if limits.CPUQuota > 0 {
+				fmt.Printf("    CPU Quota: %.0f%%\n", limits.CPUQuota*100)
+			}
+			if limits.MemoryLimit > 0 {
+				fmt.Printf("    Memory Limit: %s\n", formatBytes(limits.MemoryLimit))
+			}
+			if limits.MaxOpenFiles > 0 {
+				fmt.Printf("    Max Open Files: %d\n", limits.MaxOpenFiles)
+			}
+		}
 	},
 }
 
@@ -124,13 +156,123 @@ var processRestartCmd = &cobra.Command{
 
 		if err := client.RestartProcess(component); err != nil {
 			fmt.Printf("❌ Failed to restart %s: %v\n", component, err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Printf("✅ Component %s restarted successfully\n", component)
 	},
 }
 
+var processResetRestartsCmd = &cobra.Command{
+	Use:   "reset-restarts [component]",
+	Short: "Reset auto-restart counters of a component",
+	Long:  "Clear the restart budget and backoff of a component after manual intervention",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		component := args[0]
+
+		if err := client.ResetRestarts(component); err != nil {
+			fmt.Printf("❌ Failed to reset restarts for %s: %v\n", component, err)
+			exit(1)
+		}
+
+		fmt.Printf("✅ Restart counters for %s reset\n", component)
+	},
+}
+
+var processOutputCmd = &cobra.Command{
+	Use:   "output <component>",
+	Short: "Show recent stdout/stderr of a component",
+	Long: `Show the last stdout/stderr lines the supervisor keeps in memory for a component.
+
+The buffer is filled directly from the process pipes, independently of log files,
+and survives restarts. It shows why a component crashed even when it died before
+the log writer flushed anything. Lines from an earlier run are marked with their PID.
+
+Examples:
+  tmidb-cli process output data-consumer
+  tmidb-cli process output api --stream stderr --tail 50
+  tmidb-cli process output api --follow`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		component := args[0]
+		stream, _ := cmd.Flags().GetString("stream")
+		tail, _ := cmd.Flags().GetInt("tail")
+		follow, _ := cmd.Flags().GetBool("follow")
+		formatter := getFormatter(cmd)
+
+		output, err := client.GetProcessOutput(component, stream, tail)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+
+		if formatter.Structured() && !follow {
+			formatter.Output(output)
+			return
+		}
+		if !formatter.Structured() {
+			fmt.Printf("📜 %s (%s, PID %d): %d lines, %s of %s kept",
+				output.Name, output.Status, output.PID, len(output.Lines),
+				formatBytes(int64(output.Size)), formatBytes(int64(output.Capacity)))
+			if output.Dropped > 0 {
+				fmt.Printf(", %d older lines dropped", output.Dropped)
+			}
+			fmt.Println()
+		}
+		printOutputLines(formatter, output.Lines, output.PID)
+		if !follow {
+			return
+		}
+
+		// 마지막으로 출력한 줄 이후만 출력
+		client.KeepAlive()
+		defer client.Close()
+		var last time.Time
+		if n := len(output.Lines); n > 0 {
+			last = output.Lines[n-1].Time
+		}
+		ticker := time.NewTicker(1 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			output, err := client.GetProcessOutput(component, stream, 0)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				exit(1)
+			}
+			var fresh []ipc.OutputLine
+			for _, line := range output.Lines {
+				if line.Time.After(last) {
+					fresh = append(fresh, line)
+				}
+			}
+			if len(fresh) > 0 {
+				last = fresh[len(fresh)-1].Time
+				printOutputLines(formatter, fresh, output.PID)
+			}
+		}
+	},
+}
+
+// printOutputLines는 출력 줄을 표시합니다. 현재 실행이 아닌 줄에는 PID를 붙입니다.
+func printOutputLines(formatter *OutputFormatter, lines []ipc.OutputLine, currentPID int) {
+	for _, line := range lines {
+		if formatter.Structured() {
+			formatter.Output(line)
+			continue
+		}
+		prefix := "  "
+		if line.Stream == "stderr" {
+			prefix = "! "
+		}
+		run := ""
+		if line.PID != currentPID {
+			run = fmt.Sprintf(" [pid %d]", line.PID)
+		}
+		fmt.Printf("%s %s%s %s\n", line.Time.Local().Format("15:04:05.000"), prefix, run, line.Text)
+	}
+}
+
 var processStopCmd = &cobra.Command{
 	Use:   "stop [component]",
 	Short: "Stop a specific component",
@@ -142,7 +284,7 @@ var processStopCmd = &cobra.Command{
 
 		if err := client.StopProcess(component); err != nil {
 			fmt.Printf("❌ Failed to stop %s: %v\n", component, err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Printf("✅ Component %s stopped successfully\n", component)
@@ -160,7 +302,7 @@ var processStartCmd = &cobra.Command{
 
 		if err := client.StartProcess(component); err != nil {
 			fmt.Printf("❌ Failed to start %s: %v\n", component, err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Printf("✅ Component %s started successfully\n", component)
@@ -327,6 +469,105 @@ var processGroupRestartCmd = &cobra.Command{
 	},
 }
 
+var processRollingRestartCmd = &cobra.Command{
+	Use:   "rolling-restart <group>",
+	Short: "Restart a group one component at a time",
+	Long:  "Restart the components of a group one at a time, waiting for each to report healthy before moving on",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		group := args[0]
+		processes, exists := processGroups[group]
+		if !exists {
+			fmt.Printf("❌ Unknown process group: %s\n", group)
+			fmt.Println("Available groups: core, app, data, all")
+			return
+		}
+
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		components := sortByDependencies(processes)
+
+		fmt.Printf("🔄 Rolling restart of process group: %s\n", group)
+
+		resp, err := client.SendMessage(ipc.MessageTypeProcessRollingRestart, map[string]interface{}{
+			"components": components,
+			"timeout":    timeout.Seconds(),
+		})
+		if err != nil {
+			fmt.Printf("❌ Failed to start rolling restart: %v\n", err)
+			exit(1)
+		}
+		if !resp.Success {
+			fmt.Printf("❌ Error: %s\n", resp.Error)
+			exit(1)
+		}
+
+		data, _ := resp.Data.(map[string]interface{})
+		id, _ := data["id"].(string)
+		if err := monitorRollingRestart(id); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+	},
+}
+
+// 롤링 재시작 진행 상황 모니터링 (단계가 바뀔 때마다 출력)
+func monitorRollingRestart(id string) error {
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	reported := make(map[string]string)
+	for range ticker.C {
+		resp, err := client.SendMessage(ipc.MessageTypeRollingRestartStatus, map[string]interface{}{
+			"id": id,
+		})
+		if err != nil {
+			return err
+		}
+		if !resp.Success {
+			return fmt.Errorf("%s", resp.Error)
+		}
+
+		progress, ok := resp.Data.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		steps, _ := progress["steps"].([]interface{})
+		for _, s := range steps {
+			step, _ := s.(map[string]interface{})
+			component, _ := step["component"].(string)
+			status, _ := step["status"].(string)
+			if status == "pending" || reported[component] == status {
+				continue
+			}
+			reported[component] = status
+
+			icon := map[string]string{
+				"restarting": "🔄",
+				"waiting":    "⏳",
+				"healthy":    "✅",
+				"failed":     "❌",
+				"skipped":    "⏭️",
+			}[status]
+			fmt.Printf("  %s %-15s %s", icon, component, status)
+			if message, _ := step["message"].(string); message != "" {
+				fmt.Printf(" - %s", message)
+			}
+			fmt.Println()
+		}
+
+		switch progress["status"] {
+		case "completed":
+			fmt.Println("\n✅ Rolling restart completed")
+			return nil
+		case "failed":
+			errMsg, _ := progress["error"].(string)
+			return fmt.Errorf("rolling restart aborted: %s", errMsg)
+		}
+	}
+	return nil
+}
+
 var processGroupStatusCmd = &cobra.Command{
 	Use:   "status <group>",
 	Short: "Show status of all processes in a group",
@@ -513,6 +754,9 @@ func init() {
 	processCmd.AddCommand(processListCmd)
 	processCmd.AddCommand(processStatusCmd)
 	processCmd.AddCommand(processRestartCmd)
+	processCmd.AddCommand(processResetRestartsCmd)
+	processCmd.AddCommand(processOutputCmd)
+	processCmd.AddCommand(processRollingRestartCmd)
 	processCmd.AddCommand(processStopCmd)
 	processCmd.AddCommand(processStartCmd)
 
@@ -531,5 +775,13 @@ func init() {
 	processCmd.AddCommand(processGroupCmd)
 	processCmd.AddCommand(processBatchCmd)
 
+	processOutputCmd.Flags().String("stream", "", "Only show stdout or stderr")
+	processOutputCmd.Flags().Int("tail", 0, "Only show the last N lines (0 = everything kept)")
+	processOutputCmd.Flags().BoolP("follow", "f", false, "Keep printing new output")
+	processOutputCmd.RegisterFlagCompletionFunc("stream", cobra.FixedCompletions(
+		[]string{"stdout", "stderr"}, cobra.ShellCompDirectiveNoFileComp))
+
+	processRollingRestartCmd.Flags().Duration("timeout", 60*time.Second, "How long to wait for each component to become healthy")
+
 	rootCmd.AddCommand(processCmd)
 }
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+
+	"github.com/spf13/cobra"
+)
+
+// 초기 설정 명령어
+var setupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Complete the initial setup",
+}
+
+var setupInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create the first organization, admin user and admin token",
+	Long: `Complete the initial setup without the web console, for automated deployments.
+
+Creates the organization, an admin user in it and an admin API token, then
+marks the setup as completed. The token is printed once; store it right away.
+
+Running the command again with the same organization, admin user and password
+succeeds without changing anything (no new token is issued), so it is safe to
+run from provisioning scripts. It fails if the setup was completed with other
+values, or if the 30 minute setup window after the first API start has passed.
+
+Examples:
+  tmidb-cli setup init --org "Acme Corp" --admin-user admin --admin-pass-file /run/secrets/admin
+  tmidb-cli setup init --org "Acme Corp" --admin-user admin --admin-pass 's3cret-pass' -o json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		orgName, _ := cmd.Flags().GetString("org")
+		username, _ := cmd.Flags().GetString("admin-user")
+		password, _ := cmd.Flags().GetString("admin-pass")
+		if path, _ := cmd.Flags().GetString("admin-pass-file"); path != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				fmt.Printf("❌ Failed to read password file: %v\n", err)
+				exit(1)
+			}
+			password = strings.TrimRight(string(data), "\r\n")
+		}
+
+		req := database.SetupRequest{OrgName: orgName, Username: username, Password: password}
+		if err := req.Validate(); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+
+		var result database.SetupResult
+		alertRequest(ipc.MessageTypeSetupInit, map[string]interface{}{
+			"org_name": req.OrgName,
+			"username": req.Username,
+			"password": req.Password,
+		}, &result)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(result)
+			return
+		}
+
+		if !result.Created {
+			fmt.Printf("✅ Setup already completed for organization %q with admin %q\n", result.OrgName, result.Username)
+			return
+		}
+		fmt.Println("✅ Initial setup completed")
+		fmt.Printf("   Organization: %s (%s)\n", result.OrgName, result.OrgID)
+		fmt.Printf("   Admin user:   %s\n", result.Username)
+		fmt.Printf("   Admin token:  %s\n", result.Token)
+		fmt.Println("\n⚠️  Store the token now; it cannot be shown again")
+	},
+}
+
+var setupStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the initial setup is completed",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var state database.SetupState
+		alertRequest(ipc.MessageTypeSetupStatus, nil, &state)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(state)
+			return
+		}
+
+		switch {
+		case state.Completed:
+			fmt.Println("✅ Initial setup is completed")
+		case state.Locked:
+			fmt.Printf("🔒 Setup window expired at %s; the system is locked\n", state.Deadline.Local().Format("2006-01-02 15:04:05"))
+		case state.Deadline != nil:
+			fmt.Printf("⏳ Setup pending; complete it before %s (%s left)\n",
+				state.Deadline.Local().Format("2006-01-02 15:04:05"), formatDuration(time.Until(*state.Deadline)))
+		default:
+			fmt.Println("⏳ Setup pending; the window starts when the API first initializes the database")
+		}
+	},
+}
+
+func init() {
+	setupInitCmd.Flags().String("org", "", "Organization name (required)")
+	setupInitCmd.Flags().String("admin-user", "", "Admin username (required)")
+	setupInitCmd.Flags().String("admin-pass", "", "Admin password, at least 8 characters")
+	setupInitCmd.Flags().String("admin-pass-file", "", "Read the admin password from a file")
+	setupInitCmd.MarkFlagRequired("org")
+	setupInitCmd.MarkFlagRequired("admin-user")
+
+	setupCmd.AddCommand(setupInitCmd)
+	setupCmd.AddCommand(setupStatusCmd)
+	rootCmd.AddCommand(setupCmd)
+}
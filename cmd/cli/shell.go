@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// shellActive 셸 안에서 명령어를 실행하는 중인지 (연결 유지 클라이언트 재사용)
+var shellActive bool
+
+// shellExit 셸 안에서 명령어가 exit를 호출하면 panic으로 명령어만 중단
+type shellExit int
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Start an interactive shell",
+	Long: `Start an interactive shell that runs tmidb-cli commands over a single
+connection to the supervisor.
+
+Commands are typed without the tmidb-cli prefix. Tab completes commands,
+flags and component names (fetched live from the supervisor). Up/Down browse
+the history, which is kept in ~/.tmidb_cli_history.
+Connection flags (--addr, --token-file, ...) are taken from the shell command line.
+
+Examples:
+  tmidb-cli shell
+  tmidb> process list
+  tmidb> logs level data-consumer debug
+  tmidb> exit`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		client.KeepAlive()
+		defer client.Close()
+
+		editor := newLineEditor(os.Stdin, os.Stdout)
+		editor.complete = shellComplete
+		historyPath := shellHistoryPath()
+		editor.history = loadShellHistory(historyPath)
+
+		if editor.terminal {
+			fmt.Println("tmiDB shell. Type 'help' for commands, 'exit' to quit.")
+			if err := client.Ping(); err != nil {
+				fmt.Printf("⚠️  Supervisor is not responding: %v\n", err)
+			}
+		}
+
+		shellActive = true
+		defer func() { shellActive = false }()
+
+		// 실행 중인 명령어의 Ctrl+C가 셸을 끝내지 않도록 (명령어가 직접 처리)
+		signal.Ignore(os.Interrupt)
+		defer signal.Reset(os.Interrupt)
+
+		for {
+			line, err := editor.ReadLine("tmidb> ")
+			if errors.Is(err, errInterrupted) {
+				continue
+			}
+			if err != nil {
+				if err != io.EOF {
+					fmt.Printf("❌ %v\n", err)
+				}
+				return
+			}
+
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			editor.AddHistory(line)
+			appendShellHistory(historyPath, line)
+
+			words, err := splitShellArgs(line)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				continue
+			}
+			switch words[0] {
+			case "exit", "quit":
+				return
+			case "shell":
+				fmt.Println("❌ Already in a shell")
+				continue
+			}
+			runShellCommand(words)
+		}
+	},
+}
+
+// runShellCommand는 셸에서 입력한 명령어를 실행합니다. 명령어가 exit를 호출해도 셸은 계속됩니다.
+func runShellCommand(words []string) {
+	// 명령어가 signal.Notify로 Ctrl+C를 받을 수 있도록 실행하는 동안만 무시 해제
+	signal.Reset(os.Interrupt)
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+
+	defer func() {
+		signal.Stop(interrupts)
+		signal.Ignore(os.Interrupt)
+		exit = os.Exit
+		resetCommandFlags(rootCmd)
+		if r := recover(); r != nil {
+			if _, ok := r.(shellExit); !ok {
+				panic(r)
+			}
+		}
+	}()
+
+	exit = func(code int) { panic(shellExit(code)) }
+	rootCmd.SetArgs(words)
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+	}
+}
+
+// shellComplete는 cobra의 완성 기능(__complete)으로 셸 입력을 완성합니다
+func shellComplete(line string) ([]string, string) {
+	words, err := splitShellArgs(line)
+	if err != nil {
+		return nil, ""
+	}
+	word := ""
+	if len(words) > 0 && !strings.HasSuffix(line, " ") {
+		word = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(append(append([]string{cobra.ShellCompRequestCmd}, words...), word))
+	rootCmd.Execute()
+	rootCmd.SetOut(nil)
+	resetCommandFlags(rootCmd)
+
+	candidates := []string{}
+	if len(words) == 0 {
+		for _, builtin := range []string{"exit", "quit"} {
+			if strings.HasPrefix(builtin, word) {
+				candidates = append(candidates, builtin)
+			}
+		}
+	}
+	for _, entry := range strings.Split(out.String(), "\n") {
+		if entry == "" || strings.HasPrefix(entry, ":") {
+			continue
+		}
+		candidate, _, _ := strings.Cut(entry, "\t")
+		if candidate == cobra.ShellCompRequestCmd || candidate == cobra.ShellCompNoDescRequestCmd || candidate == "shell" {
+			continue
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, word
+}
+
+// resetCommandFlags는 이전 명령어에서 지정한 플래그를 기본값으로 되돌립니다
+// (cobra는 같은 프로세스에서 여러 번 실행하면 플래그 값을 유지함)
+func resetCommandFlags(cmd *cobra.Command) {
+	reset := func(flag *pflag.Flag) {
+		if !flag.Changed {
+			return
+		}
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			values := []string{}
+			if def := strings.Trim(flag.DefValue, "[]"); def != "" {
+				values = strings.Split(def, ",")
+			}
+			slice.Replace(values)
+		} else {
+			flag.Value.Set(flag.DefValue)
+		}
+		flag.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, child := range cmd.Commands() {
+		resetCommandFlags(child)
+	}
+}
+
+// splitShellArgs는 따옴표와 백슬래시를 처리해 입력을 인자로 나눕니다
+func splitShellArgs(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inWord {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// shellHistoryPath 셸 히스토리 파일 경로
+func shellHistoryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".tmidb_cli_history")
+}
+
+// loadShellHistory는 히스토리 파일의 최근 maxHistory 줄을 읽습니다
+func loadShellHistory(path string) []string {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > maxHistory {
+		lines = lines[len(lines)-maxHistory:]
+	}
+	history := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line != "" {
+			history = append(history, line)
+		}
+	}
+	return history
+}
+
+// appendShellHistory는 입력한 줄을 히스토리 파일에 추가합니다 (셸이 비정상 종료되어도 남도록 바로 기록)
+func appendShellHistory(path, line string) {
+	if path == "" {
+		return
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	fmt.Fprintln(file, line)
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+}
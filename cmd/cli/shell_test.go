@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitShellArgs(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"process list", []string{"process", "list"}},
+		{`logs search "connection failed"  api`, []string{"logs", "search", "connection failed", "api"}},
+		{`logs filter --pattern='a b' x\ y`, []string{"logs", "filter", "--pattern=a b", "x y"}},
+		{`config set key ""`, []string{"config", "set", "key", ""}},
+	}
+	for _, tt := range tests {
+		got, err := splitShellArgs(tt.line)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitShellArgs(%q) = %q, %v; want %q", tt.line, got, err, tt.want)
+		}
+	}
+	if _, err := splitShellArgs(`logs search "open`); err == nil {
+		t.Error("unterminated quote accepted")
+	}
+}
+
+func TestLineEditor(t *testing.T) {
+	editor := &lineEditor{out: io.Discard}
+	editor.complete = func(line string) ([]string, string) {
+		if line == "pro" {
+			return []string{"process"}, "pro"
+		}
+		return nil, ""
+	}
+	editor.AddHistory("process list")
+	editor.AddHistory("status")
+
+	read := func(input string) string {
+		editor.in = bufio.NewReader(strings.NewReader(input))
+		line, err := editor.edit("> ")
+		if err != nil {
+			t.Fatalf("edit(%q): %v", input, err)
+		}
+		return line
+	}
+
+	if got := read("pro\tlist\r"); got != "process list" {
+		t.Errorf("tab completion = %q", got)
+	}
+	if got := read("\x1b[A\x1b[A\r"); got != "process list" {
+		t.Errorf("history = %q", got)
+	}
+	if got := read("stat\x1b[D\x1b[DX\x7f\x1b[Fus\r"); got != "status" {
+		t.Errorf("cursor editing = %q", got)
+	}
+	if got := read("abc\x15def\r"); got != "def" {
+		t.Errorf("Ctrl+U = %q", got)
+	}
+
+	editor.in = bufio.NewReader(strings.NewReader("\x04"))
+	if _, err := editor.edit("> "); err != io.EOF {
+		t.Errorf("Ctrl+D on empty line = %v, want EOF", err)
+	}
+}
+
+func TestCompletionResult(t *testing.T) {
+	if insert, list := completionResult([]string{"restart", "reset-restarts"}, "re"); insert != "s" || list != nil {
+		t.Errorf("common prefix = %q, %v", insert, list)
+	}
+	if insert, list := completionResult([]string{"start", "stop", "status"}, "st"); insert != "" || len(list) != 3 {
+		t.Errorf("ambiguous = %q, %v", insert, list)
+	}
+}
+
+func TestShellComplete(t *testing.T) {
+	got, word := shellComplete("process re")
+	if word != "re" || !reflect.DeepEqual(got, []string{"reset-restarts", "restart"}) {
+		t.Errorf("shellComplete(process re) = %v, %q", got, word)
+	}
+	got, _ = shellComplete("ex")
+	if len(got) == 0 || got[0] != "exit" {
+		t.Errorf("shellComplete(ex) = %v", got)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+// isTerminal 리눅스 외 플랫폼에서는 줄 단위 입력만 지원
+func isTerminal(fd int) bool {
+	return false
+}
+
+// makeRaw 리눅스 외 플랫폼에서는 raw 모드를 지원하지 않음
+func makeRaw(fd int) (func(), error) {
+	return nil, errors.New("raw terminal mode is only supported on linux")
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+
+	"github.com/spf13/cobra"
+)
+
+// 원격 관리용 인증서 명령어
+var tlsCmd = &cobra.Command{
+	Use:   "tls",
+	Short: "Generate certificates for remote administration",
+	Long: `Generate the CA, server and client certificates used by the supervisor's
+mutual TLS listener (ipc_tcp_addr) and by 'tmidb-cli --addr host:port'.`,
+	// 인증서 생성에는 슈퍼바이저 연결이 필요 없음
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+}
+
+var tlsInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a CA, a server certificate and an admin client certificate",
+	Long: `Create ca.crt/ca.key, server.crt/server.key and client.crt/client.key in --dir.
+
+Examples:
+  tmidb-cli tls init --dir ./tls --hosts node1.example.com,10.0.0.5
+
+  # On the node
+  TMIDB_IPC_TCP_ADDR=:7443 TMIDB_IPC_TLS_DIR=/etc/tmidb/tls tmidb-supervisor
+
+  # On the admin machine (ca.crt, client.crt and client.key copied to ~/.tmidb/tls)
+  TMIDB_SUPERVISOR_ADDR=node1.example.com:7443 tmidb-cli status`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString("dir")
+		hosts, _ := cmd.Flags().GetStringSlice("hosts")
+		validFor, _ := cmd.Flags().GetDuration("valid-for")
+
+		if _, err := os.Stat(filepath.Join(dir, "ca.key")); err == nil {
+			fmt.Printf("❌ A CA already exists in %s (use 'tls client' to issue more client certificates)\n", dir)
+			exit(1)
+		}
+
+		steps := []struct {
+			name string
+			fn   func() error
+		}{
+			{"CA", func() error { return ipc.GenerateCA(dir, "tmiDB IPC CA", validFor) }},
+			{"server certificate", func() error { return ipc.GenerateServerCert(dir, "server", hosts, validFor) }},
+			{"client certificate", func() error { return ipc.GenerateClientCert(dir, "client", ipc.RoleAdmin, validFor) }},
+		}
+		for _, step := range steps {
+			if err := step.fn(); err != nil {
+				fmt.Printf("❌ Failed to create %s: %v\n", step.name, err)
+				exit(1)
+			}
+			fmt.Printf("✅ Created %s\n", step.name)
+		}
+
+		fmt.Printf("\n📁 Certificates written to %s\n", dir)
+		fmt.Println("   Supervisor: ca.crt, server.crt, server.key")
+		fmt.Println("   CLI:        ca.crt, client.crt, client.key (admin)")
+	},
+}
+
+var tlsClientCmd = &cobra.Command{
+	Use:   "client <name>",
+	Short: "Issue an additional client certificate",
+	Long: `Issue <name>.crt/<name>.key signed by the CA in --dir. The role is stored in
+the certificate and decides which requests the client may send. Copy the files
+to the client's --tls-dir as client.crt and client.key.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString("dir")
+		role, _ := cmd.Flags().GetString("role")
+		validFor, _ := cmd.Flags().GetDuration("valid-for")
+
+		if err := ipc.GenerateClientCert(dir, args[0], ipc.Role(role), validFor); err != nil {
+			fmt.Printf("❌ Failed to create client certificate: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("✅ Created %s.crt and %s.key (%s) in %s\n", args[0], args[0], role, dir)
+	},
+}
+
+// defaultTLSDir 원격 접속용 인증서 기본 위치 ($TMIDB_TLS_DIR 또는 ~/.tmidb/tls)
+func defaultTLSDir() string {
+	if dir := os.Getenv("TMIDB_TLS_DIR"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "tls"
+	}
+	return filepath.Join(home, ".tmidb", "tls")
+}
+
+func init() {
+	tlsInitCmd.Flags().String("dir", "./tls", "Output directory")
+	tlsInitCmd.Flags().StringSlice("hosts", []string{"localhost", "127.0.0.1"}, "Host names and IPs clients use to reach the supervisor")
+	tlsInitCmd.Flags().Duration("valid-for", ipc.DefaultCertValidity, "Certificate validity")
+
+	tlsClientCmd.Flags().String("dir", "./tls", "Directory containing ca.crt and ca.key")
+	tlsClientCmd.Flags().String("role", string(ipc.RoleReadOnly), "Role granted to the client (readonly, admin)")
+	tlsClientCmd.Flags().Duration("valid-for", ipc.DefaultCertValidity, "Certificate validity")
+
+	tlsCmd.AddCommand(tlsInitCmd)
+	tlsCmd.AddCommand(tlsClientCmd)
+
+	rootCmd.AddCommand(tlsCmd)
+}
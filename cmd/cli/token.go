@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+
+	"github.com/spf13/cobra"
+)
+
+// API 토큰 명령어
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage data API tokens (rotate, expire, revoke)",
+	Long: `Manage the bearer tokens used for the data API: organization API tokens
+(--kind api) and user access tokens (--kind user, the default).
+
+Every change is recorded in the token audit log. Expired tokens are disabled
+by the API server within a minute. IPC tokens are managed with "auth token".
+
+Examples:
+  tmidb-cli token list
+  tmidb-cli token rotate <token-id> --grace 48h
+  tmidb-cli token expiry <token-id> --extend 30d
+  tmidb-cli token revoke <token-id> --reason "leaked in CI logs"
+  tmidb-cli token audit --token <token-id>`,
+}
+
+var tokenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List API and user access tokens",
+	Run: func(cmd *cobra.Command, args []string) {
+		org, _ := cmd.Flags().GetString("org")
+		var tokens []database.TokenSummary
+		alertRequest(ipc.MessageTypeTokenList, map[string]interface{}{"org_id": org}, &tokens)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(tokens)
+			return
+		}
+		if len(tokens) == 0 {
+			fmt.Println("🔑 No tokens")
+			return
+		}
+
+		fmt.Printf("🔑 Tokens (%d):\n\n", len(tokens))
+		fmt.Printf("%-36s %-5s %-8s %-20s %s\n", "ID", "KIND", "STATUS", "EXPIRES", "DESCRIPTION")
+		fmt.Println(strings.Repeat("-", 100))
+		for _, t := range tokens {
+			fmt.Printf("%-36s %-5s %-8s %-20s %s\n", t.TokenID, t.Kind, tokenStatus(t), formatExpiry(t.ExpiresAt), t.Description)
+		}
+	},
+}
+
+var tokenRotateCmd = &cobra.Command{
+	Use:   "rotate <token-id>",
+	Short: "Issue a replacement token; the old one keeps working for the grace period",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data := tokenRequest(cmd, args[0])
+		data["grace_period"], _ = cmd.Flags().GetString("grace")
+
+		var rotated database.RotatedToken
+		alertRequest(ipc.MessageTypeTokenRotate, data, &rotated)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(rotated)
+			return
+		}
+		fmt.Printf("🔄 Token %s rotated\n", rotated.OldTokenID)
+		fmt.Printf("   New token ID: %s\n", rotated.TokenID)
+		fmt.Printf("   New token:    %s\n", rotated.Token)
+		fmt.Println("   ⚠️  Store the new token now; it cannot be shown again")
+		if rotated.OldExpiresAt != nil {
+			fmt.Printf("   Old token works until %s\n", formatExpiry(rotated.OldExpiresAt))
+		} else {
+			fmt.Println("   Old token disabled")
+		}
+	},
+}
+
+var tokenExpiryCmd = &cobra.Command{
+	Use:   "expiry <token-id>",
+	Short: "Set, extend or clear a token's expiry",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data := tokenRequest(cmd, args[0])
+		at, _ := cmd.Flags().GetString("at")
+		extend, _ := cmd.Flags().GetString("extend")
+		never, _ := cmd.Flags().GetBool("never")
+		switch {
+		case at != "":
+			data["expires_at"] = at
+		case extend != "":
+			data["extend_by"] = extend
+		case never:
+			data["clear"] = true
+		default:
+			fmt.Println("❌ Give one of --at, --extend or --never")
+			exit(1)
+		}
+
+		var result struct {
+			TokenID   string     `json:"token_id"`
+			ExpiresAt *time.Time `json:"expires_at"`
+		}
+		alertRequest(ipc.MessageTypeTokenExpiry, data, &result)
+		fmt.Printf("⏳ Token %s now expires: %s\n", result.TokenID, formatExpiry(result.ExpiresAt))
+	},
+}
+
+var tokenRevokeCmd = &cobra.Command{
+	Use:   "revoke <token-id>",
+	Short: "Disable a token immediately",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data := tokenRequest(cmd, args[0])
+		data["reason"], _ = cmd.Flags().GetString("reason")
+
+		alertRequest(ipc.MessageTypeTokenRevoke, data, nil)
+		fmt.Printf("🚫 Token %s revoked\n", args[0])
+	},
+}
+
+var tokenAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the token audit log",
+	Run: func(cmd *cobra.Command, args []string) {
+		org, _ := cmd.Flags().GetString("org")
+		token, _ := cmd.Flags().GetString("token")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		var entries []database.TokenAuditEntry
+		alertRequest(ipc.MessageTypeTokenAudit, map[string]interface{}{
+			"org_id": org, "token_id": token, "limit": limit,
+		}, &entries)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(entries)
+			return
+		}
+		if len(entries) == 0 {
+			fmt.Println("📜 No audit entries")
+			return
+		}
+
+		fmt.Printf("%-20s %-8s %-36s %-20s %s\n", "TIME", "ACTION", "TOKEN", "ACTOR", "DETAILS")
+		fmt.Println(strings.Repeat("-", 110))
+		for _, e := range entries {
+			var details []string
+			for key, value := range e.Details {
+				details = append(details, fmt.Sprintf("%s=%v", key, value))
+			}
+			fmt.Printf("%-20s %-8s %-36s %-20s %s\n", e.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+				e.Action, e.TokenID, e.Actor, strings.Join(details, " "))
+		}
+	},
+}
+
+// tokenRequest 토큰 ID와 --kind, --org 플래그로 요청 데이터를 만듦
+func tokenRequest(cmd *cobra.Command, tokenID string) map[string]interface{} {
+	kind, _ := cmd.Flags().GetString("kind")
+	if kind != database.TokenKindUser && kind != database.TokenKindAPI {
+		fmt.Printf("❌ Invalid --kind %q (use user or api)\n", kind)
+		exit(1)
+	}
+	org, _ := cmd.Flags().GetString("org")
+	return map[string]interface{}{"token_id": tokenID, "kind": kind, "org_id": org}
+}
+
+// tokenStatus 토큰 상태 표시 (active, expired, revoked)
+func tokenStatus(t database.TokenSummary) string {
+	switch {
+	case !t.IsActive:
+		return "revoked"
+	case t.ExpiresAt != nil && t.ExpiresAt.Before(time.Now()):
+		return "expired"
+	default:
+		return "active"
+	}
+}
+
+// formatExpiry 만료 시각 표시 (없으면 never)
+func formatExpiry(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{tokenRotateCmd, tokenExpiryCmd, tokenRevokeCmd} {
+		cmd.Flags().String("kind", database.TokenKindUser, "Token kind: user (user access token) or api (organization API token)")
+		cmd.Flags().String("org", "", "Organization ID the token must belong to")
+	}
+	tokenListCmd.Flags().String("org", "", "Only list tokens of this organization")
+	tokenAuditCmd.Flags().String("org", "", "Only show entries of this organization")
+	tokenAuditCmd.Flags().String("token", "", "Only show entries of this token")
+	tokenAuditCmd.Flags().Int("limit", 50, "Number of entries to show")
+	tokenRotateCmd.Flags().String("grace", "24h", "How long the old token keeps working (0 disables it now)")
+	tokenExpiryCmd.Flags().String("at", "", "Expiry time (RFC3339, e.g. 2026-12-31T00:00:00Z)")
+	tokenExpiryCmd.Flags().String("extend", "", "Extend the current expiry (or now) by this long, e.g. 30d or 72h")
+	tokenExpiryCmd.Flags().Bool("never", false, "Remove the expiry")
+	tokenRevokeCmd.Flags().String("reason", "", "Reason recorded in the audit log")
+
+	tokenCmd.AddCommand(tokenListCmd)
+	tokenCmd.AddCommand(tokenRotateCmd)
+	tokenCmd.AddCommand(tokenExpiryCmd)
+	tokenCmd.AddCommand(tokenRevokeCmd)
+	tokenCmd.AddCommand(tokenAuditCmd)
+	rootCmd.AddCommand(tokenCmd)
+}
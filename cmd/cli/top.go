@@ -0,0 +1,326 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Live dashboard of components and resources",
+	Long: `Show a full-screen dashboard with per-component CPU and memory, restart
+counts, health, system CPU/memory/disk, IPC connections and the most recent
+error log lines. The view refreshes every --interval.
+
+Keys:
+  ↑/↓, k/j   select a component
+  r          restart the selected component
+  s          stop the selected component
+  t          start the selected component
+  q          quit
+
+Examples:
+  tmidb-cli top
+  tmidb-cli top --interval 5s --errors 15`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		interval, _ := cmd.Flags().GetDuration("interval")
+		errorLines, _ := cmd.Flags().GetInt("errors")
+		if interval < 500*time.Millisecond {
+			fmt.Println("❌ --interval must be at least 500ms")
+			exit(1)
+		}
+
+		// 새로 고칠 때마다 다시 연결하지 않도록 연결 유지
+		client.KeepAlive()
+		defer client.Close()
+
+		model := newTopModel(interval, errorLines)
+		if _, err := tea.NewProgram(model, tea.WithAltScreen()).Run(); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+	},
+}
+
+// topModel top 화면 상태
+type topModel struct {
+	interval   time.Duration
+	errorLines int
+
+	stats     map[string]interface{}
+	processes []ipc.ProcessInfo
+	errors    []ipc.LogEntry
+	fetchErr  error
+	updated   time.Time
+
+	cursor  int
+	pending string // 확인을 기다리는 동작 (restart, stop, start)
+	status  string // 마지막 동작 결과
+	width   int
+	height  int
+}
+
+type topTickMsg time.Time
+
+type topDataMsg struct {
+	stats     map[string]interface{}
+	processes []ipc.ProcessInfo
+	errors    []ipc.LogEntry
+	err       error
+}
+
+type topActionMsg struct {
+	action    string
+	component string
+	err       error
+}
+
+var (
+	topTitleStyle    = lipgloss.NewStyle().Bold(true)
+	topHeaderStyle   = lipgloss.NewStyle().Bold(true).Underline(true)
+	topSelectedStyle = lipgloss.NewStyle().Reverse(true)
+	topErrorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	topWarnStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+	topOKStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	topHelpStyle     = lipgloss.NewStyle().Faint(true)
+)
+
+func newTopModel(interval time.Duration, errorLines int) topModel {
+	return topModel{interval: interval, errorLines: errorLines, width: 100, height: 30}
+}
+
+func (m topModel) Init() tea.Cmd {
+	return tea.Batch(fetchTopData(m.errorLines), topTick(m.interval))
+}
+
+func topTick(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg { return topTickMsg(t) })
+}
+
+// fetchTopData는 시스템 통계, 프로세스 목록, 최근 오류 로그를 가져옵니다
+func fetchTopData(errorLines int) tea.Cmd {
+	return func() tea.Msg {
+		var data topDataMsg
+
+		resp, err := client.SendMessage(ipc.MessageTypeSystemStats, nil)
+		if err != nil {
+			data.err = err
+			return data
+		}
+		if !resp.Success {
+			data.err = fmt.Errorf("%s", resp.Error)
+			return data
+		}
+		data.stats, _ = resp.Data.(map[string]interface{})
+
+		if data.processes, err = client.GetProcessList(); err != nil {
+			data.err = err
+			return data
+		}
+
+		if errorLines > 0 {
+			var result struct {
+				Entries []ipc.LogEntry `json:"entries"`
+			}
+			resp, err := client.SendMessage(ipc.MessageTypeGetLogs, map[string]interface{}{
+				"search":    true,
+				"component": "all",
+				"level":     "error",
+				"since":     time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+				"limit":     errorLines,
+			})
+			if err == nil && resp.Success {
+				raw, _ := json.Marshal(resp.Data)
+				if json.Unmarshal(raw, &result) == nil {
+					data.errors = result.Entries
+				}
+			}
+		}
+		return data
+	}
+}
+
+// runTopAction은 선택한 컴포넌트에 동작을 실행합니다
+func runTopAction(action, component string) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		switch action {
+		case "restart":
+			err = client.RestartProcess(component)
+		case "stop":
+			err = client.StopProcess(component)
+		case "start":
+			err = client.StartProcess(component)
+		}
+		return topActionMsg{action: action, component: component, err: err}
+	}
+}
+
+func (m topModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+
+	case topTickMsg:
+		return m, tea.Batch(fetchTopData(m.errorLines), topTick(m.interval))
+
+	case topDataMsg:
+		m.fetchErr = msg.err
+		if msg.err == nil {
+			m.stats, m.processes, m.errors = msg.stats, msg.processes, msg.errors
+			m.updated = time.Now()
+			if m.cursor >= len(m.processes) {
+				m.cursor = max(len(m.processes)-1, 0)
+			}
+		}
+
+	case topActionMsg:
+		if msg.err != nil {
+			m.status = topErrorStyle.Render(fmt.Sprintf("❌ %s %s failed: %v", msg.action, msg.component, msg.err))
+		} else {
+			m.status = topOKStyle.Render(fmt.Sprintf("✅ %s %s done", msg.action, msg.component))
+		}
+		return m, fetchTopData(m.errorLines)
+
+	case tea.KeyMsg:
+		key := msg.String()
+
+		// 동작 확인 중이면 y만 실행
+		if m.pending != "" {
+			action := m.pending
+			m.pending = ""
+			if key == "y" || key == "Y" {
+				component := m.processes[m.cursor].Name
+				m.status = fmt.Sprintf("⏳ %s %s...", action, component)
+				return m, runTopAction(action, component)
+			}
+			m.status = "Cancelled"
+			return m, nil
+		}
+
+		switch key {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.processes)-1 {
+				m.cursor++
+			}
+		case "r", "s", "t":
+			if len(m.processes) == 0 {
+				return m, nil
+			}
+			m.pending = map[string]string{"r": "restart", "s": "stop", "t": "start"}[key]
+		}
+	}
+	return m, nil
+}
+
+func (m topModel) View() string {
+	var b strings.Builder
+
+	title := fmt.Sprintf("tmiDB top — refresh %s", m.interval)
+	if !m.updated.IsZero() {
+		title += ", updated " + m.updated.Format("15:04:05")
+	}
+	b.WriteString(topTitleStyle.Render(title) + "\n")
+
+	if m.fetchErr != nil {
+		b.WriteString(topErrorStyle.Render(truncateText("❌ "+m.fetchErr.Error(), m.width)) + "\n")
+	}
+
+	if m.stats != nil {
+		fmt.Fprintf(&b, "CPU %5.1f%%   Memory %5.1f%%   Disk %5.1f%%   IPC connections %d\n",
+			getFloatValue(m.stats, "cpu_usage"), getFloatValue(m.stats, "memory_usage"),
+			getFloatValue(m.stats, "disk_usage"), getIntValue(m.stats, "ipc_connections"))
+		fmt.Fprintf(&b, "Processes %d: %d running, %d stopped, %d error\n",
+			getIntValue(m.stats, "processes"), getIntValue(m.stats, "running"),
+			getIntValue(m.stats, "stopped"), getIntValue(m.stats, "errors"))
+	}
+	b.WriteString("\n")
+
+	b.WriteString(topHeaderStyle.Render(fmt.Sprintf("%-18s %-9s %7s %6s %9s %10s %8s  %-8s",
+		"NAME", "STATUS", "PID", "CPU%", "MEMORY", "UPTIME", "RESTARTS", "HEALTH")) + "\n")
+	for i, p := range m.processes {
+		row := fmt.Sprintf("%-18s %-9s %7d %6.1f %9s %10s %8d  %-8s",
+			truncateText(p.Name, 18), p.Status, p.PID, p.CPU, formatBytes(p.Memory),
+			formatDuration(p.Uptime), p.Restarts, topHealth(p.Health))
+		row = truncateText(row, m.width)
+		switch {
+		case i == m.cursor:
+			row = topSelectedStyle.Render(row)
+		case p.Status == "error":
+			row = topErrorStyle.Render(row)
+		case p.Status != "running":
+			row = topWarnStyle.Render(row)
+		}
+		b.WriteString(row + "\n")
+	}
+
+	if m.errorLines > 0 {
+		b.WriteString("\n" + topHeaderStyle.Render("Recent errors") + "\n")
+		if len(m.errors) == 0 {
+			b.WriteString(topHelpStyle.Render("No errors in the last 24 hours") + "\n")
+		}
+		for _, entry := range m.errors {
+			line := fmt.Sprintf("%s %-14s %s", entry.Timestamp.Local().Format("01-02 15:04:05"), entry.Process, entry.Message)
+			b.WriteString(topErrorStyle.Render(truncateText(line, m.width)) + "\n")
+		}
+	}
+
+	b.WriteString("\n")
+	switch {
+	case m.pending != "" && len(m.processes) > 0:
+		b.WriteString(topWarnStyle.Render(fmt.Sprintf("%s %s? (y/n)", m.pending, m.processes[m.cursor].Name)))
+	case m.status != "":
+		b.WriteString(m.status + "\n" + topHelpStyle.Render("↑/↓ select  r restart  s stop  t start  q quit"))
+	default:
+		b.WriteString(topHelpStyle.Render("↑/↓ select  r restart  s stop  t start  q quit"))
+	}
+	return b.String()
+}
+
+// topHealth는 헬스 체크 결과를 짧게 표시합니다
+func topHealth(health *ipc.ProbeResult) string {
+	switch {
+	case health == nil:
+		return "-"
+	case health.Degraded:
+		return "degraded"
+	case health.Healthy:
+		return "healthy"
+	}
+	return "failing"
+}
+
+// truncateText는 화면 폭에 맞게 문자열을 자릅니다
+func truncateText(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width == 1 {
+		return "…"
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+func init() {
+	topCmd.Flags().Duration("interval", 2*time.Second, "Refresh interval")
+	topCmd.Flags().Int("errors", 8, "Number of recent error log lines to show (0 to hide)")
+	rootCmd.AddCommand(topCmd)
+}
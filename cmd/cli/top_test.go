@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+func TestTopModel(t *testing.T) {
+	var model tea.Model = newTopModel(2*time.Second, 5)
+	model, _ = model.Update(topDataMsg{
+		stats: map[string]interface{}{"cpu_usage": 12.5, "processes": float64(2), "running": float64(1), "errors": float64(1)},
+		processes: []ipc.ProcessInfo{
+			{Name: "api", Status: "running", PID: 100, Memory: 64 << 20, Restarts: 2},
+			{Name: "data-consumer", Status: "error"},
+		},
+		errors: []ipc.LogEntry{{Process: "data-consumer", Level: "ERROR", Message: "connection refused", Timestamp: time.Now()}},
+	})
+
+	view := model.View()
+	for _, want := range []string{"api", "data-consumer", "12.5%", "connection refused"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view does not contain %q:\n%s", want, view)
+		}
+	}
+
+	// 선택 이동 후 재시작은 확인을 거쳐야 함
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if cmd != nil || !strings.Contains(model.View(), "restart data-consumer? (y/n)") {
+		t.Fatalf("restart should ask for confirmation:\n%s", model.View())
+	}
+	model, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if cmd != nil || model.(topModel).pending != "" {
+		t.Fatal("answering n must cancel the restart")
+	}
+
+	// 목록이 줄면 선택도 범위 안으로
+	model, _ = model.Update(topDataMsg{processes: []ipc.ProcessInfo{{Name: "api"}}})
+	if got := model.(topModel).cursor; got != 0 {
+		t.Errorf("cursor = %d after the list shrank", got)
+	}
+}
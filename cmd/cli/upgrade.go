@@ -0,0 +1,312 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/upgrade"
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade component binaries without downtime",
+	Long: `Install signed bundles of new api, data-manager and data-consumer binaries.
+
+The supervisor verifies the bundle signature against upgrade_public_keys in its
+config file, swaps the binaries atomically and restarts the components one at a
+time. If a component does not report healthy, every component upgraded so far is
+put back on its previous binary.`,
+}
+
+var upgradeApplyCmd = &cobra.Command{
+	Use:   "apply <bundle.tar.gz>",
+	Short: "Apply a signed upgrade bundle",
+	Long: `Verify a signed upgrade bundle and roll it out one component at a time.
+
+The bundle path is read by the supervisor, so with --addr it must be a path on
+the supervisor host.
+
+Examples:
+  tmidb-cli upgrade apply ./tmidb-1.4.0.tar.gz
+  tmidb-cli upgrade apply /srv/bundles/tmidb-1.4.0.tar.gz --timeout 2m --yes`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		bundle := args[0]
+		if addr, _ := cmd.Flags().GetString("addr"); addr == "" {
+			abs, err := filepath.Abs(bundle)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				exit(1)
+			}
+			if _, err := os.Stat(abs); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				exit(1)
+			}
+			bundle = abs
+		}
+
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+			fmt.Printf("⬆️  Upgrading components from %s\n", bundle)
+			fmt.Println("   Components restart one at a time and roll back if they fail their health checks.")
+			fmt.Print("\nContinue? (yes/no): ")
+			var response string
+			fmt.Scanln(&response)
+			if response != "yes" && response != "y" {
+				fmt.Println("Upgrade cancelled")
+				return
+			}
+		}
+
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		var started struct {
+			ID         string   `json:"id"`
+			Version    string   `json:"version"`
+			Components []string `json:"components"`
+		}
+		alertRequest(ipc.MessageTypeUpgradeApply, map[string]interface{}{
+			"bundle":  bundle,
+			"timeout": timeout.Seconds(),
+		}, &started)
+
+		fmt.Printf("🔏 Bundle verified: version %s (%s)\n", started.Version, strings.Join(started.Components, ", "))
+		if err := monitorUpgrade(started.ID); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+	},
+}
+
+var upgradeStatusCmd = &cobra.Command{
+	Use:   "status [id]",
+	Short: "Show the progress of an upgrade",
+	Long:  "Show the progress of an upgrade (default: the most recent one)",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data := map[string]interface{}{}
+		if len(args) > 0 {
+			data["id"] = args[0]
+		}
+		var progress upgradeProgress
+		alertRequest(ipc.MessageTypeUpgradeStatus, data, &progress)
+
+		formatter := getFormatter(cmd)
+		if formatter.Structured() {
+			formatter.Output(progress)
+			return
+		}
+
+		fmt.Printf("⬆️  Upgrade %s to %s: %s\n", progress.ID, progress.Version, progress.Status)
+		fmt.Printf("   Bundle:  %s\n", progress.Bundle)
+		fmt.Printf("   Started: %s\n", progress.StartTime.Local().Format("2006-01-02 15:04:05"))
+		if progress.EndTime != nil {
+			fmt.Printf("   Ended:   %s\n", progress.EndTime.Local().Format("2006-01-02 15:04:05"))
+		}
+		fmt.Println()
+		for _, step := range progress.Steps {
+			printUpgradeStep(step)
+		}
+		if progress.Error != "" {
+			fmt.Printf("\n❌ %s\n", progress.Error)
+		}
+	},
+}
+
+var upgradeKeygenCmd = &cobra.Command{
+	Use:   "keygen <name>",
+	Short: "Create a key pair for signing upgrade bundles",
+	Long: `Write <name>.key (private, keep it off the servers) and <name>.pub.
+Add the contents of <name>.pub to upgrade_public_keys in the supervisor config.
+
+Examples:
+  tmidb-cli upgrade keygen release`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		publicKey, privateKey, err := upgrade.GenerateKey()
+		if err != nil {
+			fmt.Printf("❌ Failed to generate key: %v\n", err)
+			exit(1)
+		}
+		keyPath, pubPath := args[0]+".key", args[0]+".pub"
+		if _, err := os.Stat(keyPath); err == nil {
+			fmt.Printf("❌ %s already exists\n", keyPath)
+			exit(1)
+		}
+		if err := os.WriteFile(keyPath, []byte(privateKey+"\n"), 0600); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+		if err := os.WriteFile(pubPath, []byte(publicKey+"\n"), 0644); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("🔑 Private key: %s\n", keyPath)
+		fmt.Printf("🔑 Public key:  %s\n", pubPath)
+		fmt.Printf("\nAdd to the supervisor config:\n  \"upgrade_public_keys\": [\"%s\"]\n", publicKey)
+	},
+}
+
+var upgradeBundleCmd = &cobra.Command{
+	Use:   "bundle <output.tar.gz>",
+	Short: "Build and sign an upgrade bundle",
+	Long: `Package new component binaries into a bundle signed with a key from
+'upgrade keygen'. Only the components given are upgraded.
+
+Examples:
+  tmidb-cli upgrade bundle tmidb-1.4.0.tar.gz --key release.key --version 1.4.0 \
+    --api ./bin/api --data-manager ./bin/data-manager --data-consumer ./bin/data-consumer`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		keyPath, _ := cmd.Flags().GetString("key")
+		version, _ := cmd.Flags().GetString("version")
+		if keyPath == "" || version == "" {
+			fmt.Println("❌ --key and --version are required")
+			exit(1)
+		}
+
+		binaries := map[string]string{}
+		for _, component := range []string{"api", "data-manager", "data-consumer"} {
+			if path, _ := cmd.Flags().GetString(component); path != "" {
+				binaries[component] = path
+			}
+		}
+		if len(binaries) == 0 {
+			fmt.Println("❌ Specify at least one of --api, --data-manager, --data-consumer")
+			exit(1)
+		}
+
+		keyData, err := os.ReadFile(keyPath)
+		if err != nil {
+			fmt.Printf("❌ Failed to read key: %v\n", err)
+			exit(1)
+		}
+		key, err := upgrade.ParsePrivateKey(string(keyData))
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+
+		output := args[0]
+		file, err := os.Create(output)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+		manifest, err := upgrade.Create(file, version, binaries, key)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(output)
+			fmt.Printf("❌ Failed to create bundle: %v\n", err)
+			exit(1)
+		}
+
+		fmt.Printf("📦 Bundle %s (version %s)\n", output, manifest.Version)
+		for _, f := range manifest.Files {
+			fmt.Printf("   %-15s %10s  sha256:%s\n", f.Component, formatBytes(f.Size), f.SHA256[:16])
+		}
+	},
+}
+
+// upgradeProgress 슈퍼바이저의 UpgradeProgress
+type upgradeProgress struct {
+	ID        string        `json:"id"`
+	Version   string        `json:"version"`
+	Bundle    string        `json:"bundle"`
+	Status    string        `json:"status"`
+	Steps     []upgradeStep `json:"steps"`
+	StartTime time.Time     `json:"start_time"`
+	EndTime   *time.Time    `json:"end_time,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+type upgradeStep struct {
+	Component string `json:"component"`
+	Status    string `json:"status"`
+	Message   string `json:"message,omitempty"`
+}
+
+// monitorUpgrade 업그레이드 진행 상황 모니터링 (단계가 바뀔 때마다 출력)
+func monitorUpgrade(id string) error {
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	reported := make(map[string]string)
+	for range ticker.C {
+		resp, err := client.SendMessage(ipc.MessageTypeUpgradeStatus, map[string]interface{}{
+			"id": id,
+		})
+		if err != nil {
+			return err
+		}
+		if !resp.Success {
+			return fmt.Errorf("%s", resp.Error)
+		}
+
+		var progress upgradeProgress
+		raw, _ := json.Marshal(resp.Data)
+		if err := json.Unmarshal(raw, &progress); err != nil {
+			continue
+		}
+
+		for _, step := range progress.Steps {
+			if step.Status == "pending" || reported[step.Component] == step.Status {
+				continue
+			}
+			reported[step.Component] = step.Status
+			printUpgradeStep(step)
+		}
+
+		switch progress.Status {
+		case "completed":
+			fmt.Printf("\n✅ Upgrade to %s completed\n", progress.Version)
+			return nil
+		case "rolled_back":
+			return fmt.Errorf("upgrade rolled back: %s", progress.Error)
+		case "failed":
+			return fmt.Errorf("upgrade failed: %s", progress.Error)
+		}
+	}
+	return nil
+}
+
+func printUpgradeStep(step upgradeStep) {
+	icon := map[string]string{
+		"pending":     "⏸️",
+		"installing":  "📦",
+		"restarting":  "🔄",
+		"waiting":     "⏳",
+		"healthy":     "✅",
+		"failed":      "❌",
+		"skipped":     "⏭️",
+		"rolled_back": "↩️",
+	}[step.Status]
+	fmt.Printf("  %s %-15s %s", icon, step.Component, step.Status)
+	if step.Message != "" {
+		fmt.Printf(" - %s", step.Message)
+	}
+	fmt.Println()
+}
+
+func init() {
+	upgradeApplyCmd.Flags().Duration("timeout", 60*time.Second, "How long to wait for each component to become healthy")
+	upgradeApplyCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+
+	upgradeBundleCmd.Flags().String("key", "", "Private key file from 'upgrade keygen'")
+	upgradeBundleCmd.Flags().String("version", "", "Version recorded in the bundle")
+	upgradeBundleCmd.Flags().String("api", "", "New api binary")
+	upgradeBundleCmd.Flags().String("data-manager", "", "New data-manager binary")
+	upgradeBundleCmd.Flags().String("data-consumer", "", "New data-consumer binary")
+
+	upgradeCmd.AddCommand(upgradeApplyCmd)
+	upgradeCmd.AddCommand(upgradeStatusCmd)
+	upgradeCmd.AddCommand(upgradeKeygenCmd)
+	upgradeCmd.AddCommand(upgradeBundleCmd)
+	rootCmd.AddCommand(upgradeCmd)
+}
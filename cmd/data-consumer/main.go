@@ -11,17 +11,23 @@ import (
 	"github.com/tmidb/tmidb-core/internal/config"
 	"github.com/tmidb/tmidb-core/internal/database"
 	"github.com/tmidb/tmidb-core/internal/dataconsumer"
+	"github.com/tmidb/tmidb-core/internal/profiling"
 )
 
 func main() {
 	log.Println("🚀 Starting tmiDB Data Consumer...")
 
 	// 설정 로드
-	cfg, err := config.Load()
+	cfg, err := config.LoadArgs(os.Args[1:])
 	if err != nil {
 		log.Fatalf("❌ Failed to load config: %v", err)
 	}
 
+	// 프로파일링 엔드포인트 (슈퍼바이저가 TMIDB_PPROF_ADDR를 넘긴 경우에만 열림)
+	if pprofServer := profiling.StartFromEnv("data-consumer"); pprofServer != nil {
+		defer pprofServer.Stop()
+	}
+
 	// 데이터베이스 연결 (초기화 없이 연결만) - 수정됨 2025-07-01
 	log.Println("🔄 Data Consumer: Using ConnectDatabase (not InitDatabase)")
 	if err := database.ConnectDatabase(cfg); err != nil {
@@ -33,9 +39,17 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// SLOW_QUERY_MS보다 오래 걸린 쿼리 기록
+	database.StartSlowQueryLog(ctx, database.GetDB(), cfg.SlowQueryThreshold, cfg.SlowQueryParams, "data-consumer")
+
+	// 같은 event_id나 target_id+ts+payload는 INGEST_DEDUP_WINDOW_HOURS 동안 한 번만 저장
+	database.StartIngestDedup(ctx, database.GetDB(), cfg.IngestDedupWindow)
+
 	// 시그널 핸들링
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	// 슈퍼바이저가 재시작되는 동안 stdout/stderr 파이프가 닫혀도 종료되지 않도록 (재시작한 슈퍼바이저가 이어서 관리)
+	signal.Ignore(syscall.SIGPIPE)
 
 	// Data Consumer 인스턴스 생성
 	dc := dataconsumer.New()
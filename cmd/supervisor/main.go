@@ -1,18 +1,45 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/tmidb/tmidb-core/internal/supervisor"
 )
 
 func main() {
+	// PostgreSQL runs these from archive_command and restore_command
+	if len(os.Args) > 1 && (os.Args[1] == "wal-push" || os.Args[1] == "wal-fetch") {
+		if err := supervisor.RunWALCommand(os.Args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "tmidb-supervisor %s: %v\n", os.Args[1], err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Container HEALTHCHECK and init systems probe a running supervisor with this
+	if len(os.Args) > 1 && os.Args[1] == "healthcheck" {
+		os.Exit(healthcheck(os.Args[2:]))
+	}
+
 	log.Println("🚀 Starting tmiDB Supervisor...")
 
 	// Create supervisor with default config
 	config := supervisor.DefaultConfig()
 
+	// Load persisted configuration, then let environment variables override it
+	if configPath := os.Getenv("TMIDB_CONFIG_PATH"); configPath != "" {
+		config.ConfigPath = configPath
+	}
+	if err := supervisor.LoadConfigFile(config.ConfigPath, config); err != nil {
+		log.Fatalf("❌ Failed to load config: %v", err)
+	}
+
 	// Override with environment variables if set
 	if socketPath := os.Getenv("TMIDB_SOCKET_PATH"); socketPath != "" {
 		config.SocketPath = socketPath
@@ -23,6 +50,38 @@ func main() {
 	if logLevel := os.Getenv("TMIDB_LOG_LEVEL"); logLevel != "" {
 		config.LogLevel = logLevel
 	}
+	if metricsAddr, ok := os.LookupEnv("TMIDB_METRICS_ADDR"); ok {
+		config.MetricsAddr = metricsAddr
+	}
+	if tcpAddr, ok := os.LookupEnv("TMIDB_IPC_TCP_ADDR"); ok {
+		config.IPCTCPAddr = tcpAddr
+	}
+	if grpcAddr, ok := os.LookupEnv("TMIDB_GRPC_ADDR"); ok {
+		config.GRPCAddr = grpcAddr
+	}
+	if node := os.Getenv("TMIDB_CLUSTER_NODE"); node != "" {
+		config.ClusterNode = node
+	}
+	if peers := os.Getenv("TMIDB_CLUSTER_PEERS"); peers != "" {
+		config.ClusterPeers = strings.Split(peers, ",")
+	}
+	if natsURL, ok := os.LookupEnv("TMIDB_CLUSTER_NATS_URL"); ok {
+		config.ClusterNATSURL = natsURL
+	}
+	if healthSocket, ok := os.LookupEnv("TMIDB_HEALTH_SOCKET"); ok {
+		config.HealthSocket = healthSocket
+	}
+	if stateFile := os.Getenv("TMIDB_STATE_FILE"); stateFile != "" {
+		config.StateFile = stateFile
+	}
+	if os.Getenv("TMIDB_WATCHDOG_EXIT") == "true" {
+		config.WatchdogExit = true
+	}
+	if tlsDir := os.Getenv("TMIDB_IPC_TLS_DIR"); tlsDir != "" {
+		config.IPCTLSCert = filepath.Join(tlsDir, "server.crt")
+		config.IPCTLSKey = filepath.Join(tlsDir, "server.key")
+		config.IPCTLSCA = filepath.Join(tlsDir, "ca.crt")
+	}
 
 	// Create and run supervisor
 	sup, err := supervisor.New(config)
@@ -37,3 +96,27 @@ func main() {
 
 	log.Println("✅ tmiDB Supervisor stopped")
 }
+
+// healthcheck prints the state from the health socket and returns 0 only when
+// the supervisor is up and not wedged
+func healthcheck(args []string) int {
+	path := supervisor.DefaultConfig().HealthSocket
+	if env := os.Getenv("TMIDB_HEALTH_SOCKET"); env != "" {
+		path = env
+	}
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	status, err := supervisor.CheckHealth(path, 3*time.Second)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tmidb-supervisor healthcheck: %v\n", err)
+		return 1
+	}
+	out, _ := json.Marshal(status)
+	fmt.Println(string(out))
+	if status.Status != "ok" {
+		return 1
+	}
+	return 0
+}
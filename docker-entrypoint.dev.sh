@@ -12,13 +12,13 @@ echo "PostgreSQL started with PID $POSTGRES_PID"
 
 # Start NATS in background
 echo "🚀 Starting NATS..."
-runuser -u natsuser -- nats-server -sd /data/nats &
+runuser -u natsuser -- nats-server -js -sd /data/nats &
 NATS_PID=$!
 echo "NATS started with PID $NATS_PID"
 
-# Start SeaweedFS in background
+# Start SeaweedFS (master + volume + filer) in background
 echo "🚀 Starting SeaweedFS..."
-runuser -u seaweeduser -- weed master -mdir=/data/seaweedfs/master &
+runuser -u seaweeduser -- weed server -dir=/data/seaweedfs -master.dir=/data/seaweedfs/master -filer &
 SEAWEED_PID=$!
 echo "SeaweedFS started with PID $SEAWEED_PID"
 
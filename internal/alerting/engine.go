@@ -0,0 +1,352 @@
+package alerting
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ComponentStats 컴포넌트 수집값
+type ComponentStats struct {
+	Running bool
+	CPU     float64
+	Memory  int64
+}
+
+// Snapshot 평가 시점의 호스트/컴포넌트 수집값
+type Snapshot struct {
+	CPU        float64
+	Memory     float64
+	Disk       float64
+	Components map[string]ComponentStats
+}
+
+// Status 알림 상태
+type Status string
+
+const (
+	StatusPending  Status = "pending"  // 조건 충족, For 대기 중
+	StatusFiring   Status = "firing"   // 알림 발생
+	StatusResolved Status = "resolved" // 조건 해소
+)
+
+// Notification 채널로 전송되는 알림
+type Notification struct {
+	Rule      string    `json:"rule"`
+	Severity  Severity  `json:"severity"`
+	Status    Status    `json:"status"`
+	Component string    `json:"component,omitempty"`
+	Value     float64   `json:"value"`
+	Condition string    `json:"condition"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+	Channels  []string  `json:"-"` // 비어 있으면 모든 채널
+}
+
+// Title 알림 한 줄 요약
+func (n Notification) Title() string {
+	subject := n.Rule
+	if n.Component != "" {
+		subject += " (" + n.Component + ")"
+	}
+	return fmt.Sprintf("[tmiDB %s] %s %s", strings.ToUpper(string(n.Severity)), subject, n.Status)
+}
+
+// Text 알림 본문
+func (n Notification) Text() string {
+	return fmt.Sprintf("%s\n%s\nCondition: %s\nTime: %s",
+		n.Title(), n.Message, n.Condition, n.Timestamp.Format(time.RFC3339))
+}
+
+// Alert 현재 활성 알림
+type Alert struct {
+	Rule         string     `json:"rule"`
+	Component    string     `json:"component,omitempty"`
+	Severity     Severity   `json:"severity"`
+	Status       Status     `json:"status"`
+	Value        float64    `json:"value"`
+	Message      string     `json:"message"`
+	Since        time.Time  `json:"since"`
+	FiredAt      *time.Time `json:"fired_at,omitempty"`
+	LastNotified time.Time  `json:"last_notified,omitempty"`
+}
+
+// Engine 규칙 평가 및 알림 중복 제거
+type Engine struct {
+	mu     sync.Mutex
+	rules  []Rule
+	alerts map[string]*Alert // rule/component
+}
+
+// NewEngine 새 규칙 엔진 생성
+func NewEngine() *Engine {
+	return &Engine{alerts: make(map[string]*Alert)}
+}
+
+// SetRules 규칙 교체. 변경되거나 삭제된 규칙의 상태는 초기화된다.
+func (e *Engine) SetRules(rules []Rule) error {
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	previous := make(map[string]Rule, len(e.rules))
+	for _, rule := range e.rules {
+		previous[rule.Name] = rule
+	}
+
+	e.rules = make([]Rule, len(rules))
+	keep := make(map[string]bool, len(rules))
+	for i, rule := range rules {
+		e.rules[i] = rule.withDefaults()
+		if old, ok := previous[rule.Name]; ok && old.Describe() == e.rules[i].Describe() {
+			keep[rule.Name] = true
+		}
+	}
+
+	for key, alert := range e.alerts {
+		if !keep[alert.Rule] {
+			delete(e.alerts, key)
+		}
+	}
+	return nil
+}
+
+// Rules 현재 규칙 목록
+func (e *Engine) Rules() []Rule {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Rule(nil), e.rules...)
+}
+
+// Alerts 활성 알림 목록 (규칙, 컴포넌트 순)
+func (e *Engine) Alerts() []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	alerts := make([]Alert, 0, len(e.alerts))
+	for _, alert := range e.alerts {
+		alerts = append(alerts, *alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Rule != alerts[j].Rule {
+			return alerts[i].Rule < alerts[j].Rule
+		}
+		return alerts[i].Component < alerts[j].Component
+	})
+	return alerts
+}
+
+// Evaluate 임계치 규칙을 평가하고 보낼 알림을 반환
+func (e *Engine) Evaluate(now time.Time, snap Snapshot) []Notification {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var notifications []Notification
+	seen := make(map[string]bool)
+
+	for _, rule := range e.rules {
+		if rule.Disabled || rule.Metric == MetricEvent {
+			continue
+		}
+
+		for component, value := range ruleValues(rule, snap) {
+			key := rule.Name + "/" + component
+			seen[key] = true
+
+			matched := value >= 1
+			if rule.Metric != MetricDown {
+				matched, _ = compare(rule.Operator, value, rule.Threshold)
+			}
+
+			alert := e.alerts[key]
+			if !matched {
+				if alert != nil && alert.Status == StatusFiring {
+					alert.Value = value
+					alert.Status = StatusResolved
+					notifications = append(notifications, notify(rule, alert, now, "condition cleared"))
+				}
+				delete(e.alerts, key)
+				continue
+			}
+
+			if alert == nil {
+				alert = &Alert{Rule: rule.Name, Component: component, Severity: rule.Severity, Status: StatusPending, Since: now}
+				e.alerts[key] = alert
+			}
+			alert.Value = value
+			alert.Message = conditionMessage(rule, component, value)
+
+			switch {
+			case alert.Status == StatusPending && now.Sub(alert.Since) >= rule.For:
+				fired := now
+				alert.Status = StatusFiring
+				alert.FiredAt = &fired
+				alert.LastNotified = now
+				notifications = append(notifications, notify(rule, alert, now, alert.Message))
+			case alert.Status == StatusFiring && now.Sub(alert.LastNotified) >= rule.Repeat:
+				// 계속 발생 중인 알림은 Repeat 간격으로만 재전송
+				alert.LastNotified = now
+				notifications = append(notifications, notify(rule, alert, now, alert.Message))
+			}
+		}
+	}
+
+	// 사라진 컴포넌트의 상태와 만료된 이벤트 알림 정리
+	for key, alert := range e.alerts {
+		rule, ok := e.findRule(alert.Rule)
+		if !ok {
+			delete(e.alerts, key)
+			continue
+		}
+		if rule.Metric == MetricEvent {
+			if now.Sub(alert.LastNotified) >= rule.Repeat {
+				delete(e.alerts, key)
+			}
+		} else if !seen[key] {
+			delete(e.alerts, key)
+		}
+	}
+
+	return notifications
+}
+
+// HandleEvent 이벤트 규칙과 대조하여 보낼 알림을 반환
+func (e *Engine) HandleEvent(now time.Time, eventType, component, message string) []Notification {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var notifications []Notification
+	for _, rule := range e.rules {
+		if rule.Disabled || rule.Metric != MetricEvent || !matchEvent(rule.Event, eventType) {
+			continue
+		}
+		if rule.Component != "" && rule.Component != component {
+			continue
+		}
+
+		key := rule.Name + "/" + component
+		alert := e.alerts[key]
+		if alert != nil && now.Sub(alert.LastNotified) < rule.Repeat {
+			// 중복 이벤트
+			continue
+		}
+
+		if message == "" {
+			message = eventType
+		}
+		fired := now
+		alert = &Alert{
+			Rule:         rule.Name,
+			Component:    component,
+			Severity:     rule.Severity,
+			Status:       StatusFiring,
+			Value:        1,
+			Message:      message,
+			Since:        now,
+			FiredAt:      &fired,
+			LastNotified: now,
+		}
+		e.alerts[key] = alert
+		notifications = append(notifications, notify(rule, alert, now, message))
+	}
+	return notifications
+}
+
+func (e *Engine) findRule(name string) (Rule, bool) {
+	for _, rule := range e.rules {
+		if rule.Name == name {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// ruleValues 규칙이 평가할 값 (컴포넌트 메트릭이 아니면 키는 "")
+func ruleValues(rule Rule, snap Snapshot) map[string]float64 {
+	values := make(map[string]float64)
+
+	if !rule.perComponent() {
+		switch rule.Metric {
+		case MetricCPU:
+			values[""] = snap.CPU
+		case MetricMemory:
+			values[""] = snap.Memory
+		case MetricDisk:
+			values[""] = snap.Disk
+		}
+		return values
+	}
+
+	componentValue := func(stats ComponentStats) float64 {
+		switch rule.Metric {
+		case MetricProcessCPU:
+			return stats.CPU
+		case MetricProcessMemory:
+			return float64(stats.Memory)
+		}
+		if stats.Running {
+			return 0
+		}
+		return 1
+	}
+
+	if rule.Component != "" {
+		stats, ok := snap.Components[rule.Component]
+		if ok {
+			values[rule.Component] = componentValue(stats)
+		} else if rule.Metric == MetricDown {
+			// 목록에 없는 컴포넌트는 실행 중이 아닌 것으로 간주
+			values[rule.Component] = 1
+		}
+		return values
+	}
+
+	for name, stats := range snap.Components {
+		values[name] = componentValue(stats)
+	}
+	return values
+}
+
+// matchEvent "backup.*" 형식 접두사 지원
+func matchEvent(pattern, eventType string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(eventType, prefix)
+	}
+	return pattern == eventType
+}
+
+func conditionMessage(rule Rule, component string, value float64) string {
+	subject := string(rule.Metric)
+	if component != "" {
+		subject = component + " " + subject
+	}
+
+	switch rule.Metric {
+	case MetricDown:
+		return fmt.Sprintf("%s is not running", component)
+	case MetricProcessMemory:
+		return fmt.Sprintf("%s is %.0f bytes (threshold %s %g)", subject, value, rule.Operator, rule.Threshold)
+	}
+	return fmt.Sprintf("%s is %.1f%% (threshold %s %g)", subject, value, rule.Operator, rule.Threshold)
+}
+
+func notify(rule Rule, alert *Alert, now time.Time, message string) Notification {
+	return Notification{
+		Rule:      rule.Name,
+		Severity:  rule.Severity,
+		Status:    alert.Status,
+		Component: alert.Component,
+		Value:     alert.Value,
+		Condition: rule.Describe(),
+		Message:   message,
+		Timestamp: now,
+		Channels:  rule.Channels,
+	}
+}
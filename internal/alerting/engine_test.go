@@ -0,0 +1,97 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluateFiresAfterForAndResolves(t *testing.T) {
+	e := NewEngine()
+	if err := e.SetRules([]Rule{{Name: "high-cpu", Metric: MetricCPU, Operator: ">", Threshold: 90, For: 5 * time.Minute, Repeat: time.Hour}}); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if n := e.Evaluate(start, Snapshot{CPU: 95}); len(n) != 0 {
+		t.Fatalf("expected pending alert without notification, got %d", len(n))
+	}
+	if n := e.Evaluate(start.Add(4*time.Minute), Snapshot{CPU: 95}); len(n) != 0 {
+		t.Fatalf("fired before For elapsed")
+	}
+
+	n := e.Evaluate(start.Add(5*time.Minute), Snapshot{CPU: 96})
+	if len(n) != 1 || n[0].Status != StatusFiring {
+		t.Fatalf("expected one firing notification, got %+v", n)
+	}
+
+	// 반복 간격 이전에는 중복 전송하지 않음
+	if n := e.Evaluate(start.Add(30*time.Minute), Snapshot{CPU: 96}); len(n) != 0 {
+		t.Fatalf("expected deduplicated notification, got %d", len(n))
+	}
+	if n := e.Evaluate(start.Add(65*time.Minute), Snapshot{CPU: 96}); len(n) != 1 {
+		t.Fatalf("expected repeat notification after interval, got %d", len(n))
+	}
+
+	n = e.Evaluate(start.Add(66*time.Minute), Snapshot{CPU: 10})
+	if len(n) != 1 || n[0].Status != StatusResolved {
+		t.Fatalf("expected resolved notification, got %+v", n)
+	}
+	if len(e.Alerts()) != 0 {
+		t.Fatalf("expected no active alerts after resolve")
+	}
+}
+
+func TestEvaluatePendingClearsWithoutNotification(t *testing.T) {
+	e := NewEngine()
+	e.SetRules([]Rule{{Name: "down", Metric: MetricDown, For: time.Minute}})
+
+	now := time.Now()
+	snap := Snapshot{Components: map[string]ComponentStats{"api": {Running: false}, "data-manager": {Running: true}}}
+	e.Evaluate(now, snap)
+	if alerts := e.Alerts(); len(alerts) != 1 || alerts[0].Component != "api" {
+		t.Fatalf("expected pending alert for api, got %+v", alerts)
+	}
+
+	snap.Components["api"] = ComponentStats{Running: true}
+	if n := e.Evaluate(now.Add(2*time.Minute), snap); len(n) != 0 {
+		t.Fatalf("pending alert must not send resolved notification, got %+v", n)
+	}
+}
+
+func TestHandleEventDeduplicates(t *testing.T) {
+	e := NewEngine()
+	e.SetRules([]Rule{{Name: "backup-failed", Metric: MetricEvent, Event: "backup.*", Repeat: 10 * time.Minute}})
+
+	now := time.Now()
+	if n := e.HandleEvent(now, "backup.failed", "backup", "disk full"); len(n) != 1 {
+		t.Fatalf("expected notification, got %d", len(n))
+	}
+	if n := e.HandleEvent(now.Add(time.Minute), "backup.failed", "backup", "disk full"); len(n) != 0 {
+		t.Fatalf("expected duplicate event to be dropped")
+	}
+	if n := e.HandleEvent(now.Add(11*time.Minute), "backup.failed", "backup", "disk full"); len(n) != 1 {
+		t.Fatalf("expected notification after repeat interval")
+	}
+	if n := e.HandleEvent(now, "process.started", "api", ""); len(n) != 0 {
+		t.Fatalf("unmatched event must not notify")
+	}
+}
+
+func TestRuleValidate(t *testing.T) {
+	cases := []struct {
+		rule Rule
+		ok   bool
+	}{
+		{Rule{Name: "cpu", Metric: MetricCPU, Operator: ">", Threshold: 90}, true},
+		{Rule{Name: "cpu", Metric: MetricCPU, Operator: "=>"}, false},
+		{Rule{Name: "evt", Metric: MetricEvent}, false},
+		{Rule{Name: "x", Metric: "load"}, false},
+		{Rule{Metric: MetricDown}, false},
+		{Rule{Name: "down", Metric: MetricDown, Severity: "fatal"}, false},
+	}
+	for _, c := range cases {
+		if err := c.rule.Validate(); (err == nil) != c.ok {
+			t.Errorf("Validate(%+v) = %v, want ok=%v", c.rule, err, c.ok)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/smtp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChannelType 알림 채널 종류
+type ChannelType string
+
+const (
+	ChannelWebhook ChannelType = "webhook" // 알림 JSON을 POST
+	ChannelSlack   ChannelType = "slack"   // Slack 호환 incoming webhook ({"text": ...})
+	ChannelEmail   ChannelType = "email"   // SMTP
+)
+
+// sendTimeout 채널 하나로 알림을 보내는 최대 시간
+const sendTimeout = 10 * time.Second
+
+// RedactedPassword 목록 조회 시 비밀번호 대신 표시되는 값
+const RedactedPassword = "********"
+
+// Channel 알림 채널 설정
+type Channel struct {
+	Name     string      `json:"name"`
+	Type     ChannelType `json:"type"`
+	URL      string      `json:"url,omitempty"` // webhook, slack
+	SMTPHost string      `json:"smtp_host,omitempty"`
+	SMTPPort int         `json:"smtp_port,omitempty"`
+	Username string      `json:"username,omitempty"`
+	Password string      `json:"password,omitempty"`
+	From     string      `json:"from,omitempty"`
+	To       []string    `json:"to,omitempty"`
+}
+
+// Validate 채널 설정 검증
+func (c Channel) Validate() error {
+	if c.Name == "" {
+		return errors.New("alert channel requires a name")
+	}
+
+	switch c.Type {
+	case ChannelWebhook, ChannelSlack:
+		if !strings.HasPrefix(c.URL, "http://") && !strings.HasPrefix(c.URL, "https://") {
+			return fmt.Errorf("%s channel requires an http(s) url", c.Type)
+		}
+	case ChannelEmail:
+		if c.SMTPHost == "" || c.From == "" || len(c.To) == 0 {
+			return errors.New("email channel requires smtp_host, from and to")
+		}
+	default:
+		return fmt.Errorf("unknown alert channel type: %q", c.Type)
+	}
+	return nil
+}
+
+// Redacted 비밀번호를 가린 복사본
+func (c Channel) Redacted() Channel {
+	if c.Password != "" {
+		c.Password = RedactedPassword
+	}
+	return c
+}
+
+// Dispatcher 알림을 채널로 비동기 전송
+type Dispatcher struct {
+	mu       sync.RWMutex
+	channels []Channel
+
+	client   *http.Client
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewDispatcher 새 디스패처 생성
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		client:   &http.Client{Timeout: sendTimeout},
+		sendMail: smtp.SendMail,
+	}
+}
+
+// SetChannels 채널 교체
+func (d *Dispatcher) SetChannels(channels []Channel) error {
+	for _, channel := range channels {
+		if err := channel.Validate(); err != nil {
+			return fmt.Errorf("channel %q: %w", channel.Name, err)
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.channels = append([]Channel(nil), channels...)
+	return nil
+}
+
+// Channel 이름으로 채널 조회
+func (d *Dispatcher) Channel(name string) (Channel, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, channel := range d.channels {
+		if channel.Name == name {
+			return channel, true
+		}
+	}
+	return Channel{}, false
+}
+
+// Dispatch 각 알림을 대상 채널로 백그라운드 전송 (실패는 로그만 남김)
+func (d *Dispatcher) Dispatch(notifications []Notification) {
+	if len(notifications) == 0 {
+		return
+	}
+
+	d.mu.RLock()
+	channels := append([]Channel(nil), d.channels...)
+	d.mu.RUnlock()
+
+	for _, n := range notifications {
+		log.Printf("🚨 Alert %s: %s", n.Status, n.Title())
+
+		for _, channel := range channels {
+			if len(n.Channels) > 0 && !slices.Contains(n.Channels, channel.Name) {
+				continue
+			}
+
+			go func(channel Channel, n Notification) {
+				ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+				defer cancel()
+				if err := d.Send(ctx, channel, n); err != nil {
+					log.Printf("⚠️ Failed to send alert %s to channel %s: %v", n.Rule, channel.Name, err)
+				}
+			}(channel, n)
+		}
+	}
+}
+
+// Send 알림 하나를 채널로 전송
+func (d *Dispatcher) Send(ctx context.Context, channel Channel, n Notification) error {
+	switch channel.Type {
+	case ChannelWebhook:
+		return d.post(ctx, channel.URL, n)
+	case ChannelSlack:
+		return d.post(ctx, channel.URL, map[string]string{"text": n.Text()})
+	case ChannelEmail:
+		return d.email(channel, n)
+	}
+	return fmt.Errorf("unknown alert channel type: %q", channel.Type)
+}
+
+func (d *Dispatcher) post(ctx context.Context, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return nil
+}
+
+func (d *Dispatcher) email(channel Channel, n Notification) error {
+	port := channel.SMTPPort
+	if port == 0 {
+		port = 25
+	}
+	addr := net.JoinHostPort(channel.SMTPHost, strconv.Itoa(port))
+
+	var auth smtp.Auth
+	if channel.Username != "" {
+		auth = smtp.PlainAuth("", channel.Username, channel.Password, channel.SMTPHost)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", channel.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(channel.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", n.Title())
+	fmt.Fprintf(&msg, "Date: %s\r\n", n.Timestamp.Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(n.Text(), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+
+	return d.sendMail(addr, auth, channel.From, channel.To, []byte(msg.String()))
+}
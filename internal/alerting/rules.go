@@ -0,0 +1,185 @@
+package alerting
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Metric 규칙이 감시하는 값
+type Metric string
+
+const (
+	MetricCPU           Metric = "cpu"            // 호스트 CPU 사용률 (%)
+	MetricMemory        Metric = "memory"         // 호스트 메모리 사용률 (%)
+	MetricDisk          Metric = "disk"           // 데이터 디렉터리 디스크 사용률 (%)
+	MetricProcessCPU    Metric = "process_cpu"    // 컴포넌트 CPU 사용률 (%)
+	MetricProcessMemory Metric = "process_memory" // 컴포넌트 메모리 사용량 (bytes)
+	MetricDown          Metric = "down"           // 컴포넌트가 실행 중이 아니면 1
+	MetricEvent         Metric = "event"          // 슈퍼바이저 이벤트 발생 (예: backup.failed)
+)
+
+// Severity 알림 심각도
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// DefaultRepeatInterval 같은 알림을 다시 보내기까지의 기본 간격
+const DefaultRepeatInterval = time.Hour
+
+// Rule 사용자 정의 알림 규칙
+//
+// 임계치 규칙은 조건이 For 동안 유지되면 발생(firing)하고, 조건이 해소되면
+// resolved 알림을 보낸다. 이벤트 규칙은 이벤트마다 발생하되 Repeat 간격 안의
+// 같은 컴포넌트 알림은 중복으로 보고 버린다.
+type Rule struct {
+	Name      string        `json:"name"`
+	Metric    Metric        `json:"metric"`
+	Component string        `json:"component,omitempty"` // 비어 있으면 모든 컴포넌트 (컴포넌트 메트릭)
+	Operator  string        `json:"operator,omitempty"`  // ">", ">=", "<", "<=", "==", "!="
+	Threshold float64       `json:"threshold,omitempty"`
+	Event     string        `json:"event,omitempty"` // event 메트릭: 이벤트 타입 ("backup.*" 접두사 지원)
+	For       time.Duration `json:"for"`
+	Repeat    time.Duration `json:"repeat"`
+	Severity  Severity      `json:"severity"`
+	Channels  []string      `json:"channels,omitempty"` // 비어 있으면 모든 채널
+	Disabled  bool          `json:"disabled,omitempty"`
+}
+
+// ruleJSON for/repeat를 "5m" 형식 문자열로 직렬화
+type ruleJSON struct {
+	*ruleAlias
+	For    string `json:"for,omitempty"`
+	Repeat string `json:"repeat,omitempty"`
+}
+
+type ruleAlias Rule
+
+// MarshalJSON encodes durations as strings
+func (r Rule) MarshalJSON() ([]byte, error) {
+	aux := ruleJSON{ruleAlias: (*ruleAlias)(&r)}
+	if r.For > 0 {
+		aux.For = r.For.String()
+	}
+	if r.Repeat > 0 {
+		aux.Repeat = r.Repeat.String()
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON decodes durations from strings
+func (r *Rule) UnmarshalJSON(data []byte) error {
+	aux := ruleJSON{ruleAlias: (*ruleAlias)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var err error
+	if aux.For != "" {
+		if r.For, err = time.ParseDuration(aux.For); err != nil {
+			return fmt.Errorf("invalid alert rule duration: %w", err)
+		}
+	}
+	if aux.Repeat != "" {
+		if r.Repeat, err = time.ParseDuration(aux.Repeat); err != nil {
+			return fmt.Errorf("invalid alert rule repeat interval: %w", err)
+		}
+	}
+	return nil
+}
+
+// Validate 규칙 검증
+func (r Rule) Validate() error {
+	if r.Name == "" {
+		return errors.New("alert rule requires a name")
+	}
+
+	switch r.Metric {
+	case MetricCPU, MetricMemory, MetricDisk, MetricProcessCPU, MetricProcessMemory:
+		if _, err := compare(r.Operator, 0, 0); err != nil {
+			return err
+		}
+	case MetricDown:
+	case MetricEvent:
+		if r.Event == "" {
+			return errors.New("event alert rule requires an event type")
+		}
+	default:
+		return fmt.Errorf("unknown alert metric: %q", r.Metric)
+	}
+
+	switch r.Severity {
+	case "", SeverityInfo, SeverityWarning, SeverityCritical:
+	default:
+		return fmt.Errorf("unknown alert severity: %q", r.Severity)
+	}
+
+	if r.For < 0 || r.Repeat < 0 {
+		return errors.New("alert rule durations must not be negative")
+	}
+	return nil
+}
+
+// withDefaults 비어 있는 값에 기본값 적용
+func (r Rule) withDefaults() Rule {
+	if r.Severity == "" {
+		r.Severity = SeverityWarning
+	}
+	if r.Repeat <= 0 {
+		r.Repeat = DefaultRepeatInterval
+	}
+	return r
+}
+
+// perComponent 컴포넌트별로 평가하는 메트릭인지 확인
+func (r Rule) perComponent() bool {
+	switch r.Metric {
+	case MetricProcessCPU, MetricProcessMemory, MetricDown, MetricEvent:
+		return true
+	}
+	return false
+}
+
+// Describe 규칙 조건을 사람이 읽을 수 있는 형태로 표현
+func (r Rule) Describe() string {
+	var cond string
+	switch r.Metric {
+	case MetricDown:
+		cond = "component down"
+	case MetricEvent:
+		cond = "event " + r.Event
+	default:
+		cond = fmt.Sprintf("%s %s %g", r.Metric, r.Operator, r.Threshold)
+	}
+	if r.Component != "" {
+		cond = r.Component + ": " + cond
+	}
+	if r.For > 0 {
+		cond += " for " + r.For.String()
+	}
+	return cond
+}
+
+// compare 연산자 적용
+func compare(operator string, value, threshold float64) (bool, error) {
+	switch operator {
+	case ">":
+		return value > threshold, nil
+	case ">=":
+		return value >= threshold, nil
+	case "<":
+		return value < threshold, nil
+	case "<=":
+		return value <= threshold, nil
+	case "==":
+		return value == threshold, nil
+	case "!=":
+		return value != threshold, nil
+	}
+	return false, fmt.Errorf("unknown alert operator: %q", operator)
+}
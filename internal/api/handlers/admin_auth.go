@@ -3,6 +3,7 @@ package handlers
 import (
 	"log"
 
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
 	"github.com/tmidb/tmidb-core/internal/database"
 
 	"github.com/gofiber/fiber/v2"
@@ -27,8 +28,11 @@ func LoginPage(c *fiber.Ctx) error {
 	}
 
 	return c.Render("login.html", fiber.Map{
-		"Title": "Login",
-		"error": errMsg,
+		"Title":       "Login",
+		"error":       errMsg,
+		"sso_enabled": sso.enabled,
+		"sso_name":    sso.name,
+		"csrf_token":  c.Locals(middleware.CSRFContextKey),
 	})
 }
 
@@ -57,15 +61,10 @@ func LoginProcess(c *fiber.Ctx) error {
 		return c.Redirect("/login")
 	}
 
-	// 세션에 사용자 정보 저장
-	sess.Set("user_id", userID)
-	sess.Set("org_id", orgID)
-	sess.Set("username", req.Username)
-	sess.Set("role", role)
-	sess.Set("authenticated", true)
-
-	if err := sess.Save(); err != nil {
+	// 세션에 사용자 정보 저장 (새 세션 ID와 리프레시 토큰 발급)
+	if err := middleware.StartUserSession(c, sess, userID, orgID, req.Username, role); err != nil {
 		log.Printf("Failed to save session: %v", err)
+		sess, _ = store.Get(c)
 		sess.Set("error_flash", "Failed to save session.")
 		sess.Save()
 		return c.Redirect("/login")
@@ -81,6 +80,8 @@ func Logout(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Redirect("/login")
 	}
-	sess.Destroy()
+	if err := middleware.EndUserSession(c, sess); err != nil {
+		log.Printf("Failed to destroy session: %v", err)
+	}
 	return c.Redirect("/login")
 }
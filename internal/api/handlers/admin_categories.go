@@ -8,6 +8,8 @@ import (
 
 	"github.com/tmidb/tmidb-core/internal/api/middleware"
 	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/migration"
+	"github.com/tmidb/tmidb-core/internal/schema"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -51,10 +53,12 @@ func CreateCategoryAPI(c *fiber.Ctx) error {
 	if err := database.CreateCategory(&category); err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "could not create category"})
 	}
+	publishSchemaChange(orgID, category.CategoryName, schema.ChangeCreate, category.Version)
 	return c.Status(201).JSON(category)
 }
 
 // UpdateCategoryAPI는 현재 조직의 카테고리를 업데이트합니다.
+// 새 버전이 최신 버전과 호환되지 않으면(새 필수 필드, 타입 변경 등) force=true일 때만 발행하고 아니면 409와 변경 목록을 반환합니다.
 func UpdateCategoryAPI(c *fiber.Ctx) error {
 	orgID, err := middleware.GetOrgID(c)
 	if err != nil {
@@ -69,13 +73,49 @@ func UpdateCategoryAPI(c *fiber.Ctx) error {
 	category.OrgID = orgID
 	category.CategoryName = c.Params("name")
 
+	// 최신 버전과 비교해 깨지는 변경이 있으면 발행하지 않음 (force=true면 그대로 발행)
+	if current, err := database.GetCategorySchema(category.CategoryName, orgID); err == nil && !c.QueryBool("force") {
+		if diff, err := schema.CompareDefinitions(current.SchemaDefinition, category.SchemaDefinition); err == nil && !diff.Compatible {
+			return c.Status(409).JSON(fiber.Map{
+				"error":        "schema changes are not backward compatible; pass force=true to publish anyway",
+				"from_version": current.Version,
+				"diff":         diff,
+			})
+		}
+	}
+
 	if err := database.UpdateCategory(&category); err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "could not update category"})
 	}
+	publishSchemaChange(orgID, category.CategoryName, schema.ChangeUpdate, category.Version)
 
 	return c.Status(200).JSON(category)
 }
 
+// DiffCategorySchemaAPI는 카테고리 스키마 두 버전(from, to)을 비교해 변경을 하위 호환/깨지는 변경으로 분류합니다.
+// to가 없으면 최신 버전, from이 없으면 to 바로 앞 버전입니다.
+func DiffCategorySchemaAPI(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+
+	from, err := strconv.Atoi(c.Query("from", "0"))
+	if err != nil || from < 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "from must be a schema version"})
+	}
+	to, err := strconv.Atoi(c.Query("to", "0"))
+	if err != nil || to < 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "to must be a schema version"})
+	}
+
+	diff, err := migration.NewMigrationManager(database.GetDB()).DiffCategorySchemas(orgID, c.Params("name"), from, to)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(diff)
+}
+
 // DeleteCategoryAPI는 현재 조직의 카테고리를 삭제합니다.
 func DeleteCategoryAPI(c *fiber.Ctx) error {
 	orgID, err := middleware.GetOrgID(c)
@@ -87,6 +127,7 @@ func DeleteCategoryAPI(c *fiber.Ctx) error {
 	if err := database.DeleteCategory(categoryName, orgID); err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "could not delete category: " + err.Error()})
 	}
+	publishSchemaChange(orgID, categoryName, schema.ChangeDelete, 0)
 	return c.SendStatus(204)
 }
 
@@ -191,6 +232,7 @@ func SaveCategoryHandler(c *fiber.Ctx) error {
 	}
 
 	var err error
+	operation, savedVersion := schema.ChangeCreate, version
 	if schemaID == "" {
 		// 새 카테고리 생성
 		_, err = database.DB.Exec(`
@@ -208,6 +250,7 @@ func SaveCategoryHandler(c *fiber.Ctx) error {
 		`, categoryName).Scan(&maxVersion)
 
 		newVersion := maxVersion + 1
+		operation, savedVersion = schema.ChangeUpdate, newVersion
 		_, err = database.DB.Exec(`
 			INSERT INTO category_schemas (category_name, version, schema_definition, is_active) 
 			VALUES ($1, $2, $3, $4)
@@ -233,5 +276,6 @@ func SaveCategoryHandler(c *fiber.Ctx) error {
 		})
 	}
 
+	publishSchemaChange("", categoryName, operation, savedVersion)
 	return c.Redirect("/categories")
 }
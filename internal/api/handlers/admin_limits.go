@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetRateLimitsAPI는 설정된 레이트 리밋, 조직의 오늘 수집량, 이 인스턴스의 허용/거부 카운터를 반환합니다.
+func GetRateLimitsAPI(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+
+	limiter := middleware.GetRateLimiter()
+	if limiter == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Rate limiting is not initialized"})
+	}
+
+	usage, err := limiter.Usage(orgID)
+	if err != nil {
+		log.Printf("Error reading ingest quota usage: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read quota usage"})
+	}
+
+	return c.JSON(fiber.Map{
+		"limits": fiber.Map{
+			"token": limiter.TokenRule(),
+			"ip":    limiter.IPRule(),
+		},
+		"quota":    usage,
+		"counters": limiter.Stats(orgID),
+	})
+}
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"database/sql"
+	"encoding/json"
 	"log"
 
 	"github.com/tmidb/tmidb-core/internal/api/middleware"
 	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/listener"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -46,6 +49,9 @@ func CreateListener(c *fiber.Ctx) error {
 	}
 
 	listener.OrgID = orgID
+	if err := validateListenerDefinition(&listener); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
 
 	if err := database.CreateListener(&listener); err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "could not create listener"})
@@ -66,3 +72,171 @@ func DeleteListener(c *fiber.Ctx) error {
 	}
 	return c.JSON(fiber.Map{"success": true})
 }
+
+// listenerWithStats는 관리 API 응답용 리스너와 실행 통계입니다.
+type listenerWithStats struct {
+	database.Listener
+	Stats *database.ListenerStats `json:"stats,omitempty"`
+}
+
+// listenerRequest는 리스너 생성/수정 요청 본문입니다. 수정 시 생략한 필드는 유지됩니다.
+type listenerRequest struct {
+	ListenerID   string          `json:"listener_id"`
+	CategoryName string          `json:"category_name"`
+	Description  *string         `json:"description"`
+	IsActive     *bool           `json:"is_active"`
+	Conditions   json.RawMessage `json:"conditions"`
+	Actions      json.RawMessage `json:"actions"`
+}
+
+// validateListenerDefinition은 리스너의 조건과 액션 정의를 검증합니다.
+func validateListenerDefinition(l *database.Listener) error {
+	conditions, actions, err := listener.Parse(l.Conditions, l.Actions)
+	if err != nil {
+		return err
+	}
+	return listener.Validate(l.CategoryName, conditions, actions)
+}
+
+// GetListenersAPI는 현재 조직의 리스너 목록을 실행 통계와 함께 반환합니다.
+func GetListenersAPI(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+
+	listeners, err := database.GetListeners(orgID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "could not get listeners"})
+	}
+	stats, err := database.GetListenerStats(database.GetDB(), orgID)
+	if err != nil {
+		log.Printf("could not get listener stats: %v", err)
+	}
+
+	result := make([]listenerWithStats, 0, len(listeners))
+	for _, l := range listeners {
+		item := listenerWithStats{Listener: l}
+		if st, ok := stats[l.ListenerID]; ok {
+			item.Stats = &st
+		}
+		result = append(result, item)
+	}
+	return c.JSON(fiber.Map{"listeners": result})
+}
+
+// GetListenerAPI는 현재 조직의 리스너 하나를 실행 통계와 함께 반환합니다.
+func GetListenerAPI(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+
+	l, err := database.GetListener(database.GetDB(), c.Params("id"), orgID)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "listener not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "could not get listener"})
+	}
+
+	item := listenerWithStats{Listener: *l}
+	if stats, err := database.GetListenerStats(database.GetDB(), orgID); err == nil {
+		if st, ok := stats[l.ListenerID]; ok {
+			item.Stats = &st
+		}
+	}
+	return c.JSON(item)
+}
+
+// CreateListenerAPI는 현재 조직에 리스너를 생성합니다.
+func CreateListenerAPI(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+
+	var req listenerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
+	}
+	if req.ListenerID == "" || req.CategoryName == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "listener_id and category_name are required"})
+	}
+
+	l := database.Listener{
+		ListenerID:   req.ListenerID,
+		OrgID:        orgID,
+		CategoryName: req.CategoryName,
+		IsActive:     true,
+		Conditions:   req.Conditions,
+		Actions:      req.Actions,
+	}
+	if req.Description != nil {
+		l.Description = *req.Description
+	}
+	if err := validateListenerDefinition(&l); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := database.CreateListener(&l); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "could not create listener"})
+	}
+	// 생성 직후 비활성으로 요청한 경우
+	if req.IsActive != nil && !*req.IsActive {
+		l.IsActive = false
+		if err := database.UpdateListener(database.GetDB(), &l); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "could not update listener"})
+		}
+	}
+	return c.Status(fiber.StatusCreated).JSON(l)
+}
+
+// UpdateListenerAPI는 리스너의 설명, 조건, 액션을 수정하거나 활성화/비활성화합니다.
+// 변경 사항은 data-manager가 다음 재로딩(최대 15초) 때 반영합니다.
+func UpdateListenerAPI(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+
+	var req listenerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
+	}
+
+	db := database.GetDB()
+	l, err := database.GetListener(db, c.Params("id"), orgID)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "listener not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "could not get listener"})
+	}
+
+	if req.Description != nil {
+		l.Description = *req.Description
+	}
+	if req.IsActive != nil {
+		l.IsActive = *req.IsActive
+	}
+	if req.Conditions != nil {
+		l.Conditions = req.Conditions
+	}
+	if req.Actions != nil {
+		l.Actions = req.Actions
+	}
+	if err := validateListenerDefinition(l); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := database.UpdateListener(db, l); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "could not update listener"})
+	}
+	return c.JSON(l)
+}
+
+// DeleteListenerAPI는 현재 조직의 리스너를 삭제합니다.
+func DeleteListenerAPI(c *fiber.Ctx) error {
+	return DeleteListener(c)
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/migration"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// categoryMigrationRequest는 카테고리 마이그레이션 계획/미리보기/생성 요청입니다.
+// plan이 없으면 category와 버전으로 계획을 만듭니다.
+type categoryMigrationRequest struct {
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Category    string                  `json:"category"`
+	FromVersion int                     `json:"from_version"`
+	ToVersion   int                     `json:"to_version"`
+	Plan        *migration.CategoryPlan `json:"plan"`
+	Limit       int                     `json:"limit"` // 미리보기 행 수
+}
+
+// resolvePlan은 요청의 계획을 현재 조직 기준으로 정리하거나 새로 만듭니다.
+func (r *categoryMigrationRequest) resolvePlan(m *migration.MigrationManager, orgID string) (*migration.CategoryPlan, error) {
+	if r.Plan != nil {
+		r.Plan.OrgID = orgID
+		return r.Plan, nil
+	}
+	return m.PlanCategoryMigration(orgID, r.Category, r.FromVersion, r.ToVersion)
+}
+
+// orgMigration은 현재 조직의 마이그레이션을 조회합니다. 없으면 nil과 응답 전송 결과를 반환합니다.
+func orgMigration(c *fiber.Ctx, m *migration.MigrationManager, orgID string) (*migration.Migration, error) {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return nil, c.Status(400).JSON(fiber.Map{"error": "invalid migration id"})
+	}
+	mig, err := m.GetMigrationByID(id)
+	if err != nil || mig.OrgID != orgID {
+		return nil, c.Status(404).JSON(fiber.Map{"error": "migration not found"})
+	}
+	return mig, nil
+}
+
+// GetMigrationsAPI는 현재 조직의 카테고리 마이그레이션 목록을 반환합니다.
+func GetMigrationsAPI(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit", "100"))
+	m := migration.NewMigrationManager(database.GetDB())
+	migrations, err := m.GetMigrations(c.Query("category"), c.Query("status"), limit)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "could not get migrations"})
+	}
+
+	result := make([]migration.Migration, 0, len(migrations))
+	for _, mig := range migrations {
+		if mig.OrgID == orgID {
+			result = append(result, mig)
+		}
+	}
+	return c.JSON(fiber.Map{"migrations": result})
+}
+
+// PlanCategoryMigrationAPI는 카테고리 스키마 두 버전을 비교해 데이터 마이그레이션 계획을 만듭니다.
+// 저장하지 않으므로 계획을 고친 뒤 미리보기나 생성 요청에 plan으로 넘기면 됩니다.
+func PlanCategoryMigrationAPI(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+
+	var req categoryMigrationRequest
+	if err := c.BodyParser(&req); err != nil || req.Category == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "category is required"})
+	}
+
+	plan, err := migration.NewMigrationManager(database.GetDB()).PlanCategoryMigration(orgID, req.Category, req.FromVersion, req.ToVersion)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(plan)
+}
+
+// PreviewCategoryMigrationAPI는 계획의 영향을 받는 행 수와 일부 행의 변환 결과를 반환합니다.
+func PreviewCategoryMigrationAPI(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+
+	var req categoryMigrationRequest
+	if err := c.BodyParser(&req); err != nil || (req.Plan == nil && req.Category == "") {
+		return c.Status(400).JSON(fiber.Map{"error": "plan or category is required"})
+	}
+
+	m := migration.NewMigrationManager(database.GetDB())
+	plan, err := req.resolvePlan(m, orgID)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	preview, err := m.PreviewCategoryMigration(plan, req.Limit)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(preview)
+}
+
+// CreateMigrationAPI는 카테고리 데이터 마이그레이션을 등록합니다. 실행은 execute 요청으로 시작합니다.
+func CreateMigrationAPI(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+
+	var req categoryMigrationRequest
+	if err := c.BodyParser(&req); err != nil || (req.Plan == nil && req.Category == "") {
+		return c.Status(400).JSON(fiber.Map{"error": "plan or category is required; only category migrations can be created through the API"})
+	}
+
+	m := migration.NewMigrationManager(database.GetDB())
+	plan, err := req.resolvePlan(m, orgID)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	mig, err := m.CreateCategoryMigration(req.Name, req.Description, plan)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(201).JSON(mig)
+}
+
+// ExecuteMigrationAPI는 마이그레이션을 백그라운드에서 실행합니다. 진행 상황은 status로 확인합니다.
+func ExecuteMigrationAPI(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+
+	m := migration.NewMigrationManager(database.GetDB())
+	mig, err := orgMigration(c, m, orgID)
+	if mig == nil {
+		return err
+	}
+	if mig.Status == "running" || mig.Status == "completed" {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "migration is already " + mig.Status})
+	}
+
+	go func(id int) {
+		if _, err := m.ExecuteMigration(id); err != nil {
+			log.Printf("❌ Migration %d failed: %v", id, err)
+			return
+		}
+		log.Printf("✅ Migration %d completed", id)
+	}(mig.ID)
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"id": mig.ID, "status": "running"})
+}
+
+// GetMigrationStatusAPI는 마이그레이션의 상태, 계획과 진행 상황을 반환합니다.
+func GetMigrationStatusAPI(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+
+	mig, err := orgMigration(c, migration.NewMigrationManager(database.GetDB()), orgID)
+	if mig == nil {
+		return err
+	}
+	return c.JSON(mig)
+}
+
+// rollbackResponse는 롤백 결과 응답을 만듭니다.
+func rollbackResponse(c *fiber.Ctx, mig *migration.Migration, result *migration.MigrationResult, err error) error {
+	if err != nil {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error(), "result": result})
+	}
+	return c.JSON(fiber.Map{"migration": mig, "result": result})
+}
+
+// RollbackMigrationAPI는 현재 조직의 마이그레이션 하나를 되돌립니다.
+func RollbackMigrationAPI(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+
+	m := migration.NewMigrationManager(database.GetDB())
+	mig, err := orgMigration(c, m, orgID)
+	if mig == nil {
+		return err
+	}
+
+	result, err := m.RollbackMigration(mig.ID)
+	if updated, getErr := m.GetMigrationByID(mig.ID); getErr == nil {
+		mig = updated
+	}
+	return rollbackResponse(c, mig, result, err)
+}
+
+// RollbackLatestMigrationAPI는 현재 조직에서 가장 최근에 완료된 마이그레이션을 되돌립니다.
+func RollbackLatestMigrationAPI(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+
+	mig, result, err := migration.NewMigrationManager(database.GetDB()).RollbackLatest(orgID)
+	if err == migration.ErrNothingToRollback {
+		return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+	}
+	return rollbackResponse(c, mig, result, err)
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/database"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+// GetOrganizationsAPI는 모든 조직과 조직별 리소스 수를 조회합니다.
+func GetOrganizationsAPI(c *fiber.Ctx) error {
+	orgs, err := database.ListOrganizations(database.GetDB())
+	if err != nil {
+		log.Printf("Error listing organizations: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to list organizations"})
+	}
+	return c.JSON(fiber.Map{"organizations": orgs})
+}
+
+// CreateOrganizationAPI는 새 조직을 만듭니다.
+func CreateOrganizationAPI(c *fiber.Ctx) error {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	org, err := database.CreateOrganization(database.GetDB(), req.Name)
+	switch {
+	case errors.Is(err, database.ErrOrganizationExists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case err != nil && strings.TrimSpace(req.Name) == "":
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case err != nil:
+		log.Printf("Error creating organization: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create organization"})
+	}
+	return c.Status(fiber.StatusCreated).JSON(org)
+}
+
+// DeleteOrganizationAPI는 조직과 조직의 사용자, 토큰, 스키마, 데이터를 삭제합니다.
+// 로그인한 사용자의 조직과 세션에서 선택한 조직은 삭제할 수 없습니다.
+func DeleteOrganizationAPI(c *fiber.Ctx) error {
+	sess, err := c.Locals("session_store").(*session.Store).Get(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Session error"})
+	}
+	userID, _ := sess.Get("user_id").(string)
+
+	org, err := database.GetOrganization(database.GetDB(), c.Params("id"))
+	if errors.Is(err, database.ErrOrganizationNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		log.Printf("Error getting organization: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to get organization"})
+	}
+	if org.OrgID == sessionHomeOrgID(sess) || org.OrgID == sess.Get("org_id") {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "cannot delete the organization you are signed in to or currently working in",
+		})
+	}
+
+	deletion, err := database.DeleteOrganization(database.GetDB(), org.OrgID, "user:"+userID)
+	if err != nil {
+		log.Printf("Error deleting organization %s: %v", org.OrgID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete organization"})
+	}
+	log.Printf("🗑️ 조직 %s (%s) 삭제됨 (user:%s)", deletion.Name, deletion.OrgID, userID)
+
+	// 행은 이미 지워졌으므로 저장소에서 지우지 못한 파일은 알리기만 함
+	failed := 0
+	for _, path := range deletion.StoragePaths {
+		if fileStorage == nil {
+			failed = len(deletion.StoragePaths)
+			break
+		}
+		if err := fileStorage.Delete(c.UserContext(), path); err != nil {
+			log.Printf("⚠️ 첨부 파일 삭제 실패 (%s): %v", path, err)
+			failed++
+		}
+	}
+	return c.JSON(fiber.Map{"organization": deletion, "storage_failed_files": failed})
+}
+
+// GetSessionOrgAPI는 세션에서 선택한 조직과 전환할 수 있는 조직 목록을 반환합니다.
+// 설치 관리자는 모든 조직으로, 다른 사용자는 자기 조직만 선택할 수 있습니다.
+func GetSessionOrgAPI(c *fiber.Ctx) error {
+	store := c.Locals("session_store").(*session.Store)
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+
+	orgs := []database.Organization{}
+	if middleware.IsInstallationAdmin(c, store) {
+		orgs, err = database.ListOrganizations(database.GetDB())
+	} else if org, getErr := database.GetOrganization(database.GetDB(), orgID); getErr == nil {
+		orgs = append(orgs, *org)
+	} else if !errors.Is(getErr, database.ErrOrganizationNotFound) {
+		err = getErr
+	}
+	if err != nil {
+		log.Printf("Error listing organizations: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to list organizations"})
+	}
+	return c.JSON(fiber.Map{"org_id": orgID, "organizations": orgs})
+}
+
+// SwitchSessionOrgAPI는 설치 관리자 세션이 작업할 조직을 바꿉니다.
+// 이후 관리 API와 콘솔 화면은 선택한 조직의 카테고리, 토큰, 데이터를 다룹니다.
+func SwitchSessionOrgAPI(c *fiber.Ctx) error {
+	var req struct {
+		OrgID string `json:"org_id"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.OrgID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "org_id is required"})
+	}
+
+	org, err := database.GetOrganization(database.GetDB(), req.OrgID)
+	if errors.Is(err, database.ErrOrganizationNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		log.Printf("Error getting organization: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to get organization"})
+	}
+
+	sess, err := c.Locals("session_store").(*session.Store).Get(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Session error"})
+	}
+	// 로그인할 때의 조직을 기억해 두어 자기 조직은 삭제하지 못하게 함
+	sess.Set("home_org_id", sessionHomeOrgID(sess))
+	sess.Set("org_id", org.OrgID)
+	if err := sess.Save(); err != nil {
+		log.Printf("Failed to save session: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save session"})
+	}
+	return c.JSON(fiber.Map{"org_id": org.OrgID, "name": org.Name})
+}
+
+// sessionHomeOrgID는 로그인한 사용자가 속한 조직 ID를 반환합니다.
+func sessionHomeOrgID(sess *session.Session) string {
+	if home, ok := sess.Get("home_org_id").(string); ok && home != "" {
+		return home
+	}
+	orgID, _ := sess.Get("org_id").(string)
+	return orgID
+}
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"time"
 
 	"github.com/tmidb/tmidb-core/internal/api/middleware"
 	"github.com/tmidb/tmidb-core/internal/database"
@@ -30,7 +33,12 @@ func GetAuthTokensAPI(c *fiber.Ctx) error {
 	}
 
 	var tokens []database.AuthToken
-	if role == "admin" {
+	if c.Query("kind") == database.TokenKindAPI {
+		if role != "admin" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "only admins can manage API tokens"})
+		}
+		tokens, err = database.GetAuthTokens(orgID)
+	} else if role == "admin" {
 		tokens, err = database.GetAllUserTokens(orgID)
 	} else {
 		tokens, err = database.GetUserTokens(userID, orgID)
@@ -69,6 +77,10 @@ func CreateAuthTokenAPI(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create token"})
 	}
 	createdToken.DecryptedToken = rawToken // 응답에만 원본 토큰 포함
+	if err := database.RecordTokenAudit(database.GetDB(), orgID, createdToken.TokenID, database.TokenKindUser,
+		database.TokenActionCreate, "user:"+userID, nil); err != nil {
+		log.Printf("Error recording token audit: %v", err)
+	}
 
 	return c.Status(fiber.StatusCreated).JSON(createdToken)
 }
@@ -96,10 +108,142 @@ func DeleteAuthTokenAPI(c *fiber.Ctx) error {
 		log.Printf("Error deleting token: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
+	if err := database.RecordTokenAudit(database.GetDB(), orgID, tokenID, database.TokenKindUser,
+		database.TokenActionDelete, "user:"+userID, nil); err != nil {
+		log.Printf("Error recording token audit: %v", err)
+	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+// tokenRequestRef는 요청이 가리키는 토큰과 변경 주체를 반환합니다. ?kind=api면 조직 API 토큰을 가리킵니다.
+// 관리자가 아니면 본인의 사용자 토큰만 다룰 수 있습니다. 오류 응답을 보냈으면 nil을 반환합니다.
+func tokenRequestRef(c *fiber.Ctx) (*database.TokenRef, string, error) {
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return nil, "", c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+	userID, role, err := getUserInfoFromSession(c)
+	if err != nil {
+		return nil, "", c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Session error"})
+	}
+
+	ref := &database.TokenRef{Kind: c.Query("kind", database.TokenKindUser), TokenID: c.Params("id"), OrgID: orgID}
+	switch {
+	case ref.Kind != database.TokenKindUser && ref.Kind != database.TokenKindAPI:
+		return nil, "", c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "kind must be user or api"})
+	case role != "admin" && ref.Kind == database.TokenKindAPI:
+		return nil, "", c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "only admins can manage API tokens"})
+	case role != "admin":
+		ref.UserID = userID
+	}
+	return ref, "user:" + userID, nil
+}
+
+// tokenErrorResponse는 토큰 변경 오류를 응답으로 보냅니다
+func tokenErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, database.ErrTokenNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "token not found"})
+	case errors.Is(err, database.ErrTokenInactive):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "token is revoked or expired"})
+	case errors.Is(err, database.ErrTokenExpiryInPast):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	default:
+		log.Printf("Error updating token: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update token"})
+	}
+}
+
+// RotateAuthTokenAPI는 토큰을 교체합니다. 새 토큰을 발급하고 이전 토큰은 grace_period(기본 24h) 뒤에 만료됩니다.
+func RotateAuthTokenAPI(c *fiber.Ctx) error {
+	ref, actor, err := tokenRequestRef(c)
+	if ref == nil {
+		return err
+	}
+
+	var req struct {
+		GracePeriod string `json:"grace_period"`
+	}
+	c.BodyParser(&req)
+	if req.GracePeriod == "" {
+		req.GracePeriod = "24h"
+	}
+	grace, err := database.ParseTokenDuration(req.GracePeriod)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	rotated, err := database.RotateToken(database.GetDB(), *ref, grace, actor)
+	if err != nil {
+		return tokenErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(rotated)
+}
+
+// SetAuthTokenExpiryAPI는 토큰의 만료 시각을 설정(expires_at), 연장(extend_by) 하거나 없앱니다(clear).
+func SetAuthTokenExpiryAPI(c *fiber.Ctx) error {
+	ref, actor, err := tokenRequestRef(c)
+	if ref == nil {
+		return err
+	}
+
+	var req struct {
+		ExpiresAt *time.Time `json:"expires_at"`
+		ExtendBy  string     `json:"extend_by"`
+		Clear     bool       `json:"clear"`
+	}
+	if err := c.BodyParser(&req); err != nil || (req.ExpiresAt == nil && req.ExtendBy == "" && !req.Clear) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "one of expires_at, extend_by or clear is required"})
+	}
+	var extendBy time.Duration
+	if req.ExtendBy != "" {
+		if extendBy, err = database.ParseTokenDuration(req.ExtendBy); err != nil || extendBy == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid extend_by: " + req.ExtendBy})
+		}
+	}
+
+	expiresAt, err := database.SetTokenExpiry(database.GetDB(), *ref, req.ExpiresAt, extendBy, actor)
+	if err != nil {
+		return tokenErrorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{"token_id": ref.TokenID, "expires_at": expiresAt})
+}
+
+// RevokeAuthTokenAPI는 토큰을 즉시 폐기합니다. 토큰은 감사 기록을 위해 비활성 상태로 남습니다.
+func RevokeAuthTokenAPI(c *fiber.Ctx) error {
+	ref, actor, err := tokenRequestRef(c)
+	if ref == nil {
+		return err
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	c.BodyParser(&req)
+
+	if err := database.RevokeToken(database.GetDB(), *ref, req.Reason, actor); err != nil {
+		return tokenErrorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{"token_id": ref.TokenID, "is_active": false})
+}
+
+// GetTokenAuditLogAPI는 조직의 토큰 감사 로그를 반환합니다 (?token_id=로 특정 토큰만).
+func GetTokenAuditLogAPI(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit", "100"))
+	entries, err := database.GetTokenAuditLog(database.GetDB(), orgID, c.Query("token_id"), limit)
+	if err != nil {
+		log.Printf("Error getting token audit log: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to get token audit log"})
+	}
+	return c.JSON(fiber.Map{"entries": entries})
+}
+
 // getUserInfoFromSession은 세션에서 사용자 ID와 역할을 추출하는 헬퍼 함수입니다.
 func getUserInfoFromSession(c *fiber.Ctx) (string, string, error) {
 	store := c.Locals("session_store").(*session.Store)
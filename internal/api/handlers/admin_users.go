@@ -3,10 +3,10 @@ package handlers
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/tmidb/tmidb-core/internal/api/middleware"
 	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/query"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/session"
@@ -211,9 +211,7 @@ func UpdateUserHandler(c *fiber.Ctx, store *session.Store) error {
 	}
 
 	// 업데이트할 필드 구성
-	updates := []string{}
-	args := []interface{}{}
-	argIndex := 1
+	update := query.NewUpdate("users")
 
 	if req.Role != "" {
 		if req.Role != "admin" && req.Role != "editor" && req.Role != "viewer" {
@@ -221,35 +219,27 @@ func UpdateUserHandler(c *fiber.Ctx, store *session.Store) error {
 				"error": "Role must be admin, editor, or viewer",
 			})
 		}
-		updates = append(updates, "role = $"+fmt.Sprintf("%d", argIndex))
-		args = append(args, req.Role)
-		argIndex++
+		update.Set("role", req.Role)
 	}
 
 	if req.Permissions != "" {
-		updates = append(updates, "permissions = $"+fmt.Sprintf("%d", argIndex))
-		args = append(args, req.Permissions)
-		argIndex++
+		update.Set("permissions", req.Permissions)
 	}
 
 	if req.IsActive != nil {
-		updates = append(updates, "is_active = $"+fmt.Sprintf("%d", argIndex))
-		args = append(args, *req.IsActive)
-		argIndex++
+		update.Set("is_active", *req.IsActive)
 	}
 
-	if len(updates) == 0 {
+	if update.Empty() {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "No fields to update",
 		})
 	}
 
 	// 업데이트 실행
-	query := fmt.Sprintf("UPDATE users SET %s WHERE user_id = $%d",
-		strings.Join(updates, ", "), argIndex)
-	args = append(args, userID)
+	stmt, args := update.Where(update.Eq("user_id", userID)).SQL()
 
-	result, err := database.DB.Exec(query, args...)
+	result, err := database.DB.Exec(stmt, args...)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to update user",
@@ -376,10 +366,11 @@ func CreateUserAPI(c *fiber.Ctx) error {
 	}
 
 	var req struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
-		Role     string `json:"role"`
-		IsActive bool   `json:"is_active"`
+		Username    string                     `json:"username"`
+		Password    string                     `json:"password"`
+		Role        string                     `json:"role"`
+		IsActive    bool                       `json:"is_active"`
+		Permissions *database.TokenPermissions `json:"permissions"`
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -393,6 +384,12 @@ func CreateUserAPI(c *fiber.Ctx) error {
 		Role:     req.Role,
 		IsActive: req.IsActive,
 	}
+	if req.Permissions != nil {
+		if err := req.Permissions.Validate(); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		user.Permissions = *req.Permissions
+	}
 	createdUser, err := database.CreateUser(user)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Failed to create user: %v", err)})
@@ -410,9 +407,10 @@ func UpdateUserAPI(c *fiber.Ctx) error {
 
 	id := c.Params("id")
 	var req struct {
-		Role     string `json:"role"`
-		IsActive *bool  `json:"is_active"`
-		Password string `json:"password,omitempty"` // For password changes
+		Role        string                     `json:"role"`
+		IsActive    *bool                      `json:"is_active"`
+		Password    string                     `json:"password,omitempty"` // For password changes
+		Permissions *database.TokenPermissions `json:"permissions"`
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -446,6 +444,12 @@ func UpdateUserAPI(c *fiber.Ctx) error {
 	if req.IsActive != nil {
 		userToUpdate.IsActive = *req.IsActive
 	}
+	if req.Permissions != nil {
+		if err := req.Permissions.Validate(); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		userToUpdate.Permissions = *req.Permissions
+	}
 
 	updatedUser, err := database.UpdateUser(userToUpdate)
 	if err != nil {
@@ -453,9 +457,57 @@ func UpdateUserAPI(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update user"})
 	}
 
+	// 비활성화하거나 비밀번호를 바꾸면 기존 로그인을 모두 끊음
+	if (req.IsActive != nil && !*req.IsActive) || req.Password != "" {
+		if _, _, err := database.RevokeUserSessions(database.GetDB(), id); err != nil {
+			log.Printf("Error revoking sessions of user %s: %v", id, err)
+		}
+	}
+
 	return c.JSON(updatedUser)
 }
 
+// RevokeUserSessionsAPI는 사용자의 모든 웹 콘솔 세션을 끊습니다 (모든 기기에서 로그아웃).
+// 리프레시 토큰은 항상 폐기되고, 세션은 SESSION_STORE=postgres일 때만 바로 지워집니다.
+func RevokeUserSessionsAPI(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: " + err.Error()})
+	}
+
+	id := c.Params("id")
+	users, err := database.GetUsers(orgID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to get users"})
+	}
+	found := false
+	for _, u := range users {
+		if u.UserID == id {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "user not found"})
+	}
+
+	sessions, refreshTokens, err := database.RevokeUserSessions(database.GetDB(), id)
+	if err != nil {
+		log.Printf("Error revoking sessions of user %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to revoke sessions"})
+	}
+
+	result := fiber.Map{
+		"user_id":                id,
+		"sessions_revoked":       sessions,
+		"refresh_tokens_revoked": refreshTokens,
+	}
+	if !middleware.SessionsRevocable() {
+		result["warning"] = "SESSION_STORE is memory; existing sessions stay valid until they expire"
+	}
+	return c.JSON(result)
+}
+
 // DeleteUserAPI는 현재 조직의 사용자를 삭제합니다.
 func DeleteUserAPI(c *fiber.Ctx) error {
 	orgID, err := middleware.GetOrgID(c)
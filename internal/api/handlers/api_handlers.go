@@ -74,7 +74,7 @@ func GetTargetByIDLegacy(c *fiber.Ctx) error {
 		SELECT t.name, tc.category_data, tc.updated_at
 		FROM target_categories tc
 		JOIN target t ON tc.target_id = t.target_id
-		WHERE t.target_id = $1 AND tc.category_name = $2 AND tc.schema_version = $3
+		WHERE t.target_id = $1 AND tc.category_name = $2 AND tc.schema_version = $3 AND tc.deleted_at IS NULL
 	`, targetID, category, version).Scan(&targetName, &categoryData, &updatedAt)
 
 	if err != nil {
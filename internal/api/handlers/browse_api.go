@@ -0,0 +1,460 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/query"
+)
+
+// 데이터 브라우저 페이지 크기
+const (
+	browseDefaultLimit = 50
+	browseMaxLimit     = 1000
+)
+
+// browseTable은 데이터 브라우저로 볼 수 있는 테이블입니다.
+// 필터와 정렬은 카테고리 데이터 API와 같은 문법이고 table에 적힌 컬럼과 데이터 컬럼만 쓸 수 있습니다.
+type browseTable struct {
+	table      *query.Table
+	from       string
+	row        string   // 행 하나를 JSON 객체로 만드는 식
+	fields     []string // row가 만드는 객체의 필드 (콘솔 표의 열 순서)
+	order      string   // 기본 정렬
+	tieBreaker string   // 정렬 값이 같은 행의 순서 (유일한 키)
+	category   bool     // 카테고리를 지정해야 함 (카테고리 read 권한 확인)
+	admin      bool     // 관리자 토큰만
+
+	// scope는 조직(과 카테고리) 범위 조건을 문에 더합니다
+	scope func(s *query.Select, orgID, category string, versionCtx *middleware.VersionContext) error
+}
+
+// browseTables 데이터 브라우저 테이블 (요청의 table 값)
+var browseTables = map[string]*browseTable{
+	// 카테고리 문서 (휴지통과 보관된 타겟 제외, API 경로의 버전 적용)
+	"documents": {
+		table: query.CategoryData,
+		from:  "target_categories",
+		row: "jsonb_build_object('target_id', target_id, 'schema_version', schema_version, 'revision', revision, " +
+			"'data', category_data, 'created_at', created_at, 'updated_at', updated_at)",
+		fields:     []string{"target_id", "schema_version", "revision", "data", "created_at", "updated_at"},
+		order:      defaultCategoryOrder,
+		tieBreaker: "target_id DESC",
+		category:   true,
+		scope: func(s *query.Select, orgID, category string, versionCtx *middleware.VersionContext) error {
+			return buildCategoryWhere(s, orgID, category, versionCtx, &query.Query{})
+		},
+	},
+	// 카테고리의 관측값 (ts_obs에는 조직이 없어 카테고리 문서로 조직을 확인)
+	"timeseries": {
+		table: &query.Table{
+			Columns:    map[string]string{"ts": "time", "target_id": "text"},
+			DataColumn: "payload",
+		},
+		from:       "ts_obs JOIN target_categories USING (target_id, category_name)",
+		row:        "jsonb_build_object('target_id', target_id, 'ts', ts, 'payload', payload)",
+		fields:     []string{"target_id", "ts", "payload"},
+		order:      "ts DESC, target_id DESC",
+		tieBreaker: "ts DESC, target_id DESC",
+		category:   true,
+		scope: func(s *query.Select, orgID, category string, _ *middleware.VersionContext) error {
+			s.Where(s.Eq("org_id", orgID), s.Eq("category_name", category), "deleted_at IS NULL")
+			return nil
+		},
+	},
+	// 조직에 카테고리 문서가 있는 타겟 (보관된 타겟 포함, archived_at으로 거를 수 있음)
+	"targets": {
+		table: &query.Table{
+			Columns: map[string]string{
+				"target_id":   "text",
+				"name":        "text",
+				"created_at":  "time",
+				"updated_at":  "time",
+				"archived_at": "time",
+			},
+			Nullable: map[string]bool{"archived_at": true},
+		},
+		from: "target",
+		row: "jsonb_build_object('target_id', target_id, 'name', name, 'created_at', created_at, " +
+			"'updated_at', updated_at, 'archived_at', archived_at)",
+		fields:     []string{"target_id", "name", "created_at", "updated_at", "archived_at"},
+		order:      "created_at DESC, target_id DESC",
+		tieBreaker: "target_id DESC",
+		scope: func(s *query.Select, orgID, _ string, _ *middleware.VersionContext) error {
+			s.Where("deleted_at IS NULL",
+				"EXISTS (SELECT 1 FROM target_categories tc WHERE tc.target_id = target.target_id AND tc.org_id = "+
+					s.Arg(orgID)+" AND tc.deleted_at IS NULL)")
+			return nil
+		},
+	},
+	// 타겟 보관/병합/삭제 감사 로그
+	"audit": {
+		table: &query.Table{
+			Columns:    map[string]string{"id": "number", "action": "text", "actor": "text", "created_at": "time"},
+			DataColumn: "details",
+		},
+		from: "target_audit_log",
+		row: "jsonb_build_object('id', id, 'action', action, 'target_ids', target_ids, 'actor', actor, " +
+			"'details', details, 'created_at', created_at)",
+		fields:     []string{"id", "action", "target_ids", "actor", "details", "created_at"},
+		order:      "created_at DESC, id DESC",
+		tieBreaker: "id DESC",
+		admin:      true,
+		scope: func(s *query.Select, orgID, _ string, _ *middleware.VersionContext) error {
+			s.Where(s.Eq("org_id", orgID))
+			return nil
+		},
+	},
+}
+
+// BrowseRequest는 데이터 브라우저 요청입니다
+type BrowseRequest struct {
+	Table    string   `json:"table"`
+	Category string   `json:"category,omitempty"` // documents, timeseries
+	Filters  []string `json:"filters,omitempty"`  // "data.temp>25", "updated_at>=2025-01-01"
+	Sort     string   `json:"sort,omitempty"`     // "-data.temp,target_id"
+	Cursor   string   `json:"cursor,omitempty"`   // 앞 페이지 응답의 next_cursor
+	Limit    int      `json:"limit,omitempty"`    // 기본 50, 최대 1000
+	Count    bool     `json:"count,omitempty"`    // 조건에 맞는 전체 행 수도 계산
+}
+
+// BrowseResult는 데이터 브라우저 응답 데이터입니다
+type BrowseResult struct {
+	Table    string            `json:"table"`
+	Category string            `json:"category,omitempty"`
+	Fields   []string          `json:"fields"`
+	Rows     []json.RawMessage `json:"rows"`
+}
+
+// BrowseTableInfo는 콘솔이 필터와 정렬 입력을 만들 때 쓰는 테이블 정보입니다
+type BrowseTableInfo struct {
+	Name          string            `json:"name"`
+	Fields        []string          `json:"fields"`
+	Columns       map[string]string `json:"columns"`     // 필터와 정렬에 쓸 수 있는 컬럼과 종류
+	DataFields    bool              `json:"data_fields"` // data.경로 필터와 정렬을 쓸 수 있음
+	DefaultSort   string            `json:"default_sort"`
+	NeedsCategory bool              `json:"needs_category"`
+	AdminOnly     bool              `json:"admin_only"`
+}
+
+// browseCursor는 앞 페이지 마지막 행의 위치입니다. 위치는 정렬 식마다의 값(텍스트, NULL은 nil)이고
+// 다음 페이지는 정렬 순서에서 그 행 뒤에 오는 행부터 읽습니다. 다른 조건의 요청에 커서를 쓰지 못하도록
+// 요청 조건의 지문을 함께 넣습니다.
+type browseCursor struct {
+	After []*string `json:"a"`
+	Query string    `json:"q"`
+}
+
+// browseKey는 결과 순서를 정하는 정렬 식 하나입니다
+type browseKey struct {
+	expr     string
+	desc     bool
+	nullable bool
+}
+
+// ListBrowseTables는 데이터 브라우저로 볼 수 있는 테이블 목록을 반환합니다
+func ListBrowseTables(c *fiber.Ctx) error {
+	names := make([]string, 0, len(browseTables))
+	for name := range browseTables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tables := make([]BrowseTableInfo, 0, len(names))
+	for _, name := range names {
+		t := browseTables[name]
+		tables = append(tables, BrowseTableInfo{
+			Name:          name,
+			Fields:        t.fields,
+			Columns:       t.table.Columns,
+			DataFields:    t.table.DataColumn != "",
+			DefaultSort:   t.order,
+			NeedsCategory: t.category,
+			AdminOnly:     t.admin,
+		})
+	}
+	return sendSuccessResponse(c, tables, nil)
+}
+
+// Browse는 웹 콘솔의 데이터 브라우저용으로 테이블 하나를 필터, 정렬, 커서 페이징해 조회합니다.
+// 모든 테이블은 토큰의 조직으로 범위가 정해지고, 카테고리 테이블은 카테고리 read 권한,
+// 감사 로그는 관리자 토큰이 필요합니다.
+func Browse(c *fiber.Ctx) error {
+	startTime := time.Now()
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+
+	var req BrowseRequest
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return sendErrorResponse(c, "INVALID_JSON", "Invalid request body", err.Error())
+	}
+	t, err := browseTableFor(&req)
+	if err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", err.Error(), "")
+	}
+	switch {
+	case t.admin && !middleware.CategoryAllowed(c, "admin", ""):
+		return middleware.PermissionDenied(c, "admin", "")
+	case t.category && !middleware.CategoryAllowed(c, "read", req.Category):
+		return middleware.PermissionDenied(c, "read", req.Category)
+	}
+
+	q, err := t.table.Parse(req.Filters, req.Sort)
+	if err != nil {
+		return sendErrorResponse(c, "QUERY_PARSE_ERROR", err.Error(), "")
+	}
+	if req.Table == "documents" {
+		filterRecorder.Record(orgID, req.Category, q)
+	}
+
+	versionCtx := middleware.GetVersionContext(c)
+	keys := browseOrder(t, q)
+	fingerprint := browseFingerprint(&req, versionCtx, q)
+	after, err := decodeBrowseCursor(req.Cursor, fingerprint, len(keys))
+	if err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", err.Error(), "")
+	}
+
+	stmt, err := buildBrowseQuery(t, keys, orgID, req.Category, versionCtx, q)
+	if err != nil {
+		return sendErrorResponse(c, "QUERY_PARSE_ERROR", err.Error(), "")
+	}
+	db := database.GetReadDB()
+
+	pagination := &PaginationMeta{
+		PageSize: req.Limit,
+		HasPrev:  after != nil,
+		Mode:     "cursor",
+	}
+	if req.Count {
+		if err := db.QueryRow("SELECT COUNT(*) FROM "+t.from+" WHERE "+stmt.Conditions(), stmt.Args()...).Scan(&pagination.TotalRecords); err != nil {
+			log.Printf("Error counting %s rows: %v", req.Table, err)
+			return sendErrorResponse(c, "DATABASE_ERROR", "Failed to browse "+req.Table, "")
+		}
+	}
+
+	// 커서 행 다음부터, 한 행을 더 읽어 다음 페이지가 있는지 확인
+	if after != nil {
+		stmt.Where(browseAfter(stmt, keys, after))
+	}
+	sql, args := stmt.Limit(req.Limit + 1).SQL()
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		log.Printf("Error browsing %s: %v", req.Table, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to browse "+req.Table, "")
+	}
+	defer rows.Close()
+
+	result := &BrowseResult{Table: req.Table, Category: req.Category, Fields: t.fields, Rows: make([]json.RawMessage, 0, req.Limit)}
+	var last string // 페이지 마지막 행의 정렬 값
+	for rows.Next() {
+		var row, position string
+		if err := rows.Scan(&row, &position); err != nil {
+			log.Printf("Error browsing %s: %v", req.Table, err)
+			return sendErrorResponse(c, "DATABASE_ERROR", "Failed to browse "+req.Table, "")
+		}
+		if len(result.Rows) < req.Limit {
+			last = position
+		}
+		result.Rows = append(result.Rows, json.RawMessage(row))
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error browsing %s: %v", req.Table, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to browse "+req.Table, "")
+	}
+	if len(result.Rows) > req.Limit {
+		result.Rows = result.Rows[:req.Limit]
+		cursor := browseCursor{Query: fingerprint}
+		if err := json.Unmarshal([]byte(last), &cursor.After); err != nil {
+			log.Printf("Error browsing %s: %v", req.Table, err)
+			return sendErrorResponse(c, "DATABASE_ERROR", "Failed to browse "+req.Table, "")
+		}
+		pagination.HasNext = true
+		pagination.NextCursor = encodeBrowseCursor(cursor)
+	}
+
+	meta := &Meta{
+		Pagination: pagination,
+		Query: &QueryMeta{
+			Filters:     q.Strings(),
+			ProcessTime: time.Since(startTime).String(),
+		},
+	}
+	return sendSuccessResponse(c, result, meta)
+}
+
+// browseTableFor는 요청의 테이블을 찾고 카테고리와 페이지 크기를 확인합니다 (limit이 없으면 기본값으로 채움)
+func browseTableFor(req *BrowseRequest) (*browseTable, error) {
+	t, ok := browseTables[req.Table]
+	if !ok {
+		return nil, fmt.Errorf("unknown table %q", req.Table)
+	}
+	switch {
+	case t.category && req.Category == "":
+		return nil, fmt.Errorf("table %s requires a category", req.Table)
+	case !t.category && req.Category != "":
+		return nil, fmt.Errorf("table %s does not take a category", req.Table)
+	}
+	switch {
+	case req.Limit == 0:
+		req.Limit = browseDefaultLimit
+	case req.Limit < 0 || req.Limit > browseMaxLimit:
+		return nil, fmt.Errorf("limit must be between 1 and %d", browseMaxLimit)
+	}
+	return t, nil
+}
+
+// buildBrowseQuery는 조직 범위와 필터, 정렬을 적용한 조회 문을 만듭니다 (커서 조건과 LIMIT은 호출하는 쪽에서).
+// 행마다 JSON 객체와 함께 정렬 식의 값을 텍스트 배열(JSON)로 읽어 마지막 행으로 커서를 만듭니다.
+func buildBrowseQuery(t *browseTable, keys []browseKey, orgID, category string,
+	versionCtx *middleware.VersionContext, q *query.Query) (*query.Select, error) {
+
+	values := make([]string, len(keys))
+	order := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = "(" + key.expr + ")::text"
+		order[i] = key.expr
+		if key.desc {
+			order[i] += " DESC"
+		}
+	}
+	s := query.NewSelect("("+t.row+")::text, to_jsonb(ARRAY["+strings.Join(values, ", ")+"])::text", t.from)
+	if err := t.scope(s, orgID, category, versionCtx); err != nil {
+		return nil, err
+	}
+	if err := s.Filter(q); err != nil {
+		return nil, err
+	}
+	return s.OrderBy(strings.Join(order, ", ")), nil
+}
+
+// browseOrder는 요청의 정렬(없으면 테이블의 기본 정렬)에 tieBreaker를 붙인 정렬 식 목록입니다 (Query.OrderBy와 같은 순서)
+func browseOrder(t *browseTable, q *query.Query) []browseKey {
+	if len(q.Sort) == 0 {
+		return parseBrowseOrder(t.table, t.order)
+	}
+	keys := make([]browseKey, 0, len(q.Sort)+1)
+	for _, key := range q.Sort {
+		keys = append(keys, browseKey{expr: key.Expr(), desc: key.Desc, nullable: key.Nullable()})
+	}
+	return append(keys, parseBrowseOrder(t.table, t.tieBreaker)...)
+}
+
+// parseBrowseOrder는 코드에 적힌 "created_at DESC, id DESC" 형식의 순서를 읽습니다
+func parseBrowseOrder(table *query.Table, order string) []browseKey {
+	var keys []browseKey
+	for _, part := range strings.Split(order, ",") {
+		expr, desc := strings.CutSuffix(strings.TrimSpace(part), " DESC")
+		keys = append(keys, browseKey{expr: expr, desc: desc, nullable: table.Nullable[expr]})
+	}
+	return keys
+}
+
+// browseAfter는 정렬 순서에서 커서 행 뒤에 오는 행의 조건입니다. 값은 타입 없는 매개변수로 넘겨
+// PostgreSQL이 정렬 식의 타입으로 읽습니다. 방향이 모두 같고 NULL이 없으면 행 비교
+// (updated_at, target_id) < ($n, $m)로 인덱스를 쓰고, 아니면 앞 식들이 같고 다음 식이 뒤에 오는 경우를
+// OR로 풉니다. NULL은 PostgreSQL 기본대로 오름차순에서 마지막, 내림차순에서 처음입니다.
+func browseAfter(s *query.Select, keys []browseKey, after []*string) string {
+	rowCompare := true
+	for i, key := range keys {
+		if key.nullable || after[i] == nil || key.desc != keys[0].desc {
+			rowCompare = false
+		}
+	}
+	if rowCompare {
+		exprs, args := make([]string, len(keys)), make([]string, len(keys))
+		for i, key := range keys {
+			exprs[i], args[i] = key.expr, s.Arg(*after[i])
+		}
+		op := " > "
+		if keys[0].desc {
+			op = " < "
+		}
+		return "(" + strings.Join(exprs, ", ") + ")" + op + "(" + strings.Join(args, ", ") + ")"
+	}
+
+	var alternatives []string
+	equal := "" // 앞 식들이 커서 행의 값과 같음
+	for i, key := range keys {
+		value := after[i]
+		var next string
+		switch {
+		case value == nil && key.desc:
+			next = key.expr + " IS NOT NULL"
+		case value == nil:
+			// 오름차순에서 NULL 뒤에는 값이 없음
+		case key.desc:
+			next = key.expr + " < " + s.Arg(*value)
+		case key.nullable:
+			next = "(" + key.expr + " > " + s.Arg(*value) + " OR " + key.expr + " IS NULL)"
+		default:
+			next = key.expr + " > " + s.Arg(*value)
+		}
+		if next != "" {
+			alternatives = append(alternatives, "("+equal+next+")")
+		}
+		if i == len(keys)-1 {
+			break
+		}
+		if value == nil {
+			equal += key.expr + " IS NULL AND "
+		} else {
+			equal += key.expr + " = " + s.Arg(*value) + " AND "
+		}
+	}
+	if len(alternatives) == 0 {
+		return "FALSE"
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
+
+// browseFingerprint는 결과 순서를 정하는 요청 조건의 지문입니다 (페이지 크기는 페이지마다 바꿀 수 있음)
+func browseFingerprint(req *BrowseRequest, versionCtx *middleware.VersionContext, q *query.Query) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		req.Table, req.Category, versionCtx.RequestedVersion,
+		q.Key(), q.SortString(),
+	}, "\x01")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// encodeBrowseCursor는 커서를 불투명한 문자열로 만듭니다
+func encodeBrowseCursor(cursor browseCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeBrowseCursor는 커서의 위치(정렬 식 keys개의 값)를 읽습니다 (커서가 없으면 nil).
+// 다른 조건으로 만든 커서는 거부합니다.
+func decodeBrowseCursor(value, fingerprint string, keys int) ([]*string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	var cursor browseCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	if cursor.Query != fingerprint {
+		return nil, errors.New("cursor does not match the table, filters or sort of this request")
+	}
+	if len(cursor.After) != keys {
+		return nil, errors.New("invalid cursor")
+	}
+	return cursor.After, nil
+}
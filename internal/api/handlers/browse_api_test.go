@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/query"
+)
+
+func TestBrowseTableFor(t *testing.T) {
+	req := &BrowseRequest{Table: "documents", Category: "sensors"}
+	if _, err := browseTableFor(req); err != nil || req.Limit != browseDefaultLimit {
+		t.Errorf("browseTableFor = %v, limit %d", err, req.Limit)
+	}
+
+	for _, bad := range []BrowseRequest{
+		{Table: "users"},
+		{Table: "documents"},
+		{Table: "targets", Category: "sensors"},
+		{Table: "targets", Limit: browseMaxLimit + 1},
+		{Table: "audit", Limit: -1},
+	} {
+		if _, err := browseTableFor(&bad); err == nil {
+			t.Errorf("browseTableFor(%+v) accepted", bad)
+		}
+	}
+}
+
+func TestBuildBrowseQuery(t *testing.T) {
+	versionCtx := &middleware.VersionContext{RequestedVersion: "v1"}
+
+	ts := browseTables["timeseries"]
+	q, err := ts.table.Parse([]string{"data.temp>25", "ts>=2025-01-01"}, "-data.temp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := browseOrder(ts, q)
+	s, err := buildBrowseQuery(ts, keys, "org", "sensors", versionCtx, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	temp, at, id := "25.5", "2025-01-02 03:04:05.123456+00", "0b8f3a52-5a0e-4c1b-9a53-0f4f0d2c9e11"
+	sql, args := s.Where(browseAfter(s, keys, []*string{&temp, &at, &id})).Limit(51).SQL()
+	want := "SELECT (jsonb_build_object('target_id', target_id, 'ts', ts, 'payload', payload))::text, " +
+		`to_jsonb(ARRAY[(payload #> '{"temp"}')::text, (ts)::text, (target_id)::text])::text` +
+		" FROM ts_obs JOIN target_categories USING (target_id, category_name)" +
+		" WHERE org_id = $1 AND category_name = $2 AND deleted_at IS NULL AND " +
+		`CASE WHEN jsonb_typeof(payload #> '{"temp"}') = 'number' THEN (payload #>> '{"temp"}')::numeric END > $3::numeric` +
+		" AND ts >= $4 AND " +
+		`((payload #> '{"temp"}' < $5) OR (payload #> '{"temp"}' = $6 AND ts < $7) OR (payload #> '{"temp"}' = $6 AND ts = $8 AND target_id < $9))` +
+		` ORDER BY payload #> '{"temp"}' DESC, ts DESC, target_id DESC LIMIT $10`
+	if sql != want || len(args) != 10 || args[0] != "org" || args[1] != "sensors" || args[4] != temp || args[8] != id {
+		t.Errorf("SQL =\n%s %v\nwant\n%s", sql, args, want)
+	}
+
+	// 데이터 컬럼이 없는 테이블은 data. 경로를 거부
+	targets := browseTables["targets"]
+	if _, err := targets.table.Parse([]string{"data.name=a"}, ""); err == nil {
+		t.Error("data path accepted on targets")
+	}
+	q, err = targets.table.Parse([]string{"archived_at=null"}, "name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err = buildBrowseQuery(targets, browseOrder(targets, q), "org", "", versionCtx, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, args = s.SQL()
+	if !strings.Contains(sql, "tc.org_id = $1") || !strings.HasSuffix(sql, "archived_at IS NULL ORDER BY name, target_id DESC") ||
+		!reflect.DeepEqual(args, []interface{}{"org"}) {
+		t.Errorf("targets SQL = %s %v", sql, args)
+	}
+
+	// 문서는 카테고리 데이터 API와 같은 범위 (휴지통, 보관된 타겟 제외, 경로의 버전)
+	docs := browseTables["documents"]
+	q, _ = docs.table.Parse(nil, "")
+	s, err = buildBrowseQuery(docs, browseOrder(docs, q), "org", "sensors", versionCtx, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, args = s.SQL()
+	if !strings.Contains(sql, "deleted_at IS NULL AND schema_version = $3 AND NOT EXISTS") ||
+		!strings.HasSuffix(sql, "ORDER BY "+defaultCategoryOrder) || len(args) != 3 {
+		t.Errorf("documents SQL = %s %v", sql, args)
+	}
+}
+
+func TestBrowseAfter(t *testing.T) {
+	value := func(v string) *string { return &v }
+	targets := browseTables["targets"]
+	tests := []struct {
+		table *browseTable
+		sort  string
+		after []*string
+		want  string
+	}{
+		// 기본 정렬은 방향이 같고 NULL이 없어 행 비교 (카테고리 데이터 API의 커서와 같음)
+		{browseTables["documents"], "", []*string{value("2025-01-02 00:00:00+00"), value("t1")},
+			"(updated_at, target_id) < ($1, $2)"},
+		{browseTables["audit"], "", []*string{value("2025-01-02 00:00:00+00"), value("7")},
+			"(created_at, id) < ($1, $2)"},
+		// 방향이 섞이면 앞 식이 같은 경우로 풂
+		{targets, "name", []*string{value("pump"), value("t1")},
+			"((name > $1) OR (name = $2 AND target_id < $3))"},
+		// 오름차순의 NULL은 마지막이라 같은 NULL 행 중 다음 행만 남음
+		{targets, "archived_at", []*string{nil, value("t1")},
+			"((archived_at IS NULL AND target_id < $1))"},
+		{targets, "archived_at", []*string{value("2025-01-02 00:00:00+00"), value("t1")},
+			"(((archived_at > $1 OR archived_at IS NULL)) OR (archived_at = $2 AND target_id < $3))"},
+		// 내림차순의 NULL은 처음이라 값이 있는 행이 모두 뒤에 옴
+		{targets, "-archived_at", []*string{nil, value("t1")},
+			"((archived_at IS NOT NULL) OR (archived_at IS NULL AND target_id < $1))"},
+	}
+	for _, tt := range tests {
+		q, err := tt.table.table.Parse(nil, tt.sort)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := query.NewSelect("1", "t")
+		if got := browseAfter(s, browseOrder(tt.table, q), tt.after); got != tt.want {
+			t.Errorf("sort %q after %v:\n got %s\nwant %s", tt.sort, tt.after, got, tt.want)
+		}
+	}
+}
+
+func TestBrowseCursor(t *testing.T) {
+	versionCtx := &middleware.VersionContext{RequestedVersion: "latest"}
+	req := &BrowseRequest{Table: "documents", Category: "sensors", Filters: []string{"data.temp>25"}, Sort: "-data.temp"}
+	q, err := browseTables["documents"].table.Parse(req.Filters, req.Sort)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint := browseFingerprint(req, versionCtx, q)
+
+	if after, err := decodeBrowseCursor("", fingerprint, 3); err != nil || after != nil {
+		t.Errorf("empty cursor = %v, %v", after, err)
+	}
+	temp, updated := "30", "2025-01-02 00:00:00+00"
+	cursor := encodeBrowseCursor(browseCursor{After: []*string{&temp, &updated, nil}, Query: fingerprint})
+	after, err := decodeBrowseCursor(cursor, fingerprint, 3)
+	if err != nil || len(after) != 3 || *after[0] != temp || *after[1] != updated || after[2] != nil {
+		t.Errorf("decoded %v, %v", after, err)
+	}
+
+	// 정렬이 다른 요청에는 쓸 수 없음
+	other, _ := browseTables["documents"].table.Parse(req.Filters, "data.temp")
+	if _, err := decodeBrowseCursor(cursor, browseFingerprint(req, versionCtx, other), 3); err == nil {
+		t.Error("cursor accepted with a different sort")
+	}
+	for _, bad := range []string{"not base64!", encodeBrowseCursor(browseCursor{After: []*string{&temp}, Query: fingerprint})} {
+		if _, err := decodeBrowseCursor(bad, fingerprint, 3); err == nil {
+			t.Errorf("decodeBrowseCursor(%q) accepted", bad)
+		}
+	}
+}
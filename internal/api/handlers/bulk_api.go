@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/schema"
+)
+
+// 대량 수집 설정
+const (
+	MaxBulkBodySize = 32 * 1024 * 1024 // 요청 본문 최대 크기 (fiber BodyLimit)
+	maxBulkRecords  = 10000            // 요청당 최대 레코드 수
+	bulkBatchSize   = 500              // 트랜잭션 하나에 넣는 레코드 수
+)
+
+// BulkRecord는 대량 수집 요청의 레코드 하나입니다
+type BulkRecord struct {
+	TargetID string      `json:"target_id"`
+	EventID  string      `json:"event_id,omitempty"` // 중복 제거 키 (재시도해도 한 번만 저장)
+	Ts       string      `json:"ts,omitempty"`       // RFC3339, 비어 있으면 수신 시각
+	Payload  interface{} `json:"payload"`
+}
+
+// BulkRecordResult는 레코드별 처리 결과입니다
+type BulkRecordResult struct {
+	Index     int                 `json:"index"`
+	TargetID  string              `json:"target_id,omitempty"`
+	Success   bool                `json:"success"`
+	Duplicate bool                `json:"duplicate,omitempty"` // 중복 제거 기간 안에 이미 저장된 레코드 (저장하지 않음)
+	Error     string              `json:"error,omitempty"`
+	Fields    []schema.FieldError `json:"fields,omitempty"`
+}
+
+// BulkIngestResult는 대량 수집 응답 데이터입니다
+type BulkIngestResult struct {
+	Category   string             `json:"category"`
+	Total      int                `json:"total"`
+	Inserted   int                `json:"inserted"`
+	Duplicates int                `json:"duplicates"`
+	Failed     int                `json:"failed"`
+	Results    []BulkRecordResult `json:"results"`
+}
+
+// bulkItem은 파싱된 레코드와 검증 상태입니다
+type bulkItem struct {
+	record    BulkRecord
+	ts        time.Time
+	key       string // 중복 제거 키 (비어 있으면 중복 제거 안 함)
+	duplicate bool
+	err       error
+}
+
+// BulkIngestData는 여러 타겟의 시계열 레코드를 한 번에 수집합니다.
+// 본문은 JSON 배열 또는 NDJSON이며, 각 레코드는 카테고리 스키마로 검증한 뒤
+// 배치 단위 트랜잭션으로 저장하고 레코드별 성공/실패를 반환합니다.
+func BulkIngestData(c *fiber.Ctx) error {
+	category := c.Params("category")
+
+	items, err := parseBulkRecords(c.Body(), c.Get(fiber.HeaderContentType), time.Now())
+	if err != nil {
+		return sendErrorResponse(c, "INVALID_JSON", "Invalid bulk request body", err.Error())
+	}
+	if len(items) == 0 {
+		return sendErrorResponse(c, "INVALID_JSON", "No records in request body", "")
+	}
+	if len(items) > maxBulkRecords {
+		return sendErrorResponse(c, "INVALID_JSON",
+			fmt.Sprintf("Too many records: %d (max %d)", len(items), maxBulkRecords), "")
+	}
+
+	db := database.GetDB()
+	if err := validateBulkRecords(db, category, items); err != nil {
+		return sendErrorResponse(c, "DATABASE_ERROR", err.Error(), "")
+	}
+	if err := insertBulkRecords(db, category, items); err != nil {
+		return sendErrorResponse(c, "DATABASE_ERROR", err.Error(), "")
+	}
+
+	result := bulkResult(category, items)
+	middleware.RecordIngested(c, result.Inserted)
+
+	// 캐시 무효화 (데이터 변경 시, 다른 API 인스턴스에도 알림)
+	if result.Inserted > 0 {
+		var targets []string
+		seen := make(map[string]bool)
+		for _, item := range items {
+			if item.err == nil && !seen[item.record.TargetID] {
+				seen[item.record.TargetID] = true
+				targets = append(targets, item.record.TargetID)
+			}
+		}
+		invalidateCache([]string{category}, targets)
+	}
+
+	// 일부 레코드가 실패하면 207 Multi-Status
+	if result.Failed > 0 {
+		c.Status(fiber.StatusMultiStatus)
+	}
+	return sendSuccessResponse(c, result, nil)
+}
+
+// parseBulkRecords는 JSON 배열 또는 NDJSON 본문을 레코드 목록으로 파싱합니다.
+// 본문 형식 오류는 에러로, 개별 레코드 오류는 해당 항목의 err로 반환합니다.
+func parseBulkRecords(body []byte, contentType string, now time.Time) ([]*bulkItem, error) {
+	trimmed := bytes.TrimSpace(body)
+	ndjson := strings.Contains(contentType, "ndjson") || strings.Contains(contentType, "jsonlines") ||
+		(len(trimmed) > 0 && trimmed[0] != '[')
+
+	var raws []json.RawMessage
+	if ndjson {
+		scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+		scanner.Buffer(make([]byte, 64*1024), MaxBulkBodySize)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			raws = append(raws, append(json.RawMessage(nil), line...))
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	} else if err := json.Unmarshal(trimmed, &raws); err != nil {
+		return nil, err
+	}
+
+	items := make([]*bulkItem, len(raws))
+	for i, raw := range raws {
+		item := &bulkItem{ts: now}
+		items[i] = item
+
+		if err := json.Unmarshal(raw, &item.record); err != nil {
+			item.err = fmt.Errorf("invalid record: %v", err)
+			continue
+		}
+		if item.record.TargetID == "" {
+			item.err = errors.New("target_id is required")
+			continue
+		}
+		if item.record.Payload == nil {
+			item.err = errors.New("payload is required")
+			continue
+		}
+		if item.record.Ts != "" {
+			ts, err := time.Parse(time.RFC3339Nano, item.record.Ts)
+			if err != nil {
+				item.err = fmt.Errorf("invalid ts: %v", err)
+				continue
+			}
+			item.ts = ts
+		}
+		if len(item.record.EventID) > database.MaxEventIDLength {
+			item.err = fmt.Errorf("event_id is longer than %d characters", database.MaxEventIDLength)
+			continue
+		}
+		if item.record.EventID != "" || item.record.Ts != "" {
+			payloadJSON, err := json.Marshal(item.record.Payload)
+			if err != nil {
+				item.err = fmt.Errorf("invalid payload: %v", err)
+				continue
+			}
+			clientTs := time.Time{}
+			if item.record.Ts != "" {
+				clientTs = item.ts
+			}
+			item.key = database.IngestEventKey(item.record.EventID, item.record.TargetID, clientTs, payloadJSON)
+		}
+	}
+
+	return items, nil
+}
+
+// validateBulkRecords는 각 레코드를 타겟이 사용하는 카테고리 스키마로 검증합니다.
+// 스키마는 타겟별로 한 번만 조회합니다.
+func validateBulkRecords(db database.DBTX, category string, items []*bulkItem) error {
+	type targetSchema struct {
+		schema *schema.Schema
+		err    error
+	}
+	schemas := make(map[string]targetSchema)
+
+	for _, item := range items {
+		if item.err != nil {
+			continue
+		}
+
+		ts, ok := schemas[item.record.TargetID]
+		if !ok {
+			definition, err := database.GetTargetCategorySchema(db, item.record.TargetID, category)
+			switch {
+			case err == sql.ErrNoRows:
+				ts.err = fmt.Errorf("target is not linked to category %s", category)
+			case err != nil:
+				return fmt.Errorf("failed to load category schema: %v", err)
+			default:
+				if ts.schema, err = schema.Cached(definition); err != nil {
+					ts.err = fmt.Errorf("invalid schema format: %v", err)
+				}
+			}
+			schemas[item.record.TargetID] = ts
+		}
+
+		if ts.err != nil {
+			item.err = ts.err
+			continue
+		}
+		item.err = ts.schema.Validate(item.record.Payload)
+	}
+
+	return nil
+}
+
+// insertBulkRecords는 검증을 통과한 레코드를 bulkBatchSize 단위 트랜잭션으로 저장합니다.
+// 레코드마다 세이브포인트를 두어 한 레코드의 실패가 배치 전체를 취소하지 않게 합니다.
+// 중복 제거 키가 이미 기록된 레코드는 저장하지 않고 duplicate로 표시합니다.
+func insertBulkRecords(db *sql.DB, category string, items []*bulkItem) error {
+	var pending []*bulkItem
+	for _, item := range items {
+		if item.err == nil {
+			pending = append(pending, item)
+		}
+	}
+
+	for start := 0; start < len(pending); start += bulkBatchSize {
+		end := start + bulkBatchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		if err := insertBulkBatch(db, category, pending[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertBulkBatch(db *sql.DB, category string, batch []*bulkItem) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO ts_obs (target_id, category_name, ts, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (target_id, category_name, ts) DO UPDATE SET
+			payload = EXCLUDED.payload
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, item := range batch {
+		payloadJSON, err := json.Marshal(item.record.Payload)
+		if err != nil {
+			item.err = fmt.Errorf("failed to marshal payload: %v", err)
+			continue
+		}
+
+		if _, err := tx.Exec("SAVEPOINT bulk_record"); err != nil {
+			return err
+		}
+		claimed, err := database.ClaimIngestEvent(tx, item.record.TargetID, category, item.key)
+		if err == nil && claimed {
+			_, err = stmt.Exec(item.record.TargetID, category, item.ts, string(payloadJSON))
+		}
+		item.duplicate = err == nil && !claimed
+		if err != nil {
+			item.err = fmt.Errorf("insert failed: %v", err)
+			if _, err := tx.Exec("ROLLBACK TO SAVEPOINT bulk_record"); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := tx.Exec("RELEASE SAVEPOINT bulk_record"); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		// 커밋 실패 시 배치의 모든 레코드가 저장되지 않음
+		for _, item := range batch {
+			if item.err == nil {
+				item.err = fmt.Errorf("commit failed: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// bulkResult는 레코드별 처리 결과를 응답 형식으로 모읍니다
+func bulkResult(category string, items []*bulkItem) *BulkIngestResult {
+	result := &BulkIngestResult{
+		Category: category,
+		Total:    len(items),
+		Results:  make([]BulkRecordResult, len(items)),
+	}
+
+	for i, item := range items {
+		r := BulkRecordResult{Index: i, TargetID: item.record.TargetID, Success: item.err == nil}
+		switch {
+		case item.err != nil:
+			result.Failed++
+			r.Error = item.err.Error()
+			var validationErr *schema.ValidationError
+			if errors.As(item.err, &validationErr) {
+				r.Error = "payload does not match category schema"
+				r.Fields = validationErr.Errors
+			}
+		case item.duplicate:
+			result.Duplicates++
+			r.Duplicate = true
+		default:
+			result.Inserted++
+		}
+		result.Results[i] = r
+	}
+
+	return result
+}
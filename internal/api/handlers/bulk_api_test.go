@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBulkRecords(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	array := []byte(`[
+		{"target_id": "t1", "ts": "2025-01-02T03:04:05Z", "payload": {"v": 1}},
+		{"target_id": "t2", "payload": {"v": 2}},
+		{"payload": {"v": 3}},
+		{"target_id": "t4", "ts": "yesterday", "payload": {}}
+	]`)
+	items, err := parseBulkRecords(array, "application/json", now)
+	if err != nil {
+		t.Fatalf("parse array: %v", err)
+	}
+	if len(items) != 4 {
+		t.Fatalf("expected 4 items, got %d", len(items))
+	}
+	if items[0].err != nil || !items[0].ts.Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("item 0: unexpected %+v", items[0])
+	}
+	if items[1].err != nil || !items[1].ts.Equal(now) {
+		t.Errorf("item 1: expected default timestamp, got %+v", items[1])
+	}
+	if items[2].err == nil || items[3].err == nil {
+		t.Errorf("expected missing target_id and bad ts to fail, got %v / %v", items[2].err, items[3].err)
+	}
+
+	ndjson := []byte("{\"target_id\": \"t1\", \"payload\": {\"v\": 1}}\n\nnot json\n{\"target_id\": \"t2\", \"payload\": [1]}\n")
+	items, err = parseBulkRecords(ndjson, "application/x-ndjson", now)
+	if err != nil {
+		t.Fatalf("parse ndjson: %v", err)
+	}
+	if len(items) != 3 || items[0].err != nil || items[1].err == nil || items[2].err != nil {
+		t.Fatalf("unexpected ndjson items: %+v", items)
+	}
+
+	if _, err := parseBulkRecords([]byte(`[{"target_id": "t1"`), "application/json", now); err == nil {
+		t.Fatal("expected malformed array to fail")
+	}
+}
+
+func TestParseBulkRecordsEventKey(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	body := []byte(`{"target_id": "t1", "event_id": "gw-1:42", "payload": {"v": 1}}
+{"target_id": "t1", "ts": "2025-01-02T03:04:05Z", "payload": {"b": 2, "a": 1}}
+{"target_id": "t1", "ts": "2025-01-02T03:04:05Z", "payload": {"a": 1, "b": 2}}
+{"target_id": "t1", "payload": {"v": 1}}
+{"target_id": "t1", "event_id": "` + strings.Repeat("x", 201) + `", "payload": {"v": 1}}
+`)
+	items, err := parseBulkRecords(body, "application/x-ndjson", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items[0].key != "id:gw-1:42" {
+		t.Errorf("event id key = %q", items[0].key)
+	}
+	if items[1].key == "" || items[1].key != items[2].key {
+		t.Errorf("same record with reordered payload keys should share a key: %q / %q", items[1].key, items[2].key)
+	}
+	if items[3].key != "" {
+		t.Errorf("record without event id or ts should not be deduplicated, got %q", items[3].key)
+	}
+	if items[4].err == nil {
+		t.Error("expected overlong event_id to fail")
+	}
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/tmidb/tmidb-core/internal/busconsumer"
+	"github.com/tmidb/tmidb-core/internal/cache"
+	"github.com/tmidb/tmidb-core/internal/database"
+)
+
+// cdcFlushInterval 변경 이벤트로 모인 캐시 무효화를 적용하는 주기
+const cdcFlushInterval = 500 * time.Millisecond
+
+// changeInvalidator는 변경 이벤트와 무효화 메시지의 카테고리/타겟을 모아 주기적으로 캐시를 무효화합니다.
+// ts_obs 대량 수집 시 이벤트마다 캐시를 훑지 않도록 같은 키는 한 번만 처리합니다.
+type changeInvalidator struct {
+	mu         sync.Mutex
+	categories map[string]struct{}
+	targets    map[string]struct{}
+}
+
+func newChangeInvalidator() *changeInvalidator {
+	return &changeInvalidator{categories: map[string]struct{}{}, targets: map[string]struct{}{}}
+}
+
+func (ci *changeInvalidator) add(event database.ChangeEvent) {
+	ci.addKeys([]string{event.Category}, []string{event.TargetID})
+}
+
+// addKeys는 다른 API 인스턴스가 보낸 카테고리/타겟을 모읍니다
+func (ci *changeInvalidator) addKeys(categories, targets []string) {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+	for _, category := range categories {
+		if category != "" {
+			ci.categories[category] = struct{}{}
+		}
+	}
+	for _, targetID := range targets {
+		if targetID != "" {
+			ci.targets[targetID] = struct{}{}
+		}
+	}
+}
+
+// take는 모인 카테고리와 타겟을 반환하고 비웁니다
+func (ci *changeInvalidator) take() (categories, targets []string) {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+	for c := range ci.categories {
+		categories = append(categories, c)
+	}
+	for t := range ci.targets {
+		targets = append(targets, t)
+	}
+	ci.categories = map[string]struct{}{}
+	ci.targets = map[string]struct{}{}
+	return categories, targets
+}
+
+// 다른 API 인스턴스에 캐시 무효화를, 다른 컴포넌트에 스키마 변경을 알리는 연결 (StartCacheInvalidation 전에는 nil)
+var (
+	cacheSyncConn   *nats.Conn
+	cacheInstanceID = newCacheInstanceID()
+)
+
+// newCacheInstanceID는 이 API 인스턴스를 구분하는 ID를 만듭니다
+func newCacheInstanceID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// invalidateCache는 이 인스턴스의 캐시를 바로 무효화하고, 다른 API 인스턴스에도 알립니다.
+// 다른 인스턴스는 CDC 이벤트로도 무효화하지만, data-manager가 이벤트를 보낼 때까지 기다리지 않도록 직접 보냅니다.
+func invalidateCache(categories, targets []string) {
+	inv := cache.Invalidation{Origin: cacheInstanceID, Categories: categories, Targets: targets}
+	inv.Apply(dataCache)
+
+	// 공유 캐시(Redis)는 방금 지운 것으로 충분함
+	if cacheSyncConn == nil || sharedDataCache() {
+		return
+	}
+	payload, _ := json.Marshal(inv)
+	if err := cacheSyncConn.Publish(cache.InvalidationSubject, payload); err != nil {
+		log.Printf("⚠️ 캐시 무효화 메시지 발행 실패: %v", err)
+	}
+}
+
+// StartCacheInvalidation은 CDC 변경 이벤트와 다른 API 인스턴스의 무효화 메시지를 구독하여
+// 다른 컴포넌트가 쓴 데이터의 캐시를 무효화합니다. NATS 서버가 아직 없으면 백그라운드에서 계속 연결을 시도합니다.
+func StartCacheInvalidation(natsURL string) (*nats.Conn, error) {
+	nc, err := nats.Connect(natsURL, nats.RetryOnFailedConnect(true), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+
+	invalidator := newChangeInvalidator()
+	_, err = nc.Subscribe(busconsumer.ChangeSubjectPrefix+".>", func(msg *nats.Msg) {
+		var event database.ChangeEvent
+		if err := json.Unmarshal(msg.Data, &event); err != nil {
+			log.Printf("⚠️ CDC 이벤트 해석 실패: %v", err)
+			return
+		}
+		invalidator.add(event)
+	})
+	if err == nil {
+		_, err = nc.Subscribe(cache.InvalidationSubject, func(msg *nats.Msg) {
+			var inv cache.Invalidation
+			if err := json.Unmarshal(msg.Data, &inv); err != nil {
+				log.Printf("⚠️ 캐시 무효화 메시지 해석 실패: %v", err)
+				return
+			}
+			if inv.Origin == cacheInstanceID {
+				return
+			}
+			invalidator.addKeys(inv.Categories, inv.Targets)
+		})
+	}
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+	cacheSyncConn = nc
+
+	go func() {
+		ticker := time.NewTicker(cdcFlushInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if nc.IsClosed() {
+				return
+			}
+			if dataCache == nil {
+				continue
+			}
+			categories, targets := invalidator.take()
+			cache.Invalidation{Categories: categories, Targets: targets}.Apply(dataCache)
+		}
+	}()
+
+	return nc, nil
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+)
+
+func TestChangeInvalidatorCoalesces(t *testing.T) {
+	ci := newChangeInvalidator()
+	for i := 0; i < 100; i++ {
+		ci.add(database.ChangeEvent{Table: "ts_obs", Operation: "insert", TargetID: "t1", Category: "temperature"})
+	}
+	ci.add(database.ChangeEvent{Table: "target", Operation: "update", TargetID: "t2"})
+
+	categories, targets := ci.take()
+	sort.Strings(targets)
+	if len(categories) != 1 || categories[0] != "temperature" || len(targets) != 2 || targets[0] != "t1" || targets[1] != "t2" {
+		t.Fatalf("unexpected invalidation set: %v %v", categories, targets)
+	}
+	if categories, targets := ci.take(); len(categories) != 0 || len(targets) != 0 {
+		t.Fatalf("take should reset the set, got %v %v", categories, targets)
+	}
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/cluster"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// supervisorSocketPath는 로컬 슈퍼바이저의 IPC 소켓 경로를 반환합니다.
+func supervisorSocketPath() string {
+	if path := os.Getenv("TMIDB_SOCKET_PATH"); path != "" {
+		return path
+	}
+	return ipc.DefaultSocketPath
+}
+
+// GetClusterStatus는 로컬 슈퍼바이저가 알고 있는 모든 노드의 상태를 반환합니다.
+func GetClusterStatus(c *fiber.Ctx) error {
+	client := ipc.NewClientWithOptions(supervisorSocketPath(), ipc.ClientOptions{
+		ConnectTimeout: 2 * time.Second,
+		RequestTimeout: 10 * time.Second,
+	})
+	defer client.Close()
+
+	resp, err := client.SendMessage(ipc.MessageTypeClusterStatus, nil)
+	if err != nil {
+		log.Printf("could not reach supervisor: %v", err)
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "supervisor unavailable"})
+	}
+	if !resp.Success {
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": resp.Error})
+	}
+
+	var status cluster.Status
+	raw, _ := json.Marshal(resp.Data)
+	if err := json.Unmarshal(raw, &status); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "invalid cluster status"})
+	}
+	return c.JSON(status)
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -11,16 +12,49 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/tmidb/tmidb-core/internal/api/middleware"
 	"github.com/tmidb/tmidb-core/internal/cache"
+	"github.com/tmidb/tmidb-core/internal/config"
 	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/query"
+	"github.com/tmidb/tmidb-core/internal/schema"
 )
 
 // 전역 캐시 인스턴스
-var dataCache *cache.MemoryCache
-
-// InitDataCache는 데이터 캐시를 초기화합니다
-func InitDataCache() {
+var dataCache cache.Cache
+
+// InitDataCache는 설정한 백엔드로 데이터 캐시를 초기화합니다.
+// Redis 캐시를 열지 못하면 인스턴스별 메모리 캐시를 쓰고 오류를 반환합니다.
+func InitDataCache(cfg *config.Config) error {
+	redisCache, err := openRedisCache(cfg)
+	if redisCache != nil {
+		dataCache = redisCache
+		return nil
+	}
 	// 최대 10000개 항목, 기본 TTL 5분
 	dataCache = cache.NewMemoryCache(10000, 5*time.Minute)
+	return err
+}
+
+// openRedisCache는 CACHE_BACKEND=redis일 때 공유 캐시에 연결합니다 (memory면 nil)
+func openRedisCache(cfg *config.Config) (*cache.RedisCache, error) {
+	switch cfg.CacheBackend {
+	case "", cache.BackendMemory:
+		return nil, nil
+	case cache.BackendRedis:
+		opts, err := cache.ParseRedisURL(cfg.CacheRedisURL)
+		if err != nil {
+			return nil, err
+		}
+		opts.Prefix = cfg.CacheKeyPrefix
+		return cache.NewRedisCache(opts, 5*time.Minute)
+	default:
+		return nil, fmt.Errorf("알 수 없는 CACHE_BACKEND: %s (memory 또는 redis)", cfg.CacheBackend)
+	}
+}
+
+// sharedDataCache는 데이터 캐시를 여러 API 인스턴스가 함께 쓰는지 확인합니다
+func sharedDataCache() bool {
+	_, ok := dataCache.(*cache.RedisCache)
+	return ok
 }
 
 // StandardResponse는 표준화된 API 응답 형식입니다
@@ -48,6 +82,10 @@ type PaginationMeta struct {
 	TotalRecords int  `json:"total_records"`
 	HasNext      bool `json:"has_next"`
 	HasPrev      bool `json:"has_prev"`
+
+	// 커서 페이징: mode가 "cursor"이면 total_*와 current_page는 계산하지 않음
+	Mode       string `json:"mode"`                  // offset 또는 cursor
+	NextCursor string `json:"next_cursor,omitempty"` // 다음 페이지의 cursor 값 (마지막 페이지면 없음)
 }
 
 // VersionMeta는 버전 메타데이터입니다
@@ -69,6 +107,15 @@ type ApiError struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	Details string `json:"details,omitempty"`
+
+	// 스키마 검증 실패 시 필드별 오류
+	Fields []schema.FieldError `json:"fields,omitempty"`
+
+	// 트랜잭션 실패 시 작업별 오류
+	Operations []TransactionOpError `json:"operations,omitempty"`
+
+	// 리비전 충돌 시 문서의 현재 리비전 (0이면 문서 없음)
+	CurrentRevision *int `json:"current_revision,omitempty"`
 }
 
 // CategoryData는 카테고리 데이터 구조입니다
@@ -76,6 +123,7 @@ type CategoryData struct {
 	TargetID  string                 `json:"target_id"`
 	Category  string                 `json:"category"`
 	Version   string                 `json:"version"`
+	Revision  int                    `json:"revision"` // 문서를 바꿀 때마다 1씩 오름 (If-Match, expected_revision)
 	Data      map[string]interface{} `json:"data"`
 	CreatedAt time.Time              `json:"created_at"`
 	UpdatedAt time.Time              `json:"updated_at"`
@@ -100,11 +148,21 @@ func GetCategoryData(c *fiber.Ctx) error {
 	if err != nil {
 		return sendErrorResponse(c, "QUERY_PARSE_ERROR", err.Error(), "")
 	}
+	filterRecorder.Record(orgID, category, queryFilters)
+
+	// 커서 페이징 (큰 카테고리를 끝까지 훑을 때)
+	if paginationCtx.UseCursor {
+		if len(queryFilters.Sort) > 0 {
+			return sendErrorResponse(c, "QUERY_PARSE_ERROR", "sort is not supported with cursor pagination", "")
+		}
+		return getCategoryDataByCursor(c, startTime, orgID, category, versionCtx, paginationCtx, queryFilters)
+	}
 
 	// 캐시 키 생성
-	cacheKey := fmt.Sprintf("category:%s:org:%d:v:%s:page:%d:size:%d:filters:%v",
+	cacheKey := fmt.Sprintf("category:%s:org:%s:v:%s:page:%d:size:%d:filters:%s:sort:%s:fields:%s:archived:%t",
 		category, orgID, versionCtx.RequestedVersion,
-		paginationCtx.Page, paginationCtx.PageSize, queryFilters)
+		paginationCtx.Page, paginationCtx.PageSize, queryFilters.Key(), queryFilters.SortString(),
+		queryFilters.Fields, queryFilters.IncludeArchived)
 
 	var data []CategoryData
 	var totalCount int
@@ -145,6 +203,12 @@ func GetCategoryData(c *fiber.Ctx) error {
 		}
 	}
 
+	// 바뀐 행이 없으면 본문 없이 304
+	etag := categoryETag(fmt.Sprintf("%s|%d", versionCtx.RequestedVersion, totalCount), data...)
+	if notModified(c, etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
 	// 메타데이터 구성
 	meta := &Meta{
 		Pagination: &PaginationMeta{
@@ -154,6 +218,7 @@ func GetCategoryData(c *fiber.Ctx) error {
 			TotalPages:   (totalCount + paginationCtx.PageSize - 1) / paginationCtx.PageSize,
 			HasNext:      paginationCtx.Page*paginationCtx.PageSize < totalCount,
 			HasPrev:      paginationCtx.Page > 1,
+			Mode:         "offset",
 		},
 		Version: &VersionMeta{
 			RequestedVersion: versionCtx.RequestedVersion,
@@ -161,13 +226,65 @@ func GetCategoryData(c *fiber.Ctx) error {
 			IsMultiVersion:   versionCtx.IsMultiVersion,
 		},
 		Query: &QueryMeta{
-			Filters:     queryFilters,
+			Filters:     queryFilters.Strings(),
 			ProcessTime: time.Since(startTime).String(),
 			CacheHit:    cacheHit,
 		},
 	}
 
-	return sendSuccessResponse(c, data, meta)
+	return sendSuccessResponse(c, projectCategoryData(data, queryFilters.Fields), meta)
+}
+
+// getCategoryDataByCursor는 커서 다음 행부터 한 페이지를 조회합니다.
+// 전체 개수를 세지 않고, 한 행을 더 읽어 다음 페이지가 있는지 확인합니다.
+func getCategoryDataByCursor(c *fiber.Ctx, startTime time.Time, orgID, category string,
+	versionCtx *middleware.VersionContext, paginationCtx *middleware.PaginationContext, filters *query.Query) error {
+
+	var after *categoryCursor
+	if paginationCtx.Cursor != "" {
+		var err error
+		if after, err = decodeCursor(paginationCtx.Cursor); err != nil {
+			return sendErrorResponse(c, "INVALID_REQUEST", err.Error(), "")
+		}
+	}
+
+	data, err := getCategoryPageFromDB(orgID, category, versionCtx, paginationCtx.PageSize+1, after, filters)
+	if err != nil {
+		return sendErrorResponse(c, "DATABASE_ERROR", err.Error(), "")
+	}
+
+	pagination := &PaginationMeta{
+		PageSize: paginationCtx.PageSize,
+		HasPrev:  after != nil,
+		Mode:     "cursor",
+	}
+	if len(data) > paginationCtx.PageSize {
+		data = data[:paginationCtx.PageSize]
+		last := data[len(data)-1]
+		pagination.HasNext = true
+		pagination.NextCursor = encodeCursor(categoryCursor{UpdatedAt: last.UpdatedAt, TargetID: last.TargetID})
+	}
+
+	// 다음 페이지 유무도 응답에 들어가므로 ETag에 포함
+	etag := categoryETag(fmt.Sprintf("%s|next:%t", versionCtx.RequestedVersion, pagination.HasNext), data...)
+	if notModified(c, etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	meta := &Meta{
+		Pagination: pagination,
+		Version: &VersionMeta{
+			RequestedVersion: versionCtx.RequestedVersion,
+			ActualVersions:   versionCtx.TargetVersions,
+			IsMultiVersion:   versionCtx.IsMultiVersion,
+		},
+		Query: &QueryMeta{
+			Filters:     filters.Strings(),
+			ProcessTime: time.Since(startTime).String(),
+		},
+	}
+
+	return sendSuccessResponse(c, projectCategoryData(data, filters.Fields), meta)
 }
 
 // GetTargetByID는 특정 타겟의 카테고리 데이터를 조회합니다
@@ -192,6 +309,10 @@ func GetTargetByID(c *fiber.Ctx) error {
 		return sendErrorResponse(c, "DATABASE_ERROR", err.Error(), "")
 	}
 
+	if notModified(c, revisionETag(data.Revision)) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
 	meta := &Meta{
 		Version: &VersionMeta{
 			RequestedVersion: versionCtx.RequestedVersion,
@@ -229,41 +350,47 @@ func CreateOrUpdateTargetData(c *fiber.Ctx) error {
 		}
 	}
 
-	// 카테고리 스키마 검증
-	schemaValid, err := validateCategorySchema(orgID, category, version, requestData)
+	expected, err := expectedRevision(c)
 	if err != nil {
-		return sendErrorResponse(c, "SCHEMA_VALIDATION_ERROR", err.Error(), "")
+		return sendErrorResponse(c, "INVALID_REQUEST", err.Error(), "")
 	}
-	if !schemaValid {
-		return sendErrorResponse(c, "SCHEMA_VALIDATION_FAILED",
-			"Data does not match category schema", "")
+
+	// 카테고리 스키마 검증
+	if err := validateCategorySchema(orgID, category, version, requestData); err != nil {
+		return sendSchemaErrorResponse(c, err)
 	}
 
-	// 데이터 저장
-	err = saveTargetData(orgID, targetID, category, version, requestData)
-	if err != nil {
+	// 데이터 저장 (리비전이 기대와 다르면 409)
+	saved, err := saveTargetData(orgID, targetID, category, version, targetActor(c), requestData, expected)
+	var conflict *database.RevisionConflictError
+	switch {
+	case errors.As(err, &conflict):
+		return sendRevisionConflict(c, conflict)
+	case errors.Is(err, database.ErrTargetShared), errors.Is(err, database.ErrInRecycleBin):
+		return sendErrorResponse(c, "TARGET_CONFLICT", err.Error(), "")
+	case err != nil:
 		return sendErrorResponse(c, "DATABASE_ERROR", err.Error(), "")
 	}
 
-	// 캐시 무효화 (데이터 변경 시)
-	if dataCache != nil {
-		dataCache.InvalidateCategory(category)
-		dataCache.InvalidateTarget(targetID)
-	}
+	// 캐시 무효화 (데이터 변경 시, 다른 API 인스턴스에도 알림)
+	invalidateCache([]string{category}, []string{targetID})
 
 	// 응답 데이터 구성
 	responseData := &CategoryData{
 		TargetID:  targetID,
 		Category:  category,
 		Version:   version,
+		Revision:  saved.Revision,
 		Data:      requestData,
-		UpdatedAt: time.Now(),
+		CreatedAt: saved.CreatedAt,
+		UpdatedAt: saved.UpdatedAt,
 	}
+	c.Set(fiber.HeaderETag, revisionETag(saved.Revision))
 
 	return sendSuccessResponse(c, responseData, nil)
 }
 
-// DeleteTargetData는 타겟 데이터를 삭제합니다
+// DeleteTargetData는 타겟 데이터를 삭제합니다 (휴지통이 켜져 있으면 휴지통으로 보냄)
 func DeleteTargetData(c *fiber.Ctx) error {
 	targetID := c.Params("target_id")
 	category := c.Params("category")
@@ -272,8 +399,14 @@ func DeleteTargetData(c *fiber.Ctx) error {
 		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
 	}
 
-	// 삭제 실행
-	rowsAffected, err := deleteTargetData(orgID, targetID, category)
+	// 삭제 실행 (휴지통이 켜져 있으면 휴지통으로)
+	soft := softDeleteEnabled()
+	var rowsAffected int64
+	if soft {
+		rowsAffected, err = database.SoftDeleteTargetCategoryData(database.GetDB(), orgID, targetID, category, targetActor(c))
+	} else {
+		rowsAffected, err = deleteTargetData(orgID, targetID, category)
+	}
 	if err != nil {
 		return sendErrorResponse(c, "DATABASE_ERROR", err.Error(), "")
 	}
@@ -283,41 +416,47 @@ func DeleteTargetData(c *fiber.Ctx) error {
 			fmt.Sprintf("Target %s not found in category %s", targetID, category), "")
 	}
 
-	// 캐시 무효화 (데이터 삭제 시)
-	if dataCache != nil {
-		dataCache.InvalidateCategory(category)
-		dataCache.InvalidateTarget(targetID)
-	}
+	// 캐시 무효화 (데이터 삭제 시, 다른 API 인스턴스에도 알림)
+	invalidateCache([]string{category}, []string{targetID})
 
-	return sendSuccessResponse(c, fiber.Map{
-		"target_id":  targetID,
-		"category":   category,
-		"deleted":    true,
-		"deleted_at": time.Now(),
-	}, nil)
+	now := time.Now()
+	response := fiber.Map{
+		"target_id":    targetID,
+		"category":     category,
+		"deleted":      true,
+		"deleted_at":   now,
+		"soft_deleted": soft,
+	}
+	if soft {
+		response["purge_at"] = now.Add(recycleBinRetention)
+	}
+	return sendSuccessResponse(c, response, nil)
 }
 
 // 헬퍼 함수들
 
 // getCategoryDataFromDB는 데이터베이스에서 카테고리 데이터를 조회합니다
-func getCategoryDataFromDB(orgID int, category string, versionCtx *middleware.VersionContext,
-	paginationCtx *middleware.PaginationContext, filters []string) ([]CategoryData, int, error) {
+func getCategoryDataFromDB(orgID, category string, versionCtx *middleware.VersionContext,
+	paginationCtx *middleware.PaginationContext, filters *query.Query) ([]CategoryData, int, error) {
 
-	db := database.GetDB()
+	db := database.GetReadDB()
 
 	// COUNT 쿼리 (총 개수)
-	countQuery := buildCountQuery(category, versionCtx, filters)
-	var totalCount int
-	err := db.QueryRow(countQuery, orgID).Scan(&totalCount)
+	countQuery, args, err := buildCountQuery(orgID, category, versionCtx, filters)
 	if err != nil {
 		return nil, 0, err
 	}
+	var totalCount int
+	if err := db.QueryRow(countQuery, args...).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
 
 	// 데이터 조회 쿼리
-	dataQuery := buildDataQuery(category, versionCtx, paginationCtx, filters)
-
-	offset := (paginationCtx.Page - 1) * paginationCtx.PageSize
-	rows, err := db.Query(dataQuery, orgID, paginationCtx.PageSize, offset)
+	dataQuery, args, err := buildDataQuery(orgID, category, versionCtx, paginationCtx, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err := db.Query(dataQuery, args...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -330,7 +469,7 @@ func getCategoryDataFromDB(orgID int, category string, versionCtx *middleware.Ve
 		var createdAt, updatedAt time.Time
 
 		err := rows.Scan(&item.TargetID, &item.Category, &item.Version,
-			&dataJSON, &createdAt, &updatedAt)
+			&dataJSON, &createdAt, &updatedAt, &item.Revision)
 		if err != nil {
 			continue
 		}
@@ -348,11 +487,41 @@ func getCategoryDataFromDB(orgID int, category string, versionCtx *middleware.Ve
 	return results, totalCount, nil
 }
 
+// getCategoryPageFromDB는 커서(없으면 처음) 다음 행부터 최대 limit개를 조회합니다
+func getCategoryPageFromDB(orgID, category string, versionCtx *middleware.VersionContext,
+	limit int, after *categoryCursor, filters *query.Query) ([]CategoryData, error) {
+
+	pageQuery, args, err := buildCursorQuery(orgID, category, versionCtx, filters, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := database.GetReadDB().Query(pageQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]CategoryData, 0, limit)
+	for rows.Next() {
+		var item CategoryData
+		var dataJSON string
+		if err := rows.Scan(&item.TargetID, &item.Category, &item.Version,
+			&dataJSON, &item.CreatedAt, &item.UpdatedAt, &item.Revision); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(dataJSON), &item.Data); err != nil {
+			return nil, err
+		}
+		results = append(results, item)
+	}
+	return results, rows.Err()
+}
+
 // getTargetDataFromDB는 특정 타겟의 데이터를 조회합니다
-func getTargetDataFromDB(orgID int, targetID, category string,
+func getTargetDataFromDB(orgID, targetID, category string,
 	versionCtx *middleware.VersionContext) (*CategoryData, error) {
 
-	db := database.GetDB()
+	db := database.GetReadDB()
 
 	// 버전별 쿼리 구성
 	var query string
@@ -361,18 +530,18 @@ func getTargetDataFromDB(orgID int, targetID, category string,
 	if versionCtx.RequestedVersion == "all" {
 		// 모든 버전 조회
 		query = `
-			SELECT target_id, category_name, schema_version, category_data, created_at, updated_at
+			SELECT target_id, category_name, schema_version, category_data, created_at, updated_at, revision
 			FROM target_categories 
-			WHERE org_id = $1 AND target_id = $2 AND category_name = $3
+			WHERE org_id = $1 AND target_id = $2 AND category_name = $3 AND deleted_at IS NULL
 			ORDER BY schema_version DESC
 		`
 		args = []interface{}{orgID, targetID, category}
 	} else if versionCtx.RequestedVersion == "latest" {
 		// 최신 버전만 조회
 		query = `
-			SELECT target_id, category_name, schema_version, category_data, created_at, updated_at
+			SELECT target_id, category_name, schema_version, category_data, created_at, updated_at, revision
 			FROM target_categories 
-			WHERE org_id = $1 AND target_id = $2 AND category_name = $3
+			WHERE org_id = $1 AND target_id = $2 AND category_name = $3 AND deleted_at IS NULL
 			ORDER BY schema_version DESC 
 			LIMIT 1
 		`
@@ -381,9 +550,9 @@ func getTargetDataFromDB(orgID int, targetID, category string,
 		// 특정 버전 조회
 		version := strings.TrimPrefix(versionCtx.RequestedVersion, "v")
 		query = `
-			SELECT target_id, category_name, schema_version, category_data, created_at, updated_at
+			SELECT target_id, category_name, schema_version, category_data, created_at, updated_at, revision
 			FROM target_categories 
-			WHERE org_id = $1 AND target_id = $2 AND category_name = $3 AND schema_version = $4
+			WHERE org_id = $1 AND target_id = $2 AND category_name = $3 AND schema_version = $4 AND deleted_at IS NULL
 		`
 		args = []interface{}{orgID, targetID, category, version}
 	}
@@ -394,7 +563,7 @@ func getTargetDataFromDB(orgID int, targetID, category string,
 
 	err := db.QueryRow(query, args...).Scan(
 		&result.TargetID, &result.Category, &schemaVersion,
-		&dataJSON, &result.CreatedAt, &result.UpdatedAt)
+		&dataJSON, &result.CreatedAt, &result.UpdatedAt, &result.Revision)
 
 	if err != nil {
 		return nil, err
@@ -454,9 +623,18 @@ func getStatusCodeFromErrorCode(code string) int {
 		return 401
 	case "AUTH_PERMISSION_DENIED", "AUTH_CATEGORY_DENIED":
 		return 403
-	case "TARGET_NOT_FOUND", "CATEGORY_NOT_FOUND":
+	case "TARGET_NOT_FOUND", "CATEGORY_NOT_FOUND", "FILE_NOT_FOUND", "WEBHOOK_NOT_FOUND":
 		return 404
-	case "INVALID_JSON", "SCHEMA_VALIDATION_ERROR", "SCHEMA_VALIDATION_FAILED", "QUERY_PARSE_ERROR":
+	case "TARGET_CONFLICT", "CONFIRMATION_INVALID", "UPLOAD_CONFLICT", "REVISION_CONFLICT":
+		return 409
+	case "FILE_TOO_LARGE":
+		return 413
+	case "UNSUPPORTED_MEDIA_TYPE":
+		return 415
+	case "STORAGE_ERROR":
+		return 502
+	case "INVALID_JSON", "INVALID_REQUEST", "SCHEMA_VALIDATION_ERROR", "SCHEMA_VALIDATION_FAILED", "QUERY_PARSE_ERROR",
+		"TRANSACTION_INVALID", "SQL_NOT_ALLOWED", "SQL_ERROR", "SQL_TIMEOUT":
 		return 400
 	case "DATABASE_ERROR":
 		return 500
@@ -46,7 +46,7 @@ func GetTargetDetails(c *fiber.Ctx) error {
 		SELECT t.name, tc.category_data, tc.updated_at
 		FROM target_categories tc
 		JOIN target t ON tc.target_id = t.target_id
-		WHERE t.target_id = $1 AND tc.category_name = $2
+		WHERE t.target_id = $1 AND tc.category_name = $2 AND tc.deleted_at IS NULL
 	`, targetID, category).Scan(&targetName, &categoryData, &updatedAt)
 
 	if err != nil {
@@ -111,10 +111,16 @@ func InsertTimeSeriesData(c *fiber.Ctx) error {
 	}
 
 	// Payload JSON 유효성 검사
-	if !json.Valid([]byte(req.Payload)) {
+	var payload interface{}
+	if err := json.Unmarshal([]byte(req.Payload), &payload); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Payload is not valid JSON"})
 	}
 
+	// 카테고리 스키마 검증
+	if err := validateTimeSeriesPayload(req.TargetID, req.CategoryName, payload); err != nil {
+		return sendSchemaErrorResponse(c, err)
+	}
+
 	_, err := database.DB.Exec("SELECT insert_ts_obs($1, $2, $3, $4)",
 		req.TargetID, req.CategoryName, req.Ts, req.Payload)
 
@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"regexp"
+	"io"
 	"strconv"
 	"strings"
 	"time"
@@ -11,172 +15,272 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/tmidb/tmidb-core/internal/api/middleware"
 	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/query"
+	"github.com/tmidb/tmidb-core/internal/schema"
+	"github.com/tmidb/tmidb-core/internal/usage"
 )
 
-// parseQueryFilters는 쿼리 파라미터를 파싱합니다
-func parseQueryFilters(c *fiber.Ctx) ([]string, error) {
-	var filters []string
+// filterRecorder 인덱스 추천용 필터 사용 기록 (InitFilterUsage 전에는 기록하지 않음)
+var filterRecorder *usage.FilterRecorder
+
+// InitFilterUsage는 카테고리 데이터 조회의 필터 경로를 기록할 FilterRecorder를 설정합니다
+func InitFilterUsage(recorder *usage.FilterRecorder) {
+	filterRecorder = recorder
+}
 
-	// 기존 query_parser.go의 로직을 확장
-	queries := c.Context().QueryArgs()
+// parseQueryFilters는 filter, sort, fields 쿼리 파라미터를 파싱합니다 (문법은 internal/query 참고).
+// 예약되지 않은 다른 파라미터는 이전 방식대로 데이터 필드 조건으로 읽습니다 (?ward=ICU, ?bp>=120).
+// extraReserved는 엔드포인트가 따로 쓰는 파라미터입니다 (예: 내보내기의 format).
+func parseQueryFilters(c *fiber.Ctx, extraReserved ...string) (*query.Query, error) {
+	var filters []string
 
 	// 예약된 파라미터 제외
 	reservedParams := map[string]bool{
-		"page":      true,
-		"page_size": true,
-		"auto_size": true,
-		"sort":      true,
-		"order":     true,
-	}
-
-	queries.VisitAll(func(key, value []byte) {
-		keyStr := string(key)
-		valueStr := string(value)
-
-		if reservedParams[keyStr] {
-			return
-		}
-
-		// 복잡한 쿼리 파싱 (기존 코드 활용)
-		filter := parseComplexFilter(keyStr, valueStr)
-		if filter != "" {
-			filters = append(filters, filter)
+		"page":             true,
+		"page_size":        true,
+		"auto_size":        true,
+		"cursor":           true,
+		"fields":           true,
+		"sort":             true,
+		"order":            true,
+		"include_archived": true,
+	}
+	for _, key := range extraReserved {
+		reservedParams[key] = true
+	}
+
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		keyStr, valueStr := string(key), string(value)
+		switch {
+		case keyStr == "filter":
+			filters = append(filters, valueStr)
+		case reservedParams[keyStr]:
+		case valueStr == "":
+			// ?bp>120 처럼 식 전체가 키에 들어간 경우
+			filters = append(filters, keyStr)
+		default:
+			filters = append(filters, keyStr+"="+valueStr)
 		}
 	})
 
-	return filters, nil
-}
-
-// parseComplexFilter는 복잡한 필터를 파싱합니다
-func parseComplexFilter(key, value string) string {
-	// 기존 query_parser.go에서 사용하던 패턴들
-
-	// 배열 연산: tags[]contains=urgent
-	if strings.Contains(key, "[]") {
-		arrayField := strings.Replace(key, "[]", "", 1)
-		return fmt.Sprintf("%s ARRAY OPERATION '%s'", arrayField, value)
+	q, err := query.Parse(filters, c.Query("sort"))
+	if err != nil {
+		return nil, err
 	}
-
-	// 비교 연산자: age>18, bp>=120
-	comparisonOps := []string{">=", "<=", "!=", ">", "<", "="}
-	for _, op := range comparisonOps {
-		if strings.Contains(key, op) {
-			parts := strings.Split(key, op)
-			if len(parts) == 2 {
-				field := parts[0]
-				return fmt.Sprintf("%s %s '%s'", field, op, value)
-			}
-		}
+	if q.Fields, err = query.ParseFields(c.Query("fields")); err != nil {
+		return nil, err
 	}
+	q.IncludeArchived = c.QueryBool("include_archived")
+	return q, nil
+}
 
-	// 존재 확인: field.exists=true
-	if strings.HasSuffix(key, ".exists") {
-		field := strings.TrimSuffix(key, ".exists")
-		if value == "true" {
-			return fmt.Sprintf("%s IS NOT NULL", field)
-		} else {
-			return fmt.Sprintf("%s IS NULL", field)
+// projectCategoryData는 fields로 고른 최상위 필드만 응답에 남깁니다.
+// 데이터 경로는 이미 SQL에서 골랐으므로 여기서는 항목의 필드만 고릅니다.
+func projectCategoryData(items []CategoryData, fields *query.Projection) interface{} {
+	if fields == nil {
+		return items
+	}
+	projected := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		values := map[string]interface{}{
+			"target_id":  item.TargetID,
+			"category":   item.Category,
+			"version":    item.Version,
+			"revision":   item.Revision,
+			"data":       item.Data,
+			"created_at": item.CreatedAt,
+			"updated_at": item.UpdatedAt,
+		}
+		out := make(map[string]interface{}, len(fields.Columns))
+		for _, column := range fields.Columns {
+			out[column] = values[column]
 		}
+		projected = append(projected, out)
 	}
+	return projected
+}
 
-	// 기본 등등 비교
-	return fmt.Sprintf("%s = '%s'", key, value)
+// categoryDataColumns는 카테고리 데이터 조회 컬럼입니다 (fields가 있으면 category_data 대신 고른 경로만)
+func categoryDataColumns(q *query.Query) string {
+	return "target_id, category_name, schema_version::text, (" + q.DataSelect() + ")::text, created_at, updated_at, revision"
 }
 
-// buildCountQuery는 COUNT 쿼리를 생성합니다
-func buildCountQuery(category string, versionCtx *middleware.VersionContext, filters []string) string {
-	baseQuery := "SELECT COUNT(*) FROM target_categories WHERE org_id = $1 AND category_name = '" + category + "'"
+// defaultCategoryOrder 기본 정렬 (최신 순, 같은 시각이면 target_id 순). 커서 페이징은 이 순서만 지원합니다.
+const defaultCategoryOrder = "updated_at DESC, target_id DESC"
+
+// buildCategoryWhere는 조직, 카테고리, 버전, 필터 조건을 문에 더합니다
+func buildCategoryWhere(s *query.Select, orgID, category string,
+	versionCtx *middleware.VersionContext, q *query.Query) error {
+
+	// 휴지통에 있는 문서는 항상 제외
+	s.Where(s.Eq("org_id", orgID), s.Eq("category_name", category), "deleted_at IS NULL")
 
 	// 버전 필터 추가
 	if versionCtx.RequestedVersion != "all" && versionCtx.RequestedVersion != "latest" {
-		version := strings.TrimPrefix(versionCtx.RequestedVersion, "v")
-		baseQuery += " AND schema_version = " + version
+		version, err := strconv.Atoi(strings.TrimPrefix(versionCtx.RequestedVersion, "v"))
+		if err != nil {
+			return fmt.Errorf("invalid version %q", versionCtx.RequestedVersion)
+		}
+		s.Where(s.Eq("schema_version", version))
 	}
 
-	// 추가 필터 적용
-	for _, filter := range filters {
-		// JSON 필드 검색을 위한 PostgreSQL JSON 연산자 사용
-		jsonFilter := convertFilterToJSONB(filter)
-		baseQuery += " AND " + jsonFilter
+	// 보관된 타겟은 요청하지 않으면 제외
+	if !q.IncludeArchived {
+		s.Where("NOT EXISTS (SELECT 1 FROM target t WHERE t.target_id = target_categories.target_id AND t.archived_at IS NOT NULL)")
 	}
 
-	return baseQuery
+	// 추가 필터 적용
+	return s.Filter(q)
 }
 
-// buildDataQuery는 데이터 조회 쿼리를 생성합니다
-func buildDataQuery(category string, versionCtx *middleware.VersionContext,
-	paginationCtx *middleware.PaginationContext, filters []string) string {
-
-	baseQuery := `
-		SELECT target_id, category_name, schema_version::text, category_data::text, created_at, updated_at 
-		FROM target_categories 
-		WHERE org_id = $1 AND category_name = '` + category + `'`
+// buildCountQuery는 COUNT 쿼리와 인자를 생성합니다
+func buildCountQuery(orgID, category string, versionCtx *middleware.VersionContext,
+	q *query.Query) (string, []interface{}, error) {
 
-	// 버전 필터 추가
-	if versionCtx.RequestedVersion != "all" && versionCtx.RequestedVersion != "latest" {
-		version := strings.TrimPrefix(versionCtx.RequestedVersion, "v")
-		baseQuery += " AND schema_version = " + version
+	s := query.NewSelect("COUNT(*)", "target_categories")
+	if err := buildCategoryWhere(s, orgID, category, versionCtx, q); err != nil {
+		return "", nil, err
 	}
+	stmt, args := s.SQL()
+	return stmt, args, nil
+}
 
-	// 추가 필터 적용
-	for _, filter := range filters {
-		jsonFilter := convertFilterToJSONB(filter)
-		baseQuery += " AND " + jsonFilter
+// buildDataQuery는 오프셋 페이징 데이터 조회 쿼리와 인자를 생성합니다
+func buildDataQuery(orgID, category string, versionCtx *middleware.VersionContext,
+	paginationCtx *middleware.PaginationContext, q *query.Query) (string, []interface{}, error) {
+
+	s := query.NewSelect(categoryDataColumns(q), "target_categories")
+	if err := buildCategoryWhere(s, orgID, category, versionCtx, q); err != nil {
+		return "", nil, err
 	}
+	offset := (paginationCtx.Page - 1) * paginationCtx.PageSize
+	stmt, args := s.OrderBy(q.OrderBy(defaultCategoryOrder, "target_id DESC")).
+		Limit(paginationCtx.PageSize).Offset(offset).SQL()
+	return stmt, args, nil
+}
 
-	// 정렬 (최신 순)
-	baseQuery += " ORDER BY updated_at DESC"
+// buildCursorQuery는 커서 페이징 쿼리와 인자를 생성합니다.
+// 정렬 순서에서 커서 다음 행부터 읽으므로 OFFSET처럼 앞의 행을 건너뛰는 비용이 없습니다.
+func buildCursorQuery(orgID, category string, versionCtx *middleware.VersionContext,
+	q *query.Query, after *categoryCursor, limit int) (string, []interface{}, error) {
 
-	// 페이징
-	baseQuery += " LIMIT $2 OFFSET $3"
+	s := query.NewSelect(categoryDataColumns(q), "target_categories")
+	if err := buildCategoryWhere(s, orgID, category, versionCtx, q); err != nil {
+		return "", nil, err
+	}
+	if after != nil {
+		s.Where("(updated_at, target_id) < (" + s.Arg(after.UpdatedAt) + "::timestamptz, " + s.Arg(after.TargetID) + "::uuid)")
+	}
+	stmt, args := s.OrderBy(defaultCategoryOrder).Limit(limit).SQL()
+	return stmt, args, nil
+}
 
-	return baseQuery
+// categoryCursor는 커서 페이징에서 마지막으로 받은 행의 위치입니다
+type categoryCursor struct {
+	UpdatedAt time.Time `json:"u"`
+	TargetID  string    `json:"t"`
 }
 
-// convertFilterToJSONB는 필터를 PostgreSQL JSONB 쿼리로 변환합니다
-func convertFilterToJSONB(filter string) string {
-	// 간단한 패턴 매칭으로 JSONB 쿼리 생성
-	// 예: "age > '18'" -> "category_data->>'age'::numeric > 18"
+// encodeCursor는 커서를 URL에 그대로 쓸 수 있는 불투명한 문자열로 만듭니다
+func encodeCursor(cursor categoryCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
 
-	// 숫자 비교 패턴
-	numericPattern := regexp.MustCompile(`(\w+)\s*(>=|<=|!=|>|<|=)\s*'(\d+(?:\.\d+)?)'`)
-	if match := numericPattern.FindStringSubmatch(filter); match != nil {
-		field, op, value := match[1], match[2], match[3]
-		return fmt.Sprintf("(category_data->>'%s')::numeric %s %s", field, op, value)
+// decodeCursor는 encodeCursor로 만든 커서를 읽습니다
+func decodeCursor(value string) (*categoryCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	var cursor categoryCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.UpdatedAt.IsZero() || !uuidPattern.MatchString(cursor.TargetID) {
+		return nil, errors.New("invalid cursor")
 	}
+	return &cursor, nil
+}
 
-	// 문자열 비교 패턴
-	stringPattern := regexp.MustCompile(`(\w+)\s*(=|!=)\s*'([^']+)'`)
-	if match := stringPattern.FindStringSubmatch(filter); match != nil {
-		field, op, value := match[1], match[2], match[3]
-		if op == "!=" {
-			return fmt.Sprintf("category_data->>'%s' <> '%s'", field, value)
-		}
-		return fmt.Sprintf("category_data->>'%s' = '%s'", field, value)
+// categoryETag는 행들의 target_id, updated_at, version으로 약한 ETag를 만듭니다.
+// 응답 본문에는 처리 시간 같은 매번 바뀌는 값이 있어 본문 대신 데이터가 바뀌었는지로 비교합니다.
+// variant에는 같은 URL이라도 응답을 바꾸는 값(요청 버전, 전체 개수)을 넣습니다.
+func categoryETag(variant string, rows ...CategoryData) string {
+	h := sha256.New()
+	io.WriteString(h, variant)
+	for _, row := range rows {
+		fmt.Fprintf(h, "\n%s|%s|%d", row.TargetID, row.Version, row.UpdatedAt.UnixNano())
 	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
 
-	// NULL 체크 패턴
-	if strings.Contains(filter, "IS NOT NULL") {
-		field := strings.Split(filter, " ")[0]
-		return fmt.Sprintf("category_data ? '%s'", field)
+// notModified는 ETag 헤더를 붙이고, If-None-Match가 같은 ETag를 담고 있으면 true를 반환합니다
+func notModified(c *fiber.Ctx, etag string) bool {
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	return etagMatches(c.Get(fiber.HeaderIfNoneMatch), etag)
+}
+
+// revisionETag는 문서 하나의 강한 ETag입니다 (리비전 번호, If-Match로 돌려받음)
+func revisionETag(revision int) string {
+	return `"` + strconv.Itoa(revision) + `"`
+}
+
+// expectedRevision은 쓰기 요청이 기대하는 문서 리비전을 If-Match 헤더나 expected_revision 파라미터에서 읽습니다.
+// 둘 다 없으면 nil(조건 없이 덮어씀)이고, expected_revision=0은 문서가 아직 없어야 한다는 뜻입니다.
+func expectedRevision(c *fiber.Ctx) (*int, error) {
+	var expected *int
+	if value := c.Query("expected_revision"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("expected_revision must be a revision number")
+		}
+		expected = &n
 	}
-	if strings.Contains(filter, "IS NULL") {
-		field := strings.Split(filter, " ")[0]
-		return fmt.Sprintf("NOT category_data ? '%s'", field)
+	if value := strings.TrimSpace(c.Get(fiber.HeaderIfMatch)); value != "" {
+		n, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(value, "W/"), `"`))
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("If-Match must be the ETag of the document, such as \"3\"")
+		}
+		if expected != nil && *expected != n {
+			return nil, fmt.Errorf("If-Match and expected_revision disagree")
+		}
+		expected = &n
 	}
+	return expected, nil
+}
 
-	// 배열 연산 패턴 (간단 구현)
-	if strings.Contains(filter, "ARRAY OPERATION") {
-		// TODO: 복잡한 배열 연산 구현
-		return "true" // 임시로 항상 참 반환
-	}
+// sendRevisionConflict는 409 REVISION_CONFLICT 응답을 현재 리비전과 함께 보냅니다
+func sendRevisionConflict(c *fiber.Ctx, conflict *database.RevisionConflictError) error {
+	if conflict.Current > 0 {
+		c.Set(fiber.HeaderETag, revisionETag(conflict.Current))
+	}
+	response := StandardResponse{
+		Success: false,
+		Error: &ApiError{
+			Code:            "REVISION_CONFLICT",
+			Message:         conflict.Error(),
+			Details:         "Read the document again and retry with its current revision",
+			CurrentRevision: &conflict.Current,
+		},
+		Timestamp: time.Now(),
+		RequestID: c.Get("X-Request-ID", generateRequestID()),
+	}
+	return c.Status(getStatusCodeFromErrorCode("REVISION_CONFLICT")).JSON(response)
+}
 
-	// 기본값 (안전한 처리)
-	return "true"
+// etagMatches는 If-None-Match 목록에 etag가 있는지 약한 비교로 확인합니다
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
 }
 
-// validateCategorySchema는 카테고리 스키마에 대한 데이터 검증을 수행합니다
-func validateCategorySchema(orgID int, category, version string, data map[string]interface{}) (bool, error) {
+// validateCategorySchema는 카테고리 스키마(JSON Schema)로 데이터를 검증합니다.
+// 데이터가 스키마에 맞지 않으면 필드별 오류를 담은 *schema.ValidationError를 반환합니다.
+func validateCategorySchema(orgID, category, version string, data map[string]interface{}) error {
 	db := database.GetDB()
 
 	// 카테고리 스키마 조회
@@ -190,113 +294,87 @@ func validateCategorySchema(orgID int, category, version string, data map[string
 	err := db.QueryRow(query, orgID, category, version).Scan(&schemaJSON)
 	if err != nil {
 		// 스키마가 없으면 기본적으로 허용 (유연한 스키마)
-		return true, nil
+		return nil
 	}
 
-	// JSON 스키마 파싱
-	var schema map[string]interface{}
-	if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
-		return false, fmt.Errorf("invalid schema format: %v", err)
+	compiled, err := schema.Cached(schemaJSON)
+	if err != nil {
+		return fmt.Errorf("invalid schema format: %v", err)
 	}
 
-	// 기본적인 스키마 검증 (실제로는 더 복잡한 JSON Schema 라이브러리 사용 권장)
-	return validateDataAgainstSchema(data, schema), nil
+	return compiled.Validate(data)
 }
 
-// validateDataAgainstSchema는 데이터와 스키마를 비교합니다
-func validateDataAgainstSchema(data, schema map[string]interface{}) bool {
-	// 간단한 스키마 검증 로직
-	properties, hasProperties := schema["properties"].(map[string]interface{})
-	if !hasProperties {
-		return true // 스키마에 properties가 없으면 모든 데이터 허용
-	}
-
-	// 필수 필드 검증
-	if required, hasRequired := schema["required"].([]interface{}); hasRequired {
-		for _, reqField := range required {
-			fieldName := reqField.(string)
-			if _, exists := data[fieldName]; !exists {
-				return false // 필수 필드 누락
-			}
-		}
+// validateTimeSeriesPayload는 ts_obs 페이로드를 타겟이 사용하는 카테고리 스키마로 검증합니다
+func validateTimeSeriesPayload(targetID, category string, payload interface{}) error {
+	definition, err := database.GetTargetCategorySchema(database.GetDB(), targetID, category)
+	if err != nil {
+		// 연결된 스키마가 없으면 허용 (저장 시 외래키로 다시 확인됨)
+		return nil
 	}
 
-	// 타입 검증 (간단 구현)
-	for fieldName, fieldSchema := range properties {
-		if fieldValue, hasField := data[fieldName]; hasField {
-			if fieldSchemaMap, ok := fieldSchema.(map[string]interface{}); ok {
-				if fieldType, hasType := fieldSchemaMap["type"].(string); hasType {
-					if !validateFieldType(fieldValue, fieldType) {
-						return false
-					}
-				}
-			}
-		}
+	compiled, err := schema.Cached(definition)
+	if err != nil {
+		return fmt.Errorf("invalid schema format: %v", err)
 	}
 
-	return true
+	return compiled.Validate(payload)
 }
 
-// validateFieldType은 필드 타입을 검증합니다
-func validateFieldType(value interface{}, expectedType string) bool {
-	switch expectedType {
-	case "string":
-		_, ok := value.(string)
-		return ok
-	case "number":
-		_, ok1 := value.(float64)
-		_, ok2 := value.(int)
-		return ok1 || ok2
-	case "integer":
-		_, ok := value.(int)
-		return ok
-	case "boolean":
-		_, ok := value.(bool)
-		return ok
-	case "array":
-		_, ok := value.([]interface{})
-		return ok
-	case "object":
-		_, ok := value.(map[string]interface{})
-		return ok
-	default:
-		return true // 알 수 없는 타입은 허용
-	}
+// sendSchemaErrorResponse는 스키마 검증 결과에 맞는 에러 응답을 보냅니다.
+// 검증 실패는 필드별 오류 목록을 포함합니다.
+func sendSchemaErrorResponse(c *fiber.Ctx, err error) error {
+	var validationErr *schema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return sendErrorResponse(c, "SCHEMA_VALIDATION_ERROR", err.Error(), "")
+	}
+
+	response := StandardResponse{
+		Success: false,
+		Error: &ApiError{
+			Code:    "SCHEMA_VALIDATION_FAILED",
+			Message: "Data does not match category schema",
+			Fields:  validationErr.Errors,
+		},
+		Timestamp: time.Now(),
+		RequestID: c.Get("X-Request-ID", generateRequestID()),
+	}
+	return c.Status(getStatusCodeFromErrorCode("SCHEMA_VALIDATION_FAILED")).JSON(response)
 }
 
-// saveTargetData는 타겟 데이터를 저장합니다
-func saveTargetData(orgID int, targetID, category, version string, data map[string]interface{}) error {
-	db := database.GetDB()
-
+// saveTargetData는 타겟 데이터를 저장하고 저장한 리비전을 반환합니다.
+// expected가 있으면 문서가 그 리비전일 때만 쓰고, 문서 이력에는 actor가 바꾼 것으로 남습니다.
+func saveTargetData(orgID, targetID, category, version, actor string, data map[string]interface{}, expected *int) (*database.SavedDocument, error) {
 	// JSON 데이터 직렬화
 	dataJSON, err := json.Marshal(data)
 	if err != nil {
-		return fmt.Errorf("failed to marshal data: %v", err)
+		return nil, fmt.Errorf("failed to marshal data: %v", err)
 	}
 
 	versionInt, _ := strconv.Atoi(version)
 
-	// UPSERT 쿼리 (PostgreSQL)
-	query := `
-		INSERT INTO target_categories (org_id, target_id, category_name, schema_version, category_data, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
-		ON CONFLICT (org_id, target_id, category_name, schema_version)
-		DO UPDATE SET 
-			category_data = EXCLUDED.category_data,
-			updated_at = NOW()
-	`
-
-	_, err = db.Exec(query, orgID, targetID, category, versionInt, string(dataJSON))
-	return err
+	tx, err := database.GetDB().Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	if err := database.SetChangeActor(tx, actor); err != nil {
+		return nil, err
+	}
+	saved, err := database.SaveTargetCategoryData(tx, orgID, targetID, category, versionInt, string(dataJSON), expected)
+	if err != nil {
+		return nil, err
+	}
+	return saved, tx.Commit()
 }
 
 // deleteTargetData는 타겟 데이터를 삭제합니다
-func deleteTargetData(orgID int, targetID, category string) (int64, error) {
+func deleteTargetData(orgID, targetID, category string) (int64, error) {
 	db := database.GetDB()
 
 	query := `
 		DELETE FROM target_categories 
-		WHERE org_id = $1 AND target_id = $2 AND category_name = $3
+		WHERE org_id = $1 AND target_id = $2 AND category_name = $3 AND deleted_at IS NULL
 	`
 
 	result, err := db.Exec(query, orgID, targetID, category)
@@ -367,39 +445,23 @@ func InsertTimeSeriesDataHelper(c *fiber.Ctx) error {
 }
 
 // getTimeSeriesFromDB는 시계열 데이터를 조회합니다
-func getTimeSeriesFromDB(orgID int, targetID, category, startTime, endTime, interval string) (interface{}, error) {
-	db := database.GetDB()
+func getTimeSeriesFromDB(orgID, targetID, category, startTime, endTime, interval string) (interface{}, error) {
+	db := database.GetReadDB()
 
 	// TimescaleDB time_bucket 함수 사용
-	query := `
-		SELECT 
-			time_bucket($5::interval, timestamp) as time_bucket,
-			AVG((data->>'value')::numeric) as avg_value,
-			COUNT(*) as count
-		FROM target_timeseries 
-		WHERE org_id = $1 AND target_id = $2 AND category = $3
-	`
-	args := []interface{}{orgID, targetID, category}
-
+	s := query.NewSelect("", "target_timeseries")
+	bucket := "time_bucket(" + s.Arg(interval) + "::interval, timestamp)"
+	s.Where(s.Eq("org_id", orgID), s.Eq("target_id", targetID), s.Eq("category", category))
 	if startTime != "" {
-		query += " AND timestamp >= $4"
-		args = append(args, startTime)
+		s.Where("timestamp >= " + s.Arg(startTime))
 	}
 	if endTime != "" {
-		if startTime != "" {
-			query += " AND timestamp <= $5"
-			args = append(args, endTime, interval)
-		} else {
-			query += " AND timestamp <= $4"
-			args = append(args, endTime, interval)
-		}
-	} else {
-		args = append(args, interval)
+		s.Where("timestamp <= " + s.Arg(endTime))
 	}
+	stmt, args := s.Columns(bucket + " AS time_bucket, AVG((data->>'value')::numeric) AS avg_value, COUNT(*) AS count").
+		GroupBy("time_bucket").OrderBy("time_bucket").SQL()
 
-	query += " GROUP BY time_bucket ORDER BY time_bucket"
-
-	rows, err := db.Query(query, args...)
+	rows, err := db.Query(stmt, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -427,7 +489,7 @@ func getTimeSeriesFromDB(orgID int, targetID, category, startTime, endTime, inte
 }
 
 // saveTimeSeriesData는 시계열 데이터를 저장합니다
-func saveTimeSeriesData(orgID int, targetID, category string, data []map[string]interface{}) error {
+func saveTimeSeriesData(orgID, targetID, category string, data []map[string]interface{}) error {
 	db := database.GetDB()
 
 	// 트랜잭션 시작
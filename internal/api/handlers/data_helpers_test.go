@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/query"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := categoryCursor{
+		UpdatedAt: time.Date(2025, 1, 2, 3, 4, 5, 123456000, time.UTC),
+		TargetID:  "3f2a9c1e-0b7d-4e65-9a1b-2c3d4e5f6a7b",
+	}
+	got, err := decodeCursor(encodeCursor(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.UpdatedAt.Equal(want.UpdatedAt) || got.TargetID != want.TargetID {
+		t.Errorf("decoded %+v, want %+v", got, want)
+	}
+
+	for _, bad := range []string{"not base64!", "e30", encodeCursor(categoryCursor{UpdatedAt: want.UpdatedAt, TargetID: "x'; --"})} {
+		if _, err := decodeCursor(bad); err == nil {
+			t.Errorf("decodeCursor(%q) accepted", bad)
+		}
+	}
+}
+
+func TestBuildCursorQuery(t *testing.T) {
+	versionCtx := &middleware.VersionContext{RequestedVersion: "v2"}
+	filters, err := query.Parse([]string{"data.temp>25"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, args, err := buildCursorQuery("org", "sensors", versionCtx, filters, nil, 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(first, "ORDER BY updated_at DESC, target_id DESC LIMIT $5") || len(args) != 5 {
+		t.Errorf("first page query: %s %v", first, args)
+	}
+	if !strings.Contains(first, "deleted_at IS NULL") {
+		t.Errorf("recycle bin documents not excluded: %s", first)
+	}
+
+	next, args, err := buildCursorQuery("org", "sensors", versionCtx, filters, &categoryCursor{}, 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(next, "(updated_at, target_id) < ($5::timestamptz, $6::uuid)") || len(args) != 7 {
+		t.Errorf("next page query: %s %v", next, args)
+	}
+	if strings.Contains(next, "sensors") {
+		t.Errorf("category not passed as a parameter: %s", next)
+	}
+}
+
+func TestBuildQueriesBindValues(t *testing.T) {
+	payload := "x'; DROP TABLE target_categories; --"
+	filters, err := query.Parse([]string{"data.name=" + payload, "target_id=(" + payload + ",b)"}, "-data.temp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pagination := &middleware.PaginationContext{Page: 2, PageSize: 10}
+	versionCtx := &middleware.VersionContext{RequestedVersion: "latest"}
+
+	count, countArgs, err := buildCountQuery("org'--", "sensors'--", versionCtx, filters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, dataArgs, err := buildDataQuery("org'--", "sensors'--", versionCtx, pagination, filters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sql := range []string{count, data} {
+		if strings.Contains(sql, "DROP") || strings.Contains(sql, "'--") {
+			t.Errorf("request value in SQL: %s", sql)
+		}
+	}
+	if len(countArgs) != 5 || len(dataArgs) != 7 || dataArgs[5] != 10 || dataArgs[6] != 10 {
+		t.Errorf("args = %v, %v", countArgs, dataArgs)
+	}
+
+	if _, _, err := buildDataQuery("org", "sensors", &middleware.VersionContext{RequestedVersion: "v1 OR 1=1"}, pagination, filters); err == nil {
+		t.Error("invalid version accepted")
+	}
+}
+
+func TestCategoryETag(t *testing.T) {
+	row := CategoryData{TargetID: "t1", Version: "v1", UpdatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}
+	etag := categoryETag("v1", row)
+	if etag != categoryETag("v1", row) {
+		t.Fatal("etag is not stable")
+	}
+
+	changed := row
+	changed.UpdatedAt = changed.UpdatedAt.Add(time.Microsecond)
+	if etag == categoryETag("v1", changed) || etag == categoryETag("v2", row) {
+		t.Error("etag did not change")
+	}
+
+	if !etagMatches(`"x", `+strings.TrimPrefix(etag, "W/"), etag) || !etagMatches("*", etag) || etagMatches(`W/"x"`, etag) || etagMatches("", etag) {
+		t.Error("unexpected If-None-Match result")
+	}
+}
+
+func TestExpectedRevision(t *testing.T) {
+	var got *int
+	var gotErr error
+	app := fiber.New()
+	app.Post("/", func(c *fiber.Ctx) error {
+		got, gotErr = expectedRevision(c)
+		return nil
+	})
+	expected := func(query, ifMatch string) (*int, error) {
+		req := httptest.NewRequest("POST", "/"+query, nil)
+		if ifMatch != "" {
+			req.Header.Set(fiber.HeaderIfMatch, ifMatch)
+		}
+		if _, err := app.Test(req); err != nil {
+			t.Fatal(err)
+		}
+		return got, gotErr
+	}
+
+	if got, err := expected("", ""); got != nil || err != nil {
+		t.Errorf("no precondition: %v, %v", got, err)
+	}
+	for _, tc := range []struct {
+		query, ifMatch string
+		want           int
+	}{
+		{"?expected_revision=0", "", 0},
+		{"?expected_revision=4", "", 4},
+		{"", revisionETag(3), 3},
+		{"", `W/"3"`, 3},
+		{"?expected_revision=3", `"3"`, 3},
+	} {
+		got, err := expected(tc.query, tc.ifMatch)
+		if err != nil || got == nil || *got != tc.want {
+			t.Errorf("%q %q: got %v, %v, want %d", tc.query, tc.ifMatch, got, err, tc.want)
+		}
+	}
+	for _, bad := range [][2]string{{"?expected_revision=-1", ""}, {"?expected_revision=x", ""}, {"", "*"}, {"", `"0"`}, {"?expected_revision=2", `"3"`}} {
+		if _, err := expected(bad[0], bad[1]); err == nil {
+			t.Errorf("%q %q accepted", bad[0], bad[1])
+		}
+	}
+}
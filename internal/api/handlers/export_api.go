@@ -0,0 +1,397 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/export"
+	"github.com/tmidb/tmidb-core/internal/query"
+	"github.com/tmidb/tmidb-core/internal/storage"
+)
+
+const (
+	// exportTimeout 내보내기 하나의 최대 실행 시간
+	exportTimeout = 30 * time.Minute
+	// exportRetention 비동기 내보내기 결과 파일을 보관하는 시간
+	exportRetention = 24 * time.Hour
+	// maxExportColumns CSV로 펼칠 수 있는 문서 경로 수
+	maxExportColumns = 1000
+	// exportFlushRows 이 행 수마다 클라이언트에 보내 연결이 끊겼는지 확인
+	exportFlushRows = 1000
+)
+
+// exportSlots 이 API 인스턴스에서 동시에 실행하는 비동기 내보내기 수 (나머지는 대기)
+var exportSlots = make(chan struct{}, 2)
+
+// exportRequest는 검증을 마친 내보내기 요청입니다.
+// 스트리밍과 비동기 작업은 핸들러가 끝난 뒤에 실행되므로 fiber 컨텍스트 대신 값만 담습니다.
+type exportRequest struct {
+	orgID       string
+	category    string
+	format      string
+	columns     []string
+	dataPaths   [][]string // CSV로 펼칠 문서 경로
+	includeData bool
+	dataSelect  string
+	stmt        *query.Select
+}
+
+// parseExportRequest는 형식, 기간(from, to는 updated_at 기준), 버전, filter, sort, fields를 읽습니다.
+// 요청이 잘못되었으면 에러 응답을 보내고 nil 요청과 전송 결과를 반환합니다.
+func parseExportRequest(c *fiber.Ctx) (*exportRequest, error) {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return nil, sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	req := &exportRequest{orgID: orgID, category: c.Params("category"), format: c.Query("format", export.FormatNDJSON)}
+	if !export.ValidFormat(req.format) {
+		return nil, sendErrorResponse(c, "INVALID_REQUEST", "format must be csv, ndjson or parquet", req.format)
+	}
+	from, to, err := export.ParseRange(c.Query("from"), c.Query("to"))
+	if err != nil {
+		return nil, sendErrorResponse(c, "INVALID_REQUEST", err.Error(), "")
+	}
+	q, err := parseQueryFilters(c, "format", "from", "to", "async")
+	if err != nil {
+		return nil, sendErrorResponse(c, "QUERY_PARSE_ERROR", err.Error(), "")
+	}
+	filterRecorder.Record(orgID, req.category, q)
+
+	req.dataSelect = q.DataSelect()
+	req.stmt = query.NewSelect("target_id::text, category_name, schema_version, ("+req.dataSelect+")::text, created_at, updated_at",
+		"target_categories").OrderBy(q.OrderBy(defaultCategoryOrder, "target_id DESC"))
+	if err := buildCategoryWhere(req.stmt, orgID, req.category, middleware.GetVersionContext(c), q); err != nil {
+		return nil, sendErrorResponse(c, "QUERY_PARSE_ERROR", err.Error(), "")
+	}
+	if from != nil {
+		req.stmt.Where("updated_at >= " + req.stmt.Arg(*from))
+	}
+	if to != nil {
+		req.stmt.Where("updated_at < " + req.stmt.Arg(*to))
+	}
+	req.includeData = q.Fields.Includes("data")
+	for _, column := range export.Columns {
+		if q.Fields.Includes(column) {
+			req.columns = append(req.columns, column)
+		}
+	}
+	return req, nil
+}
+
+// filename은 다운로드 파일 이름입니다 (예: sensors-20260301T120000Z.csv)
+func (req *exportRequest) filename(at time.Time) string {
+	return req.category + "-" + at.UTC().Format("20060102T150405Z") + "." + req.format
+}
+
+// discoverDataPaths는 CSV 열로 펼칠 문서 경로를 찾습니다.
+// 헤더를 먼저 써야 하므로 내보낼 문서의 값 경로를 한 번 훑어 모으고, 객체는 그 아래 경로로 펼칩니다.
+func (req *exportRequest) discoverDataPaths(ctx context.Context) error {
+	if req.format != export.FormatCSV || !req.includeData {
+		return nil
+	}
+	rows, err := database.GetReadDB().QueryContext(ctx, `
+		WITH RECURSIVE docs AS (
+			SELECT (`+req.dataSelect+`) AS doc FROM target_categories WHERE `+req.stmt.Conditions()+`
+		), paths(path, value) AS (
+			SELECT ARRAY[e.key], e.value
+			FROM docs, jsonb_each(CASE WHEN jsonb_typeof(docs.doc) = 'object' THEN docs.doc ELSE '{}'::jsonb END) e
+			UNION ALL
+			SELECT p.path || e.key, e.value
+			FROM paths p, jsonb_each(CASE WHEN jsonb_typeof(p.value) = 'object' THEN p.value ELSE '{}'::jsonb END) e
+		)
+		SELECT DISTINCT path FROM paths
+		WHERE jsonb_typeof(value) <> 'object' OR value = '{}'::jsonb
+		ORDER BY path
+		LIMIT `+strconv.Itoa(maxExportColumns+1), req.stmt.Args()...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	req.dataPaths = nil
+	for rows.Next() {
+		var path []string
+		if err := rows.Scan(pq.Array(&path)); err != nil {
+			return err
+		}
+		req.dataPaths = append(req.dataPaths, path)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(req.dataPaths) > maxExportColumns {
+		return errTooManyExportColumns
+	}
+	return nil
+}
+
+var errTooManyExportColumns = fmt.Errorf("documents have more than %d distinct fields; choose some with fields", maxExportColumns)
+
+// run은 문서를 하나씩 읽어 out에 쓰고 쓴 행 수를 반환합니다. 결과를 메모리에 모으지 않습니다.
+func (req *exportRequest) run(ctx context.Context, out io.Writer) (int64, error) {
+	stmt, args := req.stmt.SQL()
+	rows, err := database.GetReadDB().QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	w, err := export.NewWriter(req.format, out, req.columns, req.dataPaths)
+	if err != nil {
+		return 0, err
+	}
+	flusher, _ := out.(interface{ Flush() error })
+
+	var count int64
+	var row export.Row
+	var data string
+	for rows.Next() {
+		if err := rows.Scan(&row.TargetID, &row.Category, &row.Version, &data, &row.CreatedAt, &row.UpdatedAt); err != nil {
+			return count, err
+		}
+		row.Data = []byte(data)
+		if err := w.Write(&row); err != nil {
+			return count, err
+		}
+		count++
+		if flusher != nil && count%exportFlushRows == 0 {
+			if err := flusher.Flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return count, err
+	}
+	return count, w.Close()
+}
+
+// ExportCategoryData는 카테고리 문서를 CSV, NDJSON, Parquet으로 내보냅니다.
+// 기본은 응답으로 바로 스트리밍하고, async=true면 SeaweedFS에 파일을 만드는 작업을 시작하고 202를 반환합니다.
+func ExportCategoryData(c *fiber.Ctx) error {
+	req, err := parseExportRequest(c)
+	if req == nil {
+		return err
+	}
+	if c.QueryBool("async") {
+		return startExportJob(c, req)
+	}
+
+	// 헤더를 쓰기 전에 열을 정해야 하고, 실패하면 아직 에러 응답을 보낼 수 있음
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	if err := req.discoverDataPaths(ctx); err != nil {
+		cancel()
+		return exportError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, export.ContentType(req.format))
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": req.filename(time.Now())}))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		start := time.Now()
+		count, err := req.run(ctx, w)
+		if err != nil {
+			// 상태 코드는 이미 보냈으므로 NDJSON은 마지막 줄로 알리고, 나머지는 잘린 파일로 남음
+			log.Printf("❌ 내보내기 중단 (%s/%s, %d rows): %v", req.orgID, req.category, count, err)
+			if req.format == export.FormatNDJSON {
+				fmt.Fprintf(w, "{\"error\":%q}\n", "export interrupted")
+			}
+		} else {
+			log.Printf("📤 Exported %d rows of %s as %s in %s", count, req.category, req.format, time.Since(start).Round(time.Millisecond))
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+// exportError는 스트리밍 전에 난 오류를 응답으로 보냅니다
+func exportError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, errTooManyExportColumns) {
+		return sendErrorResponse(c, "INVALID_REQUEST", err.Error(), "")
+	}
+	log.Printf("Error preparing export: %v", err)
+	return sendErrorResponse(c, "DATABASE_ERROR", "Failed to export data", "")
+}
+
+// startExportJob은 비동기 내보내기 작업을 만들고 백그라운드에서 실행합니다
+func startExportJob(c *fiber.Ctx, req *exportRequest) error {
+	if fileStorage == nil {
+		return sendErrorResponse(c, "STORAGE_ERROR", "File storage is not configured", "")
+	}
+	expireExportJobs()
+
+	job := &database.ExportJob{
+		OrgID:    req.orgID,
+		Category: req.category,
+		Format:   req.format,
+		Parameters: map[string]interface{}{
+			"query":   string(c.Request().URI().QueryString()),
+			"version": middleware.GetVersionContext(c).RequestedVersion,
+		},
+		CreatedBy: targetActor(c),
+	}
+	if err := database.CreateExportJob(database.GetDB(), job); err != nil {
+		log.Printf("Error creating export job: %v", err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to create export job", "")
+	}
+	go runExportJob(job, req)
+
+	statusURL := path.Join(c.Path(), "..", "jobs", job.JobID)
+	c.Set(fiber.HeaderLocation, statusURL)
+	c.Status(fiber.StatusAccepted)
+	return sendSuccessResponse(c, fiber.Map{"job": job, "status_url": statusURL}, nil)
+}
+
+// runExportJob은 내보내기를 SeaweedFS로 바로 업로드하고 결과를 작업에 기록합니다
+func runExportJob(job *database.ExportJob, req *exportRequest) {
+	exportSlots <- struct{}{}
+	defer func() { <-exportSlots }()
+
+	db := database.GetDB()
+	if err := database.StartExportJob(db, job.JobID); err != nil {
+		log.Printf("⚠️ 내보내기 작업 시작 기록 실패 (%s): %v", job.JobID, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+
+	storagePath := path.Join("exports", req.orgID, job.JobID, req.filename(job.CreatedAt))
+	count, size, err := uploadExport(ctx, req, fileStorage, storagePath)
+	if err != nil {
+		log.Printf("❌ 내보내기 작업 실패 (%s): %v", job.JobID, err)
+		if delErr := fileStorage.Delete(context.Background(), storagePath); delErr != nil && !errors.Is(delErr, storage.ErrNotFound) {
+			log.Printf("⚠️ 내보내기 파일 정리 실패 (%s): %v", storagePath, delErr)
+		}
+	} else {
+		log.Printf("📤 Export job %s finished: %d rows, %d bytes", job.JobID, count, size)
+	}
+	if err := database.FinishExportJob(db, job.JobID, count, size, storagePath, exportRetention, err); err != nil {
+		log.Printf("⚠️ 내보내기 작업 결과 기록 실패 (%s): %v", job.JobID, err)
+	}
+}
+
+// uploadExport는 파이프로 내보내기 결과를 만들면서 업로드합니다 (임시 파일이나 메모리 버퍼 없음)
+func uploadExport(ctx context.Context, req *exportRequest, filer *storage.FilerClient, storagePath string) (count, size int64, err error) {
+	if err := req.discoverDataPaths(ctx); err != nil {
+		return 0, 0, err
+	}
+
+	pr, pw := io.Pipe()
+	counter := &countingWriter{w: pw}
+	done := make(chan error, 1)
+	go func() {
+		n, err := req.run(ctx, counter)
+		count = n
+		pw.CloseWithError(err)
+		done <- err
+	}()
+
+	uploadErr := filer.Put(ctx, storagePath, pr, -1, export.ContentType(req.format))
+	// 업로드가 먼저 실패하면 쓰는 쪽이 막히지 않도록 파이프를 닫음
+	pr.CloseWithError(uploadErr)
+	if runErr := <-done; runErr != nil {
+		return count, counter.n, runErr
+	}
+	return count, counter.n, uploadErr
+}
+
+// countingWriter는 쓴 바이트 수를 셉니다
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// expireExportJobs는 만료된 내보내기 작업과 파일을 정리합니다 (작업을 만들 때마다 호출)
+func expireExportJobs() {
+	paths, err := database.ExpireExportJobs(database.GetDB(), exportTimeout+10*time.Minute, exportRetention)
+	if err != nil {
+		log.Printf("⚠️ 만료된 내보내기 작업 정리 실패: %v", err)
+		return
+	}
+	for _, p := range paths {
+		if err := fileStorage.Delete(context.Background(), p); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("⚠️ 만료된 내보내기 파일 삭제 실패 (%s): %v", p, err)
+		}
+	}
+}
+
+// lookupExportJob은 요청 경로의 내보내기 작업을 조직 범위로 조회하고 카테고리 읽기 권한을 확인합니다.
+// 찾지 못하면 에러 응답을 보내고 nil 작업과 전송 결과를 반환합니다.
+func lookupExportJob(c *fiber.Ctx) (*database.ExportJob, error) {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return nil, sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	job, err := database.GetExportJob(database.GetDB(), orgID, c.Params("job_id"))
+	if errors.Is(err, database.ErrExportJobNotFound) {
+		return nil, sendErrorResponse(c, "FILE_NOT_FOUND", "Export job not found", c.Params("job_id"))
+	}
+	if err != nil {
+		return nil, sendErrorResponse(c, "DATABASE_ERROR", "Failed to look up export job", err.Error())
+	}
+	if !middleware.CategoryAllowed(c, "read", job.Category) {
+		return nil, middleware.PermissionDenied(c, "read", job.Category)
+	}
+	return job, nil
+}
+
+// GetExportJob은 비동기 내보내기 작업의 상태를 반환합니다. 완료되었으면 download_url이 함께 옵니다.
+func GetExportJob(c *fiber.Ctx) error {
+	job, err := lookupExportJob(c)
+	if job == nil {
+		return err
+	}
+	response := fiber.Map{"job": job}
+	if job.Status == database.ExportStatusCompleted {
+		response["download_url"] = c.Path() + "/download"
+	}
+	return sendSuccessResponse(c, response, nil)
+}
+
+// DownloadExport는 완료된 내보내기 파일을 SeaweedFS에서 스트리밍으로 전달합니다
+func DownloadExport(c *fiber.Ctx) error {
+	job, err := lookupExportJob(c)
+	if job == nil {
+		return err
+	}
+	if job.Status != database.ExportStatusCompleted || job.StoragePath == "" {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Export is not ready", job.Status)
+	}
+	if fileStorage == nil {
+		return sendErrorResponse(c, "STORAGE_ERROR", "File storage is not configured", "")
+	}
+
+	obj, err := fileStorage.Get(c.UserContext(), job.StoragePath)
+	if errors.Is(err, storage.ErrNotFound) {
+		return sendErrorResponse(c, "FILE_NOT_FOUND", "Export file is missing from storage", job.JobID)
+	}
+	if err != nil {
+		log.Printf("❌ 내보내기 파일 조회 실패 (%s): %v", job.StoragePath, err)
+		return sendErrorResponse(c, "STORAGE_ERROR", "Failed to read export from storage", "")
+	}
+	c.Set(fiber.HeaderContentType, export.ContentType(job.Format))
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(job.StoragePath)}))
+	size := int(obj.Size)
+	if obj.Size <= 0 {
+		size = -1
+	}
+	return c.SendStream(obj.Body, size)
+}
@@ -0,0 +1,319 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/config"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/storage"
+)
+
+// 첨부 파일 저장소 설정 (InitFileStorage에서 초기화)
+var (
+	fileStorage        *storage.FilerClient
+	maxAttachmentSize  int64 = 25 * 1024 * 1024
+	allowedAttachTypes []string
+)
+
+// InitFileStorage는 첨부 파일용 SeaweedFS 파일러 클라이언트를 초기화합니다
+func InitFileStorage(cfg *config.Config) {
+	fileStorage = storage.NewFilerClient(cfg.SeaweedFSFilerURL)
+	if cfg.MaxAttachmentSize > 0 {
+		maxAttachmentSize = cfg.MaxAttachmentSize
+	}
+	allowedAttachTypes = allowedAttachTypes[:0]
+	for _, t := range cfg.AttachmentAllowedTypes {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			allowedAttachTypes = append(allowedAttachTypes, t)
+		}
+	}
+}
+
+// MaxUploadBodySize는 첨부 파일 업로드를 받을 수 있는 요청 본문 크기입니다 (fiber BodyLimit)
+func MaxUploadBodySize(cfg *config.Config) int {
+	limit := cfg.MaxAttachmentSize + 1024*1024 // multipart 헤더 여유분
+	if limit < MaxBulkBodySize {
+		return MaxBulkBodySize
+	}
+	return int(limit)
+}
+
+// UploadFiles는 multipart 요청의 파일들을 SeaweedFS에 저장하고 메타데이터를 기록합니다.
+// 폼 필드 이름은 "file" 또는 "files"이며, 카테고리 경로로 호출하면 카테고리가 함께 저장됩니다.
+func UploadFiles(c *fiber.Ctx) error {
+	targetID := c.Params("target_id")
+	category := c.Params("category")
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	if fileStorage == nil {
+		return sendErrorResponse(c, "STORAGE_ERROR", "File storage is not configured", "")
+	}
+
+	db := database.GetDB()
+	if exists, err := database.TargetExists(db, targetID); err != nil {
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to look up target", err.Error())
+	} else if !exists {
+		return sendErrorResponse(c, "TARGET_NOT_FOUND", "Target not found", targetID)
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Expected multipart/form-data body", err.Error())
+	}
+	files := append(form.File["file"], form.File["files"]...)
+	if len(files) == 0 {
+		return sendErrorResponse(c, "INVALID_REQUEST", "No files in request", `use form field "file" or "files"`)
+	}
+
+	// 저장 전에 모든 파일을 검증하여 일부만 저장되는 일을 줄임
+	for _, fh := range files {
+		if fh.Size > maxAttachmentSize {
+			return sendErrorResponse(c, "FILE_TOO_LARGE",
+				fmt.Sprintf("File %q exceeds the %d MB limit", fh.Filename, maxAttachmentSize/(1024*1024)), "")
+		}
+	}
+
+	uploaded := make([]database.Attachment, 0, len(files))
+	for _, fh := range files {
+		attachment, code, err := storeAttachment(c, fh, orgID, targetID, category)
+		if err != nil {
+			// 요청 단위로 처리: 앞서 저장한 파일을 되돌림
+			for i := range uploaded {
+				removeAttachment(c, &uploaded[i])
+			}
+			return sendErrorResponse(c, code, err.Error(), "")
+		}
+		uploaded = append(uploaded, *attachment)
+	}
+
+	c.Status(fiber.StatusCreated)
+	return sendSuccessResponse(c, uploaded, nil)
+}
+
+// storeAttachment는 파일 하나를 검증, 저장하고 실패 시 에러 코드를 반환합니다
+func storeAttachment(c *fiber.Ctx, fh *multipart.FileHeader, orgID, targetID, category string) (*database.Attachment, string, error) {
+	file, err := fh.Open()
+	if err != nil {
+		return nil, "INVALID_REQUEST", fmt.Errorf("failed to read %q: %v", fh.Filename, err)
+	}
+	defer file.Close()
+
+	// 앞부분으로 실제 MIME 타입 판별
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, "INVALID_REQUEST", fmt.Errorf("failed to read %q: %v", fh.Filename, err)
+	}
+	head = head[:n]
+	mimeType := resolveMimeType(fh.Header.Get("Content-Type"), http.DetectContentType(head))
+	if !mimeAllowed(mimeType, allowedAttachTypes) {
+		return nil, "UNSUPPORTED_MEDIA_TYPE", fmt.Errorf("file type %s is not allowed (%s)", mimeType, fh.Filename)
+	}
+
+	id, err := database.NewAttachmentID()
+	if err != nil {
+		return nil, "INTERNAL_ERROR", err
+	}
+	filename := sanitizeFilename(fh.Filename)
+	attachment := &database.Attachment{
+		AttachmentID: id,
+		OrgID:        orgID,
+		TargetID:     targetID,
+		CategoryName: category,
+		Filename:     filename,
+		StoragePath:  path.Join("attachments", orgID, targetID, id, filename),
+		SizeBytes:    fh.Size,
+		MimeType:     mimeType,
+	}
+	if username, ok := c.Locals("username").(string); ok {
+		attachment.UploadedBy = username
+	}
+
+	body := io.MultiReader(bytes.NewReader(head), file)
+	if err := fileStorage.Put(c.UserContext(), attachment.StoragePath, body, fh.Size, mimeType); err != nil {
+		log.Printf("❌ 첨부 파일 저장 실패 (%s): %v", attachment.StoragePath, err)
+		return nil, "STORAGE_ERROR", fmt.Errorf("failed to store %q", fh.Filename)
+	}
+
+	if err := database.CreateAttachment(database.GetDB(), attachment); err != nil {
+		// 메타데이터 없이 남는 blob 정리
+		if delErr := fileStorage.Delete(c.UserContext(), attachment.StoragePath); delErr != nil {
+			log.Printf("⚠️ 고아 첨부 파일 삭제 실패 (%s): %v", attachment.StoragePath, delErr)
+		}
+		return nil, "DATABASE_ERROR", fmt.Errorf("failed to save metadata for %q: %v", fh.Filename, err)
+	}
+	return attachment, "", nil
+}
+
+// removeAttachment는 업로드 실패 시 저장된 첨부 파일을 정리합니다
+func removeAttachment(c *fiber.Ctx, a *database.Attachment) {
+	if err := fileStorage.Delete(c.UserContext(), a.StoragePath); err != nil {
+		log.Printf("⚠️ 첨부 파일 정리 실패 (%s): %v", a.StoragePath, err)
+	}
+	if err := database.DeleteAttachment(database.GetDB(), a.OrgID, a.AttachmentID); err != nil {
+		log.Printf("⚠️ 첨부 파일 메타데이터 정리 실패 (%s): %v", a.AttachmentID, err)
+	}
+}
+
+// ListFiles는 타겟의 첨부 파일 목록을 반환합니다 (카테고리 경로면 해당 카테고리만)
+func ListFiles(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+
+	attachments, err := database.ListAttachments(database.GetDB(), orgID, c.Params("target_id"), c.Params("category"))
+	if err != nil {
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to list files", err.Error())
+	}
+	return sendSuccessResponse(c, attachments, nil)
+}
+
+// DownloadFile은 첨부 파일을 SeaweedFS에서 스트리밍으로 전달합니다
+func DownloadFile(c *fiber.Ctx) error {
+	attachment, err := lookupAttachment(c)
+	if attachment == nil {
+		return err
+	}
+
+	obj, err := fileStorage.Get(c.UserContext(), attachment.StoragePath)
+	if errors.Is(err, storage.ErrNotFound) {
+		return sendErrorResponse(c, "FILE_NOT_FOUND", "File content is missing from storage", attachment.AttachmentID)
+	}
+	if err != nil {
+		log.Printf("❌ 첨부 파일 조회 실패 (%s): %v", attachment.StoragePath, err)
+		return sendErrorResponse(c, "STORAGE_ERROR", "Failed to read file from storage", "")
+	}
+
+	contentType := attachment.MimeType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	size := int(obj.Size)
+	if obj.Size <= 0 {
+		size = -1
+	}
+	// fasthttp가 응답 전송 후 Body를 닫음
+	return c.SendStream(obj.Body, size)
+}
+
+// DeleteFile은 첨부 파일의 blob과 메타데이터를 삭제합니다
+func DeleteFile(c *fiber.Ctx) error {
+	attachment, err := lookupAttachment(c)
+	if attachment == nil {
+		return err
+	}
+
+	if err := fileStorage.Delete(c.UserContext(), attachment.StoragePath); err != nil {
+		log.Printf("❌ 첨부 파일 삭제 실패 (%s): %v", attachment.StoragePath, err)
+		return sendErrorResponse(c, "STORAGE_ERROR", "Failed to delete file from storage", "")
+	}
+	if err := database.DeleteAttachment(database.GetDB(), attachment.OrgID, attachment.AttachmentID); err != nil && err != sql.ErrNoRows {
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to delete file metadata", err.Error())
+	}
+	return sendSuccessResponse(c, fiber.Map{"file_id": attachment.AttachmentID, "deleted": true}, nil)
+}
+
+// lookupAttachment는 요청 경로의 첨부 파일을 조직 범위로 조회합니다.
+// 찾지 못하면 에러 응답을 보내고 nil 첨부 파일과 전송 결과를 반환합니다.
+func lookupAttachment(c *fiber.Ctx) (*database.Attachment, error) {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return nil, sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	if fileStorage == nil {
+		return nil, sendErrorResponse(c, "STORAGE_ERROR", "File storage is not configured", "")
+	}
+
+	attachment, err := database.GetAttachment(database.GetDB(), orgID, c.Params("target_id"), c.Params("file_id"))
+	if err == sql.ErrNoRows {
+		return nil, sendErrorResponse(c, "FILE_NOT_FOUND", "File not found", c.Params("file_id"))
+	}
+	if err != nil {
+		return nil, sendErrorResponse(c, "DATABASE_ERROR", "Failed to look up file", err.Error())
+	}
+	if category := c.Params("category"); category != "" && attachment.CategoryName != category {
+		return nil, sendErrorResponse(c, "FILE_NOT_FOUND", "File not found", c.Params("file_id"))
+	}
+	return attachment, nil
+}
+
+// resolveMimeType은 내용으로 판별한 타입을 기준으로 MIME 타입을 결정합니다.
+// 판별 결과가 일반 텍스트/바이너리일 때만 클라이언트가 보낸 타입을 신뢰합니다
+// (예: CSV, JSON, DICOM). 이미지나 PDF로 위장한 파일은 판별 결과를 따릅니다.
+func resolveMimeType(declared, sniffed string) string {
+	sniffed = baseMimeType(sniffed)
+	declared = baseMimeType(declared)
+	if declared == "" || declared == sniffed {
+		return sniffed
+	}
+
+	switch sniffed {
+	case "text/plain":
+		if strings.HasPrefix(declared, "text/") || declared == "application/json" {
+			return declared
+		}
+	case "application/octet-stream":
+		if !strings.HasPrefix(declared, "text/") && !strings.HasPrefix(declared, "image/") &&
+			declared != "application/pdf" && declared != "application/zip" && declared != "application/json" {
+			return declared
+		}
+	}
+	return sniffed
+}
+
+// baseMimeType은 파라미터(charset 등)를 제거한 소문자 MIME 타입을 반환합니다
+func baseMimeType(t string) string {
+	if mediaType, _, err := mime.ParseMediaType(t); err == nil {
+		return mediaType
+	}
+	return strings.ToLower(strings.TrimSpace(strings.SplitN(t, ";", 2)[0]))
+}
+
+// mimeAllowed는 MIME 타입이 허용 목록("image/*" 형식 포함)에 있는지 확인합니다.
+// 허용 목록이 비어 있으면 모든 타입을 허용합니다.
+func mimeAllowed(mimeType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if a == "*/*" || a == mimeType {
+			return true
+		}
+		if strings.HasSuffix(a, "/*") && strings.HasPrefix(mimeType, strings.TrimSuffix(a, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeFilename은 경로 구분자와 제어 문자를 제거한 파일 이름을 반환합니다
+func sanitizeFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" || name == ".." {
+		return "file"
+	}
+	return name
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import "testing"
+
+func TestResolveMimeType(t *testing.T) {
+	cases := []struct {
+		declared, sniffed, want string
+	}{
+		{"text/csv", "text/plain; charset=utf-8", "text/csv"},
+		{"application/json", "text/plain; charset=utf-8", "application/json"},
+		{"application/dicom", "application/octet-stream", "application/dicom"},
+		{"", "image/png", "image/png"},
+		// 내용과 다른 타입으로 위장한 경우 판별 결과를 따름
+		{"image/png", "application/pdf", "application/pdf"},
+		{"image/png", "application/octet-stream", "application/octet-stream"},
+		{"application/pdf", "text/plain; charset=utf-8", "text/plain"},
+	}
+	for _, tc := range cases {
+		if got := resolveMimeType(tc.declared, tc.sniffed); got != tc.want {
+			t.Errorf("resolveMimeType(%q, %q) = %q, want %q", tc.declared, tc.sniffed, got, tc.want)
+		}
+	}
+}
+
+func TestMimeAllowed(t *testing.T) {
+	allowed := []string{"image/*", "application/pdf"}
+	for _, mimeType := range []string{"image/png", "image/jpeg", "application/pdf"} {
+		if !mimeAllowed(mimeType, allowed) {
+			t.Errorf("%s should be allowed", mimeType)
+		}
+	}
+	for _, mimeType := range []string{"text/html", "application/zip", "imagex/png"} {
+		if mimeAllowed(mimeType, allowed) {
+			t.Errorf("%s should be rejected", mimeType)
+		}
+	}
+	if !mimeAllowed("text/html", nil) {
+		t.Error("empty allowlist should allow every type")
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	cases := map[string]string{
+		"report.pdf":           "report.pdf",
+		"../../etc/passwd":     "passwd",
+		`C:\Users\me\scan.dcm`: "scan.dcm",
+		"bad\nname.txt":        "badname.txt",
+		"..":                   "file",
+		"":                     "file",
+	}
+	for input, want := range cases {
+		if got := sanitizeFilename(input); got != want {
+			t.Errorf("sanitizeFilename(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/database"
+)
+
+// Grafana JSON 데이터소스 제한
+const (
+	grafanaFieldSample  = 500  // 메트릭을 찾을 때 카테고리별로 살펴보는 최근 관측값 수
+	grafanaMaxSeries    = 100  // 타겟별 그룹일 때 쿼리 하나의 최대 시리즈 수
+	grafanaMaxPoints    = 5000 // 시리즈 하나의 최대 점 수
+	grafanaMaxTargets   = 500  // 타겟 선택 목록의 최대 항목 수
+	grafanaMinBucket    = time.Second
+	grafanaDefaultAgg   = "avg"
+	grafanaGroupTarget  = "target"
+	grafanaAllTargets   = "*"
+	grafanaVariableCats = "categories"
+)
+
+// grafanaQueryRequest는 Grafana JSON 데이터소스(simpod)와 SimpleJSON의 /query 요청입니다
+type grafanaQueryRequest struct {
+	Range struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	} `json:"range"`
+	IntervalMs    int64                `json:"intervalMs"`
+	MaxDataPoints int64                `json:"maxDataPoints"`
+	Targets       []grafanaQueryTarget `json:"targets"`
+}
+
+type grafanaQueryTarget struct {
+	RefID   string            `json:"refId"`
+	Target  string            `json:"target"`
+	Hide    bool              `json:"hide"`
+	Payload map[string]string `json:"payload"`
+}
+
+// grafanaSeries는 Grafana의 timeserie 응답 형식입니다 (datapoints는 [값, 밀리초 타임스탬프])
+type grafanaSeries struct {
+	Target     string       `json:"target"`
+	RefID      string       `json:"refId,omitempty"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+type grafanaOption struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+type grafanaPayload struct {
+	Label   string          `json:"label"`
+	Name    string          `json:"name"`
+	Type    string          `json:"type"`
+	Options []grafanaOption `json:"options,omitempty"`
+}
+
+type grafanaMetric struct {
+	Label    string           `json:"label"`
+	Value    string           `json:"value"`
+	Payloads []grafanaPayload `json:"payloads"`
+}
+
+// GrafanaHealth는 Grafana 데이터소스의 연결 확인에 응답합니다
+func GrafanaHealth(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": "ok"})
+}
+
+// GrafanaMetrics는 JSON 데이터소스의 메트릭 목록입니다 (카테고리.필드와 집계, 타겟, 그룹 선택)
+func GrafanaMetrics(c *fiber.Ctx) error {
+	var req struct {
+		Metric string `json:"metric"`
+	}
+	_ = c.BodyParser(&req)
+
+	metrics, err := grafanaMetricNames(c, req.Metric)
+	if err != nil {
+		return err
+	}
+
+	aggs := make([]grafanaOption, len(database.AggregateFunctions))
+	for i, fn := range database.AggregateFunctions {
+		aggs[i] = grafanaOption{Label: fn, Value: fn}
+	}
+	payloads := []grafanaPayload{
+		{Label: "Aggregation", Name: "agg", Type: "select", Options: aggs},
+		{Label: "Target", Name: "target", Type: "select"},
+		{Label: "Group by", Name: "group_by", Type: "select", Options: []grafanaOption{
+			{Label: "none", Value: ""}, {Label: "target", Value: grafanaGroupTarget},
+		}},
+	}
+	out := make([]grafanaMetric, len(metrics))
+	for i, m := range metrics {
+		out[i] = grafanaMetric{Label: m, Value: m, Payloads: payloads}
+	}
+	return c.JSON(out)
+}
+
+// GrafanaSearch는 SimpleJSON 데이터소스의 메트릭 검색입니다 (이름 목록)
+func GrafanaSearch(c *fiber.Ctx) error {
+	var req struct {
+		Target string `json:"target"`
+	}
+	_ = c.BodyParser(&req)
+
+	metrics, err := grafanaMetricNames(c, req.Target)
+	if err != nil {
+		return err
+	}
+	if metrics == nil {
+		metrics = []string{}
+	}
+	return c.JSON(metrics)
+}
+
+// GrafanaMetricPayloadOptions는 메트릭 payload의 선택지입니다 (target이면 카테고리의 타겟 목록)
+func GrafanaMetricPayloadOptions(c *fiber.Ctx) error {
+	var req struct {
+		Metric string `json:"metric"`
+		Name   string `json:"name"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+	options := []grafanaOption{}
+	if req.Name != "target" {
+		return c.JSON(options)
+	}
+
+	category, _, err := splitGrafanaMetric(req.Metric)
+	if err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", err.Error(), "")
+	}
+	targets, err := grafanaTargets(c, category)
+	if err != nil {
+		return err
+	}
+	options = append(options, grafanaOption{Label: "all", Value: grafanaAllTargets})
+	return c.JSON(append(options, targets...))
+}
+
+// GrafanaVariable은 대시보드 변수 쿼리입니다.
+// "categories"는 카테고리 목록, 카테고리 이름은 그 카테고리의 타겟, 그 밖에는 메트릭 목록을 돌려줍니다.
+func GrafanaVariable(c *fiber.Ctx) error {
+	var req struct {
+		Payload struct {
+			Target string `json:"target"`
+		} `json:"payload"`
+	}
+	_ = c.BodyParser(&req)
+	query := strings.TrimSpace(req.Payload.Target)
+
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	categories, err := database.ListOrgCategoryNames(database.GetDB(), orgID)
+	if err != nil {
+		log.Printf("Error listing categories for Grafana: %v", err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to list categories", "")
+	}
+
+	var options []grafanaOption
+	switch {
+	case query == grafanaVariableCats:
+		for _, category := range categories {
+			if middleware.CategoryAllowed(c, "read", category) {
+				options = append(options, grafanaOption{Label: category, Value: category})
+			}
+		}
+	case query != "" && slices.Contains(categories, query):
+		if options, err = grafanaTargets(c, query); err != nil {
+			return err
+		}
+	default:
+		metrics, err := grafanaMetricNames(c, query)
+		if err != nil {
+			return err
+		}
+		for _, m := range metrics {
+			options = append(options, grafanaOption{Label: m, Value: m})
+		}
+	}
+
+	out := make([]fiber.Map, len(options))
+	for i, o := range options {
+		out[i] = fiber.Map{"__text": o.Label, "__value": o.Value}
+	}
+	return c.JSON(out)
+}
+
+// GrafanaQuery는 시간 범위의 메트릭을 구간별로 집계해 시리즈로 돌려줍니다.
+// 구간 크기는 intervalMs와 maxDataPoints 중 큰 쪽을 따르고, 숫자가 아닌 값은 건너뜁니다.
+func GrafanaQuery(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	var req grafanaQueryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+	from, err := time.Parse(time.RFC3339Nano, req.Range.From)
+	if err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Invalid range.from", err.Error())
+	}
+	to, err := time.Parse(time.RFC3339Nano, req.Range.To)
+	if err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Invalid range.to", err.Error())
+	}
+	if !to.After(from) {
+		return sendErrorResponse(c, "INVALID_REQUEST", "range.to must be after range.from", "")
+	}
+	bucket := grafanaBucket(from, to, req.IntervalMs, req.MaxDataPoints)
+
+	db := database.GetDB()
+	out := []grafanaSeries{}
+	for _, t := range req.Targets {
+		if t.Hide || t.Target == "" {
+			continue
+		}
+		category, field, err := splitGrafanaMetric(t.Target)
+		if err != nil {
+			return sendErrorResponse(c, "INVALID_REQUEST", err.Error(), "")
+		}
+		if !middleware.CategoryAllowed(c, "read", category) {
+			return middleware.PermissionDenied(c, "read", category)
+		}
+
+		q := database.AggregateQuery{
+			OrgID:    orgID,
+			Category: category,
+			Path:     strings.Split(field, "."),
+			Func:     t.Payload["agg"],
+			From:     from,
+			To:       to,
+			Bucket:   bucket,
+			TargetID: t.Payload["target"],
+			ByTarget: t.Payload["group_by"] == grafanaGroupTarget,
+			Limit:    grafanaMaxSeries,
+		}
+		if q.Func == "" {
+			q.Func = grafanaDefaultAgg
+		}
+		if q.TargetID == grafanaAllTargets {
+			q.TargetID = ""
+		}
+		if q.TargetID != "" && !uuidPattern.MatchString(q.TargetID) {
+			return sendErrorResponse(c, "INVALID_REQUEST", fmt.Sprintf("Invalid target ID %q", q.TargetID), "")
+		}
+		if !slices.Contains(database.AggregateFunctions, q.Func) {
+			return sendErrorResponse(c, "INVALID_REQUEST", fmt.Sprintf("Unknown aggregation %q", q.Func),
+				"one of "+strings.Join(database.AggregateFunctions, ", "))
+		}
+
+		series, err := database.AggregateObservations(db, q)
+		if err != nil {
+			log.Printf("Error querying %s for Grafana: %v", t.Target, err)
+			return sendErrorResponse(c, "DATABASE_ERROR", "Failed to query metric", "")
+		}
+		for _, s := range series {
+			name := t.Target
+			if q.ByTarget {
+				name = fmt.Sprintf("%s %s", s.TargetName, t.Target)
+			}
+			points := s.Points
+			if len(points) > grafanaMaxPoints {
+				points = points[len(points)-grafanaMaxPoints:]
+			}
+			datapoints := make([][2]float64, len(points))
+			for i, p := range points {
+				datapoints[i] = [2]float64{p.Value, float64(p.Time.UnixMilli())}
+			}
+			out = append(out, grafanaSeries{Target: name, RefID: t.RefID, Datapoints: datapoints})
+		}
+	}
+	return c.JSON(out)
+}
+
+// grafanaMetricNames는 읽을 수 있는 카테고리의 숫자 필드를 "카테고리.필드"로 나열합니다 (filter를 포함하는 것만)
+func grafanaMetricNames(c *fiber.Ctx, filter string) ([]string, error) {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return nil, sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	db := database.GetDB()
+	categories, err := database.ListOrgCategoryNames(db, orgID)
+	if err != nil {
+		log.Printf("Error listing categories for Grafana: %v", err)
+		return nil, sendErrorResponse(c, "DATABASE_ERROR", "Failed to list metrics", "")
+	}
+
+	var metrics []string
+	for _, category := range categories {
+		if !middleware.CategoryAllowed(c, "read", category) {
+			continue
+		}
+		fields, err := database.ListNumericFields(db, orgID, category, grafanaFieldSample)
+		if err != nil {
+			log.Printf("Error listing fields of %s for Grafana: %v", category, err)
+			return nil, sendErrorResponse(c, "DATABASE_ERROR", "Failed to list metrics", "")
+		}
+		for _, field := range fields {
+			metric := category + "." + field
+			if strings.Contains(metric, filter) {
+				metrics = append(metrics, metric)
+			}
+		}
+	}
+	return metrics, nil
+}
+
+// grafanaTargets는 카테고리에 연결된 타겟을 선택지로 돌려줍니다 (이름, 타겟 ID)
+func grafanaTargets(c *fiber.Ctx, category string) ([]grafanaOption, error) {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return nil, sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	if !middleware.CategoryAllowed(c, "read", category) {
+		return nil, middleware.PermissionDenied(c, "read", category)
+	}
+	targets, err := database.ListTargets(database.GetDB(), orgID, []string{category}, false, grafanaMaxTargets, 0)
+	if err != nil {
+		log.Printf("Error listing targets of %s for Grafana: %v", category, err)
+		return nil, sendErrorResponse(c, "DATABASE_ERROR", "Failed to list targets", "")
+	}
+	options := make([]grafanaOption, len(targets))
+	for i, t := range targets {
+		options[i] = grafanaOption{Label: t.Name, Value: t.TargetID}
+	}
+	return options, nil
+}
+
+// splitGrafanaMetric은 "카테고리.필드" 메트릭을 나눕니다 (필드는 점으로 구분한 payload 경로일 수 있음)
+func splitGrafanaMetric(metric string) (string, string, error) {
+	category, field, ok := strings.Cut(metric, ".")
+	if !ok || category == "" || field == "" {
+		return "", "", fmt.Errorf("invalid metric %q (expected category.field)", metric)
+	}
+	for _, part := range strings.Split(field, ".") {
+		if part == "" {
+			return "", "", fmt.Errorf("invalid metric %q (empty field path segment)", metric)
+		}
+	}
+	return category, field, nil
+}
+
+// grafanaBucket은 집계 구간 크기를 정합니다 (패널 간격, 범위를 maxDataPoints로 나눈 값, 1초 중 가장 큰 값)
+func grafanaBucket(from, to time.Time, intervalMs, maxDataPoints int64) time.Duration {
+	bucket := time.Duration(intervalMs) * time.Millisecond
+	if maxDataPoints > 0 {
+		if perPoint := to.Sub(from) / time.Duration(maxDataPoints); perPoint > bucket {
+			bucket = perPoint
+		}
+	}
+	if bucket < grafanaMinBucket {
+		bucket = grafanaMinBucket
+	}
+	return bucket.Truncate(time.Millisecond)
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSplitGrafanaMetric(t *testing.T) {
+	category, field, err := splitGrafanaMetric("vitals.heart.rate")
+	if err != nil || category != "vitals" || field != "heart.rate" {
+		t.Errorf("got %q, %q, %v", category, field, err)
+	}
+	for _, bad := range []string{"vitals", ".rate", "vitals.", "vitals.heart..rate"} {
+		if _, _, err := splitGrafanaMetric(bad); err == nil {
+			t.Errorf("%q accepted", bad)
+		}
+	}
+}
+
+func TestGrafanaBucket(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	if got := grafanaBucket(from, to, 60000, 0); got != time.Minute {
+		t.Errorf("interval only = %s", got)
+	}
+	// 하루를 100개 점으로: 패널 간격보다 큰 구간
+	if got := grafanaBucket(from, to, 60000, 100); got != 864*time.Second {
+		t.Errorf("maxDataPoints = %s", got)
+	}
+	if got := grafanaBucket(from, from.Add(time.Minute), 10, 1000); got != time.Second {
+		t.Errorf("minimum = %s", got)
+	}
+}
@@ -0,0 +1,509 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/config"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/graphql"
+	"github.com/tmidb/tmidb-core/internal/query"
+)
+
+// GraphQL 요청 제한
+const (
+	graphQLTimeout  = 10 * time.Second
+	graphQLMaxLimit = 1000 // 목록 필드의 limit 최대값
+)
+
+// graphQLEnabled GRAPHQL_ENABLED로 켜는 /api/graphql 엔드포인트 사용 여부
+var graphQLEnabled bool
+
+// InitGraphQL은 설정에 따라 GraphQL 엔드포인트를 켭니다
+func InitGraphQL(cfg *config.Config) {
+	graphQLEnabled = cfg.GraphQLEnabled
+}
+
+// GraphQLEnabled는 GraphQL 엔드포인트를 등록할지 반환합니다
+func GraphQLEnabled() bool {
+	return graphQLEnabled
+}
+
+// graphQLSchemaSDL은 graphSchema를 SDL로 나타낸 것입니다 (GET /api/graphql/schema, 프론트엔드 코드 생성용)
+const graphQLSchemaSDL = `scalar JSON
+
+type Query {
+  "A target linked to at least one category the token can read"
+  target(id: ID!): Target
+  "Archived targets are left out unless includeArchived is true"
+  targets(category: String, includeArchived: Boolean = false, limit: Int = 100, offset: Int = 0): [Target!]!
+  "Documents in one category, filtered and sorted like GET /api/v1/category/:category"
+  category(name: String!, version: Int, filter: [String!], sort: String, includeArchived: Boolean = false, limit: Int = 100, offset: Int = 0): [CategoryDocument!]!
+}
+
+type Target {
+  id: ID!
+  name: String!
+  createdAt: String!
+  updatedAt: String!
+  archivedAt: String
+  categories(names: [String!]): [CategoryDocument!]!
+  category(name: String!): CategoryDocument
+  "Newest first; since and until are RFC 3339 times"
+  observations(category: String!, since: String, until: String, limit: Int = 100): [Observation!]!
+}
+
+type CategoryDocument {
+  targetId: ID!
+  category: String!
+  version: Int!
+  "The whole document, or the value at a path such as vitals.bp"
+  data(path: String): JSON
+  createdAt: String!
+  updatedAt: String!
+  target: Target
+}
+
+type Observation {
+  targetId: ID!
+  category: String!
+  time: String!
+  payload(path: String): JSON
+}
+`
+
+// graphRequest는 리졸버가 쓰는 요청별 정보입니다
+type graphRequest struct {
+	orgID      string
+	categories []string        // 토큰이 읽을 수 있는 조직 카테고리
+	readable   map[string]bool // categories의 집합
+}
+
+type graphRequestKey struct{}
+
+func graphRequestFrom(ctx context.Context) *graphRequest {
+	req, _ := ctx.Value(graphRequestKey{}).(*graphRequest)
+	return req
+}
+
+// checkCategory는 토큰이 카테고리를 읽을 수 있는지 확인합니다
+func (r *graphRequest) checkCategory(category string) error {
+	if !r.readable[category] {
+		return fmt.Errorf("missing permission: %s", database.PermissionName("read", category))
+	}
+	return nil
+}
+
+// graphSchema는 타겟, 카테고리 문서, 관측값 그래프입니다
+var graphSchema = newGraphSchema()
+
+func newGraphSchema() *graphql.Schema {
+	targetType := &graphql.Object{Name: "Target"}
+	documentType := &graphql.Object{Name: "CategoryDocument"}
+	observationType := &graphql.Object{Name: "Observation"}
+
+	limitArgs := map[string]graphql.Arg{
+		"limit":  {Type: "Int", Default: 100},
+		"offset": {Type: "Int", Default: 0},
+	}
+	withLimit := func(args map[string]graphql.Arg) map[string]graphql.Arg {
+		for name, arg := range limitArgs {
+			args[name] = arg
+		}
+		return args
+	}
+
+	queryType := &graphql.Object{Name: "Query", Fields: map[string]*graphql.FieldDef{
+		"target": {
+			Type: targetType,
+			Args: map[string]graphql.Arg{"id": {Type: "ID!"}},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				req := graphRequestFrom(p.Context)
+				target, err := database.GetTarget(database.GetReadDB(), req.orgID, p.Args["id"].(string), req.categories)
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil, nil
+				}
+				return target, err
+			},
+		},
+		"targets": {
+			Type: targetType,
+			List: true,
+			Args: withLimit(map[string]graphql.Arg{
+				"category":        {Type: "String"},
+				"includeArchived": {Type: "Boolean"},
+			}),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				req := graphRequestFrom(p.Context)
+				limit, offset, err := limitArgsFrom(p.Args)
+				if err != nil {
+					return nil, err
+				}
+				categories := req.categories
+				if category, ok := p.Args["category"].(string); ok {
+					if err := req.checkCategory(category); err != nil {
+						return nil, err
+					}
+					categories = []string{category}
+				}
+				includeArchived, _ := p.Args["includeArchived"].(bool)
+				targets, err := database.ListTargets(database.GetReadDB(), req.orgID, categories, includeArchived, limit, offset)
+				if err != nil {
+					return nil, err
+				}
+				out := make([]*database.Target, len(targets))
+				for i := range targets {
+					out[i] = &targets[i]
+				}
+				return out, nil
+			},
+		},
+		"category": {
+			Type: documentType,
+			List: true,
+			Args: withLimit(map[string]graphql.Arg{
+				"name":            {Type: "String!"},
+				"version":         {Type: "Int"},
+				"filter":          {Type: "[String!]"},
+				"sort":            {Type: "String"},
+				"includeArchived": {Type: "Boolean"},
+			}),
+			Resolve: resolveCategoryDocuments,
+		},
+	}}
+
+	targetType.Fields = map[string]*graphql.FieldDef{
+		"id":   {Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(*database.Target).TargetID, nil }},
+		"name": {Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(*database.Target).Name, nil }},
+		"createdAt": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return graphTime(p.Source.(*database.Target).CreatedAt), nil
+		}},
+		"updatedAt": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return graphTime(p.Source.(*database.Target).UpdatedAt), nil
+		}},
+		"archivedAt": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			if archivedAt := p.Source.(*database.Target).ArchivedAt; archivedAt != nil {
+				return graphTime(*archivedAt), nil
+			}
+			return nil, nil
+		}},
+		"categories": {
+			Type: documentType,
+			List: true,
+			Args: map[string]graphql.Arg{"names": {Type: "[String!]"}},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				req := graphRequestFrom(p.Context)
+				categories := req.categories
+				if names, ok := p.Args["names"].([]interface{}); ok {
+					categories = nil
+					for _, name := range names {
+						if err := req.checkCategory(name.(string)); err != nil {
+							return nil, err
+						}
+						categories = append(categories, name.(string))
+					}
+				}
+				return targetDocuments(req, p.Source.(*database.Target).TargetID, categories)
+			},
+		},
+		"category": {
+			Type: documentType,
+			Args: map[string]graphql.Arg{"name": {Type: "String!"}},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				req := graphRequestFrom(p.Context)
+				name := p.Args["name"].(string)
+				if err := req.checkCategory(name); err != nil {
+					return nil, err
+				}
+				docs, err := targetDocuments(req, p.Source.(*database.Target).TargetID, []string{name})
+				if err != nil || len(docs) == 0 {
+					return nil, err
+				}
+				return docs[0], nil
+			},
+		},
+		"observations": {
+			Type: observationType,
+			List: true,
+			Args: map[string]graphql.Arg{
+				"category": {Type: "String!"},
+				"since":    {Type: "String"},
+				"until":    {Type: "String"},
+				"limit":    {Type: "Int", Default: 100},
+			},
+			Resolve: resolveObservations,
+		},
+	}
+
+	documentType.Fields = map[string]*graphql.FieldDef{
+		"targetId": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(*database.CategoryDocument).TargetID, nil
+		}},
+		"category": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(*database.CategoryDocument).Category, nil
+		}},
+		"version": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(*database.CategoryDocument).SchemaVersion, nil
+		}},
+		"createdAt": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return graphTime(p.Source.(*database.CategoryDocument).CreatedAt), nil
+		}},
+		"updatedAt": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return graphTime(p.Source.(*database.CategoryDocument).UpdatedAt), nil
+		}},
+		"data": {
+			Args: map[string]graphql.Arg{"path": {Type: "String"}},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return jsonPathValue(p.Source.(*database.CategoryDocument).Data, p.Args["path"])
+			},
+		},
+		"target": {
+			Type: targetType,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				req := graphRequestFrom(p.Context)
+				target, err := database.GetTarget(database.GetReadDB(), req.orgID, p.Source.(*database.CategoryDocument).TargetID, req.categories)
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil, nil
+				}
+				return target, err
+			},
+		},
+	}
+
+	observationType.Fields = map[string]*graphql.FieldDef{
+		"targetId": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(*database.Observation).TargetID, nil
+		}},
+		"category": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(*database.Observation).Category, nil
+		}},
+		"time": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return graphTime(p.Source.(*database.Observation).Time), nil
+		}},
+		"payload": {
+			Args: map[string]graphql.Arg{"path": {Type: "String"}},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return jsonPathValue(p.Source.(*database.Observation).Payload, p.Args["path"])
+			},
+		},
+	}
+
+	return &graphql.Schema{Query: queryType}
+}
+
+// resolveCategoryDocuments는 카테고리 문서를 REST API와 같은 필터/정렬 문법으로 조회합니다
+func resolveCategoryDocuments(p graphql.ResolveParams) (interface{}, error) {
+	req := graphRequestFrom(p.Context)
+	name := p.Args["name"].(string)
+	if err := req.checkCategory(name); err != nil {
+		return nil, err
+	}
+	limit, offset, err := limitArgsFrom(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	var filters []string
+	if list, ok := p.Args["filter"].([]interface{}); ok {
+		for _, item := range list {
+			filters = append(filters, item.(string))
+		}
+	}
+	sort, _ := p.Args["sort"].(string)
+	q, err := query.Parse(filters, sort)
+	if err != nil {
+		return nil, err
+	}
+	q.IncludeArchived, _ = p.Args["includeArchived"].(bool)
+	filterRecorder.Record(req.orgID, name, q)
+
+	versionCtx := &middleware.VersionContext{RequestedVersion: "all"}
+	if version, ok := p.Args["version"].(int); ok {
+		versionCtx.RequestedVersion = "v" + strconv.Itoa(version)
+	}
+
+	s := query.NewSelect("target_id::text, category_name, schema_version, category_data::text, created_at, updated_at",
+		"target_categories")
+	if err := buildCategoryWhere(s, req.orgID, name, versionCtx, q); err != nil {
+		return nil, err
+	}
+	stmt, args := s.OrderBy(q.OrderBy(defaultCategoryOrder, "target_id DESC")).Limit(limit).Offset(offset).SQL()
+	rows, err := database.GetReadDB().QueryContext(p.Context, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []*database.CategoryDocument
+	for rows.Next() {
+		doc := &database.CategoryDocument{}
+		var dataJSON string
+		if err := rows.Scan(&doc.TargetID, &doc.Category, &doc.SchemaVersion, &dataJSON, &doc.CreatedAt, &doc.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(dataJSON), &doc.Data); err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
+// resolveObservations는 타겟의 최근 관측값을 조회합니다
+func resolveObservations(p graphql.ResolveParams) (interface{}, error) {
+	req := graphRequestFrom(p.Context)
+	category := p.Args["category"].(string)
+	if err := req.checkCategory(category); err != nil {
+		return nil, err
+	}
+	limit, _, err := limitArgsFrom(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	var bounds [2]*time.Time
+	for i, name := range []string{"since", "until"} {
+		value, ok := p.Args[name].(string)
+		if !ok {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q must be an RFC 3339 time", name)
+		}
+		bounds[i] = &t
+	}
+
+	observations, err := database.ListObservations(database.GetReadDB(), req.orgID,
+		p.Source.(*database.Target).TargetID, category, bounds[0], bounds[1], limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*database.Observation, len(observations))
+	for i := range observations {
+		out[i] = &observations[i]
+	}
+	return out, nil
+}
+
+// targetDocuments는 타겟의 카테고리 문서를 조회합니다
+func targetDocuments(req *graphRequest, targetID string, categories []string) ([]*database.CategoryDocument, error) {
+	docs, err := database.ListTargetCategoryDocuments(database.GetReadDB(), req.orgID, targetID, categories)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*database.CategoryDocument, len(docs))
+	for i := range docs {
+		out[i] = &docs[i]
+	}
+	return out, nil
+}
+
+// limitArgsFrom은 limit, offset 인자를 확인합니다
+func limitArgsFrom(args map[string]interface{}) (limit, offset int, err error) {
+	limit, _ = args["limit"].(int)
+	offset, _ = args["offset"].(int)
+	if limit < 1 || limit > graphQLMaxLimit {
+		return 0, 0, fmt.Errorf("limit must be between 1 and %d", graphQLMaxLimit)
+	}
+	if offset < 0 {
+		return 0, 0, errors.New("offset must not be negative")
+	}
+	return limit, offset, nil
+}
+
+// jsonPathValue는 문서에서 path(vitals.bp, tags.0)의 값을 꺼냅니다 (path가 없으면 문서 전체, 없는 경로는 null)
+func jsonPathValue(doc interface{}, path interface{}) (interface{}, error) {
+	expr, ok := path.(string)
+	if !ok {
+		return doc, nil
+	}
+	field, err := query.ParseField(expr)
+	if err != nil || field.Column != "" {
+		return nil, fmt.Errorf("invalid path %q", expr)
+	}
+
+	value := doc
+	for _, segment := range field.Path {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[segment]
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, nil
+			}
+			value = v[index]
+		default:
+			return nil, nil
+		}
+	}
+	return value, nil
+}
+
+func graphTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// GraphQL은 타겟, 카테고리 문서, 관측값 그래프에 대한 GraphQL 요청을 실행합니다.
+// 응답은 GraphQL 형식({"data", "errors"})이며, 요청을 파싱하거나 검증하지 못하면 400을 반환합니다.
+func GraphQL(c *fiber.Ctx) error {
+	var req graphql.Request
+	if c.Method() == fiber.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return graphQLError(c, fiber.StatusBadRequest, "variables must be a JSON object")
+			}
+		}
+	} else if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return graphQLError(c, fiber.StatusBadRequest, "request body must be a JSON object with a query")
+	}
+	if req.Query == "" {
+		return graphQLError(c, fiber.StatusBadRequest, "query is required")
+	}
+
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return graphQLError(c, fiber.StatusUnauthorized, err.Error())
+	}
+	names, err := database.ListOrgCategoryNames(database.GetReadDB(), orgID)
+	if err != nil {
+		return graphQLError(c, fiber.StatusInternalServerError, err.Error())
+	}
+	greq := &graphRequest{orgID: orgID, readable: make(map[string]bool)}
+	for _, name := range names {
+		if middleware.CategoryAllowed(c, "read", name) {
+			greq.categories = append(greq.categories, name)
+			greq.readable[name] = true
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), graphQLTimeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, graphRequestKey{}, greq)
+
+	resp, ok := graphql.Execute(ctx, graphSchema, req)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(resp)
+	}
+	return c.JSON(resp)
+}
+
+// GraphQLSchema는 GraphQL 스키마를 SDL로 반환합니다
+func GraphQLSchema(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, "application/graphql; charset=utf-8")
+	return c.SendString(graphQLSchemaSDL)
+}
+
+func graphQLError(c *fiber.Ctx, status int, message string) error {
+	return c.Status(status).JSON(graphql.Response{Errors: []*graphql.Error{{Message: message}}})
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/database"
+)
+
+// 문서 이력 조회 제한
+const (
+	defaultHistoryRevisions = 50
+	maxHistoryRevisions     = 500
+)
+
+// documentParams는 요청 경로의 조직과 타겟을 읽습니다.
+// 잘못되면 에러 응답을 보내고 빈 조직과 전송 결과를 반환합니다.
+func documentParams(c *fiber.Ctx) (string, string, error) {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return "", "", sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	targetID := c.Params("target_id")
+	if !uuidPattern.MatchString(targetID) {
+		return "", "", sendErrorResponse(c, "INVALID_REQUEST", "Invalid target id", targetID)
+	}
+	return orgID, targetID, nil
+}
+
+// revisionNotFound는 문서 이력이 없을 때의 응답입니다
+func revisionNotFound(c *fiber.Ctx, targetID, category, detail string) error {
+	return sendErrorResponse(c, "TARGET_NOT_FOUND",
+		fmt.Sprintf("No history of target %s in category %s", targetID, category), detail)
+}
+
+// GetDocumentHistory는 카테고리 문서의 리비전 목록을 최신 순으로 조회합니다.
+// 각 리비전에는 작업(insert, update, delete, restore), 시각, 바꾼 주체가 있고 include_data=true면 그때의 문서도 포함합니다.
+// before=<리비전>으로 이전 페이지를 읽습니다.
+func GetDocumentHistory(c *fiber.Ctx) error {
+	orgID, targetID, err := documentParams(c)
+	if orgID == "" {
+		return err
+	}
+	category := c.Params("category")
+	limit, err := strconv.Atoi(c.Query("limit", strconv.Itoa(defaultHistoryRevisions)))
+	if err != nil || limit < 1 || limit > maxHistoryRevisions {
+		return sendErrorResponse(c, "INVALID_REQUEST", "limit must be between 1 and "+strconv.Itoa(maxHistoryRevisions), "")
+	}
+	before, err := strconv.Atoi(c.Query("before", "0"))
+	if err != nil || before < 0 {
+		return sendErrorResponse(c, "INVALID_REQUEST", "before must be a revision number", "")
+	}
+
+	revisions, err := database.ListDocumentRevisions(database.GetReadDB(), orgID, targetID, category, before, limit,
+		c.QueryBool("include_data"))
+	if err != nil {
+		log.Printf("Error listing history of %s/%s: %v", targetID, category, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to query history", "")
+	}
+	if len(revisions) == 0 && before == 0 {
+		return revisionNotFound(c, targetID, category, "")
+	}
+	return sendSuccessResponse(c, revisions, nil)
+}
+
+// GetDocumentRevision은 카테고리 문서의 리비전 하나를 그때의 문서와 함께 조회합니다
+func GetDocumentRevision(c *fiber.Ctx) error {
+	orgID, targetID, err := documentParams(c)
+	if orgID == "" {
+		return err
+	}
+	category := c.Params("category")
+	revision, err := strconv.Atoi(c.Params("revision"))
+	if err != nil || revision < 1 {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Invalid revision", c.Params("revision"))
+	}
+
+	rev, err := database.GetDocumentRevision(database.GetReadDB(), orgID, targetID, category, revision)
+	if err == sql.ErrNoRows {
+		return revisionNotFound(c, targetID, category, "revision "+strconv.Itoa(revision))
+	}
+	if err != nil {
+		log.Printf("Error reading revision %d of %s/%s: %v", revision, targetID, category, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to query history", "")
+	}
+	return sendSuccessResponse(c, rev, nil)
+}
+
+// GetDocumentAsOf는 ts(RFC3339) 시점의 카테고리 문서를 조회합니다.
+// 그때 문서가 없었거나 삭제된 상태였으면 TARGET_NOT_FOUND입니다.
+func GetDocumentAsOf(c *fiber.Ctx) error {
+	orgID, targetID, err := documentParams(c)
+	if orgID == "" {
+		return err
+	}
+	category := c.Params("category")
+	at, err := time.Parse(time.RFC3339Nano, c.Query("ts"))
+	if err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", "ts must be an RFC3339 time", err.Error())
+	}
+
+	rev, err := database.GetDocumentAsOf(database.GetReadDB(), orgID, targetID, category, at)
+	if err == sql.ErrNoRows {
+		return revisionNotFound(c, targetID, category, "no revision at or before "+at.Format(time.RFC3339))
+	}
+	if err != nil {
+		log.Printf("Error reading %s/%s as of %s: %v", targetID, category, at, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to query history", "")
+	}
+	if !rev.Exists() {
+		return sendErrorResponse(c, "TARGET_NOT_FOUND",
+			fmt.Sprintf("Target %s was deleted from category %s at %s", targetID, category, at.Format(time.RFC3339)),
+			"deleted in revision "+strconv.Itoa(rev.Revision))
+	}
+	return sendSuccessResponse(c, rev, nil)
+}
+
+// DiffDocumentRevisions는 카테고리 문서의 두 리비전 사이에 바뀐 값을 경로별로 반환합니다.
+// from은 필수이고, to가 없으면 마지막 리비전과 비교합니다.
+func DiffDocumentRevisions(c *fiber.Ctx) error {
+	orgID, targetID, err := documentParams(c)
+	if orgID == "" {
+		return err
+	}
+	category := c.Params("category")
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from < 1 {
+		return sendErrorResponse(c, "INVALID_REQUEST", "from must be a revision number", "")
+	}
+	to := 0
+	if value := c.Query("to"); value != "" {
+		if to, err = strconv.Atoi(value); err != nil || to < 1 {
+			return sendErrorResponse(c, "INVALID_REQUEST", "to must be a revision number", "")
+		}
+	}
+
+	db := database.GetReadDB()
+	older, err := database.GetDocumentRevision(db, orgID, targetID, category, from)
+	if err == sql.ErrNoRows {
+		return revisionNotFound(c, targetID, category, "revision "+strconv.Itoa(from))
+	}
+	if err != nil {
+		log.Printf("Error reading revision %d of %s/%s: %v", from, targetID, category, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to query history", "")
+	}
+	var newer *database.DocumentRevision
+	if to == 0 {
+		newer, err = database.GetLatestDocumentRevision(db, orgID, targetID, category)
+	} else {
+		newer, err = database.GetDocumentRevision(db, orgID, targetID, category, to)
+	}
+	if err == sql.ErrNoRows {
+		return revisionNotFound(c, targetID, category, "revision "+strconv.Itoa(to))
+	}
+	if err != nil {
+		log.Printf("Error reading revision %d of %s/%s: %v", to, targetID, category, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to query history", "")
+	}
+
+	return sendSuccessResponse(c, fiber.Map{
+		"target_id": targetID,
+		"category":  category,
+		"from":      revisionSummary(older),
+		"to":        revisionSummary(newer),
+		"changes":   database.DiffDocuments(older.Data, newer.Data),
+	}, nil)
+}
+
+// revisionSummary는 diff 응답에 넣을 리비전 정보입니다 (문서 제외)
+func revisionSummary(rev *database.DocumentRevision) fiber.Map {
+	return fiber.Map{
+		"revision":       rev.Revision,
+		"operation":      rev.Operation,
+		"schema_version": rev.SchemaVersion,
+		"changed_at":     rev.ChangedAt,
+		"changed_by":     rev.ChangedBy,
+	}
+}
@@ -0,0 +1,586 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/importer"
+	"github.com/tmidb/tmidb-core/internal/schema"
+	"github.com/tmidb/tmidb-core/internal/storage"
+)
+
+const (
+	// MaxImportChunkSize 조각 하나의 최대 크기 (fiber BodyLimit 안)
+	MaxImportChunkSize = MaxBulkBodySize
+	// importUploadIdle 이 시간 동안 다음 조각이 없으면 업로드를 버림
+	importUploadIdle = 24 * time.Hour
+	// importRetention 끝난 작업(결과와 줄별 오류)을 보관하는 시간
+	importRetention = 7 * 24 * time.Hour
+	// importTimeout 가져오기 하나의 최대 실행 시간
+	importTimeout = 2 * time.Hour
+	// importBatchSize COPY 한 번에 넣는 관측값 수
+	importBatchSize = 5000
+	// maxImportRowErrors 작업에 저장하는 줄별 오류 수 (나머지는 rows_failed로만 셈)
+	maxImportRowErrors = 1000
+)
+
+// importSlots 이 API 인스턴스에서 동시에 실행하는 가져오기 수 (나머지는 대기)
+var importSlots = make(chan struct{}, 2)
+
+// createImportRequest는 가져오기 작업 생성 요청입니다
+type createImportRequest struct {
+	Format     string            `json:"format"`
+	Mapping    *importer.Mapping `json:"mapping,omitempty"`
+	SourcePath string            `json:"source_path,omitempty"` // SeaweedFS에 이미 있는 파일 (없으면 조각으로 업로드)
+	Size       *int64            `json:"size,omitempty"`        // 올릴 파일 크기 (정하면 완료 요청 때 확인)
+}
+
+// CreateImportJob은 CSV, NDJSON 파일을 카테고리 시계열로 가져오는 작업을 만듭니다.
+//   - JSON 본문: 작업만 만들고 PUT /import/jobs/:job_id/chunks로 조각을 올린 뒤 complete를 호출합니다.
+//     source_path가 있으면 SeaweedFS의 그 파일을 바로 가져옵니다.
+//   - CSV, NDJSON 본문: 본문 전체를 파일로 보고 바로 가져옵니다 (?format=, ?mapping=).
+func CreateImportJob(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	if fileStorage == nil {
+		return sendErrorResponse(c, "STORAGE_ERROR", "File storage is not configured", "")
+	}
+
+	var req createImportRequest
+	upload := !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON)
+	if upload {
+		req.Format = c.Query("format", importFormatFromContentType(c.Get(fiber.HeaderContentType)))
+		if raw := c.Query("mapping"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &req.Mapping); err != nil {
+				return sendErrorResponse(c, "INVALID_REQUEST", "Invalid mapping", err.Error())
+			}
+		}
+		if len(c.Body()) == 0 {
+			return sendErrorResponse(c, "INVALID_REQUEST", "Request body is empty", "")
+		}
+	} else if err := c.BodyParser(&req); err != nil {
+		return sendErrorResponse(c, "INVALID_JSON", "Invalid import request", err.Error())
+	}
+
+	if !importer.ValidFormat(req.Format) {
+		return sendErrorResponse(c, "INVALID_REQUEST", "format must be csv or ndjson", req.Format)
+	}
+	if req.Mapping == nil {
+		req.Mapping = &importer.Mapping{}
+	}
+	if err := req.Mapping.Validate(); err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Invalid mapping", err.Error())
+	}
+	if req.SourcePath != "" {
+		if req.SourcePath, err = orgStoragePath(orgID, req.SourcePath); err != nil {
+			return sendErrorResponse(c, "INVALID_REQUEST", err.Error(), "")
+		}
+	}
+	if req.Size != nil && *req.Size <= 0 {
+		return sendErrorResponse(c, "INVALID_REQUEST", "size must be positive", "")
+	}
+
+	expireImportJobs()
+	mapping, _ := json.Marshal(req.Mapping)
+	job := &database.ImportJob{
+		OrgID:         orgID,
+		Category:      c.Params("category"),
+		Format:        req.Format,
+		Mapping:       mapping,
+		SourcePath:    req.SourcePath,
+		ExpectedBytes: req.Size,
+		CreatedBy:     targetActor(c),
+	}
+	db := database.GetDB()
+	if err := database.CreateImportJob(db, job, importUploadIdle); err != nil {
+		log.Printf("Error creating import job: %v", err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to create import job", "")
+	}
+	// 레코드 수는 가져오기가 끝난 뒤에 더함
+	middleware.RecordIngested(c, 0)
+
+	if upload {
+		if _, err := storeImportChunk(c.UserContext(), job, 0, c.Body()); err != nil {
+			return importChunkError(c, job, err)
+		}
+		if err := database.QueueImportJob(db, orgID, job.JobID); err != nil {
+			return sendErrorResponse(c, "DATABASE_ERROR", "Failed to queue import job", err.Error())
+		}
+		job.Status = database.ImportStatusQueued
+	}
+	if job.Status == database.ImportStatusQueued {
+		go runImportJob(job.JobID, orgID)
+	}
+
+	statusURL := path.Join(c.Path(), "..", "jobs", job.JobID)
+	c.Set(fiber.HeaderLocation, statusURL)
+	c.Status(fiber.StatusAccepted)
+	return sendSuccessResponse(c, fiber.Map{"job": job, "status_url": statusURL}, nil)
+}
+
+// importFormatFromContentType은 본문의 Content-Type으로 형식을 정합니다
+func importFormatFromContentType(contentType string) string {
+	if strings.Contains(contentType, "ndjson") || strings.Contains(contentType, "jsonlines") {
+		return importer.FormatNDJSON
+	}
+	if strings.Contains(contentType, "csv") {
+		return importer.FormatCSV
+	}
+	return ""
+}
+
+// orgStoragePath는 SeaweedFS 경로가 조직의 디렉터리(<종류>/<org_id>/...) 안에 있는지 확인합니다
+func orgStoragePath(orgID, p string) (string, error) {
+	cleaned := strings.TrimPrefix(path.Clean("/"+p), "/")
+	parts := strings.Split(cleaned, "/")
+	if len(parts) < 3 || parts[1] != orgID {
+		return "", fmt.Errorf("source_path must be a file in a directory of your organization, such as exports/%s/...", orgID)
+	}
+	return cleaned, nil
+}
+
+// UploadImportChunk는 업로드 중인 작업에 파일 조각을 더합니다. offset은 지금까지 받은 크기와 같아야 하고,
+// 다르면 409와 함께 받은 크기를 돌려주므로 클라이언트는 그 위치부터 이어 올리면 됩니다.
+func UploadImportChunk(c *fiber.Ctx) error {
+	job, err := lookupImportJob(c, "write")
+	if job == nil {
+		return err
+	}
+	offset, err := parseOffset(c.Query("offset"))
+	if err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", "offset must be a non-negative integer", c.Query("offset"))
+	}
+	if len(c.Body()) == 0 {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Chunk is empty", "")
+	}
+	if len(c.Body()) > MaxImportChunkSize {
+		return sendErrorResponse(c, "FILE_TOO_LARGE", fmt.Sprintf("Chunk exceeds %d bytes", MaxImportChunkSize), "")
+	}
+	if job.Status != database.ImportStatusUploading || offset != job.ReceivedBytes {
+		return importUploadConflict(c, job)
+	}
+	middleware.RecordIngested(c, 0)
+
+	received, err := storeImportChunk(c.UserContext(), job, offset, c.Body())
+	if err != nil {
+		return importChunkError(c, job, err)
+	}
+	return sendSuccessResponse(c, fiber.Map{"job_id": job.JobID, "received_bytes": received}, nil)
+}
+
+// parseOffset은 조각의 시작 위치를 읽습니다
+func parseOffset(s string) (int64, error) {
+	offset, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || offset < 0 {
+		return 0, errors.New("invalid offset")
+	}
+	return offset, nil
+}
+
+// errImportChunkStorage 조각을 SeaweedFS에 저장하지 못함
+var errImportChunkStorage = errors.New("failed to store chunk")
+
+// storeImportChunk는 조각을 SeaweedFS에 저장하고 작업에 더한 뒤 받은 크기를 반환합니다.
+// 조각마다 다른 경로에 저장하므로 같은 offset을 동시에 올려도 먼저 기록된 조각만 남습니다.
+func storeImportChunk(ctx context.Context, job *database.ImportJob, offset int64, body []byte) (int64, error) {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	chunkPath := path.Join("imports", job.OrgID, job.JobID, fmt.Sprintf("%015d-%s.part", offset, hex.EncodeToString(suffix)))
+	if err := fileStorage.Put(ctx, chunkPath, bytes.NewReader(body), int64(len(body)), "application/octet-stream"); err != nil {
+		log.Printf("❌ 가져오기 조각 저장 실패 (%s): %v", chunkPath, err)
+		return 0, errImportChunkStorage
+	}
+
+	received, err := database.AppendImportChunk(database.GetDB(), job.OrgID, job.JobID, offset, int64(len(body)), chunkPath, importUploadIdle)
+	if err != nil {
+		deleteImportChunks([]string{chunkPath})
+		return 0, err
+	}
+	return received, nil
+}
+
+// importChunkError는 storeImportChunk의 실패를 응답으로 보냅니다
+func importChunkError(c *fiber.Ctx, job *database.ImportJob, err error) error {
+	switch {
+	case errors.Is(err, errImportChunkStorage):
+		return sendErrorResponse(c, "STORAGE_ERROR", "Failed to store chunk", "")
+	case errors.Is(err, database.ErrImportUploadConflict):
+		// 다른 요청이 먼저 조각을 더했으므로 지금 받은 크기를 알려줌
+		if current, getErr := database.GetImportJob(database.GetDB(), job.OrgID, job.JobID); getErr == nil {
+			job = current
+		}
+		return importUploadConflict(c, job)
+	default:
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to record chunk", err.Error())
+	}
+}
+
+// importUploadConflict는 조각을 받을 수 없는 이유와 이어 올릴 위치를 보냅니다
+func importUploadConflict(c *fiber.Ctx, job *database.ImportJob) error {
+	if job.Status != database.ImportStatusUploading {
+		return sendErrorResponse(c, "UPLOAD_CONFLICT", "Import job is not accepting uploads", job.Status)
+	}
+	if job.ExpectedBytes != nil && job.ReceivedBytes+int64(len(c.Body())) > *job.ExpectedBytes {
+		return sendErrorResponse(c, "UPLOAD_CONFLICT", "Chunk goes past the declared size",
+			fmt.Sprintf("received_bytes=%d size=%d", job.ReceivedBytes, *job.ExpectedBytes))
+	}
+	return sendErrorResponse(c, "UPLOAD_CONFLICT", "Chunk does not start at the received size; resume from received_bytes",
+		fmt.Sprintf("received_bytes=%d", job.ReceivedBytes))
+}
+
+// CompleteImportUpload는 업로드를 마치고 가져오기를 시작합니다
+func CompleteImportUpload(c *fiber.Ctx) error {
+	job, err := lookupImportJob(c, "write")
+	if job == nil {
+		return err
+	}
+	if job.Status != database.ImportStatusUploading {
+		return sendErrorResponse(c, "UPLOAD_CONFLICT", "Import job is not accepting uploads", job.Status)
+	}
+	if job.ReceivedBytes == 0 {
+		return sendErrorResponse(c, "INVALID_REQUEST", "No data has been uploaded", "")
+	}
+	if job.ExpectedBytes != nil && job.ReceivedBytes != *job.ExpectedBytes {
+		return sendErrorResponse(c, "UPLOAD_CONFLICT", "Upload is incomplete",
+			fmt.Sprintf("received_bytes=%d size=%d", job.ReceivedBytes, *job.ExpectedBytes))
+	}
+	if err := database.QueueImportJob(database.GetDB(), job.OrgID, job.JobID); err != nil {
+		if errors.Is(err, database.ErrImportUploadConflict) {
+			return sendErrorResponse(c, "UPLOAD_CONFLICT", "Import job changed while completing", "")
+		}
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to queue import job", err.Error())
+	}
+	job.Status = database.ImportStatusQueued
+	middleware.RecordIngested(c, 0)
+	go runImportJob(job.JobID, job.OrgID)
+
+	c.Status(fiber.StatusAccepted)
+	return sendSuccessResponse(c, fiber.Map{"job": job}, nil)
+}
+
+// GetImportJob은 가져오기 작업의 상태, 진행 상황과 줄별 오류를 반환합니다
+func GetImportJob(c *fiber.Ctx) error {
+	job, err := lookupImportJob(c, "read")
+	if job == nil {
+		return err
+	}
+	return sendSuccessResponse(c, fiber.Map{"job": job}, nil)
+}
+
+// lookupImportJob은 요청 경로의 가져오기 작업을 조직 범위로 조회하고 카테고리 권한을 확인합니다.
+// 찾지 못하면 에러 응답을 보내고 nil 작업과 전송 결과를 반환합니다.
+func lookupImportJob(c *fiber.Ctx, permission string) (*database.ImportJob, error) {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return nil, sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	if fileStorage == nil {
+		return nil, sendErrorResponse(c, "STORAGE_ERROR", "File storage is not configured", "")
+	}
+	job, err := database.GetImportJob(database.GetDB(), orgID, c.Params("job_id"))
+	if errors.Is(err, database.ErrImportJobNotFound) {
+		return nil, sendErrorResponse(c, "FILE_NOT_FOUND", "Import job not found", c.Params("job_id"))
+	}
+	if err != nil {
+		return nil, sendErrorResponse(c, "DATABASE_ERROR", "Failed to look up import job", err.Error())
+	}
+	if !middleware.CategoryAllowed(c, permission, job.Category) {
+		return nil, middleware.PermissionDenied(c, permission, job.Category)
+	}
+	return job, nil
+}
+
+// importRun은 실행 중인 가져오기의 상태입니다
+type importRun struct {
+	db        *sql.DB
+	job       *database.ImportJob
+	schemas   map[string]importTarget
+	batch     []database.ImportObservation
+	lines     []int // batch와 같은 순서의 줄 번호
+	targets   map[string]bool
+	read      int64
+	imported  int64
+	failed    int64
+	rowErrors []database.ImportRowError
+}
+
+// importTarget은 타겟이 쓰는 카테고리 스키마입니다 (타겟별로 한 번만 조회)
+type importTarget struct {
+	schema *schema.Schema
+	err    error
+}
+
+// runImportJob은 작업의 파일을 읽어 검증한 뒤 COPY로 저장하고 결과를 작업에 기록합니다
+func runImportJob(jobID, orgID string) {
+	importSlots <- struct{}{}
+	defer func() { <-importSlots }()
+
+	db := database.GetDB()
+	job, err := database.GetImportJob(db, orgID, jobID)
+	if err != nil {
+		log.Printf("❌ 가져오기 작업 조회 실패 (%s): %v", jobID, err)
+		return
+	}
+	if err := database.StartImportJob(db, jobID); err != nil {
+		log.Printf("⚠️ 가져오기 작업 시작 기록 실패 (%s): %v", jobID, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), importTimeout)
+	defer cancel()
+
+	run := &importRun{db: db, job: job, schemas: make(map[string]importTarget), targets: make(map[string]bool)}
+	start := time.Now()
+	err = run.execute(ctx)
+	if err != nil {
+		log.Printf("❌ 가져오기 작업 실패 (%s): %v", jobID, err)
+	} else {
+		log.Printf("📥 Import job %s finished: %d rows imported, %d failed in %s",
+			jobID, run.imported, run.failed, time.Since(start).Round(time.Millisecond))
+	}
+	if err := database.FinishImportJob(db, jobID, run.read, run.imported, run.failed, run.rowErrors, importRetention, err); err != nil {
+		log.Printf("⚠️ 가져오기 작업 결과 기록 실패 (%s): %v", jobID, err)
+	}
+	deleteImportChunks(job.ChunkPaths)
+
+	middleware.AddIngested(orgID, run.imported, 0)
+	if run.imported > 0 {
+		targets := make([]string, 0, len(run.targets))
+		for targetID := range run.targets {
+			targets = append(targets, targetID)
+		}
+		invalidateCache([]string{job.Category}, targets)
+	}
+}
+
+// execute는 파일을 끝까지 읽습니다. 반환하는 에러는 작업 전체의 실패이고, 줄별 실패는 rowErrors에 쌓입니다.
+func (r *importRun) execute(ctx context.Context) error {
+	source, err := r.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	var mapping importer.Mapping
+	if len(r.job.Mapping) > 0 {
+		if err := json.Unmarshal(r.job.Mapping, &mapping); err != nil {
+			return fmt.Errorf("invalid mapping: %v", err)
+		}
+	}
+	reader, err := importer.NewReader(r.job.Format, source, mapping)
+	if err != nil {
+		return err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("import stopped after %d rows: %v", r.read, err)
+		}
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		var rowErr *importer.RowError
+		if errors.As(err, &rowErr) {
+			r.read++
+			r.fail(database.ImportRowError{Line: rowErr.Line, Error: rowErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		r.read++
+		if err := r.add(record); err != nil {
+			return err
+		}
+		if len(r.batch) >= importBatchSize {
+			if err := r.flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return r.flush()
+}
+
+// open은 가져올 파일을 엽니다 (SeaweedFS의 원본 파일 또는 올린 조각을 이어 읽음)
+func (r *importRun) open(ctx context.Context) (io.ReadCloser, error) {
+	if r.job.SourcePath != "" {
+		obj, err := fileStorage.Get(ctx, r.job.SourcePath)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("source file %s not found", r.job.SourcePath)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return obj.Body, nil
+	}
+	return &chunkReader{ctx: ctx, paths: r.job.ChunkPaths}, nil
+}
+
+// add는 레코드를 타겟 스키마로 검증하고 배치에 더합니다
+func (r *importRun) add(record *importer.Record) error {
+	target, ok := r.schemas[record.TargetID]
+	if !ok {
+		definition, err := database.GetOrgTargetCategorySchema(r.db, r.job.OrgID, record.TargetID, r.job.Category)
+		switch {
+		case err == sql.ErrNoRows:
+			target.err = fmt.Errorf("target is not linked to category %s", r.job.Category)
+		case err != nil:
+			return fmt.Errorf("failed to load category schema: %v", err)
+		default:
+			if target.schema, err = schema.Cached(definition); err != nil {
+				target.err = fmt.Errorf("invalid schema format: %v", err)
+			}
+		}
+		r.schemas[record.TargetID] = target
+	}
+
+	rowErr := database.ImportRowError{Line: record.Line, TargetID: record.TargetID}
+	if target.err != nil {
+		rowErr.Error = target.err.Error()
+		r.fail(rowErr)
+		return nil
+	}
+	payload, err := record.Payload(target.schema.TypesAt)
+	if err == nil {
+		err = target.schema.Validate(payload)
+	}
+	if err != nil {
+		rowErr.Error = err.Error()
+		var validationErr *schema.ValidationError
+		if errors.As(err, &validationErr) {
+			rowErr.Error = "payload does not match category schema"
+			rowErr.Fields = validationErr.Errors
+		}
+		r.fail(rowErr)
+		return nil
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		rowErr.Error = fmt.Sprintf("failed to encode payload: %v", err)
+		r.fail(rowErr)
+		return nil
+	}
+	r.batch = append(r.batch, database.ImportObservation{TargetID: record.TargetID, Ts: record.Ts, Payload: encoded})
+	r.lines = append(r.lines, record.Line)
+	return nil
+}
+
+// flush는 배치를 COPY로 저장합니다. COPY가 실패하면 어떤 줄이 문제인지 알 수 있도록
+// 대량 수집 API처럼 레코드마다 세이브포인트를 두고 다시 넣습니다.
+func (r *importRun) flush() error {
+	if len(r.batch) == 0 {
+		return nil
+	}
+	if err := database.CopyObservations(r.db, r.job.Category, r.batch); err != nil {
+		log.Printf("⚠️ 가져오기 COPY 실패, 레코드별로 다시 시도 (%s): %v", r.job.JobID, err)
+		items := make([]*bulkItem, len(r.batch))
+		for i, o := range r.batch {
+			items[i] = &bulkItem{record: BulkRecord{TargetID: o.TargetID, Payload: json.RawMessage(o.Payload)}, ts: o.Ts}
+		}
+		if err := insertBulkBatch(r.db, r.job.Category, items); err != nil {
+			return err
+		}
+		for i, item := range items {
+			if item.err != nil {
+				r.fail(database.ImportRowError{Line: r.lines[i], TargetID: item.record.TargetID, Error: item.err.Error()})
+				continue
+			}
+			r.imported++
+			r.targets[item.record.TargetID] = true
+		}
+	} else {
+		r.imported += int64(len(r.batch))
+		for _, o := range r.batch {
+			r.targets[o.TargetID] = true
+		}
+	}
+	r.batch, r.lines = r.batch[:0], r.lines[:0]
+
+	if err := database.UpdateImportProgress(r.db, r.job.JobID, r.read, r.imported, r.failed); err != nil {
+		log.Printf("⚠️ 가져오기 진행 상황 기록 실패 (%s): %v", r.job.JobID, err)
+	}
+	return nil
+}
+
+// fail은 줄 하나의 실패를 셉니다 (오류 내용은 maxImportRowErrors개까지만 보관)
+func (r *importRun) fail(rowErr database.ImportRowError) {
+	r.failed++
+	if len(r.rowErrors) < maxImportRowErrors {
+		r.rowErrors = append(r.rowErrors, rowErr)
+	}
+}
+
+// chunkReader는 SeaweedFS에 올린 조각을 순서대로 이어 읽습니다 (한 번에 조각 하나만 엶)
+type chunkReader struct {
+	ctx     context.Context
+	paths   []string
+	current io.ReadCloser
+}
+
+func (cr *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if cr.current == nil {
+			if len(cr.paths) == 0 {
+				return 0, io.EOF
+			}
+			obj, err := fileStorage.Get(cr.ctx, cr.paths[0])
+			if err != nil {
+				return 0, fmt.Errorf("failed to read uploaded chunk %s: %v", cr.paths[0], err)
+			}
+			cr.current, cr.paths = obj.Body, cr.paths[1:]
+		}
+		n, err := cr.current.Read(p)
+		if err == io.EOF {
+			cr.current.Close()
+			cr.current = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (cr *chunkReader) Close() error {
+	if cr.current != nil {
+		return cr.current.Close()
+	}
+	return nil
+}
+
+// deleteImportChunks는 올린 조각을 지웁니다 (이미 없는 조각은 무시)
+func deleteImportChunks(paths []string) {
+	for _, p := range paths {
+		if err := fileStorage.Delete(context.Background(), p); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("⚠️ 가져오기 조각 삭제 실패 (%s): %v", p, err)
+		}
+	}
+}
+
+// expireImportJobs는 버려진 업로드와 만료된 가져오기 작업을 정리합니다 (작업을 만들 때마다 호출)
+func expireImportJobs() {
+	paths, err := database.ExpireImportJobs(database.GetDB(), importTimeout+10*time.Minute, importRetention)
+	if err != nil {
+		log.Printf("⚠️ 만료된 가져오기 작업 정리 실패: %v", err)
+		return
+	}
+	deleteImportChunks(paths)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/tmidb/tmidb-core/internal/api/middleware"
 	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/query"
 )
 
 // ListenerData는 리스너 데이터 구조입니다
@@ -57,6 +58,9 @@ func GetSingleListenerData(c *fiber.Ctx) error {
 		}
 		return sendErrorResponse(c, "DATABASE_ERROR", err.Error(), "")
 	}
+	if category := deniedListenerCategory(c, listenerConfig); category != "" {
+		return middleware.PermissionDenied(c, "read", category)
+	}
 
 	// 버전 정보 가져오기
 	versionCtx := middleware.GetVersionContext(c)
@@ -128,6 +132,10 @@ func GetMultiListenerData(c *fiber.Ctx) error {
 		if err != nil {
 			continue // 에러 리스너는 스킵
 		}
+		// 권한이 없는 카테고리가 하나라도 있으면 전체 요청을 거부
+		if category := deniedListenerCategory(c, listenerConfig); category != "" {
+			return middleware.PermissionDenied(c, "read", category)
+		}
 
 		// 리스너 데이터 조회
 		data, err := getListenerData(orgID, listenerConfig, versionCtx, paginationCtx)
@@ -214,6 +222,10 @@ func HealthCheck(c *fiber.Ctx) error {
 		"version":   "1.0.0", // TODO: 실제 버전 정보로 교체
 		"database":  status,
 	}
+	// 읽기 복제본 상태 (비정상이면 기본 DB로 조회하므로 전체 상태에는 영향 없음)
+	if replicas := database.GetReplicaStatus(); replicas != nil {
+		healthData["replicas"] = replicas
+	}
 
 	if status == "unhealthy" {
 		return c.Status(503).JSON(StandardResponse{
@@ -249,8 +261,18 @@ func SystemInfo(c *fiber.Ctx) error {
 
 // 헬퍼 함수들
 
+// deniedListenerCategory는 리스너가 읽는 카테고리 중 토큰에 읽기 권한이 없는 것을 반환합니다 (없으면 빈 문자열)
+func deniedListenerCategory(c *fiber.Ctx, config *ListenerConfig) string {
+	for category := range config.Queries {
+		if !middleware.CategoryAllowed(c, "read", category) {
+			return category
+		}
+	}
+	return ""
+}
+
 // getListenerConfig는 리스너 설정을 조회합니다
-func getListenerConfig(orgID int, listenerID string) (*ListenerConfig, error) {
+func getListenerConfig(orgID, listenerID string) (*ListenerConfig, error) {
 	db := database.GetDB()
 	
 	var config ListenerConfig
@@ -287,7 +309,7 @@ func getListenerConfig(orgID int, listenerID string) (*ListenerConfig, error) {
 }
 
 // getListenerData는 리스너 데이터를 조회합니다
-func getListenerData(orgID int, config *ListenerConfig, versionCtx *middleware.VersionContext, 
+func getListenerData(orgID string, config *ListenerConfig, versionCtx *middleware.VersionContext, 
 	paginationCtx *middleware.PaginationContext) (*ListenerData, error) {
 	
 	data := &ListenerData{
@@ -299,9 +321,12 @@ func getListenerData(orgID int, config *ListenerConfig, versionCtx *middleware.V
 	}
 	
 	// 각 카테고리별 데이터 조회
-	for category, query := range config.Queries {
-		// 쿼리 파싱 (간단 구현)
-		filters := parseQueryString(query)
+	for category, queryStr := range config.Queries {
+		// 쿼리 파싱 (잘못된 쿼리의 카테고리는 스킵)
+		filters, err := parseQueryString(queryStr)
+		if err != nil {
+			continue
+		}
 		
 		// 카테고리 데이터 조회
 		categoryData, _, err := getCategoryDataFromDB(orgID, category, versionCtx, paginationCtx, filters)
@@ -323,7 +348,7 @@ func getListenerData(orgID int, config *ListenerConfig, versionCtx *middleware.V
 }
 
 // getCategorySchemaFromDB는 카테고리 스키마를 조회합니다
-func getCategorySchemaFromDB(orgID int, category, version string) (interface{}, error) {
+func getCategorySchemaFromDB(orgID, category, version string) (interface{}, error) {
 	db := database.GetDB()
 	
 	var schemaJSON string
@@ -377,7 +402,7 @@ func getCategorySchemaFromDB(orgID int, category, version string) (interface{},
 }
 
 // getAllVersionSchemas는 모든 버전의 스키마를 조회합니다
-func getAllVersionSchemas(orgID int, category string) (interface{}, error) {
+func getAllVersionSchemas(orgID, category string) (interface{}, error) {
 	db := database.GetDB()
 	
 	query := `
@@ -417,23 +442,13 @@ func getAllVersionSchemas(orgID int, category string) (interface{}, error) {
 	}, nil
 }
 
-// parseQueryString은 쿼리 문자열을 파싱합니다 (간단 구현)
-func parseQueryString(queryStr string) []string {
-	// 실제로는 더 복잡한 쿼리 파싱이 필요
-	// 여기서는 간단하게 구현
-	if queryStr == "" {
-		return []string{}
-	}
-	
-	// 예: "bp>=120&ward=ICU" -> ["bp >= '120'", "ward = 'ICU'"]
-	filters := []string{}
-	parts := strings.Split(queryStr, "&")
-	
-	for _, part := range parts {
-		if strings.Contains(part, "=") {
-			filters = append(filters, parseComplexFilter(part, ""))
+// parseQueryString은 리스너 쿼리 문자열을 파싱합니다 (예: "bp>=120&ward=ICU")
+func parseQueryString(queryStr string) (*query.Query, error) {
+	var filters []string
+	for _, part := range strings.Split(queryStr, "&") {
+		if part = strings.TrimSpace(part); part != "" {
+			filters = append(filters, part)
 		}
 	}
-	
-	return filters
+	return query.Parse(filters, "")
 } 
\ No newline at end of file
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/config"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/oidc"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+// oidcCallbackPath 공급자가 로그인 후 돌려보내는 경로 (OIDC_REDIRECT_URL이 없을 때)
+const oidcCallbackPath = "/auth/oidc/callback"
+
+// oidcLoginTimeout 공급자에서 로그인하고 돌아와야 하는 시간
+const oidcLoginTimeout = 10 * time.Minute
+
+// sso OIDC SSO 설정과 공급자 (디스커버리는 처음 로그인할 때 읽음)
+var sso struct {
+	enabled       bool
+	name          string
+	config        oidc.Config
+	mapping       oidc.Mapping
+	autoProvision bool
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+// InitSSO는 설정에 따라 OIDC SSO 로그인을 켭니다
+func InitSSO(cfg *config.Config) {
+	sso.enabled = cfg.OIDCEnabled()
+	sso.name = cfg.OIDCProviderName
+	sso.config = oidc.Config{
+		IssuerURL:    cfg.OIDCIssuerURL,
+		ClientID:     cfg.OIDCClientID,
+		ClientSecret: cfg.OIDCClientSecret,
+		RedirectURL:  cfg.OIDCRedirectURL,
+		Scopes:       cfg.OIDCScopes,
+	}
+	sso.mapping = oidc.Mapping{
+		UsernameClaim: cfg.OIDCUsernameClaim,
+		OrgClaim:      cfg.OIDCOrgClaim,
+		DefaultOrg:    cfg.OIDCDefaultOrg,
+		RoleClaim:     cfg.OIDCRoleClaim,
+		RoleMap:       cfg.OIDCRoleMapping,
+		DefaultRole:   cfg.OIDCDefaultRole,
+	}
+	if sso.mapping.DefaultRole != "" && !oidc.ValidRole(sso.mapping.DefaultRole) {
+		log.Printf("⚠️ 잘못된 OIDC_DEFAULT_ROLE %q 무시", sso.mapping.DefaultRole)
+		sso.mapping.DefaultRole = ""
+	}
+	sso.autoProvision = cfg.OIDCAutoProvision
+}
+
+// SSOEnabled는 OIDC SSO 로그인이 켜져 있는지 반환합니다
+func SSOEnabled() bool {
+	return sso.enabled
+}
+
+// ssoProvider는 공급자를 반환합니다. 디스커버리에 실패하면 다음 로그인에서 다시 시도합니다.
+func ssoProvider(ctx context.Context) (*oidc.Provider, error) {
+	sso.mu.Lock()
+	defer sso.mu.Unlock()
+	if sso.provider != nil {
+		return sso.provider, nil
+	}
+	provider, err := oidc.NewProvider(ctx, sso.config)
+	if err != nil {
+		return nil, err
+	}
+	sso.provider = provider
+	return provider, nil
+}
+
+// OIDCLogin은 로그인 상태 값을 세션에 저장하고 사용자를 OIDC 공급자로 보냅니다
+func OIDCLogin(c *fiber.Ctx) error {
+	store := c.Locals("session_store").(*session.Store)
+	sess, err := store.Get(c)
+	if err != nil {
+		return c.Redirect("/login")
+	}
+	if !sso.enabled {
+		return ssoFailed(sess, c, "SSO login is not configured.")
+	}
+
+	provider, err := ssoProvider(c.Context())
+	if err != nil {
+		log.Printf("OIDC provider unavailable: %v", err)
+		return ssoFailed(sess, c, "SSO provider is unavailable.")
+	}
+
+	var values [3]string
+	for i := range values {
+		if values[i], err = oidc.NewState(); err != nil {
+			return ssoFailed(sess, c, "Failed to start SSO login.")
+		}
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+	sess.Set("oidc_state", state)
+	sess.Set("oidc_nonce", nonce)
+	sess.Set("oidc_verifier", verifier)
+	sess.Set("oidc_started", time.Now().Unix())
+	if err := sess.Save(); err != nil {
+		log.Printf("Failed to save session: %v", err)
+		return c.Redirect("/login")
+	}
+
+	return c.Redirect(provider.AuthCodeURL(c.BaseURL()+oidcCallbackPath, state, nonce, verifier))
+}
+
+// OIDCCallback은 공급자에서 돌아온 인가 코드로 사용자를 확인하고 로그인시킵니다.
+// 처음 로그인하는 사용자는 OIDC_AUTO_PROVISION이면 클레임의 조직과 역할로 만듭니다.
+func OIDCCallback(c *fiber.Ctx) error {
+	store := c.Locals("session_store").(*session.Store)
+	sess, err := store.Get(c)
+	if err != nil {
+		return c.Redirect("/login")
+	}
+
+	state, _ := sess.Get("oidc_state").(string)
+	nonce, _ := sess.Get("oidc_nonce").(string)
+	verifier, _ := sess.Get("oidc_verifier").(string)
+	started, _ := sess.Get("oidc_started").(int64)
+	for _, key := range []string{"oidc_state", "oidc_nonce", "oidc_verifier", "oidc_started"} {
+		sess.Delete(key)
+	}
+
+	if !sso.enabled {
+		return ssoFailed(sess, c, "SSO login is not configured.")
+	}
+	if errCode := c.Query("error"); errCode != "" {
+		log.Printf("OIDC login rejected by provider: %s %s", errCode, c.Query("error_description"))
+		return ssoFailed(sess, c, "SSO login was cancelled or rejected.")
+	}
+	if state == "" || c.Query("state") != state || time.Since(time.Unix(started, 0)) > oidcLoginTimeout {
+		return ssoFailed(sess, c, "SSO login expired. Please try again.")
+	}
+
+	provider, err := ssoProvider(c.Context())
+	if err != nil {
+		log.Printf("OIDC provider unavailable: %v", err)
+		return ssoFailed(sess, c, "SSO provider is unavailable.")
+	}
+	claims, err := provider.Exchange(c.Context(), c.BaseURL()+oidcCallbackPath, c.Query("code"), verifier, nonce)
+	if err != nil {
+		log.Printf("OIDC login failed: %v", err)
+		return ssoFailed(sess, c, "SSO login failed.")
+	}
+
+	identity, err := sso.mapping.Resolve(claims)
+	if err != nil {
+		log.Printf("OIDC login failed: %v", err)
+		return ssoFailed(sess, c, "Your SSO account cannot be mapped to a tmiDB user.")
+	}
+
+	user, err := database.LoginSSOUser(database.GetDB(), database.SSOLogin{
+		Issuer:   sso.config.IssuerURL,
+		Subject:  identity.Subject,
+		Username: identity.Username,
+		Org:      identity.Org,
+		Role:     identity.Role,
+	}, sso.autoProvision)
+	if err != nil {
+		log.Printf("OIDC login failed for '%s': %v", identity.Username, err)
+		switch {
+		case errors.Is(err, database.ErrSSOUserNotProvisioned), errors.Is(err, database.ErrSSOUserInactive),
+			errors.Is(err, database.ErrSSOOrgNotFound), errors.Is(err, database.ErrSSOUsernameTaken):
+			return ssoFailed(sess, c, "SSO login failed: "+err.Error()+".")
+		}
+		return ssoFailed(sess, c, "SSO login failed.")
+	}
+	if user.Created {
+		log.Printf("SSO user '%s' provisioned in organization %s as %s", user.Username, user.OrgID, user.Role)
+	}
+
+	if err := middleware.StartUserSession(c, sess, user.UserID, user.OrgID, user.Username, user.Role); err != nil {
+		log.Printf("Failed to save session: %v", err)
+		sess, _ = store.Get(c)
+		return ssoFailed(sess, c, "Failed to save session.")
+	}
+	return c.Redirect("/dashboard")
+}
+
+// ssoFailed는 로그인 페이지에 오류를 보여 주도록 플래시 메시지를 남기고 돌려보냅니다
+func ssoFailed(sess *session.Session, c *fiber.Ctx, message string) error {
+	sess.Set("error_flash", message)
+	sess.Save()
+	return c.Redirect("/login")
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/database"
+)
+
+// 휴지통 설정
+const (
+	recycleBinPurgeInterval = time.Hour // 보관 기간이 지난 항목을 영구 삭제하는 주기
+	maxRecycleBinItems      = 1000
+)
+
+// recycleBinRetention 삭제한 항목을 휴지통에 두는 기간 (0이면 휴지통 없이 바로 삭제)
+var recycleBinRetention time.Duration
+
+// softDeleteEnabled는 삭제 요청이 휴지통으로 보내는지 반환합니다
+func softDeleteEnabled() bool {
+	return recycleBinRetention > 0
+}
+
+// StartRecycleBin은 휴지통을 켜고, 보관 기간이 지난 항목을 주기적으로 영구 삭제합니다.
+// retention이 0 이하면 아무것도 하지 않습니다 (삭제 요청은 바로 삭제).
+func StartRecycleBin(ctx context.Context, retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+	recycleBinRetention = retention
+	log.Printf("♻️ Recycle bin enabled (retention %s)", retention)
+
+	go func() {
+		ticker := time.NewTicker(recycleBinPurgeInterval)
+		defer ticker.Stop()
+		for {
+			purgeRecycleBin(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// purgeRecycleBin은 보관 기간이 지난 항목과 그 첨부 파일을 영구 삭제합니다
+func purgeRecycleBin(ctx context.Context) {
+	purge, err := database.PurgeRecycleBin(database.GetDB(), time.Now().Add(-recycleBinRetention))
+	if err != nil {
+		log.Printf("⚠️ 휴지통 정리 실패: %v", err)
+		return
+	}
+	// 행은 이미 지워졌으므로 지우지 못한 파일은 기록만 함
+	for _, path := range purge.StoragePaths {
+		if fileStorage == nil {
+			log.Printf("⚠️ 파일 저장소가 없어 첨부 파일을 지우지 못했습니다 (%s)", path)
+			continue
+		}
+		if err := fileStorage.Delete(ctx, path); err != nil {
+			log.Printf("⚠️ 첨부 파일 삭제 실패 (%s): %v", path, err)
+		}
+	}
+	if purge.Targets > 0 || purge.Categories > 0 {
+		log.Printf("♻️ 휴지통에서 타겟 %d개와 카테고리 문서 %d개를 영구 삭제했습니다", purge.Targets, purge.Categories)
+	}
+}
+
+// recycleBinError는 휴지통 복원 오류를 응답으로 보냅니다
+func recycleBinError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, database.ErrNotInRecycleBin) {
+		return sendErrorResponse(c, "TARGET_NOT_FOUND", err.Error(), "")
+	}
+	return targetLifecycleError(c, err)
+}
+
+// ListRecycleBin은 조직의 휴지통을 최근 삭제 순으로 조회합니다.
+// kind=target|category로 종류를 고르고, 각 항목의 purge_at이 지나면 영구 삭제됩니다. 관리자 토큰이 필요합니다.
+func ListRecycleBin(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	if !softDeleteEnabled() {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Recycle bin is disabled", "set SOFT_DELETE_RETENTION_DAYS to enable it")
+	}
+	kind := c.Query("kind")
+	if kind != "" && kind != database.RecycleKindTarget && kind != database.RecycleKindCategory {
+		return sendErrorResponse(c, "INVALID_REQUEST", "kind must be target or category", kind)
+	}
+	limit, err := strconv.Atoi(c.Query("limit", "100"))
+	if err != nil || limit < 1 || limit > maxRecycleBinItems {
+		return sendErrorResponse(c, "INVALID_REQUEST", "limit must be between 1 and "+strconv.Itoa(maxRecycleBinItems), "")
+	}
+
+	items, err := database.ListRecycleBin(database.GetDB(), orgID, kind, recycleBinRetention, limit)
+	if err != nil {
+		log.Printf("Error listing recycle bin: %v", err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to list recycle bin", "")
+	}
+	return sendSuccessResponse(c, items, nil)
+}
+
+// RestoreTarget은 휴지통의 타겟을 함께 삭제된 카테고리 문서와 함께 되살립니다. 관리자 토큰이 필요합니다.
+func RestoreTarget(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	targetID := c.Params("target_id")
+	if !uuidPattern.MatchString(targetID) {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Invalid target id", targetID)
+	}
+
+	target, err := database.RestoreTarget(database.GetDB(), orgID, targetID, targetActor(c))
+	if err != nil {
+		return recycleBinError(c, err)
+	}
+	invalidateTargets(*target)
+	log.Printf("♻️ Target %s restored (org %s, %s)", target.TargetID, orgID, targetActor(c))
+	return sendSuccessResponse(c, target, nil)
+}
+
+// RestoreTargetCategoryData는 따로 삭제한 타겟의 카테고리 문서를 되살립니다.
+// 타겟이 휴지통에 있으면 타겟을 복원해야 합니다. 관리자 토큰이 필요합니다.
+func RestoreTargetCategoryData(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	targetID := c.Params("target_id")
+	category := c.Params("category")
+	if !uuidPattern.MatchString(targetID) {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Invalid target id", targetID)
+	}
+
+	if err := database.RestoreTargetCategoryData(database.GetDB(), orgID, targetID, category, targetActor(c)); err != nil {
+		return recycleBinError(c, err)
+	}
+	invalidateCache([]string{category}, []string{targetID})
+	return sendSuccessResponse(c, fiber.Map{
+		"target_id": targetID,
+		"category":  category,
+		"restored":  true,
+	}, nil)
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/tmidb/tmidb-core/internal/schema"
+)
+
+// publishSchemaChange는 카테고리 스키마 변경을 data-consumer 등에 알립니다.
+// 알림을 놓친 구독자도 주기적으로 스키마를 다시 읽으므로 발행 실패는 경고만 남깁니다.
+func publishSchemaChange(orgID, category, operation string, version int) {
+	if cacheSyncConn == nil {
+		return
+	}
+	payload, _ := json.Marshal(schema.Change{OrgID: orgID, Category: category, Operation: operation, Version: version})
+	if err := cacheSyncConn.Publish(schema.ChangeSubject, payload); err != nil {
+		log.Printf("⚠️ 스키마 변경 알림 발행 실패 (%s): %v", category, err)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 
 	"github.com/tmidb/tmidb-core/internal/database"
@@ -9,7 +10,21 @@ import (
 )
 
 // SetupPage는 초기 설정 페이지를 렌더링합니다.
+// 설정이 끝났으면 로그인 페이지로, 설정 시간이 지났으면 잠금 안내 페이지로 보냅니다.
 func SetupPage(c *fiber.Ctx) error {
+	state, err := database.GetSetupState(database.GetDB())
+	if err != nil {
+		log.Printf("Failed to read setup state: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+	}
+	if state.Completed {
+		return c.Redirect("/login")
+	}
+	if state.Locked {
+		return c.Status(fiber.StatusLocked).Render("setup_timeout.html", fiber.Map{
+			"title": "Setup Locked",
+		})
+	}
 	return c.Render("setup.html", fiber.Map{
 		"Title": "Initial Setup",
 	})
@@ -17,39 +32,66 @@ func SetupPage(c *fiber.Ctx) error {
 
 // SetupProcess는 초기 설정 폼 제출을 처리합니다.
 func SetupProcess(c *fiber.Ctx) error {
-	var req struct {
-		OrgName  string `json:"org_name"`
-		Username string `json:"username"`
-		Password string `json:"password"`
+	var req database.SetupRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+	}
+
+	result, status, err := runInitialSetup(req)
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{"error": "Setup failed: " + err.Error()})
 	}
+	return c.Status(status).JSON(fiber.Map{"token": result.Token})
+}
 
+// InitialSetupAPI는 자동화된 배포를 위한 초기 설정 API입니다.
+// 조직, 관리자, 관리자 토큰을 만들고 201을 반환합니다. 같은 값으로 다시 호출하면
+// 200과 함께 기존 조직과 관리자를 반환하고 (토큰은 처음 한 번만 반환),
+// 다른 값으로 이미 설정되었으면 409, 설정 시간이 지났으면 423을 반환합니다.
+func InitialSetupAPI(c *fiber.Ctx) error {
+	var req database.SetupRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
 	}
 
-	// 기본 관리자 및 조직 생성
-	token, err := database.CreateOrgAndAdminUser(req.OrgName, req.Username, req.Password)
+	result, status, err := runInitialSetup(req)
 	if err != nil {
-		log.Printf("Initial setup failed: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Setup failed: " + err.Error()})
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
 	}
+	return c.Status(status).JSON(result)
+}
 
-	// 설정 완료 플래그 설정
-	if err := database.SetSetupCompleted(); err != nil {
-		log.Printf("Failed to set setup completed flag: %v", err)
-		// 여기서 실패해도 일단 진행
+// runInitialSetup은 초기 설정을 실행하고 결과에 맞는 HTTP 상태 코드를 반환합니다
+func runInitialSetup(req database.SetupRequest) (*database.SetupResult, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fiber.StatusBadRequest, err
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"token": token})
+	result, err := database.RunInitialSetup(database.GetDB(), req)
+	switch {
+	case errors.Is(err, database.ErrSetupLocked):
+		return nil, fiber.StatusLocked, err
+	case errors.Is(err, database.ErrSetupCompleted):
+		return nil, fiber.StatusConflict, err
+	case err != nil:
+		log.Printf("Initial setup failed: %v", err)
+		return nil, fiber.StatusInternalServerError, err
+	}
+
+	if !result.Created {
+		return result, fiber.StatusOK, nil
+	}
+	log.Printf("Initial setup completed: organization %s, admin %s", result.OrgName, result.Username)
+	return result, fiber.StatusCreated, nil
 }
 
-// SetupStatus는 설정 상태를 확인합니다.
+// SetupStatus는 설정 상태와 잠금 기한을 확인합니다.
 func SetupStatus(c *fiber.Ctx) error {
-	completed, err := database.IsSetupCompleted()
+	state, err := database.GetSetupState(database.GetDB())
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
 	}
-	return c.JSON(fiber.Map{"setup_completed": completed})
+	return c.JSON(state)
 }
 
 // CheckSetupStatus는 내부적으로 사용하는 설정 상태 확인 함수입니다.
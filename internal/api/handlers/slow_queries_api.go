@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxSlowQueryReport 한 번에 반환하는 느린 쿼리 수 (explain=true면 쿼리마다 EXPLAIN을 실행함)
+const maxSlowQueryReport = 100
+
+// GetSlowQueries는 SLOW_QUERY_MS보다 오래 걸린 쿼리를 fingerprint별로 모아 반환합니다.
+// sort(total, max, mean, calls, last, 기본 total)와 limit(기본 20)을 받고, explain=true면 가장 느렸던 실행의 계획도 붙입니다.
+// 기록은 모든 조직의 쿼리를 담으므로 관리자 토큰이 필요합니다.
+func GetSlowQueries(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 || limit > maxSlowQueryReport {
+		return sendErrorResponse(c, "INVALID_REQUEST", "limit must be between 1 and 100", "")
+	}
+
+	sort := c.Query("sort", "total")
+	if !slices.Contains(database.SlowQuerySorts, sort) {
+		return sendErrorResponse(c, "INVALID_REQUEST", "sort must be one of "+strings.Join(database.SlowQuerySorts, ", "), "")
+	}
+
+	queries, err := database.ListSlowQueries(database.GetDB(), sort, limit)
+	if err != nil {
+		log.Printf("Error listing slow queries: %v", err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to list slow queries", "")
+	}
+	if c.QueryBool("explain") {
+		database.ExplainSlowQueries(database.GetDB(), queries)
+	}
+	return sendSuccessResponse(c, queries, nil)
+}
+
+// ResetSlowQueries는 느린 쿼리 기록을 지웁니다 (인덱스를 추가한 뒤 다시 재기 위해)
+func ResetSlowQueries(c *fiber.Ctx) error {
+	deleted, err := database.ResetSlowQueries(database.GetDB())
+	if err != nil {
+		log.Printf("Error resetting slow queries: %v", err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to reset slow queries", "")
+	}
+	return sendSuccessResponse(c, fiber.Map{"deleted": deleted}, nil)
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/config"
+	"github.com/tmidb/tmidb-core/internal/database"
+)
+
+// maxConsoleStatement SQL 콘솔 문의 최대 길이 (바이트)
+const maxConsoleStatement = 64 * 1024
+
+// sqlConsole SQL_CONSOLE_ENABLED로 켜는 /api/v1/admin/sql 설정
+var sqlConsole struct {
+	enabled bool
+	timeout time.Duration
+	maxRows int
+}
+
+// InitSQLConsole은 설정에 따라 SQL 콘솔을 켭니다
+func InitSQLConsole(cfg *config.Config) {
+	sqlConsole.enabled = cfg.SQLConsoleEnabled
+	sqlConsole.timeout = cfg.SQLConsoleTimeout
+	sqlConsole.maxRows = cfg.SQLConsoleMaxRows
+}
+
+// SQLConsoleEnabled는 SQL 콘솔 엔드포인트를 등록할지 반환합니다
+func SQLConsoleEnabled() bool {
+	return sqlConsole.enabled
+}
+
+// ExecuteConsoleSQL은 웹 콘솔 문제 해결 페이지의 SQL 문 하나를 읽기 전용 트랜잭션에서 실행합니다.
+// 실행 시간은 SQL_CONSOLE_TIMEOUT_SECONDS, 행 수는 SQL_CONSOLE_MAX_ROWS(요청의 max_rows가 더 작으면 그 값)로 제한하고
+// 결과는 컬럼별로 반환합니다. 문은 조직과 관계없이 모든 조직의 테이블을 읽으므로 설치 관리자의
+// 사용자 토큰만 쓸 수 있고 (라우트의 TokenInstallationAdminRequired),
+// 거부되거나 실패한 문을 포함해 모든 문을 sql_console_log에 기록합니다. 기록하지 못하면 결과도 돌려주지 않습니다.
+func ExecuteConsoleSQL(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	var req struct {
+		Query   string `json:"query"`
+		MaxRows int    `json:"max_rows,omitempty"`
+	}
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return sendErrorResponse(c, "INVALID_JSON", "Invalid request body", err.Error())
+	}
+	if len(req.Query) > maxConsoleStatement {
+		return sendErrorResponse(c, "INVALID_REQUEST", fmt.Sprintf("query is longer than %d bytes", maxConsoleStatement), "")
+	}
+	maxRows := sqlConsole.maxRows
+	if req.MaxRows < 0 {
+		return sendErrorResponse(c, "INVALID_REQUEST", "max_rows must be positive", "")
+	}
+	if req.MaxRows > 0 && req.MaxRows < maxRows {
+		maxRows = req.MaxRows
+	}
+
+	entry := &database.SQLConsoleEntry{
+		OrgID:     orgID,
+		Actor:     targetActor(c),
+		ClientIP:  c.IP(),
+		RequestID: middleware.GetRequestID(c),
+		Statement: req.Query,
+	}
+	result, runErr := database.RunConsoleSQL(c.UserContext(), database.GetDB(), req.Query, sqlConsole.timeout, maxRows)
+	if runErr != nil {
+		entry.Error = runErr.Error()
+	} else {
+		entry.RowCount, entry.Truncated, entry.DurationMs = result.RowCount, result.Truncated, result.DurationMs
+	}
+	if err := database.RecordSQLConsole(database.GetDB(), entry); err != nil {
+		log.Printf("Error recording SQL console statement from %s: %v", entry.Actor, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to record SQL console statement", "")
+	}
+
+	if runErr != nil {
+		return sendConsoleSQLError(c, runErr)
+	}
+	return sendSuccessResponse(c, result, nil)
+}
+
+// sendConsoleSQLError는 실행하지 못한 문의 이유를 응답합니다 (PostgreSQL 오류는 SQLSTATE와 함께)
+func sendConsoleSQLError(c *fiber.Ctx, err error) error {
+	var pqErr *pq.Error
+	switch {
+	case errors.Is(err, database.ErrSQLNotAllowed), errors.Is(err, database.ErrSQLFunctionNotAllowed):
+		return sendErrorResponse(c, "SQL_NOT_ALLOWED", err.Error(), "")
+	case errors.As(err, &pqErr) && pqErr.Code == "57014": // query_canceled (statement_timeout)
+		return sendErrorResponse(c, "SQL_TIMEOUT",
+			fmt.Sprintf("statement did not finish within %s", sqlConsole.timeout), string(pqErr.Code))
+	case errors.As(err, &pqErr):
+		return sendErrorResponse(c, "SQL_ERROR", pqErr.Message, string(pqErr.Code))
+	default:
+		return sendErrorResponse(c, "SQL_ERROR", err.Error(), "")
+	}
+}
+
+// GetSQLConsoleLog는 토큰 조직의 SQL 콘솔 실행 기록을 최신 순으로 반환합니다
+func GetSQLConsoleLog(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	limit := c.QueryInt("limit", 100)
+	if limit < 1 || limit > 1000 {
+		return sendErrorResponse(c, "INVALID_REQUEST", "limit must be between 1 and 1000", "")
+	}
+	entries, err := database.GetSQLConsoleLog(database.GetDB(), orgID, limit)
+	if err != nil {
+		log.Printf("Error listing SQL console log: %v", err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to list SQL console log", "")
+	}
+	return sendSuccessResponse(c, entries, nil)
+}
@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/query"
+)
+
+// MaxBulkDeleteTargets 한 번의 대량 삭제로 지울 수 있는 타겟 수
+const MaxBulkDeleteTargets = 1000
+
+// targetConfirmation은 확인 토큰 없이 보낸 보관/병합/삭제 요청의 응답입니다.
+// 같은 요청을 confirm_token과 함께 다시 보내면 실행됩니다.
+type targetConfirmation struct {
+	Action       string                     `json:"action"`
+	Confirmed    bool                       `json:"confirmed"`
+	ConfirmToken string                     `json:"confirm_token"`
+	ExpiresAt    time.Time                  `json:"expires_at"`
+	Targets      []database.TargetFootprint `json:"targets"`
+}
+
+// confirmRequest는 확인 토큰을 받는 요청 본문의 공통 필드입니다
+type confirmRequest struct {
+	ConfirmToken string `json:"confirm_token"`
+}
+
+// parseOptionalBody는 요청 본문이 있으면 읽습니다 (보관 요청은 본문 없이 보낼 수 있음)
+func parseOptionalBody(c *fiber.Ctx, out interface{}) error {
+	if len(c.Body()) == 0 {
+		return nil
+	}
+	return c.BodyParser(out)
+}
+
+// targetActor는 감사 로그에 남길 요청 주체입니다
+func targetActor(c *fiber.Ctx) string {
+	claims := middleware.GetTokenClaims(c)
+	switch {
+	case claims == nil:
+		return "unknown"
+	case claims.Kind == "user":
+		return "user:" + claims.UserID
+	default:
+		return "token:" + claims.TokenID
+	}
+}
+
+// previewTargetOperation은 작업 대상 타겟의 데이터 양과 실행에 쓸 확인 토큰을 응답합니다
+func previewTargetOperation(c *fiber.Ctx, orgID, action string, targetIDs []string) error {
+	footprints, err := database.GetTargetFootprints(database.GetDB(), orgID, targetIDs)
+	if err != nil {
+		return targetLifecycleError(c, err)
+	}
+	token, expiresAt, err := database.IssueTargetConfirmation(database.GetDB(), orgID, action,
+		database.TargetRequestHash(action, targetIDs))
+	if err != nil {
+		log.Printf("Error issuing target confirmation: %v", err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to issue confirmation token", "")
+	}
+	return sendSuccessResponse(c, targetConfirmation{
+		Action:       action,
+		ConfirmToken: token,
+		ExpiresAt:    expiresAt,
+		Targets:      footprints,
+	}, nil)
+}
+
+// targetLifecycleError는 타겟 작업 오류를 응답으로 보냅니다
+func targetLifecycleError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, database.ErrTargetNotFound):
+		return sendErrorResponse(c, "TARGET_NOT_FOUND", err.Error(), "")
+	case errors.Is(err, database.ErrTargetShared):
+		return sendErrorResponse(c, "TARGET_CONFLICT", err.Error(), "")
+	case errors.Is(err, database.ErrConfirmationInvalid):
+		return sendErrorResponse(c, "CONFIRMATION_INVALID", err.Error(), "Request a new confirm_token by sending the request without it")
+	default:
+		log.Printf("Error in target operation: %v", err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Target operation failed", "")
+	}
+}
+
+// invalidateTargets는 타겟들이 연결된 카테고리와 타겟의 캐시를 무효화합니다
+func invalidateTargets(footprints ...database.TargetFootprint) {
+	var categories, targets []string
+	for _, f := range footprints {
+		categories = append(categories, f.Categories...)
+		targets = append(targets, f.TargetID)
+	}
+	invalidateCache(categories, targets)
+}
+
+// ArchiveTarget은 타겟을 보관합니다. 보관된 타겟은 목록 조회에서 빠지고(include_archived=true로 포함) 데이터는 남습니다.
+// confirm_token 없이 보내면 미리보기와 확인 토큰을 반환합니다. 관리자 토큰이 필요합니다.
+func ArchiveTarget(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	targetID := c.Params("target_id")
+	if !uuidPattern.MatchString(targetID) {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Invalid target id", targetID)
+	}
+	var req confirmRequest
+	if err := parseOptionalBody(c, &req); err != nil {
+		return sendErrorResponse(c, "INVALID_JSON", "Invalid JSON format", err.Error())
+	}
+	if req.ConfirmToken == "" {
+		return previewTargetOperation(c, orgID, database.TargetActionArchive, []string{targetID})
+	}
+
+	target, err := database.ArchiveTarget(database.GetDB(), orgID, targetID, req.ConfirmToken, targetActor(c))
+	if err != nil {
+		return targetLifecycleError(c, err)
+	}
+	invalidateTargets(*target)
+	return sendSuccessResponse(c, target, nil)
+}
+
+// UnarchiveTarget은 보관된 타겟을 다시 목록 조회에 보이게 합니다. 관리자 토큰이 필요합니다.
+func UnarchiveTarget(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	targetID := c.Params("target_id")
+	if !uuidPattern.MatchString(targetID) {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Invalid target id", targetID)
+	}
+
+	target, err := database.UnarchiveTarget(database.GetDB(), orgID, targetID, targetActor(c))
+	if err != nil {
+		return targetLifecycleError(c, err)
+	}
+	invalidateTargets(*target)
+	return sendSuccessResponse(c, target, nil)
+}
+
+// mergeTargetsRequest 타겟 병합 요청 본문
+type mergeTargetsRequest struct {
+	confirmRequest
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+}
+
+// MergeTargets는 source 타겟의 카테고리 문서, 관측값, 위치 이력, 첨부 파일을 destination으로 옮기고 source를 삭제합니다.
+// confirm_token 없이 보내면 두 타겟의 미리보기와 확인 토큰을 반환합니다. 관리자 토큰이 필요합니다.
+func MergeTargets(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	var req mergeTargetsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendErrorResponse(c, "INVALID_JSON", "Invalid JSON format", err.Error())
+	}
+	if !uuidPattern.MatchString(req.Source) || !uuidPattern.MatchString(req.Destination) {
+		return sendErrorResponse(c, "INVALID_REQUEST", "source and destination must be target ids", "")
+	}
+	if strings.EqualFold(req.Source, req.Destination) {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Cannot merge a target into itself", "")
+	}
+	ids := []string{req.Source, req.Destination}
+	if req.ConfirmToken == "" {
+		return previewTargetOperation(c, orgID, database.TargetActionMerge, ids)
+	}
+
+	merge, err := database.MergeTargets(database.GetDB(), orgID, req.Source, req.Destination, req.ConfirmToken, targetActor(c))
+	if err != nil {
+		return targetLifecycleError(c, err)
+	}
+	invalidateTargets(merge.Source, merge.Destination)
+	log.Printf("🔀 Target %s merged into %s (org %s, %s)", merge.Source.TargetID, merge.Destination.TargetID, orgID, targetActor(c))
+	return sendSuccessResponse(c, merge, nil)
+}
+
+// bulkDeleteTargetsRequest 대량 삭제 요청 본문. target_ids 또는 category(+filter) 중 하나로 타겟을 고릅니다.
+type bulkDeleteTargetsRequest struct {
+	confirmRequest
+	TargetIDs       []string `json:"target_ids"`
+	Category        string   `json:"category"`
+	Filter          []string `json:"filter"` // 카테고리 조회의 filter 식 (internal/query)
+	IncludeArchived bool     `json:"include_archived"`
+}
+
+// BulkDeleteTargets는 고른 타겟들과 타겟의 모든 데이터, 첨부 파일을 삭제합니다.
+// 휴지통이 켜져 있으면 타겟을 휴지통으로 보내고, 보관 기간이 지나면 영구 삭제합니다.
+// confirm_token 없이 보내면 일치하는 타겟 목록과 확인 토큰을 반환하고, 실행할 때 필터를 다시 평가하여
+// 그 사이 일치하는 타겟이 바뀌었으면 거부합니다. 관리자 토큰이 필요합니다.
+func BulkDeleteTargets(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	var req bulkDeleteTargetsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendErrorResponse(c, "INVALID_JSON", "Invalid JSON format", err.Error())
+	}
+
+	// 확인 토큰 해시가 순서에 의존하므로 타겟 ID는 정렬해서 씀
+	var ids []string
+	switch {
+	case len(req.TargetIDs) > 0 && req.Category != "":
+		return sendErrorResponse(c, "INVALID_REQUEST", "Use either target_ids or category, not both", "")
+	case len(req.TargetIDs) > 0:
+		if ids, err = normalizeTargetIDs(req.TargetIDs); err != nil {
+			return sendErrorResponse(c, "INVALID_REQUEST", err.Error(), "")
+		}
+	case req.Category != "":
+		q, err := query.Parse(req.Filter, "")
+		if err != nil {
+			return sendErrorResponse(c, "QUERY_PARSE_ERROR", err.Error(), "")
+		}
+		q.IncludeArchived = req.IncludeArchived
+		s := query.NewSelect("DISTINCT target_id::text", "target_categories")
+		if err := buildCategoryWhere(s, orgID, req.Category, &middleware.VersionContext{RequestedVersion: "all"}, q); err != nil {
+			return sendErrorResponse(c, "QUERY_PARSE_ERROR", err.Error(), "")
+		}
+		if ids, err = matchingTargetIDs(s); err != nil {
+			log.Printf("Error selecting targets to delete: %v", err)
+			return sendErrorResponse(c, "DATABASE_ERROR", "Failed to select targets", "")
+		}
+	default:
+		return sendErrorResponse(c, "INVALID_REQUEST", "target_ids or category is required", "")
+	}
+	if len(ids) > MaxBulkDeleteTargets {
+		return sendErrorResponse(c, "INVALID_REQUEST",
+			fmt.Sprintf("More than %d targets selected; narrow the filter", MaxBulkDeleteTargets), "")
+	}
+	if len(ids) == 0 {
+		return sendErrorResponse(c, "TARGET_NOT_FOUND", "No targets match the request", "")
+	}
+	if req.ConfirmToken == "" {
+		return previewTargetOperation(c, orgID, database.TargetActionDelete, ids)
+	}
+
+	details := map[string]interface{}{}
+	if req.Category != "" {
+		details["category"] = req.Category
+		details["filter"] = req.Filter
+		details["include_archived"] = req.IncludeArchived
+	}
+	soft := softDeleteEnabled()
+	deletion, err := database.DeleteTargets(database.GetDB(), orgID, ids, req.ConfirmToken, targetActor(c), details, soft)
+	if err != nil {
+		return targetLifecycleError(c, err)
+	}
+	invalidateTargets(deletion.Targets...)
+
+	// 행은 이미 지워졌으므로 지우지 못한 파일은 알리기만 함
+	storageFailed := 0
+	for _, path := range deletion.StoragePaths {
+		if fileStorage == nil {
+			storageFailed++
+			continue
+		}
+		if err := fileStorage.Delete(c.UserContext(), path); err != nil {
+			log.Printf("⚠️ 첨부 파일 삭제 실패 (%s): %v", path, err)
+			storageFailed++
+		}
+	}
+	log.Printf("🗑️ %d targets deleted (org %s, %s, soft=%t)", len(deletion.Targets), orgID, targetActor(c), soft)
+	response := fiber.Map{
+		"action":               database.TargetActionDelete,
+		"confirmed":            true,
+		"targets":              deletion.Targets,
+		"storage_failed_files": storageFailed,
+		"soft_deleted":         soft,
+	}
+	if soft {
+		response["purge_at"] = time.Now().Add(recycleBinRetention)
+	}
+	return sendSuccessResponse(c, response, nil)
+}
+
+// normalizeTargetIDs는 타겟 ID를 검사하고 소문자로 바꿔 중복 없이 정렬합니다
+func normalizeTargetIDs(targetIDs []string) ([]string, error) {
+	seen := make(map[string]bool, len(targetIDs))
+	ids := make([]string, 0, len(targetIDs))
+	for _, id := range targetIDs {
+		if !uuidPattern.MatchString(id) {
+			return nil, fmt.Errorf("invalid target id %q", id)
+		}
+		if id = strings.ToLower(id); !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// matchingTargetIDs는 카테고리 조건에 맞는 타겟 ID를 정렬해서 반환합니다.
+// 한도를 넘었는지 알 수 있도록 MaxBulkDeleteTargets보다 하나 더 읽습니다.
+func matchingTargetIDs(s *query.Select) ([]string, error) {
+	stmt, args := s.OrderBy("1").Limit(MaxBulkDeleteTargets + 1).SQL()
+	rows, err := database.GetDB().Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetTargetAuditLog는 조직의 타겟 보관/병합/삭제 기록을 최신 순으로 반환합니다 (target_id, limit 쿼리 파라미터).
+// 관리자 토큰이 필요합니다.
+func GetTargetAuditLog(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	entries, err := database.GetTargetAuditLog(database.GetDB(), orgID, c.Query("target_id"), c.QueryInt("limit", 100))
+	if err != nil {
+		log.Printf("Error getting target audit log: %v", err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to get target audit log", "")
+	}
+	return sendSuccessResponse(c, entries, nil)
+}
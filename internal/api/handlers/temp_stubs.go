@@ -63,68 +63,6 @@ func ClearCache(c *fiber.Ctx) error {
 	})
 }
 
-// 리스너 API 스텁들
-func GetListenersAPI(c *fiber.Ctx) error {
-	return c.JSON(fiber.Map{
-		"listeners": []interface{}{},
-	})
-}
-
-func CreateListenerAPI(c *fiber.Ctx) error {
-	return c.JSON(fiber.Map{
-		"success": true,
-		"message": "Listener created",
-	})
-}
-
-func DeleteListenerAPI(c *fiber.Ctx) error {
-	return c.JSON(fiber.Map{
-		"success": true,
-		"message": "Listener deleted",
-	})
-}
-
 // 사용자 API와 토큰 API는 다른 파일에 이미 구현됨
 
-// 마이그레이션 API 스텁들
-func GetMigrationsAPI(c *fiber.Ctx) error {
-	return c.JSON(fiber.Map{
-		"migrations": []interface{}{},
-	})
-}
-
-func CreateMigrationAPI(c *fiber.Ctx) error {
-	return c.JSON(fiber.Map{
-		"success": true,
-		"message": "Migration created",
-	})
-}
-
-func ExecuteMigrationAPI(c *fiber.Ctx) error {
-	return c.JSON(fiber.Map{
-		"success": true,
-		"message": "Migration executed",
-	})
-}
-
-func GetMigrationStatusAPI(c *fiber.Ctx) error {
-	return c.JSON(fiber.Map{
-		"status": "completed",
-	})
-}
-
 // 헬퍼 함수들은 다른 파일에 이미 구현됨
-
-func UploadFiles(c *fiber.Ctx) error {
-	return c.JSON(fiber.Map{
-		"success": true,
-		"message": "Files uploaded",
-	})
-}
-
-func DeleteFile(c *fiber.Ctx) error {
-	return c.JSON(fiber.Map{
-		"success": true,
-		"message": "File deleted",
-	})
-}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/database"
+)
+
+// 시계열 조회 기본값과 제한
+const (
+	defaultSeriesRange     = 24 * time.Hour
+	defaultSeriesPoints    = 500
+	maxSeriesPoints        = 10000
+	defaultObservationRows = 100
+	maxObservationRows     = 1000
+	minSeriesBucket        = time.Second
+)
+
+// GetTargetTimeSeries는 타겟의 카테고리 시계열을 조회합니다.
+// field가 없으면 최근 관측값 원본을 limit개까지, 있으면 그 숫자 필드를 구간별로 요약(samples, avg, min, max, last)해 돌려줍니다.
+// 구간 크기는 interval 또는 범위를 max_points로 나눈 값이고, resolution=auto(기본)면
+// 구간 크기 이하인 가장 큰 롤업 해상도를 읽으며 맞는 롤업이 없으면 ts_obs 원본을 집계합니다.
+func GetTargetTimeSeries(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	targetID := c.Params("target_id")
+	category := c.Params("category")
+	if !uuidPattern.MatchString(targetID) {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Invalid target ID", "")
+	}
+
+	var since, until *time.Time
+	for name, dst := range map[string]**time.Time{"from": &since, "to": &until} {
+		if value := c.Query(name); value != "" {
+			t, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return sendErrorResponse(c, "INVALID_REQUEST", "Invalid "+name, err.Error())
+			}
+			*dst = &t
+		}
+	}
+
+	db := database.GetReadDB()
+	field := c.Query("field")
+	if field == "" {
+		limit, err := strconv.Atoi(c.Query("limit", strconv.Itoa(defaultObservationRows)))
+		if err != nil || limit < 1 || limit > maxObservationRows {
+			return sendErrorResponse(c, "INVALID_REQUEST", "limit must be between 1 and "+strconv.Itoa(maxObservationRows), "")
+		}
+		observations, err := database.ListObservations(db, orgID, targetID, category, since, until, limit)
+		if err != nil {
+			log.Printf("Error listing observations of %s/%s: %v", targetID, category, err)
+			return sendErrorResponse(c, "DATABASE_ERROR", "Failed to query time series", "")
+		}
+		items := make([]fiber.Map, len(observations))
+		for i, obs := range observations {
+			items[i] = fiber.Map{"ts": obs.Time, "payload": obs.Payload}
+		}
+		return sendSuccessResponse(c, items, nil)
+	}
+	for _, part := range strings.Split(field, ".") {
+		if part == "" {
+			return sendErrorResponse(c, "INVALID_REQUEST", "Invalid field path", field)
+		}
+	}
+
+	to := time.Now()
+	if until != nil {
+		to = *until
+	}
+	from := to.Add(-defaultSeriesRange)
+	if since != nil {
+		from = *since
+	}
+	if !to.After(from) {
+		return sendErrorResponse(c, "INVALID_REQUEST", "to must be after from", "")
+	}
+
+	bucket, err := seriesBucket(from, to, c.Query("interval"), c.Query("max_points"))
+	if err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", err.Error(), "")
+	}
+
+	var progress []database.RollupProgress
+	job, err := database.GetRollupJob(db, category)
+	switch {
+	case err == nil && job.IsActive:
+		progress = job.Progress
+	case err != nil && err != sql.ErrNoRows:
+		log.Printf("Error reading rollup job of %s: %v", category, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to query time series", "")
+	}
+
+	resolution := ""
+	switch requested := c.Query("resolution", "auto"); requested {
+	case "auto":
+		resolution = database.ChooseRollupResolution(progress, field, bucket, to)
+	case "raw":
+	default:
+		if resolution, err = database.NormalizeInterval(requested); err != nil {
+			return sendErrorResponse(c, "INVALID_REQUEST", "Invalid resolution", err.Error())
+		}
+		available := false
+		for _, p := range progress {
+			available = available || (p.Resolution == resolution && slices.Contains(p.Fields, field))
+		}
+		if !available {
+			return sendErrorResponse(c, "INVALID_REQUEST", "No rollup of "+field+" every "+resolution, "use resolution=auto or raw")
+		}
+	}
+	if resolution != "" {
+		size, _ := database.IntervalDuration(resolution)
+		bucket = roundUpBucket(bucket, size)
+	}
+
+	points, err := database.QuerySeries(db, database.SeriesQuery{
+		OrgID:      orgID,
+		TargetID:   targetID,
+		Category:   category,
+		Field:      field,
+		From:       from,
+		To:         to,
+		Bucket:     bucket,
+		Resolution: resolution,
+	})
+	if err != nil {
+		log.Printf("Error querying %s of %s/%s: %v", field, targetID, category, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to query time series", "")
+	}
+
+	if resolution == "" {
+		resolution = "raw"
+	}
+	return sendSuccessResponse(c, fiber.Map{
+		"target_id":      targetID,
+		"category":       category,
+		"field":          field,
+		"from":           from,
+		"to":             to,
+		"resolution":     resolution,
+		"bucket_seconds": int64(bucket / time.Second),
+		"points":         points,
+	}, nil)
+}
+
+// seriesBucket은 요약 구간 크기를 정합니다 (interval이 있으면 그 값, 없으면 범위를 max_points로 나눈 값, 최소 1초)
+func seriesBucket(from, to time.Time, interval, maxPoints string) (time.Duration, error) {
+	if interval != "" {
+		normalized, err := database.NormalizeInterval(interval)
+		if err != nil {
+			return 0, err
+		}
+		bucket, err := database.IntervalDuration(normalized)
+		if err != nil {
+			return 0, err
+		}
+		if to.Sub(from)/bucket > maxSeriesPoints {
+			return 0, fmt.Errorf("interval too small for the range (max %d points)", maxSeriesPoints)
+		}
+		return bucket, nil
+	}
+
+	points := defaultSeriesPoints
+	if maxPoints != "" {
+		n, err := strconv.Atoi(maxPoints)
+		if err != nil || n < 1 || n > maxSeriesPoints {
+			return 0, fmt.Errorf("max_points must be between 1 and %d", maxSeriesPoints)
+		}
+		points = n
+	}
+	bucket := to.Sub(from) / time.Duration(points)
+	if bucket < minSeriesBucket {
+		bucket = minSeriesBucket
+	}
+	return bucket.Truncate(time.Second), nil
+}
+
+// roundUpBucket은 구간 크기를 롤업 해상도의 배수로 올립니다 (롤업 구간이 요약 구간 경계에 걸치지 않도록)
+func roundUpBucket(bucket, resolution time.Duration) time.Duration {
+	if bucket <= resolution {
+		return resolution
+	}
+	return (bucket + resolution - 1) / resolution * resolution
+}
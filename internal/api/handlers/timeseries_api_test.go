@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSeriesBucket(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(30 * 24 * time.Hour)
+
+	tests := []struct {
+		interval, maxPoints string
+		want                time.Duration
+	}{
+		{"", "", 30 * 24 * time.Hour / defaultSeriesPoints},
+		{"", "30", 24 * time.Hour},
+		{"6h", "", 6 * time.Hour},
+		{"", "100000", 0},
+		{"1m", "", 0}, // 43200개 점
+		{"5x", "", 0},
+	}
+	for _, tt := range tests {
+		got, err := seriesBucket(from, to, tt.interval, tt.maxPoints)
+		if tt.want == 0 {
+			if err == nil {
+				t.Errorf("interval=%q max_points=%q accepted", tt.interval, tt.maxPoints)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("interval=%q max_points=%q: got %s, %v", tt.interval, tt.maxPoints, got, err)
+		}
+	}
+
+	if got, _ := seriesBucket(from, from.Add(time.Minute), "", "1000"); got != minSeriesBucket {
+		t.Errorf("minimum bucket = %s", got)
+	}
+}
+
+func TestRoundUpBucket(t *testing.T) {
+	for _, tt := range []struct{ bucket, resolution, want time.Duration }{
+		{10 * time.Minute, time.Hour, time.Hour},
+		{90 * time.Minute, time.Hour, 2 * time.Hour},
+		{3 * time.Hour, time.Hour, 3 * time.Hour},
+	} {
+		if got := roundUpBucket(tt.bucket, tt.resolution); got != tt.want {
+			t.Errorf("roundUpBucket(%s, %s) = %s", tt.bucket, tt.resolution, got)
+		}
+	}
+}
@@ -0,0 +1,388 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/schema"
+)
+
+// maxTransactionOps 트랜잭션 하나의 최대 작업 수
+const maxTransactionOps = 1000
+
+// 트랜잭션 작업 종류
+const (
+	txOpSet    = "set"    // 타겟의 카테고리 데이터 저장 (연결이 없으면 조직의 최신 활성 스키마로 연결)
+	txOpInsert = "insert" // 관측값 저장 (ts_obs)
+	txOpDelete = "delete" // 타겟-카테고리 연결 삭제
+)
+
+// TransactionOp는 트랜잭션 요청의 작업 하나입니다
+type TransactionOp struct {
+	Op       string                 `json:"op"`
+	TargetID string                 `json:"target_id"`
+	Category string                 `json:"category"`
+	Name     string                 `json:"name,omitempty"`              // set: 새로 만드는 타겟의 이름 (기본값 타겟 ID)
+	Data     map[string]interface{} `json:"data,omitempty"`              // set: category_data
+	Expected *int                   `json:"expected_revision,omitempty"` // set: 문서가 이 리비전일 때만 씀 (0이면 없어야 함)
+	Ts       string                 `json:"ts,omitempty"`                // insert: RFC3339, 비어 있으면 수신 시각
+	EventID  string                 `json:"event_id,omitempty"`          // insert: 중복 제거 키
+	Payload  interface{}            `json:"payload,omitempty"`           // insert: ts_obs payload
+
+	ts time.Time
+}
+
+// TransactionOpError는 실패한 작업과 그 이유입니다
+type TransactionOpError struct {
+	Index    int                 `json:"index"`
+	Op       string              `json:"op,omitempty"`
+	TargetID string              `json:"target_id,omitempty"`
+	Category string              `json:"category,omitempty"`
+	Error    string              `json:"error"`
+	Fields   []schema.FieldError `json:"fields,omitempty"`
+
+	CurrentRevision *int `json:"current_revision,omitempty"` // 리비전 충돌이면 문서의 현재 리비전
+}
+
+// TransactionResult는 커밋한 트랜잭션의 작업 수입니다
+type TransactionResult struct {
+	Operations int `json:"operations"`
+	Set        int `json:"set"`
+	Inserted   int `json:"inserted"`
+	Duplicates int `json:"duplicates"` // 중복 제거 기간 안에 이미 저장된 관측값 (저장하지 않음)
+	Deleted    int `json:"deleted"`
+}
+
+// txOpError는 요청 내용 때문에 실패한 작업입니다 (DB 장애와 구별해 작업별 오류로 돌려줌)
+type txOpError struct{ msg string }
+
+func (e *txOpError) Error() string { return e.msg }
+
+func opErrorf(format string, args ...interface{}) error {
+	return &txOpError{msg: fmt.Sprintf(format, args...)}
+}
+
+// ExecuteTransaction은 여러 타겟과 카테고리에 대한 쓰기를 한 DB 트랜잭션으로 적용합니다.
+// 작업은 요청 순서대로 세이브포인트 안에서 검증하고 실행하므로 앞 작업의 결과(새 연결 등)를 뒤 작업이 볼 수 있습니다.
+// 하나라도 실패하면 모든 작업의 오류를 모아 400 TRANSACTION_INVALID로 돌려주고 아무것도 커밋하지 않습니다.
+func ExecuteTransaction(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	var req struct {
+		Operations []TransactionOp `json:"operations"`
+	}
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return sendErrorResponse(c, "INVALID_JSON", "Invalid request body", err.Error())
+	}
+	ops := req.Operations
+	if len(ops) == 0 {
+		return sendErrorResponse(c, "INVALID_REQUEST", "No operations in request body", "")
+	}
+	if len(ops) > maxTransactionOps {
+		return sendErrorResponse(c, "INVALID_REQUEST",
+			fmt.Sprintf("Too many operations: %d (max %d)", len(ops), maxTransactionOps), "")
+	}
+
+	// DB를 보기 전에 형식과 카테고리 권한을 모두 확인
+	now := time.Now()
+	var opErrs []TransactionOpError
+	for i := range ops {
+		err := parseTransactionOp(&ops[i], now)
+		if err == nil && !middleware.CategoryAllowed(c, "write", ops[i].Category) {
+			err = fmt.Errorf("missing write permission for category %s", ops[i].Category)
+		}
+		if err != nil {
+			opErrs = append(opErrs, transactionOpError(i, &ops[i], err))
+		}
+	}
+	if len(opErrs) > 0 {
+		return sendTransactionErrors(c, opErrs, len(ops))
+	}
+
+	tx, err := database.GetDB().Begin()
+	if err != nil {
+		log.Printf("Error beginning transaction: %v", err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to apply transaction", "")
+	}
+	defer tx.Rollback()
+	actor := targetActor(c)
+	if err := database.SetChangeActor(tx, actor); err != nil {
+		log.Printf("Error beginning transaction: %v", err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to apply transaction", "")
+	}
+
+	result := TransactionResult{Operations: len(ops)}
+	for i := range ops {
+		op := &ops[i]
+		opErr, err := database.WithSavepoint(tx, "transaction_op", func() error {
+			return applyTransactionOp(tx, orgID, actor, op, &result)
+		})
+		if err == nil && opErr != nil && !isTransactionOpError(opErr) {
+			err = opErr
+		}
+		if err != nil {
+			log.Printf("Error applying transaction operation %d (%s %s/%s): %v", i, op.Op, op.TargetID, op.Category, err)
+			return sendErrorResponse(c, "DATABASE_ERROR", "Failed to apply transaction", "")
+		}
+		if opErr != nil {
+			opErrs = append(opErrs, transactionOpError(i, op, opErr))
+		}
+	}
+	if len(opErrs) > 0 {
+		return sendTransactionErrors(c, opErrs, len(ops))
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to commit transaction", "")
+	}
+
+	// 캐시 무효화 (데이터 변경 시, 다른 API 인스턴스에도 알림)
+	var categories, targets []string
+	seen := make(map[string]bool)
+	for _, op := range ops {
+		if !seen["c:"+op.Category] {
+			seen["c:"+op.Category] = true
+			categories = append(categories, op.Category)
+		}
+		if !seen["t:"+op.TargetID] {
+			seen["t:"+op.TargetID] = true
+			targets = append(targets, op.TargetID)
+		}
+	}
+	invalidateCache(categories, targets)
+	middleware.RecordIngested(c, result.Set+result.Inserted)
+
+	return sendSuccessResponse(c, result, nil)
+}
+
+// parseTransactionOp는 작업의 종류와 필수 필드를 확인하고 시각을 해석합니다 (DB 조회 없음)
+func parseTransactionOp(op *TransactionOp, now time.Time) error {
+	if !uuidPattern.MatchString(op.TargetID) {
+		return errors.New("target_id must be a UUID")
+	}
+	op.TargetID = strings.ToLower(op.TargetID)
+	if op.Category == "" {
+		return errors.New("category is required")
+	}
+	if op.Expected != nil && op.Op != txOpSet {
+		return errors.New("expected_revision is only allowed on set")
+	}
+
+	switch op.Op {
+	case txOpSet:
+		if op.Data == nil {
+			return errors.New("data is required")
+		}
+		if op.Expected != nil && *op.Expected < 0 {
+			return errors.New("expected_revision must be a revision number")
+		}
+	case txOpInsert:
+		if op.Payload == nil {
+			return errors.New("payload is required")
+		}
+		if len(op.EventID) > database.MaxEventIDLength {
+			return fmt.Errorf("event_id is longer than %d characters", database.MaxEventIDLength)
+		}
+		op.ts = now
+		if op.Ts != "" {
+			ts, err := time.Parse(time.RFC3339Nano, op.Ts)
+			if err != nil {
+				return fmt.Errorf("invalid ts: %v", err)
+			}
+			op.ts = ts
+		}
+	case txOpDelete:
+	default:
+		return fmt.Errorf("op must be %s, %s or %s", txOpSet, txOpInsert, txOpDelete)
+	}
+	return nil
+}
+
+// applyTransactionOp는 트랜잭션 안에서 작업 하나를 검증하고 실행합니다.
+// 휴지통에 있는 문서에는 쓰지 않고, 휴지통이 켜져 있으면 delete는 문서를 휴지통으로 보냅니다.
+func applyTransactionOp(tx *sql.Tx, orgID, actor string, op *TransactionOp, result *TransactionResult) error {
+	switch op.Op {
+	case txOpSet:
+		dataJSON, err := json.Marshal(op.Data)
+		if err != nil {
+			return opErrorf("invalid data: %v", err)
+		}
+		if op.Expected != nil {
+			if err := database.CheckDocumentRevision(tx, orgID, op.TargetID, op.Category, *op.Expected); err != nil {
+				return err
+			}
+		}
+		link, err := database.GetTargetCategoryLink(tx, op.TargetID, op.Category)
+		switch {
+		case err == sql.ErrNoRows:
+			active, err := database.GetActiveCategorySchema(tx, orgID, op.Category)
+			if err == sql.ErrNoRows {
+				return opErrorf("category %s not found", op.Category)
+			}
+			if err != nil {
+				return err
+			}
+			if err := validateTransactionData(active.SchemaDefinition, op.Data); err != nil {
+				return err
+			}
+			name := op.Name
+			if name == "" {
+				name = op.TargetID
+			}
+			owner, err := database.EnsureTargetLink(tx, op.TargetID, name, active, op.Category, string(dataJSON))
+			if errors.Is(err, database.ErrInRecycleBin) {
+				return opErrorf("%v", err)
+			}
+			if err != nil {
+				return err
+			}
+			if owner != orgID {
+				return opErrorf("target %s belongs to another organization", op.TargetID)
+			}
+		case err != nil:
+			return err
+		case link.OrgID != orgID:
+			return opErrorf("target %s belongs to another organization", op.TargetID)
+		case link.Deleted:
+			return opErrorf("%v: target %s in category %s", database.ErrInRecycleBin, op.TargetID, op.Category)
+		default:
+			if err := validateTransactionData(link.SchemaDefinition, op.Data); err != nil {
+				return err
+			}
+			if err := database.UpsertTargetCategoryData(tx, op.TargetID, link, op.Category, string(dataJSON)); err != nil {
+				return err
+			}
+		}
+		result.Set++
+
+	case txOpInsert:
+		link, err := database.GetTargetCategoryLink(tx, op.TargetID, op.Category)
+		if err == sql.ErrNoRows || (err == nil && link.OrgID != orgID) {
+			return opErrorf("target is not linked to category %s", op.Category)
+		}
+		if err != nil {
+			return err
+		}
+		if link.Deleted {
+			return opErrorf("%v: target %s in category %s", database.ErrInRecycleBin, op.TargetID, op.Category)
+		}
+		if err := validateTransactionData(link.SchemaDefinition, op.Payload); err != nil {
+			return err
+		}
+		payloadJSON, err := json.Marshal(op.Payload)
+		if err != nil {
+			return opErrorf("invalid payload: %v", err)
+		}
+		clientTs := time.Time{}
+		if op.Ts != "" {
+			clientTs = op.ts
+		}
+		key := database.IngestEventKey(op.EventID, op.TargetID, clientTs, payloadJSON)
+		claimed, err := database.ClaimIngestEvent(tx, op.TargetID, op.Category, key)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			result.Duplicates++
+			return nil
+		}
+		if err := database.InsertTimeSeriesPoint(tx, op.TargetID, op.Category, op.ts, string(payloadJSON)); err != nil {
+			return err
+		}
+		result.Inserted++
+
+	case txOpDelete:
+		var n int64
+		var err error
+		if softDeleteEnabled() {
+			n, err = database.SoftDeleteTargetCategoryData(tx, orgID, op.TargetID, op.Category, actor)
+		} else {
+			n, err = database.DeleteTargetCategoryData(tx, orgID, op.TargetID, op.Category)
+		}
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return opErrorf("target is not linked to category %s", op.Category)
+		}
+		result.Deleted++
+	}
+	return nil
+}
+
+// validateTransactionData는 값을 카테고리 스키마로 검증합니다 (스키마가 없으면 허용)
+func validateTransactionData(definition string, value interface{}) error {
+	if definition == "" {
+		return nil
+	}
+	compiled, err := schema.Cached(definition)
+	if err != nil {
+		return opErrorf("invalid schema format: %v", err)
+	}
+	return compiled.Validate(value)
+}
+
+// isTransactionOpError는 작업 내용 때문에 생긴 오류인지 확인합니다.
+// 검증 실패와 데이터 오류(22: 데이터 예외, 23: 무결성 제약 위반)는 작업별 오류, 그 밖에는 DB 장애입니다.
+func isTransactionOpError(err error) bool {
+	var opErr *txOpError
+	var validationErr *schema.ValidationError
+	var conflict *database.RevisionConflictError
+	if errors.As(err, &opErr) || errors.As(err, &validationErr) || errors.As(err, &conflict) {
+		return true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		class := pqErr.Code.Class()
+		return class == "22" || class == "23"
+	}
+	return false
+}
+
+// transactionOpError는 작업의 오류를 응답 형식으로 만듭니다
+func transactionOpError(index int, op *TransactionOp, err error) TransactionOpError {
+	e := TransactionOpError{Index: index, Op: op.Op, TargetID: op.TargetID, Category: op.Category, Error: err.Error()}
+	var validationErr *schema.ValidationError
+	var conflict *database.RevisionConflictError
+	if errors.As(err, &validationErr) {
+		e.Error = "data does not match category schema"
+		e.Fields = validationErr.Errors
+	}
+	if errors.As(err, &conflict) {
+		e.CurrentRevision = &conflict.Current
+	}
+	return e
+}
+
+// sendTransactionErrors는 작업별 오류와 함께 400 TRANSACTION_INVALID 응답을 보냅니다.
+// 리비전 충돌만으로 실패했으면 다시 읽고 재시도할 수 있도록 409 REVISION_CONFLICT입니다.
+func sendTransactionErrors(c *fiber.Ctx, opErrs []TransactionOpError, total int) error {
+	code := "REVISION_CONFLICT"
+	for _, e := range opErrs {
+		if e.CurrentRevision == nil {
+			code = "TRANSACTION_INVALID"
+		}
+	}
+	response := StandardResponse{
+		Success: false,
+		Error: &ApiError{
+			Code:       code,
+			Message:    fmt.Sprintf("%d of %d operations failed, nothing was applied", len(opErrs), total),
+			Operations: opErrs,
+		},
+		Timestamp: time.Now(),
+		RequestID: c.Get("X-Request-ID", generateRequestID()),
+	}
+	return c.Status(getStatusCodeFromErrorCode(code)).JSON(response)
+}
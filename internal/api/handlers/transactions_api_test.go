@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/schema"
+)
+
+func TestParseTransactionOp(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	target := "3F2A9C1E-0B7D-4E65-9A1B-2C3D4E5F6A7B"
+
+	op := TransactionOp{Op: txOpInsert, TargetID: target, Category: "temperature", Payload: map[string]interface{}{"temp": 21.5}}
+	if err := parseTransactionOp(&op, now); err != nil || !op.ts.Equal(now) || op.TargetID != "3f2a9c1e-0b7d-4e65-9a1b-2c3d4e5f6a7b" {
+		t.Fatalf("unexpected op %+v, %v", op, err)
+	}
+	op = TransactionOp{Op: txOpInsert, TargetID: target, Category: "temperature", Ts: "2025-01-02T03:04:05Z", Payload: 1.5}
+	if err := parseTransactionOp(&op, now); err != nil || op.ts.Day() != 2 {
+		t.Fatalf("unexpected op %+v, %v", op, err)
+	}
+	op = TransactionOp{Op: txOpDelete, TargetID: target, Category: "pump"}
+	if err := parseTransactionOp(&op, now); err != nil {
+		t.Fatal(err)
+	}
+	op = TransactionOp{Op: txOpSet, TargetID: target, Category: "pump", Data: map[string]interface{}{}, Expected: new(int)}
+	if err := parseTransactionOp(&op, now); err != nil {
+		t.Fatal(err)
+	}
+
+	negative := -1
+	for _, bad := range []TransactionOp{
+		{Op: "upsert", TargetID: target, Category: "pump"},
+		{Op: txOpSet, TargetID: "pump-4", Category: "pump", Data: map[string]interface{}{}},
+		{Op: txOpSet, TargetID: target, Data: map[string]interface{}{}},
+		{Op: txOpSet, TargetID: target, Category: "pump"},
+		{Op: txOpSet, TargetID: target, Category: "pump", Data: map[string]interface{}{}, Expected: &negative},
+		{Op: txOpDelete, TargetID: target, Category: "pump", Expected: new(int)},
+		{Op: txOpInsert, TargetID: target, Category: "temperature"},
+		{Op: txOpInsert, TargetID: target, Category: "temperature", Ts: "yesterday", Payload: 1},
+	} {
+		if err := parseTransactionOp(&bad, now); err == nil {
+			t.Errorf("%+v accepted", bad)
+		}
+	}
+}
+
+func TestIsTransactionOpError(t *testing.T) {
+	for _, err := range []error{
+		opErrorf("category %s not found", "pump"),
+		&schema.ValidationError{},
+		fmt.Errorf("insert: %w", &pq.Error{Code: "23503"}),
+		&pq.Error{Code: "22P02"},
+		fmt.Errorf("set: %w", &database.RevisionConflictError{Expected: 2, Current: 3}),
+	} {
+		if !isTransactionOpError(err) {
+			t.Errorf("%v: expected operation error", err)
+		}
+	}
+	for _, err := range []error{errors.New("connection reset"), &pq.Error{Code: "40001"}} {
+		if isTransactionOpError(err) {
+			t.Errorf("%v: expected database error", err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/usage"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// storageUsageTTL 저장량 계산은 관측값을 모두 읽으므로 조직별로 잠시 재사용
+const storageUsageTTL = 5 * time.Minute
+
+var (
+	storageUsageMu    sync.Mutex
+	storageUsageCache = make(map[string]*database.StorageUsage)
+)
+
+// cachedStorageUsage는 storageUsageTTL 안에 계산한 조직의 저장량을 재사용합니다
+func cachedStorageUsage(orgID string) (*database.StorageUsage, error) {
+	storageUsageMu.Lock()
+	cached := storageUsageCache[orgID]
+	storageUsageMu.Unlock()
+	if cached != nil && time.Since(cached.MeasuredAt) < storageUsageTTL {
+		return cached, nil
+	}
+
+	storage, err := database.GetStorageUsage(database.GetDB(), orgID)
+	if err != nil {
+		return nil, err
+	}
+	storageUsageMu.Lock()
+	storageUsageCache[orgID] = storage
+	storageUsageMu.Unlock()
+	return storage, nil
+}
+
+// GetUsageReport는 토큰 조직의 기간 사용량(API 요청 수, 수집량, 수집 속도)과 현재 저장량을 반환합니다.
+// 기간은 from, to 쿼리 파라미터이고 기본값은 이번 달(UTC)입니다. 관리자 토큰이 필요합니다.
+func GetUsageReport(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	from, to, err := usage.ParsePeriod(c.Query("from"), c.Query("to"), time.Now())
+	if err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", err.Error(), "")
+	}
+
+	report, err := database.GetOrgUsage(database.GetDB(), orgID, from, to)
+	if err != nil {
+		log.Printf("Error getting usage for org %s: %v", orgID, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to get usage", "")
+	}
+	if len(report) == 0 {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Organization not found", "")
+	}
+	orgUsage := report[0]
+	if orgUsage.Storage, err = cachedStorageUsage(orgID); err != nil {
+		log.Printf("Error measuring storage for org %s: %v", orgID, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to measure storage", "")
+	}
+	return sendSuccessResponse(c, orgUsage, nil)
+}
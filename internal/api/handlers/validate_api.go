@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/schema"
+)
+
+// DocumentValidation은 문서 하나의 검증 결과입니다
+type DocumentValidation struct {
+	Index  int                 `json:"index"`
+	Valid  bool                `json:"valid"`
+	Error  string              `json:"error,omitempty"`
+	Fields []schema.FieldError `json:"fields,omitempty"`
+}
+
+// ValidationResult는 문서 일괄 검증 응답 데이터입니다
+type ValidationResult struct {
+	Category      string               `json:"category"`
+	SchemaVersion int                  `json:"schema_version"`
+	Total         int                  `json:"total"`
+	Valid         int                  `json:"valid"`
+	Invalid       int                  `json:"invalid"`
+	Results       []DocumentValidation `json:"results"`
+}
+
+// ValidateCategoryDocuments는 JSON 배열의 문서들을 카테고리 스키마로 검증만 하고 저장하지 않습니다.
+// 스키마 버전은 schema_version 쿼리, 없으면 API 경로의 버전(v1, v2)이고 latest/all이면 최신 버전입니다.
+// 연동 전에 페이로드를 시험해 볼 수 있도록 문서별, 필드별 오류를 반환합니다.
+func ValidateCategoryDocuments(c *fiber.Ctx) error {
+	category := c.Params("category")
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+
+	version, err := validationSchemaVersion(c.Query("schema_version"), middleware.GetVersionContext(c))
+	if err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", err.Error(), "")
+	}
+
+	var documents []json.RawMessage
+	if err := json.Unmarshal(bytes.TrimSpace(c.Body()), &documents); err != nil {
+		return sendErrorResponse(c, "INVALID_JSON", "Request body must be a JSON array of documents", err.Error())
+	}
+	if len(documents) == 0 {
+		return sendErrorResponse(c, "INVALID_JSON", "No documents in request body", "")
+	}
+	if len(documents) > maxBulkRecords {
+		return sendErrorResponse(c, "INVALID_JSON",
+			fmt.Sprintf("Too many documents: %d (max %d)", len(documents), maxBulkRecords), "")
+	}
+
+	definition, err := database.GetCategorySchemaVersion(database.GetReadDB(), orgID, category, version)
+	if err == sql.ErrNoRows {
+		message := fmt.Sprintf("Category %s not found", category)
+		if version > 0 {
+			message = fmt.Sprintf("Category %s has no schema version %d", category, version)
+		}
+		return sendErrorResponse(c, "CATEGORY_NOT_FOUND", message, "")
+	}
+	if err != nil {
+		log.Printf("Error loading schema of %s: %v", category, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to load category schema", "")
+	}
+	compiled, err := schema.Cached(definition.SchemaDefinition)
+	if err != nil {
+		return sendErrorResponse(c, "SCHEMA_VALIDATION_ERROR", fmt.Sprintf("invalid schema format: %v", err), "")
+	}
+
+	result := validateDocuments(compiled, documents)
+	result.Category = category
+	result.SchemaVersion = definition.Version
+	return sendSuccessResponse(c, result, nil)
+}
+
+// validationSchemaVersion은 검증할 스키마 버전을 정합니다 (0이면 최신 버전)
+func validationSchemaVersion(query string, versionCtx *middleware.VersionContext) (int, error) {
+	if query != "" {
+		version, err := strconv.Atoi(strings.TrimPrefix(query, "v"))
+		if err != nil || version < 1 {
+			return 0, errors.New("schema_version must be a version number")
+		}
+		return version, nil
+	}
+	if version, err := strconv.Atoi(strings.TrimPrefix(versionCtx.RequestedVersion, "v")); err == nil {
+		return version, nil
+	}
+	return 0, nil
+}
+
+// validateDocuments는 문서마다 스키마 검증 결과를 모읍니다.
+// 카테고리 문서는 저장 API와 같이 JSON 객체여야 합니다.
+func validateDocuments(compiled *schema.Schema, documents []json.RawMessage) *ValidationResult {
+	result := &ValidationResult{
+		Total:   len(documents),
+		Results: make([]DocumentValidation, len(documents)),
+	}
+
+	for i, raw := range documents {
+		r := DocumentValidation{Index: i, Valid: true}
+		var document map[string]interface{}
+		if err := json.Unmarshal(raw, &document); err != nil || document == nil {
+			r.Valid = false
+			r.Error = "document must be a JSON object"
+		} else if err := compiled.Validate(document); err != nil {
+			r.Valid = false
+			r.Error = err.Error()
+			var validationErr *schema.ValidationError
+			if errors.As(err, &validationErr) {
+				r.Error = "document does not match category schema"
+				r.Fields = validationErr.Errors
+			}
+		}
+
+		if r.Valid {
+			result.Valid++
+		} else {
+			result.Invalid++
+		}
+		result.Results[i] = r
+	}
+
+	return result
+}
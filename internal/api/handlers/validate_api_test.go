@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/schema"
+)
+
+func TestValidateDocuments(t *testing.T) {
+	compiled, err := schema.CompileJSON([]byte(`{"type":"object","required":["name"],"properties":{"name":{"type":"string"},"ward":{"type":"integer","minimum":1}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var documents []json.RawMessage
+	if err := json.Unmarshal([]byte(`[{"name":"a","ward":2},{"ward":0},"text",null]`), &documents); err != nil {
+		t.Fatal(err)
+	}
+
+	result := validateDocuments(compiled, documents)
+	if result.Total != 4 || result.Valid != 1 || result.Invalid != 3 {
+		t.Fatalf("unexpected counts %+v", result)
+	}
+	if !result.Results[0].Valid || result.Results[0].Error != "" {
+		t.Errorf("document 0: %+v", result.Results[0])
+	}
+	if r := result.Results[1]; r.Valid || len(r.Fields) != 2 {
+		t.Errorf("document 1: expected missing name and ward minimum, got %+v", r)
+	}
+	for _, r := range result.Results[2:] {
+		if r.Valid || r.Error != "document must be a JSON object" {
+			t.Errorf("document %d: %+v", r.Index, r)
+		}
+	}
+}
+
+func TestValidationSchemaVersion(t *testing.T) {
+	for _, tc := range []struct {
+		query, api string
+		want       int
+	}{
+		{"", "v2", 2},
+		{"", "latest", 0},
+		{"", "all", 0},
+		{"3", "v1", 3},
+		{"v4", "latest", 4},
+	} {
+		got, err := validationSchemaVersion(tc.query, &middleware.VersionContext{RequestedVersion: tc.api})
+		if err != nil || got != tc.want {
+			t.Errorf("%q %q: got %d, %v, want %d", tc.query, tc.api, got, err, tc.want)
+		}
+	}
+	for _, bad := range []string{"0", "-1", "x"} {
+		if _, err := validationSchemaVersion(bad, &middleware.VersionContext{RequestedVersion: "v1"}); err == nil {
+			t.Errorf("%q accepted", bad)
+		}
+	}
+}
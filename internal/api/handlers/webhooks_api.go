@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/webhook"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxWebhookDeliveries 전송 기록 조회 한 번에 반환하는 최대 수
+const maxWebhookDeliveries = 200
+
+// webhookRequest는 webhook 등록/수정 요청입니다. 수정할 때는 보낸 필드만 바꿉니다.
+type webhookRequest struct {
+	URL          *string  `json:"url"`
+	Events       []string `json:"events"`
+	Secret       *string  `json:"secret"`        // 비어 있으면 생성
+	RotateSecret bool     `json:"rotate_secret"` // 수정할 때 새 비밀값 생성
+	Description  *string  `json:"description"`
+	IsActive     *bool    `json:"is_active"`
+}
+
+// webhookWithSecret은 비밀값을 한 번 보여 주는 응답입니다 (등록, 비밀값 교체)
+type webhookWithSecret struct {
+	database.Webhook
+	Secret string `json:"secret"`
+}
+
+// applyWebhookRequest는 요청을 webhook에 반영하고 검증합니다. 비밀값이 바뀌면 true를 반환합니다.
+func applyWebhookRequest(w *database.Webhook, req webhookRequest) (bool, error) {
+	if req.URL != nil {
+		w.URL = strings.TrimSpace(*req.URL)
+	}
+	if err := webhook.ValidateURL(w.URL); err != nil {
+		return false, err
+	}
+	if req.Events != nil || w.EventTypes == nil {
+		events, err := webhook.ValidateEvents(req.Events)
+		if err != nil {
+			return false, err
+		}
+		w.EventTypes = events
+	}
+	if req.Description != nil {
+		w.Description = *req.Description
+	}
+	if req.IsActive != nil {
+		w.IsActive = *req.IsActive
+	}
+
+	switch {
+	case req.Secret != nil && *req.Secret != "":
+		w.Secret = *req.Secret
+	case w.Secret == "" || req.RotateSecret || req.Secret != nil:
+		secret, err := webhook.NewSecret()
+		if err != nil {
+			return false, err
+		}
+		w.Secret = secret
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// ListWebhooks는 토큰 조직의 webhook 목록을 반환합니다 (비밀값 제외)
+func ListWebhooks(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	hooks, err := database.ListWebhooks(database.GetDB(), orgID)
+	if err != nil {
+		log.Printf("Error listing webhooks for org %s: %v", orgID, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to list webhooks", "")
+	}
+	return sendSuccessResponse(c, hooks, nil)
+}
+
+// CreateWebhook은 토큰 조직에 webhook을 등록합니다.
+// 본문: {"url", "events": [...], "secret"(선택), "description"(선택), "is_active"(기본 true)}.
+// 응답의 secret은 이때만 보여 주므로 수신 측 서명 확인에 쓰도록 보관해야 합니다.
+func CreateWebhook(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	var req webhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendErrorResponse(c, "INVALID_JSON", "Invalid request body", err.Error())
+	}
+
+	w := database.Webhook{OrgID: orgID, IsActive: true}
+	if _, err := applyWebhookRequest(&w, req); err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", err.Error(), "")
+	}
+	if err := database.CreateWebhook(database.GetDB(), &w); err != nil {
+		log.Printf("Error creating webhook for org %s: %v", orgID, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to create webhook", "")
+	}
+	c.Status(fiber.StatusCreated)
+	return sendSuccessResponse(c, webhookWithSecret{Webhook: w, Secret: w.Secret}, nil)
+}
+
+// lookupWebhook은 요청 경로의 webhook을 토큰 조직 범위로 조회합니다.
+// 찾지 못하면 에러 응답을 보내고 nil webhook과 전송 결과를 반환합니다.
+func lookupWebhook(c *fiber.Ctx) (*database.Webhook, error) {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return nil, sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	w, err := database.GetWebhook(database.GetDB(), c.Params("webhook_id"), orgID)
+	if err == sql.ErrNoRows {
+		return nil, sendErrorResponse(c, "WEBHOOK_NOT_FOUND", "Webhook not found", "")
+	}
+	if err != nil {
+		log.Printf("Error getting webhook %s: %v", c.Params("webhook_id"), err)
+		return nil, sendErrorResponse(c, "DATABASE_ERROR", "Failed to get webhook", "")
+	}
+	return w, nil
+}
+
+// GetWebhook은 webhook 하나를 반환합니다 (비밀값 제외)
+func GetWebhook(c *fiber.Ctx) error {
+	w, err := lookupWebhook(c)
+	if w == nil {
+		return err
+	}
+	return sendSuccessResponse(c, w, nil)
+}
+
+// UpdateWebhook은 webhook의 주소, 이벤트, 설명, 활성 상태를 바꿉니다.
+// rotate_secret=true나 secret을 보내면 비밀값을 바꾸고 응답에 새 값을 한 번 보여 줍니다.
+func UpdateWebhook(c *fiber.Ctx) error {
+	w, err := lookupWebhook(c)
+	if w == nil {
+		return err
+	}
+	var req webhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return sendErrorResponse(c, "INVALID_JSON", "Invalid request body", err.Error())
+	}
+	rotated, err := applyWebhookRequest(w, req)
+	if err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", err.Error(), "")
+	}
+	if err := database.UpdateWebhook(database.GetDB(), w); err != nil {
+		log.Printf("Error updating webhook %s: %v", w.WebhookID, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to update webhook", "")
+	}
+	if rotated {
+		return sendSuccessResponse(c, webhookWithSecret{Webhook: *w, Secret: w.Secret}, nil)
+	}
+	return sendSuccessResponse(c, w, nil)
+}
+
+// DeleteWebhook은 webhook과 전송 기록을 삭제합니다
+func DeleteWebhook(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	deleted, err := database.DeleteWebhook(database.GetDB(), c.Params("webhook_id"), orgID)
+	if err != nil {
+		log.Printf("Error deleting webhook %s: %v", c.Params("webhook_id"), err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to delete webhook", "")
+	}
+	if !deleted {
+		return sendErrorResponse(c, "WEBHOOK_NOT_FOUND", "Webhook not found", "")
+	}
+	return sendSuccessResponse(c, fiber.Map{"deleted": true}, nil)
+}
+
+// GetWebhookDeliveries는 webhook의 전송 기록을 최신순으로 반환합니다.
+// status(pending, delivered, failed), event, before(delivery_id, 다음 페이지), limit(기본 50)으로 거릅니다.
+func GetWebhookDeliveries(c *fiber.Ctx) error {
+	w, err := lookupWebhook(c)
+	if w == nil {
+		return err
+	}
+
+	filter := database.WebhookDeliveryFilter{
+		Status:    c.Query("status"),
+		EventType: c.Query("event"),
+		Limit:     c.QueryInt("limit", 50),
+	}
+	if filter.Limit < 1 || filter.Limit > maxWebhookDeliveries {
+		return sendErrorResponse(c, "INVALID_REQUEST", "limit must be between 1 and 200", "")
+	}
+	if filter.Status != "" && !slices.Contains(webhook.Statuses, filter.Status) {
+		return sendErrorResponse(c, "INVALID_REQUEST", "status must be one of "+strings.Join(webhook.Statuses, ", "), "")
+	}
+	if before := c.Query("before"); before != "" {
+		if filter.Before, err = strconv.ParseInt(before, 10, 64); err != nil {
+			return sendErrorResponse(c, "INVALID_REQUEST", "before must be a delivery_id", "")
+		}
+	}
+
+	deliveries, err := database.ListWebhookDeliveries(database.GetDB(), w.OrgID, w.WebhookID, filter)
+	if err != nil {
+		log.Printf("Error listing deliveries of webhook %s: %v", w.WebhookID, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to list webhook deliveries", "")
+	}
+	return sendSuccessResponse(c, deliveries, nil)
+}
+
+// RedeliverWebhook은 전송 기록을 대기 상태로 되돌려 바로 다시 보내게 합니다 (재시도 횟수도 초기화).
+// 주소 검사가 생기기 전에 등록된 webhook일 수 있으므로 주소를 다시 확인합니다.
+func RedeliverWebhook(c *fiber.Ctx) error {
+	w, err := lookupWebhook(c)
+	if w == nil {
+		return err
+	}
+	if err := webhook.ValidateURL(w.URL); err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", err.Error(), "")
+	}
+	deliveryID, err := strconv.ParseInt(c.Params("delivery_id"), 10, 64)
+	if err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Invalid delivery_id", "")
+	}
+
+	found, err := database.RedeliverWebhookDelivery(database.GetDB(), w.OrgID, w.WebhookID, deliveryID)
+	if err != nil {
+		log.Printf("Error redelivering %d of webhook %s: %v", deliveryID, w.WebhookID, err)
+		return sendErrorResponse(c, "DATABASE_ERROR", "Failed to redeliver webhook", "")
+	}
+	if !found {
+		return sendErrorResponse(c, "WEBHOOK_NOT_FOUND", "Delivery not found", "")
+	}
+	return sendSuccessResponse(c, fiber.Map{"delivery_id": deliveryID, "status": webhook.StatusPending}, nil)
+}
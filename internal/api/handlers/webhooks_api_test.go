@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/webhook"
+)
+
+func TestApplyWebhookRequest(t *testing.T) {
+	url := "https://example.com/hook"
+
+	// 등록: 비밀값을 보내지 않으면 생성
+	w := database.Webhook{IsActive: true}
+	rotated, err := applyWebhookRequest(&w, webhookRequest{URL: &url, Events: []string{webhook.EventTargetCreated}})
+	if err != nil || !rotated || !strings.HasPrefix(w.Secret, "whsec_") {
+		t.Fatalf("create: rotated=%v secret=%q err=%v", rotated, w.Secret, err)
+	}
+	if _, err := applyWebhookRequest(&database.Webhook{}, webhookRequest{URL: &url}); err == nil {
+		t.Error("create without events accepted")
+	}
+	bad := "ftp://example.com"
+	if _, err := applyWebhookRequest(&database.Webhook{}, webhookRequest{URL: &bad, Events: []string{webhook.EventTargetCreated}}); err == nil {
+		t.Error("non-http url accepted")
+	}
+
+	// 수정: 보낸 필드만 바꾸고 비밀값은 유지
+	secret := w.Secret
+	inactive := false
+	rotated, err = applyWebhookRequest(&w, webhookRequest{IsActive: &inactive})
+	if err != nil || rotated || w.Secret != secret || w.IsActive || len(w.EventTypes) != 1 {
+		t.Errorf("partial update: rotated=%v webhook=%+v err=%v", rotated, w, err)
+	}
+	if _, err := applyWebhookRequest(&w, webhookRequest{Events: []string{}}); err == nil {
+		t.Error("empty event list accepted on update")
+	}
+
+	rotated, err = applyWebhookRequest(&w, webhookRequest{RotateSecret: true})
+	if err != nil || !rotated || w.Secret == secret {
+		t.Errorf("rotate: rotated=%v err=%v", rotated, err)
+	}
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tmidb/tmidb-core/internal/api/middleware"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/lineprotocol"
+)
+
+// maxWriteErrors 부분 쓰기 응답에 담는 줄 오류 수
+const maxWriteErrors = 10
+
+// WriteLineProtocol은 InfluxDB line protocol 본문을 시계열로 수집합니다 (Telegraf 등의 InfluxDB 출력과 호환).
+// 측정값은 카테고리, 태그는 타겟, 필드는 payload가 됩니다. 같은 태그 조합은 조직 안에서 항상 같은 타겟이고,
+// 처음 보는 타겟은 만들어 카테고리에 연결합니다 (태그가 category_data). target_id 태그가 UUID면 그 타겟에 씁니다.
+// 모두 저장하면 204, 일부 줄이 실패하면 나머지는 저장하고 400으로 실패한 줄을 알려 줍니다 (InfluxDB의 partial write).
+func WriteLineProtocol(c *fiber.Ctx) error {
+	orgID, err := middleware.GetOrgIDFromToken(c)
+	if err != nil {
+		return sendErrorResponse(c, "AUTH_ERROR", err.Error(), "")
+	}
+	precision, err := lineprotocol.ParsePrecision(c.Query("precision"))
+	if err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", err.Error(), "")
+	}
+	body, err := writeRequestBody(c)
+	if err != nil {
+		return sendErrorResponse(c, "INVALID_REQUEST", "Invalid request body", err.Error())
+	}
+
+	points, lineErrs := lineprotocol.Parse(body, precision, time.Now())
+	total := len(points) + len(lineErrs)
+	if total == 0 {
+		return sendErrorResponse(c, "INVALID_REQUEST", "No points in request body", "")
+	}
+	if total > maxBulkRecords {
+		return sendErrorResponse(c, "INVALID_REQUEST", fmt.Sprintf("Too many points: %d (max %d)", total, maxBulkRecords), "")
+	}
+
+	var errs []string
+	for _, e := range lineErrs {
+		errs = append(errs, e.Error())
+	}
+
+	// 측정값(카테고리)별로 요청 순서를 유지하며 나눔
+	var categories []string
+	byCategory := make(map[string][]lineprotocol.Point)
+	for _, p := range points {
+		if _, ok := byCategory[p.Measurement]; !ok {
+			categories = append(categories, p.Measurement)
+		}
+		byCategory[p.Measurement] = append(byCategory[p.Measurement], p)
+	}
+
+	db := database.GetDB()
+	inserted := 0
+	var written, targets []string
+	for _, category := range categories {
+		items, err := lineItems(c, db, orgID, category, byCategory[category])
+		if err != nil {
+			log.Printf("Error preparing line protocol write to %s: %v", category, err)
+			return sendErrorResponse(c, "DATABASE_ERROR", "Failed to write points", "")
+		}
+		if err := validateBulkRecords(db, category, items); err != nil {
+			return sendErrorResponse(c, "DATABASE_ERROR", err.Error(), "")
+		}
+		if err := insertBulkRecords(db, category, items); err != nil {
+			return sendErrorResponse(c, "DATABASE_ERROR", err.Error(), "")
+		}
+
+		n := 0
+		seen := make(map[string]bool)
+		for i, item := range items {
+			if item.err != nil {
+				errs = append(errs, fmt.Sprintf("line %d: %v", byCategory[category][i].Line, item.err))
+				continue
+			}
+			n++
+			if !seen[item.record.TargetID] {
+				seen[item.record.TargetID] = true
+				targets = append(targets, item.record.TargetID)
+			}
+		}
+		if n > 0 {
+			inserted += n
+			written = append(written, category)
+		}
+	}
+
+	if inserted > 0 {
+		invalidateCache(written, targets)
+	}
+	if len(errs) == 0 {
+		middleware.RecordIngested(c, inserted)
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	// 400 응답은 IngestQuota가 세지 않으므로 저장한 줄은 여기서 더함
+	if inserted > 0 {
+		middleware.AddIngested(orgID, int64(inserted), int64(len(body)))
+	}
+	if len(errs) > maxWriteErrors {
+		errs = append(errs[:maxWriteErrors], fmt.Sprintf("... and %d more", len(errs)-maxWriteErrors))
+	}
+	return sendErrorResponse(c, "INVALID_REQUEST",
+		fmt.Sprintf("partial write: %d of %d points rejected", total-inserted, total), strings.Join(errs, "; "))
+}
+
+// writeRequestBody는 요청 본문을 반환합니다 (Content-Encoding: gzip이면 MaxBulkBodySize까지 풂)
+func writeRequestBody(c *fiber.Ctx) ([]byte, error) {
+	if !strings.EqualFold(c.Get(fiber.HeaderContentEncoding), "gzip") {
+		return c.Body(), nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(c.Body()))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	body, err := io.ReadAll(io.LimitReader(zr, MaxBulkBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > MaxBulkBodySize {
+		return nil, fmt.Errorf("decompressed body exceeds %d bytes", MaxBulkBodySize)
+	}
+	return body, nil
+}
+
+// lineItems는 한 카테고리의 점을 대량 수집 레코드로 바꾸고 타겟과 카테고리 연결을 준비합니다.
+// 권한이 없거나 카테고리가 없거나 다른 조직의 타겟이면 해당 레코드의 err로 남깁니다.
+func lineItems(c *fiber.Ctx, db database.DBTX, orgID, category string, points []lineprotocol.Point) ([]*bulkItem, error) {
+	items := make([]*bulkItem, len(points))
+	for i, p := range points {
+		items[i] = &bulkItem{record: BulkRecord{Payload: p.Fields}, ts: p.Ts}
+	}
+
+	var categoryErr error
+	link, err := database.GetActiveCategorySchema(db, orgID, category)
+	switch {
+	case !middleware.CategoryAllowed(c, "write", category):
+		categoryErr = fmt.Errorf("missing write permission for category %s", category)
+	case err == sql.ErrNoRows:
+		categoryErr = fmt.Errorf("category %s not found", category)
+	case err != nil:
+		return nil, err
+	}
+	if categoryErr != nil {
+		for _, item := range items {
+			item.err = categoryErr
+		}
+		return items, nil
+	}
+
+	linked := make(map[string]error) // 타겟 ID -> 연결 결과
+	for i, p := range points {
+		targetID := p.Tags[lineprotocol.TargetIDTag]
+		if !uuidPattern.MatchString(targetID) {
+			targetID = lineprotocol.TargetID(orgID, p.SeriesKey())
+		}
+		targetID = strings.ToLower(targetID)
+		items[i].record.TargetID = targetID
+
+		linkErr, ok := linked[targetID]
+		if !ok {
+			tags := make(map[string]string, len(p.Tags))
+			for k, v := range p.Tags {
+				if k != lineprotocol.TargetIDTag {
+					tags[k] = v
+				}
+			}
+			data, _ := json.Marshal(tags)
+			owner, err := database.EnsureTargetLink(db, targetID, p.SeriesKey(), link, category, string(data))
+			switch {
+			case errors.Is(err, database.ErrInRecycleBin):
+				linkErr = err
+			case err != nil:
+				return nil, err
+			case owner != orgID:
+				linkErr = fmt.Errorf("target %s belongs to another organization", targetID)
+			}
+			linked[targetID] = linkErr
+		}
+		items[i].err = linkErr
+	}
+	return items, nil
+}
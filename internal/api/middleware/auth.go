@@ -4,7 +4,6 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
-	"strings"
 
 	"github.com/tmidb/tmidb-core/internal/database"
 
@@ -27,44 +26,22 @@ func HashToken(token string) string {
 }
 
 // TokenAuthRequired는 API 요청에 대한 토큰 인증을 처리하는 미들웨어입니다.
+// TokenAuthMiddleware와 같으며, 인증된 조직/사용자/권한을 컨텍스트에 저장합니다.
 func TokenAuthRequired(requiredPermission string, getCategory func(*fiber.Ctx) string) fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		authHeader := c.Get(HEADER_AUTHORIZATION)
-		if authHeader == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Authorization header is required"})
-		}
-
-		if !strings.HasPrefix(authHeader, HEADER_BEARER_PREFIX) {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid token format, must be Bearer token"})
-		}
-
-		token := strings.TrimPrefix(authHeader, HEADER_BEARER_PREFIX)
-		tokenHash := HashToken(token)
-
-		var categoryName string
-		if getCategory != nil {
-			categoryName = getCategory(c)
-		}
-
-		var hasPermission bool
-		err := database.DB.QueryRow("SELECT verify_token($1, $2, $3)", tokenHash, requiredPermission, categoryName).Scan(&hasPermission)
-		if err != nil || !hasPermission {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Permission denied"})
-		}
-
-		return c.Next()
-	}
+	return TokenAuthMiddleware(requiredPermission, getCategory)
 }
 
 // VerifyTokenForLogin은 로그인 시 토큰을 검증합니다.
 func VerifyTokenForLogin(token string) (bool, error) {
-	tokenHash := HashToken(token)
-	var hasPermission bool
-	err := database.DB.QueryRow("SELECT verify_token($1, 'admin', NULL)", tokenHash).Scan(&hasPermission)
-	return hasPermission, err
+	identity, err := database.AuthenticateToken(token)
+	if err != nil {
+		return false, err
+	}
+	return identity.Permissions.Admin, nil
 }
 
 // AuthRequired는 인증이 필요한 경로를 보호하는 미들웨어입니다.
+// 세션이 만료되었어도 유효한 리프레시 토큰이 있으면 세션을 다시 만들고, 활동 중인 세션은 만료를 늦춥니다.
 func AuthRequired(store *session.Store) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		sess, err := store.Get(c)
@@ -73,9 +50,13 @@ func AuthRequired(store *session.Store) fiber.Handler {
 		}
 
 		if sess.Get("authenticated") != true {
-			return c.Redirect("/login")
+			if !restoreSession(c, sess) {
+				return c.Redirect("/login")
+			}
+			return c.Next()
 		}
 
+		touchSession(sess)
 		return c.Next()
 	}
 }
@@ -101,6 +82,36 @@ func AdminRequired(store *session.Store) fiber.Handler {
 	}
 }
 
+// InstallationAdminRequired는 설치 관리자 세션만 통과시키는 미들웨어입니다.
+// 세션의 role은 로그인한 조직 안의 역할이므로, 조직 목록/생성/삭제나 조직 전환처럼
+// 다른 조직에 닿는 경로는 AdminRequired 대신 이것으로 보호합니다.
+func InstallationAdminRequired(store *session.Store) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, err := GetUserID(c, store)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).SendString("Unauthorized")
+		}
+		ok, err := isInstallationAdmin(userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to verify user")
+		}
+		if !ok {
+			return c.Status(fiber.StatusForbidden).SendString("Installation admin privileges required")
+		}
+		return c.Next()
+	}
+}
+
+// IsInstallationAdmin은 현재 세션의 사용자가 설치 관리자인지 확인합니다.
+func IsInstallationAdmin(c *fiber.Ctx, store *session.Store) bool {
+	userID, err := GetUserID(c, store)
+	if err != nil {
+		return false
+	}
+	ok, err := isInstallationAdmin(userID)
+	return err == nil && ok
+}
+
 // GetUserID는 세션에서 사용자 ID를 가져옵니다.
 func GetUserID(c *fiber.Ctx, store *session.Store) (string, error) {
 	sess, err := store.Get(c)
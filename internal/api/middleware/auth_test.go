@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+func TestInstallationAdminRequired(t *testing.T) {
+	fakeInstallationAdmin(t)
+	store := session.New()
+
+	app := fiber.New()
+	// 두 사용자 모두 자기 조직의 admin 역할로 로그인
+	app.Get("/login/:user", func(c *fiber.Ctx) error {
+		sess, err := store.Get(c)
+		if err != nil {
+			return err
+		}
+		sess.Set("authenticated", true)
+		sess.Set("user_id", c.Params("user"))
+		sess.Set("role", "admin")
+		return sess.Save()
+	})
+	app.Get("/api/manage/organizations", InstallationAdminRequired(store), func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	request := func(user string) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/manage/organizations", nil)
+		if user != "" {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/login/"+user, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, cookie := range resp.Cookies() {
+				req.AddCookie(cookie)
+			}
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode
+	}
+
+	if status := request("user-1"); status != 200 {
+		t.Errorf("installation admin: status %d", status)
+	}
+	if status := request("user-2"); status != 403 {
+		t.Errorf("admin of a second organization: status %d, want 403", status)
+	}
+	if status := request(""); status != 401 {
+		t.Errorf("no session: status %d, want 401", status)
+	}
+}
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/config"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+)
+
+// CSRF 토큰 전달 방법
+const (
+	CSRFCookieName = "csrf_token"   // 브라우저가 읽을 수 있는 쿠키 (/static/js/csrf.js가 헤더로 복사)
+	CSRFHeaderName = "X-CSRF-Token" // fetch 요청
+	CSRFFormField  = "_csrf"        // HTML 폼
+	CSRFContextKey = "csrf_token"   // 템플릿에 넣을 토큰 (c.Locals)
+)
+
+// csrfCookieTTL CSRF 쿠키 유효 기간 (쿠키가 남아 있는 동안 같은 토큰 사용)
+const csrfCookieTTL = 24 * time.Hour
+
+// CORS는 CORS_ALLOWED_ORIGINS에 따른 CORS 미들웨어입니다.
+// 목록이 비어 있으면 다른 출처의 요청을 허용하지 않고, "*"이면 모든 출처를 허용하되 쿠키는 받지 않습니다.
+// 출처를 지정하면 그 출처는 세션 쿠키를 보낼 수 있습니다.
+func CORS(cfg *config.Config) fiber.Handler {
+	if len(cfg.CORSAllowedOrigins) == 0 {
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
+	origins := strings.Join(cfg.CORSAllowedOrigins, ",")
+	return cors.New(cors.Config{
+		AllowOrigins:     origins,
+		AllowMethods:     "GET,POST,PUT,PATCH,DELETE,OPTIONS",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-Request-ID,If-None-Match," + CSRFHeaderName,
+		ExposeHeaders:    "ETag",
+		AllowCredentials: !allowsAnyOrigin(cfg.CORSAllowedOrigins),
+	})
+}
+
+// CSRFProtection은 세션 쿠키로 인증하는 웹 콘솔 경로를 CSRF에서 보호합니다 (double submit cookie).
+// 상태를 바꾸는 요청은 csrf_token 쿠키와 같은 값을 X-CSRF-Token 헤더나 _csrf 폼 필드로 보내야 하고,
+// Origin(없으면 Referer)이 같은 호스트이거나 CORS 허용 출처여야 합니다.
+// 토큰으로 인증하는 API 경로 (/api/ 아래, /api/manage 제외)는 쿠키를 쓰지 않으므로 검사하지 않습니다.
+func CSRFProtection(cfg *config.Config) fiber.Handler {
+	allowed := make(map[string]bool)
+	for _, origin := range cfg.CORSAllowedOrigins {
+		if origin != "*" {
+			allowed[strings.TrimRight(origin, "/")] = true
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		if csrfExempt(c.Path()) {
+			return c.Next()
+		}
+
+		cookieToken := c.Cookies(CSRFCookieName)
+		token := cookieToken
+		if !validCSRFToken(token) {
+			token = newCSRFToken()
+			c.Cookie(&fiber.Cookie{
+				Name:     CSRFCookieName,
+				Value:    token,
+				Path:     "/",
+				Expires:  time.Now().Add(csrfCookieTTL),
+				Secure:   c.Protocol() == "https",
+				HTTPOnly: false,
+				SameSite: "Lax",
+			})
+		}
+		c.Locals(CSRFContextKey, token)
+
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions, fiber.MethodTrace:
+			return c.Next()
+		}
+
+		if !sameOrigin(c, allowed) {
+			return csrfFailed(c, "cross-origin request rejected")
+		}
+		sent := c.Get(CSRFHeaderName)
+		if sent == "" {
+			sent = c.FormValue(CSRFFormField)
+		}
+		if !validCSRFToken(cookieToken) || subtle.ConstantTimeCompare([]byte(sent), []byte(cookieToken)) != 1 {
+			return csrfFailed(c, "missing or invalid CSRF token")
+		}
+		return c.Next()
+	}
+}
+
+// csrfExempt는 토큰으로 인증하는 API 경로인지 반환합니다 (관리 API는 세션 쿠키를 쓰므로 보호)
+func csrfExempt(path string) bool {
+	return strings.HasPrefix(path, "/api/") && !strings.HasPrefix(path, "/api/manage/") && path != "/api/manage"
+}
+
+// sameOrigin은 요청 출처가 같은 호스트나 허용 출처인지 확인합니다. 출처 정보가 없으면 허용합니다.
+// TLS를 끝내는 프록시 뒤에서도 동작하도록 같은 출처는 스킴이 아니라 호스트로 비교합니다.
+func sameOrigin(c *fiber.Ctx, allowed map[string]bool) bool {
+	origin := c.Get(fiber.HeaderOrigin)
+	if origin == "" {
+		origin = c.Get(fiber.HeaderReferer)
+		if origin == "" {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return u.Host == c.Hostname() || allowed[u.Scheme+"://"+u.Host]
+}
+
+// csrfFailed는 API나 JSON 요청이면 JSON 오류를, 폼 요청이면 403 텍스트를 반환합니다
+func csrfFailed(c *fiber.Ctx, reason string) error {
+	if strings.HasPrefix(c.Path(), "/api/") || strings.Contains(c.Get(fiber.HeaderAccept), fiber.MIMEApplicationJSON) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": reason})
+	}
+	return c.Status(fiber.StatusForbidden).SendString("Forbidden: " + reason + ". Reload the page and try again.")
+}
+
+// allowsAnyOrigin은 허용 출처에 "*"가 있는지 반환합니다
+func allowsAnyOrigin(origins []string) bool {
+	for _, origin := range origins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// newCSRFToken은 새 CSRF 토큰을 만듭니다 (32 bytes, base64url)
+func newCSRFToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// validCSRFToken은 newCSRFToken 형식인지 확인합니다
+func validCSRFToken(token string) bool {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	return err == nil && len(b) == 32
+}
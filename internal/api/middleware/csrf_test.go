@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/tmidb/tmidb-core/internal/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newCSRFTestApp() *fiber.App {
+	app := fiber.New()
+	app.Use(CSRFProtection(&config.Config{CORSAllowedOrigins: []string{"https://console.example.com"}}))
+	ok := func(c *fiber.Ctx) error { return c.SendString("ok") }
+	app.Get("/login", ok)
+	app.Post("/login", ok)
+	app.Post("/api/manage/categories", ok)
+	app.Post("/api/v1/category/sensor", ok)
+	return app
+}
+
+func TestCSRFProtection(t *testing.T) {
+	app := newCSRFTestApp()
+
+	// GET은 토큰 쿠키를 발급
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/login", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var token string
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == CSRFCookieName {
+			token = cookie.Value
+		}
+	}
+	if !validCSRFToken(token) {
+		t.Fatalf("GET did not issue a csrf cookie, got %q", token)
+	}
+
+	other := newCSRFToken()
+	tests := []struct {
+		name   string
+		path   string
+		cookie string
+		header string
+		form   string
+		origin string
+		want   int
+	}{
+		{"header matches cookie", "/api/manage/categories", token, token, "", "", fiber.StatusOK},
+		{"form field matches cookie", "/login", token, "", token, "", fiber.StatusOK},
+		{"same host origin", "/api/manage/categories", token, token, "", "http://example.com", fiber.StatusOK},
+		{"allowed cors origin", "/api/manage/categories", token, token, "", "https://console.example.com", fiber.StatusOK},
+		{"missing token", "/api/manage/categories", token, "", "", "", fiber.StatusForbidden},
+		{"missing cookie", "/api/manage/categories", "", token, "", "", fiber.StatusForbidden},
+		{"token mismatch", "/login", token, other, "", "", fiber.StatusForbidden},
+		{"cross origin", "/api/manage/categories", token, token, "", "https://evil.example", fiber.StatusForbidden},
+		{"token api exempt", "/api/v1/category/sensor", "", "", "", "https://evil.example", fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		var req *http.Request
+		if tt.form != "" {
+			req = httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(url.Values{CSRFFormField: {tt.form}}.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		} else {
+			req = httptest.NewRequest(http.MethodPost, tt.path, nil)
+		}
+		if tt.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: CSRFCookieName, Value: tt.cookie})
+		}
+		if tt.header != "" {
+			req.Header.Set(CSRFHeaderName, tt.header)
+		}
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, resp.StatusCode, tt.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tmidb/tmidb-core/internal/ratelimit"
+)
+
+// rateLimiter API 서버의 레이트 리밋 (InitRateLimiting 전에는 제한하지 않음)
+var rateLimiter *ratelimit.Limiter
+
+// 저장소 오류 로그는 1분에 한 번만 남김
+var (
+	storeErrorMu     sync.Mutex
+	storeErrorLogged time.Time
+)
+
+// InitRateLimiting은 요청 속도 제한과 수집 할당량에 쓸 Limiter를 설정합니다
+func InitRateLimiting(limiter *ratelimit.Limiter) {
+	rateLimiter = limiter
+}
+
+// GetRateLimiter는 설정된 Limiter를 반환합니다 (없으면 nil)
+func GetRateLimiter() *ratelimit.Limiter {
+	return rateLimiter
+}
+
+// IPRateLimit은 클라이언트 IP별 요청 속도를 제한하는 미들웨어입니다
+func IPRateLimit() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if rateLimiter == nil || !rateLimiter.IPRule().Enabled() {
+			return c.Next()
+		}
+		d, err := rateLimiter.AllowIP(c.IP())
+		logStoreError(err)
+		if !d.Allowed {
+			return tooManyRequests(c, d, "RATE_LIMITED", "Too many requests from this IP address")
+		}
+		return c.Next()
+	}
+}
+
+// TokenRateLimit은 토큰별 요청 속도를 제한하는 미들웨어입니다 (토큰 인증 뒤에 사용)
+func TokenRateLimit() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims := GetTokenClaims(c)
+		if rateLimiter == nil || claims == nil || !rateLimiter.TokenRule().Enabled() {
+			return c.Next()
+		}
+		d, err := rateLimiter.AllowToken(claims.TokenID, claims.OrgID)
+		logStoreError(err)
+		setRateLimitHeaders(c, d)
+		if !d.Allowed {
+			return tooManyRequests(c, d, "RATE_LIMITED", "Too many requests for this token")
+		}
+		return c.Next()
+	}
+}
+
+// IngestQuota는 조직의 일일 수집 할당량을 확인하고, 성공한 요청의 레코드 수를 사용량에 더합니다.
+// 조직별 사용량 기록에는 요청 본문 크기도 더합니다.
+// 핸들러가 RecordIngested로 레코드 수를 알려주지 않으면 요청 하나를 레코드 하나로 셉니다.
+func IngestQuota() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims := GetTokenClaims(c)
+		if claims == nil {
+			return c.Next()
+		}
+		if rateLimiter != nil {
+			d, err := rateLimiter.CheckQuota(claims.OrgID)
+			logStoreError(err)
+			if !d.Allowed {
+				return tooManyRequests(c, d, "QUOTA_EXCEEDED", "Daily ingest quota exceeded for this organization")
+			}
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+		if status := c.Response().StatusCode(); status < 200 || status >= 300 {
+			return nil
+		}
+		records, ok := c.Locals("ingested_records").(int)
+		if !ok {
+			records = 1
+		}
+		AddIngested(claims.OrgID, int64(records), int64(len(c.Body())))
+		return nil
+	}
+}
+
+// AddIngested는 수집한 레코드를 조직의 사용량과 일일 할당량에 더합니다.
+// 요청이 끝난 뒤에 저장하는 작업(비동기 가져오기)은 IngestQuota 대신 이 함수로 기록합니다.
+func AddIngested(orgID string, records, bytes int64) {
+	usageRecorder.AddIngested(orgID, records, bytes)
+	if rateLimiter != nil && records > 0 {
+		logStoreError(rateLimiter.AddIngested(orgID, records))
+	}
+}
+
+// RecordIngested는 요청에서 저장한 레코드 수를 수집 할당량 미들웨어에 알려줍니다
+func RecordIngested(c *fiber.Ctx, records int) {
+	c.Locals("ingested_records", records)
+}
+
+// setRateLimitHeaders는 X-RateLimit-Limit / X-RateLimit-Remaining 헤더를 설정합니다
+func setRateLimitHeaders(c *fiber.Ctx, d ratelimit.Decision) {
+	if d.Limit <= 0 {
+		return
+	}
+	c.Set("X-RateLimit-Limit", strconv.Itoa(d.Limit))
+	c.Set("X-RateLimit-Remaining", strconv.FormatInt(d.Remaining, 10))
+}
+
+// tooManyRequests는 Retry-After(초, 올림)와 함께 429 응답을 보냅니다
+func tooManyRequests(c *fiber.Ctx, d ratelimit.Decision, code, message string) error {
+	retryAfter := int(math.Ceil(d.RetryAfter.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+	return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+		"error":       message,
+		"code":        code,
+		"limit":       d.Limit,
+		"retry_after": retryAfter,
+	})
+}
+
+// logStoreError는 레이트 리밋 저장소 오류를 남깁니다. 저장소에 문제가 있으면 요청은 제한하지 않습니다.
+func logStoreError(err error) {
+	if err == nil {
+		return
+	}
+	storeErrorMu.Lock()
+	defer storeErrorMu.Unlock()
+	if time.Since(storeErrorLogged) < time.Minute {
+		return
+	}
+	storeErrorLogged = time.Now()
+	log.Printf("⚠️ 레이트 리밋 저장소 오류 (요청은 제한하지 않음): %v", err)
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tmidb/tmidb-core/internal/logger"
+)
+
+// maxRequestIDLength 클라이언트가 보낸 요청 ID를 그대로 사용할 최대 길이
+const maxRequestIDLength = 128
+
+// RequestLogger는 요청마다 X-Request-ID를 부여하고 구조화된 접근 로그를 남깁니다.
+// 클라이언트가 보낸 ID가 있으면 그대로 사용하고 응답 헤더로 돌려줍니다.
+func RequestLogger() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := c.Get(logger.RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = logger.NewRequestID()
+		}
+		c.Locals("request_id", requestID)
+		c.Set(logger.RequestIDHeader, requestID)
+
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if e, ok := err.(*fiber.Error); ok {
+			status = e.Code
+		} else if err != nil {
+			status = fiber.StatusInternalServerError
+		}
+
+		level := logger.LogLevelInfo
+		switch {
+		case status >= 500:
+			level = logger.LogLevelError
+		case status >= 400:
+			level = logger.LogLevelWarn
+		}
+
+		logger.Log(level, fmt.Sprintf("%s %s %d - %s", c.Method(), c.Path(), status, time.Since(start).Round(time.Microsecond)),
+			RequestFields(c)...)
+		return err
+	}
+}
+
+// RequestFields는 요청 추적용 로그 필드(request_id, target_id, org_id)를 반환합니다
+func RequestFields(c *fiber.Ctx) []logger.Field {
+	fields := []logger.Field{logger.F(logger.FieldRequestID, GetRequestID(c))}
+	if targetID := c.Params("target_id"); targetID != "" {
+		fields = append(fields, logger.F(logger.FieldTargetID, targetID))
+	}
+	if orgID, ok := c.Locals("org_id").(int); ok && orgID != 0 {
+		fields = append(fields, logger.F(logger.FieldOrgID, strconv.Itoa(orgID)))
+	}
+	return fields
+}
+
+// GetRequestID는 현재 요청의 ID를 반환합니다
+func GetRequestID(c *fiber.Ctx) string {
+	if requestID, ok := c.Locals("request_id").(string); ok {
+		return requestID
+	}
+	return ""
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r <= ' ' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/config"
+	"github.com/tmidb/tmidb-core/internal/database"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+// RefreshCookieName은 리프레시 토큰 쿠키 이름입니다
+const RefreshCookieName = "refresh_token"
+
+// sessionTouchInterval보다 오래된 세션만 요청 시 다시 저장해 만료를 늦춥니다 (요청마다 쓰지 않도록)
+const sessionTouchInterval = time.Minute
+
+// sessionGCInterval은 만료된 세션과 리프레시 토큰을 지우는 간격입니다
+const sessionGCInterval = 10 * time.Minute
+
+// 세션 설정 (NewSessionStore에서 정함)
+var sessions struct {
+	storage    *database.SessionStorage // memory 저장소이면 nil
+	refreshTTL time.Duration            // 0이면 리프레시 토큰 끔
+}
+
+// NewSessionStore는 SESSION_STORE에 맞는 웹 콘솔 세션 스토어를 만듭니다.
+// postgres이면 세션이 재시작 후에도 유지되고 여러 API 인스턴스가 공유하며, 사용자별로 세션을 끊을 수 있습니다.
+// 세션은 SESSION_IDLE_TIMEOUT_MINUTES 동안 요청이 없으면 만료되고, 리프레시 토큰이 있으면 다시 만들어집니다.
+func NewSessionStore(cfg *config.Config, db *sql.DB) *session.Store {
+	sessionConfig := session.Config{
+		KeyLookup:      "cookie:session_id",
+		CookieDomain:   "",
+		CookiePath:     "/",
+		CookieSecure:   false,
+		CookieHTTPOnly: true,
+		CookieSameSite: "Lax",
+		Expiration:     time.Duration(cfg.SessionIdleTimeout) * time.Minute,
+	}
+
+	switch cfg.SessionStore {
+	case "postgres":
+		sessions.storage = database.NewSessionStorage(db, sessionGCInterval)
+		sessionConfig.Storage = sessions.storage
+	case "memory":
+	default:
+		log.Printf("⚠️ 알 수 없는 SESSION_STORE %q, 메모리 세션 저장소 사용", cfg.SessionStore)
+	}
+	sessions.refreshTTL = time.Duration(cfg.SessionRefreshTTLDays) * 24 * time.Hour
+
+	return session.New(sessionConfig)
+}
+
+// SessionsRevocable은 사용자의 세션을 서버에서 지울 수 있는지 (postgres 세션 저장소인지) 반환합니다
+func SessionsRevocable() bool {
+	return sessions.storage != nil
+}
+
+// StartUserSession은 로그인한 사용자의 세션을 만들고 리프레시 토큰 쿠키를 발급합니다.
+// 세션 고정 공격을 막기 위해 세션 ID를 새로 만듭니다. 호출 뒤에는 sess를 쓰면 안 됩니다.
+func StartUserSession(c *fiber.Ctx, sess *session.Session, userID, orgID, username, role string) error {
+	if err := saveUserSession(sess, userID, orgID, username, role); err != nil {
+		return err
+	}
+	if sessions.refreshTTL <= 0 {
+		return nil
+	}
+
+	token, err := database.IssueRefreshToken(database.GetDB(), userID, sessions.refreshTTL)
+	if err != nil {
+		// 세션은 만들어졌으므로 로그인은 성공으로 처리 (세션이 만료되면 다시 로그인)
+		log.Printf("⚠️ 리프레시 토큰 발급 실패 (%s): %v", username, err)
+		return nil
+	}
+	setRefreshCookie(c, token)
+	return nil
+}
+
+// EndUserSession은 로그아웃 처리로 리프레시 토큰을 폐기하고 세션을 지웁니다
+func EndUserSession(c *fiber.Ctx, sess *session.Session) error {
+	if token := c.Cookies(RefreshCookieName); token != "" {
+		if err := database.RevokeRefreshToken(database.GetDB(), token); err != nil {
+			log.Printf("⚠️ 리프레시 토큰 폐기 실패: %v", err)
+		}
+		clearRefreshCookie(c)
+	}
+	return sess.Destroy()
+}
+
+// saveUserSession은 새 세션 ID로 사용자 정보를 저장하고 postgres 저장소이면 세션에 사용자를 기록합니다
+func saveUserSession(sess *session.Session, userID, orgID, username, role string) error {
+	if err := sess.Regenerate(); err != nil {
+		return err
+	}
+	sess.Set("user_id", userID)
+	sess.Set("org_id", orgID)
+	sess.Set("home_org_id", orgID)
+	sess.Set("username", username)
+	sess.Set("role", role)
+	sess.Set("authenticated", true)
+	sess.Set("last_seen", time.Now().Unix())
+
+	id := sess.ID()
+	if err := sess.Save(); err != nil {
+		return err
+	}
+	if sessions.storage != nil {
+		if err := database.BindSessionUser(database.GetDB(), id, userID); err != nil {
+			log.Printf("⚠️ 세션 사용자 기록 실패 (%s): %v", username, err)
+		}
+	}
+	return nil
+}
+
+// restoreSession은 세션이 만료된 요청을 리프레시 토큰으로 다시 로그인시킵니다.
+// 토큰은 쓸 때마다 새 토큰으로 바뀌고 유효 기간이 다시 시작됩니다 (슬라이딩 만료).
+func restoreSession(c *fiber.Ctx, sess *session.Session) bool {
+	token := c.Cookies(RefreshCookieName)
+	if token == "" || sessions.refreshTTL <= 0 {
+		return false
+	}
+
+	next, identity, err := database.RotateRefreshToken(database.GetDB(), token, sessions.refreshTTL)
+	if err != nil {
+		if !errors.Is(err, database.ErrInvalidRefreshToken) {
+			log.Printf("⚠️ 리프레시 토큰 확인 실패: %v", err)
+		}
+		clearRefreshCookie(c)
+		return false
+	}
+	setRefreshCookie(c, next)
+
+	if err := saveUserSession(sess, identity.UserID, identity.OrgID, identity.Username, identity.Role); err != nil {
+		log.Printf("⚠️ 세션 복원 실패 (%s): %v", identity.Username, err)
+		return false
+	}
+	return true
+}
+
+// touchSession은 마지막 저장 후 sessionTouchInterval이 지난 세션을 다시 저장해 만료를 늦춥니다
+func touchSession(sess *session.Session) {
+	lastSeen, _ := sess.Get("last_seen").(int64)
+	if time.Since(time.Unix(lastSeen, 0)) < sessionTouchInterval {
+		return
+	}
+	sess.Set("last_seen", time.Now().Unix())
+	if err := sess.Save(); err != nil {
+		log.Printf("⚠️ 세션 만료 연장 실패: %v", err)
+	}
+}
+
+// setRefreshCookie는 리프레시 토큰 쿠키를 설정합니다 (자바스크립트에서 읽을 수 없음)
+func setRefreshCookie(c *fiber.Ctx, token string) {
+	c.Cookie(&fiber.Cookie{
+		Name:     RefreshCookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  time.Now().Add(sessions.refreshTTL),
+		Secure:   c.Protocol() == "https",
+		HTTPOnly: true,
+		SameSite: "Lax",
+	})
+}
+
+// clearRefreshCookie는 리프레시 토큰 쿠키를 지웁니다
+func clearRefreshCookie(c *fiber.Ctx) {
+	c.Cookie(&fiber.Cookie{
+		Name:     RefreshCookieName,
+		Value:    "",
+		Path:     "/",
+		Expires:  time.Unix(0, 0),
+		HTTPOnly: true,
+		SameSite: "Lax",
+	})
+}
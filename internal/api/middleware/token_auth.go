@@ -1,22 +1,24 @@
 package middleware
 
 import (
+	"errors"
+	"log"
 	"strings"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/tmidb/tmidb-core/internal/database"
 )
 
-// TokenClaims는 토큰에 포함된 정보를 나타냅니다
+// TokenClaims는 인증된 토큰의 정보를 나타냅니다
 type TokenClaims struct {
-	UserID     int      `json:"user_id"`
-	OrgID      int      `json:"org_id"`
-	Username   string   `json:"username"`
-	Role       string   `json:"role"`       // "admin", "viewer"
-	TokenType  string   `json:"token_type"` // "permanent", "temporary"
-	Categories []string `json:"categories"` // 접근 가능한 카테고리 목록
-	ExpiresAt  int64    `json:"expires_at"`
+	TokenID     string                    `json:"token_id"`
+	Kind        string                    `json:"kind"`    // "api", "user"
+	UserID      string                    `json:"user_id"` // 사용자 토큰일 때만
+	OrgID       string                    `json:"org_id"`
+	Username    string                    `json:"username"`
+	Role        string                    `json:"role"` // "admin", "editor", "viewer", "api"
+	Permissions database.TokenPermissions `json:"permissions"`
+	ExpiresAt   int64                     `json:"expires_at"`
 }
 
 // CategoryPermissionFunc는 카테고리 권한을 확인하는 함수 타입입니다
@@ -34,9 +36,9 @@ func TokenAuthMiddleware(permission string, categoryFunc CategoryPermissionFunc)
 			})
 		}
 
-		// Bearer 토큰 형식 확인
+		// Bearer 토큰 형식 확인 (InfluxDB 클라이언트가 보내는 "Token <token>"도 허용)
 		tokenParts := strings.Split(authHeader, " ")
-		if len(tokenParts) != 2 || strings.ToLower(tokenParts[0]) != "bearer" {
+		if len(tokenParts) != 2 || (strings.ToLower(tokenParts[0]) != "bearer" && strings.ToLower(tokenParts[0]) != "token") {
 			return c.Status(401).JSON(fiber.Map{
 				"error": "Invalid authorization format. Use: Bearer <token>",
 				"code":  "AUTH_FORMAT_INVALID",
@@ -45,51 +47,50 @@ func TokenAuthMiddleware(permission string, categoryFunc CategoryPermissionFunc)
 
 		token := tokenParts[1]
 
-		// 토큰 검증
-		claims, err := validateToken(token)
-		if err != nil {
-			return c.Status(401).JSON(fiber.Map{
-				"error":   "Invalid or expired token",
-				"code":    "AUTH_TOKEN_INVALID",
-				"details": err.Error(),
-			})
-		}
-
-		// 토큰 만료 확인
-		if claims.ExpiresAt > 0 && time.Now().Unix() > claims.ExpiresAt {
-			return c.Status(401).JSON(fiber.Map{
-				"error": "Token has expired",
-				"code":  "AUTH_TOKEN_EXPIRED",
-			})
-		}
-
-		// 카테고리별 권한 확인 (필요한 경우)
-		if categoryFunc != nil {
-			category := categoryFunc(c)
-			if category != "" && !hasCategoryAccess(claims, category) {
-				return c.Status(403).JSON(fiber.Map{
-					"error": "Access denied to category: " + category,
-					"code":  "AUTH_CATEGORY_DENIED",
+		// 같은 요청에서 이미 인증했으면 (그룹 미들웨어 등) 다시 조회하지 않음
+		claims, ok := c.Locals("token_claims").(*TokenClaims)
+		if !ok {
+			var err error
+			claims, err = validateToken(token)
+			switch {
+			case errors.Is(err, database.ErrTokenExpired):
+				return c.Status(401).JSON(fiber.Map{
+					"error": "Token has expired",
+					"code":  "AUTH_TOKEN_EXPIRED",
+				})
+			case errors.Is(err, database.ErrTokenNotFound), errors.Is(err, database.ErrTokenInactive):
+				return c.Status(401).JSON(fiber.Map{
+					"error":   "Invalid or expired token",
+					"code":    "AUTH_TOKEN_INVALID",
+					"details": err.Error(),
+				})
+			case err != nil:
+				log.Printf("❌ Token verification failed: %v", err)
+				return c.Status(500).JSON(fiber.Map{
+					"error": "Failed to verify token",
+					"code":  "AUTH_ERROR",
 				})
 			}
+			// 요청마다 한 번만 셈 (권한이 없어 거부된 요청도 포함)
+			usageRecorder.AddRequests(claims.OrgID, 1)
 		}
 
-		// 권한 레벨 확인
-		if !hasPermission(claims, permission) {
-			return c.Status(403).JSON(fiber.Map{
-				"error":     "Insufficient permissions",
-				"code":      "AUTH_PERMISSION_DENIED",
-				"required":  permission,
-				"user_role": claims.Role,
-			})
+		// 권한 확인 (카테고리가 있으면 해당 카테고리 기준)
+		var category string
+		if categoryFunc != nil {
+			category = categoryFunc(c)
+		}
+		if !claims.Permissions.Allows(permission, category) {
+			return permissionDenied(c, claims, permission, category)
 		}
 
-		// 컨텍스트에 사용자 정보 저장
+		// 컨텍스트에 토큰 정보 저장
+		c.Locals("token_claims", claims)
 		c.Locals("user_id", claims.UserID)
 		c.Locals("org_id", claims.OrgID)
 		c.Locals("username", claims.Username)
 		c.Locals("user_role", claims.Role)
-		c.Locals("token_categories", claims.Categories)
+		c.Locals("token_permissions", claims.Permissions)
 
 		return c.Next()
 	}
@@ -97,101 +98,104 @@ func TokenAuthMiddleware(permission string, categoryFunc CategoryPermissionFunc)
 
 // validateToken은 토큰을 검증하고 클레임을 반환합니다
 func validateToken(token string) (*TokenClaims, error) {
-	// 데이터베이스에서 토큰 정보 조회
-	db := database.GetDB()
-
-	var claims TokenClaims
-	var isActive bool
-	var expiresAt *time.Time
-
-	query := `
-		SELECT 
-			u.id, u.org_id, u.username, u.role,
-			t.token_type, t.categories, t.expires_at, t.is_active
-		FROM auth_tokens t
-		JOIN users u ON t.user_id = u.id
-		WHERE t.token_hash = $1
-	`
-
-	err := db.QueryRow(query, hashToken(token)).Scan(
-		&claims.UserID, &claims.OrgID, &claims.Username, &claims.Role,
-		&claims.TokenType, &claims.Categories, &expiresAt, &isActive,
-	)
-
+	identity, err := database.AuthenticateToken(token)
 	if err != nil {
 		return nil, err
 	}
 
-	if !isActive {
-		return nil, fiber.NewError(401, "Token has been disabled")
+	claims := &TokenClaims{
+		TokenID:     identity.TokenID,
+		Kind:        identity.Kind,
+		UserID:      identity.UserID,
+		OrgID:       identity.OrgID,
+		Username:    identity.Username,
+		Role:        identity.Role,
+		Permissions: identity.Permissions,
 	}
-
-	if expiresAt != nil {
-		claims.ExpiresAt = expiresAt.Unix()
+	if identity.ExpiresAt != nil {
+		claims.ExpiresAt = identity.ExpiresAt.Unix()
 	}
-
-	return &claims, nil
+	return claims, nil
 }
 
-// hasPermission은 사용자가 필요한 권한을 가지고 있는지 확인합니다
-func hasPermission(claims *TokenClaims, permission string) bool {
-	switch permission {
-	case "read":
-		return claims.Role == "admin" || claims.Role == "viewer"
-	case "write":
-		return claims.Role == "admin" || claims.Role == "writer"
-	case "admin":
-		return claims.Role == "admin"
-	default:
-		return false
+// permissionDenied는 빠진 권한을 알려주는 403 응답을 보냅니다
+func permissionDenied(c *fiber.Ctx, claims *TokenClaims, permission, category string) error {
+	missing := database.PermissionName(permission, category)
+	body := fiber.Map{
+		"error":    "Missing permission: " + missing,
+		"code":     "AUTH_PERMISSION_DENIED",
+		"required": permission,
+		"missing":  missing,
 	}
-}
-
-// hasCategoryAccess는 특정 카테고리에 대한 접근 권한을 확인합니다
-func hasCategoryAccess(claims *TokenClaims, category string) bool {
-	// 관리자는 모든 카테고리 접근 가능
-	if claims.Role == "admin" {
-		return true
+	if category != "" {
+		body["code"] = "AUTH_CATEGORY_DENIED"
+		body["category"] = category
 	}
-
-	// 카테고리 제한이 없으면 모든 카테고리 접근 가능
-	if len(claims.Categories) == 0 {
-		return true
+	if claims != nil {
+		body["user_role"] = claims.Role
 	}
+	return c.Status(403).JSON(body)
+}
+
+// isInstallationAdmin은 사용자가 설치 관리자인지 확인합니다 (테스트에서 DB 없이 바꿀 수 있도록 변수)
+var isInstallationAdmin = func(userID string) (bool, error) {
+	return database.IsInstallationAdmin(database.GetDB(), userID)
+}
 
-	// 특정 카테고리 접근 권한 확인
-	for _, allowedCategory := range claims.Categories {
-		if allowedCategory == category || allowedCategory == "*" {
-			return true
+// TokenInstallationAdminRequired는 설치 관리자(초기 설정에서 만든 관리자)의 사용자 토큰만 통과시킵니다.
+// 조직의 admin 역할이나 관리자 API 토큰은 자기 조직만 다루므로, 모든 조직의 데이터에 닿는 경로는
+// TokenAuthRequired 뒤에 이것을 둡니다.
+func TokenInstallationAdminRequired() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims := GetTokenClaims(c)
+		allowed := false
+		if claims != nil && claims.Kind == "user" && claims.UserID != "" {
+			var err error
+			if allowed, err = isInstallationAdmin(claims.UserID); err != nil {
+				log.Printf("❌ Installation admin check failed: %v", err)
+				return c.Status(500).JSON(fiber.Map{
+					"error": "Failed to verify token",
+					"code":  "AUTH_ERROR",
+				})
+			}
+		}
+		if !allowed {
+			body := fiber.Map{
+				"error": "Installation admin access token required",
+				"code":  "AUTH_INSTALLATION_ADMIN_REQUIRED",
+			}
+			if claims != nil {
+				body["user_role"] = claims.Role
+			}
+			return c.Status(403).JSON(body)
 		}
+		return c.Next()
 	}
+}
 
-	return false
+// CategoryAllowed는 인증된 토큰이 카테고리에 대한 권한(read, write)을 가지고 있는지 확인합니다.
+// 리스너처럼 요청 경로가 아니라 데이터에서 카테고리를 알게 되는 핸들러에서 사용합니다.
+func CategoryAllowed(c *fiber.Ctx, permission, category string) bool {
+	claims := GetTokenClaims(c)
+	return claims != nil && claims.Permissions.Allows(permission, category)
 }
 
-// hashToken은 토큰을 해싱합니다 (보안을 위해)
-func hashToken(token string) string {
-	// TODO: 실제 환경에서는 강력한 해싱 알고리즘 사용
-	// 여기서는 기존 crypto 패키지 활용
-	return database.HashPassword(token) // 기존 함수 재활용
+// PermissionDenied는 카테고리 권한이 없는 요청에 403 응답을 보냅니다
+func PermissionDenied(c *fiber.Ctx, permission, category string) error {
+	return permissionDenied(c, GetTokenClaims(c), permission, category)
 }
 
-// GetTokenClaims는 컨텍스트에서 토큰 클레임 정보를 가져옵니다
+// GetTokenClaims는 컨텍스트에서 토큰 클레임 정보를 가져옵니다 (인증되지 않은 요청이면 nil)
 func GetTokenClaims(c *fiber.Ctx) *TokenClaims {
-	return &TokenClaims{
-		UserID:     c.Locals("user_id").(int),
-		OrgID:      c.Locals("org_id").(int),
-		Username:   c.Locals("username").(string),
-		Role:       c.Locals("user_role").(string),
-		Categories: c.Locals("token_categories").([]string),
-	}
+	claims, _ := c.Locals("token_claims").(*TokenClaims)
+	return claims
 }
 
 // GetOrgIDFromToken은 토큰에서 조직 ID를 가져옵니다 (기존 미들웨어와 호환성)
-func GetOrgIDFromToken(c *fiber.Ctx) (int, error) {
-	orgID := c.Locals("org_id")
-	if orgID == nil {
-		return 0, fiber.NewError(401, "Organization ID not found in token")
+func GetOrgIDFromToken(c *fiber.Ctx) (string, error) {
+	orgID, _ := c.Locals("org_id").(string)
+	if orgID == "" {
+		return "", fiber.NewError(401, "Organization ID not found in token")
 	}
-	return orgID.(int), nil
+	return orgID, nil
 }
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// fakeInstallationAdmin은 설치 관리자 확인을 DB 없이 바꿉니다 (user-1만 설치 관리자)
+func fakeInstallationAdmin(t *testing.T) {
+	t.Helper()
+	saved := isInstallationAdmin
+	isInstallationAdmin = func(userID string) (bool, error) { return userID == "user-1", nil }
+	t.Cleanup(func() { isInstallationAdmin = saved })
+}
+
+func TestTokenInstallationAdminRequired(t *testing.T) {
+	fakeInstallationAdmin(t)
+	tests := []struct {
+		name   string
+		claims *TokenClaims
+		status int
+	}{
+		{"installation admin", &TokenClaims{Kind: "user", UserID: "user-1", OrgID: "org-1", Role: "admin"}, 200},
+		{"admin of a second organization", &TokenClaims{Kind: "user", UserID: "user-2", OrgID: "org-2", Role: "admin"}, 403},
+		{"organization admin api token", &TokenClaims{Kind: "api", OrgID: "org-1", Role: "admin"}, 403},
+		{"unauthenticated", nil, 403},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				if tt.claims != nil {
+					c.Locals("token_claims", tt.claims)
+				}
+				return c.Next()
+			}, TokenInstallationAdminRequired(), func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package middleware
+
+import (
+	"github.com/tmidb/tmidb-core/internal/usage"
+)
+
+// usageRecorder 조직별 사용량 기록 (InitUsageAccounting 전에는 기록하지 않음)
+var usageRecorder *usage.Recorder
+
+// InitUsageAccounting은 토큰 인증 요청 수와 수집량을 기록할 Recorder를 설정합니다
+func InitUsageAccounting(recorder *usage.Recorder) {
+	usageRecorder = recorder
+}
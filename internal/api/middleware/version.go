@@ -26,6 +26,10 @@ type PaginationContext struct {
 	PageSize       int  `json:"page_size"`
 	AutoPagination bool `json:"auto_pagination"` // 자동 페이징 적용 여부
 	MaxPageSize    int  `json:"max_page_size"`   // 최대 페이지 크기
+
+	// 커서(keyset) 페이징: cursor 파라미터가 있으면 (첫 페이지는 빈 값) page 대신 커서로 이어서 조회
+	UseCursor bool   `json:"use_cursor"`
+	Cursor    string `json:"cursor,omitempty"`
 }
 
 // VersionMiddleware는 API 버전 처리를 담당합니다
@@ -98,6 +102,12 @@ func PaginationMiddleware() fiber.Handler {
 			}
 		}
 
+		// cursor 파라미터 확인 (빈 값이면 커서 모드의 첫 페이지)
+		if c.Context().QueryArgs().Has("cursor") {
+			paginationCtx.UseCursor = true
+			paginationCtx.Cursor = c.Query("cursor")
+		}
+
 		// auto_size 파라미터 확인
 		if autoSizeStr := c.Query("auto_size"); autoSizeStr == "true" {
 			paginationCtx.AutoPagination = true
@@ -186,7 +196,7 @@ func shouldEnableAutoPagination(c *fiber.Ctx, category string) bool {
 	query := `
 		SELECT COUNT(*) 
 		FROM target_categories 
-		WHERE org_id = $1 AND category_name = $2
+		WHERE org_id = $1 AND category_name = $2 AND deleted_at IS NULL
 	`
 
 	err = db.QueryRow(query, orgID, category).Scan(&approxCount)
@@ -212,7 +222,7 @@ func ValidateVersionAccess(c *fiber.Ctx, category string, requestedVersion strin
 	query := `
 		SELECT DISTINCT schema_version::text 
 		FROM target_categories 
-		WHERE org_id = $1 AND category_name = $2
+		WHERE org_id = $1 AND category_name = $2 AND deleted_at IS NULL
 		ORDER BY schema_version::int DESC
 	`
 
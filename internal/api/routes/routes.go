@@ -20,6 +20,7 @@ func SetupRoutes(app *fiber.App, sessionStore *session.Store) {
 	
 	// API 라우팅
 	api := app.Group("/api")
+	api.Use(middleware.IPRateLimit())
 	
 	// 관리 API (JSON, 세션/토큰 기반)
 	setupManagementAPIRoutes(api, sessionStore)
@@ -65,11 +66,14 @@ func setupBasicRoutes(app *fiber.App, sessionStore *session.Store) {
 	app.Get("/login", handlers.LoginPage)
 	app.Post("/login", handlers.LoginProcess)
 	app.Post("/logout", handlers.Logout)
+	app.Get("/auth/oidc/login", handlers.OIDCLogin)
+	app.Get("/auth/oidc/callback", handlers.OIDCCallback)
 	
 	// 초기 설정
 	app.Get("/setup", handlers.SetupPage)
 	app.Post("/setup", handlers.SetupProcess)
 	app.Get("/api/setup/status", handlers.SetupStatus)
+	app.Post("/api/setup", handlers.InitialSetupAPI)
 }
 
 // setupWebConsoleRoutes는 웹 콘솔 페이지 라우팅을 설정합니다
@@ -117,28 +121,52 @@ func setupManagementAPIRoutes(api fiber.Router, sessionStore *session.Store) {
 	mgmt.Put("/categories/:name", handlers.UpdateCategoryAPI)
 	mgmt.Delete("/categories/:name", handlers.DeleteCategoryAPI)
 	mgmt.Get("/categories/:name/schema", handlers.GetCategorySchemaAPI)
+	mgmt.Get("/categories/:name/diff", handlers.DiffCategorySchemaAPI)
 	
 	// 리스너 관리
 	mgmt.Get("/listeners", handlers.GetListenersAPI)
 	mgmt.Post("/listeners", handlers.CreateListenerAPI)
+	mgmt.Get("/listeners/:id", handlers.GetListenerAPI)
+	mgmt.Put("/listeners/:id", handlers.UpdateListenerAPI)
 	mgmt.Delete("/listeners/:id", handlers.DeleteListenerAPI)
 	
+	// 세션의 작업 조직 (전환은 설치 관리자만)
+	mgmt.Get("/session/org", handlers.GetSessionOrgAPI)
+	mgmt.Post("/session/org", middleware.InstallationAdminRequired(sessionStore), handlers.SwitchSessionOrgAPI)
+	
 	// 사용자 관리 (관리자만)
 	mgmtAdmin := mgmt.Group("/", middleware.AdminRequired(sessionStore))
 	mgmtAdmin.Get("/users", handlers.GetUsersAPI)
 	mgmtAdmin.Post("/users", handlers.CreateUserAPI)
 	mgmtAdmin.Put("/users/:id", handlers.UpdateUserAPI)
 	mgmtAdmin.Delete("/users/:id", handlers.DeleteUserAPI)
+	mgmtAdmin.Post("/users/:id/sessions/revoke", handlers.RevokeUserSessionsAPI)
+	
+	// 조직 관리 (설치 관리자만, 조직의 관리자는 다른 조직을 보거나 지우지 못함)
+	mgmt.Get("/organizations", middleware.InstallationAdminRequired(sessionStore), handlers.GetOrganizationsAPI)
+	mgmt.Post("/organizations", middleware.InstallationAdminRequired(sessionStore), handlers.CreateOrganizationAPI)
+	mgmt.Delete("/organizations/:id", middleware.InstallationAdminRequired(sessionStore), handlers.DeleteOrganizationAPI)
 	
 	// 토큰 관리
 	mgmtAdmin.Get("/tokens", handlers.GetAuthTokensAPI)
 	mgmtAdmin.Post("/tokens", handlers.CreateAuthTokenAPI)
 	mgmtAdmin.Delete("/tokens/:id", handlers.DeleteAuthTokenAPI)
+	mgmtAdmin.Get("/tokens/audit", handlers.GetTokenAuditLogAPI)
+	mgmtAdmin.Post("/tokens/:id/rotate", handlers.RotateAuthTokenAPI)
+	mgmtAdmin.Put("/tokens/:id/expiry", handlers.SetAuthTokenExpiryAPI)
+	mgmtAdmin.Post("/tokens/:id/revoke", handlers.RevokeAuthTokenAPI)
+
+	// 레이트 리밋과 수집 할당량 사용량
+	mgmtAdmin.Get("/limits", handlers.GetRateLimitsAPI)
 	
 	// 마이그레이션 관리
 	mgmtAdmin.Get("/migrations", handlers.GetMigrationsAPI)
 	mgmtAdmin.Post("/migrations", handlers.CreateMigrationAPI)
+	mgmtAdmin.Post("/migrations/category/plan", handlers.PlanCategoryMigrationAPI)
+	mgmtAdmin.Post("/migrations/category/preview", handlers.PreviewCategoryMigrationAPI)
+	mgmtAdmin.Post("/migrations/rollback", handlers.RollbackLatestMigrationAPI)
 	mgmtAdmin.Post("/migrations/:id/execute", handlers.ExecuteMigrationAPI)
+	mgmtAdmin.Post("/migrations/:id/rollback", handlers.RollbackMigrationAPI)
 	mgmtAdmin.Get("/migrations/:id/status", handlers.GetMigrationStatusAPI)
 }
 
@@ -147,7 +175,58 @@ func setupDataAPIRoutes(api fiber.Router) {
 	// 헬스체크 (인증 불필요)
 	api.Get("/health", handlers.HealthCheck)
 	api.Get("/system/info", handlers.SystemInfo)
-	
+
+	// 클러스터 상태 (버전 그룹보다 먼저 등록하여 카테고리 미들웨어를 거치지 않음)
+	api.Get("/v1/cluster", middleware.TokenAuthRequired("read", nil), handlers.GetClusterStatus)
+
+	// 조직 사용량 보고 (관리자 토큰, 토큰의 조직만)
+	api.Get("/v1/admin/usage", middleware.TokenAuthRequired("admin", nil), middleware.TokenRateLimit(), handlers.GetUsageReport)
+
+	// 느린 쿼리 기록 (관리자 토큰, 설치 전체의 쿼리)
+	api.Get("/v1/admin/slow-queries", middleware.TokenAuthRequired("admin", nil), middleware.TokenRateLimit(), handlers.GetSlowQueries)
+	api.Delete("/v1/admin/slow-queries", middleware.TokenAuthRequired("admin", nil), middleware.TokenRateLimit(), handlers.ResetSlowQueries)
+
+	// SQL 콘솔 (SQL_CONSOLE_ENABLED일 때만, 설치 관리자의 사용자 토큰만. API의 DB 사용자로 모든 조직의 테이블과
+	// users, auth_tokens, sessions 같은 인증 테이블까지 읽으므로 조직 관리자 API 토큰은 거부, 모든 문을 기록)
+	if handlers.SQLConsoleEnabled() {
+		api.Post("/v1/admin/sql", middleware.TokenAuthRequired("admin", nil), middleware.TokenInstallationAdminRequired(), middleware.TokenRateLimit(), handlers.ExecuteConsoleSQL)
+		api.Get("/v1/admin/sql/log", middleware.TokenAuthRequired("admin", nil), middleware.TokenInstallationAdminRequired(), middleware.TokenRateLimit(), handlers.GetSQLConsoleLog)
+	}
+
+	// InfluxDB line protocol 쓰기 (v2 경로는 Telegraf influxdb_v2 출력용, 카테고리 권한은 핸들러에서 확인)
+	for _, path := range []string{"/v1/write", "/v2/write"} {
+		api.Post(path, middleware.TokenAuthRequired("write", nil), middleware.TokenRateLimit(), middleware.IngestQuota(), handlers.WriteLineProtocol)
+	}
+
+	// 여러 타겟/카테고리 쓰기를 한 트랜잭션으로 (모두 적용하거나 아무것도 적용하지 않음, 카테고리 권한은 핸들러에서 확인)
+	api.Post("/v1/transactions", middleware.TokenAuthRequired("write", nil), middleware.TokenRateLimit(), middleware.IngestQuota(), handlers.ExecuteTransaction)
+
+	// Grafana JSON 데이터소스 (simpod JSON, SimpleJSON 호환, 카테고리 권한은 핸들러에서 확인)
+	grafana := api.Group("/grafana", middleware.TokenAuthRequired("read", nil), middleware.TokenRateLimit())
+	grafana.Get("/", handlers.GrafanaHealth)
+	grafana.Post("/metrics", handlers.GrafanaMetrics)
+	grafana.Post("/search", handlers.GrafanaSearch)
+	grafana.Post("/metric-payload-options", handlers.GrafanaMetricPayloadOptions)
+	grafana.Post("/variable", handlers.GrafanaVariable)
+	grafana.Post("/query", handlers.GrafanaQuery)
+
+	// 조직 webhook과 전송 기록 (관리자 토큰, 토큰의 조직만)
+	hooks := api.Group("/v1/admin/webhooks", middleware.TokenAuthRequired("admin", nil), middleware.TokenRateLimit())
+	hooks.Get("/", handlers.ListWebhooks)
+	hooks.Post("/", handlers.CreateWebhook)
+	hooks.Get("/:webhook_id", handlers.GetWebhook)
+	hooks.Put("/:webhook_id", handlers.UpdateWebhook)
+	hooks.Delete("/:webhook_id", handlers.DeleteWebhook)
+	hooks.Get("/:webhook_id/deliveries", handlers.GetWebhookDeliveries)
+	hooks.Post("/:webhook_id/deliveries/:delivery_id/redeliver", handlers.RedeliverWebhook)
+
+	// GraphQL (선택 사항, 카테고리 권한은 리졸버에서 확인)
+	if handlers.GraphQLEnabled() {
+		api.Get("/graphql/schema", handlers.GraphQLSchema)
+		api.Get("/graphql", middleware.TokenAuthRequired("read", nil), middleware.TokenRateLimit(), handlers.GraphQL)
+		api.Post("/graphql", middleware.TokenAuthRequired("read", nil), middleware.TokenRateLimit(), handlers.GraphQL)
+	}
+
 	// 버전별 API 그룹
 	setupVersionedRoutes(api, "v1")
 	setupVersionedRoutes(api, "v2") 
@@ -161,35 +240,94 @@ func setupVersionedRoutes(api fiber.Router, version string) {
 	v.Use(middleware.VersionMiddleware(version))
 	v.Use(middleware.AutoPaginationMiddleware())
 	v.Use(middleware.TokenAuthRequired("read", handlers.CategoryFromParams))
+	v.Use(middleware.TokenRateLimit())
 	
 	// 카테고리 데이터 API
 	v.Get("/category/:category", handlers.GetCategoryData)
 	v.Get("/category/:category/schema", handlers.GetCategorySchema)
+	v.Post("/categories/:category/validate", handlers.ValidateCategoryDocuments) // 저장하지 않고 스키마 검증만
+
+	// 웹 콘솔 데이터 브라우저 (테이블별 필터, 정렬, 커서 페이징, 카테고리 권한은 핸들러에서 확인)
+	v.Get("/browse", handlers.ListBrowseTables)
+	v.Post("/browse", handlers.Browse)
 	
 	// 타겟 데이터 API  
 	v.Get("/targets/:target_id/categories/:category", handlers.GetTargetByID)
 	v.Post("/targets/:target_id/categories/:category", 
 		middleware.TokenAuthRequired("write", handlers.CategoryFromParams),
+		middleware.IngestQuota(),
 		handlers.CreateOrUpdateTargetData)
 	v.Delete("/targets/:target_id/categories/:category",
 		middleware.TokenAuthRequired("write", handlers.CategoryFromParams), 
 		handlers.DeleteTargetData)
+
+	// 카테고리 문서 이력 (리비전 목록, 특정 시점의 문서, 리비전 간 차이)
+	v.Get("/targets/:target_id/categories/:category/history", handlers.GetDocumentHistory)
+	v.Get("/targets/:target_id/categories/:category/history/:revision", handlers.GetDocumentRevision)
+	v.Get("/targets/:target_id/categories/:category/as-of", handlers.GetDocumentAsOf)
+	v.Get("/targets/:target_id/categories/:category/diff", handlers.DiffDocumentRevisions)
+	
+	// 타겟 보관/병합/대량 삭제 (관리자 토큰, 확인 토큰으로 두 번 요청)
+	v.Get("/targets/audit", middleware.TokenAuthRequired("admin", nil), handlers.GetTargetAuditLog)
+	v.Post("/targets/merge", middleware.TokenAuthRequired("admin", nil), handlers.MergeTargets)
+	v.Post("/targets/bulk-delete", middleware.TokenAuthRequired("admin", nil), handlers.BulkDeleteTargets)
+	v.Post("/targets/:target_id/archive", middleware.TokenAuthRequired("admin", nil), handlers.ArchiveTarget)
+	v.Post("/targets/:target_id/unarchive", middleware.TokenAuthRequired("admin", nil), handlers.UnarchiveTarget)
+
+	// 휴지통 (SOFT_DELETE_RETENTION_DAYS가 있을 때 삭제한 타겟과 카테고리 문서, 관리자 토큰)
+	v.Get("/recycle-bin", middleware.TokenAuthRequired("admin", nil), handlers.ListRecycleBin)
+	v.Post("/recycle-bin/targets/:target_id/restore", middleware.TokenAuthRequired("admin", nil), handlers.RestoreTarget)
+	v.Post("/recycle-bin/targets/:target_id/categories/:category/restore",
+		middleware.TokenAuthRequired("admin", nil), handlers.RestoreTargetCategoryData)
 	
 	// 시계열 데이터 API
-	v.Get("/targets/:target_id/categories/:category/timeseries", handlers.GetTimeSeriesData)
+	v.Get("/targets/:target_id/categories/:category/timeseries", handlers.GetTargetTimeSeries)
 	v.Post("/targets/:target_id/categories/:category/timeseries",
 		middleware.TokenAuthRequired("write", handlers.CategoryFromParams),
+		middleware.IngestQuota(),
 		handlers.InsertTimeSeriesData)
 	
+	// 대량 수집 API (JSON 배열 또는 NDJSON)
+	v.Post("/data/:category/bulk",
+		middleware.TokenAuthRequired("write", handlers.CategoryFromParams),
+		middleware.IngestQuota(),
+		handlers.BulkIngestData)
+	
+	// 내보내기 API (CSV, NDJSON, Parquet 스트리밍 또는 SeaweedFS 비동기 작업)
+	v.Get("/export/jobs/:job_id", handlers.GetExportJob)
+	v.Get("/export/jobs/:job_id/download", handlers.DownloadExport)
+	v.Get("/export/:category",
+		middleware.TokenAuthRequired("read", handlers.CategoryFromParams),
+		handlers.ExportCategoryData)
+	
+	// 가져오기 API (CSV, NDJSON을 시계열로, 조각 업로드는 offset부터 이어 올릴 수 있음)
+	v.Post("/import/:category",
+		middleware.TokenAuthRequired("write", handlers.CategoryFromParams),
+		middleware.IngestQuota(),
+		handlers.CreateImportJob)
+	v.Get("/import/jobs/:job_id", handlers.GetImportJob)
+	v.Put("/import/jobs/:job_id/chunks", middleware.IngestQuota(), handlers.UploadImportChunk)
+	v.Post("/import/jobs/:job_id/complete", middleware.IngestQuota(), handlers.CompleteImportUpload)
+	
 	// 리스너 API
 	v.Get("/listener/:listener_id", handlers.GetSingleListenerData)
 	v.Get("/listener/*", handlers.GetMultiListenerData) // 다중 리스너 경로
 	
-	// 파일 관리 API (추후 구현)
+	// 파일 첨부 API (SeaweedFS, 토큰의 조직 범위로 제한)
+	v.Post("/targets/:target_id/files", middleware.TokenAuthMiddleware("write", nil), handlers.UploadFiles)
+	v.Get("/targets/:target_id/files", middleware.TokenAuthMiddleware("read", nil), handlers.ListFiles)
+	v.Get("/targets/:target_id/files/:file_id", middleware.TokenAuthMiddleware("read", nil), handlers.DownloadFile)
+	v.Delete("/targets/:target_id/files/:file_id", middleware.TokenAuthMiddleware("write", nil), handlers.DeleteFile)
 	v.Post("/targets/:target_id/categories/:category/files",
-		middleware.TokenAuthRequired("write", handlers.CategoryFromParams),
+		middleware.TokenAuthMiddleware("write", handlers.CategoryFromParams),
 		handlers.UploadFiles)
+	v.Get("/targets/:target_id/categories/:category/files",
+		middleware.TokenAuthMiddleware("read", handlers.CategoryFromParams),
+		handlers.ListFiles)
+	v.Get("/targets/:target_id/categories/:category/files/:file_id",
+		middleware.TokenAuthMiddleware("read", handlers.CategoryFromParams),
+		handlers.DownloadFile)
 	v.Delete("/targets/:target_id/categories/:category/files/:file_id",
-		middleware.TokenAuthRequired("write", handlers.CategoryFromParams),
+		middleware.TokenAuthMiddleware("write", handlers.CategoryFromParams),
 		handlers.DeleteFile)
 } 
\ No newline at end of file
@@ -0,0 +1,31 @@
+package busconsumer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/tmidb/tmidb-core/internal/database"
+)
+
+// ChangeSubjectPrefix 변경 이벤트 주제 접두사 (tmidb.cdc.<table>.<operation>)
+const ChangeSubjectPrefix = "tmidb.cdc"
+
+// ChangeSubject는 테이블 변경 이벤트의 발행 주제를 반환합니다
+func ChangeSubject(table, operation string) string {
+	return fmt.Sprintf("%s.%s.%s", ChangeSubjectPrefix, table, operation)
+}
+
+// NewChangeMsg는 변경 이벤트 메시지를 만듭니다.
+// Nats-Msg-Id에 이벤트 ID를 넣어 JetStream 스트림이 재발행을 중복 제거할 수 있게 합니다.
+func NewChangeMsg(event database.ChangeEvent) (*nats.Msg, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal change event: %w", err)
+	}
+
+	msg := nats.NewMsg(ChangeSubject(event.Table, event.Operation))
+	msg.Data = data
+	msg.Header.Set(nats.MsgIdHdr, fmt.Sprintf("cdc-%d", event.EventID))
+	return msg, nil
+}
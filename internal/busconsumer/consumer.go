@@ -2,6 +2,7 @@ package busconsumer
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -10,6 +11,8 @@ import (
 
 	"github.com/nats-io/nats.go"
 	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/logger"
+	"github.com/tmidb/tmidb-core/internal/schema"
 )
 
 // DataPoint 수집되는 데이터 포인트 구조체
@@ -88,6 +91,10 @@ func (bc *BaseConsumer) SaveToDatabase(dataPoint DataPoint) error {
 		return fmt.Errorf("database connection not available")
 	}
 
+	if err := bc.validatePayload(dataPoint); err != nil {
+		return err
+	}
+
 	dataJSON, err := json.Marshal(dataPoint.Data)
 	if err != nil {
 		return fmt.Errorf("failed to marshal data JSON: %w", err)
@@ -108,6 +115,27 @@ func (bc *BaseConsumer) SaveToDatabase(dataPoint DataPoint) error {
 	return nil
 }
 
+// validatePayload 타겟이 사용하는 카테고리 스키마로 페이로드를 검증합니다.
+// 연결된 스키마가 없으면 검증하지 않습니다.
+func (bc *BaseConsumer) validatePayload(dataPoint DataPoint) error {
+	definition, err := database.GetTargetCategorySchema(bc.DB, dataPoint.ID, dataPoint.Category)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load category schema: %w", err)
+	}
+
+	compiled, err := schema.Cached(definition)
+	if err != nil {
+		return fmt.Errorf("invalid %s schema: %w", dataPoint.Category, err)
+	}
+	if err := compiled.Validate(dataPoint.Data); err != nil {
+		return fmt.Errorf("payload rejected: %w", err)
+	}
+	return nil
+}
+
 // StartBatchProcessor 배치 처리를 시작합니다
 func (bc *BaseConsumer) StartBatchProcessor() {
 	ticker := time.NewTicker(5 * time.Minute)
@@ -181,6 +209,31 @@ func (bc *BaseConsumer) Cleanup() {
 	log.Println("✅ BaseConsumer cleanup completed")
 }
 
+// NewDataMsg는 요청 ID 헤더(X-Request-ID)를 포함한 데이터 메시지를 만듭니다.
+// 구독자는 MessageFields로 같은 요청 ID를 로그에 남깁니다.
+func NewDataMsg(dataPoint DataPoint, requestID string) (*nats.Msg, error) {
+	data, err := json.Marshal(dataPoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	msg := nats.NewMsg(fmt.Sprintf("tmidb.data.%s.%s", dataPoint.Source, dataPoint.Category))
+	msg.Data = data
+	if requestID != "" {
+		msg.Header.Set(logger.RequestIDHeader, requestID)
+	}
+	return msg, nil
+}
+
+// MessageFields는 수신한 데이터 메시지의 로그 필드(request_id, target_id)를 반환합니다
+func MessageFields(msg *nats.Msg, dataPoint DataPoint) []logger.Field {
+	fields := []logger.Field{logger.F(logger.FieldTargetID, dataPoint.ID)}
+	if msg != nil && msg.Header != nil {
+		fields = append(fields, logger.F(logger.FieldRequestID, msg.Header.Get(logger.RequestIDHeader)))
+	}
+	return fields
+}
+
 // NATS URL을 환경 변수 또는 기본값에서 가져옵니다.
 func getNatsURL() string {
 	if url := os.Getenv("NATS_URL"); url != "" {
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+)
+
+// Cache는 API 응답 캐시가 쓰는 저장소입니다.
+// MemoryCache는 인스턴스마다 따로 가지고, RedisCache는 여러 API 인스턴스가 함께 씁니다.
+// RedisCache의 Get은 저장한 값을 문자열로 돌려주므로 구조체는 SetJSON/GetJSON으로 다룹니다.
+type Cache interface {
+	Get(key string) (interface{}, bool)
+	Set(key string, value interface{}, ttl time.Duration)
+	GetString(key string) (string, bool)
+	GetJSON(key string, dest interface{}) bool
+	SetJSON(key string, value interface{}, ttl time.Duration) error
+	Delete(key string)
+	DeletePattern(pattern string) int
+	Clear()
+	Exists(key string) bool
+	Stats() CacheStats
+	InvalidateCategory(category string)
+	InvalidateTarget(targetID string)
+	Close()
+}
+
+// 캐시 백엔드
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+var (
+	_ Cache = (*MemoryCache)(nil)
+	_ Cache = (*RedisCache)(nil)
+)
+
+// categoryPatterns는 카테고리가 바뀌면 지울 키 패턴입니다
+func categoryPatterns(category string) []string {
+	return []string{
+		fmt.Sprintf("category:%s:*", category),
+		fmt.Sprintf("schema:%s", category),
+		fmt.Sprintf("targets:%s:*", category),
+		fmt.Sprintf("timeseries:%s:*", category),
+	}
+}
+
+// targetPatterns는 타겟이 바뀌면 지울 키 패턴입니다
+func targetPatterns(targetID string) []string {
+	return []string{
+		fmt.Sprintf("target:%s:*", targetID),
+		fmt.Sprintf("targets:*:%s", targetID),
+		fmt.Sprintf("timeseries:*:%s:*", targetID),
+	}
+}
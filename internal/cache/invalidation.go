@@ -0,0 +1,24 @@
+package cache
+
+// InvalidationSubject는 API 인스턴스끼리 캐시 무효화를 알리는 NATS 주제입니다
+const InvalidationSubject = "tmidb.cache.invalidate"
+
+// Invalidation은 다른 인스턴스에 보내는 캐시 무효화 메시지입니다
+type Invalidation struct {
+	Origin     string   `json:"origin"` // 보낸 인스턴스 ID (자기가 보낸 메시지는 무시)
+	Categories []string `json:"categories,omitempty"`
+	Targets    []string `json:"targets,omitempty"`
+}
+
+// Apply는 메시지의 카테고리와 타겟 캐시를 무효화합니다
+func (inv Invalidation) Apply(c Cache) {
+	if c == nil {
+		return
+	}
+	for _, category := range inv.Categories {
+		c.InvalidateCategory(category)
+	}
+	for _, targetID := range inv.Targets {
+		c.InvalidateTarget(targetID)
+	}
+}
@@ -129,6 +129,49 @@ func (c *MemoryCache) Get(key string) (interface{}, bool) {
 	return item.Value, true
 }
 
+// Update는 키의 값을 잠금 안에서 읽고 바꿉니다 (레이트 리밋 카운터처럼 경쟁 없이 갱신해야 할 때).
+// fn은 현재 값(없거나 만료되었으면 exists=false)을 받아 새 값과 저장 여부를 반환합니다.
+// 자주 호출되므로 Set과 달리 로그를 남기지 않습니다.
+func (c *MemoryCache) Update(key string, ttl time.Duration, fn func(current interface{}, exists bool) (interface{}, bool)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var current interface{}
+	item, exists := c.items[key]
+	if exists && item.isExpired() {
+		exists = false
+	}
+	if exists {
+		current = item.Value
+	}
+
+	value, store := fn(current, exists)
+	if !store {
+		return
+	}
+
+	now := time.Now()
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = now.Add(ttl)
+	} else if c.defaultTTL > 0 {
+		expiresAt = now.Add(c.defaultTTL)
+	}
+
+	if item == nil && len(c.items) >= c.maxSize {
+		c.evictOldest()
+	}
+
+	c.items[key] = &CacheItem{
+		Value:     value,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+		AccessAt:  now,
+	}
+	c.stats.Sets++
+	c.stats.Size = len(c.items)
+}
+
 // GetString은 문자열 값을 조회합니다
 func (c *MemoryCache) GetString(key string) (string, bool) {
 	value, exists := c.Get(key)
@@ -269,12 +312,7 @@ func (c *MemoryCache) Stats() CacheStats {
 
 // InvalidateCategory는 카테고리 관련 캐시를 무효화합니다
 func (c *MemoryCache) InvalidateCategory(category string) {
-	patterns := []string{
-		fmt.Sprintf("category:%s:*", category),
-		fmt.Sprintf("schema:%s", category),
-		fmt.Sprintf("targets:%s:*", category),
-		fmt.Sprintf("timeseries:%s:*", category),
-	}
+	patterns := categoryPatterns(category)
 
 	total := 0
 	for _, pattern := range patterns {
@@ -286,11 +324,7 @@ func (c *MemoryCache) InvalidateCategory(category string) {
 
 // InvalidateTarget은 타겟 관련 캐시를 무효화합니다
 func (c *MemoryCache) InvalidateTarget(targetID string) {
-	patterns := []string{
-		fmt.Sprintf("target:%s:*", targetID),
-		fmt.Sprintf("targets:*:%s", targetID),
-		fmt.Sprintf("timeseries:*:%s:*", targetID),
-	}
+	patterns := targetPatterns(targetID)
 
 	total := 0
 	for _, pattern := range patterns {
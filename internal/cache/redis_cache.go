@@ -0,0 +1,439 @@
+package cache
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Redis 연결 설정
+const (
+	redisDialTimeout = 2 * time.Second
+	redisIOTimeout   = 2 * time.Second
+	redisPoolSize    = 16
+	redisScanCount   = 500 // SCAN 한 번에 훑을 키 수
+)
+
+// RedisOptions는 Redis 호환 서버(Redis, Dragonfly, Valkey) 연결 정보입니다
+type RedisOptions struct {
+	Addr     string
+	Username string
+	Password string
+	DB       int
+	Prefix   string // 모든 키 앞에 붙임 (같은 서버를 다른 용도와 나눠 쓸 때)
+}
+
+// ParseRedisURL은 redis://[user:password@]host:port/db 형식의 주소를 읽습니다
+func ParseRedisURL(rawURL string) (RedisOptions, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return RedisOptions{}, fmt.Errorf("잘못된 Redis URL: %v", err)
+	}
+	if u.Scheme != "redis" {
+		return RedisOptions{}, fmt.Errorf("지원하지 않는 Redis URL 스킴: %s (redis만 지원)", u.Scheme)
+	}
+
+	opts := RedisOptions{Addr: u.Host}
+	if u.Port() == "" {
+		opts.Addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		opts.Password, _ = u.User.Password()
+		if opts.Password == "" {
+			// redis://:password@host 또는 redis://password@host
+			opts.Password = u.User.Username()
+		} else {
+			opts.Username = u.User.Username()
+		}
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if opts.DB, err = strconv.Atoi(db); err != nil {
+			return RedisOptions{}, fmt.Errorf("잘못된 Redis DB 번호: %s", db)
+		}
+	}
+	return opts, nil
+}
+
+// RedisCache는 여러 API 인스턴스가 함께 쓰는 Redis 호환 캐시입니다.
+// 값은 문자열로 저장하며, 문자열이 아닌 값은 JSON으로 바꿔 저장합니다.
+// 서버 오류는 캐시 미스로 처리하고 로그는 1분에 한 번만 남깁니다.
+type RedisCache struct {
+	opts       RedisOptions
+	defaultTTL time.Duration
+	pool       chan *redisConn
+	stats      struct {
+		hits, misses, sets, deletes atomic.Int64
+	}
+
+	errorMu     sync.Mutex
+	errorLogged time.Time
+}
+
+// NewRedisCache는 Redis 서버에 연결해 보고 캐시를 만듭니다
+func NewRedisCache(opts RedisOptions, defaultTTL time.Duration) (*RedisCache, error) {
+	c := &RedisCache{
+		opts:       opts,
+		defaultTTL: defaultTTL,
+		pool:       make(chan *redisConn, redisPoolSize),
+	}
+	if _, err := c.do("PING"); err != nil {
+		return nil, fmt.Errorf("Redis 연결 실패 (%s): %v", opts.Addr, err)
+	}
+	return c, nil
+}
+
+// Set은 캐시에 값을 저장합니다
+func (c *RedisCache) Set(key string, value interface{}, ttl time.Duration) {
+	var data string
+	switch v := value.(type) {
+	case string:
+		data = v
+	case []byte:
+		data = string(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			c.logError(fmt.Errorf("캐시 값 JSON 변환 실패 (%s): %v", key, err))
+			return
+		}
+		data = string(b)
+	}
+
+	args := []string{"SET", c.opts.Prefix + key, data}
+	if ttl <= 0 {
+		ttl = c.defaultTTL
+	}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	if _, err := c.do(args...); err != nil {
+		c.logError(err)
+		return
+	}
+	c.stats.sets.Add(1)
+}
+
+// Get은 캐시에서 값을 조회합니다 (값은 항상 문자열)
+func (c *RedisCache) Get(key string) (interface{}, bool) {
+	value, ok := c.GetString(key)
+	if !ok {
+		return nil, false
+	}
+	return value, true
+}
+
+// GetString은 문자열 값을 조회합니다
+func (c *RedisCache) GetString(key string) (string, bool) {
+	reply, err := c.do("GET", c.opts.Prefix+key)
+	if err != nil {
+		c.logError(err)
+	}
+	value, ok := reply.(string)
+	if err != nil || !ok {
+		c.stats.misses.Add(1)
+		return "", false
+	}
+	c.stats.hits.Add(1)
+	return value, true
+}
+
+// GetJSON은 JSON 문자열을 파싱하여 반환합니다
+func (c *RedisCache) GetJSON(key string, dest interface{}) bool {
+	value, ok := c.GetString(key)
+	return ok && json.Unmarshal([]byte(value), dest) == nil
+}
+
+// SetJSON은 값을 JSON 문자열로 저장합니다
+func (c *RedisCache) SetJSON(key string, value interface{}, ttl time.Duration) error {
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("JSON 마샬링 실패: %v", err)
+	}
+	c.Set(key, string(jsonBytes), ttl)
+	return nil
+}
+
+// Delete는 캐시에서 키를 삭제합니다
+func (c *RedisCache) Delete(key string) {
+	c.deleteKeys([]string{c.opts.Prefix + key})
+}
+
+// DeletePattern은 패턴에 맞는 모든 키를 삭제합니다 (*만 와일드카드로 봄)
+func (c *RedisCache) DeletePattern(pattern string) int {
+	match := escapeGlob(c.opts.Prefix) + escapeGlob(pattern)
+	deleted := 0
+	cursor := "0"
+	for {
+		reply, err := c.do("SCAN", cursor, "MATCH", match, "COUNT", strconv.Itoa(redisScanCount))
+		if err != nil {
+			c.logError(err)
+			return deleted
+		}
+		page, ok := reply.([]interface{})
+		if !ok || len(page) != 2 {
+			c.logError(fmt.Errorf("예상하지 못한 SCAN 응답: %v", reply))
+			return deleted
+		}
+		cursor, _ = page[0].(string)
+		keys, _ := page[1].([]interface{})
+		batch := make([]string, 0, len(keys))
+		for _, key := range keys {
+			if s, ok := key.(string); ok {
+				batch = append(batch, s)
+			}
+		}
+		deleted += c.deleteKeys(batch)
+		if cursor == "0" || cursor == "" {
+			return deleted
+		}
+	}
+}
+
+// Clear는 접두사 아래의 모든 캐시를 삭제합니다 (같은 DB의 다른 키는 건드리지 않음)
+func (c *RedisCache) Clear() {
+	count := c.DeletePattern("*")
+	log.Printf("전체 캐시 삭제: %d개", count)
+}
+
+// Exists는 키가 존재하는지 확인합니다
+func (c *RedisCache) Exists(key string) bool {
+	reply, err := c.do("EXISTS", c.opts.Prefix+key)
+	if err != nil {
+		c.logError(err)
+		return false
+	}
+	n, _ := reply.(int64)
+	return n > 0
+}
+
+// Stats는 이 인스턴스가 보낸 요청의 통계를 반환합니다 (크기와 제거 수는 서버가 관리하므로 0)
+func (c *RedisCache) Stats() CacheStats {
+	stats := CacheStats{
+		Hits:    c.stats.hits.Load(),
+		Misses:  c.stats.misses.Load(),
+		Sets:    c.stats.sets.Load(),
+		Deletes: c.stats.deletes.Load(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total) * 100
+	}
+	return stats
+}
+
+// InvalidateCategory는 카테고리 관련 캐시를 무효화합니다
+func (c *RedisCache) InvalidateCategory(category string) {
+	total := 0
+	for _, pattern := range categoryPatterns(category) {
+		total += c.DeletePattern(pattern)
+	}
+	log.Printf("카테고리 캐시 무효화: %s (%d개)", category, total)
+}
+
+// InvalidateTarget은 타겟 관련 캐시를 무효화합니다
+func (c *RedisCache) InvalidateTarget(targetID string) {
+	total := 0
+	for _, pattern := range targetPatterns(targetID) {
+		total += c.DeletePattern(pattern)
+	}
+	log.Printf("타겟 캐시 무효화: %s (%d개)", targetID, total)
+}
+
+// Close는 풀에 남은 연결을 닫습니다 (공유 캐시이므로 데이터는 지우지 않음)
+func (c *RedisCache) Close() {
+	for {
+		select {
+		case conn := <-c.pool:
+			conn.Close()
+		default:
+			log.Println("Redis 캐시 연결 종료됨")
+			return
+		}
+	}
+}
+
+// deleteKeys는 접두사가 붙은 키들을 지우고 지운 개수를 반환합니다
+func (c *RedisCache) deleteKeys(keys []string) int {
+	if len(keys) == 0 {
+		return 0
+	}
+	reply, err := c.do(append([]string{"DEL"}, keys...)...)
+	if err != nil {
+		c.logError(err)
+		return 0
+	}
+	n, _ := reply.(int64)
+	c.stats.deletes.Add(n)
+	return int(n)
+}
+
+// logError는 서버 오류를 1분에 한 번만 기록합니다 (서버가 내려가면 요청마다 실패하므로)
+func (c *RedisCache) logError(err error) {
+	c.errorMu.Lock()
+	defer c.errorMu.Unlock()
+	if time.Since(c.errorLogged) < time.Minute {
+		return
+	}
+	c.errorLogged = time.Now()
+	log.Printf("⚠️ Redis 캐시 오류 (캐시 없이 처리): %v", err)
+}
+
+// do는 풀에서 연결을 꺼내 명령을 보내고 응답을 받습니다.
+// 서버가 보낸 오류(-ERR)가 아니면 연결을 버립니다 (응답이 어디까지 읽혔는지 알 수 없으므로).
+func (c *RedisCache) do(args ...string) (interface{}, error) {
+	var conn *redisConn
+	select {
+	case conn = <-c.pool:
+	default:
+		var err error
+		if conn, err = dialRedis(c.opts); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := conn.do(args...)
+	var serverErr redisError
+	if err != nil && !errors.As(err, &serverErr) {
+		conn.Close()
+		return nil, err
+	}
+
+	select {
+	case c.pool <- conn:
+	default:
+		conn.Close()
+	}
+	return reply, err
+}
+
+// redisError는 서버가 보낸 오류 응답입니다
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// redisConn은 RESP 프로토콜로 명령을 주고받는 연결 하나입니다
+type redisConn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+// dialRedis는 서버에 연결하고 인증과 DB 선택을 마칩니다
+func dialRedis(opts RedisOptions) (*redisConn, error) {
+	nc, err := net.DialTimeout("tcp", opts.Addr, redisDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	conn := &redisConn{Conn: nc, r: bufio.NewReader(nc), w: bufio.NewWriter(nc)}
+
+	var setup [][]string
+	switch {
+	case opts.Username != "":
+		setup = append(setup, []string{"AUTH", opts.Username, opts.Password})
+	case opts.Password != "":
+		setup = append(setup, []string{"AUTH", opts.Password})
+	}
+	if opts.DB != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(opts.DB)})
+	}
+	for _, args := range setup {
+		if _, err := conn.do(args...); err != nil {
+			nc.Close()
+			return nil, fmt.Errorf("%s 실패: %v", args[0], err)
+		}
+	}
+	return conn, nil
+}
+
+func (c *redisConn) do(args ...string) (interface{}, error) {
+	c.SetDeadline(time.Now().Add(redisIOTimeout))
+	if err := writeCommand(c.w, args); err != nil {
+		return nil, err
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+	return readReply(c.r)
+}
+
+// writeCommand는 명령을 RESP 배열로 씁니다
+func writeCommand(w *bufio.Writer, args []string) error {
+	fmt.Fprintf(w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(w, "$%d\r\n", len(arg))
+		w.WriteString(arg)
+		if _, err := w.WriteString("\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readReply는 RESP 응답 하나를 읽습니다.
+// 문자열은 string, 정수는 int64, 배열은 []interface{}, null은 nil입니다.
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return nil, fmt.Errorf("잘못된 Redis 응답: %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err // $-1은 null
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err // *-1은 null
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("지원하지 않는 Redis 응답 형식: %q", line)
+	}
+}
+
+// escapeGlob은 SCAN MATCH 패턴에서 *를 뺀 특수 문자를 이스케이프합니다
+// (카테고리 이름에 ?나 [가 있어도 다른 키를 지우지 않도록)
+func escapeGlob(pattern string) string {
+	var b strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,241 @@
+package cluster
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+const (
+	// DefaultInterval 상태 알림/피어 조회 기본 주기
+	DefaultInterval = 10 * time.Second
+
+	// subjectPrefix NATS 상태 알림 제목 (tmidb.cluster.nodes.<node>)
+	subjectPrefix = "tmidb.cluster.nodes."
+
+	// peerTimeout 피어 한 곳을 조회하는 제한 시간
+	peerTimeout = 5 * time.Second
+)
+
+// Config 클러스터 설정
+type Config struct {
+	Node     string        // 이 노드 이름 (클러스터 안에서 고유해야 함)
+	Peers    []string      // 정적 피어의 원격 IPC 주소 (host:port)
+	NATSURL  string        // 비어 있지 않으면 NATS로 상태를 알리고 다른 노드를 발견
+	Interval time.Duration // 0이면 DefaultInterval
+	TLS      *tls.Config   // 피어 접속용 mTLS 클라이언트 설정 (Peers 사용 시 필요)
+}
+
+// Manager 노드 상태를 주기적으로 교환하고 클러스터 상태를 제공
+type Manager struct {
+	config   Config
+	local    func() NodeStatus
+	registry *Registry
+
+	nc     *nats.Conn
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewManager local은 이 노드의 현재 상태를 만드는 함수
+func NewManager(config Config, local func() NodeStatus) *Manager {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	return &Manager{
+		config:   config,
+		local:    local,
+		registry: NewRegistry(3*config.Interval, 30*config.Interval),
+	}
+}
+
+// Enabled 다른 노드와 상태를 교환하도록 설정되었는지 확인
+func (m *Manager) Enabled() bool {
+	return len(m.config.Peers) > 0 || m.config.NATSURL != ""
+}
+
+// Start 상태 교환 시작 (설정된 방식이 없으면 아무것도 하지 않음)
+func (m *Manager) Start(ctx context.Context) error {
+	if !m.Enabled() {
+		return nil
+	}
+	if len(m.config.Peers) > 0 && m.config.TLS == nil {
+		return errors.New("cluster peers require the ipc_tls_* certificates")
+	}
+
+	ctx, m.cancel = context.WithCancel(ctx)
+
+	if m.config.NATSURL != "" {
+		if err := m.connectNATS(); err != nil {
+			m.cancel()
+			return err
+		}
+	}
+
+	m.wg.Add(1)
+	go m.run(ctx)
+
+	log.Printf("🌐 Cluster node %s started (%d static peers, NATS: %t)", m.config.Node, len(m.config.Peers), m.nc != nil)
+	return nil
+}
+
+// Stop 상태 교환 중지
+func (m *Manager) Stop() {
+	if m.cancel == nil {
+		return
+	}
+	m.cancel()
+	m.wg.Wait()
+
+	if m.nc != nil {
+		m.nc.Close()
+	}
+}
+
+// Status 이 노드와 다른 모든 노드의 상태
+func (m *Manager) Status() Status {
+	self := m.Local()
+
+	nodes := []NodeStatus{self}
+	for _, node := range m.registry.Snapshot(time.Now()) {
+		if node.Node != self.Node {
+			nodes = append(nodes, node)
+		}
+	}
+	sortNodes(nodes)
+
+	return Status{Self: self.Node, Nodes: nodes}
+}
+
+// Local 이 노드의 현재 상태
+func (m *Manager) Local() NodeStatus {
+	status := m.local()
+	status.Node = m.config.Node
+	status.Self = true
+	status.State = NodeUp
+	status.ReportedAt = time.Now()
+	status.LastSeen = status.ReportedAt
+	return status
+}
+
+func (m *Manager) run(ctx context.Context) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		m.announce()
+		m.pollPeers(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// connectNATS NATS 연결 후 다른 노드의 상태 알림 구독
+func (m *Manager) connectNATS() error {
+	nc, err := nats.Connect(m.config.NATSURL,
+		nats.Name("tmidb-supervisor-cluster-"+m.config.Node),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to connect cluster discovery to %s: %w", m.config.NATSURL, err)
+	}
+
+	_, err = nc.Subscribe(subjectPrefix+"*", func(msg *nats.Msg) {
+		var status NodeStatus
+		if err := json.Unmarshal(msg.Data, &status); err != nil || status.Node == "" {
+			return
+		}
+		if status.Node == m.config.Node {
+			return // 자신이 보낸 알림
+		}
+		// 발견한 노드는 주소가 아닌 이름으로 추적
+		status.Address = ""
+		status.Self = false
+		m.registry.Observe(status, time.Now())
+	})
+	if err != nil {
+		nc.Close()
+		return fmt.Errorf("failed to subscribe to cluster announcements: %w", err)
+	}
+
+	m.nc = nc
+	return nil
+}
+
+// announce 이 노드의 상태를 NATS로 알림
+func (m *Manager) announce() {
+	if m.nc == nil {
+		return
+	}
+
+	status := m.Local()
+	status.Self = false
+	data, err := json.Marshal(status)
+	if err != nil {
+		return
+	}
+	if err := m.nc.Publish(subjectPrefix+m.config.Node, data); err != nil {
+		log.Printf("⚠️ Failed to announce cluster status: %v", err)
+	}
+}
+
+// pollPeers 정적 피어의 상태를 동시에 조회
+func (m *Manager) pollPeers(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, addr := range m.config.Peers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status, err := FetchNode(ctx, addr, m.config.TLS)
+			m.registry.ObservePeer(addr, status, err, time.Now())
+		}()
+	}
+	wg.Wait()
+}
+
+// FetchNode 원격 슈퍼바이저의 노드 상태 조회
+func FetchNode(ctx context.Context, addr string, tlsConfig *tls.Config) (NodeStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, peerTimeout)
+	defer cancel()
+
+	client := ipc.NewRemoteClient(addr, tlsConfig, ipc.ClientOptions{
+		ConnectTimeout: 2 * time.Second,
+		RequestTimeout: peerTimeout,
+	})
+	defer client.Close()
+
+	resp, err := client.SendMessageContext(ctx, ipc.MessageTypeClusterNode, nil)
+	if err != nil {
+		return NodeStatus{}, err
+	}
+	if !resp.Success {
+		return NodeStatus{}, errors.New(resp.Error)
+	}
+
+	var status NodeStatus
+	raw, _ := json.Marshal(resp.Data)
+	if err := json.Unmarshal(raw, &status); err != nil {
+		return NodeStatus{}, fmt.Errorf("invalid node status from %s: %w", addr, err)
+	}
+	if status.Node == "" {
+		return NodeStatus{}, fmt.Errorf("node at %s did not report a name", addr)
+	}
+	status.Self = false
+	return status, nil
+}
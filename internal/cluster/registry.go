@@ -0,0 +1,182 @@
+// Package cluster 여러 슈퍼바이저의 상태를 모아 클러스터 전체 보기를 제공한다.
+//
+// 각 슈퍼바이저는 정적 피어 목록을 원격 IPC(mTLS)로 주기적으로 조회하거나,
+// NATS로 자신의 상태를 알리고 다른 노드의 상태를 수신한다. 어느 노드에서든
+// 모든 노드의 프로세스 상태, 버전, 자원 사용량을 볼 수 있다.
+package cluster
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// NodeState 노드 상태
+type NodeState string
+
+const (
+	NodeUp          NodeState = "up"          // 최근 상태를 받음
+	NodeStale       NodeState = "stale"       // 일정 시간 동안 상태를 받지 못함
+	NodeUnreachable NodeState = "unreachable" // 피어 조회 실패
+)
+
+// Resources 노드 자원 사용률 (퍼센트)
+type Resources struct {
+	CPUUsage    float64 `json:"cpu_usage"`
+	MemoryUsage float64 `json:"memory_usage"`
+	DiskUsage   float64 `json:"disk_usage"`
+}
+
+// NodeStatus 노드 하나의 상태
+type NodeStatus struct {
+	Node       string            `json:"node"`
+	Address    string            `json:"address,omitempty"` // 정적 피어의 원격 IPC 주소
+	Hostname   string            `json:"hostname,omitempty"`
+	Version    string            `json:"version,omitempty"`
+	StartedAt  time.Time         `json:"started_at"`
+	ReportedAt time.Time         `json:"reported_at"` // 노드가 상태를 만든 시각
+	LastSeen   time.Time         `json:"last_seen"`   // 이 노드가 상태를 받은 시각
+	State      NodeState         `json:"state"`
+	Self       bool              `json:"self,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Resources  Resources         `json:"resources"`
+	Processes  []ipc.ProcessInfo `json:"processes,omitempty"`
+}
+
+// Running 실행 중인 프로세스 수
+func (n NodeStatus) Running() int {
+	running := 0
+	for _, p := range n.Processes {
+		if p.Status == "running" {
+			running++
+		}
+	}
+	return running
+}
+
+// Status 클러스터 전체 상태 (이 노드 포함, 노드 이름순)
+type Status struct {
+	Self  string       `json:"self"`
+	Nodes []NodeStatus `json:"nodes"`
+}
+
+type entry struct {
+	status      NodeStatus
+	seen        time.Time
+	static      bool // 정적 피어는 응답이 없어도 목록에서 제거하지 않음
+	unreachable bool
+}
+
+// Registry 다른 노드에서 받은 상태 목록
+type Registry struct {
+	mu    sync.Mutex
+	nodes map[string]*entry
+
+	staleAfter  time.Duration
+	expireAfter time.Duration
+}
+
+// NewRegistry staleAfter 동안 소식이 없으면 stale로, NATS로 발견한 노드는
+// expireAfter가 지나면 목록에서 제거하는 레지스트리 생성
+func NewRegistry(staleAfter, expireAfter time.Duration) *Registry {
+	return &Registry{
+		nodes:       make(map[string]*entry),
+		staleAfter:  staleAfter,
+		expireAfter: expireAfter,
+	}
+}
+
+// Observe 노드 상태 수신 기록
+func (r *Registry) Observe(status NodeStatus, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	static := false
+	if status.Address != "" {
+		// 같은 주소로 기록된 이전 항목 (이름을 알기 전의 실패 기록 등) 정리
+		for name, e := range r.nodes {
+			if e.status.Address == status.Address {
+				static = static || e.static
+				if name != status.Node {
+					delete(r.nodes, name)
+				}
+			}
+		}
+	}
+	if e, ok := r.nodes[status.Node]; ok {
+		static = static || e.static
+	}
+
+	status.LastSeen = now
+	status.Error = ""
+	r.nodes[status.Node] = &entry{status: status, seen: now, static: static}
+}
+
+// ObservePeer 정적 피어 조회 결과 기록 (err가 nil이 아니면 unreachable)
+func (r *Registry) ObservePeer(addr string, status NodeStatus, err error, now time.Time) {
+	if err == nil {
+		status.Address = addr
+		r.Observe(status, now)
+
+		r.mu.Lock()
+		r.nodes[status.Node].static = true
+		r.mu.Unlock()
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.nodes {
+		if e.status.Address == addr {
+			e.unreachable = true
+			e.static = true
+			e.status.Error = err.Error()
+			return
+		}
+	}
+	// 한 번도 응답하지 않은 피어는 주소를 이름으로 표시
+	r.nodes[addr] = &entry{
+		status:      NodeStatus{Node: addr, Address: addr, Error: err.Error()},
+		static:      true,
+		unreachable: true,
+	}
+}
+
+// Snapshot 현재 노드 목록 (만료된 동적 노드는 제거)
+func (r *Registry) Snapshot(now time.Time) []NodeStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	nodes := make([]NodeStatus, 0, len(r.nodes))
+	for name, e := range r.nodes {
+		age := now.Sub(e.seen)
+		if !e.static && r.expireAfter > 0 && age > r.expireAfter {
+			delete(r.nodes, name)
+			continue
+		}
+
+		status := e.status
+		switch {
+		case e.unreachable:
+			status.State = NodeUnreachable
+		case r.staleAfter > 0 && age > r.staleAfter:
+			status.State = NodeStale
+		default:
+			status.State = NodeUp
+		}
+		nodes = append(nodes, status)
+	}
+
+	sortNodes(nodes)
+	return nodes
+}
+
+func sortNodes(nodes []NodeStatus) {
+	slices.SortFunc(nodes, func(a, b NodeStatus) int {
+		return strings.Compare(a.Node, b.Node)
+	})
+}
@@ -0,0 +1,50 @@
+package cluster
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistryStaleAndExpire(t *testing.T) {
+	r := NewRegistry(30*time.Second, 5*time.Minute)
+	now := time.Now()
+
+	r.Observe(NodeStatus{Node: "node-b"}, now)
+	if nodes := r.Snapshot(now.Add(10 * time.Second)); len(nodes) != 1 || nodes[0].State != NodeUp {
+		t.Fatalf("expected node-b up, got %+v", nodes)
+	}
+	if nodes := r.Snapshot(now.Add(time.Minute)); nodes[0].State != NodeStale {
+		t.Fatalf("expected node-b stale, got %s", nodes[0].State)
+	}
+	if nodes := r.Snapshot(now.Add(10 * time.Minute)); len(nodes) != 0 {
+		t.Fatalf("expected discovered node to expire, got %+v", nodes)
+	}
+}
+
+func TestRegistryStaticPeer(t *testing.T) {
+	r := NewRegistry(30*time.Second, 5*time.Minute)
+	now := time.Now()
+	addr := "10.0.0.2:7443"
+
+	// 응답한 적 없는 피어는 주소로 표시
+	r.ObservePeer(addr, NodeStatus{}, errors.New("connection refused"), now)
+	nodes := r.Snapshot(now)
+	if len(nodes) != 1 || nodes[0].Node != addr || nodes[0].State != NodeUnreachable {
+		t.Fatalf("expected unreachable placeholder, got %+v", nodes)
+	}
+
+	// 응답하면 노드 이름으로 교체
+	r.ObservePeer(addr, NodeStatus{Node: "node-b", Version: "1.2.0"}, nil, now)
+	nodes = r.Snapshot(now)
+	if len(nodes) != 1 || nodes[0].Node != "node-b" || nodes[0].State != NodeUp || nodes[0].Address != addr {
+		t.Fatalf("expected node-b up at %s, got %+v", addr, nodes)
+	}
+
+	// 이후 실패는 마지막 상태를 유지하며 unreachable, 만료되지 않음
+	r.ObservePeer(addr, NodeStatus{}, errors.New("timeout"), now.Add(time.Hour))
+	nodes = r.Snapshot(now.Add(time.Hour))
+	if len(nodes) != 1 || nodes[0].State != NodeUnreachable || nodes[0].Version != "1.2.0" || nodes[0].Error != "timeout" {
+		t.Fatalf("expected static peer to stay unreachable, got %+v", nodes)
+	}
+}
@@ -4,8 +4,9 @@ import (
 	"fmt"
 	"log"
 	"os"
-
-	"github.com/joho/godotenv"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Config는 애플리케이션의 모든 설정을 담는 구조체입니다.
@@ -20,57 +21,276 @@ type Config struct {
 	TmiDBUser        string
 	TmiDBPassword    string
 
+	// 읽기 전용 복제본 (데이터 API 조회와 내보내기에 사용, 없거나 모두 비정상이면 기본 DB)
+	DatabaseReplicaURLs   []string
+	ReplicaMaxLagSeconds  int // 복제 지연이 이보다 크면 제외 (0이면 확인 안 함)
+	ReplicaHealthInterval int // 복제본 상태 확인 간격 (초)
+
+	// 느린 쿼리 기록 (slow_queries 테이블)
+	SlowQueryThreshold time.Duration // 이보다 오래 걸린 쿼리를 기록 (0이면 끔)
+	SlowQueryParams    bool          // 가장 느린 실행의 매개변수도 저장 (EXPLAIN에 사용)
+
 	// NATS 관련 설정
 	NatsURL string
 
+	// API 서버 포트
+	APIPort string
+
+	// 파일 첨부 관련 설정 (SeaweedFS 파일러)
+	SeaweedFSFilerURL      string
+	MaxAttachmentSize      int64    // 바이트
+	AttachmentAllowedTypes []string // MIME 타입 목록 ("image/*" 형식 허용)
+
+	// 파일 마이그레이션 디렉터리 (NNN_name.sql / .js)
+	MigrationsDir string
+
+	// 레이트 리밋 (초당 요청 수, 0이면 끔)과 조직별 일일 수집 할당량 (레코드 수, 0이면 무제한)
+	RateLimitTokenRPS    float64
+	RateLimitTokenBurst  int
+	RateLimitIPRPS       float64
+	RateLimitIPBurst     int
+	IngestDailyQuota     int64
+	IngestQuotaOverrides map[string]int64 // 조직 ID → 일일 할당량
+	RateLimitStore       string           // memory 또는 nats (여러 API 인스턴스가 카운터를 공유)
+
+	// 수집 중복 제거 (같은 event_id나 같은 target_id+ts+payload를 이 기간 동안 한 번만 저장, 0이면 끔)
+	IngestDedupWindow time.Duration
+
+	// 휴지통 (0보다 크면 삭제한 타겟과 카테고리 문서를 이 기간 동안 보관했다가 영구 삭제, 0이면 바로 삭제)
+	SoftDeleteRetention time.Duration
+
+	// API 응답 캐시 (memory 또는 redis; redis는 Dragonfly 등 호환 서버 포함, 여러 API 인스턴스가 공유)
+	CacheBackend   string
+	CacheRedisURL  string // redis://[user:password@]host:port/db
+	CacheKeyPrefix string
+
+	// /api/graphql 엔드포인트 (기본값 꺼짐)
+	GraphQLEnabled bool
+
+	// /api/v1/admin/sql SQL 콘솔 (기본값 꺼짐, 읽기 전용 트랜잭션에서 실행 시간과 행 수를 제한)
+	SQLConsoleEnabled bool
+	SQLConsoleTimeout time.Duration
+	SQLConsoleMaxRows int
+
+	// CORS 허용 출처 (비어 있으면 같은 출처만, "*"이면 모두 허용하되 쿠키는 보내지 않음)
+	CORSAllowedOrigins []string
+
+	// 웹 콘솔 세션 (postgres 또는 memory; postgres는 재시작 후에도 유지되고 여러 API 인스턴스가 공유)
+	SessionStore          string
+	SessionIdleTimeout    int // 요청이 없으면 세션이 만료되는 시간 (분)
+	SessionRefreshTTLDays int // 리프레시 토큰 유효 기간 (일, 사용할 때마다 연장, 0이면 리프레시 토큰 끔)
+
+	// 웹 콘솔 OIDC SSO 로그인 (발급자와 클라이언트 ID가 있으면 켜짐, 로컬 계정과 함께 사용)
+	OIDCIssuerURL     string
+	OIDCClientID      string
+	OIDCClientSecret  string
+	OIDCRedirectURL   string // 비어 있으면 요청 주소 + /auth/oidc/callback
+	OIDCScopes        []string
+	OIDCProviderName  string            // 로그인 버튼에 표시할 이름
+	OIDCUsernameClaim string            // 비어 있으면 preferred_username, email, sub 순서
+	OIDCOrgClaim      string            // 조직 이름 또는 ID 클레임
+	OIDCDefaultOrg    string            // 조직 클레임이 없을 때의 조직
+	OIDCRoleClaim     string            // 그룹/역할 클레임
+	OIDCRoleMapping   map[string]string // 클레임 값 → admin, editor, viewer
+	OIDCDefaultRole   string            // 매핑되는 값이 없을 때의 역할 (비어 있으면 로그인 거부)
+	OIDCAutoProvision bool              // 처음 로그인하는 사용자를 자동으로 만듦
+
 	// 기타
 	IsProduction  bool
 	EncryptionKey string
 	// 필요에 따라 다른 설정 추가...
 }
 
-// Load는 환경 변수(.env 파일 포함)에서 설정을 로드합니다.
+// defaultAttachmentTypes 기본 허용 첨부 파일 MIME 타입
+const defaultAttachmentTypes = "image/*,application/pdf,text/plain,text/csv,application/json,application/zip,application/dicom,application/octet-stream"
+
+// Load는 기본값, 설정 파일(.env 또는 TMIDB_CONFIG_FILE), 환경 변수 순서로 겹쳐 설정을 로드합니다.
 func Load() (*Config, error) {
-	// .env 파일을 로드합니다. 파일이 없어도 오류가 발생하지 않습니다.
-	if err := godotenv.Load(); err != nil {
+	return LoadWithOptions(Options{})
+}
+
+// LoadArgs는 Load에 명령행 플래그(--db-host=..., --config=...)를 마지막 단계로 더합니다.
+func LoadArgs(args []string) (*Config, error) {
+	return LoadWithOptions(Options{Args: args})
+}
+
+// LoadWithOptions는 설정을 병합하고 검사합니다. 잘못된 값이 있으면 키를 모두 밝힌 오류를 반환합니다.
+func LoadWithOptions(opts Options) (*Config, error) {
+	r := resolve(opts)
+	if err := r.err(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	if r.file == "" {
 		log.Println("No .env file found, using environment variables")
 	}
+	if opts.Env == nil {
+		// 설정 파일에만 있는 값도 os.Getenv로 읽을 수 있게 함 (환경 변수가 우선)
+		for k, v := range r.fileVars {
+			if _, exists := os.LookupEnv(k); !exists {
+				os.Setenv(k, v)
+			}
+		}
+	}
 
 	cfg := &Config{
-		PostgresHost:     getEnv("DB_HOST", "localhost"),
-		PostgresPort:     getEnv("DB_PORT", "5432"),
-		PostgresUser:     getEnv("POSTGRES_USER", "postgres"),
-		PostgresPassword: getEnv("POSTGRES_PASSWORD", "postgres"),
-		PostgresDBName:   getEnv("POSTGRES_DB", "tmidb"),
-		TmiDBUser:        getEnv("TMIDB_USER", "tmidb_admin"),
-		TmiDBPassword:    getEnv("TMIDB_PASSWORD", "tmidb_secure_2024!"), // 이 비밀번호는 안전하게 관리해야 합니다.
-		NatsURL:          getEnv("NATS_URL", "nats://localhost:4222"),
-		IsProduction:     getEnvAsBool("IS_PRODUCTION", false),
-		EncryptionKey:    getEnv("ENCRYPTION_KEY", "e8e1694709a47355153cf11794252386a683d789a781b5399583643f82862e63"), // 32바이트 AES 키(64 hex chars)
+		PostgresHost:     r.str("DB_HOST"),
+		PostgresPort:     r.str("DB_PORT"),
+		PostgresUser:     r.str("POSTGRES_USER"),
+		PostgresPassword: r.str("POSTGRES_PASSWORD"),
+		PostgresDBName:   r.str("POSTGRES_DB"),
+		TmiDBUser:        r.str("TMIDB_USER"),
+		TmiDBPassword:    r.str("TMIDB_PASSWORD"),
+		NatsURL:          r.str("NATS_URL"),
+		APIPort:          r.str("API_PORT"),
+		IsProduction:     r.bool("IS_PRODUCTION"),
+		EncryptionKey:    r.str("ENCRYPTION_KEY"),
 	}
 
+	cfg.SeaweedFSFilerURL = r.str("SEAWEEDFS_FILER_URL")
+	cfg.MaxAttachmentSize = int64(r.int("MAX_ATTACHMENT_SIZE_MB")) * 1024 * 1024
+	cfg.AttachmentAllowedTypes = strings.Split(r.str("ATTACHMENT_ALLOWED_TYPES"), ",")
+	cfg.MigrationsDir = r.str("MIGRATIONS_DIR")
+
+	cfg.RateLimitTokenRPS = r.float("RATE_LIMIT_TOKEN_RPS")
+	cfg.RateLimitTokenBurst = r.int("RATE_LIMIT_TOKEN_BURST")
+	cfg.RateLimitIPRPS = r.float("RATE_LIMIT_IP_RPS")
+	cfg.RateLimitIPBurst = r.int("RATE_LIMIT_IP_BURST")
+	cfg.IngestDailyQuota = int64(r.int("INGEST_DAILY_QUOTA"))
+	cfg.IngestQuotaOverrides = parseQuotaOverrides(r.str("INGEST_QUOTA_OVERRIDES"))
+	cfg.RateLimitStore = r.str("RATE_LIMIT_STORE")
+	cfg.IngestDedupWindow = time.Duration(r.int("INGEST_DEDUP_WINDOW_HOURS")) * time.Hour
+	cfg.SoftDeleteRetention = time.Duration(r.int("SOFT_DELETE_RETENTION_DAYS")) * 24 * time.Hour
+
+	cfg.CacheBackend = r.str("CACHE_BACKEND")
+	cfg.CacheRedisURL = r.str("CACHE_REDIS_URL")
+	cfg.CacheKeyPrefix = r.str("CACHE_KEY_PREFIX")
+
+	cfg.GraphQLEnabled = r.bool("GRAPHQL_ENABLED")
+
+	cfg.SQLConsoleEnabled = r.bool("SQL_CONSOLE_ENABLED")
+	cfg.SQLConsoleTimeout = time.Duration(r.int("SQL_CONSOLE_TIMEOUT_SECONDS")) * time.Second
+	if cfg.SQLConsoleTimeout <= 0 {
+		cfg.SQLConsoleTimeout = 10 * time.Second
+	}
+	cfg.SQLConsoleMaxRows = r.int("SQL_CONSOLE_MAX_ROWS")
+	if cfg.SQLConsoleMaxRows <= 0 {
+		cfg.SQLConsoleMaxRows = 1000
+	}
+
+	cfg.CORSAllowedOrigins = parseList(r.str("CORS_ALLOWED_ORIGINS"))
+
+	cfg.SessionStore = r.str("SESSION_STORE")
+	cfg.SessionIdleTimeout = r.int("SESSION_IDLE_TIMEOUT_MINUTES")
+	if cfg.SessionIdleTimeout <= 0 {
+		cfg.SessionIdleTimeout = 60
+	}
+	cfg.SessionRefreshTTLDays = r.int("SESSION_REFRESH_TTL_DAYS")
+
+	cfg.OIDCIssuerURL = r.str("OIDC_ISSUER_URL")
+	cfg.OIDCClientID = r.str("OIDC_CLIENT_ID")
+	cfg.OIDCClientSecret = r.str("OIDC_CLIENT_SECRET")
+	cfg.OIDCRedirectURL = r.str("OIDC_REDIRECT_URL")
+	cfg.OIDCScopes = strings.Fields(r.str("OIDC_SCOPES"))
+	cfg.OIDCProviderName = r.str("OIDC_PROVIDER_NAME")
+	cfg.OIDCUsernameClaim = r.str("OIDC_USERNAME_CLAIM")
+	cfg.OIDCOrgClaim = r.str("OIDC_ORG_CLAIM")
+	cfg.OIDCDefaultOrg = r.str("OIDC_DEFAULT_ORG")
+	cfg.OIDCRoleClaim = r.str("OIDC_ROLE_CLAIM")
+	cfg.OIDCRoleMapping = parseRoleMapping(r.str("OIDC_ROLE_MAPPING"))
+	cfg.OIDCDefaultRole = r.str("OIDC_DEFAULT_ROLE")
+	cfg.OIDCAutoProvision = r.bool("OIDC_AUTO_PROVISION")
+
 	cfg.DatabaseURL = fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
 		cfg.TmiDBUser, cfg.TmiDBPassword, cfg.PostgresHost, cfg.PostgresPort, cfg.PostgresDBName)
 
+	cfg.DatabaseReplicaURLs = parseReplicaHosts(r.str("DB_REPLICA_HOSTS"), cfg)
+	cfg.ReplicaMaxLagSeconds = r.int("DB_REPLICA_MAX_LAG_SECONDS")
+	cfg.ReplicaHealthInterval = r.int("DB_REPLICA_HEALTH_INTERVAL_SECONDS")
+	if cfg.ReplicaHealthInterval <= 0 {
+		cfg.ReplicaHealthInterval = 5
+	}
+
+	cfg.SlowQueryThreshold = time.Duration(r.int("SLOW_QUERY_MS")) * time.Millisecond
+	cfg.SlowQueryParams = r.bool("SLOW_QUERY_PARAMS")
+
 	return cfg, nil
 }
 
-// getEnv는 환경 변수를 읽거나, 없을 경우 기본값을 반환합니다.
-func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
+// parseList는 쉼표로 구분한 목록을 읽습니다 (빈 항목 제외)
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseQuotaOverrides는 "org1=100000,org2=0" 형식의 조직별 할당량을 읽습니다.
+// 잘못된 항목은 경고를 남기고 건너뜁니다.
+func parseQuotaOverrides(value string) map[string]int64 {
+	overrides := make(map[string]int64)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		orgID, limit, ok := strings.Cut(entry, "=")
+		quota, err := strconv.ParseInt(strings.TrimSpace(limit), 10, 64)
+		if !ok || err != nil || quota < 0 {
+			log.Printf("⚠️ 잘못된 INGEST_QUOTA_OVERRIDES 항목 무시: %q", entry)
+			continue
+		}
+		overrides[strings.TrimSpace(orgID)] = quota
 	}
-	return defaultValue
+	return overrides
+}
+
+// OIDCEnabled는 OIDC SSO 로그인이 설정되었는지 반환합니다.
+func (c *Config) OIDCEnabled() bool {
+	return c.OIDCIssuerURL != "" && c.OIDCClientID != ""
 }
 
-// getEnvAsBool는 환경 변수를 bool 값으로 읽습니다.
-func getEnvAsBool(key string, defaultValue bool) bool {
-	valueStr := getEnv(key, "")
-	if valueStr == "true" || valueStr == "1" {
-		return true
+// parseRoleMapping은 "tmidb-admins=admin,tmidb-editors=editor" 형식의 클레임 값별 역할을 읽습니다.
+// 역할은 admin, editor, viewer만 허용하고 잘못된 항목은 경고를 남기고 건너뜁니다.
+func parseRoleMapping(value string) map[string]string {
+	mapping := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		claim, role, ok := strings.Cut(entry, "=")
+		role = strings.TrimSpace(role)
+		if !ok || strings.TrimSpace(claim) == "" || (role != "admin" && role != "editor" && role != "viewer") {
+			log.Printf("⚠️ 잘못된 OIDC_ROLE_MAPPING 항목 무시: %q", entry)
+			continue
+		}
+		mapping[strings.TrimSpace(claim)] = role
 	}
-	if valueStr == "false" || valueStr == "0" {
-		return false
+	return mapping
+}
+
+// parseReplicaHosts는 "host[:port],..." 형식의 복제본 목록을 tmiDB 사용자의 연결 DSN으로 바꿉니다.
+// 포트가 없으면 기본 DB와 같은 포트를 씁니다.
+func parseReplicaHosts(value string, cfg *Config) []string {
+	var urls []string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		host, port, ok := strings.Cut(entry, ":")
+		if !ok || port == "" {
+			port = cfg.PostgresPort
+		}
+		if _, err := strconv.Atoi(port); err != nil || host == "" {
+			log.Printf("⚠️ 잘못된 DB_REPLICA_HOSTS 항목 무시: %q", entry)
+			continue
+		}
+		urls = append(urls, fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+			cfg.TmiDBUser, cfg.TmiDBPassword, host, port, cfg.PostgresDBName))
 	}
-	return defaultValue
+	return urls
 }
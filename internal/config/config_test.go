@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLayeredLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tmidb.env")
+	if err := os.WriteFile(file, []byte("DB_HOST=file-host\nDB_PORT=6000\nNATS_URL=nats://file:4222\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := Options{
+		Env:  map[string]string{EnvConfigFile: file, "DB_PORT": "7000", "NATS_URL": "nats://env:4222"},
+		Args: []string{"--nats-url=nats://flag:4222"},
+	}
+	cfg, err := LoadWithOptions(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.PostgresHost != "file-host" || cfg.PostgresPort != "7000" || cfg.NatsURL != "nats://flag:4222" || cfg.PostgresDBName != "tmidb" {
+		t.Errorf("cfg = host %s, port %s, nats %s, db %s", cfg.PostgresHost, cfg.PostgresPort, cfg.NatsURL, cfg.PostgresDBName)
+	}
+
+	report := Doctor(opts)
+	if report.File != file {
+		t.Errorf("file = %q", report.File)
+	}
+	want := map[string]Source{"POSTGRES_DB": SourceDefault, "DB_HOST": SourceFile, "DB_PORT": SourceEnv, "NATS_URL": SourceFlag}
+	for _, s := range report.Settings {
+		if source, ok := want[s.Key]; ok && s.Source != source {
+			t.Errorf("%s from %s, want %s", s.Key, s.Source, source)
+		}
+		if s.Key == "TMIDB_PASSWORD" && s.Value != secretMask {
+			t.Errorf("secret shown as %q", s.Value)
+		}
+	}
+}
+
+func TestLoadValidation(t *testing.T) {
+	_, err := LoadWithOptions(Options{
+		Env:  map[string]string{"DB_PORT": "abc", "CACHE_BACKEND": "memcached", "ENCRYPTION_KEY": "short"},
+		Args: []string{"--graphql-enabled=maybe"},
+	})
+	if err == nil {
+		t.Fatal("invalid values accepted")
+	}
+	for _, key := range []string{`DB_PORT="abc" (env)`, "CACHE_BACKEND", `GRAPHQL_ENABLED="maybe" (flag)`, `ENCRYPTION_KEY="********"`} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("error does not name %s: %v", key, err)
+		}
+	}
+
+	if _, err := LoadWithOptions(Options{Env: map[string]string{}, File: filepath.Join(t.TempDir(), "missing.env")}); err == nil {
+		t.Error("missing explicit config file accepted")
+	}
+	if _, err := LoadWithOptions(Options{Env: map[string]string{}, Args: []string{"--no-such-key=1"}}); err == nil {
+		t.Error("unknown flag accepted")
+	}
+}
+
+func TestProductionCORSDefault(t *testing.T) {
+	cfg, err := LoadWithOptions(Options{Env: map[string]string{"IS_PRODUCTION": "true"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.CORSAllowedOrigins) != 0 {
+		t.Errorf("production CORS origins = %v", cfg.CORSAllowedOrigins)
+	}
+}
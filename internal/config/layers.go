@@ -0,0 +1,335 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// Source는 설정 값이 어느 단계에서 정해졌는지 나타냅니다.
+// 뒤의 단계가 앞의 단계를 덮어씁니다: default < file < env < flag
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// EnvConfigFile은 설정 파일 경로를 지정하는 환경 변수입니다 (--config 플래그가 우선).
+const EnvConfigFile = "TMIDB_CONFIG_FILE"
+
+// defaultConfigFile 경로를 지정하지 않았을 때 읽는 설정 파일 (없어도 됨)
+const defaultConfigFile = ".env"
+
+// secretMask 시크릿 값 대신 보여 줄 문자열
+const secretMask = "********"
+
+// kind 설정 값의 형식
+type kind int
+
+const (
+	kindString kind = iota
+	kindInt
+	kindFloat
+	kindBool
+	kindPort
+	kindChoice
+	kindHexKey // 32바이트 AES 키 (64 hex chars)
+)
+
+// field 설정 키 하나의 정의 (키는 환경 변수 이름이자 설정 파일 키)
+type field struct {
+	key     string
+	def     string
+	kind    kind
+	choices []string
+	secret  bool
+}
+
+// flagName 키의 명령행 플래그 이름 (DB_HOST → db-host)
+func (f field) flagName() string {
+	return strings.ReplaceAll(strings.ToLower(f.key), "_", "-")
+}
+
+// fields Load가 읽는 모든 설정 키
+var fields = []field{
+	{key: "DB_HOST", def: "localhost"},
+	{key: "DB_PORT", def: "5432", kind: kindPort},
+	{key: "POSTGRES_USER", def: "postgres"},
+	{key: "POSTGRES_PASSWORD", def: "postgres", secret: true},
+	{key: "POSTGRES_DB", def: "tmidb"},
+	{key: "TMIDB_USER", def: "tmidb_admin"},
+	{key: "TMIDB_PASSWORD", def: "tmidb_secure_2024!", secret: true}, // 이 비밀번호는 안전하게 관리해야 합니다.
+	{key: "DB_REPLICA_HOSTS"},
+	{key: "DB_REPLICA_MAX_LAG_SECONDS", def: "30", kind: kindInt},
+	{key: "DB_REPLICA_HEALTH_INTERVAL_SECONDS", def: "5", kind: kindInt},
+	{key: "SLOW_QUERY_MS", def: "500", kind: kindInt},
+	{key: "SLOW_QUERY_PARAMS", def: "true", kind: kindBool},
+	{key: "NATS_URL", def: "nats://localhost:4222"},
+	{key: "API_PORT", def: "8020", kind: kindPort},
+	{key: "SEAWEEDFS_FILER_URL", def: "http://localhost:8888"},
+	{key: "MAX_ATTACHMENT_SIZE_MB", def: "25", kind: kindInt},
+	{key: "ATTACHMENT_ALLOWED_TYPES", def: defaultAttachmentTypes},
+	{key: "MIGRATIONS_DIR", def: "migrations"},
+	{key: "RATE_LIMIT_TOKEN_RPS", def: "0", kind: kindFloat},
+	{key: "RATE_LIMIT_TOKEN_BURST", def: "0", kind: kindInt},
+	{key: "RATE_LIMIT_IP_RPS", def: "0", kind: kindFloat},
+	{key: "RATE_LIMIT_IP_BURST", def: "0", kind: kindInt},
+	{key: "RATE_LIMIT_STORE", def: "memory", kind: kindChoice, choices: []string{"memory", "nats"}},
+	{key: "INGEST_DAILY_QUOTA", def: "0", kind: kindInt},
+	{key: "INGEST_QUOTA_OVERRIDES"},
+	{key: "INGEST_DEDUP_WINDOW_HOURS", def: "24", kind: kindInt},
+	{key: "SOFT_DELETE_RETENTION_DAYS", def: "0", kind: kindInt},
+	{key: "CACHE_BACKEND", def: "memory", kind: kindChoice, choices: []string{"memory", "redis"}},
+	{key: "CACHE_REDIS_URL", def: "redis://localhost:6379/0", secret: true},
+	{key: "CACHE_KEY_PREFIX", def: "tmidb:"},
+	{key: "GRAPHQL_ENABLED", def: "false", kind: kindBool},
+	{key: "SQL_CONSOLE_ENABLED", def: "false", kind: kindBool},
+	{key: "SQL_CONSOLE_TIMEOUT_SECONDS", def: "10", kind: kindInt},
+	{key: "SQL_CONSOLE_MAX_ROWS", def: "1000", kind: kindInt},
+	{key: "CORS_ALLOWED_ORIGINS", def: "*"}, // 운영 환경의 기본값은 비어 있음 (resolve 참고)
+	{key: "SESSION_STORE", def: "postgres", kind: kindChoice, choices: []string{"postgres", "memory"}},
+	{key: "SESSION_IDLE_TIMEOUT_MINUTES", def: "60", kind: kindInt},
+	{key: "SESSION_REFRESH_TTL_DAYS", def: "14", kind: kindInt},
+	{key: "OIDC_ISSUER_URL"},
+	{key: "OIDC_CLIENT_ID"},
+	{key: "OIDC_CLIENT_SECRET", secret: true},
+	{key: "OIDC_REDIRECT_URL"},
+	{key: "OIDC_SCOPES", def: "openid profile email"},
+	{key: "OIDC_PROVIDER_NAME", def: "SSO"},
+	{key: "OIDC_USERNAME_CLAIM"},
+	{key: "OIDC_ORG_CLAIM"},
+	{key: "OIDC_DEFAULT_ORG"},
+	{key: "OIDC_ROLE_CLAIM", def: "groups"},
+	{key: "OIDC_ROLE_MAPPING"},
+	{key: "OIDC_DEFAULT_ROLE", def: "viewer", kind: kindChoice, choices: []string{"", "admin", "editor", "viewer"}},
+	{key: "OIDC_AUTO_PROVISION", def: "true", kind: kindBool},
+	{key: "IS_PRODUCTION", def: "false", kind: kindBool},
+	{key: "ENCRYPTION_KEY", def: "e8e1694709a47355153cf11794252386a683d789a781b5399583643f82862e63", kind: kindHexKey, secret: true},
+}
+
+// Setting은 병합된 설정 값 하나와 그 출처입니다.
+type Setting struct {
+	Key    string `json:"key"`
+	Flag   string `json:"flag"`
+	Value  string `json:"value"`
+	Source Source `json:"source"`
+	Origin string `json:"origin,omitempty"` // 설정 파일 경로, 시크릿 등 출처의 세부 정보
+	Secret bool   `json:"secret,omitempty"`
+}
+
+// FieldError는 잘못된 설정 값 하나를 키와 출처와 함께 나타냅니다.
+type FieldError struct {
+	Key    string
+	Value  string
+	Source Source
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s=%q (%s): %s", e.Key, e.Value, e.Source, e.Reason)
+}
+
+// Options는 설정을 병합할 입력입니다.
+type Options struct {
+	// File은 설정 파일 경로입니다 (비어 있으면 --config, TMIDB_CONFIG_FILE, .env 순서).
+	File string
+	// Env는 환경 변수입니다 (nil이면 현재 프로세스의 환경 변수).
+	Env map[string]string
+	// Args는 --db-host=... 형식의 명령행 플래그입니다.
+	Args []string
+}
+
+// Report는 병합된 설정 전체와 검증 오류입니다. 시크릿 값은 가려져 있습니다.
+type Report struct {
+	File     string    `json:"file,omitempty"` // 읽은 설정 파일 (없으면 비어 있음)
+	Settings []Setting `json:"settings"`
+	Errors   []string  `json:"errors,omitempty"`
+}
+
+// resolved 병합 결과 (키 → 값과 출처)
+type resolved struct {
+	file     string
+	fileVars map[string]string
+	settings map[string]*Setting
+	errs     []error
+}
+
+// resolve는 기본값, 설정 파일, 환경 변수, 명령행 플래그를 차례로 겹치고 형식을 검사합니다.
+func resolve(opts Options) *resolved {
+	r := &resolved{settings: make(map[string]*Setting, len(fields))}
+
+	env := opts.Env
+	if env == nil {
+		env = environ()
+	}
+
+	// 명령행 플래그 (설정 파일 경로를 알아야 하므로 먼저 읽음)
+	fs := flag.NewFlagSet("tmidb", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	configFlag := fs.String("config", "", "config file")
+	flagValues := make(map[string]*string, len(fields))
+	for _, f := range fields {
+		flagValues[f.key] = fs.String(f.flagName(), "", f.key)
+	}
+	if err := fs.Parse(opts.Args); err != nil {
+		r.errs = append(r.errs, fmt.Errorf("invalid command line: %v", err))
+	}
+	flagSet := make(map[string]bool)
+	fs.Visit(func(fl *flag.Flag) { flagSet[fl.Name] = true })
+
+	// 설정 파일 (명시한 파일이 없으면 오류, 기본 .env는 없어도 됨)
+	path, explicit := opts.File, opts.File != ""
+	if flagSet["config"] {
+		path, explicit = *configFlag, true
+	} else if !explicit {
+		if p := env[EnvConfigFile]; p != "" {
+			path, explicit = p, true
+		} else {
+			path = defaultConfigFile
+		}
+	}
+	if vars, err := godotenv.Read(path); err == nil {
+		r.file, r.fileVars = path, vars
+	} else if explicit || !errors.Is(err, os.ErrNotExist) {
+		r.errs = append(r.errs, fmt.Errorf("config file %s: %v", path, err))
+	}
+
+	for _, f := range fields {
+		s := &Setting{Key: f.key, Flag: "--" + f.flagName(), Value: f.def, Source: SourceDefault, Secret: f.secret}
+		if v, ok := r.fileVars[f.key]; ok {
+			s.Value, s.Source, s.Origin = v, SourceFile, path
+		}
+		if v, ok := env[f.key]; ok {
+			s.Value, s.Source, s.Origin = v, SourceEnv, ""
+		}
+		if flagSet[f.flagName()] {
+			s.Value, s.Source, s.Origin = *flagValues[f.key], SourceFlag, ""
+		}
+		r.settings[f.key] = s
+		if err := f.check(s); err != nil {
+			r.errs = append(r.errs, err)
+		}
+	}
+
+	// 개발 환경은 모든 출처를 허용하고 운영 환경은 명시한 출처만 허용
+	if s := r.settings["CORS_ALLOWED_ORIGINS"]; s.Source == SourceDefault && r.bool("IS_PRODUCTION") {
+		s.Value = ""
+	}
+	return r
+}
+
+// check 값이 키의 형식에 맞는지 검사
+func (f field) check(s *Setting) error {
+	fail := func(reason string) error {
+		value := s.Value
+		if f.secret {
+			value = secretMask
+		}
+		return &FieldError{Key: f.key, Value: value, Source: s.Source, Reason: reason}
+	}
+
+	switch f.kind {
+	case kindInt:
+		if _, err := strconv.Atoi(s.Value); err != nil {
+			return fail("must be an integer")
+		}
+	case kindFloat:
+		if _, err := strconv.ParseFloat(s.Value, 64); err != nil {
+			return fail("must be a number")
+		}
+	case kindBool:
+		if _, err := strconv.ParseBool(s.Value); err != nil {
+			return fail("must be true or false")
+		}
+	case kindPort:
+		if port, err := strconv.Atoi(s.Value); err != nil || port < 1 || port > 65535 {
+			return fail("must be a port number (1-65535)")
+		}
+	case kindChoice:
+		if !slices.Contains(f.choices, s.Value) {
+			var names []string
+			for _, c := range f.choices {
+				if c != "" {
+					names = append(names, c)
+				}
+			}
+			return fail("must be one of " + strings.Join(names, ", "))
+		}
+	case kindHexKey:
+		if key, err := hex.DecodeString(s.Value); err != nil || len(key) != 32 {
+			return fail("must be 64 hex characters (32-byte AES key)")
+		}
+	}
+	return nil
+}
+
+// str 키의 값
+func (r *resolved) str(key string) string {
+	return r.settings[key].Value
+}
+
+// int 키의 정수 값 (형식은 resolve에서 검사)
+func (r *resolved) int(key string) int {
+	value, _ := strconv.Atoi(r.str(key))
+	return value
+}
+
+// float 키의 실수 값
+func (r *resolved) float(key string) float64 {
+	value, _ := strconv.ParseFloat(r.str(key), 64)
+	return value
+}
+
+// bool 키의 bool 값
+func (r *resolved) bool(key string) bool {
+	value, _ := strconv.ParseBool(r.str(key))
+	return value
+}
+
+// err 검증 오류를 하나로 묶음 (없으면 nil)
+func (r *resolved) err() error {
+	return errors.Join(r.errs...)
+}
+
+// report 시크릿을 가린 병합 결과
+func (r *resolved) report() *Report {
+	report := &Report{File: r.file, Settings: make([]Setting, 0, len(fields))}
+	for _, f := range fields {
+		s := *r.settings[f.key]
+		if s.Secret && s.Value != "" {
+			s.Value = secretMask
+		}
+		report.Settings = append(report.Settings, s)
+	}
+	for _, err := range r.errs {
+		report.Errors = append(report.Errors, err.Error())
+	}
+	return report
+}
+
+// Doctor는 설정을 적용하지 않고 병합 결과와 각 값의 출처를 반환합니다.
+func Doctor(opts Options) *Report {
+	return resolve(opts).report()
+}
+
+// environ 현재 프로세스의 환경 변수
+func environ() map[string]string {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	return env
+}
@@ -0,0 +1,134 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// 관측값 집계 함수
+var aggregateSQL = map[string]string{
+	"avg":   "avg(v)",
+	"min":   "min(v)",
+	"max":   "max(v)",
+	"sum":   "sum(v)",
+	"count": "count(v)",
+	"last":  "last(v, ts)",
+}
+
+// AggregateFunctions 지원하는 집계 함수 이름
+var AggregateFunctions = []string{"avg", "min", "max", "sum", "count", "last"}
+
+// AggregateQuery는 관측값 payload의 숫자 필드 하나를 시간 구간별로 집계하는 조건입니다.
+type AggregateQuery struct {
+	OrgID    string
+	Category string
+	Path     []string // payload 안의 경로 (예: ["vitals", "hr"])
+	Func     string   // AggregateFunctions 중 하나
+	From     time.Time
+	To       time.Time
+	Bucket   time.Duration
+	TargetID string // 비어 있으면 모든 타겟
+	ByTarget bool   // 타겟별로 나눈 시리즈
+	Limit    int    // ByTarget일 때 최대 시리즈 수
+}
+
+// AggregatePoint는 시간 구간 하나의 집계 값입니다.
+type AggregatePoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// AggregateSeries는 집계 결과 시리즈입니다 (ByTarget이 아니면 타겟 정보 없이 하나).
+type AggregateSeries struct {
+	TargetID   string
+	TargetName string
+	Points     []AggregatePoint
+}
+
+// AggregateObservations는 조직의 관측값을 time_bucket으로 묶어 집계합니다.
+// 경로의 값이 숫자가 아닌 관측값은 건너뜁니다.
+func AggregateObservations(db DBTX, q AggregateQuery) ([]AggregateSeries, error) {
+	agg, ok := aggregateSQL[q.Func]
+	if !ok {
+		return nil, fmt.Errorf("unknown aggregate %q", q.Func)
+	}
+	if len(q.Path) == 0 {
+		return nil, fmt.Errorf("field path is required")
+	}
+
+	targetCols, groupCols := "'', ''", "bucket"
+	if q.ByTarget {
+		targetCols, groupCols = "target_id::text, name", "bucket, target_id, name"
+	}
+	stmt := `WITH obs AS (
+			SELECT o.target_id, t.name, o.ts, (o.payload #>> $3)::double precision AS v
+			FROM ts_obs o
+			JOIN target_categories tc ON tc.target_id = o.target_id AND tc.category_name = o.category_name
+			JOIN target t ON t.target_id = o.target_id
+			WHERE tc.org_id::text = $1 AND o.category_name = $2 AND tc.deleted_at IS NULL AND o.ts >= $4 AND o.ts < $5
+			  AND jsonb_typeof(o.payload #> $3) = 'number'
+			  AND ($6 = '' OR o.target_id::text = $6)
+		)
+		SELECT time_bucket(make_interval(secs => $7), ts) AS bucket, ` + targetCols + `, ` + agg + `
+		FROM obs
+		GROUP BY ` + groupCols + `
+		ORDER BY ` + groupCols
+	rows, err := db.Query(stmt, q.OrgID, q.Category, pq.Array(q.Path), q.From, q.To, q.TargetID, q.Bucket.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var series []AggregateSeries
+	index := make(map[string]int)
+	for rows.Next() {
+		var p AggregatePoint
+		var targetID, name string
+		if err := rows.Scan(&p.Time, &targetID, &name, &p.Value); err != nil {
+			return nil, err
+		}
+		i, ok := index[targetID]
+		if !ok {
+			if q.ByTarget && q.Limit > 0 && len(series) >= q.Limit {
+				continue
+			}
+			i = len(series)
+			index[targetID] = i
+			series = append(series, AggregateSeries{TargetID: targetID, TargetName: name})
+		}
+		series[i].Points = append(series[i].Points, p)
+	}
+	return series, rows.Err()
+}
+
+// ListNumericFields는 카테고리의 최근 관측값 sample개에서 숫자 값을 가진 최상위 payload 필드를 찾습니다.
+func ListNumericFields(db DBTX, orgID, category string, sample int) ([]string, error) {
+	rows, err := db.Query(
+		`SELECT DISTINCT e.key
+		 FROM (
+			SELECT o.payload FROM ts_obs o
+			JOIN target_categories tc ON tc.target_id = o.target_id AND tc.category_name = o.category_name
+			WHERE tc.org_id::text = $1 AND o.category_name = $2 AND tc.deleted_at IS NULL
+			ORDER BY o.ts DESC
+			LIMIT $3
+		 ) s, jsonb_each(s.payload) e
+		 WHERE jsonb_typeof(s.payload) = 'object' AND jsonb_typeof(e.value) = 'number'
+		 ORDER BY e.key`,
+		orgID, category, sample)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fields []string
+	for rows.Next() {
+		var field string
+		if err := rows.Scan(&field); err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, rows.Err()
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Attachment는 file_attachments 테이블의 Go 표현입니다.
+// 파일 본문은 SeaweedFS의 StoragePath에 저장됩니다.
+type Attachment struct {
+	AttachmentID string    `json:"file_id"`
+	OrgID        string    `json:"-"`
+	TargetID     string    `json:"target_id"`
+	CategoryName string    `json:"category,omitempty"`
+	Filename     string    `json:"filename"`
+	StoragePath  string    `json:"-"`
+	SizeBytes    int64     `json:"file_size"`
+	MimeType     string    `json:"file_type"`
+	UploadedBy   string    `json:"uploaded_by,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewAttachmentID는 저장 경로에 쓸 첨부 파일 ID(UUID v4)를 생성합니다.
+func NewAttachmentID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// TargetExists는 타겟이 존재하는지 확인합니다.
+func TargetExists(db DBTX, targetID string) (bool, error) {
+	var exists bool
+	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM target WHERE target_id::text = $1)", targetID).Scan(&exists)
+	return exists, err
+}
+
+// CreateAttachment는 첨부 파일 메타데이터를 저장합니다.
+func CreateAttachment(db DBTX, a *Attachment) error {
+	return db.QueryRow(
+		`INSERT INTO file_attachments
+			(attachment_id, org_id, target_id, category_name, filename, s3_path, size_bytes, mime_type, uploaded_by)
+		 VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9)
+		 RETURNING created_at`,
+		a.AttachmentID, a.OrgID, a.TargetID, a.CategoryName, a.Filename, a.StoragePath, a.SizeBytes, a.MimeType, a.UploadedBy,
+	).Scan(&a.CreatedAt)
+}
+
+const attachmentColumns = `attachment_id, org_id, target_id, COALESCE(category_name, ''), filename, s3_path,
+	COALESCE(size_bytes, 0), COALESCE(mime_type, ''), COALESCE(uploaded_by, ''), created_at`
+
+func scanAttachment(row interface{ Scan(...interface{}) error }) (*Attachment, error) {
+	var a Attachment
+	err := row.Scan(&a.AttachmentID, &a.OrgID, &a.TargetID, &a.CategoryName, &a.Filename, &a.StoragePath,
+		&a.SizeBytes, &a.MimeType, &a.UploadedBy, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ListAttachments는 조직의 타겟 첨부 파일 목록을 최신순으로 조회합니다.
+// category가 비어 있지 않으면 해당 카테고리의 파일만 반환합니다.
+func ListAttachments(db DBTX, orgID, targetID, category string) ([]Attachment, error) {
+	rows, err := db.Query(
+		`SELECT `+attachmentColumns+`
+		 FROM file_attachments
+		 WHERE org_id = $1 AND target_id::text = $2 AND ($3 = '' OR category_name = $3)
+		 ORDER BY created_at DESC`,
+		orgID, targetID, category,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := []Attachment{}
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, *a)
+	}
+	return attachments, rows.Err()
+}
+
+// GetAttachment는 조직의 타겟 첨부 파일 하나를 조회합니다 (없으면 sql.ErrNoRows).
+func GetAttachment(db DBTX, orgID, targetID, attachmentID string) (*Attachment, error) {
+	return scanAttachment(db.QueryRow(
+		`SELECT `+attachmentColumns+`
+		 FROM file_attachments
+		 WHERE org_id = $1 AND target_id::text = $2 AND attachment_id::text = $3`,
+		orgID, targetID, attachmentID,
+	))
+}
+
+// DeleteAttachment는 첨부 파일 메타데이터를 삭제합니다.
+func DeleteAttachment(db DBTX, orgID, attachmentID string) error {
+	result, err := db.Exec("DELETE FROM file_attachments WHERE org_id = $1 AND attachment_id::text = $2", orgID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
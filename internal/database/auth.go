@@ -30,7 +30,7 @@ func CreateAdminUser(username, password string) (string, error) {
 // AuthenticateUser는 사용자를 인증하고 성공 시 사용자 ID, 조직 ID, 역할을 반환합니다.
 func AuthenticateUser(username, password string) (userID, orgID, role string, err error) {
 	var storedHash string
-	err = DB.QueryRow("SELECT user_id, org_id, password_hash, role FROM users WHERE username = $1 AND is_active = TRUE", username).Scan(&userID, &orgID, &storedHash, &role)
+	err = DB.QueryRow("SELECT user_id, org_id, password_hash, role FROM users WHERE username = $1 AND is_active = TRUE AND auth_provider = 'local'", username).Scan(&userID, &orgID, &storedHash, &role)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return "", "", "", fmt.Errorf("user not found or not active")
@@ -46,6 +46,16 @@ func AuthenticateUser(username, password string) (userID, orgID, role string, er
 	return userID, orgID, role, nil
 }
 
+// IsInstallationAdmin은 사용자가 활성 상태의 설치 관리자(초기 설정에서 만든 관리자)인지 확인합니다.
+// users.role은 조직마다 있으므로 모든 조직에 닿는 작업은 역할 대신 이것으로 허용합니다.
+func IsInstallationAdmin(db DBTX, userID string) (bool, error) {
+	var ok bool
+	err := db.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM users WHERE user_id::text = $1 AND is_installation_admin AND is_active)
+	`, userID).Scan(&ok)
+	return ok, err
+}
+
 // CreateOrgAndAdminUser는 새 조직과 해당 조직의 관리자를 원자적으로 생성합니다.
 func CreateOrgAndAdminUser(orgName, username, password string) (string, error) {
 	tx, err := DB.Begin()
@@ -54,58 +64,66 @@ func CreateOrgAndAdminUser(orgName, username, password string) (string, error) {
 	}
 	defer tx.Rollback() // Rollback on error
 
+	_, _, accessToken, err := createOrgAndAdmin(tx, orgName, username, password)
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return accessToken, nil
+}
+
+// createOrgAndAdmin은 트랜잭션 안에서 조직(없으면), 관리자(없으면), 관리자 API 토큰을 만듭니다.
+func createOrgAndAdmin(tx *sql.Tx, orgName, username, password string) (orgID, userID, accessToken string, err error) {
 	// 1. 조직 생성 (이미 존재하면 ID를 가져옴)
-	var orgID string
 	err = tx.QueryRow(`SELECT org_id FROM organizations WHERE name = $1`, orgName).Scan(&orgID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// 조직이 없으면 새로 생성
 			err = tx.QueryRow(`INSERT INTO organizations (name) VALUES ($1) RETURNING org_id`, orgName).Scan(&orgID)
 			if err != nil {
-				return "", fmt.Errorf("failed to create organization: %w", err)
+				return "", "", "", fmt.Errorf("failed to create organization: %w", err)
 			}
 		} else {
 			// 다른 데이터베이스 오류
-			return "", fmt.Errorf("failed to check for organization: %w", err)
+			return "", "", "", fmt.Errorf("failed to check for organization: %w", err)
 		}
 	}
 
 	// 2. 관리자 사용자 생성 (이미 존재하면 넘어감)
-	var existingUser string
-	err = tx.QueryRow(`SELECT user_id FROM users WHERE org_id = $1 AND username = $2`, orgID, username).Scan(&existingUser)
+	err = tx.QueryRow(`SELECT user_id FROM users WHERE org_id = $1 AND username = $2`, orgID, username).Scan(&userID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// 사용자가 없으면 새로 생성
 			hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 			if err != nil {
-				return "", fmt.Errorf("failed to hash password: %w", err)
+				return "", "", "", fmt.Errorf("failed to hash password: %w", err)
 			}
-			_, err = tx.Exec(`
+			err = tx.QueryRow(`
 				INSERT INTO users (org_id, username, password_hash, role, is_active)
 				VALUES ($1, $2, $3, 'admin', TRUE)
-			`, orgID, username, string(hashedPassword))
+				RETURNING user_id
+			`, orgID, username, string(hashedPassword)).Scan(&userID)
 			if err != nil {
-				return "", fmt.Errorf("failed to create admin user: %w", err)
+				return "", "", "", fmt.Errorf("failed to create admin user: %w", err)
 			}
 		} else {
-			return "", fmt.Errorf("failed to check for admin user: %w", err)
+			return "", "", "", fmt.Errorf("failed to check for admin user: %w", err)
 		}
 	}
 
 	// 3. 관리자용 API 토큰 생성
 	// 참고: 이 부분은 멱등성이 없어서 재실행 시마다 새 토큰을 만들 수 있습니다.
 	// 초기 설정에서는 문제가 되지 않습니다.
-	accessToken, err := GenerateAndSaveAuthToken(tx, orgID, "Initial admin token", true)
+	accessToken, err = GenerateAndSaveAuthToken(tx, orgID, "Initial admin token", true)
 	if err != nil {
-		return "", fmt.Errorf("failed to create admin access token: %w", err)
-	}
-
-	// 4. 트랜잭션 커밋
-	if err := tx.Commit(); err != nil {
-		return "", fmt.Errorf("failed to commit transaction: %w", err)
+		return "", "", "", fmt.Errorf("failed to create admin access token: %w", err)
 	}
 
-	return accessToken, nil
+	return orgID, userID, accessToken, nil
 }
 
 // GenerateAndSaveAuthToken는 새로운 API 토큰을 생성, 암호화, 저장합니다.
@@ -133,9 +151,9 @@ func GenerateAndSaveAuthToken(db DBTX, orgID, description string, isAdmin bool)
 
 	// 4. 데이터베이스에 저장
 	_, err = db.Exec(`
-		INSERT INTO auth_tokens (org_id, encrypted_token, description, permissions, is_admin, is_active)
-		VALUES ($1, $2, $3, $4, $5, TRUE)
-	`, orgID, encryptedToken, description, permissions, isAdmin)
+		INSERT INTO auth_tokens (org_id, encrypted_token, token_hash, description, permissions, is_admin, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, TRUE)
+	`, orgID, encryptedToken, hashToken(tokenString), description, permissions, isAdmin)
 	if err != nil {
 		return "", fmt.Errorf("could not save token to database: %w", err)
 	}
@@ -143,22 +161,6 @@ func GenerateAndSaveAuthToken(db DBTX, orgID, description string, isAdmin bool)
 	return tokenString, nil
 }
 
-// AuthenticateToken은 제공된 토큰이 유효한지 확인하고 권한을 반환합니다.
-func AuthenticateToken(tokenString string) (bool, map[string]interface{}, error) {
-	// 토큰을 해싱하여 저장된 값과 비교하는 로직 필요
-	// 현재는 임시로 true를 반환
-	// TODO: 실제 토큰 인증 로직 구현
-	var storedHash string
-	var permissions map[string]interface{}
-	// SELECT token_hash, permissions FROM auth_tokens WHERE ...
-	err := DB.QueryRow("SELECT ...").Scan(&storedHash, &permissions)
-	if err != nil {
-		return false, nil, err
-	}
-	// ... 해시 비교 ...
-	return true, permissions, nil
-}
-
 // GetAuthTokens는 특정 조직의 모든 인증 토큰을 조회합니다.
 func GetAuthTokens(orgID string) ([]AuthToken, error) {
 	rows, err := DB.Query(`
@@ -223,7 +225,7 @@ func GenerateSessionToken() (string, error) {
 	return hex.EncodeToString(b), nil
 }
 
-// Legacy - Hashing related functions, might be removed later
+// hashToken은 토큰 조회에 쓰는 SHA-256 해시를 반환합니다
 func hashToken(token string) string {
 	hash := sha256.Sum256([]byte(token))
 	return hex.EncodeToString(hash[:])
@@ -231,19 +233,21 @@ func hashToken(token string) string {
 
 // User represents a user in the system.
 type User struct {
-	UserID    string    `json:"user_id"`
-	OrgID     string    `json:"org_id"`
-	Username  string    `json:"username"`
-	Password  string    `json:"password,omitempty"`
-	Role      string    `json:"role"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	UserID       string           `json:"user_id"`
+	OrgID        string           `json:"org_id"`
+	Username     string           `json:"username"`
+	Password     string           `json:"password,omitempty"`
+	Role         string           `json:"role"`
+	IsActive     bool             `json:"is_active"`
+	Permissions  TokenPermissions `json:"permissions"`   // 사용자 토큰의 데이터 API 카테고리 권한
+	AuthProvider string           `json:"auth_provider"` // 'local' 또는 SSO 발급자 URL
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
 }
 
 // GetUsers는 특정 조직의 모든 사용자를 조회합니다.
 func GetUsers(orgID string) ([]User, error) {
-	rows, err := DB.Query("SELECT user_id, org_id, username, role, is_active, created_at, updated_at FROM users WHERE org_id = $1 ORDER BY created_at DESC", orgID)
+	rows, err := DB.Query("SELECT user_id, org_id, username, role, is_active, permissions, auth_provider, created_at, updated_at FROM users WHERE org_id = $1 ORDER BY created_at DESC", orgID)
 	if err != nil {
 		return nil, err
 	}
@@ -252,9 +256,11 @@ func GetUsers(orgID string) ([]User, error) {
 	var users []User
 	for rows.Next() {
 		var u User
-		if err := rows.Scan(&u.UserID, &u.OrgID, &u.Username, &u.Role, &u.IsActive, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		var permissions []byte
+		if err := rows.Scan(&u.UserID, &u.OrgID, &u.Username, &u.Role, &u.IsActive, &permissions, &u.AuthProvider, &u.CreatedAt, &u.UpdatedAt); err != nil {
 			return nil, err
 		}
+		u.Permissions, _ = parsePermissions(permissions, false)
 		users = append(users, u)
 	}
 	return users, nil
@@ -268,8 +274,8 @@ func CreateUser(user User) (*User, error) {
 	}
 
 	err = DB.QueryRow(
-		"INSERT INTO users (org_id, username, password_hash, role, is_active) VALUES ($1, $2, $3, $4, $5) RETURNING user_id, created_at, updated_at",
-		user.OrgID, user.Username, string(hashedPassword), user.Role, user.IsActive,
+		"INSERT INTO users (org_id, username, password_hash, role, is_active, permissions) VALUES ($1, $2, $3, $4, $5, $6) RETURNING user_id, created_at, updated_at",
+		user.OrgID, user.Username, string(hashedPassword), user.Role, user.IsActive, user.Permissions.JSON(),
 	).Scan(&user.UserID, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
@@ -288,8 +294,8 @@ func UpdateUser(user User) (*User, error) {
 			return nil, fmt.Errorf("failed to hash password: %w", err)
 		}
 		_, err = DB.Exec(
-			"UPDATE users SET role = $1, is_active = $2, password_hash = $3, updated_at = NOW() WHERE user_id = $4 AND org_id = $5",
-			user.Role, user.IsActive, string(hashedPassword), user.UserID, user.OrgID,
+			"UPDATE users SET role = $1, is_active = $2, password_hash = $3, permissions = $4, updated_at = NOW() WHERE user_id = $5 AND org_id = $6",
+			user.Role, user.IsActive, string(hashedPassword), user.Permissions.JSON(), user.UserID, user.OrgID,
 		)
 		if err != nil {
 			return nil, err
@@ -297,8 +303,8 @@ func UpdateUser(user User) (*User, error) {
 	} else {
 		// 비밀번호 변경이 없는 경우
 		_, err := DB.Exec(
-			"UPDATE users SET role = $1, is_active = $2, updated_at = NOW() WHERE user_id = $3 AND org_id = $4",
-			user.Role, user.IsActive, user.UserID, user.OrgID,
+			"UPDATE users SET role = $1, is_active = $2, permissions = $3, updated_at = NOW() WHERE user_id = $4 AND org_id = $5",
+			user.Role, user.IsActive, user.Permissions.JSON(), user.UserID, user.OrgID,
 		)
 		if err != nil {
 			return nil, err
@@ -307,14 +313,16 @@ func UpdateUser(user User) (*User, error) {
 
 	// 업데이트된 사용자 정보를 다시 조회하여 반환합니다.
 	var updatedUser User
-	err := DB.QueryRow("SELECT user_id, org_id, username, role, is_active, created_at, updated_at FROM users WHERE user_id = $1", user.UserID).Scan(
-		&updatedUser.UserID, &updatedUser.OrgID, &updatedUser.Username, &updatedUser.Role, &updatedUser.IsActive, &updatedUser.CreatedAt, &updatedUser.UpdatedAt,
+	var permissions []byte
+	err := DB.QueryRow("SELECT user_id, org_id, username, role, is_active, permissions, created_at, updated_at FROM users WHERE user_id = $1", user.UserID).Scan(
+		&updatedUser.UserID, &updatedUser.OrgID, &updatedUser.Username, &updatedUser.Role, &updatedUser.IsActive, &permissions, &updatedUser.CreatedAt, &updatedUser.UpdatedAt,
 	)
 	if err != nil {
 		// 조회 실패 시에도 최소한의 정보로 응답할 수 있도록 user 객체를 반환할 수 있지만,
 		// 일관성을 위해 오류를 반환합니다.
 		return nil, fmt.Errorf("failed to retrieve updated user data: %w", err)
 	}
+	updatedUser.Permissions, _ = parsePermissions(permissions, false)
 
 	return &updatedUser, nil
 }
@@ -360,7 +368,7 @@ func CreateUserToken(userID, orgID, description string) (string, *AuthToken, err
 // GetUserTokens는 특정 사용자의 모든 활성 액세스 토큰을 조회합니다.
 func GetUserTokens(userID, orgID string) ([]AuthToken, error) {
 	rows, err := DB.Query(`
-		SELECT token_id, user_id, org_id, description, is_active, created_at 
+		SELECT token_id, user_id, org_id, description, is_active, expires_at, created_at
 		FROM user_access_tokens 
 		WHERE user_id = $1 AND org_id = $2
 		ORDER BY created_at DESC
@@ -375,7 +383,7 @@ func GetUserTokens(userID, orgID string) ([]AuthToken, error) {
 // GetAllUserTokens는 특정 조직의 모든 사용자의 활성 액세스 토큰을 조회합니다. (관리자용)
 func GetAllUserTokens(orgID string) ([]AuthToken, error) {
 	rows, err := DB.Query(`
-		SELECT token_id, user_id, org_id, description, is_active, created_at 
+		SELECT token_id, user_id, org_id, description, is_active, expires_at, created_at
 		FROM user_access_tokens 
 		WHERE org_id = $1
 		ORDER BY created_at DESC
@@ -430,6 +438,7 @@ func scanTokens(rows *sql.Rows) ([]AuthToken, error) {
 			&token.OrgID,
 			&token.Description,
 			&token.IsActive,
+			&token.ExpiresAt,
 			&token.CreatedAt,
 		); err != nil {
 			log.Printf("Error scanning token row: %v\n", err)
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ChangeNotifyChannel 변경 이벤트가 기록될 때 pg_notify로 알리는 채널
+const ChangeNotifyChannel = "tmidb_changes"
+
+// changeCaptureSQL 트리거 기반 아웃박스 (target, target_categories, ts_obs 변경 기록)
+//
+// ts_obs는 보관 정책에 따른 대량 삭제가 이벤트로 쏟아지지 않도록 INSERT/UPDATE만 기록합니다.
+// 같은 트랜잭션의 동일한 알림은 PostgreSQL이 하나로 합치므로 대량 쓰기에도 알림은 한 번입니다.
+const changeCaptureSQL = `
+CREATE TABLE IF NOT EXISTS public.change_events (
+    event_id BIGSERIAL PRIMARY KEY,
+    table_name TEXT NOT NULL,
+    operation TEXT NOT NULL,
+    target_id UUID,
+    category_name TEXT,
+    org_id TEXT,
+    row_data JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    published_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_change_events_pending ON public.change_events (event_id) WHERE published_at IS NULL;
+
+CREATE OR REPLACE FUNCTION tmidb_capture_change() RETURNS TRIGGER AS $$
+DECLARE
+    rec JSONB;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := to_jsonb(OLD);
+    ELSE
+        rec := to_jsonb(NEW);
+        IF TG_OP = 'UPDATE' AND rec = to_jsonb(OLD) THEN
+            RETURN NULL; -- 값이 바뀌지 않은 UPDATE는 기록하지 않음
+        END IF;
+    END IF;
+
+    INSERT INTO public.change_events (table_name, operation, target_id, category_name, org_id, row_data)
+    VALUES (TG_TABLE_NAME, lower(TG_OP), (rec->>'target_id')::uuid, rec->>'category_name', rec->>'org_id', rec);
+
+    PERFORM pg_notify('` + ChangeNotifyChannel + `', TG_TABLE_NAME);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS tmidb_cdc ON public.target;
+CREATE TRIGGER tmidb_cdc AFTER INSERT OR UPDATE OR DELETE ON public.target
+    FOR EACH ROW EXECUTE FUNCTION tmidb_capture_change();
+
+DROP TRIGGER IF EXISTS tmidb_cdc ON public.target_categories;
+CREATE TRIGGER tmidb_cdc AFTER INSERT OR UPDATE OR DELETE ON public.target_categories
+    FOR EACH ROW EXECUTE FUNCTION tmidb_capture_change();
+
+DROP TRIGGER IF EXISTS tmidb_cdc ON public.ts_obs;
+CREATE TRIGGER tmidb_cdc AFTER INSERT OR UPDATE ON public.ts_obs
+    FOR EACH ROW EXECUTE FUNCTION tmidb_capture_change();
+`
+
+// ChangeEvent는 change_events 아웃박스의 행이자 NATS로 발행하는 변경 이벤트입니다.
+type ChangeEvent struct {
+	EventID   int64           `json:"event_id"`
+	Table     string          `json:"table"`
+	Operation string          `json:"operation"` // insert, update, delete
+	TargetID  string          `json:"target_id,omitempty"`
+	Category  string          `json:"category,omitempty"`
+	OrgID     string          `json:"org_id,omitempty"`
+	Row       json.RawMessage `json:"row,omitempty"` // 변경 후 행 (delete면 삭제된 행)
+	ChangedAt time.Time       `json:"changed_at"`
+}
+
+// ClaimChangeEvents는 발행되지 않은 변경 이벤트를 순서대로 잠그고 가져옵니다.
+// 다른 발행자가 잠근 행은 건너뛰므로 여러 인스턴스가 동시에 실행할 수 있습니다.
+func ClaimChangeEvents(tx *sql.Tx, limit int) ([]ChangeEvent, error) {
+	rows, err := tx.Query(
+		`SELECT event_id, table_name, operation, COALESCE(target_id::text, ''), COALESCE(category_name, ''),
+		        COALESCE(org_id, ''), COALESCE(row_data, 'null'::jsonb), created_at
+		 FROM change_events
+		 WHERE published_at IS NULL
+		 ORDER BY event_id
+		 LIMIT $1
+		 FOR UPDATE SKIP LOCKED`,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []ChangeEvent
+	for rows.Next() {
+		var e ChangeEvent
+		var row []byte
+		if err := rows.Scan(&e.EventID, &e.Table, &e.Operation, &e.TargetID, &e.Category, &e.OrgID, &row, &e.ChangedAt); err != nil {
+			return nil, err
+		}
+		if string(row) != "null" {
+			e.Row = row
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// MarkChangeEventsPublished는 이벤트를 발행 완료로 표시합니다
+func MarkChangeEventsPublished(tx *sql.Tx, eventIDs []int64) error {
+	_, err := tx.Exec("UPDATE change_events SET published_at = now() WHERE event_id = ANY($1)", pq.Array(eventIDs))
+	return err
+}
+
+// PurgeChangeEvents는 발행된 지 olderThan이 지난 이벤트를 삭제합니다
+func PurgeChangeEvents(db DBTX, olderThan time.Duration) (int64, error) {
+	result, err := db.Exec(
+		"DELETE FROM change_events WHERE published_at IS NOT NULL AND published_at < now() - make_interval(secs => $1)",
+		olderThan.Seconds(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
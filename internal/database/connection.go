@@ -4,12 +4,13 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/tmidb/tmidb-core/internal/config" // config 패키지 임포트
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 // 전역 DB 인스턴스
@@ -54,6 +55,9 @@ func setupDatabaseAndUser(cfg *config.Config) error {
 		return fmt.Errorf("failed to ping admin database: %v", err)
 	}
 
+	// 식별자와 비밀번호는 DDL에 매개변수를 쓸 수 없으므로 인용해서 넣음
+	dbName, user := pq.QuoteIdentifier(cfg.PostgresDBName), pq.QuoteIdentifier(cfg.TmiDBUser)
+
 	// tmiDB 데이터베이스 생성 (존재하지 않는 경우)
 	_, err = adminDB.Exec(fmt.Sprintf(`
 		CREATE DATABASE %s
@@ -61,7 +65,7 @@ func setupDatabaseAndUser(cfg *config.Config) error {
 		LC_COLLATE = 'en_US.utf8'
 		LC_CTYPE = 'en_US.utf8'
 		TEMPLATE = template0
-	`, cfg.PostgresDBName))
+	`, dbName))
 	if err != nil && !strings.Contains(err.Error(), "already exists") {
 		return fmt.Errorf("failed to create database: %v", err)
 	}
@@ -70,11 +74,11 @@ func setupDatabaseAndUser(cfg *config.Config) error {
 	_, err = adminDB.Exec(fmt.Sprintf(`
 		DO $$ 
 		BEGIN
-			IF NOT EXISTS (SELECT FROM pg_catalog.pg_roles WHERE rolname = '%s') THEN
-				CREATE USER %s WITH PASSWORD '%s';
+			IF NOT EXISTS (SELECT FROM pg_catalog.pg_roles WHERE rolname = %s) THEN
+				CREATE USER %s WITH PASSWORD %s;
 			END IF;
 		END $$
-	`, cfg.TmiDBUser, cfg.TmiDBUser, cfg.TmiDBPassword))
+	`, pq.QuoteLiteral(cfg.TmiDBUser), user, pq.QuoteLiteral(cfg.TmiDBPassword)))
 	if err != nil {
 		return fmt.Errorf("failed to create tmiDB user: %v", err)
 	}
@@ -83,7 +87,7 @@ func setupDatabaseAndUser(cfg *config.Config) error {
 	_, err = adminDB.Exec(fmt.Sprintf(`
 		GRANT ALL PRIVILEGES ON DATABASE %s TO %s;
 		ALTER USER %s CREATEDB;
-	`, cfg.PostgresDBName, cfg.TmiDBUser, cfg.TmiDBUser))
+	`, dbName, user, user))
 	if err != nil {
 		return fmt.Errorf("failed to grant database privileges: %v", err)
 	}
@@ -105,7 +109,7 @@ func setupDatabaseAndUser(cfg *config.Config) error {
 		GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA public TO %s;
 		ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT ALL ON TABLES TO %s;
 		ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT ALL ON SEQUENCES TO %s;
-	`, cfg.TmiDBUser, cfg.TmiDBUser, cfg.TmiDBUser, cfg.TmiDBUser, cfg.TmiDBUser))
+	`, user, user, user, user, user))
 	if err != nil {
 		return fmt.Errorf("failed to grant privileges: %v", err)
 	}
@@ -117,7 +121,7 @@ func setupDatabaseAndUser(cfg *config.Config) error {
 // connectAsTmiDBUser는 tmiDB 전용 사용자로 연결합니다.
 func connectAsTmiDBUser(cfg *config.Config) error {
 	var err error
-	DB, err = sql.Open("postgres", cfg.DatabaseURL)
+	DB, err = openDB(cfg.DatabaseURL)
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %v", err)
 	}
@@ -143,14 +147,22 @@ func initializeSchema() error {
 
 // CloseDatabase는 데이터베이스 연결을 종료합니다.
 func CloseDatabase() error {
+	closeReplicas()
 	if DB != nil {
 		return DB.Close()
 	}
 	return nil
 }
 
+// functionNamePattern 실행할 수 있는 함수 이름 (스키마 이름 포함 가능)
+var functionNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)?$`)
+
 // ExecuteFunction은 데이터베이스 함수를 실행하는 헬퍼 함수입니다.
+// 함수 이름은 매개변수로 넘길 수 없으므로 식별자 형식만 허용합니다.
 func ExecuteFunction(functionName string, args ...interface{}) (*sql.Rows, error) {
+	if !functionNamePattern.MatchString(functionName) {
+		return nil, fmt.Errorf("invalid function name %q", functionName)
+	}
 	placeholders := make([]string, len(args))
 	for i := range args {
 		placeholders[i] = fmt.Sprintf("$%d", i+1)
@@ -185,7 +197,7 @@ func ConnectDatabase(cfg *config.Config) error {
 	maxRetries := 30
 	for i := 0; i < maxRetries; i++ {
 		var err error
-		DB, err = sql.Open("postgres", cfg.DatabaseURL)
+		DB, err = openDB(cfg.DatabaseURL)
 		if err != nil {
 			log.Printf("⏳ Failed to open database connection (attempt %d/%d): %v", i+1, maxRetries, err)
 			time.Sleep(1 * time.Second)
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// 수집 중복 제거 설정
+const (
+	MaxEventIDLength         = 200              // 클라이언트 event_id 최대 길이
+	IngestDedupPurgeInterval = 10 * time.Minute // 만료된 키를 지우는 주기
+)
+
+// ingestDedupWindow 이 프로세스의 중복 제거 기간 (0이면 끔)
+var ingestDedupWindow atomic.Int64
+
+// IngestDedupWindow는 이 프로세스의 중복 제거 기간을 반환합니다 (0이면 꺼짐)
+func IngestDedupWindow() time.Duration {
+	return time.Duration(ingestDedupWindow.Load())
+}
+
+// StartIngestDedup은 이 프로세스의 수집 중복 제거를 켜고, 기간이 지난 키를 주기적으로 지웁니다.
+// window가 0 이하면 아무것도 하지 않습니다 (ClaimIngestEvent가 항상 true).
+func StartIngestDedup(ctx context.Context, db *sql.DB, window time.Duration) {
+	if window <= 0 || db == nil {
+		return
+	}
+	ingestDedupWindow.Store(int64(window))
+	log.Printf("🔁 Ingest deduplication enabled (window %s)", window)
+
+	go func() {
+		ticker := time.NewTicker(IngestDedupPurgeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if n, err := PurgeIngestEvents(db, time.Now().Add(-window)); err != nil {
+				log.Printf("⚠️ 만료된 중복 제거 키 정리 실패: %v", err)
+			} else if n > 0 {
+				log.Printf("🔁 만료된 중복 제거 키 %d개를 지웠습니다", n)
+			}
+		}
+	}()
+}
+
+// IngestEventKey는 관측값의 중복 제거 키를 만듭니다.
+// event_id가 있으면 그 값을, 없고 클라이언트가 시각을 정했으면 target_id, ts, payload의 SHA-256을 씁니다.
+// 둘 다 없으면 빈 문자열입니다 (수신 시각을 쓰는 레코드는 재시도마다 시각이 달라 해시로 구별할 수 없음).
+// payloadJSON은 키 순서가 정해진 JSON이어야 같은 값이 같은 키가 됩니다 (json.Marshal 결과).
+func IngestEventKey(eventID, targetID string, clientTs time.Time, payloadJSON []byte) string {
+	if eventID != "" {
+		return "id:" + eventID
+	}
+	if clientTs.IsZero() {
+		return ""
+	}
+	h := sha256.New()
+	h.Write([]byte(targetID))
+	h.Write([]byte{0})
+	h.Write([]byte(clientTs.UTC().Format(time.RFC3339Nano)))
+	h.Write([]byte{0})
+	h.Write(payloadJSON)
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// ClaimIngestEvent는 중복 제거 키를 기록하고, 처음 보는 키면 true를 반환합니다.
+// 같은 키가 중복 제거 기간 안에 이미 기록돼 있으면 false이므로 관측값을 저장하지 말아야 합니다.
+// 관측값 저장과 같은 트랜잭션(세이브포인트)에서 호출해야 저장이 실패했을 때 키도 함께 취소됩니다.
+// 키가 비어 있거나 중복 제거가 꺼져 있으면 항상 true입니다.
+func ClaimIngestEvent(db DBTX, targetID, category, key string) (bool, error) {
+	window := IngestDedupWindow()
+	if key == "" || window <= 0 {
+		return true, nil
+	}
+	var claimed bool
+	err := db.QueryRow(
+		`INSERT INTO ingest_dedup (target_id, category_name, event_key, seen_at)
+		 VALUES ($1, $2, $3, now())
+		 ON CONFLICT (target_id, category_name, event_key) DO UPDATE SET
+			seen_at = EXCLUDED.seen_at
+		 WHERE ingest_dedup.seen_at < EXCLUDED.seen_at - make_interval(secs => $4)
+		 RETURNING true`,
+		targetID, category, key, window.Seconds(),
+	).Scan(&claimed)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return claimed, nil
+}
+
+// PurgeIngestEvents는 before보다 먼저 기록된 중복 제거 키를 지우고 지운 수를 반환합니다
+func PurgeIngestEvents(db DBTX, before time.Time) (int64, error) {
+	result, err := db.Exec("DELETE FROM ingest_dedup WHERE seen_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,37 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIngestEventKey(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	payload := []byte(`{"temp":21.5}`)
+
+	if got := IngestEventKey("gw-1:42", "t1", ts, payload); got != "id:gw-1:42" {
+		t.Errorf("event id key = %q", got)
+	}
+	if got := IngestEventKey("", "t1", time.Time{}, payload); got != "" {
+		t.Errorf("expected no key without event id and client ts, got %q", got)
+	}
+
+	key := IngestEventKey("", "t1", ts, payload)
+	if !strings.HasPrefix(key, "sha256:") || len(key) != len("sha256:")+64 {
+		t.Fatalf("hash key = %q", key)
+	}
+	// 같은 시각을 다른 시간대로 보내도 같은 키
+	if got := IngestEventKey("", "t1", ts.In(time.FixedZone("KST", 9*3600)), payload); got != key {
+		t.Errorf("time zone changed the key: %q != %q", got, key)
+	}
+	for _, other := range []string{
+		IngestEventKey("", "t2", ts, payload),
+		IngestEventKey("", "t1", ts.Add(time.Millisecond), payload),
+		IngestEventKey("", "t1", ts, []byte(`{"temp":21.6}`)),
+	} {
+		if other == key {
+			t.Errorf("different record produced the same key %q", key)
+		}
+	}
+}
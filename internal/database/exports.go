@@ -0,0 +1,133 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// 내보내기 작업 상태
+const (
+	ExportStatusQueued    = "queued"
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+)
+
+// ErrExportJobNotFound는 조직에 없는 내보내기 작업을 조회할 때 반환됩니다
+var ErrExportJobNotFound = errors.New("export job not found")
+
+// ExportJob은 비동기 카테고리 내보내기 작업입니다. 결과 파일은 SeaweedFS의 StoragePath에 저장됩니다.
+type ExportJob struct {
+	JobID        string                 `json:"job_id"`
+	OrgID        string                 `json:"-"`
+	Category     string                 `json:"category"`
+	Format       string                 `json:"format"`
+	Parameters   map[string]interface{} `json:"parameters,omitempty"`
+	Status       string                 `json:"status"`
+	RowsExported int64                  `json:"rows_exported"`
+	SizeBytes    int64                  `json:"size_bytes"`
+	StoragePath  string                 `json:"-"`
+	Error        string                 `json:"error,omitempty"`
+	CreatedBy    string                 `json:"created_by,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	StartedAt    *time.Time             `json:"started_at,omitempty"`
+	FinishedAt   *time.Time             `json:"finished_at,omitempty"`
+	ExpiresAt    *time.Time             `json:"expires_at,omitempty"` // 이 시각 이후 작업과 파일이 삭제됨
+}
+
+// CreateExportJob은 대기 상태의 내보내기 작업을 만들고 job의 ID, 상태, 생성 시각을 채웁니다
+func CreateExportJob(db DBTX, job *ExportJob) error {
+	var params interface{}
+	if len(job.Parameters) > 0 {
+		encoded, err := json.Marshal(job.Parameters)
+		if err != nil {
+			return err
+		}
+		params = string(encoded)
+	}
+	return db.QueryRow(`
+		INSERT INTO export_jobs (org_id, category_name, format, parameters, created_by)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		RETURNING job_id::text, status, created_at
+	`, job.OrgID, job.Category, job.Format, params, job.CreatedBy).Scan(&job.JobID, &job.Status, &job.CreatedAt)
+}
+
+// GetExportJob은 조직의 내보내기 작업을 조회합니다
+func GetExportJob(db DBTX, orgID, jobID string) (*ExportJob, error) {
+	var job ExportJob
+	var params []byte
+	var storagePath, jobErr, createdBy sql.NullString
+	var startedAt, finishedAt, expiresAt sql.NullTime
+	err := db.QueryRow(`
+		SELECT job_id::text, org_id, category_name, format, parameters, status, rows_exported, size_bytes,
+		       storage_path, error, created_by, created_at, started_at, finished_at, expires_at
+		FROM export_jobs
+		WHERE org_id = $1 AND job_id::text = $2
+	`, orgID, jobID).Scan(&job.JobID, &job.OrgID, &job.Category, &job.Format, &params, &job.Status,
+		&job.RowsExported, &job.SizeBytes, &storagePath, &jobErr, &createdBy, &job.CreatedAt,
+		&startedAt, &finishedAt, &expiresAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrExportJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(params) > 0 {
+		json.Unmarshal(params, &job.Parameters)
+	}
+	job.StoragePath, job.Error, job.CreatedBy = storagePath.String, jobErr.String, createdBy.String
+	job.StartedAt, job.FinishedAt, job.ExpiresAt = nullTimePtr(startedAt), nullTimePtr(finishedAt), nullTimePtr(expiresAt)
+	return &job, nil
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// StartExportJob은 작업을 실행 중으로 표시합니다
+func StartExportJob(db DBTX, jobID string) error {
+	_, err := db.Exec(`
+		UPDATE export_jobs SET status = 'running', started_at = now()
+		WHERE job_id::text = $1
+	`, jobID)
+	return err
+}
+
+// FinishExportJob은 작업 결과를 기록합니다. jobErr가 있으면 실패로, 없으면 완료로 표시하고
+// retention 뒤에 ExpireExportJobs가 작업과 파일을 삭제하도록 만료 시각을 정합니다.
+func FinishExportJob(db DBTX, jobID string, rows, size int64, storagePath string, retention time.Duration, jobErr error) error {
+	status, message := ExportStatusCompleted, ""
+	if jobErr != nil {
+		status, message, storagePath = ExportStatusFailed, jobErr.Error(), ""
+	}
+	_, err := db.Exec(`
+		UPDATE export_jobs
+		SET status = $2, rows_exported = $3, size_bytes = $4, storage_path = NULLIF($5, ''), error = NULLIF($6, ''),
+		    finished_at = now(), expires_at = now() + make_interval(secs => $7)
+		WHERE job_id::text = $1
+	`, jobID, status, rows, size, storagePath, message, retention.Seconds())
+	return err
+}
+
+// ExpireExportJobs는 만료된 작업을 삭제하고 파일 저장소에서 지울 결과 파일 경로를 반환합니다.
+// staleAfter보다 오래 끝나지 않은 작업은 API 서버가 재시작되어 멈춘 것으로 보고 실패로 표시합니다.
+func ExpireExportJobs(db DBTX, staleAfter, retention time.Duration) ([]string, error) {
+	_, err := db.Exec(`
+		UPDATE export_jobs
+		SET status = 'failed', error = 'export was interrupted', finished_at = now(),
+		    expires_at = now() + make_interval(secs => $2)
+		WHERE status IN ('queued', 'running') AND created_at < now() - make_interval(secs => $1)
+	`, staleAfter.Seconds(), retention.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	return queryStrings(db, `
+		WITH expired AS (DELETE FROM export_jobs WHERE expires_at < now() RETURNING storage_path)
+		SELECT storage_path FROM expired WHERE storage_path IS NOT NULL
+	`)
+}
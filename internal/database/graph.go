@@ -0,0 +1,179 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Target는 타겟 한 건입니다
+type Target struct {
+	TargetID   string
+	Name       string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ArchivedAt *time.Time
+}
+
+// CategoryDocument는 타겟의 카테고리 문서 한 건입니다
+type CategoryDocument struct {
+	TargetID      string
+	Category      string
+	SchemaVersion int
+	Data          map[string]interface{}
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Observation은 ts_obs의 관측값 한 건입니다
+type Observation struct {
+	TargetID string
+	Category string
+	Time     time.Time
+	Payload  interface{}
+}
+
+// ListOrgCategoryNames는 조직의 카테고리 이름을 조회합니다
+func ListOrgCategoryNames(db DBTX, orgID string) ([]string, error) {
+	rows, err := db.Query(
+		"SELECT DISTINCT category_name FROM category_schemas WHERE org_id::text = $1 ORDER BY category_name", orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// ListTargets는 조직의 카테고리 중 하나 이상에 연결된 타겟을 이름 순으로 조회합니다.
+// 타겟 테이블에는 조직이 없으므로 target_categories로 조직과 카테고리 범위를 정합니다.
+// 보관된 타겟은 includeArchived일 때만 포함합니다.
+func ListTargets(db DBTX, orgID string, categories []string, includeArchived bool, limit, offset int) ([]Target, error) {
+	rows, err := db.Query(
+		`SELECT `+targetColumns+`
+		 FROM target t
+		 WHERE EXISTS (
+		   SELECT 1 FROM target_categories tc
+		   WHERE tc.target_id = t.target_id AND tc.org_id::text = $1 AND tc.category_name = ANY($2) AND tc.deleted_at IS NULL)
+		   AND ($5 OR t.archived_at IS NULL)
+		 ORDER BY t.name, t.target_id
+		 LIMIT $3 OFFSET $4`,
+		orgID, pq.Array(categories), limit, offset, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var targets []Target
+	for rows.Next() {
+		t, err := scanTarget(rows)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, *t)
+	}
+	return targets, rows.Err()
+}
+
+const targetColumns = "t.target_id::text, t.name, t.created_at, t.updated_at, t.archived_at"
+
+func scanTarget(row interface{ Scan(...interface{}) error }) (*Target, error) {
+	var t Target
+	var archivedAt sql.NullTime
+	if err := row.Scan(&t.TargetID, &t.Name, &t.CreatedAt, &t.UpdatedAt, &archivedAt); err != nil {
+		return nil, err
+	}
+	if archivedAt.Valid {
+		t.ArchivedAt = &archivedAt.Time
+	}
+	return &t, nil
+}
+
+// GetTarget은 조직의 카테고리에 연결된 타겟 하나를 조회합니다. 보관된 타겟도 ID로는 조회됩니다 (없으면 sql.ErrNoRows).
+func GetTarget(db DBTX, orgID, targetID string, categories []string) (*Target, error) {
+	return scanTarget(db.QueryRow(
+		`SELECT `+targetColumns+`
+		 FROM target t
+		 WHERE t.target_id::text = $1 AND EXISTS (
+		   SELECT 1 FROM target_categories tc
+		   WHERE tc.target_id = t.target_id AND tc.org_id::text = $2 AND tc.category_name = ANY($3) AND tc.deleted_at IS NULL)`,
+		targetID, orgID, pq.Array(categories)))
+}
+
+// ListTargetCategoryDocuments는 타겟의 카테고리 문서를 카테고리 이름 순으로 조회합니다
+func ListTargetCategoryDocuments(db DBTX, orgID, targetID string, categories []string) ([]CategoryDocument, error) {
+	rows, err := db.Query(
+		`SELECT target_id::text, category_name, schema_version, category_data::text, created_at, updated_at
+		 FROM target_categories
+		 WHERE org_id::text = $1 AND target_id::text = $2 AND category_name = ANY($3) AND deleted_at IS NULL
+		 ORDER BY category_name`,
+		orgID, targetID, pq.Array(categories))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []CategoryDocument
+	for rows.Next() {
+		var doc CategoryDocument
+		var dataJSON string
+		if err := rows.Scan(&doc.TargetID, &doc.Category, &doc.SchemaVersion, &dataJSON, &doc.CreatedAt, &doc.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(dataJSON), &doc.Data); err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
+// ListObservations는 타겟 카테고리의 관측값을 최신 순으로 조회합니다 (since, until은 nil이면 제한 없음).
+// 타겟이 조직의 카테고리에 연결되어 있어야 합니다.
+func ListObservations(db DBTX, orgID, targetID, category string, since, until *time.Time, limit int) ([]Observation, error) {
+	rows, err := db.Query(
+		`SELECT o.ts, o.payload::text
+		 FROM ts_obs o
+		 JOIN target_categories tc ON tc.target_id = o.target_id AND tc.category_name = o.category_name
+		 WHERE tc.org_id::text = $1 AND o.target_id::text = $2 AND o.category_name = $3 AND tc.deleted_at IS NULL
+		   AND ($4::timestamptz IS NULL OR o.ts >= $4)
+		   AND ($5::timestamptz IS NULL OR o.ts < $5)
+		 ORDER BY o.ts DESC
+		 LIMIT $6`,
+		orgID, targetID, category, nullTime(since), nullTime(until), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var observations []Observation
+	for rows.Next() {
+		obs := Observation{TargetID: targetID, Category: category}
+		var payloadJSON string
+		if err := rows.Scan(&obs.Time, &payloadJSON); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(payloadJSON), &obs.Payload); err != nil {
+			return nil, err
+		}
+		observations = append(observations, obs)
+	}
+	return observations, rows.Err()
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
@@ -0,0 +1,274 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 문서 이력 작업
+const (
+	HistoryInsert  = "insert"
+	HistoryUpdate  = "update"
+	HistoryDelete  = "delete"  // 삭제 또는 휴지통으로 보냄
+	HistoryRestore = "restore" // 휴지통에서 복원
+)
+
+// documentHistorySQL 카테고리 문서 이력 (target_categories를 바꿀 때마다 트리거가 리비전을 올리고 기록)
+//
+// 이력은 타겟이 삭제돼도 남으므로 같은 타겟-카테고리를 다시 만들면 리비전이 이어집니다.
+// 누가 바꿨는지는 트랜잭션의 tmidb.actor 설정(SetChangeActor)을 쓰고, 없으면 데이터베이스 사용자입니다.
+// 휴지통에 있던 문서를 영구 삭제할 때는 이미 delete가 기록돼 있으므로 다시 기록하지 않습니다.
+const documentHistorySQL = `
+ALTER TABLE public.target_categories ADD COLUMN IF NOT EXISTS revision INTEGER NOT NULL DEFAULT 1;
+
+-- 처음 만들 때 지금 문서를 첫 리비전으로 채움
+DO $$
+BEGIN
+    IF to_regclass('public.target_category_history') IS NULL THEN
+        CREATE TABLE public.target_category_history (
+            target_id UUID NOT NULL,
+            category_name TEXT NOT NULL,
+            revision INTEGER NOT NULL,
+            org_id UUID NOT NULL,
+            operation TEXT NOT NULL,
+            schema_version INTEGER NOT NULL,
+            category_data JSONB NOT NULL,
+            changed_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp(),
+            changed_by TEXT NOT NULL,
+            PRIMARY KEY (target_id, category_name, revision)
+        );
+        INSERT INTO public.target_category_history
+            (target_id, category_name, revision, org_id, operation, schema_version, category_data, changed_at, changed_by)
+        SELECT target_id, category_name, revision, org_id,
+               CASE WHEN deleted_at IS NULL THEN 'insert' ELSE 'delete' END,
+               schema_version, category_data, COALESCE(deleted_at, updated_at), COALESCE(deleted_by, current_user)
+        FROM public.target_categories;
+    END IF;
+END $$;
+CREATE INDEX IF NOT EXISTS idx_target_category_history_time ON public.target_category_history (target_id, category_name, changed_at DESC);
+
+CREATE OR REPLACE FUNCTION tmidb_document_revision() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        NEW.revision := COALESCE((SELECT max(h.revision) FROM public.target_category_history h
+                                  WHERE h.target_id = NEW.target_id AND h.category_name = NEW.category_name), 0) + 1;
+    ELSIF NEW.category_data IS DISTINCT FROM OLD.category_data
+       OR NEW.schema_version IS DISTINCT FROM OLD.schema_version
+       OR NEW.deleted_at IS DISTINCT FROM OLD.deleted_at THEN
+        NEW.revision := OLD.revision + 1;
+    ELSE
+        NEW.revision := OLD.revision;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION tmidb_record_history() RETURNS TRIGGER AS $$
+DECLARE
+    actor TEXT := COALESCE(NULLIF(current_setting('tmidb.actor', true), ''), current_user);
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        IF OLD.deleted_at IS NULL THEN
+            INSERT INTO public.target_category_history
+                (target_id, category_name, revision, org_id, operation, schema_version, category_data, changed_by)
+            VALUES (OLD.target_id, OLD.category_name, OLD.revision + 1, OLD.org_id, 'delete', OLD.schema_version, OLD.category_data, actor);
+        END IF;
+        RETURN NULL;
+    END IF;
+    IF TG_OP = 'UPDATE' AND NEW.revision = OLD.revision THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO public.target_category_history
+        (target_id, category_name, revision, org_id, operation, schema_version, category_data, changed_by)
+    VALUES (NEW.target_id, NEW.category_name, NEW.revision, NEW.org_id,
+            CASE
+                WHEN TG_OP = 'INSERT' THEN 'insert'
+                WHEN NEW.deleted_at IS NOT NULL AND OLD.deleted_at IS NULL THEN 'delete'
+                WHEN NEW.deleted_at IS NULL AND OLD.deleted_at IS NOT NULL THEN 'restore'
+                ELSE 'update'
+            END,
+            NEW.schema_version, NEW.category_data,
+            CASE WHEN NEW.deleted_at IS NOT NULL AND NEW.deleted_by IS NOT NULL THEN NEW.deleted_by ELSE actor END);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS tmidb_revision ON public.target_categories;
+CREATE TRIGGER tmidb_revision BEFORE INSERT OR UPDATE ON public.target_categories
+    FOR EACH ROW EXECUTE FUNCTION tmidb_document_revision();
+
+DROP TRIGGER IF EXISTS tmidb_history ON public.target_categories;
+CREATE TRIGGER tmidb_history AFTER INSERT OR UPDATE OR DELETE ON public.target_categories
+    FOR EACH ROW EXECUTE FUNCTION tmidb_record_history();
+`
+
+// DocumentRevision은 카테고리 문서 이력의 리비전 하나입니다
+type DocumentRevision struct {
+	TargetID      string      `json:"target_id"`
+	Category      string      `json:"category"`
+	Revision      int         `json:"revision"`
+	Operation     string      `json:"operation"`
+	SchemaVersion int         `json:"schema_version"`
+	Data          interface{} `json:"data,omitempty"`
+	ChangedAt     time.Time   `json:"changed_at"`
+	ChangedBy     string      `json:"changed_by"`
+}
+
+// Exists는 이 리비전 시점에 문서가 있었는지 반환합니다 (delete 이후에는 없음)
+func (r *DocumentRevision) Exists() bool {
+	return r.Operation != HistoryDelete
+}
+
+// DocumentChange는 두 리비전 사이에 바뀐 값 하나입니다. Path는 data 안의 경로입니다 (예: bp.sys, tags.0).
+type DocumentChange struct {
+	Path string      `json:"path"`
+	Op   string      `json:"op"` // added, removed, changed
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// SetChangeActor는 트랜잭션 안에서 바꾸는 문서의 이력에 남길 주체를 정합니다 (트랜잭션이 끝나면 사라짐)
+func SetChangeActor(tx *sql.Tx, actor string) error {
+	_, err := tx.Exec("SELECT set_config('tmidb.actor', $1, true)", actor)
+	return err
+}
+
+const revisionColumns = `target_id::text, category_name, revision, operation, schema_version, category_data::text, changed_at, changed_by`
+
+func scanRevision(row interface{ Scan(...interface{}) error }, withData bool) (*DocumentRevision, error) {
+	var r DocumentRevision
+	var dataJSON string
+	if err := row.Scan(&r.TargetID, &r.Category, &r.Revision, &r.Operation, &r.SchemaVersion, &dataJSON,
+		&r.ChangedAt, &r.ChangedBy); err != nil {
+		return nil, err
+	}
+	if withData {
+		if err := json.Unmarshal([]byte(dataJSON), &r.Data); err != nil {
+			return nil, err
+		}
+	}
+	return &r, nil
+}
+
+// ListDocumentRevisions는 조직의 타겟-카테고리 문서 이력을 최신 리비전부터 조회합니다.
+// before가 0보다 크면 그보다 앞선 리비전만 읽습니다 (페이징).
+func ListDocumentRevisions(db DBTX, orgID, targetID, category string, before, limit int, withData bool) ([]DocumentRevision, error) {
+	rows, err := db.Query(
+		`SELECT `+revisionColumns+`
+		 FROM target_category_history
+		 WHERE org_id::text = $1 AND target_id::text = $2 AND category_name = $3 AND ($4 <= 0 OR revision < $4)
+		 ORDER BY revision DESC
+		 LIMIT $5`,
+		orgID, strings.ToLower(targetID), category, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []DocumentRevision{}
+	for rows.Next() {
+		r, err := scanRevision(rows, withData)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *r)
+	}
+	return revisions, rows.Err()
+}
+
+// GetDocumentRevision은 문서의 리비전 하나를 조회합니다 (없으면 sql.ErrNoRows)
+func GetDocumentRevision(db DBTX, orgID, targetID, category string, revision int) (*DocumentRevision, error) {
+	return scanRevision(db.QueryRow(
+		`SELECT `+revisionColumns+`
+		 FROM target_category_history
+		 WHERE org_id::text = $1 AND target_id::text = $2 AND category_name = $3 AND revision = $4`,
+		orgID, strings.ToLower(targetID), category, revision), true)
+}
+
+// GetLatestDocumentRevision은 문서의 마지막 리비전을 조회합니다 (이력이 없으면 sql.ErrNoRows)
+func GetLatestDocumentRevision(db DBTX, orgID, targetID, category string) (*DocumentRevision, error) {
+	return scanRevision(db.QueryRow(
+		`SELECT `+revisionColumns+`
+		 FROM target_category_history
+		 WHERE org_id::text = $1 AND target_id::text = $2 AND category_name = $3
+		 ORDER BY revision DESC
+		 LIMIT 1`,
+		orgID, strings.ToLower(targetID), category), true)
+}
+
+// GetDocumentAsOf는 at 시점에 유효했던 문서 리비전을 조회합니다.
+// at 이전에 이력이 없으면 sql.ErrNoRows이고, 그때 삭제된 상태였으면 Exists()가 false인 리비전입니다.
+func GetDocumentAsOf(db DBTX, orgID, targetID, category string, at time.Time) (*DocumentRevision, error) {
+	return scanRevision(db.QueryRow(
+		`SELECT `+revisionColumns+`
+		 FROM target_category_history
+		 WHERE org_id::text = $1 AND target_id::text = $2 AND category_name = $3 AND changed_at <= $4
+		 ORDER BY revision DESC
+		 LIMIT 1`,
+		orgID, strings.ToLower(targetID), category, at), true)
+}
+
+// DiffDocuments는 두 문서의 차이를 경로 순으로 반환합니다.
+// 객체는 키별로, 배열은 인덱스별로 내려가며 비교하고, 종류가 다른 값은 통째로 changed입니다.
+func DiffDocuments(before, after interface{}) []DocumentChange {
+	changes := []DocumentChange{}
+	diffValues("", before, after, &changes)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+func diffValues(path string, before, after interface{}, changes *[]DocumentChange) {
+	switch o := before.(type) {
+	case map[string]interface{}:
+		n, ok := after.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for key, ov := range o {
+			if nv, ok := n[key]; ok {
+				diffValues(joinDocumentPath(path, key), ov, nv, changes)
+			} else {
+				*changes = append(*changes, DocumentChange{Path: joinDocumentPath(path, key), Op: "removed", Old: ov})
+			}
+		}
+		for key, nv := range n {
+			if _, ok := o[key]; !ok {
+				*changes = append(*changes, DocumentChange{Path: joinDocumentPath(path, key), Op: "added", New: nv})
+			}
+		}
+		return
+	case []interface{}:
+		n, ok := after.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(o) || i < len(n); i++ {
+			p := joinDocumentPath(path, strconv.Itoa(i))
+			switch {
+			case i >= len(n):
+				*changes = append(*changes, DocumentChange{Path: p, Op: "removed", Old: o[i]})
+			case i >= len(o):
+				*changes = append(*changes, DocumentChange{Path: p, Op: "added", New: n[i]})
+			default:
+				diffValues(p, o[i], n[i], changes)
+			}
+		}
+		return
+	}
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, DocumentChange{Path: path, Op: "changed", Old: before, New: after})
+	}
+}
+
+func joinDocumentPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,30 @@
+package database
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiffDocuments(t *testing.T) {
+	var before, after interface{}
+	if err := json.Unmarshal([]byte(`{"name":"a","bp":{"sys":120,"dia":80},"tags":["x","y"],"note":"old"}`), &before); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"name":"a","bp":{"sys":125,"dia":80},"tags":["x"],"note":{"text":"new"},"ward":3}`), &after); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []DocumentChange{
+		{Path: "bp.sys", Op: "changed", Old: 120.0, New: 125.0},
+		{Path: "note", Op: "changed", Old: "old", New: map[string]interface{}{"text": "new"}},
+		{Path: "tags.1", Op: "removed", Old: "y"},
+		{Path: "ward", Op: "added", New: 3.0},
+	}
+	if got := DiffDocuments(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffDocuments = %+v, want %+v", got, want)
+	}
+	if got := DiffDocuments(after, after); len(got) != 0 {
+		t.Errorf("identical documents differ: %+v", got)
+	}
+}
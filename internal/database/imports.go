@@ -0,0 +1,278 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/tmidb/tmidb-core/internal/schema"
+)
+
+// 가져오기 작업 상태
+const (
+	ImportStatusUploading = "uploading"
+	ImportStatusQueued    = "queued"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+var (
+	// ErrImportJobNotFound는 조직에 없는 가져오기 작업을 조회할 때 반환됩니다
+	ErrImportJobNotFound = errors.New("import job not found")
+	// ErrImportUploadConflict는 업로드 중이 아니거나 offset이 받은 크기와 다른 작업에 조각을 더할 때 반환됩니다
+	ErrImportUploadConflict = errors.New("import job is not accepting data at this offset")
+)
+
+// ImportJob은 CSV/NDJSON 파일을 카테고리 시계열로 가져오는 작업입니다.
+// 파일은 조각으로 나누어 올리거나(ChunkPaths) 이미 SeaweedFS에 있는 파일(SourcePath)을 씁니다.
+type ImportJob struct {
+	JobID         string           `json:"job_id"`
+	OrgID         string           `json:"-"`
+	Category      string           `json:"category"`
+	Format        string           `json:"format"`
+	Mapping       json.RawMessage  `json:"mapping,omitempty"`
+	SourcePath    string           `json:"source_path,omitempty"`
+	ChunkPaths    []string         `json:"-"`
+	ReceivedBytes int64            `json:"received_bytes"`
+	ExpectedBytes *int64           `json:"expected_bytes,omitempty"`
+	Status        string           `json:"status"`
+	RowsRead      int64            `json:"rows_read"`
+	RowsImported  int64            `json:"rows_imported"`
+	RowsFailed    int64            `json:"rows_failed"`
+	RowErrors     []ImportRowError `json:"row_errors,omitempty"`
+	Error         string           `json:"error,omitempty"`
+	CreatedBy     string           `json:"created_by,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+	StartedAt     *time.Time       `json:"started_at,omitempty"`
+	FinishedAt    *time.Time       `json:"finished_at,omitempty"`
+	ExpiresAt     *time.Time       `json:"expires_at,omitempty"` // 업로드 중이면 이 시각까지 다음 조각이 없을 때, 끝났으면 이 시각에 삭제됨
+}
+
+// ImportRowError는 가져오지 못한 줄 하나입니다 (Line은 파일의 줄 번호, CSV는 헤더가 1)
+type ImportRowError struct {
+	Line     int                 `json:"line"`
+	TargetID string              `json:"target_id,omitempty"`
+	Error    string              `json:"error"`
+	Fields   []schema.FieldError `json:"fields,omitempty"`
+}
+
+// ImportObservation은 ts_obs에 넣을 관측값 하나입니다
+type ImportObservation struct {
+	TargetID string
+	Ts       time.Time
+	Payload  []byte
+}
+
+// CreateImportJob은 가져오기 작업을 만들고 job의 ID, 상태, 시각을 채웁니다.
+// SourcePath가 있으면 바로 대기 상태가 되고, 없으면 expiresIn 안에 첫 조각을 올려야 합니다.
+func CreateImportJob(db DBTX, job *ImportJob, expiresIn time.Duration) error {
+	status := ImportStatusUploading
+	if job.SourcePath != "" {
+		status = ImportStatusQueued
+	}
+	var mapping interface{}
+	if len(job.Mapping) > 0 {
+		mapping = string(job.Mapping)
+	}
+	var expiresAt sql.NullTime
+	err := db.QueryRow(`
+		INSERT INTO import_jobs (org_id, category_name, format, mapping, source_path, expected_bytes, status, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, NULLIF($8, ''), now() + make_interval(secs => $9))
+		RETURNING job_id::text, status, created_at, updated_at, expires_at
+	`, job.OrgID, job.Category, job.Format, mapping, job.SourcePath, job.ExpectedBytes, status, job.CreatedBy,
+		expiresIn.Seconds()).Scan(&job.JobID, &job.Status, &job.CreatedAt, &job.UpdatedAt, &expiresAt)
+	job.ExpiresAt = nullTimePtr(expiresAt)
+	return err
+}
+
+// GetImportJob은 조직의 가져오기 작업을 조회합니다
+func GetImportJob(db DBTX, orgID, jobID string) (*ImportJob, error) {
+	var job ImportJob
+	var mapping, rowErrors []byte
+	var sourcePath, jobErr, createdBy sql.NullString
+	var expected sql.NullInt64
+	var startedAt, finishedAt, expiresAt sql.NullTime
+	err := db.QueryRow(`
+		SELECT job_id::text, org_id, category_name, format, mapping, source_path, chunk_paths, received_bytes,
+		       expected_bytes, status, rows_read, rows_imported, rows_failed, row_errors, error, created_by,
+		       created_at, updated_at, started_at, finished_at, expires_at
+		FROM import_jobs
+		WHERE org_id = $1 AND job_id::text = $2
+	`, orgID, jobID).Scan(&job.JobID, &job.OrgID, &job.Category, &job.Format, &mapping, &sourcePath,
+		pq.Array(&job.ChunkPaths), &job.ReceivedBytes, &expected, &job.Status, &job.RowsRead, &job.RowsImported,
+		&job.RowsFailed, &rowErrors, &jobErr, &createdBy, &job.CreatedAt, &job.UpdatedAt, &startedAt, &finishedAt, &expiresAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrImportJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	job.Mapping = mapping
+	if len(rowErrors) > 0 {
+		json.Unmarshal(rowErrors, &job.RowErrors)
+	}
+	if expected.Valid {
+		job.ExpectedBytes = &expected.Int64
+	}
+	job.SourcePath, job.Error, job.CreatedBy = sourcePath.String, jobErr.String, createdBy.String
+	job.StartedAt, job.FinishedAt, job.ExpiresAt = nullTimePtr(startedAt), nullTimePtr(finishedAt), nullTimePtr(expiresAt)
+	return &job, nil
+}
+
+// AppendImportChunk는 offset에서 시작하는 조각을 작업에 더하고 받은 크기를 반환합니다.
+// 같은 offset의 조각이 동시에 올라오면 하나만 더해지고 나머지는 ErrImportUploadConflict를 받습니다.
+func AppendImportChunk(db DBTX, orgID, jobID string, offset, size int64, chunkPath string, expiresIn time.Duration) (int64, error) {
+	var received int64
+	err := db.QueryRow(`
+		UPDATE import_jobs
+		SET chunk_paths = array_append(chunk_paths, $5), received_bytes = received_bytes + $4,
+		    updated_at = now(), expires_at = now() + make_interval(secs => $6)
+		WHERE org_id = $1 AND job_id::text = $2 AND status = 'uploading' AND received_bytes = $3
+		  AND (expected_bytes IS NULL OR received_bytes + $4 <= expected_bytes)
+		RETURNING received_bytes
+	`, orgID, jobID, offset, size, chunkPath, expiresIn.Seconds()).Scan(&received)
+	if err == sql.ErrNoRows {
+		return 0, ErrImportUploadConflict
+	}
+	return received, err
+}
+
+// QueueImportJob은 업로드를 마친 작업을 대기 상태로 바꿉니다.
+// expected_bytes를 정했으면 그만큼 받았어야 합니다.
+func QueueImportJob(db DBTX, orgID, jobID string) error {
+	result, err := db.Exec(`
+		UPDATE import_jobs SET status = 'queued', updated_at = now()
+		WHERE org_id = $1 AND job_id::text = $2 AND status = 'uploading' AND received_bytes > 0
+		  AND (expected_bytes IS NULL OR received_bytes = expected_bytes)
+	`, orgID, jobID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrImportUploadConflict
+	}
+	return nil
+}
+
+// StartImportJob은 작업을 실행 중으로 표시합니다
+func StartImportJob(db DBTX, jobID string) error {
+	_, err := db.Exec(`
+		UPDATE import_jobs SET status = 'running', started_at = now(), updated_at = now()
+		WHERE job_id::text = $1
+	`, jobID)
+	return err
+}
+
+// UpdateImportProgress는 실행 중인 작업의 진행 상황을 기록합니다
+func UpdateImportProgress(db DBTX, jobID string, read, imported, failed int64) error {
+	_, err := db.Exec(`
+		UPDATE import_jobs SET rows_read = $2, rows_imported = $3, rows_failed = $4, updated_at = now()
+		WHERE job_id::text = $1
+	`, jobID, read, imported, failed)
+	return err
+}
+
+// FinishImportJob은 작업 결과를 기록합니다. jobErr가 있으면 실패로 표시합니다.
+// 올린 조각은 호출자가 지우므로 chunk_paths를 비우고, retention 뒤에 작업이 삭제되도록 합니다.
+func FinishImportJob(db DBTX, jobID string, read, imported, failed int64, rowErrors []ImportRowError,
+	retention time.Duration, jobErr error) error {
+
+	status, message := ImportStatusCompleted, ""
+	if jobErr != nil {
+		status, message = ImportStatusFailed, jobErr.Error()
+	}
+	var encoded interface{}
+	if len(rowErrors) > 0 {
+		data, err := json.Marshal(rowErrors)
+		if err != nil {
+			return err
+		}
+		encoded = string(data)
+	}
+	_, err := db.Exec(`
+		UPDATE import_jobs
+		SET status = $2, rows_read = $3, rows_imported = $4, rows_failed = $5, row_errors = $6, error = NULLIF($7, ''),
+		    chunk_paths = '{}', updated_at = now(), finished_at = now(), expires_at = now() + make_interval(secs => $8)
+		WHERE job_id::text = $1
+	`, jobID, status, read, imported, failed, encoded, message, retention.Seconds())
+	return err
+}
+
+// ExpireImportJobs는 만료된 작업을 정리하고 파일 저장소에서 지울 조각 경로를 반환합니다.
+// 업로드가 멈춘 작업과 staleAfter보다 오래 끝나지 않은 작업(API 서버 재시작)은 실패로 표시하고
+// retention 뒤에 삭제합니다.
+func ExpireImportJobs(db DBTX, staleAfter, retention time.Duration) ([]string, error) {
+	_, err := db.Exec(`
+		UPDATE import_jobs
+		SET status = 'failed', finished_at = now(), updated_at = now(),
+		    error = CASE WHEN status = 'uploading' THEN 'upload expired' ELSE 'import was interrupted' END,
+		    expires_at = now() + make_interval(secs => $2)
+		WHERE (status = 'uploading' AND expires_at < now())
+		   OR (status IN ('queued', 'running') AND updated_at < now() - make_interval(secs => $1))
+	`, staleAfter.Seconds(), retention.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	// 조각이 남은 실패 작업은 조각 경로만 비우고, 작업은 다음 정리 때 삭제
+	return queryStrings(db, `
+		WITH cleared AS (
+			UPDATE import_jobs SET chunk_paths = '{}'
+			WHERE status = 'failed' AND chunk_paths <> '{}'
+			RETURNING chunk_paths AS paths
+		), deleted AS (
+			DELETE FROM import_jobs
+			WHERE status IN ('completed', 'failed') AND expires_at < now() AND chunk_paths = '{}'
+		)
+		SELECT unnest(paths) FROM cleared
+	`)
+}
+
+// CopyObservations는 관측값을 COPY로 임시 테이블에 넣은 뒤 한 번에 ts_obs에 씁니다.
+// 같은 타겟, 같은 시각의 관측값은 기존 값과 배치 안의 앞선 값을 모두 나중 값으로 덮어씁니다.
+// 하나라도 실패하면 배치 전체가 취소됩니다.
+func CopyObservations(db *sql.DB, category string, observations []ImportObservation) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		CREATE TEMP TABLE import_obs (seq INT, target_id UUID, ts TIMESTAMPTZ, payload JSONB) ON COMMIT DROP
+	`); err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(pq.CopyIn("import_obs", "seq", "target_id", "ts", "payload"))
+	if err != nil {
+		return err
+	}
+	for i, o := range observations {
+		if _, err := stmt.Exec(i, o.TargetID, o.Ts, string(o.Payload)); err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return err
+	}
+	if err := stmt.Close(); err != nil {
+		return err
+	}
+
+	// ON CONFLICT DO UPDATE는 한 문장에서 같은 키를 두 번 바꿀 수 없으므로 키마다 마지막 값만 넣음
+	if _, err := tx.Exec(`
+		INSERT INTO ts_obs (target_id, category_name, ts, payload)
+		SELECT DISTINCT ON (target_id, ts) target_id, $1, ts, payload
+		FROM import_obs
+		ORDER BY target_id, ts, seq DESC
+		ON CONFLICT (target_id, category_name, ts) DO UPDATE SET payload = EXCLUDED.payload
+	`, category); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
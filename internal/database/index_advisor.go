@@ -0,0 +1,694 @@
+package database
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/query"
+	"github.com/tmidb/tmidb-core/internal/schema"
+)
+
+// 인덱스 추천 기준
+const (
+	indexSampleRows     = 10000   // 선택도를 재려고 읽는 행 수
+	minIndexRows        = 1000    // 이보다 작은 테이블은 순차 스캔이 더 빠름
+	maxIndexSelectivity = 0.4     // 이보다 많은 행에 맞는 조건은 플래너가 인덱스를 쓰지 않음
+	rangeSelectivity    = 1.0 / 3 // 크기 비교 조건의 선택도 (PostgreSQL 기본 추정값)
+	containSelectivity  = 0.01    // @> 조건의 선택도 (대략)
+)
+
+// 인덱스 종류
+const (
+	IndexExpression = "expression"
+	IndexGIN        = "gin"
+)
+
+// IndexSuggestion은 자주 쓰는 JSONB 조건에 맞춘 인덱스 추천입니다
+type IndexSuggestion struct {
+	Name       string    `json:"name"`
+	Table      string    `json:"table"`
+	Kind       string    `json:"kind"`           // expression 또는 gin
+	Path       string    `json:"path,omitempty"` // 데이터 경로 (vitals.bp)
+	Expression string    `json:"expression"`
+	Categories []string  `json:"categories"`            // 경로를 스키마에 선언한 카테고리
+	FieldTypes []string  `json:"field_types,omitempty"` // 스키마에 선언된 타입
+	Source     string    `json:"source"`                // filters(API 필터) 또는 slow_queries
+	Calls      int64     `json:"calls"`
+	LastUsed   time.Time `json:"last_used"`
+	ObservedMs float64   `json:"observed_mean_ms,omitempty"` // 느린 쿼리의 평균 시간
+
+	// 추정 효과 (표본에서 잰 값이라 대략적임)
+	Rows           int64   `json:"rows"`           // 조건이 지금 훑는 행 수
+	Selectivity    float64 `json:"selectivity"`    // 조건에 맞는 행의 비율
+	RowsPerQuery   float64 `json:"rows_per_query"` // 인덱스가 있으면 읽을 행 수
+	EstimatedBytes int64   `json:"estimated_bytes"`
+	SQL            string  `json:"sql"`
+
+	score float64
+}
+
+// SkippedIndex는 조건은 기록되었지만 인덱스를 추천하지 않은 이유입니다
+type SkippedIndex struct {
+	Table  string `json:"table"`
+	Path   string `json:"path"`
+	Calls  int64  `json:"calls"`
+	Reason string `json:"reason"`
+}
+
+// IndexAdvice는 인덱스 추천 결과입니다 (효과가 큰 순서)
+type IndexAdvice struct {
+	Suggestions []IndexSuggestion `json:"suggestions"`
+	Skipped     []SkippedIndex    `json:"skipped"`
+}
+
+// AddFilterUsage는 조직 카테고리의 데이터 경로 필터 사용 횟수를 더합니다
+func AddFilterUsage(db DBTX, orgID, category, path, kind string, calls int64, seen time.Time) error {
+	_, err := db.Exec(`
+		INSERT INTO filter_usage (org_id, category_name, path, kind, calls, first_seen, last_seen)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (org_id, category_name, path, kind) DO UPDATE SET
+			calls = filter_usage.calls + EXCLUDED.calls,
+			last_seen = GREATEST(filter_usage.last_seen, EXCLUDED.last_seen)
+	`, orgID, category, path, kind, calls, seen)
+	return err
+}
+
+// ResetFilterUsage는 필터 사용 기록을 지웁니다
+func ResetFilterUsage(db DBTX) (int64, error) {
+	result, err := db.Exec("DELETE FROM filter_usage")
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// indexCandidate는 효과를 재기 전의 추천 후보입니다
+type indexCandidate struct {
+	IndexSuggestion
+	rangeFilter bool // 크기 비교라 선택도를 1/3로 봄
+	rejected    string
+}
+
+// AdviseIndexes는 기록된 API 필터(filter_usage)와 느린 쿼리의 JSONB 조건을 카테고리 스키마와 맞춰 보고
+// minCalls번 이상 쓰인 조건에 대해 target_categories와 ts_obs의 식 인덱스나 GIN 인덱스를 추천합니다.
+// 인덱스마다 표본으로 잰 선택도와 크기를 붙이며, 아무것도 만들지 않습니다.
+func AdviseIndexes(db *sql.DB, minCalls int64) (*IndexAdvice, error) {
+	schemas, err := activeCategorySchemas(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read category schemas: %v", err)
+	}
+	candidates, err := filterIndexCandidates(db, schemas)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read filter usage: %v", err)
+	}
+	slow, err := slowQueryIndexCandidates(db, schemas)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read slow queries: %v", err)
+	}
+	candidates = append(candidates, slow...)
+
+	hypertable := isHypertable(db, TableTsObs)
+	advice := &IndexAdvice{Suggestions: []IndexSuggestion{}, Skipped: []SkippedIndex{}}
+	for _, c := range candidates {
+		skip := func(reason string) {
+			advice.Skipped = append(advice.Skipped, SkippedIndex{Table: c.Table, Path: c.displayPath(), Calls: c.Calls, Reason: reason})
+		}
+		switch {
+		case c.rejected != "":
+			skip(c.rejected)
+			continue
+		case c.Calls < minCalls:
+			skip(fmt.Sprintf("used %d times (fewer than %d)", c.Calls, minCalls))
+			continue
+		}
+
+		state, err := indexState(db, c.Name)
+		if err != nil {
+			return nil, err
+		}
+		if state == "valid" {
+			skip("index " + c.Name + " already exists")
+			continue
+		}
+		if c.Kind == IndexGIN {
+			if existing, err := existingGINIndex(db, c.Table, c.Expression); err != nil {
+				return nil, err
+			} else if existing != "" {
+				skip("GIN index " + existing + " already covers it")
+				continue
+			}
+		}
+
+		if err := c.estimate(db, hypertable); err != nil {
+			skip(fmt.Sprintf("could not measure: %v", err))
+			continue
+		}
+		switch {
+		case c.Rows < minIndexRows:
+			skip(fmt.Sprintf("only %d rows; a sequential scan is as fast", c.Rows))
+			continue
+		case c.Selectivity > maxIndexSelectivity:
+			skip(fmt.Sprintf("matches about %.0f%% of the rows; PostgreSQL would not use an index", c.Selectivity*100))
+			continue
+		}
+
+		c.SQL = c.createSQL(hypertable && c.Table == TableTsObs)
+		c.score = float64(c.Calls) * (float64(c.Rows) - c.RowsPerQuery)
+		advice.Suggestions = append(advice.Suggestions, c.IndexSuggestion)
+	}
+
+	sort.SliceStable(advice.Suggestions, func(i, j int) bool {
+		return advice.Suggestions[i].score > advice.Suggestions[j].score
+	})
+	sort.SliceStable(advice.Skipped, func(i, j int) bool {
+		return advice.Skipped[i].Calls > advice.Skipped[j].Calls
+	})
+	return advice, nil
+}
+
+// CreateAdvisedIndex는 추천 인덱스를 만듭니다. 전에 실패해 남은 INVALID 인덱스는 지우고 다시 만듭니다.
+func CreateAdvisedIndex(db *sql.DB, s IndexSuggestion) error {
+	state, err := indexState(db, s.Name)
+	if err != nil {
+		return err
+	}
+	if state == "invalid" {
+		if _, err := db.Exec("DROP INDEX IF EXISTS public." + s.Name); err != nil {
+			return fmt.Errorf("failed to drop invalid index %s: %v", s.Name, err)
+		}
+	}
+	if _, err := db.Exec(s.SQL); err != nil {
+		// CONCURRENTLY가 실패하면 INVALID 인덱스가 남음
+		db.Exec("DROP INDEX IF EXISTS public." + s.Name)
+		return err
+	}
+	return nil
+}
+
+// categorySchema 활성 스키마 (조직, 카테고리)
+type categorySchema struct {
+	orgID    string
+	category string
+	schema   *schema.Schema
+}
+
+// activeCategorySchemas는 조직 카테고리마다 활성 스키마의 최신 버전을 읽습니다
+func activeCategorySchemas(db DBTX) ([]categorySchema, error) {
+	rows, err := db.Query(`
+		SELECT DISTINCT ON (org_id, category_name) org_id::text, category_name, schema_definition::text
+		FROM category_schemas
+		WHERE is_active = true
+		ORDER BY org_id, category_name, version DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schemas []categorySchema
+	for rows.Next() {
+		var cs categorySchema
+		var definition string
+		if err := rows.Scan(&cs.orgID, &cs.category, &definition); err != nil {
+			return nil, err
+		}
+		if cs.schema, err = schema.Cached(definition); err != nil {
+			continue // 컴파일되지 않는 스키마는 검증에도 쓰이지 않음
+		}
+		schemas = append(schemas, cs)
+	}
+	return schemas, rows.Err()
+}
+
+// schemaCheck는 경로의 선언 타입을 모아 인덱스 식과 맞는지 봅니다
+type schemaCheck struct {
+	categories map[string]bool
+	types      map[string]bool
+	undeclared int
+	mismatched int
+}
+
+func newSchemaCheck() *schemaCheck {
+	return &schemaCheck{categories: map[string]bool{}, types: map[string]bool{}}
+}
+
+// add는 카테고리 스키마에서 경로의 타입을 확인합니다. numeric이면 숫자 타입이어야 합니다.
+func (sc *schemaCheck) add(cs categorySchema, path []string, numeric bool) bool {
+	types := cs.schema.TypesAt(path)
+	if types == nil {
+		sc.undeclared++
+		return false
+	}
+	if numeric && !containsAny(types, "number", "integer") {
+		sc.mismatched++
+		return false
+	}
+	sc.categories[cs.category] = true
+	for _, t := range types {
+		sc.types[t] = true
+	}
+	return true
+}
+
+// reason은 어떤 카테고리 스키마와도 맞지 않을 때의 이유입니다
+func (sc *schemaCheck) reason() string {
+	if sc.mismatched > 0 {
+		return "the schema does not declare it as a number, so numeric comparisons never use an index"
+	}
+	return "not declared in the category schema"
+}
+
+func containsAny(list []string, values ...string) bool {
+	for _, item := range list {
+		for _, v := range values {
+			if item == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// filterIndexCandidates는 API 필터 기록을 경로와 식 종류별로 묶어 target_categories 후보를 만듭니다
+func filterIndexCandidates(db DBTX, schemas []categorySchema) ([]*indexCandidate, error) {
+	byCategory := make(map[string]categorySchema, len(schemas))
+	for _, cs := range schemas {
+		byCategory[cs.orgID+"/"+cs.category] = cs
+	}
+
+	rows, err := db.Query(`SELECT org_id, category_name, path, kind, calls, last_seen FROM filter_usage`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type group struct {
+		candidate *indexCandidate
+		check     *schemaCheck
+		allCalls  int64 // 스키마와 맞지 않는 카테고리 포함
+	}
+	groups := make(map[string]*group)
+	var order []string
+	for rows.Next() {
+		var orgID, category, path, kind string
+		var calls int64
+		var lastSeen time.Time
+		if err := rows.Scan(&orgID, &category, &path, &kind, &calls, &lastSeen); err != nil {
+			return nil, err
+		}
+		segments := strings.Split(path, ".")
+		key := path + "\x00" + kind
+		g, ok := groups[key]
+		if !ok {
+			expr := query.IndexExpr(segments, kind)
+			g = &group{check: newSchemaCheck(), candidate: &indexCandidate{
+				IndexSuggestion: IndexSuggestion{
+					Name:       advisedIndexName(indexPrefix("target_categories"), path, kind, expr),
+					Table:      "target_categories",
+					Kind:       IndexExpression,
+					Path:       path,
+					Expression: expr,
+					Source:     "filters",
+				},
+				rangeFilter: kind == query.IndexNumber,
+			}}
+			groups[key] = g
+			order = append(order, key)
+		}
+
+		g.allCalls += calls
+		cs, ok := byCategory[orgID+"/"+category]
+		if !ok {
+			g.check.undeclared++
+			continue
+		}
+		if !g.check.add(cs, segments, kind == query.IndexNumber) {
+			continue
+		}
+		g.candidate.Calls += calls
+		if lastSeen.After(g.candidate.LastUsed) {
+			g.candidate.LastUsed = lastSeen
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*indexCandidate, 0, len(order))
+	for _, key := range order {
+		g := groups[key]
+		c := g.candidate
+		c.Categories = sortedKeys(g.check.categories)
+		c.FieldTypes = sortedKeys(g.check.types)
+		if len(c.Categories) == 0 {
+			c.rejected = g.check.reason()
+			c.Calls = g.allCalls
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, nil
+}
+
+var (
+	// payloadPathPattern ts_obs 쿼리의 payload 경로 (숫자 등으로 변환하는 경우 포함)
+	payloadPathPattern = regexp.MustCompile(`(?i)(\(\s*)?\bpayload\s*(->>|#>>)\s*'([^']*)'(?:\s*\)\s*::\s*(double precision|float8|float|numeric|integer|int4|int|bigint|int8|boolean|bool))?`)
+	// containsPattern JSONB 포함 조건 (@>)
+	containsPattern = regexp.MustCompile(`(?i)\b(payload|category_data)\s*@>`)
+	tsObsPattern    = regexp.MustCompile(`(?i)\bts_obs\b`)
+	// safeSegmentPattern 인덱스 식에 넣을 수 있는 경로 단계
+	safeSegmentPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_-]*$`)
+)
+
+// castTypes 인덱스 식에 쓰는 변환 타입 이름
+var castTypes = map[string]string{
+	"double precision": "double precision", "float8": "double precision", "float": "double precision",
+	"numeric": "numeric",
+	"integer": "integer", "int4": "integer", "int": "integer",
+	"bigint": "bigint", "int8": "bigint",
+	"boolean": "boolean", "bool": "boolean",
+}
+
+// payloadExpr는 쿼리에서 찾은 payload 경로를 안전한 식으로 다시 만듭니다 (경로 단계가 이상하면 false)
+func payloadExpr(op, literal, cast string) (expr string, path []string, ok bool) {
+	if op == "->>" {
+		path = []string{literal}
+	} else {
+		inner := strings.TrimSuffix(strings.TrimPrefix(literal, "{"), "}")
+		for _, segment := range strings.Split(inner, ",") {
+			path = append(path, strings.Trim(strings.TrimSpace(segment), `"`))
+		}
+	}
+	for _, segment := range path {
+		if !safeSegmentPattern.MatchString(segment) {
+			return "", nil, false
+		}
+	}
+
+	if op == "->>" {
+		expr = "payload ->> '" + path[0] + "'"
+	} else {
+		expr = "payload #>> '{" + strings.Join(path, ",") + "}'"
+	}
+	if cast != "" {
+		expr = "(" + expr + ")::" + castTypes[strings.ToLower(cast)]
+	}
+	return expr, path, true
+}
+
+// slowQueryIndexCandidates는 느린 쿼리에서 ts_obs의 payload 경로 조건과 JSONB 포함 조건(@>)을 찾습니다.
+// target_categories의 경로 조건은 API 필터 기록에 더 정확히 남으므로 여기서는 보지 않습니다.
+func slowQueryIndexCandidates(db DBTX, schemas []categorySchema) ([]*indexCandidate, error) {
+	rows, err := db.Query(`SELECT sample_query, calls, total_ms, last_seen FROM slow_queries`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type group struct {
+		candidate *indexCandidate
+		path      []string
+		totalMs   float64
+	}
+	groups := make(map[string]*group)
+	var order []string
+	add := func(key string, c *indexCandidate, path []string, calls int64, totalMs float64, lastSeen time.Time) {
+		g, ok := groups[key]
+		if !ok {
+			g = &group{candidate: c, path: path}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.candidate.Calls += calls
+		g.totalMs += totalMs
+		if lastSeen.After(g.candidate.LastUsed) {
+			g.candidate.LastUsed = lastSeen
+		}
+	}
+
+	for rows.Next() {
+		var sampleQuery string
+		var calls int64
+		var totalMs float64
+		var lastSeen time.Time
+		if err := rows.Scan(&sampleQuery, &calls, &totalMs, &lastSeen); err != nil {
+			return nil, err
+		}
+
+		seen := map[string]bool{}
+		if tsObsPattern.MatchString(sampleQuery) {
+			for _, m := range payloadPathPattern.FindAllStringSubmatch(sampleQuery, -1) {
+				cast := m[4]
+				if m[1] == "" {
+					cast = "" // 괄호 없이 ::가 붙은 경우는 경로 식에 붙은 변환이 아님
+				}
+				expr, path, ok := payloadExpr(m[2], m[3], cast)
+				if !ok || seen[expr] {
+					continue
+				}
+				seen[expr] = true
+				numeric := cast != "" && castTypes[strings.ToLower(cast)] != "boolean"
+				kind := query.IndexText
+				if cast != "" {
+					kind = castTypes[strings.ToLower(cast)]
+				}
+				add(TableTsObs+"\x00"+expr, &indexCandidate{
+					IndexSuggestion: IndexSuggestion{
+						Name:       advisedIndexName(indexPrefix(TableTsObs), strings.Join(path, "."), kind, expr),
+						Table:      TableTsObs,
+						Kind:       IndexExpression,
+						Path:       strings.Join(path, "."),
+						Expression: expr,
+						Source:     "slow_queries",
+					},
+					rangeFilter: numeric,
+				}, path, calls, totalMs, lastSeen)
+			}
+		}
+		for _, m := range containsPattern.FindAllStringSubmatch(sampleQuery, -1) {
+			column := strings.ToLower(m[1])
+			table := "target_categories"
+			if column == "payload" {
+				if !tsObsPattern.MatchString(sampleQuery) {
+					continue
+				}
+				table = TableTsObs
+			}
+			expr := column + " jsonb_path_ops"
+			if seen[expr] {
+				continue
+			}
+			seen[expr] = true
+			add(table+"\x00"+expr, &indexCandidate{IndexSuggestion: IndexSuggestion{
+				Name:       advisedIndexName(indexPrefix(table), column, IndexGIN, expr),
+				Table:      table,
+				Kind:       IndexGIN,
+				Expression: expr,
+				Source:     "slow_queries",
+			}}, nil, calls, totalMs, lastSeen)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*indexCandidate, 0, len(order))
+	for _, key := range order {
+		g := groups[key]
+		c := g.candidate
+		if c.Calls > 0 {
+			c.ObservedMs = g.totalMs / float64(c.Calls)
+		}
+
+		// ts_obs의 payload는 카테고리 스키마로 검증된 관측값이므로 경로를 선언한 카테고리만 봄
+		check := newSchemaCheck()
+		for _, cs := range schemas {
+			if c.Kind == IndexGIN {
+				check.categories[cs.category] = true
+				continue
+			}
+			check.add(cs, g.path, c.rangeFilter)
+		}
+		c.Categories = sortedKeys(check.categories)
+		c.FieldTypes = sortedKeys(check.types)
+		if len(c.Categories) == 0 && c.Kind == IndexExpression {
+			c.rejected = check.reason()
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, nil
+}
+
+// indexPrefix 추천 인덱스 이름에 붙이는 테이블 약어
+func indexPrefix(table string) string {
+	if table == TableTsObs {
+		return "obs"
+	}
+	return "tc"
+}
+
+// advisedIndexName은 추천 인덱스 이름입니다 (같은 식이면 항상 같은 이름이라 이미 만든 인덱스를 알아봄)
+func advisedIndexName(prefix, path, kind, expr string) string {
+	sum := sha256.Sum256([]byte(prefix + "\x00" + expr))
+	readable := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r + ('a' - 'A')
+		}
+		return '_'
+	}, path)
+	if len(readable) > 32 {
+		readable = readable[:32]
+	}
+	suffix := map[string]string{query.IndexText: "txt", query.IndexNumber: "num", IndexGIN: "gin"}[kind]
+	if suffix == "" {
+		suffix = strings.Fields(kind)[0]
+	}
+	return fmt.Sprintf("idx_%s_%s_%s_%s", prefix, readable, suffix, hex.EncodeToString(sum[:3]))
+}
+
+// displayPath는 경로가 없는 GIN 후보는 식으로 나타냅니다
+func (c *indexCandidate) displayPath() string {
+	if c.Path != "" {
+		return c.Path
+	}
+	return c.Expression
+}
+
+// estimate는 조건이 훑는 행 수와 표본으로 잰 선택도, 인덱스 크기를 채웁니다
+func (c *indexCandidate) estimate(db *sql.DB, hypertable bool) error {
+	categories := "{" + strings.Join(quotedArrayItems(c.Categories), ",") + "}"
+
+	if c.Table == TableTsObs {
+		// 관측값은 많으므로 통계의 추정값을 씀 (ANALYZE 전이면 백만 행까지만 셈)
+		rowCount := `SELECT GREATEST(reltuples, 0)::bigint FROM pg_class WHERE oid = 'public.ts_obs'::regclass`
+		if hypertable {
+			rowCount = `SELECT approximate_row_count('public.ts_obs')`
+		}
+		if err := db.QueryRow(rowCount).Scan(&c.Rows); err != nil {
+			return err
+		}
+		if c.Rows <= 0 {
+			if err := db.QueryRow(`SELECT count(*) FROM (SELECT 1 FROM public.ts_obs LIMIT 1000000) s`).Scan(&c.Rows); err != nil {
+				return err
+			}
+		}
+	} else if err := db.QueryRow(`SELECT count(*) FROM target_categories WHERE category_name = ANY($1::text[])`,
+		categories).Scan(&c.Rows); err != nil {
+		return err
+	}
+
+	column := "category_data"
+	if c.Table == TableTsObs {
+		column = "payload"
+	}
+	value := c.Expression
+	if c.Kind == IndexGIN {
+		value = column
+	}
+	var sampled, nonNull, distinct int64
+	var width float64
+	err := db.QueryRow(fmt.Sprintf(`
+		SELECT count(*), count(v), count(DISTINCT v), COALESCE(avg(pg_column_size(v)), 0)
+		FROM (SELECT %s AS v FROM public.%s WHERE category_name = ANY($1::text[]) LIMIT %d) s`,
+		value, c.Table, indexSampleRows), categories).Scan(&sampled, &nonNull, &distinct, &width)
+	if err != nil {
+		return err
+	}
+	if sampled == 0 {
+		c.Rows = 0
+		return nil
+	}
+
+	filled := float64(nonNull) / float64(sampled)
+	switch {
+	case c.Kind == IndexGIN:
+		c.Selectivity = containSelectivity
+		c.EstimatedBytes = int64(float64(c.Rows) * width / 2)
+	case c.rangeFilter:
+		c.Selectivity = filled * rangeSelectivity
+		c.EstimatedBytes = int64(float64(c.Rows) * filled * (width + 24))
+	default:
+		c.Selectivity = filled / float64(max(distinct, 1))
+		c.EstimatedBytes = int64(float64(c.Rows) * filled * (width + 24))
+	}
+	c.RowsPerQuery = float64(c.Rows) * c.Selectivity
+	return nil
+}
+
+func quotedArrayItems(items []string) []string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(item) + `"`
+	}
+	return quoted
+}
+
+// createSQL은 인덱스 생성문입니다. 하이퍼테이블은 CONCURRENTLY를 지원하지 않으므로 청크마다 나눠 만듭니다.
+func (c *indexCandidate) createSQL(hypertable bool) string {
+	var columns string
+	if c.Kind == IndexGIN {
+		columns = "USING gin (" + c.Expression + ")"
+	} else {
+		columns = "(category_name, (" + c.Expression + "))"
+	}
+	if hypertable {
+		return fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON public.%s %s WITH (timescaledb.transaction_per_chunk)", c.Name, c.Table, columns)
+	}
+	return fmt.Sprintf("CREATE INDEX CONCURRENTLY IF NOT EXISTS %s ON public.%s %s", c.Name, c.Table, columns)
+}
+
+// indexState는 이름의 인덱스가 없으면 "", 있으면 "valid" 또는 "invalid"(만들다 실패함)를 반환합니다
+func indexState(db DBTX, name string) (string, error) {
+	var valid bool
+	err := db.QueryRow(`SELECT indisvalid FROM pg_index WHERE indexrelid = to_regclass('public.' || $1)`, name).Scan(&valid)
+	switch {
+	case err == sql.ErrNoRows:
+		return "", nil
+	case err != nil:
+		return "", err
+	case valid:
+		return "valid", nil
+	}
+	return "invalid", nil
+}
+
+// existingGINIndex는 같은 컬럼의 GIN 인덱스가 있으면 그 이름을 반환합니다
+func existingGINIndex(db DBTX, table, expr string) (string, error) {
+	column := strings.Fields(expr)[0]
+	var name string
+	err := db.QueryRow(`
+		SELECT indexname FROM pg_indexes
+		WHERE schemaname = 'public' AND tablename = $1 AND indexdef ILIKE '%USING gin (' || $2 || '%'
+		LIMIT 1`, table, column).Scan(&name)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return name, err
+}
+
+// isHypertable은 TimescaleDB 하이퍼테이블인지 확인합니다 (TimescaleDB가 없으면 false)
+func isHypertable(db DBTX, table string) bool {
+	var exists bool
+	err := db.QueryRow(`SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'timescaledb')`).Scan(&exists)
+	if err != nil || !exists {
+		return false
+	}
+	err = db.QueryRow(`SELECT EXISTS (SELECT 1 FROM timescaledb_information.hypertables WHERE hypertable_name = $1)`, table).Scan(&exists)
+	return err == nil && exists
+}
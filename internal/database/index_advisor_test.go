@@ -0,0 +1,86 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tmidb/tmidb-core/internal/query"
+	"github.com/tmidb/tmidb-core/internal/schema"
+)
+
+func TestPayloadExpr(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{`SELECT * FROM ts_obs WHERE payload->>'status' = $1`, []string{`payload ->> 'status'`}},
+		{`SELECT * FROM ts_obs WHERE (payload ->> 'temp')::float > 30`, []string{`(payload ->> 'temp')::double precision`}},
+		{`SELECT * FROM ts_obs WHERE payload #>> '{vitals,bp}' = $1 AND payload->>'ward' = 'ICU'`,
+			[]string{`payload #>> '{vitals,bp}'`, `payload ->> 'ward'`}},
+		{`SELECT * FROM ts_obs WHERE payload #>> '{"a","b"}' = $1`, []string{`payload #>> '{a,b}'`}},
+		// 경로에 다른 문자가 있으면 인덱스 식으로 쓰지 않음
+		{`SELECT * FROM ts_obs WHERE payload->>'x); DROP TABLE t; --' = $1`, nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, m := range payloadPathPattern.FindAllStringSubmatch(tt.query, -1) {
+			cast := m[4]
+			if m[1] == "" {
+				cast = ""
+			}
+			if expr, _, ok := payloadExpr(m[2], m[3], cast); ok {
+				got = append(got, expr)
+			}
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("payload expressions of %q = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestAdvisedIndexName(t *testing.T) {
+	text := advisedIndexName("tc", "vitals.bp", query.IndexText, query.IndexExpr([]string{"vitals", "bp"}, query.IndexText))
+	number := advisedIndexName("tc", "vitals.bp", query.IndexNumber, query.IndexExpr([]string{"vitals", "bp"}, query.IndexNumber))
+	if !strings.HasPrefix(text, "idx_tc_vitals_bp_txt_") || !strings.HasPrefix(number, "idx_tc_vitals_bp_num_") {
+		t.Errorf("names = %s, %s", text, number)
+	}
+	if again := advisedIndexName("tc", "vitals.bp", query.IndexText, query.IndexExpr([]string{"vitals", "bp"}, query.IndexText)); again != text {
+		t.Errorf("name is not stable: %s != %s", again, text)
+	}
+	long := advisedIndexName("obs", strings.Repeat("Very-Long-Field.", 10), "double precision", "(payload #>> '{x}')::double precision")
+	if len(long) > 63 || strings.ContainsAny(long, ".- ") {
+		t.Errorf("name %q is not a valid identifier", long)
+	}
+}
+
+func TestSchemaCheck(t *testing.T) {
+	s, err := schema.CompileJSON([]byte(`{"type": "object", "properties": {
+		"temp": {"type": "number"},
+		"ward": {"type": "string"},
+		"vitals": {"type": "object", "properties": {"bp": {"type": "integer"}}}
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs := categorySchema{orgID: "org", category: "vitals", schema: s}
+
+	check := newSchemaCheck()
+	if !check.add(cs, []string{"vitals", "bp"}, true) || !check.add(cs, []string{"ward"}, false) {
+		t.Error("declared fields rejected")
+	}
+	if check.add(cs, []string{"ward"}, true) {
+		t.Error("numeric comparison on a string field accepted")
+	}
+	if check.add(cs, []string{"missing"}, false) {
+		t.Error("undeclared field accepted")
+	}
+	if got := sortedKeys(check.types); strings.Join(got, ",") != "integer,string" {
+		t.Errorf("types = %v", got)
+	}
+
+	mismatch := newSchemaCheck()
+	mismatch.add(cs, []string{"ward"}, true)
+	if !strings.Contains(mismatch.reason(), "number") {
+		t.Errorf("reason = %q", mismatch.reason())
+	}
+}
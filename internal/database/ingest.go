@@ -0,0 +1,159 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// TargetCategoryLink는 타겟이 카테고리에 연결된 조직과 스키마 정보입니다.
+type TargetCategoryLink struct {
+	OrgID            string
+	SchemaVersion    int
+	SchemaDefinition string
+	Deleted          bool // 휴지통에 있는 연결 (복원하기 전에는 쓰지 않음)
+}
+
+// GetTargetCategoryLink는 타겟-카테고리 연결과 그 스키마를 조회합니다 (없으면 sql.ErrNoRows).
+// 휴지통에 있는 연결도 Deleted로 표시해 반환하므로 새로 연결하지 않고 거부할 수 있습니다.
+func GetTargetCategoryLink(db DBTX, targetID, category string) (*TargetCategoryLink, error) {
+	var link TargetCategoryLink
+	err := db.QueryRow(
+		`SELECT tc.org_id::text, tc.schema_version, COALESCE(cs.schema_definition::text, ''), tc.deleted_at IS NOT NULL
+		 FROM target_categories tc
+		 LEFT JOIN category_schemas cs
+		   ON cs.org_id = tc.org_id AND cs.category_name = tc.category_name AND cs.version = tc.schema_version
+		 WHERE tc.target_id::text = $1 AND tc.category_name = $2`,
+		targetID, category,
+	).Scan(&link.OrgID, &link.SchemaVersion, &link.SchemaDefinition, &link.Deleted)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// GetActiveCategorySchema는 조직 카테고리의 최신 활성 스키마를 조회합니다 (없으면 sql.ErrNoRows).
+func GetActiveCategorySchema(db DBTX, orgID, category string) (*TargetCategoryLink, error) {
+	link := TargetCategoryLink{OrgID: orgID}
+	err := db.QueryRow(
+		`SELECT version, schema_definition::text
+		 FROM category_schemas
+		 WHERE org_id::text = $1 AND category_name = $2 AND is_active = true
+		 ORDER BY version DESC
+		 LIMIT 1`,
+		orgID, category,
+	).Scan(&link.SchemaVersion, &link.SchemaDefinition)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// ListActiveCategoryNames는 활성 스키마가 있는 카테고리 이름을 모든 조직에 걸쳐 조회합니다.
+func ListActiveCategoryNames(db DBTX) ([]string, error) {
+	rows, err := db.Query("SELECT DISTINCT category_name FROM category_schemas WHERE is_active = true ORDER BY category_name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// UpsertTargetCategoryData는 타겟-카테고리 연결을 만들거나 category_data를 갱신합니다.
+// 이미 연결된 경우 조직과 스키마 버전은 바꾸지 않습니다.
+func UpsertTargetCategoryData(db DBTX, targetID string, link *TargetCategoryLink, category, dataJSON string) error {
+	_, err := db.Exec(
+		`INSERT INTO target_categories (target_id, org_id, category_name, schema_version, category_data)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (target_id, category_name) DO UPDATE SET
+			category_data = EXCLUDED.category_data,
+			updated_at = now()`,
+		targetID, link.OrgID, category, link.SchemaVersion, dataJSON,
+	)
+	return err
+}
+
+// DeleteTargetCategoryData는 조직 안의 타겟-카테고리 연결을 삭제하고 삭제한 행 수를 반환합니다 (휴지통에 있는 연결은 제외).
+func DeleteTargetCategoryData(db DBTX, orgID, targetID, category string) (int64, error) {
+	result, err := db.Exec(
+		"DELETE FROM target_categories WHERE org_id::text = $1 AND target_id::text = $2 AND category_name = $3 AND deleted_at IS NULL",
+		orgID, targetID, category,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// EnsureTargetLink는 타겟과 타겟-카테고리 연결이 없으면 만들고 연결된 조직을 반환합니다.
+// 이미 있는 타겟과 연결은 그대로 두므로, 반환한 조직이 요청한 조직과 다르면 쓰지 말아야 합니다.
+// 연결이 휴지통에 있으면 ErrInRecycleBin을 반환합니다.
+func EnsureTargetLink(db DBTX, targetID, name string, link *TargetCategoryLink, category, dataJSON string) (string, error) {
+	if _, err := db.Exec(
+		"INSERT INTO target (target_id, name) VALUES ($1, $2) ON CONFLICT (target_id) DO NOTHING",
+		targetID, name,
+	); err != nil {
+		return "", err
+	}
+	if _, err := db.Exec(
+		`INSERT INTO target_categories (target_id, org_id, category_name, schema_version, category_data)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (target_id, category_name) DO NOTHING`,
+		targetID, link.OrgID, category, link.SchemaVersion, dataJSON,
+	); err != nil {
+		return "", err
+	}
+
+	var orgID string
+	var deleted bool
+	err := db.QueryRow(
+		"SELECT org_id::text, deleted_at IS NOT NULL FROM target_categories WHERE target_id = $1 AND category_name = $2",
+		targetID, category,
+	).Scan(&orgID, &deleted)
+	if err == nil && deleted {
+		return orgID, fmt.Errorf("%w: target %s in category %s", ErrInRecycleBin, targetID, category)
+	}
+	return orgID, err
+}
+
+// InsertTimeSeriesPoint는 ts_obs에 관측값 하나를 저장합니다 (같은 시각이면 덮어씀).
+func InsertTimeSeriesPoint(db DBTX, targetID, category string, ts time.Time, payloadJSON string) error {
+	_, err := db.Exec(
+		`INSERT INTO ts_obs (target_id, category_name, ts, payload)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (target_id, category_name, ts) DO UPDATE SET
+			payload = EXCLUDED.payload`,
+		targetID, category, ts, payloadJSON,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert time series point: %w", err)
+	}
+	return nil
+}
+
+// WithSavepoint는 트랜잭션 안에서 fn을 세이브포인트로 감싸 실행합니다.
+// fn이 실패하면 세이브포인트까지 되돌리고 fn의 에러를 반환하며, 트랜잭션은 계속 사용할 수 있습니다.
+func WithSavepoint(tx *sql.Tx, name string, fn func() error) (fnErr error, err error) {
+	if _, err := tx.Exec("SAVEPOINT " + name); err != nil {
+		return nil, err
+	}
+	if fnErr := fn(); fnErr != nil {
+		if _, err := tx.Exec("ROLLBACK TO SAVEPOINT " + name); err != nil {
+			return fnErr, err
+		}
+		return fnErr, nil
+	}
+	if _, err := tx.Exec("RELEASE SAVEPOINT " + name); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
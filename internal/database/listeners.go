@@ -0,0 +1,136 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+)
+
+const listenerColumns = `listener_id, COALESCE(org_id, ''), category_name, COALESCE(description, ''),
+	COALESCE(is_active, false), conditions, actions, created_at, updated_at`
+
+func scanListener(row interface{ Scan(...interface{}) error }) (*Listener, error) {
+	var l Listener
+	var conditions, actions []byte
+	if err := row.Scan(&l.ListenerID, &l.OrgID, &l.CategoryName, &l.Description, &l.IsActive,
+		&conditions, &actions, &l.CreatedAt, &l.UpdatedAt); err != nil {
+		return nil, err
+	}
+	l.Conditions = conditions
+	l.Actions = actions
+	return &l, nil
+}
+
+// jsonArrayOrEmpty는 JSON 값을 문자열로 반환합니다 (비어 있으면 빈 배열)
+func jsonArrayOrEmpty(raw []byte) string {
+	if len(raw) == 0 {
+		return "[]"
+	}
+	return string(raw)
+}
+
+// GetListener는 조직의 리스너 하나를 조회합니다 (없으면 sql.ErrNoRows).
+func GetListener(db DBTX, id, orgID string) (*Listener, error) {
+	return scanListener(db.QueryRow("SELECT "+listenerColumns+" FROM listeners WHERE listener_id = $1 AND org_id = $2", id, orgID))
+}
+
+// ListActiveListeners는 모든 조직의 활성 리스너를 조회합니다 (리스너 런타임용).
+func ListActiveListeners(db DBTX) ([]Listener, error) {
+	rows, err := db.Query("SELECT " + listenerColumns + " FROM listeners WHERE is_active = true ORDER BY listener_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var listeners []Listener
+	for rows.Next() {
+		l, err := scanListener(rows)
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, *l)
+	}
+	return listeners, rows.Err()
+}
+
+// UpdateListener는 리스너의 설명, 활성 상태, 조건과 액션을 갱신합니다.
+func UpdateListener(db DBTX, listener *Listener) error {
+	err := db.QueryRow(
+		`UPDATE listeners
+		 SET description = $3, is_active = $4, conditions = $5::jsonb, actions = $6::jsonb, updated_at = now()
+		 WHERE listener_id = $1 AND org_id = $2
+		 RETURNING updated_at`,
+		listener.ListenerID, listener.OrgID, listener.Description, listener.IsActive,
+		jsonArrayOrEmpty(listener.Conditions), jsonArrayOrEmpty(listener.Actions),
+	).Scan(&listener.UpdatedAt)
+	return err
+}
+
+// ListenerStats는 리스너 실행 통계입니다.
+type ListenerStats struct {
+	ListenerID       string     `json:"listener_id"`
+	Evaluated        int64      `json:"evaluated"`
+	Matched          int64      `json:"matched"`
+	ActionsSucceeded int64      `json:"actions_succeeded"`
+	ActionsFailed    int64      `json:"actions_failed"`
+	LastMatchedAt    *time.Time `json:"last_matched_at,omitempty"`
+	LastError        string     `json:"last_error,omitempty"`
+	LastErrorAt      *time.Time `json:"last_error_at,omitempty"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// AddListenerStats는 통계 증가분을 누적합니다. 여러 data-manager가 동시에 더해도 안전합니다.
+// 삭제된 리스너의 증가분은 무시합니다.
+func AddListenerStats(db DBTX, delta ListenerStats) error {
+	_, err := db.Exec(
+		`INSERT INTO listener_stats AS s
+			(listener_id, evaluated, matched, actions_succeeded, actions_failed, last_matched_at, last_error, last_error_at)
+		 SELECT $1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8
+		 WHERE EXISTS (SELECT 1 FROM listeners WHERE listener_id = $1)
+		 ON CONFLICT (listener_id) DO UPDATE SET
+			evaluated = s.evaluated + EXCLUDED.evaluated,
+			matched = s.matched + EXCLUDED.matched,
+			actions_succeeded = s.actions_succeeded + EXCLUDED.actions_succeeded,
+			actions_failed = s.actions_failed + EXCLUDED.actions_failed,
+			last_matched_at = GREATEST(s.last_matched_at, EXCLUDED.last_matched_at),
+			last_error = COALESCE(EXCLUDED.last_error, s.last_error),
+			last_error_at = GREATEST(s.last_error_at, EXCLUDED.last_error_at),
+			updated_at = now()`,
+		delta.ListenerID, delta.Evaluated, delta.Matched, delta.ActionsSucceeded, delta.ActionsFailed,
+		delta.LastMatchedAt, delta.LastError, delta.LastErrorAt,
+	)
+	return err
+}
+
+// GetListenerStats는 조직 리스너들의 통계를 리스너 ID별로 조회합니다.
+func GetListenerStats(db DBTX, orgID string) (map[string]ListenerStats, error) {
+	rows, err := db.Query(
+		`SELECT s.listener_id, s.evaluated, s.matched, s.actions_succeeded, s.actions_failed,
+		        s.last_matched_at, COALESCE(s.last_error, ''), s.last_error_at, s.updated_at
+		 FROM listener_stats s
+		 JOIN listeners l ON l.listener_id = s.listener_id
+		 WHERE l.org_id = $1`,
+		orgID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make(map[string]ListenerStats)
+	for rows.Next() {
+		var st ListenerStats
+		var lastMatched, lastError sql.NullTime
+		if err := rows.Scan(&st.ListenerID, &st.Evaluated, &st.Matched, &st.ActionsSucceeded, &st.ActionsFailed,
+			&lastMatched, &st.LastError, &lastError, &st.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if lastMatched.Valid {
+			st.LastMatchedAt = &lastMatched.Time
+		}
+		if lastError.Valid {
+			st.LastErrorAt = &lastError.Time
+		}
+		stats[st.ListenerID] = st
+	}
+	return stats, rows.Err()
+}
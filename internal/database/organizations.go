@@ -0,0 +1,205 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// 조직 관리 오류
+var (
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrOrganizationExists   = errors.New("organization name already exists")
+)
+
+// Organization은 조직과 조직에 속한 리소스 수입니다
+type Organization struct {
+	OrgID      string    `json:"org_id"`
+	Name       string    `json:"name"`
+	CreatedAt  time.Time `json:"created_at"`
+	Users      int       `json:"users"`
+	Categories int       `json:"categories"`
+	Targets    int       `json:"targets"`
+	Tokens     int       `json:"tokens"`
+}
+
+// OrganizationDeletion은 조직 삭제로 함께 지워진 리소스입니다.
+// StoragePaths는 파일 저장소에서 따로 지워야 하는 첨부 파일 경로입니다.
+type OrganizationDeletion struct {
+	Organization
+	Listeners    int      `json:"listeners"`
+	Attachments  int      `json:"attachments"`
+	StoragePaths []string `json:"storage_paths,omitempty"`
+}
+
+const organizationColumns = `
+	o.org_id::text, o.name, o.created_at,
+	(SELECT COUNT(*) FROM users u WHERE u.org_id = o.org_id),
+	(SELECT COUNT(DISTINCT category_name) FROM category_schemas cs WHERE cs.org_id = o.org_id),
+	(SELECT COUNT(DISTINCT target_id) FROM target_categories tc WHERE tc.org_id = o.org_id),
+	(SELECT COUNT(*) FROM auth_tokens a WHERE a.org_id = o.org_id)
+	  + (SELECT COUNT(*) FROM user_access_tokens ut WHERE ut.org_id = o.org_id)`
+
+func scanOrganization(row interface{ Scan(...interface{}) error }) (*Organization, error) {
+	var o Organization
+	if err := row.Scan(&o.OrgID, &o.Name, &o.CreatedAt, &o.Users, &o.Categories, &o.Targets, &o.Tokens); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// ListOrganizations는 모든 조직을 이름 순으로 조회합니다
+func ListOrganizations(db DBTX) ([]Organization, error) {
+	rows, err := db.Query("SELECT " + organizationColumns + " FROM organizations o ORDER BY o.name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orgs := []Organization{}
+	for rows.Next() {
+		o, err := scanOrganization(rows)
+		if err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, *o)
+	}
+	return orgs, rows.Err()
+}
+
+// GetOrganization은 ID 또는 이름으로 조직을 조회합니다
+func GetOrganization(db DBTX, ref string) (*Organization, error) {
+	o, err := scanOrganization(db.QueryRow(
+		"SELECT "+organizationColumns+" FROM organizations o WHERE o.org_id::text = $1 OR o.name = $1", ref))
+	if err == sql.ErrNoRows {
+		return nil, ErrOrganizationNotFound
+	}
+	return o, err
+}
+
+// CreateOrganization은 새 조직을 만듭니다
+func CreateOrganization(db DBTX, name string) (*Organization, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("organization name required")
+	}
+
+	o := &Organization{Name: name}
+	err := db.QueryRow(
+		"INSERT INTO organizations (name) VALUES ($1) RETURNING org_id::text, created_at", name,
+	).Scan(&o.OrgID, &o.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return nil, ErrOrganizationExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// DeleteOrganization은 조직과 조직의 사용자, 토큰, 스키마, 데이터를 한 트랜잭션으로 삭제합니다.
+// 사용자, 토큰, 스키마, 타겟 카테고리(와 관측값)는 외래 키로 함께 지워지고,
+// 외래 키가 없는 리스너와 첨부 파일, 다른 조직과 연결되지 않은 타겟은 여기서 지웁니다.
+// 삭제된 토큰은 감사 로그에 남깁니다.
+func DeleteOrganization(db *sql.DB, ref, actor string) (*OrganizationDeletion, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	org, err := GetOrganization(tx, ref)
+	if err != nil {
+		return nil, err
+	}
+	// 삭제하는 동안 조직에 새 데이터나 사용자가 추가되지 않도록 조직 행을 잠금
+	if _, err := tx.Exec("SELECT 1 FROM organizations WHERE org_id = $1 FOR UPDATE", org.OrgID); err != nil {
+		return nil, err
+	}
+	deletion := &OrganizationDeletion{Organization: *org}
+
+	tokens, err := ListTokens(tx, org.OrgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tokens: %w", err)
+	}
+	for _, t := range tokens {
+		if err := RecordTokenAudit(tx, org.OrgID, t.TokenID, t.Kind, TokenActionDelete, actor,
+			map[string]interface{}{"reason": "organization deleted"}); err != nil {
+			return nil, fmt.Errorf("failed to record token audit: %w", err)
+		}
+	}
+
+	paths, err := queryStrings(tx, "DELETE FROM file_attachments WHERE org_id = $1 RETURNING s3_path", org.OrgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete attachments: %w", err)
+	}
+	deletion.StoragePaths = paths
+
+	result, err := tx.Exec("DELETE FROM listeners WHERE org_id = $1", org.OrgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete listeners: %w", err)
+	}
+	listeners, _ := result.RowsAffected()
+	deletion.Listeners = int(listeners)
+
+	// 타겟 테이블에는 조직이 없으므로 조직 삭제 전에 연결된 타겟을 기억해 둠
+	targetIDs, err := queryStrings(tx, "SELECT DISTINCT target_id::text FROM target_categories WHERE org_id = $1", org.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM organizations WHERE org_id = $1", org.OrgID); err != nil {
+		return nil, fmt.Errorf("failed to delete organization: %w", err)
+	}
+	// 문서 이력은 조직과 연결돼 있지 않으므로 따로 지움 (위에서 지워진 문서의 delete 기록 포함)
+	if _, err := tx.Exec("DELETE FROM target_category_history WHERE org_id = $1", org.OrgID); err != nil {
+		return nil, fmt.Errorf("failed to delete document history: %w", err)
+	}
+
+	// 남은 카테고리가 없는 타겟 삭제 (위치 이력과 남은 첨부 파일도 함께 지워짐)
+	if len(targetIDs) > 0 {
+		const orphaned = `target_id::text = ANY($1)
+			AND NOT EXISTS (SELECT 1 FROM target_categories tc WHERE tc.target_id = t.target_id)`
+		paths, err := queryStrings(tx, `
+			SELECT fa.s3_path FROM file_attachments fa
+			JOIN target t ON t.target_id = fa.target_id
+			WHERE t.`+orphaned, pq.Array(targetIDs))
+		if err != nil {
+			return nil, err
+		}
+		deletion.StoragePaths = append(deletion.StoragePaths, paths...)
+		if _, err := tx.Exec("DELETE FROM target t WHERE t."+orphaned, pq.Array(targetIDs)); err != nil {
+			return nil, fmt.Errorf("failed to delete orphaned targets: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	deletion.Attachments = len(deletion.StoragePaths)
+	return deletion, nil
+}
+
+// queryStrings는 한 열짜리 조회 결과를 문자열 목록으로 읽습니다
+func queryStrings(db DBTX, query string, args ...interface{}) ([]string, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
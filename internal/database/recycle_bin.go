@@ -0,0 +1,245 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// 휴지통 항목 종류
+const (
+	RecycleKindTarget   = "target"   // 삭제된 타겟 (함께 삭제된 카테고리 문서 포함)
+	RecycleKindCategory = "category" // 따로 삭제된 타겟의 카테고리 문서
+)
+
+// 휴지통 오류
+var (
+	ErrNotInRecycleBin = errors.New("item is not in the recycle bin")
+	ErrInRecycleBin    = errors.New("item is in the recycle bin; restore it before writing to it")
+)
+
+// RecycleBinItem은 휴지통의 항목 하나입니다. PurgeAt이 지나면 영구 삭제됩니다.
+type RecycleBinItem struct {
+	Kind       string    `json:"kind"`
+	TargetID   string    `json:"target_id"`
+	Name       string    `json:"name"`
+	Category   string    `json:"category,omitempty"`
+	Categories []string  `json:"categories,omitempty"` // 타겟과 함께 삭제된 카테고리
+	DeletedAt  time.Time `json:"deleted_at"`
+	DeletedBy  string    `json:"deleted_by,omitempty"`
+	PurgeAt    time.Time `json:"purge_at"`
+}
+
+// RecycleBinPurge는 영구 삭제 결과입니다. StoragePaths는 파일 저장소에서 따로 지워야 하는 첨부 파일 경로입니다.
+type RecycleBinPurge struct {
+	Targets      int64
+	Categories   int64
+	StoragePaths []string
+}
+
+// softDeleteTargets는 타겟과 아직 삭제되지 않은 카테고리 문서에 같은 삭제 시각(트랜잭션 시각)을 표시합니다.
+// 복원할 때 이 시각이 같은 카테고리 문서만 함께 되살립니다.
+func softDeleteTargets(tx *sql.Tx, targetIDs []string, actor string) error {
+	if _, err := tx.Exec(
+		"UPDATE target SET deleted_at = now(), deleted_by = $2 WHERE target_id::text = ANY($1)",
+		pq.Array(targetIDs), actor,
+	); err != nil {
+		return err
+	}
+	_, err := tx.Exec(
+		"UPDATE target_categories SET deleted_at = now(), deleted_by = $2 WHERE target_id::text = ANY($1) AND deleted_at IS NULL",
+		pq.Array(targetIDs), actor,
+	)
+	return err
+}
+
+// SoftDeleteTargetCategoryData는 조직 안의 타겟-카테고리 문서를 휴지통으로 보내고 표시한 행 수를 반환합니다.
+// 문서의 관측값은 그대로 남지만 문서를 복원할 때까지 조회되지 않습니다.
+func SoftDeleteTargetCategoryData(db DBTX, orgID, targetID, category, actor string) (int64, error) {
+	result, err := db.Exec(
+		`UPDATE target_categories SET deleted_at = now(), deleted_by = $4
+		 WHERE org_id::text = $1 AND target_id::text = $2 AND category_name = $3 AND deleted_at IS NULL`,
+		orgID, strings.ToLower(targetID), category, actor,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ListRecycleBin은 조직의 휴지통을 최근 삭제 순으로 조회합니다. kind가 비어 있으면 모든 종류입니다.
+// 타겟과 함께 삭제된 카테고리 문서는 타겟 항목의 Categories에만 나옵니다.
+func ListRecycleBin(db DBTX, orgID, kind string, retention time.Duration, limit int) ([]RecycleBinItem, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := db.Query(`
+		SELECT kind, target_id, name, category, categories, deleted_at, deleted_by FROM (
+			SELECT 'target' AS kind, t.target_id::text AS target_id, t.name, '' AS category,
+			       ARRAY(SELECT tc.category_name FROM target_categories tc
+			             WHERE tc.target_id = t.target_id AND tc.deleted_at = t.deleted_at ORDER BY 1) AS categories,
+			       t.deleted_at, COALESCE(t.deleted_by, '') AS deleted_by
+			FROM target t
+			WHERE t.deleted_at IS NOT NULL
+			  AND (EXISTS (SELECT 1 FROM target_categories tc WHERE tc.target_id = t.target_id AND tc.org_id::text = $1)
+			       OR EXISTS (SELECT 1 FROM file_attachments fa WHERE fa.target_id = t.target_id AND fa.org_id = $1))
+			UNION ALL
+			SELECT 'category', tc.target_id::text, t.name, tc.category_name, NULL,
+			       tc.deleted_at, COALESCE(tc.deleted_by, '')
+			FROM target_categories tc
+			JOIN target t ON t.target_id = tc.target_id
+			WHERE tc.org_id::text = $1 AND tc.deleted_at IS NOT NULL
+			  AND t.deleted_at IS DISTINCT FROM tc.deleted_at
+		) bin
+		WHERE $2 = '' OR kind = $2
+		ORDER BY deleted_at DESC, target_id, category
+		LIMIT $3
+	`, orgID, kind, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []RecycleBinItem{}
+	for rows.Next() {
+		var item RecycleBinItem
+		if err := rows.Scan(&item.Kind, &item.TargetID, &item.Name, &item.Category, pq.Array(&item.Categories),
+			&item.DeletedAt, &item.DeletedBy); err != nil {
+			return nil, err
+		}
+		item.PurgeAt = item.DeletedAt.Add(retention)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// RestoreTarget은 휴지통의 타겟과 타겟과 함께 삭제된 카테고리 문서를 되살립니다.
+// 타겟보다 먼저 따로 삭제된 카테고리 문서는 휴지통에 남습니다.
+func RestoreTarget(db *sql.DB, orgID, targetID, actor string) (*TargetFootprint, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	if err := SetChangeActor(tx, actor); err != nil {
+		return nil, err
+	}
+
+	var name string
+	var deletedAt sql.NullTime
+	err = tx.QueryRow(`
+		SELECT t.name, t.deleted_at FROM target t
+		WHERE t.target_id::text = $1
+		  AND (EXISTS (SELECT 1 FROM target_categories tc WHERE tc.target_id = t.target_id AND tc.org_id::text = $2)
+		       OR EXISTS (SELECT 1 FROM file_attachments fa WHERE fa.target_id = t.target_id AND fa.org_id = $2))
+		FOR UPDATE OF t
+	`, strings.ToLower(targetID), orgID).Scan(&name, &deletedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ErrTargetNotFound, targetID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !deletedAt.Valid {
+		return nil, fmt.Errorf("%w: target %s", ErrNotInRecycleBin, targetID)
+	}
+
+	if _, err := tx.Exec(
+		"UPDATE target_categories SET deleted_at = NULL, deleted_by = NULL WHERE target_id::text = $1 AND deleted_at = $2",
+		strings.ToLower(targetID), deletedAt.Time,
+	); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(
+		"UPDATE target SET deleted_at = NULL, deleted_by = NULL WHERE target_id::text = $1", strings.ToLower(targetID),
+	); err != nil {
+		return nil, err
+	}
+
+	footprints, err := targetFootprints(tx, orgID, []string{targetID}, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := RecordTargetAudit(tx, orgID, TargetActionRestore, []string{footprints[0].TargetID}, actor,
+		map[string]interface{}{"name": name, "deleted_at": deletedAt.Time}); err != nil {
+		return nil, fmt.Errorf("failed to record target audit: %w", err)
+	}
+	return &footprints[0], tx.Commit()
+}
+
+// RestoreTargetCategoryData는 따로 삭제된 타겟의 카테고리 문서를 되살립니다.
+// 타겟이 휴지통에 있으면 타겟을 먼저 복원해야 하므로 ErrNotInRecycleBin을 반환합니다.
+func RestoreTargetCategoryData(db *sql.DB, orgID, targetID, category, actor string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := SetChangeActor(tx, actor); err != nil {
+		return err
+	}
+
+	targetID = strings.ToLower(targetID)
+	var deletedAt time.Time
+	err = tx.QueryRow(`
+		SELECT tc.deleted_at FROM target_categories tc
+		JOIN target t ON t.target_id = tc.target_id
+		WHERE tc.org_id::text = $1 AND tc.target_id::text = $2 AND tc.category_name = $3
+		  AND tc.deleted_at IS NOT NULL AND t.deleted_at IS NULL
+		FOR UPDATE OF tc
+	`, orgID, targetID, category).Scan(&deletedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: %s in category %s", ErrNotInRecycleBin, targetID, category)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(
+		"UPDATE target_categories SET deleted_at = NULL, deleted_by = NULL WHERE target_id::text = $1 AND category_name = $2",
+		targetID, category,
+	); err != nil {
+		return err
+	}
+
+	if err := RecordTargetAudit(tx, orgID, TargetActionRestore, []string{targetID}, actor,
+		map[string]interface{}{"category": category, "deleted_at": deletedAt}); err != nil {
+		return fmt.Errorf("failed to record target audit: %w", err)
+	}
+	return tx.Commit()
+}
+
+// PurgeRecycleBin은 before보다 먼저 삭제된 타겟과 카테고리 문서를 관측값과 함께 영구 삭제합니다
+func PurgeRecycleBin(db *sql.DB, before time.Time) (*RecycleBinPurge, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	purge := &RecycleBinPurge{}
+	if purge.StoragePaths, err = queryStrings(tx, `
+		SELECT fa.s3_path FROM file_attachments fa
+		JOIN target t ON t.target_id = fa.target_id
+		WHERE t.deleted_at < $1`, before); err != nil {
+		return nil, err
+	}
+	result, err := tx.Exec("DELETE FROM target WHERE deleted_at < $1", before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge targets: %w", err)
+	}
+	if purge.Targets, err = result.RowsAffected(); err != nil {
+		return nil, err
+	}
+	result, err = tx.Exec("DELETE FROM target_categories WHERE deleted_at < $1", before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge category data: %w", err)
+	}
+	if purge.Categories, err = result.RowsAffected(); err != nil {
+		return nil, err
+	}
+	return purge, tx.Commit()
+}
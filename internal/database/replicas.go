@@ -0,0 +1,186 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/config"
+)
+
+// replica는 읽기 전용 복제본 연결 하나입니다
+type replica struct {
+	host    string
+	db      *sql.DB
+	healthy atomic.Bool
+
+	mu        sync.Mutex
+	lag       time.Duration
+	lastError string
+	checkedAt time.Time
+}
+
+// ReplicaStatus는 복제본의 마지막 상태 확인 결과입니다
+type ReplicaStatus struct {
+	Host       string    `json:"host"`
+	Healthy    bool      `json:"healthy"`
+	LagSeconds float64   `json:"lag_seconds"`
+	Error      string    `json:"error,omitempty"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+var (
+	replicas     []*replica
+	replicaNext  atomic.Uint64
+	stopReplicas context.CancelFunc
+)
+
+// InitReplicas는 설정된 읽기 복제본에 연결하고 주기적인 상태 확인을 시작합니다.
+// 시작할 때 연결되지 않는 복제본은 비정상으로 두었다가 상태 확인에서 살아나면 사용합니다.
+func InitReplicas(cfg *config.Config) error {
+	if len(cfg.DatabaseReplicaURLs) == 0 {
+		return nil
+	}
+	for _, dsn := range cfg.DatabaseReplicaURLs {
+		db, err := openDB(dsn)
+		if err != nil {
+			return fmt.Errorf("failed to open replica connection: %v", err)
+		}
+		db.SetMaxOpenConns(25)
+		db.SetMaxIdleConns(5)
+		host := dsn
+		if u, err := url.Parse(dsn); err == nil {
+			host = u.Host
+		}
+		replicas = append(replicas, &replica{host: host, db: db})
+	}
+
+	maxLag := time.Duration(cfg.ReplicaMaxLagSeconds) * time.Second
+	checkReplicas(maxLag)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopReplicas = cancel
+	go func() {
+		ticker := time.NewTicker(time.Duration(cfg.ReplicaHealthInterval) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				checkReplicas(maxLag)
+			}
+		}
+	}()
+	return nil
+}
+
+// GetReadDB는 읽기 전용 조회에 쓸 연결을 반환합니다. 정상인 복제본을 돌아가며 고르고,
+// 복제본이 없거나 모두 비정상이면 기본 DB를 반환합니다.
+// 복제본은 기본 DB보다 늦을 수 있으므로 방금 쓴 데이터를 다시 읽어야 하는 곳에서는 GetDB를 씁니다.
+func GetReadDB() *sql.DB {
+	n := uint64(len(replicas))
+	if n == 0 {
+		return DB
+	}
+	start := replicaNext.Add(1)
+	for i := uint64(0); i < n; i++ {
+		if r := replicas[(start+i)%n]; r.healthy.Load() {
+			return r.db
+		}
+	}
+	return DB
+}
+
+// GetReplicaStatus는 복제본별 마지막 상태 확인 결과를 반환합니다 (복제본이 없으면 nil)
+func GetReplicaStatus() []ReplicaStatus {
+	if len(replicas) == 0 {
+		return nil
+	}
+	statuses := make([]ReplicaStatus, len(replicas))
+	for i, r := range replicas {
+		r.mu.Lock()
+		statuses[i] = ReplicaStatus{
+			Host:       r.host,
+			Healthy:    r.healthy.Load(),
+			LagSeconds: r.lag.Seconds(),
+			Error:      r.lastError,
+			CheckedAt:  r.checkedAt,
+		}
+		r.mu.Unlock()
+	}
+	return statuses
+}
+
+// checkReplicas는 모든 복제본의 상태를 동시에 확인합니다
+func checkReplicas(maxLag time.Duration) {
+	var wg sync.WaitGroup
+	for _, r := range replicas {
+		wg.Add(1)
+		go func(r *replica) {
+			defer wg.Done()
+			r.check(maxLag)
+		}(r)
+	}
+	wg.Wait()
+}
+
+// check는 복제본에 연결되는지, 아직 복제 중인지, 지연이 maxLag 이내인지 확인하고
+// 상태가 바뀌면 기록합니다
+func (r *replica) check(maxLag time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// 받은 WAL을 모두 적용했으면 기본 DB에 쓰기가 없어 마지막 적용 시각이 오래되었어도 지연이 아님
+	var inRecovery bool
+	var lagSeconds float64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT pg_is_in_recovery(),
+			CASE WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+				ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+			END`).Scan(&inRecovery, &lagSeconds)
+	lag := time.Duration(lagSeconds * float64(time.Second))
+	if err == nil && !inRecovery {
+		err = errors.New("server is not in recovery (promoted?)")
+	}
+	if err == nil && maxLag > 0 && lag > maxLag {
+		err = fmt.Errorf("replication lag %s exceeds %s", lag.Round(time.Second), maxLag)
+	}
+
+	r.mu.Lock()
+	first := r.checkedAt.IsZero()
+	r.lag = lag
+	r.checkedAt = time.Now()
+	r.lastError = ""
+	if err != nil {
+		r.lastError = err.Error()
+	}
+	r.mu.Unlock()
+
+	healthy := err == nil
+	if r.healthy.Swap(healthy) != healthy {
+		if healthy {
+			log.Printf("✅ 읽기 복제본 %s 사용 (지연 %s)", r.host, lag.Round(time.Millisecond))
+		} else {
+			log.Printf("⚠️ 읽기 복제본 %s 제외, 기본 DB로 조회: %v", r.host, err)
+		}
+	} else if !healthy && first {
+		log.Printf("⚠️ 읽기 복제본 %s 사용 불가: %v", r.host, err)
+	}
+}
+
+// closeReplicas는 상태 확인을 멈추고 복제본 연결을 닫습니다
+func closeReplicas() {
+	if stopReplicas != nil {
+		stopReplicas()
+	}
+	for _, r := range replicas {
+		r.db.Close()
+	}
+}
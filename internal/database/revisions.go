@@ -0,0 +1,106 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RevisionConflictError는 문서의 현재 리비전이 요청한 리비전과 다를 때의 오류입니다 (낙관적 동시성 제어)
+type RevisionConflictError struct {
+	TargetID string
+	Category string
+	Expected int
+	Current  int // 0이면 문서가 없음
+}
+
+func (e *RevisionConflictError) Error() string {
+	switch {
+	case e.Current == 0:
+		return fmt.Sprintf("target %s has no document in category %s (expected revision %d)", e.TargetID, e.Category, e.Expected)
+	case e.Expected == 0:
+		return fmt.Sprintf("target %s already has a document in category %s (revision %d)", e.TargetID, e.Category, e.Current)
+	default:
+		return fmt.Sprintf("target %s in category %s is at revision %d, not %d", e.TargetID, e.Category, e.Current, e.Expected)
+	}
+}
+
+// SavedDocument는 저장한 카테고리 문서의 리비전과 시각입니다
+type SavedDocument struct {
+	Revision  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// CheckDocumentRevision은 조직의 문서 행을 잠그고 현재 리비전이 expected인지 확인합니다 (0이면 문서가 없어야 함).
+// 확인부터 쓰기까지 다른 쓰기가 끼어들지 않도록 쓰기와 같은 트랜잭션에서 호출해야 합니다.
+// 휴지통에 있는 문서는 없는 것으로 봅니다.
+func CheckDocumentRevision(tx *sql.Tx, orgID, targetID, category string, expected int) error {
+	var current int
+	err := tx.QueryRow(
+		`SELECT revision FROM target_categories
+		 WHERE org_id::text = $1 AND target_id::text = $2 AND category_name = $3 AND deleted_at IS NULL
+		 FOR UPDATE`,
+		orgID, strings.ToLower(targetID), category,
+	).Scan(&current)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if current != expected {
+		return &RevisionConflictError{TargetID: targetID, Category: category, Expected: expected, Current: current}
+	}
+	return nil
+}
+
+// SaveTargetCategoryData는 조직의 타겟-카테고리 문서를 만들거나 바꾸고 저장한 리비전을 반환합니다.
+// expected가 nil이 아니면 현재 리비전이 그 값일 때만 쓰고, 아니면 *RevisionConflictError를 반환합니다.
+// 다른 조직의 문서면 ErrTargetShared, 휴지통에 있으면 ErrInRecycleBin입니다.
+func SaveTargetCategoryData(tx *sql.Tx, orgID, targetID, category string, schemaVersion int, dataJSON string, expected *int) (*SavedDocument, error) {
+	var expectedArg sql.NullInt64
+	if expected != nil {
+		if err := CheckDocumentRevision(tx, orgID, targetID, category, *expected); err != nil {
+			return nil, err
+		}
+		expectedArg = sql.NullInt64{Int64: int64(*expected), Valid: true}
+	}
+
+	var doc SavedDocument
+	err := tx.QueryRow(
+		`INSERT INTO target_categories (org_id, target_id, category_name, schema_version, category_data)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (target_id, category_name) DO UPDATE SET
+			schema_version = EXCLUDED.schema_version,
+			category_data = EXCLUDED.category_data,
+			updated_at = now()
+		 WHERE target_categories.org_id = EXCLUDED.org_id AND target_categories.deleted_at IS NULL
+		   AND ($6::int IS NULL OR target_categories.revision = $6)
+		 RETURNING revision, created_at, updated_at`,
+		orgID, targetID, category, schemaVersion, dataJSON, expectedArg,
+	).Scan(&doc.Revision, &doc.CreatedAt, &doc.UpdatedAt)
+	if err != sql.ErrNoRows {
+		if err != nil {
+			return nil, err
+		}
+		return &doc, nil
+	}
+
+	// 갱신 조건에 맞지 않음: 다른 조직의 문서, 휴지통의 문서, 또는 확인한 뒤 다른 요청이 만든 문서
+	var owner string
+	var deleted bool
+	var current int
+	if err := tx.QueryRow(
+		"SELECT org_id::text, deleted_at IS NOT NULL, revision FROM target_categories WHERE target_id::text = $1 AND category_name = $2",
+		strings.ToLower(targetID), category,
+	).Scan(&owner, &deleted, &current); err != nil {
+		return nil, err
+	}
+	switch {
+	case owner != orgID:
+		return nil, fmt.Errorf("%w: %s", ErrTargetShared, targetID)
+	case deleted:
+		return nil, fmt.Errorf("%w: target %s in category %s", ErrInRecycleBin, targetID, category)
+	default:
+		return nil, &RevisionConflictError{TargetID: targetID, Category: category, Expected: int(expectedArg.Int64), Current: current}
+	}
+}
@@ -0,0 +1,467 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// 롤업 작업
+//
+// data-manager가 카테고리의 ts_obs 숫자 필드를 해상도(예: 1시간, 1일)별 구간으로 요약해
+// ts_obs_rollup에 쌓습니다. 해상도마다 rolled_up_to까지 집계가 끝났고, 매 실행은 직전 구간부터
+// 다시 집계해 늦게 들어온 관측값을 반영합니다. 시계열 API는 요청 범위에 맞는 해상도를 골라 읽습니다.
+
+// DefaultRollupResolutions 해상도를 정하지 않은 롤업 작업의 기본값
+var DefaultRollupResolutions = []string{"1 hour", "1 day"}
+
+// maxRollupBuckets 한 번 실행에서 집계하는 최대 구간 수 (처음 실행할 때 과거 데이터를 나눠서 집계)
+const maxRollupBuckets = 1000
+
+// RollupJob 카테고리 롤업 작업
+type RollupJob struct {
+	Category    string           `json:"category"`
+	Resolutions []string         `json:"resolutions"`      // 작은 해상도부터
+	Fields      []string         `json:"fields,omitempty"` // 점으로 구분한 payload 경로, 비어 있으면 스키마의 숫자 필드
+	IsActive    bool             `json:"is_active"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	Progress    []RollupProgress `json:"progress,omitempty"`
+}
+
+// RollupProgress 해상도 하나의 롤업 진행 상황
+type RollupProgress struct {
+	Resolution string    `json:"resolution"`
+	RolledUpTo time.Time `json:"rolled_up_to"`
+	Fields     []string  `json:"fields"`
+	LastRunAt  time.Time `json:"last_run_at"`
+	LastRows   int64     `json:"last_rows"`
+	LastError  string    `json:"last_error,omitempty"`
+}
+
+// Validate 작업 값을 검증하고 해상도를 정규화해 작은 것부터 정렬
+func (j *RollupJob) Validate() error {
+	if !categoryPattern.MatchString(j.Category) {
+		return fmt.Errorf("invalid category name: %s", j.Category)
+	}
+	if len(j.Resolutions) == 0 {
+		j.Resolutions = DefaultRollupResolutions
+	}
+
+	seen := make(map[time.Duration]bool)
+	var resolutions []string
+	for _, value := range j.Resolutions {
+		resolution, err := NormalizeInterval(value)
+		if err != nil {
+			return fmt.Errorf("resolution: %v", err)
+		}
+		if resolution == "" {
+			continue
+		}
+		size, _ := IntervalDuration(resolution)
+		if seen[size] {
+			continue
+		}
+		seen[size] = true
+		resolutions = append(resolutions, resolution)
+	}
+	if len(resolutions) == 0 {
+		return fmt.Errorf("at least one resolution is required")
+	}
+	sort.Slice(resolutions, func(a, b int) bool {
+		x, _ := IntervalDuration(resolutions[a])
+		y, _ := IntervalDuration(resolutions[b])
+		return x < y
+	})
+	j.Resolutions = resolutions
+
+	for _, field := range j.Fields {
+		for _, part := range strings.Split(field, ".") {
+			if !identifierPattern.MatchString(part) {
+				return fmt.Errorf("invalid field: %s", field)
+			}
+		}
+	}
+	return nil
+}
+
+// IntervalDuration NormalizeInterval로 정규화한 기간 (예: "2 hours")을 time.Duration으로 변환
+func IntervalDuration(interval string) (time.Duration, error) {
+	n, unit, ok := strings.Cut(interval, " ")
+	count, err := strconv.Atoi(n)
+	if !ok || err != nil {
+		return 0, fmt.Errorf("invalid interval %q", interval)
+	}
+	var d time.Duration
+	switch strings.TrimSuffix(unit, "s") {
+	case "minute":
+		d = time.Minute
+	case "hour":
+		d = time.Hour
+	case "day":
+		d = 24 * time.Hour
+	case "week":
+		d = 7 * 24 * time.Hour
+	default:
+		return 0, fmt.Errorf("invalid interval %q", interval)
+	}
+	return time.Duration(count) * d, nil
+}
+
+// ListRollupJobs 롤업 작업과 해상도별 진행 상황
+func ListRollupJobs(db DBTX) ([]RollupJob, error) {
+	rows, err := db.Query(`SELECT category_name, resolutions, fields, is_active, updated_at
+		FROM rollup_jobs ORDER BY category_name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []RollupJob
+	index := make(map[string]int)
+	for rows.Next() {
+		var j RollupJob
+		var fields []byte
+		if err := rows.Scan(&j.Category, pq.Array(&j.Resolutions), &fields, &j.IsActive, &j.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(fields, &j.Fields); err != nil {
+			return nil, fmt.Errorf("rollup %s: invalid fields: %v", j.Category, err)
+		}
+		index[j.Category] = len(jobs)
+		jobs = append(jobs, j)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	rows, err = db.Query(`SELECT category_name, resolution, rolled_up_to, fields, last_run_at, last_rows, last_error
+		FROM rollup_progress ORDER BY category_name, rolled_up_to`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var category string
+		var p RollupProgress
+		if err := rows.Scan(&category, &p.Resolution, &p.RolledUpTo, pq.Array(&p.Fields), &p.LastRunAt, &p.LastRows, &p.LastError); err != nil {
+			return nil, err
+		}
+		if i, ok := index[category]; ok {
+			jobs[i].Progress = append(jobs[i].Progress, p)
+		}
+	}
+	return jobs, rows.Err()
+}
+
+// GetRollupJob 카테고리의 롤업 작업 (없으면 sql.ErrNoRows)
+func GetRollupJob(db DBTX, category string) (*RollupJob, error) {
+	jobs, err := ListRollupJobs(db)
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs {
+		if jobs[i].Category == category {
+			return &jobs[i], nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// SetRollupJob 롤업 작업을 저장합니다. 빠진 해상도의 진행 상황과 요약은 지웁니다.
+func SetRollupJob(db *sql.DB, j *RollupJob) error {
+	if err := j.Validate(); err != nil {
+		return err
+	}
+	fields, _ := json.Marshal(j.Fields)
+	if j.Fields == nil {
+		fields = []byte("[]")
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`INSERT INTO rollup_jobs (category_name, resolutions, fields, is_active)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (category_name) DO UPDATE SET
+			resolutions = EXCLUDED.resolutions,
+			fields = EXCLUDED.fields,
+			is_active = EXCLUDED.is_active,
+			updated_at = now()
+		RETURNING updated_at`,
+		j.Category, pq.Array(j.Resolutions), string(fields), j.IsActive).Scan(&j.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM rollup_progress WHERE category_name = $1 AND resolution <> ALL($2)",
+		j.Category, pq.Array(j.Resolutions)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM ts_obs_rollup WHERE category_name = $1 AND resolution <> ALL($2)",
+		j.Category, pq.Array(j.Resolutions)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RemoveRollupJob 롤업 작업과 요약을 지웁니다
+func RemoveRollupJob(db *sql.DB, category string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM rollup_jobs WHERE category_name = $1", category)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("no rollup job for %s", category)
+	}
+	if _, err := tx.Exec("DELETE FROM ts_obs_rollup WHERE category_name = $1", category); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RollupFields 작업이 집계할 필드 (정하지 않았으면 조직마다의 활성 스키마에 숫자로 선언된 경로 전체)
+func RollupFields(db DBTX, j *RollupJob) ([]string, error) {
+	if len(j.Fields) > 0 {
+		return j.Fields, nil
+	}
+	schemas, err := activeCategorySchemas(db)
+	if err != nil {
+		return nil, err
+	}
+	var fields []string
+	for _, cs := range schemas {
+		if cs.category != j.Category {
+			continue
+		}
+		for _, path := range cs.schema.PathsOf("number", "integer") {
+			if field := strings.Join(path, "."); !slices.Contains(fields, field) {
+				fields = append(fields, field)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// RunRollup 해상도 하나를 이어서 집계합니다. 다른 인스턴스가 같은 해상도를 집계 중이면 nil을 반환합니다.
+// 집계할 필드가 늘면 처음부터 다시 집계하고, 빠진 필드의 요약은 지웁니다.
+func RunRollup(db *sql.DB, category, resolution string, fields []string, now time.Time) (*RollupProgress, error) {
+	bucket, err := IntervalDuration(resolution)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRow("SELECT pg_try_advisory_xact_lock(hashtext('tmidb_rollup'), hashtext($1))",
+		category+"/"+resolution).Scan(&locked); err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, nil
+	}
+
+	p := &RollupProgress{Resolution: resolution, Fields: fields, LastRunAt: now}
+	var start time.Time
+	var previous []string
+	err = tx.QueryRow("SELECT rolled_up_to, fields FROM rollup_progress WHERE category_name = $1 AND resolution = $2",
+		category, resolution).Scan(&start, pq.Array(&previous))
+	switch {
+	case err != nil && err != sql.ErrNoRows:
+		return nil, err
+	case err == sql.ErrNoRows || addsFields(previous, fields):
+		// 처음부터: 가장 오래된 관측값의 구간
+		var first sql.NullTime
+		if err := tx.QueryRow("SELECT time_bucket($1::interval, min(ts)) FROM ts_obs WHERE category_name = $2",
+			resolution, category).Scan(&first); err != nil {
+			return nil, err
+		}
+		start = now
+		if first.Valid {
+			start = first.Time
+		}
+	default:
+		// 직전 구간부터 다시 집계해 늦게 들어온 관측값을 반영
+		start = start.Add(-bucket)
+	}
+
+	if _, err := tx.Exec("DELETE FROM ts_obs_rollup WHERE category_name = $1 AND resolution = $2 AND field <> ALL($3)",
+		category, resolution, pq.Array(fields)); err != nil {
+		return nil, err
+	}
+
+	end := start.Add(maxRollupBuckets * bucket)
+	if end.After(now) {
+		end = now
+	}
+	if len(fields) > 0 && start.Before(end) {
+		result, err := tx.Exec(`INSERT INTO ts_obs_rollup
+				(category_name, resolution, target_id, field, bucket, samples, sum, min, max, last, last_ts)
+			SELECT $1, $2, o.target_id, f.field, time_bucket($2::interval, o.ts) AS bucket,
+				count(*), sum(f.v), min(f.v), max(f.v), last(f.v, o.ts), max(o.ts)
+			FROM ts_obs o
+			CROSS JOIN LATERAL (
+				SELECT field, (o.payload #>> string_to_array(field, '.'))::double precision AS v
+				FROM unnest($3::text[]) AS field
+				WHERE jsonb_typeof(o.payload #> string_to_array(field, '.')) = 'number'
+			) f
+			WHERE o.category_name = $1 AND o.ts >= $4 AND o.ts < $5
+			GROUP BY o.target_id, f.field, bucket
+			ON CONFLICT (category_name, resolution, target_id, field, bucket) DO UPDATE SET
+				samples = EXCLUDED.samples,
+				sum = EXCLUDED.sum,
+				min = EXCLUDED.min,
+				max = EXCLUDED.max,
+				last = EXCLUDED.last,
+				last_ts = EXCLUDED.last_ts`,
+			category, resolution, pq.Array(fields), start, end)
+		if err != nil {
+			return nil, err
+		}
+		p.LastRows, _ = result.RowsAffected()
+	}
+
+	// 아직 끝나지 않은 구간은 다음 실행에서 다시 집계
+	if err := tx.QueryRow("SELECT time_bucket($1::interval, $2::timestamptz)", resolution, end).Scan(&p.RolledUpTo); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(`INSERT INTO rollup_progress (category_name, resolution, rolled_up_to, fields, last_run_at, last_rows, last_error)
+		VALUES ($1, $2, $3, $4, $5, $6, '')
+		ON CONFLICT (category_name, resolution) DO UPDATE SET
+			rolled_up_to = EXCLUDED.rolled_up_to,
+			fields = EXCLUDED.fields,
+			last_run_at = EXCLUDED.last_run_at,
+			last_rows = EXCLUDED.last_rows,
+			last_error = ''`,
+		category, resolution, p.RolledUpTo, pq.Array(fields), now, p.LastRows)
+	if err != nil {
+		return nil, err
+	}
+	return p, tx.Commit()
+}
+
+// RecordRollupError 실패한 실행을 진행 상황에 남깁니다 (진행 상황이 아직 없으면 남기지 않음)
+func RecordRollupError(db DBTX, category, resolution string, runErr error, now time.Time) error {
+	_, err := db.Exec(`UPDATE rollup_progress SET last_error = $3, last_run_at = $4
+		WHERE category_name = $1 AND resolution = $2`, category, resolution, runErr.Error(), now)
+	return err
+}
+
+func addsFields(previous, fields []string) bool {
+	for _, field := range fields {
+		if !slices.Contains(previous, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// ChooseRollupResolution 필드를 요약하는 롤업 중 구간 크기 bucket 이하인 가장 큰 해상도 (to까지 집계된 해상도만,
+// 없으면 "" = 원본). 마지막 실행 시각까지 따라잡은 해상도는 그 뒤의 범위에도 씁니다.
+func ChooseRollupResolution(progress []RollupProgress, field string, bucket time.Duration, to time.Time) string {
+	best, bestSize := "", time.Duration(0)
+	for _, p := range progress {
+		size, err := IntervalDuration(p.Resolution)
+		if err != nil || size > bucket || size <= bestSize || !slices.Contains(p.Fields, field) {
+			continue
+		}
+		covered := to
+		if p.LastRunAt.Before(covered) {
+			covered = p.LastRunAt
+		}
+		if p.RolledUpTo.Add(size).Before(covered) {
+			continue
+		}
+		best, bestSize = p.Resolution, size
+	}
+	return best
+}
+
+// SeriesQuery 타겟 하나의 숫자 필드를 구간별로 요약하는 조건
+type SeriesQuery struct {
+	OrgID      string
+	TargetID   string
+	Category   string
+	Field      string // 점으로 구분한 payload 경로
+	From       time.Time
+	To         time.Time
+	Bucket     time.Duration
+	Resolution string // 읽을 롤업 해상도 ("" = ts_obs 원본)
+}
+
+// SeriesPoint 구간 하나의 요약
+type SeriesPoint struct {
+	Time    time.Time `json:"time"`
+	Samples int64     `json:"samples"`
+	Avg     float64   `json:"avg"`
+	Min     float64   `json:"min"`
+	Max     float64   `json:"max"`
+	Last    float64   `json:"last"`
+}
+
+// QuerySeries 원본 또는 롤업에서 구간별 요약을 읽습니다 (롤업 해상도보다 큰 구간은 롤업을 다시 묶음)
+func QuerySeries(db DBTX, q SeriesQuery) ([]SeriesPoint, error) {
+	var stmt string
+	args := []interface{}{q.OrgID, q.TargetID, q.Category, q.From, q.To, q.Bucket.Seconds()}
+	const owned = `EXISTS (SELECT 1 FROM target_categories tc
+		WHERE tc.target_id::text = $2 AND tc.category_name = $3 AND tc.org_id::text = $1 AND tc.deleted_at IS NULL)`
+
+	if q.Resolution == "" {
+		stmt = `SELECT time_bucket(make_interval(secs => $6), ts) AS b, count(*), avg(v), min(v), max(v), last(v, ts)
+			FROM (
+				SELECT o.ts, (o.payload #>> $7)::double precision AS v
+				FROM ts_obs o
+				WHERE o.target_id::text = $2 AND o.category_name = $3 AND o.ts >= $4 AND o.ts < $5
+				  AND jsonb_typeof(o.payload #> $7) = 'number'
+			) obs
+			WHERE ` + owned + `
+			GROUP BY b ORDER BY b`
+		args = append(args, pq.Array(strings.Split(q.Field, ".")))
+	} else {
+		stmt = `SELECT time_bucket(make_interval(secs => $6), r.bucket) AS b,
+				sum(r.samples), sum(r.sum) / sum(r.samples), min(r.min), max(r.max), last(r.last, r.last_ts)
+			FROM ts_obs_rollup r
+			WHERE r.category_name = $3 AND r.resolution = $7 AND r.target_id::text = $2 AND r.field = $8
+			  AND r.bucket >= time_bucket($7::interval, $4::timestamptz) AND r.bucket < $5
+			  AND ` + owned + `
+			GROUP BY b ORDER BY b`
+		args = append(args, q.Resolution, q.Field)
+	}
+
+	rows, err := db.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []SeriesPoint{}
+	for rows.Next() {
+		var p SeriesPoint
+		if err := rows.Scan(&p.Time, &p.Samples, &p.Avg, &p.Min, &p.Max, &p.Last); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
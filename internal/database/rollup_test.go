@@ -0,0 +1,86 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRollupJobValidate(t *testing.T) {
+	j := RollupJob{Category: "temperature", Resolutions: []string{"1d", "15m", "1h", "60 minutes"}, Fields: []string{"temp", "vitals.hr"}}
+	if err := j.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"15 minutes", "1 hour", "1 day"}; !reflect.DeepEqual(j.Resolutions, want) {
+		t.Errorf("resolutions = %v, want %v", j.Resolutions, want)
+	}
+
+	j = RollupJob{Category: "temperature"}
+	if err := j.Validate(); err != nil || !reflect.DeepEqual(j.Resolutions, DefaultRollupResolutions) {
+		t.Errorf("default resolutions = %v, %v", j.Resolutions, err)
+	}
+
+	for _, bad := range []RollupJob{
+		{Category: "bad name"},
+		{Category: "temperature", Resolutions: []string{"1 month"}},
+		{Category: "temperature", Fields: []string{"vitals..hr"}},
+		{Category: "temperature", Fields: []string{"temp'); DROP"}},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("%+v accepted", bad)
+		}
+	}
+}
+
+func TestIntervalDuration(t *testing.T) {
+	for in, want := range map[string]time.Duration{
+		"1 minute": time.Minute,
+		"6 hours":  6 * time.Hour,
+		"1 day":    24 * time.Hour,
+		"2 weeks":  14 * 24 * time.Hour,
+	} {
+		if got, err := IntervalDuration(in); err != nil || got != want {
+			t.Errorf("IntervalDuration(%q) = %s, %v", in, got, err)
+		}
+	}
+	if _, err := IntervalDuration("7d"); err == nil {
+		t.Error("unnormalized interval accepted")
+	}
+}
+
+func TestChooseRollupResolution(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 30, 0, 0, time.UTC)
+	fields := []string{"temp"}
+	progress := []RollupProgress{
+		{Resolution: "1 hour", RolledUpTo: now.Truncate(time.Hour), LastRunAt: now, Fields: fields},
+		{Resolution: "1 day", RolledUpTo: now.Truncate(24 * time.Hour), LastRunAt: now, Fields: fields},
+	}
+
+	tests := []struct {
+		name   string
+		field  string
+		bucket time.Duration
+		to     time.Time
+		want   string
+	}{
+		{"finer than any rollup", "temp", 10 * time.Minute, now, ""},
+		{"hourly", "temp", 3 * time.Hour, now, "1 hour"},
+		{"daily", "temp", 7 * 24 * time.Hour, now, "1 day"},
+		{"range in the past", "temp", 2 * time.Hour, now.Add(-72 * time.Hour), "1 hour"},
+		{"field not rolled up", "humidity", 3 * time.Hour, now, ""},
+	}
+	for _, tt := range tests {
+		if got := ChooseRollupResolution(progress, tt.field, tt.bucket, tt.to); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// 과거 데이터를 아직 집계하는 중이면 원본
+	backfilling := []RollupProgress{{Resolution: "1 hour", RolledUpTo: now.Add(-30 * 24 * time.Hour), LastRunAt: now, Fields: fields}}
+	if got := ChooseRollupResolution(backfilling, "temp", 3*time.Hour, now); got != "" {
+		t.Errorf("backfilling rollup chosen: %q", got)
+	}
+	if got := ChooseRollupResolution(backfilling, "temp", 3*time.Hour, now.Add(-40*24*time.Hour)); got != "1 hour" {
+		t.Errorf("range before the backfill position: %q", got)
+	}
+}
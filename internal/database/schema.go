@@ -1,6 +1,7 @@
 package database
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"time"
@@ -108,19 +109,68 @@ func GetCategorySchema(name, orgID string) (*CategorySchema, error) {
 	return &c, nil
 }
 
+// GetCategorySchemaVersion은 조직 카테고리의 스키마 버전 하나를 조회합니다 (version이 0이면 최신 버전, 없으면 sql.ErrNoRows).
+func GetCategorySchemaVersion(db DBTX, orgID, category string, version int) (*CategorySchema, error) {
+	var c CategorySchema
+	err := db.QueryRow(
+		`SELECT schema_id, org_id, category_name, version, schema_definition::text, is_active, created_at
+		 FROM category_schemas
+		 WHERE org_id::text = $1 AND category_name = $2 AND ($3 = 0 OR version = $3)
+		 ORDER BY version DESC LIMIT 1`,
+		orgID, category, version,
+	).Scan(&c.SchemaID, &c.OrgID, &c.CategoryName, &c.Version, &c.SchemaDefinition, &c.IsActive, &c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetTargetCategorySchema는 타겟이 사용하는 카테고리 스키마 버전의 정의를 조회합니다.
+// 타겟이 카테고리에 연결되어 있지 않으면 sql.ErrNoRows를 반환합니다.
+func GetTargetCategorySchema(db DBTX, targetID, category string) (string, error) {
+	var definition string
+	err := db.QueryRow(
+		`SELECT cs.schema_definition
+		 FROM target_categories tc
+		 JOIN category_schemas cs
+		   ON cs.org_id = tc.org_id AND cs.category_name = tc.category_name AND cs.version = tc.schema_version
+		 WHERE tc.target_id = $1 AND tc.category_name = $2 AND tc.deleted_at IS NULL`,
+		targetID, category,
+	).Scan(&definition)
+	return definition, err
+}
+
+// GetOrgTargetCategorySchema는 조직의 타겟이 쓰는 카테고리 스키마 정의를 조회합니다 (연결이 없으면 sql.ErrNoRows)
+func GetOrgTargetCategorySchema(db DBTX, orgID, targetID, category string) (string, error) {
+	var definition string
+	err := db.QueryRow(
+		`SELECT cs.schema_definition
+		 FROM target_categories tc
+		 JOIN category_schemas cs
+		   ON cs.org_id = tc.org_id AND cs.category_name = tc.category_name AND cs.version = tc.schema_version
+		 WHERE tc.org_id = $1 AND tc.target_id = $2 AND tc.category_name = $3 AND tc.deleted_at IS NULL`,
+		orgID, targetID, category,
+	).Scan(&definition)
+	return definition, err
+}
+
 // Listener는 리스너 테이블의 Go 표현입니다.
+// Conditions와 Actions는 internal/listener 형식의 JSON 배열입니다.
 type Listener struct {
-	ListenerID   string    `json:"listener_id"`
-	OrgID        string    `json:"org_id"`
-	CategoryName string    `json:"category_name"`
-	Description  string    `json:"description"`
-	IsActive     bool      `json:"is_active"`
-	CreatedAt    time.Time `json:"created_at"`
+	ListenerID   string          `json:"listener_id"`
+	OrgID        string          `json:"org_id"`
+	CategoryName string          `json:"category_name"`
+	Description  string          `json:"description"`
+	IsActive     bool            `json:"is_active"`
+	Conditions   json.RawMessage `json:"conditions,omitempty"`
+	Actions      json.RawMessage `json:"actions,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
 }
 
 // GetListeners는 특정 조직의 모든 리스너를 조회합니다.
 func GetListeners(orgID string) ([]Listener, error) {
-	rows, err := DB.Query("SELECT listener_id, org_id, category_name, description, is_active, created_at FROM listeners WHERE org_id = $1 ORDER BY created_at DESC", orgID)
+	rows, err := DB.Query("SELECT "+listenerColumns+" FROM listeners WHERE org_id = $1 ORDER BY created_at DESC", orgID)
 	if err != nil {
 		return nil, err
 	}
@@ -128,23 +178,24 @@ func GetListeners(orgID string) ([]Listener, error) {
 
 	var listeners []Listener
 	for rows.Next() {
-		var l Listener
-		if err := rows.Scan(&l.ListenerID, &l.OrgID, &l.CategoryName, &l.Description, &l.IsActive, &l.CreatedAt); err != nil {
+		l, err := scanListener(rows)
+		if err != nil {
 			return nil, err
 		}
-		listeners = append(listeners, l)
+		listeners = append(listeners, *l)
 	}
-	return listeners, nil
+	return listeners, rows.Err()
 }
 
 // CreateListener는 새 리스너를 생성합니다.
 func CreateListener(listener *Listener) error {
-	_, err := DB.Exec(
-		`INSERT INTO listeners (listener_id, org_id, category_name, description, is_active)
-		 VALUES ($1, $2, $3, $4, TRUE)`,
+	return DB.QueryRow(
+		`INSERT INTO listeners (listener_id, org_id, category_name, description, is_active, conditions, actions)
+		 VALUES ($1, $2, $3, $4, TRUE, $5::jsonb, $6::jsonb)
+		 RETURNING created_at, updated_at`,
 		listener.ListenerID, listener.OrgID, listener.CategoryName, listener.Description,
-	)
-	return err
+		jsonArrayOrEmpty(listener.Conditions), jsonArrayOrEmpty(listener.Actions),
+	).Scan(&listener.CreatedAt, &listener.UpdatedAt)
 }
 
 // DeleteListener는 특정 조직에서 리스너를 삭제합니다.
@@ -214,6 +265,8 @@ CREATE TABLE IF NOT EXISTS public.target_categories (
         FOREIGN KEY(org_id, category_name, schema_version)
         REFERENCES public.category_schemas(org_id, category_name, version)
 );
+-- 카테고리 데이터 API의 정렬 순서 (커서 페이징)
+CREATE INDEX IF NOT EXISTS idx_target_categories_scan ON public.target_categories (org_id, category_name, updated_at DESC, target_id DESC);
 
 ----------------------------------------------------------------
 -- 4. 시계열 관측 데이터 (TimescaleDB Hypertable)
@@ -273,6 +326,11 @@ CREATE TABLE IF NOT EXISTS public.file_attachments (
         ON DELETE CASCADE
 );
 
+-- 조직 범위와 카테고리 (기존 설치에도 추가)
+ALTER TABLE public.file_attachments ADD COLUMN IF NOT EXISTS org_id TEXT;
+ALTER TABLE public.file_attachments ADD COLUMN IF NOT EXISTS category_name TEXT;
+CREATE INDEX IF NOT EXISTS idx_file_attachments_target ON public.file_attachments (org_id, target_id);
+
 ----------------------------------------------------------------
 -- 8. 트리거 함수
 ----------------------------------------------------------------
@@ -295,6 +353,25 @@ CREATE TABLE IF NOT EXISTS public.listeners (
     created_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
 
+-- 리스너 실행 정의 (기존 설치에도 추가)
+ALTER TABLE public.listeners ADD COLUMN IF NOT EXISTS org_id TEXT;
+ALTER TABLE public.listeners ADD COLUMN IF NOT EXISTS conditions JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE public.listeners ADD COLUMN IF NOT EXISTS actions JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE public.listeners ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- 리스너 실행 통계 (data-manager가 주기적으로 누적)
+CREATE TABLE IF NOT EXISTS public.listener_stats (
+    listener_id TEXT PRIMARY KEY REFERENCES public.listeners(listener_id) ON DELETE CASCADE,
+    evaluated BIGINT NOT NULL DEFAULT 0,
+    matched BIGINT NOT NULL DEFAULT 0,
+    actions_succeeded BIGINT NOT NULL DEFAULT 0,
+    actions_failed BIGINT NOT NULL DEFAULT 0,
+    last_matched_at TIMESTAMPTZ,
+    last_error TEXT,
+    last_error_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
 ----------------------------------------------------------------
 -- 10. 인증 관련 테이블
 ----------------------------------------------------------------
@@ -325,6 +402,19 @@ CREATE TABLE IF NOT EXISTS public.auth_tokens (
     created_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
 
+-- 요청 인증용 토큰 해시 (기존 토큰은 첫 인증 시 채워짐)
+ALTER TABLE public.auth_tokens ADD COLUMN IF NOT EXISTS token_hash TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_auth_tokens_hash ON public.auth_tokens (token_hash);
+
+-- SSO 사용자 (auth_provider는 'local' 또는 OIDC 발급자 URL, external_subject는 ID 토큰의 sub)
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS auth_provider TEXT NOT NULL DEFAULT 'local';
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS external_subject TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_external_subject ON public.users (auth_provider, external_subject) WHERE external_subject IS NOT NULL;
+
+-- 설치 관리자 (초기 설정에서 만든 관리자). role은 조직마다 있으므로 조직 관리나 SQL 콘솔처럼
+-- 모든 조직에 닿는 작업은 role이 아니라 이 표시로 허용
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS is_installation_admin BOOLEAN NOT NULL DEFAULT false;
+
 ----------------------------------------------------------------
 -- 11. 시스템 설정 테이블
 ----------------------------------------------------------------
@@ -336,6 +426,22 @@ CREATE TABLE IF NOT EXISTS public.system_config (
     updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
 
+-- 설치 관리자 표시 전에 설정을 마친 설치는 가장 먼저 만든 조직의 첫 관리자를 한 번만 표시
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM public.system_config WHERE config_key = 'setup_completed')
+       AND NOT EXISTS (SELECT 1 FROM public.system_config WHERE config_key = 'installation_admin_marked') THEN
+        UPDATE public.users SET is_installation_admin = true
+        WHERE user_id = (
+            SELECT u.user_id FROM public.users u JOIN public.organizations o ON o.org_id = u.org_id
+            WHERE u.role = 'admin' AND u.auth_provider = 'local'
+            ORDER BY o.created_at, u.created_at
+            LIMIT 1
+        ) AND NOT EXISTS (SELECT 1 FROM public.users WHERE is_installation_admin);
+        INSERT INTO public.system_config (config_key, config_value) VALUES ('installation_admin_marked', 'true');
+    END IF;
+END $$;
+
 -- 사용자별 액세스 토큰 테이블
 CREATE TABLE IF NOT EXISTS public.user_access_tokens (
     token_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -351,6 +457,256 @@ CREATE TABLE IF NOT EXISTS public.user_access_tokens (
         REFERENCES public.users(user_id)
         ON DELETE CASCADE
 );
+
+-- 토큰 발급/교체/만료/폐기 감사 로그 (토큰이 삭제되어도 남음)
+CREATE TABLE IF NOT EXISTS public.token_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    org_id UUID,
+    token_id UUID NOT NULL,
+    token_kind TEXT NOT NULL, -- 'api' (auth_tokens), 'user' (user_access_tokens)
+    action TEXT NOT NULL, -- 'create', 'rotate', 'expiry', 'revoke', 'expire', 'delete'
+    actor TEXT NOT NULL,
+    details JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_token_audit_log_org ON public.token_audit_log (org_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_token_audit_log_token ON public.token_audit_log (token_id, created_at DESC);
+
+-- 보관된 타겟은 기본 조회에서 숨김 (데이터는 유지)
+ALTER TABLE public.target ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+-- 타겟 보관/병합/삭제 감사 로그 (타겟이 삭제되어도 남음)
+CREATE TABLE IF NOT EXISTS public.target_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    org_id TEXT NOT NULL,
+    action TEXT NOT NULL, -- 'archive', 'unarchive', 'merge', 'delete'
+    target_ids TEXT[] NOT NULL,
+    actor TEXT NOT NULL,
+    details JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_target_audit_log_org ON public.target_audit_log (org_id, created_at DESC);
+
+-- 타겟 보관/병합/삭제 확인 토큰 (한 번만 사용, 해시로 저장)
+CREATE TABLE IF NOT EXISTS public.target_confirmations (
+    token_hash TEXT PRIMARY KEY,
+    org_id TEXT NOT NULL,
+    action TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- 비동기 카테고리 내보내기 작업 (결과 파일은 SeaweedFS의 storage_path)
+CREATE TABLE IF NOT EXISTS public.export_jobs (
+    job_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    org_id TEXT NOT NULL,
+    category_name TEXT NOT NULL,
+    format TEXT NOT NULL,
+    parameters JSONB,
+    status TEXT NOT NULL DEFAULT 'queued', -- 'queued', 'running', 'completed', 'failed'
+    rows_exported BIGINT NOT NULL DEFAULT 0,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    storage_path TEXT,
+    error TEXT,
+    created_by TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_export_jobs_org ON public.export_jobs (org_id, created_at DESC);
+
+-- 시계열 가져오기 작업 (업로드한 파일 조각은 SeaweedFS의 chunk_paths, 파일러에 있던 파일은 source_path)
+CREATE TABLE IF NOT EXISTS public.import_jobs (
+    job_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    org_id TEXT NOT NULL,
+    category_name TEXT NOT NULL,
+    format TEXT NOT NULL,
+    mapping JSONB,
+    source_path TEXT,
+    chunk_paths TEXT[] NOT NULL DEFAULT '{}',
+    received_bytes BIGINT NOT NULL DEFAULT 0,
+    expected_bytes BIGINT,
+    status TEXT NOT NULL DEFAULT 'uploading', -- 'uploading', 'queued', 'running', 'completed', 'failed'
+    rows_read BIGINT NOT NULL DEFAULT 0,
+    rows_imported BIGINT NOT NULL DEFAULT 0,
+    rows_failed BIGINT NOT NULL DEFAULT 0,
+    row_errors JSONB,
+    error TEXT,
+    created_by TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_import_jobs_org ON public.import_jobs (org_id, created_at DESC);
+
+-- 조직별 시간 단위 사용량 (API 서버와 데이터 컨슈머가 더함, 과금 기록이므로 조직이 삭제되어도 남음)
+CREATE TABLE IF NOT EXISTS public.org_usage (
+    org_id TEXT NOT NULL,
+    hour TIMESTAMPTZ NOT NULL,
+    api_requests BIGINT NOT NULL DEFAULT 0,
+    ingested_records BIGINT NOT NULL DEFAULT 0,
+    ingested_bytes BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (org_id, hour)
+);
+
+-- 웹 콘솔 세션 (SESSION_STORE=postgres, 세션 ID는 해시로 저장, 로그인하면 user_id가 채워짐)
+CREATE TABLE IF NOT EXISTS public.web_sessions (
+    session_id TEXT PRIMARY KEY,
+    data BYTEA NOT NULL,
+    user_id UUID REFERENCES users(user_id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_web_sessions_user ON public.web_sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_web_sessions_expires ON public.web_sessions (expires_at);
+
+-- 웹 콘솔 리프레시 토큰 (해시로 저장, 사용할 때마다 새 토큰으로 교체)
+CREATE TABLE IF NOT EXISTS public.session_refresh_tokens (
+    token_hash TEXT PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_session_refresh_tokens_user ON public.session_refresh_tokens (user_id);
+
+-- 느린 쿼리 기록 (SLOW_QUERY_MS, 모든 컴포넌트가 fingerprint별로 더함, sample은 가장 느렸던 실행)
+CREATE TABLE IF NOT EXISTS public.slow_queries (
+    fingerprint TEXT PRIMARY KEY,
+    query TEXT NOT NULL,
+    calls BIGINT NOT NULL DEFAULT 0,
+    total_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+    max_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+    sample_query TEXT NOT NULL,
+    sample_params JSONB,
+    component TEXT,
+    first_seen TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_seen TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- 데이터 API 필터가 거른 JSONB 경로별 횟수 (인덱스 추천용, kind는 text 또는 number 식)
+CREATE TABLE IF NOT EXISTS public.filter_usage (
+    org_id TEXT NOT NULL,
+    category_name TEXT NOT NULL,
+    path TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    calls BIGINT NOT NULL DEFAULT 0,
+    first_seen TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_seen TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (org_id, category_name, path, kind)
+);
+
+-- 조직 webhook (secret은 서명에 쓰므로 평문으로 두고 API 응답에는 생성할 때만 포함)
+CREATE TABLE IF NOT EXISTS public.webhooks (
+    webhook_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    org_id UUID NOT NULL REFERENCES organizations(org_id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    event_types TEXT[] NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_webhooks_org ON public.webhooks (org_id) WHERE is_active;
+
+-- webhook 전송 기록이자 재시도 대기열 (event_key로 같은 이벤트를 한 번만 적재)
+CREATE TABLE IF NOT EXISTS public.webhook_deliveries (
+    delivery_id BIGSERIAL PRIMARY KEY,
+    webhook_id UUID NOT NULL REFERENCES public.webhooks(webhook_id) ON DELETE CASCADE,
+    org_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    event_key TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_status_code INTEGER,
+    last_error TEXT,
+    last_response TEXT,
+    last_duration_ms INTEGER,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMPTZ,
+    UNIQUE (webhook_id, event_key)
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON public.webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON public.webhook_deliveries (webhook_id, delivery_id DESC);
+
+-- 카테고리별 롤업 작업 (fields가 비어 있으면 스키마의 숫자 필드 전체)
+CREATE TABLE IF NOT EXISTS public.rollup_jobs (
+    category_name TEXT PRIMARY KEY,
+    resolutions TEXT[] NOT NULL,
+    fields JSONB NOT NULL DEFAULT '[]',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- 해상도별 롤업 진행 상황 (rolled_up_to 이전 구간은 fields에 대해 집계가 끝남)
+CREATE TABLE IF NOT EXISTS public.rollup_progress (
+    category_name TEXT NOT NULL REFERENCES public.rollup_jobs(category_name) ON DELETE CASCADE,
+    resolution TEXT NOT NULL,
+    rolled_up_to TIMESTAMPTZ NOT NULL,
+    fields TEXT[] NOT NULL DEFAULT '{}',
+    last_run_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_rows BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (category_name, resolution)
+);
+
+-- ts_obs 숫자 필드의 구간별 요약 (평균은 sum / samples)
+CREATE TABLE IF NOT EXISTS public.ts_obs_rollup (
+    category_name TEXT NOT NULL,
+    resolution TEXT NOT NULL,
+    target_id UUID NOT NULL,
+    field TEXT NOT NULL,
+    bucket TIMESTAMPTZ NOT NULL,
+    samples BIGINT NOT NULL,
+    sum DOUBLE PRECISION NOT NULL,
+    min DOUBLE PRECISION NOT NULL,
+    max DOUBLE PRECISION NOT NULL,
+    last DOUBLE PRECISION NOT NULL,
+    last_ts TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (category_name, resolution, target_id, field, bucket)
+);
+
+-- 수집 중복 제거 키 (event_id 또는 target_id+ts+payload 해시, INGEST_DEDUP_WINDOW_HOURS 동안 유지)
+CREATE TABLE IF NOT EXISTS public.ingest_dedup (
+    target_id UUID NOT NULL,
+    category_name TEXT NOT NULL,
+    event_key TEXT NOT NULL,
+    seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (target_id, category_name, event_key)
+);
+CREATE INDEX IF NOT EXISTS idx_ingest_dedup_seen_at ON public.ingest_dedup (seen_at);
+
+-- 소프트 삭제 (휴지통, SOFT_DELETE_RETENTION_DAYS가 지나면 영구 삭제). 타겟을 지우면 타겟의 카테고리 문서도 같은 시각으로 표시
+ALTER TABLE public.target ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE public.target ADD COLUMN IF NOT EXISTS deleted_by TEXT;
+ALTER TABLE public.target_categories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE public.target_categories ADD COLUMN IF NOT EXISTS deleted_by TEXT;
+CREATE INDEX IF NOT EXISTS idx_target_deleted_at ON public.target (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_target_categories_deleted_at ON public.target_categories (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- SQL 콘솔 실행 기록 (거부되거나 실패한 문도 남김)
+CREATE TABLE IF NOT EXISTS public.sql_console_log (
+    id BIGSERIAL PRIMARY KEY,
+    org_id TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    client_ip TEXT NOT NULL DEFAULT '',
+    request_id TEXT NOT NULL DEFAULT '',
+    statement TEXT NOT NULL,
+    row_count INTEGER NOT NULL DEFAULT 0,
+    truncated BOOLEAN NOT NULL DEFAULT false,
+    duration_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_sql_console_log_org ON public.sql_console_log (org_id, created_at DESC);
 `
 
 // 트리거 생성 SQL
@@ -571,6 +927,24 @@ func InitializeCompleteSchema() error {
 		return fmt.Errorf("failed to create database functions: %v", err)
 	}
 
+	// 시계열 정책 테이블 생성 및 저장된 정책 적용 (TimescaleDB가 없으면 건너뜀)
+	if _, err := DB.Exec(timeseriesPolicyTableSQL); err != nil {
+		return fmt.Errorf("failed to create timeseries policy table: %v", err)
+	}
+	if err := ApplyTimeseriesPolicies(DB); err != nil {
+		log.Printf("⚠️ Timeseries policies not applied: %v", err)
+	}
+
+	// 변경 데이터 캡처(CDC) 아웃박스와 트리거 생성
+	if _, err := DB.Exec(changeCaptureSQL); err != nil {
+		return fmt.Errorf("failed to create change capture triggers: %v", err)
+	}
+
+	// 카테고리 문서 이력 테이블과 리비전 트리거 생성
+	if _, err := DB.Exec(documentHistorySQL); err != nil {
+		return fmt.Errorf("failed to create document history triggers: %v", err)
+	}
+
 	// 초기 데이터 생성
 	if err := CreateInitialData(); err != nil {
 		return fmt.Errorf("failed to create initial data: %v", err)
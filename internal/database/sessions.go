@@ -0,0 +1,262 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ErrInvalidRefreshToken는 리프레시 토큰이 없거나, 만료/폐기되었거나, 사용자가 비활성일 때 반환됩니다
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+// refreshReuseGrace 동안은 교체된 토큰이 다시 와도 탈취로 보지 않습니다.
+// 세션이 만료된 직후 브라우저가 같은 쿠키로 동시에 보낸 요청들이 서로를 로그아웃시키지 않게 합니다.
+const refreshReuseGrace = 30 * time.Second
+
+// SessionStorage는 웹 콘솔 세션을 web_sessions 테이블에 저장하는 fiber.Storage 구현입니다.
+// API 서버를 다시 시작해도 세션이 유지되고 여러 API 인스턴스가 세션을 공유합니다.
+// 세션 ID는 해시로 저장하므로 테이블이 유출되어도 쿠키를 만들 수 없습니다.
+type SessionStorage struct {
+	db   *sql.DB
+	stop context.CancelFunc
+}
+
+// NewSessionStorage는 PostgreSQL 세션 저장소를 만들고 gcInterval마다
+// 만료된 세션과 리프레시 토큰을 지우는 작업을 시작합니다.
+func NewSessionStorage(db *sql.DB, gcInterval time.Duration) *SessionStorage {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &SessionStorage{db: db, stop: cancel}
+	go func() {
+		ticker := time.NewTicker(gcInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if n, err := DeleteExpiredSessions(db); err != nil {
+					log.Printf("⚠️ 만료 세션 정리 실패: %v", err)
+				} else if n > 0 {
+					log.Printf("⌛ 만료된 세션과 리프레시 토큰 %d개를 지웠습니다", n)
+				}
+			}
+		}
+	}()
+	return s
+}
+
+// Get은 세션 데이터를 반환합니다. 없거나 만료되었으면 nil, nil을 반환합니다.
+func (s *SessionStorage) Get(key string) ([]byte, error) {
+	var data []byte
+	err := s.db.QueryRow(`
+		SELECT data FROM web_sessions
+		WHERE session_id = $1 AND (expires_at IS NULL OR expires_at > now())
+	`, hashToken(key)).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Set은 세션 데이터를 저장하고 만료 시각을 exp 뒤로 미룹니다 (0이면 만료 없음).
+// 저장할 때마다 만료가 늦춰지므로 활동 중인 세션은 만료되지 않습니다.
+func (s *SessionStorage) Set(key string, val []byte, exp time.Duration) error {
+	if key == "" || len(val) == 0 {
+		return nil
+	}
+	var expiresAt sql.NullTime
+	if exp > 0 {
+		expiresAt = sql.NullTime{Time: time.Now().Add(exp), Valid: true}
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO web_sessions (session_id, data, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (session_id) DO UPDATE SET
+			data = EXCLUDED.data,
+			expires_at = EXCLUDED.expires_at,
+			updated_at = now()
+	`, hashToken(key), val, expiresAt)
+	return err
+}
+
+// Delete는 세션을 지웁니다
+func (s *SessionStorage) Delete(key string) error {
+	_, err := s.db.Exec(`DELETE FROM web_sessions WHERE session_id = $1`, hashToken(key))
+	return err
+}
+
+// Reset은 모든 세션을 지웁니다
+func (s *SessionStorage) Reset() error {
+	_, err := s.db.Exec(`DELETE FROM web_sessions`)
+	return err
+}
+
+// Close는 정리 작업을 멈춥니다 (DB 연결은 닫지 않음)
+func (s *SessionStorage) Close() error {
+	s.stop()
+	return nil
+}
+
+// BindSessionUser는 로그인한 세션에 사용자를 기록합니다.
+// 사용자의 모든 세션을 끊을 때 (RevokeUserSessions) 이 값으로 세션을 찾습니다.
+func BindSessionUser(db DBTX, sessionID, userID string) error {
+	_, err := db.Exec(`UPDATE web_sessions SET user_id = $2 WHERE session_id = $1`, hashToken(sessionID), userID)
+	return err
+}
+
+// DeleteExpiredSessions는 만료된 세션과, 만료되었거나 폐기된 지 하루가 지난 리프레시 토큰을 지웁니다.
+// 폐기된 토큰을 하루 동안 남겨 두는 것은 재사용을 감지하기 위해서입니다.
+func DeleteExpiredSessions(db DBTX) (int64, error) {
+	res, err := db.Exec(`DELETE FROM web_sessions WHERE expires_at <= now()`)
+	if err != nil {
+		return 0, err
+	}
+	sessions, _ := res.RowsAffected()
+
+	res, err = db.Exec(`
+		DELETE FROM session_refresh_tokens
+		WHERE expires_at <= now() OR revoked_at <= now() - interval '1 day'
+	`)
+	if err != nil {
+		return sessions, err
+	}
+	tokens, _ := res.RowsAffected()
+	return sessions + tokens, nil
+}
+
+// RefreshIdentity는 리프레시 토큰으로 세션을 다시 만들 때 쓰는 사용자 정보입니다
+type RefreshIdentity struct {
+	UserID   string
+	OrgID    string
+	Username string
+	Role     string
+}
+
+// IssueRefreshToken은 사용자의 새 리프레시 토큰을 발급합니다 (ttl 뒤 만료).
+// 토큰은 해시만 저장하므로 반환된 값은 쿠키에만 남습니다.
+func IssueRefreshToken(db DBTX, userID string, ttl time.Duration) (string, error) {
+	token, err := newTokenString()
+	if err != nil {
+		return "", err
+	}
+	if _, err := db.Exec(`
+		INSERT INTO session_refresh_tokens (token_hash, user_id, expires_at)
+		VALUES ($1, $2, $3)
+	`, hashToken(token), userID, time.Now().Add(ttl)); err != nil {
+		return "", fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return token, nil
+}
+
+// RotateRefreshToken은 리프레시 토큰을 확인하고 폐기한 뒤, 만료를 ttl 뒤로 미룬 새 토큰을 발급합니다.
+// 이미 교체된 토큰이 다시 쓰이면 토큰이 탈취된 것으로 보고 사용자의 리프레시 토큰을 모두 폐기합니다.
+func RotateRefreshToken(db *sql.DB, token string, ttl time.Duration) (string, *RefreshIdentity, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var (
+		identity  RefreshIdentity
+		expiresAt time.Time
+		revokedAt sql.NullTime
+		isActive  bool
+	)
+	err = tx.QueryRow(`
+		SELECT u.user_id::text, u.org_id::text, u.username, u.role, u.is_active, t.expires_at, t.revoked_at
+		FROM session_refresh_tokens t
+		JOIN users u ON u.user_id = t.user_id
+		WHERE t.token_hash = $1
+		FOR UPDATE OF t
+	`, hashToken(token)).Scan(&identity.UserID, &identity.OrgID, &identity.Username, &identity.Role,
+		&isActive, &expiresAt, &revokedAt)
+	if err == sql.ErrNoRows {
+		return "", nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read refresh token: %w", err)
+	}
+
+	switch refreshTokenStatus(expiresAt, revokedAt, isActive, time.Now()) {
+	case refreshTokenReused:
+		if _, err := tx.Exec(`
+			UPDATE session_refresh_tokens SET revoked_at = now()
+			WHERE user_id = $1 AND revoked_at IS NULL
+		`, identity.UserID); err != nil {
+			return "", nil, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return "", nil, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		log.Printf("⚠️ 폐기된 리프레시 토큰이 다시 사용되어 사용자 %s의 리프레시 토큰을 모두 폐기했습니다", identity.UserID)
+		return "", nil, ErrInvalidRefreshToken
+	case refreshTokenInvalid:
+		return "", nil, ErrInvalidRefreshToken
+	}
+
+	if _, err := tx.Exec(`UPDATE session_refresh_tokens SET revoked_at = now() WHERE token_hash = $1`, hashToken(token)); err != nil {
+		return "", nil, fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	next, err := IssueRefreshToken(tx, identity.UserID, ttl)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return next, &identity, nil
+}
+
+// 리프레시 토큰 상태
+const (
+	refreshTokenValid   = "valid"
+	refreshTokenInvalid = "invalid" // 만료, 유예 기간 안의 재사용, 비활성 사용자
+	refreshTokenReused  = "reused"  // 교체된 지 refreshReuseGrace가 지난 토큰이 다시 쓰임
+)
+
+// refreshTokenStatus는 저장된 리프레시 토큰을 now 기준으로 판정합니다
+func refreshTokenStatus(expiresAt time.Time, revokedAt sql.NullTime, isActive bool, now time.Time) string {
+	if revokedAt.Valid {
+		if now.Sub(revokedAt.Time) > refreshReuseGrace {
+			return refreshTokenReused
+		}
+		return refreshTokenInvalid
+	}
+	if !isActive || !expiresAt.After(now) {
+		return refreshTokenInvalid
+	}
+	return refreshTokenValid
+}
+
+// RevokeRefreshToken은 로그아웃할 때 리프레시 토큰 하나를 폐기합니다
+func RevokeRefreshToken(db DBTX, token string) error {
+	_, err := db.Exec(`
+		UPDATE session_refresh_tokens SET revoked_at = now()
+		WHERE token_hash = $1 AND revoked_at IS NULL
+	`, hashToken(token))
+	return err
+}
+
+// RevokeUserSessions는 사용자의 모든 웹 콘솔 세션을 지우고 리프레시 토큰을 모두 폐기합니다 (모든 기기에서 로그아웃).
+// 메모리 세션 저장소를 쓰면 세션은 지울 수 없으므로 리프레시 토큰만 폐기됩니다.
+func RevokeUserSessions(db DBTX, userID string) (sessions, refreshTokens int64, err error) {
+	res, err := db.Exec(`DELETE FROM web_sessions WHERE user_id = $1`, userID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to delete sessions: %w", err)
+	}
+	sessions, _ = res.RowsAffected()
+
+	res, err = db.Exec(`
+		UPDATE session_refresh_tokens SET revoked_at = now()
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`, userID)
+	if err != nil {
+		return sessions, 0, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	refreshTokens, _ = res.RowsAffected()
+	return sessions, refreshTokens, nil
+}
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestRefreshTokenStatus(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	future := now.Add(time.Hour)
+	revoked := func(ago time.Duration) sql.NullTime {
+		return sql.NullTime{Time: now.Add(-ago), Valid: true}
+	}
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		revokedAt sql.NullTime
+		isActive  bool
+		want      string
+	}{
+		{"valid", future, sql.NullTime{}, true, refreshTokenValid},
+		{"expired", now.Add(-time.Second), sql.NullTime{}, true, refreshTokenInvalid},
+		{"expires now", now, sql.NullTime{}, true, refreshTokenInvalid},
+		{"inactive user", future, sql.NullTime{}, false, refreshTokenInvalid},
+		{"rotated within grace", future, revoked(10 * time.Second), true, refreshTokenInvalid},
+		{"reused after grace", future, revoked(time.Minute), true, refreshTokenReused},
+		{"reused after expiry", now.Add(-time.Hour), revoked(2 * time.Hour), true, refreshTokenReused},
+	}
+	for _, tt := range tests {
+		if got := refreshTokenStatus(tt.expiresAt, tt.revokedAt, tt.isActive, now); got != tt.want {
+			t.Errorf("%s: refreshTokenStatus() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// SetupWindow는 설정 시작 후 초기 설정을 완료해야 하는 시간입니다 (지나면 잠김)
+const SetupWindow = 30 * time.Minute
+
+// minSetupPasswordLength는 초기 관리자 비밀번호의 최소 길이입니다
+const minSetupPasswordLength = 8
+
+// 초기 설정 오류
+var (
+	ErrSetupCompleted = errors.New("initial setup has already been completed with a different organization or admin")
+	ErrSetupLocked    = errors.New("setup timeout exceeded - system is locked")
+)
+
+// SetupRequest는 초기 조직과 관리자 정보입니다
+type SetupRequest struct {
+	OrgName  string `json:"org_name"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Validate는 필수 값과 비밀번호 길이를 확인합니다
+func (r SetupRequest) Validate() error {
+	if strings.TrimSpace(r.OrgName) == "" {
+		return errors.New("organization name is required")
+	}
+	if strings.TrimSpace(r.Username) == "" {
+		return errors.New("admin username is required")
+	}
+	if len(r.Password) < minSetupPasswordLength {
+		return fmt.Errorf("admin password must be at least %d characters", minSetupPasswordLength)
+	}
+	return nil
+}
+
+// SetupResult는 초기 설정 결과입니다.
+// 같은 요청으로 이미 설정이 끝난 경우 Created는 false이고 토큰은 다시 발급하지 않습니다.
+type SetupResult struct {
+	OrgID    string `json:"org_id"`
+	OrgName  string `json:"org_name"`
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Token    string `json:"token,omitempty"`
+	Created  bool   `json:"created"`
+}
+
+// SetupState는 초기 설정 진행 상태입니다
+type SetupState struct {
+	Completed bool       `json:"setup_completed"`
+	Locked    bool       `json:"locked"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	Deadline  *time.Time `json:"deadline,omitempty"`
+}
+
+// setupLocked는 설정 시작 시각으로부터 SetupWindow가 지났는지 확인합니다.
+// 시작 시각이 기록되지 않았으면 잠기지 않은 것으로 봅니다 (CheckSetupTimeout과 동일).
+func setupLocked(startedAt *time.Time, now time.Time) bool {
+	return startedAt != nil && now.Sub(*startedAt) > SetupWindow
+}
+
+// readSetupState는 system_config에서 설정 완료 여부와 시작 시각을 읽습니다
+func readSetupState(db DBTX, now time.Time) (*SetupState, error) {
+	rows, err := db.Query(`SELECT config_key, config_value FROM system_config WHERE config_key IN ('setup_completed', 'setup_started_at')`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	state := &SetupState{}
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		switch key {
+		case "setup_completed":
+			state.Completed = true
+		case "setup_started_at":
+			startedAt, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid setup_started_at: %w", err)
+			}
+			deadline := startedAt.Add(SetupWindow)
+			state.StartedAt = &startedAt
+			state.Deadline = &deadline
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	state.Locked = !state.Completed && setupLocked(state.StartedAt, now)
+	return state, nil
+}
+
+// GetSetupState는 초기 설정 상태와 잠금 기한을 조회합니다
+func GetSetupState(db DBTX) (*SetupState, error) {
+	return readSetupState(db, time.Now())
+}
+
+// RunInitialSetup은 조직, 관리자, 관리자 API 토큰을 만들고 설정을 완료로 표시합니다.
+// 같은 조직, 관리자, 비밀번호로 다시 호출하면 아무것도 바꾸지 않고 기존 정보를 반환하므로
+// 자동화된 배포에서 반복 실행할 수 있습니다. 설정 시간이 지났으면 ErrSetupLocked를 반환합니다.
+func RunInitialSetup(db *sql.DB, req SetupRequest) (*SetupResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	req.OrgName = strings.TrimSpace(req.OrgName)
+	req.Username = strings.TrimSpace(req.Username)
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 동시에 들어온 설정 요청이 둘 다 완료되지 않도록 직렬화
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('tmidb_initial_setup'))`); err != nil {
+		return nil, fmt.Errorf("failed to lock setup: %w", err)
+	}
+
+	state, err := readSetupState(tx, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read setup state: %w", err)
+	}
+	if state.Completed {
+		return existingSetup(tx, req)
+	}
+	if state.Locked {
+		return nil, ErrSetupLocked
+	}
+
+	orgID, userID, token, err := createOrgAndAdmin(tx, req.OrgName, req.Username, req.Password)
+	if err != nil {
+		return nil, err
+	}
+	// 초기 설정의 관리자만 설치 관리자 (다른 조직의 관리자는 자기 조직만 다룸)
+	if _, err := tx.Exec(`UPDATE users SET is_installation_admin = TRUE WHERE user_id = $1`, userID); err != nil {
+		return nil, fmt.Errorf("failed to mark installation admin: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO system_config (config_key, config_value)
+		VALUES ('setup_completed', 'true'), ('installation_admin_marked', 'true')
+		ON CONFLICT (config_key) DO UPDATE SET
+			config_value = EXCLUDED.config_value,
+			updated_at = now()
+	`); err != nil {
+		return nil, fmt.Errorf("failed to mark setup completed: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &SetupResult{
+		OrgID:    orgID,
+		OrgName:  req.OrgName,
+		UserID:   userID,
+		Username: req.Username,
+		Token:    token,
+		Created:  true,
+	}, nil
+}
+
+// existingSetup은 완료된 설정이 요청과 같은 조직과 관리자로 이루어졌는지 확인합니다
+func existingSetup(db DBTX, req SetupRequest) (*SetupResult, error) {
+	result := &SetupResult{OrgName: req.OrgName, Username: req.Username}
+	var passwordHash string
+	err := db.QueryRow(`
+		SELECT o.org_id::text, u.user_id::text, u.password_hash
+		FROM organizations o
+		JOIN users u ON u.org_id = o.org_id
+		WHERE o.name = $1 AND u.username = $2 AND u.role = 'admin' AND u.is_active
+	`, req.OrgName, req.Username).Scan(&result.OrgID, &result.UserID, &passwordHash)
+	if err == sql.ErrNoRows {
+		return nil, ErrSetupCompleted
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing setup: %w", err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(req.Password)) != nil {
+		return nil, ErrSetupCompleted
+	}
+	return result, nil
+}
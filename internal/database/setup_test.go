@@ -0,0 +1,37 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetupRequestValidate(t *testing.T) {
+	if err := (SetupRequest{OrgName: "Acme", Username: "admin", Password: "12345678"}).Validate(); err != nil {
+		t.Errorf("valid request rejected: %v", err)
+	}
+	for _, req := range []SetupRequest{
+		{Username: "admin", Password: "12345678"},
+		{OrgName: "Acme", Username: " ", Password: "12345678"},
+		{OrgName: "Acme", Username: "admin", Password: "1234567"},
+	} {
+		if err := req.Validate(); err == nil {
+			t.Errorf("%+v accepted", req)
+		}
+	}
+}
+
+func TestSetupLocked(t *testing.T) {
+	now := time.Now()
+	recent := now.Add(-time.Minute)
+	expired := now.Add(-SetupWindow - time.Second)
+
+	if setupLocked(nil, now) {
+		t.Error("locked without a start time")
+	}
+	if setupLocked(&recent, now) {
+		t.Error("locked inside the window")
+	}
+	if !setupLocked(&expired, now) {
+		t.Error("not locked after the window")
+	}
+}
@@ -0,0 +1,541 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+)
+
+// 느린 쿼리 기록 설정
+const (
+	SlowQueryFlushInterval = 30 * time.Second // 메모리에 모은 기록을 slow_queries에 더하는 간격
+	maxPendingSlowQueries  = 500              // 한 번 Flush하기 전까지 모으는 쿼리 종류 수
+	maxSlowQueryParam      = 1024             // 저장하는 매개변수 하나의 최대 길이
+)
+
+// SlowQuery는 fingerprint(리터럴을 지운 쿼리)별로 모은 느린 쿼리 기록입니다
+type SlowQuery struct {
+	Fingerprint  string        `json:"fingerprint"`
+	Query        string        `json:"query"`
+	Calls        int64         `json:"calls"`
+	TotalMs      float64       `json:"total_ms"`
+	MeanMs       float64       `json:"mean_ms"`
+	MaxMs        float64       `json:"max_ms"`
+	SampleQuery  string        `json:"sample_query"` // 가장 느렸던 실행
+	SampleParams []interface{} `json:"sample_params"`
+	SampleMs     float64       `json:"sample_ms"`
+	Component    string        `json:"component"` // 마지막으로 기록한 컴포넌트
+	FirstSeen    time.Time     `json:"first_seen"`
+	LastSeen     time.Time     `json:"last_seen"`
+	Plan         string        `json:"plan,omitempty"`
+	PlanError    string        `json:"plan_error,omitempty"`
+}
+
+// slowQueryStat은 Flush 전까지 메모리에 모은 한 쿼리의 기록입니다
+type slowQueryStat struct {
+	query        string
+	calls        int64
+	total        time.Duration
+	max          time.Duration
+	sampleQuery  string
+	sampleParams []interface{}
+	firstSeen    time.Time
+	lastSeen     time.Time
+}
+
+// SlowQueryLog는 threshold보다 오래 걸린 쿼리를 메모리에 모았다가 주기적으로 slow_queries 테이블에 더합니다.
+// 여러 컴포넌트와 API 인스턴스가 같은 행에 더하므로 값은 모든 프로세스의 합계입니다.
+type SlowQueryLog struct {
+	threshold time.Duration
+	params    bool
+	component string
+	write     func(fingerprint string, stat *slowQueryStat) error
+
+	mu      sync.Mutex
+	pending map[string]*slowQueryStat
+	dropped int64
+}
+
+// slowLog 현재 프로세스의 느린 쿼리 기록 (nil이면 끔)
+var slowLog atomic.Pointer[SlowQueryLog]
+
+// skipRecording 기록 자체나 EXPLAIN처럼 기록하지 않을 쿼리의 컨텍스트 표시
+type skipRecordingKey struct{}
+
+func withoutRecording(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipRecordingKey{}, true)
+}
+
+// StartSlowQueryLog는 이 프로세스의 느린 쿼리 기록을 켭니다. threshold가 0 이하면 아무것도 하지 않습니다.
+// params가 false면 매개변수 값을 저장하지 않습니다 (여러 조직이 쓰는 설치에서 값이 드러나지 않게).
+// ctx가 끝나면 마지막으로 한 번 더 Flush하고 멈춥니다.
+func StartSlowQueryLog(ctx context.Context, db *sql.DB, threshold time.Duration, params bool, component string) {
+	if threshold <= 0 || db == nil {
+		return
+	}
+	l := newSlowQueryLog(threshold, params, component, func(fingerprint string, stat *slowQueryStat) error {
+		return addSlowQuery(db, fingerprint, component, stat)
+	})
+	slowLog.Store(l)
+	log.Printf("🐢 Slow query log enabled (threshold %s)", threshold)
+
+	go func() {
+		ticker := time.NewTicker(SlowQueryFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				slowLog.CompareAndSwap(l, nil)
+				if err := l.Flush(); err != nil {
+					log.Printf("⚠️ 느린 쿼리 기록 실패: %v", err)
+				}
+				return
+			case <-ticker.C:
+				if err := l.Flush(); err != nil {
+					log.Printf("⚠️ 느린 쿼리 기록 실패 (다음 주기에 다시 시도): %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func newSlowQueryLog(threshold time.Duration, params bool, component string, write func(string, *slowQueryStat) error) *SlowQueryLog {
+	return &SlowQueryLog{
+		threshold: threshold,
+		params:    params,
+		component: component,
+		write:     write,
+		pending:   make(map[string]*slowQueryStat),
+	}
+}
+
+// observe는 쿼리 하나의 실행 시간을 받아 threshold를 넘으면 기록합니다
+func (l *SlowQueryLog) observe(query string, args []driver.NamedValue, elapsed time.Duration) {
+	if elapsed < l.threshold {
+		return
+	}
+	normalized := NormalizeQuery(query)
+	fingerprint := QueryFingerprint(normalized)
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stat, ok := l.pending[fingerprint]
+	if !ok {
+		if len(l.pending) >= maxPendingSlowQueries {
+			l.dropped++
+			return
+		}
+		stat = &slowQueryStat{query: normalized, firstSeen: now}
+		l.pending[fingerprint] = stat
+	}
+	stat.calls++
+	stat.total += elapsed
+	stat.lastSeen = now
+	if elapsed > stat.max {
+		stat.max = elapsed
+		stat.sampleQuery = query
+		stat.sampleParams = nil
+		if l.params {
+			stat.sampleParams = sampleParams(args)
+		}
+	}
+}
+
+// Flush는 모은 기록을 DB에 더합니다. 실패한 몫은 다음 Flush에서 다시 시도합니다.
+func (l *SlowQueryLog) Flush() error {
+	l.mu.Lock()
+	pending := l.pending
+	dropped := l.dropped
+	l.pending = make(map[string]*slowQueryStat)
+	l.dropped = 0
+	l.mu.Unlock()
+
+	if dropped > 0 {
+		log.Printf("⚠️ 느린 쿼리 %d건은 종류가 너무 많아 기록하지 못함", dropped)
+	}
+
+	var firstErr error
+	for fingerprint, stat := range pending {
+		if err := l.write(fingerprint, stat); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			l.mu.Lock()
+			if cur, ok := l.pending[fingerprint]; ok {
+				stat.merge(cur)
+			}
+			l.pending[fingerprint] = stat
+			l.mu.Unlock()
+		}
+	}
+	return firstErr
+}
+
+// merge는 나중에 모인 기록을 더합니다
+func (s *slowQueryStat) merge(o *slowQueryStat) {
+	s.calls += o.calls
+	s.total += o.total
+	s.lastSeen = o.lastSeen
+	if o.max > s.max {
+		s.max = o.max
+		s.sampleQuery = o.sampleQuery
+		s.sampleParams = o.sampleParams
+	}
+}
+
+// sampleParams는 매개변수를 JSON으로 저장할 수 있는 값으로 바꿉니다 (긴 값은 자름)
+func sampleParams(args []driver.NamedValue) []interface{} {
+	params := make([]interface{}, len(args))
+	for i, arg := range args {
+		var s string
+		switch v := arg.Value.(type) {
+		case nil:
+			continue
+		case []byte:
+			if utf8.Valid(v) {
+				s = string(v)
+			} else {
+				s = `\x` + hex.EncodeToString(v)
+			}
+		case string:
+			s = v
+		case time.Time:
+			s = v.Format(time.RFC3339Nano)
+		default:
+			params[i] = v
+			continue
+		}
+		if len(s) > maxSlowQueryParam {
+			s = s[:maxSlowQueryParam] + "…"
+		}
+		params[i] = s
+	}
+	return params
+}
+
+var (
+	stringLiteralPattern = regexp.MustCompile(`'(?:[^']|'')*'`)
+	numberLiteralPattern = regexp.MustCompile(`([^\w$.])-?\d+(?:\.\d+)?\b`)
+	valueListPattern     = regexp.MustCompile(`\(\s*(?:\?|\$\d+)(?:\s*,\s*(?:\?|\$\d+))+\s*\)`)
+	spacePattern         = regexp.MustCompile(`\s+`)
+)
+
+// NormalizeQuery는 공백을 한 칸으로 줄이고 문자열과 숫자 리터럴을 ?로 바꿉니다.
+// 값만 다른 쿼리(fmt.Sprintf로 만든 쿼리 포함)와 길이만 다른 IN 목록을 같은 쿼리로 묶기 위한 것입니다.
+func NormalizeQuery(query string) string {
+	q := spacePattern.ReplaceAllString(strings.TrimSpace(query), " ")
+	q = stringLiteralPattern.ReplaceAllString(q, "?")
+	q = numberLiteralPattern.ReplaceAllString(q, "$1?")
+	q = valueListPattern.ReplaceAllString(q, "(...)")
+	return q
+}
+
+// QueryFingerprint는 정규화한 쿼리의 짧은 해시입니다
+func QueryFingerprint(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:8])
+}
+
+// addSlowQuery는 slow_queries 행에 기록을 더하고 더 느린 실행이면 예시를 바꿉니다
+func addSlowQuery(db *sql.DB, fingerprint, component string, stat *slowQueryStat) error {
+	params, err := json.Marshal(stat.sampleParams)
+	if err != nil {
+		params = []byte("null")
+	}
+	_, err = db.ExecContext(withoutRecording(context.Background()), `
+		INSERT INTO slow_queries (fingerprint, query, calls, total_ms, max_ms, sample_query, sample_params, component, first_seen, last_seen)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (fingerprint) DO UPDATE SET
+			calls = slow_queries.calls + EXCLUDED.calls,
+			total_ms = slow_queries.total_ms + EXCLUDED.total_ms,
+			max_ms = GREATEST(slow_queries.max_ms, EXCLUDED.max_ms),
+			sample_query = CASE WHEN EXCLUDED.max_ms > slow_queries.max_ms THEN EXCLUDED.sample_query ELSE slow_queries.sample_query END,
+			sample_params = CASE WHEN EXCLUDED.max_ms > slow_queries.max_ms THEN EXCLUDED.sample_params ELSE slow_queries.sample_params END,
+			component = EXCLUDED.component,
+			last_seen = GREATEST(slow_queries.last_seen, EXCLUDED.last_seen)
+	`, fingerprint, stat.query, stat.calls, millis(stat.total), millis(stat.max),
+		stat.sampleQuery, string(params), component, stat.firstSeen, stat.lastSeen)
+	return err
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// slowQueryOrder 정렬 기준별 ORDER BY
+var slowQueryOrder = map[string]string{
+	"total": "total_ms DESC",
+	"max":   "max_ms DESC",
+	"mean":  "total_ms / GREATEST(calls, 1) DESC",
+	"calls": "calls DESC",
+	"last":  "last_seen DESC",
+}
+
+// SlowQuerySorts는 ListSlowQueries가 받는 정렬 기준입니다
+var SlowQuerySorts = []string{"total", "max", "mean", "calls", "last"}
+
+// ListSlowQueries는 기록된 느린 쿼리를 sort 기준(total, max, mean, calls, last)으로 limit개 반환합니다
+func ListSlowQueries(db DBTX, sort string, limit int) ([]SlowQuery, error) {
+	order, ok := slowQueryOrder[sort]
+	if !ok {
+		return nil, fmt.Errorf("invalid sort %q (use %s)", sort, strings.Join(SlowQuerySorts, ", "))
+	}
+	rows, err := db.Query(`
+		SELECT fingerprint, query, calls, total_ms, max_ms, sample_query, COALESCE(sample_params::text, 'null'),
+		       COALESCE(component, ''), first_seen, last_seen
+		FROM slow_queries
+		ORDER BY `+order+`
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	queries := []SlowQuery{}
+	for rows.Next() {
+		var q SlowQuery
+		var params string
+		if err := rows.Scan(&q.Fingerprint, &q.Query, &q.Calls, &q.TotalMs, &q.MaxMs, &q.SampleQuery, &params,
+			&q.Component, &q.FirstSeen, &q.LastSeen); err != nil {
+			return nil, err
+		}
+		json.Unmarshal([]byte(params), &q.SampleParams)
+		q.SampleMs = q.MaxMs
+		if q.Calls > 0 {
+			q.MeanMs = q.TotalMs / float64(q.Calls)
+		}
+		queries = append(queries, q)
+	}
+	return queries, rows.Err()
+}
+
+// ResetSlowQueries는 기록을 모두 지웁니다
+func ResetSlowQueries(db DBTX) (int64, error) {
+	result, err := db.Exec("DELETE FROM slow_queries")
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// explainable EXPLAIN할 수 있는 문장 (트랜잭션 제어나 DDL은 제외)
+var explainablePattern = regexp.MustCompile(`(?i)^\s*(SELECT|WITH|INSERT|UPDATE|DELETE|VALUES|TABLE)\b`)
+
+// ExplainSlowQuery는 가장 느렸던 실행을 저장된 매개변수로 EXPLAIN합니다 (실행하지 않음).
+// 매개변수가 없으면 PostgreSQL 16 이상의 GENERIC_PLAN으로 일반 계획을 봅니다.
+func ExplainSlowQuery(db *sql.DB, q *SlowQuery) (string, error) {
+	if !explainablePattern.MatchString(q.SampleQuery) {
+		return "", fmt.Errorf("only SELECT, INSERT, UPDATE and DELETE statements can be explained")
+	}
+
+	ctx, cancel := context.WithTimeout(withoutRecording(context.Background()), 10*time.Second)
+	defer cancel()
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, "SET LOCAL statement_timeout = '5s'"); err != nil {
+		return "", err
+	}
+
+	explain := "EXPLAIN " + q.SampleQuery
+	args := q.SampleParams
+	if len(args) == 0 && strings.Contains(q.SampleQuery, "$1") {
+		explain = "EXPLAIN (GENERIC_PLAN) " + q.SampleQuery
+	}
+	rows, err := tx.QueryContext(ctx, explain, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return "", err
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n"), rows.Err()
+}
+
+// ExplainSlowQueries는 각 쿼리의 Plan을 채웁니다 (실패하면 PlanError)
+func ExplainSlowQueries(db *sql.DB, queries []SlowQuery) {
+	for i := range queries {
+		plan, err := ExplainSlowQuery(db, &queries[i])
+		if err != nil {
+			queries[i].PlanError = err.Error()
+			continue
+		}
+		queries[i].Plan = plan
+	}
+}
+
+// openDB는 쿼리 시간을 재는 연결 풀을 엽니다 (느린 쿼리 기록이 꺼져 있으면 재기만 하고 버림)
+func openDB(dsn string) (*sql.DB, error) {
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(timedConnector{connector}), nil
+}
+
+// timedConnector는 pq 연결을 쿼리 시간을 재는 연결로 감쌉니다
+type timedConnector struct {
+	driver.Connector
+}
+
+func (c timedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &timedConn{conn}, nil
+}
+
+// timedConn은 pq 연결이 구현하는 인터페이스를 그대로 넘기면서 Query/Exec 시간을 잽니다
+type timedConn struct {
+	driver.Conn
+}
+
+// observeQuery는 기록이 켜져 있고 건너뛸 쿼리가 아니면 실행 시간을 넘깁니다
+func observeQuery(ctx context.Context, query string, args []driver.NamedValue, start time.Time, err error) {
+	l := slowLog.Load()
+	if l == nil || err == driver.ErrSkip || ctx.Value(skipRecordingKey{}) != nil {
+		return
+	}
+	l.observe(query, args, time.Since(start))
+}
+
+func (c *timedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	observeQuery(ctx, query, args, start, err)
+	return rows, err
+}
+
+func (c *timedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	result, err := execer.ExecContext(ctx, query, args)
+	observeQuery(ctx, query, args, start, err)
+	return result, err
+}
+
+func (c *timedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &timedStmt{Stmt: stmt, query: query}, nil
+}
+
+func (c *timedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *timedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() //nolint:staticcheck // 컨텍스트를 지원하지 않는 드라이버
+}
+
+func (c *timedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *timedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *timedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// timedStmt는 준비된 문장의 실행 시간을 잽니다 (COPY 등)
+type timedStmt struct {
+	driver.Stmt
+	query string
+}
+
+func (s *timedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var result driver.Result
+	var err error
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		result, err = execer.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValues(args); err == nil {
+			result, err = s.Stmt.Exec(values) //nolint:staticcheck // 컨텍스트를 지원하지 않는 문장 (pq COPY)
+		}
+	}
+	observeQuery(ctx, s.query, args, start, err)
+	return result, err
+}
+
+func (s *timedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var rows driver.Rows
+	var err error
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValues(args); err == nil {
+			rows, err = s.Stmt.Query(values) //nolint:staticcheck // 컨텍스트를 지원하지 않는 문장
+		}
+	}
+	observeQuery(ctx, s.query, args, start, err)
+	return rows, err
+}
+
+func namedValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, fmt.Errorf("named parameters are not supported")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNormalizeQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"whitespace", "SELECT *\n\t FROM target\n WHERE org_id = $1", "SELECT * FROM target WHERE org_id = $1"},
+		{"string literal", "SELECT * FROM t WHERE data->>'name' = 'it''s'", "SELECT * FROM t WHERE data->>? = ?"},
+		{"numbers", "SELECT * FROM t WHERE (data->>'temp')::float > 21.5 LIMIT 10", "SELECT * FROM t WHERE (data->>?)::float > ? LIMIT ?"},
+		{"negative number", "SELECT * FROM t WHERE x = -5", "SELECT * FROM t WHERE x = ?"},
+		{"identifiers kept", "SELECT t1.col2 FROM ts_obs_2026 t1", "SELECT t1.col2 FROM ts_obs_2026 t1"},
+		{"in list", "SELECT * FROM t WHERE id IN ($1, $2, $3)", "SELECT * FROM t WHERE id IN (...)"},
+		{"literal in list", "SELECT * FROM t WHERE id IN (1, 2, 3, 4)", "SELECT * FROM t WHERE id IN (...)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeQuery(tt.query); got != tt.want {
+				t.Errorf("NormalizeQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if QueryFingerprint(NormalizeQuery("SELECT 1 WHERE a IN (1,2)")) != QueryFingerprint(NormalizeQuery("SELECT  7 WHERE a IN (3, 4, 5)")) {
+		t.Error("queries that differ only in literals should share a fingerprint")
+	}
+}
+
+func TestSlowQueryLog(t *testing.T) {
+	written := map[string]*slowQueryStat{}
+	fail := true
+	l := newSlowQueryLog(100*time.Millisecond, true, "api", func(fingerprint string, stat *slowQueryStat) error {
+		if fail {
+			return errors.New("database unavailable")
+		}
+		written[fingerprint] = stat
+		return nil
+	})
+
+	query := "SELECT * FROM target WHERE name = $1"
+	l.observe(query, []driver.NamedValue{{Ordinal: 1, Value: "fast"}}, 50*time.Millisecond)
+	l.observe(query, []driver.NamedValue{{Ordinal: 1, Value: "slow"}}, 300*time.Millisecond)
+	l.observe(query, []driver.NamedValue{{Ordinal: 1, Value: []byte{0xff}}}, 200*time.Millisecond)
+
+	// 실패한 기록은 다음 Flush까지 남아 새 기록과 합쳐짐
+	if err := l.Flush(); err == nil {
+		t.Fatal("Flush() should return the write error")
+	}
+	l.observe(query, []driver.NamedValue{{Ordinal: 1, Value: "slowest"}}, 400*time.Millisecond)
+	fail = false
+	if err := l.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	stat := written[QueryFingerprint(NormalizeQuery(query))]
+	if stat == nil {
+		t.Fatalf("query not written: %v", written)
+	}
+	if stat.calls != 3 || stat.total != 900*time.Millisecond || stat.max != 400*time.Millisecond {
+		t.Errorf("calls=%d total=%s max=%s, want 3, 900ms, 400ms", stat.calls, stat.total, stat.max)
+	}
+	if len(stat.sampleParams) != 1 || stat.sampleParams[0] != "slowest" {
+		t.Errorf("sample params = %v, want the slowest run's", stat.sampleParams)
+	}
+	if got := sampleParams([]driver.NamedValue{{Value: []byte{0xff}}}); got[0] != `\xff` {
+		t.Errorf("binary param = %v, want hex", got[0])
+	}
+	if err := l.Flush(); err != nil || len(l.pending) != 0 {
+		t.Errorf("second Flush() = %v with %d pending, want nothing to write", err, len(l.pending))
+	}
+}
@@ -0,0 +1,257 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrSQLNotAllowed는 SQL 콘솔이 실행하지 않는 문입니다 (조회 문이 아님)
+var ErrSQLNotAllowed = errors.New("only SELECT, WITH, VALUES, TABLE, SHOW and EXPLAIN statements are allowed")
+
+// ErrSQLFunctionNotAllowed는 읽기 전용 트랜잭션에서도 부작용이 있는 함수를 부르는 문입니다
+var ErrSQLFunctionNotAllowed = errors.New("statement calls a function the SQL console does not allow")
+
+// sqlConsoleDeniedFunctions 읽기 전용 트랜잭션이 막지 못하는 함수. 다른 세션 종료/취소, 제한 시간 등
+// 설정 변경(set_config로 SET LOCAL statement_timeout을 되돌림), 세션 단위 잠금, 서버 파일 접근,
+// 문자열로 받은 SQL을 실행해 이 검사를 우회할 수 있는 함수입니다
+var sqlConsoleDeniedFunctions = map[string]bool{
+	"pg_terminate_backend":           true,
+	"pg_cancel_backend":              true,
+	"set_config":                     true,
+	"pg_reload_conf":                 true,
+	"pg_rotate_logfile":              true,
+	"pg_promote":                     true,
+	"pg_switch_wal":                  true,
+	"pg_create_restore_point":        true,
+	"pg_log_backend_memory_contexts": true,
+	"pg_notify":                      true,
+	"pg_stat_file":                   true,
+	"query_to_xml":                   true,
+	"query_to_xml_and_xmlschema":     true,
+	"query_to_xmlschema":             true,
+	"cursor_to_xml":                  true,
+	"cursor_to_xmlschema":            true,
+}
+
+// sqlConsoleDeniedPrefixes 이름이 이렇게 시작하는 함수도 거부 (pg_sleep_for, pg_advisory_xact_lock, lo_export 등)
+var sqlConsoleDeniedPrefixes = []string{"pg_sleep", "pg_advisory", "pg_try_advisory", "pg_read_", "pg_ls_", "lo_", "dblink"}
+
+// sqlConsoleKeywords SQL 콘솔에서 실행할 수 있는 문의 첫 단어.
+// COPY는 읽기 전용 트랜잭션에서도 서버 파일이나 프로그램으로 쓸 수 있어 제외합니다.
+var sqlConsoleKeywords = map[string]bool{
+	"select":  true,
+	"with":    true,
+	"values":  true,
+	"table":   true,
+	"show":    true,
+	"explain": true,
+}
+
+// SQLColumn은 SQL 콘솔 결과의 컬럼 하나와 그 값들입니다 (행 순서)
+type SQLColumn struct {
+	Name   string        `json:"name"`
+	Type   string        `json:"type"` // PostgreSQL 타입 이름 (INT8, TEXT, JSONB...)
+	Values []interface{} `json:"values"`
+}
+
+// SQLResult는 SQL 콘솔 실행 결과입니다. 값은 컬럼별로 모아 반환합니다.
+type SQLResult struct {
+	Columns    []SQLColumn `json:"columns"`
+	RowCount   int         `json:"row_count"`
+	Truncated  bool        `json:"truncated"` // 행이 maxRows보다 많아 나머지를 읽지 않음
+	DurationMs float64     `json:"duration_ms"`
+}
+
+// SQLConsoleEntry는 SQL 콘솔 실행 기록입니다
+type SQLConsoleEntry struct {
+	ID         int64     `json:"id"`
+	OrgID      string    `json:"org_id"`
+	Actor      string    `json:"actor"`
+	ClientIP   string    `json:"client_ip,omitempty"`
+	RequestID  string    `json:"request_id,omitempty"`
+	Statement  string    `json:"statement"`
+	RowCount   int       `json:"row_count"`
+	Truncated  bool      `json:"truncated"`
+	DurationMs float64   `json:"duration_ms"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CheckConsoleStatement는 앞의 공백과 주석을 건너뛴 첫 단어로 조회 문인지 확인하고, 부작용이 있는 함수를 거릅니다.
+// 쓰기는 읽기 전용 트랜잭션이 막고, 이 확인은 트랜잭션 제어나 COPY, pg_terminate_backend처럼 읽기 전용이어도 위험한 문을 거릅니다.
+func CheckConsoleStatement(statement string) error {
+	rest := statement
+	for {
+		rest = strings.TrimLeftFunc(rest, func(r rune) bool { return unicode.IsSpace(r) || r == '(' })
+		switch {
+		case strings.HasPrefix(rest, "--"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				rest = ""
+			} else {
+				rest = rest[end+1:]
+			}
+			continue
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest, "*/")
+			if end < 0 {
+				rest = ""
+			} else {
+				rest = rest[end+2:]
+			}
+			continue
+		}
+		break
+	}
+	if rest == "" {
+		return errors.New("empty statement")
+	}
+	end := strings.IndexFunc(rest, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end < 0 {
+		end = len(rest)
+	}
+	word := strings.ToLower(rest[:end])
+	if !sqlConsoleKeywords[word] {
+		return fmt.Errorf("%w (got %s)", ErrSQLNotAllowed, strings.ToUpper(word))
+	}
+	return checkConsoleFunctions(statement)
+}
+
+// checkConsoleFunctions는 문에 나오는 모든 이름(주석, 문자열, 따옴표 식별자 안 포함)을 거부할 함수와 비교합니다.
+// U&"..." 유니코드 이스케이프는 이름을 숨길 수 있어 거부합니다.
+func checkConsoleFunctions(statement string) error {
+	lower := strings.ToLower(statement)
+	if strings.Contains(lower, `u&"`) || strings.Contains(lower, "u&'") {
+		return fmt.Errorf("%w (unicode escapes)", ErrSQLFunctionNotAllowed)
+	}
+	words := strings.FieldsFunc(lower, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '$'
+	})
+	for _, word := range words {
+		denied := sqlConsoleDeniedFunctions[word]
+		for _, prefix := range sqlConsoleDeniedPrefixes {
+			denied = denied || strings.HasPrefix(word, prefix)
+		}
+		if denied {
+			return fmt.Errorf("%w (%s)", ErrSQLFunctionNotAllowed, word)
+		}
+	}
+	return nil
+}
+
+// RunConsoleSQL은 문 하나를 읽기 전용 트랜잭션에서 실행하고 최대 maxRows 행을 반환합니다.
+// statement_timeout으로 서버에서 실행 시간을 제한하고, 트랜잭션은 항상 롤백합니다.
+// 문은 준비된 문으로 보내므로 세미콜론으로 이은 여러 문은 서버가 거부합니다.
+func RunConsoleSQL(ctx context.Context, db *sql.DB, statement string, timeout time.Duration, maxRows int) (*SQLResult, error) {
+	if err := CheckConsoleStatement(statement); err != nil {
+		return nil, err
+	}
+
+	// 콘솔 문은 느린 쿼리 기록에 넣지 않음 (제한 시간 안에서 오래 걸리는 문을 일부러 실행하기도 함)
+	ctx, cancel := context.WithTimeout(withoutRecording(ctx), timeout+5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds())); err != nil {
+		return nil, err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, statement)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	result := &SQLResult{Columns: make([]SQLColumn, len(types))}
+	for i, t := range types {
+		result.Columns[i] = SQLColumn{Name: t.Name(), Type: t.DatabaseTypeName(), Values: []interface{}{}}
+	}
+
+	values := make([]interface{}, len(types))
+	pointers := make([]interface{}, len(types))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if result.RowCount == maxRows {
+			result.Truncated = true
+			break
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				v = string(b) // text, numeric, json 등은 드라이버가 바이트로 줌
+			}
+			result.Columns[i].Values = append(result.Columns[i].Values, v)
+		}
+		result.RowCount++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	result.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+	return result, nil
+}
+
+// RecordSQLConsole은 SQL 콘솔 실행 기록을 남깁니다 (거부되거나 실패한 문은 Error에 이유)
+func RecordSQLConsole(db DBTX, e *SQLConsoleEntry) error {
+	var errText interface{}
+	if e.Error != "" {
+		errText = e.Error
+	}
+	_, err := db.Exec(`
+		INSERT INTO sql_console_log (org_id, actor, client_ip, request_id, statement, row_count, truncated, duration_ms, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, e.OrgID, e.Actor, e.ClientIP, e.RequestID, e.Statement, e.RowCount, e.Truncated, e.DurationMs, errText)
+	return err
+}
+
+// GetSQLConsoleLog는 조직의 SQL 콘솔 실행 기록을 최신 순으로 조회합니다
+func GetSQLConsoleLog(db DBTX, orgID string, limit int) ([]SQLConsoleEntry, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := db.Query(`
+		SELECT id, org_id, actor, client_ip, request_id, statement, row_count, truncated, duration_ms, COALESCE(error, ''), created_at
+		FROM sql_console_log
+		WHERE org_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`, orgID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []SQLConsoleEntry{}
+	for rows.Next() {
+		var e SQLConsoleEntry
+		if err := rows.Scan(&e.ID, &e.OrgID, &e.Actor, &e.ClientIP, &e.RequestID, &e.Statement,
+			&e.RowCount, &e.Truncated, &e.DurationMs, &e.Error, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
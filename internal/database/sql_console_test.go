@@ -0,0 +1,63 @@
+package database
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckConsoleStatement(t *testing.T) {
+	for _, ok := range []string{
+		"SELECT 1",
+		"  select * from target limit 10;",
+		"-- 최근 문서\nWITH recent AS (SELECT 1) SELECT * FROM recent",
+		"/* plan */ EXPLAIN ANALYZE SELECT 1",
+		"(SELECT 1) UNION (SELECT 2)",
+		"show statement_timeout",
+		"TABLE organizations",
+		"VALUES (1), (2)",
+	} {
+		if err := CheckConsoleStatement(ok); err != nil {
+			t.Errorf("CheckConsoleStatement(%q) = %v", ok, err)
+		}
+	}
+
+	for _, bad := range []string{
+		"DELETE FROM target",
+		"COMMIT",
+		"copy target to program 'id'",
+		"/* select */ DROP TABLE target",
+		"-- select\nSET default_transaction_read_only = off",
+		"1",
+	} {
+		if err := CheckConsoleStatement(bad); !errors.Is(err, ErrSQLNotAllowed) {
+			t.Errorf("CheckConsoleStatement(%q) = %v, want ErrSQLNotAllowed", bad, err)
+		}
+	}
+	for _, bad := range []string{
+		"SELECT pg_terminate_backend(pid) FROM pg_stat_activity",
+		"select PG_CANCEL_BACKEND(123)",
+		"SELECT pg_sleep(3600)",
+		"SELECT pg_catalog.pg_sleep_for('1 hour')",
+		"SELECT set_config('statement_timeout', '0', true), pg_sleep(60)",
+		`SELECT "set_config"('statement_timeout', '0', false)`,
+		"SELECT pg_advisory_lock(1)",
+		"SELECT * FROM query_to_xml('select pg_' || 'sleep(60)', true, false, '')",
+		`SELECT U&"\0070g_sleep"(1)`,
+		"WITH x AS (SELECT lo_export(1, '/tmp/x')) SELECT * FROM x",
+	} {
+		if err := CheckConsoleStatement(bad); !errors.Is(err, ErrSQLFunctionNotAllowed) {
+			t.Errorf("CheckConsoleStatement(%q) = %v, want ErrSQLFunctionNotAllowed", bad, err)
+		}
+	}
+	// 비슷한 이름의 컬럼이나 읽기 함수는 허용
+	for _, ok := range []string{"SELECT current_setting('statement_timeout')", "SELECT sleep_minutes, config FROM devices"} {
+		if err := CheckConsoleStatement(ok); err != nil {
+			t.Errorf("CheckConsoleStatement(%q) = %v", ok, err)
+		}
+	}
+	for _, empty := range []string{"", "  \n", "-- only a comment", "/* unterminated SELECT 1"} {
+		if err := CheckConsoleStatement(empty); err == nil {
+			t.Errorf("CheckConsoleStatement(%q) accepted", empty)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// SSO 로그인 오류
+var (
+	ErrSSOUserNotProvisioned = errors.New("no tmiDB user is linked to this SSO account")
+	ErrSSOUserInactive       = errors.New("the tmiDB user linked to this SSO account is deactivated")
+	ErrSSOOrgNotFound        = errors.New("organization from the SSO claims does not exist")
+	ErrSSOUsernameTaken      = errors.New("a local user with the same username already exists in the organization")
+)
+
+// SSOLogin은 SSO 로그인 요청입니다. Org는 조직 이름 또는 ID입니다.
+type SSOLogin struct {
+	Issuer   string
+	Subject  string
+	Username string
+	Org      string
+	Role     string
+}
+
+// SSOUser는 SSO 로그인으로 찾거나 만든 사용자입니다
+type SSOUser struct {
+	UserID   string
+	OrgID    string
+	Username string
+	Role     string
+	Created  bool
+}
+
+// LoginSSOUser는 발급자와 sub로 연결된 사용자를 찾아 역할을 클레임에 맞춰 갱신합니다.
+// 연결된 사용자가 없으면 autoProvision일 때 조직에 새 사용자를 만듭니다.
+// SSO 사용자는 비밀번호로 로그인할 수 없고, 같은 이름의 로컬 사용자와 연결하지 않습니다.
+func LoginSSOUser(db *sql.DB, login SSOLogin, autoProvision bool) (*SSOUser, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	user := &SSOUser{Role: login.Role}
+	var isActive bool
+	err = tx.QueryRow(`
+		SELECT user_id::text, org_id::text, username, is_active FROM users
+		WHERE auth_provider = $1 AND external_subject = $2
+		FOR UPDATE
+	`, login.Issuer, login.Subject).Scan(&user.UserID, &user.OrgID, &user.Username, &isActive)
+	switch {
+	case err == nil:
+		if !isActive {
+			return nil, ErrSSOUserInactive
+		}
+		if _, err := tx.Exec(`UPDATE users SET role = $2, updated_at = now() WHERE user_id = $1 AND role <> $2`,
+			user.UserID, login.Role); err != nil {
+			return nil, fmt.Errorf("failed to update role: %w", err)
+		}
+	case err == sql.ErrNoRows:
+		if !autoProvision {
+			return nil, ErrSSOUserNotProvisioned
+		}
+		if err := provisionSSOUser(tx, login, user); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("failed to find sso user: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return user, nil
+}
+
+// provisionSSOUser는 처음 로그인한 SSO 사용자를 조직에 만듭니다.
+// 비밀번호는 아무도 모르는 임의 값의 해시로 채웁니다 (로컬 로그인도 auth_provider로 막힘).
+func provisionSSOUser(tx *sql.Tx, login SSOLogin, user *SSOUser) error {
+	err := tx.QueryRow(`SELECT org_id::text FROM organizations WHERE name = $1 OR org_id::text = $1`, login.Org).Scan(&user.OrgID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: %s", ErrSSOOrgNotFound, login.Org)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find organization: %w", err)
+	}
+
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM users WHERE org_id = $1 AND username = $2)`,
+		user.OrgID, login.Username).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check username: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("%w: %s", ErrSSOUsernameTaken, login.Username)
+	}
+
+	secret, err := newTokenString()
+	if err != nil {
+		return err
+	}
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user.Username = login.Username
+	user.Created = true
+	if err := tx.QueryRow(`
+		INSERT INTO users (org_id, username, password_hash, role, is_active, auth_provider, external_subject)
+		VALUES ($1, $2, $3, $4, true, $5, $6)
+		RETURNING user_id::text
+	`, user.OrgID, login.Username, string(passwordHash), login.Role, login.Issuer, login.Subject).Scan(&user.UserID); err != nil {
+		return fmt.Errorf("failed to create sso user: %w", err)
+	}
+	return nil
+}
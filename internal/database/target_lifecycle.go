@@ -0,0 +1,465 @@
+package database
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// 타겟 수명 주기 작업 (감사 로그와 확인 토큰의 action)
+const (
+	TargetActionArchive   = "archive"
+	TargetActionUnarchive = "unarchive"
+	TargetActionMerge     = "merge"
+	TargetActionDelete    = "delete"
+	TargetActionRestore   = "restore"
+)
+
+// TargetConfirmationTTL은 보관/병합/삭제 확인 토큰의 유효 시간입니다
+const TargetConfirmationTTL = 5 * time.Minute
+
+// 타겟 수명 주기 오류
+var (
+	ErrTargetNotFound      = errors.New("target not found")
+	ErrTargetShared        = errors.New("target is also used by another organization")
+	ErrConfirmationInvalid = errors.New("confirmation token is invalid, expired or was issued for a different request")
+)
+
+// TargetFootprint는 타겟과 타겟에 딸린 데이터의 양입니다 (보관/병합/삭제 미리보기)
+type TargetFootprint struct {
+	TargetID     string     `json:"target_id"`
+	Name         string     `json:"name"`
+	ArchivedAt   *time.Time `json:"archived_at,omitempty"`
+	Categories   []string   `json:"categories"`
+	Observations int64      `json:"observations"`
+	GeoPoints    int64      `json:"geo_points"`
+	Attachments  int64      `json:"attachments"`
+}
+
+// TargetMerge는 병합 결과입니다. 옮긴 수에는 대상 타겟에 이미 같은 시각의 값이 있어 버린 행은 빠집니다.
+type TargetMerge struct {
+	Source                TargetFootprint `json:"source"`
+	Destination           TargetFootprint `json:"destination"`
+	CategoriesMoved       int64           `json:"categories_moved"`
+	ObservationsMoved     int64           `json:"observations_moved"`
+	ObservationsDiscarded int64           `json:"observations_discarded"`
+	GeoPointsMoved        int64           `json:"geo_points_moved"`
+	AttachmentsMoved      int64           `json:"attachments_moved"`
+}
+
+// TargetDeletion은 삭제된 타겟들입니다. StoragePaths는 파일 저장소에서 따로 지워야 하는 첨부 파일 경로입니다.
+type TargetDeletion struct {
+	Targets      []TargetFootprint `json:"targets"`
+	StoragePaths []string          `json:"-"`
+}
+
+// TargetAuditEntry는 타겟 감사 로그 항목입니다
+type TargetAuditEntry struct {
+	ID        int64                  `json:"id"`
+	OrgID     string                 `json:"org_id"`
+	Action    string                 `json:"action"`
+	TargetIDs []string               `json:"target_ids"`
+	Actor     string                 `json:"actor"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// TargetRequestHash는 확인 토큰을 발급한 요청과 실행 요청이 같은지 비교하는 해시입니다.
+// 병합은 방향이 있으므로 타겟 순서도 해시에 포함됩니다. 여러 타겟은 호출하는 쪽에서 정렬해서 넘깁니다.
+func TargetRequestHash(action string, targetIDs []string) string {
+	h := sha256.New()
+	io.WriteString(h, action)
+	for _, id := range targetIDs {
+		io.WriteString(h, "\n"+strings.ToLower(id))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// IssueTargetConfirmation은 작업을 실행할 때 함께 보내야 하는 한 번만 쓸 수 있는 확인 토큰을 발급합니다.
+// 토큰은 해시로만 저장하고, 만료된 토큰은 이때 정리합니다.
+func IssueTargetConfirmation(db DBTX, orgID, action, requestHash string) (string, time.Time, error) {
+	token, err := GenerateSessionToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if _, err := db.Exec("DELETE FROM target_confirmations WHERE expires_at < now()"); err != nil {
+		return "", time.Time{}, err
+	}
+	var expiresAt time.Time
+	err = db.QueryRow(`
+		INSERT INTO target_confirmations (token_hash, org_id, action, request_hash, expires_at)
+		VALUES ($1, $2, $3, $4, now() + $5::interval)
+		RETURNING expires_at
+	`, hashToken(token), orgID, action, requestHash, fmt.Sprintf("%d seconds", int(TargetConfirmationTTL.Seconds()))).Scan(&expiresAt)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// consumeTargetConfirmation은 같은 조직, 작업, 요청으로 발급된 유효한 확인 토큰을 사용 처리합니다.
+// 트랜잭션이 롤백되면 토큰도 다시 쓸 수 있게 됩니다.
+func consumeTargetConfirmation(tx *sql.Tx, token, orgID, action, requestHash string) error {
+	var one int
+	err := tx.QueryRow(`
+		DELETE FROM target_confirmations
+		WHERE token_hash = $1 AND org_id = $2 AND action = $3 AND request_hash = $4 AND expires_at > now()
+		RETURNING 1
+	`, hashToken(token), orgID, action, requestHash).Scan(&one)
+	if err == sql.ErrNoRows {
+		return ErrConfirmationInvalid
+	}
+	return err
+}
+
+// RecordTargetAudit는 타겟 감사 로그를 남깁니다. actor는 작업한 주체입니다 (예: user:<id>, token:<id>).
+func RecordTargetAudit(db DBTX, orgID, action string, targetIDs []string, actor string, details map[string]interface{}) error {
+	var detailsJSON interface{}
+	if len(details) > 0 {
+		encoded, err := json.Marshal(details)
+		if err != nil {
+			return err
+		}
+		detailsJSON = string(encoded)
+	}
+	_, err := db.Exec(`
+		INSERT INTO target_audit_log (org_id, action, target_ids, actor, details)
+		VALUES ($1, $2, $3, $4, $5)
+	`, orgID, action, pq.Array(targetIDs), actor, detailsJSON)
+	return err
+}
+
+// GetTargetAuditLog는 조직의 타겟 감사 로그를 최신 순으로 조회합니다. targetID가 비어 있으면 모든 타겟의 로그입니다.
+func GetTargetAuditLog(db DBTX, orgID, targetID string, limit int) ([]TargetAuditEntry, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := db.Query(`
+		SELECT id, org_id, action, target_ids, actor, details, created_at
+		FROM target_audit_log
+		WHERE org_id = $1 AND ($2 = '' OR $2 = ANY(target_ids))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`, orgID, strings.ToLower(targetID), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []TargetAuditEntry{}
+	for rows.Next() {
+		var e TargetAuditEntry
+		var details []byte
+		if err := rows.Scan(&e.ID, &e.OrgID, &e.Action, pq.Array(&e.TargetIDs), &e.Actor, &details, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		if len(details) > 0 {
+			json.Unmarshal(details, &e.Details)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// GetTargetFootprints는 조직 타겟들의 데이터 양을 조회합니다.
+// 타겟 테이블에는 조직이 없으므로 카테고리 문서나 첨부 파일로 조직에 연결된 타겟만 조직의 타겟으로 봅니다.
+// 조직의 타겟이 아니면 ErrTargetNotFound, 다른 조직도 쓰는 타겟이면 ErrTargetShared를 반환합니다.
+func GetTargetFootprints(db DBTX, orgID string, targetIDs []string) ([]TargetFootprint, error) {
+	return targetFootprints(db, orgID, targetIDs, false)
+}
+
+// targetFootprints는 GetTargetFootprints와 같고, lock이면 작업이 끝날 때까지 타겟 행을 잠급니다
+func targetFootprints(db DBTX, orgID string, targetIDs []string, lock bool) ([]TargetFootprint, error) {
+	query := `
+		SELECT t.target_id::text, t.name, t.archived_at,
+		       ARRAY(SELECT tc.category_name FROM target_categories tc
+		             WHERE tc.target_id = t.target_id AND tc.deleted_at IS NULL ORDER BY 1),
+		       (SELECT COUNT(*) FROM ts_obs o WHERE o.target_id = t.target_id),
+		       (SELECT COUNT(*) FROM geo_trace g WHERE g.target_id = t.target_id),
+		       (SELECT COUNT(*) FROM file_attachments fa WHERE fa.target_id = t.target_id),
+		       EXISTS (SELECT 1 FROM target_categories tc WHERE tc.target_id = t.target_id AND tc.org_id::text = $2)
+		         OR EXISTS (SELECT 1 FROM file_attachments fa WHERE fa.target_id = t.target_id AND fa.org_id = $2),
+		       EXISTS (SELECT 1 FROM target_categories tc WHERE tc.target_id = t.target_id AND tc.org_id::text <> $2)
+		         OR EXISTS (SELECT 1 FROM file_attachments fa WHERE fa.target_id = t.target_id AND fa.org_id <> $2)
+		FROM target t
+		WHERE t.target_id::text = ANY($1) AND t.deleted_at IS NULL
+		ORDER BY t.target_id`
+	if lock {
+		// 타겟 ID 순으로 잠가 동시에 실행된 작업끼리 교착 상태가 되지 않도록 함
+		query += " FOR UPDATE OF t"
+	}
+	ids := make([]string, len(targetIDs))
+	for i, id := range targetIDs {
+		ids[i] = strings.ToLower(id)
+	}
+	rows, err := db.Query(query, pq.Array(ids), orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[string]TargetFootprint, len(ids))
+	for rows.Next() {
+		var f TargetFootprint
+		var archivedAt sql.NullTime
+		var owned, shared bool
+		if err := rows.Scan(&f.TargetID, &f.Name, &archivedAt, pq.Array(&f.Categories),
+			&f.Observations, &f.GeoPoints, &f.Attachments, &owned, &shared); err != nil {
+			return nil, err
+		}
+		if !owned {
+			continue
+		}
+		if shared {
+			return nil, fmt.Errorf("%w: %s", ErrTargetShared, f.TargetID)
+		}
+		if archivedAt.Valid {
+			f.ArchivedAt = &archivedAt.Time
+		}
+		if f.Categories == nil {
+			f.Categories = []string{}
+		}
+		byID[f.TargetID] = f
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	footprints := make([]TargetFootprint, 0, len(ids))
+	for _, id := range ids {
+		f, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTargetNotFound, id)
+		}
+		footprints = append(footprints, f)
+	}
+	return footprints, nil
+}
+
+// ArchiveTarget은 타겟을 보관합니다. 보관된 타겟은 기본 목록 조회에서 빠지지만 데이터는 그대로 남습니다.
+// confirmToken은 IssueTargetConfirmation으로 같은 타겟에 대해 발급한 토큰이어야 합니다.
+func ArchiveTarget(db *sql.DB, orgID, targetID, confirmToken, actor string) (*TargetFootprint, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ids := []string{targetID}
+	if err := consumeTargetConfirmation(tx, confirmToken, orgID, TargetActionArchive, TargetRequestHash(TargetActionArchive, ids)); err != nil {
+		return nil, err
+	}
+	footprints, err := targetFootprints(tx, orgID, ids, true)
+	if err != nil {
+		return nil, err
+	}
+	target := footprints[0]
+	if target.ArchivedAt != nil {
+		// 이미 보관됨, 보관 시각은 처음 보관한 시각으로 유지
+		return &target, tx.Commit()
+	}
+
+	var archivedAt time.Time
+	if err := tx.QueryRow(
+		"UPDATE target SET archived_at = now() WHERE target_id::text = $1 RETURNING archived_at", target.TargetID,
+	).Scan(&archivedAt); err != nil {
+		return nil, err
+	}
+	target.ArchivedAt = &archivedAt
+	if err := RecordTargetAudit(tx, orgID, TargetActionArchive, []string{target.TargetID}, actor,
+		map[string]interface{}{"name": target.Name}); err != nil {
+		return nil, fmt.Errorf("failed to record target audit: %w", err)
+	}
+	return &target, tx.Commit()
+}
+
+// UnarchiveTarget은 보관된 타겟을 다시 기본 조회에 보이게 합니다 (되돌리는 작업이므로 확인 토큰이 필요 없음)
+func UnarchiveTarget(db *sql.DB, orgID, targetID, actor string) (*TargetFootprint, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	footprints, err := targetFootprints(tx, orgID, []string{targetID}, true)
+	if err != nil {
+		return nil, err
+	}
+	target := footprints[0]
+	if target.ArchivedAt == nil {
+		return &target, tx.Commit()
+	}
+
+	if _, err := tx.Exec("UPDATE target SET archived_at = NULL WHERE target_id::text = $1", target.TargetID); err != nil {
+		return nil, err
+	}
+	if err := RecordTargetAudit(tx, orgID, TargetActionUnarchive, []string{target.TargetID}, actor,
+		map[string]interface{}{"name": target.Name, "archived_at": target.ArchivedAt}); err != nil {
+		return nil, fmt.Errorf("failed to record target audit: %w", err)
+	}
+	target.ArchivedAt = nil
+	return &target, tx.Commit()
+}
+
+// MergeTargets는 source 타겟의 이력을 dest 타겟으로 옮기고 source를 삭제합니다 (한 트랜잭션).
+// dest에 없는 카테고리 문서는 그대로 옮기고, 이미 있는 카테고리는 dest의 문서를 유지합니다.
+// 관측값과 위치 이력은 dest에 같은 시각의 값이 없을 때만 옮기며, 첨부 파일은 모두 dest로 옮깁니다.
+// 휴지통에 있는 source의 카테고리 문서는 옮기지 않고 source와 함께 지웁니다.
+func MergeTargets(db *sql.DB, orgID, sourceID, destID, confirmToken, actor string) (*TargetMerge, error) {
+	if strings.EqualFold(sourceID, destID) {
+		return nil, fmt.Errorf("cannot merge a target into itself")
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	if err := SetChangeActor(tx, actor); err != nil {
+		return nil, err
+	}
+
+	ids := []string{sourceID, destID}
+	if err := consumeTargetConfirmation(tx, confirmToken, orgID, TargetActionMerge, TargetRequestHash(TargetActionMerge, ids)); err != nil {
+		return nil, err
+	}
+	footprints, err := targetFootprints(tx, orgID, ids, true)
+	if err != nil {
+		return nil, err
+	}
+	merge := &TargetMerge{Source: footprints[0]}
+	source, dest := footprints[0].TargetID, footprints[1].TargetID
+
+	exec := func(query string) (int64, error) {
+		result, err := tx.Exec(query, source, dest)
+		if err != nil {
+			return 0, err
+		}
+		return result.RowsAffected()
+	}
+
+	// 관측값의 외래 키가 (타겟, 카테고리) 문서를 가리키므로 카테고리 문서를 먼저 옮김
+	if merge.CategoriesMoved, err = exec(`
+		INSERT INTO target_categories (target_id, org_id, category_name, schema_version, category_data, created_at, updated_at)
+		SELECT $2::uuid, org_id, category_name, schema_version, category_data, created_at, now()
+		FROM target_categories WHERE target_id = $1::uuid AND deleted_at IS NULL
+		ON CONFLICT DO NOTHING`); err != nil {
+		return nil, fmt.Errorf("failed to move categories: %w", err)
+	}
+	if merge.ObservationsMoved, err = exec(`
+		INSERT INTO ts_obs (target_id, category_name, ts, payload)
+		SELECT $2::uuid, category_name, ts, payload FROM ts_obs WHERE target_id = $1::uuid
+		ON CONFLICT DO NOTHING`); err != nil {
+		return nil, fmt.Errorf("failed to move observations: %w", err)
+	}
+	merge.ObservationsDiscarded = merge.Source.Observations - merge.ObservationsMoved
+	if merge.GeoPointsMoved, err = exec(`
+		INSERT INTO geo_trace (target_id, ts, lon, lat)
+		SELECT $2::uuid, ts, lon, lat FROM geo_trace WHERE target_id = $1::uuid
+		ON CONFLICT DO NOTHING`); err != nil {
+		return nil, fmt.Errorf("failed to move geo trace: %w", err)
+	}
+	if merge.AttachmentsMoved, err = exec(
+		"UPDATE file_attachments SET target_id = $2::uuid WHERE target_id = $1::uuid"); err != nil {
+		return nil, fmt.Errorf("failed to move attachments: %w", err)
+	}
+
+	// 옮기지 않은 중복 행은 source와 함께 외래 키로 지워짐
+	if _, err := tx.Exec("DELETE FROM target WHERE target_id = $1::uuid", source); err != nil {
+		return nil, fmt.Errorf("failed to delete merged target: %w", err)
+	}
+	if _, err := tx.Exec("UPDATE target SET updated_at = now() WHERE target_id = $1::uuid", dest); err != nil {
+		return nil, err
+	}
+
+	if err := RecordTargetAudit(tx, orgID, TargetActionMerge, ids, actor, map[string]interface{}{
+		"source":                 source,
+		"source_name":            merge.Source.Name,
+		"destination":            dest,
+		"categories_moved":       merge.CategoriesMoved,
+		"observations_moved":     merge.ObservationsMoved,
+		"observations_discarded": merge.ObservationsDiscarded,
+		"geo_points_moved":       merge.GeoPointsMoved,
+		"attachments_moved":      merge.AttachmentsMoved,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to record target audit: %w", err)
+	}
+
+	after, err := targetFootprints(tx, orgID, []string{dest}, false)
+	if err != nil {
+		return nil, err
+	}
+	merge.Destination = after[0]
+	return merge, tx.Commit()
+}
+
+// DeleteTargets는 타겟들과 카테고리 문서, 관측값, 위치 이력, 첨부 파일 메타데이터를 한 트랜잭션으로 삭제합니다.
+// targetIDs는 확인 토큰을 발급할 때와 같은 순서(정렬)여야 합니다. details는 감사 로그에 함께 남깁니다 (예: 필터).
+// soft이면 지우지 않고 타겟과 카테고리 문서에 삭제 시각을 표시해 휴지통으로 보냅니다 (첨부 파일도 남김).
+func DeleteTargets(db *sql.DB, orgID string, targetIDs []string, confirmToken, actor string, details map[string]interface{}, soft bool) (*TargetDeletion, error) {
+	if len(targetIDs) == 0 {
+		return &TargetDeletion{Targets: []TargetFootprint{}}, nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	if err := SetChangeActor(tx, actor); err != nil {
+		return nil, err
+	}
+
+	if err := consumeTargetConfirmation(tx, confirmToken, orgID, TargetActionDelete, TargetRequestHash(TargetActionDelete, targetIDs)); err != nil {
+		return nil, err
+	}
+	footprints, err := targetFootprints(tx, orgID, targetIDs, true)
+	if err != nil {
+		return nil, err
+	}
+	deletion := &TargetDeletion{Targets: footprints}
+
+	ids := make([]string, len(footprints))
+	for i, f := range footprints {
+		ids[i] = f.TargetID
+	}
+	if soft {
+		if err := softDeleteTargets(tx, ids, actor); err != nil {
+			return nil, fmt.Errorf("failed to delete targets: %w", err)
+		}
+	} else {
+		if deletion.StoragePaths, err = queryStrings(tx,
+			"SELECT s3_path FROM file_attachments WHERE target_id::text = ANY($1)", pq.Array(ids)); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec("DELETE FROM target WHERE target_id::text = ANY($1)", pq.Array(ids)); err != nil {
+			return nil, fmt.Errorf("failed to delete targets: %w", err)
+		}
+	}
+
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	if soft {
+		details["soft"] = true
+	}
+	var observations, attachments int64
+	for _, f := range footprints {
+		observations += f.Observations
+		attachments += f.Attachments
+	}
+	details["observations"] = observations
+	details["attachments"] = attachments
+	if err := RecordTargetAudit(tx, orgID, TargetActionDelete, ids, actor, details); err != nil {
+		return nil, fmt.Errorf("failed to record target audit: %w", err)
+	}
+	return deletion, tx.Commit()
+}
@@ -0,0 +1,24 @@
+package database
+
+import "testing"
+
+func TestTargetRequestHash(t *testing.T) {
+	a := "3f2a9c1e-0b7d-4e65-9a1b-2c3d4e5f6a7b"
+	b := "8c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+
+	if TargetRequestHash(TargetActionMerge, []string{a, b}) != TargetRequestHash(TargetActionMerge, []string{"3F2A9C1E-0B7D-4E65-9A1B-2C3D4E5F6A7B", b}) {
+		t.Error("hash depends on target id case")
+	}
+	// 병합 방향, 작업, 타겟 목록이 다르면 다른 요청
+	base := TargetRequestHash(TargetActionMerge, []string{a, b})
+	for _, other := range []string{
+		TargetRequestHash(TargetActionMerge, []string{b, a}),
+		TargetRequestHash(TargetActionDelete, []string{a, b}),
+		TargetRequestHash(TargetActionMerge, []string{a}),
+		TargetRequestHash(TargetActionMerge, []string{a + b}),
+	} {
+		if other == base {
+			t.Error("different requests share a hash")
+		}
+	}
+}
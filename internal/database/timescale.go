@@ -0,0 +1,527 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimescaleDB 정책 관리
+//
+// 정책 이름이 하이퍼테이블(ts_obs, geo_trace)이면 테이블 전체의 압축/보관 정책을,
+// 카테고리 이름이면 ts_obs 중 그 카테고리 행의 보관 기간과 연속 집계를 관리합니다.
+// 압축은 TimescaleDB에서 하이퍼테이블 단위로만 설정할 수 있습니다.
+
+// 정책 대상 하이퍼테이블
+const (
+	TableTsObs    = "ts_obs"
+	TableGeoTrace = "geo_trace"
+)
+
+// categoryRetentionProc 카테고리별 보관 기간을 적용하는 TimescaleDB 사용자 정의 작업
+const categoryRetentionProc = "tmidb_category_retention"
+
+// timeseriesPolicyTableSQL 정책 저장 테이블
+const timeseriesPolicyTableSQL = `
+CREATE TABLE IF NOT EXISTS public.timeseries_policies (
+    name TEXT PRIMARY KEY,
+    compress_after TEXT NOT NULL DEFAULT '',
+    retain_for TEXT NOT NULL DEFAULT '',
+    aggregate_bucket TEXT NOT NULL DEFAULT '',
+    aggregate_fields JSONB NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+`
+
+// categoryRetentionSQL 카테고리 보관 기간이 지난 ts_obs 행을 삭제하는 프로시저
+const categoryRetentionSQL = `
+CREATE OR REPLACE PROCEDURE ` + categoryRetentionProc + `(job_id INT, config JSONB)
+LANGUAGE plpgsql AS $$
+BEGIN
+    DELETE FROM public.ts_obs
+    WHERE category_name = config->>'category'
+      AND ts < now() - (config->>'retain_for')::interval;
+END
+$$;
+`
+
+var (
+	intervalPattern   = regexp.MustCompile(`^(\d+)\s*([a-z]+)$`)
+	categoryPattern   = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// TimeseriesPolicy 하이퍼테이블 또는 카테고리의 시계열 정책
+type TimeseriesPolicy struct {
+	Name            string    `json:"name"`                       // ts_obs, geo_trace 또는 카테고리 이름
+	CompressAfter   string    `json:"compress_after,omitempty"`   // 이 기간이 지난 청크 압축 (하이퍼테이블만)
+	RetainFor       string    `json:"retain_for,omitempty"`       // 이 기간이 지난 데이터 삭제
+	AggregateBucket string    `json:"aggregate_bucket,omitempty"` // 연속 집계 버킷 크기 (카테고리만)
+	AggregateFields []string  `json:"aggregate_fields,omitempty"` // avg/min/max를 집계할 payload 숫자 필드
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// IsTable 정책 대상이 하이퍼테이블 전체인지 확인
+func (p *TimeseriesPolicy) IsTable() bool {
+	return p.Name == TableTsObs || p.Name == TableGeoTrace
+}
+
+// AggregateView 카테고리 연속 집계 뷰 이름
+func (p *TimeseriesPolicy) AggregateView() string {
+	if p.IsTable() || p.AggregateBucket == "" {
+		return ""
+	}
+	return "ts_obs_agg_" + strings.ToLower(strings.ReplaceAll(p.Name, "-", "_"))
+}
+
+// Validate 정책 값을 검증하고 기간을 PostgreSQL interval 형식으로 정규화
+func (p *TimeseriesPolicy) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("policy name is required")
+	}
+	if !p.IsTable() && !categoryPattern.MatchString(p.Name) {
+		return fmt.Errorf("invalid category name: %s", p.Name)
+	}
+
+	var err error
+	if p.CompressAfter, err = NormalizeInterval(p.CompressAfter); err != nil {
+		return fmt.Errorf("compress_after: %v", err)
+	}
+	if p.RetainFor, err = NormalizeInterval(p.RetainFor); err != nil {
+		return fmt.Errorf("retain_for: %v", err)
+	}
+	if p.AggregateBucket, err = NormalizeInterval(p.AggregateBucket); err != nil {
+		return fmt.Errorf("aggregate_bucket: %v", err)
+	}
+
+	if p.IsTable() {
+		if p.AggregateBucket != "" || len(p.AggregateFields) > 0 {
+			return fmt.Errorf("continuous aggregates are configured per category, not on %s", p.Name)
+		}
+		return nil
+	}
+
+	if p.CompressAfter != "" {
+		return fmt.Errorf("compression applies to the whole %s hypertable; set it on the %s policy", TableTsObs, TableTsObs)
+	}
+	if len(p.AggregateFields) > 0 && p.AggregateBucket == "" {
+		return fmt.Errorf("aggregate_fields requires aggregate_bucket")
+	}
+	for _, field := range p.AggregateFields {
+		if !identifierPattern.MatchString(field) {
+			return fmt.Errorf("invalid aggregate field: %s", field)
+		}
+	}
+	return nil
+}
+
+// NormalizeInterval "7d", "12h", "2w", "30m" 또는 "7 days" 형식을 PostgreSQL interval 문자열로 변환
+func NormalizeInterval(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return "", nil
+	}
+
+	match := intervalPattern.FindStringSubmatch(value)
+	if match == nil {
+		return "", fmt.Errorf("invalid interval %q (e.g. 30m, 12h, 7d, 2w)", value)
+	}
+	n, err := strconv.Atoi(match[1])
+	if err != nil || n <= 0 {
+		return "", fmt.Errorf("invalid interval %q: must be positive", value)
+	}
+
+	var unit string
+	switch match[2] {
+	case "m", "min", "mins", "minute", "minutes":
+		unit = "minute"
+	case "h", "hour", "hours":
+		unit = "hour"
+	case "d", "day", "days":
+		unit = "day"
+	case "w", "week", "weeks":
+		unit = "week"
+	default:
+		return "", fmt.Errorf("invalid interval unit %q", match[2])
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", n, unit), nil
+}
+
+// aggregateViewSQL 카테고리 연속 집계 생성 SQL
+func aggregateViewSQL(p *TimeseriesPolicy) string {
+	columns := []string{
+		fmt.Sprintf("time_bucket(INTERVAL %s, ts) AS bucket", quoteLiteral(p.AggregateBucket)),
+		"target_id",
+		"count(*) AS samples",
+	}
+	for _, field := range p.AggregateFields {
+		value := fmt.Sprintf("(payload->>%s)::double precision", quoteLiteral(field))
+		columns = append(columns,
+			fmt.Sprintf("avg(%s) AS %s_avg", value, field),
+			fmt.Sprintf("min(%s) AS %s_min", value, field),
+			fmt.Sprintf("max(%s) AS %s_max", value, field),
+		)
+	}
+
+	return fmt.Sprintf(`CREATE MATERIALIZED VIEW IF NOT EXISTS public.%s
+WITH (timescaledb.continuous) AS
+SELECT %s
+FROM public.ts_obs
+WHERE category_name = %s
+GROUP BY bucket, target_id
+WITH NO DATA`, p.AggregateView(), strings.Join(columns, ",\n       "), quoteLiteral(p.Name))
+}
+
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// segmentBy 하이퍼테이블 압축 시 세그먼트 기준 컬럼
+func segmentBy(table string) string {
+	if table == TableTsObs {
+		return "target_id, category_name"
+	}
+	return "target_id"
+}
+
+// ensureTimescale TimescaleDB 확장과 정책 테이블 확인
+func ensureTimescale(db *sql.DB) error {
+	var installed bool
+	if err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM pg_extension WHERE extname = 'timescaledb')").Scan(&installed); err != nil {
+		return err
+	}
+	if !installed {
+		return fmt.Errorf("TimescaleDB extension is not installed")
+	}
+	_, err := db.Exec(timeseriesPolicyTableSQL)
+	return err
+}
+
+// ListTimeseriesPolicies 저장된 정책 목록
+func ListTimeseriesPolicies(db *sql.DB) ([]TimeseriesPolicy, error) {
+	if _, err := db.Exec(timeseriesPolicyTableSQL); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT name, compress_after, retain_for, aggregate_bucket, aggregate_fields, updated_at
+		FROM timeseries_policies ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []TimeseriesPolicy
+	for rows.Next() {
+		var p TimeseriesPolicy
+		var fields []byte
+		if err := rows.Scan(&p.Name, &p.CompressAfter, &p.RetainFor, &p.AggregateBucket, &fields, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(fields, &p.AggregateFields); err != nil {
+			return nil, fmt.Errorf("policy %s: invalid aggregate_fields: %v", p.Name, err)
+		}
+		policies = append(policies, p)
+	}
+	return policies, rows.Err()
+}
+
+// GetTimeseriesPolicy 저장된 정책 조회 (없으면 sql.ErrNoRows)
+func GetTimeseriesPolicy(db *sql.DB, name string) (*TimeseriesPolicy, error) {
+	policies, err := ListTimeseriesPolicies(db)
+	if err != nil {
+		return nil, err
+	}
+	for i := range policies {
+		if policies[i].Name == name {
+			return &policies[i], nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// SetTimeseriesPolicy 정책을 저장하고 TimescaleDB에 적용
+// 버킷이나 집계 필드가 바뀌면 연속 집계를 다시 만듭니다 (기존 집계 데이터는 원본에서 다시 계산).
+func SetTimeseriesPolicy(db *sql.DB, p *TimeseriesPolicy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if err := ensureTimescale(db); err != nil {
+		return err
+	}
+
+	previous, err := GetTimeseriesPolicy(db, p.Name)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	recreate := previous != nil && (previous.AggregateBucket != p.AggregateBucket ||
+		strings.Join(previous.AggregateFields, ",") != strings.Join(p.AggregateFields, ","))
+	if recreate && previous.AggregateView() != "" {
+		if _, err := db.Exec("DROP MATERIALIZED VIEW IF EXISTS public." + previous.AggregateView() + " CASCADE"); err != nil {
+			return fmt.Errorf("failed to drop continuous aggregate: %v", err)
+		}
+	}
+
+	if err := applyTimeseriesPolicy(db, p); err != nil {
+		return err
+	}
+
+	fields, _ := json.Marshal(p.AggregateFields)
+	if p.AggregateFields == nil {
+		fields = []byte("[]")
+	}
+	p.UpdatedAt = time.Now()
+	_, err = db.Exec(`INSERT INTO timeseries_policies (name, compress_after, retain_for, aggregate_bucket, aggregate_fields, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (name) DO UPDATE SET
+			compress_after = EXCLUDED.compress_after,
+			retain_for = EXCLUDED.retain_for,
+			aggregate_bucket = EXCLUDED.aggregate_bucket,
+			aggregate_fields = EXCLUDED.aggregate_fields,
+			updated_at = EXCLUDED.updated_at`,
+		p.Name, p.CompressAfter, p.RetainFor, p.AggregateBucket, string(fields), p.UpdatedAt)
+	return err
+}
+
+// RemoveTimeseriesPolicy 정책과 관련 TimescaleDB 작업, 연속 집계를 제거
+func RemoveTimeseriesPolicy(db *sql.DB, name string) error {
+	if err := ensureTimescale(db); err != nil {
+		return err
+	}
+	p, err := GetTimeseriesPolicy(db, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("no policy for %s", name)
+		}
+		return err
+	}
+
+	if p.IsTable() {
+		target := "public." + p.Name
+		if _, err := db.Exec("SELECT remove_compression_policy($1, if_exists => true)", target); err != nil {
+			return fmt.Errorf("failed to remove compression policy: %v", err)
+		}
+		if _, err := db.Exec("SELECT remove_retention_policy($1, if_exists => true)", target); err != nil {
+			return fmt.Errorf("failed to remove retention policy: %v", err)
+		}
+	} else {
+		if err := removeCategoryRetention(db, p.Name); err != nil {
+			return err
+		}
+		if view := p.AggregateView(); view != "" {
+			if _, err := db.Exec("DROP MATERIALIZED VIEW IF EXISTS public." + view + " CASCADE"); err != nil {
+				return fmt.Errorf("failed to drop continuous aggregate: %v", err)
+			}
+		}
+	}
+
+	_, err = db.Exec("DELETE FROM timeseries_policies WHERE name = $1", name)
+	return err
+}
+
+// ApplyTimeseriesPolicies 저장된 모든 정책을 다시 적용 (스키마 초기화 시 호출)
+func ApplyTimeseriesPolicies(db *sql.DB) error {
+	if err := ensureTimescale(db); err != nil {
+		return err
+	}
+	policies, err := ListTimeseriesPolicies(db)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for i := range policies {
+		if err := applyTimeseriesPolicy(db, &policies[i]); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", policies[i].Name, err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to apply policies: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// applyTimeseriesPolicy 정책을 TimescaleDB 작업으로 반영 (여러 번 호출해도 같은 결과)
+func applyTimeseriesPolicy(db *sql.DB, p *TimeseriesPolicy) error {
+	if p.IsTable() {
+		return applyTablePolicy(db, p)
+	}
+	return applyCategoryPolicy(db, p)
+}
+
+func applyTablePolicy(db *sql.DB, p *TimeseriesPolicy) error {
+	target := "public." + p.Name
+
+	if _, err := db.Exec("SELECT create_hypertable($1, 'ts', if_not_exists => TRUE, migrate_data => TRUE)", target); err != nil {
+		return fmt.Errorf("failed to create hypertable: %v", err)
+	}
+
+	if _, err := db.Exec("SELECT remove_compression_policy($1, if_exists => true)", target); err != nil {
+		return fmt.Errorf("failed to remove compression policy: %v", err)
+	}
+	if p.CompressAfter != "" {
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s SET (timescaledb.compress, timescaledb.compress_segmentby = %s)",
+			target, quoteLiteral(segmentBy(p.Name)))); err != nil {
+			return fmt.Errorf("failed to enable compression: %v", err)
+		}
+		if _, err := db.Exec("SELECT add_compression_policy($1, $2::interval)", target, p.CompressAfter); err != nil {
+			return fmt.Errorf("failed to add compression policy: %v", err)
+		}
+	}
+
+	if _, err := db.Exec("SELECT remove_retention_policy($1, if_exists => true)", target); err != nil {
+		return fmt.Errorf("failed to remove retention policy: %v", err)
+	}
+	if p.RetainFor != "" {
+		if _, err := db.Exec("SELECT add_retention_policy($1, $2::interval)", target, p.RetainFor); err != nil {
+			return fmt.Errorf("failed to add retention policy: %v", err)
+		}
+	}
+	return nil
+}
+
+func applyCategoryPolicy(db *sql.DB, p *TimeseriesPolicy) error {
+	if err := removeCategoryRetention(db, p.Name); err != nil {
+		return err
+	}
+	if p.RetainFor != "" {
+		if _, err := db.Exec(categoryRetentionSQL); err != nil {
+			return fmt.Errorf("failed to create retention procedure: %v", err)
+		}
+		config, _ := json.Marshal(map[string]string{"category": p.Name, "retain_for": p.RetainFor})
+		if _, err := db.Exec("SELECT add_job($1, INTERVAL '1 hour', config => $2::jsonb)",
+			categoryRetentionProc, string(config)); err != nil {
+			return fmt.Errorf("failed to add retention job: %v", err)
+		}
+	}
+
+	view := p.AggregateView()
+	if view == "" {
+		return nil
+	}
+	if _, err := db.Exec(aggregateViewSQL(p)); err != nil {
+		return fmt.Errorf("failed to create continuous aggregate: %v", err)
+	}
+
+	// 원본 보관 기간보다 오래된 구간은 다시 계산하지 않아 삭제된 원본의 집계를 유지
+	var startOffset interface{}
+	if p.RetainFor != "" {
+		startOffset = p.RetainFor
+	}
+	if _, err := db.Exec("SELECT remove_continuous_aggregate_policy($1, if_exists => true)", "public."+view); err != nil {
+		return fmt.Errorf("failed to remove refresh policy: %v", err)
+	}
+	if _, err := db.Exec(`SELECT add_continuous_aggregate_policy($1,
+		start_offset => $2::interval, end_offset => $3::interval, schedule_interval => $3::interval)`,
+		"public."+view, startOffset, p.AggregateBucket); err != nil {
+		return fmt.Errorf("failed to add refresh policy: %v", err)
+	}
+	return nil
+}
+
+func removeCategoryRetention(db *sql.DB, category string) error {
+	_, err := db.Exec(`SELECT delete_job(job_id) FROM timescaledb_information.jobs
+		WHERE proc_name = $1 AND config->>'category' = $2`, categoryRetentionProc, category)
+	if err != nil {
+		return fmt.Errorf("failed to remove retention job: %v", err)
+	}
+	return nil
+}
+
+// TimescaleJob 정책에 연결된 TimescaleDB 백그라운드 작업
+type TimescaleJob struct {
+	ID            int        `json:"id"`
+	Proc          string     `json:"proc"`
+	Schedule      string     `json:"schedule"`
+	LastRunStatus string     `json:"last_run_status,omitempty"`
+	NextStart     *time.Time `json:"next_start,omitempty"`
+}
+
+// TimeseriesPolicyStatus 정책과 현재 TimescaleDB 상태
+type TimeseriesPolicyStatus struct {
+	TimeseriesPolicy
+	Jobs             []TimescaleJob `json:"jobs"`
+	TotalChunks      int64          `json:"total_chunks,omitempty"`
+	CompressedChunks int64          `json:"compressed_chunks,omitempty"`
+	BytesBefore      int64          `json:"bytes_before_compression,omitempty"`
+	BytesAfter       int64          `json:"bytes_after_compression,omitempty"`
+}
+
+// TimeseriesPolicyStatuses 저장된 정책별 작업과 압축 통계
+func TimeseriesPolicyStatuses(db *sql.DB) ([]TimeseriesPolicyStatus, error) {
+	if err := ensureTimescale(db); err != nil {
+		return nil, err
+	}
+	policies, err := ListTimeseriesPolicies(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]TimeseriesPolicyStatus, 0, len(policies))
+	for _, p := range policies {
+		status := TimeseriesPolicyStatus{TimeseriesPolicy: p, Jobs: []TimescaleJob{}}
+		if status.Jobs, err = policyJobs(db, &p); err != nil {
+			return nil, err
+		}
+		if p.IsTable() {
+			var total, compressed, before, after sql.NullInt64
+			err := db.QueryRow(`SELECT total_chunks, number_compressed_chunks,
+					before_compression_total_bytes, after_compression_total_bytes
+				FROM hypertable_compression_stats($1)`, "public."+p.Name).Scan(&total, &compressed, &before, &after)
+			if err != nil && err != sql.ErrNoRows {
+				return nil, fmt.Errorf("%s: compression stats: %v", p.Name, err)
+			}
+			status.TotalChunks, status.CompressedChunks = total.Int64, compressed.Int64
+			status.BytesBefore, status.BytesAfter = before.Int64, after.Int64
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func policyJobs(db *sql.DB, p *TimeseriesPolicy) ([]TimescaleJob, error) {
+	var rows *sql.Rows
+	var err error
+	const columns = `SELECT j.job_id, j.proc_name, j.schedule_interval::text,
+			COALESCE(s.last_run_status, ''), s.next_start
+		FROM timescaledb_information.jobs j
+		LEFT JOIN timescaledb_information.job_stats s ON s.job_id = j.job_id`
+
+	if p.IsTable() {
+		rows, err = db.Query(columns+`
+		WHERE j.hypertable_schema = 'public' AND j.hypertable_name = $1
+		  AND j.proc_name IN ('policy_compression', 'policy_retention')
+		ORDER BY j.job_id`, p.Name)
+	} else {
+		rows, err = db.Query(columns+`
+		LEFT JOIN timescaledb_information.continuous_aggregates ca
+		  ON ca.materialization_hypertable_name = j.hypertable_name
+		WHERE (j.proc_name = $1 AND j.config->>'category' = $2)
+		   OR (j.proc_name = 'policy_refresh_continuous_aggregate' AND ca.view_name = $3)
+		ORDER BY j.job_id`, categoryRetentionProc, p.Name, p.AggregateView())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: jobs: %v", p.Name, err)
+	}
+	defer rows.Close()
+
+	jobs := []TimescaleJob{}
+	for rows.Next() {
+		var job TimescaleJob
+		var next sql.NullTime
+		if err := rows.Scan(&job.ID, &job.Proc, &job.Schedule, &job.LastRunStatus, &next); err != nil {
+			return nil, err
+		}
+		if next.Valid {
+			job.NextStart = &next.Time
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
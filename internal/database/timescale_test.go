@@ -0,0 +1,58 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeInterval(t *testing.T) {
+	cases := map[string]string{
+		"":        "",
+		"7d":      "7 days",
+		"1d":      "1 day",
+		"12h":     "12 hours",
+		"30m":     "30 minutes",
+		"2w":      "2 weeks",
+		"90 days": "90 days",
+	}
+	for input, want := range cases {
+		got, err := NormalizeInterval(input)
+		if err != nil || got != want {
+			t.Errorf("%q: expected %q, got %q (%v)", input, want, got, err)
+		}
+	}
+	for _, input := range []string{"7", "0d", "1y", "1 day; DROP TABLE ts_obs"} {
+		if _, err := NormalizeInterval(input); err == nil {
+			t.Errorf("%q: expected error", input)
+		}
+	}
+}
+
+func TestTimeseriesPolicyValidate(t *testing.T) {
+	p := &TimeseriesPolicy{Name: "air-quality", RetainFor: "30d", AggregateBucket: "1h", AggregateFields: []string{"pm10"}}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("expected valid category policy, got %v", err)
+	}
+	if p.AggregateView() != "ts_obs_agg_air_quality" {
+		t.Errorf("unexpected view name %s", p.AggregateView())
+	}
+	sql := aggregateViewSQL(p)
+	for _, want := range []string{"time_bucket(INTERVAL '1 hour', ts)", "WHERE category_name = 'air-quality'", "AS pm10_avg"} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("aggregate SQL missing %q:\n%s", want, sql)
+		}
+	}
+
+	invalid := []*TimeseriesPolicy{
+		{Name: "air-quality", CompressAfter: "7d"},          // 압축은 하이퍼테이블 단위
+		{Name: TableTsObs, AggregateBucket: "1h"},           // 집계는 카테고리 단위
+		{Name: "metrics", AggregateFields: []string{"cpu"}}, // 버킷 없음
+		{Name: "metrics", AggregateBucket: "1h", AggregateFields: []string{"cpu'); --"}},
+		{Name: "bad name"},
+	}
+	for _, p := range invalid {
+		if err := p.Validate(); err == nil {
+			t.Errorf("%+v: expected validation error", p)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"sync"
+	"time"
+)
+
+// 토큰 인증 오류
+var (
+	ErrTokenNotFound = errors.New("token not found")
+	ErrTokenInactive = errors.New("token has been disabled")
+	ErrTokenExpired  = errors.New("token has expired")
+)
+
+// TokenPermissions는 토큰(또는 사용자)의 permissions JSON입니다.
+// read/write에는 카테고리 이름, 모든 카테고리를 뜻하는 "*", 또는 "sensor_*" 같은 패턴이 들어갑니다.
+type TokenPermissions struct {
+	Admin bool     `json:"admin"`
+	Read  []string `json:"read"`
+	Write []string `json:"write"`
+}
+
+// Allows는 권한(read, write, admin)이 카테고리에 허용되는지 확인합니다.
+// category가 비어 있으면 어떤 카테고리에든 해당 권한이 있는지만 봅니다. 쓰기 권한은 읽기 권한을 포함합니다.
+func (p TokenPermissions) Allows(permission, category string) bool {
+	if p.Admin {
+		return true
+	}
+	switch permission {
+	case "read":
+		return matchCategory(p.Read, category) || matchCategory(p.Write, category)
+	case "write":
+		return matchCategory(p.Write, category)
+	default:
+		return false
+	}
+}
+
+// Validate는 카테고리 패턴이 올바른지 확인합니다
+func (p TokenPermissions) Validate() error {
+	for _, list := range [][]string{p.Read, p.Write} {
+		for _, pattern := range list {
+			if pattern == "" {
+				return errors.New("empty category in permissions")
+			}
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid category pattern %q", pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// JSON은 permissions 컬럼에 저장할 JSON을 반환합니다 (빈 목록은 []로 저장)
+func (p TokenPermissions) JSON() string {
+	if p.Read == nil {
+		p.Read = []string{}
+	}
+	if p.Write == nil {
+		p.Write = []string{}
+	}
+	encoded, _ := json.Marshal(p)
+	return string(encoded)
+}
+
+// matchCategory는 허용 목록에 카테고리가 있는지 확인합니다. 목록 항목은 path.Match 패턴으로 비교합니다.
+func matchCategory(allowed []string, category string) bool {
+	for _, pattern := range allowed {
+		if pattern == "*" || category == "" || pattern == category {
+			return true
+		}
+		if ok, _ := path.Match(pattern, category); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionName은 권한과 카테고리를 "write:sensors" 형식으로 나타냅니다 (403 응답용)
+func PermissionName(permission, category string) string {
+	if category == "" {
+		return permission
+	}
+	return permission + ":" + category
+}
+
+// parsePermissions는 permissions JSON을 읽습니다. admin이 true면 관리자 권한을 더합니다.
+func parsePermissions(raw []byte, admin bool) (TokenPermissions, error) {
+	var p TokenPermissions
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return p, err
+		}
+	}
+	if admin {
+		p.Admin = true
+	}
+	return p, nil
+}
+
+// TokenIdentity는 인증된 토큰의 주인과 권한입니다.
+type TokenIdentity struct {
+	TokenID     string           `json:"token_id"`
+	Kind        string           `json:"kind"` // "api" (조직 API 토큰) 또는 "user" (사용자 액세스 토큰)
+	OrgID       string           `json:"org_id"`
+	UserID      string           `json:"user_id,omitempty"`
+	Username    string           `json:"username,omitempty"`
+	Role        string           `json:"role"`
+	Permissions TokenPermissions `json:"permissions"`
+	ExpiresAt   *time.Time       `json:"expires_at,omitempty"`
+}
+
+var backfillTokenHashesOnce sync.Once
+
+// AuthenticateToken은 Bearer 토큰을 조직 API 토큰과 사용자 액세스 토큰에서 찾아 인증합니다.
+// 토큰은 SHA-256 해시로 조회하며, 비활성(사용자 비활성 포함)이거나 만료된 토큰은 오류를 반환합니다.
+func AuthenticateToken(token string) (*TokenIdentity, error) {
+	if token == "" {
+		return nil, ErrTokenNotFound
+	}
+	backfillTokenHashesOnce.Do(backfillAuthTokenHashes)
+
+	hash := hashToken(token)
+	identity, err := authenticateAPIToken(hash)
+	if err == ErrTokenNotFound {
+		identity, err = authenticateUserToken(hash)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if identity.ExpiresAt != nil && time.Now().After(*identity.ExpiresAt) {
+		return nil, ErrTokenExpired
+	}
+	return identity, nil
+}
+
+// authenticateAPIToken은 auth_tokens에서 토큰을 찾습니다
+func authenticateAPIToken(hash string) (*TokenIdentity, error) {
+	identity := &TokenIdentity{Kind: "api", Role: "api"}
+	var rawPermissions []byte
+	var isAdmin, isActive bool
+	var expiresAt sql.NullTime
+
+	err := DB.QueryRow(`
+		SELECT token_id, org_id, permissions, is_admin, is_active, expires_at
+		FROM auth_tokens
+		WHERE token_hash = $1
+	`, hash).Scan(&identity.TokenID, &identity.OrgID, &rawPermissions, &isAdmin, &isActive, &expiresAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !isActive {
+		return nil, ErrTokenInactive
+	}
+
+	if identity.Permissions, err = parsePermissions(rawPermissions, isAdmin); err != nil {
+		return nil, err
+	}
+	if isAdmin {
+		identity.Role = "admin"
+	}
+	if expiresAt.Valid {
+		identity.ExpiresAt = &expiresAt.Time
+	}
+	return identity, nil
+}
+
+// authenticateUserToken은 user_access_tokens에서 토큰을 찾고 사용자의 역할과 권한을 가져옵니다
+func authenticateUserToken(hash string) (*TokenIdentity, error) {
+	identity := &TokenIdentity{Kind: "user"}
+	var rawPermissions []byte
+	var isActive bool
+	var expiresAt sql.NullTime
+
+	err := DB.QueryRow(`
+		SELECT t.token_id, t.org_id, u.user_id, u.username, u.role, u.permissions,
+		       t.is_active AND u.is_active, t.expires_at
+		FROM user_access_tokens t
+		JOIN users u ON u.user_id = t.user_id
+		WHERE t.token_hash = $1
+	`, hash).Scan(&identity.TokenID, &identity.OrgID, &identity.UserID, &identity.Username,
+		&identity.Role, &rawPermissions, &isActive, &expiresAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !isActive {
+		return nil, ErrTokenInactive
+	}
+
+	if identity.Permissions, err = parsePermissions(rawPermissions, identity.Role == "admin"); err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		identity.ExpiresAt = &expiresAt.Time
+	}
+	return identity, nil
+}
+
+// backfillAuthTokenHashes는 token_hash 컬럼이 생기기 전에 만든 API 토큰의 해시를 채웁니다.
+// 암호화된 토큰을 복호화할 수 없으면 그 토큰은 건너뜁니다.
+func backfillAuthTokenHashes() {
+	rows, err := DB.Query("SELECT token_id, encrypted_token FROM auth_tokens WHERE token_hash IS NULL")
+	if err != nil {
+		log.Printf("⚠️ 토큰 해시 채우기 실패: %v", err)
+		return
+	}
+	hashes := make(map[string]string)
+	for rows.Next() {
+		var tokenID, encrypted string
+		if err := rows.Scan(&tokenID, &encrypted); err != nil {
+			continue
+		}
+		token, err := DecryptToken(encrypted)
+		if err != nil {
+			continue
+		}
+		hashes[tokenID] = hashToken(token)
+	}
+	rows.Close()
+
+	for tokenID, hash := range hashes {
+		if _, err := DB.Exec("UPDATE auth_tokens SET token_hash = $2 WHERE token_id = $1 AND token_hash IS NULL", tokenID, hash); err != nil {
+			log.Printf("⚠️ 토큰 해시 저장 실패 (%s): %v", tokenID, err)
+		}
+	}
+	if len(hashes) > 0 {
+		log.Printf("🔑 기존 API 토큰 %d개의 해시를 채웠습니다", len(hashes))
+	}
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenPermissionsAllows(t *testing.T) {
+	p, err := parsePermissions([]byte(`{"read": ["sensors"], "write": ["alarms"]}`), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		permission, category string
+		want                 bool
+	}{
+		{"read", "sensors", true},
+		{"read", "alarms", true}, // 쓰기 권한은 읽기를 포함
+		{"read", "devices", false},
+		{"write", "alarms", true},
+		{"write", "sensors", false},
+		{"write", "", true},
+		{"admin", "", false},
+	}
+	for _, c := range cases {
+		if got := p.Allows(c.permission, c.category); got != c.want {
+			t.Errorf("Allows(%q, %q) = %v, want %v", c.permission, c.category, got, c.want)
+		}
+	}
+
+	readOnly, _ := parsePermissions([]byte(`{"read": ["*"], "write": []}`), false)
+	if !readOnly.Allows("read", "anything") || readOnly.Allows("write", "") {
+		t.Error("wildcard read token should read every category and write none")
+	}
+
+	pattern, _ := parsePermissions([]byte(`{"read": ["sensor_*"]}`), false)
+	if !pattern.Allows("read", "sensor_temp") || pattern.Allows("read", "alarms") {
+		t.Error("pattern should match categories by prefix only")
+	}
+
+	admin, _ := parsePermissions([]byte(`{"read": [], "write": []}`), true)
+	if !admin.Allows("admin", "") || !admin.Allows("write", "sensors") {
+		t.Error("admin token should be allowed everything")
+	}
+}
+
+func TestParseTokenDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"0":   0,
+		"90m": 90 * time.Minute,
+		"24h": 24 * time.Hour,
+		"30d": 30 * 24 * time.Hour,
+	}
+	for input, want := range cases {
+		if got, err := ParseTokenDuration(input); err != nil || got != want {
+			t.Errorf("ParseTokenDuration(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "d", "-1h", "soon"} {
+		if _, err := ParseTokenDuration(input); err == nil {
+			t.Errorf("ParseTokenDuration(%q) should fail", input)
+		}
+	}
+}
@@ -0,0 +1,452 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 토큰 종류
+const (
+	TokenKindAPI  = "api"  // auth_tokens (조직 API 토큰)
+	TokenKindUser = "user" // user_access_tokens (사용자 액세스 토큰)
+)
+
+// 토큰 감사 로그 동작
+const (
+	TokenActionCreate = "create"
+	TokenActionRotate = "rotate"
+	TokenActionExpiry = "expiry" // 만료 시각 설정/연장
+	TokenActionRevoke = "revoke"
+	TokenActionExpire = "expire" // 만료되어 백그라운드 작업이 비활성화함
+	TokenActionDelete = "delete"
+)
+
+// ErrTokenExpiryInPast는 만료 시각을 과거로 설정하려 할 때 반환됩니다 (즉시 막으려면 폐기 사용)
+var ErrTokenExpiryInPast = errors.New("expires_at must be in the future; revoke the token to disable it now")
+
+// TokenRef는 관리 요청이 가리키는 토큰입니다.
+// OrgID가 비어 있으면 조직으로, UserID가 비어 있으면 사용자로 제한하지 않습니다.
+type TokenRef struct {
+	Kind    string `json:"kind"` // 비어 있으면 user
+	TokenID string `json:"token_id"`
+	OrgID   string `json:"org_id,omitempty"`
+	UserID  string `json:"user_id,omitempty"` // 사용자 토큰을 본인 것으로 제한할 때
+}
+
+// table은 토큰 종류의 테이블을 반환합니다
+func (r TokenRef) table() (string, error) {
+	switch r.Kind {
+	case "", TokenKindUser:
+		return "user_access_tokens", nil
+	case TokenKindAPI:
+		return "auth_tokens", nil
+	default:
+		return "", fmt.Errorf("unknown token kind: %s", r.Kind)
+	}
+}
+
+// kind는 기본값을 채운 토큰 종류를 반환합니다
+func (r TokenRef) kind() string {
+	if r.Kind == "" {
+		return TokenKindUser
+	}
+	return r.Kind
+}
+
+// lockedToken은 트랜잭션에서 잠근 토큰 행입니다
+type lockedToken struct {
+	table     string
+	orgID     string
+	isActive  bool
+	expiresAt sql.NullTime
+}
+
+// lockToken은 토큰 행을 FOR UPDATE로 잠그고 상태를 읽습니다
+func lockToken(tx *sql.Tx, ref TokenRef) (*lockedToken, error) {
+	table, err := ref.table()
+	if err != nil {
+		return nil, err
+	}
+	query := "SELECT org_id, is_active, expires_at FROM " + table +
+		" WHERE token_id::text = $1 AND ($2 = '' OR org_id::text = $2)"
+	args := []interface{}{ref.TokenID, ref.OrgID}
+	if table == "user_access_tokens" {
+		query += " AND ($3 = '' OR user_id::text = $3)"
+		args = append(args, ref.UserID)
+	}
+
+	t := &lockedToken{table: table}
+	err = tx.QueryRow(query+" FOR UPDATE", args...).Scan(&t.orgID, &t.isActive, &t.expiresAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// usable은 토큰이 아직 인증에 쓰일 수 있는지 확인합니다
+func (t *lockedToken) usable(now time.Time) bool {
+	return t.isActive && (!t.expiresAt.Valid || t.expiresAt.Time.After(now))
+}
+
+// TokenSummary는 관리 목록에 보여줄 토큰 정보입니다 (토큰 값은 포함하지 않음).
+type TokenSummary struct {
+	Kind        string     `json:"kind"`
+	TokenID     string     `json:"token_id"`
+	OrgID       string     `json:"org_id"`
+	UserID      string     `json:"user_id,omitempty"`
+	Description string     `json:"description"`
+	IsActive    bool       `json:"is_active"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ListTokens는 조직 API 토큰과 사용자 액세스 토큰을 함께 조회합니다. orgID가 비어 있으면 모든 조직입니다.
+func ListTokens(db DBTX, orgID string) ([]TokenSummary, error) {
+	rows, err := db.Query(`
+		SELECT 'api', token_id, org_id, '', COALESCE(description, ''), is_active, expires_at, created_at
+		FROM auth_tokens WHERE $1 = '' OR org_id::text = $1
+		UNION ALL
+		SELECT 'user', token_id, org_id, user_id::text, COALESCE(description, ''), is_active, expires_at, created_at
+		FROM user_access_tokens WHERE $1 = '' OR org_id::text = $1
+		ORDER BY 8 DESC
+	`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []TokenSummary{}
+	for rows.Next() {
+		var t TokenSummary
+		var expiresAt sql.NullTime
+		if err := rows.Scan(&t.Kind, &t.TokenID, &t.OrgID, &t.UserID, &t.Description, &t.IsActive, &expiresAt, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		if expiresAt.Valid {
+			t.ExpiresAt = &expiresAt.Time
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, rows.Err()
+}
+
+// TokenAuditEntry는 토큰 감사 로그 항목입니다.
+type TokenAuditEntry struct {
+	ID        int64                  `json:"id"`
+	OrgID     string                 `json:"org_id"`
+	TokenID   string                 `json:"token_id"`
+	TokenKind string                 `json:"token_kind"`
+	Action    string                 `json:"action"`
+	Actor     string                 `json:"actor"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// RecordTokenAudit는 토큰 감사 로그를 남깁니다. actor는 변경한 주체입니다 (예: user:<id>, cli:<name>, system).
+func RecordTokenAudit(db DBTX, orgID, tokenID, kind, action, actor string, details map[string]interface{}) error {
+	var detailsJSON interface{}
+	if len(details) > 0 {
+		encoded, err := json.Marshal(details)
+		if err != nil {
+			return err
+		}
+		detailsJSON = string(encoded)
+	}
+	_, err := db.Exec(`
+		INSERT INTO token_audit_log (org_id, token_id, token_kind, action, actor, details)
+		VALUES (NULLIF($1, '')::uuid, $2, $3, $4, $5, $6)
+	`, orgID, tokenID, kind, action, actor, detailsJSON)
+	return err
+}
+
+// GetTokenAuditLog는 감사 로그를 최신 순으로 조회합니다. orgID, tokenID가 비어 있으면 그 조건으로 거르지 않습니다.
+func GetTokenAuditLog(db DBTX, orgID, tokenID string, limit int) ([]TokenAuditEntry, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := db.Query(`
+		SELECT id, COALESCE(org_id::text, ''), token_id, token_kind, action, actor, details, created_at
+		FROM token_audit_log
+		WHERE ($1 = '' OR org_id::text = $1) AND ($2 = '' OR token_id::text = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`, orgID, tokenID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []TokenAuditEntry{}
+	for rows.Next() {
+		var e TokenAuditEntry
+		var details []byte
+		if err := rows.Scan(&e.ID, &e.OrgID, &e.TokenID, &e.TokenKind, &e.Action, &e.Actor, &details, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		if len(details) > 0 {
+			json.Unmarshal(details, &e.Details)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// RotatedToken은 교체로 발급된 새 토큰입니다. Token은 이 응답에서만 볼 수 있습니다.
+type RotatedToken struct {
+	TokenID      string     `json:"token_id"`
+	Token        string     `json:"token"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	OldTokenID   string     `json:"old_token_id"`
+	OldExpiresAt *time.Time `json:"old_expires_at,omitempty"` // 이전 토큰을 더 쓸 수 없게 되는 시각 (유예 없으면 비움)
+}
+
+// RotateToken은 같은 설명, 권한, 만료 시각으로 새 토큰을 발급합니다.
+// 이전 토큰은 grace 동안 계속 쓸 수 있고 (원래 만료가 더 빠르면 그대로), grace가 0이면 바로 비활성화됩니다.
+func RotateToken(db *sql.DB, ref TokenRef, grace time.Duration, actor string) (*RotatedToken, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	old, err := lockToken(tx, ref)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !old.usable(now) {
+		return nil, ErrTokenInactive
+	}
+
+	token, err := newTokenString()
+	if err != nil {
+		return nil, err
+	}
+	rotated := &RotatedToken{Token: token, OldTokenID: ref.TokenID}
+	if old.expiresAt.Valid {
+		rotated.ExpiresAt = &old.expiresAt.Time
+	}
+
+	if old.table == "auth_tokens" {
+		encrypted, err := EncryptToken(token)
+		if err != nil {
+			return nil, fmt.Errorf("could not encrypt token: %w", err)
+		}
+		err = tx.QueryRow(`
+			INSERT INTO auth_tokens (org_id, encrypted_token, token_hash, description, permissions, is_admin, is_active, expires_at)
+			SELECT org_id, $2, $3, description, permissions, is_admin, TRUE, expires_at FROM auth_tokens WHERE token_id = $1
+			RETURNING token_id
+		`, ref.TokenID, encrypted, hashToken(token)).Scan(&rotated.TokenID)
+		if err != nil {
+			return nil, fmt.Errorf("could not save token: %w", err)
+		}
+	} else {
+		err = tx.QueryRow(`
+			INSERT INTO user_access_tokens (user_id, org_id, token_hash, description, is_active, expires_at)
+			SELECT user_id, org_id, $2, description, TRUE, expires_at FROM user_access_tokens WHERE token_id = $1
+			RETURNING token_id
+		`, ref.TokenID, hashToken(token)).Scan(&rotated.TokenID)
+		if err != nil {
+			return nil, fmt.Errorf("could not save token: %w", err)
+		}
+	}
+
+	// 이전 토큰은 유예 기간 뒤에 만료 (유예가 없으면 즉시 비활성화)
+	if grace <= 0 {
+		_, err = tx.Exec("UPDATE "+old.table+" SET is_active = FALSE WHERE token_id = $1", ref.TokenID)
+	} else {
+		until := now.Add(grace)
+		if old.expiresAt.Valid && old.expiresAt.Time.Before(until) {
+			until = old.expiresAt.Time
+		}
+		rotated.OldExpiresAt = &until
+		_, err = tx.Exec("UPDATE "+old.table+" SET expires_at = $2 WHERE token_id = $1", ref.TokenID, until)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	details := map[string]interface{}{"new_token_id": rotated.TokenID, "grace_period": grace.String()}
+	if rotated.OldExpiresAt != nil {
+		details["old_expires_at"] = rotated.OldExpiresAt
+	}
+	if err := RecordTokenAudit(tx, old.orgID, ref.TokenID, ref.kind(), TokenActionRotate, actor, details); err != nil {
+		return nil, err
+	}
+	if err := RecordTokenAudit(tx, old.orgID, rotated.TokenID, ref.kind(), TokenActionCreate, actor,
+		map[string]interface{}{"rotated_from": ref.TokenID}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return rotated, nil
+}
+
+// SetTokenExpiry는 토큰의 만료 시각을 설정합니다. extendBy가 있으면 현재 만료 시각(없거나 지났으면 지금)에서 연장하고,
+// 둘 다 없으면 만료를 없앱니다. 폐기되었거나 이미 만료된 토큰은 되살리지 않습니다.
+func SetTokenExpiry(db *sql.DB, ref TokenRef, expiresAt *time.Time, extendBy time.Duration, actor string) (*time.Time, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	t, err := lockToken(tx, ref)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !t.usable(now) {
+		return nil, ErrTokenInactive
+	}
+
+	if extendBy > 0 {
+		base := now
+		if t.expiresAt.Valid && t.expiresAt.Time.After(now) {
+			base = t.expiresAt.Time
+		}
+		extended := base.Add(extendBy)
+		expiresAt = &extended
+	}
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, ErrTokenExpiryInPast
+	}
+
+	if _, err := tx.Exec("UPDATE "+t.table+" SET expires_at = $2 WHERE token_id = $1", ref.TokenID, expiresAt); err != nil {
+		return nil, err
+	}
+	details := map[string]interface{}{"expires_at": expiresAt}
+	if t.expiresAt.Valid {
+		details["previous_expires_at"] = t.expiresAt.Time
+	}
+	if err := RecordTokenAudit(tx, t.orgID, ref.TokenID, ref.kind(), TokenActionExpiry, actor, details); err != nil {
+		return nil, err
+	}
+	return expiresAt, tx.Commit()
+}
+
+// RevokeToken은 토큰을 즉시 비활성화합니다. 이미 비활성화된 토큰이면 아무것도 하지 않습니다.
+func RevokeToken(db *sql.DB, ref TokenRef, reason, actor string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	t, err := lockToken(tx, ref)
+	if err != nil {
+		return err
+	}
+	if !t.isActive {
+		return nil
+	}
+
+	if _, err := tx.Exec("UPDATE "+t.table+" SET is_active = FALSE WHERE token_id = $1", ref.TokenID); err != nil {
+		return err
+	}
+	var details map[string]interface{}
+	if reason != "" {
+		details = map[string]interface{}{"reason": reason}
+	}
+	if err := RecordTokenAudit(tx, t.orgID, ref.TokenID, ref.kind(), TokenActionRevoke, actor, details); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ExpireTokens는 만료 시각이 지난 활성 토큰을 비활성화하고 감사 로그를 남깁니다.
+func ExpireTokens(db *sql.DB) (int, error) {
+	expired := 0
+	for kind, table := range map[string]string{TokenKindAPI: "auth_tokens", TokenKindUser: "user_access_tokens"} {
+		rows, err := db.Query(`
+			UPDATE ` + table + ` SET is_active = FALSE
+			WHERE is_active AND expires_at IS NOT NULL AND expires_at <= now()
+			RETURNING token_id, org_id, expires_at
+		`)
+		if err != nil {
+			return expired, err
+		}
+		type expiredToken struct {
+			tokenID, orgID string
+			expiresAt      time.Time
+		}
+		var tokens []expiredToken
+		for rows.Next() {
+			var t expiredToken
+			if err := rows.Scan(&t.tokenID, &t.orgID, &t.expiresAt); err != nil {
+				rows.Close()
+				return expired, err
+			}
+			tokens = append(tokens, t)
+		}
+		rows.Close()
+
+		for _, t := range tokens {
+			if err := RecordTokenAudit(db, t.orgID, t.tokenID, kind, TokenActionExpire, "system",
+				map[string]interface{}{"expires_at": t.expiresAt}); err != nil {
+				log.Printf("⚠️ 토큰 만료 감사 로그 저장 실패 (%s): %v", t.tokenID, err)
+			}
+		}
+		expired += len(tokens)
+	}
+	return expired, nil
+}
+
+// StartTokenExpiryJob은 interval마다 만료된 토큰을 비활성화합니다. ctx가 끝나면 멈춥니다.
+func StartTokenExpiryJob(ctx context.Context, db *sql.DB, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if n, err := ExpireTokens(db); err != nil {
+				log.Printf("⚠️ 만료 토큰 정리 실패: %v", err)
+			} else if n > 0 {
+				log.Printf("⌛ 만료된 토큰 %d개를 비활성화했습니다", n)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// ParseTokenDuration은 유예 기간/연장 기간을 읽습니다. Go 형식(24h, 90m)과 일 단위(30d)를 지원합니다.
+func ParseTokenDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration: %s", s)
+	}
+	return d, nil
+}
+
+// newTokenString은 새 토큰 문자열을 만듭니다 (32 bytes -> 64 hex chars)
+func newTokenString() (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", fmt.Errorf("could not generate token: %w", err)
+	}
+	return hex.EncodeToString(tokenBytes), nil
+}
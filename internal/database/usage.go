@@ -0,0 +1,159 @@
+package database
+
+import (
+	"time"
+)
+
+// DailyUsage는 조직의 하루(UTC) 사용량입니다
+type DailyUsage struct {
+	Date            string `json:"date"`
+	APIRequests     int64  `json:"api_requests"`
+	IngestedRecords int64  `json:"ingested_records"`
+	IngestedBytes   int64  `json:"ingested_bytes"`
+}
+
+// StorageUsage는 조직이 지금 저장하고 있는 데이터의 양입니다.
+// 바이트 수는 압축 전 행 크기 기준이므로 실제 디스크 사용량과는 다를 수 있습니다.
+type StorageUsage struct {
+	Targets           int64     `json:"targets"`
+	CategoryDocuments int64     `json:"category_documents"`
+	CategoryDataBytes int64     `json:"category_data_bytes"`
+	Observations      int64     `json:"observations"`
+	ObservationBytes  int64     `json:"observation_bytes"`
+	Attachments       int64     `json:"attachments"`
+	AttachmentBytes   int64     `json:"attachment_bytes"`
+	TotalBytes        int64     `json:"total_bytes"`
+	MeasuredAt        time.Time `json:"measured_at"`
+}
+
+// OrgUsage는 기간 동안의 조직 사용량입니다. Name이 비어 있으면 이미 삭제된 조직입니다.
+type OrgUsage struct {
+	OrgID             string        `json:"org_id"`
+	Name              string        `json:"name"`
+	From              time.Time     `json:"from"`
+	To                time.Time     `json:"to"`
+	APIRequests       int64         `json:"api_requests"`
+	IngestedRecords   int64         `json:"ingested_records"`
+	IngestedBytes     int64         `json:"ingested_bytes"`
+	IngestRate        float64       `json:"ingest_rate_per_second"` // 기간 평균
+	PeakHourlyRecords int64         `json:"peak_hourly_records"`
+	Daily             []DailyUsage  `json:"daily"`
+	Storage           *StorageUsage `json:"storage,omitempty"`
+}
+
+// AddOrgUsage는 조직의 한 시간 사용량 행에 값을 더합니다
+func AddOrgUsage(db DBTX, orgID string, hour time.Time, requests, records, bytes int64) error {
+	_, err := db.Exec(`
+		INSERT INTO org_usage (org_id, hour, api_requests, ingested_records, ingested_bytes)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (org_id, hour) DO UPDATE SET
+			api_requests = org_usage.api_requests + EXCLUDED.api_requests,
+			ingested_records = org_usage.ingested_records + EXCLUDED.ingested_records,
+			ingested_bytes = org_usage.ingested_bytes + EXCLUDED.ingested_bytes
+	`, orgID, hour.UTC().Truncate(time.Hour), requests, records, bytes)
+	return err
+}
+
+// GetOrgUsage는 [from, to) 기간의 조직별 사용량을 일별로 집계합니다.
+// 사용량은 시간 단위로 기록되므로 from은 정시로 내림합니다.
+// orgID는 조직 ID나 이름이고, 비어 있으면 모든 조직과 기간 안에 사용량이 있는 삭제된 조직을 포함합니다.
+func GetOrgUsage(db DBTX, orgID string, from, to time.Time) ([]OrgUsage, error) {
+	from = from.UTC().Truncate(time.Hour)
+	to = to.UTC()
+
+	var orgs []Organization
+	if orgID == "" {
+		var err error
+		if orgs, err = ListOrganizations(db); err != nil {
+			return nil, err
+		}
+	} else if org, err := GetOrganization(db, orgID); err == nil {
+		orgs = append(orgs, *org)
+		orgID = org.OrgID
+	} else if err != ErrOrganizationNotFound {
+		return nil, err
+	}
+
+	report := make([]OrgUsage, 0, len(orgs))
+	index := make(map[string]int)
+	newUsage := func(id, name string) *OrgUsage {
+		index[id] = len(report)
+		report = append(report, OrgUsage{OrgID: id, Name: name, From: from, To: to, Daily: []DailyUsage{}})
+		return &report[len(report)-1]
+	}
+	for _, o := range orgs {
+		newUsage(o.OrgID, o.Name)
+	}
+
+	rows, err := db.Query(`
+		SELECT org_id, to_char(hour AT TIME ZONE 'UTC', 'YYYY-MM-DD'),
+		       SUM(api_requests), SUM(ingested_records), SUM(ingested_bytes), MAX(ingested_records)
+		FROM org_usage
+		WHERE ($1 = '' OR org_id = $1) AND hour >= $2 AND hour < $3
+		GROUP BY 1, 2
+		ORDER BY 1, 2
+	`, orgID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var day DailyUsage
+		var peak int64
+		if err := rows.Scan(&id, &day.Date, &day.APIRequests, &day.IngestedRecords, &day.IngestedBytes, &peak); err != nil {
+			return nil, err
+		}
+		var u *OrgUsage
+		if i, ok := index[id]; ok {
+			u = &report[i]
+		} else {
+			u = newUsage(id, "")
+		}
+		u.APIRequests += day.APIRequests
+		u.IngestedRecords += day.IngestedRecords
+		u.IngestedBytes += day.IngestedBytes
+		u.PeakHourlyRecords = max(u.PeakHourlyRecords, peak)
+		u.Daily = append(u.Daily, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if seconds := to.Sub(from).Seconds(); seconds > 0 {
+		for i := range report {
+			report[i].IngestRate = float64(report[i].IngestedRecords) / seconds
+		}
+	}
+	return report, nil
+}
+
+// GetStorageUsage는 조직의 데이터 양을 계산합니다.
+// 조직의 관측값을 모두 읽으므로 데이터가 많으면 오래 걸릴 수 있습니다.
+func GetStorageUsage(db DBTX, orgID string) (*StorageUsage, error) {
+	u := &StorageUsage{MeasuredAt: time.Now()}
+	err := db.QueryRow(`
+		WITH docs AS (
+			SELECT COUNT(DISTINCT target_id) AS targets, COUNT(*) AS n,
+			       COALESCE(SUM(pg_column_size(category_data)), 0) AS bytes
+			FROM target_categories WHERE org_id = $1::uuid
+		), obs AS (
+			SELECT COUNT(*) AS n, COALESCE(SUM(pg_column_size(o.*)), 0) AS bytes
+			FROM ts_obs o
+			JOIN target_categories tc ON tc.target_id = o.target_id AND tc.category_name = o.category_name
+			WHERE tc.org_id = $1::uuid
+		), files AS (
+			SELECT COUNT(*) AS n, COALESCE(SUM(size_bytes), 0) AS bytes
+			FROM file_attachments WHERE org_id = $1::text
+		)
+		SELECT docs.targets, docs.n, docs.bytes, obs.n, obs.bytes, files.n, files.bytes
+		FROM docs, obs, files
+	`, orgID).Scan(&u.Targets, &u.CategoryDocuments, &u.CategoryDataBytes,
+		&u.Observations, &u.ObservationBytes, &u.Attachments, &u.AttachmentBytes)
+	if err != nil {
+		return nil, err
+	}
+	u.TotalBytes = u.CategoryDataBytes + u.ObservationBytes + u.AttachmentBytes
+	return u, nil
+}
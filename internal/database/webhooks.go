@@ -0,0 +1,317 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/tmidb/tmidb-core/internal/webhook"
+)
+
+// Webhook은 조직이 등록한 webhook입니다. Secret은 서명에만 쓰고 JSON에는 내보내지 않습니다.
+type Webhook struct {
+	WebhookID   string    `json:"webhook_id"`
+	OrgID       string    `json:"org_id"`
+	URL         string    `json:"url"`
+	Secret      string    `json:"-"`
+	EventTypes  []string  `json:"event_types"`
+	Description string    `json:"description,omitempty"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery는 webhook 전송 기록 한 건입니다.
+type WebhookDelivery struct {
+	DeliveryID     int64           `json:"delivery_id"`
+	WebhookID      string          `json:"webhook_id"`
+	EventType      string          `json:"event_type"`
+	EventKey       string          `json:"event_key"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"` // pending일 때만
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	LastResponse   string          `json:"last_response,omitempty"`
+	LastDurationMs int             `json:"last_duration_ms,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// DueWebhookDelivery는 전송할 차례가 된 기록과 보낼 곳입니다 (디스패처용).
+type DueWebhookDelivery struct {
+	DeliveryID int64
+	WebhookID  string
+	EventType  string
+	Payload    []byte
+	Attempts   int // 이번 시도를 포함한 횟수
+	URL        string
+	Secret     string
+}
+
+// WebhookResult는 전송 시도 한 번의 결과입니다.
+type WebhookResult struct {
+	Status     string        // delivered, pending(재시도), failed
+	RetryAfter time.Duration // Status가 pending일 때 다음 시도까지
+	StatusCode int
+	Error      string
+	Response   string
+	Duration   time.Duration
+}
+
+const webhookColumns = `webhook_id, org_id, url, secret, event_types, description, is_active, created_at, updated_at`
+
+func scanWebhook(row interface{ Scan(...interface{}) error }) (*Webhook, error) {
+	var w Webhook
+	if err := row.Scan(&w.WebhookID, &w.OrgID, &w.URL, &w.Secret, pq.Array(&w.EventTypes),
+		&w.Description, &w.IsActive, &w.CreatedAt, &w.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// CreateWebhook은 조직에 webhook을 등록합니다.
+func CreateWebhook(db DBTX, w *Webhook) error {
+	return db.QueryRow(
+		`INSERT INTO webhooks (org_id, url, secret, event_types, description, is_active)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING webhook_id, created_at, updated_at`,
+		w.OrgID, w.URL, w.Secret, pq.Array(w.EventTypes), w.Description, w.IsActive,
+	).Scan(&w.WebhookID, &w.CreatedAt, &w.UpdatedAt)
+}
+
+// GetWebhook은 조직의 webhook 하나를 조회합니다 (없으면 sql.ErrNoRows).
+func GetWebhook(db DBTX, id, orgID string) (*Webhook, error) {
+	return scanWebhook(db.QueryRow(
+		"SELECT "+webhookColumns+" FROM webhooks WHERE webhook_id::text = $1 AND org_id::text = $2", id, orgID))
+}
+
+// ListWebhooks는 조직의 webhook을 등록 순서대로 조회합니다.
+func ListWebhooks(db DBTX, orgID string) ([]Webhook, error) {
+	return queryWebhooks(db, "SELECT "+webhookColumns+" FROM webhooks WHERE org_id::text = $1 ORDER BY created_at", orgID)
+}
+
+// ListActiveWebhooks는 모든 조직의 활성 webhook을 조회합니다 (webhook 디스패처용).
+func ListActiveWebhooks(db DBTX) ([]Webhook, error) {
+	return queryWebhooks(db, "SELECT "+webhookColumns+" FROM webhooks WHERE is_active ORDER BY created_at")
+}
+
+func queryWebhooks(db DBTX, query string, args ...interface{}) ([]Webhook, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []Webhook{}
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, *w)
+	}
+	return webhooks, rows.Err()
+}
+
+// UpdateWebhook은 webhook의 주소, 비밀값, 이벤트 종류, 설명과 활성 상태를 갱신합니다.
+func UpdateWebhook(db DBTX, w *Webhook) error {
+	return db.QueryRow(
+		`UPDATE webhooks
+		 SET url = $3, secret = $4, event_types = $5, description = $6, is_active = $7, updated_at = now()
+		 WHERE webhook_id::text = $1 AND org_id::text = $2
+		 RETURNING updated_at`,
+		w.WebhookID, w.OrgID, w.URL, w.Secret, pq.Array(w.EventTypes), w.Description, w.IsActive,
+	).Scan(&w.UpdatedAt)
+}
+
+// DeleteWebhook은 webhook과 전송 기록을 삭제합니다. 없으면 false를 반환합니다.
+func DeleteWebhook(db DBTX, id, orgID string) (bool, error) {
+	res, err := db.Exec("DELETE FROM webhooks WHERE webhook_id::text = $1 AND org_id::text = $2", id, orgID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// EnqueueWebhookEvent는 이벤트를 구독하는 조직의 활성 webhook마다 전송 기록을 적재하고 적재한 수를 반환합니다.
+// 같은 이벤트 키는 webhook마다 한 번만 적재하므로 CDC 이벤트가 다시 와도 중복 전송하지 않습니다.
+func EnqueueWebhookEvent(db DBTX, env webhook.Envelope) (int64, error) {
+	payload, err := json.Marshal(env)
+	if err != nil {
+		return 0, err
+	}
+	res, err := db.Exec(
+		`INSERT INTO webhook_deliveries (webhook_id, org_id, event_type, event_key, payload)
+		 SELECT webhook_id, org_id, $2, $3, $4::jsonb FROM webhooks
+		 WHERE org_id::text = $1 AND is_active AND $2 = ANY(event_types)
+		 ON CONFLICT (webhook_id, event_key) DO NOTHING`,
+		env.OrgID, env.Event, env.ID, string(payload),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// CategoryOrgs는 카테고리의 활성 스키마를 가진 조직을 반환합니다 (조직이 없는 스키마 변경 이벤트용).
+func CategoryOrgs(db DBTX, category string) ([]string, error) {
+	rows, err := db.Query(
+		"SELECT DISTINCT org_id::text FROM category_schemas WHERE category_name = $1 AND is_active = true", category)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orgs []string
+	for rows.Next() {
+		var orgID string
+		if err := rows.Scan(&orgID); err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, orgID)
+	}
+	return orgs, rows.Err()
+}
+
+// ClaimWebhookDeliveries는 차례가 된 전송 기록을 가져오고 시도 횟수를 올립니다.
+// 가져간 기록은 lease 동안 다시 가져가지 않으므로 디스패처가 결과를 남기지 못하고 죽어도 lease 뒤에 재시도됩니다.
+// 비활성 webhook의 기록은 다시 활성화될 때까지 대기합니다.
+func ClaimWebhookDeliveries(db DBTX, limit int, lease time.Duration) ([]DueWebhookDelivery, error) {
+	rows, err := db.Query(
+		`WITH due AS (
+			SELECT d.delivery_id FROM webhook_deliveries d
+			JOIN webhooks w ON w.webhook_id = d.webhook_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= now() AND w.is_active
+			ORDER BY d.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET attempts = d.attempts + 1, next_attempt_at = now() + make_interval(secs => $2)
+		FROM due, webhooks w
+		WHERE d.delivery_id = due.delivery_id AND w.webhook_id = d.webhook_id
+		RETURNING d.delivery_id, d.webhook_id, d.event_type, d.payload, d.attempts, w.url, w.secret`,
+		limit, lease.Seconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []DueWebhookDelivery
+	for rows.Next() {
+		var d DueWebhookDelivery
+		if err := rows.Scan(&d.DeliveryID, &d.WebhookID, &d.EventType, &d.Payload, &d.Attempts, &d.URL, &d.Secret); err != nil {
+			return nil, err
+		}
+		due = append(due, d)
+	}
+	return due, rows.Err()
+}
+
+// FinishWebhookDelivery는 전송 시도 결과를 기록합니다.
+func FinishWebhookDelivery(db DBTX, deliveryID int64, result WebhookResult) error {
+	_, err := db.Exec(
+		`UPDATE webhook_deliveries
+		 SET status = $2,
+			next_attempt_at = now() + make_interval(secs => $3),
+			last_status_code = NULLIF($4, 0),
+			last_error = NULLIF($5, ''),
+			last_response = NULLIF($6, ''),
+			last_duration_ms = $7,
+			delivered_at = CASE WHEN $2 = 'delivered' THEN now() END
+		 WHERE delivery_id = $1`,
+		deliveryID, result.Status, result.RetryAfter.Seconds(), result.StatusCode, result.Error, result.Response,
+		result.Duration.Milliseconds(),
+	)
+	return err
+}
+
+// WebhookDeliveryFilter는 전송 기록 조회 조건입니다.
+type WebhookDeliveryFilter struct {
+	Status    string
+	EventType string
+	Before    int64 // 이 delivery_id보다 오래된 기록만 (페이지 넘김)
+	Limit     int
+}
+
+// ListWebhookDeliveries는 조직 webhook의 전송 기록을 최신순으로 조회합니다.
+func ListWebhookDeliveries(db DBTX, orgID, webhookID string, filter WebhookDeliveryFilter) ([]WebhookDelivery, error) {
+	query := `SELECT delivery_id, webhook_id, event_type, event_key, payload, status, attempts,
+			CASE WHEN status = 'pending' THEN next_attempt_at END, COALESCE(last_status_code, 0),
+			COALESCE(last_error, ''), COALESCE(last_response, ''), COALESCE(last_duration_ms, 0), created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE org_id::text = $1 AND webhook_id::text = $2`
+	args := []interface{}{orgID, webhookID}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		query += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+	if filter.EventType != "" {
+		args = append(args, filter.EventType)
+		query += fmt.Sprintf(" AND event_type = $%d", len(args))
+	}
+	if filter.Before > 0 {
+		args = append(args, filter.Before)
+		query += fmt.Sprintf(" AND delivery_id < $%d", len(args))
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY delivery_id DESC LIMIT $%d", len(args))
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []WebhookDelivery{}
+	for rows.Next() {
+		var d WebhookDelivery
+		var nextAttempt, deliveredAt sql.NullTime
+		if err := rows.Scan(&d.DeliveryID, &d.WebhookID, &d.EventType, &d.EventKey, &d.Payload, &d.Status, &d.Attempts,
+			&nextAttempt, &d.LastStatusCode, &d.LastError, &d.LastResponse, &d.LastDurationMs, &d.CreatedAt, &deliveredAt); err != nil {
+			return nil, err
+		}
+		if nextAttempt.Valid {
+			d.NextAttemptAt = &nextAttempt.Time
+		}
+		if deliveredAt.Valid {
+			d.DeliveredAt = &deliveredAt.Time
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// RedeliverWebhookDelivery는 전송 기록을 시도 횟수 0의 대기 상태로 되돌려 바로 다시 보내게 합니다.
+// 없으면 false를 반환합니다.
+func RedeliverWebhookDelivery(db DBTX, orgID, webhookID string, deliveryID int64) (bool, error) {
+	res, err := db.Exec(
+		`UPDATE webhook_deliveries
+		 SET status = 'pending', attempts = 0, next_attempt_at = now(), delivered_at = NULL
+		 WHERE delivery_id = $3 AND org_id::text = $1 AND webhook_id::text = $2`,
+		orgID, webhookID, deliveryID,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// PurgeWebhookDeliveries는 끝난(delivered, failed) 전송 기록 중 olderThan보다 오래된 것을 삭제합니다.
+func PurgeWebhookDeliveries(db DBTX, olderThan time.Duration) (int64, error) {
+	res, err := db.Exec(
+		"DELETE FROM webhook_deliveries WHERE status <> 'pending' AND created_at < now() - make_interval(secs => $1)",
+		olderThan.Seconds(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/nats-io/nats.go"
 	"github.com/tmidb/tmidb-core/internal/busconsumer"
 	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/logger"
 )
 
 // DataConsumer 데이터 소비 및 처리를 담당하는 구조체
@@ -62,6 +63,13 @@ func (dc *DataConsumer) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to start subscriptions: %w", err)
 	}
 
+	// JetStream 수집 파이프라인 시작 (tmidb.ingest.<org>.<category>)
+	pipeline, err := NewIngestPipeline(dc.NatsConn, database.DB)
+	if err != nil {
+		return fmt.Errorf("failed to create ingest pipeline: %w", err)
+	}
+	go pipeline.Run(dc.Ctx)
+
 	// 배치 처리 시작
 	go dc.StartBatchProcessor()
 
@@ -108,15 +116,16 @@ func (dc *DataConsumer) handleDataMessage(msg *nats.Msg) {
 		return
 	}
 
-	log.Printf("📨 DataConsumer received data: %s from %s.%s", dataPoint.ID, dataPoint.Source, dataPoint.Category)
+	fields := busconsumer.MessageFields(msg, dataPoint)
+	logger.Log(logger.LogLevelInfo, fmt.Sprintf("📨 DataConsumer received data: %s from %s.%s", dataPoint.ID, dataPoint.Source, dataPoint.Category), fields...)
 
 	// 데이터베이스에 저장
 	if err := dc.SaveToDatabase(dataPoint); err != nil {
-		log.Printf("❌ DataConsumer: Failed to save data to database: %v", err)
+		logger.Log(logger.LogLevelError, fmt.Sprintf("❌ DataConsumer: Failed to save data to database: %v", err), fields...)
 		return
 	}
 
-	log.Printf("💾 DataConsumer saved data: %s", dataPoint.ID)
+	logger.Log(logger.LogLevelInfo, fmt.Sprintf("💾 DataConsumer saved data: %s", dataPoint.ID), fields...)
 }
 
 // handleSystemMetrics 시스템 메트릭을 처리합니다
@@ -0,0 +1,593 @@
+package dataconsumer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/logger"
+	"github.com/tmidb/tmidb-core/internal/schema"
+	"github.com/tmidb/tmidb-core/internal/usage"
+)
+
+// JetStream 수집 파이프라인 설정
+const (
+	IngestStream            = "TMIDB_INGEST"
+	IngestSubjectPrefix     = "tmidb.ingest"
+	DeadLetterStream        = "TMIDB_INGEST_DLQ"
+	DeadLetterSubjectPrefix = "tmidb.deadletter.ingest"
+
+	ingestBatchSize     = 500              // Fetch 한 번에 가져오는 메시지 수
+	ingestFetchWait     = 2 * time.Second  // 배치를 채우기 위해 기다리는 최대 시간
+	ingestAckWait       = 30 * time.Second // 이 시간 안에 ack하지 않으면 재전송
+	ingestMaxDeliver    = 5                // 이 횟수만큼 실패하면 dead-letter로 보냄
+	ingestMaxRetryDelay = time.Minute
+	ingestSyncInterval  = time.Minute // 새 카테고리 확인 주기
+)
+
+// 데드레터 메시지 헤더
+const (
+	HeaderIngestError      = "Tmidb-Ingest-Error"
+	HeaderIngestSubject    = "Tmidb-Ingest-Subject"
+	HeaderIngestDeliveries = "Tmidb-Ingest-Deliveries"
+	HeaderIngestReplayOf   = "Tmidb-Ingest-Replay-Of" // 다시 보낸 메시지의 dead-letter 시퀀스
+)
+
+// subjectTokenPattern NATS 주제 토큰과 durable 이름에 쓸 수 있는 카테고리/조직 이름
+var subjectTokenPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// IngestRecord는 tmidb.ingest.<org>.<category> 메시지 하나의 본문입니다.
+// payload는 ts_obs에, data는 target_categories.category_data에 저장됩니다.
+type IngestRecord struct {
+	TargetID string          `json:"target_id"`
+	EventID  string          `json:"event_id,omitempty"` // 중복 제거 키 (재전송돼도 관측값을 한 번만 저장)
+	Ts       string          `json:"ts,omitempty"`       // RFC3339, 비어 있으면 스트림 저장 시각
+	Payload  json.RawMessage `json:"payload,omitempty"`
+	Data     json.RawMessage `json:"data,omitempty"`
+}
+
+// IngestSubject는 조직 카테고리의 수집 주제를 반환합니다
+func IngestSubject(orgID, category string) string {
+	return fmt.Sprintf("%s.%s.%s", IngestSubjectPrefix, orgID, category)
+}
+
+// parseIngestSubject는 수집 주제에서 조직과 카테고리를 추출합니다
+func parseIngestSubject(subject string) (orgID, category string, err error) {
+	rest := strings.TrimPrefix(subject, IngestSubjectPrefix+".")
+	parts := strings.Split(rest, ".")
+	if rest == subject || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("subject %q is not %s.<org>.<category>", subject, IngestSubjectPrefix)
+	}
+	return parts[0], parts[1], nil
+}
+
+// ingestDurableName은 카테고리별 durable 소비자 이름입니다
+func ingestDurableName(category string) string {
+	return "ingest_" + category
+}
+
+// retryDelay는 n번째 전송 실패 후 재전송까지 기다릴 시간입니다 (지수 백오프)
+func retryDelay(numDelivered uint64) time.Duration {
+	delay := 2 * time.Second
+	for i := uint64(1); i < numDelivered && delay < ingestMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > ingestMaxRetryDelay {
+		delay = ingestMaxRetryDelay
+	}
+	return delay
+}
+
+// ingestItem은 배치 안의 메시지 하나와 처리 상태입니다
+type ingestItem struct {
+	msg       jetstream.Msg
+	orgID     string
+	category  string
+	record    IngestRecord
+	ts        time.Time
+	payload   interface{}
+	data      interface{}
+	link      *database.TargetCategoryLink
+	key       string // 중복 제거 키 (비어 있으면 중복 제거 안 함)
+	duplicate bool   // 중복 제거 기간 안에 이미 저장된 관측값 (저장하지 않음)
+	err       error
+	permanent bool // 재시도해도 성공할 수 없는 실패 (바로 dead-letter)
+}
+
+func (it *ingestItem) fail(err error, permanent bool) {
+	it.err = err
+	it.permanent = permanent
+}
+
+func (it *ingestItem) fields() []logger.Field {
+	return []logger.Field{
+		logger.F(logger.FieldTargetID, it.record.TargetID),
+		logger.F(logger.FieldRequestID, it.msg.Headers().Get(logger.RequestIDHeader)),
+	}
+}
+
+// decodeIngestRecord는 메시지 본문을 해석하고 필수 필드를 확인합니다
+func decodeIngestRecord(it *ingestItem, body []byte, storedAt time.Time) {
+	if err := json.Unmarshal(body, &it.record); err != nil {
+		it.fail(fmt.Errorf("invalid JSON: %v", err), true)
+		return
+	}
+	if it.record.TargetID == "" {
+		it.fail(errors.New("target_id is required"), true)
+		return
+	}
+	if len(it.record.Payload) == 0 && len(it.record.Data) == 0 {
+		it.fail(errors.New("payload or data is required"), true)
+		return
+	}
+
+	it.ts = storedAt
+	if it.record.Ts != "" {
+		ts, err := time.Parse(time.RFC3339Nano, it.record.Ts)
+		if err != nil {
+			it.fail(fmt.Errorf("invalid ts: %v", err), true)
+			return
+		}
+		it.ts = ts
+	}
+
+	if len(it.record.Payload) > 0 {
+		if err := json.Unmarshal(it.record.Payload, &it.payload); err != nil {
+			it.fail(fmt.Errorf("invalid payload: %v", err), true)
+			return
+		}
+	}
+	if len(it.record.Data) > 0 {
+		if err := json.Unmarshal(it.record.Data, &it.data); err != nil {
+			it.fail(fmt.Errorf("invalid data: %v", err), true)
+			return
+		}
+	}
+
+	if len(it.record.EventID) > database.MaxEventIDLength {
+		it.fail(fmt.Errorf("event_id is longer than %d characters", database.MaxEventIDLength), true)
+		return
+	}
+	if it.payload != nil && (it.record.EventID != "" || it.record.Ts != "") {
+		// 대량 수집 API와 같은 키가 되도록 다시 직렬화한 payload로 해시
+		payloadJSON, _ := json.Marshal(it.payload)
+		clientTs := time.Time{}
+		if it.record.Ts != "" {
+			clientTs = it.ts
+		}
+		it.key = database.IngestEventKey(it.record.EventID, it.record.TargetID, clientTs, payloadJSON)
+	}
+}
+
+// IngestPipeline은 JetStream의 카테고리별 durable 소비자로 수집 메시지를 받아
+// 스키마 검증 후 배치로 저장합니다.
+type IngestPipeline struct {
+	nc      *nats.Conn
+	js      jetstream.JetStream
+	db      *sql.DB
+	usage   *usage.Recorder // 조직별 수집량 기록
+	schemas *schemaCache    // 조직 카테고리별 활성 스키마 (스키마 변경 이벤트로 갱신)
+	resync  chan struct{}   // 새 카테고리가 생겨 소비자를 바로 추가해야 함
+	workers map[string]bool // 소비자를 시작한 카테고리 (Run 고루틴에서만 접근)
+}
+
+// NewIngestPipeline은 NATS 연결로 수집 파이프라인을 생성합니다
+func NewIngestPipeline(nc *nats.Conn, db *sql.DB) (*IngestPipeline, error) {
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+	return &IngestPipeline{
+		nc:    nc,
+		js:    js,
+		db:    db,
+		usage: usage.NewRecorder(db),
+		schemas: newSchemaCache(func(orgID, category string) (*database.TargetCategoryLink, error) {
+			return database.GetActiveCategorySchema(db, orgID, category)
+		}),
+		resync:  make(chan struct{}, 1),
+		workers: make(map[string]bool),
+	}, nil
+}
+
+// Run은 스트림을 준비하고 활성 카테고리마다 소비자를 실행합니다.
+// 새로 생긴 카테고리는 스키마 변경 이벤트를 받거나 주기적으로 확인하여 소비자를 추가합니다.
+func (p *IngestPipeline) Run(ctx context.Context) {
+	p.usage.Start(ctx, usage.FlushInterval)
+
+	for {
+		err := p.ensureStreams(ctx)
+		if err == nil {
+			break
+		}
+		log.Printf("⏳ Ingest pipeline waiting for JetStream: %v", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(10 * time.Second):
+		}
+	}
+	log.Printf("📥 Ingest pipeline consuming %s.> (dead-letter: %s.>)", IngestSubjectPrefix, DeadLetterSubjectPrefix)
+
+	if sub, err := p.watchSchemas(); err != nil {
+		log.Printf("⚠️ Ingest pipeline: schema changes will be picked up every %s: %v", ingestSyncInterval, err)
+	} else {
+		defer sub.Unsubscribe()
+	}
+
+	ticker := time.NewTicker(ingestSyncInterval)
+	defer ticker.Stop()
+	for {
+		p.syncConsumers(ctx)
+		select {
+		case <-ctx.Done():
+			log.Println("🛑 Ingest pipeline stopped")
+			return
+		case <-p.resync:
+		case <-ticker.C:
+			// 놓친 스키마 변경 이벤트에 대비해 캐시를 비움
+			p.schemas.reset()
+		}
+	}
+}
+
+// ensureStreams는 수집 스트림과 dead-letter 스트림이 없으면 생성합니다.
+// 이미 있는 스트림의 설정은 supervisor의 nats_streams가 관리하므로 바꾸지 않습니다.
+func (p *IngestPipeline) ensureStreams(ctx context.Context) error {
+	streams := []jetstream.StreamConfig{
+		{
+			Name:     IngestStream,
+			Subjects: []string{IngestSubjectPrefix + ".>"},
+			Storage:  jetstream.FileStorage,
+			MaxAge:   7 * 24 * time.Hour,
+		},
+		{
+			Name:     DeadLetterStream,
+			Subjects: []string{DeadLetterSubjectPrefix + ".>"},
+			Storage:  jetstream.FileStorage,
+			MaxAge:   30 * 24 * time.Hour,
+		},
+	}
+	for _, cfg := range streams {
+		_, err := p.js.Stream(ctx, cfg.Name)
+		if errors.Is(err, jetstream.ErrStreamNotFound) {
+			_, err = p.js.CreateStream(ctx, cfg)
+		}
+		if err != nil && !errors.Is(err, jetstream.ErrStreamNameAlreadyInUse) {
+			return fmt.Errorf("stream %s: %w", cfg.Name, err)
+		}
+	}
+	return nil
+}
+
+// syncConsumers는 아직 소비자가 없는 활성 카테고리의 소비자를 시작합니다
+func (p *IngestPipeline) syncConsumers(ctx context.Context) {
+	categories, err := database.ListActiveCategoryNames(p.db)
+	if err != nil {
+		log.Printf("❌ Ingest pipeline: failed to list categories: %v", err)
+		return
+	}
+
+	for _, category := range categories {
+		if p.workers[category] {
+			continue
+		}
+		if !subjectTokenPattern.MatchString(category) {
+			log.Printf("⚠️ Ingest pipeline: category %q cannot be used as a NATS subject token, skipping", category)
+			p.workers[category] = true
+			continue
+		}
+
+		consumer, err := p.js.CreateOrUpdateConsumer(ctx, IngestStream, jetstream.ConsumerConfig{
+			Durable:       ingestDurableName(category),
+			FilterSubject: IngestSubject("*", category),
+			AckPolicy:     jetstream.AckExplicitPolicy,
+			AckWait:       ingestAckWait,
+			MaxDeliver:    ingestMaxDeliver,
+			MaxAckPending: ingestBatchSize * 4,
+		})
+		if err != nil {
+			log.Printf("❌ Ingest pipeline: failed to create consumer for %s: %v", category, err)
+			continue
+		}
+
+		p.workers[category] = true
+		go p.consume(ctx, category, consumer)
+		log.Printf("📡 Ingest pipeline: consumer %s started", ingestDurableName(category))
+	}
+}
+
+// consume은 카테고리 소비자에서 배치를 가져와 처리합니다
+func (p *IngestPipeline) consume(ctx context.Context, category string, consumer jetstream.Consumer) {
+	for ctx.Err() == nil {
+		batch, err := consumer.Fetch(ingestBatchSize, jetstream.FetchMaxWait(ingestFetchWait))
+		if err != nil {
+			log.Printf("❌ Ingest pipeline: fetch failed for %s: %v", category, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(ingestFetchWait):
+			}
+			continue
+		}
+
+		var msgs []jetstream.Msg
+		for msg := range batch.Messages() {
+			msgs = append(msgs, msg)
+		}
+		if err := batch.Error(); err != nil && !errors.Is(err, nats.ErrTimeout) {
+			log.Printf("⚠️ Ingest pipeline: fetch for %s ended early: %v", category, err)
+		}
+		if len(msgs) > 0 {
+			p.processBatch(msgs)
+		}
+	}
+}
+
+// processBatch는 메시지 배치를 해석, 검증, 저장하고 각 메시지를 ack/nak/dead-letter 처리합니다
+func (p *IngestPipeline) processBatch(msgs []jetstream.Msg) {
+	items := make([]*ingestItem, len(msgs))
+	for i, msg := range msgs {
+		it := &ingestItem{msg: msg}
+		items[i] = it
+
+		orgID, category, err := parseIngestSubject(msg.Subject())
+		if err != nil {
+			it.fail(err, true)
+			continue
+		}
+		it.orgID, it.category = orgID, category
+
+		storedAt := time.Now()
+		if meta, err := msg.Metadata(); err == nil {
+			storedAt = meta.Timestamp
+		}
+		decodeIngestRecord(it, msg.Data(), storedAt)
+	}
+
+	p.validate(items)
+	p.store(items)
+
+	stored, duplicates := 0, 0
+	for _, it := range items {
+		if it.err == nil && it.duplicate {
+			duplicates++
+		} else if it.err == nil {
+			stored++
+			p.usage.AddIngested(it.orgID, 1, int64(len(it.msg.Data())))
+		}
+		p.settle(it)
+	}
+	if duplicates > 0 {
+		log.Printf("💾 Ingest pipeline stored %d/%d records (%d duplicates skipped)", stored, len(items), duplicates)
+	} else {
+		log.Printf("💾 Ingest pipeline stored %d/%d records", stored, len(items))
+	}
+}
+
+// validate는 타겟의 조직과 카테고리 스키마로 레코드를 검증합니다
+func (p *IngestPipeline) validate(items []*ingestItem) {
+	links := make(map[string]*database.TargetCategoryLink)
+	unlinked := make(map[string]bool) // 활성 스키마 캐시에서 가져온 연결
+
+	for _, it := range items {
+		if it.err != nil {
+			continue
+		}
+
+		key := it.category + "/" + it.record.TargetID
+		link, ok := links[key]
+		if !ok {
+			var err error
+			link, err = database.GetTargetCategoryLink(p.db, it.record.TargetID, it.category)
+			if err != nil && err != sql.ErrNoRows {
+				it.fail(fmt.Errorf("failed to look up target: %w", err), false)
+				continue
+			}
+			links[key] = link // 연결이 없으면 nil
+		}
+
+		if link != nil && link.Deleted {
+			it.fail(fmt.Errorf("%w: target %s in category %s", database.ErrInRecycleBin, it.record.TargetID, it.category), true)
+			continue
+		}
+		if link == nil {
+			// 연결되지 않은 타겟은 data로 연결을 만들 때만 받음
+			if it.data == nil {
+				it.fail(fmt.Errorf("target %s is not linked to category %s", it.record.TargetID, it.category), true)
+				continue
+			}
+			active, err := p.schemas.get(it.orgID, it.category)
+			if err != nil {
+				it.fail(fmt.Errorf("failed to load category schema: %w", err), false)
+				continue
+			}
+			if active == nil {
+				it.fail(fmt.Errorf("category %s has no active schema for organization %s", it.category, it.orgID), true)
+				continue
+			}
+			link = active
+			links[key] = link // 같은 배치의 뒤 레코드는 이 연결을 사용
+			unlinked[key] = true
+		}
+
+		if link.OrgID != it.orgID {
+			it.fail(fmt.Errorf("target %s belongs to another organization", it.record.TargetID), true)
+			continue
+		}
+		it.link = link
+
+		err := validateRecord(it, link)
+		if err != nil && unlinked[key] {
+			// 캐시한 스키마가 변경 이벤트보다 먼저 온 레코드를 거부하지 않도록 새 버전이 있으면 다시 검증
+			// (잘못된 레코드가 많아도 DB는 배치마다 한 번만 읽음)
+			unlinked[key] = false
+			if fresh, loadErr := p.schemas.reload(it.orgID, it.category); loadErr == nil && fresh != nil && fresh.SchemaVersion != link.SchemaVersion {
+				it.link, links[key] = fresh, fresh
+				err = validateRecord(it, fresh)
+			}
+		}
+		if err != nil {
+			it.fail(err, true)
+		}
+	}
+}
+
+// validateRecord는 레코드의 data와 payload를 연결된 스키마로 검증합니다
+func validateRecord(it *ingestItem, link *database.TargetCategoryLink) error {
+	if link.SchemaDefinition == "" {
+		return nil
+	}
+	compiled, err := schema.Cached(link.SchemaDefinition)
+	if err != nil {
+		return fmt.Errorf("invalid %s schema: %w", it.category, err)
+	}
+	for _, v := range []interface{}{it.data, it.payload} {
+		if v == nil {
+			continue
+		}
+		if err := compiled.Validate(v); err != nil {
+			return fmt.Errorf("payload rejected: %w", err)
+		}
+	}
+	return nil
+}
+
+// store는 검증된 레코드를 한 트랜잭션으로 저장합니다.
+// 레코드별 세이브포인트를 사용하므로 한 레코드의 실패가 다른 레코드에 영향을 주지 않습니다.
+func (p *IngestPipeline) store(items []*ingestItem) {
+	var pending []*ingestItem
+	for _, it := range items {
+		if it.err == nil {
+			pending = append(pending, it)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	failAll := func(err error) {
+		for _, it := range pending {
+			if it.err == nil {
+				it.fail(err, false)
+			}
+		}
+	}
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		failAll(fmt.Errorf("failed to begin transaction: %w", err))
+		return
+	}
+	defer tx.Rollback()
+	if err := database.SetChangeActor(tx, "ingest:nats"); err != nil {
+		failAll(fmt.Errorf("failed to begin transaction: %w", err))
+		return
+	}
+
+	for _, it := range pending {
+		recordErr, err := database.WithSavepoint(tx, "ingest_record", func() error {
+			if it.data != nil {
+				if err := database.UpsertTargetCategoryData(tx, it.record.TargetID, it.link, it.category, string(it.record.Data)); err != nil {
+					return fmt.Errorf("failed to save category data: %w", err)
+				}
+			}
+			if it.payload == nil {
+				return nil
+			}
+			claimed, err := database.ClaimIngestEvent(tx, it.record.TargetID, it.category, it.key)
+			if err != nil {
+				return fmt.Errorf("failed to check duplicate: %w", err)
+			}
+			if !claimed {
+				it.duplicate = true
+				return nil
+			}
+			return database.InsertTimeSeriesPoint(tx, it.record.TargetID, it.category, it.ts, string(it.record.Payload))
+		})
+		if err != nil {
+			failAll(fmt.Errorf("transaction failed: %w", err))
+			return
+		}
+		if recordErr != nil {
+			it.fail(recordErr, isPermanentDBError(recordErr))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		failAll(fmt.Errorf("commit failed: %w", err))
+	}
+}
+
+// isPermanentDBError는 재시도해도 같은 결과가 나오는 데이터 오류인지 확인합니다
+// (22: 데이터 예외, 23: 무결성 제약 위반)
+func isPermanentDBError(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		class := pqErr.Code.Class()
+		return class == "22" || class == "23"
+	}
+	return false
+}
+
+// settle은 처리 결과에 따라 메시지를 ack, 재전송 예약 또는 dead-letter 처리합니다
+func (p *IngestPipeline) settle(it *ingestItem) {
+	if it.err == nil {
+		if err := it.msg.Ack(); err != nil {
+			logger.Log(logger.LogLevelWarn, fmt.Sprintf("⚠️ Ingest pipeline: ack failed: %v", err), it.fields()...)
+		}
+		return
+	}
+
+	var numDelivered uint64 = 1
+	if meta, err := it.msg.Metadata(); err == nil {
+		numDelivered = meta.NumDelivered
+	}
+
+	if !it.permanent && numDelivered < ingestMaxDeliver {
+		logger.Log(logger.LogLevelWarn, fmt.Sprintf("⚠️ Ingest pipeline: %s (delivery %d/%d, retrying)", it.err, numDelivered, ingestMaxDeliver), it.fields()...)
+		it.msg.NakWithDelay(retryDelay(numDelivered))
+		return
+	}
+
+	if err := p.deadLetter(it, numDelivered); err != nil {
+		logger.Log(logger.LogLevelError, fmt.Sprintf("❌ Ingest pipeline: dead-letter publish failed, will retry: %v", err), it.fields()...)
+		it.msg.NakWithDelay(retryDelay(numDelivered))
+		return
+	}
+	logger.Log(logger.LogLevelError, fmt.Sprintf("❌ Ingest pipeline: %s (sent to dead-letter)", it.err), it.fields()...)
+	it.msg.Term()
+}
+
+// deadLetter는 원본 메시지를 실패 사유와 함께 dead-letter 주제로 보냅니다
+func (p *IngestPipeline) deadLetter(it *ingestItem, numDelivered uint64) error {
+	subject := it.msg.Subject()
+	msg := nats.NewMsg(DeadLetterSubjectPrefix + "." + strings.TrimPrefix(subject, IngestSubjectPrefix+"."))
+	msg.Data = it.msg.Data()
+	for key, values := range it.msg.Headers() {
+		if strings.HasPrefix(key, "Nats-") {
+			continue // 발행 옵션(Nats-Msg-Id 등)이 dead-letter 발행에 적용되지 않도록 제외
+		}
+		for _, v := range values {
+			msg.Header.Add(key, v)
+		}
+	}
+	msg.Header.Set(HeaderIngestError, it.err.Error())
+	msg.Header.Set(HeaderIngestSubject, subject)
+	msg.Header.Set(HeaderIngestDeliveries, fmt.Sprint(numDelivered))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := p.js.PublishMsg(ctx, msg)
+	return err
+}
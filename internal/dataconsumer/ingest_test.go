@@ -0,0 +1,105 @@
+package dataconsumer
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/schema"
+)
+
+func TestParseIngestSubject(t *testing.T) {
+	orgID, category, err := parseIngestSubject(IngestSubject("9b2c", "air-quality"))
+	if err != nil || orgID != "9b2c" || category != "air-quality" {
+		t.Fatalf("unexpected result %q %q %v", orgID, category, err)
+	}
+	for _, subject := range []string{"tmidb.ingest.org", "tmidb.ingest.org.cat.extra", "tmidb.data.org.cat", "tmidb.ingest..cat"} {
+		if _, _, err := parseIngestSubject(subject); err == nil {
+			t.Errorf("%q: expected error", subject)
+		}
+	}
+}
+
+func TestDecodeIngestRecord(t *testing.T) {
+	storedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	it := &ingestItem{}
+	decodeIngestRecord(it, []byte(`{"target_id":"t1","payload":{"temp":21.5}}`), storedAt)
+	if it.err != nil || !it.ts.Equal(storedAt) || it.payload == nil || it.data != nil {
+		t.Fatalf("unexpected item %+v", it)
+	}
+
+	it = &ingestItem{}
+	decodeIngestRecord(it, []byte(`{"target_id":"t1","ts":"2025-02-01T00:00:00Z","data":{"name":"pump"}}`), storedAt)
+	if it.err != nil || it.ts.Month() != time.February || it.data == nil {
+		t.Fatalf("unexpected item %+v", it)
+	}
+
+	for _, body := range []string{`not json`, `{"payload":{}}`, `{"target_id":"t1"}`, `{"target_id":"t1","ts":"yesterday","payload":{}}`} {
+		it = &ingestItem{}
+		decodeIngestRecord(it, []byte(body), storedAt)
+		if it.err == nil || !it.permanent {
+			t.Errorf("%s: expected permanent error, got %+v", body, it)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	if d := retryDelay(1); d != 2*time.Second {
+		t.Errorf("first retry: %v", d)
+	}
+	if d := retryDelay(3); d != 8*time.Second {
+		t.Errorf("third retry: %v", d)
+	}
+	if d := retryDelay(20); d != ingestMaxRetryDelay {
+		t.Errorf("retry delay should be capped, got %v", d)
+	}
+}
+
+func TestSchemaCacheReload(t *testing.T) {
+	versions := map[string]int{"org-a/sensors": 1, "org-b/sensors": 1}
+	loads := 0
+	c := newSchemaCache(func(orgID, category string) (*database.TargetCategoryLink, error) {
+		loads++
+		version, ok := versions[orgID+"/"+category]
+		if !ok {
+			return nil, sql.ErrNoRows
+		}
+		return &database.TargetCategoryLink{OrgID: orgID, SchemaVersion: version, SchemaDefinition: `{"type":"object"}`}, nil
+	})
+
+	first, err := c.get("org-a", "sensors")
+	if err != nil || first.SchemaVersion != 1 {
+		t.Fatalf("get = %+v, %v", first, err)
+	}
+	c.get("org-a", "sensors")
+	c.get("org-b", "sensors")
+	if loads != 2 {
+		t.Errorf("loaded %d times, want 2", loads)
+	}
+
+	// 처리 중인 배치가 가진 연결은 바뀌지 않고 다음 get부터 새 버전
+	versions["org-a/sensors"] = 2
+	c.apply(schema.Change{OrgID: "org-a", Category: "sensors", Operation: schema.ChangeUpdate, Version: 2})
+	if first.SchemaVersion != 1 {
+		t.Error("in-flight link modified")
+	}
+	if link, _ := c.get("org-a", "sensors"); link.SchemaVersion != 2 {
+		t.Errorf("version after update = %d", link.SchemaVersion)
+	}
+
+	delete(versions, "org-a/sensors")
+	c.apply(schema.Change{OrgID: "org-a", Category: "sensors", Operation: schema.ChangeDelete})
+	if link, err := c.get("org-a", "sensors"); link != nil || err != nil {
+		t.Errorf("deleted schema still cached: %+v %v", link, err)
+	}
+
+	// 조직이 없는 이벤트는 모든 조직의 카테고리를 다시 읽게 함
+	before := loads
+	c.apply(schema.Change{Category: "sensors", Operation: schema.ChangeUpdate})
+	c.get("org-b", "sensors")
+	if loads != before+1 {
+		t.Errorf("org-less event did not drop org-b (loads %d -> %d)", before, loads)
+	}
+}
@@ -0,0 +1,125 @@
+package dataconsumer
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"maps"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nats-io/nats.go"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/schema"
+)
+
+// schemaKey 조직 카테고리
+type schemaKey struct {
+	orgID    string
+	category string
+}
+
+// schemaCache는 조직 카테고리별 활성 스키마를 캐시합니다.
+// 읽기는 잠금 없이 현재 map을 쓰고, 다시 읽을 때는 새 스키마를 컴파일한 뒤 복사한 map으로 한 번에 바꿉니다.
+// 처리 중인 배치는 이미 읽은 스키마로 끝까지 검증하므로 다시 읽는 동안 메시지를 멈추거나 버리지 않습니다.
+type schemaCache struct {
+	mu      sync.Mutex // 쓰기(다시 읽기)를 하나씩 처리
+	current atomic.Pointer[map[schemaKey]*database.TargetCategoryLink]
+	load    func(orgID, category string) (*database.TargetCategoryLink, error)
+}
+
+// newSchemaCache는 load로 활성 스키마를 읽는 캐시를 만듭니다 (없으면 sql.ErrNoRows)
+func newSchemaCache(load func(orgID, category string) (*database.TargetCategoryLink, error)) *schemaCache {
+	c := &schemaCache{load: load}
+	c.current.Store(&map[schemaKey]*database.TargetCategoryLink{})
+	return c
+}
+
+// get은 활성 스키마를 반환합니다. 활성 스키마가 없으면 nil입니다.
+func (c *schemaCache) get(orgID, category string) (*database.TargetCategoryLink, error) {
+	if link, ok := (*c.current.Load())[schemaKey{orgID, category}]; ok {
+		return link, nil
+	}
+	return c.reload(orgID, category)
+}
+
+// reload는 활성 스키마를 DB에서 다시 읽어 캐시를 바꿉니다.
+// 읽기에 실패하면 캐시에서 빼서 다음 get이 다시 읽게 합니다.
+func (c *schemaCache) reload(orgID, category string) (*database.TargetCategoryLink, error) {
+	key := schemaKey{orgID, category}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	link, err := c.load(orgID, category)
+	if errors.Is(err, sql.ErrNoRows) {
+		link, err = nil, nil
+	}
+	if err != nil {
+		c.update(func(m map[schemaKey]*database.TargetCategoryLink) { delete(m, key) })
+		return nil, err
+	}
+	if link != nil && link.SchemaDefinition != "" {
+		// 바꾸기 전에 컴파일해 두어 다음 배치가 기다리지 않게 함 (잘못된 정의는 검증할 때 보고)
+		schema.Cached(link.SchemaDefinition)
+	}
+	c.update(func(m map[schemaKey]*database.TargetCategoryLink) { m[key] = link })
+	return link, nil
+}
+
+// apply는 스키마 변경 이벤트를 반영합니다.
+// 조직이 없는 이벤트는 모든 조직의 같은 카테고리를 캐시에서 빼고 다음에 필요할 때 읽습니다.
+func (c *schemaCache) apply(change schema.Change) {
+	if change.OrgID != "" {
+		if _, err := c.reload(change.OrgID, change.Category); err != nil {
+			log.Printf("⚠️ Ingest pipeline: failed to reload %s schema: %v", change.Category, err)
+		}
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.update(func(m map[schemaKey]*database.TargetCategoryLink) {
+		for key := range m {
+			if key.category == change.Category {
+				delete(m, key)
+			}
+		}
+	})
+}
+
+// reset은 캐시를 비웁니다 (놓친 이벤트에 대비해 주기적으로 호출)
+func (c *schemaCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current.Store(&map[schemaKey]*database.TargetCategoryLink{})
+}
+
+// update는 현재 map을 복사해 바꾼 뒤 교체합니다 (c.mu를 잡은 상태에서 호출)
+func (c *schemaCache) update(change func(map[schemaKey]*database.TargetCategoryLink)) {
+	next := maps.Clone(*c.current.Load())
+	change(next)
+	c.current.Store(&next)
+}
+
+// watchSchemas는 스키마 변경 이벤트를 구독해 캐시를 갱신하고,
+// 새 카테고리가 생기면 Run이 바로 소비자를 추가하도록 알립니다.
+func (p *IngestPipeline) watchSchemas() (*nats.Subscription, error) {
+	return p.nc.Subscribe(schema.ChangeSubject, func(msg *nats.Msg) {
+		var change schema.Change
+		if err := json.Unmarshal(msg.Data, &change); err != nil || change.Category == "" {
+			log.Printf("⚠️ Ingest pipeline: invalid schema change event: %s", msg.Data)
+			return
+		}
+		p.schemas.apply(change)
+		log.Printf("🔄 Ingest pipeline: reloaded schema of %s after %s", change.Category, change.Operation)
+
+		if change.Operation == schema.ChangeCreate {
+			select {
+			case p.resync <- struct{}{}:
+			default:
+			}
+		}
+	})
+}
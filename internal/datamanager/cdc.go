@@ -0,0 +1,139 @@
+package datamanager
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/nats-io/nats.go"
+	"github.com/tmidb/tmidb-core/internal/busconsumer"
+	"github.com/tmidb/tmidb-core/internal/database"
+)
+
+// CDC 발행 설정
+const (
+	cdcBatchSize     = 500
+	cdcPollInterval  = 5 * time.Second // 알림을 놓쳤을 때를 대비한 주기적 확인
+	cdcPurgeInterval = time.Hour
+	cdcRetention     = 24 * time.Hour // 발행된 이벤트를 아웃박스에 남겨 두는 기간
+	cdcFlushTimeout  = 5 * time.Second
+)
+
+// CDCPublisher는 change_events 아웃박스의 변경 이벤트를 NATS로 발행합니다.
+// 트리거가 기록한 이벤트를 LISTEN/NOTIFY로 감지하고, 발행에 성공한 이벤트만 완료로 표시하므로
+// 이벤트는 최소 한 번 전달됩니다 (구독자는 event_id로 중복을 걸러야 함).
+type CDCPublisher struct {
+	db  *sql.DB
+	nc  *nats.Conn
+	dsn string
+}
+
+// NewCDCPublisher는 CDC 발행자를 생성합니다. dsn이 비어 있으면 주기적 확인만 사용합니다.
+func NewCDCPublisher(db *sql.DB, nc *nats.Conn, dsn string) *CDCPublisher {
+	return &CDCPublisher{db: db, nc: nc, dsn: dsn}
+}
+
+// Run은 컨텍스트가 끝날 때까지 변경 이벤트를 발행합니다
+func (p *CDCPublisher) Run(ctx context.Context) {
+	var notify <-chan *pq.Notification
+	if p.dsn != "" {
+		listener := pq.NewListener(p.dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+			if err != nil {
+				log.Printf("⚠️ CDC listener: %v", err)
+			}
+		})
+		defer listener.Close()
+		if err := listener.Listen(database.ChangeNotifyChannel); err != nil {
+			log.Printf("⚠️ CDC listener unavailable, polling every %s: %v", cdcPollInterval, err)
+		} else {
+			notify = listener.Notify
+		}
+	}
+
+	log.Printf("📣 CDC publisher started (%s.<table>.<operation>)", busconsumer.ChangeSubjectPrefix)
+
+	poll := time.NewTicker(cdcPollInterval)
+	defer poll.Stop()
+	purge := time.NewTicker(cdcPurgeInterval)
+	defer purge.Stop()
+
+	for {
+		p.drain()
+
+		select {
+		case <-ctx.Done():
+			log.Println("🛑 CDC publisher stopped")
+			return
+		case <-notify:
+		case <-poll.C:
+		case <-purge.C:
+			if n, err := database.PurgeChangeEvents(p.db, cdcRetention); err != nil {
+				log.Printf("❌ CDC: failed to purge published events: %v", err)
+			} else if n > 0 {
+				log.Printf("🧹 CDC: purged %d published events", n)
+			}
+		}
+	}
+}
+
+// drain은 대기 중인 이벤트가 없을 때까지 배치 단위로 발행합니다
+func (p *CDCPublisher) drain() {
+	for {
+		n, err := p.publishBatch()
+		if err != nil {
+			log.Printf("❌ CDC: %v", err)
+			return
+		}
+		if n < cdcBatchSize {
+			return
+		}
+	}
+}
+
+// publishBatch는 이벤트 한 배치를 발행하고 완료로 표시합니다.
+// 발행이나 flush가 실패하면 트랜잭션을 되돌려 다음 시도에서 다시 발행합니다.
+func (p *CDCPublisher) publishBatch() (int, error) {
+	if p.nc == nil || !p.nc.IsConnected() {
+		return 0, fmt.Errorf("NATS connection not available")
+	}
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	events, err := database.ClaimChangeEvents(tx, cdcBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read change events: %w", err)
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]int64, len(events))
+	for i, event := range events {
+		msg, err := busconsumer.NewChangeMsg(event)
+		if err != nil {
+			return 0, err
+		}
+		if err := p.nc.PublishMsg(msg); err != nil {
+			return 0, fmt.Errorf("failed to publish change event %d: %w", event.EventID, err)
+		}
+		ids[i] = event.EventID
+	}
+	if err := p.nc.FlushTimeout(cdcFlushTimeout); err != nil {
+		return 0, fmt.Errorf("failed to flush change events: %w", err)
+	}
+
+	if err := database.MarkChangeEventsPublished(tx, ids); err != nil {
+		return 0, fmt.Errorf("failed to mark change events published: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit change events: %w", err)
+	}
+	return len(events), nil
+}
@@ -10,7 +10,9 @@ import (
 
 	"github.com/nats-io/nats.go"
 	"github.com/tmidb/tmidb-core/internal/busconsumer"
+	"github.com/tmidb/tmidb-core/internal/config"
 	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/logger"
 )
 
 // DataManager 데이터 수집 및 데이터베이스 관리를 담당하는 구조체
@@ -54,6 +56,31 @@ func (dm *DataManager) Start(ctx context.Context) error {
 	// 데이터 수집 프로세스 시작
 	go dm.startDataCollection()
 
+	// 변경 데이터 캡처(CDC) 이벤트 발행 시작 (LISTEN에 쓸 DSN이 없으면 주기적 확인만 사용)
+	dsn := ""
+	if cfg, err := config.Load(); err == nil {
+		dsn = cfg.DatabaseURL
+	}
+	go NewCDCPublisher(database.DB, dm.NatsConn, dsn).Run(dm.Ctx)
+
+	// 조직 webhook 전송 시작 (타겟/스키마 변경, 리스너 조건 일치)
+	hooks := NewWebhookDispatcher(database.DB, dm.NatsConn)
+	go func() {
+		if err := hooks.Run(dm.Ctx); err != nil {
+			log.Printf("❌ Webhook dispatcher failed: %v", err)
+		}
+	}()
+
+	// 리스너 런타임 시작 (CDC 이벤트를 리스너 조건으로 평가)
+	go func() {
+		if err := NewListenerRuntime(database.DB, dm.NatsConn, hooks).Run(dm.Ctx); err != nil {
+			log.Printf("❌ Listener runtime failed: %v", err)
+		}
+	}()
+
+	// 카테고리 롤업 작업 시작 (ts_obs 숫자 필드의 시간/일 단위 요약)
+	go NewRollupRunner(database.DB).Run(dm.Ctx)
+
 	// 배치 처리 시작
 	go dm.StartBatchProcessor()
 
@@ -98,14 +125,15 @@ func (dm *DataManager) handleDataMessage(msg *nats.Msg) {
 		return
 	}
 
-	log.Printf("📨 DataManager received data: %s from %s.%s", dataPoint.ID, dataPoint.Source, dataPoint.Category)
+	fields := busconsumer.MessageFields(msg, dataPoint)
+	logger.Log(logger.LogLevelInfo, fmt.Sprintf("📨 DataManager received data: %s from %s.%s", dataPoint.ID, dataPoint.Source, dataPoint.Category), fields...)
 
 	if err := dm.SaveToDatabase(dataPoint); err != nil {
-		log.Printf("❌ DataManager: Failed to save data to database: %v", err)
+		logger.Log(logger.LogLevelError, fmt.Sprintf("❌ DataManager: Failed to save data to database: %v", err), fields...)
 		return
 	}
 
-	log.Printf("💾 DataManager saved data: %s", dataPoint.ID)
+	logger.Log(logger.LogLevelInfo, fmt.Sprintf("💾 DataManager saved data: %s", dataPoint.ID), fields...)
 }
 
 // handleSystemMetrics 시스템 메트릭을 처리합니다
@@ -179,24 +207,26 @@ func (dm *DataManager) collectSystemMetrics() {
 		},
 	}
 
-	if err := dm.publishData(dataPoint); err != nil {
-		log.Printf("❌ Failed to publish system metrics: %v", err)
+	// 구독자 로그와 연결할 수 있도록 발행마다 요청 ID 부여
+	requestID := logger.NewRequestID()
+	fields := []logger.Field{logger.F(logger.FieldRequestID, requestID), logger.F(logger.FieldTargetID, dataPoint.ID)}
+
+	if err := dm.publishData(dataPoint, requestID); err != nil {
+		logger.Log(logger.LogLevelError, fmt.Sprintf("❌ Failed to publish system metrics: %v", err), fields...)
 	} else {
-		log.Printf("📤 Data Manager published system metrics: %s", dataPoint.Data["timestamp_id"])
+		logger.Log(logger.LogLevelInfo, fmt.Sprintf("📤 Data Manager published system metrics: %s", dataPoint.Data["timestamp_id"]), fields...)
 	}
 }
 
 // publishData 데이터를 NATS로 발행합니다
-func (dm *DataManager) publishData(dataPoint busconsumer.DataPoint) error {
+func (dm *DataManager) publishData(dataPoint busconsumer.DataPoint, requestID string) error {
 	if dm.NatsConn == nil {
 		return fmt.Errorf("NATS connection not available")
 	}
 
-	data, err := json.Marshal(dataPoint)
+	msg, err := busconsumer.NewDataMsg(dataPoint, requestID)
 	if err != nil {
-		return fmt.Errorf("failed to marshal data: %w", err)
+		return err
 	}
-
-	subject := fmt.Sprintf("tmidb.data.%s.%s", dataPoint.Source, dataPoint.Category)
-	return dm.NatsConn.Publish(subject, data)
+	return dm.NatsConn.PublishMsg(msg)
 }
@@ -0,0 +1,393 @@
+package datamanager
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/tmidb/tmidb-core/internal/busconsumer"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/listener"
+	"github.com/tmidb/tmidb-core/internal/webhook"
+)
+
+// 리스너 런타임 설정
+const (
+	listenerReloadInterval = 15 * time.Second // 리스너 정의 변경(활성화/비활성화 포함) 반영 주기
+	listenerStatsInterval  = 10 * time.Second
+	listenerWorkers        = 4
+	listenerQueueSize      = 1000
+	listenerActionTimeout  = 10 * time.Second
+	listenerOrgCacheSize   = 10000
+)
+
+// activeListener는 해석된 조건과 액션을 가진 실행 중인 리스너입니다
+type activeListener struct {
+	database.Listener
+	conditions []listener.Condition
+	actions    []listener.Action
+}
+
+// listenerJob은 실행할 리스너와 트리거입니다
+type listenerJob struct {
+	listener *activeListener
+	trigger  listener.Trigger
+}
+
+// ListenerRuntime은 CDC 변경 이벤트(ts_obs, target_categories)를 활성 리스너의 조건으로 평가하고
+// 조건을 만족하면 액션(webhook, NATS 발행, 다른 카테고리에 쓰기)을 실행합니다.
+type ListenerRuntime struct {
+	db     *sql.DB
+	nc     *nats.Conn
+	client *http.Client
+	jobs   chan listenerJob
+	hooks  *WebhookDispatcher // listener.matched webhook 이벤트 (nil이면 보내지 않음)
+
+	mu         sync.RWMutex
+	byCategory map[string][]*activeListener
+
+	statsMu sync.Mutex
+	stats   map[string]*database.ListenerStats // 마지막 flush 이후 증가분
+
+	orgMu sync.Mutex
+	orgs  map[string]string // target/category -> org_id
+}
+
+// NewListenerRuntime은 리스너 런타임을 생성합니다. hooks가 있으면 조건 일치를 webhook 이벤트로도 보냅니다.
+func NewListenerRuntime(db *sql.DB, nc *nats.Conn, hooks *WebhookDispatcher) *ListenerRuntime {
+	return &ListenerRuntime{
+		db:         db,
+		nc:         nc,
+		client:     webhook.NewClient(listenerActionTimeout),
+		jobs:       make(chan listenerJob, listenerQueueSize),
+		hooks:      hooks,
+		byCategory: make(map[string][]*activeListener),
+		stats:      make(map[string]*database.ListenerStats),
+		orgs:       make(map[string]string),
+	}
+}
+
+// Run은 컨텍스트가 끝날 때까지 리스너를 실행합니다
+func (r *ListenerRuntime) Run(ctx context.Context) error {
+	r.reload()
+
+	var subs []*nats.Subscription
+	for _, table := range []string{"ts_obs", "target_categories"} {
+		sub, err := r.nc.Subscribe(busconsumer.ChangeSubject(table, "*"), r.handleChange)
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to %s changes: %w", table, err)
+		}
+		subs = append(subs, sub)
+	}
+	defer func() {
+		for _, sub := range subs {
+			sub.Unsubscribe()
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < listenerWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.worker(ctx)
+		}()
+	}
+
+	log.Println("👂 Listener runtime started")
+
+	reload := time.NewTicker(listenerReloadInterval)
+	defer reload.Stop()
+	flush := time.NewTicker(listenerStatsInterval)
+	defer flush.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			r.flushStats()
+			log.Println("🛑 Listener runtime stopped")
+			return nil
+		case <-reload.C:
+			r.reload()
+		case <-flush.C:
+			r.flushStats()
+		}
+	}
+}
+
+// reload는 활성 리스너를 다시 읽습니다. 잘못된 정의는 건너뜁니다.
+func (r *ListenerRuntime) reload() {
+	rows, err := database.ListActiveListeners(r.db)
+	if err != nil {
+		log.Printf("❌ Listener runtime: failed to load listeners: %v", err)
+		return
+	}
+
+	byCategory := make(map[string][]*activeListener)
+	for _, l := range rows {
+		conditions, actions, err := listener.Parse(l.Conditions, l.Actions)
+		if err == nil {
+			err = listener.Validate(l.CategoryName, conditions, actions)
+		}
+		if err != nil {
+			r.recordError(l.ListenerID, fmt.Errorf("invalid definition: %w", err))
+			continue
+		}
+		byCategory[l.CategoryName] = append(byCategory[l.CategoryName], &activeListener{Listener: l, conditions: conditions, actions: actions})
+	}
+
+	r.mu.Lock()
+	r.byCategory = byCategory
+	r.mu.Unlock()
+}
+
+// handleChange는 변경 이벤트를 해당 카테고리의 리스너로 평가합니다
+func (r *ListenerRuntime) handleChange(msg *nats.Msg) {
+	var event database.ChangeEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		log.Printf("⚠️ Listener runtime: invalid change event: %v", err)
+		return
+	}
+	if event.Operation == "delete" || event.Category == "" {
+		return
+	}
+
+	r.mu.RLock()
+	candidates := r.byCategory[event.Category]
+	r.mu.RUnlock()
+	if len(candidates) == 0 {
+		return
+	}
+
+	data, ts := changeData(event)
+	if data == nil {
+		return
+	}
+	if _, fromListener := data[listener.SourceMarker]; fromListener {
+		return // 리스너 액션이 쓴 데이터는 다시 평가하지 않음
+	}
+
+	orgID := event.OrgID
+	if orgID == "" {
+		orgID = r.resolveOrg(event.TargetID, event.Category)
+	}
+
+	for _, l := range candidates {
+		if l.OrgID != "" && orgID != "" && l.OrgID != orgID {
+			continue
+		}
+
+		matched := listener.Match(l.conditions, data)
+		r.recordEvaluation(l.ListenerID, matched)
+		if !matched {
+			continue
+		}
+
+		job := listenerJob{listener: l, trigger: listener.Trigger{
+			ListenerID: l.ListenerID,
+			OrgID:      orgID,
+			Category:   event.Category,
+			TargetID:   event.TargetID,
+			Source:     event.Table,
+			Operation:  event.Operation,
+			EventID:    event.EventID,
+			Ts:         ts,
+			Data:       data,
+			FiredAt:    time.Now(),
+		}}
+		select {
+		case r.jobs <- job:
+		default:
+			r.recordActions(l.ListenerID, 0, len(l.actions), fmt.Errorf("action queue full, trigger dropped"))
+		}
+	}
+}
+
+// changeData는 변경된 행에서 평가할 데이터와 시각을 추출합니다
+// (ts_obs는 payload, target_categories는 category_data).
+func changeData(event database.ChangeEvent) (map[string]interface{}, time.Time) {
+	var row struct {
+		Ts           time.Time              `json:"ts"`
+		UpdatedAt    time.Time              `json:"updated_at"`
+		Payload      map[string]interface{} `json:"payload"`
+		CategoryData map[string]interface{} `json:"category_data"`
+	}
+	if len(event.Row) == 0 || json.Unmarshal(event.Row, &row) != nil {
+		return nil, time.Time{}
+	}
+
+	if event.Table == "ts_obs" {
+		return row.Payload, row.Ts
+	}
+	ts := row.UpdatedAt
+	if ts.IsZero() {
+		ts = event.ChangedAt
+	}
+	return row.CategoryData, ts
+}
+
+// resolveOrg는 타겟-카테고리 연결에서 조직을 찾습니다 (ts_obs 이벤트에는 조직이 없음)
+func (r *ListenerRuntime) resolveOrg(targetID, category string) string {
+	key := category + "/" + targetID
+	r.orgMu.Lock()
+	orgID, ok := r.orgs[key]
+	r.orgMu.Unlock()
+	if ok {
+		return orgID
+	}
+
+	link, err := database.GetTargetCategoryLink(r.db, targetID, category)
+	if err != nil {
+		return ""
+	}
+
+	r.orgMu.Lock()
+	if len(r.orgs) >= listenerOrgCacheSize {
+		r.orgs = make(map[string]string)
+	}
+	r.orgs[key] = link.OrgID
+	r.orgMu.Unlock()
+	return link.OrgID
+}
+
+// worker는 대기열의 리스너 액션을 실행합니다
+func (r *ListenerRuntime) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-r.jobs:
+			succeeded, failed := 0, 0
+			var lastErr error
+			for _, action := range job.listener.actions {
+				if err := r.execute(ctx, job.listener, action, job.trigger); err != nil {
+					failed++
+					lastErr = fmt.Errorf("%s action failed: %w", action.Type, err)
+					log.Printf("❌ Listener %s: %v", job.listener.ListenerID, lastErr)
+					continue
+				}
+				succeeded++
+			}
+			r.recordActions(job.listener.ListenerID, succeeded, failed, lastErr)
+			if r.hooks != nil {
+				r.hooks.ListenerMatched(job.trigger)
+			}
+		}
+	}
+}
+
+// execute는 액션 하나를 실행합니다
+func (r *ListenerRuntime) execute(ctx context.Context, l *activeListener, action listener.Action, trigger listener.Trigger) error {
+	switch action.Type {
+	case listener.ActionWebhook:
+		body, err := json.Marshal(trigger)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, action.URL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for key, value := range action.Headers {
+			req.Header.Set(key, value)
+		}
+		resp, err := r.client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode/100 != 2 {
+			return fmt.Errorf("webhook returned %s", resp.Status)
+		}
+		return nil
+
+	case listener.ActionNATS:
+		body, err := json.Marshal(trigger)
+		if err != nil {
+			return err
+		}
+		return r.nc.Publish(action.NATSSubject(l.ListenerID), body)
+
+	case listener.ActionCategory:
+		payload := make(map[string]interface{})
+		if len(action.Fields) == 0 {
+			for k, v := range trigger.Data {
+				payload[k] = v
+			}
+		}
+		for _, field := range action.Fields {
+			if v, ok := listener.Lookup(trigger.Data, field); ok {
+				payload[field] = v
+			}
+		}
+		payload[listener.SourceMarker] = l.ListenerID
+		payloadJSON, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		return database.InsertTimeSeriesPoint(r.db, trigger.TargetID, action.Category, trigger.Ts, string(payloadJSON))
+	}
+	return fmt.Errorf("unknown action type %q", action.Type)
+}
+
+// statsFor는 리스너의 통계 증가분을 반환합니다 (statsMu를 잡은 상태에서 호출)
+func (r *ListenerRuntime) statsFor(listenerID string) *database.ListenerStats {
+	st, ok := r.stats[listenerID]
+	if !ok {
+		st = &database.ListenerStats{ListenerID: listenerID}
+		r.stats[listenerID] = st
+	}
+	return st
+}
+
+func (r *ListenerRuntime) recordEvaluation(listenerID string, matched bool) {
+	r.statsMu.Lock()
+	defer r.statsMu.Unlock()
+	st := r.statsFor(listenerID)
+	st.Evaluated++
+	if matched {
+		now := time.Now()
+		st.Matched++
+		st.LastMatchedAt = &now
+	}
+}
+
+func (r *ListenerRuntime) recordActions(listenerID string, succeeded, failed int, err error) {
+	r.statsMu.Lock()
+	defer r.statsMu.Unlock()
+	st := r.statsFor(listenerID)
+	st.ActionsSucceeded += int64(succeeded)
+	st.ActionsFailed += int64(failed)
+	if err != nil {
+		now := time.Now()
+		st.LastError = err.Error()
+		st.LastErrorAt = &now
+	}
+}
+
+func (r *ListenerRuntime) recordError(listenerID string, err error) {
+	r.recordActions(listenerID, 0, 0, err)
+}
+
+// flushStats는 모인 통계 증가분을 DB에 누적합니다
+func (r *ListenerRuntime) flushStats() {
+	r.statsMu.Lock()
+	pending := r.stats
+	r.stats = make(map[string]*database.ListenerStats)
+	r.statsMu.Unlock()
+
+	for _, st := range pending {
+		if err := database.AddListenerStats(r.db, *st); err != nil {
+			log.Printf("❌ Listener runtime: failed to save stats for %s: %v", st.ListenerID, err)
+		}
+	}
+}
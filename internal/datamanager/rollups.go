@@ -0,0 +1,115 @@
+package datamanager
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+)
+
+// 롤업 실행 설정
+const (
+	rollupTick        = time.Minute
+	rollupMinInterval = time.Minute
+	rollupMaxInterval = time.Hour
+)
+
+// RollupRunner는 롤업 작업을 주기적으로 실행해 ts_obs를 해상도별 요약으로 쌓습니다.
+// 해상도마다 해상도의 1/12 간격(1분~1시간)으로 실행하고, 과거 데이터를 집계하는 중이면 매 틱 이어서 실행합니다.
+// 같은 해상도는 advisory lock으로 한 인스턴스만 집계합니다.
+type RollupRunner struct {
+	db *sql.DB
+}
+
+// NewRollupRunner는 롤업 실행기를 생성합니다
+func NewRollupRunner(db *sql.DB) *RollupRunner {
+	return &RollupRunner{db: db}
+}
+
+// Run은 컨텍스트가 끝날 때까지 차례가 된 롤업을 실행합니다
+func (r *RollupRunner) Run(ctx context.Context) {
+	log.Println("📉 Rollup runner started")
+
+	ticker := time.NewTicker(rollupTick)
+	defer ticker.Stop()
+	for {
+		r.runDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("🛑 Rollup runner stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *RollupRunner) runDue(ctx context.Context) {
+	jobs, err := database.ListRollupJobs(r.db)
+	if err != nil {
+		log.Printf("❌ Rollups: failed to load jobs: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for i := range jobs {
+		job := &jobs[i]
+		if !job.IsActive {
+			continue
+		}
+		fields, err := database.RollupFields(r.db, job)
+		if err != nil {
+			log.Printf("❌ Rollups: %s: failed to resolve fields: %v", job.Category, err)
+			continue
+		}
+
+		for _, resolution := range job.Resolutions {
+			if ctx.Err() != nil {
+				return
+			}
+			if !rollupDue(job.Progress, resolution, fields, now) {
+				continue
+			}
+			p, err := database.RunRollup(r.db, job.Category, resolution, fields, now)
+			if err != nil {
+				log.Printf("❌ Rollups: %s every %s failed: %v", job.Category, resolution, err)
+				if err := database.RecordRollupError(r.db, job.Category, resolution, err, now); err != nil {
+					log.Printf("❌ Rollups: failed to record error: %v", err)
+				}
+				continue
+			}
+			if p != nil && p.LastRows > 0 {
+				log.Printf("📉 Rollups: %s every %s: %d rows up to %s", job.Category, resolution, p.LastRows,
+					p.RolledUpTo.Format(time.RFC3339))
+			}
+		}
+	}
+}
+
+// rollupDue는 해상도를 지금 실행할 차례인지 봅니다 (처음, 필드가 바뀜, 새 구간이 시작됐거나 과거 데이터 집계 중, 실행 간격이 지남)
+func rollupDue(progress []database.RollupProgress, resolution string, fields []string, now time.Time) bool {
+	size, err := database.IntervalDuration(resolution)
+	if err != nil {
+		return false
+	}
+	for _, p := range progress {
+		if p.Resolution != resolution {
+			continue
+		}
+		if !slices.Equal(p.Fields, fields) || p.RolledUpTo.Add(size).Before(now) {
+			return true
+		}
+		interval := size / 12
+		if interval < rollupMinInterval {
+			interval = rollupMinInterval
+		}
+		if interval > rollupMaxInterval {
+			interval = rollupMaxInterval
+		}
+		return !now.Before(p.LastRunAt.Add(interval))
+	}
+	return true
+}
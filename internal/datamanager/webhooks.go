@@ -0,0 +1,327 @@
+package datamanager
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/tmidb/tmidb-core/internal/busconsumer"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/listener"
+	"github.com/tmidb/tmidb-core/internal/schema"
+	"github.com/tmidb/tmidb-core/internal/webhook"
+)
+
+// webhook 디스패처 설정
+const (
+	webhookReloadInterval = 15 * time.Second // webhook 등록/변경 반영 주기
+	webhookPollInterval   = 5 * time.Second  // 재시도 차례가 된 기록 확인 주기
+	webhookPurgeInterval  = time.Hour
+	webhookRetention      = 7 * 24 * time.Hour // 끝난 전송 기록을 남겨 두는 기간
+	webhookBatchSize      = 50
+	webhookWorkers        = 8
+	webhookTimeout        = 10 * time.Second
+	webhookLease          = time.Minute // 가져간 기록을 다른 디스패처가 다시 가져가지 않는 시간
+	webhookMaxResponse    = 1024        // 기록에 남기는 응답 본문 크기
+)
+
+// WebhookDispatcher는 데이터 변경 이벤트를 조직 webhook의 전송 기록으로 적재하고,
+// 적재된 기록을 HMAC 서명과 함께 보내며 실패하면 지수 백오프로 재시도합니다.
+//
+// 이벤트는 target_categories 변경(target.created/updated/deleted), 스키마 변경 알림(schema.published),
+// 리스너 런타임의 조건 일치(listener.matched)에서 옵니다. 전송 기록이 DB에 있으므로
+// data-manager가 재시작해도 재시도가 이어지고, 여러 인스턴스가 나눠서 보낼 수 있습니다.
+type WebhookDispatcher struct {
+	db     *sql.DB
+	nc     *nats.Conn
+	client *http.Client
+	wake   chan struct{}
+
+	mu   sync.RWMutex
+	subs map[string]map[string]bool // org_id -> 구독 중인 이벤트 종류
+}
+
+// NewWebhookDispatcher는 webhook 디스패처를 생성합니다
+func NewWebhookDispatcher(db *sql.DB, nc *nats.Conn) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		db:     db,
+		nc:     nc,
+		client: webhook.NewClient(webhookTimeout),
+		wake:   make(chan struct{}, 1),
+		subs:   make(map[string]map[string]bool),
+	}
+}
+
+// Run은 컨텍스트가 끝날 때까지 이벤트를 적재하고 전송합니다
+func (d *WebhookDispatcher) Run(ctx context.Context) error {
+	d.reload()
+
+	var subs []*nats.Subscription
+	sub, err := d.nc.Subscribe(busconsumer.ChangeSubject("target_categories", "*"), d.handleTargetChange)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to target changes: %w", err)
+	}
+	subs = append(subs, sub)
+	sub, err = d.nc.Subscribe(schema.ChangeSubject, d.handleSchemaChange)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to schema changes: %w", err)
+	}
+	subs = append(subs, sub)
+	defer func() {
+		for _, sub := range subs {
+			sub.Unsubscribe()
+		}
+	}()
+
+	log.Println("🪝 Webhook dispatcher started")
+
+	reload := time.NewTicker(webhookReloadInterval)
+	defer reload.Stop()
+	poll := time.NewTicker(webhookPollInterval)
+	defer poll.Stop()
+	purge := time.NewTicker(webhookPurgeInterval)
+	defer purge.Stop()
+
+	for {
+		d.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("🛑 Webhook dispatcher stopped")
+			return nil
+		case <-d.wake:
+		case <-poll.C:
+		case <-reload.C:
+			d.reload()
+		case <-purge.C:
+			if n, err := database.PurgeWebhookDeliveries(d.db, webhookRetention); err != nil {
+				log.Printf("❌ Webhooks: failed to purge delivery log: %v", err)
+			} else if n > 0 {
+				log.Printf("🧹 Webhooks: purged %d finished deliveries", n)
+			}
+		}
+	}
+}
+
+// reload는 조직별로 구독 중인 이벤트 종류를 다시 읽습니다 (구독자가 없는 이벤트는 적재하지 않음)
+func (d *WebhookDispatcher) reload() {
+	hooks, err := database.ListActiveWebhooks(d.db)
+	if err != nil {
+		log.Printf("❌ Webhooks: failed to load webhooks: %v", err)
+		return
+	}
+
+	subs := make(map[string]map[string]bool)
+	for _, w := range hooks {
+		if subs[w.OrgID] == nil {
+			subs[w.OrgID] = make(map[string]bool)
+		}
+		for _, event := range w.EventTypes {
+			subs[w.OrgID][event] = true
+		}
+	}
+
+	d.mu.Lock()
+	d.subs = subs
+	d.mu.Unlock()
+}
+
+func (d *WebhookDispatcher) subscribed(orgID, event string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.subs[orgID][event]
+}
+
+// enqueue는 구독 중인 조직이면 이벤트를 전송 기록으로 적재합니다
+func (d *WebhookDispatcher) enqueue(env webhook.Envelope) {
+	if env.OrgID == "" || !d.subscribed(env.OrgID, env.Event) {
+		return
+	}
+	n, err := database.EnqueueWebhookEvent(d.db, env)
+	if err != nil {
+		log.Printf("❌ Webhooks: failed to enqueue %s (%s): %v", env.Event, env.ID, err)
+		return
+	}
+	if n > 0 {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// handleTargetChange는 타겟-카테고리 연결의 변경을 target.created/updated/deleted 이벤트로 적재합니다.
+// 휴지통으로 보낸 연결(deleted_at이 채워진 갱신)도 target.deleted입니다.
+func (d *WebhookDispatcher) handleTargetChange(msg *nats.Msg) {
+	var event database.ChangeEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		log.Printf("⚠️ Webhooks: invalid change event: %v", err)
+		return
+	}
+
+	var eventType string
+	switch event.Operation {
+	case "insert":
+		eventType = webhook.EventTargetCreated
+	case "update":
+		eventType = webhook.EventTargetUpdated
+		var row struct {
+			DeletedAt *string `json:"deleted_at"`
+		}
+		if json.Unmarshal(event.Row, &row) == nil && row.DeletedAt != nil {
+			eventType = webhook.EventTargetDeleted
+		}
+	case "delete":
+		eventType = webhook.EventTargetDeleted
+	default:
+		return
+	}
+
+	d.enqueue(webhook.Envelope{
+		ID:         "cdc:" + strconv.FormatInt(event.EventID, 10),
+		Event:      eventType,
+		OrgID:      event.OrgID,
+		OccurredAt: event.ChangedAt,
+		Data:       event.Row,
+	})
+}
+
+// handleSchemaChange는 스키마 생성/갱신 알림을 schema.published 이벤트로 적재합니다.
+// 조직이 없는 알림은 그 카테고리를 가진 모든 조직에 적재합니다.
+func (d *WebhookDispatcher) handleSchemaChange(msg *nats.Msg) {
+	var change schema.Change
+	if err := json.Unmarshal(msg.Data, &change); err != nil {
+		log.Printf("⚠️ Webhooks: invalid schema change event: %v", err)
+		return
+	}
+	if change.Operation == schema.ChangeDelete || change.Version == 0 {
+		return
+	}
+
+	orgs := []string{change.OrgID}
+	if change.OrgID == "" {
+		var err error
+		if orgs, err = database.CategoryOrgs(d.db, change.Category); err != nil {
+			log.Printf("❌ Webhooks: failed to resolve organizations of %s: %v", change.Category, err)
+			return
+		}
+	}
+
+	now := time.Now()
+	for _, orgID := range orgs {
+		change.OrgID = orgID
+		data, _ := json.Marshal(change)
+		d.enqueue(webhook.Envelope{
+			ID:         fmt.Sprintf("schema:%s:%d", change.Category, change.Version),
+			Event:      webhook.EventSchemaPublished,
+			OrgID:      orgID,
+			OccurredAt: now,
+			Data:       data,
+		})
+	}
+}
+
+// ListenerMatched는 리스너 조건 일치를 listener.matched 이벤트로 적재합니다 (리스너 런타임이 호출)
+func (d *WebhookDispatcher) ListenerMatched(trigger listener.Trigger) {
+	data, err := json.Marshal(trigger)
+	if err != nil {
+		return
+	}
+	d.enqueue(webhook.Envelope{
+		ID:         fmt.Sprintf("listener:%s:%d", trigger.ListenerID, trigger.EventID),
+		Event:      webhook.EventListenerMatched,
+		OrgID:      trigger.OrgID,
+		OccurredAt: trigger.FiredAt,
+		Data:       data,
+	})
+}
+
+// deliverDue는 차례가 된 기록이 없을 때까지 배치 단위로 전송합니다
+func (d *WebhookDispatcher) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		due, err := database.ClaimWebhookDeliveries(d.db, webhookBatchSize, webhookLease)
+		if err != nil {
+			log.Printf("❌ Webhooks: failed to claim deliveries: %v", err)
+			return
+		}
+
+		sem := make(chan struct{}, webhookWorkers)
+		var wg sync.WaitGroup
+		for _, delivery := range due {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(delivery database.DueWebhookDelivery) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				result := d.send(ctx, delivery)
+				if err := database.FinishWebhookDelivery(d.db, delivery.DeliveryID, result); err != nil {
+					log.Printf("❌ Webhooks: failed to record delivery %d: %v", delivery.DeliveryID, err)
+				}
+			}(delivery)
+		}
+		wg.Wait()
+
+		if len(due) < webhookBatchSize {
+			return
+		}
+	}
+}
+
+// send는 전송 기록 하나를 보내고 결과(성공, 재시도, 포기)를 반환합니다
+func (d *WebhookDispatcher) send(ctx context.Context, delivery database.DueWebhookDelivery) database.WebhookResult {
+	start := time.Now()
+	result := database.WebhookResult{Status: webhook.StatusDelivered}
+
+	err := func() error {
+		// 검사가 생기기 전에 등록된 주소도 보내기 전에 다시 확인
+		if err := webhook.ValidateURL(delivery.URL); err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "tmidb-webhook")
+		req.Header.Set(webhook.HeaderEvent, delivery.EventType)
+		req.Header.Set(webhook.HeaderDelivery, strconv.FormatInt(delivery.DeliveryID, 10))
+		req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(start.Unix(), 10))
+		req.Header.Set(webhook.HeaderSignature, webhook.Sign(delivery.Secret, start, delivery.Payload))
+
+		resp, err := d.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponse))
+		result.StatusCode = resp.StatusCode
+		result.Response = string(bytes.ToValidUTF8(body, nil))
+		if resp.StatusCode/100 != 2 {
+			return fmt.Errorf("webhook returned %s", resp.Status)
+		}
+		return nil
+	}()
+	result.Duration = time.Since(start)
+	if err == nil {
+		return result
+	}
+
+	result.Error = err.Error()
+	if delivery.Attempts >= webhook.MaxAttempts {
+		result.Status = webhook.StatusFailed
+		log.Printf("❌ Webhook %s: delivery %d failed after %d attempts: %v", delivery.WebhookID, delivery.DeliveryID, delivery.Attempts, err)
+	} else {
+		result.Status = webhook.StatusPending
+		result.RetryAfter = webhook.Backoff(delivery.Attempts)
+	}
+	return result
+}
@@ -0,0 +1,111 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// deregisterCriticalAfter 이 시간 동안 critical이면 Consul이 서비스를 지움 (슈퍼바이저가 사라진 노드 정리)
+const deregisterCriticalAfter = 30 * time.Minute
+
+// consulRegistry 로컬 Consul 에이전트 HTTP API (/v1/agent)
+type consulRegistry struct {
+	address string
+	token   string
+	client  *http.Client
+}
+
+type consulCheck struct {
+	CheckID                        string `json:"CheckID"`
+	Name                           string `json:"Name"`
+	TTL                            string `json:"TTL"`
+	DeregisterCriticalServiceAfter string `json:"DeregisterCriticalServiceAfter"`
+}
+
+type consulService struct {
+	ID      string            `json:"ID"`
+	Name    string            `json:"Name"`
+	Address string            `json:"Address"`
+	Port    int               `json:"Port"`
+	Tags    []string          `json:"Tags,omitempty"`
+	Meta    map[string]string `json:"Meta,omitempty"`
+	Check   consulCheck       `json:"Check"`
+}
+
+// checkID 서비스의 TTL 체크 ID
+func consulCheckID(svc Service) string {
+	return "service:" + svc.ID
+}
+
+func (r *consulRegistry) Register(ctx context.Context, svc Service, ttl time.Duration) error {
+	return r.put(ctx, "/v1/agent/service/register", consulService{
+		ID:      svc.ID,
+		Name:    svc.Name,
+		Address: svc.Address,
+		Port:    svc.Port,
+		Tags:    svc.Tags,
+		Meta:    svc.Meta,
+		Check: consulCheck{
+			CheckID:                        consulCheckID(svc),
+			Name:                           svc.Name + " supervisor health",
+			TTL:                            ttl.String(),
+			DeregisterCriticalServiceAfter: deregisterCriticalAfter.String(),
+		},
+	})
+}
+
+func (r *consulRegistry) Heartbeat(ctx context.Context, check Check) error {
+	err := r.put(ctx, "/v1/agent/check/update/"+url.PathEscape(consulCheckID(check.Service)), map[string]string{
+		"Status": string(check.Status),
+		"Output": check.Output,
+	})
+	// 에이전트 버전에 따라 404 또는 500 ("Unknown check", "does not have associated TTL")
+	if err != nil && (strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "Unknown check") ||
+		strings.Contains(err.Error(), "does not have associated TTL")) {
+		return fmt.Errorf("%w: %v", ErrNotRegistered, err)
+	}
+	return err
+}
+
+func (r *consulRegistry) Deregister(ctx context.Context, svc Service) error {
+	return r.put(ctx, "/v1/agent/service/deregister/"+url.PathEscape(svc.ID), nil)
+}
+
+// put 에이전트 API 호출 (2xx가 아니면 응답 본문을 오류에 포함)
+func (r *consulRegistry) put(ctx context.Context, path string, body interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, r.address+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.token != "" {
+		req.Header.Set("X-Consul-Token", r.token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("consul %s returned %s: %s", path, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+// Package discovery 서비스 디스커버리 저장소(Consul, etcd)에 tmiDB 엔드포인트를 등록하고,
+// 슈퍼바이저의 헬스 점검 주기마다 TTL을 갱신합니다. 슈퍼바이저가 멈추면 갱신도 멈추므로
+// 저장소가 TTL 만료로 엔드포인트를 정상 아님으로 표시합니다.
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTTL 갱신이 없을 때 저장소가 엔드포인트를 정상 아님으로 보는 시간
+	DefaultTTL = 30 * time.Second
+
+	// defaultPrefix etcd 키 접두사 (<prefix>/<service>/<id>)
+	defaultPrefix = "/tmidb/services"
+
+	// requestTimeout 저장소 요청 한 번의 제한 시간
+	requestTimeout = 5 * time.Second
+)
+
+// ErrNotRegistered 저장소가 서비스를 모름 (에이전트 재시작, 리스 만료 등). 다시 등록해야 함
+var ErrNotRegistered = errors.New("service is not registered")
+
+// Status 헬스 상태 (Consul 체크 상태와 같은 값)
+type Status string
+
+const (
+	StatusPassing  Status = "passing"
+	StatusWarning  Status = "warning"
+	StatusCritical Status = "critical"
+)
+
+// Config 디스커버리 설정
+type Config struct {
+	Type     string        `json:"type"`               // consul, etcd
+	Address  string        `json:"address"`            // http://127.0.0.1:8500 (consul), http://127.0.0.1:2379 (etcd)
+	Token    string        `json:"token,omitempty"`    // consul ACL 토큰
+	Username string        `json:"username,omitempty"` // etcd 인증
+	Password string        `json:"password,omitempty"`
+	Prefix   string        `json:"prefix,omitempty"` // etcd 키 접두사 (기본 /tmidb/services)
+	TTL      time.Duration `json:"ttl"`              // 0이면 DefaultTTL
+
+	// 엔드포인트로 알릴 주소 (비어 있으면 루프백이 아닌 첫 IPv4 주소)
+	ServiceAddress string `json:"service_address,omitempty"`
+	// 등록할 서비스 (비어 있으면 api, nats, postgresql 모두)
+	Services []string `json:"services,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// configJSON ttl을 "30s" 형식 문자열로 직렬화
+type configJSON struct {
+	*configAlias
+	TTL string `json:"ttl,omitempty"`
+}
+
+type configAlias Config
+
+// MarshalJSON encodes the TTL as a string
+func (c Config) MarshalJSON() ([]byte, error) {
+	aux := configJSON{configAlias: (*configAlias)(&c)}
+	if c.TTL > 0 {
+		aux.TTL = c.TTL.String()
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON decodes the TTL from a string
+func (c *Config) UnmarshalJSON(data []byte) error {
+	aux := configJSON{configAlias: (*configAlias)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.TTL != "" {
+		ttl, err := time.ParseDuration(aux.TTL)
+		if err != nil {
+			return fmt.Errorf("invalid discovery ttl: %w", err)
+		}
+		c.TTL = ttl
+	}
+	return nil
+}
+
+// Validate 설정 검증
+func (c Config) Validate() error {
+	switch c.Type {
+	case "consul", "etcd":
+	default:
+		return fmt.Errorf("unknown discovery type: %q (use consul or etcd)", c.Type)
+	}
+	if !strings.HasPrefix(c.Address, "http://") && !strings.HasPrefix(c.Address, "https://") {
+		return fmt.Errorf("%s discovery requires an http(s) address", c.Type)
+	}
+	if c.TTL < 0 || (c.TTL > 0 && c.TTL < time.Second) {
+		return fmt.Errorf("discovery ttl must be at least 1s, got %s", c.TTL)
+	}
+	return nil
+}
+
+// Service 저장소에 등록하는 엔드포인트
+type Service struct {
+	ID      string            `json:"id"`   // 저장소 안에서 고유 (예: tmidb-api-node1)
+	Name    string            `json:"name"` // 같은 종류의 엔드포인트가 공유 (예: tmidb-api)
+	Address string            `json:"address"`
+	Port    int               `json:"port"`
+	Tags    []string          `json:"tags,omitempty"`
+	Meta    map[string]string `json:"meta,omitempty"`
+}
+
+// Check 엔드포인트 하나의 현재 상태
+type Check struct {
+	Service Service
+	Status  Status
+	Output  string
+}
+
+// Registry 서비스 디스커버리 저장소
+type Registry interface {
+	// Register 서비스를 ttl 동안 갱신이 없으면 정상 아님으로 보도록 등록
+	Register(ctx context.Context, svc Service, ttl time.Duration) error
+	// Heartbeat 상태를 알리고 TTL을 갱신. 저장소가 서비스를 모르면 ErrNotRegistered
+	Heartbeat(ctx context.Context, check Check) error
+	// Deregister 서비스 등록 해제
+	Deregister(ctx context.Context, svc Service) error
+}
+
+// New 설정에 맞는 저장소 클라이언트 생성
+func New(config Config) (Registry, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: requestTimeout}
+	address := strings.TrimRight(config.Address, "/")
+
+	switch config.Type {
+	case "consul":
+		return &consulRegistry{address: address, token: config.Token, client: client}, nil
+	default:
+		prefix := config.Prefix
+		if prefix == "" {
+			prefix = defaultPrefix
+		}
+		return &etcdRegistry{
+			address:  address,
+			prefix:   strings.TrimRight(prefix, "/"),
+			username: config.Username,
+			password: config.Password,
+			client:   client,
+			leases:   make(map[string]string),
+		}, nil
+	}
+}
+
+// Registrar 엔드포인트 목록을 등록하고 Sync마다 상태를 저장소에 반영
+type Registrar struct {
+	registry Registry
+	ttl      time.Duration
+
+	mu         sync.Mutex
+	registered map[string]Service
+}
+
+// NewRegistrar ttl이 0이면 DefaultTTL
+func NewRegistrar(registry Registry, ttl time.Duration) *Registrar {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Registrar{registry: registry, ttl: ttl, registered: make(map[string]Service)}
+}
+
+// Sync 처음 보거나 저장소에서 사라진 서비스는 등록한 뒤 상태를 알리고,
+// checks에서 빠진 서비스는 등록 해제합니다. 서비스 하나의 실패는 나머지를 막지 않습니다
+func (r *Registrar) Sync(ctx context.Context, checks []Check) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	seen := make(map[string]bool, len(checks))
+	for _, check := range checks {
+		seen[check.Service.ID] = true
+		if err := r.sync(ctx, check); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", check.Service.ID, err))
+		}
+	}
+
+	for id, svc := range r.registered {
+		if seen[id] {
+			continue
+		}
+		if err := r.registry.Deregister(ctx, svc); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", id, err))
+			continue
+		}
+		delete(r.registered, id)
+	}
+	return errors.Join(errs...)
+}
+
+// sync 서비스 하나 (호출자가 mu 보유)
+func (r *Registrar) sync(ctx context.Context, check Check) error {
+	id := check.Service.ID
+	if prev, ok := r.registered[id]; !ok || !sameService(prev, check.Service) {
+		if err := r.registry.Register(ctx, check.Service, r.ttl); err != nil {
+			return err
+		}
+		r.registered[id] = check.Service
+		log.Printf("🧭 Registered %s (%s:%d) for service discovery", id, check.Service.Address, check.Service.Port)
+	}
+
+	err := r.registry.Heartbeat(ctx, check)
+	if errors.Is(err, ErrNotRegistered) {
+		// 저장소가 재시작되었거나 리스가 만료됨
+		log.Printf("🧭 %s was dropped by the discovery registry, registering it again", id)
+		if err := r.registry.Register(ctx, check.Service, r.ttl); err != nil {
+			delete(r.registered, id)
+			return err
+		}
+		err = r.registry.Heartbeat(ctx, check)
+	}
+	return err
+}
+
+// Close 등록한 서비스를 모두 해제
+func (r *Registrar) Close(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	for id, svc := range r.registered {
+		if err := r.registry.Deregister(ctx, svc); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", id, err))
+		}
+		delete(r.registered, id)
+	}
+	return errors.Join(errs...)
+}
+
+func sameService(a, b Service) bool {
+	if a.Name != b.Name || a.Address != b.Address || a.Port != b.Port || len(a.Meta) != len(b.Meta) ||
+		strings.Join(a.Tags, "\x00") != strings.Join(b.Tags, "\x00") {
+		return false
+	}
+	for k, v := range a.Meta {
+		if b.Meta[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// AdvertiseAddress 루프백이 아닌 첫 IPv4 주소 (없으면 127.0.0.1)
+func AdvertiseAddress() string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "127.0.0.1"
+	}
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok && !ipnet.IP.IsLoopback() && ipnet.IP.To4() != nil {
+			return ipnet.IP.String()
+		}
+	}
+	return "127.0.0.1"
+}
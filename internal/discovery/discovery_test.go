@@ -0,0 +1,260 @@
+package discovery
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeConsul 서비스 등록과 TTL 체크 상태만 기억하는 Consul 에이전트
+type fakeConsul struct {
+	mu       sync.Mutex
+	services map[string]consulService
+	status   map[string]string
+	token    string
+}
+
+func newFakeConsul(t *testing.T) (*fakeConsul, *httptest.Server) {
+	f := &fakeConsul{services: make(map[string]consulService), status: make(map[string]string)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.token = r.Header.Get("X-Consul-Token")
+
+		switch {
+		case r.URL.Path == "/v1/agent/service/register":
+			var svc consulService
+			json.NewDecoder(r.Body).Decode(&svc)
+			f.services[svc.ID] = svc
+			f.status[svc.Check.CheckID] = "critical"
+		case strings.HasPrefix(r.URL.Path, "/v1/agent/check/update/"):
+			id := strings.TrimPrefix(r.URL.Path, "/v1/agent/check/update/")
+			if _, ok := f.status[id]; !ok {
+				http.Error(w, `Unknown check ID "`+id+`"`, http.StatusNotFound)
+				return
+			}
+			var update map[string]string
+			json.NewDecoder(r.Body).Decode(&update)
+			f.status[id] = update["Status"]
+		case strings.HasPrefix(r.URL.Path, "/v1/agent/service/deregister/"):
+			id := strings.TrimPrefix(r.URL.Path, "/v1/agent/service/deregister/")
+			delete(f.services, id)
+			delete(f.status, "service:"+id)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeConsul) checkStatus(id string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.status["service:"+id]
+}
+
+func testCheck(id string, status Status) Check {
+	return Check{
+		Service: Service{ID: id, Name: "tmidb-api", Address: "10.0.0.5", Port: 8020, Tags: []string{"tmidb"}},
+		Status:  status,
+		Output:  "api is " + string(status),
+	}
+}
+
+func TestConsulRegistrar(t *testing.T) {
+	consul, srv := newFakeConsul(t)
+	registry, err := New(Config{Type: "consul", Address: srv.URL, Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRegistrar(registry, 20*time.Second)
+	ctx := context.Background()
+
+	if err := r.Sync(ctx, []Check{testCheck("tmidb-api-node1", StatusPassing)}); err != nil {
+		t.Fatal(err)
+	}
+	svc := consul.services["tmidb-api-node1"]
+	if svc.Port != 8020 || svc.Check.TTL != "20s" || consul.token != "secret" {
+		t.Errorf("registered %+v with token %q", svc, consul.token)
+	}
+	if got := consul.checkStatus("tmidb-api-node1"); got != "passing" {
+		t.Errorf("status = %q", got)
+	}
+
+	// 에이전트가 재시작되어 서비스를 잊으면 다시 등록
+	consul.mu.Lock()
+	consul.services, consul.status = make(map[string]consulService), make(map[string]string)
+	consul.mu.Unlock()
+	if err := r.Sync(ctx, []Check{testCheck("tmidb-api-node1", StatusCritical)}); err != nil {
+		t.Fatal(err)
+	}
+	if got := consul.checkStatus("tmidb-api-node1"); got != "critical" {
+		t.Errorf("status after agent restart = %q", got)
+	}
+
+	// 목록에서 빠진 서비스는 해제
+	if err := r.Sync(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(consul.services) != 0 {
+		t.Errorf("services after sync = %v", consul.services)
+	}
+}
+
+// fakeEtcd 리스와 키만 기억하는 etcd JSON 게이트웨이
+type fakeEtcd struct {
+	mu     sync.Mutex
+	next   int
+	leases map[string]bool
+	keys   map[string]string // key -> value
+	owner  map[string]string // key -> lease
+	auths  int
+}
+
+func newFakeEtcd(t *testing.T, user string) (*fakeEtcd, *httptest.Server) {
+	f := &fakeEtcd{leases: make(map[string]bool), keys: make(map[string]string), owner: make(map[string]string)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		decode := func(s string) string {
+			b, _ := base64.StdEncoding.DecodeString(s)
+			return string(b)
+		}
+
+		if r.URL.Path == "/v3/auth/authenticate" {
+			f.auths++
+			json.NewEncoder(w).Encode(map[string]string{"token": "token-" + body["name"]})
+			return
+		}
+		if user != "" && r.Header.Get("Authorization") != "token-"+user {
+			http.Error(w, `{"error":"etcdserver: invalid auth token"}`, http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v3/lease/grant":
+			f.next++
+			id := string(rune('0' + f.next))
+			f.leases[id] = true
+			json.NewEncoder(w).Encode(map[string]string{"ID": id, "TTL": body["TTL"]})
+		case "/v3/lease/keepalive":
+			result := map[string]string{"ID": body["ID"]}
+			if f.leases[body["ID"]] {
+				result["TTL"] = "30"
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+		case "/v3/lease/revoke":
+			f.expire(body["ID"])
+			w.Write([]byte("{}"))
+		case "/v3/kv/put":
+			if body["lease"] != "" && !f.leases[body["lease"]] {
+				http.Error(w, `{"error":"etcdserver: requested lease not found"}`, http.StatusNotFound)
+				return
+			}
+			f.keys[decode(body["key"])] = decode(body["value"])
+			f.owner[decode(body["key"])] = body["lease"]
+			w.Write([]byte("{}"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+// expire 리스와 그 키를 지움 (호출자가 mu 보유)
+func (f *fakeEtcd) expire(lease string) {
+	delete(f.leases, lease)
+	for key, owner := range f.owner {
+		if owner == lease {
+			delete(f.keys, key)
+			delete(f.owner, key)
+		}
+	}
+}
+
+func (f *fakeEtcd) entry(t *testing.T, key string) etcdEntry {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var entry etcdEntry
+	if value, ok := f.keys[key]; !ok {
+		t.Fatalf("key %s not found in %v", key, f.keys)
+	} else if err := json.Unmarshal([]byte(value), &entry); err != nil {
+		t.Fatal(err)
+	}
+	return entry
+}
+
+func TestEtcdRegistrar(t *testing.T) {
+	etcd, srv := newFakeEtcd(t, "tmidb")
+	registry, err := New(Config{Type: "etcd", Address: srv.URL, Username: "tmidb", Password: "pw"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRegistrar(registry, 0)
+	ctx := context.Background()
+	key := "/tmidb/services/tmidb-api/tmidb-api-node1"
+
+	if err := r.Sync(ctx, []Check{testCheck("tmidb-api-node1", StatusPassing)}); err != nil {
+		t.Fatal(err)
+	}
+	if entry := etcd.entry(t, key); entry.Status != StatusPassing || entry.Port != 8020 || entry.Address != "10.0.0.5" {
+		t.Errorf("entry = %+v", entry)
+	}
+	if etcd.auths != 1 {
+		t.Errorf("authenticated %d times, want 1", etcd.auths)
+	}
+
+	// 리스가 만료되면 새 리스로 다시 등록
+	etcd.mu.Lock()
+	etcd.expire("1")
+	etcd.mu.Unlock()
+	if err := r.Sync(ctx, []Check{testCheck("tmidb-api-node1", StatusWarning)}); err != nil {
+		t.Fatal(err)
+	}
+	if entry := etcd.entry(t, key); entry.Status != StatusWarning {
+		t.Errorf("entry after lease expiry = %+v", entry)
+	}
+
+	if err := r.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(etcd.keys) != 0 || len(etcd.leases) != 0 {
+		t.Errorf("keys %v, leases %v after close", etcd.keys, etcd.leases)
+	}
+}
+
+func TestConfigJSON(t *testing.T) {
+	var c Config
+	if err := json.Unmarshal([]byte(`{"type":"consul","address":"http://127.0.0.1:8500","ttl":"45s"}`), &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.TTL != 45*time.Second || c.Validate() != nil {
+		t.Errorf("config = %+v", c)
+	}
+	data, _ := json.Marshal(c)
+	if !strings.Contains(string(data), `"ttl":"45s"`) {
+		t.Errorf("marshalled %s", data)
+	}
+
+	for _, bad := range []Config{
+		{Type: "zookeeper", Address: "http://zk:2181"},
+		{Type: "etcd", Address: "127.0.0.1:2379"},
+		{Type: "consul", Address: "http://127.0.0.1:8500", TTL: time.Millisecond},
+	} {
+		if bad.Validate() == nil {
+			t.Errorf("%+v: expected error", bad)
+		}
+	}
+}
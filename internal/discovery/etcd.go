@@ -0,0 +1,208 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errEtcdUnauthenticated 토큰이 만료되어 다시 인증해야 함
+var errEtcdUnauthenticated = errors.New("etcd token expired")
+
+// etcdRegistry etcd v3 JSON 게이트웨이 (/v3). 서비스마다 TTL 리스를 받아
+// <prefix>/<name>/<id> 키에 엔드포인트와 상태를 JSON으로 기록합니다
+type etcdRegistry struct {
+	address  string
+	prefix   string
+	username string
+	password string
+	client   *http.Client
+
+	mu     sync.Mutex
+	token  string
+	leases map[string]string // 서비스 ID -> 리스 ID
+}
+
+// etcdEntry 키에 기록하는 값
+type etcdEntry struct {
+	Service
+	Status    Status    `json:"status"`
+	Output    string    `json:"output,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (r *etcdRegistry) key(svc Service) string {
+	return r.prefix + "/" + svc.Name + "/" + svc.ID
+}
+
+func (r *etcdRegistry) Register(ctx context.Context, svc Service, ttl time.Duration) error {
+	var grant struct {
+		ID    string `json:"ID"`
+		Error string `json:"error"`
+	}
+	if err := r.call(ctx, "/v3/lease/grant", map[string]string{"TTL": strconv.Itoa(int(ttl.Seconds()))}, &grant); err != nil {
+		return err
+	}
+	if grant.ID == "" {
+		return fmt.Errorf("etcd lease grant failed: %s", grant.Error)
+	}
+
+	r.mu.Lock()
+	old := r.leases[svc.ID]
+	r.leases[svc.ID] = grant.ID
+	r.mu.Unlock()
+
+	// 이전 리스는 새 키를 쓴 뒤 해제 (다시 등록하는 동안 키가 사라지지 않게)
+	if err := r.put(ctx, svc, etcdEntry{Service: svc, Status: StatusCritical, Output: "registered", UpdatedAt: time.Now()}); err != nil {
+		return err
+	}
+	if old != "" && old != grant.ID {
+		r.call(ctx, "/v3/lease/revoke", map[string]string{"ID": old}, nil)
+	}
+	return nil
+}
+
+func (r *etcdRegistry) Heartbeat(ctx context.Context, check Check) error {
+	r.mu.Lock()
+	lease := r.leases[check.Service.ID]
+	r.mu.Unlock()
+	if lease == "" {
+		return ErrNotRegistered
+	}
+
+	var keepalive struct {
+		Result struct {
+			TTL string `json:"TTL"`
+		} `json:"result"`
+	}
+	if err := r.call(ctx, "/v3/lease/keepalive", map[string]string{"ID": lease}, &keepalive); err != nil {
+		return err
+	}
+	// 만료된 리스는 TTL 0 (JSON에서는 생략)
+	if ttl, _ := strconv.Atoi(keepalive.Result.TTL); ttl <= 0 {
+		return ErrNotRegistered
+	}
+
+	return r.put(ctx, check.Service, etcdEntry{
+		Service:   check.Service,
+		Status:    check.Status,
+		Output:    check.Output,
+		UpdatedAt: time.Now(),
+	})
+}
+
+func (r *etcdRegistry) Deregister(ctx context.Context, svc Service) error {
+	r.mu.Lock()
+	lease := r.leases[svc.ID]
+	delete(r.leases, svc.ID)
+	r.mu.Unlock()
+
+	// 리스를 해제하면 키도 지워짐
+	if lease != "" {
+		return r.call(ctx, "/v3/lease/revoke", map[string]string{"ID": lease}, nil)
+	}
+	key := base64.StdEncoding.EncodeToString([]byte(r.key(svc)))
+	return r.call(ctx, "/v3/kv/deleterange", map[string]string{"key": key}, nil)
+}
+
+// put 서비스 키를 리스와 함께 기록
+func (r *etcdRegistry) put(ctx context.Context, svc Service, entry etcdEntry) error {
+	r.mu.Lock()
+	lease := r.leases[svc.ID]
+	r.mu.Unlock()
+
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return r.call(ctx, "/v3/kv/put", map[string]string{
+		"key":   base64.StdEncoding.EncodeToString([]byte(r.key(svc))),
+		"value": base64.StdEncoding.EncodeToString(value),
+		"lease": lease,
+	}, nil)
+}
+
+// call 게이트웨이 호출. 인증을 쓰면 토큰을 받아 두고, 만료되면 한 번 다시 받습니다
+func (r *etcdRegistry) call(ctx context.Context, path string, body, result interface{}) error {
+	err := r.do(ctx, path, body, result)
+	if errors.Is(err, errEtcdUnauthenticated) {
+		r.mu.Lock()
+		r.token = ""
+		r.mu.Unlock()
+		err = r.do(ctx, path, body, result)
+	}
+	return err
+}
+
+func (r *etcdRegistry) do(ctx context.Context, path string, body, result interface{}) error {
+	token, err := r.authToken(ctx)
+	if err != nil {
+		return err
+	}
+	return r.post(ctx, path, token, body, result)
+}
+
+// authToken 사용자 이름이 없으면 인증하지 않음
+func (r *etcdRegistry) authToken(ctx context.Context) (string, error) {
+	if r.username == "" {
+		return "", nil
+	}
+	r.mu.Lock()
+	token := r.token
+	r.mu.Unlock()
+	if token != "" {
+		return token, nil
+	}
+
+	var auth struct {
+		Token string `json:"token"`
+	}
+	if err := r.post(ctx, "/v3/auth/authenticate", "", map[string]string{"name": r.username, "password": r.password}, &auth); err != nil {
+		return "", fmt.Errorf("etcd authentication failed: %w", err)
+	}
+	r.mu.Lock()
+	r.token = auth.Token
+	r.mu.Unlock()
+	return auth.Token, nil
+}
+
+func (r *etcdRegistry) post(ctx context.Context, path, token string, body, result interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.address+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if token != "" && (resp.StatusCode == http.StatusUnauthorized || strings.Contains(string(message), "invalid auth token")) {
+			return errEtcdUnauthenticated
+		}
+		return fmt.Errorf("etcd %s returned %s: %s", path, resp.Status, strings.TrimSpace(string(message)))
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,110 @@
+// Package export는 카테고리 문서를 CSV, NDJSON, Parquet 파일로 씁니다.
+// 행을 하나씩 받아 바로 쓰므로 큰 결과도 메모리에 모두 올리지 않고 스트리밍할 수 있습니다.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// 내보내기 형식
+const (
+	FormatCSV     = "csv"
+	FormatNDJSON  = "ndjson"
+	FormatParquet = "parquet"
+)
+
+// Columns는 내보낼 수 있는 최상위 열입니다 (fields 파라미터의 이름과 같음)
+var Columns = []string{"target_id", "category", "version", "created_at", "updated_at", "data"}
+
+// Row는 내보내는 카테고리 문서 한 건입니다. Data는 category_data JSON 그대로입니다.
+type Row struct {
+	TargetID  string
+	Category  string
+	Version   int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Data      json.RawMessage
+}
+
+// Writer는 행을 한 형식으로 씁니다. Close는 남은 내용과 (Parquet이면) 푸터를 쓰고, 밑의 io.Writer는 닫지 않습니다.
+type Writer interface {
+	Write(row *Row) error
+	Close() error
+}
+
+// ValidFormat은 지원하는 형식인지 확인합니다
+func ValidFormat(format string) bool {
+	return format == FormatCSV || format == FormatNDJSON || format == FormatParquet
+}
+
+// ContentType은 형식의 MIME 타입입니다
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/vnd.apache.parquet"
+	}
+}
+
+// NewWriter는 format 형식의 Writer를 만듭니다. columns는 Columns 중 내보낼 열이고,
+// dataPaths는 CSV에서 data 대신 열로 펼칠 문서 경로입니다 (헤더를 먼저 쓰므로 호출자가 미리 모음).
+func NewWriter(format string, w io.Writer, columns []string, dataPaths [][]string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w, columns, dataPaths)
+	case FormatNDJSON:
+		return NewNDJSONWriter(w, columns), nil
+	case FormatParquet:
+		return NewParquetWriter(w, columns), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q (csv, ndjson, parquet)", format)
+	}
+}
+
+// ParseRange는 from, to 파라미터를 읽습니다. RFC3339 시각이나 날짜(2006-01-02, UTC 자정)이고, 비어 있으면 제한 없음(nil)입니다.
+func ParseRange(fromText, toText string) (from, to *time.Time, err error) {
+	if from, err = parseTime(fromText); err != nil {
+		return nil, nil, fmt.Errorf("invalid from: %v", err)
+	}
+	if to, err = parseTime(toText); err != nil {
+		return nil, nil, fmt.Errorf("invalid to: %v", err)
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, fmt.Errorf("from must be before to")
+	}
+	return from, to, nil
+}
+
+func parseTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		if t, err = time.Parse(time.RFC3339, s); err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}
+
+// includes는 columns에 column이 있는지 확인합니다
+func includes(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// formatTime은 CSV와 NDJSON에 쓰는 시각 형식입니다
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,294 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+func testRows() []*Row {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []*Row{
+		{TargetID: "t1", Category: "vitals", Version: 2, CreatedAt: at, UpdatedAt: at,
+			Data: json.RawMessage(`{"bp":{"sys":120,"dia":80},"tags":["a","b"],"note":"x, y","big":12345678901234567890}`)},
+		{TargetID: "t2", Category: "vitals", Version: 2, CreatedAt: at, UpdatedAt: at,
+			Data: json.RawMessage(`{"bp":null,"ok":true}`)},
+	}
+}
+
+func TestCSVFlattening(t *testing.T) {
+	var buf bytes.Buffer
+	paths := [][]string{{"big"}, {"bp", "dia"}, {"bp", "sys"}, {"note"}, {"ok"}, {"tags"}}
+	w, err := NewWriter(FormatCSV, &buf, []string{"target_id", "data"}, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range testRows() {
+		if err := w.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "target_id,data.big,data.bp.dia,data.bp.sys,data.note,data.ok,data.tags\n" +
+		`t1,12345678901234567890,80,120,"x, y",,"[""a"",""b""]"` + "\n" +
+		"t2,,,,,true,\n"
+	if buf.String() != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestNDJSONColumns(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewWriter(FormatNDJSON, &buf, []string{"target_id", "updated_at", "data"}, nil)
+	for _, row := range testRows()[1:] {
+		w.Write(row)
+	}
+	want := `{"target_id":"t2","updated_at":"2026-03-01T12:00:00Z","data":{"bp":null,"ok":true}}` + "\n"
+	if buf.String() != want {
+		t.Errorf("NDJSON = %s, want %s", buf.String(), want)
+	}
+}
+
+func TestParquetLayout(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewWriter(FormatParquet, &buf, Columns, nil)
+	rows := testRows()
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file := buf.Bytes()
+	if !bytes.HasPrefix(file, parquetMagic) || !bytes.HasSuffix(file, parquetMagic) {
+		t.Fatal("missing PAR1 magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footerStart := len(file) - 8 - footerLen
+	meta, n := readThriftStruct(t, file[footerStart:len(file)-8])
+	if n != footerLen {
+		t.Fatalf("FileMetaData is %d bytes, footer length says %d", n, footerLen)
+	}
+	if meta[1] != int64(1) || string(meta[6].([]byte)) != "tmidb export" {
+		t.Errorf("version %v, created_by %q", meta[1], meta[6])
+	}
+
+	// 스키마: 루트 다음에 열마다 REQUIRED 요소 하나
+	schema := meta[2].([]any)
+	root := schema[0].(map[int16]any)
+	if string(root[4].([]byte)) != "schema" || root[5] != int64(len(Columns)) {
+		t.Errorf("schema root = %v", root)
+	}
+	wantSchema := []struct {
+		physical, converted int64
+	}{
+		{parquetByteArray, parquetUTF8},
+		{parquetByteArray, parquetUTF8},
+		{parquetInt32, -1},
+		{parquetInt64, parquetTimestampMicros},
+		{parquetInt64, parquetTimestampMicros},
+		{parquetByteArray, parquetJSON},
+	}
+	if len(schema) != len(Columns)+1 {
+		t.Fatalf("schema has %d elements", len(schema))
+	}
+	for i, want := range wantSchema {
+		element := schema[i+1].(map[int16]any)
+		converted, ok := element[6]
+		if !ok {
+			converted = int64(-1)
+		}
+		if string(element[4].([]byte)) != Columns[i] || element[1] != want.physical ||
+			element[3] != int64(parquetRequired) || converted != want.converted {
+			t.Errorf("schema element %d = %v", i+1, element)
+		}
+	}
+	if meta[3] != int64(len(rows)) {
+		t.Errorf("num_rows = %v, want %d", meta[3], len(rows))
+	}
+
+	// 열 청크는 매직 바로 뒤부터 빈틈없이 이어지고, 각각 데이터 페이지 하나로 값을 모두 담음
+	groups := meta[4].([]any)
+	if len(groups) != 1 {
+		t.Fatalf("%d row groups", len(groups))
+	}
+	group := groups[0].(map[int16]any)
+	chunks := group[1].([]any)
+	if group[3] != int64(len(rows)) || len(chunks) != len(Columns) {
+		t.Fatalf("row group = %v", group)
+	}
+	offset, total := int64(len(parquetMagic)), int64(0)
+	values := make(map[string][]byte)
+	for i, c := range chunks {
+		chunk := c.(map[int16]any)
+		column := chunk[3].(map[int16]any)
+		path := column[3].([]any)
+		size := column[7].(int64)
+		if chunk[2] != offset || column[9] != offset || column[1] != wantSchema[i].physical ||
+			string(path[0].([]byte)) != Columns[i] || column[4] != int64(0) ||
+			column[5] != int64(len(rows)) || column[6] != size {
+			t.Errorf("column chunk %s = %v at %d", Columns[i], chunk, offset)
+		}
+
+		header, headerLen := readThriftStruct(t, file[offset:])
+		page := header[5].(map[int16]any)
+		pageSize := header[3].(int64)
+		if header[1] != int64(parquetDataPage) || header[2] != pageSize || page[1] != int64(len(rows)) ||
+			page[2] != int64(parquetPlain) || int64(headerLen)+pageSize != size {
+			t.Errorf("page of %s = %v (header %d bytes, chunk %d bytes)", Columns[i], header, headerLen, size)
+		}
+		values[Columns[i]] = file[offset+int64(headerLen) : offset+size]
+		offset += size
+		total += size
+	}
+	if offset != int64(footerStart) || group[2] != total {
+		t.Errorf("column chunks end at %d (footer at %d), total_byte_size %v, want %d", offset, footerStart, group[2], total)
+	}
+
+	// PLAIN 인코딩된 값을 읽어 원래 행과 비교
+	for _, name := range []string{"target_id", "category", "data"} {
+		rest := values[name]
+		for i, row := range rows {
+			want := map[string]string{"target_id": row.TargetID, "category": row.Category, "data": string(row.Data)}[name]
+			size := int(binary.LittleEndian.Uint32(rest))
+			if got := string(rest[4 : 4+size]); got != want {
+				t.Errorf("%s of row %d = %q, want %q", name, i, got, want)
+			}
+			rest = rest[4+size:]
+		}
+	}
+	for i, row := range rows {
+		if v := int32(binary.LittleEndian.Uint32(values["version"][4*i:])); v != int32(row.Version) {
+			t.Errorf("version of row %d = %d", i, v)
+		}
+		if v := int64(binary.LittleEndian.Uint64(values["updated_at"][8*i:])); v != row.UpdatedAt.UnixMicro() {
+			t.Errorf("updated_at of row %d = %d", i, v)
+		}
+	}
+}
+
+// readThriftStruct는 Thrift compact protocol 구조체 하나를 필드 ID별 값으로 읽고 읽은 바이트 수를 반환합니다.
+// 정수는 int64, binary는 []byte, list는 []any, 구조체는 map[int16]any입니다 (테스트에서 푸터 확인용).
+func readThriftStruct(t *testing.T, b []byte) (map[int16]any, int) {
+	t.Helper()
+	r := &thriftReader{b: b}
+	v := r.structValue()
+	if r.err != nil {
+		t.Fatalf("invalid thrift struct: %v", r.err)
+	}
+	return v, r.pos
+}
+
+type thriftReader struct {
+	b   []byte
+	pos int
+	err error
+}
+
+func (r *thriftReader) byte() byte {
+	if r.pos >= len(r.b) {
+		r.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	r.pos++
+	return r.b[r.pos-1]
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.b[min(r.pos, len(r.b)):])
+	if n <= 0 {
+		r.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) varint() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) structValue() map[int16]any {
+	fields := make(map[int16]any)
+	var last int16
+	for r.err == nil {
+		header := r.byte()
+		if header == 0 {
+			break
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.varint())
+		}
+		fields[id] = r.value(header & 0x0f)
+		last = id
+	}
+	return fields
+}
+
+func (r *thriftReader) value(typ byte) any {
+	switch typ {
+	case 1, 2: // bool (구조체 필드는 타입에 값이 들어 있음)
+		return typ == 1
+	case 3:
+		return int64(int8(r.byte()))
+	case 4, thriftI32, thriftI64:
+		return r.varint()
+	case thriftBinary:
+		size := int(r.uvarint())
+		if r.err != nil || r.pos+size > len(r.b) {
+			r.err = io.ErrUnexpectedEOF
+			return nil
+		}
+		r.pos += size
+		return r.b[r.pos-size : r.pos]
+	case thriftList:
+		header := r.byte()
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		list := make([]any, 0, size)
+		for i := 0; i < size && r.err == nil; i++ {
+			elem := header & 0x0f
+			if elem == 1 || elem == 2 {
+				list = append(list, r.byte() == 1)
+				continue
+			}
+			list = append(list, r.value(elem))
+		}
+		return list
+	case thriftStruct:
+		return r.structValue()
+	default:
+		r.err = fmt.Errorf("unsupported thrift type %d", typ)
+		return nil
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	from, to, err := ParseRange("2026-03-01", "2026-03-02T00:00:00+09:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !from.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) || to.UTC().Hour() != 15 {
+		t.Errorf("ParseRange = %v, %v", from, to)
+	}
+	if _, _, err := ParseRange("2026-03-02", "2026-03-01"); err == nil {
+		t.Error("expected error for reversed range")
+	}
+	if _, _, err := ParseRange("yesterday", ""); err == nil {
+		t.Error("expected error for invalid from")
+	}
+}
@@ -0,0 +1,347 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// Parquet 파일 구조 중 쓰기에 필요한 부분만 구현합니다 (https://github.com/apache/parquet-format).
+// 모든 열은 REQUIRED이고 PLAIN 인코딩, 압축 없이 행 그룹마다 데이터 페이지 하나를 씁니다.
+// data 열은 문서 JSON 텍스트(JSON 논리 타입)이므로 CSV처럼 평탄화하지 않습니다.
+
+const (
+	// ParquetRowGroupRows 행 그룹 하나에 모으는 최대 행 수
+	ParquetRowGroupRows = 50000
+	// parquetRowGroupBytes 값이 이만큼 모이면 행 수와 관계없이 행 그룹을 씀 (메모리 상한)
+	parquetRowGroupBytes = 64 << 20
+)
+
+var parquetMagic = []byte("PAR1")
+
+// parquet-format의 열거형 값
+const (
+	parquetInt32     = 1
+	parquetInt64     = 2
+	parquetByteArray = 6
+
+	parquetRequired = 0
+
+	parquetUTF8            = 0
+	parquetTimestampMicros = 10
+	parquetJSON            = 19
+
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	parquetDataPage = 0
+)
+
+// parquetColumn은 열 하나와 현재 행 그룹에 모인 PLAIN 인코딩 값입니다
+type parquetColumn struct {
+	name      string
+	physical  int32
+	converted int32 // -1이면 없음
+	values    bytes.Buffer
+}
+
+// parquetChunk는 푸터에 기록할 열 청크 위치입니다
+type parquetChunk struct {
+	offset int64
+	size   int64
+}
+
+type parquetRowGroup struct {
+	rows   int64
+	chunks []parquetChunk
+}
+
+// ParquetWriter는 행을 Parquet 파일로 씁니다. 메모리에는 쓰지 않은 행 그룹 하나만 남습니다.
+type ParquetWriter struct {
+	w         io.Writer
+	offset    int64
+	columns   []*parquetColumn
+	rows      int64 // 현재 행 그룹의 행 수
+	buffered  int
+	totalRows int64
+	groups    []parquetRowGroup
+}
+
+// NewParquetWriter는 columns 열을 쓰는 Parquet Writer를 만듭니다
+func NewParquetWriter(w io.Writer, columns []string) *ParquetWriter {
+	p := &ParquetWriter{w: w}
+	for _, name := range columns {
+		column := &parquetColumn{name: name, physical: parquetByteArray, converted: parquetUTF8}
+		switch name {
+		case "version":
+			column.physical, column.converted = parquetInt32, -1
+		case "created_at", "updated_at":
+			column.physical, column.converted = parquetInt64, parquetTimestampMicros
+		case "data":
+			column.converted = parquetJSON
+		}
+		p.columns = append(p.columns, column)
+	}
+	return p
+}
+
+// Write는 행 하나를 현재 행 그룹에 더하고, 행 그룹이 차면 씁니다
+func (p *ParquetWriter) Write(row *Row) error {
+	for _, column := range p.columns {
+		switch column.name {
+		case "target_id":
+			p.buffered += appendByteArray(&column.values, []byte(row.TargetID))
+		case "category":
+			p.buffered += appendByteArray(&column.values, []byte(row.Category))
+		case "version":
+			binary.Write(&column.values, binary.LittleEndian, int32(row.Version))
+			p.buffered += 4
+		case "created_at":
+			binary.Write(&column.values, binary.LittleEndian, row.CreatedAt.UnixMicro())
+			p.buffered += 8
+		case "updated_at":
+			binary.Write(&column.values, binary.LittleEndian, row.UpdatedAt.UnixMicro())
+			p.buffered += 8
+		case "data":
+			data := row.Data
+			if len(data) == 0 {
+				data = []byte("null")
+			}
+			p.buffered += appendByteArray(&column.values, data)
+		}
+	}
+	p.rows++
+	if p.rows >= ParquetRowGroupRows || p.buffered >= parquetRowGroupBytes {
+		return p.flushRowGroup()
+	}
+	return nil
+}
+
+// Close는 남은 행 그룹과 푸터를 씁니다
+func (p *ParquetWriter) Close() error {
+	if p.rows > 0 {
+		if err := p.flushRowGroup(); err != nil {
+			return err
+		}
+	}
+	if p.offset == 0 {
+		if err := p.write(parquetMagic); err != nil {
+			return err
+		}
+	}
+	footer := p.fileMetaData()
+	if err := p.write(footer); err != nil {
+		return err
+	}
+	length := make([]byte, 4)
+	binary.LittleEndian.PutUint32(length, uint32(len(footer)))
+	if err := p.write(length); err != nil {
+		return err
+	}
+	return p.write(parquetMagic)
+}
+
+func (p *ParquetWriter) write(b []byte) error {
+	n, err := p.w.Write(b)
+	p.offset += int64(n)
+	return err
+}
+
+// flushRowGroup은 열마다 데이터 페이지 하나로 된 열 청크를 씁니다
+func (p *ParquetWriter) flushRowGroup() error {
+	if p.offset == 0 {
+		if err := p.write(parquetMagic); err != nil {
+			return err
+		}
+	}
+	group := parquetRowGroup{rows: p.rows}
+	for _, column := range p.columns {
+		header := pageHeader(int32(p.rows), int32(column.values.Len()))
+		chunk := parquetChunk{offset: p.offset}
+		if err := p.write(header); err != nil {
+			return err
+		}
+		if err := p.write(column.values.Bytes()); err != nil {
+			return err
+		}
+		chunk.size = p.offset - chunk.offset
+		group.chunks = append(group.chunks, chunk)
+		column.values.Reset()
+	}
+	p.groups = append(p.groups, group)
+	p.totalRows += p.rows
+	p.rows, p.buffered = 0, 0
+	return nil
+}
+
+// appendByteArray는 BYTE_ARRAY 값 하나를 PLAIN 인코딩(4바이트 길이 + 내용)으로 더합니다
+func appendByteArray(buf *bytes.Buffer, value []byte) int {
+	binary.Write(buf, binary.LittleEndian, uint32(len(value)))
+	buf.Write(value)
+	return 4 + len(value)
+}
+
+// pageHeader는 PageHeader(DataPageHeader 포함)를 인코딩합니다
+func pageHeader(numValues, size int32) []byte {
+	t := newThriftWriter()
+	t.i32(1, parquetDataPage)
+	t.i32(2, size) // uncompressed_page_size
+	t.i32(3, size) // compressed_page_size
+	t.structField(5)
+	t.i32(1, numValues)
+	t.i32(2, parquetPlain)
+	t.i32(3, parquetRLE) // definition_level_encoding (REQUIRED 열이라 레벨 없음)
+	t.i32(4, parquetRLE) // repetition_level_encoding
+	t.structEnd()
+	t.structEnd()
+	return t.buf.Bytes()
+}
+
+// fileMetaData는 푸터의 FileMetaData를 인코딩합니다
+func (p *ParquetWriter) fileMetaData() []byte {
+	t := newThriftWriter()
+	t.i32(1, 1) // version
+
+	t.listHeader(2, thriftStruct, len(p.columns)+1)
+	t.structBegin()
+	t.binary(4, "schema")
+	t.i32(5, int32(len(p.columns)))
+	t.structEnd()
+	for _, column := range p.columns {
+		t.structBegin()
+		t.i32(1, column.physical)
+		t.i32(3, parquetRequired)
+		t.binary(4, column.name)
+		if column.converted >= 0 {
+			t.i32(6, column.converted)
+		}
+		t.structEnd()
+	}
+
+	t.i64(3, p.totalRows)
+
+	t.listHeader(4, thriftStruct, len(p.groups))
+	for _, group := range p.groups {
+		t.structBegin()
+		t.listHeader(1, thriftStruct, len(group.chunks))
+		var total int64
+		for i, chunk := range group.chunks {
+			column := p.columns[i]
+			total += chunk.size
+			t.structBegin()
+			t.i64(2, chunk.offset) // file_offset
+			t.structField(3)       // ColumnMetaData
+			t.i32(1, column.physical)
+			t.listHeader(2, thriftI32, 2)
+			t.listI32(parquetPlain)
+			t.listI32(parquetRLE)
+			t.listHeader(3, thriftBinary, 1)
+			t.listBinary(column.name)
+			t.i32(4, 0) // UNCOMPRESSED
+			t.i64(5, group.rows)
+			t.i64(6, chunk.size)
+			t.i64(7, chunk.size)
+			t.i64(9, chunk.offset) // data_page_offset
+			t.structEnd()
+			t.structEnd()
+		}
+		t.i64(2, total)
+		t.i64(3, group.rows)
+		t.structEnd()
+	}
+
+	t.binary(6, "tmidb export")
+	t.structEnd()
+	return t.buf.Bytes()
+}
+
+// Thrift compact protocol 타입 (Parquet 메타데이터 인코딩)
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter는 Parquet 메타데이터에 필요한 만큼의 Thrift compact protocol 인코더입니다.
+// 최상위 구조체는 newThriftWriter로 시작하고 structEnd로 끝냅니다.
+type thriftWriter struct {
+	buf  bytes.Buffer
+	last []int16 // 구조체 단계별 마지막 필드 ID (필드 헤더는 이전 ID와의 차이로 인코딩)
+}
+
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{last: []int16{0}}
+}
+
+func (t *thriftWriter) field(id int16, typ byte) {
+	last := &t.last[len(t.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(int64(id))
+	}
+	*last = id
+}
+
+// varint는 zigzag 인코딩한 정수를 씁니다
+func (t *thriftWriter) varint(v int64) {
+	t.uvarint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+func (t *thriftWriter) uvarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	t.buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftWriter) binary(id int16, v string) {
+	t.field(id, thriftBinary)
+	t.listBinary(v)
+}
+
+// structField는 구조체 필드를 시작합니다 (structEnd로 끝냄)
+func (t *thriftWriter) structField(id int16) {
+	t.field(id, thriftStruct)
+	t.structBegin()
+}
+
+// structBegin은 리스트 원소인 구조체를 시작합니다
+func (t *thriftWriter) structBegin() {
+	t.last = append(t.last, 0)
+}
+
+func (t *thriftWriter) structEnd() {
+	t.buf.WriteByte(0)
+	t.last = t.last[:len(t.last)-1]
+}
+
+func (t *thriftWriter) listHeader(id int16, elem byte, size int) {
+	t.field(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elem)
+	} else {
+		t.buf.WriteByte(0xf0 | elem)
+		t.uvarint(uint64(size))
+	}
+}
+
+func (t *thriftWriter) listI32(v int32) {
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) listBinary(v string) {
+	t.uvarint(uint64(len(v)))
+	t.buf.WriteString(v)
+}
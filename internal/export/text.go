@@ -0,0 +1,169 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// NDJSONWriter는 행마다 JSON 객체 한 줄을 씁니다. data는 저장된 문서 그대로입니다.
+type NDJSONWriter struct {
+	w       io.Writer
+	columns []string
+	line    bytes.Buffer
+}
+
+// NewNDJSONWriter는 columns 열만 담는 NDJSON Writer를 만듭니다
+func NewNDJSONWriter(w io.Writer, columns []string) *NDJSONWriter {
+	return &NDJSONWriter{w: w, columns: columns}
+}
+
+// Write는 행 하나를 한 줄로 씁니다
+func (n *NDJSONWriter) Write(row *Row) error {
+	n.line.Reset()
+	n.line.WriteByte('{')
+	for i, column := range n.columns {
+		if i > 0 {
+			n.line.WriteByte(',')
+		}
+		n.line.WriteString(strconv.Quote(column))
+		n.line.WriteByte(':')
+		switch column {
+		case "target_id":
+			writeJSONString(&n.line, row.TargetID)
+		case "category":
+			writeJSONString(&n.line, row.Category)
+		case "version":
+			n.line.WriteString(strconv.Itoa(row.Version))
+		case "created_at":
+			writeJSONString(&n.line, formatTime(row.CreatedAt))
+		case "updated_at":
+			writeJSONString(&n.line, formatTime(row.UpdatedAt))
+		case "data":
+			if len(row.Data) == 0 {
+				n.line.WriteString("null")
+			} else {
+				n.line.Write(row.Data)
+			}
+		}
+	}
+	n.line.WriteString("}\n")
+	_, err := n.w.Write(n.line.Bytes())
+	return err
+}
+
+// Close는 아무것도 하지 않습니다 (줄 단위로 이미 씀)
+func (n *NDJSONWriter) Close() error {
+	return nil
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	encoded, _ := json.Marshal(s)
+	buf.Write(encoded)
+}
+
+// CSVWriter는 문서를 평탄화하여 CSV로 씁니다. 평탄화 규칙:
+//   - 중첩 객체는 점으로 이은 경로의 열이 됩니다 (data.vitals.bp)
+//   - 배열, 그리고 다른 문서에서는 값이 아니라 객체인 경로의 값은 JSON 텍스트로 씁니다
+//   - 문자열은 따옴표 없이, 숫자는 저장된 그대로(지수 표기 변환 없음), 불리언은 true/false로 씁니다
+//   - null이거나 문서에 없는 경로는 빈 칸입니다
+type CSVWriter struct {
+	csv       *csv.Writer
+	columns   []string
+	dataPaths [][]string
+	record    []string
+}
+
+// NewCSVWriter는 헤더를 쓰고 CSV Writer를 반환합니다. columns에 data가 있으면 data 대신 dataPaths 열을 씁니다.
+func NewCSVWriter(w io.Writer, columns []string, dataPaths [][]string) (*CSVWriter, error) {
+	c := &CSVWriter{csv: csv.NewWriter(w), dataPaths: dataPaths}
+	var header []string
+	for _, column := range columns {
+		if column != "data" {
+			c.columns = append(c.columns, column)
+			header = append(header, column)
+		}
+	}
+	if !includes(columns, "data") {
+		c.dataPaths = nil
+	}
+	for _, path := range c.dataPaths {
+		header = append(header, "data."+strings.Join(path, "."))
+	}
+	c.record = make([]string, len(header))
+	if err := c.csv.Write(header); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Write는 행 하나를 씁니다
+func (c *CSVWriter) Write(row *Row) error {
+	for i, column := range c.columns {
+		switch column {
+		case "target_id":
+			c.record[i] = row.TargetID
+		case "category":
+			c.record[i] = row.Category
+		case "version":
+			c.record[i] = strconv.Itoa(row.Version)
+		case "created_at":
+			c.record[i] = formatTime(row.CreatedAt)
+		case "updated_at":
+			c.record[i] = formatTime(row.UpdatedAt)
+		}
+	}
+	if len(c.dataPaths) > 0 {
+		var doc interface{}
+		if len(row.Data) > 0 {
+			decoder := json.NewDecoder(bytes.NewReader(row.Data))
+			decoder.UseNumber()
+			if err := decoder.Decode(&doc); err != nil {
+				return err
+			}
+		}
+		offset := len(c.columns)
+		for i, path := range c.dataPaths {
+			c.record[offset+i] = FlattenValue(lookupPath(doc, path))
+		}
+	}
+	return c.csv.Write(c.record)
+}
+
+// Close는 남은 CSV 내용을 씁니다
+func (c *CSVWriter) Close() error {
+	c.csv.Flush()
+	return c.csv.Error()
+}
+
+// lookupPath는 문서에서 경로의 값을 찾습니다 (없으면 nil)
+func lookupPath(doc interface{}, path []string) interface{} {
+	for _, key := range path {
+		object, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		doc = object[key]
+	}
+	return doc
+}
+
+// FlattenValue는 CSV 칸 하나에 쓸 값입니다 (평탄화 규칙은 CSVWriter 참고)
+func FlattenValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		encoded, _ := json.Marshal(value)
+		return string(encoded)
+	}
+}
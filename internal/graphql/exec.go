@@ -0,0 +1,576 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// 실행 제한
+const (
+	MaxDepth = 10    // 선택 집합 중첩
+	MaxNodes = 10000 // 한 요청에서 만드는 객체 수
+
+	// maxObjectFields 객체 하나에서 프래그먼트를 풀어 모을 수 있는 필드 수 (서로를 여러 번 펼치는 프래그먼트 방지)
+	maxObjectFields = 1000
+)
+
+// Schema는 실행할 수 있는 타입입니다 (query 루트만 있음)
+type Schema struct {
+	Query *Object
+}
+
+// Object는 필드를 가진 객체 타입입니다
+type Object struct {
+	Name   string
+	Fields map[string]*FieldDef
+}
+
+// FieldDef는 객체 타입의 필드 하나입니다
+type FieldDef struct {
+	Type    *Object // 객체를 반환하면 그 타입 (nil이면 스칼라, JSON으로 그대로 씀)
+	List    bool    // Type의 목록을 반환하는지 (Resolve는 슬라이스를 반환)
+	Args    map[string]Arg
+	Resolve func(p ResolveParams) (interface{}, error)
+}
+
+// Arg는 필드 인자의 타입과 기본값입니다.
+// 타입은 String, ID, Int, Float, Boolean, JSON과 [..], ! 표기를 씁니다. 그 밖의 이름은 열거형으로 봅니다.
+type Arg struct {
+	Type    string
+	Default interface{}
+}
+
+// ResolveParams는 필드 리졸버에 넘기는 값입니다.
+// Args는 타입에 맞게 변환되어 있습니다 (Int는 int, Float는 float64, 목록은 []interface{}).
+type ResolveParams struct {
+	Context context.Context
+	Source  interface{}
+	Args    map[string]interface{}
+}
+
+// Request는 POST 본문 또는 GET 쿼리의 GraphQL 요청입니다
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response는 GraphQL 응답입니다
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error는 응답의 오류 하나입니다 (실행 중 오류면 Path에 필드 위치)
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// Execute는 요청을 파싱, 검증하고 실행합니다.
+// 파싱과 검증 오류는 Data 없이 Errors만 담아 반환합니다 (HTTP 400으로 보낼 것).
+func Execute(ctx context.Context, schema *Schema, req Request) (*Response, bool) {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return requestError(err), false
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return requestError(err), false
+	}
+	vars, err := coerceVariables(op, req.Variables)
+	if err != nil {
+		return requestError(err), false
+	}
+
+	e := &executor{ctx: ctx, doc: doc, op: op, vars: vars, validated: make(map[string]bool)}
+	if err := e.validate(schema.Query, op.Selections, 1, map[string]bool{}); err != nil {
+		return requestError(err), false
+	}
+
+	data := e.executeObject(schema.Query, nil, op.Selections, nil)
+	return &Response{Data: data, Errors: e.errors}, true
+}
+
+func requestError(err error) *Response {
+	return &Response{Errors: []*Error{{Message: err.Error()}}}
+}
+
+// selectOperation은 실행할 작업을 고릅니다 (작업이 여럿이면 operationName 필요)
+func selectOperation(doc *Document, name string) (*Operation, error) {
+	var op *Operation
+	switch {
+	case name != "":
+		for _, candidate := range doc.Operations {
+			if candidate.Name == name {
+				op = candidate
+			}
+		}
+		if op == nil {
+			return nil, fmt.Errorf("unknown operation %q", name)
+		}
+	case len(doc.Operations) > 1:
+		return nil, fmt.Errorf("operationName is required when the document has several operations")
+	default:
+		op = doc.Operations[0]
+	}
+	if op.Type != "query" {
+		return nil, fmt.Errorf("%s operations are not supported", op.Type)
+	}
+	return op, nil
+}
+
+// coerceVariables는 요청의 변수를 선언된 타입에 맞게 변환합니다
+func coerceVariables(op *Operation, values map[string]interface{}) (map[string]interface{}, error) {
+	vars := make(map[string]interface{})
+	for _, def := range op.Variables {
+		value, ok := values[def.Name]
+		if !ok {
+			if def.Default == nil {
+				if strings.HasSuffix(def.Type, "!") {
+					return nil, fmt.Errorf("variable $%s of type %s was not provided", def.Name, def.Type)
+				}
+				continue
+			}
+			value = def.Default
+		}
+		coerced, err := coerce(def.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("variable $%s: %v", def.Name, err)
+		}
+		vars[def.Name] = coerced
+	}
+	return vars, nil
+}
+
+// executor는 요청 하나의 실행 상태입니다
+type executor struct {
+	ctx    context.Context
+	doc    *Document
+	op     *Operation
+	vars   map[string]interface{}
+	errors []*Error
+	nodes  int
+
+	validated map[string]bool // 이미 검증한 프래그먼트
+}
+
+// validate는 실행 전에 필드 이름, 인자 이름, 하위 선택, 프래그먼트, 깊이를 확인합니다
+func (e *executor) validate(obj *Object, selections []Selection, depth int, fragments map[string]bool) error {
+	if depth > MaxDepth {
+		return fmt.Errorf("query is nested deeper than %d levels", MaxDepth)
+	}
+	for _, selection := range selections {
+		switch s := selection.(type) {
+		case *Field:
+			if s.Name == "__typename" {
+				if len(s.Selections) > 0 {
+					return fmt.Errorf("field __typename cannot have a selection")
+				}
+				continue
+			}
+			def, ok := obj.Fields[s.Name]
+			if !ok {
+				return fmt.Errorf("cannot query field %q on type %s", s.Name, obj.Name)
+			}
+			for _, arg := range s.Args {
+				if _, ok := def.Args[arg.Name]; !ok {
+					return fmt.Errorf("unknown argument %q on field %s.%s", arg.Name, obj.Name, s.Name)
+				}
+				if err := e.checkVariables(arg.Value); err != nil {
+					return err
+				}
+			}
+			switch {
+			case def.Type == nil && len(s.Selections) > 0:
+				return fmt.Errorf("field %s.%s is a scalar and cannot have a selection", obj.Name, s.Name)
+			case def.Type != nil && len(s.Selections) == 0:
+				return fmt.Errorf("field %s.%s of type %s must have a selection", obj.Name, s.Name, def.Type.Name)
+			case def.Type != nil:
+				if err := e.validate(def.Type, s.Selections, depth+1, fragments); err != nil {
+					return err
+				}
+			}
+		case *InlineFragment:
+			if s.TypeCondition != "" && s.TypeCondition != obj.Name {
+				return fmt.Errorf("fragment on %s cannot be used on type %s", s.TypeCondition, obj.Name)
+			}
+			if err := e.validate(obj, s.Selections, depth, fragments); err != nil {
+				return err
+			}
+		case *FragmentSpread:
+			fragment, ok := e.doc.Fragments[s.Name]
+			switch {
+			case !ok:
+				return fmt.Errorf("unknown fragment %q", s.Name)
+			case fragments[s.Name]:
+				return fmt.Errorf("fragment %q spreads itself", s.Name)
+			case fragment.TypeCondition != obj.Name:
+				return fmt.Errorf("fragment %q on %s cannot be used on type %s", s.Name, fragment.TypeCondition, obj.Name)
+			}
+			// 깊이에 따라 결과가 달라지므로 깊이별로 한 번만 검증
+			key := fmt.Sprintf("%s@%d", s.Name, depth)
+			if e.validated[key] {
+				continue
+			}
+			fragments[s.Name] = true
+			err := e.validate(obj, fragment.Selections, depth, fragments)
+			delete(fragments, s.Name)
+			if err != nil {
+				return err
+			}
+			e.validated[key] = true
+		}
+	}
+	return nil
+}
+
+// checkVariables는 값에 쓴 변수가 작업에 선언되었는지 확인합니다
+func (e *executor) checkVariables(value interface{}) error {
+	switch v := value.(type) {
+	case Variable:
+		for _, def := range e.op.Variables {
+			if def.Name == string(v) {
+				return nil
+			}
+		}
+		return fmt.Errorf("variable $%s is not defined", v)
+	case []interface{}:
+		for _, item := range v {
+			if err := e.checkVariables(item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			if err := e.checkVariables(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// collectFields는 지시자와 프래그먼트를 풀어 응답 키별로 필드를 모읍니다 (같은 키는 하위 선택을 합침)
+func (e *executor) collectFields(obj *Object, selections []Selection, keys *[]string, fields map[string][]*Field, collected *int) error {
+	for _, selection := range selections {
+		switch s := selection.(type) {
+		case *Field:
+			include, err := e.included(s.Directives)
+			if err != nil {
+				return err
+			}
+			if !include {
+				continue
+			}
+			key := s.ResponseKey()
+			if _, ok := fields[key]; !ok {
+				*keys = append(*keys, key)
+			}
+			fields[key] = append(fields[key], s)
+			if *collected++; *collected > maxObjectFields {
+				return fmt.Errorf("selection on %s expands to more than %d fields", obj.Name, maxObjectFields)
+			}
+		case *InlineFragment:
+			include, err := e.included(s.Directives)
+			if err != nil {
+				return err
+			}
+			if include {
+				if err := e.collectFields(obj, s.Selections, keys, fields, collected); err != nil {
+					return err
+				}
+			}
+		case *FragmentSpread:
+			include, err := e.included(s.Directives)
+			if err != nil {
+				return err
+			}
+			if include {
+				if err := e.collectFields(obj, e.doc.Fragments[s.Name].Selections, keys, fields, collected); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// included는 @skip(if:)과 @include(if:)를 평가합니다
+func (e *executor) included(directives []Directive) (bool, error) {
+	for _, directive := range directives {
+		if directive.Name != "skip" && directive.Name != "include" {
+			continue
+		}
+		var condition interface{}
+		for _, arg := range directive.Args {
+			if arg.Name == "if" {
+				condition = e.resolveValue(arg.Value)
+			}
+		}
+		value, ok := condition.(bool)
+		if !ok {
+			return false, fmt.Errorf("@%s requires a Boolean \"if\" argument", directive.Name)
+		}
+		if directive.Name == "skip" && value || directive.Name == "include" && !value {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// resolveValue는 값 안의 변수를 변수 값으로 바꿉니다 (열거형은 문자열로)
+func (e *executor) resolveValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case Variable:
+		return e.vars[string(v)]
+	case Enum:
+		return string(v)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = e.resolveValue(item)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = e.resolveValue(item)
+		}
+		return out
+	}
+	return value
+}
+
+// fieldArgs는 필드 인자를 모아 타입에 맞게 변환하고 기본값을 채웁니다
+func (e *executor) fieldArgs(def *FieldDef, field *Field) (map[string]interface{}, error) {
+	args := make(map[string]interface{}, len(def.Args))
+	for _, arg := range field.Args {
+		if v, ok := arg.Value.(Variable); ok {
+			if _, provided := e.vars[string(v)]; !provided {
+				continue // 값이 없는 변수는 인자를 주지 않은 것으로 봄
+			}
+		}
+		value, err := coerce(def.Args[arg.Name].Type, e.resolveValue(arg.Value))
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %v", arg.Name, err)
+		}
+		args[arg.Name] = value
+	}
+	for name, spec := range def.Args {
+		if _, ok := args[name]; ok {
+			continue
+		}
+		if spec.Default != nil {
+			args[name] = spec.Default
+		} else if strings.HasSuffix(spec.Type, "!") {
+			return nil, fmt.Errorf("argument %q of type %s is required", name, spec.Type)
+		}
+	}
+	return args, nil
+}
+
+// executeObject는 객체 하나의 선택 집합을 실행합니다
+func (e *executor) executeObject(obj *Object, source interface{}, selections []Selection, path []interface{}) interface{} {
+	e.nodes++
+	if e.nodes > MaxNodes {
+		e.addError(path, fmt.Errorf("result has more than %d objects; narrow the query or lower the limits", MaxNodes))
+		return nil
+	}
+
+	var keys []string
+	var collected int
+	fields := make(map[string][]*Field)
+	if err := e.collectFields(obj, selections, &keys, fields, &collected); err != nil {
+		e.addError(path, err)
+		return nil
+	}
+
+	result := make(orderedObject, 0, len(keys))
+	for _, key := range keys {
+		field := fields[key][0]
+		fieldPath := append(append([]interface{}(nil), path...), key)
+		if field.Name == "__typename" {
+			result = append(result, orderedField{key, obj.Name})
+			continue
+		}
+		def := obj.Fields[field.Name]
+		result = append(result, orderedField{key, e.executeField(def, source, fields[key], fieldPath)})
+	}
+	return result
+}
+
+// executeField는 필드를 해석하고 객체 필드면 하위 선택을 실행합니다
+func (e *executor) executeField(def *FieldDef, source interface{}, fields []*Field, path []interface{}) interface{} {
+	if err := e.ctx.Err(); err != nil {
+		e.addError(path, err)
+		return nil
+	}
+	args, err := e.fieldArgs(def, fields[0])
+	if err != nil {
+		e.addError(path, err)
+		return nil
+	}
+	value, err := def.Resolve(ResolveParams{Context: e.ctx, Source: source, Args: args})
+	if err != nil {
+		e.addError(path, err)
+		return nil
+	}
+	if def.Type == nil || isNil(value) {
+		return value
+	}
+
+	var selections []Selection
+	for _, field := range fields {
+		selections = append(selections, field.Selections...)
+	}
+	if !def.List {
+		return e.executeObject(def.Type, value, selections, path)
+	}
+
+	list := reflect.ValueOf(value)
+	if list.Kind() != reflect.Slice {
+		e.addError(path, fmt.Errorf("resolver returned %T for a list field", value))
+		return nil
+	}
+	items := make([]interface{}, list.Len())
+	for i := range items {
+		if item := list.Index(i).Interface(); !isNil(item) {
+			items[i] = e.executeObject(def.Type, item, selections, append(append([]interface{}(nil), path...), i))
+		}
+	}
+	return items
+}
+
+func (e *executor) addError(path []interface{}, err error) {
+	e.errors = append(e.errors, &Error{Message: err.Error(), Path: append([]interface{}(nil), path...)})
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// coerce는 입력 값을 인자 타입에 맞게 변환합니다
+func coerce(typ string, value interface{}) (interface{}, error) {
+	nonNull := strings.HasSuffix(typ, "!")
+	typ = strings.TrimSuffix(typ, "!")
+	if value == nil {
+		if nonNull {
+			return nil, fmt.Errorf("expected %s!, found null", typ)
+		}
+		return nil, nil
+	}
+
+	if strings.HasPrefix(typ, "[") && strings.HasSuffix(typ, "]") {
+		inner := typ[1 : len(typ)-1]
+		items, ok := value.([]interface{})
+		if !ok {
+			items = []interface{}{value} // 목록 자리에 값 하나를 주면 길이 1인 목록
+		}
+		out := make([]interface{}, len(items))
+		for i, item := range items {
+			coerced, err := coerce(inner, item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = coerced
+		}
+		return out, nil
+	}
+
+	switch typ {
+	case "String":
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case "ID":
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case int:
+			return strconv.Itoa(v), nil
+		case int64:
+			return strconv.FormatInt(v, 10), nil
+		case float64:
+			if v == math.Trunc(v) {
+				return strconv.FormatInt(int64(v), 10), nil
+			}
+		}
+	case "Int":
+		switch v := value.(type) {
+		case int: // 이미 변환된 변수
+			return v, nil
+		case int64:
+			if v >= math.MinInt32 && v <= math.MaxInt32 {
+				return int(v), nil
+			}
+		case float64: // JSON 변수
+			if v == math.Trunc(v) && v >= math.MinInt32 && v <= math.MaxInt32 {
+				return int(v), nil
+			}
+		}
+	case "Float":
+		switch v := value.(type) {
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		}
+	case "Boolean":
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	case "JSON":
+		return value, nil
+	default: // 열거형 (리터럴은 이미 문자열로 바뀜)
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("expected %s, found %v", typ, value)
+}
+
+// orderedObject는 선택한 순서대로 키를 쓰는 JSON 객체입니다
+type orderedObject []orderedField
+
+type orderedField struct {
+	key   string
+	value interface{}
+}
+
+// MarshalJSON은 필드를 선택 순서대로 씁니다
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(field.key)
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,136 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testItem struct {
+	ID    string
+	Score int
+}
+
+func testSchema() *Schema {
+	item := &Object{Name: "Item"}
+	item.Fields = map[string]*FieldDef{
+		"id":    {Resolve: func(p ResolveParams) (interface{}, error) { return p.Source.(*testItem).ID, nil }},
+		"score": {Resolve: func(p ResolveParams) (interface{}, error) { return p.Source.(*testItem).Score, nil }},
+		"fail":  {Resolve: func(p ResolveParams) (interface{}, error) { return nil, errors.New("boom") }},
+		"self":  {Type: item, Resolve: func(p ResolveParams) (interface{}, error) { return p.Source, nil }},
+	}
+	items := []*testItem{{"a", 1}, {"b", 2}, {"c", 3}}
+	return &Schema{Query: &Object{Name: "Query", Fields: map[string]*FieldDef{
+		"items": {
+			Type: item,
+			List: true,
+			Args: map[string]Arg{"limit": {Type: "Int", Default: 10}, "ids": {Type: "[ID!]"}},
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				var out []*testItem
+				for _, it := range items {
+					if ids, ok := p.Args["ids"].([]interface{}); ok {
+						found := false
+						for _, id := range ids {
+							found = found || id == it.ID
+						}
+						if !found {
+							continue
+						}
+					}
+					if len(out) < p.Args["limit"].(int) {
+						out = append(out, it)
+					}
+				}
+				return out, nil
+			},
+		},
+		"item": {
+			Type: item,
+			Args: map[string]Arg{"id": {Type: "ID!"}},
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				for _, it := range items {
+					if it.ID == p.Args["id"] {
+						return it, nil
+					}
+				}
+				return nil, nil
+			},
+		},
+	}}}
+}
+
+func run(t *testing.T, req Request) (string, bool) {
+	t.Helper()
+	resp, ok := Execute(context.Background(), testSchema(), req)
+	out, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out), ok
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		query string
+		vars  map[string]interface{}
+		want  string
+	}{
+		{`{ items(limit: 2) { id score } }`,
+			nil, `{"data":{"items":[{"id":"a","score":1},{"id":"b","score":2}]}}`},
+		{`query Q($id: ID!) { first: item(id: $id) { __typename id } missing: item(id: "zz") { id } }`,
+			map[string]interface{}{"id": "b"}, `{"data":{"first":{"__typename":"Item","id":"b"},"missing":null}}`},
+		{`{ items(ids: ["c", "a"]) { ...F self { ... on Item { score } } } } fragment F on Item { id }`,
+			nil, `{"data":{"items":[{"id":"a","self":{"score":1}},{"id":"c","self":{"score":3}}]}}`},
+		{`query ($skip: Boolean = true) { item(id: "a") { id score @skip(if: $skip) score2: score @include(if: false) } }`,
+			nil, `{"data":{"item":{"id":"a"}}}`},
+		{`query ($n: Int) { items(limit: $n) { id } }`,
+			map[string]interface{}{"n": float64(1)}, `{"data":{"items":[{"id":"a"}]}}`},
+		{`{ item(id: "a") { id fail } }`,
+			nil, `{"data":{"item":{"id":"a","fail":null}},"errors":[{"message":"boom","path":["item","fail"]}]}`},
+	}
+	for _, tt := range tests {
+		got, ok := run(t, Request{Query: tt.query, Variables: tt.vars})
+		if !ok || got != tt.want {
+			t.Errorf("%s\n got %s\nwant %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestExecuteRejects(t *testing.T) {
+	for _, query := range []string{
+		`{ items { nope } }`,
+		`{ items }`,
+		`{ item(id: "a") { id { x } } }`,
+		`{ item { id } }`,
+		`{ items(limit: "x") { id } }`,
+		`{ items(bogus: 1) { id } }`,
+		`{ items { ...Missing } }`,
+		`{ items { ...A } } fragment A on Item { ...A }`,
+		`query { items(limit: $undefined) { id } }`,
+		`mutation { items { id } }`,
+		`{ items { id }`,
+		`{ item(id: "a") { ` + strings.Repeat("self { ", MaxDepth) + "id" + strings.Repeat(" }", MaxDepth) + ` } }`,
+	} {
+		got, ok := run(t, Request{Query: query})
+		if ok && !strings.Contains(got, `"errors"`) {
+			t.Errorf("accepted %s: %s", query, got)
+		}
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	doc, err := Parse(`{ f(a: -1.5e2, b: "x\"é", c: [1, {d: ENUM}], e: null) { g } }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := doc.Operations[0].Selections[0].(*Field).Args
+	if args[0].Value != -150.0 || args[1].Value != `x"é` || args[3].Value != nil {
+		t.Errorf("unexpected values: %#v", args)
+	}
+	list := args[2].Value.([]interface{})
+	if list[0] != int64(1) || list[1].(map[string]interface{})["d"] != Enum("ENUM") {
+		t.Errorf("unexpected list: %#v", list)
+	}
+}
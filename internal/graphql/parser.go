@@ -0,0 +1,699 @@
+// Package graphql는 데이터 API의 /graphql 엔드포인트에 필요한 만큼의 GraphQL을 구현합니다.
+//
+// 지원하는 문법은 query 작업, 필드 별칭, 인자, 변수, 중첩 선택, 프래그먼트(이름 있는 것과 인라인),
+// @skip/@include 지시자, __typename입니다. mutation, subscription, 스키마 인트로스펙션(__schema)은 지원하지 않습니다.
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Document는 파싱된 요청 문서입니다
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation은 query 작업 하나입니다
+type Operation struct {
+	Type       string // query (mutation, subscription은 파싱만 하고 실행하지 않음)
+	Name       string
+	Variables  []VariableDef
+	Selections []Selection
+}
+
+// VariableDef는 작업에 선언된 변수입니다
+type VariableDef struct {
+	Name    string
+	Type    string // "[String!]!" 형식
+	Default interface{}
+}
+
+// Selection은 *Field, *FragmentSpread, *InlineFragment 중 하나입니다
+type Selection interface{}
+
+// Field는 선택한 필드입니다
+type Field struct {
+	Alias      string
+	Name       string
+	Args       []Argument
+	Directives []Directive
+	Selections []Selection
+}
+
+// ResponseKey는 응답에 쓰는 이름입니다 (별칭이 있으면 별칭)
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// Argument는 필드나 지시자의 인자입니다
+type Argument struct {
+	Name  string
+	Value interface{}
+}
+
+// Directive는 @skip(if: true) 같은 지시자입니다
+type Directive struct {
+	Name string
+	Args []Argument
+}
+
+// FragmentSpread는 ...Name 입니다
+type FragmentSpread struct {
+	Name       string
+	Directives []Directive
+}
+
+// InlineFragment는 ... on Type { } 입니다
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []Directive
+	Selections    []Selection
+}
+
+// Fragment는 fragment Name on Type { } 정의입니다
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	Selections    []Selection
+}
+
+// Variable은 값 자리에 쓴 $name 입니다
+type Variable string
+
+// Enum은 따옴표 없는 이름 값입니다
+type Enum string
+
+// 토큰 종류
+const (
+	tokenEOF = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  int
+	value string
+	pos   int
+}
+
+// lexer는 요청 문서를 토큰으로 나눕니다
+type lexer struct {
+	src string
+	pos int
+}
+
+func (l *lexer) next() (token, error) {
+	// 공백, 쉼표, 주석, BOM은 무시
+	for l.pos < len(l.src) {
+		ch := l.src[l.pos]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r' || ch == ',':
+			l.pos++
+			continue
+		case ch == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' && l.src[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		case strings.HasPrefix(l.src[l.pos:], "\uFEFF"):
+			l.pos += len("\uFEFF")
+			continue
+		}
+		break
+	}
+	if l.pos >= len(l.src) {
+		return token{kind: tokenEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	ch := l.src[l.pos]
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.pos += 3
+		return token{kind: tokenPunct, value: "...", pos: start}, nil
+	case strings.ContainsRune("!$():=@[]{}|&", rune(ch)):
+		l.pos++
+		return token{kind: tokenPunct, value: string(ch), pos: start}, nil
+	case ch == '_' || isLetter(ch):
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, value: l.src[start:l.pos], pos: start}, nil
+	case ch == '-' || isDigit(ch):
+		return l.number()
+	case ch == '"':
+		return l.string()
+	}
+	return token{}, fmt.Errorf("unexpected character %q at %d", ch, start)
+}
+
+func (l *lexer) number() (token, error) {
+	start := l.pos
+	kind := tokenInt
+	if l.src[l.pos] == '-' {
+		l.pos++
+	}
+	digits := func() int {
+		n := 0
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.pos++
+			n++
+		}
+		return n
+	}
+	if digits() == 0 {
+		return token{}, fmt.Errorf("invalid number at %d", start)
+	}
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		l.pos++
+		kind = tokenFloat
+		if digits() == 0 {
+			return token{}, fmt.Errorf("invalid number at %d", start)
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		l.pos++
+		kind = tokenFloat
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+		if digits() == 0 {
+			return token{}, fmt.Errorf("invalid number at %d", start)
+		}
+	}
+	return token{kind: kind, value: l.src[start:l.pos], pos: start}, nil
+}
+
+// string은 큰따옴표 문자열과 """블록 문자열"""을 읽습니다
+func (l *lexer) string() (token, error) {
+	start := l.pos
+	if strings.HasPrefix(l.src[l.pos:], `"""`) {
+		end := strings.Index(l.src[l.pos+3:], `"""`)
+		if end < 0 {
+			return token{}, fmt.Errorf("unterminated string at %d", start)
+		}
+		value := l.src[l.pos+3 : l.pos+3+end]
+		l.pos += end + 6
+		return token{kind: tokenString, value: strings.TrimSpace(value), pos: start}, nil
+	}
+
+	var b strings.Builder
+	l.pos++
+	for l.pos < len(l.src) {
+		ch := l.src[l.pos]
+		switch {
+		case ch == '"':
+			l.pos++
+			return token{kind: tokenString, value: b.String(), pos: start}, nil
+		case ch == '\n' || ch == '\r':
+			return token{}, fmt.Errorf("unterminated string at %d", start)
+		case ch == '\\' && l.pos+1 < len(l.src):
+			l.pos++
+			esc := l.src[l.pos]
+			switch esc {
+			case '"', '\\', '/':
+				b.WriteByte(esc)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if l.pos+4 >= len(l.src) {
+					return token{}, fmt.Errorf("invalid unicode escape at %d", l.pos)
+				}
+				code, err := strconv.ParseUint(l.src[l.pos+1:l.pos+5], 16, 32)
+				if err != nil {
+					return token{}, fmt.Errorf("invalid unicode escape at %d", l.pos)
+				}
+				b.WriteRune(rune(code))
+				l.pos += 4
+			default:
+				return token{}, fmt.Errorf("invalid escape \\%c at %d", esc, l.pos)
+			}
+			l.pos++
+		default:
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			b.WriteRune(r)
+			l.pos += size
+		}
+	}
+	return token{}, fmt.Errorf("unterminated string at %d", start)
+}
+
+func isLetter(ch byte) bool { return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' }
+func isDigit(ch byte) bool  { return ch >= '0' && ch <= '9' }
+
+// parser는 토큰을 읽어 Document를 만듭니다 (한 토큰 미리 보기)
+type parser struct {
+	lex   lexer
+	tok   token
+	depth int
+}
+
+// maxParseDepth 선택 집합과 값의 최대 중첩 (깊은 요청으로 스택을 소모하지 않도록)
+const maxParseDepth = 32
+
+// Parse는 GraphQL 요청 문서를 파싱합니다
+func Parse(src string) (*Document, error) {
+	p := &parser{lex: lexer{src: src}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Fragments: make(map[string]*Fragment)}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peek("{"):
+			selections, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, &Operation{Type: "query", Selections: selections})
+		case p.tok.kind == tokenName && p.tok.value == "fragment":
+			fragment, err := p.fragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := doc.Fragments[fragment.Name]; exists {
+				return nil, fmt.Errorf("duplicate fragment %q", fragment.Name)
+			}
+			doc.Fragments[fragment.Name] = fragment
+		case p.tok.kind == tokenName && (p.tok.value == "query" || p.tok.value == "mutation" || p.tok.value == "subscription"):
+			op, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(doc.Operations) == 0 {
+		return nil, fmt.Errorf("document has no operation")
+	}
+	return doc, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) peek(punct string) bool {
+	return p.tok.kind == tokenPunct && p.tok.value == punct
+}
+
+// skip은 현재 토큰이 punct이면 넘기고 true를 반환합니다
+func (p *parser) skip(punct string) (bool, error) {
+	if !p.peek(punct) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.peek(punct) {
+		return fmt.Errorf("expected %q at %d, found %s", punct, p.tok.pos, p.describe())
+	}
+	return p.advance()
+}
+
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", fmt.Errorf("expected name at %d, found %s", p.tok.pos, p.describe())
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) describe() string {
+	if p.tok.kind == tokenEOF {
+		return "end of document"
+	}
+	return strconv.Quote(p.tok.value)
+}
+
+func (p *parser) unexpected() error {
+	return fmt.Errorf("unexpected %s at %d", p.describe(), p.tok.pos)
+}
+
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > maxParseDepth {
+		return fmt.Errorf("document is nested deeper than %d levels", maxParseDepth)
+	}
+	return nil
+}
+
+func (p *parser) operation() (*Operation, error) {
+	op := &Operation{Type: p.tok.value}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenName {
+		op.Name = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if ok, err := p.skip("("); err != nil {
+		return nil, err
+	} else if ok {
+		for !p.peek(")") {
+			def, err := p.variableDef()
+			if err != nil {
+				return nil, err
+			}
+			op.Variables = append(op.Variables, def)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.Selections = selections
+	return op, nil
+}
+
+func (p *parser) variableDef() (VariableDef, error) {
+	if err := p.expect("$"); err != nil {
+		return VariableDef{}, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return VariableDef{}, err
+	}
+	if err := p.expect(":"); err != nil {
+		return VariableDef{}, err
+	}
+	typ, err := p.typeRef()
+	if err != nil {
+		return VariableDef{}, err
+	}
+	def := VariableDef{Name: name, Type: typ}
+	if ok, err := p.skip("="); err != nil {
+		return VariableDef{}, err
+	} else if ok {
+		if def.Default, err = p.value(true); err != nil {
+			return VariableDef{}, err
+		}
+	}
+	if _, err := p.directives(); err != nil {
+		return VariableDef{}, err
+	}
+	return def, nil
+}
+
+// typeRef는 String, [Int!]! 같은 타입 표기를 읽어 문자열로 돌려줍니다
+func (p *parser) typeRef() (string, error) {
+	var typ string
+	if ok, err := p.skip("["); err != nil {
+		return "", err
+	} else if ok {
+		inner, err := p.typeRef()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expect("]"); err != nil {
+			return "", err
+		}
+		typ = "[" + inner + "]"
+	} else if typ, err = p.name(); err != nil {
+		return "", err
+	}
+	if ok, err := p.skip("!"); err != nil {
+		return "", err
+	} else if ok {
+		typ += "!"
+	}
+	return typ, nil
+}
+
+func (p *parser) fragment() (*Fragment, error) {
+	if err := p.advance(); err != nil { // fragment
+		return nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if name == "on" {
+		return nil, fmt.Errorf("fragment cannot be named \"on\"")
+	}
+	if p.tok.kind != tokenName || p.tok.value != "on" {
+		return nil, fmt.Errorf("expected \"on\" at %d, found %s", p.tok.pos, p.describe())
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	typeCondition, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	return &Fragment{Name: name, TypeCondition: typeCondition, Selections: selections}, nil
+}
+
+func (p *parser) selectionSet() ([]Selection, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var selections []Selection
+	for !p.peek("}") {
+		if p.tok.kind == tokenEOF {
+			return nil, p.unexpected()
+		}
+		selection, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+	if len(selections) == 0 {
+		return nil, fmt.Errorf("empty selection set at %d", p.tok.pos)
+	}
+	return selections, p.advance()
+}
+
+func (p *parser) selection() (Selection, error) {
+	if ok, err := p.skip("..."); err != nil {
+		return nil, err
+	} else if ok {
+		return p.fragmentSelection()
+	}
+
+	field := &Field{}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := p.skip(":"); err != nil {
+		return nil, err
+	} else if ok {
+		field.Alias = name
+		if name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	field.Name = name
+	if field.Args, err = p.arguments(); err != nil {
+		return nil, err
+	}
+	if field.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if p.peek("{") {
+		if field.Selections, err = p.selectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return field, nil
+}
+
+// fragmentSelection은 ... 다음의 프래그먼트 전개나 인라인 프래그먼트를 읽습니다
+func (p *parser) fragmentSelection() (Selection, error) {
+	if p.tok.kind == tokenName && p.tok.value != "on" {
+		spread := &FragmentSpread{Name: p.tok.value}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var err error
+		spread.Directives, err = p.directives()
+		return spread, err
+	}
+
+	inline := &InlineFragment{}
+	if p.tok.kind == tokenName { // on
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var err error
+		if inline.TypeCondition, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if inline.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	inline.Selections, err = p.selectionSet()
+	return inline, err
+}
+
+func (p *parser) arguments() ([]Argument, error) {
+	if ok, err := p.skip("("); err != nil || !ok {
+		return nil, err
+	}
+	var args []Argument
+	for !p.peek(")") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.value(false)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, Argument{Name: name, Value: value})
+	}
+	return args, p.advance()
+}
+
+func (p *parser) directives() ([]Directive, error) {
+	var directives []Directive
+	for p.peek("@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		args, err := p.arguments()
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, Directive{Name: name, Args: args})
+	}
+	return directives, nil
+}
+
+// value는 값 하나를 읽습니다. constant면 변수를 쓸 수 없습니다 (변수 기본값).
+func (p *parser) value(constant bool) (interface{}, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+
+	tok := p.tok
+	switch {
+	case tok.kind == tokenPunct && tok.value == "$" && !constant:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		return Variable(name), err
+	case tok.kind == tokenPunct && tok.value == "[":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		list := []interface{}{}
+		for !p.peek("]") {
+			if p.tok.kind == tokenEOF {
+				return nil, p.unexpected()
+			}
+			item, err := p.value(constant)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		return list, p.advance()
+	case tok.kind == tokenPunct && tok.value == "{":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		object := map[string]interface{}{}
+		for !p.peek("}") {
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if object[name], err = p.value(constant); err != nil {
+				return nil, err
+			}
+		}
+		return object, p.advance()
+	case tok.kind == tokenInt:
+		n, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %s at %d", tok.value, tok.pos)
+		}
+		return n, p.advance()
+	case tok.kind == tokenFloat:
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid float %s at %d", tok.value, tok.pos)
+		}
+		return f, p.advance()
+	case tok.kind == tokenString:
+		return tok.value, p.advance()
+	case tok.kind == tokenName:
+		var value interface{}
+		switch tok.value {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		default:
+			value = Enum(tok.value)
+		}
+		return value, p.advance()
+	}
+	return nil, p.unexpected()
+}
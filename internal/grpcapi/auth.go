@@ -0,0 +1,95 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// peerAuthInfo 유닉스 소켓 연결의 상대 프로세스 정보
+type peerAuthInfo struct {
+	credentials.CommonAuthInfo
+	Peer *ipc.PeerCredentials
+}
+
+func (peerAuthInfo) AuthType() string { return "peercred" }
+
+// peerCredentialsTransport 유닉스 소켓 연결에서 SO_PEERCRED를 읽어 AuthInfo로 전달
+// (암호화는 하지 않으며 로컬 소켓에서만 사용한다)
+type peerCredentialsTransport struct{}
+
+func (peerCredentialsTransport) ClientHandshake(_ context.Context, _ string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return conn, peerAuthInfo{CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.NoSecurity}}, nil
+}
+
+func (peerCredentialsTransport) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	info := peerAuthInfo{CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.PrivacyAndIntegrity}}
+	if creds, err := ipc.PeerCredentialsOf(conn); err == nil {
+		info.Peer = creds
+	}
+	return conn, info, nil
+}
+
+func (peerCredentialsTransport) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: "peercred"}
+}
+
+func (t peerCredentialsTransport) Clone() credentials.TransportCredentials { return t }
+
+func (peerCredentialsTransport) OverrideServerName(string) error { return nil }
+
+// callerFromContext 요청 컨텍스트에서 권한 판단에 사용할 요청자 정보 추출
+// 토큰은 "authorization: Bearer <token>" 메타데이터로 전달한다.
+func callerFromContext(ctx context.Context) ipc.Caller {
+	var caller ipc.Caller
+
+	if p, ok := peer.FromContext(ctx); ok {
+		switch info := p.AuthInfo.(type) {
+		case peerAuthInfo:
+			caller.Peer = info.Peer
+		case credentials.TLSInfo:
+			if certs := info.State.PeerCertificates; len(certs) > 0 {
+				caller.Cert = certs[0]
+			}
+		}
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, value := range md.Get("authorization") {
+			if token, ok := strings.CutPrefix(value, "Bearer "); ok {
+				caller.Token = strings.TrimSpace(token)
+				break
+			}
+		}
+	}
+	return caller
+}
+
+// dispatchError IPC 디스패치 오류를 gRPC 상태로 변환
+func dispatchError(err error) error {
+	switch {
+	case errors.Is(err, ipc.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, ipc.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return status.Error(codes.Unimplemented, err.Error())
+}
+
+// responseError 실패한 핸들러 응답을 gRPC 상태로 변환
+func responseError(resp *ipc.Response) error {
+	code := codes.FailedPrecondition
+	if strings.Contains(resp.Error, "not found") {
+		code = codes.NotFound
+	}
+	return status.Error(code, resp.Error)
+}
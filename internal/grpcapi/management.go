@@ -0,0 +1,470 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/tmidb/tmidb-core/internal/grpcapi/managementpb"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// backupTimeLayout backup_list 응답의 생성 시각 형식 (슈퍼바이저 로컬 시간)
+const backupTimeLayout = "2006-01-02 15:04:05"
+
+// call IPC 핸들러를 실행하고 응답 데이터를 out에 디코딩 (out이 nil이면 무시)
+func (s *Server) call(ctx context.Context, msgType ipc.MessageType, data map[string]interface{}, out interface{}) error {
+	resp, err := s.dispatcher.Dispatch(callerFromContext(ctx), msgType, data)
+	if err != nil {
+		return dispatchError(err)
+	}
+	if !resp.Success {
+		return responseError(resp)
+	}
+	if out == nil || resp.Data == nil {
+		return nil
+	}
+	return decodeData(resp.Data, out)
+}
+
+// decodeData 핸들러 응답 데이터를 JSON을 거쳐 Go 타입으로 변환
+func decodeData(data interface{}, out interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to encode response: %v", err)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return status.Errorf(codes.Internal, "failed to decode response: %v", err)
+	}
+	return nil
+}
+
+func requireField(name, value string) error {
+	if value == "" {
+		return status.Errorf(codes.InvalidArgument, "%s is required", name)
+	}
+	return nil
+}
+
+// stringList 핸들러가 기대하는 JSON 배열 형태로 변환
+func stringList(values []string) []interface{} {
+	list := make([]interface{}, len(values))
+	for i, v := range values {
+		list[i] = v
+	}
+	return list
+}
+
+// ListProcesses 관리 중인 모든 프로세스 조회
+func (s *Server) ListProcesses(ctx context.Context, _ *pb.ListProcessesRequest) (*pb.ListProcessesResponse, error) {
+	var processes []ipc.ProcessInfo
+	if err := s.call(ctx, ipc.MessageTypeProcessList, nil, &processes); err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListProcessesResponse{}
+	for i := range processes {
+		resp.Processes = append(resp.Processes, processToProto(&processes[i]))
+	}
+	return resp, nil
+}
+
+// GetProcess 프로세스 상태 조회
+func (s *Server) GetProcess(ctx context.Context, req *pb.ProcessRequest) (*pb.Process, error) {
+	if err := requireField("name", req.GetName()); err != nil {
+		return nil, err
+	}
+
+	var process ipc.ProcessInfo
+	if err := s.call(ctx, ipc.MessageTypeProcessStatus, map[string]interface{}{"component": req.GetName()}, &process); err != nil {
+		return nil, err
+	}
+	return processToProto(&process), nil
+}
+
+// StartProcess 프로세스 시작
+func (s *Server) StartProcess(ctx context.Context, req *pb.ProcessRequest) (*pb.ProcessActionResponse, error) {
+	return s.processAction(ctx, ipc.MessageTypeProcessStart, req)
+}
+
+// StopProcess 프로세스 정지
+func (s *Server) StopProcess(ctx context.Context, req *pb.ProcessRequest) (*pb.ProcessActionResponse, error) {
+	return s.processAction(ctx, ipc.MessageTypeProcessStop, req)
+}
+
+// RestartProcess 프로세스 재시작
+func (s *Server) RestartProcess(ctx context.Context, req *pb.ProcessRequest) (*pb.ProcessActionResponse, error) {
+	return s.processAction(ctx, ipc.MessageTypeProcessRestart, req)
+}
+
+func (s *Server) processAction(ctx context.Context, msgType ipc.MessageType, req *pb.ProcessRequest) (*pb.ProcessActionResponse, error) {
+	if err := requireField("name", req.GetName()); err != nil {
+		return nil, err
+	}
+
+	var message string
+	if err := s.call(ctx, msgType, map[string]interface{}{"component": req.GetName()}, &message); err != nil {
+		return nil, err
+	}
+	return &pb.ProcessActionResponse{Message: message}, nil
+}
+
+// GetLogs 최근 로그 조회
+func (s *Server) GetLogs(ctx context.Context, req *pb.GetLogsRequest) (*pb.GetLogsResponse, error) {
+	if err := requireField("component", req.GetComponent()); err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{"component": req.GetComponent()}
+	if req.GetLines() > 0 {
+		data["lines"] = float64(req.GetLines())
+	}
+
+	var entries []ipc.LogEntry
+	if err := s.call(ctx, ipc.MessageTypeGetLogs, data, &entries); err != nil {
+		return nil, err
+	}
+
+	resp := &pb.GetLogsResponse{}
+	for _, entry := range entries {
+		resp.Entries = append(resp.Entries, logEntryToProto(entry))
+	}
+	return resp, nil
+}
+
+// StreamLogs 최근 로그와 이후 실시간 로그를 클라이언트가 끊을 때까지 전송
+func (s *Server) StreamLogs(req *pb.StreamLogsRequest, stream pb.Management_StreamLogsServer) error {
+	if err := requireField("component", req.GetComponent()); err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"component": req.GetComponent(),
+		"action":    "start",
+		"lines":     float64(req.GetLines()),
+		"buffer":    float64(req.GetBuffer()),
+	}
+
+	ctx := stream.Context()
+	logStream, resp, err := s.dispatcher.OpenLogStream(callerFromContext(ctx), data)
+	if err != nil {
+		return dispatchError(err)
+	}
+	if logStream == nil {
+		return responseError(resp)
+	}
+	defer s.dispatcher.RemoveLogStream(logStream.ConnID)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-logStream.Done():
+			return nil
+		case entry := <-logStream.Entries():
+			if err := stream.Send(logEntryToProto(entry)); err != nil {
+				return err
+			}
+			logStream.MarkSent()
+		}
+	}
+}
+
+// CreateBackup 백업 시작 (진행 상황은 GetBackupProgress로 조회)
+func (s *Server) CreateBackup(ctx context.Context, req *pb.CreateBackupRequest) (*pb.CreateBackupResponse, error) {
+	data := map[string]interface{}{
+		"name":       req.GetName(),
+		"components": stringList(req.GetComponents()),
+		"compress":   req.GetCompress(),
+		"output_dir": req.GetOutputDir(),
+		"encrypt":    req.GetEncrypt(),
+		"passphrase": req.GetPassphrase(),
+	}
+
+	var result struct {
+		ID   string `json:"id"`
+		Path string `json:"path"`
+	}
+	if err := s.call(ctx, ipc.MessageTypeBackupCreate, data, &result); err != nil {
+		return nil, err
+	}
+	return &pb.CreateBackupResponse{Id: result.ID, Path: result.Path}, nil
+}
+
+// ListBackups 백업 목록 조회
+func (s *Server) ListBackups(ctx context.Context, _ *pb.ListBackupsRequest) (*pb.ListBackupsResponse, error) {
+	var backups []struct {
+		ID         string   `json:"id"`
+		Name       string   `json:"name"`
+		Created    string   `json:"created"`
+		Size       int64    `json:"size"`
+		Components []string `json:"components"`
+		Compressed bool     `json:"compressed"`
+		Encrypted  bool     `json:"encrypted"`
+		Status     string   `json:"status"`
+	}
+	if err := s.call(ctx, ipc.MessageTypeBackupList, nil, &backups); err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListBackupsResponse{}
+	for _, b := range backups {
+		backup := &pb.Backup{
+			Id:         b.ID,
+			Name:       b.Name,
+			Size:       b.Size,
+			Components: b.Components,
+			Compressed: b.Compressed,
+			Encrypted:  b.Encrypted,
+			Status:     b.Status,
+		}
+		if created, err := time.ParseInLocation(backupTimeLayout, b.Created, time.Local); err == nil {
+			backup.Created = timestamppb.New(created)
+		}
+		resp.Backups = append(resp.Backups, backup)
+	}
+	return resp, nil
+}
+
+// DeleteBackup 백업 파일 삭제
+func (s *Server) DeleteBackup(ctx context.Context, req *pb.DeleteBackupRequest) (*pb.DeleteBackupResponse, error) {
+	if err := requireField("id", req.GetId()); err != nil {
+		return nil, err
+	}
+	if err := s.call(ctx, ipc.MessageTypeBackupDelete, map[string]interface{}{"id": req.GetId()}, nil); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteBackupResponse{}, nil
+}
+
+// GetBackupProgress 백업 진행 상황 조회
+func (s *Server) GetBackupProgress(ctx context.Context, req *pb.ProgressRequest) (*pb.OperationProgress, error) {
+	return s.progress(ctx, ipc.MessageTypeBackupProgress, req)
+}
+
+// RestoreBackup 복원 시작 (진행 상황은 GetRestoreProgress로 조회)
+func (s *Server) RestoreBackup(ctx context.Context, req *pb.RestoreBackupRequest) (*pb.RestoreBackupResponse, error) {
+	if err := requireField("backup", req.GetBackup()); err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{
+		"backup":     req.GetBackup(),
+		"components": stringList(req.GetComponents()),
+		"passphrase": req.GetPassphrase(),
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := s.call(ctx, ipc.MessageTypeBackupRestore, data, &result); err != nil {
+		return nil, err
+	}
+	return &pb.RestoreBackupResponse{Id: result.ID}, nil
+}
+
+// GetRestoreProgress 복원 진행 상황 조회
+func (s *Server) GetRestoreProgress(ctx context.Context, req *pb.ProgressRequest) (*pb.OperationProgress, error) {
+	return s.progress(ctx, ipc.MessageTypeRestoreProgress, req)
+}
+
+func (s *Server) progress(ctx context.Context, msgType ipc.MessageType, req *pb.ProgressRequest) (*pb.OperationProgress, error) {
+	if err := requireField("id", req.GetId()); err != nil {
+		return nil, err
+	}
+
+	var p struct {
+		ID           string     `json:"id"`
+		Status       string     `json:"status"`
+		Percent      float64    `json:"percent"`
+		Current      string     `json:"current"`
+		BytesWritten int64      `json:"bytes_written"`
+		StartTime    time.Time  `json:"start_time"`
+		EndTime      *time.Time `json:"end_time"`
+		Error        string     `json:"error"`
+	}
+	if err := s.call(ctx, msgType, map[string]interface{}{"id": req.GetId()}, &p); err != nil {
+		return nil, err
+	}
+
+	return &pb.OperationProgress{
+		Id:           p.ID,
+		Status:       p.Status,
+		Percent:      p.Percent,
+		Current:      p.Current,
+		BytesWritten: p.BytesWritten,
+		StartTime:    timestamp(p.StartTime),
+		EndTime:      optionalTimestamp(p.EndTime),
+		Error:        p.Error,
+	}, nil
+}
+
+// GetConfig 설정 조회 (key가 비어 있으면 전체 설정)
+func (s *Server) GetConfig(ctx context.Context, req *pb.GetConfigRequest) (*pb.GetConfigResponse, error) {
+	data := map[string]interface{}{}
+	if req.GetKey() != "" {
+		data["key"] = req.GetKey()
+	}
+
+	values, err := s.callStruct(ctx, ipc.MessageTypeConfigGet, data)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetConfigResponse{Values: values}, nil
+}
+
+// SetConfig 설정 값 변경 (저장 및 config.changed 이벤트 발행은 IPC 핸들러와 동일)
+func (s *Server) SetConfig(ctx context.Context, req *pb.SetConfigRequest) (*pb.SetConfigResponse, error) {
+	if err := requireField("key", req.GetKey()); err != nil {
+		return nil, err
+	}
+	if req.GetValue() == nil {
+		return nil, status.Error(codes.InvalidArgument, "value is required")
+	}
+
+	data := map[string]interface{}{
+		"key":   req.GetKey(),
+		"value": req.GetValue().AsInterface(),
+	}
+
+	var result struct {
+		NeedsRestart bool   `json:"needs_restart"`
+		Component    string `json:"component"`
+	}
+	if err := s.call(ctx, ipc.MessageTypeConfigSet, data, &result); err != nil {
+		return nil, err
+	}
+	return &pb.SetConfigResponse{NeedsRestart: result.NeedsRestart, Component: result.Component}, nil
+}
+
+// ReloadConfig 설정 파일 다시 읽기
+func (s *Server) ReloadConfig(ctx context.Context, _ *pb.ReloadConfigRequest) (*pb.ReloadConfigResponse, error) {
+	result, err := s.callStruct(ctx, ipc.MessageTypeConfigReload, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ReloadConfigResponse{Result: result}, nil
+}
+
+// callStruct 객체 형태의 응답을 google.protobuf.Struct로 변환
+func (s *Server) callStruct(ctx context.Context, msgType ipc.MessageType, data map[string]interface{}) (*structpb.Struct, error) {
+	var values map[string]interface{}
+	if err := s.call(ctx, msgType, data, &values); err != nil {
+		return nil, err
+	}
+
+	result, err := structpb.NewStruct(values)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode response: %v", err)
+	}
+	return result, nil
+}
+
+// SubscribeEvents 슈퍼바이저 이벤트를 클라이언트가 끊을 때까지 전송
+func (s *Server) SubscribeEvents(req *pb.SubscribeEventsRequest, stream pb.Management_SubscribeEventsServer) error {
+	data := map[string]interface{}{
+		"types":  stringList(req.GetTypes()),
+		"buffer": float64(req.GetBuffer()),
+	}
+
+	ctx := stream.Context()
+	eventStream, resp, err := s.dispatcher.OpenEventStream(callerFromContext(ctx), data)
+	if err != nil {
+		return dispatchError(err)
+	}
+	if eventStream == nil {
+		return responseError(resp)
+	}
+	defer s.dispatcher.RemoveEventStream(eventStream.ConnID)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-eventStream.Done():
+			return nil
+		case event := <-eventStream.Events():
+			if err := stream.Send(eventToProto(event)); err != nil {
+				return err
+			}
+			eventStream.MarkSent()
+		}
+	}
+}
+
+func processToProto(p *ipc.ProcessInfo) *pb.Process {
+	process := &pb.Process{
+		Name:        p.Name,
+		Type:        p.Type,
+		Status:      p.Status,
+		Pid:         int32(p.PID),
+		Uptime:      durationpb.New(p.Uptime),
+		MemoryBytes: p.Memory,
+		CpuPercent:  p.CPU,
+		Enabled:     p.Enabled,
+		StartTime:   timestamp(p.StartTime),
+		Restarts:    int32(p.Restarts),
+		NextRestart: optionalTimestamp(p.NextRestart),
+		Config:      p.Config,
+	}
+	if h := p.Health; h != nil {
+		process.Health = &pb.HealthCheck{
+			Type:                h.Type,
+			Healthy:             h.Healthy,
+			Degraded:            h.Degraded,
+			ConsecutiveFailures: int32(h.Failures),
+			Message:             h.Message,
+			Latency:             durationpb.New(h.Latency),
+			CheckedAt:           timestamp(h.CheckedAt),
+		}
+	}
+	return process
+}
+
+func logEntryToProto(entry ipc.LogEntry) *pb.LogEntry {
+	return &pb.LogEntry{
+		Process:   entry.Process,
+		Level:     entry.Level,
+		Message:   entry.Message,
+		Timestamp: timestamp(entry.Timestamp),
+	}
+}
+
+func eventToProto(event ipc.Event) *pb.Event {
+	e := &pb.Event{
+		Id:        event.ID,
+		Type:      string(event.Type),
+		Component: event.Component,
+		Message:   event.Message,
+		Timestamp: timestamp(event.Timestamp),
+	}
+
+	// Data에는 임의의 값이 들어 있으므로 JSON 표현으로 맞춘 뒤 변환
+	if len(event.Data) > 0 {
+		var values map[string]interface{}
+		if decodeData(event.Data, &values) == nil {
+			e.Data, _ = structpb.NewStruct(values)
+		}
+	}
+	return e
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamp(*t)
+}
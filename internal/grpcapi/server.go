@@ -0,0 +1,126 @@
+// Package grpcapi 슈퍼바이저 관리 기능을 gRPC로 제공한다.
+//
+// 각 RPC는 IPC 서버에 등록된 핸들러로 그대로 전달되므로, CLI가 사용하는
+// JSON IPC 프로토콜과 같은 동작과 권한 규칙이 적용된다. 외부 오케스트레이션
+// 도구나 다른 언어의 클라이언트는 managementpb/management.proto로 연동한다.
+package grpcapi
+
+//go:generate protoc -I managementpb --go_out=managementpb --go_opt=paths=source_relative --go-grpc_out=managementpb --go-grpc_opt=paths=source_relative management.proto
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/tmidb/tmidb-core/internal/grpcapi/managementpb"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// unixScheme 유닉스 소켓 주소 접두사 ("unix:///run/tmidb/grpc.sock")
+const unixScheme = "unix://"
+
+// Server 관리 gRPC 서버
+type Server struct {
+	managementpb.UnimplementedManagementServer
+
+	dispatcher *ipc.Server
+	grpcServer *grpc.Server
+	listener   net.Listener
+	socketPath string
+}
+
+// NewServer dispatcher에 등록된 IPC 핸들러를 사용하는 gRPC 서버 생성
+func NewServer(dispatcher *ipc.Server) *Server {
+	return &Server{dispatcher: dispatcher}
+}
+
+// Start addr에서 gRPC 요청 수신 시작
+//
+// "unix://<path>" 주소는 유닉스 소켓으로 열고 상대 프로세스의 UID/GID로 권한을
+// 판단한다. 그 외 주소는 TCP로 열며 클라이언트 인증서를 검증하는 tlsConfig가
+// 반드시 필요하다 (IPC 원격 관리와 같은 인증서 사용).
+func (s *Server) Start(addr string, tlsConfig *tls.Config) error {
+	var creds credentials.TransportCredentials
+	var listener net.Listener
+
+	if path, ok := strings.CutPrefix(addr, unixScheme); ok {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create socket directory: %w", err)
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove existing socket: %w", err)
+		}
+
+		l, err := net.Listen("unix", path)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", path, err)
+		}
+		// 권한은 요청마다 SO_PEERCRED로 판단하므로 IPC 소켓과 같이 모두 접속 가능
+		if err := os.Chmod(path, 0666); err != nil {
+			log.Printf("Warning: failed to set socket permissions: %v", err)
+		}
+
+		listener = l
+		s.socketPath = path
+		creds = peerCredentialsTransport{}
+	} else {
+		if tlsConfig == nil || tlsConfig.ClientAuth != tls.RequireAndVerifyClientCert {
+			return errors.New("gRPC over TCP requires a TLS config that verifies client certificates")
+		}
+
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", addr, err)
+		}
+
+		listener = l
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	s.listener = listener
+	s.grpcServer = grpc.NewServer(grpc.Creds(creds))
+	managementpb.RegisterManagementServer(s.grpcServer, s)
+
+	log.Printf("🛰️ gRPC management API listening on %s", describeAddr(addr, listener))
+
+	go func() {
+		if err := s.grpcServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.Printf("❌ gRPC server stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Addr 수신 중인 주소 (시작하지 않았으면 nil)
+func (s *Server) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Stop 진행 중인 스트림을 끊고 서버 종료
+func (s *Server) Stop() {
+	if s.grpcServer == nil {
+		return
+	}
+	s.grpcServer.Stop()
+	if s.socketPath != "" {
+		os.Remove(s.socketPath)
+	}
+}
+
+func describeAddr(addr string, listener net.Listener) string {
+	if strings.HasPrefix(addr, unixScheme) {
+		return addr
+	}
+	return listener.Addr().String() + " (mTLS)"
+}
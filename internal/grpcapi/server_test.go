@@ -0,0 +1,72 @@
+package grpcapi
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/tmidb/tmidb-core/internal/grpcapi/managementpb"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+func TestManagementOverUnixSocket(t *testing.T) {
+	dir := t.TempDir()
+	dispatcher := ipc.NewServer(filepath.Join(dir, "supervisor.sock"))
+	dispatcher.RegisterHandler(ipc.MessageTypeProcessList, func(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+		return ipc.NewResponse(msg.ID, true, []ipc.ProcessInfo{{Name: "api", Status: "running", PID: 42, Uptime: time.Minute}}, "")
+	})
+	dispatcher.RegisterHandler(ipc.MessageTypeProcessStop, func(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+		return ipc.NewResponse(msg.ID, false, nil, "process not found: "+msg.Data["component"].(string))
+	})
+	if err := dispatcher.SetAuth(ipc.AuthConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewServer(dispatcher)
+	addr := "unix://" + filepath.Join(dir, "grpc.sock")
+	if err := server.Start(addr, nil); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := pb.NewManagementClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.ListProcesses(ctx, &pb.ListProcessesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Processes) != 1 || resp.Processes[0].Name != "api" || resp.Processes[0].Uptime.AsDuration() != time.Minute {
+		t.Fatalf("unexpected processes: %v", resp.Processes)
+	}
+
+	_, err = client.StopProcess(ctx, &pb.ProcessRequest{Name: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+	_, err = client.StopProcess(ctx, &pb.ProcessRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+
+	// 잘못된 토큰은 소켓 상대 권한과 관계없이 거부
+	badCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer wrong")
+	_, err = client.ListProcesses(badCtx, &pb.ListProcessesRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+}
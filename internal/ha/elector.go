@@ -0,0 +1,150 @@
+package ha
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// releaseTimeout 종료할 때 임대를 놓는 제한 시간
+const releaseTimeout = 5 * time.Second
+
+// Status 선출 상태
+type Status struct {
+	Node    string    `json:"node"`
+	Role    Role      `json:"role"`
+	Leader  string    `json:"leader,omitempty"`
+	Since   time.Time `json:"since"`             // 지금 역할이 된 시각
+	Renewed time.Time `json:"renewed,omitempty"` // active가 마지막으로 임대를 갱신한 시각
+	Error   string    `json:"error,omitempty"`   // 마지막 저장소 오류
+}
+
+// Elector TTL의 1/3마다 임대를 잡거나 갱신하고, 역할이 바뀌면 알립니다.
+// 임대를 잃으면(ErrLeaseLost) 바로 standby로 내려갑니다. 저장소 오류로 갱신 결과를 모를 때는
+// TTL의 2/3까지 역할을 유지하는데, 이 유예는 만료 시각이 있는 NATS 임대에만 해당합니다.
+// PostgreSQL 락은 세션이 끝나는 즉시 풀리므로 그 저장소는 잡고 있던 세션의 오류를 ErrLeaseLost로 알립니다
+type Elector struct {
+	backend  Backend
+	node     string
+	ttl      time.Duration
+	onChange func(Role)
+
+	mu     sync.Mutex
+	status Status
+}
+
+// NewElector onChange는 선출 루프 안에서 호출되므로 오래 걸리는 작업은 고루틴으로 넘겨야 합니다
+func NewElector(backend Backend, node string, ttl time.Duration, onChange func(Role)) *Elector {
+	if ttl <= 0 {
+		ttl = DefaultLeaseTTL
+	}
+	return &Elector{
+		backend:  backend,
+		node:     node,
+		ttl:      ttl,
+		onChange: onChange,
+		status:   Status{Node: node, Role: RoleStandby, Since: time.Now()},
+	}
+}
+
+// Run ctx가 끝날 때까지 선출을 반복하고, active였다면 임대를 놓습니다
+func (e *Elector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		e.campaign(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			e.release()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Status 현재 선출 상태
+func (e *Elector) Status() Status {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.status
+}
+
+// campaign 임대를 한 번 잡거나 갱신
+func (e *Elector) campaign(ctx context.Context, now time.Time) {
+	ctx, cancel := context.WithTimeout(ctx, e.ttl/3)
+	defer cancel()
+
+	held, err := e.backend.Acquire(ctx)
+	// 잃은 임대는 잡지 않은 것과 같음 (active였다면 바로 내려감)
+	var lost error
+	if errors.Is(err, ErrLeaseLost) {
+		lost, err = err, nil
+	}
+	leader := e.node
+	if err == nil && !held {
+		leader, err = e.backend.Leader(ctx)
+	}
+
+	e.mu.Lock()
+	role := e.status.Role
+	if err != nil {
+		e.status.Error = err.Error()
+		// 갱신 결과를 모름. NATS 임대는 TTL이 지나야 만료되므로 TTL의 2/3까지는 역할 유지
+		if role == RoleActive && now.Sub(e.status.Renewed) < e.ttl*2/3 {
+			e.mu.Unlock()
+			return
+		}
+		held = false
+	} else {
+		e.status.Error = ""
+		e.status.Leader = leader
+	}
+
+	next := RoleStandby
+	if held {
+		next = RoleActive
+		e.status.Renewed = now
+	}
+	changed := next != role
+	if changed {
+		e.status.Role, e.status.Since = next, now
+	}
+	e.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	if next == RoleActive {
+		log.Printf("👑 %s acquired the leadership lease", e.node)
+	} else if lost != nil || err != nil {
+		log.Printf("⚠️ %s lost the leadership lease: %v", e.node, errors.Join(lost, err))
+	} else {
+		log.Printf("⚠️ %s lost the leadership lease to %s", e.node, leader)
+	}
+	if e.onChange != nil {
+		e.onChange(next)
+	}
+}
+
+// release active였다면 임대를 놓음
+func (e *Elector) release() {
+	e.mu.Lock()
+	active := e.status.Role == RoleActive
+	e.status.Role, e.status.Since = RoleStandby, time.Now()
+	e.mu.Unlock()
+	if !active {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+	if err := e.backend.Release(ctx); err != nil {
+		log.Printf("⚠️ Failed to release the leadership lease: %v", err)
+		return
+	}
+	log.Printf("👋 %s released the leadership lease", e.node)
+}
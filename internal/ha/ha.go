@@ -0,0 +1,166 @@
+// Package ha 두 슈퍼바이저를 active/standby로 실행하기 위한 리더 선출과 상태 복제.
+//
+// 리더 자격은 TTL이 있는 임대(lease)로 NATS JetStream KV 버킷이나 PostgreSQL advisory lock에
+// 둡니다. active는 TTL보다 짧은 주기로 임대를 갱신하고, 죽거나 갱신하지 못하면 임대가 풀려
+// standby가 이어받습니다. 같은 저장소에 active의 카탈로그를 기록해 standby가 복제해 둡니다.
+package ha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+const (
+	// DefaultLeaseTTL active가 갱신하지 못한 뒤 standby가 이어받기까지의 시간
+	DefaultLeaseTTL = 15 * time.Second
+
+	// defaultGroup 같은 임대를 두고 경쟁하는 슈퍼바이저 묶음의 기본 이름
+	defaultGroup = "tmidb"
+)
+
+// ErrLeaseLost 임대를 다른 노드가 가져갔거나 임대를 잡은 세션이 끊김 (갱신 실패와 달리 확실함)
+var ErrLeaseLost = errors.New("leadership lease lost")
+
+// groupPattern NATS 버킷 이름에 쓸 수 있는 문자만 허용
+var groupPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Role 이 노드의 역할
+type Role string
+
+const (
+	RoleStandby Role = "standby"
+	RoleActive  Role = "active"
+)
+
+// Config HA 설정
+type Config struct {
+	Backend  string        `json:"backend"`         // nats, postgresql
+	URL      string        `json:"url"`             // nats://host:4222, postgres://user:pw@host/db
+	Group    string        `json:"group,omitempty"` // 같은 임대를 두고 경쟁하는 묶음 (기본 tmidb)
+	LeaseTTL time.Duration `json:"lease_ttl"`       // 0이면 DefaultLeaseTTL
+}
+
+// configJSON lease_ttl을 "15s" 형식 문자열로 직렬화
+type configJSON struct {
+	*configAlias
+	LeaseTTL string `json:"lease_ttl,omitempty"`
+}
+
+type configAlias Config
+
+// MarshalJSON encodes the lease TTL as a string
+func (c Config) MarshalJSON() ([]byte, error) {
+	aux := configJSON{configAlias: (*configAlias)(&c)}
+	if c.LeaseTTL > 0 {
+		aux.LeaseTTL = c.LeaseTTL.String()
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON decodes the lease TTL from a string
+func (c *Config) UnmarshalJSON(data []byte) error {
+	aux := configJSON{configAlias: (*configAlias)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.LeaseTTL != "" {
+		ttl, err := time.ParseDuration(aux.LeaseTTL)
+		if err != nil {
+			return fmt.Errorf("invalid ha lease_ttl: %w", err)
+		}
+		c.LeaseTTL = ttl
+	}
+	return nil
+}
+
+// Validate 설정 검증
+func (c Config) Validate() error {
+	switch c.Backend {
+	case "nats":
+		if !strings.Contains(c.URL, "://") {
+			return errors.New("nats ha backend requires a nats:// url")
+		}
+	case "postgresql":
+		if c.URL == "" {
+			return errors.New("postgresql ha backend requires a connection url")
+		}
+	default:
+		return fmt.Errorf("unknown ha backend: %q (use nats or postgresql)", c.Backend)
+	}
+	if c.Group != "" && !groupPattern.MatchString(c.Group) {
+		return fmt.Errorf("ha group %q may only contain letters, digits, '-' and '_'", c.Group)
+	}
+	if c.LeaseTTL < 0 || (c.LeaseTTL > 0 && c.LeaseTTL < 3*time.Second) {
+		return fmt.Errorf("ha lease_ttl must be at least 3s, got %s", c.LeaseTTL)
+	}
+	return nil
+}
+
+// TTL 설정된 임대 TTL (없으면 DefaultLeaseTTL)
+func (c Config) TTL() time.Duration {
+	if c.LeaseTTL > 0 {
+		return c.LeaseTTL
+	}
+	return DefaultLeaseTTL
+}
+
+// GroupName 설정된 그룹 (없으면 기본 그룹)
+func (c Config) GroupName() string {
+	if c.Group != "" {
+		return c.Group
+	}
+	return defaultGroup
+}
+
+// Backend 임대와 복제 상태를 두는 저장소
+type Backend interface {
+	// Acquire 임대를 잡거나 갱신. 다른 노드가 잡고 있으면 false.
+	// 잡고 있던 임대를 잃은 것이 확실하면 ErrLeaseLost
+	Acquire(ctx context.Context) (bool, error)
+	// Release 잡고 있는 임대를 놓아 standby가 TTL을 기다리지 않고 이어받게 함
+	Release(ctx context.Context) error
+	// Leader 임대를 가진 노드 이름 (없으면 "")
+	Leader(ctx context.Context) (string, error)
+	// Put 복제할 상태 기록 (임대를 가진 노드만)
+	Put(ctx context.Context, key string, data []byte) error
+	// Get 복제된 상태 (없으면 nil)
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Close 연결 종료. 임대를 잡고 있었다면 TTL이 지나야 풀릴 수 있음
+	Close() error
+}
+
+// Open 설정에 맞는 저장소에 연결. node는 클러스터 안에서 고유한 이 노드 이름
+func Open(ctx context.Context, config Config, node string) (Backend, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	switch config.Backend {
+	case "nats":
+		return openNATS(ctx, config, node)
+	default:
+		return openPostgres(ctx, config, node)
+	}
+}
+
+// ProcessReplica active가 기록하고 standby가 복제하는 프로세스 목록
+type ProcessReplica struct {
+	Node      string            `json:"node"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Processes []ipc.ProcessInfo `json:"processes"`
+}
+
+// Report ha_status 응답
+type Report struct {
+	Status
+	Backend  string          `json:"backend"`
+	Group    string          `json:"group"`
+	LeaseTTL string          `json:"lease_ttl"`
+	Replica  *ProcessReplica `json:"replica,omitempty"` // standby가 마지막으로 복제한 active의 프로세스 목록
+}
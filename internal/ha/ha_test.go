@@ -0,0 +1,175 @@
+package ha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLease 노드 여러 개가 공유하는 임대
+type fakeLease struct {
+	mu     sync.Mutex
+	holder string
+	down   bool // 저장소에 닿지 않음
+}
+
+// fakeBackend 노드 하나의 fakeLease 클라이언트
+type fakeBackend struct {
+	lease *fakeLease
+	node  string
+	held  bool
+}
+
+func (b *fakeBackend) Acquire(ctx context.Context) (bool, error) {
+	b.lease.mu.Lock()
+	defer b.lease.mu.Unlock()
+	if b.lease.down {
+		return false, errors.New("connection refused")
+	}
+	switch b.lease.holder {
+	case "", b.node:
+		b.lease.holder, b.held = b.node, true
+		return true, nil
+	}
+	if b.held {
+		b.held = false
+		return false, ErrLeaseLost
+	}
+	return false, nil
+}
+
+func (b *fakeBackend) Release(ctx context.Context) error {
+	b.lease.mu.Lock()
+	defer b.lease.mu.Unlock()
+	if b.lease.holder == b.node {
+		b.lease.holder = ""
+	}
+	b.held = false
+	return nil
+}
+
+func (b *fakeBackend) Leader(ctx context.Context) (string, error) {
+	b.lease.mu.Lock()
+	defer b.lease.mu.Unlock()
+	return b.lease.holder, nil
+}
+
+func (b *fakeBackend) Put(ctx context.Context, key string, data []byte) error { return nil }
+func (b *fakeBackend) Get(ctx context.Context, key string) ([]byte, error)    { return nil, nil }
+func (b *fakeBackend) Close() error                                           { return nil }
+
+func TestElectorFailover(t *testing.T) {
+	lease := &fakeLease{}
+	var changes []string
+	record := func(node string) func(Role) {
+		return func(role Role) { changes = append(changes, node+"="+string(role)) }
+	}
+	ttl := 9 * time.Second
+	a := NewElector(&fakeBackend{lease: lease, node: "a"}, "a", ttl, record("a"))
+	b := NewElector(&fakeBackend{lease: lease, node: "b"}, "b", ttl, record("b"))
+	ctx := context.Background()
+	now := time.Now()
+
+	a.campaign(ctx, now)
+	b.campaign(ctx, now)
+	if a.Status().Role != RoleActive || b.Status().Role != RoleStandby || b.Status().Leader != "a" {
+		t.Fatalf("a = %+v, b = %+v", a.Status(), b.Status())
+	}
+
+	// 저장소에 닿지 않아도 TTL의 2/3까지는 active 유지
+	lease.mu.Lock()
+	lease.down = true
+	lease.mu.Unlock()
+	a.campaign(ctx, now.Add(3*time.Second))
+	if a.Status().Role != RoleActive || a.Status().Error == "" {
+		t.Fatalf("a after one failed renewal = %+v", a.Status())
+	}
+	a.campaign(ctx, now.Add(6*time.Second))
+	if a.Status().Role != RoleStandby {
+		t.Fatalf("a after failed renewals = %+v", a.Status())
+	}
+
+	// 임대가 만료된 뒤 b가 이어받음
+	lease.mu.Lock()
+	lease.down, lease.holder = false, ""
+	lease.mu.Unlock()
+	b.campaign(ctx, now.Add(9*time.Second))
+	a.campaign(ctx, now.Add(9*time.Second))
+	if b.Status().Role != RoleActive || a.Status().Leader != "b" {
+		t.Fatalf("a = %+v, b = %+v", a.Status(), b.Status())
+	}
+
+	// b가 놓으면 a가 바로 잡음
+	b.release()
+	a.campaign(ctx, now.Add(10*time.Second))
+	if a.Status().Role != RoleActive {
+		t.Fatalf("a after release = %+v", a.Status())
+	}
+
+	want := "a=active,a=standby,b=active,a=active"
+	if got := strings.Join(changes, ","); got != want {
+		t.Errorf("changes = %s, want %s", got, want)
+	}
+}
+
+func TestElectorLeaseLost(t *testing.T) {
+	lease := &fakeLease{}
+	var roles []Role
+	a := NewElector(&fakeBackend{lease: lease, node: "a"}, "a", 9*time.Second, func(role Role) { roles = append(roles, role) })
+	now := time.Now()
+
+	a.campaign(context.Background(), now)
+
+	// 다른 노드가 임대를 가져가면 기다리지 않고 내려감
+	lease.mu.Lock()
+	lease.holder = "b"
+	lease.mu.Unlock()
+	a.campaign(context.Background(), now.Add(time.Second))
+	if s := a.Status(); s.Role != RoleStandby || s.Leader != "b" {
+		t.Fatalf("status = %+v", s)
+	}
+	if len(roles) != 2 || roles[1] != RoleStandby {
+		t.Errorf("roles = %v", roles)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	var c Config
+	if err := json.Unmarshal([]byte(`{"backend":"nats","url":"nats://nats-ha:4222","lease_ttl":"20s"}`), &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.TTL() != 20*time.Second || c.Validate() != nil {
+		t.Errorf("config = %+v", c)
+	}
+	data, _ := json.Marshal(c)
+	if !strings.Contains(string(data), `"lease_ttl":"20s"`) {
+		t.Errorf("marshalled %s", data)
+	}
+
+	for _, bad := range []Config{
+		{Backend: "zookeeper", URL: "zk:2181"},
+		{Backend: "nats", URL: "localhost:4222"},
+		{Backend: "postgresql"},
+		{Backend: "nats", URL: "nats://localhost:4222", Group: "a.b"},
+		{Backend: "nats", URL: "nats://localhost:4222", LeaseTTL: time.Second},
+	} {
+		if bad.Validate() == nil {
+			t.Errorf("%+v: expected error", bad)
+		}
+	}
+}
+
+func TestSessionDSN(t *testing.T) {
+	got, err := sessionDSN("postgres://tmidb:pw@db:5432/tmidb?sslmode=disable", "tmidb-ha-node1", 15*time.Second)
+	if err != nil || got != "postgres://tmidb:pw@db:5432/tmidb?application_name=tmidb-ha-node1&sslmode=disable&tcp_keepalives_count=3&tcp_keepalives_idle=5&tcp_keepalives_interval=1" {
+		t.Errorf("url = %s, %v", got, err)
+	}
+	got, _ = sessionDSN("host=db dbname=tmidb", "tmidb-ha-node1", 30*time.Second)
+	if got != "host=db dbname=tmidb application_name='tmidb-ha-node1' tcp_keepalives_idle='10' tcp_keepalives_interval='3' tcp_keepalives_count='3'" {
+		t.Errorf("dsn = %s", got)
+	}
+}
@@ -0,0 +1,191 @@
+package ha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// leaderKey 임대 버킷에서 리더 이름을 담는 키
+const leaderKey = "leader"
+
+// natsBackend JetStream KV 버킷 두 개를 사용합니다. 임대 버킷은 TTL이 있어 active가 갱신하지
+// 않으면 leader 키가 사라지고, 갱신은 마지막 리비전을 조건으로 하므로 다른 노드가 잡은 임대를
+// 덮어쓰지 않습니다. 상태 버킷에는 복제할 카탈로그를 TTL 없이 두고, 기록도 임대와 키의
+// 리비전을 조건으로 하므로 임대를 잃은 노드의 늦은 기록이 새 active의 상태를 덮지 않습니다
+type natsBackend struct {
+	nc    *nats.Conn
+	lease jetstream.KeyValue
+	state jetstream.KeyValue
+	node  string
+
+	mu       sync.Mutex
+	revision uint64            // 잡고 있는 임대의 리비전 (0이면 잡지 않음)
+	written  map[string]uint64 // 이 노드가 마지막으로 기록한 상태 키의 리비전
+}
+
+func openNATS(ctx context.Context, config Config, node string) (*natsBackend, error) {
+	nc, err := nats.Connect(config.URL,
+		nats.Name("tmidb-supervisor-ha-"+node),
+		nats.Timeout(5*time.Second),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect ha backend to %s: %w", config.URL, err)
+	}
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+
+	group := config.GroupName()
+	lease, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      "tmidb_ha_lease_" + group,
+		Description: "tmiDB supervisor leadership lease",
+		TTL:         config.TTL(),
+		History:     1,
+	})
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("failed to create ha lease bucket: %w", err)
+	}
+	state, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      "tmidb_ha_state_" + group,
+		Description: "tmiDB supervisor state replicated to the standby",
+		History:     1,
+	})
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("failed to create ha state bucket: %w", err)
+	}
+
+	return &natsBackend{nc: nc, lease: lease, state: state, node: node, written: make(map[string]uint64)}, nil
+}
+
+func (b *natsBackend) Acquire(ctx context.Context) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.revision != 0 {
+		if err := b.renew(ctx); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	revision, err := b.lease.Create(ctx, leaderKey, []byte(b.node))
+	if errors.Is(err, jetstream.ErrKeyExists) {
+		// 재시작한 이 노드가 TTL 안에 돌아왔으면 그대로 이어서 잡음
+		entry, getErr := b.lease.Get(ctx, leaderKey)
+		if getErr != nil || string(entry.Value()) != b.node {
+			return false, nil
+		}
+		revision, err = b.lease.Update(ctx, leaderKey, []byte(b.node), entry.Revision())
+		if err != nil {
+			return false, nil
+		}
+	} else if err != nil {
+		return false, err
+	}
+	b.revision = revision
+	return true, nil
+}
+
+// renew 잡고 있는 임대를 마지막 리비전을 조건으로 갱신. b.mu를 잡은 채 호출
+func (b *natsBackend) renew(ctx context.Context) error {
+	revision, err := b.lease.Update(ctx, leaderKey, []byte(b.node), b.revision)
+	if err == nil {
+		b.revision = revision
+		return nil
+	}
+	// 서버가 거절했으면 리비전이 바뀐 것 (만료 후 다른 노드가 잡음)
+	var apiErr *jetstream.APIError
+	if errors.As(err, &apiErr) {
+		b.revision = 0
+		return fmt.Errorf("%w: %v", ErrLeaseLost, err)
+	}
+	return err
+}
+
+func (b *natsBackend) Release(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.revision == 0 {
+		return nil
+	}
+	revision := b.revision
+	b.revision = 0
+	return b.lease.Delete(ctx, leaderKey, jetstream.LastRevision(revision))
+}
+
+func (b *natsBackend) Leader(ctx context.Context) (string, error) {
+	entry, err := b.lease.Get(ctx, leaderKey)
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(entry.Value()), nil
+}
+
+// Put 임대를 갱신해 아직 가졌는지 확인한 뒤, 이 노드가 마지막으로 기록한 리비전을 조건으로
+// 상태를 기록. 이어받은 직후처럼 기록한 적이 없으면 지금 리비전을 읽어 조건으로 씀
+func (b *natsBackend) Put(ctx context.Context, key string, data []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.revision == 0 {
+		return ErrLeaseLost
+	}
+	if err := b.renew(ctx); err != nil {
+		return err
+	}
+
+	last, ok := b.written[key]
+	if !ok {
+		entry, err := b.state.Get(ctx, key)
+		if err == nil {
+			last = entry.Revision()
+		} else if !errors.Is(err, jetstream.ErrKeyNotFound) {
+			return err
+		}
+	}
+	var revision uint64
+	var err error
+	if last == 0 {
+		revision, err = b.state.Create(ctx, key, data)
+	} else {
+		revision, err = b.state.Update(ctx, key, data, last)
+	}
+	if err != nil {
+		// 다른 노드가 그사이 기록했음. 다음 기록에서 리비전을 다시 읽음
+		delete(b.written, key)
+		return err
+	}
+	b.written[key] = revision
+	return nil
+}
+
+func (b *natsBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	entry, err := b.state.Get(ctx, key)
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry.Value(), nil
+}
+
+func (b *natsBackend) Close() error {
+	b.nc.Close()
+	return nil
+}
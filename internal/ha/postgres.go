@@ -0,0 +1,200 @@
+package ha
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// applicationPrefix 임대를 잡은 세션의 application_name 접두사 (pg_stat_activity에서 리더 이름을 읽음)
+const applicationPrefix = "tmidb-ha-"
+
+// postgresBackend 세션 단위 advisory lock을 임대로 사용합니다. 락은 잡은 세션에 묶여 있어
+// 세션이 끝나야 풀립니다. 노드가 죽거나 네트워크가 끊기면 서버 쪽 TCP keepalive가 TTL 안에
+// 세션을 정리하도록 연결마다 tcp_keepalives_*를 지정하고, 멈춘 노드는 idle_session_timeout으로
+// 정리합니다(PostgreSQL 14 이상). 락을 놓을 때는 세션을 풀에 돌려주지 않고 닫습니다.
+// 복제 상태는 tmidb_ha_state 테이블에 두며, 락을 잡은 세션으로만 기록하므로 임대를 잃은 노드는
+// 쓰지 못합니다. NATS와 달리 만료 시각이 없으므로 잡고 있던 세션의 오류는 모두 ErrLeaseLost입니다
+type postgresBackend struct {
+	db    *sql.DB
+	group string
+	node  string
+	key   int64
+	ttl   time.Duration
+
+	mu   sync.Mutex
+	conn *sql.Conn // 락을 잡은 연결 (nil이면 잡지 않음)
+}
+
+func openPostgres(ctx context.Context, config Config, node string) (*postgresBackend, error) {
+	dsn, err := sessionDSN(config.URL, applicationPrefix+node, config.TTL())
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(4)
+
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS tmidb_ha_state (
+		grp        TEXT NOT NULL,
+		key        TEXT NOT NULL,
+		node       TEXT NOT NULL,
+		data       BYTEA NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		PRIMARY KEY (grp, key)
+	)`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to prepare ha state table: %w", err)
+	}
+
+	return &postgresBackend{db: db, group: config.GroupName(), node: node, key: lockKey(config.GroupName()), ttl: config.TTL()}, nil
+}
+
+// lockKey 그룹마다 다른 advisory lock 키. 상위 32비트를 0으로 두어 pg_locks.objid만으로 찾음
+func lockKey(group string) int64 {
+	h := fnv.New32a()
+	h.Write([]byte("tmidb-ha:" + group))
+	return int64(h.Sum32())
+}
+
+// sessionDSN URL(postgres://...)과 key=value 형식 모두에 application_name과 서버 쪽 TCP keepalive
+// 설정을 지정. 응답 없는 노드의 세션이 TTL 안에 끊기도록 idle + interval*count를 TTL보다 짧게 둠
+func sessionDSN(dsn, name string, ttl time.Duration) (string, error) {
+	params := [][2]string{
+		{"application_name", name},
+		{"tcp_keepalives_idle", strconv.Itoa(max(int(ttl/3/time.Second), 1))},
+		{"tcp_keepalives_interval", strconv.Itoa(max(int(ttl/9/time.Second), 1))},
+		{"tcp_keepalives_count", "3"},
+	}
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", fmt.Errorf("invalid ha postgresql url: %w", err)
+		}
+		q := u.Query()
+		for _, p := range params {
+			q.Set(p[0], p[1])
+		}
+		u.RawQuery = q.Encode()
+		return u.String(), nil
+	}
+	for _, p := range params {
+		dsn += " " + p[0] + "='" + strings.ReplaceAll(p[1], "'", `\'`) + "'"
+	}
+	return dsn, nil
+}
+
+// discard 연결을 풀에 돌려주지 않고 닫음. sql.Conn.Close는 세션을 풀에 돌려줄 뿐이라 세션에
+// 묶인 advisory lock이 남으므로, Raw에서 ErrBadConn을 돌려 database/sql이 연결을 버리게 함
+func discard(conn *sql.Conn) {
+	conn.Raw(func(any) error { return driver.ErrBadConn })
+	conn.Close()
+}
+
+func (b *postgresBackend) Acquire(ctx context.Context) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.conn != nil {
+		if err := b.conn.PingContext(ctx); err != nil {
+			// 세션이 살아 있는지 모르므로 닫아서 락을 확실히 놓음. 다른 노드가 이미 잡았을 수 있음
+			discard(b.conn)
+			b.conn = nil
+			return false, fmt.Errorf("%w: %v", ErrLeaseLost, err)
+		}
+		return true, nil
+	}
+
+	conn, err := b.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", b.key).Scan(&locked); err != nil {
+		// 락을 잡은 뒤 응답만 잃었을 수 있으므로 풀에 돌려주지 않음
+		discard(conn)
+		return false, err
+	}
+	if !locked {
+		conn.Close()
+		return false, nil
+	}
+	// 14 미만 서버는 모르는 설정이므로 실패해도 무시 (keepalive만으로 정리됨)
+	conn.ExecContext(ctx, "SELECT set_config('idle_session_timeout', $1, false)", strconv.FormatInt(b.ttl.Milliseconds(), 10))
+	b.conn = conn
+	return true, nil
+}
+
+func (b *postgresBackend) Release(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.conn == nil {
+		return nil
+	}
+	_, err := b.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", b.key)
+	// unlock이 실패했어도 세션을 닫으면 락이 풀림. 세션 설정이 바뀌어 있으므로 풀에도 돌려주지 않음
+	discard(b.conn)
+	b.conn = nil
+	return err
+}
+
+func (b *postgresBackend) Leader(ctx context.Context) (string, error) {
+	var name string
+	err := b.db.QueryRowContext(ctx, `SELECT a.application_name
+		FROM pg_locks l JOIN pg_stat_activity a ON a.pid = l.pid
+		WHERE l.locktype = 'advisory' AND l.classid = 0 AND l.objid::bigint = $1 AND l.objsubid = 1 AND l.granted`,
+		b.key).Scan(&name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(name, applicationPrefix), nil
+}
+
+func (b *postgresBackend) Put(ctx context.Context, key string, data []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.conn == nil {
+		return ErrLeaseLost
+	}
+	_, err := b.conn.ExecContext(ctx, `INSERT INTO tmidb_ha_state (grp, key, node, data, updated_at)
+		VALUES ($1, $2, $3, $4, now())
+		ON CONFLICT (grp, key) DO UPDATE SET node = EXCLUDED.node, data = EXCLUDED.data, updated_at = now()`,
+		b.group, key, b.node, data)
+	return err
+}
+
+func (b *postgresBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	var data []byte
+	err := b.db.QueryRowContext(ctx, "SELECT data FROM tmidb_ha_state WHERE grp = $1 AND key = $2", b.group, key).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return data, err
+}
+
+func (b *postgresBackend) Close() error {
+	b.mu.Lock()
+	if b.conn != nil {
+		discard(b.conn)
+		b.conn = nil
+	}
+	b.mu.Unlock()
+	return b.db.Close()
+}
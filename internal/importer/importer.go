@@ -0,0 +1,278 @@
+// Package importer는 CSV, NDJSON 파일을 읽어 카테고리 시계열 레코드로 바꿉니다.
+// 파일을 한 줄씩 읽으므로 큰 파일도 메모리에 모두 올리지 않고, 잘못된 줄은 줄 번호와 함께
+// RowError로 돌려주어 호출자가 나머지 줄을 계속 가져올 수 있게 합니다.
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 가져오기 형식
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// MaxMappedFields 매핑에 쓸 수 있는 필드 수
+const MaxMappedFields = 1000
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Mapping은 파일의 열(CSV) 또는 경로(NDJSON, 점으로 구분)를 레코드에 연결합니다.
+// Fields가 없으면 target_id, ts가 아닌 모든 열을 같은 이름의 payload 필드로 가져옵니다.
+// CSV에 "data."로 시작하는 열이 있으면 내보내기 API의 파일로 보고 그 열만 접두어를 떼고 가져옵니다.
+type Mapping struct {
+	TargetID  string            `json:"target_id,omitempty"` // 기본 "target_id"
+	Timestamp string            `json:"ts,omitempty"`        // 기본 "ts"
+	Fields    map[string]string `json:"fields,omitempty"`    // payload 경로(vitals.bp) → 원본 열
+}
+
+// Validate는 매핑의 경로를 확인하고 기본값을 채웁니다
+func (m *Mapping) Validate() error {
+	if m.TargetID == "" {
+		m.TargetID = "target_id"
+	}
+	if m.Timestamp == "" {
+		m.Timestamp = "ts"
+	}
+	if len(m.Fields) > MaxMappedFields {
+		return fmt.Errorf("too many mapped fields: %d (max %d)", len(m.Fields), MaxMappedFields)
+	}
+	for dest, source := range m.Fields {
+		if _, err := splitPath(dest); err != nil {
+			return fmt.Errorf("invalid field %q: %v", dest, err)
+		}
+		if source == "" {
+			return fmt.Errorf("field %q has no source column", dest)
+		}
+	}
+	return nil
+}
+
+// splitPath는 점으로 구분한 payload 경로를 나눕니다
+func splitPath(path string) ([]string, error) {
+	parts := strings.Split(path, ".")
+	for _, part := range parts {
+		if part == "" {
+			return nil, errors.New("empty path segment")
+		}
+	}
+	return parts, nil
+}
+
+// Record는 파일의 한 줄에서 읽은 관측값입니다
+type Record struct {
+	Line     int
+	TargetID string
+	Ts       time.Time
+	fields   []field
+}
+
+// field는 payload 경로 하나의 값입니다. text면 CSV 칸이라 스키마 타입에 맞춰 읽어야 합니다.
+type field struct {
+	path  []string
+	value interface{}
+	text  bool
+}
+
+// Payload는 레코드의 payload 객체를 만듭니다. CSV 칸의 문자열은 types가 돌려주는
+// 스키마 타입에 맞춰 읽습니다 (규칙은 Coerce 참고). types는 nil이어도 됩니다.
+func (r *Record) Payload(types func(path []string) []string) (map[string]interface{}, error) {
+	payload := make(map[string]interface{}, len(r.fields))
+	for _, f := range r.fields {
+		value := f.value
+		if f.text {
+			var declared []string
+			if types != nil {
+				declared = types(f.path)
+			}
+			value = Coerce(f.value.(string), declared)
+		}
+
+		object := payload
+		for i, key := range f.path[:len(f.path)-1] {
+			next, ok := object[key].(map[string]interface{})
+			if !ok {
+				if _, exists := object[key]; exists {
+					return nil, fmt.Errorf("field %s conflicts with %s", strings.Join(f.path, "."), strings.Join(f.path[:i+1], "."))
+				}
+				next = make(map[string]interface{})
+				object[key] = next
+			}
+			object = next
+		}
+		object[f.path[len(f.path)-1]] = value
+	}
+	return payload, nil
+}
+
+// RowError는 한 줄을 가져오지 못한 이유입니다. Reader는 RowError 뒤에도 다음 줄을 읽을 수 있습니다.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Reader는 파일에서 레코드를 하나씩 읽습니다. 끝나면 io.EOF를, 줄 하나가 잘못되었으면 *RowError를 반환하고
+// 그 밖의 에러(읽기 실패, 너무 긴 줄)는 더 읽을 수 없다는 뜻입니다.
+type Reader interface {
+	Next() (*Record, error)
+}
+
+// ValidFormat은 지원하는 형식인지 확인합니다
+func ValidFormat(format string) bool {
+	return format == FormatCSV || format == FormatNDJSON
+}
+
+// NewReader는 format 형식의 Reader를 만듭니다. CSV는 여기서 헤더를 읽고 매핑한 열이 있는지 확인합니다.
+func NewReader(format string, r io.Reader, mapping Mapping) (Reader, error) {
+	if err := mapping.Validate(); err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r, mapping)
+	case FormatNDJSON:
+		return newNDJSONReader(r, mapping), nil
+	default:
+		return nil, fmt.Errorf("unsupported import format %q (csv, ndjson)", format)
+	}
+}
+
+// newRecord는 target_id와 ts를 확인하고 레코드를 만듭니다
+func newRecord(line int, targetID, ts interface{}) (*Record, error) {
+	id, ok := targetID.(string)
+	if !ok || id == "" {
+		return nil, errors.New("target_id is required")
+	}
+	if !uuidPattern.MatchString(id) {
+		return nil, fmt.Errorf("invalid target_id %q", id)
+	}
+	if ts == nil || ts == "" {
+		return nil, errors.New("ts is required")
+	}
+	t, err := ParseTimestamp(ts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ts: %v", err)
+	}
+	return &Record{Line: line, TargetID: strings.ToLower(id), Ts: t}, nil
+}
+
+// 시간대가 없는 시각은 UTC로 읽음
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// ParseTimestamp는 RFC3339 시각, 시간대 없는 날짜·시각(UTC), 유닉스 초(소수 가능)를 읽습니다
+func ParseTimestamp(v interface{}) (time.Time, error) {
+	var text string
+	switch value := v.(type) {
+	case string:
+		text = strings.TrimSpace(value)
+	case json.Number:
+		text = value.String()
+	case float64:
+		text = strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return time.Time{}, fmt.Errorf("unsupported timestamp %v", v)
+	}
+
+	if isNumber(text) {
+		seconds, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		whole := int64(seconds)
+		return time.Unix(whole, int64((seconds-float64(whole))*1e9)).UTC().Round(time.Microsecond), nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", text)
+}
+
+// Coerce는 CSV 칸의 문자열을 스키마에 선언된 타입으로 읽습니다. 선언된 타입을 순서대로 시도하며:
+//   - integer, number: JSON 숫자면 숫자 (저장된 자릿수 그대로)
+//   - boolean: true, false (대소문자 무시)
+//   - object, array: {나 [로 시작하는 JSON
+//   - string: 그대로
+//
+// 선언이 없으면 숫자, 불리언, JSON 객체/배열, 문자열 순으로 시도합니다.
+// 맞는 타입이 없으면 문자열을 그대로 돌려주어 스키마 검증에서 오류가 나게 합니다.
+func Coerce(text string, types []string) interface{} {
+	if len(types) == 0 {
+		types = []string{"number", "boolean", "object", "array", "string"}
+	}
+	for _, t := range types {
+		switch t {
+		case "integer", "number":
+			if isNumber(text) {
+				return json.Number(text)
+			}
+		case "boolean":
+			if strings.EqualFold(text, "true") {
+				return true
+			}
+			if strings.EqualFold(text, "false") {
+				return false
+			}
+		case "object", "array":
+			open := byte('{')
+			if t == "array" {
+				open = '['
+			}
+			if len(text) > 0 && text[0] == open {
+				if value, err := decodeJSON([]byte(text)); err == nil {
+					return value
+				}
+			}
+		case "string":
+			return text
+		}
+	}
+	return text
+}
+
+// isNumber는 JSON 숫자 문법인지 확인합니다 (NaN, Inf, 16진수는 아님)
+func isNumber(text string) bool {
+	if text == "" || (text[0] != '-' && (text[0] < '0' || text[0] > '9')) {
+		return false
+	}
+	var n json.Number
+	return json.Unmarshal([]byte(text), &n) == nil
+}
+
+// decodeJSON은 숫자를 json.Number로 유지하여 JSON 값 하나를 읽습니다
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after JSON value")
+	}
+	return value, nil
+}
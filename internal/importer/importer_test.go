@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testTarget = "6f1c2a9e-0d4b-4c1e-9a57-2b8d3c4e5f60"
+
+// readAll은 레코드와 RowError 줄 번호를 모읍니다
+func readAll(t *testing.T, r Reader) ([]*Record, []int) {
+	t.Helper()
+	var records []*Record
+	var failed []int
+	for {
+		record, err := r.Next()
+		if err == io.EOF {
+			return records, failed
+		}
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			failed = append(failed, rowErr.Line)
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		records = append(records, record)
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	file := "\ufefftarget_id,ts,data.vitals.sys,data.note,category\n" +
+		testTarget + ",2026-03-01T12:00:00Z,120,\"x, y\",vitals\n" +
+		testTarget + ",not a time,121,,vitals\n" +
+		"bad-id,2026-03-01,122,,vitals\n" +
+		testTarget + ",1772366400,123\n" +
+		testTarget + ",2026-03-01 12:00:05,00123,,vitals\n"
+	r, err := NewReader(FormatCSV, strings.NewReader(file), Mapping{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, failed := readAll(t, r)
+	if !reflect.DeepEqual(failed, []int{3, 4, 5}) || len(records) != 2 {
+		t.Fatalf("records = %d, failed lines = %v", len(records), failed)
+	}
+
+	types := func(path []string) []string {
+		if path[len(path)-1] == "note" {
+			return []string{"string"}
+		}
+		return nil
+	}
+	payload, err := records[0].Payload(types)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"vitals": map[string]interface{}{"sys": json.Number("120")}, "note": "x, y"}
+	if !reflect.DeepEqual(payload, want) {
+		t.Errorf("payload = %#v, want %#v", payload, want)
+	}
+	if !records[1].Ts.Equal(time.Date(2026, 3, 1, 12, 0, 5, 0, time.UTC)) {
+		t.Errorf("ts = %v", records[1].Ts)
+	}
+	// 0으로 시작하는 숫자는 JSON 숫자가 아니므로 선언이 없으면 문자열
+	if payload, _ := records[1].Payload(nil); payload["vitals"].(map[string]interface{})["sys"] != "00123" {
+		t.Errorf("payload = %#v", payload)
+	}
+
+	if _, err := NewReader(FormatCSV, strings.NewReader("id,ts\n"), Mapping{}); err == nil {
+		t.Error("expected error for missing target_id column")
+	}
+}
+
+func TestNDJSONReader(t *testing.T) {
+	file := `{"device": {"id": "` + strings.ToUpper(testTarget) + `"}, "time": 1772366400.5, "temp": 21.5, "raw": {"a": 1}}` + "\n\n" +
+		"[1, 2]\n" +
+		`{"device": {"id": "` + testTarget + `"}, "temp": 20}` + "\n"
+	mapping := Mapping{TargetID: "device.id", Timestamp: "time", Fields: map[string]string{"temperature": "temp", "extra.a": "raw.a"}}
+	r, err := NewReader(FormatNDJSON, strings.NewReader(file), mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, failed := readAll(t, r)
+	if !reflect.DeepEqual(failed, []int{3, 4}) || len(records) != 1 {
+		t.Fatalf("records = %d, failed lines = %v", len(records), failed)
+	}
+	record := records[0]
+	if record.TargetID != testTarget || !record.Ts.Equal(time.Unix(1772366400, 5e8)) {
+		t.Errorf("record = %+v", record)
+	}
+	payload, _ := record.Payload(nil)
+	want := map[string]interface{}{"temperature": json.Number("21.5"), "extra": map[string]interface{}{"a": json.Number("1")}}
+	if !reflect.DeepEqual(payload, want) {
+		t.Errorf("payload = %#v, want %#v", payload, want)
+	}
+}
+
+func TestCoerce(t *testing.T) {
+	tests := []struct {
+		text  string
+		types []string
+		want  interface{}
+	}{
+		{"12.50", nil, json.Number("12.50")},
+		{"12.50", []string{"string"}, "12.50"},
+		{"TRUE", nil, true},
+		{"true", []string{"string", "boolean"}, "true"},
+		{"[1]", nil, []interface{}{json.Number("1")}},
+		{"[1]", []string{"object"}, "[1]"},
+		{"NaN", []string{"number"}, "NaN"},
+		{"0x10", nil, "0x10"},
+	}
+	for _, tt := range tests {
+		if got := Coerce(tt.text, tt.types); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Coerce(%q, %v) = %#v, want %#v", tt.text, tt.types, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MaxLineSize NDJSON 한 줄의 최대 크기
+const MaxLineSize = 16 * 1024 * 1024
+
+// csvReader는 헤더가 있는 CSV를 읽습니다
+type csvReader struct {
+	csv      *csv.Reader
+	targetID int
+	ts       int
+	columns  []csvColumn
+}
+
+// csvColumn은 payload로 가져오는 열입니다
+type csvColumn struct {
+	index int
+	path  []string
+}
+
+func newCSVReader(r io.Reader, mapping Mapping) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %v", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff") // UTF-8 BOM (엑셀에서 저장한 CSV)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		if _, dup := index[name]; dup {
+			return nil, fmt.Errorf("duplicate CSV column %q", name)
+		}
+		index[name] = i
+	}
+
+	reader := &csvReader{csv: cr, targetID: -1, ts: -1}
+	var ok bool
+	if reader.targetID, ok = index[mapping.TargetID]; !ok {
+		return nil, fmt.Errorf("CSV has no %q column for target_id", mapping.TargetID)
+	}
+	if reader.ts, ok = index[mapping.Timestamp]; !ok {
+		return nil, fmt.Errorf("CSV has no %q column for ts", mapping.Timestamp)
+	}
+
+	if len(mapping.Fields) > 0 {
+		for dest, source := range mapping.Fields {
+			i, ok := index[source]
+			if !ok {
+				return nil, fmt.Errorf("CSV has no %q column for field %s", source, dest)
+			}
+			path, _ := splitPath(dest)
+			reader.columns = append(reader.columns, csvColumn{index: i, path: path})
+		}
+		return reader, nil
+	}
+	// 내보내기 API의 CSV처럼 data. 열이 있으면 그 열만 가져옴 (category, version 같은 열은 payload가 아님)
+	exported := false
+	for _, name := range header {
+		exported = exported || strings.HasPrefix(name, "data.")
+	}
+	for i, name := range header {
+		if i == reader.targetID || i == reader.ts || name == "" || (exported && !strings.HasPrefix(name, "data.")) {
+			continue
+		}
+		path, err := splitPath(strings.TrimPrefix(name, "data."))
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV column %q: %v", name, err)
+		}
+		reader.columns = append(reader.columns, csvColumn{index: i, path: path})
+	}
+	return reader, nil
+}
+
+// Next는 다음 줄을 읽습니다. 빈 칸은 payload에서 빠집니다.
+func (r *csvReader) Next() (*Record, error) {
+	row, err := r.csv.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, &RowError{Line: parseErr.StartLine, Err: parseErr.Err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	line, _ := r.csv.FieldPos(0)
+
+	record, err := newRecord(line, row[r.targetID], row[r.ts])
+	if err != nil {
+		return nil, &RowError{Line: line, Err: err}
+	}
+	for _, column := range r.columns {
+		if value := row[column.index]; value != "" {
+			record.fields = append(record.fields, field{path: column.path, value: value, text: true})
+		}
+	}
+	return record, nil
+}
+
+// ndjsonReader는 줄마다 JSON 객체 하나인 파일을 읽습니다
+type ndjsonReader struct {
+	scanner  *bufio.Scanner
+	mapping  Mapping
+	targetID []string
+	ts       []string
+	fields   []ndjsonField
+	line     int
+}
+
+// ndjsonField는 payload 경로와 원본 경로입니다
+type ndjsonField struct {
+	path   []string
+	source []string
+}
+
+func newNDJSONReader(r io.Reader, mapping Mapping) *ndjsonReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), MaxLineSize)
+	reader := &ndjsonReader{
+		scanner:  scanner,
+		mapping:  mapping,
+		targetID: strings.Split(mapping.TargetID, "."),
+		ts:       strings.Split(mapping.Timestamp, "."),
+	}
+	for dest, source := range mapping.Fields {
+		path, _ := splitPath(dest)
+		reader.fields = append(reader.fields, ndjsonField{path: path, source: strings.Split(source, ".")})
+	}
+	return reader
+}
+
+// Next는 다음 객체를 읽습니다. 매핑에 Fields가 없으면 payload는 객체의 "payload"나 "data" 객체이고,
+// 둘 다 없으면 target_id, ts를 뺀 나머지 키입니다.
+func (r *ndjsonReader) Next() (*Record, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		value, err := decodeJSON(line)
+		if err != nil {
+			return nil, &RowError{Line: r.line, Err: fmt.Errorf("invalid JSON: %v", err)}
+		}
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, &RowError{Line: r.line, Err: errors.New("line is not a JSON object")}
+		}
+
+		record, err := newRecord(r.line, lookup(object, r.targetID), lookup(object, r.ts))
+		if err != nil {
+			return nil, &RowError{Line: r.line, Err: err}
+		}
+		if len(r.fields) > 0 {
+			for _, f := range r.fields {
+				if v := lookup(object, f.source); v != nil {
+					record.fields = append(record.fields, field{path: f.path, value: v})
+				}
+			}
+			return record, nil
+		}
+
+		payload, ok := object["payload"].(map[string]interface{})
+		if !ok {
+			payload, ok = object["data"].(map[string]interface{})
+		}
+		if !ok {
+			payload = object
+			delete(payload, r.mapping.TargetID)
+			delete(payload, r.mapping.Timestamp)
+		}
+		for key, v := range payload {
+			record.fields = append(record.fields, field{path: []string{key}, value: v})
+		}
+		return record, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("line %d is longer than %d bytes", r.line+1, MaxLineSize)
+		}
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// lookup은 객체에서 점으로 구분한 경로의 값을 찾습니다 (없으면 nil)
+func lookup(object map[string]interface{}, path []string) interface{} {
+	var value interface{} = object
+	for _, key := range path {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	return value
+}
@@ -0,0 +1,257 @@
+package ipc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"slices"
+)
+
+// Role IPC 클라이언트 권한
+type Role string
+
+const (
+	RoleNone     Role = "none"     // 어떤 요청도 허용하지 않음
+	RoleReadOnly Role = "readonly" // 상태 조회만 허용
+	RoleAdmin    Role = "admin"    // 모든 요청 허용
+)
+
+func (r Role) level() int {
+	switch r {
+	case RoleReadOnly:
+		return 1
+	case RoleAdmin:
+		return 2
+	}
+	return 0
+}
+
+// Allows required 권한이 필요한 요청을 허용하는지 확인
+func (r Role) Allows(required Role) bool {
+	return r.level() >= required.level()
+}
+
+func (r Role) valid() bool {
+	return r == RoleNone || r == RoleReadOnly || r == RoleAdmin
+}
+
+// 권한 검사 실패 오류 (gRPC 등에서 상태 코드로 구분할 수 있도록 errors.Is 지원)
+var (
+	ErrUnauthenticated  = errors.New("authentication failed")
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// PeerCredentials 소켓 상대 프로세스 정보 (SO_PEERCRED)
+type PeerCredentials struct {
+	PID int32  `json:"pid"`
+	UID uint32 `json:"uid"`
+	GID uint32 `json:"gid"`
+}
+
+// AuthToken 토큰 인증 항목. 토큰 원문은 저장하지 않고 SHA-256 해시만 보관한다.
+type AuthToken struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+	Role   Role   `json:"role"`
+}
+
+// AuthConfig IPC 인증/권한 설정
+//
+// 권한은 연결한 프로세스의 UID/GID로 먼저 정해지고 (root와 서버 실행 사용자는 항상 admin),
+// 요청에 유효한 토큰이 있으면 두 권한 중 높은 쪽이 적용된다.
+type AuthConfig struct {
+	Disabled     bool                 `json:"disabled,omitempty"`
+	AdminUIDs    []uint32             `json:"admin_uids,omitempty"`
+	AdminGIDs    []uint32             `json:"admin_gids,omitempty"`
+	ReadOnlyUIDs []uint32             `json:"readonly_uids,omitempty"`
+	ReadOnlyGIDs []uint32             `json:"readonly_gids,omitempty"`
+	DefaultRole  Role                 `json:"default_role,omitempty"` // 그 외 사용자 (기본: readonly)
+	Tokens       []AuthToken          `json:"tokens,omitempty"`
+	Permissions  map[MessageType]Role `json:"permissions,omitempty"` // 메시지 타입별 필요 권한 재정의
+}
+
+// Validate 설정 검증
+func (c AuthConfig) Validate() error {
+	if c.DefaultRole != "" && !c.DefaultRole.valid() {
+		return fmt.Errorf("unknown default role: %q", c.DefaultRole)
+	}
+	for _, token := range c.Tokens {
+		if token.Name == "" {
+			return errors.New("auth token requires a name")
+		}
+		if !token.Role.valid() {
+			return fmt.Errorf("auth token %s: unknown role %q", token.Name, token.Role)
+		}
+		if sum, err := hex.DecodeString(token.SHA256); err != nil || len(sum) != sha256.Size {
+			return fmt.Errorf("auth token %s: sha256 must be a hex-encoded SHA-256 digest", token.Name)
+		}
+	}
+	for msgType, role := range c.Permissions {
+		if !role.valid() {
+			return fmt.Errorf("permission for %s: unknown role %q", msgType, role)
+		}
+	}
+	return nil
+}
+
+// peerRole 연결한 프로세스의 UID/GID에 따른 권한
+func (c AuthConfig) peerRole(peer *PeerCredentials) Role {
+	defaultRole := c.DefaultRole
+	if defaultRole == "" {
+		defaultRole = RoleReadOnly
+	}
+	if peer == nil {
+		return defaultRole
+	}
+
+	switch {
+	case peer.UID == 0 || peer.UID == uint32(os.Getuid()):
+		return RoleAdmin
+	case slices.Contains(c.AdminUIDs, peer.UID) || slices.Contains(c.AdminGIDs, peer.GID):
+		return RoleAdmin
+	case slices.Contains(c.ReadOnlyUIDs, peer.UID) || slices.Contains(c.ReadOnlyGIDs, peer.GID):
+		return RoleReadOnly
+	}
+	return defaultRole
+}
+
+// tokenRole 토큰에 해당하는 권한 (일치하는 토큰이 없으면 false)
+func (c AuthConfig) tokenRole(token string) (string, Role, bool) {
+	sum := sha256.Sum256([]byte(token))
+	for _, t := range c.Tokens {
+		expected, err := hex.DecodeString(t.SHA256)
+		if err != nil {
+			continue
+		}
+		if subtle.ConstantTimeCompare(sum[:], expected) == 1 {
+			return t.Name, t.Role, true
+		}
+	}
+	return "", RoleNone, false
+}
+
+// RequiredRole 메시지 타입 처리에 필요한 권한
+func (c AuthConfig) RequiredRole(msgType MessageType) Role {
+	if role, ok := c.Permissions[msgType]; ok {
+		return role
+	}
+	switch msgType {
+	case MessageTypeLogStream, MessageTypeEventSubscribe, MessageTypeAuthWhoAmI:
+		return RoleReadOnly
+	}
+	if msgType.IsReadOnly() {
+		return RoleReadOnly
+	}
+	return RoleAdmin
+}
+
+// HashToken 설정 파일에 저장할 토큰 해시
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateToken 새 임의 토큰 생성
+func GenerateToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// SetAuth 인증/권한 설정 적용 (Disabled이면 모든 요청 허용)
+func (s *Server) SetAuth(config AuthConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	s.authMutex.Lock()
+	defer s.authMutex.Unlock()
+
+	if config.Disabled {
+		s.auth = nil
+		return nil
+	}
+	s.auth = &config
+	return nil
+}
+
+// authorize 연결과 토큰의 권한으로 메시지를 처리할 수 있는지 확인
+// 확인 후 토큰은 핸들러에 전달되지 않도록 지운다.
+func (s *Server) authorize(conn *Connection, msg *Message) error {
+	token := msg.Token
+	msg.Token = ""
+
+	s.authMutex.RLock()
+	auth := s.auth
+	s.authMutex.RUnlock()
+
+	if auth == nil {
+		conn.Role = RoleAdmin
+		return nil
+	}
+
+	// 원격 연결은 인증서에 기록된 권한, 로컬 연결은 UID/GID에 따른 권한
+	role := auth.peerRole(conn.Peer)
+	if conn.certRole != "" {
+		role = conn.certRole
+	}
+	if token != "" {
+		name, tokenRole, ok := auth.tokenRole(token)
+		if !ok {
+			log.Printf("🔒 Rejected %s from %s: invalid token", msg.Type, describeConn(conn))
+			return fmt.Errorf("%w: invalid token", ErrUnauthenticated)
+		}
+		if tokenRole.level() > role.level() {
+			role = tokenRole
+		}
+		conn.TokenName = name
+	}
+	conn.Role = role
+
+	required := auth.RequiredRole(msg.Type)
+	if !role.Allows(required) {
+		log.Printf("🔒 Denied %s from %s (role %s, requires %s)", msg.Type, describeConn(conn), role, required)
+		return fmt.Errorf("%w: %s requires %s role (current role: %s)", ErrPermissionDenied, msg.Type, required, role)
+	}
+	return nil
+}
+
+// handleWhoAmI 현재 연결의 인증 정보 반환
+func (s *Server) handleWhoAmI(conn *Connection, msg *Message) *Response {
+	s.authMutex.RLock()
+	enabled := s.auth != nil
+	s.authMutex.RUnlock()
+
+	return NewResponse(msg.ID, true, map[string]interface{}{
+		"peer":         conn.Peer,
+		"role":         conn.Role,
+		"token":        conn.TokenName,
+		"cert":         conn.CertName,
+		"auth_enabled": enabled,
+	}, "")
+}
+
+// PeerCredentialsOf 유닉스 소켓 연결의 상대 프로세스 정보 (다른 전송 계층의 권한 판단용)
+func PeerCredentialsOf(conn net.Conn) (*PeerCredentials, error) {
+	return peerCredentials(conn)
+}
+
+func describeConn(conn *Connection) string {
+	switch {
+	case conn.CertName != "" && conn.Conn != nil:
+		return fmt.Sprintf("%s (%s)", conn.CertName, conn.Conn.RemoteAddr())
+	case conn.CertName != "":
+		return conn.CertName
+	case conn.Peer != nil:
+		return fmt.Sprintf("pid %d (uid %d, gid %d)", conn.Peer.PID, conn.Peer.UID, conn.Peer.GID)
+	}
+	return "unknown peer"
+}
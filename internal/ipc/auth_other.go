@@ -0,0 +1,13 @@
+//go:build !linux
+
+package ipc
+
+import (
+	"errors"
+	"net"
+)
+
+// peerCredentials 리눅스 외 플랫폼에서는 SO_PEERCRED를 지원하지 않음
+func peerCredentials(conn net.Conn) (*PeerCredentials, error) {
+	return nil, errors.New("peer credentials are only supported on linux")
+}
@@ -0,0 +1,79 @@
+package ipc
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuthorizeByPeerAndToken(t *testing.T) {
+	token := "s3cret"
+	server := NewServer(filepath.Join(t.TempDir(), "supervisor.sock"))
+	if err := server.SetAuth(AuthConfig{
+		AdminGIDs: []uint32{500},
+		Tokens:    []AuthToken{{Name: "ci", SHA256: HashToken(token), Role: RoleAdmin}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	other := &PeerCredentials{PID: 42, UID: 12345, GID: 12345}
+	cases := []struct {
+		name    string
+		peer    *PeerCredentials
+		msgType MessageType
+		token   string
+		allowed bool
+	}{
+		{"readonly peer reads", other, MessageTypeProcessList, "", true},
+		{"readonly peer streams logs", other, MessageTypeLogStream, "", true},
+		{"readonly peer cannot stop", other, MessageTypeProcessStop, "", false},
+		{"readonly peer cannot restore", other, MessageTypeBackupRestore, "", false},
+		{"admin group", &PeerCredentials{UID: 12345, GID: 500}, MessageTypeProcessStop, "", true},
+		{"root", &PeerCredentials{UID: 0, GID: 0}, MessageTypeBackupRestore, "", true},
+		{"token grants admin", other, MessageTypeProcessStop, token, true},
+		{"invalid token", other, MessageTypeProcessList, "wrong", false},
+	}
+
+	for _, c := range cases {
+		conn := &Connection{Peer: c.peer}
+		msg := &Message{Type: c.msgType, Token: c.token}
+		err := server.authorize(conn, msg)
+		if (err == nil) != c.allowed {
+			t.Errorf("%s: authorize = %v, want allowed=%v", c.name, err, c.allowed)
+		}
+		if msg.Token != "" {
+			t.Errorf("%s: token must be cleared before the handler runs", c.name)
+		}
+	}
+}
+
+func TestAuthPermissionOverrideAndDisabled(t *testing.T) {
+	server := NewServer(filepath.Join(t.TempDir(), "supervisor.sock"))
+	server.SetAuth(AuthConfig{
+		DefaultRole:  RoleNone,
+		ReadOnlyUIDs: []uint32{1000},
+		Permissions:  map[MessageType]Role{MessageTypeProcessRestart: RoleReadOnly},
+	})
+
+	if err := server.authorize(&Connection{Peer: &PeerCredentials{UID: 1000}}, &Message{Type: MessageTypeProcessRestart}); err != nil {
+		t.Errorf("override should allow readonly restart: %v", err)
+	}
+	err := server.authorize(&Connection{Peer: &PeerCredentials{UID: 2000}}, &Message{Type: MessageTypeProcessList})
+	if err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("default role none should deny, got %v", err)
+	}
+
+	server.SetAuth(AuthConfig{Disabled: true})
+	if err := server.authorize(&Connection{}, &Message{Type: MessageTypeBackupRestore}); err != nil {
+		t.Errorf("disabled auth should allow everything: %v", err)
+	}
+}
+
+func TestAuthConfigValidate(t *testing.T) {
+	if err := (AuthConfig{Tokens: []AuthToken{{Name: "x", SHA256: "abc", Role: RoleAdmin}}}).Validate(); err == nil {
+		t.Error("expected invalid hash to be rejected")
+	}
+	if err := (AuthConfig{DefaultRole: "root"}).Validate(); err == nil {
+		t.Error("expected unknown role to be rejected")
+	}
+}
@@ -3,7 +3,9 @@ package ipc
 import (
 	"bufio"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"runtime"
@@ -23,13 +25,28 @@ type Client struct {
 	cancel      context.CancelFunc
 	connected   bool
 	connMux     sync.RWMutex
+	opts        ClientOptions
+
+	// 원격 모드: mTLS로 remoteAddr에 접속 (tlsConfig가 nil이면 socketPath 사용)
+	remoteAddr string
+	tlsConfig  *tls.Config
+
+	// 세션 모드: 요청마다 연결하지 않고 session_open으로 연 연결 하나를 재사용
+	keepAlive  bool
+	session    *sessionConn
+	sessionMux sync.Mutex
 
 	// Go 1.24 기능: 자원 관리
 	cleanup func()
 }
 
-// NewClient 새로운 IPC 클라이언트 생성
+// NewClient 기본 타임아웃/재시도 설정으로 새로운 IPC 클라이언트 생성
 func NewClient(socketPath string) *Client {
+	return NewClientWithOptions(socketPath, DefaultClientOptions())
+}
+
+// NewClientWithOptions 새로운 IPC 클라이언트 생성
+func NewClientWithOptions(socketPath string, opts ClientOptions) *Client {
 	if socketPath == "" {
 		socketPath = DefaultSocketPath
 	}
@@ -41,6 +58,7 @@ func NewClient(socketPath string) *Client {
 		responses:  make(map[string]chan *Response),
 		ctx:        ctx,
 		cancel:     cancel,
+		opts:       opts.withDefaults(),
 	}
 
 	// Go 1.24 기능: 클라이언트 정리를 위한 finalizer 설정
@@ -56,6 +74,14 @@ func NewClient(socketPath string) *Client {
 	return client
 }
 
+// NewRemoteClient 원격 슈퍼바이저(host:port)에 mTLS로 접속하는 클라이언트 생성
+func NewRemoteClient(addr string, tlsConfig *tls.Config, opts ClientOptions) *Client {
+	client := NewClientWithOptions("", opts)
+	client.remoteAddr = addr
+	client.tlsConfig = tlsConfig
+	return client
+}
+
 // Connect 서버에 연결
 func (c *Client) Connect() error {
 	c.connMux.Lock()
@@ -65,10 +91,10 @@ func (c *Client) Connect() error {
 		return nil
 	}
 
-	// Unix Domain Socket 연결
-	conn, err := net.Dial("unix", c.socketPath)
+	// Unix Domain Socket 연결 (슈퍼바이저가 재시작 중이면 백오프하며 재시도)
+	conn, err := c.dial(c.ctx)
 	if err != nil {
-		return fmt.Errorf("failed to connect to supervisor: %w", err)
+		return err
 	}
 
 	c.conn = conn
@@ -85,6 +111,7 @@ func (c *Client) Connect() error {
 // Close 연결 종료
 func (c *Client) Close() error {
 	c.cancel()
+	c.closeSession()
 
 	c.connMux.Lock()
 	defer c.connMux.Unlock()
@@ -107,17 +134,17 @@ func (c *Client) Close() error {
 
 // SendMessage 메시지 전송
 func (c *Client) SendMessage(msgType MessageType, data map[string]interface{}) (*Response, error) {
-	// CLI 명령어의 경우 새로운 연결 생성
-	conn, err := net.Dial("unix", c.socketPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to supervisor: %w", err)
-	}
-	// defer를 제거하고 명시적으로 연결 종료
-
-	reader := bufio.NewReader(conn)
-	writer := bufio.NewWriter(conn)
+	return c.SendMessageContext(context.Background(), msgType, data)
+}
 
+// SendMessageContext 메시지 전송
+//
+// 요청마다 새 연결을 사용한다. 연결에 실패하면 백오프하며 재시도하고, 응답을 받기 전에
+// 연결이 끊긴 경우에는 읽기 전용 메시지만 다시 보낸다 (상태 변경 요청의 중복 실행 방지).
+// 응답 대기는 RequestTimeout과 ctx의 deadline 중 이른 쪽에서 끝난다.
+func (c *Client) SendMessageContext(ctx context.Context, msgType MessageType, data map[string]interface{}) (*Response, error) {
 	msg := NewMessage(msgType, data)
+	msg.Token = c.opts.Token
 
 	// JSON 직렬화
 	msgData, err := msg.ToJSON()
@@ -125,54 +152,82 @@ func (c *Client) SendMessage(msgType MessageType, data map[string]interface{}) (
 		return nil, fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	// 쓰기 타임아웃 설정
-	conn.SetWriteDeadline(time.Now().Add(WriteTimeout))
+	var lastErr error
+	for attempt := 0; attempt <= c.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleepContext(ctx, c.opts.backoff(attempt)); err != nil {
+				break
+			}
+		}
 
-	// 메시지 전송
-	_, err = writer.Write(append(msgData, '\n'))
-	if err != nil {
-		return nil, fmt.Errorf("failed to send message: %w", err)
+		resp, err := c.roundTrip(ctx, msgData)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+
+		if ctx.Err() != nil || !isRetryable(err, msgType) {
+			break
+		}
 	}
+	return nil, lastErr
+}
 
-	if err := writer.Flush(); err != nil {
-		return nil, fmt.Errorf("failed to flush message: %w", err)
+// roundTrip 새 연결로 요청 하나를 보내고 응답을 읽는다 (KeepAlive이면 세션 연결 사용)
+func (c *Client) roundTrip(ctx context.Context, msgData []byte) (*Response, error) {
+	if resp, ok, err := c.sessionRoundTrip(ctx, msgData); ok {
+		return resp, err
 	}
 
-	// 응답 읽기를 위한 채널과 고루틴 사용
-	responseChan := make(chan *Response, 1)
-	errorChan := make(chan error, 1)
+	conn, err := c.dialOnce(ctx)
+	if err != nil {
+		return nil, &errRequestNotSent{fmt.Errorf("failed to connect to supervisor: %w", err)}
+	}
+	defer conn.Close()
 
-	go func() {
-		// 고루틴 내부에서도 충분한 타임아웃 설정
-		conn.SetReadDeadline(time.Now().Add(25 * time.Second))
+	return c.exchange(ctx, conn, bufio.NewReader(conn), msgData)
+}
 
-		line, err := reader.ReadString('\n')
-		if err != nil {
-			errorChan <- fmt.Errorf("failed to read response: %w", err)
-			return
-		}
+// exchange 연결에 요청 하나를 쓰고 응답 한 줄을 읽는다
+func (c *Client) exchange(ctx context.Context, conn net.Conn, reader *bufio.Reader, msgData []byte) (*Response, error) {
+	// 컨텍스트가 취소되면 블로킹된 읽기/쓰기를 해제
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
 
-		var resp Response
-		if err := json.Unmarshal([]byte(line), &resp); err != nil {
-			errorChan <- fmt.Errorf("failed to parse response: %w", err)
-			return
-		}
+	deadline := time.Now().Add(c.opts.RequestTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
 
-		responseChan <- &resp
-	}()
+	// 메시지 전송
+	writer := bufio.NewWriter(conn)
+	if _, err := writer.Write(append(msgData, '\n')); err != nil {
+		return nil, &errRequestNotSent{fmt.Errorf("failed to send message: %w", err)}
+	}
+	if err := writer.Flush(); err != nil {
+		return nil, &errRequestNotSent{fmt.Errorf("failed to flush message: %w", err)}
+	}
 
-	// 30초 타임아웃으로 응답 대기 (프로세스 재시작 등 긴 작업을 충분히 고려)
-	select {
-	case resp := <-responseChan:
-		conn.Close() // 응답 받으면 즉시 연결 종료
-		return resp, nil
-	case err := <-errorChan:
-		conn.Close() // 오류 발생 시 즉시 연결 종료
-		return nil, err
-	case <-time.After(30 * time.Second):
-		conn.Close() // 타임아웃 시 즉시 연결 종료
-		return nil, fmt.Errorf("request timeout after 30 seconds")
+	// 응답 읽기 (프로세스 재시작 등 긴 작업은 RequestTimeout까지 대기)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, fmt.Errorf("request timeout after %s: %w", c.opts.RequestTimeout, err)
+		}
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
+
+	var resp Response
+	if err := json.Unmarshal([]byte(line), &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp, nil
 }
 
 // SendMessageAsync 비동기 메시지 전송
@@ -184,41 +239,106 @@ func (c *Client) SendMessageAsync(msgType MessageType, data map[string]interface
 	}
 
 	msg := NewMessage(msgType, data)
+	msg.Token = c.opts.Token
 	return c.sendMessage(msg)
 }
 
 // StreamLogs 로그 스트림 시작
-func (c *Client) StreamLogs(component string) (<-chan LogEntry, error) {
-	if !c.isConnected() {
-		if err := c.Connect(); err != nil {
-			return nil, err
-		}
-	}
-
-	// 로그 스트림 요청
-	data := map[string]interface{}{
+//
+// 스트림 전용 연결을 열어 유지하며, lines > 0 이면 최근 로그 lines개를 먼저 받은 뒤
+// 새 로그를 계속 수신한다. 반환된 채널은 연결이 끊기거나 Close가 호출되면 닫힌다.
+// 채널 소비가 느리면 소켓 수신이 멈추고 서버 측 tailer도 대기한다 (백프레셔).
+func (c *Client) StreamLogs(component string, lines int) (<-chan LogEntry, error) {
+	conn, reader, err := c.openStream(MessageTypeLogStream, map[string]interface{}{
 		"component": component,
 		"action":    "start",
-	}
-
-	resp, err := c.SendMessage(MessageTypeLogStream, data)
+		"lines":     lines,
+	})
 	if err != nil {
-		return nil, err
-	}
-
-	if !resp.Success {
-		return nil, fmt.Errorf("failed to start log stream: %s", resp.Error)
+		return nil, fmt.Errorf("failed to start log stream: %w", err)
 	}
 
 	// 로그 엔트리 채널 생성
 	logChan := make(chan LogEntry, 100)
 
 	// 로그 스트림 처리 고루틴 시작
-	go c.handleLogStream(logChan)
+	go c.handleLogStream(conn, reader, logChan)
 
 	return logChan, nil
 }
 
+// SubscribeEvents 슈퍼바이저 이벤트 구독
+//
+// types가 비어 있으면 모든 이벤트를 받으며, "process.*"처럼 접두사로 지정할 수 있다.
+// 반환된 채널은 연결이 끊기거나 Close가 호출되면 닫힌다.
+func (c *Client) SubscribeEvents(types []string) (<-chan Event, error) {
+	conn, reader, err := c.openStream(MessageTypeEventSubscribe, map[string]interface{}{
+		"types": types,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to events: %w", err)
+	}
+
+	eventChan := make(chan Event, 100)
+	go c.handleEventStream(conn, reader, eventChan)
+
+	return eventChan, nil
+}
+
+// openStream 스트림 전용 연결을 열고 시작 요청의 응답을 확인한다
+func (c *Client) openStream(msgType MessageType, data map[string]interface{}) (net.Conn, *bufio.Reader, error) {
+	conn, err := c.dial(c.ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	writer := bufio.NewWriter(conn)
+
+	msg := NewMessage(msgType, data)
+	msg.Token = c.opts.Token
+
+	msgData, err := msg.ToJSON()
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(WriteTimeout))
+	if _, err := writer.Write(append(msgData, '\n')); err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to send message: %w", err)
+	}
+	if err := writer.Flush(); err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to flush message: %w", err)
+	}
+
+	// 스트림 시작 응답 확인
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var resp Response
+	if err := json.Unmarshal([]byte(line), &resp); err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if !resp.Success {
+		conn.Close()
+		return nil, nil, errors.New(resp.Error)
+	}
+
+	// 이후 데이터는 무기한 대기
+	conn.SetReadDeadline(time.Time{})
+
+	return conn, reader, nil
+}
+
 // sendMessage 실제 메시지 전송
 func (c *Client) sendMessage(msg *Message) error {
 	// 뮤텍스 사용 최소화
@@ -306,26 +426,24 @@ func (c *Client) handleResponses() {
 }
 
 // handleLogStream 로그 스트림 처리
-func (c *Client) handleLogStream(logChan chan<- LogEntry) {
+func (c *Client) handleLogStream(conn net.Conn, reader *bufio.Reader, logChan chan<- LogEntry) {
 	defer close(logChan)
+	defer conn.Close()
 
-	for {
+	// 클라이언트 종료 시 블로킹된 읽기를 해제
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
 		select {
 		case <-c.ctx.Done():
-			return
-		default:
-		}
-
-		c.connMux.RLock()
-		if !c.connected || c.conn == nil {
-			c.connMux.RUnlock()
-			return
+			conn.Close()
+		case <-stop:
 		}
+	}()
 
+	for {
 		// 로그 엔트리 읽기
-		line, err := c.reader.ReadString('\n')
-		c.connMux.RUnlock()
-
+		line, err := reader.ReadString('\n')
 		if err != nil {
 			return
 		}
@@ -336,13 +454,46 @@ func (c *Client) handleLogStream(logChan chan<- LogEntry) {
 			continue // 로그가 아닌 다른 메시지일 수 있음
 		}
 
-		// 로그 채널로 전송
+		// 로그 채널로 전송 (가득 차면 소비될 때까지 대기)
 		select {
 		case logChan <- logEntry:
 		case <-c.ctx.Done():
 			return
-		default:
-			// 버퍼가 가득 찬 경우 무시
+		}
+	}
+}
+
+// handleEventStream 이벤트 스트림 처리
+func (c *Client) handleEventStream(conn net.Conn, reader *bufio.Reader, eventChan chan<- Event) {
+	defer close(eventChan)
+	defer conn.Close()
+
+	// 클라이언트 종료 시 블로킹된 읽기를 해제
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-c.ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		var event Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil || event.Type == "" {
+			continue
+		}
+
+		select {
+		case eventChan <- event:
+		case <-c.ctx.Done():
+			return
 		}
 	}
 }
@@ -383,7 +534,7 @@ func (c *Client) EnableLogs(component string) error {
 	}
 
 	if !resp.Success {
-		return fmt.Errorf(resp.Error)
+		return errors.New(resp.Error)
 	}
 
 	return nil
@@ -401,7 +552,7 @@ func (c *Client) DisableLogs(component string) error {
 	}
 
 	if !resp.Success {
-		return fmt.Errorf(resp.Error)
+		return errors.New(resp.Error)
 	}
 
 	return nil
@@ -415,7 +566,7 @@ func (c *Client) GetLogStatus() (map[string]bool, error) {
 	}
 
 	if !resp.Success {
-		return nil, fmt.Errorf(resp.Error)
+		return nil, errors.New(resp.Error)
 	}
 
 	// Convert response data to map[string]bool
@@ -488,6 +639,49 @@ func (c *Client) RestartProcess(component string) error {
 	return nil
 }
 
+// ResetRestarts 자동 재시작 카운터 및 백오프 초기화
+func (c *Client) ResetRestarts(component string) error {
+	data := map[string]interface{}{
+		"component": component,
+	}
+
+	resp, err := c.SendMessage(MessageTypeProcessResetRestarts, data)
+	if err != nil {
+		return err
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("failed to reset restarts: %s", resp.Error)
+	}
+
+	return nil
+}
+
+// GetProcessOutput 메모리에 보관된 프로세스의 최근 출력 조회 (stream: "", "stdout", "stderr")
+func (c *Client) GetProcessOutput(component, stream string, tail int) (*ProcessOutput, error) {
+	data := map[string]interface{}{
+		"component": component,
+		"stream":    stream,
+		"tail":      tail,
+	}
+
+	resp, err := c.SendMessage(MessageTypeProcessOutput, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get process output: %s", resp.Error)
+	}
+
+	jsonData, _ := json.Marshal(resp.Data)
+	var output ProcessOutput
+	if err := json.Unmarshal(jsonData, &output); err != nil {
+		return nil, fmt.Errorf("invalid response format")
+	}
+	return &output, nil
+}
+
 // StopProcess 프로세스 정지
 func (c *Client) StopProcess(component string) error {
 	data := map[string]interface{}{
@@ -0,0 +1,87 @@
+package ipc
+
+import (
+	"crypto/x509"
+	"fmt"
+	"time"
+)
+
+// Caller 소켓 연결 없이 들어온 요청(gRPC 등)의 요청자 정보
+type Caller struct {
+	Peer  *PeerCredentials  // 로컬 유닉스 소켓 상대 프로세스
+	Cert  *x509.Certificate // 원격 mTLS 클라이언트 인증서
+	Token string            // 토큰 인증 (비어 있으면 Peer/Cert로만 권한 판단)
+}
+
+// Dispatch 등록된 핸들러를 IPC 연결 없이 실행한다.
+// 권한 검사는 소켓 요청과 동일하게 적용되며, 알 수 없는 메시지 타입이나
+// 권한 오류는 error로, 핸들러 결과는 Response로 반환한다.
+func (s *Server) Dispatch(caller Caller, msgType MessageType, data map[string]interface{}) (*Response, error) {
+	resp, _, err := s.dispatch(caller, msgType, data)
+	return resp, err
+}
+
+// OpenLogStream log_stream 핸들러를 실행하고 핸들러가 만든 스트림을 반환한다.
+// 사용이 끝나면 RemoveLogStream(stream.ConnID)로 정리해야 한다.
+func (s *Server) OpenLogStream(caller Caller, data map[string]interface{}) (*LogStream, *Response, error) {
+	resp, conn, err := s.dispatch(caller, MessageTypeLogStream, data)
+	if err != nil || !resp.Success {
+		return nil, resp, err
+	}
+
+	stream := s.getLogStream(conn.ID)
+	if stream == nil {
+		return nil, resp, fmt.Errorf("log stream was not created")
+	}
+	return stream, resp, nil
+}
+
+// OpenEventStream event_subscribe 핸들러를 실행하고 구독 스트림을 반환한다.
+// 사용이 끝나면 RemoveEventStream(stream.ConnID)로 정리해야 한다.
+func (s *Server) OpenEventStream(caller Caller, data map[string]interface{}) (*EventStream, *Response, error) {
+	resp, conn, err := s.dispatch(caller, MessageTypeEventSubscribe, data)
+	if err != nil || !resp.Success {
+		return nil, resp, err
+	}
+
+	stream := s.getEventStream(conn.ID)
+	if stream == nil {
+		return nil, resp, fmt.Errorf("event stream was not created")
+	}
+	return stream, resp, nil
+}
+
+func (s *Server) dispatch(caller Caller, msgType MessageType, data map[string]interface{}) (*Response, *Connection, error) {
+	handler, exists := s.handlers[msgType]
+	if !exists {
+		return nil, nil, fmt.Errorf("unknown message type: %s", msgType)
+	}
+
+	conn := &Connection{
+		ID:       generateID(),
+		LastSeen: time.Now(),
+		Peer:     caller.Peer,
+	}
+	if caller.Cert != nil {
+		conn.CertName = caller.Cert.Subject.CommonName
+		if role, ok := certRole(caller.Cert); ok {
+			conn.certRole = role
+		}
+	}
+
+	msg := NewMessage(msgType, data)
+	if msg.Data == nil {
+		msg.Data = map[string]interface{}{}
+	}
+	msg.Token = caller.Token
+
+	if err := s.authorize(conn, msg); err != nil {
+		return nil, nil, err
+	}
+
+	resp := handler(conn, msg)
+	if resp == nil {
+		resp = NewResponse(msg.ID, true, nil, "")
+	}
+	return resp, conn, nil
+}
@@ -0,0 +1,198 @@
+package ipc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// ClientOptions 클라이언트 연결/요청 타임아웃 및 재시도 설정
+type ClientOptions struct {
+	ConnectTimeout time.Duration // 소켓 연결 시도 1회의 제한 시간
+	RequestTimeout time.Duration // 요청 전송부터 응답 수신까지의 제한 시간
+	MaxRetries     int           // 연결 실패 시 재시도 횟수 (0이면 재시도 안 함)
+	RetryBackoff   time.Duration // 첫 재시도 대기 시간 (이후 두 배씩 증가)
+	MaxBackoff     time.Duration // 재시도 대기 시간 상한
+	Token          string        // 토큰 인증 (비어 있으면 소켓 상대 UID로만 권한 판단)
+}
+
+// DefaultClientOptions 기본 설정 (슈퍼바이저 재시작 동안 약 5초까지 재연결 시도)
+func DefaultClientOptions() ClientOptions {
+	return ClientOptions{
+		ConnectTimeout: 2 * time.Second,
+		RequestTimeout: 30 * time.Second,
+		MaxRetries:     5,
+		RetryBackoff:   200 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+	}
+}
+
+// withDefaults 0 값 항목에 기본값 적용 (MaxRetries 제외)
+func (o ClientOptions) withDefaults() ClientOptions {
+	defaults := DefaultClientOptions()
+	if o.ConnectTimeout <= 0 {
+		o.ConnectTimeout = defaults.ConnectTimeout
+	}
+	if o.RequestTimeout <= 0 {
+		o.RequestTimeout = defaults.RequestTimeout
+	}
+	if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	}
+	if o.RetryBackoff <= 0 {
+		o.RetryBackoff = defaults.RetryBackoff
+	}
+	if o.MaxBackoff < o.RetryBackoff {
+		o.MaxBackoff = max(defaults.MaxBackoff, o.RetryBackoff)
+	}
+	return o
+}
+
+// backoff attempt번째 재시도 전 대기 시간 (attempt >= 1)
+func (o ClientOptions) backoff(attempt int) time.Duration {
+	delay := o.RetryBackoff
+	for i := 1; i < attempt && delay < o.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, o.MaxBackoff)
+}
+
+// readOnlyMessageTypes 여러 번 보내도 상태가 바뀌지 않아 응답 전에 연결이 끊겨도
+// 다시 보낼 수 있는 메시지 타입
+var readOnlyMessageTypes = map[MessageType]bool{
+	MessageTypeLogStatus:                true,
+	MessageTypeGetLogs:                  true,
+	MessageTypeLogSinkList:              true,
+	MessageTypeLogLevelGet:              true,
+	MessageTypeLogConfig:                true,
+	MessageTypeProcessList:              true,
+	MessageTypeProcessStatus:            true,
+	MessageTypeProcessOutput:            true,
+	MessageTypeRollingRestartStatus:     true,
+	MessageTypeUpgradeStatus:            true,
+	MessageTypeSystemHealth:             true,
+	MessageTypeSystemStats:              true,
+	MessageTypeConfigGet:                true,
+	MessageTypeConfigList:               true,
+	MessageTypeConfigValidate:           true,
+	MessageTypeConfigSecretList:         true,
+	MessageTypeConfigDoctor:             true,
+	MessageTypeBackupList:               true,
+	MessageTypeBackupVerify:             true,
+	MessageTypeBackupProgress:           true,
+	MessageTypeRestoreProgress:          true,
+	MessageTypeBackupWALStatus:          true,
+	MessageTypeDiagnoseAll:              true,
+	MessageTypeDiagnoseComponent:        true,
+	MessageTypeDiagnoseConnectivity:     true,
+	MessageTypeDiagnosePerformance:      true,
+	MessageTypeDiagnoseLogs:             true,
+	MessageTypeDiagnoseResult:           true,
+	MessageTypeDiagnoseCrashes:          true,
+	MessageTypeDiagnoseSlowQueries:      true,
+	MessageTypeCopyStatus:               true,
+	MessageTypeCopyList:                 true,
+	MessageTypeDBPolicyList:             true,
+	MessageTypeDBRollupList:             true,
+	MessageTypeDBIndexAdvise:            true,
+	MessageTypeCategorySchemaDiff:       true,
+	MessageTypeCategoryMigrationPlan:    true,
+	MessageTypeCategoryMigrationPreview: true,
+	MessageTypeCategoryMigrationStatus:  true,
+	MessageTypeTokenList:                true,
+	MessageTypeTokenAudit:               true,
+	MessageTypeOrgList:                  true,
+	MessageTypeOrgUsage:                 true,
+	MessageTypeAlertList:                true,
+	MessageTypeAlertRuleList:            true,
+	MessageTypeAlertChannelList:         true,
+	MessageTypeClusterStatus:            true,
+	MessageTypeClusterNode:              true,
+	MessageTypeHAStatus:                 true,
+	MessageTypeNATSStreams:              true,
+	MessageTypeDLQList:                  true,
+	MessageTypeDLQShow:                  true,
+	MessageTypeSetupStatus:              true,
+}
+
+// IsReadOnly 메시지가 슈퍼바이저 상태를 변경하지 않는지 확인
+func (t MessageType) IsReadOnly() bool {
+	return readOnlyMessageTypes[t]
+}
+
+// errRequestNotSent 요청이 슈퍼바이저에 전달되기 전에 실패 (항상 재시도 가능)
+type errRequestNotSent struct{ err error }
+
+func (e *errRequestNotSent) Error() string { return e.err.Error() }
+func (e *errRequestNotSent) Unwrap() error { return e.err }
+
+// isRetryable 재시도해도 안전하고 의미 있는 오류인지 판단
+func isRetryable(err error, msgType MessageType) bool {
+	if isCertificateError(err) {
+		return false
+	}
+	var notSent *errRequestNotSent
+	if errors.As(err, &notSent) {
+		return true
+	}
+	// 전송 후 응답 대기 시간 초과는 슈퍼바이저가 작업 중일 수 있으므로 재시도하지 않는다
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return false
+	}
+	return msgType.IsReadOnly()
+}
+
+// dialOnce 소켓 연결 1회 시도 (원격 모드에서는 mTLS 연결)
+func (c *Client) dialOnce(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: c.opts.ConnectTimeout}
+	if c.tlsConfig != nil {
+		tlsDialer := tls.Dialer{NetDialer: dialer, Config: c.tlsConfig}
+		return tlsDialer.DialContext(ctx, "tcp", c.remoteAddr)
+	}
+	return dialer.DialContext(ctx, "unix", c.socketPath)
+}
+
+// dial 소켓 연결, 실패하면 백오프하며 재시도
+func (c *Client) dial(ctx context.Context) (net.Conn, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleepContext(ctx, c.opts.backoff(attempt)); err != nil {
+				break
+			}
+		}
+
+		conn, err := c.dialOnce(ctx)
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+		if isCertificateError(err) {
+			break
+		}
+	}
+	return nil, fmt.Errorf("failed to connect to supervisor: %w", lastErr)
+}
+
+// isCertificateError 인증서 검증 실패는 재시도해도 해결되지 않는다
+func isCertificateError(err error) bool {
+	var certErr *tls.CertificateVerificationError
+	return errors.As(err, &certErr)
+}
+
+// sleepContext 컨텍스트가 취소되면 일찍 반환
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
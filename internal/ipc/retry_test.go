@@ -0,0 +1,108 @@
+package ipc
+
+import (
+	"bufio"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientOptionsBackoff(t *testing.T) {
+	opts := ClientOptions{RetryBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}.withDefaults()
+
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for i, w := range want {
+		if got := opts.backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+// serveOnce 요청마다 handle을 호출하는 테스트용 소켓 서버
+func serveOnce(t *testing.T, path string, handle func(conn net.Conn, line string)) {
+	t.Helper()
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				line, err := bufio.NewReader(conn).ReadString('\n')
+				if err == nil {
+					handle(conn, line)
+				}
+			}()
+		}
+	}()
+}
+
+func testOptions() ClientOptions {
+	return ClientOptions{
+		ConnectTimeout: time.Second,
+		RequestTimeout: time.Second,
+		MaxRetries:     5,
+		RetryBackoff:   20 * time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+	}
+}
+
+func TestSendMessageWaitsForSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "supervisor.sock")
+
+	// 소켓이 잠시 뒤에 생성되는 상황 (슈퍼바이저 재시작)
+	go func() {
+		time.Sleep(60 * time.Millisecond)
+		serveOnce(t, path, func(conn net.Conn, line string) {
+			conn.Write([]byte(`{"id":"1","success":true}` + "\n"))
+		})
+	}()
+
+	client := NewClientWithOptions(path, testOptions())
+	resp, err := client.SendMessage(MessageTypeProcessStart, nil)
+	if err != nil {
+		t.Fatalf("expected reconnect to succeed, got %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestSendMessageRetriesOnlyReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "supervisor.sock")
+
+	// 첫 요청은 응답 없이 연결을 끊는다
+	var requests atomic.Int32
+	serveOnce(t, path, func(conn net.Conn, line string) {
+		if requests.Add(1) == 1 {
+			return
+		}
+		conn.Write([]byte(`{"id":"1","success":true}` + "\n"))
+	})
+
+	client := NewClientWithOptions(path, testOptions())
+	if _, err := client.SendMessage(MessageTypeProcessList, nil); err != nil {
+		t.Fatalf("read-only request should be retried, got %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("expected 2 requests, got %d", got)
+	}
+
+	requests.Store(0)
+	if _, err := client.SendMessage(MessageTypeProcessRestart, nil); err == nil {
+		t.Fatalf("mutating request must not be retried after it was sent")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("expected 1 request, got %d", got)
+	}
+}
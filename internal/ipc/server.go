@@ -3,6 +3,7 @@ package ipc
 import (
 	"bufio"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -19,6 +20,9 @@ const (
 	MaxConnections    = 100
 	ReadTimeout       = 1 * time.Second
 	WriteTimeout      = 5 * time.Second
+
+	// SessionIdleTimeout session_open 이후 요청 없이 연결을 유지하는 시간
+	SessionIdleTimeout = 5 * time.Minute
 )
 
 // Server IPC 서버 구조체
@@ -28,11 +32,22 @@ type Server struct {
 	connections map[string]*Connection
 	connMutex   sync.RWMutex
 	handlers    map[MessageType]HandlerFunc
-	logStreams  map[string]chan LogEntry
+	logStreams  map[string]*LogStream
 	streamMutex sync.RWMutex
 	ctx         context.Context
 	cancel      context.CancelFunc
 
+	// 이벤트 구독 스트림
+	eventStreams map[string]*EventStream
+	eventMutex   sync.RWMutex
+
+	// 인증/권한 설정 (nil이면 검사하지 않음)
+	auth      *AuthConfig
+	authMutex sync.RWMutex
+
+	// 원격 관리용 mTLS 리스너 (선택)
+	tcpListener net.Listener
+
 	// Go 1.24 기능: 자원 관리를 위한 cleanup 함수들
 	cleanupFuncs []func()
 	cleanupMutex sync.Mutex
@@ -46,6 +61,15 @@ type Connection struct {
 	Writer   *bufio.Writer
 	LastSeen time.Time
 
+	// 상대 프로세스 정보 (SO_PEERCRED)와 마지막 요청에 적용된 권한/토큰 이름
+	Peer      *PeerCredentials
+	Role      Role
+	TokenName string
+
+	// 원격 연결의 클라이언트 인증서 CN과 인증서에 기록된 권한
+	CertName string
+	certRole Role
+
 	// Go 1.24 기능: 약한 참조를 통한 메모리 관리
 	cleanup func()
 }
@@ -65,12 +89,16 @@ func NewServer(socketPath string) *Server {
 		socketPath:   socketPath,
 		connections:  make(map[string]*Connection),
 		handlers:     make(map[MessageType]HandlerFunc),
-		logStreams:   make(map[string]chan LogEntry),
+		logStreams:   make(map[string]*LogStream),
+		eventStreams: make(map[string]*EventStream),
 		ctx:          ctx,
 		cancel:       cancel,
 		cleanupFuncs: make([]func(), 0),
 	}
 
+	// 기본 핸들러
+	server.handlers[MessageTypeAuthWhoAmI] = server.handleWhoAmI
+
 	// Go 1.24 기능: 서버 종료 시 자동 정리를 위한 finalizer 등록
 	runtime.SetFinalizer(server, (*Server).cleanup)
 
@@ -104,7 +132,7 @@ func (s *Server) Start() error {
 	log.Printf("🔌 IPC Server listening on %s", s.socketPath)
 
 	// 연결 수락 고루틴 시작
-	go s.acceptConnections()
+	go s.acceptConnections(listener)
 
 	// 연결 정리 고루틴 시작
 	go s.cleanupConnections()
@@ -119,6 +147,9 @@ func (s *Server) Stop() error {
 	if s.listener != nil {
 		s.listener.Close()
 	}
+	if s.tcpListener != nil {
+		s.tcpListener.Close()
+	}
 
 	// 모든 연결 종료
 	s.connMutex.Lock()
@@ -136,22 +167,8 @@ func (s *Server) RegisterHandler(msgType MessageType, handler HandlerFunc) {
 	s.handlers[msgType] = handler
 }
 
-// BroadcastLogEntry 로그 엔트리를 모든 스트림에 브로드캐스트
-func (s *Server) BroadcastLogEntry(entry LogEntry) {
-	s.streamMutex.RLock()
-	defer s.streamMutex.RUnlock()
-
-	for _, stream := range s.logStreams {
-		select {
-		case stream <- entry:
-		default:
-			// 버퍼가 가득 찬 경우 무시 (논블로킹)
-		}
-	}
-}
-
 // acceptConnections 연결 수락 처리
-func (s *Server) acceptConnections() {
+func (s *Server) acceptConnections(listener net.Listener) {
 	for {
 		select {
 		case <-s.ctx.Done():
@@ -159,7 +176,7 @@ func (s *Server) acceptConnections() {
 		default:
 		}
 
-		conn, err := s.listener.Accept()
+		conn, err := listener.Accept()
 		if err != nil {
 			if s.ctx.Err() != nil {
 				return // 서버가 종료되는 중
@@ -196,6 +213,17 @@ func (s *Server) handleConnection(netConn net.Conn) {
 		LastSeen: time.Now(),
 	}
 
+	// 권한 판단에 사용할 상대 정보: 원격 연결은 클라이언트 인증서, 로컬 연결은 SO_PEERCRED
+	if tlsConn, ok := netConn.(*tls.Conn); ok {
+		if err := verifyTLSClient(conn, tlsConn); err != nil {
+			log.Printf("🔒 Rejected remote connection from %s: %v", netConn.RemoteAddr(), err)
+			netConn.Close()
+			return
+		}
+	} else if peer, err := peerCredentials(netConn); err == nil {
+		conn.Peer = peer
+	}
+
 	// Go 1.24 기능: 연결별 정리 함수 설정
 	conn.cleanup = func() {
 		netConn.Close()
@@ -220,6 +248,9 @@ func (s *Server) handleConnection(netConn net.Conn) {
 		log.Printf("📱 IPC connection closed: %s", connID)
 	}()
 
+	// session_open을 받은 연결은 요청마다 닫지 않고 유지 (대화형 셸)
+	session := false
+
 	// 메시지 처리 루프
 	for {
 		select {
@@ -229,7 +260,11 @@ func (s *Server) handleConnection(netConn net.Conn) {
 		}
 
 		// 읽기 타임아웃 설정
-		netConn.SetReadDeadline(time.Now().Add(ReadTimeout))
+		if session {
+			netConn.SetReadDeadline(time.Now().Add(SessionIdleTimeout))
+		} else {
+			netConn.SetReadDeadline(time.Now().Add(ReadTimeout))
+		}
 
 		// 메시지 읽기
 		line, err := conn.Reader.ReadString('\n')
@@ -250,12 +285,143 @@ func (s *Server) handleConnection(netConn net.Conn) {
 		// 마지막 활동 시간 업데이트
 		conn.LastSeen = time.Now()
 
+		if msg.Type == MessageTypeSessionOpen {
+			session = true
+			s.sendResponse(conn, NewResponse(msg.ID, true, map[string]interface{}{
+				"idle_timeout": SessionIdleTimeout.String(),
+			}, ""))
+			continue
+		}
+
 		// 메시지 처리
 		s.handleMessage(conn, &msg)
 
-		// 로그 스트림이 아닌 일반 명령어의 경우 응답 후 연결 종료
-		if msg.Type != MessageTypeLogStream {
+		// 로그 스트림이 생성된 경우 연결을 유지하며 엔트리를 전송
+		if msg.Type == MessageTypeLogStream {
+			if stream := s.getLogStream(connID); stream != nil {
+				s.serveLogStream(conn, stream)
+				return
+			}
+		}
+
+		// 이벤트 구독도 같은 방식으로 연결 유지
+		if msg.Type == MessageTypeEventSubscribe {
+			if stream := s.getEventStream(connID); stream != nil {
+				s.serveEventStream(conn, stream)
+				return
+			}
+		}
+
+		// 일반 명령어는 한 번의 요청-응답 후 연결 종료
+		if !session {
+			return
+		}
+	}
+}
+
+// serveLogStream 로그 스트림 엔트리를 클라이언트로 전송 (블로킹)
+func (s *Server) serveLogStream(conn *Connection, stream *LogStream) {
+	defer s.RemoveLogStream(conn.ID)
+
+	// 클라이언트 종료 또는 stop 요청 감지
+	go s.watchStreamClient(conn, MessageTypeLogStream, stream.Close)
+
+	heartbeat := time.NewTicker(30 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-stream.Done():
+			return
+		case <-heartbeat.C:
+			// 유휴 스트림이 비활성 연결로 정리되지 않도록 갱신
+			conn.LastSeen = time.Now()
+		case entry := <-stream.entries:
+			data, err := json.Marshal(entry)
+			if err != nil {
+				continue
+			}
+
+			// 느린 클라이언트는 WriteTimeout 동안만 기다린다
+			conn.Conn.SetWriteDeadline(time.Now().Add(WriteTimeout))
+			if _, err := conn.Writer.Write(append(data, '\n')); err != nil {
+				return
+			}
+
+			// 버퍼에 더 남아있지 않을 때만 flush하여 시스템 콜 수를 줄인다
+			if len(stream.entries) == 0 {
+				if err := conn.Writer.Flush(); err != nil {
+					return
+				}
+			}
+
+			stream.sent.Add(1)
+			conn.LastSeen = time.Now()
+		}
+	}
+}
+
+// watchStreamClient 스트리밍 중인 연결에서 클라이언트 메시지/종료를 감시
+func (s *Server) watchStreamClient(conn *Connection, msgType MessageType, closeStream func()) {
+	defer closeStream()
+
+	// 스트림 동안에는 읽기 타임아웃을 사용하지 않음
+	conn.Conn.SetReadDeadline(time.Time{})
+
+	for {
+		line, err := conn.Reader.ReadString('\n')
+		if err != nil {
+			return // 클라이언트 연결 종료
+		}
+
+		var msg Message
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			continue
+		}
+
+		if msg.Type == msgType {
+			if action, _ := msg.Data["action"].(string); action == "stop" {
+				return
+			}
+		}
+	}
+}
+
+// serveEventStream 구독한 이벤트를 클라이언트로 전송 (블로킹)
+func (s *Server) serveEventStream(conn *Connection, stream *EventStream) {
+	defer s.RemoveEventStream(conn.ID)
+
+	go s.watchStreamClient(conn, MessageTypeEventSubscribe, stream.Close)
+
+	heartbeat := time.NewTicker(30 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-stream.Done():
 			return
+		case <-heartbeat.C:
+			conn.LastSeen = time.Now()
+		case event := <-stream.events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+
+			conn.Conn.SetWriteDeadline(time.Now().Add(WriteTimeout))
+			if _, err := conn.Writer.Write(append(data, '\n')); err != nil {
+				return
+			}
+			if err := conn.Writer.Flush(); err != nil {
+				return
+			}
+
+			stream.sent.Add(1)
+			conn.LastSeen = time.Now()
 		}
 	}
 }
@@ -269,6 +435,12 @@ func (s *Server) handleMessage(conn *Connection, msg *Message) {
 		return
 	}
 
+	// 권한 확인
+	if err := s.authorize(conn, msg); err != nil {
+		s.sendResponse(conn, NewResponse(msg.ID, false, nil, err.Error()))
+		return
+	}
+
 	// 핸들러 실행
 	response := handler(conn, msg)
 	if response != nil {
@@ -337,7 +509,10 @@ func (s *Server) removeConnection(connID string) {
 
 	// 로그 스트림도 정리
 	s.streamMutex.Lock()
-	delete(s.logStreams, connID)
+	if stream, exists := s.logStreams[connID]; exists {
+		stream.Close()
+		delete(s.logStreams, connID)
+	}
 	s.streamMutex.Unlock()
 }
 
@@ -378,12 +553,17 @@ func (s *Server) GetConnectionCount() int {
 	return len(s.connections)
 }
 
-// CreateLogStream 로그 스트림 생성
-func (s *Server) CreateLogStream(connID string) chan LogEntry {
+// CreateLogStream 로그 스트림 생성 (bufferSize <= 0 이면 기본값 사용)
+func (s *Server) CreateLogStream(connID, component string, bufferSize int) *LogStream {
 	s.streamMutex.Lock()
 	defer s.streamMutex.Unlock()
 
-	stream := make(chan LogEntry, 1000) // 버퍼 크기: 1000
+	// 같은 연결의 기존 스트림은 교체
+	if existing, exists := s.logStreams[connID]; exists {
+		existing.Close()
+	}
+
+	stream := newLogStream(connID, component, bufferSize)
 	s.logStreams[connID] = stream
 
 	return stream
@@ -395,7 +575,57 @@ func (s *Server) RemoveLogStream(connID string) {
 	defer s.streamMutex.Unlock()
 
 	if stream, exists := s.logStreams[connID]; exists {
-		close(stream)
+		stream.Close()
 		delete(s.logStreams, connID)
 	}
 }
+
+// getLogStream 연결의 로그 스트림 조회
+func (s *Server) getLogStream(connID string) *LogStream {
+	s.streamMutex.RLock()
+	defer s.streamMutex.RUnlock()
+
+	return s.logStreams[connID]
+}
+
+// GetLogStreamCount 활성 로그 스트림 수 반환
+func (s *Server) GetLogStreamCount() int {
+	s.streamMutex.RLock()
+	defer s.streamMutex.RUnlock()
+
+	return len(s.logStreams)
+}
+
+// CreateEventStream 이벤트 구독 스트림 생성 (types가 비어 있으면 모든 이벤트)
+func (s *Server) CreateEventStream(connID string, types []EventType, bufferSize int) *EventStream {
+	s.eventMutex.Lock()
+	defer s.eventMutex.Unlock()
+
+	if existing, exists := s.eventStreams[connID]; exists {
+		existing.Close()
+	}
+
+	stream := newEventStream(connID, types, bufferSize)
+	s.eventStreams[connID] = stream
+
+	return stream
+}
+
+// RemoveEventStream 이벤트 스트림 제거
+func (s *Server) RemoveEventStream(connID string) {
+	s.eventMutex.Lock()
+	defer s.eventMutex.Unlock()
+
+	if stream, exists := s.eventStreams[connID]; exists {
+		stream.Close()
+		delete(s.eventStreams, connID)
+	}
+}
+
+// getEventStream 연결의 이벤트 스트림 조회
+func (s *Server) getEventStream(connID string) *EventStream {
+	s.eventMutex.RLock()
+	defer s.eventMutex.RUnlock()
+
+	return s.eventStreams[connID]
+}
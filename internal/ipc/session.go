@@ -0,0 +1,117 @@
+package ipc
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// sessionConn session_open으로 연 연결
+type sessionConn struct {
+	conn     net.Conn
+	reader   *bufio.Reader
+	lastUsed time.Time
+}
+
+// KeepAlive 이후 요청을 연결 하나로 보낸다 (대화형 셸처럼 요청이 많은 경우).
+// 연결은 처음 요청할 때 열고, 끊기면 다음 요청에서 다시 연다.
+// session_open을 모르는 슈퍼바이저에는 요청마다 연결하는 방식으로 돌아간다.
+func (c *Client) KeepAlive() {
+	c.sessionMux.Lock()
+	defer c.sessionMux.Unlock()
+	c.keepAlive = true
+}
+
+// sessionRoundTrip 세션 연결로 요청을 보낸다. 세션을 쓰지 않으면 ok가 false다.
+func (c *Client) sessionRoundTrip(ctx context.Context, msgData []byte) (resp *Response, ok bool, err error) {
+	c.sessionMux.Lock()
+	defer c.sessionMux.Unlock()
+
+	if !c.keepAlive {
+		return nil, false, nil
+	}
+
+	if c.session != nil && !c.session.alive() {
+		c.session.conn.Close()
+		c.session = nil
+	}
+	if c.session == nil {
+		session, err := c.openSession(ctx)
+		if errors.Is(err, errSessionUnsupported) {
+			c.keepAlive = false
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, true, &errRequestNotSent{err}
+		}
+		c.session = session
+	}
+
+	resp, err = c.exchange(ctx, c.session.conn, c.session.reader, msgData)
+	if err != nil {
+		c.session.conn.Close()
+		c.session = nil
+		return nil, true, err
+	}
+	c.session.lastUsed = time.Now()
+	return resp, true, nil
+}
+
+// errSessionUnsupported 슈퍼바이저가 session_open을 처리하지 못함 (이전 버전)
+var errSessionUnsupported = errors.New("supervisor does not support session connections")
+
+// openSession 새 연결을 열고 session_open을 보낸다
+func (c *Client) openSession(ctx context.Context) (*sessionConn, error) {
+	conn, err := c.dialOnce(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to supervisor: %w", err)
+	}
+
+	msg := NewMessage(MessageTypeSessionOpen, nil)
+	msg.Token = c.opts.Token
+	msgData, err := msg.ToJSON()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := c.exchange(ctx, conn, reader, msgData)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !resp.Success {
+		conn.Close()
+		return nil, errSessionUnsupported
+	}
+	conn.SetDeadline(time.Time{})
+	return &sessionConn{conn: conn, reader: reader, lastUsed: time.Now()}, nil
+}
+
+// alive 서버가 유휴 연결을 닫았거나 재시작했는지 확인한다.
+// 잠깐 읽어 보고 타임아웃이면 연결이 살아 있는 것이다.
+func (s *sessionConn) alive() bool {
+	if time.Since(s.lastUsed) >= SessionIdleTimeout {
+		return false
+	}
+	s.conn.SetReadDeadline(time.Now().Add(time.Millisecond))
+	_, err := s.reader.Peek(1)
+	s.conn.SetReadDeadline(time.Time{})
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// closeSession 세션 연결을 닫는다
+func (c *Client) closeSession() {
+	c.sessionMux.Lock()
+	defer c.sessionMux.Unlock()
+	if c.session != nil {
+		c.session.conn.Close()
+		c.session = nil
+	}
+}
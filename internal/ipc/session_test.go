@@ -0,0 +1,76 @@
+package ipc
+
+import (
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestKeepAliveReusesConnection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "supervisor.sock")
+	server := NewServer(path)
+	connIDs := []string{}
+	server.RegisterHandler(MessageTypeProcessList, func(conn *Connection, msg *Message) *Response {
+		connIDs = append(connIDs, conn.ID)
+		return NewResponse(msg.ID, true, nil, "")
+	})
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+
+	client := NewClientWithOptions(path, testOptions())
+	client.KeepAlive()
+	defer client.Close()
+	for i := 0; i < 3; i++ {
+		if _, err := client.SendMessage(MessageTypeProcessList, nil); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	if len(connIDs) != 3 || connIDs[0] != connIDs[1] || connIDs[1] != connIDs[2] {
+		t.Fatalf("session requests used connections %v", connIDs)
+	}
+
+	// 서버가 연결을 닫으면 다음 요청에서 다시 연다
+	client.sessionMux.Lock()
+	client.session.conn.Close()
+	client.sessionMux.Unlock()
+	if _, err := client.SendMessage(MessageTypeProcessList, nil); err != nil {
+		t.Fatalf("request after reconnect: %v", err)
+	}
+	if connIDs[3] == connIDs[2] {
+		t.Fatal("expected a new connection after the session was closed")
+	}
+
+	// 세션이 없으면 요청마다 새 연결
+	plain := NewClientWithOptions(path, testOptions())
+	plain.SendMessage(MessageTypeProcessList, nil)
+	plain.SendMessage(MessageTypeProcessList, nil)
+	if connIDs[4] == connIDs[5] {
+		t.Fatal("requests without a session must not share a connection")
+	}
+}
+
+func TestKeepAliveFallsBackWithoutSessionSupport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "supervisor.sock")
+
+	// session_open을 모르는 슈퍼바이저
+	var requests atomic.Int32
+	serveOnce(t, path, func(conn net.Conn, line string) {
+		requests.Add(1)
+		conn.Write([]byte(`{"id":"1","success":false,"error":"Unknown message type"}` + "\n"))
+	})
+
+	client := NewClientWithOptions(path, testOptions())
+	client.KeepAlive()
+	if _, err := client.SendMessage(MessageTypeProcessList, nil); err != nil {
+		t.Fatalf("request should fall back to a plain connection, got %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("expected session_open and the request, got %d requests", got)
+	}
+	if client.keepAlive {
+		t.Fatal("keep-alive should be disabled after the supervisor rejected session_open")
+	}
+}
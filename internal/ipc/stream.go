@@ -0,0 +1,215 @@
+package ipc
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// DefaultLogStreamBuffer 연결별 로그 스트림 기본 버퍼 크기
+	DefaultLogStreamBuffer = 1000
+	// MaxLogStreamBuffer 클라이언트가 요청할 수 있는 최대 버퍼 크기
+	MaxLogStreamBuffer = 10000
+)
+
+// LogStream 연결별 로그 스트림
+//
+// 생산자(로그 tailer)는 Send로 엔트리를 넣고, IPC 서버는 버퍼에서 꺼내
+// 클라이언트 소켓에 기록한다. 버퍼가 가득 차면 Send가 블로킹되어 생산자가
+// 파일 읽기를 멈추므로, 느린 클라이언트가 메모리를 무한히 늘리지 않는다.
+type LogStream struct {
+	ConnID    string
+	Component string
+
+	entries chan LogEntry
+	done    chan struct{}
+	once    sync.Once
+
+	sent    atomic.Int64
+	dropped atomic.Int64
+}
+
+// newLogStream 새 로그 스트림 생성
+func newLogStream(connID, component string, bufferSize int) *LogStream {
+	if bufferSize <= 0 {
+		bufferSize = DefaultLogStreamBuffer
+	}
+	if bufferSize > MaxLogStreamBuffer {
+		bufferSize = MaxLogStreamBuffer
+	}
+
+	return &LogStream{
+		ConnID:    connID,
+		Component: component,
+		entries:   make(chan LogEntry, bufferSize),
+		done:      make(chan struct{}),
+	}
+}
+
+// Send 엔트리를 스트림에 넣는다. 버퍼가 가득 차면 공간이 생기거나
+// 스트림/컨텍스트가 종료될 때까지 대기한다 (백프레셔).
+func (ls *LogStream) Send(ctx context.Context, entry LogEntry) error {
+	select {
+	case <-ls.done:
+		return context.Canceled
+	default:
+	}
+
+	select {
+	case ls.entries <- entry:
+		return nil
+	case <-ls.done:
+		return context.Canceled
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TrySend 논블로킹 전송. 버퍼가 가득 찬 경우 엔트리를 버리고 false를 반환한다.
+func (ls *LogStream) TrySend(entry LogEntry) bool {
+	select {
+	case <-ls.done:
+		return false
+	default:
+	}
+
+	select {
+	case ls.entries <- entry:
+		return true
+	default:
+		ls.dropped.Add(1)
+		return false
+	}
+}
+
+// Entries 전송 대기 중인 엔트리 채널 (IPC 연결 외의 전송 계층에서 사용)
+func (ls *LogStream) Entries() <-chan LogEntry {
+	return ls.entries
+}
+
+// MarkSent 전송 계층이 엔트리를 전달했음을 기록
+func (ls *LogStream) MarkSent() {
+	ls.sent.Add(1)
+}
+
+// Done 스트림 종료 시 닫히는 채널
+func (ls *LogStream) Done() <-chan struct{} {
+	return ls.done
+}
+
+// Close 스트림 종료 (여러 번 호출해도 안전)
+func (ls *LogStream) Close() {
+	ls.once.Do(func() {
+		close(ls.done)
+	})
+}
+
+// Pending 버퍼에 대기 중인 엔트리 수
+func (ls *LogStream) Pending() int {
+	return len(ls.entries)
+}
+
+// Sent 클라이언트로 전송된 엔트리 수
+func (ls *LogStream) Sent() int64 {
+	return ls.sent.Load()
+}
+
+// Dropped 버퍼 초과로 버려진 엔트리 수
+func (ls *LogStream) Dropped() int64 {
+	return ls.dropped.Load()
+}
+
+// EventStream 연결별 이벤트 구독
+//
+// 이벤트 발행자가 느린 구독자 때문에 멈추지 않도록 TrySend만 제공하며,
+// 버퍼가 가득 차면 이벤트를 버리고 Dropped를 증가시킨다.
+type EventStream struct {
+	ConnID string
+	Types  []EventType // 비어 있으면 모든 이벤트
+
+	events chan Event
+	done   chan struct{}
+	once   sync.Once
+
+	sent    atomic.Int64
+	dropped atomic.Int64
+}
+
+// newEventStream 새 이벤트 스트림 생성
+func newEventStream(connID string, types []EventType, bufferSize int) *EventStream {
+	if bufferSize <= 0 {
+		bufferSize = DefaultLogStreamBuffer
+	}
+	if bufferSize > MaxLogStreamBuffer {
+		bufferSize = MaxLogStreamBuffer
+	}
+
+	return &EventStream{
+		ConnID: connID,
+		Types:  types,
+		events: make(chan Event, bufferSize),
+		done:   make(chan struct{}),
+	}
+}
+
+// Matches 구독 필터에 해당하는 이벤트인지 확인 ("process.*" 형식 접두사 지원)
+func (es *EventStream) Matches(eventType EventType) bool {
+	if len(es.Types) == 0 {
+		return true
+	}
+	for _, t := range es.Types {
+		if t == eventType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(string(t), "*"); ok && strings.HasPrefix(string(eventType), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// TrySend 논블로킹 전송. 버퍼가 가득 찬 경우 이벤트를 버리고 false를 반환한다.
+func (es *EventStream) TrySend(event Event) bool {
+	select {
+	case <-es.done:
+		return false
+	default:
+	}
+
+	select {
+	case es.events <- event:
+		return true
+	default:
+		es.dropped.Add(1)
+		return false
+	}
+}
+
+// Events 전송 대기 중인 이벤트 채널 (IPC 연결 외의 전송 계층에서 사용)
+func (es *EventStream) Events() <-chan Event {
+	return es.events
+}
+
+// MarkSent 전송 계층이 이벤트를 전달했음을 기록
+func (es *EventStream) MarkSent() {
+	es.sent.Add(1)
+}
+
+// Done 스트림 종료 시 닫히는 채널
+func (es *EventStream) Done() <-chan struct{} {
+	return es.done
+}
+
+// Close 스트림 종료 (여러 번 호출해도 안전)
+func (es *EventStream) Close() {
+	es.once.Do(func() {
+		close(es.done)
+	})
+}
+
+// Dropped 버퍼 초과로 버려진 이벤트 수
+func (es *EventStream) Dropped() int64 {
+	return es.dropped.Load()
+}
@@ -0,0 +1,257 @@
+package ipc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// DefaultCertValidity 생성하는 인증서의 기본 유효 기간
+	DefaultCertValidity = 2 * 365 * 24 * time.Hour
+
+	// tlsHandshakeTimeout 원격 연결의 TLS 핸드셰이크 제한 시간
+	tlsHandshakeTimeout = 10 * time.Second
+)
+
+// ServerTLSConfig 클라이언트 인증서를 요구하는 (mTLS) 서버 설정
+func ServerTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+	pool, err := loadCertPool(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
+
+// ClientTLSConfig 클라이언트 인증서로 서버에 접속하는 설정
+func ClientTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+	pool, err := loadCertPool(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
+
+// StartTCP mTLS 리스너를 추가로 시작 (Start 이후 호출)
+func (s *Server) StartTCP(addr string, tlsConfig *tls.Config) error {
+	if tlsConfig == nil || tlsConfig.ClientAuth != tls.RequireAndVerifyClientCert {
+		return errors.New("remote IPC requires a TLS config that verifies client certificates")
+	}
+
+	listener, err := tls.Listen("tcp", addr, tlsConfig)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	s.tcpListener = listener
+
+	log.Printf("🔐 IPC Server listening on %s (mTLS)", listener.Addr())
+
+	go s.acceptConnections(listener)
+	return nil
+}
+
+// TCPAddr mTLS 리스너 주소 (시작하지 않았으면 nil)
+func (s *Server) TCPAddr() net.Addr {
+	if s.tcpListener == nil {
+		return nil
+	}
+	return s.tcpListener.Addr()
+}
+
+// verifyTLSClient 핸드셰이크를 마치고 클라이언트 인증서 정보를 연결에 기록
+func verifyTLSClient(conn *Connection, tlsConn *tls.Conn) error {
+	tlsConn.SetDeadline(time.Now().Add(tlsHandshakeTimeout))
+	if err := tlsConn.Handshake(); err != nil {
+		return err
+	}
+	tlsConn.SetDeadline(time.Time{})
+
+	certs := tlsConn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return errors.New("no client certificate")
+	}
+
+	conn.CertName = certs[0].Subject.CommonName
+	if role, ok := certRole(certs[0]); ok {
+		conn.certRole = role
+	}
+	return nil
+}
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", caFile)
+	}
+	return pool, nil
+}
+
+// certRole 클라이언트 인증서의 OU에서 권한을 읽는다 (없으면 false)
+func certRole(cert *x509.Certificate) (Role, bool) {
+	for _, ou := range cert.Subject.OrganizationalUnit {
+		if role := Role(ou); role == RoleAdmin || role == RoleReadOnly {
+			return role, true
+		}
+	}
+	return RoleNone, false
+}
+
+// GenerateCA 자체 서명 CA 인증서와 키를 dir/ca.crt, dir/ca.key로 생성
+func GenerateCA(dir, commonName string, validFor time.Duration) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	template, err := certTemplate(commonName, validFor)
+	if err != nil {
+		return err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+	return writeKeyPair(dir, "ca", der, key)
+}
+
+// GenerateServerCert dir의 CA로 서명한 서버 인증서를 dir/<name>.crt, dir/<name>.key로 생성
+// hosts에는 클라이언트가 접속할 호스트 이름 또는 IP를 지정한다.
+// 클러스터 피어를 조회할 때 노드 자신의 클라이언트 인증서로도 사용되며, 이때 권한은 readonly이다.
+func GenerateServerCert(dir, name string, hosts []string, validFor time.Duration) error {
+	if len(hosts) == 0 {
+		return errors.New("server certificate requires at least one host")
+	}
+
+	template, err := certTemplate(hosts[0], validFor)
+	if err != nil {
+		return err
+	}
+	template.Subject.OrganizationalUnit = []string{string(RoleReadOnly)}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	return signWithCA(dir, name, template)
+}
+
+// GenerateClientCert dir의 CA로 서명한 클라이언트 인증서 생성. 권한은 OU에 기록된다.
+func GenerateClientCert(dir, name string, role Role, validFor time.Duration) error {
+	if role != RoleAdmin && role != RoleReadOnly {
+		return fmt.Errorf("invalid client role: %q", role)
+	}
+
+	template, err := certTemplate(name, validFor)
+	if err != nil {
+		return err
+	}
+	template.Subject.OrganizationalUnit = []string{string(role)}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+
+	return signWithCA(dir, name, template)
+}
+
+func certTemplate(commonName string, validFor time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	if validFor <= 0 {
+		validFor = DefaultCertValidity
+	}
+
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"tmiDB"}},
+		NotBefore:    now.Add(-5 * time.Minute),
+		NotAfter:     now.Add(validFor),
+	}, nil
+}
+
+func signWithCA(dir, name string, template *x509.Certificate) error {
+	ca, err := tls.LoadX509KeyPair(filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key"))
+	if err != nil {
+		return fmt.Errorf("failed to load CA from %s: %w", dir, err)
+	}
+	caCert, err := x509.ParseCertificate(ca.Certificate[0])
+	if err != nil {
+		return err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, ca.PrivateKey)
+	if err != nil {
+		return fmt.Errorf("failed to create certificate: %w", err)
+	}
+	return writeKeyPair(dir, name, der, key)
+}
+
+// writeKeyPair <name>.crt와 <name>.key를 기록 (키는 소유자만 읽을 수 있음)
+func writeKeyPair(dir, name string, der []byte, key *ecdsa.PrivateKey) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, name+".crt"), certPEM, 0644); err != nil {
+		return err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return os.WriteFile(filepath.Join(dir, name+".key"), keyPEM, 0600)
+}
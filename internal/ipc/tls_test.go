@@ -0,0 +1,73 @@
+package ipc
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRemoteClientMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	if err := GenerateCA(dir, "test CA", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateServerCert(dir, "server", []string{"127.0.0.1"}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateClientCert(dir, "viewer", RoleReadOnly, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewServer(filepath.Join(dir, "supervisor.sock"))
+	server.SetAuth(AuthConfig{})
+	server.RegisterHandler(MessageTypeProcessStop, func(conn *Connection, msg *Message) *Response {
+		return NewResponse(msg.ID, true, nil, "")
+	})
+
+	serverTLS, err := ServerTLSConfig(filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key"), filepath.Join(dir, "ca.crt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := server.StartTCP("127.0.0.1:0", serverTLS); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+
+	clientTLS, err := ClientTLSConfig(filepath.Join(dir, "viewer.crt"), filepath.Join(dir, "viewer.key"), filepath.Join(dir, "ca.crt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := ClientOptions{RequestTimeout: 5 * time.Second, MaxRetries: 0}
+	client := NewRemoteClient(server.TCPAddr().String(), clientTLS, opts)
+
+	resp, err := client.SendMessage(MessageTypeAuthWhoAmI, nil)
+	if err != nil {
+		t.Fatalf("whoami over mTLS failed: %v", err)
+	}
+	data, _ := resp.Data.(map[string]interface{})
+	if data["role"] != string(RoleReadOnly) || data["cert"] != "viewer" {
+		t.Fatalf("unexpected identity: %+v", data)
+	}
+
+	// 인증서의 권한(readonly)으로는 관리 요청 불가
+	resp, err = client.SendMessage(MessageTypeProcessStop, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Success {
+		t.Fatal("readonly certificate must not be allowed to stop processes")
+	}
+
+	// 다른 CA의 인증서는 거부
+	otherDir := t.TempDir()
+	GenerateCA(otherDir, "other CA", time.Hour)
+	GenerateClientCert(otherDir, "intruder", RoleAdmin, time.Hour)
+	intruderTLS, err := ClientTLSConfig(filepath.Join(otherDir, "intruder.crt"), filepath.Join(otherDir, "intruder.key"), filepath.Join(dir, "ca.crt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	intruder := NewRemoteClient(server.TCPAddr().String(), intruderTLS, opts)
+	if resp, err := intruder.SendMessage(MessageTypeProcessList, nil); err == nil {
+		t.Fatalf("certificate from another CA must be rejected, got %+v", resp)
+	}
+}
@@ -17,12 +17,24 @@ const (
 	MessageTypeLogConfig  MessageType = "log_config"
 	MessageTypeGetLogs    MessageType = "get_logs"
 
+	MessageTypeLogLevelGet  MessageType = "log_level_get"
+	MessageTypeLogLevelSet  MessageType = "log_level_set"
+	MessageTypeLogPolicySet MessageType = "log_policy_set"
+
+	MessageTypeLogSinkList    MessageType = "log_sink_list"
+	MessageTypeLogSinkEnable  MessageType = "log_sink_enable"
+	MessageTypeLogSinkDisable MessageType = "log_sink_disable"
+
 	// 프로세스 관련
-	MessageTypeProcessList    MessageType = "process_list"
-	MessageTypeProcessStatus  MessageType = "process_status"
-	MessageTypeProcessStart   MessageType = "process_start"
-	MessageTypeProcessStop    MessageType = "process_stop"
-	MessageTypeProcessRestart MessageType = "process_restart"
+	MessageTypeProcessList           MessageType = "process_list"
+	MessageTypeProcessStatus         MessageType = "process_status"
+	MessageTypeProcessStart          MessageType = "process_start"
+	MessageTypeProcessStop           MessageType = "process_stop"
+	MessageTypeProcessRestart        MessageType = "process_restart"
+	MessageTypeProcessResetRestarts  MessageType = "process_reset_restarts"
+	MessageTypeProcessRollingRestart MessageType = "process_rolling_restart"
+	MessageTypeProcessOutput         MessageType = "process_output"
+	MessageTypeRollingRestartStatus  MessageType = "process_rolling_restart_status"
 
 	// 시스템 관련
 	MessageTypeSystemHealth MessageType = "system_health"
@@ -35,6 +47,13 @@ const (
 	MessageTypeConfigReset    MessageType = "config_reset"
 	MessageTypeConfigImport   MessageType = "config_import"
 	MessageTypeConfigValidate MessageType = "config_validate"
+	MessageTypeConfigReload   MessageType = "config_reload"
+	MessageTypeConfigDoctor   MessageType = "config_doctor"
+
+	// 암호화된 설정 값 (컴포넌트 환경 변수로 전달)
+	MessageTypeConfigSecretSet    MessageType = "config_secret_set"
+	MessageTypeConfigSecretDelete MessageType = "config_secret_delete"
+	MessageTypeConfigSecretList   MessageType = "config_secret_list"
 
 	// 백업 관련
 	MessageTypeBackupCreate    MessageType = "backup_create"
@@ -44,6 +63,9 @@ const (
 	MessageTypeBackupVerify    MessageType = "backup_verify"
 	MessageTypeBackupProgress  MessageType = "backup_progress"
 	MessageTypeRestoreProgress MessageType = "restore_progress"
+	MessageTypeBackupBase      MessageType = "backup_base"
+	MessageTypeBackupPITR      MessageType = "backup_pitr"
+	MessageTypeBackupWALStatus MessageType = "backup_wal_status"
 
 	// 진단 관련
 	MessageTypeDiagnoseAll          MessageType = "diagnose_all"
@@ -53,6 +75,11 @@ const (
 	MessageTypeDiagnoseLogs         MessageType = "diagnose_logs"
 	MessageTypeDiagnoseFix          MessageType = "diagnose_fix"
 	MessageTypeDiagnoseResult       MessageType = "diagnose_result"
+	MessageTypeDiagnoseCrashes      MessageType = "diagnose_crashes"
+	MessageTypeDiagnoseProfile      MessageType = "diagnose_profile"
+
+	MessageTypeDiagnoseSlowQueries      MessageType = "diagnose_slow_queries"
+	MessageTypeDiagnoseSlowQueriesReset MessageType = "diagnose_slow_queries_reset"
 
 	// 복사 관련
 	MessageTypeCopyReceive MessageType = "copy_receive"
@@ -61,6 +88,87 @@ const (
 	MessageTypeCopyList    MessageType = "copy_list"
 	MessageTypeCopyStop    MessageType = "copy_stop"
 
+	// 시계열 DB 정책 관련
+	MessageTypeDBPolicyList   MessageType = "db_policy_list"
+	MessageTypeDBPolicySet    MessageType = "db_policy_set"
+	MessageTypeDBPolicyRemove MessageType = "db_policy_remove"
+	MessageTypeDBPolicyApply  MessageType = "db_policy_apply"
+
+	// 롤업 작업 관련
+	MessageTypeDBRollupList   MessageType = "db_rollup_list"
+	MessageTypeDBRollupSet    MessageType = "db_rollup_set"
+	MessageTypeDBRollupRemove MessageType = "db_rollup_remove"
+
+	// 인덱스 추천 관련
+	MessageTypeDBIndexAdvise MessageType = "db_index_advise"
+	MessageTypeDBIndexApply  MessageType = "db_index_apply"
+
+	// 마이그레이션 관련
+	MessageTypeCategorySchemaDiff       MessageType = "category_schema_diff"
+	MessageTypeCategoryMigrationPlan    MessageType = "category_migration_plan"
+	MessageTypeCategoryMigrationPreview MessageType = "category_migration_preview"
+	MessageTypeCategoryMigrationRun     MessageType = "category_migration_run"
+	MessageTypeCategoryMigrationStatus  MessageType = "category_migration_status"
+	MessageTypeMigrationRollback        MessageType = "migration_rollback"
+
+	// API 토큰 관련
+	MessageTypeTokenList   MessageType = "token_list"
+	MessageTypeTokenRotate MessageType = "token_rotate"
+	MessageTypeTokenExpiry MessageType = "token_expiry"
+	MessageTypeTokenRevoke MessageType = "token_revoke"
+	MessageTypeTokenAudit  MessageType = "token_audit"
+
+	// 조직 관련
+	MessageTypeOrgList   MessageType = "org_list"
+	MessageTypeOrgCreate MessageType = "org_create"
+	MessageTypeOrgDelete MessageType = "org_delete"
+	MessageTypeOrgUsage  MessageType = "org_usage"
+
+	// 이벤트 관련
+	MessageTypeEventSubscribe MessageType = "event_subscribe"
+
+	// 알림 관련
+	MessageTypeAlertList          MessageType = "alert_list"
+	MessageTypeAlertRuleList      MessageType = "alert_rule_list"
+	MessageTypeAlertRuleSet       MessageType = "alert_rule_set"
+	MessageTypeAlertRuleDelete    MessageType = "alert_rule_delete"
+	MessageTypeAlertChannelList   MessageType = "alert_channel_list"
+	MessageTypeAlertChannelSet    MessageType = "alert_channel_set"
+	MessageTypeAlertChannelDelete MessageType = "alert_channel_delete"
+	MessageTypeAlertTest          MessageType = "alert_test"
+
+	// 인증 관련
+	MessageTypeAuthWhoAmI MessageType = "auth_whoami"
+
+	// 클러스터 관련
+	MessageTypeClusterStatus MessageType = "cluster_status" // 모든 노드 상태
+	MessageTypeClusterNode   MessageType = "cluster_node"   // 이 노드의 상태 (피어 조회용)
+	MessageTypeHAStatus      MessageType = "ha_status"      // active/standby 역할과 복제 상태
+
+	// 초기 설정 관련
+	MessageTypeSetupInit   MessageType = "setup_init"
+	MessageTypeSetupStatus MessageType = "setup_status"
+
+	// JetStream 관련
+	MessageTypeNATSStreams   MessageType = "nats_streams"   // 스트림과 소비자 상태
+	MessageTypeNATSReconcile MessageType = "nats_reconcile" // 선언된 스트림 다시 적용
+
+	// 수집 dead-letter 큐 (TMIDB_INGEST_DLQ)
+	MessageTypeDLQList   MessageType = "dlq_list"   // 실패한 메시지와 사유
+	MessageTypeDLQShow   MessageType = "dlq_show"   // 메시지 하나의 본문과 헤더
+	MessageTypeDLQReplay MessageType = "dlq_replay" // 수집 주제로 다시 보냄 (본문 수정 가능)
+	MessageTypeDLQDelete MessageType = "dlq_delete"
+
+	// 수집 처리량 벤치마크
+	MessageTypeBenchIngest MessageType = "bench_ingest"
+
+	// 업그레이드 관련
+	MessageTypeUpgradeApply  MessageType = "upgrade_apply"  // 서명된 번들로 바이너리 교체와 롤링 재시작
+	MessageTypeUpgradeStatus MessageType = "upgrade_status" // 업그레이드 진행 상황
+
+	// 세션 연결 (서버가 직접 처리: 이후 요청에도 연결을 유지)
+	MessageTypeSessionOpen MessageType = "session_open"
+
 	// 응답
 	MessageTypeResponse MessageType = "response"
 	MessageTypeError    MessageType = "error"
@@ -72,6 +180,7 @@ type Message struct {
 	Type      MessageType            `json:"type"`
 	Data      map[string]interface{} `json:"data,omitempty"`
 	Timestamp time.Time              `json:"timestamp"`
+	Token     string                 `json:"token,omitempty"` // 토큰 인증 (서버가 권한 확인 후 지움)
 }
 
 // Response IPC 응답 구조체
@@ -84,25 +193,68 @@ type Response struct {
 
 // LogEntry 로그 엔트리 구조체
 type LogEntry struct {
-	Process   string    `json:"process"`
-	Level     string    `json:"level"`
-	Message   string    `json:"message"`
-	Timestamp time.Time `json:"timestamp"`
+	Process   string            `json:"process"`
+	Level     string            `json:"level"`
+	Message   string            `json:"message"`
+	Timestamp time.Time         `json:"timestamp"`
+	Fields    map[string]string `json:"fields,omitempty"` // request_id, target_id, org_id 등
 }
 
 // ProcessInfo 프로세스 정보 구조체
 type ProcessInfo struct {
-	Name      string            `json:"name"`
-	Type      string            `json:"type"`
-	Status    string            `json:"status"`
-	PID       int               `json:"pid"`
-	Uptime    time.Duration     `json:"uptime"`
-	Memory    int64             `json:"memory"`
-	CPU       float64           `json:"cpu"`
-	Enabled   bool              `json:"enabled"`
-	Logs      bool              `json:"logs"`
-	StartTime time.Time         `json:"start_time"`
-	Config    map[string]string `json:"config,omitempty"`
+	Name        string            `json:"name"`
+	Type        string            `json:"type"`
+	Status      string            `json:"status"`
+	PID         int               `json:"pid"`
+	Uptime      time.Duration     `json:"uptime"`
+	Memory      int64             `json:"memory"`
+	CPU         float64           `json:"cpu"`
+	Enabled     bool              `json:"enabled"`
+	Logs        bool              `json:"logs"`
+	StartTime   time.Time         `json:"start_time"`
+	Restarts    int               `json:"restarts"`
+	Limits      *ResourceLimits   `json:"limits,omitempty"`
+	Health      *ProbeResult      `json:"health,omitempty"`
+	NextRestart *time.Time        `json:"next_restart,omitempty"` // 예약된 자동 재시작 시각
+	Config      map[string]string `json:"config,omitempty"`
+}
+
+// OutputLine 메모리에 보관된 프로세스 출력 한 줄
+type OutputLine struct {
+	Time   time.Time `json:"time"`
+	Stream string    `json:"stream"` // "stdout" or "stderr"
+	PID    int       `json:"pid"`    // 출력한 실행의 PID (재시작 경계 구분용)
+	Text   string    `json:"text"`
+}
+
+// ProcessOutput 프로세스의 최근 stdout/stderr 출력
+type ProcessOutput struct {
+	Name     string       `json:"name"`
+	Status   string       `json:"status"`
+	PID      int          `json:"pid"`
+	Size     int          `json:"size"`     // 보관 중인 바이트
+	Capacity int          `json:"capacity"` // 버퍼 크기 한도
+	Dropped  int64        `json:"dropped"`  // 한도 때문에 버려진 줄 수
+	Lines    []OutputLine `json:"lines"`
+}
+
+// ProbeResult 프로세스 헬스 체크 결과
+type ProbeResult struct {
+	Type      string        `json:"type"` // "tcp", "http", "exec"
+	Healthy   bool          `json:"healthy"`
+	Degraded  bool          `json:"degraded"`             // 실패 임계치 초과로 degraded 표시됨
+	Failures  int           `json:"consecutive_failures"` // 연속 실패 횟수
+	Message   string        `json:"message,omitempty"`
+	Latency   time.Duration `json:"latency"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+// ResourceLimits 프로세스 자원 제한
+type ResourceLimits struct {
+	CPUQuota     float64 `json:"cpu_quota,omitempty"`      // 사용 가능한 CPU 수 (1.5 = 150%)
+	MemoryLimit  int64   `json:"memory_limit,omitempty"`   // 바이트
+	MaxOpenFiles uint64  `json:"max_open_files,omitempty"` // 열린 파일 디스크립터 수
+	Enforcement  string  `json:"enforcement,omitempty"`    // "cgroup-v2", "rlimit", "none"
 }
 
 // LogConfig 로그 설정 구조체
@@ -141,15 +293,20 @@ type SystemResources struct {
 // CopySession 복사 세션 정보
 type CopySession struct {
 	ID          string    `json:"id"`
-	Mode        string    `json:"mode"`        // "receive" or "send"
-	Status      string    `json:"status"`      // "listening", "connected", "transferring", "completed", "failed"
-	Port        int       `json:"port"`        // 수신 포트
-	Path        string    `json:"path"`        // 수신 경로 또는 전송 파일 경로
-	TargetHost  string    `json:"target_host"` // 전송 대상 호스트 (send 모드)
-	TargetPort  int       `json:"target_port"` // 전송 대상 포트 (send 모드)
-	FileSize    int64     `json:"file_size"`   // 파일 크기
-	Transferred int64     `json:"transferred"` // 전송된 바이트
-	Speed       float64   `json:"speed"`       // 전송 속도 (MB/s)
+	Mode        string    `json:"mode"`                      // "receive" or "send"
+	Status      string    `json:"status"`                    // "listening", "connected", "transferring", "completed", "failed"
+	Port        int       `json:"port"`                      // 수신 포트
+	Path        string    `json:"path"`                      // 수신 경로 또는 전송 파일 경로
+	File        string    `json:"file,omitempty"`            // 수신 완료된 파일 경로 (receive 모드)
+	TargetHost  string    `json:"target_host"`               // 전송 대상 호스트 (send 모드)
+	TargetPort  int       `json:"target_port"`               // 전송 대상 포트 (send 모드)
+	FileSize    int64     `json:"file_size"`                 // 파일 크기
+	Transferred int64     `json:"transferred"`               // 전송된 바이트
+	Speed       float64   `json:"speed"`                     // 전송 속도 (MB/s)
+	TLS         bool      `json:"tls"`                       // TLS 사용 여부
+	Fingerprint string    `json:"tls_fingerprint,omitempty"` // 수신측 인증서 SHA-256 지문
+	TokenAuth   bool      `json:"token_auth"`                // 세션 토큰 요구 여부
+	Token       string    `json:"-"`                         // 일회용 세션 토큰 (노출하지 않음)
 	StartTime   time.Time `json:"start_time"`
 	EndTime     time.Time `json:"end_time,omitempty"`
 	Error       string    `json:"error,omitempty"`
@@ -165,6 +322,37 @@ type CopyProgress struct {
 	ETA         int64   `json:"eta"`         // 예상 완료 시간 (초)
 }
 
+// EventType 슈퍼바이저 이벤트 타입 (NATS 제목 tmidb.events.<type>으로도 발행)
+type EventType string
+
+const (
+	EventProcessStarted  EventType = "process.started"
+	EventProcessStopped  EventType = "process.stopped"
+	EventProcessCrashed  EventType = "process.crashed"
+	EventHealthDegraded  EventType = "health.degraded"
+	EventBackupCompleted EventType = "backup.completed"
+	EventBackupFailed    EventType = "backup.failed"
+	EventConfigChanged   EventType = "config.changed"
+	EventUpgradeComplete EventType = "upgrade.completed"
+	EventUpgradeFailed   EventType = "upgrade.failed"
+
+	EventSupervisorWedged  EventType = "supervisor.wedged"
+	EventSupervisorUnclean EventType = "supervisor.unclean_shutdown"
+
+	EventHAPromoted EventType = "ha.promoted" // standby가 임대를 잡고 active가 됨
+	EventHADemoted  EventType = "ha.demoted"  // active가 임대를 잃음
+)
+
+// Event 슈퍼바이저 라이프사이클 이벤트
+type Event struct {
+	ID        string                 `json:"id"`
+	Type      EventType              `json:"type"`
+	Component string                 `json:"component,omitempty"`
+	Message   string                 `json:"message,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
 // NewMessage 새로운 메시지 생성
 func NewMessage(msgType MessageType, data map[string]interface{}) *Message {
 	return &Message{
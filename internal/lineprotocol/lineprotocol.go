@@ -0,0 +1,249 @@
+// Package lineprotocol은 InfluxDB line protocol을 해석합니다.
+// 측정값(measurement)은 카테고리, 태그는 타겟 식별자, 필드는 관측값 payload로 쓰입니다.
+package lineprotocol
+
+import (
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TargetIDTag 값이 UUID이면 태그로 타겟을 만들지 않고 이 타겟에 씁니다
+const TargetIDTag = "target_id"
+
+// targetNamespace 태그로 만든 타겟 ID의 네임스페이스 (UUID v5)
+var targetNamespace = [16]byte{0x6b, 0x1d, 0x3e, 0x52, 0x9a, 0x07, 0x4c, 0x8f, 0xb3, 0x21, 0x5e, 0x90, 0xd4, 0x77, 0x0a, 0xc6}
+
+// Point는 line protocol 한 줄입니다
+type Point struct {
+	Line        int
+	Measurement string
+	Tags        map[string]string
+	Fields      map[string]interface{}
+	Ts          time.Time
+}
+
+// LineError는 해석할 수 없는 줄입니다
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// ParsePrecision은 타임스탬프 단위를 해석합니다 (v2의 ns, us, ms, s와 v1의 n, u, ms, s, m, h, 기본 ns)
+func ParsePrecision(s string) (time.Duration, error) {
+	switch s {
+	case "", "ns", "n":
+		return time.Nanosecond, nil
+	case "us", "u":
+		return time.Microsecond, nil
+	case "ms":
+		return time.Millisecond, nil
+	case "s":
+		return time.Second, nil
+	case "m":
+		return time.Minute, nil
+	case "h":
+		return time.Hour, nil
+	}
+	return 0, fmt.Errorf("invalid precision %q (ns, us, ms, s)", s)
+}
+
+// Parse는 본문의 각 줄을 해석합니다. 빈 줄과 #으로 시작하는 줄은 건너뛰고,
+// 잘못된 줄은 LineError로 모아 나머지 줄과 함께 반환합니다. 타임스탬프가 없으면 now를 씁니다.
+func Parse(body []byte, precision time.Duration, now time.Time) ([]Point, []*LineError) {
+	var points []Point
+	var errs []*LineError
+	for i, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		p, err := parseLine(line, precision, now)
+		if err != nil {
+			errs = append(errs, &LineError{Line: i + 1, Err: err})
+			continue
+		}
+		p.Line = i + 1
+		points = append(points, *p)
+	}
+	return points, errs
+}
+
+func parseLine(line string, precision time.Duration, now time.Time) (*Point, error) {
+	keyEnd := indexUnescaped(line, 0, ' ', false)
+	if keyEnd < 0 {
+		return nil, errors.New("missing fields")
+	}
+	fieldsStart := keyEnd + 1
+	for fieldsStart < len(line) && line[fieldsStart] == ' ' {
+		fieldsStart++
+	}
+	fieldsEnd := indexUnescaped(line, fieldsStart, ' ', true)
+	if fieldsEnd < 0 {
+		fieldsEnd = len(line)
+	}
+
+	p := &Point{Tags: make(map[string]string), Fields: make(map[string]interface{}), Ts: now}
+
+	keys := splitUnescaped(line[:keyEnd], ',', false)
+	p.Measurement = unescape(keys[0])
+	if p.Measurement == "" {
+		return nil, errors.New("missing measurement")
+	}
+	for _, tag := range keys[1:] {
+		k, v, ok := splitPair(tag)
+		if !ok || k == "" || v == "" {
+			return nil, fmt.Errorf("invalid tag %q", tag)
+		}
+		p.Tags[k] = v
+	}
+
+	for _, field := range splitUnescaped(line[fieldsStart:fieldsEnd], ',', true) {
+		eq := indexUnescaped(field, 0, '=', false)
+		if eq <= 0 {
+			return nil, fmt.Errorf("invalid field %q", field)
+		}
+		value, err := parseValue(field[eq+1:])
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", unescape(field[:eq]), err)
+		}
+		p.Fields[unescape(field[:eq])] = value
+	}
+	if len(p.Fields) == 0 {
+		return nil, errors.New("missing fields")
+	}
+
+	if ts := strings.TrimSpace(line[fieldsEnd:]); ts != "" {
+		n, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp %q", ts)
+		}
+		if precision > time.Nanosecond && (n > math.MaxInt64/int64(precision) || n < math.MinInt64/int64(precision)) {
+			return nil, fmt.Errorf("timestamp %q out of range", ts)
+		}
+		p.Ts = time.Unix(0, n*int64(precision)).UTC()
+	}
+	return p, nil
+}
+
+// parseValue는 필드 값을 해석합니다 ("문자열", 정수 i, 부호 없는 정수 u, 불리언, 실수)
+func parseValue(s string) (interface{}, error) {
+	if s == "" {
+		return nil, errors.New("missing value")
+	}
+	if s[0] == '"' {
+		if len(s) < 2 || s[len(s)-1] != '"' {
+			return nil, errors.New("unterminated string")
+		}
+		return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(s[1 : len(s)-1]), nil
+	}
+	switch s {
+	case "t", "T", "true", "True", "TRUE":
+		return true, nil
+	case "f", "F", "false", "False", "FALSE":
+		return false, nil
+	}
+	switch s[len(s)-1] {
+	case 'i':
+		return strconv.ParseInt(s[:len(s)-1], 10, 64)
+	case 'u':
+		return strconv.ParseUint(s[:len(s)-1], 10, 64)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("invalid number %q", s)
+	}
+	return f, nil
+}
+
+// indexUnescaped는 start부터 이스케이프되지 않은 sep의 위치를 찾습니다 (quoted면 따옴표 안은 건너뜀)
+func indexUnescaped(s string, start int, sep byte, quoted bool) int {
+	inQuote := false
+	for i := start; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case quoted && s[i] == '"':
+			inQuote = !inQuote
+		case s[i] == sep && !inQuote:
+			return i
+		}
+	}
+	return -1
+}
+
+func splitUnescaped(s string, sep byte, quoted bool) []string {
+	var parts []string
+	for {
+		i := indexUnescaped(s, 0, sep, quoted)
+		if i < 0 {
+			return append(parts, s)
+		}
+		parts = append(parts, s[:i])
+		s = s[i+1:]
+	}
+}
+
+func splitPair(s string) (string, string, bool) {
+	i := indexUnescaped(s, 0, '=', false)
+	if i < 0 {
+		return "", "", false
+	}
+	return unescape(s[:i]), unescape(s[i+1:]), true
+}
+
+// unescape는 측정값, 태그, 필드 이름의 \, \= \(공백) \\ 이스케이프를 풉니다
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	return strings.NewReplacer(`\,`, ",", `\=`, "=", `\ `, " ", `\\`, `\`).Replace(s)
+}
+
+// SeriesKey는 타겟을 구분하는 태그 조합입니다 (정렬된 key=value 목록, 태그가 없으면 측정값 이름).
+// 같은 태그로 여러 측정값을 쓰면 같은 타겟의 여러 카테고리가 됩니다.
+func (p Point) SeriesKey() string {
+	keys := make([]string, 0, len(p.Tags))
+	for k := range p.Tags {
+		if k != TargetIDTag {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return p.Measurement
+	}
+	sort.Strings(keys)
+
+	esc := strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(esc.Replace(k))
+		b.WriteByte('=')
+		b.WriteString(esc.Replace(p.Tags[k]))
+	}
+	return b.String()
+}
+
+// TargetID는 조직과 시리즈 키로 항상 같은 타겟 ID(UUID v5)를 만듭니다
+func TargetID(orgID, seriesKey string) string {
+	h := sha1.New()
+	h.Write(targetNamespace[:])
+	h.Write([]byte(orgID + "\n" + seriesKey))
+	var u [16]byte
+	copy(u[:], h.Sum(nil))
+	u[6] = (u[6] & 0x0f) | 0x50
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
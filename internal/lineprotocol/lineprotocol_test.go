@@ -0,0 +1,77 @@
+package lineprotocol
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	body := `# telegraf
+cpu,host=server01,region=us\ west usage_idle=98.5,usage_user=1i,online=t 1700000000000000000
+
+weather,location=a\,b temp=21.5,note="said \"hi\", then left",count=3u
+bad_no_fields,host=x
+mem,host=server01 used=oops 1700000000000000000
+disk\ io,host=server01 reads=10i 1700000000`
+
+	points, errs := Parse([]byte(body), time.Nanosecond, now)
+	if len(points) != 3 || len(errs) != 2 {
+		t.Fatalf("got %d points, %d errors: %v", len(points), len(errs), errs)
+	}
+	if errs[0].Line != 5 || errs[1].Line != 6 {
+		t.Errorf("error lines = %d, %d", errs[0].Line, errs[1].Line)
+	}
+
+	cpu := points[0]
+	if cpu.Measurement != "cpu" || cpu.Line != 2 {
+		t.Errorf("cpu = %+v", cpu)
+	}
+	if want := map[string]string{"host": "server01", "region": "us west"}; !reflect.DeepEqual(cpu.Tags, want) {
+		t.Errorf("cpu tags = %v", cpu.Tags)
+	}
+	if want := map[string]interface{}{"usage_idle": 98.5, "usage_user": int64(1), "online": true}; !reflect.DeepEqual(cpu.Fields, want) {
+		t.Errorf("cpu fields = %v", cpu.Fields)
+	}
+	if !cpu.Ts.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("cpu ts = %s", cpu.Ts)
+	}
+
+	weather := points[1]
+	if weather.Tags["location"] != "a,b" || weather.Fields["note"] != `said "hi", then left` || weather.Fields["count"] != uint64(3) {
+		t.Errorf("weather = %+v", weather)
+	}
+	if !weather.Ts.Equal(now) {
+		t.Errorf("missing timestamp should use now, got %s", weather.Ts)
+	}
+
+	if points[2].Measurement != "disk io" {
+		t.Errorf("escaped measurement = %q", points[2].Measurement)
+	}
+
+	// 초 단위
+	points, _ = Parse([]byte("cpu v=1 1700000000"), time.Second, now)
+	if len(points) != 1 || !points[0].Ts.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("second precision = %v", points)
+	}
+}
+
+func TestSeriesKey(t *testing.T) {
+	a := Point{Measurement: "cpu", Tags: map[string]string{"region": "us west", "host": "s1"}}
+	b := Point{Measurement: "mem", Tags: map[string]string{"host": "s1", "region": "us west"}}
+	if a.SeriesKey() != `host=s1,region=us\ west` || a.SeriesKey() != b.SeriesKey() {
+		t.Errorf("series keys = %q, %q", a.SeriesKey(), b.SeriesKey())
+	}
+	if got := (Point{Measurement: "uptime"}).SeriesKey(); got != "uptime" {
+		t.Errorf("untagged series key = %q", got)
+	}
+
+	id := TargetID("org-1", a.SeriesKey())
+	if id != TargetID("org-1", b.SeriesKey()) || id == TargetID("org-2", a.SeriesKey()) {
+		t.Error("target ID must depend only on organization and series key")
+	}
+	if len(id) != 36 || id[14] != '5' {
+		t.Errorf("target ID %q is not a v5 UUID", id)
+	}
+}
@@ -0,0 +1,254 @@
+// Package listener는 리스너의 조건과 액션 정의를 해석하고 검증합니다.
+// 실행은 data-manager의 리스너 런타임이 담당합니다.
+package listener
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// 조건 연산자
+const (
+	OpEq       = "eq"
+	OpNe       = "ne"
+	OpGt       = "gt"
+	OpGte      = "gte"
+	OpLt       = "lt"
+	OpLte      = "lte"
+	OpContains = "contains"
+	OpIn       = "in"
+	OpExists   = "exists"
+)
+
+// 액션 종류
+const (
+	ActionWebhook  = "webhook"
+	ActionNATS     = "nats"
+	ActionCategory = "category"
+)
+
+// SourceMarker 리스너 액션이 쓴 페이로드에 붙는 키 (리스너끼리 서로를 무한히 호출하지 않도록
+// 이 키가 있는 데이터는 다시 평가하지 않음)
+const SourceMarker = "_listener_id"
+
+// Condition은 데이터 필드 하나에 대한 조건입니다. 모든 조건을 만족해야 리스너가 실행됩니다.
+type Condition struct {
+	Field string      `json:"field"` // 점으로 구분한 경로 (예: "sensor.temp")
+	Op    string      `json:"op"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Action은 조건을 만족했을 때 실행할 작업입니다.
+type Action struct {
+	Type     string            `json:"type"`
+	URL      string            `json:"url,omitempty"`      // webhook
+	Headers  map[string]string `json:"headers,omitempty"`  // webhook
+	Subject  string            `json:"subject,omitempty"`  // nats (기본: tmidb.listener.<listener_id>)
+	Category string            `json:"category,omitempty"` // category
+	Fields   []string          `json:"fields,omitempty"`   // category: 복사할 필드 (비어 있으면 전체)
+}
+
+// Trigger는 리스너가 실행될 때 액션에 전달되는 이벤트입니다 (webhook 본문, NATS 메시지).
+type Trigger struct {
+	ListenerID string                 `json:"listener_id"`
+	OrgID      string                 `json:"org_id,omitempty"`
+	Category   string                 `json:"category"`
+	TargetID   string                 `json:"target_id"`
+	Source     string                 `json:"source"` // ts_obs 또는 target_categories
+	Operation  string                 `json:"operation"`
+	EventID    int64                  `json:"event_id"`
+	Ts         time.Time              `json:"ts"`
+	Data       map[string]interface{} `json:"data"`
+	FiredAt    time.Time              `json:"fired_at"`
+}
+
+// NATSSubject는 액션의 발행 주제를 반환합니다
+func (a Action) NATSSubject(listenerID string) string {
+	if a.Subject != "" {
+		return a.Subject
+	}
+	return "tmidb.listener." + listenerID
+}
+
+// Parse는 DB에 저장된 조건/액션 JSON을 해석합니다
+func Parse(conditionsJSON, actionsJSON []byte) ([]Condition, []Action, error) {
+	var conditions []Condition
+	var actions []Action
+	if len(conditionsJSON) > 0 {
+		if err := json.Unmarshal(conditionsJSON, &conditions); err != nil {
+			return nil, nil, fmt.Errorf("invalid conditions: %w", err)
+		}
+	}
+	if len(actionsJSON) > 0 {
+		if err := json.Unmarshal(actionsJSON, &actions); err != nil {
+			return nil, nil, fmt.Errorf("invalid actions: %w", err)
+		}
+	}
+	return conditions, actions, nil
+}
+
+// Validate는 리스너 카테고리에 대한 조건과 액션 정의를 검증합니다
+func Validate(category string, conditions []Condition, actions []Action) error {
+	for i, c := range conditions {
+		if strings.TrimSpace(c.Field) == "" {
+			return fmt.Errorf("conditions[%d]: field is required", i)
+		}
+		switch c.Op {
+		case OpEq, OpNe, OpContains:
+		case OpGt, OpGte, OpLt, OpLte:
+			if _, ok := toFloat(c.Value); !ok {
+				return fmt.Errorf("conditions[%d]: %s needs a numeric value", i, c.Op)
+			}
+		case OpIn:
+			if _, ok := c.Value.([]interface{}); !ok {
+				return fmt.Errorf("conditions[%d]: in needs an array value", i)
+			}
+		case OpExists:
+			if _, ok := c.Value.(bool); c.Value != nil && !ok {
+				return fmt.Errorf("conditions[%d]: exists takes true or false", i)
+			}
+		default:
+			return fmt.Errorf("conditions[%d]: unknown operator %q", i, c.Op)
+		}
+	}
+
+	for i, a := range actions {
+		switch a.Type {
+		case ActionWebhook:
+			u, err := url.Parse(a.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("actions[%d]: webhook needs an http(s) url", i)
+			}
+		case ActionNATS:
+			if strings.ContainsAny(a.Subject, " \t*>") {
+				return fmt.Errorf("actions[%d]: invalid NATS subject %q", i, a.Subject)
+			}
+		case ActionCategory:
+			if a.Category == "" {
+				return fmt.Errorf("actions[%d]: category is required", i)
+			}
+			if a.Category == category {
+				return fmt.Errorf("actions[%d]: a listener cannot write to its own category", i)
+			}
+		default:
+			return fmt.Errorf("actions[%d]: unknown action type %q", i, a.Type)
+		}
+	}
+	return nil
+}
+
+// Match는 데이터가 모든 조건을 만족하는지 확인합니다
+func Match(conditions []Condition, data map[string]interface{}) bool {
+	for _, c := range conditions {
+		if !matchCondition(c, data) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchCondition(c Condition, data map[string]interface{}) bool {
+	value, found := Lookup(data, c.Field)
+
+	switch c.Op {
+	case OpExists:
+		want, ok := c.Value.(bool)
+		if !ok {
+			want = true
+		}
+		return found == want
+	case OpNe:
+		return !found || !equal(value, c.Value)
+	}
+
+	if !found {
+		return false
+	}
+	switch c.Op {
+	case OpEq:
+		return equal(value, c.Value)
+	case OpGt, OpGte, OpLt, OpLte:
+		a, ok1 := toFloat(value)
+		b, ok2 := toFloat(c.Value)
+		if !ok1 || !ok2 {
+			return false
+		}
+		switch c.Op {
+		case OpGt:
+			return a > b
+		case OpGte:
+			return a >= b
+		case OpLt:
+			return a < b
+		default:
+			return a <= b
+		}
+	case OpContains:
+		switch v := value.(type) {
+		case string:
+			s, ok := c.Value.(string)
+			return ok && strings.Contains(v, s)
+		case []interface{}:
+			for _, item := range v {
+				if equal(item, c.Value) {
+					return true
+				}
+			}
+		}
+		return false
+	case OpIn:
+		options, _ := c.Value.([]interface{})
+		for _, option := range options {
+			if equal(value, option) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// Lookup은 점으로 구분한 경로의 값을 찾습니다
+func Lookup(data map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = data
+	for _, key := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// equal은 JSON 값을 비교합니다 (숫자는 타입과 관계없이 값으로 비교)
+func equal(a, b interface{}) bool {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
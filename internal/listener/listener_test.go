@@ -0,0 +1,69 @@
+package listener
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	var data map[string]interface{}
+	json.Unmarshal([]byte(`{"temp": 85, "status": "hot", "tags": ["a", "b"], "sensor": {"id": "s1"}}`), &data)
+
+	tests := []struct {
+		name string
+		cond Condition
+		want bool
+	}{
+		{"gt", Condition{Field: "temp", Op: OpGt, Value: 80}, true},
+		{"lte", Condition{Field: "temp", Op: OpLte, Value: 80.0}, false},
+		{"eq number", Condition{Field: "temp", Op: OpEq, Value: float64(85)}, true},
+		{"eq string", Condition{Field: "status", Op: OpEq, Value: "hot"}, true},
+		{"ne missing", Condition{Field: "missing", Op: OpNe, Value: "x"}, true},
+		{"contains string", Condition{Field: "status", Op: OpContains, Value: "ho"}, true},
+		{"contains array", Condition{Field: "tags", Op: OpContains, Value: "b"}, true},
+		{"in", Condition{Field: "status", Op: OpIn, Value: []interface{}{"cold", "hot"}}, true},
+		{"nested", Condition{Field: "sensor.id", Op: OpEq, Value: "s1"}, true},
+		{"exists", Condition{Field: "sensor.id", Op: OpExists}, true},
+		{"not exists", Condition{Field: "sensor.x", Op: OpExists, Value: false}, true},
+		{"gt on string", Condition{Field: "status", Op: OpGt, Value: 1}, false},
+	}
+	for _, tt := range tests {
+		if got := Match([]Condition{tt.cond}, data); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if !Match(nil, data) {
+		t.Error("no conditions should match")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	conditions, actions, err := Parse(
+		[]byte(`[{"field": "temp", "op": "gt", "value": 80}]`),
+		[]byte(`[{"type": "webhook", "url": "https://example.com/hook"}, {"type": "category", "category": "alerts"}]`),
+	)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if err := Validate("temperature", conditions, actions); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	invalid := []struct {
+		name       string
+		conditions []Condition
+		actions    []Action
+	}{
+		{"unknown op", []Condition{{Field: "temp", Op: "regex"}}, nil},
+		{"non-numeric gt", []Condition{{Field: "temp", Op: OpGt, Value: "hot"}}, nil},
+		{"bad webhook", nil, []Action{{Type: ActionWebhook, URL: "ftp://example.com"}}},
+		{"wildcard subject", nil, []Action{{Type: ActionNATS, Subject: "alerts.>"}}},
+		{"own category", nil, []Action{{Type: ActionCategory, Category: "temperature"}}},
+	}
+	for _, tt := range invalid {
+		if err := Validate("temperature", tt.conditions, tt.actions); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+// 요청 추적용 필드 키
+const (
+	FieldRequestID = "request_id"
+	FieldTargetID  = "target_id"
+	FieldOrgID     = "org_id"
+)
+
+// RequestIDHeader 요청 ID를 전달하는 HTTP/NATS 헤더
+const RequestIDHeader = "X-Request-ID"
+
+// Field 구조화 로그 필드
+type Field struct {
+	Key   string
+	Value string
+}
+
+// F 필드 생성
+func F(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Record 자식 프로세스가 표준 출력으로 내보내는 구조화 로그 한 줄
+// 슈퍼바이저는 이 형식의 줄에서 레벨과 필드를 분리해 저장
+type Record struct {
+	Level   string            `json:"level"`
+	Message string            `json:"msg"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+var recordOutput = log.New(os.Stdout, "", 0)
+
+// Log 구조화 로그 한 줄 출력 (api, data-manager, data-consumer 등 관리 대상 프로세스용)
+func Log(level LogLevel, message string, fields ...Field) {
+	data, err := json.Marshal(Record{
+		Level:   logLevelNames[level],
+		Message: message,
+		Fields:  fieldMap(fields),
+	})
+	if err != nil {
+		return
+	}
+	recordOutput.Println(string(data))
+}
+
+// ParseRecord 구조화 로그 줄 해석 (Record 형식이 아니면 ok=false)
+func ParseRecord(line string) (level LogLevel, message string, fields []Field, ok bool) {
+	if !strings.HasPrefix(line, "{") {
+		return 0, "", nil, false
+	}
+
+	var record Record
+	if err := json.Unmarshal([]byte(line), &record); err != nil || record.Message == "" {
+		return 0, "", nil, false
+	}
+	if level, ok = parseLevelName(record.Level); !ok {
+		level = LogLevelInfo
+	}
+
+	keys := make([]string, 0, len(record.Fields))
+	for key := range record.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fields = append(fields, F(key, record.Fields[key]))
+	}
+	return level, record.Message, fields, true
+}
+
+// NewRequestID 새 요청 ID 생성
+func NewRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// FormatFields 필드를 "key=value" 형식으로 나열 (키 순서대로)
+func FormatFields(fields map[string]string) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + "=" + fields[key]
+	}
+	return strings.Join(parts, " ")
+}
+
+// fieldMap 빈 값을 제외한 필드 맵 (필드가 없으면 nil)
+func fieldMap(fields []Field) map[string]string {
+	var m map[string]string
+	for _, field := range fields {
+		if field.Key == "" || field.Value == "" {
+			continue
+		}
+		if m == nil {
+			m = make(map[string]string, len(fields))
+		}
+		m[field.Key] = field.Value
+	}
+	return m
+}
@@ -0,0 +1,27 @@
+package logger
+
+import "testing"
+
+func TestParseRecord(t *testing.T) {
+	level, message, fields, ok := ParseRecord(`{"level":"WARN","msg":"slow insert","fields":{"target_id":"t-1","request_id":"abc"}}`)
+	if !ok || level != LogLevelWarn || message != "slow insert" {
+		t.Fatalf("unexpected record: ok=%t level=%v message=%q", ok, level, message)
+	}
+	if len(fields) != 2 || fields[0] != F(FieldRequestID, "abc") || fields[1] != F(FieldTargetID, "t-1") {
+		t.Fatalf("unexpected fields: %+v", fields)
+	}
+
+	for _, line := range []string{
+		"2024/01/01 12:00:00 plain log line",
+		`{"unrelated":"json"}`,
+		`{"level":"INFO","msg":`,
+	} {
+		if _, _, _, ok := ParseRecord(line); ok {
+			t.Errorf("expected %q not to parse as a record", line)
+		}
+	}
+
+	if got := FormatFields(fieldMap([]Field{F("b", "2"), F("a", "1"), F("empty", "")})); got != "a=1 b=2" {
+		t.Fatalf("unexpected formatted fields: %q", got)
+	}
+}
@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// policiesFile 컴포넌트별 정책이 저장되는 파일 (로그 디렉토리 기준)
+const policiesFile = "policies.json"
+
+// LevelStatus 전역 및 컴포넌트별 로그 레벨 (log_level_get 응답)
+type LevelStatus struct {
+	Global     string            `json:"global"`
+	Components map[string]string `json:"components"` // 전역 레벨과 다르게 지정된 컴포넌트
+}
+
+// ComponentLevel 컴포넌트에 적용되는 최소 로그 레벨
+func (m *Manager) ComponentLevel(component string) LogLevel {
+	m.policiesMux.RLock()
+	policy, exists := m.policies[component]
+	m.policiesMux.RUnlock()
+
+	if exists && policy.Level != "" {
+		if level, ok := parseLevelName(policy.Level); ok {
+			return level
+		}
+	}
+	return m.GetLevel()
+}
+
+// SetComponentLevel 컴포넌트별 로그 레벨 지정 ("" 또는 "default"면 전역 레벨로 되돌림)
+// 재시작 없이 다음 로그부터 적용되고 보관 정책과 함께 저장됨
+func (m *Manager) SetComponentLevel(component, level string) error {
+	if !validComponentName(component) {
+		return fmt.Errorf("invalid component name: %q", component)
+	}
+
+	name := ""
+	if level != "" && !strings.EqualFold(level, "default") {
+		parsed, ok := parseLevelName(level)
+		if !ok {
+			return fmt.Errorf("unknown log level: %s", level)
+		}
+		name = logLevelNames[parsed]
+	}
+
+	policy := *m.GetLogPolicy(component)
+	policy.Level = name
+	m.storePolicy(component, &policy)
+
+	return m.savePolicies()
+}
+
+// LevelStatus 전역 및 컴포넌트별 로그 레벨 조회
+func (m *Manager) LevelStatus() LevelStatus {
+	status := LevelStatus{
+		Global:     logLevelNames[m.GetLevel()],
+		Components: make(map[string]string),
+	}
+
+	m.policiesMux.RLock()
+	defer m.policiesMux.RUnlock()
+	for component, policy := range m.policies {
+		if policy.Level != "" {
+			status.Components[component] = policy.Level
+		}
+	}
+	return status
+}
+
+// savePolicies 컴포넌트별 정책을 로그 디렉토리에 저장
+func (m *Manager) savePolicies() error {
+	m.policiesMux.RLock()
+	data, err := json.MarshalIndent(m.policies, "", "  ")
+	m.policiesMux.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(m.config.BaseDir, policiesFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// loadPolicies 저장된 컴포넌트별 정책 불러오기 (파일이 없으면 무시)
+func (m *Manager) loadPolicies() error {
+	data, err := os.ReadFile(filepath.Join(m.config.BaseDir, policiesFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var policies map[string]*RetentionPolicy
+	if err := json.Unmarshal(data, &policies); err != nil {
+		return fmt.Errorf("%s: %w", policiesFile, err)
+	}
+	for component, policy := range policies {
+		if policy != nil && validComponentName(component) {
+			m.storePolicy(component, policy)
+		}
+	}
+	return nil
+}
+
+// validComponentName 로그 디렉토리 밖을 가리키지 않는 컴포넌트 이름인지 확인
+func validComponentName(component string) bool {
+	return component != "" && component == filepath.Base(component) && !strings.HasPrefix(component, ".")
+}
+
+// handleLogLevelGet 로그 레벨 조회 핸들러
+func (m *Manager) handleLogLevelGet(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	return ipc.NewResponse(msg.ID, true, m.LevelStatus(), "")
+}
+
+// handleLogLevelSet 컴포넌트 로그 레벨 설정 핸들러
+func (m *Manager) handleLogLevelSet(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	component, ok := msg.Data["component"].(string)
+	if !ok {
+		return ipc.NewResponse(msg.ID, false, nil, "component parameter required")
+	}
+	level, _ := msg.Data["level"].(string)
+
+	if err := m.SetComponentLevel(component, level); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	log.Printf("📝 Log level for %s set to %s", component, logLevelNames[m.ComponentLevel(component)])
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
+		"component": component,
+		"level":     logLevelNames[m.ComponentLevel(component)],
+		"override":  m.LevelStatus().Components[component] != "",
+	}, "")
+}
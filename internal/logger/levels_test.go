@@ -0,0 +1,34 @@
+package logger
+
+import "testing"
+
+func TestComponentLevelOverridePersists(t *testing.T) {
+	config := &LogConfig{BaseDir: t.TempDir(), Level: LogLevelInfo}
+	m := NewManager(config, nil)
+
+	if err := m.SetComponentLevel("data-consumer", "debug"); err != nil {
+		t.Fatal(err)
+	}
+	if m.ComponentLevel("data-consumer") != LogLevelDebug || m.ComponentLevel("api") != LogLevelInfo {
+		t.Fatalf("unexpected levels: data-consumer=%v api=%v", m.ComponentLevel("data-consumer"), m.ComponentLevel("api"))
+	}
+	if err := m.SetComponentLevel("api", "verbose"); err == nil {
+		t.Fatal("expected unknown level error")
+	}
+
+	// 새 관리자에서도 저장된 레벨 유지
+	restarted := NewManager(config, nil)
+	if err := restarted.loadPolicies(); err != nil {
+		t.Fatal(err)
+	}
+	if restarted.ComponentLevel("data-consumer") != LogLevelDebug {
+		t.Fatalf("expected persisted DEBUG level, got %v", restarted.ComponentLevel("data-consumer"))
+	}
+
+	if err := restarted.SetComponentLevel("data-consumer", "default"); err != nil {
+		t.Fatal(err)
+	}
+	if len(restarted.LevelStatus().Components) != 0 {
+		t.Fatalf("expected override to be cleared, got %+v", restarted.LevelStatus())
+	}
+}
@@ -39,6 +39,7 @@ var logLevelNames = map[LogLevel]string{
 // Manager 로그 관리자
 type Manager struct {
 	config     *LogConfig
+	configMux  sync.RWMutex
 	writers    map[string]*ProcessWriter
 	writersMux sync.RWMutex
 	ipcServer  *ipc.Server
@@ -53,6 +54,15 @@ type Manager struct {
 	streams    map[string]bool // 컴포넌트별 스트림 활성화 상태
 	streamsMux sync.RWMutex
 
+	// 로그 처리량 통계 (컴포넌트/레벨별)
+	stats    map[string]*LogStats
+	statsMux sync.Mutex
+
+	// 외부 로그 싱크
+	sinks       []*sink
+	sinksCancel context.CancelFunc
+	sinksMux    sync.RWMutex
+
 	// Go 1.24 기능: 자원 관리
 	cleanupFuncs []func()
 	cleanupMux   sync.Mutex
@@ -69,6 +79,7 @@ type LogConfig struct {
 	BufferSize    int           `json:"buffer_size"`
 	FlushInterval time.Duration `json:"flush_interval"`
 	ConsoleOutput bool          `json:"console_output"`
+	Sinks         []SinkConfig  `json:"sinks,omitempty"`
 }
 
 // RetentionPolicy 로그 보관 정책
@@ -79,6 +90,17 @@ type RetentionPolicy struct {
 	MaxAge      time.Duration `json:"max_age"`
 	Compress    bool          `json:"compress"`
 	Enabled     bool          `json:"enabled"`
+	Level       string        `json:"level,omitempty"` // 컴포넌트 최소 로그 레벨 (비어 있으면 전역 레벨)
+
+	MaxTotalSize int64 `json:"max_total_size,omitempty"` // MB, 압축된 로테이션 포함 컴포넌트 전체 (0이면 제한 없음)
+}
+
+// LogStats 컴포넌트/레벨별 누적 로그 처리량
+type LogStats struct {
+	Component string `json:"component"`
+	Level     string `json:"level"`
+	Lines     int64  `json:"lines"`
+	Bytes     int64  `json:"bytes"`
 }
 
 // ProcessWriter 프로세스별 로그 라이터
@@ -121,6 +143,7 @@ func NewManager(config *LogConfig, ipcServer *ipc.Server) *Manager {
 		cancel:       cancel,
 		policies:     make(map[string]*RetentionPolicy),
 		streams:      make(map[string]bool),
+		stats:        make(map[string]*LogStats),
 		cleanupFuncs: make([]func(), 0),
 	}
 
@@ -139,6 +162,16 @@ func (m *Manager) Start() error {
 		return fmt.Errorf("failed to create log directory: %w", err)
 	}
 
+	// 저장된 컴포넌트별 정책 (보관 정책, 로그 레벨) 불러오기
+	if err := m.loadPolicies(); err != nil {
+		log.Printf("⚠️ Failed to load log policies: %v", err)
+	}
+
+	// 외부 로그 싱크 시작
+	if err := m.SetSinks(m.config.Sinks); err != nil {
+		return err
+	}
+
 	// IPC 핸들러 등록
 	m.registerIPCHandlers()
 
@@ -153,6 +186,9 @@ func (m *Manager) Start() error {
 func (m *Manager) Stop() error {
 	m.cancel()
 
+	// 싱크의 남은 엔트리 전송
+	m.stopSinks()
+
 	// 모든 라이터 종료
 	m.writersMux.Lock()
 	for _, writer := range m.writers {
@@ -176,10 +212,10 @@ func (m *Manager) GetWriter(component string) (*ProcessWriter, error) {
 	return m.createWriter(component)
 }
 
-// WriteLog 로그 작성
-func (m *Manager) WriteLog(component string, level LogLevel, message string) error {
-	// 레벨 필터링
-	if level < m.config.Level {
+// WriteLog 로그 작성 (fields는 request_id 등 구조화 필드)
+func (m *Manager) WriteLog(component string, level LogLevel, message string, fields ...Field) error {
+	// 레벨 필터링 (컴포넌트별 레벨 우선)
+	if level < m.ComponentLevel(component) {
 		return nil
 	}
 
@@ -194,6 +230,7 @@ func (m *Manager) WriteLog(component string, level LogLevel, message string) err
 		Level:     logLevelNames[level],
 		Message:   message,
 		Timestamp: time.Now(),
+		Fields:    fieldMap(fields),
 	}
 
 	// JSON 형태로 직렬화
@@ -206,11 +243,14 @@ func (m *Manager) WriteLog(component string, level LogLevel, message string) err
 	if err := writer.Write(data); err != nil {
 		return err
 	}
+	m.recordWrite(component, entry.Level, int64(len(data)+1))
+	m.forwardToSinks(entry)
 
 	// 콘솔 출력
 	if m.config.ConsoleOutput {
 		color := getComponentColor(entry.Process)
 		levelColor := getLevelColor(entry.Level)
+		message := entryLine(entry)
 		
 		fmt.Printf("%s[%s] %s%s%s: %s%s\n",
 			color,
@@ -218,24 +258,94 @@ func (m *Manager) WriteLog(component string, level LogLevel, message string) err
 			color,
 			entry.Process,
 			levelColor,
-			entry.Message,
+			message,
 			"\033[0m") // reset color
 	}
 
-	// IPC 스트림으로 브로드캐스트
-	m.streamsMux.RLock()
-	if m.streams[component] {
-		if m.ipcServer != nil {
-			m.ipcServer.BroadcastLogEntry(entry)
+	return nil
+}
+
+// SetLevel 최소 로그 레벨 변경 (재시작 없이 적용)
+func (m *Manager) SetLevel(level LogLevel) {
+	m.configMux.Lock()
+	defer m.configMux.Unlock()
+
+	m.config.Level = level
+}
+
+// GetLevel 현재 최소 로그 레벨 조회
+func (m *Manager) GetLevel() LogLevel {
+	m.configMux.RLock()
+	defer m.configMux.RUnlock()
+
+	return m.config.Level
+}
+
+// recordWrite 처리량 통계 갱신
+func (m *Manager) recordWrite(component, level string, size int64) {
+	m.statsMux.Lock()
+	defer m.statsMux.Unlock()
+
+	key := component + "/" + level
+	stat, exists := m.stats[key]
+	if !exists {
+		stat = &LogStats{Component: component, Level: level}
+		m.stats[key] = stat
+	}
+	stat.Lines++
+	stat.Bytes += size
+}
+
+// GetLogStats 누적 로그 처리량 통계 조회
+func (m *Manager) GetLogStats() []LogStats {
+	m.statsMux.Lock()
+	defer m.statsMux.Unlock()
+
+	result := make([]LogStats, 0, len(m.stats))
+	for _, stat := range m.stats {
+		result = append(result, *stat)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Component != result[j].Component {
+			return result[i].Component < result[j].Component
 		}
+		return result[i].Level < result[j].Level
+	})
+
+	return result
+}
+
+// LogFilePath 컴포넌트의 현재 로그 파일 경로
+func (m *Manager) LogFilePath(component string) string {
+	return filepath.Join(m.config.BaseDir, component, fmt.Sprintf("%s.log", component))
+}
+
+// Flush 모든 라이터의 버퍼를 파일로 기록
+func (m *Manager) Flush() {
+	m.writersMux.RLock()
+	writers := make([]*ProcessWriter, 0, len(m.writers))
+	for _, writer := range m.writers {
+		writers = append(writers, writer)
 	}
-	m.streamsMux.RUnlock()
+	m.writersMux.RUnlock()
 
-	return nil
+	for _, writer := range writers {
+		writer.Flush()
+	}
 }
 
-// SetLogPolicy 로그 정책 설정
+// SetLogPolicy 로그 정책 설정 (로그 디렉토리에 저장되어 재시작 후에도 유지)
 func (m *Manager) SetLogPolicy(component string, policy *RetentionPolicy) {
+	m.storePolicy(component, policy)
+
+	if err := m.savePolicies(); err != nil {
+		log.Printf("⚠️ Failed to save log policies: %v", err)
+	}
+}
+
+// storePolicy 정책을 메모리에 반영
+func (m *Manager) storePolicy(component string, policy *RetentionPolicy) {
 	m.policiesMux.Lock()
 	defer m.policiesMux.Unlock()
 
@@ -286,6 +396,15 @@ func (m *Manager) DisableStream(component string) {
 	m.streams[component] = false
 }
 
+// IsStreamEnabled 컴포넌트의 스트림 활성화 여부 (등록되지 않은 컴포넌트는 활성으로 간주)
+func (m *Manager) IsStreamEnabled(component string) bool {
+	m.streamsMux.RLock()
+	defer m.streamsMux.RUnlock()
+
+	enabled, exists := m.streams[component]
+	return !exists || enabled
+}
+
 // GetStreamStatus 스트림 상태 조회
 func (m *Manager) GetStreamStatus() map[string]bool {
 	m.streamsMux.RLock()
@@ -443,6 +562,9 @@ func (pw *ProcessWriter) rotate() error {
 	pw.currentSize = 0
 	pw.rotationCount++
 
+	// 전체 크기 제한 적용
+	enforceTotalSize(logDir, pw.file.Name(), pw.policy.MaxTotalSize)
+
 	return nil
 }
 
@@ -467,6 +589,19 @@ func (pw *ProcessWriter) compressFile(src, dst string) error {
 	return err
 }
 
+// Flush 버퍼된 로그를 파일로 기록
+func (pw *ProcessWriter) Flush() error {
+	pw.bufferMux.Lock()
+	defer pw.bufferMux.Unlock()
+
+	if pw.writer == nil || pw.writer.Buffered() == 0 {
+		return nil
+	}
+
+	pw.lastFlush = time.Now()
+	return pw.writer.Flush()
+}
+
 // Close 라이터 종료
 func (pw *ProcessWriter) Close() error {
 	pw.bufferMux.Lock()
@@ -492,12 +627,22 @@ func (m *Manager) periodicTasks() {
 	ticker := time.NewTicker(24 * time.Hour) // 하루에 한 번만 정리
 	defer ticker.Stop()
 
+	// 버퍼된 로그를 주기적으로 flush하여 follow 스트림이 지연되지 않도록 함
+	flushInterval := m.config.FlushInterval
+	if flushInterval <= 0 {
+		flushInterval = 5 * time.Second
+	}
+	flushTicker := time.NewTicker(flushInterval)
+	defer flushTicker.Stop()
+
 	for {
 		select {
 		case <-m.ctx.Done():
 			return
 		case <-ticker.C:
 			m.cleanupOldLogs()
+		case <-flushTicker.C:
+			m.Flush()
 		}
 	}
 }
@@ -562,6 +707,10 @@ func (m *Manager) cleanupComponentLogs(logDir string, policy *RetentionPolicy) {
 			log.Printf("🗑️ Removed excess log file: %s", fileInfos[i].path)
 		}
 	}
+
+	// 전체 크기 제한
+	component := filepath.Base(logDir)
+	enforceTotalSize(logDir, filepath.Join(logDir, component+".log"), policy.MaxTotalSize)
 }
 
 // registerIPCHandlers IPC 핸들러 등록
@@ -575,6 +724,14 @@ func (m *Manager) registerIPCHandlers() {
 	m.ipcServer.RegisterHandler(ipc.MessageTypeLogDisable, m.handleLogDisable)
 	m.ipcServer.RegisterHandler(ipc.MessageTypeLogStatus, m.handleLogStatus)
 	m.ipcServer.RegisterHandler(ipc.MessageTypeLogConfig, m.handleLogConfig)
+	m.ipcServer.RegisterHandler(ipc.MessageTypeLogPolicySet, m.handleLogPolicySet)
+	m.ipcServer.RegisterHandler(ipc.MessageTypeLogLevelGet, m.handleLogLevelGet)
+	m.ipcServer.RegisterHandler(ipc.MessageTypeLogLevelSet, m.handleLogLevelSet)
+
+	// 외부 로그 싱크
+	m.ipcServer.RegisterHandler(ipc.MessageTypeLogSinkList, m.handleLogSinkList)
+	m.ipcServer.RegisterHandler(ipc.MessageTypeLogSinkEnable, m.handleLogSinkEnable)
+	m.ipcServer.RegisterHandler(ipc.MessageTypeLogSinkDisable, m.handleLogSinkDisable)
 }
 
 // handleLogEnable 로그 활성화 핸들러
@@ -622,6 +779,38 @@ func (m *Manager) handleLogConfig(conn *ipc.Connection, msg *ipc.Message) *ipc.R
 	return ipc.NewResponse(msg.ID, true, policy, "")
 }
 
+// handleLogSinkList 싱크 목록 핸들러
+func (m *Manager) handleLogSinkList(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	return ipc.NewResponse(msg.ID, true, m.SinkStatus(), "")
+}
+
+// handleLogSinkEnable 싱크 활성화 핸들러
+func (m *Manager) handleLogSinkEnable(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	return m.setSinkEnabled(msg, true)
+}
+
+// handleLogSinkDisable 싱크 비활성화 핸들러
+func (m *Manager) handleLogSinkDisable(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	return m.setSinkEnabled(msg, false)
+}
+
+func (m *Manager) setSinkEnabled(msg *ipc.Message, enabled bool) *ipc.Response {
+	name, ok := msg.Data["sink"].(string)
+	if !ok || name == "" {
+		return ipc.NewResponse(msg.ID, false, nil, "sink parameter required")
+	}
+	component, _ := msg.Data["component"].(string)
+
+	if err := m.SetSinkEnabled(name, component, enabled); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
+		"sink":      name,
+		"component": component,
+		"enabled":   enabled,
+	}, "")
+}
+
 // cleanup Go 1.24 기능: 자원 정리
 func (m *Manager) cleanup() {
 	m.cleanupMux.Lock()
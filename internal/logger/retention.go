@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// enforceTotalSize 컴포넌트 디렉토리의 로그 파일(압축된 로테이션 포함) 합계가
+// maxTotalSize(MB)를 넘으면 오래된 파일부터 삭제 (현재 파일은 유지)
+func enforceTotalSize(logDir, current string, maxTotalSize int64) {
+	if maxTotalSize <= 0 {
+		return
+	}
+
+	files, err := filepath.Glob(filepath.Join(logDir, "*.log*"))
+	if err != nil {
+		return
+	}
+
+	type fileInfo struct {
+		path string
+		info os.FileInfo
+	}
+
+	var total int64
+	var fileInfos []fileInfo
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil && info.Mode().IsRegular() {
+			fileInfos = append(fileInfos, fileInfo{file, info})
+			total += info.Size()
+		}
+	}
+
+	// 수정 시간 기준 정렬 (오래된 것부터)
+	sort.Slice(fileInfos, func(i, j int) bool {
+		return fileInfos[i].info.ModTime().Before(fileInfos[j].info.ModTime())
+	})
+
+	limit := maxTotalSize * 1024 * 1024
+	for _, fi := range fileInfos {
+		if total <= limit {
+			break
+		}
+		if fi.path == current {
+			continue
+		}
+		if err := os.Remove(fi.path); err != nil {
+			continue
+		}
+		total -= fi.info.Size()
+		log.Printf("🗑️ Removed log file over size limit: %s", fi.path)
+	}
+}
+
+// applyPolicyUpdate log_policy_set 요청에 포함된 값만 정책에 반영
+func applyPolicyUpdate(policy *RetentionPolicy, data map[string]interface{}) error {
+	if v, ok := data["max_file_size"].(float64); ok {
+		if v <= 0 {
+			return fmt.Errorf("max_file_size must be positive")
+		}
+		policy.MaxFileSize = int64(v)
+	}
+	if v, ok := data["max_files"].(float64); ok {
+		if v < 1 {
+			return fmt.Errorf("max_files must be at least 1")
+		}
+		policy.MaxFiles = int(v)
+	}
+	if v, ok := data["max_total_size"].(float64); ok {
+		if v < 0 {
+			return fmt.Errorf("max_total_size must not be negative")
+		}
+		policy.MaxTotalSize = int64(v)
+	}
+	if v, ok := data["max_age"].(string); ok {
+		age, err := time.ParseDuration(v)
+		if err != nil || age <= 0 {
+			return fmt.Errorf("invalid max_age: %q", v)
+		}
+		policy.MaxAge = age
+	}
+	if v, ok := data["compress"].(bool); ok {
+		policy.Compress = v
+	}
+	if v, ok := data["enabled"].(bool); ok {
+		policy.Enabled = v
+	}
+	return nil
+}
+
+// handleLogPolicySet 컴포넌트 보관 정책 변경 핸들러 (저장 후 바로 정리 실행)
+func (m *Manager) handleLogPolicySet(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	component, ok := msg.Data["component"].(string)
+	if !ok || !validComponentName(component) {
+		return ipc.NewResponse(msg.ID, false, nil, "valid component parameter required")
+	}
+
+	policy := *m.GetLogPolicy(component)
+	if err := applyPolicyUpdate(&policy, msg.Data); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	m.storePolicy(component, &policy)
+	if err := m.savePolicies(); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to save log policy: %v", err))
+	}
+
+	if policy.Enabled {
+		go m.cleanupComponentLogs(filepath.Join(m.config.BaseDir, component), &policy)
+	}
+
+	log.Printf("📝 Log retention policy for %s updated", component)
+	return ipc.NewResponse(msg.ID, true, policy, "")
+}
@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEnforceTotalSizeKeepsCurrentFile(t *testing.T) {
+	dir := t.TempDir()
+	current := filepath.Join(dir, "api.log")
+	files := []string{filepath.Join(dir, "api.1.log.gz"), filepath.Join(dir, "api.0.log"), current}
+
+	now := time.Now()
+	for i, path := range files {
+		if err := os.WriteFile(path, make([]byte, 400*1024), 0644); err != nil {
+			t.Fatal(err)
+		}
+		// 현재 파일이 가장 오래된 경우에도 삭제되지 않아야 함
+		modTime := now.Add(time.Duration(i-3) * time.Hour)
+		if path == current {
+			modTime = now.Add(-24 * time.Hour)
+		}
+		os.Chtimes(path, modTime, modTime)
+	}
+
+	enforceTotalSize(dir, current, 1)
+
+	for path, want := range map[string]bool{files[0]: false, files[1]: true, current: true} {
+		if _, err := os.Stat(path); (err == nil) != want {
+			t.Errorf("%s: expected exists=%t", filepath.Base(path), want)
+		}
+	}
+}
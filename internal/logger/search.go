@@ -0,0 +1,267 @@
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// 로그 검색 설정
+const (
+	maxRotatedFiles    = 100  // 검색하는 로테이션 파일 최대 개수
+	defaultSearchLimit = 100  // 페이지 크기 기본값
+	maxSearchLimit     = 1000 // 페이지 크기 상한
+)
+
+// SearchQuery 로그 검색 조건
+type SearchQuery struct {
+	Component string            // 컴포넌트 이름 ("all" 또는 빈 값이면 전체)
+	Since     time.Time         // 이 시각 이후 (zero면 제한 없음)
+	Until     time.Time         // 이 시각 이전 (zero면 제한 없음)
+	Level     string            // 최소 로그 레벨 (DEBUG, INFO, WARN, ERROR)
+	Pattern   *regexp.Regexp    // 메시지 정규식
+	Fields    map[string]string // 구조화 필드 일치 조건 (예: request_id)
+	Offset    int               // 건너뛸 엔트리 수
+	Limit     int               // 페이지 크기
+}
+
+// SearchResult 로그 검색 결과 (최신순)
+type SearchResult struct {
+	Entries      []ipc.LogEntry `json:"entries"`
+	Total        int            `json:"total"`
+	Offset       int            `json:"offset"`
+	Limit        int            `json:"limit"`
+	HasMore      bool           `json:"has_more"`
+	FilesScanned int            `json:"files_scanned"`
+}
+
+// LogFiles 컴포넌트의 현재 로그 파일과 since 이후 수정된 로테이션 파일
+// (.N.log, .N.log.gz)을 최신순으로 반환
+func (m *Manager) LogFiles(component string, since time.Time) []string {
+	current := m.LogFilePath(component)
+	files := []string{current}
+
+	base := strings.TrimSuffix(current, ".log")
+	for i := 0; i < maxRotatedFiles; i++ {
+		plain := fmt.Sprintf("%s.%d.log", base, i)
+		path := ""
+		for _, candidate := range []string{plain, plain + ".gz"} {
+			if _, err := os.Stat(candidate); err == nil {
+				path = candidate
+				break
+			}
+		}
+		if path == "" {
+			break
+		}
+
+		// 로테이션 파일은 오래된 순으로 번호가 커지므로 범위를 벗어나면 중단
+		info, err := os.Stat(path)
+		if err != nil || info.ModTime().Before(since) {
+			break
+		}
+		files = append(files, path)
+	}
+
+	return files
+}
+
+// Search 현재 및 로테이션(압축 포함) 로그 파일에서 조건에 맞는 엔트리를 최신순으로 검색
+func (m *Manager) Search(query SearchQuery) (*SearchResult, error) {
+	minLevel := -1
+	if query.Level != "" {
+		level, ok := parseLevelName(query.Level)
+		if !ok {
+			return nil, fmt.Errorf("unknown log level: %s", query.Level)
+		}
+		minLevel = int(level)
+	}
+
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultSearchLimit
+	}
+	if query.Limit > maxSearchLimit {
+		query.Limit = maxSearchLimit
+	}
+
+	components, err := m.searchComponents(query.Component)
+	if err != nil {
+		return nil, err
+	}
+
+	// 버퍼에 남아 있는 최근 로그도 검색되도록 먼저 기록
+	m.Flush()
+
+	result := &SearchResult{Offset: query.Offset, Limit: query.Limit}
+	keep := query.Offset + query.Limit
+	var matches []ipc.LogEntry
+
+	for _, component := range components {
+		for _, path := range m.LogFiles(component, query.Since) {
+			err := scanLogFile(component, path, func(entry ipc.LogEntry) {
+				if !query.matches(entry, minLevel) {
+					return
+				}
+				result.Total++
+				matches = append(matches, entry)
+
+				// 요청한 페이지까지의 최신 엔트리만 유지하여 메모리 사용을 제한
+				if len(matches) >= 2*keep+defaultSearchLimit {
+					matches = newestEntries(matches, keep)
+				}
+			})
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			result.FilesScanned++
+		}
+	}
+
+	matches = newestEntries(matches, keep)
+	if query.Offset < len(matches) {
+		result.Entries = matches[query.Offset:]
+	}
+	if result.Entries == nil {
+		result.Entries = []ipc.LogEntry{}
+	}
+	result.HasMore = result.Total > query.Offset+len(result.Entries)
+
+	return result, nil
+}
+
+// searchComponents 검색 대상 컴포넌트 목록 ("all"이면 로그 디렉토리 전체)
+func (m *Manager) searchComponents(component string) ([]string, error) {
+	if component != "" && component != "all" {
+		if !validComponentName(component) {
+			return nil, fmt.Errorf("invalid component name: %s", component)
+		}
+		return []string{component}, nil
+	}
+
+	dirs, err := os.ReadDir(m.config.BaseDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var components []string
+	for _, dir := range dirs {
+		if dir.IsDir() {
+			components = append(components, dir.Name())
+		}
+	}
+	return components, nil
+}
+
+// matches 엔트리가 검색 조건에 맞는지 확인
+func (q SearchQuery) matches(entry ipc.LogEntry, minLevel int) bool {
+	if !q.Since.IsZero() && entry.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && entry.Timestamp.After(q.Until) {
+		return false
+	}
+	if minLevel >= 0 {
+		// 알 수 없는 레벨은 통과
+		if level, ok := parseLevelName(entry.Level); ok && int(level) < minLevel {
+			return false
+		}
+	}
+	if q.Pattern != nil && !q.Pattern.MatchString(entry.Message) {
+		return false
+	}
+	for key, value := range q.Fields {
+		if entry.Fields[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// newestEntries 엔트리를 최신순으로 정렬하고 앞의 n개만 남김
+func newestEntries(entries []ipc.LogEntry, n int) []ipc.LogEntry {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
+
+// scanLogFile 로그 파일(gzip 압축 포함)의 각 엔트리에 대해 fn 호출
+// JSON이 아닌 줄(외부 서비스 원본 출력)은 파일 수정 시각의 INFO 엔트리로 취급
+func scanLogFile(component, path string, fn func(ipc.LogEntry)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	modTime := time.Now()
+	if info, err := file.Stat(); err == nil {
+		modTime = info.ModTime()
+	}
+
+	var reader io.Reader = file
+	if filepath.Ext(path) == ".gz" {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry ipc.LogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			entry = ipc.LogEntry{Level: "INFO", Message: line, Timestamp: modTime}
+		}
+		if entry.Process == "" {
+			entry.Process = component
+		}
+		fn(entry)
+	}
+
+	return scanner.Err()
+}
+
+// parseLevelName 레벨 이름을 LogLevel로 변환
+func parseLevelName(name string) (LogLevel, bool) {
+	name = strings.ToUpper(name)
+	if name == "WARNING" {
+		name = "WARN"
+	}
+	for level, levelName := range logLevelNames {
+		if levelName == name {
+			return level, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+func writeLogFile(t *testing.T, path string, entries ...ipc.LogEntry) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var w io.Writer = file
+	if filepath.Ext(path) == ".gz" {
+		gz := gzip.NewWriter(file)
+		defer gz.Close()
+		w = gz
+	}
+	for _, entry := range entries {
+		data, _ := json.Marshal(entry)
+		w.Write(append(data, '\n'))
+	}
+}
+
+func TestSearchRotatedAndCompressed(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "api"), 0755)
+	m := &Manager{config: &LogConfig{BaseDir: dir}, writers: map[string]*ProcessWriter{}}
+
+	now := time.Now()
+	entry := func(age time.Duration, level, message string) ipc.LogEntry {
+		return ipc.LogEntry{Process: "api", Level: level, Message: message, Timestamp: now.Add(-age)}
+	}
+	writeLogFile(t, filepath.Join(dir, "api", "api.log"),
+		entry(time.Minute, "ERROR", "request timeout"),
+		entry(time.Minute, "INFO", "request timeout"))
+	writeLogFile(t, filepath.Join(dir, "api", "api.0.log.gz"),
+		entry(time.Hour, "ERROR", "db timeout"),
+		entry(time.Hour, "ERROR", "db refused"))
+	writeLogFile(t, filepath.Join(dir, "api", "api.1.log"),
+		entry(5*time.Hour, "ERROR", "old timeout"))
+
+	query := SearchQuery{Component: "api", Level: "error", Pattern: regexp.MustCompile("timeout"), Limit: 1}
+	result, err := m.Search(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 3 || len(result.Entries) != 1 || result.Entries[0].Message != "request timeout" || !result.HasMore {
+		t.Fatalf("unexpected first page: %+v", result)
+	}
+
+	query.Offset = 2
+	if result, _ = m.Search(query); len(result.Entries) != 1 || result.Entries[0].Message != "old timeout" || result.HasMore {
+		t.Fatalf("unexpected last page: %+v", result)
+	}
+
+	query.Offset = 0
+	query.Limit = 10
+	query.Since = now.Add(-2 * time.Hour)
+	if result, _ = m.Search(query); result.Total != 2 || result.Entries[1].Message != "db timeout" {
+		t.Fatalf("unexpected since result: %+v", result)
+	}
+
+	if _, err := m.Search(SearchQuery{Component: "../etc"}); err == nil {
+		t.Fatal("expected invalid component error")
+	}
+}
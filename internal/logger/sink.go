@@ -0,0 +1,524 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// SinkType 외부 로그 싱크 종류
+type SinkType string
+
+const (
+	SinkSyslog SinkType = "syslog" // 로컬 syslog 또는 udp/tcp 원격 syslog
+	SinkLoki   SinkType = "loki"   // Grafana Loki push API
+	SinkHTTP   SinkType = "http"   // LogEntry 배열 JSON을 POST
+)
+
+// 싱크 전송 설정
+const (
+	defaultSinkBuffer = 10000 // 전송 대기 엔트리 최대 개수 (초과분은 버림)
+	defaultSinkBatch  = 100
+	sinkFlushInterval = time.Second
+	sinkSendTimeout   = 10 * time.Second
+	sinkRetryBackoff  = time.Second
+	sinkMaxBackoff    = time.Minute
+	sinkDrainTimeout  = 5 * time.Second // 종료 시 남은 엔트리 전송에 쓰는 시간
+)
+
+// SinkConfig 외부 로그 싱크 설정
+type SinkConfig struct {
+	Name       string            `json:"name"`
+	Type       SinkType          `json:"type"`
+	URL        string            `json:"url,omitempty"`        // loki (push URL), http
+	Address    string            `json:"address,omitempty"`    // syslog: udp://host:514, tcp://host:514 (비어 있으면 로컬)
+	Tag        string            `json:"tag,omitempty"`        // syslog 태그 (기본 tmidb)
+	Headers    map[string]string `json:"headers,omitempty"`    // loki, http 요청 헤더 (예: Authorization)
+	Labels     map[string]string `json:"labels,omitempty"`     // loki 스트림 라벨 (component, level은 자동 추가)
+	Components []string          `json:"components,omitempty"` // 비어 있으면 모든 컴포넌트
+	Level      string            `json:"level,omitempty"`      // 최소 로그 레벨
+	BufferSize int               `json:"buffer_size,omitempty"`
+	BatchSize  int               `json:"batch_size,omitempty"`
+	Disabled   bool              `json:"disabled,omitempty"`
+}
+
+// Validate 싱크 설정 검증
+func (c SinkConfig) Validate() error {
+	if c.Name == "" {
+		return errors.New("log sink requires a name")
+	}
+
+	switch c.Type {
+	case SinkLoki, SinkHTTP:
+		if !strings.HasPrefix(c.URL, "http://") && !strings.HasPrefix(c.URL, "https://") {
+			return fmt.Errorf("%s sink requires an http(s) url", c.Type)
+		}
+	case SinkSyslog:
+		if c.Address != "" {
+			if _, _, err := syslogAddress(c.Address); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unknown log sink type: %q", c.Type)
+	}
+
+	if c.Level != "" {
+		if _, ok := parseLevelName(c.Level); !ok {
+			return fmt.Errorf("unknown log level: %s", c.Level)
+		}
+	}
+	if c.BufferSize < 0 || c.BatchSize < 0 {
+		return errors.New("buffer_size and batch_size must not be negative")
+	}
+	return nil
+}
+
+// SinkStatus 싱크 상태 (log_sink_list 응답)
+type SinkStatus struct {
+	Name               string    `json:"name"`
+	Type               SinkType  `json:"type"`
+	Enabled            bool      `json:"enabled"`
+	Components         []string  `json:"components,omitempty"`
+	DisabledComponents []string  `json:"disabled_components,omitempty"`
+	Pending            int       `json:"pending"`
+	Sent               int64     `json:"sent"`
+	Dropped            int64     `json:"dropped"`
+	Failures           int       `json:"failures"` // 연속 전송 실패 횟수
+	LastError          string    `json:"last_error,omitempty"`
+	LastErrorAt        time.Time `json:"last_error_at,omitempty"`
+}
+
+// sinkSender 배치 하나를 외부 시스템으로 전송
+type sinkSender interface {
+	send(ctx context.Context, entries []ipc.LogEntry) error
+	close() error
+}
+
+// sink 버퍼와 재시도를 담당하는 싱크 워커
+type sink struct {
+	config       SinkConfig
+	sender       sinkSender
+	minLevel     int
+	queue        chan ipc.LogEntry
+	retryBackoff time.Duration
+	done         chan struct{}
+
+	mu          sync.Mutex
+	enabled     bool
+	disabled    map[string]bool // 실행 중 비활성화한 컴포넌트
+	sent        int64
+	dropped     int64
+	failures    int
+	lastError   string
+	lastErrorAt time.Time
+}
+
+func newSink(config SinkConfig) (*sink, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	var sender sinkSender
+	switch config.Type {
+	case SinkSyslog:
+		sender = &syslogSender{config: config}
+	case SinkLoki:
+		sender = &lokiSender{config: config, client: &http.Client{Timeout: sinkSendTimeout}}
+	case SinkHTTP:
+		sender = &httpSender{config: config, client: &http.Client{Timeout: sinkSendTimeout}}
+	}
+
+	minLevel := -1
+	if level, ok := parseLevelName(config.Level); ok {
+		minLevel = int(level)
+	}
+	if config.BufferSize == 0 {
+		config.BufferSize = defaultSinkBuffer
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = defaultSinkBatch
+	}
+
+	return &sink{
+		config:       config,
+		sender:       sender,
+		minLevel:     minLevel,
+		queue:        make(chan ipc.LogEntry, config.BufferSize),
+		retryBackoff: sinkRetryBackoff,
+		done:         make(chan struct{}),
+		enabled:      !config.Disabled,
+		disabled:     make(map[string]bool),
+	}, nil
+}
+
+// accept 조건에 맞는 엔트리를 큐에 넣음 (큐가 가득 차면 버림)
+func (s *sink) accept(entry ipc.LogEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.enabled || s.disabled[entry.Process] {
+		return
+	}
+	if len(s.config.Components) > 0 && !slices.Contains(s.config.Components, entry.Process) {
+		return
+	}
+	if s.minLevel >= 0 {
+		if level, ok := parseLevelName(entry.Level); ok && int(level) < s.minLevel {
+			return
+		}
+	}
+
+	select {
+	case s.queue <- entry:
+	default:
+		s.dropped++
+	}
+}
+
+// run 큐의 엔트리를 배치로 전송, ctx 종료 시 남은 엔트리를 한 번 더 전송하고 종료
+func (s *sink) run(ctx context.Context) {
+	defer close(s.done)
+	defer s.sender.close()
+
+	ticker := time.NewTicker(sinkFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]ipc.LogEntry, 0, s.config.BatchSize)
+	for {
+		select {
+		case entry := <-s.queue:
+			batch = append(batch, entry)
+			if len(batch) < s.config.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		case <-ctx.Done():
+			s.drain(batch)
+			return
+		}
+
+		if !s.flush(ctx, batch) {
+			// 종료 중 전송 실패: 배치를 유지한 채 drain에서 재시도
+			s.drain(batch)
+			return
+		}
+		batch = batch[:0]
+	}
+}
+
+// flush 배치가 전송될 때까지 백오프하며 재시도 (ctx 종료 시 false)
+func (s *sink) flush(ctx context.Context, batch []ipc.LogEntry) bool {
+	backoff := s.retryBackoff
+	for {
+		sendCtx, cancel := context.WithTimeout(ctx, sinkSendTimeout)
+		err := s.sender.send(sendCtx, batch)
+		cancel()
+		if err == nil {
+			s.recordSent(len(batch))
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		s.recordFailure(err)
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, sinkMaxBackoff)
+	}
+}
+
+// drain 남은 배치와 큐를 제한 시간 안에 한 번 전송
+func (s *sink) drain(batch []ipc.LogEntry) {
+	for len(s.queue) > 0 {
+		batch = append(batch, <-s.queue)
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sinkDrainTimeout)
+	defer cancel()
+	if err := s.sender.send(ctx, batch); err != nil {
+		log.Printf("⚠️ Log sink %s: dropped %d entries on shutdown: %v", s.config.Name, len(batch), err)
+		return
+	}
+	s.recordSent(len(batch))
+}
+
+func (s *sink) recordSent(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures > 0 {
+		log.Printf("✅ Log sink %s recovered after %d failed attempts", s.config.Name, s.failures)
+	}
+	s.sent += int64(n)
+	s.failures = 0
+}
+
+func (s *sink) recordFailure(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 연속 실패는 처음 한 번만 기록
+	if s.failures == 0 {
+		log.Printf("⚠️ Log sink %s failed, retrying: %v", s.config.Name, err)
+	}
+	s.failures++
+	s.lastError = err.Error()
+	s.lastErrorAt = time.Now()
+}
+
+// setEnabled 싱크 전체 또는 특정 컴포넌트 전송 활성화/비활성화
+func (s *sink) setEnabled(component string, enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if component == "" {
+		s.enabled = enabled
+		return
+	}
+	if enabled {
+		delete(s.disabled, component)
+	} else {
+		s.disabled[component] = true
+	}
+}
+
+func (s *sink) status() SinkStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := SinkStatus{
+		Name:        s.config.Name,
+		Type:        s.config.Type,
+		Enabled:     s.enabled,
+		Components:  s.config.Components,
+		Pending:     len(s.queue),
+		Sent:        s.sent,
+		Dropped:     s.dropped,
+		Failures:    s.failures,
+		LastError:   s.lastError,
+		LastErrorAt: s.lastErrorAt,
+	}
+	for component := range s.disabled {
+		status.DisabledComponents = append(status.DisabledComponents, component)
+	}
+	sort.Strings(status.DisabledComponents)
+	return status
+}
+
+// SetSinks 외부 로그 싱크 교체 (기존 싱크는 남은 엔트리를 전송한 뒤 종료)
+func (m *Manager) SetSinks(configs []SinkConfig) error {
+	sinks := make([]*sink, 0, len(configs))
+	names := make(map[string]bool)
+	for _, config := range configs {
+		if names[config.Name] {
+			return fmt.Errorf("duplicate log sink name: %s", config.Name)
+		}
+		names[config.Name] = true
+
+		s, err := newSink(config)
+		if err != nil {
+			return fmt.Errorf("log sink %q: %w", config.Name, err)
+		}
+		sinks = append(sinks, s)
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	for _, s := range sinks {
+		go s.run(ctx)
+	}
+
+	m.sinksMux.Lock()
+	oldSinks, oldCancel := m.sinks, m.sinksCancel
+	m.sinks, m.sinksCancel = sinks, cancel
+	m.sinksMux.Unlock()
+
+	if oldCancel != nil {
+		oldCancel()
+		waitSinks(oldSinks)
+	}
+
+	for _, s := range sinks {
+		log.Printf("📤 Forwarding logs to %s sink %s", s.config.Type, s.config.Name)
+	}
+	return nil
+}
+
+// stopSinks 모든 싱크 종료 (남은 엔트리 전송 대기)
+func (m *Manager) stopSinks() {
+	m.sinksMux.Lock()
+	sinks, cancel := m.sinks, m.sinksCancel
+	m.sinks, m.sinksCancel = nil, nil
+	m.sinksMux.Unlock()
+
+	if cancel != nil {
+		cancel()
+		waitSinks(sinks)
+	}
+}
+
+func waitSinks(sinks []*sink) {
+	for _, s := range sinks {
+		<-s.done
+	}
+}
+
+// forwardToSinks 엔트리를 모든 싱크 큐에 전달
+func (m *Manager) forwardToSinks(entry ipc.LogEntry) {
+	m.sinksMux.RLock()
+	defer m.sinksMux.RUnlock()
+
+	for _, s := range m.sinks {
+		s.accept(entry)
+	}
+}
+
+// SinkStatus 싱크 상태 조회
+func (m *Manager) SinkStatus() []SinkStatus {
+	m.sinksMux.RLock()
+	defer m.sinksMux.RUnlock()
+
+	statuses := make([]SinkStatus, 0, len(m.sinks))
+	for _, s := range m.sinks {
+		statuses = append(statuses, s.status())
+	}
+	return statuses
+}
+
+// SetSinkEnabled 싱크 전체(component가 빈 값) 또는 컴포넌트별 전송 활성화/비활성화
+// (재시작 시 설정 파일 값으로 돌아감)
+func (m *Manager) SetSinkEnabled(name, component string, enabled bool) error {
+	m.sinksMux.RLock()
+	defer m.sinksMux.RUnlock()
+
+	for _, s := range m.sinks {
+		if s.config.Name == name {
+			s.setEnabled(component, enabled)
+			return nil
+		}
+	}
+	return fmt.Errorf("log sink not found: %s", name)
+}
+
+func syslogAddress(address string) (network, addr string, err error) {
+	u, err := url.Parse(address)
+	if err != nil || (u.Scheme != "udp" && u.Scheme != "tcp") || u.Host == "" {
+		return "", "", fmt.Errorf("syslog address must be udp://host:port or tcp://host:port, got %q", address)
+	}
+	return u.Scheme, u.Host, nil
+}
+
+// lokiSender Loki push API로 전송 (컴포넌트/레벨별 스트림)
+type lokiSender struct {
+	config SinkConfig
+	client *http.Client
+}
+
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// lokiPayload 엔트리를 컴포넌트/레벨 라벨별 스트림으로 묶음
+func lokiPayload(labels map[string]string, entries []ipc.LogEntry) lokiPush {
+	var push lokiPush
+	index := make(map[string]int)
+	for _, entry := range entries {
+		key := entry.Process + "\x00" + entry.Level
+		i, ok := index[key]
+		if !ok {
+			stream := map[string]string{"component": entry.Process, "level": strings.ToLower(entry.Level)}
+			for k, v := range labels {
+				stream[k] = v
+			}
+			i = len(push.Streams)
+			index[key] = i
+			push.Streams = append(push.Streams, lokiStream{Stream: stream})
+		}
+		push.Streams[i].Values = append(push.Streams[i].Values,
+			[2]string{strconv.FormatInt(entry.Timestamp.UnixNano(), 10), entryLine(entry)})
+	}
+	return push
+}
+
+// entryLine 메시지 뒤에 구조화 필드를 logfmt 형식으로 붙임
+func entryLine(entry ipc.LogEntry) string {
+	if len(entry.Fields) == 0 {
+		return entry.Message
+	}
+	return entry.Message + " " + FormatFields(entry.Fields)
+}
+
+func (s *lokiSender) send(ctx context.Context, entries []ipc.LogEntry) error {
+	return postJSON(ctx, s.client, s.config.URL, s.config.Headers, lokiPayload(s.config.Labels, entries))
+}
+
+func (s *lokiSender) close() error { return nil }
+
+// httpSender 임의의 HTTP 수집기로 LogEntry 배열을 전송
+type httpSender struct {
+	config SinkConfig
+	client *http.Client
+}
+
+func (s *httpSender) send(ctx context.Context, entries []ipc.LogEntry) error {
+	return postJSON(ctx, s.client, s.config.URL, s.config.Headers, entries)
+}
+
+func (s *httpSender) close() error { return nil }
+
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"context"
+	"log/syslog"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// syslogSender syslog로 전송 (연결은 처음 전송 시 및 실패 후 다시 맺음)
+type syslogSender struct {
+	config SinkConfig
+	writer *syslog.Writer
+}
+
+func (s *syslogSender) send(ctx context.Context, entries []ipc.LogEntry) error {
+	if s.writer == nil {
+		network, addr := "", ""
+		if s.config.Address != "" {
+			network, addr, _ = syslogAddress(s.config.Address)
+		}
+		tag := s.config.Tag
+		if tag == "" {
+			tag = "tmidb"
+		}
+		writer, err := syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+		if err != nil {
+			return err
+		}
+		s.writer = writer
+	}
+
+	for _, entry := range entries {
+		message := entry.Process + ": " + entryLine(entry)
+		var err error
+		switch level, _ := parseLevelName(entry.Level); level {
+		case LogLevelError:
+			err = s.writer.Err(message)
+		case LogLevelWarn:
+			err = s.writer.Warning(message)
+		case LogLevelDebug:
+			err = s.writer.Debug(message)
+		default:
+			err = s.writer.Info(message)
+		}
+		if err != nil {
+			s.close()
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *syslogSender) close() error {
+	if s.writer == nil {
+		return nil
+	}
+	err := s.writer.Close()
+	s.writer = nil
+	return err
+}
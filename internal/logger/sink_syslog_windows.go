@@ -0,0 +1,21 @@
+package logger
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// syslogSender 윈도우에는 syslog가 없으므로 전송하지 않음 (원격 syslog는 Loki/HTTP 싱크 사용)
+type syslogSender struct {
+	config SinkConfig
+}
+
+func (s *syslogSender) send(ctx context.Context, entries []ipc.LogEntry) error {
+	return errors.New("syslog sink is not supported on windows")
+}
+
+func (s *syslogSender) close() error {
+	return nil
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+func TestHTTPSinkRetriesAndFilters(t *testing.T) {
+	var mu sync.Mutex
+	var received []ipc.LogEntry
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		var entries []ipc.LogEntry
+		json.NewDecoder(r.Body).Decode(&entries)
+		received = append(received, entries...)
+	}))
+	defer server.Close()
+
+	s, err := newSink(SinkConfig{Name: "collector", Type: SinkHTTP, URL: server.URL, Level: "warn", BatchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.retryBackoff = 10 * time.Millisecond
+	s.setEnabled("nats", false)
+
+	s.accept(ipc.LogEntry{Process: "api", Level: "INFO", Message: "below level"})
+	s.accept(ipc.LogEntry{Process: "nats", Level: "ERROR", Message: "disabled component"})
+	s.accept(ipc.LogEntry{Process: "api", Level: "ERROR", Message: "first"})
+	s.accept(ipc.LogEntry{Process: "api", Level: "WARN", Message: "second"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.run(ctx)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if s.status().Sent == 2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-s.done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 || received[0].Message != "first" || received[1].Message != "second" {
+		t.Fatalf("expected the two warn/error api entries after a retry, got %+v", received)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected one failed and one successful attempt, got %d", attempts)
+	}
+}
+
+func TestLokiPayloadGroupsStreams(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	push := lokiPayload(map[string]string{"host": "node1"}, []ipc.LogEntry{
+		{Process: "api", Level: "INFO", Message: "a", Timestamp: now},
+		{Process: "api", Level: "ERROR", Message: "b", Timestamp: now},
+		{Process: "api", Level: "INFO", Message: "c", Timestamp: now},
+	})
+
+	if len(push.Streams) != 2 {
+		t.Fatalf("expected 2 streams, got %+v", push.Streams)
+	}
+	info := push.Streams[0]
+	if info.Stream["component"] != "api" || info.Stream["level"] != "info" || info.Stream["host"] != "node1" || len(info.Values) != 2 {
+		t.Fatalf("unexpected info stream: %+v", info)
+	}
+	if info.Values[1] != [2]string{"1700000000000000000", "c"} {
+		t.Fatalf("unexpected value: %v", info.Values[1])
+	}
+}
@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MetricType Prometheus 메트릭 타입
+type MetricType string
+
+const (
+	TypeCounter MetricType = "counter"
+	TypeGauge   MetricType = "gauge"
+)
+
+// Sample 레이블이 붙은 단일 측정값
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// Family 같은 이름/타입을 공유하는 샘플 묶음
+type Family struct {
+	Name    string
+	Help    string
+	Type    MetricType
+	Samples []Sample
+}
+
+// Collector 수집 시점에 메트릭 패밀리를 생성하는 인터페이스
+type Collector interface {
+	Collect() []Family
+}
+
+// CollectorFunc 함수를 Collector로 사용하기 위한 어댑터
+type CollectorFunc func() []Family
+
+// Collect Collector 구현
+func (f CollectorFunc) Collect() []Family {
+	return f()
+}
+
+// Registry 등록된 Collector들의 메트릭을 모아 노출
+type Registry struct {
+	collectors []Collector
+	mutex      sync.RWMutex
+}
+
+// NewRegistry 새로운 레지스트리 생성
+func NewRegistry() *Registry {
+	return &Registry{
+		collectors: make([]Collector, 0),
+	}
+}
+
+// Register Collector 등록
+func (r *Registry) Register(c Collector) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.collectors = append(r.collectors, c)
+}
+
+// Gather 모든 Collector를 실행하고 이름순으로 정렬된 패밀리를 반환.
+// 같은 이름의 패밀리는 하나로 합친다.
+func (r *Registry) Gather() []Family {
+	r.mutex.RLock()
+	collectors := make([]Collector, len(r.collectors))
+	copy(collectors, r.collectors)
+	r.mutex.RUnlock()
+
+	merged := make(map[string]*Family)
+	for _, c := range collectors {
+		for _, f := range c.Collect() {
+			if existing, ok := merged[f.Name]; ok {
+				existing.Samples = append(existing.Samples, f.Samples...)
+				continue
+			}
+			family := f
+			merged[f.Name] = &family
+		}
+	}
+
+	families := make([]Family, 0, len(merged))
+	for _, f := range merged {
+		families = append(families, *f)
+	}
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].Name < families[j].Name
+	})
+
+	return families
+}
+
+// WriteText Prometheus 텍스트 포맷(0.0.4)으로 출력
+func (r *Registry) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	for _, f := range r.Gather() {
+		if f.Help != "" {
+			fmt.Fprintf(bw, "# HELP %s %s\n", f.Name, escapeHelp(f.Help))
+		}
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.Name, f.Type)
+
+		for _, sample := range f.Samples {
+			bw.WriteString(f.Name)
+			writeLabels(bw, sample.Labels)
+			bw.WriteByte(' ')
+			bw.WriteString(formatValue(sample.Value))
+			bw.WriteByte('\n')
+		}
+	}
+
+	return bw.Flush()
+}
+
+// NewGauge 단일 샘플 게이지 패밀리 생성 헬퍼
+func NewGauge(name, help string, value float64, labels map[string]string) Family {
+	return Family{
+		Name:    name,
+		Help:    help,
+		Type:    TypeGauge,
+		Samples: []Sample{{Labels: labels, Value: value}},
+	}
+}
+
+// NewCounter 단일 샘플 카운터 패밀리 생성 헬퍼
+func NewCounter(name, help string, value float64, labels map[string]string) Family {
+	return Family{
+		Name:    name,
+		Help:    help,
+		Type:    TypeCounter,
+		Samples: []Sample{{Labels: labels, Value: value}},
+	}
+}
+
+// writeLabels 레이블을 키 순서대로 출력
+func writeLabels(bw *bufio.Writer, labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	bw.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		bw.WriteString(k)
+		bw.WriteString(`="`)
+		bw.WriteString(escapeLabelValue(labels[k]))
+		bw.WriteByte('"')
+	}
+	bw.WriteByte('}')
+}
+
+// formatValue 샘플 값을 Prometheus 형식으로 변환
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelEscaper.Replace(s)
+}
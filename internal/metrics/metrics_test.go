@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(CollectorFunc(func() []Family {
+		return []Family{
+			NewGauge("tmidb_up", "Whether the supervisor is up", 1, nil),
+			{
+				Name: "tmidb_process_restarts_total",
+				Help: "Process restarts",
+				Type: TypeCounter,
+				Samples: []Sample{
+					{Labels: map[string]string{"process": "api", "type": "internal"}, Value: 3},
+				},
+			},
+		}
+	}))
+	registry.Register(CollectorFunc(func() []Family {
+		return []Family{
+			{
+				Name: "tmidb_process_restarts_total",
+				Type: TypeCounter,
+				Samples: []Sample{
+					{Labels: map[string]string{"process": "nats", "type": "external"}, Value: 0},
+				},
+			},
+		}
+	}))
+
+	var buf bytes.Buffer
+	if err := registry.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+
+	expected := `# HELP tmidb_process_restarts_total Process restarts
+# TYPE tmidb_process_restarts_total counter
+tmidb_process_restarts_total{process="api",type="internal"} 3
+tmidb_process_restarts_total{process="nats",type="external"} 0
+# HELP tmidb_up Whether the supervisor is up
+# TYPE tmidb_up gauge
+tmidb_up 1
+`
+	if buf.String() != expected {
+		t.Errorf("WriteText() =\n%s\nwant\n%s", buf.String(), expected)
+	}
+}
+
+func TestEscaping(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(CollectorFunc(func() []Family {
+		return []Family{
+			NewGauge("m", "line1\nline2 \\ end", math.Inf(1), map[string]string{"path": "a\"b\\c\nd"}),
+		}
+	}))
+
+	var buf bytes.Buffer
+	registry.WriteText(&buf)
+	out := buf.String()
+
+	if !strings.Contains(out, `# HELP m line1\nline2 \\ end`) {
+		t.Errorf("help not escaped: %q", out)
+	}
+	if !strings.Contains(out, `m{path="a\"b\\c\nd"} +Inf`) {
+		t.Errorf("label not escaped: %q", out)
+	}
+}
@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+// ContentType Prometheus 텍스트 포맷 Content-Type
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Handler 레지스트리를 노출하는 HTTP 핸들러
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", ContentType)
+		if err := r.WriteText(w); err != nil {
+			log.Printf("❌ Failed to write metrics: %v", err)
+		}
+	})
+}
+
+// Server /metrics 엔드포인트를 제공하는 HTTP 서버
+type Server struct {
+	addr     string
+	registry *Registry
+	server   *http.Server
+	listener net.Listener
+}
+
+// NewServer 새로운 메트릭 서버 생성
+func NewServer(addr string, registry *Registry) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry.Handler())
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/" {
+			http.NotFound(w, req)
+			return
+		}
+		fmt.Fprintln(w, "tmiDB supervisor metrics: /metrics")
+	})
+
+	return &Server{
+		addr:     addr,
+		registry: registry,
+		server: &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+	}
+}
+
+// Start 서버 시작 (논블로킹)
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.addr, err)
+	}
+	s.listener = listener
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("❌ Metrics server error: %v", err)
+		}
+	}()
+
+	log.Printf("📈 Metrics endpoint listening on %s/metrics", listener.Addr())
+	return nil
+}
+
+// Stop 서버 정지
+func (s *Server) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return s.server.Shutdown(ctx)
+}
+
+// Addr 실제 수신 주소 (시작 전에는 설정값)
+func (s *Server) Addr() string {
+	if s.listener != nil {
+		return s.listener.Addr().String()
+	}
+	return s.addr
+}
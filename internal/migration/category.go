@@ -0,0 +1,693 @@
+package migration
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/schema"
+)
+
+// 카테고리 데이터 마이그레이션 설정
+const (
+	defaultCategoryBatchSize = 500
+	maxCategoryBatchSize     = 10000
+	maxRecordedFailures      = 20
+	categoryMigrationType    = "category"
+	firstTargetID            = "00000000-0000-0000-0000-000000000000"
+)
+
+// CategoryPlan은 카테고리 데이터를 이전 스키마 버전에서 새 버전으로 옮기는 계획입니다.
+// 적용 순서는 이름 변경, 삭제, 기본값 채우기입니다.
+type CategoryPlan struct {
+	OrgID       string                 `json:"org_id"`
+	Category    string                 `json:"category"`
+	FromVersion int                    `json:"from_version"`
+	ToVersion   int                    `json:"to_version"`
+	Renames     map[string]string      `json:"renames,omitempty"`  // 이전 필드 -> 새 필드
+	Drops       []string               `json:"drops,omitempty"`    // 새 스키마가 허용하지 않는 필드
+	Defaults    map[string]interface{} `json:"defaults,omitempty"` // 값이 없을 때 채울 필드
+	BatchSize   int                    `json:"batch_size,omitempty"`
+	Warnings    []string               `json:"warnings,omitempty"`
+}
+
+// CategoryPreviewRow는 미리보기 대상 행 하나의 변환 전후 데이터입니다.
+type CategoryPreviewRow struct {
+	TargetID string                 `json:"target_id"`
+	Before   map[string]interface{} `json:"before"`
+	After    map[string]interface{} `json:"after"`
+	Error    string                 `json:"error,omitempty"` // 새 스키마 검증 실패
+}
+
+// CategoryPreview는 계획의 영향 범위입니다.
+type CategoryPreview struct {
+	Plan     *CategoryPlan        `json:"plan"`
+	Affected int                  `json:"affected"` // 이전 버전에 남아 있는 행 수
+	Samples  []CategoryPreviewRow `json:"samples"`
+	Invalid  int                  `json:"invalid"` // 샘플 중 검증에 실패한 행 수
+}
+
+// CategoryFailure는 옮기지 못한 행입니다.
+type CategoryFailure struct {
+	TargetID string `json:"target_id"`
+	Error    string `json:"error"`
+}
+
+// CategoryProgress는 카테고리 마이그레이션의 진행 상황과 결과입니다 (migrations.result에 기록).
+type CategoryProgress struct {
+	Total      int               `json:"total"`
+	Migrated   int               `json:"migrated"`
+	Failed     int               `json:"failed"`
+	Batches    int               `json:"batches"`
+	Failures   []CategoryFailure `json:"failures,omitempty"` // 처음 몇 개만 기록
+	StartedAt  time.Time         `json:"started_at"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
+}
+
+// schemaField는 스키마 정의의 최상위 필드 정보입니다.
+type schemaField struct {
+	Type       string
+	Default    interface{}
+	HasDefault bool
+	Required   bool
+}
+
+// schemaFields는 스키마 정의(JSON Schema 또는 이전 fields 형식)의 최상위 필드를 읽습니다.
+// closed는 정의에 없는 필드를 허용하지 않는지(additionalProperties: false) 여부입니다.
+func schemaFields(definition string) (fields map[string]schemaField, closed bool, err error) {
+	var def map[string]interface{}
+	if err := json.Unmarshal([]byte(definition), &def); err != nil {
+		return nil, false, fmt.Errorf("invalid schema definition: %w", err)
+	}
+
+	fields = make(map[string]schemaField)
+	read := func(raw interface{}) schemaField {
+		f := schemaField{}
+		prop, _ := raw.(map[string]interface{})
+		switch t := prop["type"].(type) {
+		case string:
+			f.Type = t
+		case []interface{}:
+			for _, item := range t {
+				if s, ok := item.(string); ok && s != "null" {
+					f.Type = s
+					break
+				}
+			}
+		}
+		f.Default, f.HasDefault = prop["default"]
+		return f
+	}
+
+	if props, ok := def["properties"].(map[string]interface{}); ok {
+		for name, raw := range props {
+			fields[name] = read(raw)
+		}
+	}
+	if required, ok := def["required"].([]interface{}); ok {
+		for _, item := range required {
+			if name, ok := item.(string); ok {
+				f := fields[name]
+				f.Required = true
+				fields[name] = f
+			}
+		}
+	}
+	if legacy, ok := def["fields"].(map[string]interface{}); ok {
+		for name, raw := range legacy {
+			f := read(raw)
+			if prop, ok := raw.(map[string]interface{}); ok {
+				f.Required, _ = prop["required"].(bool)
+			}
+			fields[name] = f
+		}
+	}
+
+	closed = def["additionalProperties"] == false
+	return fields, closed, nil
+}
+
+// zeroValue는 기본값이 없는 필수 필드에 채울 타입별 값을 반환합니다.
+func zeroValue(fieldType string) interface{} {
+	switch fieldType {
+	case "number", "integer":
+		return 0
+	case "boolean":
+		return false
+	case "array":
+		return []interface{}{}
+	case "object":
+		return map[string]interface{}{}
+	}
+	return ""
+}
+
+// GenerateCategoryPlan은 두 스키마 정의를 비교해 데이터 마이그레이션 계획을 만듭니다.
+//   - 사라진 필드와 (default가 없는) 새 필드가 같은 타입으로 하나씩만 짝지어지면 이름 변경으로 봅니다.
+//   - 새 필수 필드는 스키마의 default 값으로 채우고, 없으면 타입별 0 값을 쓰고 경고를 남깁니다.
+//   - 새 스키마가 추가 필드를 허용하지 않으면 사라진 필드를 삭제합니다.
+func GenerateCategoryPlan(fromDefinition, toDefinition string) (*CategoryPlan, error) {
+	oldFields, _, err := schemaFields(fromDefinition)
+	if err != nil {
+		return nil, fmt.Errorf("source schema: %w", err)
+	}
+	newFields, closed, err := schemaFields(toDefinition)
+	if err != nil {
+		return nil, fmt.Errorf("target schema: %w", err)
+	}
+
+	plan := &CategoryPlan{
+		Renames:  make(map[string]string),
+		Defaults: make(map[string]interface{}),
+	}
+
+	var removed, added []string
+	for name, f := range oldFields {
+		nf, ok := newFields[name]
+		if !ok {
+			removed = append(removed, name)
+			continue
+		}
+		if f.Type != "" && nf.Type != "" && f.Type != nf.Type {
+			plan.Warnings = append(plan.Warnings,
+				fmt.Sprintf("field %q changed type from %s to %s; existing values may fail validation", name, f.Type, nf.Type))
+		}
+	}
+	for name, f := range newFields {
+		// 스키마에 default가 있는 새 필드는 이름 변경 후보로 보지 않음
+		if _, ok := oldFields[name]; !ok && !f.HasDefault {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(removed)
+	sort.Strings(added)
+
+	sameType := func(name string, candidates []string, fields, others map[string]schemaField) []string {
+		var matches []string
+		for _, other := range candidates {
+			if t := fields[name].Type; t != "" && t == others[other].Type {
+				matches = append(matches, other)
+			}
+		}
+		return matches
+	}
+
+	renamed := make(map[string]bool)
+	for _, name := range added {
+		candidates := sameType(name, removed, newFields, oldFields)
+		if len(candidates) != 1 || renamed[candidates[0]] {
+			continue
+		}
+		if len(sameType(candidates[0], added, oldFields, newFields)) != 1 {
+			continue
+		}
+		plan.Renames[candidates[0]] = name
+		renamed[candidates[0]] = true
+		plan.Warnings = append(plan.Warnings,
+			fmt.Sprintf("assuming %q was renamed to %q (same type); edit renames if not", candidates[0], name))
+	}
+
+	renamedTo := make(map[string]bool)
+	for _, to := range plan.Renames {
+		renamedTo[to] = true
+	}
+	names := make([]string, 0, len(newFields))
+	for name := range newFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := newFields[name]
+		if _, ok := oldFields[name]; ok || renamedTo[name] || !f.Required {
+			continue
+		}
+		if f.HasDefault {
+			plan.Defaults[name] = f.Default
+			continue
+		}
+		plan.Defaults[name] = zeroValue(f.Type)
+		plan.Warnings = append(plan.Warnings,
+			fmt.Sprintf("required field %q has no default in the schema; using %v", name, plan.Defaults[name]))
+	}
+
+	if closed {
+		for _, name := range removed {
+			if !renamed[name] {
+				plan.Drops = append(plan.Drops, name)
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// Apply는 계획에 따라 데이터 한 건을 변환합니다. 원본은 바꾸지 않습니다.
+func (p *CategoryPlan) Apply(data map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(data)+len(p.Defaults))
+	for k, v := range data {
+		out[k] = v
+	}
+	for from, to := range p.Renames {
+		if v, ok := out[from]; ok {
+			if _, exists := out[to]; !exists {
+				out[to] = v
+			}
+			delete(out, from)
+		}
+	}
+	for _, name := range p.Drops {
+		delete(out, name)
+	}
+	for name, v := range p.Defaults {
+		if _, ok := out[name]; !ok {
+			out[name] = v
+		}
+	}
+	return out
+}
+
+// validate는 실행 전에 계획의 기본 조건을 확인합니다.
+func (p *CategoryPlan) validate() error {
+	if p.OrgID == "" || p.Category == "" {
+		return fmt.Errorf("plan needs org_id and category")
+	}
+	if p.FromVersion <= 0 || p.ToVersion <= 0 || p.FromVersion == p.ToVersion {
+		return fmt.Errorf("plan needs different from_version and to_version")
+	}
+	if p.BatchSize < 0 || p.BatchSize > maxCategoryBatchSize {
+		return fmt.Errorf("batch_size must be between 1 and %d", maxCategoryBatchSize)
+	}
+	for from, to := range p.Renames {
+		if from == "" || to == "" {
+			return fmt.Errorf("renames must not have empty field names")
+		}
+	}
+	return nil
+}
+
+func (p *CategoryPlan) batchSize() int {
+	if p.BatchSize <= 0 {
+		return defaultCategoryBatchSize
+	}
+	return p.BatchSize
+}
+
+// categoryDefinition은 카테고리 스키마 한 버전의 정의를 조회합니다.
+func (m *MigrationManager) categoryDefinition(orgID, category string, version int) (string, error) {
+	var definition string
+	err := m.db.QueryRow(
+		`SELECT schema_definition FROM category_schemas WHERE org_id = $1 AND category_name = $2 AND version = $3`,
+		orgID, category, version,
+	).Scan(&definition)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("category %s has no schema version %d", category, version)
+	}
+	return definition, err
+}
+
+// ResolveCategoryOrg는 카테고리를 가진 조직을 찾습니다. 여러 조직에 같은 이름이 있으면 오류입니다.
+func (m *MigrationManager) ResolveCategoryOrg(category string) (string, error) {
+	rows, err := m.db.Query(`SELECT DISTINCT org_id::text FROM category_schemas WHERE category_name = $1`, category)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var orgs []string
+	for rows.Next() {
+		var orgID string
+		if err := rows.Scan(&orgID); err != nil {
+			return "", err
+		}
+		orgs = append(orgs, orgID)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	switch len(orgs) {
+	case 0:
+		return "", fmt.Errorf("category not found: %s", category)
+	case 1:
+		return orgs[0], nil
+	}
+	return "", fmt.Errorf("category %s exists in %d organizations; specify the organization", category, len(orgs))
+}
+
+// categoryVersions는 비교할 두 스키마 버전과 정의를 정합니다.
+// toVersion이 0이면 최신 버전, fromVersion이 0이면 toVersion 바로 앞 버전을 사용합니다.
+func (m *MigrationManager) categoryVersions(orgID, category string, fromVersion, toVersion int) (from, to int, fromDefinition, toDefinition string, err error) {
+	if toVersion == 0 {
+		err = m.db.QueryRow(
+			`SELECT COALESCE(MAX(version), 0) FROM category_schemas WHERE org_id = $1 AND category_name = $2`,
+			orgID, category,
+		).Scan(&toVersion)
+		if err != nil {
+			return 0, 0, "", "", err
+		}
+		if toVersion == 0 {
+			return 0, 0, "", "", fmt.Errorf("category not found: %s", category)
+		}
+	}
+	if fromVersion == 0 {
+		fromVersion = toVersion - 1
+	}
+	if fromVersion <= 0 || fromVersion == toVersion {
+		return 0, 0, "", "", fmt.Errorf("category %s has no earlier schema version to compare with", category)
+	}
+
+	if fromDefinition, err = m.categoryDefinition(orgID, category, fromVersion); err != nil {
+		return 0, 0, "", "", err
+	}
+	if toDefinition, err = m.categoryDefinition(orgID, category, toVersion); err != nil {
+		return 0, 0, "", "", err
+	}
+	return fromVersion, toVersion, fromDefinition, toDefinition, nil
+}
+
+// CategorySchemaDiff는 카테고리 스키마 두 버전의 비교 결과입니다.
+type CategorySchemaDiff struct {
+	OrgID       string `json:"org_id"`
+	Category    string `json:"category"`
+	FromVersion int    `json:"from_version"`
+	ToVersion   int    `json:"to_version"`
+	*schema.Diff
+}
+
+// DiffCategorySchemas는 카테고리 스키마 두 버전을 비교해 변경을 하위 호환/깨지는 변경으로 분류합니다.
+// 버전 기본값은 PlanCategoryMigration과 같습니다.
+func (m *MigrationManager) DiffCategorySchemas(orgID, category string, fromVersion, toVersion int) (*CategorySchemaDiff, error) {
+	fromVersion, toVersion, fromDefinition, toDefinition, err := m.categoryVersions(orgID, category, fromVersion, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := schema.CompareDefinitions(fromDefinition, toDefinition)
+	if err != nil {
+		return nil, err
+	}
+	return &CategorySchemaDiff{OrgID: orgID, Category: category, FromVersion: fromVersion, ToVersion: toVersion, Diff: diff}, nil
+}
+
+// PlanCategoryMigration은 카테고리의 데이터 마이그레이션 계획을 만듭니다.
+// toVersion이 0이면 최신 버전, fromVersion이 0이면 toVersion 바로 앞 버전을 사용합니다.
+func (m *MigrationManager) PlanCategoryMigration(orgID, category string, fromVersion, toVersion int) (*CategoryPlan, error) {
+	fromVersion, toVersion, fromDefinition, toDefinition, err := m.categoryVersions(orgID, category, fromVersion, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := GenerateCategoryPlan(fromDefinition, toDefinition)
+	if err != nil {
+		return nil, err
+	}
+	plan.OrgID = orgID
+	plan.Category = category
+	plan.FromVersion = fromVersion
+	plan.ToVersion = toVersion
+	return plan, nil
+}
+
+// categoryRow는 이전 버전에 남아 있는 target_categories 행입니다.
+type categoryRow struct {
+	targetID string
+	data     []byte
+}
+
+// selectCategoryRows는 target_id 순서로 afterTargetID 다음의 행들을 읽습니다.
+func selectCategoryRows(q interface {
+	Query(string, ...interface{}) (*sql.Rows, error)
+}, p *CategoryPlan, afterTargetID string, limit int, lock bool) ([]categoryRow, error) {
+	query := `SELECT target_id::text, category_data FROM target_categories
+		 WHERE org_id = $1 AND category_name = $2 AND schema_version = $3 AND target_id > $4::uuid
+		 ORDER BY target_id LIMIT $5`
+	if lock {
+		query += " FOR UPDATE SKIP LOCKED"
+	}
+	rows, err := q.Query(query, p.OrgID, p.Category, p.FromVersion, afterTargetID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []categoryRow
+	for rows.Next() {
+		var row categoryRow
+		if err := rows.Scan(&row.targetID, &row.data); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// convert는 행 하나를 변환하고 새 스키마로 검증합니다.
+func convert(p *CategoryPlan, target *schema.Schema, raw []byte) (before, after map[string]interface{}, err error) {
+	if err := json.Unmarshal(raw, &before); err != nil {
+		return nil, nil, fmt.Errorf("invalid category_data: %w", err)
+	}
+	if before == nil {
+		before = map[string]interface{}{}
+	}
+	after = p.Apply(before)
+	return before, after, target.Validate(after)
+}
+
+// compileTarget은 계획의 대상 버전 스키마를 컴파일합니다.
+func (m *MigrationManager) compileTarget(p *CategoryPlan) (*schema.Schema, error) {
+	definition, err := m.categoryDefinition(p.OrgID, p.Category, p.ToVersion)
+	if err != nil {
+		return nil, err
+	}
+	return schema.Cached(definition)
+}
+
+// PreviewCategoryMigration은 영향받는 행 수와 처음 limit개 행의 변환 결과를 보여 줍니다.
+func (m *MigrationManager) PreviewCategoryMigration(p *CategoryPlan, limit int) (*CategoryPreview, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	target, err := m.compileTarget(p)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &CategoryPreview{Plan: p, Samples: []CategoryPreviewRow{}}
+	err = m.db.QueryRow(
+		`SELECT COUNT(*) FROM target_categories WHERE org_id = $1 AND category_name = $2 AND schema_version = $3`,
+		p.OrgID, p.Category, p.FromVersion,
+	).Scan(&preview.Affected)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+	rows, err := selectCategoryRows(m.db, p, firstTargetID, limit, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		sample := CategoryPreviewRow{TargetID: row.targetID}
+		sample.Before, sample.After, err = convert(p, target, row.data)
+		if err != nil {
+			sample.Error = err.Error()
+			preview.Invalid++
+		}
+		preview.Samples = append(preview.Samples, sample)
+	}
+	return preview, nil
+}
+
+// CreateCategoryMigration은 카테고리 데이터 마이그레이션을 migrations 테이블에 등록합니다.
+func (m *MigrationManager) CreateCategoryMigration(name, description string, p *CategoryPlan) (*Migration, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	if _, err := m.categoryDefinition(p.OrgID, p.Category, p.FromVersion); err != nil {
+		return nil, err
+	}
+	if _, err := m.categoryDefinition(p.OrgID, p.Category, p.ToVersion); err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = fmt.Sprintf("%s_v%d_to_v%d_%s", p.Category, p.FromVersion, p.ToVersion, time.Now().UTC().Format("20060102150405"))
+	}
+	var exists bool
+	if err := m.db.QueryRow("SELECT EXISTS(SELECT 1 FROM migrations WHERE name = $1)", name).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("이름 중복 확인 실패: %v", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("마이그레이션 이름이 이미 존재합니다: %s", name)
+	}
+	if description == "" {
+		description = fmt.Sprintf("Migrate %s data from schema v%d to v%d", p.Category, p.FromVersion, p.ToVersion)
+	}
+
+	planJSON, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	migration := &Migration{
+		Name:        name,
+		Description: description,
+		Category:    p.Category,
+		Version:     fmt.Sprintf("%d", p.ToVersion),
+		Type:        categoryMigrationType,
+		Status:      "pending",
+		OrgID:       p.OrgID,
+		Plan:        p,
+	}
+	err = m.db.QueryRow(
+		`INSERT INTO migrations (name, description, category, version, type, status, org_id, plan)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 RETURNING id, created_at`,
+		migration.Name, migration.Description, migration.Category, migration.Version,
+		migration.Type, migration.Status, migration.OrgID, string(planJSON),
+	).Scan(&migration.ID, &migration.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("마이그레이션 생성 실패: %v", err)
+	}
+	return migration, nil
+}
+
+// claimMigration은 실행 중이거나 완료되지 않은 마이그레이션을 실행 중으로 바꿉니다.
+func (m *MigrationManager) claimMigration(id int) error {
+	res, err := m.db.Exec(
+		`UPDATE migrations SET status = 'running', error = '' WHERE id = $1 AND status NOT IN ('running', 'completed')`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("마이그레이션이 이미 실행 중이거나 완료되었습니다: ID %d", id)
+	}
+	return nil
+}
+
+// saveProgress는 진행 상황을 migrations.result에 기록합니다.
+func (m *MigrationManager) saveProgress(id int, progress *CategoryProgress) error {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	_, err = m.db.Exec(`UPDATE migrations SET result = $2 WHERE id = $1`, id, string(data))
+	return err
+}
+
+// executeCategoryMigration은 이전 버전 행을 배치 단위로 변환해 새 버전으로 올립니다.
+// 배치마다 트랜잭션을 나누고, 새 스키마 검증에 실패한 행은 이전 버전에 그대로 둡니다.
+// 실패한 행이 있으면 마이그레이션은 failed가 되며, 데이터를 고친 뒤 다시 실행하면 남은 행만 옮깁니다.
+func (m *MigrationManager) executeCategoryMigration(migration *Migration) (*CategoryProgress, error) {
+	p := migration.Plan
+	progress := &CategoryProgress{StartedAt: time.Now()}
+
+	finish := func(err error) (*CategoryProgress, error) {
+		now := time.Now()
+		progress.FinishedAt = &now
+		m.saveProgress(migration.ID, progress)
+		if err == nil && progress.Failed > 0 {
+			err = fmt.Errorf("%d행이 새 스키마 검증에 실패해 v%d에 남았습니다", progress.Failed, p.FromVersion)
+		}
+		if err != nil {
+			m.updateMigrationStatus(migration.ID, "failed", err.Error())
+			return progress, err
+		}
+		m.updateMigrationStatus(migration.ID, "completed", "")
+		m.updateExecutedAt(migration.ID)
+		return progress, nil
+	}
+
+	if p == nil {
+		return finish(fmt.Errorf("마이그레이션에 계획이 없습니다"))
+	}
+	if err := p.validate(); err != nil {
+		return finish(err)
+	}
+	target, err := m.compileTarget(p)
+	if err != nil {
+		return finish(err)
+	}
+	err = m.db.QueryRow(
+		`SELECT COUNT(*) FROM target_categories WHERE org_id = $1 AND category_name = $2 AND schema_version = $3`,
+		p.OrgID, p.Category, p.FromVersion,
+	).Scan(&progress.Total)
+	if err != nil {
+		return finish(err)
+	}
+	m.saveProgress(migration.ID, progress)
+
+	after := firstTargetID
+	for {
+		n, last, err := m.migrateCategoryBatch(migration.ID, p, target, after, progress)
+		if err != nil {
+			return finish(fmt.Errorf("배치 %d 실패: %v", progress.Batches+1, err))
+		}
+		if n == 0 {
+			return finish(nil)
+		}
+		progress.Batches++
+		after = last
+		m.saveProgress(migration.ID, progress)
+	}
+}
+
+// migrateCategoryBatch는 배치 하나를 한 트랜잭션으로 옮기고 읽은 행 수와 마지막 target_id를 반환합니다.
+// 바꾸기 전의 행은 롤백을 위해 migration_row_backups에 남깁니다.
+func (m *MigrationManager) migrateCategoryBatch(migrationID int, p *CategoryPlan, target *schema.Schema, after string, progress *CategoryProgress) (int, string, error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return 0, "", err
+	}
+	defer tx.Rollback()
+
+	rows, err := selectCategoryRows(tx, p, after, p.batchSize(), true)
+	if err != nil || len(rows) == 0 {
+		return 0, "", err
+	}
+
+	migrated, failed := 0, []CategoryFailure{}
+	for _, row := range rows {
+		_, converted, err := convert(p, target, row.data)
+		if err != nil {
+			failed = append(failed, CategoryFailure{TargetID: row.targetID, Error: err.Error()})
+			continue
+		}
+		data, err := json.Marshal(converted)
+		if err != nil {
+			failed = append(failed, CategoryFailure{TargetID: row.targetID, Error: err.Error()})
+			continue
+		}
+		_, err = tx.Exec(
+			`INSERT INTO migration_row_backups (migration_id, target_id, category_name, schema_version, category_data)
+			 VALUES ($1, $2, $3, $4, $5)
+			 ON CONFLICT (migration_id, target_id) DO NOTHING`,
+			migrationID, row.targetID, p.Category, p.FromVersion, string(row.data),
+		)
+		if err != nil {
+			return 0, "", err
+		}
+		_, err = tx.Exec(
+			`UPDATE target_categories SET category_data = $4, schema_version = $5, updated_at = now()
+			 WHERE target_id = $1 AND category_name = $2 AND schema_version = $3`,
+			row.targetID, p.Category, p.FromVersion, string(data), p.ToVersion,
+		)
+		if err != nil {
+			return 0, "", err
+		}
+		migrated++
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, "", err
+	}
+
+	progress.Migrated += migrated
+	progress.Failed += len(failed)
+	for _, f := range failed {
+		if len(progress.Failures) < maxRecordedFailures {
+			progress.Failures = append(progress.Failures, f)
+		}
+	}
+	return len(rows), rows[len(rows)-1].targetID, nil
+}
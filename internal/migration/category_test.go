@@ -0,0 +1,68 @@
+package migration
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGenerateCategoryPlan(t *testing.T) {
+	from := `{"type": "object", "properties": {"temp": {"type": "number"}, "loc": {"type": "string"}, "note": {"type": "string"}, "old": {"type": "boolean"}},
+		"required": ["temp"]}`
+	to := `{"type": "object", "additionalProperties": false,
+		"properties": {"temp": {"type": "number"}, "location": {"type": "string"}, "unit": {"type": "string", "default": "C"}, "count": {"type": "integer"}, "note": {"type": "string"}},
+		"required": ["temp", "location", "unit", "count"]}`
+
+	plan, err := GenerateCategoryPlan(from, to)
+	if err != nil {
+		t.Fatalf("GenerateCategoryPlan: %v", err)
+	}
+	if want := map[string]string{"loc": "location"}; !reflect.DeepEqual(plan.Renames, want) {
+		t.Errorf("renames = %v, want %v", plan.Renames, want)
+	}
+	if want := []string{"old"}; !reflect.DeepEqual(plan.Drops, want) {
+		t.Errorf("drops = %v, want %v", plan.Drops, want)
+	}
+	if want := map[string]interface{}{"unit": "C", "count": 0}; !reflect.DeepEqual(plan.Defaults, want) {
+		t.Errorf("defaults = %v, want %v", plan.Defaults, want)
+	}
+	if len(plan.Warnings) != 2 {
+		t.Errorf("expected rename and missing default warnings, got %v", plan.Warnings)
+	}
+}
+
+func TestGenerateCategoryPlanAmbiguousRename(t *testing.T) {
+	from := `{"fields": {"a": {"type": "string"}, "b": {"type": "string"}}}`
+	to := `{"fields": {"c": {"type": "string", "required": true}}}`
+
+	plan, err := GenerateCategoryPlan(from, to)
+	if err != nil {
+		t.Fatalf("GenerateCategoryPlan: %v", err)
+	}
+	if len(plan.Renames) != 0 {
+		t.Errorf("ambiguous rename should not be guessed: %v", plan.Renames)
+	}
+	if plan.Defaults["c"] != "" {
+		t.Errorf("expected empty string default for c, got %v", plan.Defaults)
+	}
+	if len(plan.Drops) != 0 {
+		t.Errorf("open schema should keep old fields: %v", plan.Drops)
+	}
+}
+
+func TestCategoryPlanApply(t *testing.T) {
+	plan := &CategoryPlan{
+		Renames:  map[string]string{"loc": "location", "x": "y"},
+		Drops:    []string{"old"},
+		Defaults: map[string]interface{}{"unit": "C", "temp": 0},
+	}
+	data := map[string]interface{}{"loc": "seoul", "x": 1, "y": 2, "old": true, "temp": 21.5}
+
+	got := plan.Apply(data)
+	want := map[string]interface{}{"location": "seoul", "y": 2, "temp": 21.5, "unit": "C"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply = %v, want %v", got, want)
+	}
+	if _, ok := data["location"]; ok {
+		t.Error("Apply must not modify its input")
+	}
+}
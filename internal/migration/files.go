@@ -0,0 +1,250 @@
+package migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 파일 마이그레이션 설정
+const (
+	fileMigrationSource   = "file"
+	fileMigrationCategory = "file"
+	fileMigrationLockKey  = 7_446_830_147 // pg_advisory_lock 키 (여러 API 인스턴스가 동시에 실행하지 않도록)
+)
+
+// fileMigrationPattern NNN_name.sql, NNN_name.js, NNN_name.down.sql 형식
+var fileMigrationPattern = regexp.MustCompile(`^(\d+)_([A-Za-z0-9_-]+?)(\.down)?\.(sql|js)$`)
+
+// FileMigration은 migrations 디렉터리에서 읽은 마이그레이션 하나입니다.
+type FileMigration struct {
+	Sequence int
+	Name     string // 확장자를 뺀 파일 이름 (예: 001_create_devices)
+	File     string
+	Type     string // sql 또는 script
+	Up       string
+	Down     string // 같은 이름의 .down 파일 (선택)
+	Checksum string // Up 내용의 SHA-256
+}
+
+// checksum은 마이그레이션 내용의 SHA-256을 반환합니다
+func checksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadMigrationFiles는 디렉터리의 마이그레이션 파일을 번호 순으로 읽습니다.
+// 디렉터리가 없으면 빈 목록을 반환합니다. 번호가 겹치거나 .down 파일만 있으면 오류입니다.
+func LoadMigrationFiles(dir string) ([]FileMigration, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("마이그레이션 디렉터리 읽기 실패: %v", err)
+	}
+
+	byName := make(map[string]*FileMigration)
+	downs := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileMigrationPattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("마이그레이션 파일 읽기 실패: %v", err)
+		}
+
+		seq, _ := strconv.Atoi(match[1])
+		name := match[1] + "_" + match[2]
+		if match[3] != "" {
+			downs[name] = string(content)
+			continue
+		}
+		if _, dup := byName[name]; dup {
+			return nil, fmt.Errorf("같은 이름의 마이그레이션 파일이 여러 개입니다: %s", name)
+		}
+		migrationType := "sql"
+		if match[4] == "js" {
+			migrationType = "script"
+		}
+		byName[name] = &FileMigration{
+			Sequence: seq,
+			Name:     name,
+			File:     entry.Name(),
+			Type:     migrationType,
+			Up:       string(content),
+			Checksum: checksum(string(content)),
+		}
+	}
+
+	files := make([]FileMigration, 0, len(byName))
+	for name, down := range downs {
+		f, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("롤백 파일에 맞는 마이그레이션 파일이 없습니다: %s", name)
+		}
+		f.Down = down
+	}
+	for _, f := range byName {
+		files = append(files, *f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Sequence < files[j].Sequence })
+
+	for i := 1; i < len(files); i++ {
+		if files[i].Sequence == files[i-1].Sequence {
+			return nil, fmt.Errorf("마이그레이션 번호가 겹칩니다: %s, %s", files[i-1].File, files[i].File)
+		}
+	}
+	return files, nil
+}
+
+// editableStatus는 파일이 바뀌었을 때 DB의 내용을 새 파일로 바꿔도 되는 상태인지 확인합니다.
+// 적용된(completed, running) 마이그레이션의 파일이 바뀌면 체크섬 불일치입니다.
+func editableStatus(status string) bool {
+	return status == "pending" || status == "failed" || status == "rollback"
+}
+
+// SyncFileMigrations는 파일 마이그레이션을 migrations 테이블에 반영합니다.
+// 새 파일은 pending으로 추가하고, 아직 적용되지 않은 마이그레이션은 파일 내용으로 갱신합니다.
+// 이미 적용된 마이그레이션의 파일이 바뀌었으면 해당 파일 목록과 함께 오류를 반환합니다.
+func (m *MigrationManager) SyncFileMigrations(files []FileMigration) error {
+	var mismatched []string
+	for _, f := range files {
+		var id int
+		var status, source, stored string
+		err := m.db.QueryRow(
+			`SELECT id, status, COALESCE(source, ''), COALESCE(checksum, '') FROM migrations WHERE name = $1`, f.Name,
+		).Scan(&id, &status, &source, &stored)
+
+		sqlText, script := f.Up, ""
+		downSQL, downScript := f.Down, ""
+		if f.Type == "script" {
+			sqlText, script = "", f.Up
+			downSQL, downScript = "", f.Down
+		}
+
+		switch {
+		case err == sql.ErrNoRows:
+			_, err = m.db.Exec(
+				`INSERT INTO migrations (name, description, category, version, sql, script, down_sql, down_script,
+					type, status, checksum, sequence, source)
+				 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, 'pending', $10, $11, $12)`,
+				f.Name, "Loaded from "+f.File, fileMigrationCategory, strconv.Itoa(f.Sequence),
+				sqlText, script, downSQL, downScript, f.Type, f.Checksum, f.Sequence, fileMigrationSource,
+			)
+			if err != nil {
+				return fmt.Errorf("마이그레이션 등록 실패 (%s): %v", f.File, err)
+			}
+			log.Printf("마이그레이션 파일 등록됨: %s", f.File)
+			continue
+		case err != nil:
+			return fmt.Errorf("마이그레이션 조회 실패 (%s): %v", f.File, err)
+		case source != fileMigrationSource:
+			return fmt.Errorf("파일 마이그레이션 이름이 DB에 저장된 마이그레이션과 겹칩니다: %s", f.Name)
+		}
+
+		if stored != f.Checksum && !editableStatus(status) {
+			mismatched = append(mismatched, f.File)
+			continue
+		}
+		// 내용이 바뀌지 않았어도 롤백 파일은 언제든 고칠 수 있음
+		_, err = m.db.Exec(
+			`UPDATE migrations SET sql = $2, script = $3, down_sql = $4, down_script = $5, checksum = $6, sequence = $7
+			 WHERE id = $1`,
+			id, sqlText, script, downSQL, downScript, f.Checksum, f.Sequence,
+		)
+		if err != nil {
+			return fmt.Errorf("마이그레이션 갱신 실패 (%s): %v", f.File, err)
+		}
+	}
+
+	if len(mismatched) > 0 {
+		return fmt.Errorf("이미 적용된 마이그레이션 파일이 수정되었습니다 (체크섬 불일치): %s", strings.Join(mismatched, ", "))
+	}
+	return nil
+}
+
+// RunFileMigrations는 디렉터리의 마이그레이션 파일을 동기화하고 아직 적용되지 않은 것을 번호 순으로 실행합니다.
+// 하나라도 실패하면 그 뒤의 마이그레이션은 실행하지 않습니다. 롤백된 마이그레이션은 다시 실행하지 않습니다.
+// 여러 인스턴스가 동시에 시작해도 한 곳에서만 실행되도록 advisory lock을 잡습니다.
+func (m *MigrationManager) RunFileMigrations(ctx context.Context, dir string) (applied []string, err error) {
+	files, err := LoadMigrationFiles(dir)
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", fileMigrationLockKey); err != nil {
+		return nil, fmt.Errorf("마이그레이션 잠금 실패: %v", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", fileMigrationLockKey)
+
+	if err := m.SyncFileMigrations(files); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx,
+		`SELECT id, name, sequence FROM migrations
+		 WHERE source = $1 AND status IN ('pending', 'failed')
+		 ORDER BY sequence, name`, fileMigrationSource)
+	if err != nil {
+		return nil, err
+	}
+	type pendingMigration struct {
+		id       int
+		name     string
+		sequence int
+	}
+	var pending []pendingMigration
+	for rows.Next() {
+		var p pendingMigration
+		if err := rows.Scan(&p.id, &p.name, &p.sequence); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		pending = append(pending, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var lastApplied int
+	m.db.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(sequence), 0) FROM migrations WHERE source = $1 AND status = 'completed'`,
+		fileMigrationSource).Scan(&lastApplied)
+
+	for _, p := range pending {
+		if p.sequence < lastApplied {
+			log.Printf("⚠️ 마이그레이션 %s의 번호가 이미 적용된 마이그레이션(%d)보다 앞섭니다; 순서에 주의하세요", p.name, lastApplied)
+		}
+		result, err := m.ExecuteMigration(p.id)
+		if err == nil && !result.Success {
+			err = errors.New(result.Error)
+		}
+		if err != nil {
+			return applied, fmt.Errorf("마이그레이션 %s 실패: %v", p.name, err)
+		}
+		applied = append(applied, p.name)
+	}
+	return applied, nil
+}
@@ -0,0 +1,69 @@
+package migration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeMigrationFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadMigrationFiles(t *testing.T) {
+	dir := writeMigrationFiles(t, map[string]string{
+		"010_add_index.sql":         "CREATE INDEX i ON t(c)",
+		"002_create_table.sql":      "CREATE TABLE t (c int)",
+		"002_create_table.down.sql": "DROP TABLE t",
+		"003_backfill.js":           "// script",
+		"README.md":                 "not a migration",
+		"create_without_number.sql": "SELECT 1",
+	})
+
+	files, err := LoadMigrationFiles(dir)
+	if err != nil {
+		t.Fatalf("LoadMigrationFiles: %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("expected 3 migrations, got %d", len(files))
+	}
+
+	want := []struct{ name, typ string }{
+		{"002_create_table", "sql"}, {"003_backfill", "script"}, {"010_add_index", "sql"},
+	}
+	for i, w := range want {
+		if files[i].Name != w.name || files[i].Type != w.typ {
+			t.Errorf("files[%d] = %s (%s), want %s (%s)", i, files[i].Name, files[i].Type, w.name, w.typ)
+		}
+	}
+	if files[0].Down != "DROP TABLE t" {
+		t.Errorf("down file not attached: %q", files[0].Down)
+	}
+	if files[0].Checksum != checksum("CREATE TABLE t (c int)") {
+		t.Error("checksum should cover the up file")
+	}
+}
+
+func TestLoadMigrationFilesErrors(t *testing.T) {
+	cases := map[string]map[string]string{
+		"duplicate sequence": {"001_a.sql": "SELECT 1", "001_b.sql": "SELECT 2"},
+		"orphan down file":   {"001_a.down.sql": "SELECT 1"},
+	}
+	for name, files := range cases {
+		if _, err := LoadMigrationFiles(writeMigrationFiles(t, files)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	files, err := LoadMigrationFiles(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || files != nil {
+		t.Errorf("missing directory should be empty, got %v, %v", files, err)
+	}
+}
@@ -2,6 +2,8 @@ package migration
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -17,11 +19,23 @@ type Migration struct {
 	Version     string     `json:"version" db:"version"`
 	SQL         string     `json:"sql,omitempty" db:"sql"`
 	Script      string     `json:"script,omitempty" db:"script"`
-	Type        string     `json:"type" db:"type"` // "sql" or "script"
+	DownSQL     string     `json:"down_sql,omitempty" db:"down_sql"`       // 롤백 SQL (선택)
+	DownScript  string     `json:"down_script,omitempty" db:"down_script"` // 롤백 스크립트 (선택)
+	Type        string     `json:"type" db:"type"`                         // "sql", "script" or "category"
 	Status      string     `json:"status" db:"status"`
 	Error       string     `json:"error,omitempty" db:"error"`
 	ExecutedAt  *time.Time `json:"executed_at,omitempty" db:"executed_at"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+
+	// 카테고리 데이터 마이그레이션 (type "category")
+	OrgID  string          `json:"org_id,omitempty" db:"org_id"`
+	Plan   *CategoryPlan   `json:"plan,omitempty" db:"plan"`
+	Result json.RawMessage `json:"result,omitempty" db:"result"` // CategoryProgress
+
+	// 파일 마이그레이션 (migrations 디렉터리의 NNN_name.sql / .js)
+	Source   string `json:"source,omitempty" db:"source"` // "file" 또는 비어 있음 (DB에 직접 저장)
+	Sequence int    `json:"sequence,omitempty" db:"sequence"`
+	Checksum string `json:"checksum,omitempty" db:"checksum"`
 }
 
 // MigrationResult는 마이그레이션 실행 결과를 나타냅니다
@@ -65,6 +79,35 @@ func (m *MigrationManager) InitializeMigrationTable() error {
 	CREATE INDEX IF NOT EXISTS idx_migrations_category ON migrations(category);
 	CREATE INDEX IF NOT EXISTS idx_migrations_status ON migrations(status);
 	CREATE INDEX IF NOT EXISTS idx_migrations_created_at ON migrations(created_at);
+
+	-- 카테고리 데이터 마이그레이션 (기존 설치에도 추가)
+	ALTER TABLE migrations ADD COLUMN IF NOT EXISTS org_id TEXT;
+	ALTER TABLE migrations ADD COLUMN IF NOT EXISTS plan JSONB;
+	ALTER TABLE migrations ADD COLUMN IF NOT EXISTS result JSONB;
+	ALTER TABLE migrations DROP CONSTRAINT IF EXISTS migrations_type_check;
+	ALTER TABLE migrations ADD CONSTRAINT migrations_type_check CHECK (type IN ('sql', 'script', 'category'));
+
+	-- 롤백
+	ALTER TABLE migrations ADD COLUMN IF NOT EXISTS down_sql TEXT;
+	ALTER TABLE migrations ADD COLUMN IF NOT EXISTS down_script TEXT;
+	ALTER TABLE migrations ADD COLUMN IF NOT EXISTS rolled_back_at TIMESTAMP;
+
+	-- 카테고리 마이그레이션이 바꾸기 전의 행 (롤백 시 복원)
+	CREATE TABLE IF NOT EXISTS migration_row_backups (
+		migration_id INTEGER NOT NULL REFERENCES migrations(id) ON DELETE CASCADE,
+		target_id UUID NOT NULL,
+		category_name TEXT NOT NULL,
+		schema_version INTEGER NOT NULL,
+		category_data JSONB NOT NULL,
+		migrated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		PRIMARY KEY (migration_id, target_id)
+	);
+
+	-- 파일 마이그레이션
+	ALTER TABLE migrations ADD COLUMN IF NOT EXISTS source VARCHAR(10);
+	ALTER TABLE migrations ADD COLUMN IF NOT EXISTS sequence INTEGER;
+	ALTER TABLE migrations ADD COLUMN IF NOT EXISTS checksum TEXT;
+	CREATE INDEX IF NOT EXISTS idx_migrations_source_sequence ON migrations(source, sequence);
 	`
 
 	_, err := m.db.Exec(createTableSQL)
@@ -105,13 +148,13 @@ func (m *MigrationManager) CreateMigration(migration *Migration) error {
 
 	// 삽입
 	query := `
-	INSERT INTO migrations (name, description, category, version, sql, script, type, status)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	INSERT INTO migrations (name, description, category, version, sql, script, down_sql, down_script, type, status)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	RETURNING id, created_at`
 
 	err = m.db.QueryRow(query,
 		migration.Name, migration.Description, migration.Category, migration.Version,
-		migration.SQL, migration.Script, migration.Type, migration.Status,
+		migration.SQL, migration.Script, migration.DownSQL, migration.DownScript, migration.Type, migration.Status,
 	).Scan(&migration.ID, &migration.CreatedAt)
 
 	if err != nil {
@@ -129,7 +172,9 @@ func (m *MigrationManager) GetMigrations(category string, status string, limit i
 	var conditions []string
 	argIdx := 1
 
-	query := "SELECT id, name, description, category, version, type, status, error, executed_at, created_at FROM migrations"
+	query := `SELECT id, name, COALESCE(description, ''), category, version, type, status, COALESCE(error, ''),
+		executed_at, created_at, COALESCE(org_id, ''), result,
+		COALESCE(source, ''), COALESCE(sequence, 0), COALESCE(checksum, '') FROM migrations`
 
 	if category != "" {
 		conditions = append(conditions, fmt.Sprintf("category = $%d", argIdx))
@@ -166,7 +211,8 @@ func (m *MigrationManager) GetMigrations(category string, status string, limit i
 			&migration.ID, &migration.Name, &migration.Description,
 			&migration.Category, &migration.Version, &migration.Type,
 			&migration.Status, &migration.Error, &migration.ExecutedAt,
-			&migration.CreatedAt,
+			&migration.CreatedAt, &migration.OrgID, &migration.Result,
+			&migration.Source, &migration.Sequence, &migration.Checksum,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("마이그레이션 스캔 실패: %v", err)
@@ -180,16 +226,23 @@ func (m *MigrationManager) GetMigrations(category string, status string, limit i
 // GetMigrationByID는 ID로 마이그레이션을 조회합니다
 func (m *MigrationManager) GetMigrationByID(id int) (*Migration, error) {
 	var migration Migration
+	var plan []byte
 
 	query := `
-	SELECT id, name, description, category, version, sql, script, type, status, error, executed_at, created_at 
+	SELECT id, name, COALESCE(description, ''), category, version, COALESCE(sql, ''), COALESCE(script, ''),
+		COALESCE(down_sql, ''), COALESCE(down_script, ''),
+		type, status, COALESCE(error, ''), executed_at, created_at, COALESCE(org_id, ''), plan, result,
+		COALESCE(source, ''), COALESCE(sequence, 0), COALESCE(checksum, '')
 	FROM migrations WHERE id = $1`
 
 	err := m.db.QueryRow(query, id).Scan(
 		&migration.ID, &migration.Name, &migration.Description,
 		&migration.Category, &migration.Version, &migration.SQL,
-		&migration.Script, &migration.Type, &migration.Status,
+		&migration.Script, &migration.DownSQL, &migration.DownScript,
+		&migration.Type, &migration.Status,
 		&migration.Error, &migration.ExecutedAt, &migration.CreatedAt,
+		&migration.OrgID, &plan, &migration.Result,
+		&migration.Source, &migration.Sequence, &migration.Checksum,
 	)
 
 	if err != nil {
@@ -198,6 +251,11 @@ func (m *MigrationManager) GetMigrationByID(id int) (*Migration, error) {
 		}
 		return nil, fmt.Errorf("마이그레이션 조회 실패: %v", err)
 	}
+	if len(plan) > 0 {
+		if err := json.Unmarshal(plan, &migration.Plan); err != nil {
+			return nil, fmt.Errorf("마이그레이션 계획 해석 실패: %v", err)
+		}
+	}
 
 	return &migration, nil
 }
@@ -219,7 +277,25 @@ func (m *MigrationManager) ExecuteMigration(id int) (*MigrationResult, error) {
 	// 상태 확인
 	if migration.Status == "completed" {
 		result.Error = "이미 완료된 마이그레이션입니다"
-		return result, fmt.Errorf(result.Error)
+		return result, errors.New(result.Error)
+	}
+
+	// 카테고리 데이터 마이그레이션은 배치마다 트랜잭션을 나눠 실행
+	if migration.Type == categoryMigrationType {
+		if err := m.claimMigration(id); err != nil {
+			result.Error = err.Error()
+			return result, err
+		}
+		progress, err := m.executeCategoryMigration(migration)
+		result.Duration = time.Since(startTime)
+		result.Changes = progress.Migrated
+		result.Details["progress"] = progress
+		if err != nil {
+			result.Error = err.Error()
+			return result, err
+		}
+		result.Success = true
+		return result, nil
 	}
 
 	// 실행 중 상태로 변경
@@ -257,7 +333,7 @@ func (m *MigrationManager) ExecuteMigration(id int) (*MigrationResult, error) {
 		result = m.executeScriptMigration(tx, migration)
 	default:
 		result.Error = fmt.Sprintf("지원하지 않는 마이그레이션 타입: %s", migration.Type)
-		return result, fmt.Errorf(result.Error)
+		return result, errors.New(result.Error)
 	}
 
 	return result, nil
@@ -0,0 +1,161 @@
+package migration
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// ErrNothingToRollback는 롤백할 완료된 마이그레이션이 없을 때 반환됩니다
+var ErrNothingToRollback = errors.New("롤백할 완료된 마이그레이션이 없습니다")
+
+// rollbackable는 롤백할 수 있는 상태인지 확인합니다.
+// 카테고리 마이그레이션은 일부 행만 옮기고 실패한 경우에도 되돌릴 수 있습니다.
+func rollbackable(migration *Migration) error {
+	switch {
+	case migration.Status == "completed":
+	case migration.Status == "failed" && migration.Type == categoryMigrationType:
+	default:
+		return fmt.Errorf("완료된 마이그레이션만 롤백할 수 있습니다 (현재: %s)", migration.Status)
+	}
+	if migration.Type == "sql" && strings.TrimSpace(migration.DownSQL) == "" {
+		return fmt.Errorf("롤백 SQL(down_sql)이 없는 마이그레이션입니다: %s", migration.Name)
+	}
+	if migration.Type == "script" && migration.DownScript == "" {
+		return fmt.Errorf("롤백 스크립트(down_script)가 없는 마이그레이션입니다: %s", migration.Name)
+	}
+	return nil
+}
+
+// RollbackMigration은 마이그레이션을 되돌립니다.
+// 되돌리는 작업과 상태 변경은 한 트랜잭션에서 실행되며, 마이그레이션 행을 잠가 동시에 실행되지 않게 합니다.
+// 실패하면 데이터와 상태 모두 그대로 남습니다.
+func (m *MigrationManager) RollbackMigration(id int) (*MigrationResult, error) {
+	startTime := time.Now()
+	result := &MigrationResult{Details: make(map[string]interface{})}
+
+	migration, err := m.GetMigrationByID(id)
+	if err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
+	if err := rollbackable(migration); err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		result.Error = fmt.Sprintf("트랜잭션 시작 실패: %v", err)
+		return result, errors.New(result.Error)
+	}
+	defer tx.Rollback()
+
+	// 잠근 뒤 상태를 다시 확인 (다른 요청이 먼저 롤백했을 수 있음)
+	var status string
+	if err := tx.QueryRow("SELECT status FROM migrations WHERE id = $1 FOR UPDATE", id).Scan(&status); err != nil {
+		result.Error = fmt.Sprintf("마이그레이션 잠금 실패: %v", err)
+		return result, errors.New(result.Error)
+	}
+	if status != migration.Status {
+		result.Error = fmt.Sprintf("마이그레이션 상태가 바뀌었습니다 (현재: %s)", status)
+		return result, errors.New(result.Error)
+	}
+
+	switch migration.Type {
+	case "sql":
+		down := &Migration{SQL: migration.DownSQL}
+		*result = *m.executeSQLMigration(tx, down)
+	case categoryMigrationType:
+		result, err = m.rollbackCategoryMigration(tx, migration)
+		if err != nil {
+			result.Error = err.Error()
+		}
+	default:
+		result.Error = "JavaScript 마이그레이션 롤백은 현재 지원되지 않습니다"
+	}
+	if !result.Success {
+		return result, errors.New(result.Error)
+	}
+
+	if _, err := tx.Exec(
+		"UPDATE migrations SET status = 'rollback', error = '', rolled_back_at = NOW() WHERE id = $1", id,
+	); err != nil {
+		result.Success = false
+		result.Error = fmt.Sprintf("상태 업데이트 실패: %v", err)
+		return result, errors.New(result.Error)
+	}
+	if err := tx.Commit(); err != nil {
+		result.Success = false
+		result.Error = fmt.Sprintf("커밋 실패: %v", err)
+		return result, errors.New(result.Error)
+	}
+
+	result.Duration = time.Since(startTime)
+	log.Printf("마이그레이션 롤백됨: %s (ID: %d, %d행)", migration.Name, id, result.Changes)
+	return result, nil
+}
+
+// RollbackLatest는 가장 최근에 완료된 마이그레이션을 되돌립니다. orgID가 비어 있지 않으면 그 조직의 것만 봅니다.
+func (m *MigrationManager) RollbackLatest(orgID string) (*Migration, *MigrationResult, error) {
+	var id int
+	err := m.db.QueryRow(
+		`SELECT id FROM migrations
+		 WHERE status = 'completed' AND ($1 = '' OR org_id = $1)
+		 ORDER BY executed_at DESC NULLS LAST, id DESC LIMIT 1`,
+		orgID,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil, ErrNothingToRollback
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("마이그레이션 조회 실패: %v", err)
+	}
+
+	result, err := m.RollbackMigration(id)
+	migration, getErr := m.GetMigrationByID(id)
+	if getErr != nil && err == nil {
+		err = getErr
+	}
+	return migration, result, err
+}
+
+// rollbackCategoryMigration은 백업해 둔 행을 이전 스키마 버전과 데이터로 복원합니다.
+// 마이그레이션 뒤에 다시 쓰인 행은 새 데이터를 잃지 않도록 건너뛰고 결과에 개수를 남깁니다.
+func (m *MigrationManager) rollbackCategoryMigration(tx *sql.Tx, migration *Migration) (*MigrationResult, error) {
+	result := &MigrationResult{Details: make(map[string]interface{})}
+	p := migration.Plan
+	if p == nil {
+		return result, fmt.Errorf("마이그레이션에 계획이 없습니다")
+	}
+
+	res, err := tx.Exec(
+		`UPDATE target_categories tc
+		 SET category_data = b.category_data, schema_version = b.schema_version, updated_at = now()
+		 FROM migration_row_backups b
+		 WHERE b.migration_id = $1
+		   AND tc.target_id = b.target_id AND tc.category_name = b.category_name
+		   AND tc.schema_version = $2 AND tc.updated_at <= b.migrated_at`,
+		migration.ID, p.ToVersion,
+	)
+	if err != nil {
+		return result, fmt.Errorf("행 복원 실패: %v", err)
+	}
+	restored, _ := res.RowsAffected()
+
+	res, err = tx.Exec("DELETE FROM migration_row_backups WHERE migration_id = $1", migration.ID)
+	if err != nil {
+		return result, fmt.Errorf("백업 정리 실패: %v", err)
+	}
+	backedUp, _ := res.RowsAffected()
+
+	result.Success = true
+	result.Changes = int(restored)
+	result.Details["migration_type"] = "category"
+	result.Details["restored"] = restored
+	result.Details["skipped"] = backedUp - restored // 마이그레이션 뒤에 바뀌었거나 삭제된 행
+	return result, nil
+}
@@ -0,0 +1,25 @@
+package migration
+
+import "testing"
+
+func TestRollbackable(t *testing.T) {
+	tests := []struct {
+		name      string
+		migration Migration
+		ok        bool
+	}{
+		{"completed sql with down", Migration{Type: "sql", Status: "completed", DownSQL: "DROP TABLE x"}, true},
+		{"completed sql without down", Migration{Type: "sql", Status: "completed", DownSQL: "  "}, false},
+		{"pending sql", Migration{Type: "sql", Status: "pending", DownSQL: "DROP TABLE x"}, false},
+		{"failed sql", Migration{Type: "sql", Status: "failed", DownSQL: "DROP TABLE x"}, false},
+		{"completed category", Migration{Type: categoryMigrationType, Status: "completed"}, true},
+		{"partially failed category", Migration{Type: categoryMigrationType, Status: "failed"}, true},
+		{"rolled back category", Migration{Type: categoryMigrationType, Status: "rollback"}, false},
+		{"script without down", Migration{Type: "script", Status: "completed"}, false},
+	}
+	for _, tt := range tests {
+		if err := rollbackable(&tt.migration); (err == nil) != tt.ok {
+			t.Errorf("%s: rollbackable = %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package oidc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 역할 우선순위 (여러 역할이 매핑되면 가장 높은 역할)
+var rolePriority = map[string]int{"viewer": 1, "editor": 2, "admin": 3}
+
+// ValidRole tmiDB 사용자 역할인지 확인합니다
+func ValidRole(role string) bool {
+	return rolePriority[role] > 0
+}
+
+// Mapping ID 토큰 클레임을 tmiDB 사용자, 조직, 역할로 바꾸는 규칙
+type Mapping struct {
+	UsernameClaim string            // 비어 있으면 preferred_username, email, sub 순서
+	OrgClaim      string            // 조직 이름 또는 ID가 들어 있는 클레임 (비어 있으면 DefaultOrg만 사용)
+	DefaultOrg    string            // 클레임이 없을 때의 조직 이름 또는 ID
+	RoleClaim     string            // 그룹이나 역할 목록 클레임 (문자열 또는 배열)
+	RoleMap       map[string]string // 클레임 값 → admin, editor, viewer
+	DefaultRole   string            // 매핑되는 값이 없을 때의 역할 (비어 있으면 로그인 거부)
+}
+
+// Identity 클레임에서 읽은 SSO 사용자
+type Identity struct {
+	Subject  string
+	Username string
+	Org      string
+	Role     string
+}
+
+// Resolve 클레임을 사용자, 조직, 역할로 바꿉니다
+func (m Mapping) Resolve(claims map[string]interface{}) (*Identity, error) {
+	id := &Identity{}
+	id.Subject, _ = claims["sub"].(string)
+	if id.Subject == "" {
+		return nil, errors.New("id token has no sub claim")
+	}
+
+	usernameClaims := []string{"preferred_username", "email", "sub"}
+	if m.UsernameClaim != "" {
+		usernameClaims = []string{m.UsernameClaim}
+	}
+	for _, claim := range usernameClaims {
+		if value, _ := claims[claim].(string); strings.TrimSpace(value) != "" {
+			id.Username = strings.TrimSpace(value)
+			break
+		}
+	}
+	if id.Username == "" {
+		return nil, fmt.Errorf("id token has no %s claim", strings.Join(usernameClaims, " or "))
+	}
+
+	if m.OrgClaim != "" {
+		if values := claimStrings(claims[m.OrgClaim]); len(values) > 0 {
+			id.Org = values[0]
+		}
+	}
+	if id.Org == "" {
+		id.Org = m.DefaultOrg
+	}
+	if id.Org == "" {
+		return nil, fmt.Errorf("id token has no %q claim and no default organization is set", m.OrgClaim)
+	}
+
+	if m.RoleClaim != "" {
+		for _, value := range claimStrings(claims[m.RoleClaim]) {
+			if role, ok := m.RoleMap[value]; ok && rolePriority[role] > rolePriority[id.Role] {
+				id.Role = role
+			}
+		}
+	}
+	if id.Role == "" {
+		id.Role = m.DefaultRole
+	}
+	if id.Role == "" {
+		return nil, fmt.Errorf("no role mapped from the %q claim", m.RoleClaim)
+	}
+	return id, nil
+}
+
+// claimStrings 문자열 또는 문자열 배열 클레임을 목록으로 바꿉니다
+func claimStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	case []interface{}:
+		var out []string
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+// Package oidc는 웹 콘솔 SSO를 위한 OpenID Connect 클라이언트입니다.
+// 인가 코드 흐름(PKCE 포함)과 ID 토큰 서명/클레임 검증만 구현합니다.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidToken ID 토큰 서명이나 클레임이 올바르지 않음
+var ErrInvalidToken = errors.New("invalid id token")
+
+// Config OIDC 공급자와 클라이언트 설정
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// discovery /.well-known/openid-configuration 문서 중 사용하는 항목
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider 디스커버리 문서를 읽은 OIDC 공급자
+type Provider struct {
+	config   Config
+	endpoint discovery
+	client   *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]interface{} // kid → *rsa.PublicKey 또는 *ecdsa.PublicKey
+	keysFetch time.Time
+}
+
+// NewProvider 공급자의 디스커버리 문서를 읽습니다.
+// 문서의 issuer가 설정한 IssuerURL과 다르면 오류를 반환합니다.
+func NewProvider(ctx context.Context, cfg Config) (*Provider, error) {
+	p := &Provider{
+		config: cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+
+	wellKnown := strings.TrimRight(cfg.IssuerURL, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(ctx, wellKnown, &p.endpoint); err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+	if strings.TrimRight(p.endpoint.Issuer, "/") != strings.TrimRight(cfg.IssuerURL, "/") {
+		return nil, fmt.Errorf("oidc discovery failed: issuer %q does not match %q", p.endpoint.Issuer, cfg.IssuerURL)
+	}
+	if p.endpoint.AuthorizationEndpoint == "" || p.endpoint.TokenEndpoint == "" || p.endpoint.JWKSURI == "" {
+		return nil, errors.New("oidc discovery failed: authorization, token or jwks endpoint missing")
+	}
+	return p, nil
+}
+
+// AuthCodeURL 사용자를 보낼 공급자의 로그인 URL (redirectURL이 비어 있으면 설정값 사용)
+func (p *Provider) AuthCodeURL(redirectURL, state, nonce, verifier string) string {
+	scopes := p.config.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid"}
+	}
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.redirectURL(redirectURL)},
+		"scope":                 {strings.Join(scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+	sep := "?"
+	if strings.Contains(p.endpoint.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return p.endpoint.AuthorizationEndpoint + sep + params.Encode()
+}
+
+// Exchange 인가 코드를 토큰으로 바꾸고 ID 토큰을 검증해 클레임을 반환합니다
+func (p *Provider) Exchange(ctx context.Context, redirectURL, code, verifier, nonce string) (map[string]interface{}, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.redirectURL(redirectURL)},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oidc token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var token struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&token); err != nil {
+		return nil, fmt.Errorf("oidc token response (%s): %w", resp.Status, err)
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("oidc token request failed: %s %s", token.Error, token.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc token request failed: %s", resp.Status)
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("oidc token response has no id_token")
+	}
+	return p.Verify(ctx, token.IDToken, nonce)
+}
+
+// redirectURL 요청마다 정한 콜백 주소, 없으면 설정값
+func (p *Provider) redirectURL(override string) string {
+	if p.config.RedirectURL != "" {
+		return p.config.RedirectURL
+	}
+	return override
+}
+
+// getJSON url의 JSON 응답을 v로 읽습니다
+func (p *Provider) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+// NewState state, nonce, PKCE verifier로 쓰는 임의 문자열
+func NewState() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// codeChallenge PKCE S256 challenge
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,246 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testIssuer 디스커버리, JWKS, 토큰 엔드포인트를 제공하는 테스트 공급자
+type testIssuer struct {
+	server  *httptest.Server
+	rsaKey  *rsa.PrivateKey
+	ecKey   *ecdsa.PrivateKey
+	idToken string
+	form    url.Values
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ti := &testIssuer{rsaKey: rsaKey, ecKey: ecKey}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 ti.server.URL,
+			"authorization_endpoint": ti.server.URL + "/authorize",
+			"token_endpoint":         ti.server.URL + "/token",
+			"jwks_uri":               ti.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		b64 := base64.RawURLEncoding.EncodeToString
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa1", "use": "sig", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+			{"kty": "EC", "kid": "ec1", "crv": "P-256", "x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+		}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		ti.form = r.PostForm
+		if user, pass, _ := r.BasicAuth(); user != "tmidb" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": ti.idToken, "token_type": "Bearer"})
+	})
+	ti.server = httptest.NewServer(mux)
+	t.Cleanup(ti.server.Close)
+	return ti
+}
+
+// sign 헤더와 클레임으로 서명된 JWT를 만듭니다
+func (ti *testIssuer) sign(t *testing.T, alg, kid string, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(input))
+
+	var sig []byte
+	switch alg {
+	case "RS256":
+		var err error
+		if sig, err = rsa.SignPKCS1v15(rand.Reader, ti.rsaKey, crypto.SHA256, digest[:]); err != nil {
+			t.Fatal(err)
+		}
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, ti.ecKey, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func (ti *testIssuer) claims(overrides map[string]interface{}) map[string]interface{} {
+	claims := map[string]interface{}{
+		"iss":                ti.server.URL,
+		"aud":                "tmidb",
+		"sub":                "user-123",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"nonce":              "n-0S6",
+		"preferred_username": "alice",
+	}
+	for k, v := range overrides {
+		if v == nil {
+			delete(claims, k)
+			continue
+		}
+		claims[k] = v
+	}
+	return claims
+}
+
+func TestProviderExchange(t *testing.T) {
+	ti := newTestIssuer(t)
+	p, err := NewProvider(context.Background(), Config{IssuerURL: ti.server.URL, ClientID: "tmidb", ClientSecret: "secret",
+		Scopes: []string{"openid", "email"}})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+
+	authURL, err := url.Parse(p.AuthCodeURL("https://tmidb.example/auth/oidc/callback", "st", "n-0S6", "verifier"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := authURL.Query()
+	if authURL.Path != "/authorize" || q.Get("state") != "st" || q.Get("scope") != "openid email" ||
+		q.Get("code_challenge") != codeChallenge("verifier") || q.Get("code_challenge_method") != "S256" {
+		t.Errorf("unexpected authorization URL %s", authURL)
+	}
+
+	ti.idToken = ti.sign(t, "RS256", "rsa1", ti.claims(nil))
+	claims, err := p.Exchange(context.Background(), "https://tmidb.example/auth/oidc/callback", "code-1", "verifier", "n-0S6")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if claims["preferred_username"] != "alice" {
+		t.Errorf("claims = %v", claims)
+	}
+	if ti.form.Get("code") != "code-1" || ti.form.Get("code_verifier") != "verifier" ||
+		ti.form.Get("redirect_uri") != "https://tmidb.example/auth/oidc/callback" {
+		t.Errorf("token request form = %v", ti.form)
+	}
+}
+
+func TestProviderRejectsIssuerMismatch(t *testing.T) {
+	ti := newTestIssuer(t)
+	if _, err := NewProvider(context.Background(), Config{IssuerURL: ti.server.URL + "/other"}); err == nil {
+		t.Fatal("expected discovery to fail for a different issuer")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	ti := newTestIssuer(t)
+	p, err := NewProvider(context.Background(), Config{IssuerURL: ti.server.URL, ClientID: "tmidb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	forged := ti.sign(t, "RS256", "rsa1", ti.claims(nil))
+	parts := strings.Split(forged, ".")
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	badSig, _ := rsa.SignPKCS1v15(rand.Reader, other, crypto.SHA256, digest[:])
+	forged = parts[0] + "." + parts[1] + "." + base64.RawURLEncoding.EncodeToString(badSig)
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"rsa1"}`)) + "." + parts[1] + "."
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"rsa", ti.sign(t, "RS256", "rsa1", ti.claims(nil)), false},
+		{"ecdsa", ti.sign(t, "ES256", "ec1", ti.claims(nil)), false},
+		{"audience list", ti.sign(t, "RS256", "rsa1", ti.claims(map[string]interface{}{"aud": []string{"other", "tmidb"}})), false},
+		{"forged signature", forged, true},
+		{"alg none", unsigned, true},
+		{"unknown kid", ti.sign(t, "RS256", "rsa2", ti.claims(nil)), true},
+		{"alg does not match key", ti.sign(t, "ES256", "rsa1", ti.claims(nil)), true},
+		{"wrong issuer", ti.sign(t, "RS256", "rsa1", ti.claims(map[string]interface{}{"iss": "https://evil.example"})), true},
+		{"wrong audience", ti.sign(t, "RS256", "rsa1", ti.claims(map[string]interface{}{"aud": "other"})), true},
+		{"expired", ti.sign(t, "RS256", "rsa1", ti.claims(map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()})), true},
+		{"wrong nonce", ti.sign(t, "RS256", "rsa1", ti.claims(map[string]interface{}{"nonce": "replayed"})), true},
+		{"missing sub", ti.sign(t, "RS256", "rsa1", ti.claims(map[string]interface{}{"sub": nil})), true},
+		{"malformed", "abc.def", true},
+	}
+	for _, tt := range tests {
+		_, err := p.Verify(context.Background(), tt.token, "n-0S6")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Verify() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: error %v is not ErrInvalidToken", tt.name, err)
+		}
+	}
+}
+
+func TestMappingResolve(t *testing.T) {
+	m := Mapping{
+		OrgClaim:    "org",
+		DefaultOrg:  "Acme",
+		RoleClaim:   "groups",
+		RoleMap:     map[string]string{"tmidb-admins": "admin", "tmidb-editors": "editor"},
+		DefaultRole: "viewer",
+	}
+
+	id, err := m.Resolve(map[string]interface{}{
+		"sub": "u1", "preferred_username": "alice", "org": "Globex",
+		"groups": []interface{}{"tmidb-editors", "tmidb-admins", "staff"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Username != "alice" || id.Org != "Globex" || id.Role != "admin" || id.Subject != "u1" {
+		t.Errorf("Resolve() = %+v", id)
+	}
+
+	id, err = m.Resolve(map[string]interface{}{"sub": "u2", "email": "bob@example.com", "groups": "staff"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Username != "bob@example.com" || id.Org != "Acme" || id.Role != "viewer" {
+		t.Errorf("Resolve() with defaults = %+v", id)
+	}
+
+	strict := m
+	strict.DefaultRole = ""
+	if _, err := strict.Resolve(map[string]interface{}{"sub": "u3", "preferred_username": "carol"}); err == nil {
+		t.Error("expected an error when no role is mapped and there is no default role")
+	}
+
+	noOrg := m
+	noOrg.DefaultOrg = ""
+	if _, err := noOrg.Resolve(map[string]interface{}{"sub": "u4", "preferred_username": "dave"}); err == nil {
+		t.Error("expected an error when there is no organization")
+	}
+
+	if _, err := m.Resolve(map[string]interface{}{"preferred_username": "erin"}); err == nil {
+		t.Error("expected an error without sub")
+	}
+}
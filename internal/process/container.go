@@ -0,0 +1,438 @@
+package process
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+const (
+	defaultContainerRuntime     = "docker"
+	defaultContainerStopTimeout = 10 // 초
+	containerNamePrefix         = "tmidb-"
+
+	// 컨테이너에 붙이는 레이블 (설정이 바뀌었는지 판단)
+	containerProcessLabel = "tmidb.process"
+	containerSpecLabel    = "tmidb.spec"
+
+	// 런타임 명령 (inspect, rm, run) 제한 시간. 이미지를 받아야 하는 run은 더 길게 기다림
+	containerCommandTimeout = 30 * time.Second
+	containerRunTimeout     = 10 * time.Minute
+)
+
+var containerNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// ContainerConfig 호스트 바이너리 대신 컨테이너로 실행할 때의 이미지/런타임 설정.
+// 컨테이너는 런타임 CLI(docker, podman, containerd의 nerdctl)로 만들고 관리합니다
+type ContainerConfig struct {
+	Runtime     string            `json:"runtime,omitempty"` // docker (기본), podman, nerdctl
+	Image       string            `json:"image"`
+	Name        string            `json:"name,omitempty"` // 컨테이너 이름 (기본 tmidb-<프로세스 이름>)
+	Args        []string          `json:"args,omitempty"` // 이미지 엔트리포인트에 넘길 인자
+	Env         map[string]string `json:"env,omitempty"`
+	Ports       []string          `json:"ports,omitempty"`   // "5432:5432"
+	Volumes     []string          `json:"volumes,omitempty"` // "/data/postgresql:/var/lib/postgresql/data"
+	Network     string            `json:"network,omitempty"` // 비어 있으면 런타임 기본 네트워크
+	User        string            `json:"user,omitempty"`
+	StopTimeout int               `json:"stop_timeout,omitempty"` // 정지 요청 후 강제 종료까지 기다리는 초 (0이면 10)
+}
+
+// Validate 설정 검증
+func (c ContainerConfig) Validate() error {
+	if c.Image == "" {
+		return errors.New("container requires an image")
+	}
+	switch c.Runtime {
+	case "", "docker", "podman", "nerdctl":
+	default:
+		return fmt.Errorf("unknown container runtime: %q", c.Runtime)
+	}
+	if c.Name != "" && !containerNamePattern.MatchString(c.Name) {
+		return fmt.Errorf("invalid container name: %q", c.Name)
+	}
+	if c.StopTimeout < 0 {
+		return errors.New("container stop_timeout must not be negative")
+	}
+	return nil
+}
+
+// withDefaults 비어 있는 값에 기본값 적용
+func (c ContainerConfig) withDefaults(process string) ContainerConfig {
+	if c.Runtime == "" {
+		c.Runtime = defaultContainerRuntime
+	}
+	if c.Name == "" {
+		c.Name = containerNamePrefix + process
+	}
+	if c.StopTimeout <= 0 {
+		c.StopTimeout = defaultContainerStopTimeout
+	}
+	return c
+}
+
+// runArgs 컨테이너를 만드는 run 명령 인자. 환경 변수는 값 없이 이름만 넘겨
+// 런타임 CLI의 환경에서 읽게 하므로 시크릿이 명령줄(ps)에 드러나지 않습니다
+func (c ContainerConfig) runArgs(process string, env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	args := []string{"run", "--detach", "--name", c.Name,
+		"--label", containerProcessLabel + "=" + process,
+		"--label", containerSpecLabel + "=" + c.spec(process, env),
+	}
+	for _, k := range keys {
+		args = append(args, "--env", k)
+	}
+	for _, p := range c.Ports {
+		args = append(args, "--publish", p)
+	}
+	for _, v := range c.Volumes {
+		args = append(args, "--volume", v)
+	}
+	if c.Network != "" {
+		args = append(args, "--network", c.Network)
+	}
+	if c.User != "" {
+		args = append(args, "--user", c.User)
+	}
+	args = append(args, c.Image)
+	return append(args, c.Args...)
+}
+
+// spec 컨테이너를 만든 설정의 해시. 실행 중인 컨테이너의 레이블과 다르면 다시 만듭니다
+func (c ContainerConfig) spec(process string, env map[string]string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%s\x00", process, c.Image, c.Name, c.Network, c.User)
+	for _, list := range [][]string{c.Args, c.Ports, c.Volumes} {
+		fmt.Fprintf(h, "%q\x00", list)
+	}
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%s\x00", k, env[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// containerStatus inspect로 조회한 컨테이너 상태
+type containerStatus struct {
+	Exists    bool
+	Running   bool
+	PID       int // 호스트에서 본 컨테이너 init 프로세스 PID
+	ExitCode  int
+	StartedAt time.Time
+	Spec      string
+	Health    string // 이미지에 HEALTHCHECK가 없으면 비어 있음
+}
+
+// containerInspectFormat parseContainerInspect가 읽는 inspect 출력 형식
+const containerInspectFormat = `{{.State.Running}}|{{.State.Pid}}|{{.State.ExitCode}}|{{.State.StartedAt}}|{{index .Config.Labels "` +
+	containerSpecLabel + `"}}|{{if .State.Health}}{{.State.Health.Status}}{{end}}`
+
+// parseContainerInspect containerInspectFormat 출력 해석
+func parseContainerInspect(out string) (containerStatus, error) {
+	fields := strings.Split(strings.TrimSpace(out), "|")
+	if len(fields) != 6 {
+		return containerStatus{}, fmt.Errorf("unexpected inspect output %q", out)
+	}
+
+	status := containerStatus{Exists: true, Spec: fields[4], Health: fields[5]}
+	running, err := strconv.ParseBool(fields[0])
+	if err != nil {
+		return containerStatus{}, fmt.Errorf("unexpected inspect output %q", out)
+	}
+	status.Running = running
+	if status.PID, err = strconv.Atoi(fields[1]); err != nil {
+		return containerStatus{}, fmt.Errorf("unexpected inspect output %q", out)
+	}
+	if status.ExitCode, err = strconv.Atoi(fields[2]); err != nil {
+		return containerStatus{}, fmt.Errorf("unexpected inspect output %q", out)
+	}
+	// 시작한 적 없으면 0001-01-01T00:00:00Z
+	status.StartedAt, _ = time.Parse(time.RFC3339Nano, fields[3])
+	return status, nil
+}
+
+// runtimeCommand 컨테이너 런타임 명령 실행 (실패하면 출력을 오류에 포함)
+func runtimeCommand(ctx context.Context, c ContainerConfig, env []string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, c.Runtime, args...)
+	cmd.Env = env
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("%s %s: %w: %s", c.Runtime, args[0], err, message)
+		}
+		return "", fmt.Errorf("%s %s: %w", c.Runtime, args[0], err)
+	}
+	return string(out), nil
+}
+
+// inspectContainer 컨테이너 상태 조회 (없으면 Exists가 false)
+func inspectContainer(ctx context.Context, c ContainerConfig) (containerStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, containerCommandTimeout)
+	defer cancel()
+
+	out, err := runtimeCommand(ctx, c, nil, "inspect", "--type", "container", "--format", containerInspectFormat, c.Name)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "no such") {
+			return containerStatus{}, nil
+		}
+		return containerStatus{}, err
+	}
+	return parseContainerInspect(out)
+}
+
+// containerEnv 런타임 CLI에 넘길 환경 (run의 --env 이름이 여기서 값을 읽음)
+func containerEnv(env map[string]string) []string {
+	result := os.Environ()
+	for k, v := range env {
+		result = append(result, k+"="+v)
+	}
+	return result
+}
+
+// startContainer 컨테이너 프로세스 시작 (StartProcess에서 호출, 상태는 starting).
+// 같은 설정으로 이미 실행 중인 컨테이너는 다시 만들지 않고 이어받으며,
+// 멈췄거나 설정이 바뀐 컨테이너는 지우고 새로 만듭니다 (데이터는 볼륨에 남음)
+func (m *Manager) startContainer(process *Process) error {
+	process.mutex.RLock()
+	name := process.Name
+	c := process.container.withDefaults(name)
+	env := make(map[string]string, len(process.Env)+len(c.Env))
+	for k, v := range process.Env {
+		env[k] = v
+	}
+	for k, v := range c.Env {
+		env[k] = v
+	}
+	process.mutex.RUnlock()
+
+	fail := func(err error) error {
+		process.mutex.Lock()
+		process.State = StateError
+		process.LastError = err.Error()
+		process.mutex.Unlock()
+		return err
+	}
+
+	status, err := inspectContainer(m.ctx, c)
+	if err != nil {
+		return fail(fmt.Errorf("failed to inspect container %s: %w", c.Name, err))
+	}
+
+	spec := c.spec(name, env)
+	adopted := status.Running && status.Spec == spec
+	if !adopted {
+		if status.Exists {
+			if status.Running {
+				log.Printf("♻️ Container %s runs an outdated configuration, recreating it", c.Name)
+			}
+			ctx, cancel := context.WithTimeout(m.ctx, containerCommandTimeout+time.Duration(c.StopTimeout)*time.Second)
+			_, err := runtimeCommand(ctx, c, nil, "rm", "--force", c.Name)
+			cancel()
+			if err != nil {
+				return fail(fmt.Errorf("failed to remove container %s: %w", c.Name, err))
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(m.ctx, containerRunTimeout)
+		_, err := runtimeCommand(ctx, c, containerEnv(env), c.runArgs(name, env)...)
+		cancel()
+		if err != nil {
+			return fail(fmt.Errorf("failed to run container %s: %w", c.Name, err))
+		}
+
+		if status, err = inspectContainer(m.ctx, c); err != nil {
+			return fail(fmt.Errorf("failed to inspect container %s: %w", c.Name, err))
+		}
+		if !status.Running {
+			return fail(fmt.Errorf("container %s exited right after start (exit code %d)", c.Name, status.ExitCode))
+		}
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	startTime := status.StartedAt
+	if startTime.IsZero() {
+		startTime = time.Now()
+	}
+
+	process.mutex.Lock()
+	process.PID = status.PID
+	process.StartTime = startTime
+	process.State = StateRunning
+	process.LastError = ""
+	process.procSnapshot = nil
+	process.cmd = nil
+	process.cancel = cancel
+	process.mutex.Unlock()
+
+	if adopted {
+		log.Printf("🔗 Adopted container %s for %s (PID: %d)", c.Name, name, status.PID)
+	} else {
+		log.Printf("🐳 Container started: %s (%s, PID: %d)", c.Name, c.Image, status.PID)
+	}
+	m.emitEvent(ipc.EventProcessStarted, name, "", map[string]interface{}{
+		"pid":       status.PID,
+		"container": c.Name,
+		"image":     c.Image,
+		"adopted":   adopted,
+	})
+
+	// 이어받은 컨테이너는 이전 출력을 다시 기록하지 않음
+	since := ""
+	if adopted {
+		since = time.Now().UTC().Format(time.RFC3339)
+	}
+	go m.followContainerLogs(ctx, process, c, status.PID, since)
+	go m.watchContainer(ctx, process, c)
+	return nil
+}
+
+// stopContainer 컨테이너 정지 (StopTimeout 동안 기다린 뒤 런타임이 강제 종료)
+func (m *Manager) stopContainer(c ContainerConfig) error {
+	ctx, cancel := context.WithTimeout(context.Background(), containerCommandTimeout+time.Duration(c.StopTimeout)*time.Second)
+	defer cancel()
+	_, err := runtimeCommand(ctx, c, nil, "stop", "--time", strconv.Itoa(c.StopTimeout), c.Name)
+	return err
+}
+
+// followContainerLogs 런타임의 logs 명령으로 컨테이너 출력을 캡처 (ctx가 취소되면 끝남)
+func (m *Manager) followContainerLogs(ctx context.Context, process *Process, c ContainerConfig, pid int, since string) {
+	args := []string{"logs", "--follow"}
+	if since != "" {
+		args = append(args, "--since", since)
+	}
+	cmd := exec.CommandContext(ctx, c.Runtime, append(args, c.Name)...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		log.Printf("⚠️ Failed to capture logs of container %s: %v", c.Name, err)
+		return
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		log.Printf("⚠️ Failed to capture logs of container %s: %v", c.Name, err)
+		return
+	}
+	if err := cmd.Start(); err != nil {
+		if ctx.Err() == nil {
+			log.Printf("⚠️ Failed to capture logs of container %s: %v", c.Name, err)
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		m.captureOutput(process, pid, stdout, "stdout")
+	}()
+	go func() {
+		defer wg.Done()
+		m.captureOutput(process, pid, stderr, "stderr")
+	}()
+	wg.Wait()
+	cmd.Wait()
+}
+
+// watchContainer 컨테이너가 끝날 때까지 기다리고, 예상치 못한 종료면 자동 재시작
+func (m *Manager) watchContainer(ctx context.Context, process *Process, c ContainerConfig) {
+	exitCode := -1
+	for {
+		out, err := exec.CommandContext(ctx, c.Runtime, "wait", c.Name).Output()
+		if ctx.Err() != nil {
+			// 정지했거나 슈퍼바이저가 끝나는 중 (컨테이너는 계속 실행됨)
+			return
+		}
+		if err == nil {
+			if code, err := strconv.Atoi(strings.TrimSpace(string(out))); err == nil {
+				exitCode = code
+			}
+			break
+		}
+
+		// 런타임에 연결할 수 없으면 컨테이너가 실제로 끝났는지 확인 후 다시 기다림
+		status, ierr := inspectContainer(ctx, c)
+		if ierr == nil && !status.Running {
+			if status.Exists {
+				exitCode = status.ExitCode
+			}
+			break
+		}
+		log.Printf("⚠️ Failed to wait for container %s: %v", c.Name, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+
+	process.mutex.Lock()
+	defer process.mutex.Unlock()
+
+	if process.State != StateRunning {
+		// StopProcess가 정지한 경우
+		return
+	}
+	if process.cancel != nil {
+		process.cancel()
+	}
+
+	process.State = StateError
+	process.LastError = fmt.Sprintf("container %s exited with code %d", c.Name, exitCode)
+	log.Printf("❌ Container %s of %s exited unexpectedly (exit code %d)", c.Name, process.Name, exitCode)
+	m.emitEvent(ipc.EventProcessCrashed, process.Name, process.LastError, map[string]interface{}{
+		"exit_code": exitCode,
+		"container": c.Name,
+	})
+
+	if m.crashHandler != nil {
+		go m.crashHandler(process.crashSnapshot(exitCode, "", false))
+	}
+	process.PID = 0
+
+	if process.AutoRestart {
+		m.scheduleAutoRestart(process)
+	}
+}
+
+// containerHealth 런타임이 보고하는 컨테이너 헬스 상태 (이미지의 HEALTHCHECK).
+// HEALTHCHECK가 없는 이미지는 실행 중이면 정상으로 봅니다
+func containerHealth(ctx context.Context, c ContainerConfig) (string, error) {
+	status, err := inspectContainer(ctx, c)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case !status.Exists:
+		return "", fmt.Errorf("container %s does not exist", c.Name)
+	case !status.Running:
+		return "", fmt.Errorf("container %s is not running (exit code %d)", c.Name, status.ExitCode)
+	case status.Health == "unhealthy":
+		return "", fmt.Errorf("container %s is unhealthy", c.Name)
+	case status.Health == "":
+		return "running", nil
+	}
+	return status.Health, nil
+}
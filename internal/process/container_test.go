@@ -0,0 +1,217 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRuntime docker 대신 쓰는 셸 스크립트. 컨테이너 하나의 상태를 파일에 기록하고 호출을 남김
+func fakeRuntime(t *testing.T) (path string, calls func() []string, setState func(string)) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake runtime is a shell script")
+	}
+	dir := t.TempDir()
+	state := filepath.Join(dir, "state")
+	script := `#!/bin/sh
+state="` + state + `"
+cmd=$1
+shift
+echo "$cmd $*" >> "` + filepath.Join(dir, "calls") + `"
+case $cmd in
+inspect)
+	if [ -f "$state" ]; then cat "$state"; else echo "Error: No such container: tmidb-nats" >&2; exit 1; fi ;;
+run)
+	spec=""
+	for arg in "$@"; do case $arg in tmidb.spec=*) spec=${arg#tmidb.spec=} ;; esac; done
+	echo "true|4242|0|2026-10-16T10:00:00.5Z|$spec|healthy" > "$state" ;;
+rm)
+	rm -f "$state" ;;
+stop)
+	spec=$(cut -d'|' -f5 "$state")
+	echo "false|0|143|2026-10-16T10:00:00.5Z|$spec|" > "$state" ;;
+wait)
+	while grep -q '^true' "$state" 2>/dev/null; do sleep 0.05; done
+	cut -d'|' -f3 "$state" ;;
+logs)
+	echo "container says hello"
+	echo "container warns" >&2
+	case " $* " in *" --follow "*) exec sleep 30 ;; esac ;;
+esac
+`
+	path = filepath.Join(dir, "fake-docker")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	calls = func() []string {
+		data, _ := os.ReadFile(filepath.Join(dir, "calls"))
+		var names []string
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			if name, _, _ := strings.Cut(line, " "); name != "" && name != "logs" && name != "wait" {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	setState = func(content string) {
+		if err := os.WriteFile(state, []byte(content+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path, calls, setState
+}
+
+// containerManager 컨테이너 프로세스 하나를 등록한 관리자
+func containerManager(t *testing.T, rt string, env map[string]string) (*Manager, *Process) {
+	t.Helper()
+	m := NewManager(nil, nil)
+	t.Cleanup(m.cancel)
+
+	config := &ContainerConfig{Image: "nats:2", Ports: []string{"4222:4222"}, Env: env}
+	if err := m.RegisterProcess(&ProcessConfig{Name: "nats", Type: TypeContainer, Container: config}); err != nil {
+		t.Fatal(err)
+	}
+	// Validate는 알려진 런타임 이름만 허용하므로 등록 후 바꿈
+	config.Runtime = rt
+	return m, m.processes["nats"]
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if cond() {
+			return
+		}
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func TestContainerStartAdoptAndStop(t *testing.T) {
+	rt, calls, _ := fakeRuntime(t)
+
+	m, process := containerManager(t, rt, map[string]string{"NATS_TOKEN": "secret"})
+	if err := m.StartProcess("nats"); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := m.GetProcessStatus("nats")
+	if info.Status != string(StateRunning) || info.PID != 4242 || info.Type != "container" {
+		t.Fatalf("status = %+v", info)
+	}
+	if got := strings.Join(calls(), ","); got != "inspect,run,inspect" {
+		t.Errorf("calls = %s", got)
+	}
+
+	// 런타임 로그가 출력 버퍼로 들어옴
+	waitFor(t, "container logs", func() bool {
+		lines, _, _ := process.output.Snapshot("", 0)
+		return len(lines) == 2
+	})
+
+	// 같은 설정이면 새 관리자가 실행 중인 컨테이너를 이어받음
+	m2, _ := containerManager(t, rt, map[string]string{"NATS_TOKEN": "secret"})
+	if err := m2.StartProcess("nats"); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(calls(), ","); got != "inspect,run,inspect,inspect" {
+		t.Errorf("calls after adopt = %s", got)
+	}
+	m2.cancel()
+
+	// 설정이 바뀌면 지우고 다시 만듦
+	m3, _ := containerManager(t, rt, map[string]string{"NATS_TOKEN": "rotated"})
+	if err := m3.StartProcess("nats"); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(calls(), ","); got != "inspect,run,inspect,inspect,inspect,rm,run,inspect" {
+		t.Errorf("calls after config change = %s", got)
+	}
+
+	if err := m3.StopProcess("nats"); err != nil {
+		t.Fatal(err)
+	}
+	info, _ = m3.GetProcessStatus("nats")
+	if info.Status != string(StateStopped) {
+		t.Errorf("status after stop = %s", info.Status)
+	}
+	if got := calls(); got[len(got)-1] != "stop" {
+		t.Errorf("last call = %s, want stop", got[len(got)-1])
+	}
+}
+
+func TestContainerExitIsReported(t *testing.T) {
+	rt, _, setState := fakeRuntime(t)
+
+	m, process := containerManager(t, rt, nil)
+	if err := m.StartProcess("nats"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 런타임 밖에서 컨테이너가 끝남
+	setState("false|0|3|2026-10-16T10:00:00.5Z||")
+	waitFor(t, "crash", func() bool {
+		process.mutex.RLock()
+		defer process.mutex.RUnlock()
+		return process.State == StateError
+	})
+
+	process.mutex.RLock()
+	defer process.mutex.RUnlock()
+	if !strings.Contains(process.LastError, "exited with code 3") || process.PID != 0 {
+		t.Errorf("last error = %q, pid = %d", process.LastError, process.PID)
+	}
+}
+
+func TestContainerRunArgsHideEnvValues(t *testing.T) {
+	c := ContainerConfig{Image: "postgres:16", Volumes: []string{"/data/pg:/var/lib/postgresql/data"}, Args: []string{"-c", "fsync=on"}}.withDefaults("postgresql")
+	env := map[string]string{"POSTGRES_PASSWORD": "secret", "PGDATA": "/var/lib/postgresql/data"}
+
+	args := strings.Join(c.runArgs("postgresql", env), " ")
+	if strings.Contains(args, "secret") {
+		t.Errorf("run args leak an env value: %s", args)
+	}
+	for _, want := range []string{"--name tmidb-postgresql", "--env PGDATA --env POSTGRES_PASSWORD", "--volume /data/pg:/var/lib/postgresql/data", "postgres:16 -c fsync=on"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("run args %q missing %q", args, want)
+		}
+	}
+
+	if c.spec("postgresql", env) == c.spec("postgresql", map[string]string{"POSTGRES_PASSWORD": "other"}) {
+		t.Error("spec does not change with env")
+	}
+}
+
+func TestParseContainerInspect(t *testing.T) {
+	status, err := parseContainerInspect("true|123|0|2026-10-16T10:00:00.123456789Z|abc|starting\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Exists || !status.Running || status.PID != 123 || status.Spec != "abc" || status.Health != "starting" || status.StartedAt.IsZero() {
+		t.Errorf("status = %+v", status)
+	}
+
+	for _, out := range []string{"", "true|1|0", "yes|1|0|t||", "true|x|0|t||"} {
+		if _, err := parseContainerInspect(out); err == nil {
+			t.Errorf("%q: expected error", out)
+		}
+	}
+}
+
+func TestContainerConfigValidate(t *testing.T) {
+	valid := []ContainerConfig{{Image: "nats:2"}, {Image: "nats:2", Runtime: "podman", Name: "my_nats.1"}}
+	for _, c := range valid {
+		if err := c.Validate(); err != nil {
+			t.Errorf("%+v: %v", c, err)
+		}
+	}
+	invalid := []ContainerConfig{{}, {Image: "nats:2", Runtime: "lxc"}, {Image: "nats:2", Name: "-bad"}, {Image: "nats:2", StopTimeout: -1}}
+	for _, c := range invalid {
+		if err := c.Validate(); err == nil {
+			t.Errorf("%+v: expected error", c)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package process
+
+import (
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// procSnapshotInterval 실행 중인 프로세스의 /proc 상태를 기록하는 주기.
+// 종료된 프로세스의 /proc 항목은 사라지므로 크래시 덤프에는 마지막 기록을 사용한다.
+const procSnapshotInterval = 5 * time.Second
+
+// ProcSnapshot 마지막으로 기록한 /proc/<pid> 상태
+type ProcSnapshot struct {
+	Status  string    `json:"status"` // /proc/<pid>/status
+	Limits  string    `json:"limits"` // /proc/<pid>/limits
+	TakenAt time.Time `json:"taken_at"`
+}
+
+// CrashSnapshot 비정상 종료 순간 프로세스 관리자가 알고 있던 정보
+type CrashSnapshot struct {
+	Name       string            `json:"name"`
+	Command    string            `json:"command"`
+	Args       []string          `json:"args"`
+	WorkDir    string            `json:"work_dir,omitempty"`
+	Env        map[string]string `json:"env,omitempty"` // 설정된 환경 변수 (상속된 것은 제외)
+	PID        int               `json:"pid"`
+	StartTime  time.Time         `json:"start_time"`
+	ExitTime   time.Time         `json:"exit_time"`
+	ExitCode   int               `json:"exit_code"`
+	Signal     string            `json:"signal,omitempty"`
+	CoreDumped bool              `json:"core_dumped"`
+	Error      string            `json:"error,omitempty"`
+	Restarts   int               `json:"restarts"`
+	Proc       *ProcSnapshot     `json:"proc,omitempty"`
+	Output     []ipc.OutputLine  `json:"-"`
+}
+
+// SetCrashHandler 비정상 종료 콜백 설정. 콜백은 별도 고루틴에서 호출된다.
+func (m *Manager) SetCrashHandler(handler func(snapshot *CrashSnapshot)) {
+	m.crashHandler = handler
+}
+
+// snapshotProcesses 실행 중인 프로세스의 /proc 상태 기록
+func (m *Manager) snapshotProcesses() {
+	m.processesMux.RLock()
+	defer m.processesMux.RUnlock()
+
+	for _, process := range m.processes {
+		process.mutex.RLock()
+		pid := process.PID
+		running := process.State == StateRunning
+		process.mutex.RUnlock()
+		if !running || pid == 0 {
+			continue
+		}
+
+		snapshot := readProcSnapshot(pid)
+		if snapshot == nil {
+			continue
+		}
+		process.mutex.Lock()
+		// 기록하는 동안 재시작되었으면 버림
+		if process.PID == pid {
+			process.procSnapshot = snapshot
+		}
+		process.mutex.Unlock()
+	}
+}
+
+// crashSnapshot 종료된 프로세스 정보 수집 (process.mutex를 잡은 상태에서 호출)
+func (process *Process) crashSnapshot(exitCode int, signal string, coreDumped bool) *CrashSnapshot {
+	env := make(map[string]string, len(process.Env))
+	for k, v := range process.Env {
+		env[k] = v
+	}
+	lines, _, _ := process.output.Snapshot("", 0)
+
+	return &CrashSnapshot{
+		Name:       process.Name,
+		Command:    process.Command,
+		Args:       append([]string(nil), process.Args...),
+		WorkDir:    process.WorkDir,
+		Env:        env,
+		PID:        process.PID,
+		StartTime:  process.StartTime,
+		ExitTime:   time.Now(),
+		ExitCode:   exitCode,
+		Signal:     signal,
+		CoreDumped: coreDumped,
+		Error:      process.LastError,
+		Restarts:   process.RestartCount,
+		Proc:       process.procSnapshot,
+		Output:     lines,
+	}
+}
@@ -0,0 +1,32 @@
+//go:build linux
+
+package process
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// readProcSnapshot /proc/<pid>/status와 limits 읽기
+func readProcSnapshot(pid int) *ProcSnapshot {
+	status, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return nil
+	}
+	limits, _ := os.ReadFile(fmt.Sprintf("/proc/%d/limits", pid))
+	return &ProcSnapshot{Status: string(status), Limits: string(limits), TakenAt: time.Now()}
+}
+
+// exitSignal 프로세스를 끝낸 시그널과 코어 덤프 여부
+func exitSignal(state *os.ProcessState) (string, bool) {
+	if state == nil {
+		return "", false
+	}
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return "", false
+	}
+	return status.Signal().String(), status.CoreDump()
+}
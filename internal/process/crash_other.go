@@ -0,0 +1,15 @@
+//go:build !linux
+
+package process
+
+import "os"
+
+// readProcSnapshot 리눅스 외 플랫폼에는 /proc이 없음
+func readProcSnapshot(pid int) *ProcSnapshot {
+	return nil
+}
+
+// exitSignal 리눅스 외 플랫폼에서는 시그널 정보를 제공하지 않음
+func exitSignal(state *os.ProcessState) (string, bool) {
+	return "", false
+}
@@ -0,0 +1,333 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/logger"
+)
+
+// ProbeType 헬스 체크 방식
+type ProbeType string
+
+const (
+	ProbeTCP  ProbeType = "tcp"  // 포트 연결 확인
+	ProbeHTTP ProbeType = "http" // HTTP 상태 코드 확인 (2xx, 3xx 정상)
+	ProbeExec ProbeType = "exec" // 명령 종료 코드 확인 (0 정상)
+
+	// 컨테이너 런타임이 보고하는 상태 확인 (이미지의 HEALTHCHECK, 없으면 실행 중이면 정상)
+	ProbeContainer ProbeType = "container"
+)
+
+// ProbeAction 실패 임계치 도달 시 동작
+type ProbeAction string
+
+const (
+	ActionRestart ProbeAction = "restart" // 프로세스 재시작
+	ActionAlert   ProbeAction = "alert"   // 알림 핸들러 호출
+	ActionDegrade ProbeAction = "degrade" // degraded로 표시만 함
+)
+
+const (
+	defaultProbeInterval  = 10 * time.Second
+	defaultProbeTimeout   = 3 * time.Second
+	defaultProbeThreshold = 3
+	maxProbeMessageLength = 256
+)
+
+// HealthCheck 프로세스 헬스 체크 설정
+type HealthCheck struct {
+	Type             ProbeType     `json:"type"`
+	Address          string        `json:"address,omitempty"` // tcp: host:port
+	URL              string        `json:"url,omitempty"`     // http
+	Command          []string      `json:"command,omitempty"` // exec
+	Interval         time.Duration `json:"interval"`
+	Timeout          time.Duration `json:"timeout"`
+	FailureThreshold int           `json:"failure_threshold"`
+	Action           ProbeAction   `json:"action"`
+}
+
+// healthCheckJSON interval/timeout을 "10s" 형식 문자열로 직렬화
+type healthCheckJSON struct {
+	*healthCheckAlias
+	Interval string `json:"interval,omitempty"`
+	Timeout  string `json:"timeout,omitempty"`
+}
+
+type healthCheckAlias HealthCheck
+
+// MarshalJSON encodes durations as strings
+func (hc HealthCheck) MarshalJSON() ([]byte, error) {
+	aux := healthCheckJSON{healthCheckAlias: (*healthCheckAlias)(&hc)}
+	if hc.Interval > 0 {
+		aux.Interval = hc.Interval.String()
+	}
+	if hc.Timeout > 0 {
+		aux.Timeout = hc.Timeout.String()
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON decodes durations from strings
+func (hc *HealthCheck) UnmarshalJSON(data []byte) error {
+	aux := healthCheckJSON{healthCheckAlias: (*healthCheckAlias)(hc)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var err error
+	if aux.Interval != "" {
+		if hc.Interval, err = time.ParseDuration(aux.Interval); err != nil {
+			return fmt.Errorf("invalid health check interval: %w", err)
+		}
+	}
+	if aux.Timeout != "" {
+		if hc.Timeout, err = time.ParseDuration(aux.Timeout); err != nil {
+			return fmt.Errorf("invalid health check timeout: %w", err)
+		}
+	}
+	return nil
+}
+
+// withDefaults 비어 있는 값에 기본값 적용
+func (hc HealthCheck) withDefaults() HealthCheck {
+	if hc.Interval <= 0 {
+		hc.Interval = defaultProbeInterval
+	}
+	if hc.Timeout <= 0 {
+		hc.Timeout = defaultProbeTimeout
+	}
+	if hc.FailureThreshold <= 0 {
+		hc.FailureThreshold = defaultProbeThreshold
+	}
+	if hc.Action == "" {
+		hc.Action = ActionDegrade
+	}
+	return hc
+}
+
+// Validate 설정 검증
+func (hc HealthCheck) Validate() error {
+	switch hc.Type {
+	case ProbeTCP:
+		if hc.Address == "" {
+			return errors.New("tcp health check requires an address")
+		}
+	case ProbeHTTP:
+		if hc.URL == "" {
+			return errors.New("http health check requires a url")
+		}
+	case ProbeExec:
+		if len(hc.Command) == 0 {
+			return errors.New("exec health check requires a command")
+		}
+	case ProbeContainer:
+	default:
+		return fmt.Errorf("unknown health check type: %q", hc.Type)
+	}
+
+	switch hc.Action {
+	case "", ActionRestart, ActionAlert, ActionDegrade:
+		return nil
+	default:
+		return fmt.Errorf("unknown health check action: %q", hc.Action)
+	}
+}
+
+// SetHealthAlertHandler 헬스 체크 알림 콜백 설정
+func (m *Manager) SetHealthAlertHandler(handler func(name string, result ipc.ProbeResult)) {
+	m.healthAlertHandler = handler
+}
+
+// SetEventHandler 프로세스 라이프사이클 이벤트 콜백 설정 (블로킹하지 않아야 함)
+func (m *Manager) SetEventHandler(handler func(event ipc.Event)) {
+	m.eventHandler = handler
+}
+
+// emitEvent 이벤트 콜백 호출
+func (m *Manager) emitEvent(eventType ipc.EventType, name, message string, data map[string]interface{}) {
+	if m.eventHandler == nil {
+		return
+	}
+	m.eventHandler(ipc.Event{
+		Type:      eventType,
+		Component: name,
+		Message:   message,
+		Data:      data,
+		Timestamp: time.Now(),
+	})
+}
+
+// runHealthCheck 프로세스가 실행 중일 때 주기적으로 probe 수행
+func (m *Manager) runHealthCheck(process *Process, hc HealthCheck) {
+	ticker := time.NewTicker(hc.Interval)
+	defer ticker.Stop()
+
+	client := &http.Client{Timeout: hc.Timeout}
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		process.mutex.RLock()
+		running := process.State == StateRunning
+		container := process.container
+		name := process.Name
+		process.mutex.RUnlock()
+
+		if !running {
+			// 정지/재시작 중에는 결과를 초기화하여 다음 시작 시 새로 집계
+			process.mutex.Lock()
+			process.health = nil
+			process.mutex.Unlock()
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(m.ctx, hc.Timeout)
+		start := time.Now()
+		var message string
+		var err error
+		if hc.Type == ProbeContainer {
+			message, err = containerHealth(ctx, container.withDefaults(name))
+		} else {
+			message, err = probe(ctx, client, hc)
+		}
+		latency := time.Since(start)
+		cancel()
+
+		m.recordProbe(process, hc, message, err, latency)
+	}
+}
+
+// recordProbe 결과 기록 및 임계치 도달 시 동작 수행
+func (m *Manager) recordProbe(process *Process, hc HealthCheck, message string, err error, latency time.Duration) {
+	process.mutex.Lock()
+
+	result := ipc.ProbeResult{
+		Type:      string(hc.Type),
+		Healthy:   err == nil,
+		Message:   message,
+		Latency:   latency,
+		CheckedAt: time.Now(),
+	}
+	if err != nil {
+		result.Message = truncateProbeMessage(err.Error())
+		if process.health != nil {
+			result.Failures = process.health.Failures
+			result.Degraded = process.health.Degraded
+		}
+		result.Failures++
+	}
+
+	// 연속 실패가 임계치에 도달한 순간 한 번만 동작
+	triggered := result.Failures == hc.FailureThreshold
+	if triggered && hc.Action == ActionDegrade {
+		result.Degraded = true
+	}
+
+	if err == nil && process.health != nil && !process.health.Healthy {
+		log.Printf("💚 Health check for %s recovered", process.Name)
+	}
+
+	process.health = &result
+	name := process.Name
+	process.mutex.Unlock()
+
+	if !triggered {
+		return
+	}
+
+	log.Printf("💔 Health check for %s failed %d times: %s (action: %s)", name, result.Failures, result.Message, hc.Action)
+	m.emitEvent(ipc.EventHealthDegraded, name, result.Message, map[string]interface{}{
+		"probe":    result.Type,
+		"failures": result.Failures,
+		"action":   string(hc.Action),
+	})
+	if m.logManager != nil {
+		m.logManager.WriteLog(name, logger.LogLevelError, fmt.Sprintf("health check failed %d times: %s", result.Failures, result.Message))
+	}
+
+	switch hc.Action {
+	case ActionRestart:
+		go func() {
+			if err := m.RestartProcess(name); err != nil {
+				log.Printf("⚠️ Health check restart of %s failed: %v", name, err)
+			}
+		}()
+	case ActionAlert:
+		if m.healthAlertHandler != nil {
+			m.healthAlertHandler(name, result)
+		}
+	}
+}
+
+// probe 단일 헬스 체크 수행
+func probe(ctx context.Context, client *http.Client, hc HealthCheck) (string, error) {
+	switch hc.Type {
+	case ProbeTCP:
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", hc.Address)
+		if err != nil {
+			return "", err
+		}
+		conn.Close()
+		return "connected to " + hc.Address, nil
+
+	case ProbeHTTP:
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, hc.URL, nil)
+		if err != nil {
+			return "", err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 400 {
+			return "", fmt.Errorf("%s returned %s", hc.URL, resp.Status)
+		}
+		return resp.Status, nil
+
+	case ProbeExec:
+		output, err := exec.CommandContext(ctx, hc.Command[0], hc.Command[1:]...).CombinedOutput()
+		message := truncateProbeMessage(strings.TrimSpace(string(output)))
+		if err != nil {
+			if message != "" {
+				return "", fmt.Errorf("%v: %s", err, message)
+			}
+			return "", err
+		}
+		return message, nil
+	}
+
+	return "", fmt.Errorf("unknown health check type: %q", hc.Type)
+}
+
+func truncateProbeMessage(message string) string {
+	runes := []rune(message)
+	if len(runes) <= maxProbeMessageLength {
+		return message
+	}
+	return string(runes[:maxProbeMessageLength]) + "..."
+}
+
+// healthInfo 마지막 probe 결과 복사본 (호출자가 mutex 보유)
+func (p *Process) healthInfo() *ipc.ProbeResult {
+	if p.health == nil {
+		return nil
+	}
+	result := *p.health
+	return &result
+}
@@ -0,0 +1,111 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+func TestRecordProbeThreshold(t *testing.T) {
+	m := &Manager{}
+	p := &Process{Name: "api", State: StateRunning}
+
+	var alerts []ipc.ProbeResult
+	m.SetHealthAlertHandler(func(name string, result ipc.ProbeResult) {
+		alerts = append(alerts, result)
+	})
+
+	hc := HealthCheck{Type: ProbeTCP, Address: "localhost:1", Action: ActionAlert}.withDefaults()
+	for i := 0; i < 5; i++ {
+		m.recordProbe(p, hc, "", errors.New("connection refused"), time.Millisecond)
+	}
+
+	if p.health.Failures != 5 || p.health.Healthy {
+		t.Errorf("health = %+v", p.health)
+	}
+	if len(alerts) != 1 || alerts[0].Failures != defaultProbeThreshold {
+		t.Errorf("alerts = %+v, want exactly one at threshold", alerts)
+	}
+
+	m.recordProbe(p, hc, "ok", nil, time.Millisecond)
+	if !p.health.Healthy || p.health.Failures != 0 {
+		t.Errorf("after recovery health = %+v", p.health)
+	}
+}
+
+func TestRecordProbeDegrade(t *testing.T) {
+	m := &Manager{}
+	p := &Process{Name: "data-consumer", State: StateRunning}
+	hc := HealthCheck{Type: ProbeExec, Command: []string{"false"}, FailureThreshold: 2}.withDefaults()
+
+	m.recordProbe(p, hc, "", errors.New("exit status 1"), 0)
+	if p.health.Degraded {
+		t.Fatal("degraded before reaching threshold")
+	}
+	m.recordProbe(p, hc, "", errors.New("exit status 1"), 0)
+	m.recordProbe(p, hc, "", errors.New("exit status 1"), 0)
+	if !p.health.Degraded {
+		t.Errorf("health = %+v, want degraded", p.health)
+	}
+}
+
+func TestProbeTypes(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go http.Serve(listener, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+
+	addr := listener.Addr().String()
+	client := &http.Client{Timeout: time.Second}
+	tests := []struct {
+		hc      HealthCheck
+		healthy bool
+	}{
+		{HealthCheck{Type: ProbeTCP, Address: addr}, true},
+		{HealthCheck{Type: ProbeHTTP, URL: "http://" + addr + "/health"}, true},
+		{HealthCheck{Type: ProbeHTTP, URL: "http://" + addr + "/down"}, false},
+		{HealthCheck{Type: ProbeExec, Command: []string{"true"}}, true},
+		{HealthCheck{Type: ProbeExec, Command: []string{"false"}}, false},
+	}
+
+	for _, tt := range tests {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		_, err := probe(ctx, client, tt.hc)
+		cancel()
+		if (err == nil) != tt.healthy {
+			t.Errorf("probe(%+v) err = %v, want healthy=%v", tt.hc, err, tt.healthy)
+		}
+	}
+}
+
+func TestHealthCheckJSON(t *testing.T) {
+	var hc HealthCheck
+	data := `{"type":"http","url":"http://localhost/health","interval":"15s","timeout":"2s","failure_threshold":4,"action":"restart"}`
+	if err := json.Unmarshal([]byte(data), &hc); err != nil {
+		t.Fatal(err)
+	}
+	if hc.Interval != 15*time.Second || hc.Timeout != 2*time.Second || hc.Action != ActionRestart {
+		t.Errorf("decoded = %+v", hc)
+	}
+
+	out, err := json.Marshal(hc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back HealthCheck
+	if err := json.Unmarshal(out, &back); err != nil || back.Interval != hc.Interval || back.Timeout != hc.Timeout {
+		t.Errorf("round trip %s -> %+v (%v)", out, back, err)
+	}
+}
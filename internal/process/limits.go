@@ -0,0 +1,35 @@
+package process
+
+import (
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+const (
+	EnforcementCgroup = "cgroup-v2" // cgroup v2 컨트롤러로 제한
+	EnforcementRlimit = "rlimit"    // setrlimit 기반 제한 (cgroup 미사용 시)
+	EnforcementNone   = "none"      // 제한 없음 또는 적용 실패
+)
+
+// hasLimits 자원 제한이 설정되어 있는지 확인
+func (p *Process) hasLimits() bool {
+	return p.CPUQuota > 0 || p.MemoryLimit > 0 || p.MaxOpenFiles > 0
+}
+
+// limitsInfo IPC로 노출할 자원 제한 정보 (호출자가 mutex 보유)
+func (p *Process) limitsInfo() *ipc.ResourceLimits {
+	if !p.hasLimits() {
+		return nil
+	}
+
+	enforcement := p.limitEnforcement
+	if enforcement == "" {
+		enforcement = EnforcementNone
+	}
+
+	return &ipc.ResourceLimits{
+		CPUQuota:     p.CPUQuota,
+		MemoryLimit:  p.MemoryLimit,
+		MaxOpenFiles: p.MaxOpenFiles,
+		Enforcement:  enforcement,
+	}
+}
@@ -0,0 +1,126 @@
+//go:build linux
+
+package process
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	cgroupRoot   = "/sys/fs/cgroup"
+	cgroupParent = "tmidb"
+	cpuPeriod    = 100000 // cpu.max 주기 (마이크로초)
+)
+
+// applyResourceLimits 시작된 프로세스에 자원 제한 적용
+// CPU/메모리는 cgroup v2를 우선 사용하고, 사용할 수 없으면 메모리만 RLIMIT_AS로 대체한다.
+// 열린 파일 수는 cgroup으로 제한할 수 없으므로 항상 RLIMIT_NOFILE을 사용한다.
+func applyResourceLimits(process *Process, pid int) (string, error) {
+	enforcement := EnforcementNone
+	var firstErr error
+
+	if process.CPUQuota > 0 || process.MemoryLimit > 0 {
+		path, err := setupCgroup(process.Name, process.CPUQuota, process.MemoryLimit, pid)
+		if err == nil {
+			process.cgroupPath = path
+			enforcement = EnforcementCgroup
+		} else {
+			log.Printf("⚠️ cgroup v2 unavailable for %s, falling back to rlimits: %v", process.Name, err)
+
+			if process.CPUQuota > 0 {
+				log.Printf("⚠️ CPU quota for %s cannot be enforced without cgroup v2", process.Name)
+			}
+			if process.MemoryLimit > 0 {
+				limit := uint64(process.MemoryLimit)
+				if err := unix.Prlimit(pid, unix.RLIMIT_AS, &unix.Rlimit{Cur: limit, Max: limit}, nil); err != nil {
+					firstErr = fmt.Errorf("failed to set memory rlimit: %w", err)
+				} else {
+					enforcement = EnforcementRlimit
+				}
+			}
+		}
+	}
+
+	if process.MaxOpenFiles > 0 {
+		limit := process.MaxOpenFiles
+		if err := unix.Prlimit(pid, unix.RLIMIT_NOFILE, &unix.Rlimit{Cur: limit, Max: limit}, nil); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to set open files rlimit: %w", err)
+			}
+		} else if enforcement == EnforcementNone {
+			enforcement = EnforcementRlimit
+		}
+	}
+
+	return enforcement, firstErr
+}
+
+// setupCgroup /sys/fs/cgroup/tmidb/<name> 생성 후 제한 설정 및 프로세스 이동
+func setupCgroup(name string, cpuQuota float64, memoryLimit int64, pid int) (string, error) {
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		return "", fmt.Errorf("cgroup v2 is not mounted at %s", cgroupRoot)
+	}
+
+	parent := filepath.Join(cgroupRoot, cgroupParent)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return "", err
+	}
+
+	// 하위 cgroup에서 cpu, memory 컨트롤러 사용 가능하도록 활성화
+	for _, dir := range []string{cgroupRoot, parent} {
+		if err := writeCgroupFile(dir, "cgroup.subtree_control", "+cpu +memory"); err != nil {
+			return "", err
+		}
+	}
+
+	path := filepath.Join(parent, name)
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return "", err
+	}
+
+	cpuMax := "max " + strconv.Itoa(cpuPeriod)
+	if cpuQuota > 0 {
+		// 커널이 허용하는 최소 quota는 1ms
+		cpuMax = fmt.Sprintf("%d %d", max(int64(cpuQuota*cpuPeriod), 1000), cpuPeriod)
+	}
+	if err := writeCgroupFile(path, "cpu.max", cpuMax); err != nil {
+		return "", err
+	}
+
+	memoryMax := "max"
+	if memoryLimit > 0 {
+		memoryMax = strconv.FormatInt(memoryLimit, 10)
+	}
+	if err := writeCgroupFile(path, "memory.max", memoryMax); err != nil {
+		return "", err
+	}
+
+	if err := writeCgroupFile(path, "cgroup.procs", strconv.Itoa(pid)); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+func writeCgroupFile(dir, file, value string) error {
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(value), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	return nil
+}
+
+// removeCgroup 프로세스 종료 후 cgroup 정리 (남은 프로세스가 있으면 실패하며 무시)
+func removeCgroup(path string) {
+	if path == "" {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.Printf("⚠️ Failed to remove cgroup %s: %v", path, err)
+	}
+}
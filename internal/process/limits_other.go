@@ -0,0 +1,17 @@
+//go:build !linux
+
+package process
+
+import (
+	"errors"
+)
+
+// applyResourceLimits 리눅스 외 플랫폼에서는 자원 제한을 지원하지 않음
+func applyResourceLimits(process *Process, pid int) (string, error) {
+	if !process.hasLimits() {
+		return EnforcementNone, nil
+	}
+	return EnforcementNone, errors.New("resource limits are only supported on linux")
+}
+
+func removeCgroup(path string) {}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/tmidb/tmidb-core/internal/ipc"
 	"github.com/tmidb/tmidb-core/internal/logger"
+	"github.com/tmidb/tmidb-core/internal/sysinfo"
 )
 
 // ProcessState 프로세스 상태
@@ -35,9 +36,10 @@ const (
 type ProcessType string
 
 const (
-	TypeInternal ProcessType = "internal" // 내부 Go 프로세스
-	TypeExternal ProcessType = "external" // 외부 바이너리
-	TypeService  ProcessType = "service"  // 시스템 서비스
+	TypeInternal  ProcessType = "internal"  // 내부 Go 프로세스
+	TypeExternal  ProcessType = "external"  // 외부 바이너리
+	TypeService   ProcessType = "service"   // 시스템 서비스
+	TypeContainer ProcessType = "container" // 컨테이너 런타임으로 실행하는 서비스
 )
 
 // Manager 프로세스 관리자
@@ -55,6 +57,20 @@ type Manager struct {
 	
 	// External service restart callback
 	externalServiceRestarter func(serviceName string) error
+
+	// Health check alert callback
+	healthAlertHandler func(name string, result ipc.ProbeResult)
+
+	// Lifecycle event callback
+	eventHandler func(event ipc.Event)
+
+	// Crash dump callback
+	crashHandler func(snapshot *CrashSnapshot)
+
+	// 상태 파일 (LoadState로 설정)과 이전 슈퍼바이저가 남긴 입양 대상 (processesMux로 보호)
+	statePath string
+	stateMux  sync.Mutex
+	adoptable map[string]PersistedProcess
 }
 
 // Process 프로세스 정보
@@ -72,7 +88,32 @@ type Process struct {
 	Uptime       time.Duration     `json:"uptime"`
 	RestartCount int               `json:"restart_count"`
 	AutoRestart  bool              `json:"auto_restart"`
-	MaxRestarts  int               `json:"max_restarts"`
+	MaxRestarts  int               `json:"max_restarts"` // RestartWindow 내 자동 재시작 허용 횟수
+
+	// 자동 재시작 백오프 상태
+	RestartWindow       time.Duration `json:"restart_window"` // 재시작 예산 윈도우
+	restartTimes        []time.Time
+	consecutiveFailures int
+	restartTimer        *time.Timer
+	nextRestart         *time.Time
+
+	// 자원 제한
+	CPUQuota         float64 `json:"cpu_quota"`
+	MemoryLimit      int64   `json:"memory_limit"`
+	MaxOpenFiles     uint64  `json:"max_open_files"`
+	limitEnforcement string
+	cgroupPath       string
+
+	// 헬스 체크
+	health *ipc.ProbeResult
+
+	// 컨테이너로 실행할 때의 설정 (nil이면 호스트 프로세스)
+	container *ContainerConfig
+
+	// 최근 stdout/stderr 출력
+	output *outputBuffer
+	// 마지막 /proc 상태 (크래시 덤프용)
+	procSnapshot *ProcSnapshot
 
 	// 프로세스 제어
 	cmd    *exec.Cmd
@@ -102,7 +143,24 @@ type ProcessConfig struct {
 	WorkDir     string            `json:"work_dir"`
 	Env         map[string]string `json:"env"`
 	AutoRestart bool              `json:"auto_restart"`
-	MaxRestarts int               `json:"max_restarts"`
+	MaxRestarts int               `json:"max_restarts"` // RestartWindow 내 자동 재시작 허용 횟수 (0이면 5)
+
+	// 재시작 예산 윈도우 (0이면 10분)
+	RestartWindow time.Duration `json:"restart_window"`
+
+	// 자원 제한 (0이면 제한 없음), 내부 컴포넌트 시작 시 적용
+	CPUQuota     float64 `json:"cpu_quota"`      // 사용 가능한 CPU 수 (1.5 = 150%)
+	MemoryLimit  int64   `json:"memory_limit"`   // 바이트
+	MaxOpenFiles uint64  `json:"max_open_files"` // 열린 파일 디스크립터 수
+
+	// 헬스 체크 (nil이면 사용 안 함)
+	HealthCheck *HealthCheck `json:"health_check,omitempty"`
+
+	// 메모리에 보관할 최근 출력 크기 (0이면 DefaultOutputBufferSize)
+	OutputBufferSize int `json:"output_buffer_size,omitempty"`
+
+	// 컨테이너로 실행 (nil이면 Command를 호스트에서 실행)
+	Container *ContainerConfig `json:"container,omitempty"`
 }
 
 // NewManager 새로운 프로세스 관리자 생성
@@ -156,6 +214,10 @@ func (m *Manager) Stop() error {
 		wg.Add(1)
 		go func(p *Process) {
 			defer wg.Done()
+			// 컨테이너는 attach한 외부 서비스처럼 계속 실행되며, 다음 슈퍼바이저가 이어받음
+			if p.container != nil {
+				return
+			}
 			m.StopProcess(p.Name)
 		}(proc)
 	}
@@ -187,18 +249,46 @@ func (m *Manager) RegisterProcess(config *ProcessConfig) error {
 		return fmt.Errorf("process %s already registered", config.Name)
 	}
 
+	if config.HealthCheck != nil {
+		if err := config.HealthCheck.Validate(); err != nil {
+			return fmt.Errorf("invalid health check for %s: %w", config.Name, err)
+		}
+		if config.HealthCheck.Type == ProbeContainer && config.Container == nil {
+			return fmt.Errorf("invalid health check for %s: container health check requires a container", config.Name)
+		}
+	}
+
+	command, args := config.Command, config.Args
+	if config.Container != nil {
+		if err := config.Container.Validate(); err != nil {
+			return fmt.Errorf("invalid container for %s: %w", config.Name, err)
+		}
+		// 목록과 크래시 덤프에는 이미지를 명령으로 표시
+		if command == "" {
+			command, args = config.Container.Image, config.Container.Args
+		}
+	}
+
 	process := &Process{
 		Name:         config.Name,
 		User:         config.User,
 		Type:         config.Type,
-		Command:      config.Command,
-		Args:         config.Args,
+		Command:      command,
+		Args:         args,
 		WorkDir:      config.WorkDir,
 		Env:          config.Env,
 		State:        StateStopped,
 		AutoRestart:  config.AutoRestart,
 		MaxRestarts:  config.MaxRestarts,
 		RestartCount: 0,
+		CPUQuota:     config.CPUQuota,
+		MemoryLimit:  config.MemoryLimit,
+		MaxOpenFiles: config.MaxOpenFiles,
+
+		RestartWindow: config.RestartWindow,
+
+		output:    newOutputBuffer(config.OutputBufferSize),
+		container: config.Container,
 	}
 
 	// Go 1.24 기능: 프로세스별 정리 함수 설정
@@ -215,6 +305,10 @@ func (m *Manager) RegisterProcess(config *ProcessConfig) error {
 
 	m.processes[config.Name] = process
 
+	if config.HealthCheck != nil {
+		go m.runHealthCheck(process, config.HealthCheck.withDefaults())
+	}
+
 	log.Printf("📋 Process registered: %s (%s)", config.Name, config.Type)
 	return nil
 }
@@ -235,9 +329,21 @@ func (m *Manager) StartProcess(name string) error {
 		process.mutex.Unlock()
 		return fmt.Errorf("process %s is already running or starting", name)
 	}
+	process.cancelPendingRestart()
 	process.State = StateStarting
 	process.mutex.Unlock()
 
+	// 이전 슈퍼바이저가 시작해 아직 실행 중이면 중복으로 띄우지 않고 이어받음
+	if prev, ok := m.takeAdoptable(name); ok && m.adopt(process, prev) {
+		m.saveState()
+		return nil
+	}
+
+	// 컨테이너는 런타임이 실행 (같은 설정으로 실행 중이면 이어받음)
+	if process.container != nil {
+		return m.startContainer(process)
+	}
+
 	// 프로세스 컨텍스트 생성
 	ctx, cancel := context.WithCancel(m.ctx)
 	process.cancel = cancel
@@ -293,19 +399,33 @@ func (m *Manager) StartProcess(name string) error {
 	}
 
 	process.PID = cmd.Process.Pid
+	process.procSnapshot = nil
 	process.StartTime = time.Now()
 	process.State = StateRunning
 	process.LastError = ""
 
 	log.Printf("🚀 Process started: %s (PID: %d)", name, process.PID)
+	m.emitEvent(ipc.EventProcessStarted, name, "", map[string]interface{}{"pid": process.PID})
+
+	// 자원 제한 적용
+	if process.hasLimits() {
+		enforcement, err := applyResourceLimits(process, process.PID)
+		if err != nil {
+			log.Printf("⚠️ Failed to apply resource limits to %s: %v", name, err)
+		}
+		process.limitEnforcement = enforcement
+		log.Printf("📏 Resource limits for %s: cpu=%.2f memory=%d nofile=%d (%s)",
+			name, process.CPUQuota, process.MemoryLimit, process.MaxOpenFiles, enforcement)
+	}
 
 	// 로그 캡처 고루틴 시작
-	go m.captureOutput(process, stdout, "stdout")
-	go m.captureOutput(process, stderr, "stderr")
+	go m.captureOutput(process, process.PID, stdout, "stdout")
+	go m.captureOutput(process, process.PID, stderr, "stderr")
 
 	// 프로세스 모니터링 고루틴 시작
 	go m.watchProcess(process)
 
+	m.saveState()
 	return nil
 }
 
@@ -321,8 +441,13 @@ func (m *Manager) StopProcess(name string) error {
 
 	// 뮤텍스 사용 최소화
 	process.mutex.Lock()
+	pending := process.cancelPendingRestart()
 	if process.State != StateRunning {
 		process.mutex.Unlock()
+		if pending {
+			log.Printf("🛑 Cancelled pending auto-restart of %s", name)
+			return nil
+		}
 		return fmt.Errorf("process %s is not running", name)
 	}
 
@@ -331,12 +456,19 @@ func (m *Manager) StopProcess(name string) error {
 	process.State = StateStopping
 	cmd := process.cmd
 	cancel := process.cancel
+	container := process.container
 	process.mutex.Unlock()
 
-	// 내부 프로세스의 경우 PID 기반으로 직접 종료
-	if processType == TypeInternal && currentPID > 0 {
+	if container != nil {
+		// 컨테이너는 런타임을 통해 정지 (StopTimeout 뒤 강제 종료)
+		c := container.withDefaults(name)
+		if err := m.stopContainer(c); err != nil {
+			log.Printf("⚠️ Failed to stop container %s of %s: %v", c.Name, name, err)
+		}
+	} else if processType == TypeInternal && currentPID > 0 {
+		// 내부 프로세스의 경우 PID 기반으로 직접 종료
 		// 직접 SIGTERM 전송
-		if err := syscall.Kill(currentPID, syscall.SIGTERM); err != nil {
+		if err := sysinfo.Signal(currentPID, syscall.SIGTERM); err != nil {
 			log.Printf("⚠️ Failed to send SIGTERM to %s (PID: %d): %v", name, currentPID, err)
 		}
 
@@ -351,7 +483,7 @@ func (m *Manager) StopProcess(name string) error {
 		// 여전히 실행 중이면 강제 종료
 		if m.isProcessRunning(currentPID) {
 			log.Printf("🔨 Force killing process %s (PID: %d)", name, currentPID)
-			syscall.Kill(currentPID, syscall.SIGKILL)
+			sysinfo.Signal(currentPID, syscall.SIGKILL)
 			time.Sleep(1 * time.Second)
 		}
 	} else {
@@ -395,6 +527,8 @@ func (m *Manager) StopProcess(name string) error {
 	process.mutex.Unlock()
 
 	log.Printf("🛑 Process stopped: %s", name)
+	m.emitEvent(ipc.EventProcessStopped, name, "", nil)
+	m.saveState()
 	return nil
 }
 
@@ -455,14 +589,14 @@ func (m *Manager) RestartProcess(name string) error {
 	// 내부 프로세스의 경우 PID 기반으로 직접 종료
 	if processType == TypeInternal && currentState == StateRunning && currentPID > 0 {
 		// 직접 SIGTERM 전송
-		if err := syscall.Kill(currentPID, syscall.SIGTERM); err != nil {
+		if err := sysinfo.Signal(currentPID, syscall.SIGTERM); err != nil {
 			log.Printf("⚠️ Failed to send SIGTERM to %s (PID: %d): %v", name, currentPID, err)
 		} else {
 			// 3초 대기 후 강제 종료
 			time.Sleep(3 * time.Second)
 			if m.isProcessRunning(currentPID) {
 				log.Printf("🔨 Force killing process %s (PID: %d)", name, currentPID)
-				syscall.Kill(currentPID, syscall.SIGKILL)
+				sysinfo.Signal(currentPID, syscall.SIGKILL)
 			}
 		}
 
@@ -493,6 +627,22 @@ func (m *Manager) RestartProcess(name string) error {
 	return m.StartProcess(name)
 }
 
+// SetProcessEnv 프로세스 환경 변수 교체 (다음 시작부터 적용)
+func (m *Manager) SetProcessEnv(name string, env map[string]string) error {
+	m.processesMux.RLock()
+	process, exists := m.processes[name]
+	m.processesMux.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("process %s not found", name)
+	}
+
+	process.mutex.Lock()
+	process.Env = env
+	process.mutex.Unlock()
+	return nil
+}
+
 // AttachProcess 기존에 실행 중인 프로세스에 attach
 func (m *Manager) AttachProcess(name string, pid int) error {
 	m.processesMux.RLock()
@@ -541,9 +691,7 @@ func (m *Manager) isProcessRunning(pid int) bool {
 		return false
 	}
 
-	// Check if /proc/[pid] exists
-	_, err := os.Stat(fmt.Sprintf("/proc/%d", pid))
-	return err == nil
+	return sysinfo.ProcessRunning(pid)
 }
 
 // watchAttachedProcess monitors an attached process
@@ -559,36 +707,24 @@ func (m *Manager) watchAttachedProcess(process *Process) {
 			process.mutex.RLock()
 			pid := process.PID
 			name := process.Name
-			autoRestart := process.AutoRestart
-			maxRestarts := process.MaxRestarts
-			restartCount := process.RestartCount
 			process.mutex.RUnlock()
 
 			// Check if process is still running
 			if !m.isProcessRunning(pid) {
+				log.Printf("❌ Attached process %s (PID: %d) exited unexpectedly", name, pid)
+
 				process.mutex.Lock()
 				process.State = StateError
 				process.LastError = "Process exited unexpectedly"
 				process.PID = 0
-				process.mutex.Unlock()
-
-				log.Printf("❌ Attached process %s (PID: %d) exited unexpectedly", name, pid)
-
-				// Auto-restart if enabled
-				if autoRestart && restartCount < maxRestarts {
-					log.Printf("🔄 Auto-restarting attached process: %s (attempt %d/%d)",
-						name, restartCount+1, maxRestarts)
+				m.emitEvent(ipc.EventProcessCrashed, name, process.LastError, map[string]interface{}{"pid": pid})
 
-					process.mutex.Lock()
-					process.RestartCount++
-					process.mutex.Unlock()
-
-					// Wait a moment before restarting
-					go func() {
-						time.Sleep(5 * time.Second)
-						m.RestartProcess(name)
-					}()
+				// Auto-restart with backoff if enabled
+				if process.AutoRestart {
+					m.scheduleAutoRestart(process)
 				}
+				process.mutex.Unlock()
+				m.saveState()
 				return
 			}
 		}
@@ -616,6 +752,10 @@ func (m *Manager) GetProcessList() []ipc.ProcessInfo {
 		memoryUsage := proc.MemoryUsage
 		cpuUsage := proc.CPUUsage
 		autoRestart := proc.AutoRestart
+		restartCount := proc.RestartCount
+		limits := proc.limitsInfo()
+		health := proc.healthInfo()
+		nextRestart := proc.nextRestart
 		proc.mutex.RUnlock()
 
 		uptime := time.Duration(0)
@@ -624,16 +764,20 @@ func (m *Manager) GetProcessList() []ipc.ProcessInfo {
 		}
 
 		processInfo := ipc.ProcessInfo{
-			Name:      name,
-			Type:      ptype,
-			Status:    state,
-			PID:       pid,
-			Uptime:    uptime,
-			Memory:    memoryUsage,
-			CPU:       cpuUsage,
-			Enabled:   autoRestart,
-			Logs:      true, // 로그는 항상 활성화
-			StartTime: startTime,
+			Name:        name,
+			Type:        ptype,
+			Status:      state,
+			PID:         pid,
+			Uptime:      uptime,
+			Memory:      memoryUsage,
+			CPU:         cpuUsage,
+			Enabled:     autoRestart,
+			Logs:        true, // 로그는 항상 활성화
+			StartTime:   startTime,
+			Restarts:    restartCount,
+			Limits:      limits,
+			Health:      health,
+			NextRestart: nextRestart,
 		}
 
 		processes = append(processes, processInfo)
@@ -661,27 +805,34 @@ func (m *Manager) GetProcessStatus(name string) (*ipc.ProcessInfo, error) {
 	}
 
 	return &ipc.ProcessInfo{
-		Name:      process.Name,
-		Type:      string(process.Type),
-		Status:    string(process.State),
-		PID:       process.PID,
-		Uptime:    uptime,
-		Memory:    process.MemoryUsage,
-		CPU:       process.CPUUsage,
-		Enabled:   process.AutoRestart,
-		Logs:      true,
-		StartTime: process.StartTime,
+		Name:        process.Name,
+		Type:        string(process.Type),
+		Status:      string(process.State),
+		PID:         process.PID,
+		Uptime:      uptime,
+		Memory:      process.MemoryUsage,
+		CPU:         process.CPUUsage,
+		Enabled:     process.AutoRestart,
+		Logs:        true,
+		StartTime:   process.StartTime,
+		Restarts:    process.RestartCount,
+		Limits:      process.limitsInfo(),
+		Health:      process.healthInfo(),
+		NextRestart: process.nextRestart,
 	}, nil
 }
 
 // captureOutput 프로세스 출력 캡처
-func (m *Manager) captureOutput(process *Process, reader io.ReadCloser, streamType string) {
+func (m *Manager) captureOutput(process *Process, pid int, reader io.ReadCloser, streamType string) {
 	defer reader.Close()
 
 	scanner := bufio.NewScanner(reader)
 	for scanner.Scan() {
 		line := scanner.Text()
 
+		// 로그 기록기와 상관없이 메모리에도 보관
+		process.output.Write(streamType, pid, line)
+
 		// 로그 레벨 결정
 		var level logger.LogLevel
 		if streamType == "stderr" {
@@ -690,9 +841,13 @@ func (m *Manager) captureOutput(process *Process, reader io.ReadCloser, streamTy
 			level = logger.LogLevelInfo
 		}
 
-		// 로그 매니저에 전달
+		// 로그 매니저에 전달 (구조화 로그 줄은 레벨과 필드를 그대로 사용)
 		if m.logManager != nil {
-			m.logManager.WriteLog(process.Name, level, line)
+			if recordLevel, message, fields, ok := logger.ParseRecord(line); ok {
+				m.logManager.WriteLog(process.Name, recordLevel, message, fields...)
+			} else {
+				m.logManager.WriteLog(process.Name, level, line)
+			}
 		}
 	}
 
@@ -708,11 +863,18 @@ func (m *Manager) watchProcess(process *Process) {
 	}
 
 	// 프로세스 종료 대기
-	err := process.cmd.Wait()
+	cmd := process.cmd
+	err := cmd.Wait()
 
+	// 잠금을 푼 뒤 기록
+	defer m.saveState()
 	process.mutex.Lock()
 	defer process.mutex.Unlock()
 
+	// 프로세스별 cgroup 정리
+	removeCgroup(process.cgroupPath)
+	process.cgroupPath = ""
+
 	if process.State == StateStopping {
 		// 정상적인 종료
 		process.State = StateStopped
@@ -727,17 +889,23 @@ func (m *Manager) watchProcess(process *Process) {
 	} else {
 		log.Printf("⚠️ Process %s exited unexpectedly", process.Name)
 	}
+	exitCode := -1
+	if cmd.ProcessState != nil {
+		exitCode = cmd.ProcessState.ExitCode()
+	}
+	signal, coreDumped := exitSignal(cmd.ProcessState)
+	m.emitEvent(ipc.EventProcessCrashed, process.Name, process.LastError, map[string]interface{}{
+		"exit_code": exitCode,
+	})
 
-	// 자동 재시작 확인
-	if process.AutoRestart && process.RestartCount < process.MaxRestarts {
-		log.Printf("🔄 Auto-restarting process: %s (attempt %d/%d)",
-			process.Name, process.RestartCount+1, process.MaxRestarts)
+	// 재시작으로 상태가 바뀌기 전에 크래시 정보 수집
+	if m.crashHandler != nil {
+		go m.crashHandler(process.crashSnapshot(exitCode, signal, coreDumped))
+	}
 
-		// 잠시 대기 후 재시작
-		go func() {
-			time.Sleep(5 * time.Second)
-			m.RestartProcess(process.Name)
-		}()
+	// 자동 재시작 (지수 백오프 + 재시작 예산)
+	if process.AutoRestart {
+		m.scheduleAutoRestart(process)
 	}
 }
 
@@ -745,6 +913,8 @@ func (m *Manager) watchProcess(process *Process) {
 func (m *Manager) monitorProcesses() {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
+	procTicker := time.NewTicker(procSnapshotInterval)
+	defer procTicker.Stop()
 
 	for {
 		select {
@@ -752,6 +922,8 @@ func (m *Manager) monitorProcesses() {
 			return
 		case <-ticker.C:
 			m.updateProcessStats()
+		case <-procTicker.C:
+			m.snapshotProcesses()
 		}
 	}
 }
@@ -876,6 +1048,8 @@ func (m *Manager) registerIPCHandlers() {
 	m.ipcServer.RegisterHandler(ipc.MessageTypeProcessStart, m.handleProcessStart)
 	m.ipcServer.RegisterHandler(ipc.MessageTypeProcessStop, m.handleProcessStop)
 	m.ipcServer.RegisterHandler(ipc.MessageTypeProcessRestart, m.handleProcessRestart)
+	m.ipcServer.RegisterHandler(ipc.MessageTypeProcessResetRestarts, m.handleProcessResetRestarts)
+	m.ipcServer.RegisterHandler(ipc.MessageTypeProcessOutput, m.handleProcessOutput)
 }
 
 // handleProcessList 프로세스 목록 핸들러
@@ -950,6 +1124,40 @@ func (m *Manager) handleProcessRestart(conn *ipc.Connection, msg *ipc.Message) *
 	}, "")
 }
 
+// handleProcessResetRestarts 재시작 카운터 초기화 핸들러
+func (m *Manager) handleProcessResetRestarts(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	component, ok := msg.Data["component"].(string)
+	if !ok {
+		return ipc.NewResponse(msg.ID, false, nil, "component parameter required")
+	}
+
+	if err := m.ResetRestarts(component); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
+		"component": component,
+		"action":    "restarts_reset",
+	}, "")
+}
+
+// handleProcessOutput 최근 출력 조회 핸들러
+func (m *Manager) handleProcessOutput(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	component, ok := msg.Data["component"].(string)
+	if !ok {
+		return ipc.NewResponse(msg.ID, false, nil, "component parameter required")
+	}
+	stream, _ := msg.Data["stream"].(string)
+	tail, _ := msg.Data["tail"].(float64)
+
+	output, err := m.GetProcessOutput(component, stream, int(tail))
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	return ipc.NewResponse(msg.ID, true, output, "")
+}
+
 // cleanup Go 1.24 기능: 자원 정리
 func (m *Manager) cleanup() {
 	m.cleanupMux.Lock()
@@ -0,0 +1,125 @@
+package process
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// DefaultOutputBufferSize 프로세스마다 메모리에 남기는 stdout/stderr 크기
+const DefaultOutputBufferSize = 64 * 1024
+
+// outputBuffer 프로세스 출력의 마지막 줄들을 크기 한도 안에서 보관하는 링 버퍼.
+// 로그 파일과 별개로 유지되므로 로그 기록기가 아무것도 쓰기 전에 죽은 프로세스의
+// 출력도 볼 수 있다. 재시작해도 비우지 않으므로 직전 실행의 마지막 출력이 남는다.
+type outputBuffer struct {
+	mu       sync.Mutex
+	lines    []ipc.OutputLine
+	head     int   // 가장 오래된 줄의 위치
+	count    int   // 보관 중인 줄 수
+	size     int   // 보관 중인 줄의 바이트 합계
+	capacity int   // 바이트 한도
+	dropped  int64 // 한도 때문에 버린 줄 수
+}
+
+func newOutputBuffer(capacity int) *outputBuffer {
+	if capacity <= 0 {
+		capacity = DefaultOutputBufferSize
+	}
+	return &outputBuffer{capacity: capacity}
+}
+
+// Write 한 줄을 추가하고 한도를 넘으면 오래된 줄부터 버린다
+func (b *outputBuffer) Write(stream string, pid int, text string) {
+	// 한 줄이 한도보다 길면 뒷부분만 보관
+	if len(text) > b.capacity {
+		text = text[len(text)-b.capacity:]
+	}
+	line := ipc.OutputLine{Time: time.Now(), Stream: stream, PID: pid, Text: text}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for b.count > 0 && b.size+len(text) > b.capacity {
+		b.size -= len(b.lines[b.head].Text)
+		b.lines[b.head] = ipc.OutputLine{}
+		b.head = (b.head + 1) % len(b.lines)
+		b.count--
+		b.dropped++
+	}
+
+	if b.count == len(b.lines) {
+		b.grow()
+	}
+	b.lines[(b.head+b.count)%len(b.lines)] = line
+	b.count++
+	b.size += len(text)
+}
+
+// grow 슬롯이 모자라면 순서를 유지한 채 두 배로 늘린다
+func (b *outputBuffer) grow() {
+	n := len(b.lines) * 2
+	if n == 0 {
+		n = 64
+	}
+	lines := make([]ipc.OutputLine, n)
+	for i := 0; i < b.count; i++ {
+		lines[i] = b.lines[(b.head+i)%len(b.lines)]
+	}
+	b.lines = lines
+	b.head = 0
+}
+
+// Snapshot 보관 중인 줄을 오래된 순서로 복사한다. stream이 비어 있지 않으면 그 스트림만,
+// tail이 0보다 크면 마지막 tail줄만 반환한다.
+func (b *outputBuffer) Snapshot(stream string, tail int) ([]ipc.OutputLine, int, int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	lines := make([]ipc.OutputLine, 0, b.count)
+	for i := 0; i < b.count; i++ {
+		line := b.lines[(b.head+i)%len(b.lines)]
+		if stream == "" || line.Stream == stream {
+			lines = append(lines, line)
+		}
+	}
+	if tail > 0 && len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+	return lines, b.size, b.dropped
+}
+
+// GetProcessOutput 프로세스의 최근 stdout/stderr 출력 조회
+func (m *Manager) GetProcessOutput(name, stream string, tail int) (*ipc.ProcessOutput, error) {
+	switch stream {
+	case "", "stdout", "stderr":
+	default:
+		return nil, fmt.Errorf("invalid stream %q (use stdout or stderr)", stream)
+	}
+
+	m.processesMux.RLock()
+	process, exists := m.processes[name]
+	m.processesMux.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("process %s not found", name)
+	}
+
+	process.mutex.RLock()
+	state := string(process.State)
+	pid := process.PID
+	process.mutex.RUnlock()
+
+	lines, size, dropped := process.output.Snapshot(stream, tail)
+	return &ipc.ProcessOutput{
+		Name:     name,
+		Status:   state,
+		PID:      pid,
+		Size:     size,
+		Capacity: process.output.capacity,
+		Dropped:  dropped,
+		Lines:    lines,
+	}, nil
+}
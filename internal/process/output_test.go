@@ -0,0 +1,56 @@
+package process
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOutputBufferKeepsNewestLines(t *testing.T) {
+	b := newOutputBuffer(20)
+	b.Write("stdout", 10, "first")  // 5
+	b.Write("stderr", 10, "second") // 6
+	b.Write("stdout", 11, "third")  // 5
+	b.Write("stdout", 11, "fourth") // 6 → "first" 버림
+
+	lines, size, dropped := b.Snapshot("", 0)
+	var texts []string
+	for _, line := range lines {
+		texts = append(texts, line.Text)
+	}
+	if got := strings.Join(texts, ","); got != "second,third,fourth" {
+		t.Fatalf("lines = %s", got)
+	}
+	if size != 17 || dropped != 1 {
+		t.Fatalf("size = %d, dropped = %d", size, dropped)
+	}
+	if lines[0].Stream != "stderr" || lines[0].PID != 10 || lines[2].PID != 11 {
+		t.Fatalf("unexpected metadata: %+v", lines)
+	}
+
+	stderr, _, _ := b.Snapshot("stderr", 0)
+	if len(stderr) != 1 || stderr[0].Text != "second" {
+		t.Fatalf("stderr = %+v", stderr)
+	}
+	tail, _, _ := b.Snapshot("", 1)
+	if len(tail) != 1 || tail[0].Text != "fourth" {
+		t.Fatalf("tail = %+v", tail)
+	}
+}
+
+func TestOutputBufferGrowsAndTruncatesLongLines(t *testing.T) {
+	b := newOutputBuffer(1000)
+	for i := 0; i < 200; i++ {
+		b.Write("stdout", 1, "x")
+	}
+	lines, size, dropped := b.Snapshot("", 0)
+	if len(lines) != 200 || size != 200 || dropped != 0 {
+		t.Fatalf("len = %d, size = %d, dropped = %d", len(lines), size, dropped)
+	}
+
+	// 한도보다 긴 줄은 뒷부분만 남고 나머지는 모두 밀려남
+	b.Write("stderr", 1, strings.Repeat("a", 500)+strings.Repeat("b", 1000))
+	lines, size, dropped = b.Snapshot("", 0)
+	if len(lines) != 1 || size != 1000 || dropped != 200 || strings.Contains(lines[0].Text, "a") {
+		t.Fatalf("len = %d, size = %d, dropped = %d", len(lines), size, dropped)
+	}
+}
@@ -0,0 +1,154 @@
+package process
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+)
+
+const (
+	restartBackoffBase   = 2 * time.Second
+	restartBackoffMax    = 5 * time.Minute
+	restartJitter        = 0.2             // ±20%
+	restartStableAfter   = 2 * time.Minute // 이 시간 이상 실행되면 백오프 초기화
+	defaultRestartBudget = 5
+	defaultRestartWindow = 10 * time.Minute
+)
+
+// backoffDelay 연속 실패 횟수에 따른 지수 백오프 (rnd는 [0,1) 난수)
+func backoffDelay(consecutive int, rnd float64) time.Duration {
+	delay := restartBackoffBase
+	for i := 1; i < consecutive && delay < restartBackoffMax; i++ {
+		delay *= 2
+	}
+	delay = min(delay, restartBackoffMax)
+
+	jitter := (rnd*2 - 1) * restartJitter
+	return time.Duration(float64(delay) * (1 + jitter))
+}
+
+// restartBudget 윈도우당 허용 재시작 횟수와 윈도우 길이
+func (p *Process) restartBudget() (int, time.Duration) {
+	budget, window := p.MaxRestarts, p.RestartWindow
+	if budget <= 0 {
+		budget = defaultRestartBudget
+	}
+	if window <= 0 {
+		window = defaultRestartWindow
+	}
+	return budget, window
+}
+
+// planRestart 다음 자동 재시작까지의 지연 계산 (호출자가 mutex 보유)
+// 예산이 소진된 경우 가장 오래된 재시작이 윈도우를 벗어날 때까지 지연하며 budgetWait=true
+func (p *Process) planRestart(now time.Time, rnd float64) (delay time.Duration, budgetWait bool) {
+	budget, window := p.restartBudget()
+
+	// 윈도우를 벗어난 재시작 기록 제거
+	recent := p.restartTimes[:0]
+	for _, t := range p.restartTimes {
+		if now.Sub(t) < window {
+			recent = append(recent, t)
+		}
+	}
+	p.restartTimes = recent
+
+	// 충분히 오래 실행되었다면 일시적 장애로 보고 백오프 초기화
+	if !p.StartTime.IsZero() && now.Sub(p.StartTime) >= restartStableAfter {
+		p.consecutiveFailures = 0
+	}
+	p.consecutiveFailures++
+
+	delay = backoffDelay(p.consecutiveFailures, rnd)
+	if len(p.restartTimes) >= budget {
+		if wait := p.restartTimes[len(p.restartTimes)-budget].Add(window).Sub(now); wait > delay {
+			delay = wait
+			budgetWait = true
+		}
+	}
+
+	p.restartTimes = append(p.restartTimes, now.Add(delay))
+	return delay, budgetWait
+}
+
+// scheduleAutoRestart 백오프와 재시작 예산에 따라 자동 재시작 예약 (호출자가 mutex 보유)
+func (m *Manager) scheduleAutoRestart(process *Process) {
+	if process.restartTimer != nil {
+		return
+	}
+
+	delay, budgetWait := process.planRestart(time.Now(), rand.Float64())
+	budget, window := process.restartBudget()
+	name := process.Name
+
+	if budgetWait {
+		log.Printf("⏸️ Restart budget for %s exhausted (%d per %s), next attempt in %s",
+			name, budget, window, delay.Round(time.Second))
+	} else {
+		log.Printf("🔄 Auto-restarting process %s in %s (consecutive failure %d)",
+			name, delay.Round(time.Millisecond), process.consecutiveFailures)
+	}
+
+	next := time.Now().Add(delay)
+	process.nextRestart = &next
+	process.restartTimer = time.AfterFunc(delay, func() {
+		process.mutex.Lock()
+		process.restartTimer = nil
+		process.nextRestart = nil
+		process.mutex.Unlock()
+
+		if err := m.RestartProcess(name); err != nil {
+			log.Printf("❌ Auto-restart of %s failed: %v", name, err)
+
+			// 시작 자체가 실패한 경우 다음 시도 예약
+			process.mutex.Lock()
+			if process.AutoRestart && process.State == StateError {
+				m.scheduleAutoRestart(process)
+			}
+			process.mutex.Unlock()
+		}
+	})
+}
+
+// cancelPendingRestart 예약된 자동 재시작 취소 (호출자가 mutex 보유)
+func (p *Process) cancelPendingRestart() bool {
+	if p.restartTimer == nil {
+		return false
+	}
+	stopped := p.restartTimer.Stop()
+	p.restartTimer = nil
+	p.nextRestart = nil
+	return stopped
+}
+
+// ResetRestarts 수동 조치 후 재시작 카운터와 백오프 초기화
+// 재시작이 예약되어 있었다면 즉시 재시작한다.
+func (m *Manager) ResetRestarts(name string) error {
+	m.processesMux.RLock()
+	process, exists := m.processes[name]
+	m.processesMux.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("process %s not found", name)
+	}
+
+	process.mutex.Lock()
+	pending := process.cancelPendingRestart()
+	process.RestartCount = 0
+	process.consecutiveFailures = 0
+	process.restartTimes = nil
+	process.mutex.Unlock()
+
+	log.Printf("🧹 Restart counters reset for %s", name)
+
+	if pending {
+		go func() {
+			if err := m.RestartProcess(name); err != nil {
+				log.Printf("❌ Restart of %s after reset failed: %v", name, err)
+			}
+		}()
+	}
+
+	return nil
+}
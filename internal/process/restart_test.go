@@ -0,0 +1,59 @@
+package process
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	cases := []struct {
+		consecutive int
+		want        time.Duration
+	}{
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{5, 32 * time.Second},
+		{20, restartBackoffMax},
+	}
+	for _, tc := range cases {
+		// rnd=0.5는 지터 0
+		if got := backoffDelay(tc.consecutive, 0.5); got != tc.want {
+			t.Errorf("backoffDelay(%d) = %s, want %s", tc.consecutive, got, tc.want)
+		}
+	}
+
+	low, high := backoffDelay(1, 0), backoffDelay(1, 0.999)
+	if low < 1600*time.Millisecond || high > 2400*time.Millisecond {
+		t.Errorf("jitter out of range: %s..%s", low, high)
+	}
+}
+
+func TestPlanRestartBudget(t *testing.T) {
+	p := &Process{MaxRestarts: 3, RestartWindow: time.Minute}
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		p.StartTime = now
+		delay, budgetWait := p.planRestart(now, 0.5)
+		if budgetWait {
+			t.Fatalf("restart %d: budget exhausted too early", i)
+		}
+		now = now.Add(delay + time.Second)
+	}
+
+	// 4번째 재시작은 첫 재시작이 윈도우를 벗어날 때까지 대기
+	first := p.restartTimes[0]
+	delay, budgetWait := p.planRestart(now, 0.5)
+	if !budgetWait {
+		t.Fatal("expected restart budget to be exhausted")
+	}
+	if want := first.Add(time.Minute).Sub(now); delay != want {
+		t.Errorf("delay = %s, want %s", delay, want)
+	}
+
+	// 안정적으로 오래 실행된 뒤의 실패는 백오프 초기화
+	p = &Process{StartTime: now.Add(-time.Hour), consecutiveFailures: 6}
+	if delay, _ := p.planRestart(now, 0.5); delay != restartBackoffBase {
+		t.Errorf("delay after stable run = %s, want %s", delay, restartBackoffBase)
+	}
+}
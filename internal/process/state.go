@@ -0,0 +1,265 @@
+package process
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/sysinfo"
+)
+
+// PersistedProcess 슈퍼바이저가 다시 시작해도 이어서 관리할 수 있도록 기록하는 내부 컴포넌트 프로세스.
+// 환경 변수는 시크릿이 들어 있으므로 기록하지 않음
+type PersistedProcess struct {
+	Name       string      `json:"name"`
+	Type       ProcessType `json:"type"`
+	User       string      `json:"user,omitempty"`
+	Command    string      `json:"command"`
+	Args       []string    `json:"args,omitempty"`
+	WorkDir    string      `json:"work_dir,omitempty"`
+	PID        int         `json:"pid"`
+	StartTicks uint64      `json:"start_ticks"` // 프로세스 시작 시각 (PID 재사용 감지)
+	StartTime  time.Time   `json:"start_time"`
+}
+
+// persistedState 프로세스 상태 파일 내용
+type persistedState struct {
+	BootID               string             `json:"boot_id,omitempty"`
+	SupervisorPID        int                `json:"supervisor_pid"`
+	SupervisorStartTicks uint64             `json:"supervisor_start_ticks"`
+	UpdatedAt            time.Time          `json:"updated_at"`
+	Processes            []PersistedProcess `json:"processes"`
+}
+
+// LoadState 이전 슈퍼바이저가 남긴 상태 파일을 읽어 아직 살아 있는 프로세스를 입양 대상으로 기억하고,
+// 이후 상태 변화를 같은 파일에 기록합니다. 재부팅했거나, 프로세스가 끝났거나, PID가 다른 프로세스에
+// 재사용된 항목은 오래된 상태로 보고 버립니다. 이전 슈퍼바이저가 아직 실행 중이면 아무것도 입양하지 않습니다.
+func (m *Manager) LoadState(path string) error {
+	m.stateMux.Lock()
+	m.statePath = path
+	m.stateMux.Unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read process state: %w", err)
+	}
+	var state persistedState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("invalid process state file %s: %w", path, err)
+	}
+
+	adoptable, stale := m.checkState(&state)
+	for _, reason := range stale {
+		log.Printf("🧹 Ignoring stale process state: %s", reason)
+	}
+
+	m.processesMux.Lock()
+	m.adoptable = adoptable
+	m.processesMux.Unlock()
+
+	for _, p := range adoptable {
+		log.Printf("🔎 %s (PID: %d) is still running from the previous supervisor", p.Name, p.PID)
+	}
+	return nil
+}
+
+// checkState 기록된 프로세스 중 그대로 이어받을 수 있는 것과, 버리는 항목의 이유를 반환합니다
+func (m *Manager) checkState(state *persistedState) (map[string]PersistedProcess, []string) {
+	adoptable := make(map[string]PersistedProcess)
+	var stale []string
+
+	if state.BootID != "" && state.BootID != bootID() {
+		return adoptable, []string{"the host rebooted since it was written"}
+	}
+	if state.SupervisorPID > 0 && state.SupervisorPID != os.Getpid() && m.isProcessRunning(state.SupervisorPID) {
+		if ticks, err := processStartTicks(state.SupervisorPID); err == nil && ticks == state.SupervisorStartTicks {
+			return adoptable, []string{fmt.Sprintf("the supervisor that wrote it (PID: %d) is still running", state.SupervisorPID)}
+		}
+	}
+
+	for _, p := range state.Processes {
+		if !m.isProcessRunning(p.PID) {
+			stale = append(stale, fmt.Sprintf("%s (PID: %d) is no longer running", p.Name, p.PID))
+			continue
+		}
+		if ticks, err := processStartTicks(p.PID); err != nil || ticks != p.StartTicks {
+			stale = append(stale, fmt.Sprintf("PID %d of %s now belongs to another process", p.PID, p.Name))
+			continue
+		}
+		adoptable[p.Name] = p
+	}
+	return adoptable, stale
+}
+
+// Unadopted 이전 슈퍼바이저가 남긴 프로세스 중 아직 등록되어 입양되지 않은 것
+func (m *Manager) Unadopted() []PersistedProcess {
+	m.processesMux.RLock()
+	defer m.processesMux.RUnlock()
+
+	var left []PersistedProcess
+	for _, p := range m.adoptable {
+		left = append(left, p)
+	}
+	sort.Slice(left, func(i, j int) bool { return left[i].Name < left[j].Name })
+	return left
+}
+
+// takeAdoptable 입양 대상에서 name을 꺼냅니다
+func (m *Manager) takeAdoptable(name string) (PersistedProcess, bool) {
+	m.processesMux.Lock()
+	defer m.processesMux.Unlock()
+
+	p, ok := m.adoptable[name]
+	if ok {
+		delete(m.adoptable, name)
+	}
+	return p, ok
+}
+
+// adopt 이전 슈퍼바이저가 시작한 프로세스를 새로 시작하는 대신 이어서 관리합니다.
+// 명령이 바뀌었거나 그 사이 프로세스가 끝났으면 false를 반환하고, 호출자는 새로 시작합니다.
+// stdout/stderr 파이프는 이전 슈퍼바이저와 함께 닫혔으므로 출력은 다시 시작할 때까지 캡처하지 않습니다.
+func (m *Manager) adopt(process *Process, prev PersistedProcess) bool {
+	process.mutex.RLock()
+	sameCommand := process.Command == prev.Command && process.User == prev.User &&
+		process.WorkDir == prev.WorkDir && slices.Equal(process.Args, prev.Args)
+	process.mutex.RUnlock()
+
+	if !sameCommand {
+		// 설정이 바뀐 채로 두면 같은 컴포넌트가 둘 실행됨
+		log.Printf("⚠️ %s (PID: %d) from the previous supervisor runs an outdated command, stopping it", prev.Name, prev.PID)
+		terminatePID(prev.PID, 5*time.Second, m.isProcessRunning)
+		return false
+	}
+	if ticks, err := processStartTicks(prev.PID); err != nil || ticks != prev.StartTicks || !m.isProcessRunning(prev.PID) {
+		log.Printf("⚠️ %s (PID: %d) from the previous supervisor exited before it was adopted", prev.Name, prev.PID)
+		return false
+	}
+
+	process.mutex.Lock()
+	process.PID = prev.PID
+	process.StartTime = prev.StartTime
+	process.State = StateRunning
+	process.LastError = ""
+	process.procSnapshot = nil
+	process.cmd = nil
+	process.cancel = nil
+	process.mutex.Unlock()
+
+	log.Printf("🔗 Adopted %s (PID: %d) started by the previous supervisor", prev.Name, prev.PID)
+	m.emitEvent(ipc.EventProcessStarted, prev.Name, "", map[string]interface{}{"pid": prev.PID, "adopted": true})
+
+	if process.hasLimits() {
+		enforcement, err := applyResourceLimits(process, prev.PID)
+		if err != nil {
+			log.Printf("⚠️ Failed to apply resource limits to %s: %v", prev.Name, err)
+		}
+		process.limitEnforcement = enforcement
+	}
+
+	go m.watchAttachedProcess(process)
+	return true
+}
+
+// terminatePID SIGTERM을 보내고 timeout 안에 끝나지 않으면 SIGKILL
+func terminatePID(pid int, timeout time.Duration, running func(int) bool) {
+	if err := sysinfo.Signal(pid, syscall.SIGTERM); err != nil {
+		return
+	}
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if !running(pid) {
+			return
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	sysinfo.Signal(pid, syscall.SIGKILL)
+}
+
+// processStartTicks 프로세스 시작 시각을 나타내는 값. 같은 PID를 쓰는 다른 프로세스를 구분하며,
+// 시작 시각을 알 수 없는 플랫폼에서는 항상 0이므로 PID 재사용을 구분하지 않음
+func processStartTicks(pid int) (uint64, error) {
+	ticks, err := sysinfo.ProcessStartTime(pid)
+	if errors.Is(err, sysinfo.ErrUnsupported) {
+		return 0, nil
+	}
+	return ticks, err
+}
+
+// saveState 실행 중인 내부 컴포넌트를 상태 파일에 기록합니다 (LoadState 전에는 아무것도 하지 않음)
+func (m *Manager) saveState() {
+	m.stateMux.Lock()
+	defer m.stateMux.Unlock()
+	if m.statePath == "" {
+		return
+	}
+
+	state := persistedState{
+		BootID:        bootID(),
+		SupervisorPID: os.Getpid(),
+		UpdatedAt:     time.Now(),
+		Processes:     []PersistedProcess{},
+	}
+	state.SupervisorStartTicks, _ = processStartTicks(os.Getpid())
+
+	m.processesMux.RLock()
+	for _, process := range m.processes {
+		process.mutex.RLock()
+		if process.Type == TypeInternal && process.State == StateRunning && process.PID > 0 {
+			state.Processes = append(state.Processes, PersistedProcess{
+				Name:      process.Name,
+				Type:      process.Type,
+				User:      process.User,
+				Command:   process.Command,
+				Args:      process.Args,
+				WorkDir:   process.WorkDir,
+				PID:       process.PID,
+				StartTime: process.StartTime,
+			})
+		}
+		process.mutex.RUnlock()
+	}
+	// 아직 입양되지 않은 프로세스도 다음 슈퍼바이저를 위해 남김
+	for _, p := range m.adoptable {
+		state.Processes = append(state.Processes, p)
+	}
+	m.processesMux.RUnlock()
+
+	for i := range state.Processes {
+		if state.Processes[i].StartTicks == 0 {
+			state.Processes[i].StartTicks, _ = processStartTicks(state.Processes[i].PID)
+		}
+	}
+	sort.Slice(state.Processes, func(i, j int) bool { return state.Processes[i].Name < state.Processes[j].Name })
+
+	if err := writeStateFile(m.statePath, &state); err != nil {
+		log.Printf("⚠️ Failed to save process state: %v", err)
+	}
+}
+
+// writeStateFile 상태 파일을 원자적으로 교체
+func writeStateFile(path string, state *persistedState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
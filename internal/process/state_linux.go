@@ -0,0 +1,17 @@
+//go:build linux
+
+package process
+
+import (
+	"os"
+	"strings"
+)
+
+// bootID 현재 부팅의 ID (재부팅 후에는 기록된 PID가 모두 무효)
+func bootID() string {
+	data, err := os.ReadFile("/proc/sys/kernel/random/boot_id")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package process
+
+// bootID 리눅스 외 플랫폼에서는 부팅 ID를 제공하지 않음
+func bootID() string {
+	return ""
+}
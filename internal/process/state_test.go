@@ -0,0 +1,155 @@
+package process
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// startSleeper 테스트가 끝나면 정리되는 sleep 프로세스
+func startSleeper(t *testing.T) (*exec.Cmd, <-chan struct{}) {
+	t.Helper()
+	path, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("sleep not available")
+	}
+	cmd := exec.Command(path, "30")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	// 종료되면 바로 회수해야 /proc에서 사라짐
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+	t.Cleanup(func() { cmd.Process.Kill(); <-exited })
+	return cmd, exited
+}
+
+func writeTestState(t *testing.T, state *persistedState) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "state", "processes.json")
+	if err := writeStateFile(path, state); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadStateDetectsStaleEntries(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("PID reuse detection needs /proc")
+	}
+	sleeper, _ := startSleeper(t)
+	ticks, err := processStartTicks(sleeper.Process.Pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gone := exec.Command("true")
+	if err := gone.Run(); err != nil {
+		t.Skip("true not available")
+	}
+
+	path := writeTestState(t, &persistedState{
+		BootID: bootID(),
+		Processes: []PersistedProcess{
+			{Name: "api", Command: sleeper.Path, Args: []string{"30"}, PID: sleeper.Process.Pid, StartTicks: ticks},
+			{Name: "data-manager", PID: gone.Process.Pid},
+			{Name: "data-consumer", PID: sleeper.Process.Pid, StartTicks: ticks + 1},
+		},
+	})
+	m := NewManager(nil, nil)
+	if err := m.LoadState(path); err != nil {
+		t.Fatal(err)
+	}
+	left := m.Unadopted()
+	if len(left) != 1 || left[0].Name != "api" {
+		t.Fatalf("adoptable = %+v, want only api", left)
+	}
+
+	// 재부팅 뒤에는 아무것도 이어받지 않음
+	path = writeTestState(t, &persistedState{
+		BootID:    "another-boot",
+		Processes: []PersistedProcess{{Name: "api", PID: sleeper.Process.Pid, StartTicks: ticks}},
+	})
+	m = NewManager(nil, nil)
+	if err := m.LoadState(path); err != nil {
+		t.Fatal(err)
+	}
+	if left := m.Unadopted(); len(left) != 0 {
+		t.Errorf("adoptable after reboot = %+v", left)
+	}
+}
+
+func TestStartProcessAdoptsRunningProcess(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("adoption needs /proc")
+	}
+	sleeper, exited := startSleeper(t)
+	ticks, _ := processStartTicks(sleeper.Process.Pid)
+	path := writeTestState(t, &persistedState{
+		BootID:    bootID(),
+		Processes: []PersistedProcess{{Name: "worker", Type: TypeInternal, Command: sleeper.Path, Args: []string{"30"}, PID: sleeper.Process.Pid, StartTicks: ticks}},
+	})
+
+	m := NewManager(nil, nil)
+	defer m.cancel()
+	if err := m.LoadState(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterProcess(&ProcessConfig{Name: "worker", Type: TypeInternal, Command: sleeper.Path, Args: []string{"30"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.StartProcess("worker"); err != nil {
+		t.Fatal(err)
+	}
+	status, err := m.GetProcessStatus("worker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.PID != sleeper.Process.Pid || status.Status != string(StateRunning) {
+		t.Fatalf("status = %+v, want adopted PID %d", status, sleeper.Process.Pid)
+	}
+
+	var saved persistedState
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Processes) != 1 || saved.Processes[0].PID != sleeper.Process.Pid || saved.SupervisorPID != os.Getpid() {
+		t.Errorf("saved state = %+v", saved)
+	}
+
+	if err := m.StopProcess("worker"); err != nil {
+		t.Fatal(err)
+	}
+	<-exited
+	data, _ = os.ReadFile(path)
+	saved = persistedState{}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Processes) != 0 {
+		t.Errorf("stopped process still recorded: %+v", saved.Processes)
+	}
+}
+
+func TestAdoptStopsOutdatedProcess(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("adoption needs /proc")
+	}
+	sleeper, exited := startSleeper(t)
+	ticks, _ := processStartTicks(sleeper.Process.Pid)
+
+	m := NewManager(nil, nil)
+	p := &Process{Name: "worker", Type: TypeInternal, Command: sleeper.Path, Args: []string{"60"}}
+	prev := PersistedProcess{Name: "worker", Command: sleeper.Path, Args: []string{"30"}, PID: sleeper.Process.Pid, StartTicks: ticks}
+	if m.adopt(p, prev) {
+		t.Fatal("adopted a process started with other arguments")
+	}
+	<-exited
+}
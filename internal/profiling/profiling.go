@@ -0,0 +1,172 @@
+// Package profiling 컴포넌트의 net/http/pprof 엔드포인트를 토큰 인증 뒤에 노출한다.
+//
+// 슈퍼바이저는 설정에서 profiling이 켜져 있으면 내부 컴포넌트를 시작할 때
+// TMIDB_PPROF_ADDR/TMIDB_PPROF_TOKEN을 넘기고, 컴포넌트는 StartFromEnv로 서버를 연다.
+// 프로파일은 슈퍼바이저가 Fetch로 받아 저장한다.
+package profiling
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// EnvAddr 컴포넌트가 pprof 서버를 열 주소 (비어 있으면 열지 않음)
+	EnvAddr = "TMIDB_PPROF_ADDR"
+	// EnvToken 요청에 필요한 Bearer 토큰
+	EnvToken = "TMIDB_PPROF_TOKEN"
+
+	// DefaultDuration cpu/trace 프로파일의 기본 수집 시간
+	DefaultDuration = 30 * time.Second
+	// MaxDuration cpu/trace 프로파일의 최대 수집 시간
+	MaxDuration = 5 * time.Minute
+)
+
+// Types 수집할 수 있는 프로파일 종류
+var Types = []string{"heap", "cpu", "goroutine", "allocs", "trace"}
+
+// Path 프로파일 종류에 해당하는 요청 경로. duration은 cpu/trace에만 쓰인다.
+func Path(profileType string, duration time.Duration) (string, error) {
+	switch profileType {
+	case "cpu", "trace":
+		if duration <= 0 {
+			duration = DefaultDuration
+		}
+		if duration > MaxDuration {
+			return "", fmt.Errorf("duration %s exceeds the maximum of %s", duration, MaxDuration)
+		}
+		seconds := int(duration.Round(time.Second) / time.Second)
+		if seconds < 1 {
+			seconds = 1
+		}
+		name := "profile"
+		if profileType == "trace" {
+			name = "trace"
+		}
+		return fmt.Sprintf("/debug/pprof/%s?seconds=%d", name, seconds), nil
+	case "heap", "goroutine", "allocs":
+		return "/debug/pprof/" + profileType, nil
+	default:
+		return "", fmt.Errorf("unknown profile type %q (use %s)", profileType, strings.Join(Types, ", "))
+	}
+}
+
+// Handler /debug/pprof/ 핸들러를 토큰 검사로 감싼다
+func Handler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got := []byte(req.Header.Get("Authorization"))
+		if token == "" || subtle.ConstantTimeCompare(got, expected) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, req)
+	})
+}
+
+// Server 토큰 인증이 걸린 pprof HTTP 서버
+type Server struct {
+	server   *http.Server
+	listener net.Listener
+}
+
+// Start pprof 서버 시작 (논블로킹). 토큰 없이는 열지 않는다.
+func Start(addr, token string) (*Server, error) {
+	if token == "" {
+		return nil, fmt.Errorf("pprof endpoint requires a token")
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	s := &Server{
+		listener: listener,
+		server: &http.Server{
+			Handler:           Handler(token),
+			ReadHeaderTimeout: 5 * time.Second,
+			// pprof는 수집 시간이 WriteTimeout보다 길면 요청을 거부한다
+			WriteTimeout: MaxDuration + 30*time.Second,
+		},
+	}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("❌ pprof server error: %v", err)
+		}
+	}()
+	return s, nil
+}
+
+// StartFromEnv 환경 변수에 주소가 있으면 pprof 서버를 연다. 실패해도 컴포넌트는 계속 동작한다.
+func StartFromEnv(component string) *Server {
+	addr := strings.TrimSpace(os.Getenv(EnvAddr))
+	if addr == "" {
+		return nil
+	}
+	s, err := Start(addr, os.Getenv(EnvToken))
+	if err != nil {
+		log.Printf("⚠️ %s: failed to start pprof endpoint: %v", component, err)
+		return nil
+	}
+	log.Printf("🔬 %s: pprof endpoint listening on %s/debug/pprof/", component, s.Addr())
+	return s
+}
+
+// Stop 서버 정지
+func (s *Server) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return s.server.Shutdown(ctx)
+}
+
+// Addr 실제 수신 주소
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Fetch addr의 pprof 서버에서 프로파일을 받는다. cpu/trace는 duration만큼 걸린다.
+func Fetch(ctx context.Context, addr, token, profileType string, duration time.Duration) ([]byte, error) {
+	path, err := Path(profileType, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach pprof endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("pprof endpoint returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,79 @@
+package profiling
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPath(t *testing.T) {
+	cases := map[string]string{
+		"heap":      "/debug/pprof/heap",
+		"goroutine": "/debug/pprof/goroutine",
+		"cpu":       "/debug/pprof/profile?seconds=2",
+		"trace":     "/debug/pprof/trace?seconds=2",
+	}
+	for profileType, want := range cases {
+		got, err := Path(profileType, 2*time.Second)
+		if err != nil || got != want {
+			t.Errorf("Path(%s) = %q, %v; want %q", profileType, got, err, want)
+		}
+	}
+
+	if _, err := Path("threadcreate", 0); err == nil {
+		t.Error("unknown type should fail")
+	}
+	if _, err := Path("cpu", MaxDuration+time.Second); err == nil {
+		t.Error("duration above the maximum should fail")
+	}
+}
+
+func TestServerRequiresToken(t *testing.T) {
+	if _, err := Start("127.0.0.1:0", ""); err == nil {
+		t.Fatal("server must not start without a token")
+	}
+
+	s, err := Start("127.0.0.1:0", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	for _, header := range []string{"", "Bearer wrong", "secret"} {
+		req, _ := http.NewRequest(http.MethodGet, "http://"+s.Addr()+"/debug/pprof/heap", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: status %d, want 401", header, resp.StatusCode)
+		}
+	}
+
+	if _, err := Fetch(context.Background(), s.Addr(), "wrong", "heap", 0); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("expected 401 error, got %v", err)
+	}
+}
+
+func TestFetchHeapProfile(t *testing.T) {
+	s, err := Start("127.0.0.1:0", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	data, err := Fetch(context.Background(), s.Addr(), "secret", "heap", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// pprof 프로토콜 버퍼는 gzip으로 압축되어 있음
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		t.Fatalf("heap profile is not gzip data (%d bytes)", len(data))
+	}
+}
@@ -0,0 +1,147 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaxFields fields 파라미터에 쓸 수 있는 항목 수
+const MaxFields = 50
+
+// responseColumns 응답 항목의 최상위 필드
+var responseColumns = map[string]bool{
+	"target_id":  true,
+	"category":   true,
+	"version":    true,
+	"revision":   true,
+	"created_at": true,
+	"updated_at": true,
+	"data":       true,
+}
+
+// Projection은 ?fields=target_id,data.temperature 처럼 응답에 포함할 필드입니다.
+// 데이터 경로는 SQL에서 jsonb_build_object로 골라내고, 최상위 필드는 응답을 만들 때 고릅니다.
+type Projection struct {
+	Columns []string   // 최상위 필드 (data는 데이터 경로가 있으면 자동으로 포함)
+	Paths   [][]string // category_data 안의 경로 (없으면 data 전체)
+}
+
+// ParseFields는 쉼표로 구분한 필드 목록을 읽습니다. 빈 문자열이면 nil(전체)을 반환합니다.
+// target_id, category, version, revision, created_at, updated_at, data가 아닌 이름은 데이터 경로로 봅니다.
+func ParseFields(expr string) (*Projection, error) {
+	p := &Projection{}
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(expr, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		if responseColumns[item] {
+			p.Columns = append(p.Columns, item)
+			continue
+		}
+		field, err := ParseField(item)
+		if err != nil || field.Column != "" {
+			return nil, fmt.Errorf("invalid field %q", item)
+		}
+		p.Paths = append(p.Paths, field.Path)
+	}
+	if len(seen) > MaxFields {
+		return nil, fmt.Errorf("too many fields: %d (max %d)", len(seen), MaxFields)
+	}
+	if len(seen) == 0 {
+		return nil, nil
+	}
+	if len(p.Paths) > 0 && !p.Includes("data") {
+		p.Columns = append(p.Columns, "data")
+	}
+	return p, nil
+}
+
+// Includes는 최상위 필드가 응답에 포함되는지 확인합니다
+func (p *Projection) Includes(column string) bool {
+	if p == nil {
+		return true
+	}
+	for _, c := range p.Columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// String은 캐시 키에 쓸 정규화된 필드 목록입니다
+func (p *Projection) String() string {
+	if p == nil {
+		return "*"
+	}
+	items := append([]string(nil), p.Columns...)
+	for _, path := range p.Paths {
+		items = append(items, "data."+strings.Join(path, "."))
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
+// DataSQL은 category_data 대신 선택할 SQL 식입니다.
+// 경로가 없으면 문서 전체, 있으면 경로만 담은 중첩 객체를 만듭니다
+// (data.vitals.bp, data.vitals.hr → {"vitals": {"bp": ..., "hr": ...}}). 없는 경로의 값은 null입니다.
+func (p *Projection) DataSQL() string {
+	if p == nil || len(p.Paths) == 0 {
+		if p != nil && !p.Includes("data") {
+			return "'{}'::jsonb"
+		}
+		return dataColumn
+	}
+	return buildObject(newPathTree(p.Paths), nil)
+}
+
+// pathTree는 경로를 단계별로 묶은 트리입니다. leaf면 그 아래 전체를 선택합니다.
+type pathTree struct {
+	leaf     bool
+	children map[string]*pathTree
+}
+
+func newPathTree(paths [][]string) *pathTree {
+	root := &pathTree{children: make(map[string]*pathTree)}
+	for _, path := range paths {
+		node := root
+		for _, segment := range path {
+			if node.leaf {
+				break // 상위 경로를 이미 통째로 선택함
+			}
+			child, ok := node.children[segment]
+			if !ok {
+				child = &pathTree{children: make(map[string]*pathTree)}
+				node.children[segment] = child
+			}
+			node = child
+		}
+		node.leaf = true
+		node.children = map[string]*pathTree{}
+	}
+	return root
+}
+
+// buildObject는 트리를 jsonb_build_object 식으로 만듭니다 (키는 정렬해 항상 같은 SQL이 나오게 함)
+func buildObject(node *pathTree, prefix []string) string {
+	keys := make([]string, 0, len(node.children))
+	for key := range node.children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	args := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		child, path := node.children[key], append(append([]string(nil), prefix...), key)
+		value := jsonExpr(dataColumn, path)
+		if !child.leaf {
+			value = buildObject(child, path)
+		}
+		args = append(args, "'"+strings.ReplaceAll(key, "'", "''")+"'", value)
+	}
+	return "jsonb_build_object(" + strings.Join(args, ", ") + ")"
+}
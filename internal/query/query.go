@@ -0,0 +1,369 @@
+// Package query는 카테고리 데이터 API의 필터/정렬 문법을 파싱하고 매개변수화된 SQL로 바꿉니다.
+//
+// 필터는 "경로 연산자 값" 형식입니다 (?filter=data.temp>25&filter=data.status=active).
+//
+//	경로     data.temp, data.vitals.bp (category_data 안의 중첩 필드, 배열은 data.tags.0)
+//	         updated_at, created_at, target_id, schema_version (컬럼)
+//	         data.를 빼면 데이터 필드로 봅니다 (temp = data.temp)
+//	연산자   = != > >= < <= ~ (~는 대소문자 구분 없는 부분 일치)
+//	값       data.status=(active,idle)   IN 목록 (!=이면 NOT IN)
+//	         data.temp=20..30            범위 (양 끝 포함)
+//	         data.note=null              값이 없거나 null (!=null은 값이 있음)
+//	         "..."                       따옴표로 감싸면 그대로 문자열 ("null", "1..2", 쉼표 포함 항목)
+//
+// 정렬은 쉼표로 구분한 경로이며 앞에 -를 붙이면 내림차순입니다 (?sort=-data.temp,target_id).
+// 경로는 허용된 문자만 쓸 수 있어 SQL에 그대로 넣고, 값은 항상 매개변수로 전달합니다.
+// 다른 테이블은 Table로 컬럼과 데이터 컬럼을 정해 같은 문법으로 조회합니다.
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 필터 제한
+const (
+	MaxConditions = 20
+	MaxListItems  = 100
+	MaxPathDepth  = 8
+)
+
+// 연산자
+const (
+	OpEq      = "="
+	OpNe      = "!="
+	OpGt      = ">"
+	OpGte     = ">="
+	OpLt      = "<"
+	OpLte     = "<="
+	OpLike    = "~"
+	OpIn      = "in"
+	OpNotIn   = "not in"
+	OpBetween = "between"
+	OpNull    = "null"
+	OpNotNull = "not null"
+)
+
+// operators 필터 식에서 찾는 연산자 (긴 것부터)
+var operators = []string{OpGte, OpLte, OpNe, OpGt, OpLt, OpEq, OpLike}
+
+// segmentPattern 경로 한 단계에 쓸 수 있는 문자
+var segmentPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_-]*$`)
+
+// Table은 필터와 정렬을 적용할 테이블입니다. 카테고리 데이터 API 밖에서도
+// 같은 문법으로 다른 테이블을 조회할 수 있도록 경로로 쓸 컬럼과 데이터 컬럼을 정합니다.
+type Table struct {
+	Columns    map[string]string // 경로로 쓸 수 있는 컬럼과 종류 (time, text, number)
+	Nullable   map[string]bool   // null 조건을 쓸 수 있는 컬럼
+	DataColumn string            // 데이터 필드가 들어 있는 JSONB 컬럼 (없으면 컬럼만)
+}
+
+// CategoryData 카테고리 데이터 API의 target_categories (Parse, ParseField가 쓰는 테이블)
+var CategoryData = &Table{
+	Columns: map[string]string{
+		"updated_at":     "time",
+		"created_at":     "time",
+		"target_id":      "text",
+		"schema_version": "number",
+	},
+	DataColumn: dataColumn,
+}
+
+// Field는 필터나 정렬의 대상입니다 (컬럼 또는 데이터 컬럼 안의 경로)
+type Field struct {
+	Column string   // 컬럼이면 컬럼 이름
+	Path   []string // 데이터 필드면 데이터 컬럼 안의 경로
+	Table  *Table   // nil이면 CategoryData
+}
+
+// table은 필드가 속한 테이블입니다
+func (f Field) table() *Table {
+	if f.Table == nil {
+		return CategoryData
+	}
+	return f.Table
+}
+
+// String은 필드를 요청에 쓰는 형식으로 나타냅니다
+func (f Field) String() string {
+	if f.Column != "" {
+		return f.Column
+	}
+	return "data." + strings.Join(f.Path, ".")
+}
+
+// ParseField는 경로를 읽습니다. data.로 시작하지 않는 경로는 컬럼 이름이 아니면 데이터 필드로 봅니다.
+func ParseField(path string) (Field, error) {
+	return CategoryData.ParseField(path)
+}
+
+// ParseField는 테이블의 컬럼이나 데이터 필드 경로를 읽습니다
+func (t *Table) ParseField(path string) (Field, error) {
+	if _, ok := t.Columns[path]; ok {
+		return Field{Column: path, Table: t}, nil
+	}
+	if t.DataColumn == "" {
+		return Field{}, fmt.Errorf("unknown column %q", path)
+	}
+	path = strings.TrimPrefix(path, "data.")
+	segments := strings.Split(path, ".")
+	if len(segments) > MaxPathDepth {
+		return Field{}, fmt.Errorf("path %q is deeper than %d levels", path, MaxPathDepth)
+	}
+	for _, segment := range segments {
+		if !segmentPattern.MatchString(segment) {
+			return Field{}, fmt.Errorf("invalid field path %q", path)
+		}
+	}
+	return Field{Path: segments, Table: t}, nil
+}
+
+// Condition은 필터 하나입니다
+type Condition struct {
+	Field  Field
+	Op     string
+	Values []string
+}
+
+// Key는 캐시 키에 쓸 조건의 표현입니다. 값마다 따옴표로 감싸므로 String과 달리
+// 쉼표나 " and "가 들어간 값 하나와 여러 값이 같은 키가 되지 않습니다
+func (c Condition) Key() string {
+	return fmt.Sprintf("%s %s %q", c.Field, c.Op, c.Values)
+}
+
+// String은 조건을 읽기 쉬운 형식으로 나타냅니다 (응답 메타데이터용, 캐시 키는 Key)
+func (c Condition) String() string {
+	switch c.Op {
+	case OpNull, OpNotNull:
+		return c.Field.String() + " is " + c.Op
+	case OpIn, OpNotIn:
+		return c.Field.String() + " " + c.Op + " (" + strings.Join(c.Values, ", ") + ")"
+	case OpBetween:
+		return c.Field.String() + " between " + c.Values[0] + " and " + c.Values[1]
+	default:
+		return c.Field.String() + " " + c.Op + " " + c.Values[0]
+	}
+}
+
+// ParseCondition은 "data.temp>25" 같은 필터 식을 읽습니다
+func ParseCondition(expr string) (Condition, error) {
+	return CategoryData.ParseCondition(expr)
+}
+
+// ParseCondition은 테이블의 필터 식을 읽습니다
+func (t *Table) ParseCondition(expr string) (Condition, error) {
+	at, op := -1, ""
+	for i := 0; i < len(expr) && at < 0; i++ {
+		for _, candidate := range operators {
+			if strings.HasPrefix(expr[i:], candidate) {
+				at, op = i, candidate
+				break
+			}
+		}
+	}
+	if at <= 0 {
+		return Condition{}, fmt.Errorf("invalid filter %q: expected <path><operator><value>", expr)
+	}
+
+	field, err := t.ParseField(strings.TrimSpace(expr[:at]))
+	if err != nil {
+		return Condition{}, err
+	}
+	cond := Condition{Field: field, Op: op}
+	raw := strings.TrimSpace(expr[at+len(op):])
+
+	switch {
+	case raw == "null" && (op == OpEq || op == OpNe):
+		cond.Op = map[string]string{OpEq: OpNull, OpNe: OpNotNull}[op]
+	case strings.HasPrefix(raw, "(") && strings.HasSuffix(raw, ")") && (op == OpEq || op == OpNe):
+		items, err := splitList(raw[1 : len(raw)-1])
+		if err != nil {
+			return Condition{}, fmt.Errorf("invalid filter %q: %v", expr, err)
+		}
+		cond.Op = map[string]string{OpEq: OpIn, OpNe: OpNotIn}[op]
+		cond.Values = items
+	case op == OpEq && !isQuoted(raw) && strings.Count(raw, "..") == 1:
+		low, high, _ := strings.Cut(raw, "..")
+		if low == "" || high == "" {
+			return Condition{}, fmt.Errorf("invalid range in filter %q", expr)
+		}
+		cond.Op = OpBetween
+		cond.Values = []string{low, high}
+	default:
+		cond.Values = []string{unquote(raw)}
+	}
+	return cond, cond.check()
+}
+
+// check는 컬럼 종류에 맞지 않는 연산자를 거부합니다
+func (c Condition) check() error {
+	if c.Field.Column == "" {
+		return nil
+	}
+	table := c.Field.table()
+	kind := table.Columns[c.Field.Column]
+	switch {
+	case (c.Op == OpNull || c.Op == OpNotNull) && !table.Nullable[c.Field.Column]:
+		return fmt.Errorf("%s is never null", c.Field.Column)
+	case c.Op == OpLike && kind != "text":
+		return fmt.Errorf("operator ~ is not supported on %s", c.Field.Column)
+	}
+	return nil
+}
+
+// splitList는 IN 목록을 쉼표로 나눕니다 (따옴표 안의 쉼표는 나누지 않음)
+func splitList(list string) ([]string, error) {
+	var items []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range list {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case r == ',' && !quoted:
+			items = append(items, unquote(strings.TrimSpace(current.String())))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	items = append(items, unquote(strings.TrimSpace(current.String())))
+	if len(items) > MaxListItems {
+		return nil, fmt.Errorf("more than %d items in list", MaxListItems)
+	}
+	return items, nil
+}
+
+func isQuoted(value string) bool {
+	return len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"'
+}
+
+func unquote(value string) string {
+	if isQuoted(value) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// SortKey는 정렬 기준 하나입니다
+type SortKey struct {
+	Field Field
+	Desc  bool
+}
+
+// String은 정렬 기준을 요청 형식으로 나타냅니다 (-updated_at)
+func (s SortKey) String() string {
+	if s.Desc {
+		return "-" + s.Field.String()
+	}
+	return s.Field.String()
+}
+
+// ParseSort는 "-updated_at,data.temp" 같은 정렬 식을 읽습니다
+func ParseSort(expr string) ([]SortKey, error) {
+	return CategoryData.ParseSort(expr)
+}
+
+// ParseSort는 테이블의 정렬 식을 읽습니다
+func (t *Table) ParseSort(expr string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key := SortKey{}
+		if strings.HasPrefix(part, "-") {
+			key.Desc = true
+			part = part[1:]
+		} else {
+			part = strings.TrimPrefix(part, "+")
+		}
+		field, err := t.ParseField(part)
+		if err != nil {
+			return nil, err
+		}
+		key.Field = field
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Query는 파싱된 필터, 정렬, 응답 필드입니다
+type Query struct {
+	Conditions []Condition
+	Sort       []SortKey
+	Fields     *Projection // nil이면 전체
+
+	IncludeArchived bool // 보관된 타겟도 포함 (include_archived 파라미터)
+}
+
+// Parse는 필터 식 목록과 정렬 식을 읽습니다
+func Parse(filters []string, sort string) (*Query, error) {
+	return CategoryData.Parse(filters, sort)
+}
+
+// Parse는 테이블의 필터 식 목록과 정렬 식을 읽습니다
+func (t *Table) Parse(filters []string, sort string) (*Query, error) {
+	if len(filters) > MaxConditions {
+		return nil, fmt.Errorf("too many filters: %d (max %d)", len(filters), MaxConditions)
+	}
+	q := &Query{}
+	for _, expr := range filters {
+		cond, err := t.ParseCondition(expr)
+		if err != nil {
+			return nil, err
+		}
+		q.Conditions = append(q.Conditions, cond)
+	}
+	var err error
+	if q.Sort, err = t.ParseSort(sort); err != nil {
+		return nil, err
+	}
+	// 컬럼 값 변환(시각, 정수) 오류도 요청을 읽을 때 알 수 있도록 한 번 만들어 봄
+	if _, err := q.Where(&Builder{}); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Strings는 조건을 문자열 목록으로 반환합니다
+func (q *Query) Strings() []string {
+	if q == nil {
+		return nil
+	}
+	out := make([]string, 0, len(q.Conditions))
+	for _, cond := range q.Conditions {
+		out = append(out, cond.String())
+	}
+	return out
+}
+
+// Key는 조건 전체를 캐시 키나 커서 지문에 쓸 수 있게 나타냅니다 (Condition.Key 참고)
+func (q *Query) Key() string {
+	if q == nil {
+		return ""
+	}
+	parts := make([]string, 0, len(q.Conditions))
+	for _, cond := range q.Conditions {
+		parts = append(parts, cond.Key())
+	}
+	return strings.Join(parts, "&")
+}
+
+// SortString은 정렬 기준을 요청 형식으로 반환합니다
+func (q *Query) SortString() string {
+	if q == nil {
+		return ""
+	}
+	parts := make([]string, 0, len(q.Sort))
+	for _, key := range q.Sort {
+		parts = append(parts, key.String())
+	}
+	return strings.Join(parts, ",")
+}
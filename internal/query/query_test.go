@@ -0,0 +1,313 @@
+package query
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		expr   string
+		field  string
+		op     string
+		values []string
+	}{
+		{"data.temp>25", "data.temp", OpGt, []string{"25"}},
+		{"data.vitals.bp>=120", "data.vitals.bp", OpGte, []string{"120"}},
+		{"status=active", "data.status", OpEq, []string{"active"}},
+		{"data.status=(active, idle)", "data.status", OpIn, []string{"active", "idle"}},
+		{`data.ward!=("A,1",B)`, "data.ward", OpNotIn, []string{"A,1", "B"}},
+		{"data.temp=20..30", "data.temp", OpBetween, []string{"20", "30"}},
+		{"data.note=null", "data.note", OpNull, nil},
+		{"data.note!=null", "data.note", OpNotNull, nil},
+		{`data.note="null"`, "data.note", OpEq, []string{"null"}},
+		{"data.name~kim", "data.name", OpLike, []string{"kim"}},
+		{"updated_at>=2025-01-01", "updated_at", OpGte, []string{"2025-01-01"}},
+	}
+	for _, tt := range tests {
+		cond, err := ParseCondition(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCondition(%q): %v", tt.expr, err)
+			continue
+		}
+		if cond.Field.String() != tt.field || cond.Op != tt.op || !reflect.DeepEqual(cond.Values, tt.values) {
+			t.Errorf("ParseCondition(%q) = %s %s %v, want %s %s %v",
+				tt.expr, cond.Field, cond.Op, cond.Values, tt.field, tt.op, tt.values)
+		}
+	}
+
+	for _, bad := range []string{"temp", ">5", "data.te'mp=1", "data.a b=1", "data.x=(a,\"b)", "updated_at=null", "data.a.b.c.d.e.f.g.h.i=1"} {
+		if _, err := ParseCondition(bad); err == nil {
+			t.Errorf("ParseCondition(%q) accepted", bad)
+		}
+	}
+}
+
+func TestConditionKey(t *testing.T) {
+	// String으로는 같아 보이는 필터도 캐시 키는 달라야 함
+	pairs := [][2]string{
+		{`data.ward=("A, B")`, `data.ward=(A, B)`},
+		{`data.code=a and b..c`, `data.code=a..b and c`},
+	}
+	for _, pair := range pairs {
+		a, errA := Parse([]string{pair[0]}, "")
+		b, errB := Parse([]string{pair[1]}, "")
+		if errA != nil || errB != nil {
+			t.Fatalf("Parse: %v, %v", errA, errB)
+		}
+		if a.Key() == b.Key() {
+			t.Errorf("%s and %s share the cache key %s", pair[0], pair[1], a.Key())
+		}
+	}
+
+	q, _ := Parse([]string{"data.temp>25", "data.status=(active,idle)"}, "")
+	if got := q.Key(); got != `data.temp > ["25"]&data.status in ["active" "idle"]` {
+		t.Errorf("Key = %s", got)
+	}
+}
+
+func TestConditionSQL(t *testing.T) {
+	tests := []struct {
+		expr string
+		sql  string
+		args []interface{}
+	}{
+		{"data.status=active", `category_data #>> '{"status"}' = $1`, []interface{}{"active"}},
+		{"data.vitals.bp>120", `CASE WHEN jsonb_typeof(category_data #> '{"vitals","bp"}') = 'number' THEN (category_data #>> '{"vitals","bp"}')::numeric END > $1::numeric`, []interface{}{"120"}},
+		{"data.temp=20..30", `CASE WHEN jsonb_typeof(category_data #> '{"temp"}') = 'number' THEN (category_data #>> '{"temp"}')::numeric END BETWEEN $1::numeric AND $2::numeric`, []interface{}{"20", "30"}},
+		{"data.code>B", `category_data #>> '{"code"}' > $1`, []interface{}{"B"}},
+		{"data.s=(a,b)", `category_data #>> '{"s"}' IN ($1, $2)`, []interface{}{"a", "b"}},
+		{"data.name~50%", `category_data #>> '{"name"}' ILIKE $1`, []interface{}{`%50\%%`}},
+		{"data.x=null", `COALESCE(jsonb_typeof(category_data #> '{"x"}'), 'null') = 'null'`, nil},
+		{"schema_version=(1,2)", `schema_version IN ($1, $2)`, []interface{}{1, 2}},
+	}
+	for _, tt := range tests {
+		cond, err := ParseCondition(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCondition(%q): %v", tt.expr, err)
+		}
+		b := &Builder{}
+		sql, err := cond.SQL(b)
+		if err != nil {
+			t.Fatalf("SQL(%q): %v", tt.expr, err)
+		}
+		if sql != tt.sql || !reflect.DeepEqual(b.Args(), tt.args) {
+			t.Errorf("SQL(%q) = %s %v\nwant %s %v", tt.expr, sql, b.Args(), tt.sql, tt.args)
+		}
+	}
+
+	cond, _ := ParseCondition("created_at>yesterday")
+	if _, err := cond.SQL(&Builder{}); err == nil {
+		t.Error("invalid time accepted")
+	}
+}
+
+func TestTable(t *testing.T) {
+	events := &Table{
+		Columns:    map[string]string{"ts": "time", "kind": "text", "closed_at": "time"},
+		Nullable:   map[string]bool{"closed_at": true},
+		DataColumn: "payload",
+	}
+	q, err := events.Parse([]string{"data.level>=3", "closed_at=null", "kind~warn"}, "-data.level")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &Builder{}
+	where, err := q.Where(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `CASE WHEN jsonb_typeof(payload #> '{"level"}') = 'number' THEN (payload #>> '{"level"}')::numeric END >= $1::numeric` +
+		` AND closed_at IS NULL AND kind::text ILIKE $2`
+	if where != want {
+		t.Errorf("Where = %s, want %s", where, want)
+	}
+	if got := q.OrderBy("ts DESC", "ts DESC"); got != `payload #> '{"level"}' DESC, ts DESC` {
+		t.Errorf("OrderBy = %s", got)
+	}
+	if _, err := events.Parse([]string{"ts=null"}, ""); err == nil {
+		t.Error("null condition accepted on a non-nullable column")
+	}
+
+	// 데이터 컬럼이 없는 테이블은 컬럼만
+	targets := &Table{Columns: map[string]string{"name": "text"}}
+	if _, err := targets.Parse([]string{"name=a"}, "name"); err != nil {
+		t.Errorf("column filter rejected: %v", err)
+	}
+	for _, bad := range []string{"data.name=a", "owner=a"} {
+		if _, err := targets.Parse([]string{bad}, ""); err == nil {
+			t.Errorf("filter %q accepted on a table without data column", bad)
+		}
+	}
+}
+
+func TestIndexExpr(t *testing.T) {
+	tests := []struct {
+		expr string
+		kind string
+		ok   bool
+	}{
+		{"data.status=active", IndexText, true},
+		{"data.s=(a,b)", IndexText, true},
+		{"data.vitals.bp>120", IndexNumber, true},
+		{"data.temp=20..30", IndexNumber, true},
+		{"data.code>B", IndexText, true},
+		{"data.name~kim", "", false},
+		{"data.x=null", "", false},
+		{"data.status!=active", "", false},
+		{"updated_at>2025-01-01", "", false},
+	}
+	for _, tt := range tests {
+		cond, err := ParseCondition(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCondition(%q): %v", tt.expr, err)
+		}
+		kind, ok := cond.IndexKind()
+		if kind != tt.kind || ok != tt.ok {
+			t.Errorf("IndexKind(%q) = %q, %t, want %q, %t", tt.expr, kind, ok, tt.kind, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		// 플래너가 인덱스를 쓰려면 조건 SQL에 인덱스 식이 그대로 들어 있어야 함
+		sql, _ := cond.SQL(&Builder{})
+		if index := IndexExpr(cond.Field.Path, kind); !strings.HasPrefix(sql, index+" ") {
+			t.Errorf("SQL(%q) = %s, does not start with index expression %s", tt.expr, sql, index)
+		}
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	q, err := Parse(nil, "-data.temp,target_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := q.OrderBy("updated_at DESC", "target_id DESC")
+	want := `category_data #> '{"temp"}' DESC, target_id, target_id DESC`
+	if got != want {
+		t.Errorf("OrderBy = %s, want %s", got, want)
+	}
+	if _, err := Parse(nil, "data.temp;drop"); err == nil {
+		t.Error("invalid sort accepted")
+	}
+}
+
+func TestProjection(t *testing.T) {
+	p, err := ParseFields("target_id, data.vitals.bp,data.vitals.hr,temperature,data.vitals.bp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Includes("target_id") || !p.Includes("data") || p.Includes("updated_at") {
+		t.Errorf("columns = %v", p.Columns)
+	}
+	want := `jsonb_build_object('temperature', category_data #> '{"temperature"}', 'vitals', ` +
+		`jsonb_build_object('bp', category_data #> '{"vitals","bp"}', 'hr', category_data #> '{"vitals","hr"}'))`
+	if got := p.DataSQL(); got != want {
+		t.Errorf("DataSQL =\n%s\nwant\n%s", got, want)
+	}
+
+	// 상위 경로를 고르면 하위 경로는 따로 만들지 않음
+	p, _ = ParseFields("data.vitals.bp,data.vitals")
+	if got := p.DataSQL(); got != `jsonb_build_object('vitals', category_data #> '{"vitals"}')` {
+		t.Errorf("DataSQL = %s", got)
+	}
+
+	if p, _ := ParseFields(""); p != nil || p.DataSQL() != "category_data" {
+		t.Error("empty fields should select everything")
+	}
+	if p, _ := ParseFields("target_id"); p.DataSQL() != "'{}'::jsonb" {
+		t.Error("data selected without being requested")
+	}
+	for _, bad := range []string{"data.a'b", "schema_version"} {
+		if _, err := ParseFields(bad); err == nil {
+			t.Errorf("ParseFields(%q) accepted", bad)
+		}
+	}
+}
+
+// 필터, 정렬, 필드에 SQL을 넣으려는 요청은 거절되거나 값이 매개변수로만 전달되어야 함
+func TestMaliciousInput(t *testing.T) {
+	for _, bad := range []string{
+		`data.a');DROP TABLE target_categories;--=1`,
+		`data.a"b=1`,
+		`data.a\b=1`,
+		`data.a/*x*/=1`,
+		`target_id;DELETE FROM target=1`,
+	} {
+		if _, err := ParseCondition(bad); err == nil {
+			t.Errorf("ParseCondition(%q) accepted", bad)
+		}
+	}
+	for _, bad := range []string{"data.temp);DROP TABLE target;--", "-data.a'b", "updated_at DESC;--"} {
+		if _, err := Parse(nil, bad); err == nil {
+			t.Errorf("sort %q accepted", bad)
+		}
+	}
+
+	values := []string{
+		`data.name='; DROP TABLE target_categories; --`,
+		`data.name=x' OR '1'='1`,
+		`data.name=("a'); DELETE FROM target; --",b)`,
+		`data.name~%' OR 1=1 --`,
+		`data.n=1; SELECT pg_sleep(10)`,
+		`target_id=' OR ''='`,
+	}
+	for _, expr := range values {
+		q, err := Parse([]string{expr}, "")
+		if err != nil {
+			continue // 거절도 안전함
+		}
+		b := &Builder{}
+		sql, err := q.Where(b)
+		if err != nil {
+			t.Fatalf("Where(%q): %v", expr, err)
+		}
+		if strings.Contains(sql, ";") || strings.Contains(sql, "--") || strings.Contains(sql, "OR") || len(b.Args()) == 0 {
+			t.Errorf("Where(%q) = %s %v: value not bound", expr, sql, b.Args())
+		}
+	}
+
+	// Field를 직접 만들어도 경로가 리터럴을 벗어나지 못함
+	cond := Condition{Field: Field{Path: []string{`a'); DROP TABLE t; --`, `"\`}}, Op: OpEq, Values: []string{"1"}}
+	sql, err := cond.SQL(&Builder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `category_data #>> '{"a''); DROP TABLE t; --","\"\\"}' = $1`; sql != want {
+		t.Errorf("SQL = %s, want %s", sql, want)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	q, err := Parse([]string{"data.temp>25"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewSelect("target_id", "target_categories")
+	s.Where(s.Eq("org_id", "org"), "")
+	if err := s.Filter(q); err != nil {
+		t.Fatal(err)
+	}
+	sql, args := s.OrderBy("target_id").Limit(10).Offset(20).SQL()
+	want := `SELECT target_id FROM target_categories WHERE org_id = $1 AND ` +
+		`CASE WHEN jsonb_typeof(category_data #> '{"temp"}') = 'number' THEN (category_data #>> '{"temp"}')::numeric END > $2::numeric ` +
+		`ORDER BY target_id LIMIT $3 OFFSET $4`
+	if sql != want || !reflect.DeepEqual(args, []interface{}{"org", "25", 10, 20}) {
+		t.Errorf("SQL =\n%s %v\nwant\n%s", sql, args, want)
+	}
+
+	u := NewUpdate("users").Set("role", "viewer").Set("is_active", false)
+	sql, args = u.Where(u.Eq("user_id", "u1")).SQL()
+	if sql != "UPDATE users SET role = $1, is_active = $2 WHERE user_id = $3" || len(args) != 3 {
+		t.Errorf("UPDATE = %s %v", sql, args)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("invalid column name accepted")
+		}
+	}()
+	NewUpdate("users").Set("role = 'admin', name", "x")
+}
@@ -0,0 +1,281 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dataColumn 데이터 필드가 들어 있는 JSONB 컬럼
+const dataColumn = "category_data"
+
+// Builder는 SQL 매개변수를 모으고 $1, $2... 자리표시자를 만듭니다
+type Builder struct {
+	args []interface{}
+}
+
+// Arg는 값을 매개변수로 추가하고 자리표시자를 반환합니다
+func (b *Builder) Arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+// Args는 지금까지 추가한 매개변수입니다
+func (b *Builder) Args() []interface{} {
+	return b.args
+}
+
+// Where는 모든 조건을 AND로 묶은 SQL을 반환합니다 (조건이 없으면 빈 문자열)
+func (q *Query) Where(b *Builder) (string, error) {
+	if q == nil {
+		return "", nil
+	}
+	parts := make([]string, 0, len(q.Conditions))
+	for _, cond := range q.Conditions {
+		sql, err := cond.SQL(b)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, sql)
+	}
+	return strings.Join(parts, " AND "), nil
+}
+
+// OrderBy는 ORDER BY 목록을 반환합니다. 정렬이 없으면 defaultOrder를 쓰고,
+// 결과 순서가 항상 같도록 tieBreaker를 마지막에 붙입니다.
+func (q *Query) OrderBy(defaultOrder, tieBreaker string) string {
+	if q == nil || len(q.Sort) == 0 {
+		return defaultOrder
+	}
+	parts := make([]string, 0, len(q.Sort)+1)
+	for _, key := range q.Sort {
+		expr := key.Expr()
+		if key.Desc {
+			expr += " DESC"
+		}
+		parts = append(parts, expr)
+	}
+	return strings.Join(append(parts, tieBreaker), ", ")
+}
+
+// Expr은 정렬 기준의 SQL 식입니다 (컬럼이거나 데이터 필드의 JSONB 값)
+func (s SortKey) Expr() string {
+	if s.Field.Column != "" {
+		return s.Field.Column
+	}
+	return s.Field.jsonExpr()
+}
+
+// Nullable은 정렬 식이 NULL일 수 있는지입니다 (데이터 필드는 경로가 없으면 NULL)
+func (s SortKey) Nullable() bool {
+	return s.Field.Column == "" || s.Field.table().Nullable[s.Field.Column]
+}
+
+// DataSelect는 category_data 자리에 선택할 식입니다 (응답 필드를 골랐으면 그 경로만)
+func (q *Query) DataSelect() string {
+	if q == nil {
+		return dataColumn
+	}
+	return q.Fields.DataSQL()
+}
+
+// SQL은 조건 하나를 SQL로 바꿉니다
+func (c Condition) SQL(b *Builder) (string, error) {
+	if c.Field.Column != "" {
+		return c.columnSQL(b)
+	}
+
+	text, value := c.Field.textExpr(), c.Field.jsonExpr()
+	switch c.Op {
+	case OpNull:
+		return fmt.Sprintf("COALESCE(jsonb_typeof(%s), 'null') = 'null'", value), nil
+	case OpNotNull:
+		return fmt.Sprintf("COALESCE(jsonb_typeof(%s), 'null') <> 'null'", value), nil
+	case OpEq:
+		return fmt.Sprintf("%s = %s", text, b.Arg(c.Values[0])), nil
+	case OpNe:
+		return fmt.Sprintf("%s IS DISTINCT FROM %s", text, b.Arg(c.Values[0])), nil
+	case OpLike:
+		return fmt.Sprintf("%s ILIKE %s", text, b.Arg("%"+escapeLike(c.Values[0])+"%")), nil
+	case OpIn:
+		return fmt.Sprintf("%s IN (%s)", text, placeholders(b, c.Values)), nil
+	case OpNotIn:
+		return fmt.Sprintf("(%s IS NULL OR %s NOT IN (%s))", text, text, placeholders(b, c.Values)), nil
+	}
+
+	// 크기 비교: 값이 숫자면 숫자 필드끼리, 아니면 문자열로 비교 (ISO 8601 시각은 문자열 비교로 충분)
+	if allNumbers(c.Values) {
+		return comparison(b, c.Field.numberExpr(), c.Op, c.Values, "::numeric"), nil
+	}
+	return comparison(b, text, c.Op, c.Values, ""), nil
+}
+
+// columnSQL은 컬럼 조건을 SQL로 바꿉니다. 값은 컬럼 종류에 맞게 변환합니다.
+func (c Condition) columnSQL(b *Builder) (string, error) {
+	column, kind := c.Field.Column, c.Field.table().Columns[c.Field.Column]
+	switch c.Op {
+	case OpNull:
+		return column + " IS NULL", nil
+	case OpNotNull:
+		return column + " IS NOT NULL", nil
+	}
+	values := make([]interface{}, len(c.Values))
+	for i, raw := range c.Values {
+		switch kind {
+		case "time":
+			t, err := parseTime(raw)
+			if err != nil {
+				return "", fmt.Errorf("%s: %v", column, err)
+			}
+			values[i] = t
+		case "number":
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				return "", fmt.Errorf("%s must be an integer: %q", column, raw)
+			}
+			values[i] = n
+		default:
+			values[i] = raw
+		}
+	}
+	if kind == "text" {
+		column += "::text" // target_id는 UUID
+	}
+
+	switch c.Op {
+	case OpNe:
+		return fmt.Sprintf("%s <> %s", column, b.Arg(values[0])), nil
+	case OpLike:
+		return fmt.Sprintf("%s ILIKE %s", column, b.Arg("%"+escapeLike(c.Values[0])+"%")), nil
+	case OpIn, OpNotIn:
+		list := make([]string, len(values))
+		for i, v := range values {
+			list[i] = b.Arg(v)
+		}
+		return fmt.Sprintf("%s %s (%s)", column, strings.ToUpper(c.Op), strings.Join(list, ", ")), nil
+	case OpBetween:
+		return fmt.Sprintf("%s BETWEEN %s AND %s", column, b.Arg(values[0]), b.Arg(values[1])), nil
+	default:
+		return fmt.Sprintf("%s %s %s", column, c.Op, b.Arg(values[0])), nil
+	}
+}
+
+// comparison은 크기 비교나 범위 조건을 만듭니다
+func comparison(b *Builder, expr, op string, values []string, cast string) string {
+	if op == OpBetween {
+		return fmt.Sprintf("%s BETWEEN %s%s AND %s%s", expr, b.Arg(values[0]), cast, b.Arg(values[1]), cast)
+	}
+	return fmt.Sprintf("%s %s %s%s", expr, op, b.Arg(values[0]), cast)
+}
+
+// jsonPath는 경로를 PostgreSQL 텍스트 배열 리터럴로 만듭니다 ('{"vitals","bp"}').
+// 경로 단계는 ParseField에서 영문자, 숫자, _, -만 허용하지만, Field를 직접 만든 경우에도
+// 문을 벗어나지 못하도록 배열 원소와 문자열 리터럴의 특수 문자를 한 번 더 이스케이프합니다.
+// 각 단계를 따옴표로 감싸 NULL 같은 이름도 문자열로 읽힙니다.
+func jsonPath(path []string) string {
+	quoted := make([]string, len(path))
+	for i, segment := range path {
+		quoted[i] = pathEscaper.Replace(segment)
+	}
+	return `'{"` + strings.Join(quoted, `","`) + `"}'`
+}
+
+// pathEscaper 배열 원소 안의 \와 ", SQL 문자열 안의 '
+var pathEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `'`, `''`)
+
+// jsonExpr은 경로의 JSONB 값, textExpr은 텍스트 값입니다
+func jsonExpr(column string, path []string) string {
+	return column + " #> " + jsonPath(path)
+}
+
+func textExpr(column string, path []string) string {
+	return column + " #>> " + jsonPath(path)
+}
+
+// numberExpr은 숫자인 값만 numeric으로 바꾼 식입니다 (숫자가 아니면 NULL이라 변환 오류가 나지 않음)
+func numberExpr(column string, path []string) string {
+	return fmt.Sprintf("CASE WHEN jsonb_typeof(%s) = 'number' THEN (%s)::numeric END", jsonExpr(column, path), textExpr(column, path))
+}
+
+// jsonExpr, textExpr, numberExpr 메서드는 필드가 속한 테이블의 데이터 컬럼으로 식을 만듭니다
+func (f Field) jsonExpr() string {
+	return jsonExpr(f.table().DataColumn, f.Path)
+}
+
+func (f Field) textExpr() string {
+	return textExpr(f.table().DataColumn, f.Path)
+}
+
+func (f Field) numberExpr() string {
+	return numberExpr(f.table().DataColumn, f.Path)
+}
+
+// 인덱스 식의 종류
+const (
+	IndexText   = "text"
+	IndexNumber = "number"
+)
+
+// IndexKind는 조건이 B-tree 식 인덱스를 쓸 수 있으면 그 식의 종류를 반환합니다.
+// 같음, IN과 크기 비교만 해당하고 부분 일치(~), null, 부정 조건은 인덱스를 쓰지 못합니다.
+func (c Condition) IndexKind() (string, bool) {
+	if c.Field.Column != "" {
+		return "", false
+	}
+	switch c.Op {
+	case OpEq, OpIn:
+		return IndexText, true
+	case OpGt, OpGte, OpLt, OpLte, OpBetween:
+		if allNumbers(c.Values) {
+			return IndexNumber, true
+		}
+		return IndexText, true
+	}
+	return "", false
+}
+
+// IndexExpr은 조건이 만드는 것과 같은 식을 반환합니다. 이 식으로 만든 인덱스를 플래너가 필터에 씁니다.
+func IndexExpr(path []string, kind string) string {
+	if kind == IndexNumber {
+		return numberExpr(dataColumn, path)
+	}
+	return textExpr(dataColumn, path)
+}
+
+func placeholders(b *Builder, values []string) string {
+	list := make([]string, len(values))
+	for i, v := range values {
+		list[i] = b.Arg(v)
+	}
+	return strings.Join(list, ", ")
+}
+
+// numberPattern 숫자로 비교할 값 (PostgreSQL numeric으로 변환할 수 있는 형식만)
+var numberPattern = regexp.MustCompile(`^-?\d+(\.\d+)?([eE][-+]?\d+)?$`)
+
+func allNumbers(values []string) bool {
+	for _, v := range values {
+		if !numberPattern.MatchString(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// escapeLike는 ILIKE 패턴의 특수 문자를 이스케이프합니다
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// parseTime은 RFC 3339 시각이나 날짜(2025-01-02)를 읽습니다
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use RFC 3339 or YYYY-MM-DD)", value)
+}
@@ -0,0 +1,161 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// identPattern 문에 직접 들어가는 컬럼 이름 (소문자 SQL 식별자만)
+var identPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// mustIdent는 컬럼 이름을 확인합니다. 컬럼 이름은 코드에 적힌 상수만 쓰므로
+// 식별자가 아니면 요청 값이 섞인 것으로 보고 panic합니다.
+func mustIdent(column string) string {
+	if !identPattern.MatchString(column) {
+		panic(fmt.Sprintf("query: invalid column name %q", column))
+	}
+	return column
+}
+
+// Eq는 "column = $n" 조건을 만듭니다
+func (b *Builder) Eq(column string, value interface{}) string {
+	return mustIdent(column) + " = " + b.Arg(value)
+}
+
+// Select는 SELECT 문을 조립합니다. 요청에서 온 값은 모두 Arg로 매개변수가 되고,
+// 문에 직접 들어가는 것은 코드에 적힌 SQL 조각과 ParseField로 검증한 필드 경로뿐입니다.
+type Select struct {
+	Builder
+	columns    string
+	from       string
+	conditions []string
+	groupBy    string
+	orderBy    string
+	limit      string
+	offset     string
+}
+
+// NewSelect는 from에서 columns를 읽는 SELECT 문을 시작합니다
+func NewSelect(columns, from string) *Select {
+	return &Select{columns: columns, from: from}
+}
+
+// Columns는 읽을 컬럼을 바꿉니다. 컬럼 식에 매개변수가 있으면 Arg 다음에 정합니다.
+func (s *Select) Columns(columns string) *Select {
+	s.columns = columns
+	return s
+}
+
+// Where는 조건을 AND로 더합니다. 빈 조건은 건너뜁니다.
+func (s *Select) Where(conditions ...string) *Select {
+	for _, cond := range conditions {
+		if cond != "" {
+			s.conditions = append(s.conditions, cond)
+		}
+	}
+	return s
+}
+
+// Filter는 필터 조건을 더합니다
+func (s *Select) Filter(q *Query) error {
+	where, err := q.Where(&s.Builder)
+	if err != nil {
+		return err
+	}
+	s.Where(where)
+	return nil
+}
+
+// GroupBy는 묶을 식을 정합니다
+func (s *Select) GroupBy(expr string) *Select {
+	s.groupBy = expr
+	return s
+}
+
+// OrderBy는 정렬 순서를 정합니다 (Query.OrderBy 결과나 코드에 적힌 순서)
+func (s *Select) OrderBy(order string) *Select {
+	s.orderBy = order
+	return s
+}
+
+// Limit과 Offset은 값을 매개변수로 넣습니다
+func (s *Select) Limit(n int) *Select {
+	s.limit = s.Arg(n)
+	return s
+}
+
+func (s *Select) Offset(n int) *Select {
+	s.offset = s.Arg(n)
+	return s
+}
+
+// Conditions는 WHERE 뒤에 올 조건입니다 (조건이 없으면 TRUE). 같은 조건으로 다른 문을
+// 만들 때 Args와 함께 씁니다.
+func (s *Select) Conditions() string {
+	if len(s.conditions) == 0 {
+		return "TRUE"
+	}
+	return strings.Join(s.conditions, " AND ")
+}
+
+// SQL은 완성된 문과 매개변수를 반환합니다
+func (s *Select) SQL() (string, []interface{}) {
+	var sb strings.Builder
+	sb.WriteString("SELECT " + s.columns + " FROM " + s.from)
+	if len(s.conditions) > 0 {
+		sb.WriteString(" WHERE " + s.Conditions())
+	}
+	if s.groupBy != "" {
+		sb.WriteString(" GROUP BY " + s.groupBy)
+	}
+	if s.orderBy != "" {
+		sb.WriteString(" ORDER BY " + s.orderBy)
+	}
+	if s.limit != "" {
+		sb.WriteString(" LIMIT " + s.limit)
+	}
+	if s.offset != "" {
+		sb.WriteString(" OFFSET " + s.offset)
+	}
+	return sb.String(), s.Args()
+}
+
+// Update는 바꿀 컬럼이 요청에 따라 달라지는 UPDATE 문을 조립합니다
+type Update struct {
+	Builder
+	table      string
+	sets       []string
+	conditions []string
+}
+
+// NewUpdate는 table의 UPDATE 문을 시작합니다
+func NewUpdate(table string) *Update {
+	return &Update{table: table}
+}
+
+// Set은 column을 value로 바꿉니다
+func (u *Update) Set(column string, value interface{}) *Update {
+	u.sets = append(u.sets, u.Eq(column, value))
+	return u
+}
+
+// Where는 조건을 AND로 더합니다
+func (u *Update) Where(conditions ...string) *Update {
+	u.conditions = append(u.conditions, conditions...)
+	return u
+}
+
+// Empty는 바꿀 컬럼이 없는지 확인합니다
+func (u *Update) Empty() bool {
+	return len(u.sets) == 0
+}
+
+// SQL은 완성된 문과 매개변수를 반환합니다. 조건 없는 UPDATE는 만들지 않습니다.
+func (u *Update) SQL() (string, []interface{}) {
+	if len(u.conditions) == 0 {
+		panic("query: UPDATE without WHERE")
+	}
+	return "UPDATE " + mustIdent(u.table) + " SET " + strings.Join(u.sets, ", ") +
+		" WHERE " + strings.Join(u.conditions, " AND "), u.Args()
+}
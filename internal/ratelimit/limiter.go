@@ -0,0 +1,287 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/config"
+)
+
+// Rule은 초당 요청 수와 버스트(한 번에 몰아서 보낼 수 있는 요청 수)입니다.
+// Rate가 0 이하면 제한하지 않고, Burst가 0이면 Rate를 올림한 값을 씁니다.
+type Rule struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// Enabled는 제한이 켜져 있는지 확인합니다
+func (r Rule) Enabled() bool {
+	return r.Rate > 0
+}
+
+// interval은 요청 하나가 차지하는 시간입니다
+func (r Rule) interval() time.Duration {
+	return time.Duration(float64(time.Second) / r.Rate)
+}
+
+func (r Rule) burst() int {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+	return int(math.Max(1, math.Ceil(r.Rate)))
+}
+
+// Decision은 요청 하나에 대한 판정입니다
+type Decision struct {
+	Allowed    bool
+	Limit      int           // 버스트 또는 일일 할당량
+	Remaining  int64         // 지금 바로 더 보낼 수 있는 요청(레코드) 수
+	RetryAfter time.Duration // 거부된 경우 다시 시도할 수 있을 때까지의 시간
+}
+
+// gcra는 GCRA(generic cell rate algorithm)로 요청 하나를 허용할지 정합니다.
+// tat는 이론상 다음 요청 도착 시각(유닉스 나노초)이며, 허용하면 갱신된 값을 반환합니다.
+func gcra(tat, now int64, interval time.Duration, burst int) (int64, Decision) {
+	step := int64(interval)
+	if tat < now {
+		tat = now
+	}
+	newTAT := tat + step
+	allowAt := newTAT - step*int64(burst)
+	if allowAt > now {
+		return tat, Decision{Limit: burst, RetryAfter: time.Duration(allowAt - now)}
+	}
+	return newTAT, Decision{Allowed: true, Limit: burst, Remaining: (now - allowAt) / step}
+}
+
+// Counter는 이 인스턴스에서 허용/거부한 요청 수입니다
+type Counter struct {
+	OrgID         string     `json:"org_id,omitempty"`
+	Allowed       int64      `json:"allowed"`
+	Limited       int64      `json:"limited"`
+	LastLimitedAt *time.Time `json:"last_limited_at,omitempty"`
+}
+
+func (c *Counter) record(d Decision, now time.Time) {
+	if d.Allowed {
+		c.Allowed++
+		return
+	}
+	c.Limited++
+	c.LastLimitedAt = &now
+}
+
+// QuotaUsage는 조직의 오늘(UTC) 수집량입니다
+type QuotaUsage struct {
+	OrgID     string    `json:"org_id"`
+	Date      string    `json:"date"`
+	Used      int64     `json:"used"`
+	Limit     int64     `json:"limit"`               // 0이면 무제한
+	Remaining *int64    `json:"remaining,omitempty"` // 무제한이면 없음
+	ResetAt   time.Time `json:"reset_at"`
+}
+
+// Stats는 이 인스턴스의 레이트 리밋 통계입니다. 할당량 사용량은 저장소(공유 가능)에 있습니다.
+type Stats struct {
+	Tokens        map[string]Counter `json:"tokens"`
+	IP            Counter            `json:"ip"`
+	QuotaRejected map[string]int64   `json:"quota_rejected"`
+	StoreErrors   int64              `json:"store_errors"`
+}
+
+// Limiter는 토큰별/IP별 요청 속도와 조직별 일일 수집량을 제한합니다.
+type Limiter struct {
+	store          Store
+	token          Rule
+	ip             Rule
+	quota          int64
+	quotaOverrides map[string]int64
+	now            func() time.Time
+
+	mu    sync.Mutex
+	stats Stats
+}
+
+// OpenStore는 설정(RATE_LIMIT_STORE)에 맞는 저장소를 엽니다
+func OpenStore(cfg *config.Config) (Store, error) {
+	switch cfg.RateLimitStore {
+	case "", "memory":
+		return NewMemoryStore(), nil
+	case "nats":
+		return NewNATSStore(cfg.NatsURL)
+	default:
+		return nil, fmt.Errorf("알 수 없는 RATE_LIMIT_STORE: %s (memory 또는 nats)", cfg.RateLimitStore)
+	}
+}
+
+// New는 설정의 레이트 리밋과 할당량으로 Limiter를 만듭니다
+func New(cfg *config.Config, store Store) *Limiter {
+	return &Limiter{
+		store:          store,
+		token:          Rule{Rate: cfg.RateLimitTokenRPS, Burst: cfg.RateLimitTokenBurst},
+		ip:             Rule{Rate: cfg.RateLimitIPRPS, Burst: cfg.RateLimitIPBurst},
+		quota:          cfg.IngestDailyQuota,
+		quotaOverrides: cfg.IngestQuotaOverrides,
+		now:            time.Now,
+		stats: Stats{
+			Tokens:        make(map[string]Counter),
+			QuotaRejected: make(map[string]int64),
+		},
+	}
+}
+
+// TokenRule과 IPRule은 설정된 제한입니다
+func (l *Limiter) TokenRule() Rule { return l.token }
+func (l *Limiter) IPRule() Rule    { return l.ip }
+
+// AllowToken은 토큰의 요청 하나를 허용할지 정합니다
+func (l *Limiter) AllowToken(tokenID, orgID string) (Decision, error) {
+	d, err := l.allow("token:"+tokenID, l.token)
+	l.mu.Lock()
+	counter := l.stats.Tokens[tokenID]
+	counter.OrgID = orgID
+	counter.record(d, l.now())
+	l.stats.Tokens[tokenID] = counter
+	l.mu.Unlock()
+	return d, err
+}
+
+// AllowIP는 클라이언트 IP의 요청 하나를 허용할지 정합니다
+func (l *Limiter) AllowIP(ip string) (Decision, error) {
+	d, err := l.allow("ip:"+ip, l.ip)
+	l.mu.Lock()
+	l.stats.IP.record(d, l.now())
+	l.mu.Unlock()
+	return d, err
+}
+
+// allow는 저장소의 GCRA 상태로 판정합니다. 저장소 오류가 나면 요청을 막지 않고 오류를 함께 반환합니다.
+func (l *Limiter) allow(key string, rule Rule) (Decision, error) {
+	if !rule.Enabled() {
+		return Decision{Allowed: true}, nil
+	}
+	interval, burst := rule.interval(), rule.burst()
+	now := l.now().UnixNano()
+
+	var d Decision
+	_, err := l.store.Update(key, interval*time.Duration(burst)+time.Second, func(tat int64) (int64, bool) {
+		var next int64
+		next, d = gcra(tat, now, interval, burst)
+		return next, d.Allowed
+	})
+	if err != nil {
+		l.storeError()
+		return Decision{Allowed: true, Limit: burst}, err
+	}
+	return d, nil
+}
+
+// Quota는 조직의 일일 수집 할당량입니다 (0이면 무제한)
+func (l *Limiter) Quota(orgID string) int64 {
+	if quota, ok := l.quotaOverrides[orgID]; ok {
+		return quota
+	}
+	return l.quota
+}
+
+// quotaKey는 조직의 UTC 날짜별 수집량 키입니다
+func quotaKey(orgID string, day time.Time) string {
+	return "quota:" + orgID + ":" + day.Format("2006-01-02")
+}
+
+// Usage는 조직의 오늘 수집량을 반환합니다
+func (l *Limiter) Usage(orgID string) (QuotaUsage, error) {
+	now := l.now().UTC()
+	day := now.Truncate(24 * time.Hour)
+	usage := QuotaUsage{
+		OrgID:   orgID,
+		Date:    day.Format("2006-01-02"),
+		Limit:   l.Quota(orgID),
+		ResetAt: day.Add(24 * time.Hour),
+	}
+	used, err := l.store.Get(quotaKey(orgID, day))
+	if err != nil {
+		l.storeError()
+		return usage, err
+	}
+	usage.Used = used
+	if usage.Limit > 0 {
+		remaining := usage.Limit - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		usage.Remaining = &remaining
+	}
+	return usage, nil
+}
+
+// CheckQuota는 조직이 오늘 할당량을 다 썼는지 확인합니다.
+// 요청 하나가 할당량을 조금 넘길 수는 있고, 넘긴 뒤의 요청은 다음 날(UTC)까지 거부됩니다.
+func (l *Limiter) CheckQuota(orgID string) (Decision, error) {
+	quota := l.Quota(orgID)
+	if quota <= 0 {
+		return Decision{Allowed: true}, nil
+	}
+	usage, err := l.Usage(orgID)
+	if err != nil {
+		return Decision{Allowed: true, Limit: int(quota)}, err
+	}
+	d := Decision{Allowed: usage.Used < quota, Limit: int(quota), Remaining: *usage.Remaining}
+	if !d.Allowed {
+		d.RetryAfter = usage.ResetAt.Sub(l.now())
+		l.mu.Lock()
+		l.stats.QuotaRejected[orgID]++
+		l.mu.Unlock()
+	}
+	return d, nil
+}
+
+// AddIngested는 조직의 오늘 수집량에 레코드 수를 더합니다 (할당량이 없어도 사용량은 기록)
+func (l *Limiter) AddIngested(orgID string, records int64) error {
+	if records <= 0 {
+		return nil
+	}
+	now := l.now().UTC()
+	day := now.Truncate(24 * time.Hour)
+	// 키는 다음 날까지 남겨 자정 직후에도 어제 사용량을 볼 수 있게 함
+	ttl := day.Add(48 * time.Hour).Sub(now)
+	_, err := l.store.Update(quotaKey(orgID, day), ttl, func(used int64) (int64, bool) {
+		return used + records, true
+	})
+	if err != nil {
+		l.storeError()
+	}
+	return err
+}
+
+// Stats는 이 인스턴스의 통계를 반환합니다. orgID를 주면 그 조직의 토큰만 포함합니다.
+func (l *Limiter) Stats(orgID string) Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := Stats{
+		Tokens:        make(map[string]Counter),
+		IP:            l.stats.IP,
+		QuotaRejected: make(map[string]int64),
+		StoreErrors:   l.stats.StoreErrors,
+	}
+	for tokenID, counter := range l.stats.Tokens {
+		if orgID == "" || counter.OrgID == orgID {
+			stats.Tokens[tokenID] = counter
+		}
+	}
+	for org, rejected := range l.stats.QuotaRejected {
+		if orgID == "" || org == orgID {
+			stats.QuotaRejected[org] = rejected
+		}
+	}
+	return stats
+}
+
+func (l *Limiter) storeError() {
+	l.mu.Lock()
+	l.stats.StoreErrors++
+	l.mu.Unlock()
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/config"
+)
+
+func TestGCRABurstAndRetryAfter(t *testing.T) {
+	now := time.Now().UnixNano()
+	interval := 100 * time.Millisecond // 10 req/s
+	var tat int64
+
+	for i := 0; i < 3; i++ {
+		var d Decision
+		tat, d = gcra(tat, now, interval, 3)
+		if !d.Allowed {
+			t.Fatalf("request %d within burst rejected", i+1)
+		}
+		if d.Remaining != int64(2-i) {
+			t.Errorf("request %d: remaining = %d, want %d", i+1, d.Remaining, 2-i)
+		}
+	}
+
+	_, d := gcra(tat, now, interval, 3)
+	if d.Allowed {
+		t.Fatal("request over burst allowed")
+	}
+	if d.RetryAfter != interval {
+		t.Errorf("retry after = %v, want %v", d.RetryAfter, interval)
+	}
+
+	if _, d = gcra(tat, now+int64(interval), interval, 3); !d.Allowed {
+		t.Error("request after retry-after rejected")
+	}
+}
+
+func TestDailyQuota(t *testing.T) {
+	cfg := &config.Config{IngestDailyQuota: 10, IngestQuotaOverrides: map[string]int64{"big": 0}}
+	l := New(cfg, NewMemoryStore())
+	now := time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	if err := l.AddIngested("org", 12); err != nil {
+		t.Fatal(err)
+	}
+	d, err := l.CheckQuota("org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Allowed || d.RetryAfter != time.Hour {
+		t.Errorf("over quota: allowed = %v, retry after = %v; want rejected for 1h", d.Allowed, d.RetryAfter)
+	}
+	if d, _ := l.CheckQuota("big"); !d.Allowed {
+		t.Error("org with unlimited override rejected")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if d, _ := l.CheckQuota("org"); !d.Allowed {
+		t.Error("quota not reset on the next UTC day")
+	}
+}
@@ -0,0 +1,174 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/tmidb/tmidb-core/internal/cache"
+)
+
+// Store는 레이트 리밋 카운터를 보관합니다. 값은 int64 하나(GCRA 시각 또는 누적 카운트)입니다.
+type Store interface {
+	// Update는 키의 현재 값(없으면 0)을 fn에 넘기고, fn이 저장하라고 하면 새 값을 원자적으로 저장합니다.
+	// 반환값은 저장 후의 값(저장하지 않았으면 현재 값)입니다.
+	Update(key string, ttl time.Duration, fn func(current int64) (int64, bool)) (int64, error)
+	// Get은 키의 현재 값을 반환합니다 (없으면 0)
+	Get(key string) (int64, error)
+}
+
+// MemoryStore는 인스턴스 하나에서만 쓰는 메모리 저장소입니다.
+type MemoryStore struct {
+	cache *cache.MemoryCache
+}
+
+// NewMemoryStore는 메모리 저장소를 만듭니다
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{cache: cache.NewMemoryCache(100000, time.Hour)}
+}
+
+// Update는 카운터를 원자적으로 갱신합니다
+func (s *MemoryStore) Update(key string, ttl time.Duration, fn func(current int64) (int64, bool)) (int64, error) {
+	var result int64
+	s.cache.Update(key, ttl, func(current interface{}, exists bool) (interface{}, bool) {
+		result, _ = current.(int64)
+		next, store := fn(result)
+		if store {
+			result = next
+		}
+		return next, store
+	})
+	return result, nil
+}
+
+// Get은 카운터를 읽습니다
+func (s *MemoryStore) Get(key string) (int64, error) {
+	value, _ := s.cache.Get(key)
+	current, _ := value.(int64)
+	return current, nil
+}
+
+// NATS KV 저장소 설정
+const (
+	kvBucket      = "tmidb_ratelimit"
+	kvMaxAge      = 48 * time.Hour // 일일 할당량 키가 다음 날까지 남도록
+	kvTimeout     = 2 * time.Second
+	kvMaxAttempts = 5 // 다른 인스턴스와 동시에 갱신할 때 재시도 횟수
+)
+
+// kvInvalidKeyChars NATS KV 키에 쓸 수 없는 문자 (IPv6 주소의 ':' 등)
+var kvInvalidKeyChars = regexp.MustCompile(`[^-/_=.a-zA-Z0-9]`)
+
+// NATSStore는 여러 API 인스턴스가 카운터를 공유하도록 NATS JetStream KV 버킷을 씁니다.
+// 키별 TTL 대신 버킷의 MaxAge(48시간)가 오래된 키를 지웁니다.
+type NATSStore struct {
+	conn *nats.Conn
+	kv   jetstream.KeyValue
+}
+
+// NewNATSStore는 NATS에 연결하고 레이트 리밋 KV 버킷을 만들거나 엽니다
+func NewNATSStore(url string) (*NATSStore, error) {
+	nc, err := nats.Connect(url, nats.Name("tmidb-ratelimit"))
+	if err != nil {
+		return nil, fmt.Errorf("NATS 연결 실패: %v", err)
+	}
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("JetStream 초기화 실패: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      kvBucket,
+		Description: "tmiDB rate limit and ingest quota counters",
+		TTL:         kvMaxAge,
+		History:     1,
+	})
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("레이트 리밋 KV 버킷 생성 실패: %v", err)
+	}
+	return &NATSStore{conn: nc, kv: kv}, nil
+}
+
+// Update는 리비전 비교(CAS)로 카운터를 갱신하고, 다른 인스턴스와 겹치면 다시 시도합니다
+func (s *NATSStore) Update(key string, ttl time.Duration, fn func(current int64) (int64, bool)) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kvTimeout)
+	defer cancel()
+	key = kvKey(key)
+
+	for attempt := 0; attempt < kvMaxAttempts; attempt++ {
+		var current int64
+		var revision uint64
+		entry, err := s.kv.Get(ctx, key)
+		switch {
+		case errors.Is(err, jetstream.ErrKeyNotFound):
+		case err != nil:
+			return 0, err
+		default:
+			current, revision = decodeValue(entry.Value()), entry.Revision()
+		}
+
+		next, store := fn(current)
+		if !store {
+			return current, nil
+		}
+		if revision == 0 {
+			_, err = s.kv.Create(ctx, key, encodeValue(next))
+		} else {
+			_, err = s.kv.Update(ctx, key, encodeValue(next), revision)
+		}
+		if err == nil {
+			return next, nil
+		}
+		// 다른 인스턴스가 먼저 갱신함 (ErrKeyExists와 잘못된 리비전은 같은 오류 코드)
+		var apiErr *jetstream.APIError
+		if !errors.Is(err, jetstream.ErrKeyExists) &&
+			!(errors.As(err, &apiErr) && apiErr.ErrorCode == jetstream.JSErrCodeStreamWrongLastSequence) {
+			return 0, err
+		}
+	}
+	return 0, fmt.Errorf("레이트 리밋 카운터 갱신 충돌: %s", key)
+}
+
+// Get은 카운터를 읽습니다
+func (s *NATSStore) Get(key string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kvTimeout)
+	defer cancel()
+	entry, err := s.kv.Get(ctx, kvKey(key))
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return decodeValue(entry.Value()), nil
+}
+
+// Close는 NATS 연결을 닫습니다
+func (s *NATSStore) Close() {
+	s.conn.Close()
+}
+
+// kvKey는 키를 NATS KV에서 쓸 수 있는 형태로 바꿉니다 (예: IPv6 주소의 ":" → "_")
+func kvKey(key string) string {
+	return kvInvalidKeyChars.ReplaceAllString(key, "_")
+}
+
+func encodeValue(v int64) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(v))
+}
+
+func decodeValue(b []byte) int64 {
+	if len(b) != 8 {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(b))
+}
@@ -0,0 +1,35 @@
+package schema
+
+import "sync"
+
+// maxCachedSchemas 캐시에 보관하는 스키마 정의 최대 개수
+const maxCachedSchemas = 256
+
+var (
+	cacheMux sync.Mutex
+	cache    = make(map[string]*Schema)
+)
+
+// Cached 스키마 정의(JSON)를 컴파일하고 결과를 정의 문자열 기준으로 캐시
+// 메시지마다 같은 카테고리 스키마를 다시 컴파일하지 않도록 수집 경로에서 사용
+func Cached(definition string) (*Schema, error) {
+	cacheMux.Lock()
+	s, ok := cache[definition]
+	cacheMux.Unlock()
+	if ok {
+		return s, nil
+	}
+
+	s, err := CompileJSON([]byte(definition))
+	if err != nil {
+		return nil, err
+	}
+
+	cacheMux.Lock()
+	if len(cache) >= maxCachedSchemas {
+		cache = make(map[string]*Schema)
+	}
+	cache[definition] = s
+	cacheMux.Unlock()
+	return s, nil
+}
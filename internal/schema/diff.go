@@ -0,0 +1,374 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 스키마 비교 결과의 변경 종류
+const (
+	DiffFieldAdded        = "field_added"
+	DiffFieldRemoved      = "field_removed"
+	DiffRequiredAdded     = "required_added"
+	DiffRequiredRemoved   = "required_removed"
+	DiffTypeChanged       = "type_changed"
+	DiffEnumChanged       = "enum_changed"
+	DiffConstraintChanged = "constraint_changed"
+	DiffAdditionalFields  = "additional_fields_changed"
+)
+
+// Difference 두 스키마 버전 사이의 변경 하나
+type Difference struct {
+	Path     string `json:"path"` // 필드 위치 (JSON Pointer, 배열 항목은 /*, 루트는 "")
+	Kind     string `json:"kind"`
+	Breaking bool   `json:"breaking"` // 이전 버전에 맞던 데이터가 거부될 수 있음
+	Message  string `json:"message"`
+}
+
+// Diff 스키마 비교 결과
+type Diff struct {
+	Compatible bool         `json:"compatible"` // 깨지는 변경이 없음
+	Changes    []Difference `json:"changes"`
+}
+
+// Breaking 깨지는 변경만 반환
+func (d *Diff) Breaking() []Difference {
+	var breaking []Difference
+	for _, change := range d.Changes {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
+
+// Compare 이전 스키마(from)와 새 스키마(to)를 비교해 변경마다 하위 호환인지 깨지는지 분류.
+// 이전 버전에 맞던 데이터를 새 버전이 거부할 수 있으면 깨지는 변경 (새 필수 필드, 타입 변경,
+// 줄어든 enum, 좁아진 범위, 추가 필드 금지 등). 조합 키워드(allOf, anyOf, oneOf, not, if/then/else)와
+// patternProperties는 비교하지 않음
+func Compare(from, to *Schema) *Diff {
+	d := &Diff{Changes: []Difference{}}
+	d.compare("", from, to, 0)
+	d.Compatible = len(d.Breaking()) == 0
+	return d
+}
+
+// CompareDefinitions 스키마 정의 두 개를 컴파일해 비교
+func CompareDefinitions(from, to string) (*Diff, error) {
+	fromSchema, err := Cached(from)
+	if err != nil {
+		return nil, fmt.Errorf("old schema: %w", err)
+	}
+	toSchema, err := Cached(to)
+	if err != nil {
+		return nil, fmt.Errorf("new schema: %w", err)
+	}
+	return Compare(fromSchema, toSchema), nil
+}
+
+func (d *Diff) add(path, kind string, breaking bool, format string, args ...interface{}) {
+	d.Changes = append(d.Changes, Difference{Path: path, Kind: kind, Breaking: breaking, Message: fmt.Sprintf(format, args...)})
+}
+
+// deref 같은 문서 안의 $ref를 따라감
+func (s *Schema) deref() *Schema {
+	for depth := 0; s.resolved != nil && depth < maxRefDepth; depth++ {
+		s = s.resolved
+	}
+	return s
+}
+
+// closed 정의에 없는 필드를 허용하지 않는지 (additionalProperties: false)
+func (s *Schema) closed() bool {
+	if s.additionalProperties == nil {
+		return false
+	}
+	ap := s.additionalProperties.deref()
+	return ap.boolean != nil && !*ap.boolean
+}
+
+func (d *Diff) compare(path string, from, to *Schema, depth int) {
+	if depth >= maxRefDepth {
+		return
+	}
+	from, to = from.deref(), to.deref()
+
+	if from.boolean != nil {
+		if !*from.boolean {
+			// 이전 버전이 아무 값도 허용하지 않았으면 무엇으로 바꿔도 깨지지 않음
+			return
+		}
+		from = &Schema{}
+	}
+	if to.boolean != nil {
+		if !*to.boolean {
+			d.add(path, DiffConstraintChanged, true, "%s no longer allows any value", fieldName(path))
+		}
+		return
+	}
+
+	d.compareTypes(path, from, to)
+	d.compareValues(path, from, to)
+	d.compareBounds(path, from, to)
+	d.compareObject(path, from, to, depth)
+
+	if to.items != nil {
+		items := from.items
+		if items == nil {
+			items = &Schema{}
+		}
+		d.compare(path+"/*", items, to.items, depth+1)
+	}
+}
+
+func (d *Diff) compareTypes(path string, from, to *Schema) {
+	fromTypes, toTypes := from.types, to.types
+	if path == "" {
+		// 카테고리 문서는 항상 객체이므로 루트의 type 생략은 object와 같음
+		fromTypes, toTypes = documentTypes(fromTypes), documentTypes(toTypes)
+	}
+	if sameStrings(fromTypes, toTypes) {
+		return
+	}
+	breaking := len(toTypes) > 0 && len(fromTypes) == 0
+	for _, t := range fromTypes {
+		if len(toTypes) > 0 && !acceptsType(toTypes, t) {
+			breaking = true
+		}
+	}
+	d.add(path, DiffTypeChanged, breaking, "%s changed type from %s to %s", fieldName(path), typeList(fromTypes), typeList(toTypes))
+}
+
+func documentTypes(types []string) []string {
+	if len(types) == 0 {
+		return []string{"object"}
+	}
+	return types
+}
+
+func (d *Diff) compareValues(path string, from, to *Schema) {
+	switch {
+	case from.enum == nil && to.enum != nil:
+		d.add(path, DiffEnumChanged, true, "%s is limited to %s", fieldName(path), formatValues(to.enum))
+	case from.enum != nil && to.enum == nil:
+		d.add(path, DiffEnumChanged, false, "%s is no longer limited to %s", fieldName(path), formatValues(from.enum))
+	case from.enum != nil:
+		var removed, added []interface{}
+		for _, v := range from.enum {
+			if !containsValue(to.enum, v) {
+				removed = append(removed, v)
+			}
+		}
+		for _, v := range to.enum {
+			if !containsValue(from.enum, v) {
+				added = append(added, v)
+			}
+		}
+		if len(removed) > 0 {
+			d.add(path, DiffEnumChanged, true, "%s no longer allows %s", fieldName(path), formatValues(removed))
+		}
+		if len(added) > 0 {
+			d.add(path, DiffEnumChanged, false, "%s now also allows %s", fieldName(path), formatValues(added))
+		}
+	}
+
+	switch {
+	case to.hasConst && (!from.hasConst || !equal(from.constVal, to.constVal)):
+		d.add(path, DiffEnumChanged, true, "%s must be %s", fieldName(path), formatValue(to.constVal))
+	case from.hasConst && !to.hasConst:
+		d.add(path, DiffEnumChanged, false, "%s no longer has to be %s", fieldName(path), formatValue(from.constVal))
+	}
+}
+
+func (d *Diff) compareBounds(path string, from, to *Schema) {
+	// 하한은 커지면, 상한은 작아지면 좁아진 것
+	lower := []struct {
+		keyword  string
+		from, to *float64
+	}{
+		{"minimum", from.minimum, to.minimum},
+		{"exclusiveMinimum", from.exclusiveMinimum, to.exclusiveMinimum},
+		{"minLength", intBound(from.minLength), intBound(to.minLength)},
+		{"minItems", intBound(from.minItems), intBound(to.minItems)},
+		{"minProperties", intBound(from.minProperties), intBound(to.minProperties)},
+	}
+	for _, b := range lower {
+		d.compareBound(path, b.keyword, b.from, b.to, func(from, to float64) bool { return to > from })
+	}
+	upper := []struct {
+		keyword  string
+		from, to *float64
+	}{
+		{"maximum", from.maximum, to.maximum},
+		{"exclusiveMaximum", from.exclusiveMaximum, to.exclusiveMaximum},
+		{"maxLength", intBound(from.maxLength), intBound(to.maxLength)},
+		{"maxItems", intBound(from.maxItems), intBound(to.maxItems)},
+		{"maxProperties", intBound(from.maxProperties), intBound(to.maxProperties)},
+	}
+	for _, b := range upper {
+		d.compareBound(path, b.keyword, b.from, b.to, func(from, to float64) bool { return to < from })
+	}
+	d.compareBound(path, "multipleOf", from.multipleOf, to.multipleOf, func(from, to float64) bool { return from != to })
+
+	fromPattern, toPattern := "", ""
+	if from.pattern != nil {
+		fromPattern = from.pattern.String()
+	}
+	if to.pattern != nil {
+		toPattern = to.pattern.String()
+	}
+	d.compareText(path, "pattern", fromPattern, toPattern)
+	d.compareText(path, "format", from.format, to.format)
+
+	if from.uniqueItems != to.uniqueItems {
+		d.add(path, DiffConstraintChanged, to.uniqueItems, "%s uniqueItems changed from %t to %t", fieldName(path), from.uniqueItems, to.uniqueItems)
+	}
+}
+
+// compareBound 범위 키워드 하나를 비교 (새로 생기면 좁아진 것, 없어지면 넓어진 것)
+func (d *Diff) compareBound(path, keyword string, from, to *float64, narrower func(from, to float64) bool) {
+	switch {
+	case from == nil && to == nil:
+	case from == nil:
+		d.add(path, DiffConstraintChanged, true, "%s %s set to %s", fieldName(path), keyword, formatBound(to))
+	case to == nil:
+		d.add(path, DiffConstraintChanged, false, "%s %s %s removed", fieldName(path), keyword, formatBound(from))
+	case *from != *to:
+		d.add(path, DiffConstraintChanged, narrower(*from, *to), "%s %s changed from %s to %s",
+			fieldName(path), keyword, formatBound(from), formatBound(to))
+	}
+}
+
+// compareText pattern, format처럼 값이 같아야 하는 키워드를 비교
+func (d *Diff) compareText(path, keyword, from, to string) {
+	switch {
+	case from == to:
+	case to == "":
+		d.add(path, DiffConstraintChanged, false, "%s %s %q removed", fieldName(path), keyword, from)
+	case from == "":
+		d.add(path, DiffConstraintChanged, true, "%s %s set to %q", fieldName(path), keyword, to)
+	default:
+		d.add(path, DiffConstraintChanged, true, "%s %s changed from %q to %q", fieldName(path), keyword, from, to)
+	}
+}
+
+func (d *Diff) compareObject(path string, from, to *Schema, depth int) {
+	fromRequired := make(map[string]bool, len(from.required))
+	for _, name := range from.required {
+		fromRequired[name] = true
+	}
+	toRequired := make(map[string]bool, len(to.required))
+	for _, name := range to.required {
+		toRequired[name] = true
+	}
+
+	names := make(map[string]bool)
+	for name := range from.properties {
+		names[name] = true
+	}
+	for name := range to.properties {
+		names[name] = true
+	}
+	for name := range toRequired {
+		names[name] = true
+	}
+	for name := range fromRequired {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		fieldPath := path + "/" + escapePointer(name)
+		fromField, inFrom := from.properties[name]
+		toField, inTo := to.properties[name]
+		newField := !inFrom && !fromRequired[name]
+
+		switch {
+		case toRequired[name] && newField:
+			d.add(fieldPath, DiffFieldAdded, true, "new required field %q", name)
+		case toRequired[name] && !fromRequired[name]:
+			d.add(fieldPath, DiffRequiredAdded, true, "field %q is now required", name)
+		case fromRequired[name] && !toRequired[name]:
+			d.add(fieldPath, DiffRequiredRemoved, false, "field %q is no longer required", name)
+		case inTo && newField:
+			d.add(fieldPath, DiffFieldAdded, false, "new optional field %q", name)
+		}
+
+		switch {
+		case inFrom && !inTo && !toRequired[name]:
+			if to.closed() {
+				d.add(fieldPath, DiffFieldRemoved, true, "field %q was removed and is no longer allowed", name)
+			} else {
+				d.add(fieldPath, DiffFieldRemoved, false, "field %q was removed from the schema", name)
+			}
+		case inFrom && inTo:
+			d.compare(fieldPath, fromField, toField, depth+1)
+		}
+	}
+
+	switch {
+	case !from.closed() && to.closed():
+		d.add(path, DiffAdditionalFields, true, "%s no longer allows fields that are not in the schema", fieldName(path))
+	case from.closed() && !to.closed():
+		d.add(path, DiffAdditionalFields, false, "%s now allows fields that are not in the schema", fieldName(path))
+	}
+}
+
+// fieldName 메시지에 쓰는 위치 이름
+func fieldName(path string) string {
+	if path == "" {
+		return "document"
+	}
+	return "field " + strconv.Quote(strings.TrimPrefix(path, "/"))
+}
+
+// acceptsType 타입 목록이 값의 타입을 허용하는지 (number는 integer도 허용)
+func acceptsType(types []string, t string) bool {
+	for _, candidate := range types {
+		if candidate == t || (candidate == "number" && t == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func typeList(types []string) string {
+	if len(types) == 0 {
+		return "any"
+	}
+	return strings.Join(types, " or ")
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func intBound(n *int) *float64 {
+	if n == nil {
+		return nil
+	}
+	f := float64(*n)
+	return &f
+}
+
+func formatBound(n *float64) string {
+	return strconv.FormatFloat(*n, 'g', -1, 64)
+}
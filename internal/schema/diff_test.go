@@ -0,0 +1,97 @@
+package schema
+
+import (
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	from := `{
+		"type": "object",
+		"required": ["name", "ward"],
+		"properties": {
+			"name": {"type": "string", "maxLength": 20},
+			"ward": {"type": "integer"},
+			"status": {"enum": ["active", "inactive"]},
+			"note": {"type": "string"},
+			"bp": {"type": "object", "properties": {"sys": {"type": "number", "minimum": 0}}}
+		}
+	}`
+	to := `{
+		"type": "object",
+		"required": ["name", "bed"],
+		"properties": {
+			"name": {"type": "string", "maxLength": 40},
+			"ward": {"type": "number"},
+			"bed": {"type": "string"},
+			"status": {"enum": ["active"]},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"bp": {"type": "object", "properties": {"sys": {"type": "integer", "minimum": 40}}}
+		},
+		"additionalProperties": false
+	}`
+
+	diff, err := CompareDefinitions(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		path, kind string
+		breaking   bool
+	}{
+		{"", DiffAdditionalFields, true},
+		{"/bed", DiffFieldAdded, true},
+		{"/bp/sys", DiffTypeChanged, true},
+		{"/bp/sys", DiffConstraintChanged, true},
+		{"/name", DiffConstraintChanged, false},
+		{"/note", DiffFieldRemoved, true},
+		{"/status", DiffEnumChanged, true},
+		{"/tags", DiffFieldAdded, false},
+		{"/ward", DiffRequiredRemoved, false},
+		{"/ward", DiffTypeChanged, false},
+	}
+	got := make(map[[2]string]bool)
+	for _, change := range diff.Changes {
+		got[[2]string{change.Path, change.Kind}] = change.Breaking
+	}
+	for _, w := range want {
+		breaking, ok := got[[2]string{w.path, w.kind}]
+		if !ok || breaking != w.breaking {
+			t.Errorf("%s %s: got %v (found %v), want breaking=%v", w.path, w.kind, breaking, ok, w.breaking)
+		}
+	}
+	if len(diff.Changes) != len(want) {
+		t.Errorf("got %d changes, want %d: %+v", len(diff.Changes), len(want), diff.Changes)
+	}
+	if diff.Compatible {
+		t.Error("diff with breaking changes is compatible")
+	}
+
+	// 반대 방향은 넓어지는 변경과 깨지는 변경이 뒤바뀜
+	reverse, err := CompareDefinitions(to, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reverse.Compatible {
+		t.Error("reverse diff adds required ward and narrows types")
+	}
+
+	same, err := CompareDefinitions(from, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !same.Compatible || len(same.Changes) != 0 {
+		t.Errorf("identical schemas differ: %+v", same.Changes)
+	}
+}
+
+func TestCompareLegacyFields(t *testing.T) {
+	diff, err := CompareDefinitions(
+		`{"fields": {"name": {"type": "string", "required": true}}}`,
+		`{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}, "age": {"type": "integer"}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Compatible || len(diff.Changes) != 1 || diff.Changes[0].Path != "/age" {
+		t.Errorf("expected optional age only, got %+v", diff.Changes)
+	}
+}
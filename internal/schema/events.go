@@ -0,0 +1,20 @@
+package schema
+
+// ChangeSubject 카테고리 스키마가 바뀌었음을 알리는 NATS 주제
+// (API가 발행하고 data-consumer가 캐시한 스키마를 다시 읽음)
+const ChangeSubject = "tmidb.schema.changed"
+
+// 스키마 변경 종류
+const (
+	ChangeCreate = "create"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// Change 카테고리 스키마 변경 이벤트
+type Change struct {
+	OrgID     string `json:"org_id,omitempty"` // 비어 있으면 모든 조직의 같은 카테고리
+	Category  string `json:"category"`
+	Operation string `json:"operation"`
+	Version   int    `json:"version,omitempty"` // 새 활성 버전 (delete면 0)
+}
@@ -0,0 +1,53 @@
+package schema
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnamePattern = regexp.MustCompile(`^(?i)[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+)
+
+// checkFormat 값이 format에 맞는지 확인. 지원하지 않는 format은 known=false (주석으로만 취급)
+func checkFormat(format, value string) (ok, known bool) {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339Nano, value)
+		return err == nil, true
+	case "date":
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil, true
+	case "time":
+		_, err := time.Parse("15:04:05.999999999Z07:00", value)
+		return err == nil, true
+	case "email":
+		addr, err := mail.ParseAddress(value)
+		return err == nil && addr.Address == value, true
+	case "uuid":
+		return uuidPattern.MatchString(value), true
+	case "uri":
+		u, err := url.Parse(value)
+		return err == nil && u.Scheme != "", true
+	case "uri-reference":
+		_, err := url.Parse(value)
+		return err == nil, true
+	case "ipv4":
+		ip := net.ParseIP(value)
+		return ip != nil && ip.To4() != nil && !strings.Contains(value, ":"), true
+	case "ipv6":
+		ip := net.ParseIP(value)
+		return ip != nil && strings.Contains(value, ":"), true
+	case "hostname":
+		return len(value) <= 253 && hostnamePattern.MatchString(value), true
+	case "regex":
+		_, err := regexp.Compile(value)
+		return err == nil, true
+	}
+	return true, false
+}
@@ -0,0 +1,201 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Generate 스키마에 맞는 임의의 값을 만듦 (벤치마크용 합성 데이터)
+// 최선의 시도라서 pattern, not, if/then 같은 제약은 맞추지 못할 수 있으므로
+// 필요하면 Validate로 확인할 것. required가 아닌 속성도 모두 채움
+func (s *Schema) Generate(r *rand.Rand) interface{} {
+	return s.generate(r, 0)
+}
+
+func (s *Schema) generate(r *rand.Rand, depth int) interface{} {
+	if s == nil || depth >= maxRefDepth {
+		return nil
+	}
+	if s.boolean != nil {
+		return r.Float64() * 100
+	}
+	if s.resolved != nil {
+		return s.resolved.generate(r, depth+1)
+	}
+	if s.hasConst {
+		return s.constVal
+	}
+	if len(s.enum) > 0 {
+		return s.enum[r.Intn(len(s.enum))]
+	}
+
+	// allOf는 첫 스키마를 바탕으로 나머지의 속성을 합침
+	if len(s.allOf) > 0 && len(s.types) == 0 && len(s.properties) == 0 {
+		value := s.allOf[0].generate(r, depth+1)
+		if obj, ok := value.(map[string]interface{}); ok {
+			for _, sub := range s.allOf[1:] {
+				if more, ok := sub.generate(r, depth+1).(map[string]interface{}); ok {
+					for k, v := range more {
+						obj[k] = v
+					}
+				}
+			}
+		}
+		return value
+	}
+	if len(s.types) == 0 && len(s.properties) == 0 {
+		for _, alternatives := range [][]*Schema{s.oneOf, s.anyOf} {
+			if len(alternatives) > 0 {
+				return alternatives[0].generate(r, depth+1)
+			}
+		}
+	}
+
+	switch s.generatedType() {
+	case "object":
+		obj := make(map[string]interface{}, len(s.properties))
+		names := make([]string, 0, len(s.properties))
+		for name := range s.properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			obj[name] = s.properties[name].generate(r, depth+1)
+		}
+		for _, name := range s.required {
+			if _, ok := obj[name]; !ok {
+				obj[name] = s.additionalProperties.generate(r, depth+1)
+			}
+		}
+		return obj
+	case "array":
+		n := 1
+		if s.minItems != nil && *s.minItems > n {
+			n = *s.minItems
+		}
+		if s.maxItems != nil && *s.maxItems < n {
+			n = *s.maxItems
+		}
+		arr := make([]interface{}, n)
+		for i := range arr {
+			item := s.items
+			if i < len(s.prefixItems) {
+				item = s.prefixItems[i]
+			}
+			arr[i] = item.generate(r, depth+1)
+			if arr[i] == nil && item == nil {
+				arr[i] = i
+			}
+		}
+		return arr
+	case "string":
+		return s.generateString(r)
+	case "integer":
+		lo, hi := s.numberRange()
+		lo, hi = math.Ceil(lo), math.Floor(hi)
+		if hi < lo {
+			return int64(lo)
+		}
+		return int64(lo) + r.Int63n(int64(hi-lo)+1)
+	case "number":
+		lo, hi := s.numberRange()
+		v := lo + r.Float64()*(hi-lo)
+		if s.multipleOf != nil && *s.multipleOf > 0 {
+			v = math.Ceil(v / *s.multipleOf) * *s.multipleOf
+		}
+		return math.Round(v*1000) / 1000
+	case "boolean":
+		return r.Intn(2) == 0
+	}
+	return nil
+}
+
+// generatedType 만들 값의 타입 (선언이 없으면 구조에서 짐작)
+func (s *Schema) generatedType() string {
+	for _, preferred := range []string{"object", "number", "integer", "string", "boolean", "array"} {
+		for _, t := range s.types {
+			if t == preferred {
+				return t
+			}
+		}
+	}
+	switch {
+	case len(s.types) > 0:
+		return s.types[0]
+	case s.properties != nil || len(s.required) > 0:
+		return "object"
+	case s.items != nil || len(s.prefixItems) > 0:
+		return "array"
+	case s.minLength != nil || s.maxLength != nil || s.format != "":
+		return "string"
+	}
+	return "number"
+}
+
+// numberRange minimum/maximum 안의 범위 (없으면 0~100)
+func (s *Schema) numberRange() (float64, float64) {
+	lo, hi := 0.0, 100.0
+	if s.minimum != nil {
+		lo = *s.minimum
+	}
+	if s.exclusiveMinimum != nil {
+		lo = *s.exclusiveMinimum + 1
+	}
+	if s.maximum != nil {
+		hi = *s.maximum
+	}
+	if s.exclusiveMaximum != nil {
+		hi = *s.exclusiveMaximum - 1
+	}
+	if hi < lo {
+		if s.minimum != nil || s.exclusiveMinimum != nil {
+			hi = lo + 100
+		} else {
+			lo = hi - 100
+		}
+	}
+	return lo, hi
+}
+
+func (s *Schema) generateString(r *rand.Rand) string {
+	switch s.format {
+	case "date-time":
+		return time.Now().UTC().Format(time.RFC3339)
+	case "date":
+		return time.Now().UTC().Format("2006-01-02")
+	case "time":
+		return time.Now().UTC().Format("15:04:05Z")
+	case "email":
+		return fmt.Sprintf("bench%d@example.com", r.Intn(10000))
+	case "uuid":
+		b := make([]byte, 16)
+		r.Read(b)
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	case "uri", "uri-reference":
+		return fmt.Sprintf("https://example.com/%d", r.Intn(10000))
+	case "ipv4":
+		return fmt.Sprintf("10.%d.%d.%d", r.Intn(256), r.Intn(256), r.Intn(256))
+	case "ipv6":
+		return fmt.Sprintf("fd00::%x", r.Intn(0xffff))
+	case "hostname":
+		return fmt.Sprintf("host%d.example.com", r.Intn(10000))
+	}
+
+	n := 8
+	if s.minLength != nil && *s.minLength > n {
+		n = *s.minLength
+	}
+	if s.maxLength != nil && *s.maxLength < n {
+		n = *s.maxLength
+	}
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		b.WriteByte(letters[r.Intn(len(letters))])
+	}
+	return b.String()
+}
@@ -0,0 +1,621 @@
+// Package schema는 카테고리 데이터 검증에 쓰는 JSON Schema (draft 2020-12) 엔진입니다.
+//
+// 지원 키워드: type, enum, const, required, properties, patternProperties,
+// additionalProperties, propertyNames, min/maxProperties, dependentRequired,
+// items, prefixItems, contains, min/maxContains, min/maxItems, uniqueItems,
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf,
+// minLength, maxLength, pattern, format, allOf, anyOf, oneOf, not,
+// if/then/else, 같은 문서 안의 $ref (#, #/$defs/...).
+// 알 수 없는 키워드는 스펙대로 무시합니다.
+//
+// 이전 형식인 {"fields": {"name": {"type": "string", "required": true}}}도
+// properties/required로 변환해 검증합니다.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError 필드 하나의 검증 실패
+type FieldError struct {
+	Path    string `json:"path"`    // 값의 위치 (JSON Pointer, 루트는 "")
+	Keyword string `json:"keyword"` // 실패한 스키마 키워드
+	Message string `json:"message"`
+}
+
+// ValidationError 검증 실패 목록
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		path := fe.Path
+		if path == "" {
+			path = "/"
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", path, fe.Message))
+	}
+	return "schema validation failed: " + strings.Join(parts, "; ")
+}
+
+// Schema 컴파일된 스키마
+type Schema struct {
+	boolean *bool // true/false 스키마
+
+	types    []string
+	enum     []interface{}
+	constVal interface{}
+	hasConst bool
+
+	required             []string
+	properties           map[string]*Schema
+	patternProperties    []patternSchema
+	additionalProperties *Schema
+	propertyNames        *Schema
+	minProperties        *int
+	maxProperties        *int
+	dependentRequired    map[string][]string
+
+	items       *Schema
+	prefixItems []*Schema
+	contains    *Schema
+	minContains *int
+	maxContains *int
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+	format    string
+
+	allOf []*Schema
+	anyOf []*Schema
+	oneOf []*Schema
+	not   *Schema
+	ifS   *Schema
+	thenS *Schema
+	elseS *Schema
+
+	ref      string
+	resolved *Schema
+}
+
+type patternSchema struct {
+	re     *regexp.Regexp
+	schema *Schema
+}
+
+// CompileJSON JSON 문서를 스키마로 컴파일
+func CompileJSON(data []byte) (*Schema, error) {
+	var def interface{}
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("invalid schema JSON: %w", err)
+	}
+	return Compile(def)
+}
+
+// Compile 디코딩된 스키마 (map[string]interface{} 또는 bool)를 컴파일
+func Compile(def interface{}) (*Schema, error) {
+	c := &compiler{root: def, byPointer: make(map[string]*Schema)}
+	root, err := c.compile(def, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := c.resolveRefs(); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// Validate 값을 검증하고 실패하면 *ValidationError를 반환
+func (s *Schema) Validate(value interface{}) error {
+	var errs []FieldError
+	s.validate(normalize(value), "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: errs}
+}
+
+// TypesAt 객체 경로(예: ["vitals", "bp"])에 선언된 type 목록 (선언이 없으면 nil)
+// CSV처럼 문자열로만 받은 값을 어떤 타입으로 읽을지 정할 때 씀. properties, patternProperties,
+// additionalProperties, allOf와 $ref만 따라가며 anyOf/oneOf/if 같은 조건부 스키마는 보지 않음
+func (s *Schema) TypesAt(path []string) []string {
+	return s.typesAt(path, 0)
+}
+
+func (s *Schema) typesAt(path []string, depth int) []string {
+	if s == nil || s.boolean != nil || depth >= maxRefDepth {
+		return nil
+	}
+	if s.resolved != nil {
+		if types := s.resolved.typesAt(path, depth+1); types != nil {
+			return types
+		}
+	}
+	for _, sub := range s.allOf {
+		if types := sub.typesAt(path, depth+1); types != nil {
+			return types
+		}
+	}
+	if len(path) == 0 {
+		return s.types
+	}
+
+	key, rest := path[0], path[1:]
+	if child, ok := s.properties[key]; ok {
+		return child.typesAt(rest, depth+1)
+	}
+	for _, ps := range s.patternProperties {
+		if ps.re.MatchString(key) {
+			return ps.schema.typesAt(rest, depth+1)
+		}
+	}
+	return s.additionalProperties.typesAt(rest, depth+1)
+}
+
+// PathsOf properties로 선언된 객체 경로 중 type이 types 중 하나인 경로 (점으로 이은 경로 순으로 정렬)
+// TypesAt과 같이 properties, allOf와 $ref만 따라가며 patternProperties처럼 이름이 정해지지 않은 속성은 빠짐
+func (s *Schema) PathsOf(types ...string) [][]string {
+	found := make(map[string][]string)
+	s.pathsOf(nil, types, found, 0)
+
+	keys := make([]string, 0, len(found))
+	for key := range found {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	paths := make([][]string, len(keys))
+	for i, key := range keys {
+		paths[i] = found[key]
+	}
+	return paths
+}
+
+func (s *Schema) pathsOf(prefix []string, types []string, found map[string][]string, depth int) {
+	if s == nil || s.boolean != nil || depth >= maxRefDepth {
+		return
+	}
+	s.resolved.pathsOf(prefix, types, found, depth+1)
+	for _, sub := range s.allOf {
+		sub.pathsOf(prefix, types, found, depth+1)
+	}
+	for key, child := range s.properties {
+		path := append(append([]string{}, prefix...), key)
+		for _, t := range child.TypesAt(nil) {
+			if slices.Contains(types, t) {
+				found[strings.Join(path, ".")] = path
+				break
+			}
+		}
+		child.pathsOf(path, types, found, depth+1)
+	}
+}
+
+// compiler $ref 해석을 위해 JSON Pointer별 컴파일 결과를 보관
+type compiler struct {
+	root      interface{}
+	byPointer map[string]*Schema
+	refs      []*Schema
+}
+
+func (c *compiler) compile(def interface{}, pointer string) (*Schema, error) {
+	s := &Schema{}
+	c.byPointer[pointer] = s
+
+	switch v := def.(type) {
+	case bool:
+		s.boolean = &v
+		return s, nil
+	case map[string]interface{}:
+		if err := c.compileObject(s, v, pointer); err != nil {
+			return nil, err
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("%s: schema must be an object or boolean", pointerOrRoot(pointer))
+	}
+}
+
+func (c *compiler) compileObject(s *Schema, m map[string]interface{}, pointer string) error {
+	var err error
+	sub := func(key string) (*Schema, error) {
+		raw, ok := m[key]
+		if !ok {
+			return nil, nil
+		}
+		return c.compile(raw, pointer+"/"+escapePointer(key))
+	}
+	subList := func(key string) ([]*Schema, error) {
+		raw, ok := m[key]
+		if !ok {
+			return nil, nil
+		}
+		list, ok := raw.([]interface{})
+		if !ok || len(list) == 0 {
+			return nil, fmt.Errorf("%s/%s: must be a non-empty array", pointer, key)
+		}
+		schemas := make([]*Schema, len(list))
+		for i, item := range list {
+			if schemas[i], err = c.compile(item, fmt.Sprintf("%s/%s/%d", pointer, key, i)); err != nil {
+				return nil, err
+			}
+		}
+		return schemas, nil
+	}
+	subMap := func(key string) (map[string]*Schema, error) {
+		raw, ok := m[key]
+		if !ok {
+			return nil, nil
+		}
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s/%s: must be an object", pointer, key)
+		}
+		schemas := make(map[string]*Schema, len(obj))
+		for name, item := range obj {
+			if schemas[name], err = c.compile(item, pointer+"/"+key+"/"+escapePointer(name)); err != nil {
+				return nil, err
+			}
+		}
+		return schemas, nil
+	}
+
+	// $defs/definitions는 $ref 대상이므로 참조 여부와 관계없이 컴파일
+	for _, key := range []string{"$defs", "definitions"} {
+		if _, err := subMap(key); err != nil {
+			return err
+		}
+	}
+
+	if ref, ok := m["$ref"].(string); ok {
+		s.ref = ref
+		c.refs = append(c.refs, s)
+	}
+
+	if raw, ok := m["type"]; ok {
+		switch t := raw.(type) {
+		case string:
+			s.types = []string{t}
+		case []interface{}:
+			for _, item := range t {
+				name, ok := item.(string)
+				if !ok {
+					return fmt.Errorf("%s/type: must be a string or array of strings", pointer)
+				}
+				s.types = append(s.types, name)
+			}
+		default:
+			return fmt.Errorf("%s/type: must be a string or array of strings", pointer)
+		}
+		for _, t := range s.types {
+			if !validTypeName(t) {
+				return fmt.Errorf("%s/type: unknown type %q", pointer, t)
+			}
+		}
+	}
+
+	if raw, ok := m["enum"]; ok {
+		list, ok := raw.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s/enum: must be an array", pointer)
+		}
+		for _, item := range list {
+			s.enum = append(s.enum, normalize(item))
+		}
+	}
+	if raw, ok := m["const"]; ok {
+		s.constVal = normalize(raw)
+		s.hasConst = true
+	}
+
+	// 객체
+	if raw, ok := m["required"]; ok {
+		if s.required, err = stringList(raw); err != nil {
+			return fmt.Errorf("%s/required: %v", pointer, err)
+		}
+	}
+	if s.properties, err = subMap("properties"); err != nil {
+		return err
+	}
+	if s.properties == nil {
+		if err := c.compileLegacyFields(s, m, pointer); err != nil {
+			return err
+		}
+	}
+	if raw, ok := m["patternProperties"].(map[string]interface{}); ok {
+		patterns := make([]string, 0, len(raw))
+		for p := range raw {
+			patterns = append(patterns, p)
+		}
+		sort.Strings(patterns)
+		for _, p := range patterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return fmt.Errorf("%s/patternProperties: invalid pattern %q: %v", pointer, p, err)
+			}
+			ps, err := c.compile(raw[p], pointer+"/patternProperties/"+escapePointer(p))
+			if err != nil {
+				return err
+			}
+			s.patternProperties = append(s.patternProperties, patternSchema{re: re, schema: ps})
+		}
+	}
+	if s.additionalProperties, err = sub("additionalProperties"); err != nil {
+		return err
+	}
+	if s.propertyNames, err = sub("propertyNames"); err != nil {
+		return err
+	}
+	if s.minProperties, err = intKeyword(m, "minProperties", pointer); err != nil {
+		return err
+	}
+	if s.maxProperties, err = intKeyword(m, "maxProperties", pointer); err != nil {
+		return err
+	}
+	if raw, ok := m["dependentRequired"].(map[string]interface{}); ok {
+		s.dependentRequired = make(map[string][]string, len(raw))
+		for name, deps := range raw {
+			if s.dependentRequired[name], err = stringList(deps); err != nil {
+				return fmt.Errorf("%s/dependentRequired/%s: %v", pointer, name, err)
+			}
+		}
+	}
+
+	// 배열
+	if s.prefixItems, err = subList("prefixItems"); err != nil {
+		return err
+	}
+	if s.items, err = sub("items"); err != nil {
+		return err
+	}
+	if s.contains, err = sub("contains"); err != nil {
+		return err
+	}
+	if s.minContains, err = intKeyword(m, "minContains", pointer); err != nil {
+		return err
+	}
+	if s.maxContains, err = intKeyword(m, "maxContains", pointer); err != nil {
+		return err
+	}
+	if s.minItems, err = intKeyword(m, "minItems", pointer); err != nil {
+		return err
+	}
+	if s.maxItems, err = intKeyword(m, "maxItems", pointer); err != nil {
+		return err
+	}
+	if unique, ok := m["uniqueItems"].(bool); ok {
+		s.uniqueItems = unique
+	}
+
+	// 숫자
+	for key, dst := range map[string]**float64{
+		"minimum":          &s.minimum,
+		"maximum":          &s.maximum,
+		"exclusiveMinimum": &s.exclusiveMinimum,
+		"exclusiveMaximum": &s.exclusiveMaximum,
+		"multipleOf":       &s.multipleOf,
+	} {
+		raw, ok := m[key]
+		if !ok {
+			continue
+		}
+		n, ok := toFloat(normalize(raw))
+		if !ok {
+			return fmt.Errorf("%s/%s: must be a number", pointer, key)
+		}
+		*dst = &n
+	}
+	if s.multipleOf != nil && *s.multipleOf <= 0 {
+		return fmt.Errorf("%s/multipleOf: must be greater than 0", pointer)
+	}
+
+	// 문자열
+	if s.minLength, err = intKeyword(m, "minLength", pointer); err != nil {
+		return err
+	}
+	if s.maxLength, err = intKeyword(m, "maxLength", pointer); err != nil {
+		return err
+	}
+	if raw, ok := m["pattern"].(string); ok {
+		if s.pattern, err = regexp.Compile(raw); err != nil {
+			return fmt.Errorf("%s/pattern: invalid pattern: %v", pointer, err)
+		}
+	}
+	if format, ok := m["format"].(string); ok {
+		s.format = format
+	}
+
+	// 조합
+	if s.allOf, err = subList("allOf"); err != nil {
+		return err
+	}
+	if s.anyOf, err = subList("anyOf"); err != nil {
+		return err
+	}
+	if s.oneOf, err = subList("oneOf"); err != nil {
+		return err
+	}
+	if s.not, err = sub("not"); err != nil {
+		return err
+	}
+	if s.ifS, err = sub("if"); err != nil {
+		return err
+	}
+	if s.thenS, err = sub("then"); err != nil {
+		return err
+	}
+	if s.elseS, err = sub("else"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// compileLegacyFields 이전 형식의 "fields" 정의를 properties/required로 변환
+func (c *compiler) compileLegacyFields(s *Schema, m map[string]interface{}, pointer string) error {
+	fields, ok := m["fields"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	s.properties = make(map[string]*Schema, len(fields))
+	for _, name := range names {
+		def := fields[name]
+		if field, ok := def.(map[string]interface{}); ok {
+			if required, _ := field["required"].(bool); required {
+				s.required = append(s.required, name)
+			}
+			// 필드 단위 required 플래그는 배열 형식의 required와 충돌하므로 제거한 사본을 컴파일
+			if _, isBool := field["required"].(bool); isBool {
+				copied := make(map[string]interface{}, len(field))
+				for k, v := range field {
+					if k != "required" {
+						copied[k] = v
+					}
+				}
+				def = copied
+			}
+		}
+		sub, err := c.compile(def, pointer+"/fields/"+escapePointer(name))
+		if err != nil {
+			return err
+		}
+		s.properties[name] = sub
+	}
+	return nil
+}
+
+// resolveRefs 같은 문서 안의 $ref를 컴파일된 스키마에 연결
+func (c *compiler) resolveRefs() error {
+	for i := 0; i < len(c.refs); i++ {
+		s := c.refs[i]
+		if !strings.HasPrefix(s.ref, "#") {
+			return fmt.Errorf("unsupported $ref %q: only references within the schema are allowed", s.ref)
+		}
+		pointer := strings.TrimPrefix(s.ref, "#")
+		if target, ok := c.byPointer[pointer]; ok {
+			s.resolved = target
+			continue
+		}
+
+		// 키워드 밖의 위치를 가리키는 참조는 해당 위치를 따로 컴파일
+		def, err := lookupPointer(c.root, pointer)
+		if err != nil {
+			return fmt.Errorf("$ref %q: %v", s.ref, err)
+		}
+		target, err := c.compile(def, pointer)
+		if err != nil {
+			return err
+		}
+		s.resolved = target
+	}
+	return nil
+}
+
+func lookupPointer(doc interface{}, pointer string) (interface{}, error) {
+	if pointer == "" {
+		return doc, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer")
+	}
+	current := doc
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch v := current.(type) {
+		case map[string]interface{}:
+			next, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("%q not found", token)
+			}
+			current = next
+		case []interface{}:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil, fmt.Errorf("index %q out of range", token)
+			}
+			current = v[idx]
+		default:
+			return nil, fmt.Errorf("%q not found", token)
+		}
+	}
+	return current, nil
+}
+
+func intKeyword(m map[string]interface{}, key, pointer string) (*int, error) {
+	raw, ok := m[key]
+	if !ok {
+		return nil, nil
+	}
+	n, ok := toFloat(normalize(raw))
+	if !ok || n < 0 || n != math.Trunc(n) {
+		return nil, fmt.Errorf("%s/%s: must be a non-negative integer", pointer, key)
+	}
+	v := int(n)
+	return &v, nil
+}
+
+func stringList(raw interface{}) ([]string, error) {
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("must be an array of strings")
+	}
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		name, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be an array of strings")
+		}
+		result = append(result, name)
+	}
+	return result, nil
+}
+
+func validTypeName(name string) bool {
+	switch name {
+	case "null", "boolean", "object", "array", "number", "integer", "string":
+		return true
+	}
+	return false
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func pointerOrRoot(pointer string) string {
+	if pointer == "" {
+		return "schema"
+	}
+	return pointer
+}
+
+// utf8 길이 (minLength/maxLength는 코드 포인트 기준)
+func stringLength(s string) int {
+	return utf8.RuneCountInString(s)
+}
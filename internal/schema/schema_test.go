@@ -0,0 +1,224 @@
+package schema
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func validationErrors(t *testing.T, s *Schema, value interface{}) []FieldError {
+	t.Helper()
+	err := s.Validate(value)
+	if err == nil {
+		return nil
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	return verr.Errors
+}
+
+func TestValidateObject(t *testing.T) {
+	s, err := CompileJSON([]byte(`{
+		"type": "object",
+		"required": ["name", "status"],
+		"properties": {
+			"name": {"type": "string", "minLength": 2},
+			"status": {"enum": ["active", "inactive"]},
+			"count": {"type": "integer", "minimum": 0},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+			"owner": {"$ref": "#/$defs/owner"}
+		},
+		"additionalProperties": false,
+		"$defs": {
+			"owner": {
+				"type": "object",
+				"required": ["email"],
+				"properties": {"email": {"type": "string", "format": "email"}}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+
+	valid := map[string]interface{}{
+		"name":   "sensor-1",
+		"status": "active",
+		"count":  float64(3),
+		"tags":   []interface{}{"a", "b"},
+		"owner":  map[string]interface{}{"email": "ops@example.com"},
+	}
+	if errs := validationErrors(t, s, valid); errs != nil {
+		t.Fatalf("expected valid, got %+v", errs)
+	}
+
+	invalid := map[string]interface{}{
+		"name":   "x",
+		"count":  1.5,
+		"tags":   []interface{}{"a", "a"},
+		"owner":  map[string]interface{}{"email": "not-an-email"},
+		"extra":  true,
+		"status": "deleted",
+	}
+	got := map[string]string{}
+	for _, fe := range validationErrors(t, s, invalid) {
+		got[fe.Path] = fe.Keyword
+	}
+	want := map[string]string{
+		"/name":        "minLength",
+		"/status":      "enum",
+		"/count":       "type",
+		"/tags":        "uniqueItems",
+		"/owner/email": "format",
+		"/extra":       "additionalProperties",
+	}
+	for path, keyword := range want {
+		if got[path] != keyword {
+			t.Errorf("%s: expected %s error, got %q (all: %v)", path, keyword, got[path], got)
+		}
+	}
+
+	missing := validationErrors(t, s, map[string]interface{}{"status": "active"})
+	if len(missing) != 1 || missing[0].Path != "/name" || missing[0].Keyword != "required" {
+		t.Fatalf("expected missing /name, got %+v", missing)
+	}
+}
+
+func TestValidateCombinators(t *testing.T) {
+	s, err := CompileJSON([]byte(`{
+		"oneOf": [
+			{"type": "integer", "multipleOf": 5},
+			{"type": "string", "format": "uuid"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	for _, value := range []interface{}{float64(10), 15, "3f2a9c1e-0b7d-4e65-9a1b-2c3d4e5f6a7b"} {
+		if err := s.Validate(value); err != nil {
+			t.Errorf("%v: expected valid, got %v", value, err)
+		}
+	}
+	for _, value := range []interface{}{float64(12), "abc", nil} {
+		if err := s.Validate(value); err == nil {
+			t.Errorf("%v: expected oneOf failure", value)
+		}
+	}
+}
+
+func TestLegacyFieldsFormat(t *testing.T) {
+	s, err := CompileJSON([]byte(`{"type": "object", "fields": {"cpu_usage": {"type": "number", "required": true}}}`))
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if err := s.Validate(map[string]interface{}{"cpu_usage": 12.5}); err != nil {
+		t.Fatalf("expected valid, got %v", err)
+	}
+	errs := validationErrors(t, s, map[string]interface{}{"cpu_usage": "high"})
+	if len(errs) != 1 || errs[0].Path != "/cpu_usage" || errs[0].Keyword != "type" {
+		t.Fatalf("expected type error, got %+v", errs)
+	}
+	if errs := validationErrors(t, s, map[string]interface{}{}); len(errs) != 1 || errs[0].Keyword != "required" {
+		t.Fatalf("expected required error, got %+v", errs)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, def := range []string{
+		`{"type": "text"}`,
+		`{"pattern": "("}`,
+		`{"$ref": "http://example.com/schema.json"}`,
+		`{"$ref": "#/$defs/missing"}`,
+		`{"minLength": -1}`,
+	} {
+		if _, err := CompileJSON([]byte(def)); err == nil {
+			t.Errorf("%s: expected compile error", def)
+		}
+	}
+}
+
+func TestTypesAt(t *testing.T) {
+	s, err := CompileJSON([]byte(`{
+		"type": "object",
+		"$defs": {"reading": {"type": "object", "properties": {"value": {"type": ["number", "null"]}}}},
+		"properties": {
+			"name": {"type": "string"},
+			"vitals": {"$ref": "#/$defs/reading"}
+		},
+		"patternProperties": {"^flag_": {"type": "boolean"}},
+		"additionalProperties": {"type": "integer"}
+	}`))
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	tests := []struct {
+		path []string
+		want []string
+	}{
+		{[]string{"name"}, []string{"string"}},
+		{[]string{"vitals", "value"}, []string{"number", "null"}},
+		{[]string{"flag_ok"}, []string{"boolean"}},
+		{[]string{"count"}, []string{"integer"}},
+		{[]string{"name", "first"}, nil},
+	}
+	for _, tt := range tests {
+		if got := s.TypesAt(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("TypesAt(%v) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPathsOf(t *testing.T) {
+	s, err := CompileJSON([]byte(`{
+		"type": "object",
+		"$defs": {"reading": {"type": "object", "properties": {"value": {"type": ["number", "null"]}, "unit": {"type": "string"}}}},
+		"properties": {
+			"name": {"type": "string"},
+			"temp": {"type": "number"},
+			"vitals": {"$ref": "#/$defs/reading"}
+		},
+		"allOf": [{"properties": {"count": {"type": "integer"}}}],
+		"patternProperties": {"^n_": {"type": "number"}}
+	}`))
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	want := [][]string{{"count"}, {"temp"}, {"vitals", "value"}}
+	if got := s.PathsOf("number", "integer"); !reflect.DeepEqual(got, want) {
+		t.Errorf("PathsOf = %v, want %v", got, want)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	for _, def := range []string{
+		`{"type": "object", "required": ["temp", "unit"], "properties": {
+			"temp": {"type": "number", "minimum": -40, "maximum": 60},
+			"count": {"type": "integer", "exclusiveMinimum": 10},
+			"unit": {"enum": ["C", "F"]},
+			"id": {"type": "string", "format": "uuid"},
+			"at": {"type": "string", "format": "date-time"},
+			"code": {"type": "string", "minLength": 12, "maxLength": 12},
+			"tags": {"type": "array", "items": {"type": "string"}, "minItems": 2},
+			"owner": {"$ref": "#/$defs/owner"}
+		}, "additionalProperties": false,
+		"$defs": {"owner": {"type": "object", "required": ["email"], "properties": {"email": {"type": "string", "format": "email"}}}}}`,
+		`{"fields": {"name": {"type": "string", "required": true}, "level": {"type": "integer", "maximum": -5}}}`,
+		`{"allOf": [{"type": "object", "properties": {"a": {"type": "boolean"}}}, {"properties": {"b": {"const": 3}}, "required": ["b"]}]}`,
+	} {
+		s, err := CompileJSON([]byte(def))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 20; i++ {
+			value := s.Generate(r)
+			if err := s.Validate(value); err != nil {
+				t.Errorf("Generate(%s) = %v: %v", def, value, err)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,409 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxRefDepth 순환 $ref로 인한 무한 재귀 방지
+const maxRefDepth = 64
+
+func (s *Schema) validate(value interface{}, path string, errs *[]FieldError) {
+	s.validateDepth(value, path, errs, 0)
+}
+
+func (s *Schema) validateDepth(value interface{}, path string, errs *[]FieldError, depth int) {
+	add := func(keyword, format string, args ...interface{}) {
+		*errs = append(*errs, FieldError{Path: path, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.boolean != nil {
+		if !*s.boolean {
+			add("false", "value is not allowed")
+		}
+		return
+	}
+
+	if s.resolved != nil {
+		if depth >= maxRefDepth {
+			add("$ref", "reference depth exceeds %d", maxRefDepth)
+			return
+		}
+		s.resolved.validateDepth(value, path, errs, depth+1)
+	}
+
+	if len(s.types) > 0 && !matchesAnyType(value, s.types) {
+		add("type", "expected %s, got %s", strings.Join(s.types, " or "), typeName(value))
+		// 타입이 다르면 나머지 타입별 키워드 결과는 의미가 없음
+		return
+	}
+
+	if s.enum != nil && !containsValue(s.enum, value) {
+		add("enum", "value must be one of %s", formatValues(s.enum))
+	}
+	if s.hasConst && !equal(s.constVal, value) {
+		add("const", "value must be %s", formatValue(s.constVal))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		s.validateObject(v, path, errs, depth)
+	case []interface{}:
+		s.validateArray(v, path, errs, depth)
+	case string:
+		s.validateString(v, add)
+	case float64:
+		s.validateNumber(v, add)
+	}
+
+	for _, sub := range s.allOf {
+		sub.validateDepth(value, path, errs, depth)
+	}
+	if len(s.anyOf) > 0 {
+		matched := false
+		for _, sub := range s.anyOf {
+			if sub.matches(value, depth) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			add("anyOf", "value does not match any of the allowed schemas")
+		}
+	}
+	if len(s.oneOf) > 0 {
+		count := 0
+		for _, sub := range s.oneOf {
+			if sub.matches(value, depth) {
+				count++
+			}
+		}
+		if count != 1 {
+			add("oneOf", "value must match exactly one schema, matched %d", count)
+		}
+	}
+	if s.not != nil && s.not.matches(value, depth) {
+		add("not", "value must not match the schema")
+	}
+	if s.ifS != nil {
+		if s.ifS.matches(value, depth) {
+			if s.thenS != nil {
+				s.thenS.validateDepth(value, path, errs, depth)
+			}
+		} else if s.elseS != nil {
+			s.elseS.validateDepth(value, path, errs, depth)
+		}
+	}
+}
+
+// matches 오류 수집 없이 일치 여부만 확인 (anyOf, oneOf, not, if)
+func (s *Schema) matches(value interface{}, depth int) bool {
+	var errs []FieldError
+	s.validateDepth(value, "", &errs, depth)
+	return len(errs) == 0
+}
+
+func (s *Schema) validateObject(obj map[string]interface{}, path string, errs *[]FieldError, depth int) {
+	add := func(keyword, format string, args ...interface{}) {
+		*errs = append(*errs, FieldError{Path: path, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, name := range s.required {
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, FieldError{
+				Path:    path + "/" + escapePointer(name),
+				Keyword: "required",
+				Message: "required field is missing",
+			})
+		}
+	}
+	if s.minProperties != nil && len(obj) < *s.minProperties {
+		add("minProperties", "must have at least %d properties", *s.minProperties)
+	}
+	if s.maxProperties != nil && len(obj) > *s.maxProperties {
+		add("maxProperties", "must have at most %d properties", *s.maxProperties)
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := obj[name]
+		childPath := path + "/" + escapePointer(name)
+
+		if deps, ok := s.dependentRequired[name]; ok {
+			for _, dep := range deps {
+				if _, ok := obj[dep]; !ok {
+					*errs = append(*errs, FieldError{
+						Path:    path + "/" + escapePointer(dep),
+						Keyword: "dependentRequired",
+						Message: fmt.Sprintf("required when %q is present", name),
+					})
+				}
+			}
+		}
+		if s.propertyNames != nil && !s.propertyNames.matches(name, depth) {
+			*errs = append(*errs, FieldError{Path: childPath, Keyword: "propertyNames", Message: "property name is not allowed"})
+		}
+
+		evaluated := false
+		if sub, ok := s.properties[name]; ok {
+			sub.validateDepth(value, childPath, errs, depth)
+			evaluated = true
+		}
+		for _, ps := range s.patternProperties {
+			if ps.re.MatchString(name) {
+				ps.schema.validateDepth(value, childPath, errs, depth)
+				evaluated = true
+			}
+		}
+		if !evaluated && s.additionalProperties != nil {
+			if s.additionalProperties.boolean != nil && !*s.additionalProperties.boolean {
+				*errs = append(*errs, FieldError{Path: childPath, Keyword: "additionalProperties", Message: "unknown field is not allowed"})
+				continue
+			}
+			s.additionalProperties.validateDepth(value, childPath, errs, depth)
+		}
+	}
+}
+
+func (s *Schema) validateArray(arr []interface{}, path string, errs *[]FieldError, depth int) {
+	add := func(keyword, format string, args ...interface{}) {
+		*errs = append(*errs, FieldError{Path: path, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.minItems != nil && len(arr) < *s.minItems {
+		add("minItems", "must have at least %d items", *s.minItems)
+	}
+	if s.maxItems != nil && len(arr) > *s.maxItems {
+		add("maxItems", "must have at most %d items", *s.maxItems)
+	}
+	if s.uniqueItems {
+	outer:
+		for i := 0; i < len(arr); i++ {
+			for j := i + 1; j < len(arr); j++ {
+				if equal(arr[i], arr[j]) {
+					add("uniqueItems", "items %d and %d are equal", i, j)
+					break outer
+				}
+			}
+		}
+	}
+
+	for i, item := range arr {
+		itemPath := path + "/" + strconv.Itoa(i)
+		if i < len(s.prefixItems) {
+			s.prefixItems[i].validateDepth(item, itemPath, errs, depth)
+		} else if s.items != nil {
+			s.items.validateDepth(item, itemPath, errs, depth)
+		}
+	}
+
+	if s.contains != nil {
+		count := 0
+		for _, item := range arr {
+			if s.contains.matches(item, depth) {
+				count++
+			}
+		}
+		min := 1
+		if s.minContains != nil {
+			min = *s.minContains
+		}
+		if count < min {
+			add("contains", "must contain at least %d matching items, found %d", min, count)
+		}
+		if s.maxContains != nil && count > *s.maxContains {
+			add("maxContains", "must contain at most %d matching items, found %d", *s.maxContains, count)
+		}
+	}
+}
+
+func (s *Schema) validateString(str string, add func(keyword, format string, args ...interface{})) {
+	length := stringLength(str)
+	if s.minLength != nil && length < *s.minLength {
+		add("minLength", "must be at least %d characters", *s.minLength)
+	}
+	if s.maxLength != nil && length > *s.maxLength {
+		add("maxLength", "must be at most %d characters", *s.maxLength)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		add("pattern", "must match pattern %s", s.pattern.String())
+	}
+	if s.format != "" {
+		if ok, known := checkFormat(s.format, str); known && !ok {
+			add("format", "must be a valid %s", s.format)
+		}
+	}
+}
+
+func (s *Schema) validateNumber(n float64, add func(keyword, format string, args ...interface{})) {
+	if s.minimum != nil && n < *s.minimum {
+		add("minimum", "must be >= %v", *s.minimum)
+	}
+	if s.maximum != nil && n > *s.maximum {
+		add("maximum", "must be <= %v", *s.maximum)
+	}
+	if s.exclusiveMinimum != nil && n <= *s.exclusiveMinimum {
+		add("exclusiveMinimum", "must be > %v", *s.exclusiveMinimum)
+	}
+	if s.exclusiveMaximum != nil && n >= *s.exclusiveMaximum {
+		add("exclusiveMaximum", "must be < %v", *s.exclusiveMaximum)
+	}
+	if s.multipleOf != nil {
+		q := n / *s.multipleOf
+		if math.Abs(q-math.Round(q)) > 1e-9 {
+			add("multipleOf", "must be a multiple of %v", *s.multipleOf)
+		}
+	}
+}
+
+func matchesAnyType(value interface{}, types []string) bool {
+	actual := typeName(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeName JSON Schema 타입 이름 (소수부가 없는 숫자는 integer)
+func typeName(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// normalize 디코딩 방식에 따라 다른 숫자/컨테이너 타입을 JSON 기본 타입으로 통일
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = normalize(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = normalize(item)
+		}
+		return out
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case nil, bool, string, float64:
+		return v
+	}
+
+	if f, ok := toFloat(value); ok {
+		return f
+	}
+
+	// 구조체 등은 JSON으로 왕복하여 기본 타입으로 변환
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return value
+	}
+	return out
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	}
+	return 0, false
+}
+
+// equal JSON 값 비교 (숫자는 값으로, 객체는 키 순서와 무관)
+func equal(a, b interface{}) bool {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, item := range av {
+			other, ok := bv[k]
+			if !ok || !equal(item, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equal(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, candidate := range values {
+		if equal(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func formatValues(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = formatValue(v)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
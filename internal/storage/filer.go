@@ -0,0 +1,129 @@
+// Package storage는 SeaweedFS 파일러 HTTP API로 파일(blob)을 저장하고 읽습니다.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound 요청한 경로에 파일이 없음
+var ErrNotFound = errors.New("file not found in storage")
+
+// Object 파일러에서 읽은 파일 (Body는 호출자가 닫아야 함)
+type Object struct {
+	Body        io.ReadCloser
+	Size        int64
+	ContentType string
+}
+
+// FilerClient SeaweedFS 파일러 클라이언트
+type FilerClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewFilerClient 파일러 주소(예: http://localhost:8888)로 클라이언트 생성
+func NewFilerClient(baseURL string) *FilerClient {
+	return &FilerClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		// 대용량 파일 전송이 있으므로 전체 타임아웃 대신 연결 단계 타임아웃만 둠
+		client: &http.Client{Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			ResponseHeaderTimeout: 30 * time.Second,
+			IdleConnTimeout:       90 * time.Second,
+		}},
+	}
+}
+
+// URL 파일 경로의 파일러 URL
+func (f *FilerClient) URL(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return f.baseURL + "/" + strings.Join(segments, "/")
+}
+
+// Put 본문을 스트리밍하여 path에 저장 (같은 경로가 있으면 덮어씀)
+func (f *FilerClient) Put(ctx context.Context, path string, body io.Reader, size int64, contentType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, f.URL(path), body)
+	if err != nil {
+		return err
+	}
+	if size >= 0 {
+		req.ContentLength = size
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("filer upload failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("filer upload failed: %s", responseError(resp))
+	}
+	return nil
+}
+
+// Get path의 파일을 연다
+func (f *FilerClient) Get(ctx context.Context, path string) (*Object, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.URL(path), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("filer download failed: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("filer download failed: %s", responseError(resp))
+	}
+
+	size := resp.ContentLength
+	if size < 0 {
+		size, _ = strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	}
+	return &Object{Body: resp.Body, Size: size, ContentType: resp.Header.Get("Content-Type")}, nil
+}
+
+// Delete path의 파일을 삭제 (없으면 성공으로 처리)
+func (f *FilerClient) Delete(ctx context.Context, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, f.URL(path), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("filer delete failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("filer delete failed: %s", responseError(resp))
+	}
+	return nil
+}
+
+func responseError(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if msg := strings.TrimSpace(string(body)); msg != "" {
+		return fmt.Sprintf("%s: %s", resp.Status, msg)
+	}
+	return resp.Status
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeFiler 경로별로 본문을 메모리에 보관하는 파일러
+func fakeFiler() *httptest.Server {
+	var mu sync.Mutex
+	files := map[string]string{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			files[r.URL.Path] = string(body)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			body, ok := files[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			io.WriteString(w, body)
+		case http.MethodDelete:
+			delete(files, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func TestFilerClientRoundTrip(t *testing.T) {
+	server := fakeFiler()
+	defer server.Close()
+
+	client := NewFilerClient(server.URL + "/")
+	ctx := context.Background()
+	path := "/attachments/org/target/report 1.pdf"
+
+	if err := client.Put(ctx, path, strings.NewReader("hello"), 5, "application/pdf"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	obj, err := client.Get(ctx, path)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	body, _ := io.ReadAll(obj.Body)
+	obj.Body.Close()
+	if string(body) != "hello" || obj.Size != 5 {
+		t.Fatalf("unexpected object %q (size %d)", body, obj.Size)
+	}
+
+	if err := client.Delete(ctx, path); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := client.Get(ctx, path); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+	if err := client.Delete(ctx, path); err != nil {
+		t.Fatalf("deleting a missing file should succeed, got %v", err)
+	}
+}
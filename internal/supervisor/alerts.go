@@ -0,0 +1,200 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/alerting"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// setupAlerting loads the configured rules and channels; invalid entries are
+// reported but do not prevent the supervisor from starting
+func (s *Supervisor) setupAlerting() {
+	s.alertEngine = alerting.NewEngine()
+	s.alertDispatcher = alerting.NewDispatcher()
+
+	if err := s.alertEngine.SetRules(s.config.AlertRules); err != nil {
+		log.Printf("⚠️ Ignoring alert rules: %v", err)
+	}
+	if err := s.alertDispatcher.SetChannels(s.config.AlertChannels); err != nil {
+		log.Printf("⚠️ Ignoring alert channels: %v", err)
+	}
+}
+
+// evaluateAlerts checks threshold rules against the latest collected stats
+func (s *Supervisor) evaluateAlerts() {
+	snap := alerting.Snapshot{
+		CPU:        s.getCPUUsage(),
+		Memory:     s.getMemoryUsage(),
+		Disk:       s.getDiskUsage(),
+		Components: make(map[string]alerting.ComponentStats),
+	}
+	for _, proc := range s.processManager.GetProcessList() {
+		snap.Components[proc.Name] = alerting.ComponentStats{
+			Running: proc.Status == "running",
+			CPU:     proc.CPU,
+			Memory:  proc.Memory,
+		}
+	}
+
+	s.alertDispatcher.Dispatch(s.alertEngine.Evaluate(time.Now(), snap))
+}
+
+// publishEvent sends an event to subscribers and matches it against event rules
+func (s *Supervisor) publishEvent(event ipc.Event) {
+	s.events.publish(event)
+
+	if s.alertEngine != nil {
+		notifications := s.alertEngine.HandleEvent(time.Now(), string(event.Type), event.Component, event.Message)
+		s.alertDispatcher.Dispatch(notifications)
+	}
+}
+
+// decodeMessageField decodes a nested object of the message data into v
+func decodeMessageField(msg *ipc.Message, key string, v interface{}) error {
+	raw, ok := msg.Data[key]
+	if !ok {
+		return fmt.Errorf("%s parameter required", key)
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid %s: %v", key, err)
+	}
+	return nil
+}
+
+func (s *Supervisor) handleAlertList(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	return ipc.NewResponse(msg.ID, true, s.alertEngine.Alerts(), "")
+}
+
+func (s *Supervisor) handleAlertRuleList(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	return ipc.NewResponse(msg.ID, true, s.alertEngine.Rules(), "")
+}
+
+// handleAlertRuleSet adds a rule or replaces the rule with the same name
+func (s *Supervisor) handleAlertRuleSet(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	var rule alerting.Rule
+	if err := decodeMessageField(msg, "rule", &rule); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	rules := slices.Clone(s.config.AlertRules)
+	if i := slices.IndexFunc(rules, func(r alerting.Rule) bool { return r.Name == rule.Name }); i >= 0 {
+		rules[i] = rule
+	} else {
+		rules = append(rules, rule)
+	}
+
+	if err := s.alertEngine.SetRules(rules); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	s.config.AlertRules = rules
+
+	return s.persistConfig(msg, map[string]interface{}{"rule": rule.Name, "rules": len(rules)})
+}
+
+func (s *Supervisor) handleAlertRuleDelete(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	name, _ := msg.Data["name"].(string)
+	if name == "" {
+		return ipc.NewResponse(msg.ID, false, nil, "name parameter required")
+	}
+
+	rules := slices.DeleteFunc(slices.Clone(s.config.AlertRules), func(r alerting.Rule) bool { return r.Name == name })
+	if len(rules) == len(s.config.AlertRules) {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("alert rule %s not found", name))
+	}
+
+	if err := s.alertEngine.SetRules(rules); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	s.config.AlertRules = rules
+
+	return s.persistConfig(msg, map[string]interface{}{"rule": name, "rules": len(rules)})
+}
+
+func (s *Supervisor) handleAlertChannelList(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	channels := make([]alerting.Channel, len(s.config.AlertChannels))
+	for i, channel := range s.config.AlertChannels {
+		channels[i] = channel.Redacted()
+	}
+	return ipc.NewResponse(msg.ID, true, channels, "")
+}
+
+// handleAlertChannelSet adds a channel or replaces the channel with the same name
+func (s *Supervisor) handleAlertChannelSet(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	var channel alerting.Channel
+	if err := decodeMessageField(msg, "channel", &channel); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	channels := slices.Clone(s.config.AlertChannels)
+	if i := slices.IndexFunc(channels, func(c alerting.Channel) bool { return c.Name == channel.Name }); i >= 0 {
+		// 목록 조회 결과를 그대로 다시 보낸 경우 기존 비밀번호 유지
+		if channel.Password == alerting.RedactedPassword {
+			channel.Password = channels[i].Password
+		}
+		channels[i] = channel
+	} else {
+		channels = append(channels, channel)
+	}
+
+	if err := s.alertDispatcher.SetChannels(channels); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	s.config.AlertChannels = channels
+
+	return s.persistConfig(msg, map[string]interface{}{"channel": channel.Name, "channels": len(channels)})
+}
+
+func (s *Supervisor) handleAlertChannelDelete(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	name, _ := msg.Data["name"].(string)
+	if name == "" {
+		return ipc.NewResponse(msg.ID, false, nil, "name parameter required")
+	}
+
+	channels := slices.DeleteFunc(slices.Clone(s.config.AlertChannels), func(c alerting.Channel) bool { return c.Name == name })
+	if len(channels) == len(s.config.AlertChannels) {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("alert channel %s not found", name))
+	}
+
+	if err := s.alertDispatcher.SetChannels(channels); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	s.config.AlertChannels = channels
+
+	return s.persistConfig(msg, map[string]interface{}{"channel": name, "channels": len(channels)})
+}
+
+// handleAlertTest sends a test notification synchronously so errors reach the CLI
+func (s *Supervisor) handleAlertTest(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	name, _ := msg.Data["channel"].(string)
+	channel, ok := s.alertDispatcher.Channel(name)
+	if !ok {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("alert channel %s not found", name))
+	}
+
+	n := alerting.Notification{
+		Rule:      "test",
+		Severity:  alerting.SeverityInfo,
+		Status:    alerting.StatusFiring,
+		Condition: "manual test",
+		Message:   "This is a test notification from the tmiDB supervisor",
+		Timestamp: time.Now(),
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 15*time.Second)
+	defer cancel()
+	if err := s.alertDispatcher.Send(ctx, channel, n); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to send test notification: %v", err))
+	}
+
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{"channel": name, "status": "sent"}, "")
+}
@@ -0,0 +1,106 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// defaultBackupCatalog is used when backup_catalog is not configured
+const defaultBackupCatalog = "./backups/catalog.json"
+
+// backupCatalog is the on-disk form of the backup catalog
+type backupCatalog struct {
+	Version int           `json:"version"`
+	Backups []*BackupInfo `json:"backups"`
+}
+
+func (s *Supervisor) backupCatalogPath() string {
+	if s.config.BackupCatalog != "" {
+		return s.config.BackupCatalog
+	}
+	return defaultBackupCatalog
+}
+
+// loadBackupCatalog reads the catalog into s.backups, so backups made before a
+// restart keep their components, checksum and versions
+func (s *Supervisor) loadBackupCatalog() error {
+	data, err := os.ReadFile(s.backupCatalogPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var catalog backupCatalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return fmt.Errorf("invalid backup catalog %s: %w", s.backupCatalogPath(), err)
+	}
+	for _, backup := range catalog.Backups {
+		if backup == nil || backup.ID == "" {
+			continue
+		}
+		// 이전 슈퍼바이저가 백업 도중 종료됨
+		if backup.Status == "creating" {
+			backup.Status = "failed"
+		}
+		s.backups[backup.ID] = backup
+	}
+	return nil
+}
+
+// saveBackupCatalog writes s.backups to the catalog file (write to a temp file, then rename)
+func (s *Supervisor) saveBackupCatalog() {
+	s.backupCatalogMutex.Lock()
+	defer s.backupCatalogMutex.Unlock()
+
+	catalog := backupCatalog{Version: 1, Backups: s.sortedBackups()}
+	data, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		log.Printf("⚠️ Failed to encode backup catalog: %v", err)
+		return
+	}
+
+	path := s.backupCatalogPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.Printf("⚠️ Failed to save backup catalog: %v", err)
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		log.Printf("⚠️ Failed to save backup catalog: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		log.Printf("⚠️ Failed to save backup catalog: %v", err)
+	}
+}
+
+// sortedBackups returns the known backups, newest first
+func (s *Supervisor) sortedBackups() []*BackupInfo {
+	backups := make([]*BackupInfo, 0, len(s.backups))
+	for _, backup := range s.backups {
+		backups = append(backups, backup)
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].Created.After(backups[j].Created) })
+	return backups
+}
+
+// findBackup looks a backup up by ID, path or file name
+func (s *Supervisor) findBackup(ref string) *BackupInfo {
+	if backup, ok := s.backups[ref]; ok {
+		return backup
+	}
+	clean := filepath.Clean(ref)
+	for _, backup := range s.sortedBackups() {
+		if filepath.Clean(backup.Path) == clean || filepath.Base(backup.Path) == ref {
+			return backup
+		}
+	}
+	return nil
+}
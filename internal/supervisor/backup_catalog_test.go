@@ -0,0 +1,77 @@
+package supervisor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBackupCatalogRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	config := &Config{BackupCatalog: filepath.Join(dir, "catalog.json")}
+
+	s := &Supervisor{config: config, backups: make(map[string]*BackupInfo)}
+	s.backups["backup_1"] = &BackupInfo{
+		ID:            "backup_1",
+		Name:          "nightly",
+		Path:          filepath.Join(dir, "nightly.tar.gz"),
+		Created:       time.Now().Add(-time.Hour),
+		Components:    []string{"database", "config"},
+		Checksum:      "abc",
+		SourceVersion: "1.2.3",
+		SchemaHash:    "def",
+		Status:        "completed",
+	}
+	s.backups["backup_2"] = &BackupInfo{
+		ID:      "backup_2",
+		Path:    filepath.Join(dir, "running.tar"),
+		Created: time.Now(),
+		Status:  "creating",
+	}
+	s.saveBackupCatalog()
+
+	// 재시작 후 새 슈퍼바이저가 카탈로그를 읽음
+	restarted := &Supervisor{config: config, backups: make(map[string]*BackupInfo)}
+	if err := restarted.loadBackupCatalog(); err != nil {
+		t.Fatal(err)
+	}
+	if len(restarted.backups) != 2 {
+		t.Fatalf("loaded %d backups, want 2", len(restarted.backups))
+	}
+
+	nightly := restarted.backups["backup_1"]
+	if nightly.Checksum != "abc" || nightly.SourceVersion != "1.2.3" || nightly.SchemaHash != "def" || len(nightly.Components) != 2 {
+		t.Errorf("backup_1 = %+v", nightly)
+	}
+	if status := restarted.backups["backup_2"].Status; status != "failed" {
+		t.Errorf("interrupted backup status = %q, want failed", status)
+	}
+	if sorted := restarted.sortedBackups(); sorted[0].ID != "backup_2" {
+		t.Errorf("sortedBackups not newest first: %s", sorted[0].ID)
+	}
+}
+
+func TestFindBackup(t *testing.T) {
+	s := &Supervisor{config: &Config{}, backups: map[string]*BackupInfo{
+		"backup_1": {ID: "backup_1", Path: "./backups/nightly.tar.gz"},
+	}}
+
+	for _, ref := range []string{"backup_1", "./backups/nightly.tar.gz", "backups/nightly.tar.gz", "nightly.tar.gz"} {
+		if backup := s.findBackup(ref); backup == nil || backup.ID != "backup_1" {
+			t.Errorf("findBackup(%q) = %v", ref, backup)
+		}
+	}
+	if backup := s.findBackup("other.tar"); backup != nil {
+		t.Errorf("findBackup(other.tar) = %v, want nil", backup)
+	}
+}
+
+func TestLoadBackupCatalogMissing(t *testing.T) {
+	s := &Supervisor{
+		config:  &Config{BackupCatalog: filepath.Join(t.TempDir(), "catalog.json")},
+		backups: make(map[string]*BackupInfo),
+	}
+	if err := s.loadBackupCatalog(); err != nil || len(s.backups) != 0 {
+		t.Errorf("missing catalog: err %v, %d backups", err, len(s.backups))
+	}
+}
@@ -0,0 +1,357 @@
+package supervisor
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Backup encryption format
+//
+//	header: magic(8) | version(1) | scrypt logN(1) | r(1) | p(1) | salt(16) | nonce prefix(7)
+//	body:   AES-256-GCM sealed chunks of backupChunkSize plaintext bytes
+//
+// Each chunk nonce is prefix(7) | counter(4, big endian) | last(1), so chunks
+// cannot be reordered and a truncated archive fails authentication.
+const (
+	backupEncMagic      = "TMIDBENC"
+	backupEncVersion    = 1
+	backupEncSuffix     = ".enc"
+	backupChunkSize     = 64 * 1024
+	backupSaltSize      = 16
+	backupNoncePrefix   = 7
+	backupEncHeaderSize = len(backupEncMagic) + 4 + backupSaltSize + backupNoncePrefix
+
+	// scrypt 기본 파라미터 (N=2^15, r=8, p=1)
+	backupScryptLogN = 15
+	backupScryptR    = 8
+	backupScryptP    = 1
+)
+
+// ErrBackupPassphraseRequired is returned when an encrypted backup is opened without a passphrase
+var ErrBackupPassphraseRequired = errors.New("backup is encrypted; a passphrase is required")
+
+// BackupEncryption records how a backup archive was encrypted
+type BackupEncryption struct {
+	Algorithm string `json:"algorithm"`
+	KDF       string `json:"kdf"`
+	KDFParams string `json:"kdf_params"`
+	Salt      string `json:"salt"`
+	ChunkSize int    `json:"chunk_size"`
+}
+
+// backupEncHeader is the parsed file header
+type backupEncHeader struct {
+	logN, r, p  uint8
+	salt        []byte
+	noncePrefix []byte
+}
+
+func (h *backupEncHeader) marshal() []byte {
+	buf := make([]byte, 0, backupEncHeaderSize)
+	buf = append(buf, backupEncMagic...)
+	buf = append(buf, backupEncVersion, h.logN, h.r, h.p)
+	buf = append(buf, h.salt...)
+	buf = append(buf, h.noncePrefix...)
+	return buf
+}
+
+func parseBackupEncHeader(data []byte) (*backupEncHeader, error) {
+	if len(data) < backupEncHeaderSize || string(data[:len(backupEncMagic)]) != backupEncMagic {
+		return nil, errors.New("not an encrypted backup")
+	}
+
+	p := data[len(backupEncMagic):]
+	if p[0] != backupEncVersion {
+		return nil, fmt.Errorf("unsupported backup encryption version %d", p[0])
+	}
+
+	h := &backupEncHeader{logN: p[1], r: p[2], p: p[3]}
+	p = p[4:]
+	h.salt = append([]byte(nil), p[:backupSaltSize]...)
+	h.noncePrefix = append([]byte(nil), p[backupSaltSize:backupSaltSize+backupNoncePrefix]...)
+
+	if h.logN < 10 || h.logN > 20 || h.r == 0 || h.p == 0 {
+		return nil, errors.New("invalid key derivation parameters")
+	}
+	return h, nil
+}
+
+func (h *backupEncHeader) aead(passphrase string) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), h.salt, 1<<h.logN, int(h.r), int(h.p), 32)
+	if err != nil {
+		return nil, fmt.Errorf("key derivation failed: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (h *backupEncHeader) info() *BackupEncryption {
+	return &BackupEncryption{
+		Algorithm: "AES-256-GCM",
+		KDF:       "scrypt",
+		KDFParams: fmt.Sprintf("N=%d,r=%d,p=%d", 1<<h.logN, h.r, h.p),
+		Salt:      hex.EncodeToString(h.salt),
+		ChunkSize: backupChunkSize,
+	}
+}
+
+func chunkNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, 0, backupNoncePrefix+5)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, counter)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+// encryptWriter seals plaintext in fixed-size chunks
+type encryptWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	buf     []byte
+	closed  bool
+}
+
+// newEncryptWriter writes the header to w and returns a writer that encrypts
+// everything written to it. Close must be called to write the final chunk.
+func newEncryptWriter(w io.Writer, passphrase string) (io.WriteCloser, *BackupEncryption, error) {
+	if passphrase == "" {
+		return nil, nil, ErrBackupPassphraseRequired
+	}
+
+	h := &backupEncHeader{
+		logN:        backupScryptLogN,
+		r:           backupScryptR,
+		p:           backupScryptP,
+		salt:        make([]byte, backupSaltSize),
+		noncePrefix: make([]byte, backupNoncePrefix),
+	}
+	if _, err := rand.Read(h.salt); err != nil {
+		return nil, nil, err
+	}
+	if _, err := rand.Read(h.noncePrefix); err != nil {
+		return nil, nil, err
+	}
+
+	aead, err := h.aead(passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := w.Write(h.marshal()); err != nil {
+		return nil, nil, err
+	}
+
+	return &encryptWriter{
+		w:      w,
+		aead:   aead,
+		prefix: h.noncePrefix,
+		buf:    make([]byte, 0, backupChunkSize),
+	}, h.info(), nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("write to closed encrypt writer")
+	}
+
+	written := 0
+	for len(p) > 0 {
+		// 버퍼가 가득 찬 상태에서 추가 데이터가 오면 마지막 청크가 아님이 확정됨
+		if len(e.buf) == backupChunkSize {
+			if err := e.seal(false); err != nil {
+				return written, err
+			}
+		}
+
+		n := copy(e.buf[len(e.buf):backupChunkSize], p)
+		e.buf = e.buf[:len(e.buf)+n]
+		p = p[n:]
+		written += n
+	}
+
+	return written, nil
+}
+
+func (e *encryptWriter) seal(last bool) error {
+	sealed := e.aead.Seal(nil, chunkNonce(e.prefix, e.counter, last), e.buf, nil)
+	if _, err := e.w.Write(sealed); err != nil {
+		return err
+	}
+	e.counter++
+	e.buf = e.buf[:0]
+	return nil
+}
+
+// Close writes the final (possibly empty) chunk
+func (e *encryptWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.seal(true)
+}
+
+// decryptReader opens chunks written by encryptWriter
+type decryptReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	chunk   []byte
+	plain   []byte
+	done    bool
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+func (d *decryptReader) next() error {
+	n, err := io.ReadFull(d.r, d.chunk)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err
+	}
+	if n < d.aead.Overhead() {
+		return errors.New("encrypted backup is truncated")
+	}
+
+	// 청크를 꽉 채웠더라도 뒤에 데이터가 없으면 마지막 청크
+	last := n < len(d.chunk)
+	if !last {
+		if _, err := d.r.Peek(1); err == io.EOF {
+			last = true
+		}
+	}
+
+	plain, err := d.aead.Open(d.chunk[:0:0], chunkNonce(d.prefix, d.counter, last), d.chunk[:n], nil)
+	if err != nil {
+		return errors.New("failed to decrypt backup: wrong passphrase or corrupted archive")
+	}
+
+	d.counter++
+	d.plain = plain
+	d.done = last
+	return nil
+}
+
+// openBackupStream returns a plaintext reader for a backup archive stream.
+// Encrypted archives are detected by their header and decrypted with passphrase;
+// gzip compression is detected by its magic bytes.
+func openBackupStream(r io.Reader, passphrase string) (io.Reader, *BackupEncryption, error) {
+	br := bufio.NewReaderSize(r, backupChunkSize+64)
+
+	header, _ := br.Peek(backupEncHeaderSize)
+	var encryption *BackupEncryption
+	var plain io.Reader = br
+
+	if bytes.HasPrefix(header, []byte(backupEncMagic)) {
+		h, err := parseBackupEncHeader(header)
+		if err != nil {
+			return nil, nil, err
+		}
+		if passphrase == "" {
+			return nil, nil, ErrBackupPassphraseRequired
+		}
+
+		aead, err := h.aead(passphrase)
+		if err != nil {
+			return nil, nil, err
+		}
+		br.Discard(backupEncHeaderSize)
+
+		encryption = h.info()
+		plain = bufio.NewReader(&decryptReader{
+			r:      br,
+			aead:   aead,
+			prefix: h.noncePrefix,
+			chunk:  make([]byte, backupChunkSize+aead.Overhead()),
+		})
+	}
+
+	return plain, encryption, nil
+}
+
+// backupArchive is a plaintext tar stream over a (possibly encrypted and compressed) backup file
+type backupArchive struct {
+	io.Reader
+	Encryption *BackupEncryption
+	Compressed bool
+
+	closers []io.Closer
+}
+
+// Close releases the gzip reader and the underlying file
+func (a *backupArchive) Close() error {
+	var firstErr error
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		if err := a.closers[i].Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// openBackupArchive opens a backup file for reading its tar entries.
+// Encryption and gzip compression are detected from the file contents.
+func openBackupArchive(path, passphrase string) (*backupArchive, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &backupArchive{closers: []io.Closer{file}}
+
+	plain, encryption, err := openBackupStream(file, passphrase)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	archive.Encryption = encryption
+
+	br := bufio.NewReader(plain)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gzReader, err := gzip.NewReader(br)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("invalid gzip format: %w", err)
+		}
+		archive.closers = append(archive.closers, gzReader)
+		archive.Compressed = true
+		archive.Reader = gzReader
+	} else {
+		archive.Reader = br
+	}
+
+	return archive, nil
+}
@@ -0,0 +1,120 @@
+package supervisor
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func encryptBytes(t *testing.T, plain []byte, passphrase string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, info, err := newEncryptWriter(&buf, passphrase)
+	if err != nil {
+		t.Fatalf("newEncryptWriter: %v", err)
+	}
+	if info.Algorithm != "AES-256-GCM" || info.KDF != "scrypt" {
+		t.Errorf("encryption info = %+v", info)
+	}
+	if _, err := w.Write(plain); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestBackupEncryptionRoundTrip(t *testing.T) {
+	for _, size := range []int{0, 100, backupChunkSize, 3*backupChunkSize + 17} {
+		plain := make([]byte, size)
+		rand.Read(plain)
+
+		sealed := encryptBytes(t, plain, "secret")
+
+		r, encryption, err := openBackupStream(bytes.NewReader(sealed), "secret")
+		if err != nil {
+			t.Fatalf("size %d: openBackupStream: %v", size, err)
+		}
+		if encryption == nil {
+			t.Fatalf("size %d: encryption not detected", size)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("size %d: ReadAll: %v", size, err)
+		}
+		if !bytes.Equal(got, plain) {
+			t.Errorf("size %d: plaintext mismatch", size)
+		}
+	}
+}
+
+func TestBackupEncryptionRejectsTampering(t *testing.T) {
+	plain := bytes.Repeat([]byte("tmidb"), backupChunkSize/2)
+	sealed := encryptBytes(t, plain, "secret")
+
+	if _, _, err := openBackupStream(bytes.NewReader(sealed), ""); err != ErrBackupPassphraseRequired {
+		t.Errorf("missing passphrase: err = %v", err)
+	}
+
+	cases := map[string]struct {
+		data       []byte
+		passphrase string
+	}{
+		"wrong passphrase": {sealed, "wrong"},
+		"truncated":        {sealed[:backupEncHeaderSize+backupChunkSize+16], "secret"},
+		"flipped bit":      {append(append([]byte(nil), sealed[:100]...), append([]byte{sealed[100] ^ 1}, sealed[101:]...)...), "secret"},
+	}
+	for name, tc := range cases {
+		r, _, err := openBackupStream(bytes.NewReader(tc.data), tc.passphrase)
+		if err == nil {
+			_, err = io.ReadAll(r)
+		}
+		if err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestOpenBackupArchiveDetectsFormat(t *testing.T) {
+	dir := t.TempDir()
+	plain := []byte("tar contents")
+
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	gw.Write(plain)
+	gw.Close()
+
+	files := map[string][]byte{
+		"plain.tar":         plain,
+		"backup.tar.gz":     gz.Bytes(),
+		"backup.tar.gz.enc": encryptBytes(t, gz.Bytes(), "secret"),
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		archive, err := openBackupArchive(path, "secret")
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		got, err := io.ReadAll(archive)
+		archive.Close()
+		if err != nil || !bytes.Equal(got, plain) {
+			t.Errorf("%s: got %q, err %v", name, got, err)
+		}
+		if archive.Compressed != (name != "plain.tar") {
+			t.Errorf("%s: compressed = %v", name, archive.Compressed)
+		}
+		if (archive.Encryption != nil) != (filepath.Ext(name) == backupEncSuffix) {
+			t.Errorf("%s: encryption = %+v", name, archive.Encryption)
+		}
+	}
+}
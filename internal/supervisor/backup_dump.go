@@ -0,0 +1,203 @@
+package supervisor
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// dumpEntryName is the single-entry layout used by older backups
+	dumpEntryName = "database/tmidb.sql"
+	// dumpPartPrefix names the chunked entries written by streaming backups
+	dumpPartPrefix = "database/tmidb.sql.part-"
+	// dumpPartSize bounds the memory held while streaming pg_dump output
+	dumpPartSize = 16 * 1024 * 1024
+)
+
+// progressWriter counts bytes written into the backup archive
+type progressWriter struct {
+	w        io.Writer
+	progress *BackupProgress
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.progress.BytesWritten += int64(n)
+	return n, err
+}
+
+// backupDatabase streams pg_dump output into the archive as fixed-size tar entries,
+// so the supervisor never holds more than one part of the dump in memory
+func (s *Supervisor) backupDatabase(tarWriter *manifestWriter, progress *BackupProgress) error {
+	cmd := exec.Command("pg_dump", "-h", "localhost", "-p", "5432", "-U", "postgres", "tmidb")
+	cmd.Env = append(os.Environ(), "PGPASSWORD=postgres")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start pg_dump: %v", err)
+	}
+
+	// 덤프를 흘려보내며 테이블별 행 수를 세어 매니페스트에 기록
+	stats := newDumpStats()
+	dumped, err := writeDumpParts(tarWriter, io.TeeReader(stdout, stats), dumpPartSize, func(total int64) {
+		progress.Current = fmt.Sprintf("Backing up database (%.1f MB dumped)", float64(total)/(1024*1024))
+	})
+	if err != nil {
+		// pg_dump이 파이프 쓰기에서 멈추지 않도록 종료
+		cmd.Process.Kill()
+		cmd.Wait()
+		return err
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("pg_dump failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	if dumped == 0 {
+		return fmt.Errorf("pg_dump produced no output")
+	}
+
+	entry := tarWriter.manifest.component("database")
+	entry.Tables = stats.Tables
+	entry.ServerVersion = stats.ServerVersion
+	entry.DumpVersion = stats.DumpVersion
+	entry.SchemaHash = stats.SchemaHash()
+	return nil
+}
+
+// writeDumpParts copies r into numbered tar entries of at most partSize bytes
+// and returns the total number of bytes copied
+func writeDumpParts(tarWriter archiveWriter, r io.Reader, partSize int, onPart func(total int64)) (int64, error) {
+	buf := make([]byte, partSize)
+	var total int64
+
+	for part := 0; ; part++ {
+		n, err := io.ReadFull(r, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return total, fmt.Errorf("failed to read pg_dump output: %v", err)
+		}
+		if n == 0 {
+			return total, nil
+		}
+
+		header := &tar.Header{
+			Name:    fmt.Sprintf("%s%05d", dumpPartPrefix, part),
+			Mode:    0644,
+			Size:    int64(n),
+			ModTime: time.Now(),
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return total, err
+		}
+		if _, err := tarWriter.Write(buf[:n]); err != nil {
+			return total, err
+		}
+
+		total += int64(n)
+		if onPart != nil {
+			onPart(total)
+		}
+
+		if n < partSize {
+			return total, nil
+		}
+	}
+}
+
+// isDumpEntry reports whether a tar entry holds (part of) the database dump
+func isDumpEntry(name string) bool {
+	return name == dumpEntryName || strings.HasPrefix(name, dumpPartPrefix)
+}
+
+// restoreDatabase streams the dump entries from the archive into psql
+func (s *Supervisor) restoreDatabase(tarReader *tar.Reader) error {
+	return restoreDump(tarReader, psqlCommand("tmidb"))
+}
+
+// psqlCommand runs psql against a database of the managed PostgreSQL
+func psqlCommand(database string, args ...string) *exec.Cmd {
+	cmd := exec.Command("psql", append([]string{"-h", "localhost", "-p", "5432", "-U", "postgres", "-d", database}, args...)...)
+	cmd.Env = append(os.Environ(), "PGPASSWORD=postgres")
+	return cmd
+}
+
+// restoreDump streams the dump entries from the archive into cmd, which is
+// started at the first dump entry
+func restoreDump(tarReader *tar.Reader, cmd *exec.Cmd) error {
+	var stdin io.WriteCloser
+	var output bytes.Buffer
+	started := false
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if started {
+				stdin.Close()
+				cmd.Process.Kill()
+				cmd.Wait()
+			}
+			return err
+		}
+
+		if !isDumpEntry(header.Name) {
+			continue
+		}
+
+		// 첫 덤프 엔트리에서 psql 시작, 이후 파트는 순서대로 이어서 전달
+		if !started {
+			cmd.Stdout = &output
+			cmd.Stderr = &output
+
+			stdin, err = cmd.StdinPipe()
+			if err != nil {
+				return err
+			}
+			if err := cmd.Start(); err != nil {
+				return fmt.Errorf("failed to start psql: %v", err)
+			}
+			started = true
+		}
+
+		if _, err := io.Copy(stdin, tarReader); err != nil {
+			stdin.Close()
+			waitErr := cmd.Wait()
+			return fmt.Errorf("psql failed: %v, output: %s", firstError(waitErr, err), output.String())
+		}
+	}
+
+	if !started {
+		return fmt.Errorf("database backup not found in archive")
+	}
+
+	stdin.Close()
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("psql failed: %v, output: %s", err, output.String())
+	}
+
+	return nil
+}
+
+// firstError returns the first non-nil error
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
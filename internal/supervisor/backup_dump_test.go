@@ -0,0 +1,45 @@
+package supervisor
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestWriteDumpParts(t *testing.T) {
+	dump := bytes.Repeat([]byte("INSERT INTO t VALUES (1);\n"), 100)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	parts := 0
+	total, err := writeDumpParts(tw, bytes.NewReader(dump), 1000, func(int64) { parts++ })
+	if err != nil {
+		t.Fatalf("writeDumpParts: %v", err)
+	}
+	tw.Close()
+
+	if total != int64(len(dump)) || parts != 3 {
+		t.Errorf("total = %d, parts = %d; want %d, 3", total, parts, len(dump))
+	}
+
+	// 파트를 순서대로 이어 붙이면 원본 덤프가 되어야 함
+	var restored bytes.Buffer
+	tr := tar.NewReader(&buf)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !isDumpEntry(header.Name) {
+			t.Errorf("unexpected entry %s", header.Name)
+		}
+		io.Copy(&restored, tr)
+	}
+	if !bytes.Equal(restored.Bytes(), dump) {
+		t.Error("reassembled dump does not match")
+	}
+}
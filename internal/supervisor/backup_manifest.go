@@ -0,0 +1,225 @@
+package supervisor
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/version"
+)
+
+const (
+	// manifestEntryName is the last entry of a backup archive
+	manifestEntryName = "manifest.json"
+	// backupChecksumSuffix names the sidecar file holding the SHA-256 of a backup
+	backupChecksumSuffix = ".sha256"
+	// maxDumpLine bounds how much of a single dump line is kept for parsing
+	maxDumpLine = 64 * 1024
+)
+
+// BackupManifest records what a backup archive holds, written as its last entry
+type BackupManifest struct {
+	Version      int                           `json:"version"`
+	Created      time.Time                     `json:"created"`
+	TmidbVersion string                        `json:"tmidb_version"`
+	Components   map[string]*ManifestComponent `json:"components"`
+}
+
+// ManifestComponent counts the archive entries of one component
+type ManifestComponent struct {
+	Entries int   `json:"entries"`
+	Bytes   int64 `json:"bytes"`
+
+	// database only: rows per table and the versions from the dump header
+	Tables        map[string]int64 `json:"tables,omitempty"`
+	ServerVersion string           `json:"server_version,omitempty"`
+	DumpVersion   string           `json:"pg_dump_version,omitempty"`
+	SchemaHash    string           `json:"schema_hash,omitempty"`
+}
+
+func newBackupManifest() *BackupManifest {
+	return &BackupManifest{
+		Version:      1,
+		Created:      time.Now(),
+		TmidbVersion: version.Version,
+		Components:   make(map[string]*ManifestComponent),
+	}
+}
+
+// component returns the entry of a component, creating it on first use
+func (m *BackupManifest) component(name string) *ManifestComponent {
+	entry, ok := m.Components[name]
+	if !ok {
+		entry = &ManifestComponent{}
+		m.Components[name] = entry
+	}
+	return entry
+}
+
+// record counts a tar entry under the component named by its first path element
+func (m *BackupManifest) record(header *tar.Header) {
+	component, _, found := strings.Cut(header.Name, "/")
+	if !found {
+		return
+	}
+	entry := m.component(component)
+	entry.Entries++
+	if header.Typeflag == tar.TypeReg {
+		entry.Bytes += header.Size
+	}
+}
+
+// archiveWriter is the part of *tar.Writer the component backups use
+type archiveWriter interface {
+	WriteHeader(header *tar.Header) error
+	Write(b []byte) (int, error)
+}
+
+// manifestWriter records every entry written through it in the manifest
+type manifestWriter struct {
+	*tar.Writer
+	manifest *BackupManifest
+}
+
+func (w *manifestWriter) WriteHeader(header *tar.Header) error {
+	if header.Typeflag == 0 {
+		header.Typeflag = tar.TypeReg
+	}
+	if err := w.Writer.WriteHeader(header); err != nil {
+		return err
+	}
+	w.manifest.record(header)
+	return nil
+}
+
+// writeManifest appends the manifest as the last archive entry
+func (w *manifestWriter) writeManifest() error {
+	data, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	header := &tar.Header{
+		Name:    manifestEntryName,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := w.Writer.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = w.Writer.Write(data)
+	return err
+}
+
+// dumpStats follows a plain pg_dump stream: the versions in its header, the
+// rows of every COPY block, a hash of the schema statements and whether the
+// completion trailer was reached
+type dumpStats struct {
+	ServerVersion string
+	DumpVersion   string
+	Complete      bool
+	Bytes         int64
+	Tables        map[string]int64
+
+	copying string // table of the COPY block being read
+	line    []byte
+	schema  hash.Hash
+}
+
+func newDumpStats() *dumpStats {
+	return &dumpStats{Tables: make(map[string]int64), schema: sha256.New()}
+}
+
+// SchemaHash is the SHA-256 of the dump's DDL statements. Comments, session
+// settings and sequence positions are left out, so two dumps of the same schema
+// hash alike whatever their data or pg_dump version.
+func (d *dumpStats) SchemaHash() string {
+	return hex.EncodeToString(d.schema.Sum(nil))
+}
+
+// Write consumes dump output; it never fails so it can sit behind an io.TeeReader
+func (d *dumpStats) Write(p []byte) (int, error) {
+	d.Bytes += int64(len(p))
+	rest := p
+	for len(rest) > 0 {
+		i := bytes.IndexByte(rest, '\n')
+		if i < 0 {
+			d.appendLine(rest)
+			break
+		}
+		d.appendLine(rest[:i])
+		d.parseLine(string(d.line))
+		d.line = d.line[:0]
+		rest = rest[i+1:]
+	}
+	return len(p), nil
+}
+
+// appendLine keeps at most maxDumpLine bytes of a line; long data rows only need counting
+func (d *dumpStats) appendLine(b []byte) {
+	if room := maxDumpLine - len(d.line); room > 0 {
+		if len(b) > room {
+			b = b[:room]
+		}
+		d.line = append(d.line, b...)
+	}
+}
+
+func (d *dumpStats) parseLine(line string) {
+	if d.copying != "" {
+		if line == `\.` {
+			d.copying = ""
+		} else {
+			d.Tables[d.copying]++
+		}
+		return
+	}
+
+	if table, _, ok := parseCopyHeader(line); ok {
+		d.copying = table
+		d.Tables[table] += 0
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(line, "-- Dumped from database version "):
+		d.ServerVersion = strings.TrimPrefix(line, "-- Dumped from database version ")
+	case strings.HasPrefix(line, "-- Dumped by pg_dump version "):
+		d.DumpVersion = strings.TrimPrefix(line, "-- Dumped by pg_dump version ")
+	case line == "-- PostgreSQL database dump complete":
+		d.Complete = true
+	case line == "", strings.HasPrefix(line, "--"), strings.HasPrefix(line, "SET "),
+		strings.HasPrefix(line, "SELECT pg_catalog.set_config("), strings.HasPrefix(line, "SELECT pg_catalog.setval("):
+	default:
+		d.schema.Write([]byte(line))
+		d.schema.Write([]byte{'\n'})
+	}
+}
+
+// writeBackupChecksum stores the SHA-256 of a backup next to it, so it can be
+// checked after the supervisor restarted
+func writeBackupChecksum(backupPath, checksum string) error {
+	line := fmt.Sprintf("%s  %s\n", checksum, filepath.Base(backupPath))
+	return os.WriteFile(backupPath+backupChecksumSuffix, []byte(line), 0644)
+}
+
+// readBackupChecksum reads the sidecar checksum of a backup ("" if there is none)
+func readBackupChecksum(backupPath string) string {
+	data, err := os.ReadFile(backupPath + backupChecksumSuffix)
+	if err != nil {
+		return ""
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
@@ -0,0 +1,519 @@
+package supervisor
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// RestoreScope limits a database restore to one organization and, optionally,
+// one of its categories
+type RestoreScope struct {
+	Org      string `json:"org"` // name or org_id
+	Category string `json:"category,omitempty"`
+	OrgID    string `json:"org_id,omitempty"`
+}
+
+func (r RestoreScope) String() string {
+	if r.Category != "" {
+		return fmt.Sprintf("organization %s, category %s", r.Org, r.Category)
+	}
+	return "organization " + r.Org
+}
+
+// scopePair is a (target_id, category_name) key of target_categories
+type scopePair struct {
+	target   string
+	category string
+}
+
+// scopeFilter is a scope resolved against the dump of one backup
+type scopeFilter struct {
+	RestoreScope
+	pairs   map[scopePair]bool
+	targets map[string]bool
+}
+
+// orgCondition selects the live rows of the scope in a table with org_id and category_name
+func (f *scopeFilter) orgCondition() string {
+	cond := "org_id = " + quoteLiteral(f.OrgID)
+	if f.Category != "" {
+		cond += " AND category_name = " + quoteLiteral(f.Category)
+	}
+	return cond
+}
+
+func (f *scopeFilter) matchOrg(row copyRow) bool {
+	return row.get("org_id") == f.OrgID && (f.Category == "" || row.get("category_name") == f.Category)
+}
+
+// scopedTable says how the rows of one table are picked for a scoped restore.
+// Tables without a condition are shared with other scopes: missing rows are
+// added, existing rows are left alone.
+type scopedTable struct {
+	name      string
+	match     func(f *scopeFilter, row copyRow) bool
+	condition func(f *scopeFilter) string
+}
+
+// scopedTables are listed parents first; deletes run in reverse order
+var scopedTables = []scopedTable{
+	{
+		name:  "public.organizations",
+		match: func(f *scopeFilter, row copyRow) bool { return row.get("org_id") == f.OrgID },
+	},
+	{
+		name:  "public.target",
+		match: func(f *scopeFilter, row copyRow) bool { return f.targets[row.get("target_id")] },
+	},
+	{name: "public.category_schemas", match: (*scopeFilter).matchOrg, condition: (*scopeFilter).orgCondition},
+	{name: "public.target_categories", match: (*scopeFilter).matchOrg, condition: (*scopeFilter).orgCondition},
+	{
+		name: "public.ts_obs",
+		match: func(f *scopeFilter, row copyRow) bool {
+			return f.pairs[scopePair{row.get("target_id"), row.get("category_name")}]
+		},
+		condition: func(f *scopeFilter) string {
+			return "(target_id, category_name) IN (SELECT target_id, category_name FROM public.target_categories WHERE " + f.orgCondition() + ")"
+		},
+	},
+	{name: "public.file_attachments", match: (*scopeFilter).matchOrg, condition: (*scopeFilter).orgCondition},
+}
+
+// ScopedTablePreview compares the rows of one table in the backup with the
+// live rows a scoped restore would replace
+type ScopedTablePreview struct {
+	Table   string `json:"table"`
+	Backup  int64  `json:"backup"`
+	Current *int64 `json:"current,omitempty"`
+	Action  string `json:"action"` // "replace", "add missing"
+}
+
+// copyRow is a data row of a COPY block
+type copyRow struct {
+	columns []string
+	fields  []string
+}
+
+// get returns the decoded value of a column ("" for NULL or an unknown column)
+func (r copyRow) get(column string) string {
+	for i, name := range r.columns {
+		if name == column && i < len(r.fields) {
+			value, _ := copyValue(r.fields[i])
+			return value
+		}
+	}
+	return ""
+}
+
+// parseCopyHeader splits `COPY public.t (a, b) FROM stdin;` into the table and its columns
+func parseCopyHeader(line string) (string, []string, bool) {
+	if !strings.HasPrefix(line, "COPY ") || !strings.HasSuffix(line, " FROM stdin;") {
+		return "", nil, false
+	}
+	spec := strings.TrimSuffix(strings.TrimPrefix(line, "COPY "), " FROM stdin;")
+	table, list, found := strings.Cut(spec, " (")
+	if !found {
+		return table, nil, true
+	}
+	var columns []string
+	for _, column := range strings.Split(strings.TrimSuffix(list, ")"), ",") {
+		columns = append(columns, strings.Trim(strings.TrimSpace(column), `"`))
+	}
+	return table, columns, true
+}
+
+// copyValue decodes a field of the COPY text format; null reports \N
+func copyValue(field string) (value string, null bool) {
+	if field == `\N` {
+		return "", true
+	}
+	if !strings.Contains(field, `\`) {
+		return field, false
+	}
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		c := field[i]
+		if c != '\\' || i+1 == len(field) {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch field[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'v':
+			b.WriteByte('\v')
+		default:
+			b.WriteByte(field[i])
+		}
+	}
+	return b.String(), false
+}
+
+// dumpRows walks the COPY data rows of a plain pg_dump stream
+type dumpRows struct {
+	r      *bufio.Reader
+	tables map[string]bool // only rows of these tables are returned
+	// onBlock is called at the start of every COPY block; returning false ends the scan
+	onBlock func(table string, columns []string) bool
+
+	Table string
+	Row   copyRow
+	Line  string // raw row including the newline
+
+	copying bool
+}
+
+func newDumpRows(r io.Reader, tables ...string) *dumpRows {
+	d := &dumpRows{r: bufio.NewReaderSize(r, 64*1024), tables: make(map[string]bool)}
+	for _, table := range tables {
+		d.tables[table] = true
+	}
+	return d
+}
+
+// Next advances to the next row of a wanted table; io.EOF at the end of the dump
+func (d *dumpRows) Next() error {
+	for {
+		line, err := d.r.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return err
+		}
+		text := strings.TrimSuffix(line, "\n")
+
+		if d.copying {
+			if text == `\.` {
+				d.copying = false
+				continue
+			}
+			if d.tables[d.Table] {
+				d.Line = line
+				if !strings.HasSuffix(d.Line, "\n") {
+					d.Line += "\n"
+				}
+				d.Row.fields = strings.Split(text, "\t")
+				return nil
+			}
+			continue
+		}
+
+		if table, columns, ok := parseCopyHeader(text); ok {
+			d.copying = true
+			d.Table = table
+			d.Row = copyRow{columns: columns}
+			if d.onBlock != nil && !d.onBlock(table, columns) {
+				return io.EOF
+			}
+		}
+	}
+}
+
+// dumpStream reads the dump entries of a backup archive as one stream
+type dumpStream struct {
+	tr     *tar.Reader
+	inDump bool
+}
+
+func (d *dumpStream) Read(p []byte) (int, error) {
+	for {
+		if d.inDump {
+			n, err := d.tr.Read(p)
+			if err == io.EOF {
+				d.inDump = false
+				if n > 0 {
+					return n, nil
+				}
+				continue
+			}
+			return n, err
+		}
+		header, err := d.tr.Next()
+		if err != nil {
+			return 0, err
+		}
+		d.inDump = isDumpEntry(header.Name)
+	}
+}
+
+// readDumpTable calls fn for every row of one table in the backup's dump and
+// stops reading once the next table's COPY block starts
+func readDumpTable(backupPath, passphrase, table string, fn func(row copyRow)) error {
+	archive, err := openBackupArchive(backupPath, passphrase)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	rows := newDumpRows(&dumpStream{tr: tar.NewReader(archive)}, table)
+	seen := false
+	rows.onBlock = func(name string, _ []string) bool {
+		if seen {
+			return false
+		}
+		seen = name == table
+		return true
+	}
+	for {
+		if err := rows.Next(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		fn(rows.Row)
+	}
+}
+
+// resolveRestoreScope looks the organization and the targets of the scope up in
+// the backup's dump. pg_dump writes table data in name order, so the
+// organizations block is read first and target_categories in a second pass.
+func resolveRestoreScope(backupPath, passphrase string, scope RestoreScope) (*scopeFilter, error) {
+	f := &scopeFilter{RestoreScope: scope, pairs: make(map[scopePair]bool), targets: make(map[string]bool)}
+
+	err := readDumpTable(backupPath, passphrase, "public.organizations", func(row copyRow) {
+		if id := row.get("org_id"); id == scope.Org || row.get("name") == scope.Org {
+			f.OrgID = id
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read organizations: %v", err)
+	}
+	if f.OrgID == "" {
+		return nil, fmt.Errorf("organization %q not found in backup", scope.Org)
+	}
+
+	err = readDumpTable(backupPath, passphrase, "public.target_categories", func(row copyRow) {
+		if f.matchOrg(row) {
+			f.pairs[scopePair{row.get("target_id"), row.get("category_name")}] = true
+			f.targets[row.get("target_id")] = true
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read target categories: %v", err)
+	}
+	return f, nil
+}
+
+// writeScopedRestore turns the dump into a psql script that replaces the rows of
+// the scope in one transaction. The backup's rows are loaded into temporary
+// tables first, so nothing is deleted unless the whole dump could be read.
+// It returns the rows taken from the backup per table.
+func writeScopedRestore(w io.Writer, dump io.Reader, f *scopeFilter) (map[string]int64, error) {
+	bw := bufio.NewWriter(w)
+	tables := make(map[string]scopedTable, len(scopedTables))
+	names := make([]string, len(scopedTables))
+	fmt.Fprintln(bw, "BEGIN;")
+	for i, table := range scopedTables {
+		tables[table.name] = table
+		names[i] = table.name
+		fmt.Fprintf(bw, "CREATE TEMP TABLE %s (LIKE %s INCLUDING DEFAULTS) ON COMMIT DROP;\n", stagingTable(table.name), table.name)
+	}
+
+	counts := make(map[string]int64)
+	columns := make(map[string][]string) // 덤프에 있던 테이블의 컬럼 (없는 테이블은 건드리지 않음)
+	open := ""
+	closeBlock := func() {
+		if open != "" {
+			fmt.Fprintln(bw, `\.`)
+			open = ""
+		}
+	}
+
+	rows := newDumpRows(dump, names...)
+	rows.onBlock = func(table string, cols []string) bool {
+		if _, ok := tables[table]; ok {
+			columns[table] = cols
+			counts[table] = 0
+		}
+		return true
+	}
+	for {
+		err := rows.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !tables[rows.Table].match(f, rows.Row) {
+			continue
+		}
+		if open != rows.Table {
+			closeBlock()
+			fmt.Fprintf(bw, "COPY %s (%s) FROM stdin;\n", stagingTable(rows.Table), strings.Join(rows.Row.columns, ", "))
+			open = rows.Table
+		}
+		bw.WriteString(rows.Line)
+		counts[rows.Table]++
+	}
+	closeBlock()
+
+	// 자식 테이블부터 삭제하고 부모 테이블부터 다시 채움
+	for i := len(scopedTables) - 1; i >= 0; i-- {
+		table := scopedTables[i]
+		if _, ok := columns[table.name]; ok && table.condition != nil {
+			fmt.Fprintf(bw, "DELETE FROM %s WHERE %s;\n", table.name, table.condition(f))
+		}
+	}
+	for _, table := range scopedTables {
+		cols, ok := columns[table.name]
+		if !ok {
+			continue
+		}
+		list := strings.Join(cols, ", ")
+		conflict := ""
+		if table.condition == nil {
+			conflict = " ON CONFLICT DO NOTHING"
+		}
+		fmt.Fprintf(bw, "INSERT INTO %s (%s) SELECT %s FROM %s%s;\n", table.name, list, list, stagingTable(table.name), conflict)
+	}
+	fmt.Fprintln(bw, "COMMIT;")
+
+	return counts, bw.Flush()
+}
+
+// stagingTable names the temporary table a scoped restore loads a table into
+func stagingTable(table string) string {
+	return "restore_" + strings.TrimPrefix(table, "public.")
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// previewScopedRestore lists, per table, the rows the backup holds for the scope
+// and the live rows a restore would replace
+func previewScopedRestore(backupPath, passphrase string, scope RestoreScope) (map[string]interface{}, error) {
+	f, err := resolveRestoreScope(backupPath, passphrase, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := openBackupArchive(backupPath, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+	counts, err := writeScopedRestore(io.Discard, &dumpStream{tr: tar.NewReader(archive)}, f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read database dump: %v", err)
+	}
+
+	result := map[string]interface{}{
+		"backup":   backupPath,
+		"org":      f.Org,
+		"org_id":   f.OrgID,
+		"category": f.Category,
+		"targets":  len(f.targets),
+	}
+
+	current, err := countScopedRows(f)
+	if err != nil {
+		result["current_error"] = err.Error()
+	}
+
+	tables := make([]ScopedTablePreview, 0, len(scopedTables))
+	for _, table := range scopedTables {
+		rows, ok := counts[table.name]
+		if !ok {
+			continue
+		}
+		preview := ScopedTablePreview{Table: table.name, Backup: rows, Action: "add missing"}
+		if table.condition != nil {
+			preview.Action = "replace"
+			if n, ok := current[table.name]; ok {
+				preview.Current = &n
+			}
+		}
+		tables = append(tables, preview)
+	}
+	result["tables"] = tables
+	return result, nil
+}
+
+// countScopedRows counts the live rows a scoped restore would replace
+func countScopedRows(f *scopeFilter) (map[string]int64, error) {
+	var selects []string
+	for _, table := range scopedTables {
+		if table.condition != nil {
+			selects = append(selects, fmt.Sprintf("SELECT %s, count(*) FROM %s WHERE %s", quoteLiteral(table.name), table.name, table.condition(f)))
+		}
+	}
+	return queryCounts("tmidb", selects)
+}
+
+// performScopedRestore restores the rows of one organization or category from
+// the database dump, leaving everything else in place
+func (s *Supervisor) performScopedRestore(restoreID, backupPath, passphrase string, scope RestoreScope) {
+	progress := s.restoreProgress[restoreID]
+	if progress == nil {
+		return
+	}
+	fail := func(format string, args ...interface{}) {
+		progress.Status = "failed"
+		progress.Error = fmt.Sprintf(format, args...)
+		now := time.Now()
+		progress.EndTime = &now
+	}
+
+	progress.Current = "Resolving " + scope.String()
+	f, err := resolveRestoreScope(backupPath, passphrase, scope)
+	if err != nil {
+		fail("%v", err)
+		return
+	}
+
+	archive, err := openBackupArchive(backupPath, passphrase)
+	if err != nil {
+		fail("failed to open backup file: %v", err)
+		return
+	}
+	defer archive.Close()
+
+	progress.Current = fmt.Sprintf("Restoring %s (%d targets)", scope, len(f.targets))
+	progress.Percent = 50
+
+	var output bytes.Buffer
+	cmd := psqlCommand("tmidb", "-q", "-v", "ON_ERROR_STOP=1")
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		fail("%v", err)
+		return
+	}
+	if err := cmd.Start(); err != nil {
+		fail("failed to start psql: %v", err)
+		return
+	}
+
+	_, writeErr := writeScopedRestore(stdin, &dumpStream{tr: tar.NewReader(archive)}, f)
+	if writeErr != nil {
+		// COMMIT 전에 끊기므로 트랜잭션은 롤백됨
+		cmd.Process.Kill()
+	}
+	stdin.Close()
+	waitErr := cmd.Wait()
+	if writeErr != nil || waitErr != nil {
+		fail("failed to restore %s: %v, output: %s", scope, firstError(writeErr, waitErr), strings.TrimSpace(output.String()))
+		return
+	}
+
+	progress.Current = "Restore completed"
+	progress.Percent = 100
+	progress.Status = "completed"
+	now := time.Now()
+	progress.EndTime = &now
+}
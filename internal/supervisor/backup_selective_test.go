@@ -0,0 +1,135 @@
+package supervisor
+
+import (
+	"archive/tar"
+	"strings"
+	"testing"
+)
+
+const scopedDump = `--
+-- PostgreSQL database dump
+--
+
+COPY public.category_schemas (schema_id, org_id, category_name, version, schema_definition, is_active, created_at) FROM stdin;
+s1	org-a	sensors	1	{}	t	2024-01-01
+s2	org-a	cars	1	{}	t	2024-01-01
+s3	org-b	sensors	1	{}	t	2024-01-01
+\.
+
+COPY public.file_attachments (attachment_id, target_id, filename, s3_path, size_bytes, mime_type, uploaded_by, created_at, org_id, category_name) FROM stdin;
+\.
+
+COPY public.organizations (org_id, name, created_at) FROM stdin;
+org-a	acme	2024-01-01
+org-b	it's	2024-01-01
+\.
+
+COPY public.target (target_id, name, created_at, updated_at) FROM stdin;
+t1	one	2024-01-01	2024-01-01
+t2	two	2024-01-01	2024-01-01
+t3	three	2024-01-01	2024-01-01
+\.
+
+COPY public.target_categories (target_id, org_id, category_name, schema_version, category_data, created_at, updated_at) FROM stdin;
+t1	org-a	sensors	1	{"a":"x\\ty"}	2024-01-01	2024-01-01
+t2	org-a	cars	1	{}	2024-01-01	2024-01-01
+t3	org-b	sensors	1	{}	2024-01-01	2024-01-01
+\.
+
+COPY public.ts_obs (target_id, category_name, ts, payload) FROM stdin;
+t1	sensors	2024-01-01	{}
+t1	sensors	2024-01-02	{}
+t2	cars	2024-01-01	{}
+t3	sensors	2024-01-01	{}
+\.
+
+--
+-- PostgreSQL database dump complete
+--
+`
+
+func TestParseCopyHeader(t *testing.T) {
+	table, columns, ok := parseCopyHeader(`COPY public.users (id, "user", name) FROM stdin;`)
+	if !ok || table != "public.users" || strings.Join(columns, ",") != "id,user,name" {
+		t.Errorf("got %q %v %v", table, columns, ok)
+	}
+	if _, _, ok := parseCopyHeader("CREATE TABLE public.users (id integer);"); ok {
+		t.Error("DDL parsed as COPY header")
+	}
+
+	for field, want := range map[string]string{`plain`: "plain", `a\tb`: "a\tb", `back\\slash`: `back\slash`, `x\ny`: "x\ny"} {
+		if got, null := copyValue(field); got != want || null {
+			t.Errorf("copyValue(%q) = %q, %v", field, got, null)
+		}
+	}
+	if _, null := copyValue(`\N`); !null {
+		t.Error(`\N not decoded as NULL`)
+	}
+}
+
+func TestScopedRestore(t *testing.T) {
+	path := writeTestBackup(t, scopedDump, nil)
+
+	f, err := resolveRestoreScope(path, "", RestoreScope{Org: "acme", Category: "sensors"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.OrgID != "org-a" || len(f.targets) != 1 || !f.targets["t1"] {
+		t.Fatalf("resolved %+v", f)
+	}
+
+	archive, err := openBackupArchive(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+
+	var script strings.Builder
+	counts, err := writeScopedRestore(&script, &dumpStream{tr: tar.NewReader(archive)}, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int64{
+		"public.organizations":     1,
+		"public.target":            1,
+		"public.category_schemas":  1,
+		"public.target_categories": 1,
+		"public.ts_obs":            2,
+		"public.file_attachments":  0,
+	}
+	for table, rows := range want {
+		if counts[table] != rows {
+			t.Errorf("%s: %d rows, want %d", table, counts[table], rows)
+		}
+	}
+
+	out := script.String()
+	for _, line := range []string{
+		"BEGIN;",
+		"COPY restore_ts_obs (target_id, category_name, ts, payload) FROM stdin;\nt1\tsensors\t2024-01-01\t{}\nt1\tsensors\t2024-01-02\t{}\n\\.\n",
+		"t1\torg-a\tsensors\t1\t{\"a\":\"x\\\\ty\"}\t2024-01-01\t2024-01-01\n",
+		"DELETE FROM public.file_attachments WHERE org_id = 'org-a' AND category_name = 'sensors';",
+		"DELETE FROM public.category_schemas WHERE org_id = 'org-a' AND category_name = 'sensors';",
+		"INSERT INTO public.organizations (org_id, name, created_at) SELECT org_id, name, created_at FROM restore_organizations ON CONFLICT DO NOTHING;",
+		"INSERT INTO public.ts_obs (target_id, category_name, ts, payload) SELECT target_id, category_name, ts, payload FROM restore_ts_obs;",
+		"COMMIT;",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("script lacks %q\n%s", line, out)
+		}
+	}
+	for _, other := range []string{"org-b", "t2\t", "t3\t", "cars"} {
+		if strings.Contains(out, other) {
+			t.Errorf("script contains rows outside the scope (%q)", other)
+		}
+	}
+	// 자식 테이블을 먼저 지워야 함
+	if strings.Index(out, "DELETE FROM public.ts_obs") > strings.Index(out, "DELETE FROM public.target_categories") {
+		t.Error("ts_obs must be deleted before target_categories")
+	}
+
+	if _, err := resolveRestoreScope(path, "", RestoreScope{Org: "missing"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("unknown organization: %v", err)
+	}
+}
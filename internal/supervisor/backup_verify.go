@@ -0,0 +1,415 @@
+package supervisor
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TableCheck compares the rows of one table across the manifest, the dump and a test restore
+type TableCheck struct {
+	Table    string `json:"table"`
+	Manifest *int64 `json:"manifest,omitempty"`
+	Dump     int64  `json:"dump"`
+	Restored *int64 `json:"restored,omitempty"`
+	Status   string `json:"status"` // "ok", "mismatch", "missing", "unlisted"
+}
+
+// ComponentCheck compares the archive entries of one component with the manifest
+type ComponentCheck struct {
+	Entries         int    `json:"entries"`
+	Bytes           int64  `json:"bytes"`
+	ManifestEntries int    `json:"manifest_entries"`
+	ManifestBytes   int64  `json:"manifest_bytes"`
+	Status          string `json:"status"`
+}
+
+// TestRestore reports restoring the dump into a throwaway database
+type TestRestore struct {
+	Status   string `json:"status"` // "passed", "failed"
+	Database string `json:"database"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+// verifyBackupDeep checks a backup beyond its tar structure: the file against its
+// stored SHA-256, the SQL dump header and trailer, the archive and table row
+// counts against the manifest and, with testRestore, a restore of the dump into
+// a throwaway database
+func (s *Supervisor) verifyBackupDeep(backupPath, passphrase, expectedChecksum string, testRestore bool) map[string]interface{} {
+	result := map[string]interface{}{
+		"deep":       true,
+		"status":     "valid",
+		"integrity":  "valid",
+		"components": map[string]interface{}{},
+	}
+	var errors []string
+	fail := func(format string, args ...interface{}) {
+		errors = append(errors, fmt.Sprintf(format, args...))
+	}
+	defer func() {
+		if len(errors) > 0 {
+			result["status"] = "invalid"
+		}
+		if errors == nil {
+			errors = []string{}
+		}
+		result["errors"] = errors
+	}()
+
+	// 1. 저장된 체크섬과 비교 (원본 파일 기준)
+	checksum := map[string]interface{}{"expected": expectedChecksum, "status": "unknown"}
+	if actual, err := s.calculateChecksum(backupPath); err != nil {
+		fail("Cannot compute checksum: %v", err)
+	} else {
+		checksum["actual"] = actual
+		switch {
+		case expectedChecksum == "":
+		case strings.EqualFold(actual, expectedChecksum):
+			checksum["status"] = "match"
+		default:
+			checksum["status"] = "mismatch"
+			result["integrity"] = "invalid"
+			fail("Checksum mismatch: expected %s, got %s", expectedChecksum, actual)
+		}
+	}
+	result["checksum"] = checksum
+
+	// 2. 아카이브 전체를 읽으며 엔트리, 덤프, 매니페스트 수집
+	scan, err := scanBackupArchive(backupPath, passphrase)
+	if err != nil {
+		result["integrity"] = "invalid"
+		fail("%v", err)
+		return result
+	}
+	result["compressed"] = scan.compressed
+	result["encrypted"] = scan.encryption != nil
+	if scan.encryption != nil {
+		result["encryption"] = scan.encryption
+	}
+
+	if scan.dump != nil {
+		d := scan.dump
+		result["database"] = map[string]interface{}{
+			"server_version":  d.ServerVersion,
+			"pg_dump_version": d.DumpVersion,
+			"complete":        d.Complete,
+			"bytes":           d.Bytes,
+			"tables":          len(d.Tables),
+			"schema_hash":     d.SchemaHash(),
+		}
+		if d.DumpVersion == "" {
+			fail("Database dump has no pg_dump header")
+		}
+		if !d.Complete {
+			fail("Database dump is truncated (no completion trailer)")
+		}
+	}
+
+	// 매니페스트가 없는 이전 백업은 비교만 생략
+	manifest := scan.manifest
+	result["manifest"] = manifest
+	if manifest != nil && scan.dump != nil {
+		if db := manifest.Components["database"]; db != nil && db.SchemaHash != "" && db.SchemaHash != scan.dump.SchemaHash() {
+			fail("Schema hash %s differs from the manifest (%s)", scan.dump.SchemaHash(), db.SchemaHash)
+		}
+	}
+
+	components := make(map[string]interface{})
+	names := make(map[string]bool)
+	for name := range scan.components {
+		names[name] = true
+	}
+	if manifest != nil {
+		for name := range manifest.Components {
+			names[name] = true
+		}
+	}
+	for name := range names {
+		found := scan.components[name]
+		if found == nil {
+			found = &ManifestComponent{}
+		}
+		check := ComponentCheck{Entries: found.Entries, Bytes: found.Bytes, Status: "valid"}
+		if manifest != nil {
+			expected := manifest.Components[name]
+			if expected == nil {
+				expected = &ManifestComponent{}
+			}
+			check.ManifestEntries = expected.Entries
+			check.ManifestBytes = expected.Bytes
+			if check.Entries != expected.Entries || check.Bytes != expected.Bytes {
+				check.Status = fmt.Sprintf("%d entries / %d bytes, manifest lists %d / %d",
+					check.Entries, check.Bytes, expected.Entries, expected.Bytes)
+				fail("Component %s differs from the manifest: %s", name, check.Status)
+			}
+		}
+		components[name] = check
+	}
+	result["components"] = components
+
+	// 3. 테이블별 행 수 비교
+	var expectedTables map[string]int64
+	if manifest != nil && manifest.Components["database"] != nil {
+		expectedTables = manifest.Components["database"].Tables
+	}
+	var dumpTables map[string]int64
+	if scan.dump != nil {
+		dumpTables = scan.dump.Tables
+	}
+	tables := compareTables(expectedTables, dumpTables, manifest != nil)
+
+	// 4. 임시 데이터베이스로 복원해 보고 행 수 비교
+	if testRestore {
+		if scan.dump == nil {
+			fail("Test restore skipped: backup has no database dump")
+		} else {
+			report, restored := s.testRestoreBackup(backupPath, passphrase, tableNames(tables))
+			result["test_restore"] = report
+			if report.Status != "passed" {
+				fail("Test restore failed: %s", report.Error)
+			} else {
+				applyRestoredCounts(tables, restored)
+			}
+		}
+	}
+
+	for _, table := range tables {
+		if table.Status != "ok" {
+			fail("Table %s: %s", table.Table, describeTableCheck(table))
+		}
+	}
+	result["tables"] = tables
+
+	return result
+}
+
+// backupScan is what one pass over a backup archive found
+type backupScan struct {
+	compressed bool
+	encryption *BackupEncryption
+	components map[string]*ManifestComponent
+	dump       *dumpStats
+	manifest   *BackupManifest
+}
+
+// scanBackupArchive reads every entry of a backup, counting entries per component,
+// parsing the SQL dump and decoding the manifest
+func scanBackupArchive(backupPath, passphrase string) (*backupScan, error) {
+	archive, err := openBackupArchive(backupPath, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot open backup: %v", err)
+	}
+	defer archive.Close()
+
+	scan := &backupScan{
+		compressed: archive.Compressed,
+		encryption: archive.Encryption,
+	}
+	counted := &BackupManifest{Components: make(map[string]*ManifestComponent)}
+
+	tarReader := tar.NewReader(archive)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("TAR read error: %v", err)
+		}
+
+		switch {
+		case header.Name == manifestEntryName:
+			var manifest BackupManifest
+			if err := json.NewDecoder(tarReader).Decode(&manifest); err != nil {
+				return nil, fmt.Errorf("Invalid manifest: %v", err)
+			}
+			scan.manifest = &manifest
+			continue
+		case isDumpEntry(header.Name):
+			if scan.dump == nil {
+				scan.dump = newDumpStats()
+			}
+			if _, err := io.Copy(scan.dump, tarReader); err != nil {
+				return nil, fmt.Errorf("Read error in %s: %v", header.Name, err)
+			}
+		default:
+			// 엔트리 내용까지 읽어 압축/암호화 스트림 전체를 확인
+			if _, err := io.Copy(io.Discard, tarReader); err != nil {
+				return nil, fmt.Errorf("Read error in %s: %v", header.Name, err)
+			}
+		}
+		counted.record(header)
+	}
+
+	// 남은 데이터까지 읽어 암호화 청크 전체의 인증 태그를 확인
+	if _, err := io.Copy(io.Discard, archive); err != nil {
+		return nil, fmt.Errorf("Archive read error: %v", err)
+	}
+
+	scan.components = counted.Components
+	return scan, nil
+}
+
+// compareTables lines up the manifest's row counts with the dump's
+func compareTables(expected, dumped map[string]int64, haveManifest bool) []TableCheck {
+	names := make(map[string]bool)
+	for name := range expected {
+		names[name] = true
+	}
+	for name := range dumped {
+		names[name] = true
+	}
+
+	tables := make([]TableCheck, 0, len(names))
+	for name := range names {
+		check := TableCheck{Table: name, Status: "ok"}
+		rows, inDump := dumped[name]
+		check.Dump = rows
+		if want, ok := expected[name]; ok {
+			check.Manifest = &want
+			switch {
+			case !inDump:
+				check.Status = "missing"
+			case want != rows:
+				check.Status = "mismatch"
+			}
+		} else if haveManifest {
+			check.Status = "unlisted"
+		}
+		tables = append(tables, check)
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Table < tables[j].Table })
+	return tables
+}
+
+// applyRestoredCounts adds the counts of a test restore to the table checks
+func applyRestoredCounts(tables []TableCheck, restored map[string]int64) {
+	for i := range tables {
+		rows, ok := restored[tables[i].Table]
+		if !ok {
+			if tables[i].Status == "ok" {
+				tables[i].Status = "missing"
+			}
+			continue
+		}
+		tables[i].Restored = &rows
+		if rows != tables[i].Dump && tables[i].Status == "ok" {
+			tables[i].Status = "mismatch"
+		}
+	}
+}
+
+func tableNames(tables []TableCheck) []string {
+	names := make([]string, 0, len(tables))
+	for _, table := range tables {
+		if table.Status != "missing" {
+			names = append(names, table.Table)
+		}
+	}
+	return names
+}
+
+func describeTableCheck(t TableCheck) string {
+	parts := []string{fmt.Sprintf("dump %d", t.Dump)}
+	if t.Manifest != nil {
+		parts = append([]string{fmt.Sprintf("manifest %d", *t.Manifest)}, parts...)
+	}
+	if t.Restored != nil {
+		parts = append(parts, fmt.Sprintf("restored %d", *t.Restored))
+	}
+	return t.Status + " (" + strings.Join(parts, ", ") + ")"
+}
+
+// testRestoreBackup restores the dump into a throwaway database, counts the rows
+// of the given tables and drops the database again
+func (s *Supervisor) testRestoreBackup(backupPath, passphrase string, tables []string) (TestRestore, map[string]int64) {
+	started := time.Now()
+	report := TestRestore{
+		Status:   "failed",
+		Database: fmt.Sprintf("tmidb_verify_%d", started.UnixNano()),
+	}
+	finish := func(err error) (TestRestore, map[string]int64) {
+		report.Duration = time.Since(started).Round(time.Millisecond).String()
+		if err != nil {
+			report.Error = err.Error()
+		}
+		return report, nil
+	}
+
+	if out, err := psqlCommand("postgres", "-c", "CREATE DATABASE "+report.Database).CombinedOutput(); err != nil {
+		return finish(fmt.Errorf("failed to create database: %v: %s", err, strings.TrimSpace(string(out))))
+	}
+	defer func() {
+		if out, err := psqlCommand("postgres", "-c", "DROP DATABASE IF EXISTS "+report.Database).CombinedOutput(); err != nil {
+			log.Printf("⚠️ Failed to drop verification database %s: %v: %s", report.Database, err, strings.TrimSpace(string(out)))
+		}
+	}()
+
+	archive, err := openBackupArchive(backupPath, passphrase)
+	if err != nil {
+		return finish(err)
+	}
+	defer archive.Close()
+
+	// 오류가 나면 멈추도록 해야 실패를 알 수 있음
+	if err := restoreDump(tar.NewReader(archive), psqlCommand(report.Database, "-q", "-v", "ON_ERROR_STOP=1")); err != nil {
+		return finish(err)
+	}
+
+	restored, err := countRestoredRows(report.Database, tables)
+	if err != nil {
+		return finish(fmt.Errorf("failed to count rows: %v", err))
+	}
+
+	report.Status = "passed"
+	report.Duration = time.Since(started).Round(time.Millisecond).String()
+	return report, restored
+}
+
+// countRestoredRows counts the rows of each table (names as written by pg_dump) in one query
+func countRestoredRows(database string, tables []string) (map[string]int64, error) {
+	selects := make([]string, len(tables))
+	for i, table := range tables {
+		selects[i] = fmt.Sprintf("SELECT %s, count(*) FROM %s", quoteLiteral(table), table)
+	}
+	return queryCounts(database, selects)
+}
+
+// queryCounts runs `SELECT name, count` queries as one UNION and maps each name to its count
+func queryCounts(database string, selects []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(selects))
+	if len(selects) == 0 {
+		return counts, nil
+	}
+
+	var stderr bytes.Buffer
+	cmd := psqlCommand(database, "-At", "-F", "\t", "-v", "ON_ERROR_STOP=1", "-c", strings.Join(selects, " UNION ALL "))
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), "\t")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected count %q for %s", value, name)
+		}
+		counts[name] = n
+	}
+	return counts, scanner.Err()
+}
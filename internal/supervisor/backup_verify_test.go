@@ -0,0 +1,163 @@
+package supervisor
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testDump = `--
+-- PostgreSQL database dump
+--
+
+-- Dumped from database version 16.4
+-- Dumped by pg_dump version 16.4
+
+CREATE TABLE public.users (id integer, name text);
+
+COPY public.users (id, name) FROM stdin;
+1	alice
+2	bob
+3	carol
+\.
+
+COPY public.empty (id) FROM stdin;
+\.
+
+--
+-- PostgreSQL database dump complete
+--
+`
+
+func TestDumpStats(t *testing.T) {
+	stats := newDumpStats()
+	// 줄이 Write 경계에서 잘려도 같은 결과여야 함
+	for i := 0; i < len(testDump); i += 7 {
+		end := min(i+7, len(testDump))
+		stats.Write([]byte(testDump[i:end]))
+	}
+
+	if stats.ServerVersion != "16.4" || stats.DumpVersion != "16.4" || !stats.Complete {
+		t.Fatalf("header/trailer not parsed: %+v", stats)
+	}
+	if stats.Tables["public.users"] != 3 {
+		t.Errorf("public.users = %d rows, want 3", stats.Tables["public.users"])
+	}
+	if rows, ok := stats.Tables["public.empty"]; !ok || rows != 0 {
+		t.Errorf("public.empty = %d (listed %v), want 0 rows listed", rows, ok)
+	}
+	if stats.Bytes != int64(len(testDump)) {
+		t.Errorf("bytes = %d, want %d", stats.Bytes, len(testDump))
+	}
+
+	// 스키마 해시는 데이터와 주석에 영향받지 않고 DDL이 바뀌면 달라짐
+	other := newDumpStats()
+	other.Write([]byte(strings.Replace(strings.Replace(testDump, "3\tcarol\n", "", 1), "16.4", "17.0", -1)))
+	if other.SchemaHash() != stats.SchemaHash() {
+		t.Error("schema hash changed with data or versions")
+	}
+	changed := newDumpStats()
+	changed.Write([]byte(strings.Replace(testDump, "name text", "name varchar", 1)))
+	if changed.SchemaHash() == stats.SchemaHash() {
+		t.Error("schema hash did not change with the DDL")
+	}
+}
+
+// writeTestBackup writes an uncompressed backup with a dump, a config entry and a manifest
+func writeTestBackup(t *testing.T, dump string, editManifest func(*BackupManifest)) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.tar")
+
+	var buf bytes.Buffer
+	w := &manifestWriter{Writer: tar.NewWriter(&buf), manifest: newBackupManifest()}
+	stats := newDumpStats()
+	stats.Write([]byte(dump))
+	if _, err := writeDumpParts(w, strings.NewReader(dump), 100, nil); err != nil {
+		t.Fatal(err)
+	}
+	w.manifest.component("database").Tables = stats.Tables
+
+	config := []byte(`{"log_level":"info"}`)
+	if err := w.WriteHeader(&tar.Header{Name: "config/supervisor.json", Mode: 0644, Size: int64(len(config)), ModTime: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	w.Write(config)
+
+	if editManifest != nil {
+		editManifest(w.manifest)
+	}
+	if err := w.writeManifest(); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestVerifyBackupDeep(t *testing.T) {
+	s := &Supervisor{config: &Config{}}
+	path := writeTestBackup(t, testDump, nil)
+
+	checksum, err := s.calculateChecksum(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeBackupChecksum(path, checksum); err != nil {
+		t.Fatal(err)
+	}
+	if readBackupChecksum(path) != checksum {
+		t.Fatal("sidecar checksum not read back")
+	}
+
+	result := s.verifyBackupDeep(path, "", checksum, false)
+	if result["status"] != "valid" {
+		t.Fatalf("status = %v, errors %v", result["status"], result["errors"])
+	}
+	if result["checksum"].(map[string]interface{})["status"] != "match" {
+		t.Errorf("checksum = %v", result["checksum"])
+	}
+	database := result["components"].(map[string]interface{})["database"].(ComponentCheck)
+	if database.Entries != 4 || database.Bytes != int64(len(testDump)) {
+		t.Errorf("database component = %+v", database)
+	}
+	tables := result["tables"].([]TableCheck)
+	if len(tables) != 2 || tables[1].Table != "public.users" || *tables[1].Manifest != 3 || tables[1].Status != "ok" {
+		t.Errorf("tables = %+v", tables)
+	}
+
+	// 다른 체크섬이 기록되어 있으면 무결성 실패
+	result = s.verifyBackupDeep(path, "", strings.Repeat("0", 64), false)
+	if result["status"] != "invalid" || result["integrity"] != "invalid" {
+		t.Errorf("checksum mismatch not reported: %v", result["errors"])
+	}
+}
+
+func TestVerifyBackupDeepDetectsMismatch(t *testing.T) {
+	s := &Supervisor{config: &Config{}}
+
+	// 매니페스트의 행 수와 덤프가 다른 경우
+	path := writeTestBackup(t, testDump, func(m *BackupManifest) {
+		m.Components["database"].Tables["public.users"] = 5
+	})
+	result := s.verifyBackupDeep(path, "", "", false)
+	errors := strings.Join(result["errors"].([]string), "\n")
+	if result["status"] != "invalid" || !strings.Contains(errors, "Table public.users: mismatch (manifest 5, dump 3)") {
+		t.Errorf("row mismatch not reported: %s", errors)
+	}
+
+	// 완료 표시 없이 끝난 덤프
+	truncated := testDump[:strings.Index(testDump, "COPY public.empty")]
+	path = writeTestBackup(t, truncated, nil)
+	result = s.verifyBackupDeep(path, "", "", false)
+	errors = strings.Join(result["errors"].([]string), "\n")
+	if result["status"] != "invalid" || !strings.Contains(errors, "truncated") {
+		t.Errorf("truncated dump not reported: %s", errors)
+	}
+}
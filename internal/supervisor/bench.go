@@ -0,0 +1,616 @@
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/tmidb/tmidb-core/internal/config"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/dataconsumer"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/schema"
+)
+
+const (
+	// benchMaxDuration caps how long one benchmark may send
+	benchMaxDuration = time.Hour
+	// benchTick is how often the sender tops up to the target rate
+	benchTick = 10 * time.Millisecond
+	// benchLagInterval is how often written rows are counted
+	benchLagInterval = time.Second
+	// benchDrainTimeout is how long to wait for the last rows after sending stops
+	benchDrainTimeout = 2 * time.Minute
+	// benchPayloads is the number of distinct payloads generated up front
+	benchPayloads = 64
+	// benchLatencySamples bounds the latency reservoir
+	benchLatencySamples = 100000
+	// benchTargetPrefix names the synthetic targets so leftovers can be found
+	benchTargetPrefix = "tmidb-bench-"
+)
+
+// benchOptions are the parameters of one ingest benchmark
+type benchOptions struct {
+	org      string
+	category string
+	via      string // nats or bulk
+	token    string // API token for bulk
+	rate     int
+	duration time.Duration
+	targets  int
+	batch    int
+	workers  int
+	keep     bool
+}
+
+// BenchLatency summarizes latencies in milliseconds
+type BenchLatency struct {
+	P50 float64 `json:"p50_ms"`
+	P95 float64 `json:"p95_ms"`
+	P99 float64 `json:"p99_ms"`
+	Max float64 `json:"max_ms"`
+}
+
+// BenchResult is the outcome of an ingest benchmark
+type BenchResult struct {
+	OrgID        string        `json:"org_id"`
+	Category     string        `json:"category"`
+	Via          string        `json:"via"`
+	Targets      int           `json:"targets"`
+	Workers      int           `json:"workers"`
+	Batch        int           `json:"batch"`
+	TargetRate   int           `json:"target_rate"`
+	Duration     float64       `json:"duration_seconds"`
+	Sent         int           `json:"sent"`
+	Accepted     int           `json:"accepted"`
+	Failed       int           `json:"failed"`
+	Errors       []string      `json:"errors,omitempty"`
+	AchievedRate float64       `json:"achieved_rate"`
+	PayloadBytes int           `json:"payload_bytes"`
+	Latency      BenchLatency  `json:"latency"`
+	LatencyOf    string        `json:"latency_of"`
+	Written      int64         `json:"written"`
+	WriteRate    float64       `json:"write_rate"`
+	InsertLag    BenchLatency  `json:"insert_lag"`
+	Drain        float64       `json:"drain_seconds"`
+	Drained      bool          `json:"drained"`
+	Kept         []string      `json:"kept_targets,omitempty"`
+	Samples      []BenchSample `json:"samples,omitempty"`
+}
+
+// BenchSample is one per-second measurement
+type BenchSample struct {
+	Elapsed float64 `json:"elapsed_seconds"`
+	Sent    int     `json:"sent"`
+	Written int64   `json:"written"`
+	Lag     float64 `json:"lag_ms"`
+}
+
+// latencyRecorder keeps a uniform sample of latencies (in ms) and the maximum
+type latencyRecorder struct {
+	mu      sync.Mutex
+	r       *rand.Rand
+	count   int
+	max     float64
+	samples []float64
+}
+
+func newLatencyRecorder() *latencyRecorder {
+	return &latencyRecorder{r: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (l *latencyRecorder) add(d time.Duration) {
+	ms := millis(d)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.count++
+	if ms > l.max {
+		l.max = ms
+	}
+	if len(l.samples) < benchLatencySamples {
+		l.samples = append(l.samples, ms)
+	} else if i := l.r.Intn(l.count); i < benchLatencySamples {
+		l.samples[i] = ms
+	}
+}
+
+func (l *latencyRecorder) summary() BenchLatency {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return BenchLatency{
+		P50: percentile(l.samples, 50),
+		P95: percentile(l.samples, 95),
+		P99: percentile(l.samples, 99),
+		Max: l.max,
+	}
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// sendLog remembers when each record was sent, one checkpoint per tick
+type sendLog struct {
+	mu     sync.Mutex
+	seqs   []int // records sent before the checkpoint
+	times  []time.Time
+	failed int
+}
+
+func (l *sendLog) mark(sent int, at time.Time) {
+	l.mu.Lock()
+	l.seqs = append(l.seqs, sent)
+	l.times = append(l.times, at)
+	l.mu.Unlock()
+}
+
+func (l *sendLog) fail(n int) {
+	l.mu.Lock()
+	l.failed += n
+	l.mu.Unlock()
+}
+
+// lag is how long the oldest record not yet written has been waiting, given
+// the number of written rows. Failed records never arrive and are skipped.
+func (l *sendLog) lag(written int64, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	next := int(written) + l.failed
+	i := sort.SearchInts(l.seqs, next+1)
+	if i >= len(l.seqs) {
+		return 0
+	}
+	return now.Sub(l.times[i])
+}
+
+// parseBenchOptions reads and bounds the benchmark parameters
+func parseBenchOptions(data map[string]interface{}) (benchOptions, error) {
+	opts := benchOptions{via: "nats", rate: 1000, duration: time.Minute, targets: 10, batch: 500, workers: 8}
+	opts.org, _ = data["org"].(string)
+	opts.category, _ = data["category"].(string)
+	opts.token, _ = data["token"].(string)
+	opts.keep, _ = data["keep"].(bool)
+	if via, _ := data["via"].(string); via != "" {
+		opts.via = via
+	}
+	if d, _ := data["duration"].(string); d != "" {
+		duration, err := time.ParseDuration(d)
+		if err != nil {
+			return opts, fmt.Errorf("invalid duration %q: %v", d, err)
+		}
+		opts.duration = duration
+	}
+	for key, dst := range map[string]*int{"rate": &opts.rate, "targets": &opts.targets, "batch": &opts.batch, "workers": &opts.workers} {
+		if v, ok := data[key].(float64); ok {
+			*dst = int(v)
+		}
+	}
+
+	switch {
+	case opts.category == "":
+		return opts, errors.New("category is required")
+	case opts.via != "nats" && opts.via != "bulk":
+		return opts, fmt.Errorf("invalid via %q (nats or bulk)", opts.via)
+	case opts.via == "bulk" && opts.token == "":
+		return opts, errors.New("the bulk API needs an API token with write access to the category")
+	case opts.rate < 1 || opts.rate > 1000000:
+		return opts, fmt.Errorf("rate must be between 1 and 1000000 records per second")
+	case opts.duration < time.Second || opts.duration > benchMaxDuration:
+		return opts, fmt.Errorf("duration must be between 1s and %s", benchMaxDuration)
+	case opts.targets < 1 || opts.targets > 10000:
+		return opts, errors.New("targets must be between 1 and 10000")
+	case opts.batch < 1 || opts.batch > 10000:
+		return opts, errors.New("batch must be between 1 and 10000")
+	case opts.workers < 1 || opts.workers > 256:
+		return opts, errors.New("workers must be between 1 and 256")
+	}
+	if opts.via == "nats" {
+		// NATS 메시지는 레코드 하나씩
+		opts.batch = 1
+	}
+	return opts, nil
+}
+
+// handleBenchIngest pushes synthetic, schema-conforming records for a category
+// through NATS or the bulk API at a fixed rate and reports the achieved
+// throughput, send latency and how far the database falls behind. The records
+// go to temporary targets that are deleted afterwards unless keep is set.
+func (s *Supervisor) handleBenchIngest(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	opts, err := parseBenchOptions(msg.Data)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	orgID, err := benchOrg(db, opts.org, opts.category)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	link, err := database.GetActiveCategorySchema(db, orgID, opts.category)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to load schema of %s: %v", opts.category, err))
+	}
+	payloads, err := benchPayloadPool(link.SchemaDefinition)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	targets, err := createBenchTargets(db, link, opts.category, opts.targets, payloads[0])
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to create benchmark targets: %v", err))
+	}
+	if !opts.keep {
+		defer func() {
+			// 연결된 target_categories와 ts_obs 행은 CASCADE로 함께 삭제
+			if _, err := db.Exec(`DELETE FROM target WHERE target_id = ANY($1::uuid[])`, pq.Array(targets)); err != nil {
+				log.Printf("⚠️ Failed to delete benchmark targets: %v", err)
+			}
+		}()
+	}
+
+	send, closeSender, err := s.benchSender(opts, orgID)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer closeSender()
+
+	result := runBench(s.ctx, db, opts, targets, payloads, send)
+	result.OrgID = orgID
+	if opts.keep {
+		result.Kept = targets
+	}
+	return ipc.NewResponse(msg.ID, true, result, "")
+}
+
+// benchOrg finds the organization that owns the category. org may be an ID or
+// a name and is only needed when several organizations use the category.
+func benchOrg(db *sql.DB, org, category string) (string, error) {
+	rows, err := db.Query(
+		`SELECT DISTINCT cs.org_id::text, o.name
+		 FROM category_schemas cs JOIN organizations o ON o.org_id = cs.org_id
+		 WHERE cs.category_name = $1 AND cs.is_active = true
+		   AND ($2 = '' OR cs.org_id::text = $2 OR o.name = $2)
+		 ORDER BY o.name`, category, org)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var ids, names []string
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return "", err
+		}
+		ids = append(ids, id)
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	switch len(ids) {
+	case 0:
+		if org != "" {
+			return "", fmt.Errorf("organization %s has no active schema for category %s", org, category)
+		}
+		return "", fmt.Errorf("category %s has no active schema", category)
+	case 1:
+		return ids[0], nil
+	}
+	return "", fmt.Errorf("category %s exists in several organizations (%s), choose one with org", category, strings.Join(names, ", "))
+}
+
+// benchPayloadPool generates payloads that pass the category schema
+func benchPayloadPool(definition string) ([]json.RawMessage, error) {
+	compiled, err := schema.Cached(definition)
+	if err != nil {
+		return nil, fmt.Errorf("invalid category schema: %v", err)
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	var pool []json.RawMessage
+	var lastErr error
+	for i := 0; i < benchPayloads*4 && len(pool) < benchPayloads; i++ {
+		value := compiled.Generate(r)
+		if err := compiled.Validate(value); err != nil {
+			lastErr = err
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		pool = append(pool, raw)
+	}
+	if len(pool) == 0 {
+		return nil, fmt.Errorf("cannot generate payloads for this schema: %v", lastErr)
+	}
+	return pool, nil
+}
+
+// createBenchTargets creates synthetic targets linked to the category
+func createBenchTargets(db *sql.DB, link *database.TargetCategoryLink, category string, n int, data json.RawMessage) ([]string, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	run := time.Now().Format("20060102-150405")
+	targets := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		var id string
+		name := fmt.Sprintf("%s%s-%d", benchTargetPrefix, run, i+1)
+		if err := tx.QueryRow(`INSERT INTO target (name) VALUES ($1) RETURNING target_id::text`, name).Scan(&id); err != nil {
+			return nil, err
+		}
+		if err := database.UpsertTargetCategoryData(tx, id, link, category, string(data)); err != nil {
+			return nil, err
+		}
+		targets = append(targets, id)
+	}
+	return targets, tx.Commit()
+}
+
+// benchSendFunc sends a batch of encoded records and returns how many failed
+type benchSendFunc func(ctx context.Context, records [][]byte) (failed int, err error)
+
+// benchSender returns the send function for NATS or the bulk API
+func (s *Supervisor) benchSender(opts benchOptions, orgID string) (benchSendFunc, func(), error) {
+	if opts.via == "nats" {
+		nc, js, err := connectJetStream()
+		if err != nil {
+			return nil, nil, err
+		}
+		subject := dataconsumer.IngestSubject(orgID, opts.category)
+		send := func(ctx context.Context, records [][]byte) (int, error) {
+			for i, record := range records {
+				if _, err := js.Publish(ctx, subject, record); err != nil {
+					return len(records) - i, err
+				}
+			}
+			return 0, nil
+		}
+		return send, nc.Close, nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	endpoint := fmt.Sprintf("http://localhost:%s/api/v1/data/%s/bulk", cfg.APIPort, url.PathEscape(opts.category))
+	client := &http.Client{
+		Timeout:   time.Minute,
+		Transport: &http.Transport{MaxIdleConnsPerHost: opts.workers},
+	}
+	send := func(ctx context.Context, records [][]byte) (int, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(bytes.Join(records, []byte("\n"))))
+		if err != nil {
+			return len(records), err
+		}
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		req.Header.Set("Authorization", "Bearer "+opts.token)
+		resp, err := client.Do(req)
+		if err != nil {
+			return len(records), err
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return 0, nil
+		case http.StatusMultiStatus:
+			var out struct {
+				Data struct {
+					Failed  int `json:"failed"`
+					Results []struct {
+						Error string `json:"error"`
+					} `json:"results"`
+				} `json:"data"`
+			}
+			json.Unmarshal(body, &out)
+			for _, r := range out.Data.Results {
+				if r.Error != "" {
+					return out.Data.Failed, errors.New(r.Error)
+				}
+			}
+			return out.Data.Failed, nil
+		}
+		snippet := strings.TrimSpace(string(body))
+		if len(snippet) > 200 {
+			snippet = snippet[:200]
+		}
+		return len(records), fmt.Errorf("HTTP %d: %s", resp.StatusCode, snippet)
+	}
+	return send, client.CloseIdleConnections, nil
+}
+
+// runBench sends records at the target rate and measures the database side
+func runBench(ctx context.Context, db *sql.DB, opts benchOptions, targets []string, payloads []json.RawMessage, send benchSendFunc) *BenchResult {
+	result := &BenchResult{
+		Category:   opts.category,
+		Via:        opts.via,
+		Targets:    len(targets),
+		Workers:    opts.workers,
+		Batch:      opts.batch,
+		TargetRate: opts.rate,
+		LatencyOf:  "publish acknowledged by JetStream",
+	}
+	if opts.via == "bulk" {
+		result.LatencyOf = "bulk API request"
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	latency := newLatencyRecorder()
+	sends := &sendLog{}
+	var (
+		mu       sync.Mutex
+		accepted int
+		errs     = map[string]bool{}
+	)
+
+	// 전송 워커
+	jobs := make(chan [][]byte, opts.workers*2)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for records := range jobs {
+				began := time.Now()
+				failed, err := send(ctx, records)
+				latency.add(time.Since(began))
+				sends.fail(failed)
+
+				mu.Lock()
+				accepted += len(records) - failed
+				if err != nil && len(errs) < 5 {
+					errs[err.Error()] = true
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// 기록된 행 수를 주기적으로 세어 삽입 지연을 계산
+	base := time.Now().UTC().Truncate(time.Second)
+	var written int64
+	countRows := func() {
+		db.QueryRowContext(ctx,
+			`SELECT count(*) FROM ts_obs WHERE category_name = $1 AND target_id = ANY($2::uuid[]) AND ts >= $3`,
+			opts.category, pq.Array(targets), base).Scan(&written)
+	}
+	lags := newLatencyRecorder()
+	sample := func(start time.Time, sent int) {
+		countRows()
+		now := time.Now()
+		lag := sends.lag(written, now)
+		lags.add(lag)
+		result.Samples = append(result.Samples, BenchSample{
+			Elapsed: now.Sub(start).Seconds(),
+			Sent:    sent,
+			Written: written,
+			Lag:     millis(lag),
+		})
+	}
+
+	// 목표 속도에 맞춰 레코드를 만들어 보냄 (워커가 밀리면 실제 속도가 떨어짐)
+	start := time.Now()
+	deadline := start.Add(opts.duration)
+	ticker := time.NewTicker(benchTick)
+	nextSample := start.Add(benchLagInterval)
+	sent, size := 0, 0
+	var pending [][]byte
+	flush := func() bool {
+		if len(pending) == 0 {
+			return true
+		}
+		select {
+		case jobs <- pending:
+			pending = nil
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+send:
+	for now := range ticker.C {
+		if now.After(deadline) {
+			now = deadline
+		}
+		due := int(float64(opts.rate) * now.Sub(start).Seconds())
+		for sent < due {
+			record, _ := json.Marshal(dataconsumer.IngestRecord{
+				TargetID: targets[sent%len(targets)],
+				Ts:       base.Add(time.Duration(sent) * time.Microsecond).Format(time.RFC3339Nano),
+				Payload:  payloads[sent%len(payloads)],
+			})
+			size += len(record)
+			pending = append(pending, record)
+			sent++
+			if len(pending) >= opts.batch && !flush() {
+				break send
+			}
+		}
+		if !flush() {
+			break
+		}
+		sends.mark(sent, time.Now())
+
+		if time.Now().After(nextSample) {
+			sample(start, sent)
+			nextSample = nextSample.Add(benchLagInterval)
+		}
+		if !now.Before(deadline) {
+			break
+		}
+	}
+	ticker.Stop()
+	close(jobs)
+	wg.Wait()
+	sendElapsed := time.Since(start)
+
+	// 남은 행이 기록될 때까지 기다림
+	sendEnd := time.Now()
+	drainUntil := sendEnd.Add(benchDrainTimeout)
+	expected := int64(accepted)
+	for ctx.Err() == nil {
+		if time.Now().After(nextSample) {
+			sample(start, sent)
+			nextSample = nextSample.Add(benchLagInterval)
+		} else {
+			countRows()
+		}
+		if written >= expected {
+			result.Drained = true
+			break
+		}
+		if time.Now().After(drainUntil) {
+			break
+		}
+		time.Sleep(benchLagInterval / 4)
+	}
+	result.Drain = time.Since(sendEnd).Seconds()
+
+	result.Duration = sendElapsed.Seconds()
+	result.Sent = sent
+	result.Accepted = accepted
+	result.Failed = sent - accepted
+	for e := range errs {
+		result.Errors = append(result.Errors, e)
+	}
+	sort.Strings(result.Errors)
+	result.AchievedRate = float64(accepted) / sendElapsed.Seconds()
+	if sent > 0 {
+		result.PayloadBytes = size / sent
+	}
+	result.Latency = latency.summary()
+	result.Written = written
+	if total := time.Since(start).Seconds(); total > 0 {
+		result.WriteRate = float64(written) / total
+	}
+	result.InsertLag = lags.summary()
+	return result
+}
@@ -0,0 +1,62 @@
+package supervisor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseBenchOptions(t *testing.T) {
+	opts, err := parseBenchOptions(map[string]interface{}{"category": "sensor", "rate": float64(5000), "duration": "60s", "batch": float64(1000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.via != "nats" || opts.rate != 5000 || opts.duration != time.Minute || opts.batch != 1 || opts.workers != 8 {
+		t.Errorf("opts = %+v", opts)
+	}
+
+	opts, err = parseBenchOptions(map[string]interface{}{"category": "sensor", "via": "bulk", "token": "t", "batch": float64(1000)})
+	if err != nil || opts.batch != 1000 {
+		t.Errorf("bulk opts = %+v, %v", opts, err)
+	}
+
+	for _, data := range []map[string]interface{}{
+		{},
+		{"category": "sensor", "via": "http"},
+		{"category": "sensor", "via": "bulk"},
+		{"category": "sensor", "rate": float64(0)},
+		{"category": "sensor", "duration": "2h"},
+		{"category": "sensor", "duration": "soon"},
+	} {
+		if _, err := parseBenchOptions(data); err == nil {
+			t.Errorf("parseBenchOptions(%v) accepted", data)
+		}
+	}
+}
+
+func TestSendLogLag(t *testing.T) {
+	start := time.Now()
+	var log sendLog
+	log.mark(100, start)
+	log.mark(200, start.Add(time.Second))
+	log.mark(300, start.Add(2*time.Second))
+
+	now := start.Add(3 * time.Second)
+	for _, tc := range []struct {
+		written int64
+		want    time.Duration
+	}{
+		{0, 3 * time.Second},   // 첫 레코드가 3초째 대기
+		{150, 2 * time.Second}, // 151번째는 1초에 보냄
+		{300, 0},               // 모두 기록됨
+	} {
+		if got := log.lag(tc.written, now); got != tc.want {
+			t.Errorf("lag(%d) = %v, want %v", tc.written, got, tc.want)
+		}
+	}
+
+	// 실패한 레코드는 기다리지 않음
+	log.fail(100)
+	if got := log.lag(200, now); got != 0 {
+		t.Errorf("lag with failures = %v", got)
+	}
+}
@@ -0,0 +1,195 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/migration"
+)
+
+// categoryMigrationPlan builds the plan for a request: either the plan sent by
+// the client (e.g. an edited plan file) or a generated one for the category
+// and versions given. The organization is looked up when it is not given.
+func categoryMigrationPlan(m *migration.MigrationManager, data map[string]interface{}) (*migration.CategoryPlan, error) {
+	if raw, ok := data["plan"]; ok && raw != nil {
+		encoded, err := json.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+		var plan migration.CategoryPlan
+		if err := json.Unmarshal(encoded, &plan); err != nil {
+			return nil, fmt.Errorf("invalid plan: %v", err)
+		}
+		if plan.OrgID == "" {
+			if plan.OrgID, err = m.ResolveCategoryOrg(plan.Category); err != nil {
+				return nil, err
+			}
+		}
+		return &plan, nil
+	}
+
+	category, _ := data["category"].(string)
+	if category == "" {
+		return nil, fmt.Errorf("category required")
+	}
+	orgID, _ := data["org_id"].(string)
+	if orgID == "" {
+		var err error
+		if orgID, err = m.ResolveCategoryOrg(category); err != nil {
+			return nil, err
+		}
+	}
+	from, _ := data["from_version"].(float64)
+	to, _ := data["to_version"].(float64)
+	return m.PlanCategoryMigration(orgID, category, int(from), int(to))
+}
+
+// handleCategorySchemaDiff compares two schema versions of a category and
+// classifies each change as backward compatible or breaking
+func (s *Supervisor) handleCategorySchemaDiff(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	m := migration.NewMigrationManager(db)
+	category, _ := msg.Data["category"].(string)
+	if category == "" {
+		return ipc.NewResponse(msg.ID, false, nil, "category required")
+	}
+	orgID, _ := msg.Data["org_id"].(string)
+	if orgID == "" {
+		if orgID, err = m.ResolveCategoryOrg(category); err != nil {
+			return ipc.NewResponse(msg.ID, false, nil, err.Error())
+		}
+	}
+	from, _ := msg.Data["from_version"].(float64)
+	to, _ := msg.Data["to_version"].(float64)
+
+	diff, err := m.DiffCategorySchemas(orgID, category, int(from), int(to))
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, diff, "")
+}
+
+// handleCategoryMigrationPlan generates a data migration plan between two
+// schema versions of a category
+func (s *Supervisor) handleCategoryMigrationPlan(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	plan, err := categoryMigrationPlan(migration.NewMigrationManager(db), msg.Data)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, plan, "")
+}
+
+// handleCategoryMigrationPreview shows how many rows a plan affects and how
+// the first few rows would look after the upgrade
+func (s *Supervisor) handleCategoryMigrationPreview(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	m := migration.NewMigrationManager(db)
+	plan, err := categoryMigrationPlan(m, msg.Data)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	limit, _ := msg.Data["limit"].(float64)
+	preview, err := m.PreviewCategoryMigration(plan, int(limit))
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, preview, "")
+}
+
+// handleCategoryMigrationRun records a category migration and executes it in
+// the background; progress is read with handleCategoryMigrationStatus
+func (s *Supervisor) handleCategoryMigrationRun(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	m := migration.NewMigrationManager(db)
+	plan, err := categoryMigrationPlan(m, msg.Data)
+	if err != nil {
+		db.Close()
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	name, _ := msg.Data["name"].(string)
+	mig, err := m.CreateCategoryMigration(name, "", plan)
+	if err != nil {
+		db.Close()
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	go func() {
+		defer db.Close()
+		if _, err := m.ExecuteMigration(mig.ID); err != nil {
+			log.Printf("Category migration %s failed: %v", mig.Name, err)
+			return
+		}
+		log.Printf("Category migration %s completed", mig.Name)
+	}()
+
+	return ipc.NewResponse(msg.ID, true, mig, "")
+}
+
+// handleCategoryMigrationStatus returns a migration with its plan and progress
+func (s *Supervisor) handleCategoryMigrationStatus(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	id, _ := msg.Data["id"].(float64)
+	if id <= 0 {
+		return ipc.NewResponse(msg.ID, false, nil, "migration id required")
+	}
+
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	mig, err := migration.NewMigrationManager(db).GetMigrationByID(int(id))
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, mig, "")
+}
+
+// handleMigrationRollback rolls back the migration with the given id, or the
+// most recently completed migration when no id is given
+func (s *Supervisor) handleMigrationRollback(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	m := migration.NewMigrationManager(db)
+	var mig *migration.Migration
+	var result *migration.MigrationResult
+
+	if id, _ := msg.Data["id"].(float64); id > 0 {
+		result, err = m.RollbackMigration(int(id))
+		if err == nil {
+			mig, err = m.GetMigrationByID(int(id))
+		}
+	} else {
+		mig, result, err = m.RollbackLatest("")
+	}
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{"migration": mig, "result": result}, "")
+}
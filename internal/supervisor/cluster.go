@@ -0,0 +1,73 @@
+package supervisor
+
+import (
+	"crypto/tls"
+	"log"
+	"os"
+
+	"github.com/tmidb/tmidb-core/internal/cluster"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/version"
+)
+
+// setupCluster creates the cluster manager from the configuration.
+// Without peers or a NATS URL the cluster view contains only this node.
+func (s *Supervisor) setupCluster() {
+	node := s.nodeName()
+
+	// 피어 조회에는 노드 인증서를 클라이언트 인증서로 사용
+	var tlsConfig *tls.Config
+	peers := s.config.ClusterPeers
+	if len(peers) > 0 {
+		var err error
+		tlsConfig, err = ipc.ClientTLSConfig(s.config.IPCTLSCert, s.config.IPCTLSKey, s.config.IPCTLSCA)
+		if err != nil {
+			log.Printf("⚠️ Cluster peers disabled: %v", err)
+			peers = nil
+		}
+	}
+
+	s.cluster = cluster.NewManager(cluster.Config{
+		Node:    node,
+		Peers:   peers,
+		NATSURL: s.config.ClusterNATSURL,
+		TLS:     tlsConfig,
+	}, s.localNodeStatus)
+}
+
+// nodeName is this supervisor's name in the cluster (default: hostname)
+func (s *Supervisor) nodeName() string {
+	if s.config.ClusterNode != "" {
+		return s.config.ClusterNode
+	}
+	hostname, _ := os.Hostname()
+	return hostname
+}
+
+// localNodeStatus reports this node's processes, version and resource usage
+func (s *Supervisor) localNodeStatus() cluster.NodeStatus {
+	hostname, _ := os.Hostname()
+
+	return cluster.NodeStatus{
+		Address:   s.config.IPCTCPAddr,
+		Hostname:  hostname,
+		Version:   version.Version,
+		StartedAt: s.startedAt,
+		Resources: cluster.Resources{
+			CPUUsage:    s.getCPUUsage(),
+			MemoryUsage: s.getMemoryUsage(),
+			DiskUsage:   s.getDiskUsage(),
+		},
+		Processes: s.processManager.GetProcessList(),
+	}
+}
+
+// handleClusterStatus returns the status of every known node
+func (s *Supervisor) handleClusterStatus(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	return ipc.NewResponse(msg.ID, true, s.cluster.Status(), "")
+}
+
+// handleClusterNode returns this node's status for peers polling it
+func (s *Supervisor) handleClusterNode(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	return ipc.NewResponse(msg.ID, true, s.cluster.Local(), "")
+}
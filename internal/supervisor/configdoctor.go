@@ -0,0 +1,71 @@
+package supervisor
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/tmidb/tmidb-core/internal/config"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// handleConfigDoctor reports the configuration a component would load on its
+// next start: defaults, config file, environment (including secrets) and flags,
+// with the layer each value came from. Secret values are masked.
+func (s *Supervisor) handleConfigDoctor(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	component, _ := msg.Data["component"].(string)
+	if component == "" {
+		component = "api"
+	}
+	if !slices.Contains(secretComponents, component) {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("unknown component %q (valid: %s)", component, strings.Join(secretComponents, ", ")))
+	}
+
+	var args []string
+	if list, ok := msg.Data["args"].([]interface{}); ok {
+		for _, item := range list {
+			if arg, ok := item.(string); ok {
+				args = append(args, arg)
+			}
+		}
+	}
+
+	report := config.Doctor(config.Options{
+		Env:  s.doctorEnv(component),
+		Args: args,
+	})
+
+	// 시크릿으로 넘겨지는 값은 출처를 구분해서 보여 줌
+	secrets := s.secretEnv(component)
+	for i := range report.Settings {
+		setting := &report.Settings[i]
+		if _, ok := secrets[setting.Key]; ok && setting.Source == config.SourceEnv {
+			setting.Origin = "secret"
+			setting.Secret = true
+			setting.Value = secretMask
+		}
+	}
+
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
+		"component": component,
+		"file":      report.File,
+		"settings":  report.Settings,
+		"errors":    report.Errors,
+	}, "")
+}
+
+// doctorEnv is the environment a component is started with: the supervisor's
+// own environment plus the variables the supervisor adds for it
+func (s *Supervisor) doctorEnv(component string) map[string]string {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	for k, v := range s.componentEnv(component) {
+		env[k] = v
+	}
+	return env
+}
@@ -0,0 +1,549 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/logger"
+)
+
+// DefaultConfigPath is where the supervisor persists its configuration
+const DefaultConfigPath = "./config/supervisor.json"
+
+// configKeyComponents maps config keys to the component they affect
+var configKeyComponents = map[string]string{
+	"socket_path":      "supervisor",
+	"postgresql_path":  "postgresql",
+	"nats_path":        "nats",
+	"seaweedfs_path":   "seaweedfs",
+	"postgresql_port":  "postgresql",
+	"nats_port":        "nats",
+	"seaweedfs_port":   "seaweedfs",
+	"startup_timeout":  "supervisor",
+	"shutdown_timeout": "supervisor",
+	"log_dir":          "logging",
+	"log_level":        "logging",
+	"metrics_addr":     "metrics",
+	"events_nats_url":  "events",
+	"postgres_profile": "postgresql",
+	"wal_archive":      "postgresql",
+	"nats_streams":     "nats",
+}
+
+// hotReloadableKeys are applied without restarting anything
+var hotReloadableKeys = map[string]bool{
+	"startup_timeout":  true,
+	"shutdown_timeout": true,
+	"log_level":        true,
+	"metrics_addr":     true,
+	"events_nats_url":  true,
+	"nats_streams":     true,
+}
+
+// serviceDependents lists internal components that connect to an external service
+var serviceDependents = map[string][]string{
+	"postgresql": {"api", "data-manager", "data-consumer"},
+	"nats":       {"api", "data-manager", "data-consumer"},
+	"seaweedfs":  {"api", "data-manager"},
+}
+
+// configChange describes a single key changed by a reload
+type configChange struct {
+	Key          string      `json:"key"`
+	OldValue     interface{} `json:"old_value"`
+	NewValue     interface{} `json:"new_value"`
+	Component    string      `json:"component"`
+	Applied      bool        `json:"applied"`
+	NeedsRestart bool        `json:"needs_restart"`
+}
+
+// configJSON is the on-disk form of Config with human readable durations
+type configJSON struct {
+	*configAlias
+	StartupTimeout  string `json:"startup_timeout"`
+	ShutdownTimeout string `json:"shutdown_timeout"`
+	WatchdogTimeout string `json:"watchdog_timeout,omitempty"`
+}
+
+type configAlias Config
+
+// MarshalJSON encodes durations as strings (e.g. "30s")
+func (c *Config) MarshalJSON() ([]byte, error) {
+	return json.Marshal(configJSON{
+		configAlias:     (*configAlias)(c),
+		StartupTimeout:  c.StartupTimeout.String(),
+		ShutdownTimeout: c.ShutdownTimeout.String(),
+		WatchdogTimeout: c.WatchdogTimeout.String(),
+	})
+}
+
+// UnmarshalJSON decodes durations from strings; missing keys keep their current value
+func (c *Config) UnmarshalJSON(data []byte) error {
+	aux := configJSON{
+		configAlias:     (*configAlias)(c),
+		StartupTimeout:  c.StartupTimeout.String(),
+		ShutdownTimeout: c.ShutdownTimeout.String(),
+		WatchdogTimeout: c.WatchdogTimeout.String(),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	startup, err := time.ParseDuration(aux.StartupTimeout)
+	if err != nil {
+		return fmt.Errorf("invalid startup_timeout: %w", err)
+	}
+	shutdown, err := time.ParseDuration(aux.ShutdownTimeout)
+	if err != nil {
+		return fmt.Errorf("invalid shutdown_timeout: %w", err)
+	}
+
+	watchdog, err := time.ParseDuration(aux.WatchdogTimeout)
+	if err != nil {
+		return fmt.Errorf("invalid watchdog_timeout: %w", err)
+	}
+
+	c.StartupTimeout = startup
+	c.ShutdownTimeout = shutdown
+	c.WatchdogTimeout = watchdog
+	return nil
+}
+
+// values returns the config as the key/value map exposed over IPC
+func (c *Config) values() map[string]interface{} {
+	return map[string]interface{}{
+		"socket_path":      c.SocketPath,
+		"postgresql_path":  c.PostgreSQLPath,
+		"nats_path":        c.NATSPath,
+		"seaweedfs_path":   c.SeaweedFSPath,
+		"postgresql_port":  c.PostgreSQLPort,
+		"nats_port":        c.NATSPort,
+		"seaweedfs_port":   c.SeaweedFSPort,
+		"startup_timeout":  c.StartupTimeout.String(),
+		"shutdown_timeout": c.ShutdownTimeout.String(),
+		"log_dir":          c.LogDir,
+		"log_level":        c.LogLevel,
+		"metrics_addr":     c.MetricsAddr,
+		"events_nats_url":  c.EventsNATSURL,
+		"postgres_profile": c.PostgresProfile,
+		"wal_archive":      c.WALArchive,
+		"nats_streams":     c.NATSStreams,
+	}
+}
+
+// LoadConfigFile overlays the persisted configuration at path onto cfg.
+// A missing file is not an error.
+func LoadConfigFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// candidateConfig returns a copy of cfg with the given keys applied. Values
+// use the same form as config get and the config file (durations as strings).
+func candidateConfig(cfg *Config, values map[string]interface{}) (*Config, error) {
+	// export나 edit에서 돌아온 가려진 secrets는 그대로 둠
+	if secrets, ok := values["secrets"]; ok {
+		if !onlyMasked(secrets) {
+			return nil, fmt.Errorf("secrets can only be changed with config secret set/delete")
+		}
+		values = maps.Clone(values)
+		delete(values, "secrets")
+	}
+
+	unknown := []string{}
+	for key := range values {
+		if _, ok := configKeyComponents[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown config keys: %s", strings.Join(unknown, ", "))
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config values: %w", err)
+	}
+	candidate := *cfg
+	if _, ok := values["nats_streams"]; ok {
+		// 디코딩이 기존 슬라이스를 덮어쓰지 않도록 새로 할당
+		candidate.NATSStreams = nil
+	}
+	if err := json.Unmarshal(data, &candidate); err != nil {
+		return nil, fmt.Errorf("invalid config values: %w", err)
+	}
+	return &candidate, nil
+}
+
+// validateConfig returns the problems that make cfg unusable (errors) and
+// the ones worth a look (warnings)
+func validateConfig(cfg *Config) (errs []string, warnings []string) {
+	errs, warnings = []string{}, []string{}
+
+	// 포트 범위와 충돌 검사
+	ports := []struct {
+		service string
+		port    int
+	}{
+		{"postgresql", cfg.PostgreSQLPort},
+		{"nats", cfg.NATSPort},
+		{"seaweedfs", cfg.SeaweedFSPort},
+	}
+	portMap := make(map[int]string)
+	for _, p := range ports {
+		if p.port <= 0 || p.port > 65535 {
+			errs = append(errs, fmt.Sprintf("Invalid %s port: %d", p.service, p.port))
+			continue
+		}
+		if existingService, exists := portMap[p.port]; exists {
+			warnings = append(warnings, fmt.Sprintf("Port conflict: %s and %s both use port %d", p.service, existingService, p.port))
+		} else {
+			portMap[p.port] = p.service
+		}
+	}
+
+	// 로그 레벨 검사
+	validLevels := []string{"DEBUG", "INFO", "WARN", "ERROR"}
+	validLevel := false
+	for _, level := range validLevels {
+		if cfg.LogLevel == level {
+			validLevel = true
+			break
+		}
+	}
+	if !validLevel {
+		errs = append(errs, fmt.Sprintf("Invalid log level: %s (valid: %v)", cfg.LogLevel, validLevels))
+	}
+
+	// 시작/종료 대기 시간 검사
+	if cfg.StartupTimeout <= 0 {
+		errs = append(errs, fmt.Sprintf("Invalid startup_timeout: %s", cfg.StartupTimeout))
+	}
+	if cfg.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Sprintf("Invalid shutdown_timeout: %s", cfg.ShutdownTimeout))
+	}
+
+	// PostgreSQL 튜닝 프로필 검사
+	if _, err := ParsePostgresProfile(cfg.PostgresProfile); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// WAL 보관 위치 검사
+	if _, err := parseWALArchive(cfg.WALArchive); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// JetStream 스트림 선언 검사
+	streams := cfg.NATSStreams
+	if streams == nil {
+		streams = defaultStreams()
+	}
+	if err := validateStreamSpecs(streams); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// 컨테이너로 실행할 외부 서비스 검사
+	for _, name := range slices.Sorted(maps.Keys(cfg.Containers)) {
+		if !slices.Contains(containerServices, name) {
+			errs = append(errs, fmt.Sprintf("Unknown container service: %s (valid: %v)", name, containerServices))
+		} else if err := cfg.Containers[name].Validate(); err != nil {
+			errs = append(errs, fmt.Sprintf("Invalid container for %s: %v", name, err))
+		}
+	}
+
+	// 서비스 디스커버리 검사
+	if cfg.Discovery != nil {
+		if err := cfg.Discovery.Validate(); err != nil {
+			errs = append(errs, err.Error())
+		}
+		for _, name := range cfg.Discovery.Services {
+			if name != "api" && !slices.Contains(containerServices, name) {
+				errs = append(errs, fmt.Sprintf("Unknown discovery service: %s (valid: api, postgresql, nats, seaweedfs)", name))
+			}
+		}
+	}
+
+	// 임대 저장소가 이 노드에 있으면 노드와 함께 사라져 standby가 이어받을 수 없음
+	if cfg.HA != nil {
+		if err := cfg.HA.Validate(); err != nil {
+			errs = append(errs, err.Error())
+		} else if strings.Contains(cfg.HA.URL, "localhost") || strings.Contains(cfg.HA.URL, "127.0.0.1") {
+			warnings = append(warnings, fmt.Sprintf("HA backend %s is on this node; the standby cannot take over when the node dies", cfg.HA.URL))
+		}
+	}
+
+	// 비밀 값 키 위치와 암호화 여부 검사
+	if kind, _, _ := strings.Cut(cfg.SecretsKey, ":"); cfg.SecretsKey != "" && kind != "env" && kind != "file" && kind != "exec" {
+		errs = append(errs, fmt.Sprintf("Invalid secrets_key: %s (use env:NAME, file:PATH or exec:COMMAND)", cfg.SecretsKey))
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.Secrets)) {
+		if secret := cfg.Secrets[name]; secret == nil || !strings.HasPrefix(secret.Value, secretPrefix) {
+			errs = append(errs, fmt.Sprintf("Secret %s is not encrypted (set it with config secret set)", name))
+		}
+	}
+
+	// 디렉토리 존재 검사
+	if _, err := os.Stat(cfg.LogDir); os.IsNotExist(err) {
+		warnings = append(warnings, fmt.Sprintf("Log directory does not exist: %s", cfg.LogDir))
+	}
+
+	return errs, warnings
+}
+
+// saveConfig persists the current configuration atomically
+func (s *Supervisor) saveConfig() error {
+	s.configMutex.Lock()
+	defer s.configMutex.Unlock()
+
+	path := s.config.ConfigPath
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace config file: %w", err)
+	}
+
+	return nil
+}
+
+// persistConfig saves the config and turns a failure into an error response
+func (s *Supervisor) persistConfig(msg *ipc.Message, data interface{}) *ipc.Response {
+	if err := s.saveConfig(); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("config changed in memory but not persisted: %v", err))
+	}
+
+	eventData := map[string]interface{}{"operation": string(msg.Type)}
+	if key, ok := msg.Data["key"].(string); ok && key != "" {
+		eventData["key"] = key
+	}
+	s.emitEvent(ipc.EventConfigChanged, "supervisor", "", eventData)
+
+	return ipc.NewResponse(msg.ID, true, data, "")
+}
+
+// handleConfigReload re-reads the config file and applies the differences
+func (s *Supervisor) handleConfigReload(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	restart, _ := msg.Data["restart"].(bool)
+
+	s.configMutex.Lock()
+	oldConfig := s.config
+	newConfig := *oldConfig
+	// 디코딩이 기존 맵에 합쳐지지 않도록 파일의 secrets로 새로 채움
+	newConfig.Secrets = nil
+	if err := LoadConfigFile(oldConfig.ConfigPath, &newConfig); err != nil {
+		s.configMutex.Unlock()
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	if err := newConfig.IPCAuth.Validate(); err != nil {
+		s.configMutex.Unlock()
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("invalid ipc_auth: %v", err))
+	}
+	newConfig.ConfigPath = oldConfig.ConfigPath
+	s.config = &newConfig
+	s.configMutex.Unlock()
+
+	// 인증 설정은 다음 요청부터 적용
+	s.ipcServer.SetAuth(newConfig.IPCAuth)
+
+	changes := diffConfig(oldConfig, &newConfig)
+	for i := range changes {
+		s.applyConfigChange(&changes[i])
+	}
+
+	notified := s.notifyConfigChanges(changes)
+
+	// 파일에서 바뀐 비밀 값은 다음 시작부터 전달
+	if !reflect.DeepEqual(oldConfig.Secrets, newConfig.Secrets) {
+		notified = mergeComponents(notified, s.refreshSecretEnv(secretComponents))
+	}
+
+	restarted := []string{}
+	if restart {
+		for _, component := range notified {
+			if err := s.processManager.RestartProcess(component); err != nil {
+				log.Printf("⚠️ Failed to restart %s after config reload: %v", component, err)
+				continue
+			}
+			restarted = append(restarted, component)
+		}
+	}
+
+	log.Printf("🔄 Configuration reloaded from %s (%d changes)", newConfig.ConfigPath, len(changes))
+
+	if len(changes) > 0 {
+		keys := make([]string, len(changes))
+		for i, change := range changes {
+			keys[i] = change.Key
+		}
+		s.emitEvent(ipc.EventConfigChanged, "supervisor", "", map[string]interface{}{
+			"operation": string(msg.Type),
+			"keys":      keys,
+		})
+	}
+
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
+		"path":      newConfig.ConfigPath,
+		"changes":   changes,
+		"notified":  notified,
+		"restarted": restarted,
+	}, "")
+}
+
+// diffConfig returns the keys whose values differ, sorted by key
+func diffConfig(oldConfig, newConfig *Config) []configChange {
+	oldValues := oldConfig.values()
+	newValues := newConfig.values()
+
+	keys := make([]string, 0, len(newValues))
+	for key := range newValues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	changes := []configChange{}
+	for _, key := range keys {
+		if reflect.DeepEqual(oldValues[key], newValues[key]) {
+			continue
+		}
+		changes = append(changes, configChange{
+			Key:          key,
+			OldValue:     oldValues[key],
+			NewValue:     newValues[key],
+			Component:    configKeyComponents[key],
+			NeedsRestart: !hotReloadableKeys[key],
+		})
+	}
+
+	return changes
+}
+
+// applyConfigChange applies hot-reloadable keys to running subsystems
+func (s *Supervisor) applyConfigChange(change *configChange) {
+	switch change.Key {
+	case "log_level":
+		s.logManager.SetLevel(parseLogLevel(s.config.LogLevel))
+		change.Applied = true
+	case "metrics_addr":
+		change.Applied = s.restartMetricsServer() == nil
+	case "events_nats_url":
+		if err := s.events.setNATS(s.config.EventsNATSURL); err != nil {
+			log.Printf("⚠️ %v", err)
+		} else {
+			change.Applied = true
+		}
+	case "startup_timeout", "shutdown_timeout":
+		// 다음 시작/종료 시점에 s.config에서 바로 읽힘
+		change.Applied = true
+	case "postgres_profile":
+		// 설정 파일은 바로 다시 쓰고, PostgreSQL 재시작 후 적용됨
+		if _, err := s.applyPostgresTuning(); err != nil {
+			log.Printf("⚠️ Failed to apply PostgreSQL tuning: %v", err)
+		} else {
+			change.Applied = true
+		}
+	case "wal_archive":
+		if _, err := s.applyWALArchive(); err != nil {
+			log.Printf("⚠️ Failed to apply WAL archiving: %v", err)
+		} else {
+			change.Applied = true
+		}
+	case "nats_streams":
+		// 백그라운드에서 NATS가 받아들일 때까지 재시도
+		s.provisionStreams()
+		change.Applied = true
+	}
+}
+
+// restartMetricsServer restarts the exporter on the configured address
+func (s *Supervisor) restartMetricsServer() error {
+	if s.metricsServer != nil {
+		if err := s.metricsServer.Stop(); err != nil {
+			log.Printf("⚠️ Failed to stop metrics server: %v", err)
+		}
+		s.metricsServer = nil
+	}
+
+	if s.config.MetricsAddr == "" {
+		return nil
+	}
+
+	server := s.newMetricsServer()
+	if err := server.Start(); err != nil {
+		log.Printf("⚠️ Failed to start metrics server: %v", err)
+		return err
+	}
+	s.metricsServer = server
+	return nil
+}
+
+// notifyConfigChanges writes a notice to the log of every affected component
+// and returns the internal components that must be restarted to pick them up
+func (s *Supervisor) notifyConfigChanges(changes []configChange) []string {
+	affected := make(map[string]bool)
+
+	for _, change := range changes {
+		notice := fmt.Sprintf("configuration changed: %s %v -> %v", change.Key, change.OldValue, change.NewValue)
+		if change.NeedsRestart {
+			notice += " (restart required)"
+		}
+
+		// 실제 로그를 가진 컴포넌트에만 기록 (logging, metrics 등 제외)
+		targets := append([]string{change.Component}, serviceDependents[change.Component]...)
+		for _, target := range targets {
+			if slices.Contains(logComponents, target) {
+				s.logManager.WriteLog(target, logger.LogLevelInfo, notice)
+			}
+		}
+
+		if change.NeedsRestart {
+			for _, dependent := range serviceDependents[change.Component] {
+				affected[dependent] = true
+			}
+		}
+	}
+
+	notified := make([]string, 0, len(affected))
+	for component := range affected {
+		notified = append(notified, component)
+	}
+	sort.Strings(notified)
+
+	return notified
+}
@@ -0,0 +1,75 @@
+package supervisor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ha"
+	"github.com/tmidb/tmidb-core/internal/process"
+)
+
+func TestCandidateConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.LogDir = t.TempDir()
+
+	candidate, err := candidateConfig(cfg, map[string]interface{}{
+		"log_level":       "DEBUG",
+		"nats_port":       float64(4333),
+		"startup_timeout": "45s",
+	})
+	if err != nil {
+		t.Fatalf("candidateConfig: %v", err)
+	}
+	if candidate.LogLevel != "DEBUG" || candidate.NATSPort != 4333 || candidate.StartupTimeout != 45*time.Second {
+		t.Errorf("candidate = %+v", candidate)
+	}
+	if cfg.LogLevel == "DEBUG" || cfg.NATSPort == 4333 {
+		t.Error("candidateConfig modified the current config")
+	}
+
+	changes := diffConfig(cfg, candidate)
+	if len(changes) != 3 || changes[0].Key != "log_level" || changes[1].Key != "nats_port" || changes[2].Key != "startup_timeout" {
+		t.Errorf("changes = %+v", changes)
+	}
+
+	if _, err := candidateConfig(cfg, map[string]interface{}{"nats_prot": float64(1)}); err == nil {
+		t.Error("unknown key accepted")
+	}
+	if _, err := candidateConfig(cfg, map[string]interface{}{"startup_timeout": "soon"}); err == nil {
+		t.Error("invalid duration accepted")
+	}
+	if _, err := candidateConfig(cfg, map[string]interface{}{"nats_port": "4222"}); err == nil {
+		t.Error("string port accepted")
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.LogDir = t.TempDir()
+	if errs, warnings := validateConfig(cfg); len(errs) != 0 || len(warnings) != 0 {
+		t.Errorf("default config: errors %v, warnings %v", errs, warnings)
+	}
+
+	bad := *cfg
+	bad.LogLevel = "VERBOSE"
+	bad.PostgresProfile = "huge"
+	bad.NATSPort = 70000
+	bad.Containers = map[string]process.ContainerConfig{
+		"nats":      {Image: "nats:2"},
+		"redis":     {Image: "redis:7"},
+		"seaweedfs": {},
+	}
+	bad.HA = &ha.Config{Backend: "etcd", URL: "http://etcd:2379"}
+	if errs, _ := validateConfig(&bad); len(errs) != 6 {
+		t.Errorf("errors = %v, want 6", errs)
+	}
+
+	conflict := *cfg
+	conflict.NATSPort = conflict.PostgreSQLPort
+	conflict.LogDir = "/nonexistent/tmidb-logs"
+	conflict.HA = &ha.Config{Backend: "nats", URL: "nats://127.0.0.1:4222"}
+	errs, warnings := validateConfig(&conflict)
+	if len(errs) != 0 || len(warnings) != 3 {
+		t.Errorf("errors %v, warnings %v", errs, warnings)
+	}
+}
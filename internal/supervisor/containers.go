@@ -0,0 +1,31 @@
+package supervisor
+
+import (
+	"github.com/tmidb/tmidb-core/internal/process"
+)
+
+// containerServices are the external services that can run as containers
+var containerServices = []string{"postgresql", "nats", "seaweedfs"}
+
+// startContainerService registers an external service configured under
+// containers and starts its container, or adopts it when it is already
+// running with the same settings. It reports false when the service is not
+// configured as a container and runs from host binaries instead.
+func (s *Supervisor) startContainerService(name string) (bool, error) {
+	container, ok := s.config.Containers[name]
+	if !ok {
+		return false, nil
+	}
+
+	if err := s.processManager.RegisterProcess(&process.ProcessConfig{
+		Name:        name,
+		Type:        process.TypeContainer,
+		Container:   &container,
+		AutoRestart: true,
+		MaxRestarts: 3,
+		HealthCheck: s.healthCheckFor(name),
+	}); err != nil {
+		return true, err
+	}
+	return true, s.processManager.StartProcess(name)
+}
@@ -0,0 +1,222 @@
+package supervisor
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// copyHandshakePrefix starts the first line a sender writes after connecting
+	copyHandshakePrefix = "TMIDB-COPY/1"
+	// copyHandshakeTimeout bounds how long a peer may take to complete the handshake
+	copyHandshakeTimeout = 10 * time.Second
+	// copySelfSignedValidity is the lifetime of generated receiver certificates
+	copySelfSignedValidity = 24 * time.Hour
+)
+
+// copyTLSOptions TLS settings from IPC message data
+type copyTLSOptions struct {
+	Enabled     bool
+	CertFile    string
+	KeyFile     string
+	CAFile      string
+	Fingerprint string
+	Insecure    bool
+}
+
+// parseCopyTLSOptions reads tls, cert_file, key_file, ca_file, tls_fingerprint and insecure
+func parseCopyTLSOptions(data map[string]interface{}) copyTLSOptions {
+	var opts copyTLSOptions
+	opts.Enabled, _ = data["tls"].(bool)
+	opts.CertFile, _ = data["cert_file"].(string)
+	opts.KeyFile, _ = data["key_file"].(string)
+	opts.CAFile, _ = data["ca_file"].(string)
+	opts.Fingerprint, _ = data["tls_fingerprint"].(string)
+	opts.Insecure, _ = data["insecure"].(bool)
+	return opts
+}
+
+// generateCopyToken returns a random one-time session token
+func generateCopyToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// certFingerprint returns the SHA-256 fingerprint of a DER certificate
+func certFingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])
+}
+
+// copyServerTLSConfig loads the provided certificate or generates a self-signed one.
+// The returned fingerprint lets senders pin a self-signed certificate.
+func copyServerTLSConfig(opts copyTLSOptions) (*tls.Config, string, error) {
+	var cert tls.Certificate
+	var err error
+
+	switch {
+	case opts.CertFile != "" && opts.KeyFile != "":
+		cert, err = tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to load certificate: %v", err)
+		}
+	case opts.CertFile != "" || opts.KeyFile != "":
+		return nil, "", errors.New("cert_file and key_file must be provided together")
+	default:
+		cert, err = selfSignedCertificate()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to generate certificate: %v", err)
+		}
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	return config, certFingerprint(cert.Certificate[0]), nil
+}
+
+// selfSignedCertificate creates a short-lived ECDSA certificate for a receiver
+func selfSignedCertificate() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	hostname, _ := os.Hostname()
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "tmidb-copy", Organization: []string{"tmiDB"}},
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     now.Add(copySelfSignedValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost", hostname},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// copyClientTLSConfig builds the sender side configuration.
+// A pinned fingerprint takes precedence, then a CA file, then system roots.
+func copyClientTLSConfig(host string, opts copyTLSOptions) (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName: host,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	switch {
+	case opts.Fingerprint != "":
+		want := strings.ToLower(strings.ReplaceAll(opts.Fingerprint, ":", ""))
+		// 자체 서명 인증서는 체인 검증 대신 지문으로 확인
+		config.InsecureSkipVerify = true
+		config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("receiver presented no certificate")
+			}
+			got := certFingerprint(rawCerts[0])
+			if subtle.ConstantTimeCompare([]byte(got), []byte(want)) != 1 {
+				return fmt.Errorf("certificate fingerprint mismatch: got %s", got)
+			}
+			return nil
+		}
+	case opts.CAFile != "":
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates found in CA file")
+		}
+		config.RootCAs = pool
+	case opts.Insecure:
+		config.InsecureSkipVerify = true
+	}
+
+	return config, nil
+}
+
+// authenticateCopySender reads the sender handshake and checks its token.
+// An empty expected token accepts any sender.
+func authenticateCopySender(conn net.Conn, expected string) error {
+	conn.SetDeadline(time.Now().Add(copyHandshakeTimeout))
+	defer conn.SetDeadline(time.Time{})
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read handshake: %v", err)
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 || fields[0] != copyHandshakePrefix {
+		fmt.Fprintf(conn, "ERR invalid handshake\n")
+		return errors.New("invalid handshake")
+	}
+
+	if expected != "" {
+		token := ""
+		if len(fields) > 1 {
+			token = fields[1]
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+			fmt.Fprintf(conn, "ERR invalid token\n")
+			return errors.New("invalid session token")
+		}
+	}
+
+	_, err = fmt.Fprintf(conn, "OK\n")
+	return err
+}
+
+// presentCopyToken performs the sender side of the handshake
+func presentCopyToken(conn net.Conn, token string) error {
+	conn.SetDeadline(time.Now().Add(copyHandshakeTimeout))
+	defer conn.SetDeadline(time.Time{})
+
+	if _, err := fmt.Fprintf(conn, "%s %s\n", copyHandshakePrefix, token); err != nil {
+		return fmt.Errorf("failed to send handshake: %v", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read handshake reply: %v", err)
+	}
+
+	reply = strings.TrimSpace(reply)
+	if reply != "OK" {
+		return fmt.Errorf("receiver rejected connection: %s", strings.TrimPrefix(reply, "ERR "))
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package supervisor
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+)
+
+// copyHandshake runs both sides of the handshake over a loopback connection
+func copyHandshake(t *testing.T, expected, presented string, server, client *tls.Config) (recvErr, sendErr error) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	if server != nil {
+		listener = tls.NewListener(listener, server)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			done <- err
+			return
+		}
+		defer conn.Close()
+		done <- authenticateCopySender(conn, expected)
+	}()
+
+	var conn net.Conn
+	if client != nil {
+		conn, err = tls.Dial("tcp", listener.Addr().String(), client)
+	} else {
+		conn, err = net.Dial("tcp", listener.Addr().String())
+	}
+	if err != nil {
+		listener.Close()
+		<-done
+		return nil, err
+	}
+	defer conn.Close()
+
+	sendErr = presentCopyToken(conn, presented)
+	conn.Close()
+	return <-done, sendErr
+}
+
+func TestCopyHandshakeToken(t *testing.T) {
+	token, err := generateCopyToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if recvErr, sendErr := copyHandshake(t, token, token, nil, nil); recvErr != nil || sendErr != nil {
+		t.Errorf("valid token: recv=%v send=%v", recvErr, sendErr)
+	}
+	if recvErr, sendErr := copyHandshake(t, token, "wrong", nil, nil); recvErr == nil || sendErr == nil {
+		t.Errorf("wrong token accepted: recv=%v send=%v", recvErr, sendErr)
+	}
+	if recvErr, sendErr := copyHandshake(t, "", "", nil, nil); recvErr != nil || sendErr != nil {
+		t.Errorf("no token: recv=%v send=%v", recvErr, sendErr)
+	}
+}
+
+func TestCopyHandshakeTLSFingerprint(t *testing.T) {
+	server, fingerprint, err := copyServerTLSConfig(copyTLSOptions{Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pinned, err := copyClientTLSConfig("localhost", copyTLSOptions{Enabled: true, Fingerprint: fingerprint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recvErr, sendErr := copyHandshake(t, "", "", server, pinned); recvErr != nil || sendErr != nil {
+		t.Errorf("pinned fingerprint: recv=%v send=%v", recvErr, sendErr)
+	}
+
+	other, _, _ := copyServerTLSConfig(copyTLSOptions{Enabled: true})
+	if _, sendErr := copyHandshake(t, "", "", other, pinned); sendErr == nil {
+		t.Error("handshake succeeded with a different certificate")
+	}
+}
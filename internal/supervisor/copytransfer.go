@@ -0,0 +1,205 @@
+package supervisor
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxCopyNameLength bounds the file name a sender may announce
+const maxCopyNameLength = 4096
+
+// copyProgressWriter reports the bytes copied so far
+type copyProgressWriter struct {
+	w       io.Writer
+	written int64
+	report  func(written int64)
+}
+
+func (p *copyProgressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	if p.report != nil {
+		p.report(p.written)
+	}
+	return n, err
+}
+
+// writeCopyFile sends one file after the handshake:
+// name length (4 bytes) + name + size (8 bytes) + data, then waits for the receiver's reply
+func writeCopyFile(conn io.ReadWriter, name string, size int64, r io.Reader, report func(int64)) error {
+	header := make([]byte, 4+len(name)+8)
+	binary.BigEndian.PutUint32(header, uint32(len(name)))
+	copy(header[4:], name)
+	binary.BigEndian.PutUint64(header[4+len(name):], uint64(size))
+	if _, err := conn.Write(header); err != nil {
+		return fmt.Errorf("failed to send header: %v", err)
+	}
+
+	w := &copyProgressWriter{w: conn, report: report}
+	if _, err := io.CopyN(w, r, size); err != nil {
+		return fmt.Errorf("failed to send data: %v", err)
+	}
+
+	// 수신측이 디스크에 기록을 마칠 때까지 대기
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read receiver reply: %v", err)
+	}
+	if reply = strings.TrimSpace(reply); reply != "OK" {
+		return fmt.Errorf("receiver failed: %s", strings.TrimPrefix(reply, "ERR "))
+	}
+	return nil
+}
+
+// readCopyFile receives one file into dir and returns its path. The data is written
+// to a .part file first, so an interrupted transfer never leaves a truncated file
+// under the final name. The reply tells the sender whether the file was stored.
+func readCopyFile(conn io.ReadWriter, dir string, onHeader func(name string, size int64), report func(int64)) (string, error) {
+	path, err := receiveCopyData(conn, dir, onHeader, report)
+	if err != nil {
+		fmt.Fprintf(conn, "ERR %v\n", err)
+		return "", err
+	}
+	if _, err := fmt.Fprintf(conn, "OK\n"); err != nil {
+		return path, err
+	}
+	return path, nil
+}
+
+func receiveCopyData(r io.Reader, dir string, onHeader func(name string, size int64), report func(int64)) (string, error) {
+	var nameLen uint32
+	if err := binary.Read(r, binary.BigEndian, &nameLen); err != nil {
+		return "", fmt.Errorf("failed to read header: %v", err)
+	}
+	if nameLen == 0 || nameLen > maxCopyNameLength {
+		return "", fmt.Errorf("invalid file name length %d", nameLen)
+	}
+	nameBuf := make([]byte, nameLen)
+	if _, err := io.ReadFull(r, nameBuf); err != nil {
+		return "", fmt.Errorf("failed to read header: %v", err)
+	}
+	var size uint64
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		return "", fmt.Errorf("failed to read header: %v", err)
+	}
+
+	// 보낸 쪽이 정한 이름은 수신 디렉터리 안의 파일 이름으로만 사용
+	name := string(nameBuf)
+	if name != filepath.Base(name) || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid file name %q", name)
+	}
+	if onHeader != nil {
+		onHeader(name, int64(size))
+	}
+
+	path := filepath.Join(dir, name)
+	partial := path + ".part"
+	file, err := os.OpenFile(partial, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %v", err)
+	}
+
+	w := &copyProgressWriter{w: file, report: report}
+	_, err = io.CopyN(w, r, int64(size))
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(partial, path)
+	}
+	if err != nil {
+		os.Remove(partial)
+		return "", fmt.Errorf("failed to receive file: %v", err)
+	}
+	return path, nil
+}
+
+// copySpeed returns the transfer rate in MB/s
+func copySpeed(transferred int64, start time.Time) float64 {
+	elapsed := time.Since(start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(transferred) / (1024 * 1024) / elapsed
+}
+
+// receiveFile stores the file sent over an authenticated connection
+func (s *Supervisor) receiveFile(sessionID string, conn net.Conn) {
+	defer conn.Close()
+
+	session, exists := s.copySessions[sessionID]
+	if !exists {
+		return
+	}
+
+	session.Status = "transferring"
+	started := time.Now()
+
+	path, err := readCopyFile(conn, session.Path,
+		func(name string, size int64) { session.FileSize = size },
+		func(written int64) {
+			session.Transferred = written
+			session.Speed = copySpeed(written, started)
+		})
+	session.EndTime = time.Now()
+	if err != nil {
+		session.Status = "failed"
+		session.Error = err.Error()
+		log.Printf("❌ Copy receiver %s: %v", sessionID, err)
+		return
+	}
+
+	session.File = path
+	session.Status = "completed"
+	log.Printf("Copy receiver %s: received %s (%d bytes)", sessionID, path, session.Transferred)
+}
+
+// sendFile streams the session's file to a connected receiver
+func (s *Supervisor) sendFile(sessionID string, conn net.Conn) {
+	session, exists := s.copySessions[sessionID]
+	if !exists {
+		return
+	}
+
+	session.Status = "transferring"
+
+	file, err := os.Open(session.Path)
+	if err != nil {
+		session.Status = "failed"
+		session.Error = fmt.Sprintf("failed to open file: %v", err)
+		session.EndTime = time.Now()
+		return
+	}
+	defer file.Close()
+
+	started := time.Now()
+	err = writeCopyFile(conn, filepath.Base(session.Path), session.FileSize, file, func(written int64) {
+		session.Transferred = written
+		session.Speed = copySpeed(written, started)
+	})
+	session.EndTime = time.Now()
+	if err != nil {
+		session.Status = "failed"
+		session.Error = err.Error()
+		log.Printf("❌ Copy sender %s: %v", sessionID, err)
+		return
+	}
+
+	session.Status = "completed"
+	log.Printf("Copy sender %s: file sent successfully", sessionID)
+}
@@ -0,0 +1,101 @@
+package supervisor
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// copyConnPair returns both ends of a loopback TCP connection
+func copyConnPair(t *testing.T) (net.Conn, net.Conn) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, _ := listener.Accept()
+		accepted <- conn
+	}()
+	sender, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiver := <-accepted
+	if receiver == nil {
+		t.Fatal("accept failed")
+	}
+	return sender, receiver
+}
+
+func TestCopyFileTransfer(t *testing.T) {
+	dir := t.TempDir()
+	data := bytes.Repeat([]byte("tmidb"), 100000)
+
+	sender, receiver := copyConnPair(t)
+	defer sender.Close()
+
+	type result struct {
+		path string
+		err  error
+	}
+	done := make(chan result, 1)
+	var announced string
+	var received int64
+	go func() {
+		defer receiver.Close()
+		path, err := readCopyFile(receiver, dir,
+			func(name string, size int64) { announced = name },
+			func(written int64) { received = written })
+		done <- result{path, err}
+	}()
+
+	if err := writeCopyFile(sender, "backup.tar.gz", int64(len(data)), bytes.NewReader(data), nil); err != nil {
+		t.Fatal(err)
+	}
+	res := <-done
+	if res.err != nil {
+		t.Fatal(res.err)
+	}
+	if announced != "backup.tar.gz" || received != int64(len(data)) {
+		t.Fatalf("announced %q, received %d", announced, received)
+	}
+	if res.path != filepath.Join(dir, "backup.tar.gz") {
+		t.Fatalf("path = %s", res.path)
+	}
+	got, err := os.ReadFile(res.path)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("received file differs: %d bytes, %v", len(got), err)
+	}
+}
+
+func TestCopyFileRejectsPathInName(t *testing.T) {
+	dir := t.TempDir()
+	sender, receiver := copyConnPair(t)
+	defer sender.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		defer receiver.Close()
+		_, err := readCopyFile(receiver, dir, nil, nil)
+		done <- err
+	}()
+
+	// 수신측은 헤더를 읽자마자 거부하므로 데이터 전송이나 응답 중 하나가 실패함
+	sendErr := writeCopyFile(sender, "../escape", 4, strings.NewReader("evil"), nil)
+	if err := <-done; err == nil || !strings.Contains(err.Error(), "invalid file name") {
+		t.Fatalf("expected invalid file name, got %v", err)
+	}
+	if sendErr == nil {
+		t.Fatal("sender should see the rejection")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("files written: %v", entries)
+	}
+}
@@ -0,0 +1,80 @@
+package supervisor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/sysinfo"
+)
+
+// cpuSampler keeps the previous CPU counters of every sampled PID and of the host,
+// so usage can be reported over the sampling interval instead of since start
+type cpuSampler struct {
+	mu sync.Mutex
+
+	lastTime time.Time
+	lastCPU  map[int]time.Duration
+	usage    map[int]float64
+
+	lastSystem    sysinfo.CPUTimes
+	system        float64
+	systemSampled bool
+}
+
+// sample reads the current counters and updates usage for the given PIDs.
+// PIDs not passed in are forgotten, so a reused PID starts a fresh interval.
+func (c *cpuSampler) sample(pids []int, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elapsed := now.Sub(c.lastTime).Seconds()
+	if c.lastTime.IsZero() {
+		elapsed = 0
+	}
+
+	cpu := make(map[int]time.Duration, len(pids))
+	usage := make(map[int]float64, len(pids))
+	for _, pid := range pids {
+		current, err := sysinfo.ProcessCPUTime(pid)
+		if err != nil {
+			continue
+		}
+		cpu[pid] = current
+
+		if prev, seen := c.lastCPU[pid]; seen && current >= prev && elapsed > 0 {
+			usage[pid] = cpuPercent(current-prev, elapsed)
+		}
+	}
+	c.lastCPU = cpu
+	c.usage = usage
+	c.lastTime = now
+
+	if times, err := sysinfo.SystemCPUTimes(); err == nil {
+		if c.lastSystem.Total > 0 {
+			if usage, ok := times.UsagePercent(c.lastSystem); ok {
+				c.system = usage
+				c.systemSampled = true
+			}
+		}
+		c.lastSystem = times
+	}
+}
+
+// processUsage returns the CPU usage of a PID over the last sampling interval
+func (c *cpuSampler) processUsage(pid int) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.usage[pid]
+}
+
+// systemUsage returns host CPU usage over the last sampling interval
+func (c *cpuSampler) systemUsage() (float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.system, c.systemSampled
+}
+
+// cpuPercent converts CPU time used over elapsed seconds to percent of one core
+func cpuPercent(delta time.Duration, elapsed float64) float64 {
+	return delta.Seconds() / elapsed * 100
+}
@@ -0,0 +1,37 @@
+package supervisor
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/sysinfo"
+)
+
+func TestCPUSamplerInterval(t *testing.T) {
+	if _, err := sysinfo.ProcessCPUTime(os.Getpid()); err != nil {
+		t.Skipf("process cpu time not available: %v", err)
+	}
+
+	var c cpuSampler
+	pid := os.Getpid()
+	start := time.Now()
+	c.sample([]int{pid}, start)
+	if usage := c.processUsage(pid); usage != 0 {
+		t.Errorf("usage before second sample = %f", usage)
+	}
+
+	// 샘플 구간 동안 CPU 사용
+	for deadline := time.Now().Add(300 * time.Millisecond); time.Now().Before(deadline); {
+	}
+	c.sample([]int{pid}, start.Add(time.Second))
+	if usage := c.processUsage(pid); usage <= 0 || usage > 100 {
+		t.Errorf("usage = %f, want (0, 100]", usage)
+	}
+
+	// 목록에서 빠진 PID는 잊음
+	c.sample(nil, start.Add(2*time.Second))
+	if usage := c.processUsage(pid); usage != 0 {
+		t.Errorf("usage of dropped pid = %f", usage)
+	}
+}
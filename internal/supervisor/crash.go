@@ -0,0 +1,383 @@
+package supervisor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/process"
+)
+
+const (
+	// defaultCrashDir is used when crash_dir is not configured
+	defaultCrashDir = "./crashes"
+	// maxCrashDumps is how many dumps are kept per component, so a crash loop cannot fill the disk
+	maxCrashDumps = 20
+	// crashLogLines is how many log entries of the component go into a dump
+	crashLogLines = 200
+	// crashEvents is how many recent supervisor events go into a dump
+	crashEvents = 50
+	// crashSummaryFile holds the CrashDump of a dump directory
+	crashSummaryFile = "crash.json"
+)
+
+// sensitiveEnvMarkers mark environment variables whose values are not written to dumps
+var sensitiveEnvMarkers = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "KEY", "CREDENTIAL"}
+
+// CrashDump describes one crash dump directory
+type CrashDump struct {
+	ID         string    `json:"id"`
+	Component  string    `json:"component"`
+	Time       time.Time `json:"time"`
+	PID        int       `json:"pid"`
+	ExitCode   int       `json:"exit_code"`
+	Signal     string    `json:"signal,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Uptime     string    `json:"uptime"`
+	Restarts   int       `json:"restarts"`
+	Command    string    `json:"command"`
+	Args       []string  `json:"args,omitempty"`
+	CoreDumped bool      `json:"core_dumped"`
+	CoreFile   string    `json:"core_file,omitempty"`
+	CoreNote   string    `json:"core_note,omitempty"`
+	Dir        string    `json:"dir"`
+	Files      []string  `json:"files"`
+}
+
+func (s *Supervisor) crashDir() string {
+	if s.config.CrashDir != "" {
+		return s.config.CrashDir
+	}
+	return defaultCrashDir
+}
+
+// captureCrash writes a diagnostic bundle for a component that exited unexpectedly
+func (s *Supervisor) captureCrash(snapshot *process.CrashSnapshot) {
+	id := fmt.Sprintf("%s-%s-%d", snapshot.Name, snapshot.ExitTime.Format("20060102-150405"), snapshot.PID)
+	dir := filepath.Join(s.crashDir(), id)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		log.Printf("⚠️ Failed to create crash dump directory for %s: %v", snapshot.Name, err)
+		return
+	}
+
+	dump := &CrashDump{
+		ID:         id,
+		Component:  snapshot.Name,
+		Time:       snapshot.ExitTime,
+		PID:        snapshot.PID,
+		ExitCode:   snapshot.ExitCode,
+		Signal:     snapshot.Signal,
+		Error:      snapshot.Error,
+		Restarts:   snapshot.Restarts,
+		Command:    snapshot.Command,
+		Args:       snapshot.Args,
+		CoreDumped: snapshot.CoreDumped,
+		Dir:        dir,
+	}
+	if !snapshot.StartTime.IsZero() {
+		dump.Uptime = snapshot.ExitTime.Sub(snapshot.StartTime).Round(time.Millisecond).String()
+	}
+
+	write := func(name string, data []byte) {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			log.Printf("⚠️ Failed to write %s for crash %s: %v", name, id, err)
+			return
+		}
+		dump.Files = append(dump.Files, name)
+	}
+
+	write("output.log", []byte(formatCrashOutput(snapshot.Output)))
+
+	// 로그 기록기에 남은 줄을 먼저 내보냄
+	if s.logManager != nil {
+		s.logManager.Flush()
+	}
+	logDir := filepath.Join(s.config.LogDir, snapshot.Name)
+	if entries, err := s.readRecentLogsFromDir(logDir, snapshot.Name, crashLogLines); err == nil && len(entries) > 0 {
+		write("component.log", []byte(formatCrashLogs(entries)))
+	}
+
+	if proc := snapshot.Proc; proc != nil {
+		header := fmt.Sprintf("# recorded %s (%s before exit)\n", proc.TakenAt.Format(time.RFC3339),
+			snapshot.ExitTime.Sub(proc.TakenAt).Round(time.Second))
+		write("proc-status.txt", []byte(header+proc.Status))
+		if proc.Limits != "" {
+			write("proc-limits.txt", []byte(header+proc.Limits))
+		}
+	}
+
+	write("environ.txt", []byte(formatCrashEnv(s.redactSecretEnv(snapshot.Env), os.Environ())))
+
+	if events, err := json.MarshalIndent(s.events.recent(crashEvents), "", "  "); err == nil {
+		write("events.json", events)
+	}
+
+	if snapshot.CoreDumped {
+		dump.CoreFile, dump.CoreNote = collectCoreFile(snapshot, dir)
+		if dump.CoreFile != "" && filepath.Dir(dump.CoreFile) == dir {
+			dump.Files = append(dump.Files, filepath.Base(dump.CoreFile))
+		}
+	}
+
+	dump.Files = append(dump.Files, crashSummaryFile)
+	sort.Strings(dump.Files)
+	summary, _ := json.MarshalIndent(dump, "", "  ")
+	if err := os.WriteFile(filepath.Join(dir, crashSummaryFile), summary, 0600); err != nil {
+		log.Printf("⚠️ Failed to write crash summary %s: %v", id, err)
+		return
+	}
+
+	log.Printf("💥 Crash dump for %s written to %s", snapshot.Name, dir)
+	s.pruneCrashDumps(snapshot.Name)
+}
+
+// formatCrashOutput renders the in-memory stdout/stderr lines
+func formatCrashOutput(lines []ipc.OutputLine) string {
+	var b strings.Builder
+	for _, line := range lines {
+		fmt.Fprintf(&b, "%s %s [pid %d] %s\n", line.Time.Format("2006-01-02T15:04:05.000"), line.Stream, line.PID, line.Text)
+	}
+	return b.String()
+}
+
+// formatCrashLogs renders log entries oldest first
+func formatCrashLogs(entries []ipc.LogEntry) string {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Timestamp.Before(entries[j].Timestamp) })
+	var b strings.Builder
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "%s %-5s %s\n", entry.Timestamp.Format("2006-01-02T15:04:05.000"), entry.Level, entry.Message)
+	}
+	return b.String()
+}
+
+// formatCrashEnv lists the configured and inherited environment with secrets redacted
+func formatCrashEnv(configured map[string]string, inherited []string) string {
+	var b strings.Builder
+	b.WriteString("# configured for the component\n")
+	keys := make([]string, 0, len(configured))
+	for k := range configured {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s=%s\n", k, redactEnv(k, configured[k]))
+	}
+
+	b.WriteString("\n# inherited from the supervisor\n")
+	inherited = append([]string(nil), inherited...)
+	sort.Strings(inherited)
+	for _, kv := range inherited {
+		k, v, _ := strings.Cut(kv, "=")
+		if _, overridden := configured[k]; overridden {
+			continue
+		}
+		fmt.Fprintf(&b, "%s=%s\n", k, redactEnv(k, v))
+	}
+	return b.String()
+}
+
+func redactEnv(key, value string) string {
+	upper := strings.ToUpper(key)
+	for _, marker := range sensitiveEnvMarkers {
+		if strings.Contains(upper, marker) {
+			return "<redacted>"
+		}
+	}
+	return value
+}
+
+// collectCoreFile finds the core file the kernel wrote and moves it into the dump.
+// If the core went to a pipe handler (e.g. systemd-coredump) or cannot be found,
+// a note explains where to look instead.
+func collectCoreFile(snapshot *process.CrashSnapshot, dir string) (string, string) {
+	data, err := os.ReadFile("/proc/sys/kernel/core_pattern")
+	if err != nil {
+		return "", "core dumped, but core_pattern is unavailable"
+	}
+	pattern := strings.TrimSpace(string(data))
+	if strings.HasPrefix(pattern, "|") {
+		handler := strings.Fields(strings.TrimPrefix(pattern, "|"))
+		name := "a pipe handler"
+		if len(handler) > 0 {
+			name = filepath.Base(handler[0])
+		}
+		return "", fmt.Sprintf("core handed to %s (e.g. coredumpctl info %d)", name, snapshot.PID)
+	}
+
+	usesPID := false
+	if data, err := os.ReadFile("/proc/sys/kernel/core_uses_pid"); err == nil {
+		usesPID = strings.TrimSpace(string(data)) == "1"
+	}
+	glob := coreFileGlob(pattern, snapshot.PID, filepath.Base(snapshot.Command), usesPID)
+	if !filepath.IsAbs(glob) {
+		base := snapshot.WorkDir
+		if base == "" {
+			base, _ = os.Getwd()
+		}
+		glob = filepath.Join(base, glob)
+	}
+
+	matches, _ := filepath.Glob(glob)
+	var newest string
+	var newestTime time.Time
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil || info.IsDir() || info.ModTime().Before(snapshot.StartTime) {
+			continue
+		}
+		if info.ModTime().After(newestTime) {
+			newest, newestTime = match, info.ModTime()
+		}
+	}
+	if newest == "" {
+		return "", fmt.Sprintf("core dumped, but no file matched %s", glob)
+	}
+
+	target := filepath.Join(dir, "core")
+	if err := os.Rename(newest, target); err != nil {
+		// 다른 파일 시스템이면 크기가 클 수 있으므로 복사하지 않고 위치만 기록
+		return newest, "core file left in place"
+	}
+	return target, ""
+}
+
+// coreFileGlob turns a core_pattern into a glob for this process
+func coreFileGlob(pattern string, pid int, executable string, usesPID bool) string {
+	if len(executable) > 15 {
+		executable = executable[:15] // %e는 comm (최대 15자)
+	}
+	hasPID := false
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' || i+1 == len(pattern) {
+			b.WriteByte(pattern[i])
+			continue
+		}
+		i++
+		switch pattern[i] {
+		case '%':
+			b.WriteByte('%')
+		case 'p', 'P':
+			b.WriteString(strconv.Itoa(pid))
+			hasPID = true
+		case 'e':
+			b.WriteString(executable)
+		default:
+			b.WriteByte('*')
+		}
+	}
+	if usesPID && !hasPID {
+		b.WriteString("." + strconv.Itoa(pid))
+	}
+	return b.String()
+}
+
+// listCrashDumps reads the dump summaries, newest first
+func (s *Supervisor) listCrashDumps(component string) ([]CrashDump, error) {
+	entries, err := os.ReadDir(s.crashDir())
+	if os.IsNotExist(err) {
+		return []CrashDump{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dumps := []CrashDump{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dump, err := s.readCrashDump(entry.Name())
+		if err != nil {
+			continue
+		}
+		if component != "" && dump.Component != component {
+			continue
+		}
+		dumps = append(dumps, *dump)
+	}
+	sort.Slice(dumps, func(i, j int) bool { return dumps[i].Time.After(dumps[j].Time) })
+	return dumps, nil
+}
+
+func (s *Supervisor) readCrashDump(id string) (*CrashDump, error) {
+	if id != filepath.Base(id) || id == "." || id == ".." {
+		return nil, fmt.Errorf("invalid crash id %q", id)
+	}
+	data, err := os.ReadFile(filepath.Join(s.crashDir(), id, crashSummaryFile))
+	if err != nil {
+		return nil, fmt.Errorf("crash %s not found", id)
+	}
+	var dump CrashDump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return nil, fmt.Errorf("invalid crash summary %s: %v", id, err)
+	}
+	return &dump, nil
+}
+
+// pruneCrashDumps removes the oldest dumps of a component beyond maxCrashDumps
+func (s *Supervisor) pruneCrashDumps(component string) {
+	dumps, err := s.listCrashDumps(component)
+	if err != nil || len(dumps) <= maxCrashDumps {
+		return
+	}
+	for _, dump := range dumps[maxCrashDumps:] {
+		if err := os.RemoveAll(filepath.Join(s.crashDir(), dump.ID)); err != nil {
+			log.Printf("⚠️ Failed to remove old crash dump %s: %v", dump.ID, err)
+		}
+	}
+}
+
+// tailFile returns the last n lines of a file
+func tailFile(path string, n int) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// handleDiagnoseCrashes lists crash dumps, or shows one with the end of its output
+func (s *Supervisor) handleDiagnoseCrashes(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	if id, _ := msg.Data["id"].(string); id != "" {
+		dump, err := s.readCrashDump(id)
+		if err != nil {
+			return ipc.NewResponse(msg.ID, false, nil, err.Error())
+		}
+		lines := 20
+		if l, ok := msg.Data["lines"].(float64); ok && l > 0 {
+			lines = int(l)
+		}
+		output, _ := tailFile(filepath.Join(dump.Dir, "output.log"), lines)
+		return ipc.NewResponse(msg.ID, true, map[string]interface{}{
+			"crash":  dump,
+			"output": output,
+		}, "")
+	}
+
+	component, _ := msg.Data["component"].(string)
+	dumps, err := s.listCrashDumps(component)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to list crash dumps: %v", err))
+	}
+	return ipc.NewResponse(msg.ID, true, dumps, "")
+}
@@ -0,0 +1,103 @@
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/process"
+)
+
+func TestCoreFileGlob(t *testing.T) {
+	cases := []struct {
+		pattern string
+		usesPID bool
+		want    string
+	}{
+		{"core", false, "core"},
+		{"core", true, "core.42"},
+		{"/var/crash/core.%e.%p.%t", false, "/var/crash/core.data-consumer.42.*"},
+		{"core-%p", true, "core-42"},
+		{"%%core", false, "%core"},
+	}
+	for _, c := range cases {
+		if got := coreFileGlob(c.pattern, 42, "data-consumer", c.usesPID); got != c.want {
+			t.Errorf("coreFileGlob(%q, %v) = %q, want %q", c.pattern, c.usesPID, got, c.want)
+		}
+	}
+}
+
+func TestFormatCrashEnvRedactsSecrets(t *testing.T) {
+	env := formatCrashEnv(
+		map[string]string{"DB_PASSWORD": "hunter2", "LOG_LEVEL": "debug"},
+		[]string{"PATH=/usr/bin", "TMIDB_IPC_TOKEN=abc", "LOG_LEVEL=info"},
+	)
+	for _, secret := range []string{"hunter2", "abc"} {
+		if strings.Contains(env, secret) {
+			t.Errorf("secret %q written to the dump:\n%s", secret, env)
+		}
+	}
+	if !strings.Contains(env, "LOG_LEVEL=debug") || strings.Contains(env, "LOG_LEVEL=info") {
+		t.Errorf("configured values should override inherited ones:\n%s", env)
+	}
+	if !strings.Contains(env, "PATH=/usr/bin") {
+		t.Errorf("inherited environment missing:\n%s", env)
+	}
+}
+
+func TestCaptureCrash(t *testing.T) {
+	dir := t.TempDir()
+	s := &Supervisor{config: &Config{CrashDir: dir, LogDir: t.TempDir()}}
+	s.events.publish(ipc.Event{Type: ipc.EventProcessCrashed, Component: "api"})
+
+	exit := time.Now()
+	snapshot := &process.CrashSnapshot{
+		Name:      "api",
+		Command:   "/usr/local/bin/api",
+		PID:       1234,
+		StartTime: exit.Add(-time.Minute),
+		ExitTime:  exit,
+		ExitCode:  2,
+		Error:     "exit status 2",
+		Proc:      &process.ProcSnapshot{Status: "Name:\tapi\nVmRSS:\t1024 kB\n", TakenAt: exit.Add(-3 * time.Second)},
+		Output: []ipc.OutputLine{
+			{Time: exit, Stream: "stderr", PID: 1234, Text: "panic: nil map"},
+		},
+	}
+	for i := 0; i < maxCrashDumps+2; i++ {
+		snapshot.PID = 1000 + i
+		snapshot.ExitTime = exit.Add(time.Duration(i) * time.Second)
+		s.captureCrash(snapshot)
+	}
+
+	dumps, err := s.listCrashDumps("api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dumps) != maxCrashDumps {
+		t.Fatalf("kept %d dumps, want %d", len(dumps), maxCrashDumps)
+	}
+	newest := dumps[0]
+	if newest.PID != 1000+maxCrashDumps+1 || newest.ExitCode != 2 || newest.Uptime == "" {
+		t.Fatalf("unexpected newest dump: %+v", newest)
+	}
+	for _, name := range []string{"output.log", "proc-status.txt", "environ.txt", "events.json", crashSummaryFile} {
+		if _, err := os.Stat(filepath.Join(newest.Dir, name)); err != nil {
+			t.Errorf("%s missing: %v", name, err)
+		}
+	}
+
+	output, _ := tailFile(filepath.Join(newest.Dir, "output.log"), 5)
+	if len(output) != 1 || !strings.Contains(output[0], "panic: nil map") {
+		t.Fatalf("output.log = %v", output)
+	}
+	if dumps, _ := s.listCrashDumps("data-consumer"); len(dumps) != 0 {
+		t.Fatalf("component filter returned %d dumps", len(dumps))
+	}
+	if _, err := s.readCrashDump("../etc"); err == nil {
+		t.Fatal("expected an error for a path as crash id")
+	}
+}
@@ -0,0 +1,127 @@
+package supervisor
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/tmidb/tmidb-core/internal/config"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// openPolicyDB opens a connection for TimescaleDB policy management
+func openPolicyDB() (*sql.DB, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %v", err)
+	}
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("database unavailable: %v", err)
+	}
+	return db, nil
+}
+
+// handleDBPolicyList returns the stored policies with their TimescaleDB jobs
+// and compression statistics
+func (s *Supervisor) handleDBPolicyList(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	statuses, err := database.TimeseriesPolicyStatuses(db)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to read policies: %v", err))
+	}
+	return ipc.NewResponse(msg.ID, true, statuses, "")
+}
+
+// handleDBPolicySet changes the given fields of a policy and applies it.
+// An empty string clears a setting.
+func (s *Supervisor) handleDBPolicySet(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	name, _ := msg.Data["name"].(string)
+	if name == "" {
+		return ipc.NewResponse(msg.ID, false, nil, "policy name required")
+	}
+
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	policy, err := database.GetTimeseriesPolicy(db, name)
+	if err == sql.ErrNoRows {
+		policy, err = &database.TimeseriesPolicy{Name: name}, nil
+	}
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to read policy: %v", err))
+	}
+
+	if value, ok := msg.Data["compress_after"].(string); ok {
+		policy.CompressAfter = value
+	}
+	if value, ok := msg.Data["retain_for"].(string); ok {
+		policy.RetainFor = value
+	}
+	if value, ok := msg.Data["aggregate_bucket"].(string); ok {
+		policy.AggregateBucket = value
+	}
+	if values, ok := msg.Data["aggregate_fields"].([]interface{}); ok {
+		policy.AggregateFields = nil
+		for _, value := range values {
+			if field, ok := value.(string); ok && field != "" {
+				policy.AggregateFields = append(policy.AggregateFields, field)
+			}
+		}
+	}
+
+	if err := database.SetTimeseriesPolicy(db, policy); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, policy, "")
+}
+
+// handleDBPolicyRemove removes a policy together with its jobs and
+// continuous aggregate
+func (s *Supervisor) handleDBPolicyRemove(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	name, _ := msg.Data["name"].(string)
+	if name == "" {
+		return ipc.NewResponse(msg.ID, false, nil, "policy name required")
+	}
+
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	if err := database.RemoveTimeseriesPolicy(db, name); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, map[string]string{"name": name}, "")
+}
+
+// handleDBPolicyApply re-applies all stored policies, e.g. after a restore
+func (s *Supervisor) handleDBPolicyApply(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	if err := database.ApplyTimeseriesPolicies(db); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	statuses, err := database.TimeseriesPolicyStatuses(db)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to read policies: %v", err))
+	}
+	return ipc.NewResponse(msg.ID, true, statuses, "")
+}
@@ -0,0 +1,91 @@
+package supervisor
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// handleDBRollupList returns the rollup jobs with their progress per resolution
+func (s *Supervisor) handleDBRollupList(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	jobs, err := database.ListRollupJobs(db)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to read rollup jobs: %v", err))
+	}
+	return ipc.NewResponse(msg.ID, true, jobs, "")
+}
+
+// handleDBRollupSet creates a rollup job or changes the given fields of one.
+// The data manager picks up the change on its next tick.
+func (s *Supervisor) handleDBRollupSet(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	category, _ := msg.Data["category"].(string)
+	if category == "" {
+		return ipc.NewResponse(msg.ID, false, nil, "category required")
+	}
+
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	job, err := database.GetRollupJob(db, category)
+	if err == sql.ErrNoRows {
+		job, err = &database.RollupJob{Category: category, IsActive: true}, nil
+	}
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to read rollup job: %v", err))
+	}
+
+	if values, ok := msg.Data["resolutions"].([]interface{}); ok {
+		job.Resolutions = stringList(values)
+	}
+	if values, ok := msg.Data["fields"].([]interface{}); ok {
+		job.Fields = stringList(values)
+	}
+	if active, ok := msg.Data["is_active"].(bool); ok {
+		job.IsActive = active
+	}
+
+	if err := database.SetRollupJob(db, job); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, job, "")
+}
+
+// handleDBRollupRemove removes a rollup job and its summaries
+func (s *Supervisor) handleDBRollupRemove(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	category, _ := msg.Data["category"].(string)
+	if category == "" {
+		return ipc.NewResponse(msg.ID, false, nil, "category required")
+	}
+
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	if err := database.RemoveRollupJob(db, category); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, map[string]string{"category": category}, "")
+}
+
+func stringList(values []interface{}) []string {
+	var list []string
+	for _, value := range values {
+		if s, ok := value.(string); ok && s != "" {
+			list = append(list, s)
+		}
+	}
+	return list
+}
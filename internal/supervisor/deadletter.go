@@ -0,0 +1,382 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/tmidb/tmidb-core/internal/dataconsumer"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+const (
+	// dlqListLimit caps dlq list when no limit is given
+	dlqListLimit = 100
+	// dlqPreviewBytes is how much of each body dlq list returns
+	dlqPreviewBytes = 120
+	// dlqReplayTimeout bounds a bulk replay or delete
+	dlqReplayTimeout = 30 * time.Minute
+)
+
+// dlqFilter selects dead-lettered messages by sequence, organization,
+// category and error text
+type dlqFilter struct {
+	seqs     []uint64
+	org      string
+	category string
+	errText  string
+	since    time.Time
+}
+
+// parseDLQFilter reads the filter fields shared by the dlq handlers
+func parseDLQFilter(data map[string]interface{}) (dlqFilter, error) {
+	var f dlqFilter
+	f.org, _ = data["org"].(string)
+	f.category, _ = data["category"].(string)
+	f.errText, _ = data["error"].(string)
+	for _, token := range []string{f.org, f.category} {
+		if strings.ContainsAny(token, ".*> ") {
+			return f, fmt.Errorf("invalid organization or category %q", token)
+		}
+	}
+	if since, _ := data["since"].(string); since != "" {
+		d, err := time.ParseDuration(since)
+		if err != nil {
+			return f, fmt.Errorf("invalid since %q: %v", since, err)
+		}
+		f.since = time.Now().Add(-d)
+	}
+	if list, ok := data["seqs"].([]interface{}); ok {
+		for _, item := range list {
+			seq, ok := item.(float64)
+			if !ok || seq < 1 || seq != float64(uint64(seq)) {
+				return f, fmt.Errorf("invalid sequence %v", item)
+			}
+			f.seqs = append(f.seqs, uint64(seq))
+		}
+	}
+	return f, nil
+}
+
+// subject is the dead-letter subject filter (tmidb.deadletter.ingest.<org>.<category>)
+func (f dlqFilter) subject() string {
+	org, category := f.org, f.category
+	if org == "" {
+		org = "*"
+	}
+	if category == "" {
+		category = "*"
+	}
+	return dataconsumer.DeadLetterSubjectPrefix + "." + org + "." + category
+}
+
+// matches applies the checks the subject filter cannot express
+func (f dlqFilter) matches(msg *jetstream.RawStreamMsg) bool {
+	if !f.since.IsZero() && msg.Time.Before(f.since) {
+		return false
+	}
+	if f.errText != "" && !strings.Contains(strings.ToLower(msg.Header.Get(dataconsumer.HeaderIngestError)), strings.ToLower(f.errText)) {
+		return false
+	}
+	return true
+}
+
+// eachDeadLetter calls fn for every matching message, oldest first, until fn
+// returns false. Messages dead-lettered after the scan starts (such as replays
+// that fail again) are not visited.
+func eachDeadLetter(ctx context.Context, stream jetstream.Stream, f dlqFilter, fn func(*jetstream.RawStreamMsg) (bool, error)) error {
+	if len(f.seqs) > 0 {
+		for _, seq := range f.seqs {
+			msg, err := stream.GetMsg(ctx, seq)
+			if errors.Is(err, jetstream.ErrMsgNotFound) {
+				return fmt.Errorf("dead-letter message %d not found", seq)
+			}
+			if err != nil {
+				return err
+			}
+			if next, err := fn(msg); err != nil || !next {
+				return err
+			}
+		}
+		return nil
+	}
+
+	info, err := stream.Info(ctx)
+	if err != nil {
+		return err
+	}
+	last := info.State.LastSeq
+	subject := f.subject()
+	for seq := info.State.FirstSeq; seq > 0 && seq <= last; {
+		msg, err := stream.GetMsg(ctx, seq, jetstream.WithGetMsgSubject(subject))
+		if errors.Is(err, jetstream.ErrMsgNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if msg.Sequence > last {
+			return nil
+		}
+		seq = msg.Sequence + 1
+		if !f.matches(msg) {
+			continue
+		}
+		if next, err := fn(msg); err != nil || !next {
+			return err
+		}
+	}
+	return nil
+}
+
+// deadLetterSummary describes a dead-lettered message for dlq list
+func deadLetterSummary(msg *jetstream.RawStreamMsg) map[string]interface{} {
+	subject := originalSubject(msg)
+	parts := strings.Split(strings.TrimPrefix(subject, dataconsumer.IngestSubjectPrefix+"."), ".")
+	entry := map[string]interface{}{
+		"seq":        msg.Sequence,
+		"time":       msg.Time,
+		"subject":    subject,
+		"error":      msg.Header.Get(dataconsumer.HeaderIngestError),
+		"deliveries": msg.Header.Get(dataconsumer.HeaderIngestDeliveries),
+		"size":       len(msg.Data),
+	}
+	if len(parts) == 2 {
+		entry["org"], entry["category"] = parts[0], parts[1]
+	}
+	var record dataconsumer.IngestRecord
+	if json.Unmarshal(msg.Data, &record) == nil && record.TargetID != "" {
+		entry["target_id"] = record.TargetID
+	}
+	if replayOf := msg.Header.Get(dataconsumer.HeaderIngestReplayOf); replayOf != "" {
+		entry["replay_of"] = replayOf
+	}
+	preview := string(msg.Data)
+	if len(preview) > dlqPreviewBytes {
+		preview = preview[:dlqPreviewBytes] + "…"
+	}
+	entry["preview"] = preview
+	return entry
+}
+
+// originalSubject is the ingest subject a dead-lettered message was first published to
+func originalSubject(msg *jetstream.RawStreamMsg) string {
+	if subject := msg.Header.Get(dataconsumer.HeaderIngestSubject); subject != "" {
+		return subject
+	}
+	return dataconsumer.IngestSubjectPrefix + strings.TrimPrefix(msg.Subject, dataconsumer.DeadLetterSubjectPrefix)
+}
+
+// replayMsg rebuilds the original ingest message, without the failure headers
+func replayMsg(msg *jetstream.RawStreamMsg, body []byte) *nats.Msg {
+	replay := nats.NewMsg(originalSubject(msg))
+	replay.Data = msg.Data
+	if body != nil {
+		replay.Data = body
+	}
+	for key, values := range msg.Header {
+		switch {
+		case strings.HasPrefix(key, "Nats-"),
+			key == dataconsumer.HeaderIngestError,
+			key == dataconsumer.HeaderIngestSubject,
+			key == dataconsumer.HeaderIngestDeliveries,
+			key == dataconsumer.HeaderIngestReplayOf:
+			continue
+		}
+		for _, v := range values {
+			replay.Header.Add(key, v)
+		}
+	}
+	replay.Header.Set(dataconsumer.HeaderIngestReplayOf, strconv.FormatUint(msg.Sequence, 10))
+	return replay
+}
+
+// openDeadLetters connects to JetStream and opens the dead-letter stream
+func openDeadLetters(ctx context.Context) (*nats.Conn, jetstream.JetStream, jetstream.Stream, error) {
+	nc, js, err := connectJetStream()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	stream, err := js.Stream(ctx, dataconsumer.DeadLetterStream)
+	if err != nil {
+		nc.Close()
+		return nil, nil, nil, fmt.Errorf("dead-letter stream %s: %w", dataconsumer.DeadLetterStream, err)
+	}
+	return nc, js, stream, nil
+}
+
+// handleDLQList lists dead-lettered ingest messages with their failure reasons
+func (s *Supervisor) handleDLQList(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	f, err := parseDLQFilter(msg.Data)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	limit := dlqListLimit
+	if l, ok := msg.Data["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, dlqReplayTimeout)
+	defer cancel()
+	nc, _, stream, err := openDeadLetters(ctx)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer nc.Close()
+
+	entries := []map[string]interface{}{}
+	total := 0
+	err = eachDeadLetter(ctx, stream, f, func(m *jetstream.RawStreamMsg) (bool, error) {
+		total++
+		if len(entries) < limit {
+			entries = append(entries, deadLetterSummary(m))
+		}
+		return true, nil
+	})
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to read dead-letter queue: %v", err))
+	}
+
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
+		"messages": entries,
+		"total":    total,
+	}, "")
+}
+
+// handleDLQShow returns one dead-lettered message in full
+func (s *Supervisor) handleDLQShow(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	f, err := parseDLQFilter(msg.Data)
+	if err != nil || len(f.seqs) != 1 {
+		return ipc.NewResponse(msg.ID, false, nil, "one dead-letter sequence is required")
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, streamRequestTimeout)
+	defer cancel()
+	nc, _, stream, err := openDeadLetters(ctx)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer nc.Close()
+
+	var entry map[string]interface{}
+	err = eachDeadLetter(ctx, stream, f, func(m *jetstream.RawStreamMsg) (bool, error) {
+		entry = deadLetterSummary(m)
+		delete(entry, "preview")
+		entry["body"] = string(m.Data)
+		headers := map[string]string{}
+		for key := range m.Header {
+			headers[key] = m.Header.Get(key)
+		}
+		entry["headers"] = headers
+		return false, nil
+	})
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, entry, "")
+}
+
+// handleDLQReplay publishes dead-lettered messages back to their ingest
+// subjects so the pipeline processes them again, then removes them from the
+// queue. A single message can be replayed with a corrected body. Messages that
+// fail again are dead-lettered anew with a Tmidb-Ingest-Replay-Of header.
+func (s *Supervisor) handleDLQReplay(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	f, err := parseDLQFilter(msg.Data)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	all, _ := msg.Data["all"].(bool)
+	keep, _ := msg.Data["keep"].(bool)
+	dryRun, _ := msg.Data["dry_run"].(bool)
+	if len(f.seqs) == 0 && !all && f.org == "" && f.category == "" && f.errText == "" && f.since.IsZero() {
+		return ipc.NewResponse(msg.ID, false, nil, "select messages by sequence or filter, or replay all of them explicitly")
+	}
+
+	var body []byte
+	if edited, ok := msg.Data["body"].(string); ok {
+		if len(f.seqs) != 1 {
+			return ipc.NewResponse(msg.ID, false, nil, "a corrected body can only be given for a single message")
+		}
+		if !json.Valid([]byte(edited)) {
+			return ipc.NewResponse(msg.ID, false, nil, "corrected body is not valid JSON")
+		}
+		body = []byte(edited)
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, dlqReplayTimeout)
+	defer cancel()
+	nc, js, stream, err := openDeadLetters(ctx)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer nc.Close()
+
+	replayed, failed := 0, []string{}
+	err = eachDeadLetter(ctx, stream, f, func(m *jetstream.RawStreamMsg) (bool, error) {
+		if dryRun {
+			replayed++
+			return true, nil
+		}
+		if _, err := js.PublishMsg(ctx, replayMsg(m, body)); err != nil {
+			failed = append(failed, fmt.Sprintf("%d: %v", m.Sequence, err))
+			return true, nil
+		}
+		replayed++
+		if !keep {
+			// 다시 보낸 메시지는 큐에서 지움 (다시 실패하면 새 시퀀스로 들어옴)
+			if err := stream.DeleteMsg(ctx, m.Sequence); err != nil {
+				failed = append(failed, fmt.Sprintf("%d: replayed but not removed: %v", m.Sequence, err))
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("replay stopped after %d messages: %v", replayed, err))
+	}
+
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
+		"replayed": replayed,
+		"failed":   failed,
+		"dry_run":  dryRun,
+	}, "")
+}
+
+// handleDLQDelete removes dead-lettered messages that should not be replayed
+func (s *Supervisor) handleDLQDelete(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	f, err := parseDLQFilter(msg.Data)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	all, _ := msg.Data["all"].(bool)
+	if len(f.seqs) == 0 && !all && f.org == "" && f.category == "" && f.errText == "" && f.since.IsZero() {
+		return ipc.NewResponse(msg.ID, false, nil, "select messages by sequence or filter, or delete all of them explicitly")
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, dlqReplayTimeout)
+	defer cancel()
+	nc, _, stream, err := openDeadLetters(ctx)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer nc.Close()
+
+	deleted := 0
+	err = eachDeadLetter(ctx, stream, f, func(m *jetstream.RawStreamMsg) (bool, error) {
+		if err := stream.DeleteMsg(ctx, m.Sequence); err != nil {
+			return false, err
+		}
+		deleted++
+		return true, nil
+	})
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("delete stopped after %d messages: %v", deleted, err))
+	}
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{"deleted": deleted}, "")
+}
@@ -0,0 +1,65 @@
+package supervisor
+
+import (
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/tmidb/tmidb-core/internal/dataconsumer"
+)
+
+func TestReplayMsg(t *testing.T) {
+	dead := &jetstream.RawStreamMsg{
+		Subject:  "tmidb.deadletter.ingest.org1.sensor",
+		Sequence: 42,
+		Data:     []byte(`{"target_id":"t1","data":{"temp":"hot"}}`),
+		Header: nats.Header{
+			dataconsumer.HeaderIngestError:      []string{"schema validation failed"},
+			dataconsumer.HeaderIngestSubject:    []string{"tmidb.ingest.org1.sensor"},
+			dataconsumer.HeaderIngestDeliveries: []string{"1"},
+			"Nats-Msg-Id":                       []string{"abc"},
+			"Trace-Id":                          []string{"t-1"},
+		},
+	}
+
+	replay := replayMsg(dead, nil)
+	if replay.Subject != "tmidb.ingest.org1.sensor" || string(replay.Data) != string(dead.Data) {
+		t.Errorf("replay = %s %s", replay.Subject, replay.Data)
+	}
+	if replay.Header.Get(dataconsumer.HeaderIngestError) != "" || replay.Header.Get("Nats-Msg-Id") != "" {
+		t.Errorf("failure headers were copied: %v", replay.Header)
+	}
+	if replay.Header.Get("Trace-Id") != "t-1" || replay.Header.Get(dataconsumer.HeaderIngestReplayOf) != "42" {
+		t.Errorf("headers = %v", replay.Header)
+	}
+
+	// 헤더가 없으면 데드레터 주제에서 원래 주제를 계산
+	dead.Header = nats.Header{}
+	replay = replayMsg(dead, []byte(`{"target_id":"t1","data":{"temp":21}}`))
+	if replay.Subject != "tmidb.ingest.org1.sensor" || string(replay.Data) != `{"target_id":"t1","data":{"temp":21}}` {
+		t.Errorf("edited replay = %s %s", replay.Subject, replay.Data)
+	}
+}
+
+func TestParseDLQFilter(t *testing.T) {
+	f, err := parseDLQFilter(map[string]interface{}{"category": "sensor", "seqs": []interface{}{float64(3), float64(1000000)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.subject() != "tmidb.deadletter.ingest.*.sensor" || len(f.seqs) != 2 || f.seqs[1] != 1000000 {
+		t.Errorf("filter = %+v, subject %s", f, f.subject())
+	}
+
+	for _, data := range []map[string]interface{}{
+		{"org": "a.b"},
+		{"category": ">"},
+		{"seqs": []interface{}{float64(0)}},
+		{"seqs": []interface{}{"x"}},
+		{"since": "yesterday"},
+	} {
+		if _, err := parseDLQFilter(data); err == nil {
+			t.Errorf("parseDLQFilter(%v) accepted", data)
+		}
+	}
+}
@@ -0,0 +1,485 @@
+package supervisor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/tmidb/tmidb-core/internal/config"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/sysinfo"
+
+	_ "github.com/lib/pq"
+)
+
+// Diagnostic thresholds
+const (
+	diagnoseTimeout     = 20 * time.Second
+	diagnoseDialTimeout = 2 * time.Second
+
+	pgLatencyWarn   = 100 * time.Millisecond
+	natsRTTWarn     = 50 * time.Millisecond
+	diskUsageWarn   = 80.0
+	diskUsageFail   = 90.0
+	logErrorWindow  = time.Hour
+	logErrorRateMin = 20 // 이보다 적은 로그는 비율을 판단하지 않음
+	logErrorWarn    = 5.0
+	logErrorFail    = 20.0
+)
+
+// schemaTables lists the tables created by database.InitSchema
+var schemaTables = []string{
+	"organizations", "category_schemas", "target", "target_categories",
+	"ts_obs", "geo_trace", "raw_bucket", "file_attachments", "listeners",
+	"users", "auth_tokens", "system_config", "user_access_tokens",
+	"timeseries_policies", "change_events", "listener_stats",
+}
+
+// Check/component statuses as rendered by `tmidb-cli diagnose`
+const (
+	checkPassed  = "passed"
+	checkWarning = "warning"
+	checkFailed  = "failed"
+
+	statusHealthy  = "healthy"
+	statusWarning  = "warning"
+	statusCritical = "critical"
+)
+
+// diagnosticCheck is a single check result
+type diagnosticCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+
+	solution string
+}
+
+// componentDiagnostic holds the checks for one component
+type componentDiagnostic struct {
+	Status  string                 `json:"status"`
+	Checks  []diagnosticCheck      `json:"checks"`
+	Metrics map[string]interface{} `json:"metrics,omitempty"`
+}
+
+// diagnosticIssue is a failed or degraded check surfaced in the report
+type diagnosticIssue struct {
+	Severity  string `json:"severity"`
+	Title     string `json:"title"`
+	Component string `json:"component"`
+	Details   string `json:"details"`
+	Solution  string `json:"solution,omitempty"`
+}
+
+// diagnosticReport is the response of MessageTypeDiagnoseAll
+type diagnosticReport struct {
+	Status          string                          `json:"status"`
+	Timestamp       string                          `json:"timestamp"`
+	Duration        string                          `json:"duration"`
+	Components      map[string]*componentDiagnostic `json:"components"`
+	Issues          []diagnosticIssue               `json:"issues"`
+	Recommendations []string                        `json:"recommendations"`
+}
+
+func newComponentDiagnostic() *componentDiagnostic {
+	return &componentDiagnostic{
+		Status:  statusHealthy,
+		Checks:  make([]diagnosticCheck, 0),
+		Metrics: make(map[string]interface{}),
+	}
+}
+
+func (c *componentDiagnostic) pass(name, message string) {
+	c.Checks = append(c.Checks, diagnosticCheck{Name: name, Status: checkPassed, Message: message})
+}
+
+func (c *componentDiagnostic) warn(name, message, solution string) {
+	c.Checks = append(c.Checks, diagnosticCheck{Name: name, Status: checkWarning, Message: message, solution: solution})
+	if c.Status == statusHealthy {
+		c.Status = statusWarning
+	}
+}
+
+func (c *componentDiagnostic) fail(name, message, solution string) {
+	c.Checks = append(c.Checks, diagnosticCheck{Name: name, Status: checkFailed, Message: message, solution: solution})
+	c.Status = statusCritical
+}
+
+// handleDiagnoseAll runs every diagnostic check and returns a combined report
+func (s *Supervisor) handleDiagnoseAll(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	ctx, cancel := context.WithTimeout(s.ctx, diagnoseTimeout)
+	defer cancel()
+
+	return ipc.NewResponse(msg.ID, true, s.runDiagnostics(ctx), "")
+}
+
+// runDiagnostics runs all component checks concurrently
+func (s *Supervisor) runDiagnostics(ctx context.Context) *diagnosticReport {
+	start := time.Now()
+
+	cfg, cfgErr := config.Load()
+
+	checks := map[string]func(context.Context, *componentDiagnostic){
+		"postgresql": func(ctx context.Context, c *componentDiagnostic) { s.diagnosePostgreSQL(ctx, c, cfg) },
+		"schema":     func(ctx context.Context, c *componentDiagnostic) { s.diagnoseSchema(ctx, c, cfg) },
+		"nats":       func(ctx context.Context, c *componentDiagnostic) { s.diagnoseNATS(ctx, c, cfg) },
+		"seaweedfs":  s.diagnoseSeaweedFS,
+		"system":     s.diagnoseSystem,
+	}
+	if cfgErr != nil {
+		// 잘못된 설정으로는 연결 정보를 알 수 없음
+		for _, name := range []string{"postgresql", "schema", "nats"} {
+			checks[name] = func(ctx context.Context, c *componentDiagnostic) {
+				c.fail("Configuration", cfgErr.Error(), "Run 'tmidb-cli config doctor' and fix the reported keys")
+			}
+		}
+	}
+	for _, name := range []string{"api", "data-manager", "data-consumer"} {
+		checks[name] = func(ctx context.Context, c *componentDiagnostic) { s.diagnoseProcess(c, name) }
+	}
+
+	components := make(map[string]*componentDiagnostic, len(checks))
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		c := newComponentDiagnostic()
+		components[name] = c
+
+		wg.Add(1)
+		go func(check func(context.Context, *componentDiagnostic), c *componentDiagnostic) {
+			defer wg.Done()
+			check(ctx, c)
+		}(check, c)
+	}
+	wg.Wait()
+
+	// 로그 에러율은 각 컴포넌트 결과에 덧붙임
+	for _, name := range logComponents {
+		if c, ok := components[name]; ok {
+			s.diagnoseLogErrorRate(c, name)
+		}
+	}
+
+	return buildDiagnosticReport(components, start)
+}
+
+// buildDiagnosticReport derives overall status, issues and recommendations
+func buildDiagnosticReport(components map[string]*componentDiagnostic, start time.Time) *diagnosticReport {
+	report := &diagnosticReport{
+		Status:          statusHealthy,
+		Timestamp:       time.Now().Format(time.RFC3339),
+		Components:      components,
+		Issues:          make([]diagnosticIssue, 0),
+		Recommendations: make([]string, 0),
+	}
+
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	seen := make(map[string]bool)
+	for _, name := range names {
+		c := components[name]
+		switch c.Status {
+		case statusCritical:
+			report.Status = statusCritical
+		case statusWarning:
+			if report.Status == statusHealthy {
+				report.Status = statusWarning
+			}
+		}
+
+		for _, check := range c.Checks {
+			if check.Status == checkPassed {
+				continue
+			}
+
+			severity := "warning"
+			if check.Status == checkFailed {
+				severity = "critical"
+			}
+			report.Issues = append(report.Issues, diagnosticIssue{
+				Severity:  severity,
+				Title:     check.Name,
+				Component: name,
+				Details:   check.Message,
+				Solution:  check.solution,
+			})
+		}
+
+		if c.Status != statusHealthy && !seen[name] {
+			seen[name] = true
+			report.Recommendations = append(report.Recommendations,
+				fmt.Sprintf("Inspect recent logs with 'tmidb-cli logs %s'", name))
+		}
+	}
+
+	// critical 이슈를 먼저 표시
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		return report.Issues[i].Severity == "critical" && report.Issues[j].Severity != "critical"
+	})
+
+	report.Duration = time.Since(start).Round(time.Millisecond).String()
+	return report
+}
+
+// checkPort verifies that a local TCP port accepts connections
+func checkPort(ctx context.Context, c *componentDiagnostic, port int) bool {
+	name := fmt.Sprintf("Port %d reachable", port)
+
+	dialer := net.Dialer{Timeout: diagnoseDialTimeout}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort("localhost", strconv.Itoa(port)))
+	if err != nil {
+		c.fail(name, err.Error(), fmt.Sprintf("Make sure the service is running and listening on port %d", port))
+		return false
+	}
+	conn.Close()
+
+	c.Metrics["port"] = port
+	c.pass(name, fmt.Sprintf("connected in %v", time.Since(start).Round(time.Microsecond)))
+	return true
+}
+
+// diagnosePostgreSQL checks port reachability and query latency
+func (s *Supervisor) diagnosePostgreSQL(ctx context.Context, c *componentDiagnostic, cfg *config.Config) {
+	if !checkPort(ctx, c, s.config.PostgreSQLPort) {
+		return
+	}
+
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		c.fail("Database connection", err.Error(), "Check DB_HOST/DB_PORT and TMIDB_USER/TMIDB_PASSWORD")
+		return
+	}
+	defer db.Close()
+
+	if err := db.PingContext(ctx); err != nil {
+		c.fail("Database connection", err.Error(), "Check DB_HOST/DB_PORT and TMIDB_USER/TMIDB_PASSWORD")
+		return
+	}
+	c.pass("Database connection", "authenticated as "+cfg.TmiDBUser)
+
+	start := time.Now()
+	var one int
+	if err := db.QueryRowContext(ctx, "SELECT 1").Scan(&one); err != nil {
+		c.fail("Query latency", err.Error(), "Check PostgreSQL logs for errors")
+		return
+	}
+	latency := time.Since(start)
+	c.Metrics["query_latency_ms"] = float64(latency.Microseconds()) / 1000
+
+	message := fmt.Sprintf("SELECT 1 took %v", latency.Round(time.Microsecond))
+	if latency > pgLatencyWarn {
+		c.warn("Query latency", message, "Check PostgreSQL load and slow queries (pg_stat_activity)")
+	} else {
+		c.pass("Query latency", message)
+	}
+
+	var connections int
+	if err := db.QueryRowContext(ctx, "SELECT count(*) FROM pg_stat_activity").Scan(&connections); err == nil {
+		c.Metrics["connections"] = connections
+	}
+}
+
+// diagnoseSchema checks that all tmiDB tables exist
+func (s *Supervisor) diagnoseSchema(ctx context.Context, c *componentDiagnostic, cfg *config.Config) {
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		c.fail("Schema tables", err.Error(), "Check database connectivity first")
+		return
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx,
+		"SELECT table_name FROM information_schema.tables WHERE table_schema = 'public'")
+	if err != nil {
+		c.fail("Schema tables", err.Error(), "Check database connectivity first")
+		return
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err == nil {
+			existing[table] = true
+		}
+	}
+
+	var missing []string
+	for _, table := range schemaTables {
+		if !existing[table] {
+			missing = append(missing, table)
+		}
+	}
+
+	c.Metrics["tables_expected"] = len(schemaTables)
+	c.Metrics["tables_found"] = len(schemaTables) - len(missing)
+
+	if len(missing) > 0 {
+		c.fail("Schema tables", "missing: "+strings.Join(missing, ", "),
+			"Restart the api component to re-run schema initialization")
+		return
+	}
+	c.pass("Schema tables", fmt.Sprintf("all %d tables present", len(schemaTables)))
+}
+
+// diagnoseNATS checks port reachability and round-trip time
+func (s *Supervisor) diagnoseNATS(ctx context.Context, c *componentDiagnostic, cfg *config.Config) {
+	if !checkPort(ctx, c, s.config.NATSPort) {
+		return
+	}
+
+	nc, err := nats.Connect(cfg.NatsURL, nats.Timeout(diagnoseDialTimeout), nats.NoReconnect())
+	if err != nil {
+		c.fail("NATS connection", err.Error(), "Check NATS_URL and the nats service logs")
+		return
+	}
+	defer nc.Close()
+	c.pass("NATS connection", "connected to "+nc.ConnectedUrl())
+
+	rtt, err := nc.RTT()
+	if err != nil {
+		c.fail("Round-trip time", err.Error(), "Check the nats service logs")
+		return
+	}
+	c.Metrics["rtt_ms"] = float64(rtt.Microseconds()) / 1000
+
+	message := fmt.Sprintf("PING/PONG took %v", rtt.Round(time.Microsecond))
+	if rtt > natsRTTWarn {
+		c.warn("Round-trip time", message, "Check NATS server load and slow consumers")
+	} else {
+		c.pass("Round-trip time", message)
+	}
+}
+
+// diagnoseSeaweedFS checks port reachability and master cluster status
+func (s *Supervisor) diagnoseSeaweedFS(ctx context.Context, c *componentDiagnostic) {
+	if !checkPort(ctx, c, s.config.SeaweedFSPort) {
+		return
+	}
+
+	url := fmt.Sprintf("http://localhost:%d/cluster/status", s.config.SeaweedFSPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		c.fail("Master health", err.Error(), "")
+		return
+	}
+
+	httpClient := &http.Client{Timeout: diagnoseDialTimeout}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		c.fail("Master health", err.Error(), "Check the seaweedfs service logs")
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.fail("Master health", fmt.Sprintf("GET /cluster/status returned %s", resp.Status),
+			"Check the seaweedfs service logs")
+		return
+	}
+
+	var status struct {
+		IsLeader bool   `json:"IsLeader"`
+		Leader   string `json:"Leader"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err == nil && status.Leader != "" {
+		c.Metrics["leader"] = status.Leader
+		c.pass("Master health", "leader: "+status.Leader)
+		return
+	}
+
+	c.warn("Master health", "master responded without a leader", "Check that the SeaweedFS master has elected a leader")
+}
+
+// diagnoseProcess checks an internal component's process state
+func (s *Supervisor) diagnoseProcess(c *componentDiagnostic, name string) {
+	info, err := s.processManager.GetProcessStatus(name)
+	if err != nil {
+		c.fail("Process running", err.Error(), fmt.Sprintf("Start it with 'tmidb-cli start %s'", name))
+		return
+	}
+
+	c.Metrics["pid"] = info.PID
+	c.Metrics["restarts"] = info.Restarts
+	c.Metrics["memory"] = info.Memory
+	c.Metrics["cpu"] = info.CPU
+
+	if info.Status != "running" {
+		c.fail("Process running", "state: "+info.Status, fmt.Sprintf("Start it with 'tmidb-cli start %s'", name))
+		return
+	}
+	c.pass("Process running", fmt.Sprintf("pid %d, up %v", info.PID, info.Uptime.Round(time.Second)))
+
+	if info.Restarts > 0 {
+		c.warn("Restart count", fmt.Sprintf("restarted %d times", info.Restarts),
+			"Check the component logs for crash causes")
+	}
+}
+
+// diagnoseSystem checks disk space for the log directory
+func (s *Supervisor) diagnoseSystem(ctx context.Context, c *componentDiagnostic) {
+	disk, err := sysinfo.DiskUsage(s.config.LogDir)
+	if err != nil {
+		c.fail("Disk space", err.Error(), "")
+		return
+	}
+	if disk.Total == 0 {
+		c.warn("Disk space", "unable to determine filesystem size", "")
+		return
+	}
+
+	usage := disk.UsagePercent()
+	available := disk.Available
+	c.Metrics["disk_usage_percent"] = usage
+	c.Metrics["disk_available_bytes"] = available
+	c.Metrics["memory_usage_percent"] = s.getMemoryUsage()
+
+	message := fmt.Sprintf("%.1f%% used, %d MB available", usage, available/1024/1024)
+	switch {
+	case usage >= diskUsageFail:
+		c.fail("Disk space", message, "Free disk space or reduce log retention")
+	case usage >= diskUsageWarn:
+		c.warn("Disk space", message, "Free disk space or reduce log retention")
+	default:
+		c.pass("Disk space", message)
+	}
+}
+
+// diagnoseLogErrorRate checks the share of ERROR entries over the last hour
+func (s *Supervisor) diagnoseLogErrorRate(c *componentDiagnostic, component string) {
+	analysis, err := s.analyzeLogs([]string{component}, logErrorWindow)
+	if err != nil {
+		c.warn("Log error rate", err.Error(), "")
+		return
+	}
+
+	summary := analysis.Summary
+	c.Metrics["log_error_rate"] = summary.ErrorRate
+
+	message := fmt.Sprintf("%d errors in %d entries over the last %v (%.1f%%)",
+		summary.Errors, summary.Total, logErrorWindow, summary.ErrorRate)
+	solution := fmt.Sprintf("Run 'tmidb-cli diagnose logs' or inspect errors with 'tmidb-cli logs %s'", component)
+	switch {
+	case summary.Total < logErrorRateMin:
+		c.pass("Log error rate", message)
+	case summary.ErrorRate >= logErrorFail:
+		c.fail("Log error rate", message, solution)
+	case summary.ErrorRate >= logErrorWarn:
+		c.warn("Log error rate", message, solution)
+	default:
+		c.pass("Log error rate", message)
+	}
+}
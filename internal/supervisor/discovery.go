@@ -0,0 +1,177 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/discovery"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/version"
+)
+
+// discoveryServices are the endpoints registered for service discovery
+var discoveryServices = []string{"api", "nats", "postgresql"}
+
+// discoveryState registers the endpoints with Consul or etcd. The watchdog
+// hands it the process table after every successful check, so the TTLs lapse
+// when the supervisor is wedged or gone.
+type discoveryState struct {
+	registrar *discovery.Registrar
+	updates   chan []ipc.ProcessInfo
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// startDiscovery creates the registry client and the loop that talks to it
+func (s *Supervisor) startDiscovery() {
+	cfg := s.config.Discovery
+	if cfg == nil {
+		return
+	}
+	registry, err := discovery.New(*cfg)
+	if err != nil {
+		log.Printf("⚠️ Service discovery disabled: %v", err)
+		return
+	}
+
+	d := &s.discovery
+	d.registrar = discovery.NewRegistrar(registry, cfg.TTL)
+	d.updates = make(chan []ipc.ProcessInfo, 1)
+	ctx, cancel := context.WithCancel(s.ctx)
+	d.cancel = cancel
+	d.wg.Add(1)
+	go s.runDiscovery(ctx)
+	log.Printf("🧭 Registering endpoints with %s at %s", cfg.Type, cfg.Address)
+}
+
+// updateDiscovery passes the latest process table to the discovery loop
+// without blocking the watchdog; an update it has not picked up yet is replaced
+func (s *Supervisor) updateDiscovery(processes []ipc.ProcessInfo) {
+	d := &s.discovery
+	if d.updates == nil {
+		return
+	}
+	select {
+	case <-d.updates:
+	default:
+	}
+	d.updates <- processes
+}
+
+// runDiscovery reports every update to the registry until the supervisor stops
+func (s *Supervisor) runDiscovery(ctx context.Context) {
+	d := &s.discovery
+	defer d.wg.Done()
+
+	failing := false
+	for {
+		select {
+		case processes := <-d.updates:
+			syncCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			err := d.registrar.Sync(syncCtx, s.discoveryChecks(processes))
+			cancel()
+
+			// 저장소 장애는 복구될 때까지 한 번만 기록
+			if err != nil && !failing {
+				log.Printf("⚠️ Service discovery update failed: %v", err)
+			} else if err == nil && failing {
+				log.Printf("🧭 Service discovery updates recovered")
+			}
+			failing = err != nil
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// stopDiscovery deregisters the endpoints so clients stop using them at once
+// instead of waiting for the TTL
+func (s *Supervisor) stopDiscovery() {
+	d := &s.discovery
+	if d.registrar == nil {
+		return
+	}
+	// 해제한 뒤 밀려 있던 갱신이 다시 등록하지 않도록 루프부터 멈춤
+	d.cancel()
+	d.wg.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := d.registrar.Close(ctx); err != nil {
+		log.Printf("⚠️ Failed to deregister from service discovery: %v", err)
+	}
+}
+
+// discoveryChecks builds the endpoints and their health from the process table
+func (s *Supervisor) discoveryChecks(processes []ipc.ProcessInfo) []discovery.Check {
+	cfg := s.config.Discovery
+	address := cfg.ServiceAddress
+	if address == "" {
+		address = discovery.AdvertiseAddress()
+	}
+	node := s.nodeName()
+
+	services := cfg.Services
+	if len(services) == 0 {
+		services = discoveryServices
+	}
+	byName := make(map[string]ipc.ProcessInfo, len(processes))
+	for _, p := range processes {
+		byName[p.Name] = p
+	}
+
+	checks := make([]discovery.Check, 0, len(services))
+	for _, name := range services {
+		status, output := discoveryStatus(name, byName)
+		checks = append(checks, discovery.Check{
+			Service: discovery.Service{
+				ID:      fmt.Sprintf("tmidb-%s-%s", name, node),
+				Name:    "tmidb-" + name,
+				Address: address,
+				Port:    s.discoveryPort(name),
+				Tags:    append([]string{"tmidb"}, cfg.Tags...),
+				Meta:    map[string]string{"node": node, "version": version.Version},
+			},
+			Status: status,
+			Output: output,
+		})
+	}
+	return checks
+}
+
+// discoveryPort is the port clients connect to for a service
+func (s *Supervisor) discoveryPort(name string) int {
+	switch name {
+	case "nats":
+		return s.config.NATSPort
+	case "postgresql":
+		return s.config.PostgreSQLPort
+	case "seaweedfs":
+		return s.config.SeaweedFSPort
+	}
+	// api
+	port, err := strconv.Atoi(os.Getenv("API_PORT"))
+	if err != nil {
+		return 8020
+	}
+	return port
+}
+
+// discoveryStatus maps a component's state and last probe to a check status
+func discoveryStatus(name string, processes map[string]ipc.ProcessInfo) (discovery.Status, string) {
+	p, ok := processes[name]
+	switch {
+	case !ok:
+		return discovery.StatusCritical, name + " is not managed by the supervisor"
+	case p.Status != "running":
+		return discovery.StatusCritical, fmt.Sprintf("%s is %s", name, p.Status)
+	case p.Health != nil && !p.Health.Healthy:
+		return discovery.StatusCritical, fmt.Sprintf("%s health check failed: %s", name, p.Health.Message)
+	}
+	return discovery.StatusPassing, name + " is running"
+}
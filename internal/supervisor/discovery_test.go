@@ -0,0 +1,98 @@
+package supervisor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/discovery"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+func TestDiscoveryChecks(t *testing.T) {
+	t.Setenv("API_PORT", "9020")
+	s := &Supervisor{config: &Config{
+		ClusterNode:    "node1",
+		NATSPort:       4222,
+		PostgreSQLPort: 5432,
+		Discovery:      &discovery.Config{Type: "consul", Address: "http://127.0.0.1:8500", ServiceAddress: "10.0.0.5", Tags: []string{"prod"}},
+	}}
+
+	checks := s.discoveryChecks([]ipc.ProcessInfo{
+		{Name: "api", Status: "running", Health: &ipc.ProbeResult{Healthy: true}},
+		{Name: "nats", Status: "running", Health: &ipc.ProbeResult{Healthy: false, Message: "connection refused"}},
+	})
+	if len(checks) != 3 {
+		t.Fatalf("checks = %+v", checks)
+	}
+
+	want := []struct {
+		id     string
+		port   int
+		status discovery.Status
+	}{
+		{"tmidb-api-node1", 9020, discovery.StatusPassing},
+		{"tmidb-nats-node1", 4222, discovery.StatusCritical},
+		{"tmidb-postgresql-node1", 5432, discovery.StatusCritical},
+	}
+	for i, w := range want {
+		c := checks[i]
+		if c.Service.ID != w.id || c.Service.Port != w.port || c.Status != w.status || c.Service.Address != "10.0.0.5" {
+			t.Errorf("check %d = %+v, want %s:%d %s", i, c, w.id, w.port, w.status)
+		}
+	}
+	if !strings.Contains(checks[1].Output, "connection refused") {
+		t.Errorf("nats output = %q", checks[1].Output)
+	}
+	if tags := strings.Join(checks[0].Service.Tags, ","); tags != "tmidb,prod" {
+		t.Errorf("tags = %s", tags)
+	}
+}
+
+func TestDiscoveryLoop(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.URL.Path)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &Supervisor{ctx: ctx, config: &Config{
+		ClusterNode: "node1",
+		Discovery:   &discovery.Config{Type: "consul", Address: srv.URL, ServiceAddress: "10.0.0.5", Services: []string{"api"}},
+	}}
+
+	// 시작 전 갱신은 무시
+	s.updateDiscovery(nil)
+
+	s.startDiscovery()
+	s.updateDiscovery([]ipc.ProcessInfo{{Name: "api", Status: "running"}})
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		mu.Lock()
+		n := len(calls)
+		mu.Unlock()
+		if n >= 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for registration")
+		}
+	}
+	s.stopDiscovery()
+
+	mu.Lock()
+	defer mu.Unlock()
+	got := strings.Join(calls, ",")
+	want := "/v1/agent/service/register,/v1/agent/check/update/service:tmidb-api-node1,/v1/agent/service/deregister/tmidb-api-node1"
+	if got != want {
+		t.Errorf("calls = %s\nwant    %s", got, want)
+	}
+}
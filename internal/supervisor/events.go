@@ -0,0 +1,198 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// eventSubjectPrefix is prepended to the event type to form the NATS subject
+const eventSubjectPrefix = "tmidb.events."
+
+// eventHistorySize is how many recent events are kept for crash dumps
+const eventHistorySize = 200
+
+// eventBus fans supervisor events out to IPC subscribers and, when configured, NATS.
+// Publishing never blocks: slow subscribers lose events instead of stalling the supervisor.
+type eventBus struct {
+	mu          sync.Mutex
+	seq         uint64
+	subscribers []*ipc.EventStream
+	history     []ipc.Event // 최근 이벤트 (오래된 순)
+
+	nc      *nats.Conn
+	natsURL string
+}
+
+// subscribe adds a stream; it is dropped automatically once closed
+func (b *eventBus) subscribe(stream *ipc.EventStream) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, stream)
+}
+
+// publish delivers an event to every matching subscriber and to NATS
+func (b *eventBus) publish(event ipc.Event) {
+	b.mu.Lock()
+	b.seq++
+	event.ID = fmt.Sprintf("evt-%d-%d", time.Now().Unix(), b.seq)
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	if len(b.history) == eventHistorySize {
+		b.history = append(b.history[:0], b.history[1:]...)
+	}
+	b.history = append(b.history, event)
+
+	active := b.subscribers[:0]
+	for _, stream := range b.subscribers {
+		select {
+		case <-stream.Done():
+			continue
+		default:
+		}
+		active = append(active, stream)
+
+		if stream.Matches(event.Type) {
+			stream.TrySend(event)
+		}
+	}
+	clear(b.subscribers[len(active):])
+	b.subscribers = active
+	nc := b.nc
+	b.mu.Unlock()
+
+	if nc == nil {
+		return
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	// 연결이 끊긴 동안에는 클라이언트 재연결 버퍼에 쌓인다
+	if err := nc.Publish(eventSubjectPrefix+string(event.Type), data); err != nil {
+		log.Printf("⚠️ Failed to publish event %s to NATS: %v", event.Type, err)
+	}
+}
+
+// recent returns up to n of the latest events, oldest first
+func (b *eventBus) recent(n int) []ipc.Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if n > len(b.history) {
+		n = len(b.history)
+	}
+	return append([]ipc.Event(nil), b.history[len(b.history)-n:]...)
+}
+
+// setNATS (re)connects the NATS publisher; an empty URL disables it
+func (b *eventBus) setNATS(url string) error {
+	b.mu.Lock()
+	if url == b.natsURL && (url == "" || b.nc != nil) {
+		b.mu.Unlock()
+		return nil
+	}
+	old := b.nc
+	b.nc = nil
+	b.natsURL = url
+	b.mu.Unlock()
+
+	if old != nil {
+		old.Close()
+	}
+	if url == "" {
+		return nil
+	}
+
+	// NATS가 아직 준비되지 않았어도 백그라운드에서 계속 재연결
+	nc, err := nats.Connect(url,
+		nats.Name("tmidb-supervisor-events"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to connect event publisher to %s: %w", url, err)
+	}
+
+	b.mu.Lock()
+	b.nc = nc
+	b.mu.Unlock()
+
+	log.Printf("📣 Publishing supervisor events to NATS (%s*)", eventSubjectPrefix)
+	return nil
+}
+
+// close stops publishing to NATS and ends all subscriptions
+func (b *eventBus) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.nc != nil {
+		b.nc.Close()
+		b.nc = nil
+	}
+	for _, stream := range b.subscribers {
+		stream.Close()
+	}
+	b.subscribers = nil
+}
+
+// emitEvent publishes a supervisor event
+func (s *Supervisor) emitEvent(eventType ipc.EventType, component, message string, data map[string]interface{}) {
+	s.publishEvent(ipc.Event{
+		Type:      eventType,
+		Component: component,
+		Message:   message,
+		Data:      data,
+	})
+}
+
+// emitBackupEvent reports the outcome of a finished backup
+func (s *Supervisor) emitBackupEvent(backup *BackupInfo, progress *BackupProgress) {
+	data := map[string]interface{}{
+		"id":         backup.ID,
+		"path":       backup.Path,
+		"components": backup.Components,
+	}
+
+	if backup.Status == "completed" {
+		data["size"] = backup.Size
+		data["checksum"] = backup.Checksum
+		s.emitEvent(ipc.EventBackupCompleted, "backup", fmt.Sprintf("backup %s completed", backup.Name), data)
+		return
+	}
+	s.emitEvent(ipc.EventBackupFailed, "backup", progress.Error, data)
+}
+
+// handleEventSubscribe keeps the connection open and streams matching events
+func (s *Supervisor) handleEventSubscribe(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	var types []ipc.EventType
+	if list, ok := msg.Data["types"].([]interface{}); ok {
+		for _, t := range list {
+			if name, ok := t.(string); ok && name != "" {
+				types = append(types, ipc.EventType(name))
+			}
+		}
+	}
+
+	bufferSize := 0
+	if b, ok := msg.Data["buffer"].(float64); ok && b > 0 {
+		bufferSize = int(b)
+	}
+
+	// IPC 서버가 응답 전송 후 이 연결에서 스트림을 전송한다
+	stream := s.ipcServer.CreateEventStream(conn.ID, types, bufferSize)
+	s.events.subscribe(stream)
+
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
+		"status": "subscribed",
+		"types":  types,
+	}, "")
+}
@@ -0,0 +1,36 @@
+package supervisor
+
+import (
+	"testing"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+func TestEventBusFanOut(t *testing.T) {
+	server := ipc.NewServer(t.TempDir() + "/test.sock")
+
+	var bus eventBus
+	all := server.CreateEventStream("all", nil, 1)
+	backups := server.CreateEventStream("backups", []ipc.EventType{"backup.*"}, 1)
+	bus.subscribe(all)
+	bus.subscribe(backups)
+
+	bus.publish(ipc.Event{Type: ipc.EventProcessStarted, Component: "api"})
+	bus.publish(ipc.Event{Type: ipc.EventBackupCompleted})
+	bus.publish(ipc.Event{Type: ipc.EventBackupFailed})
+
+	// 버퍼 크기 1: 첫 이벤트 이후는 버려짐
+	if got := all.Dropped(); got != 2 {
+		t.Errorf("all: dropped = %d, want 2", got)
+	}
+	// 필터에 맞지 않는 process 이벤트는 전달되지 않음
+	if got := backups.Dropped(); got != 1 {
+		t.Errorf("backups: dropped = %d, want 1", got)
+	}
+
+	all.Close()
+	bus.publish(ipc.Event{Type: ipc.EventConfigChanged})
+	if len(bus.subscribers) != 1 || bus.subscribers[0] != backups {
+		t.Errorf("closed subscriber was not removed: %d left", len(bus.subscribers))
+	}
+}
@@ -0,0 +1,392 @@
+package supervisor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ha"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/process"
+)
+
+// Keys the active supervisor replicates its catalogs under
+const (
+	haKeyProcesses = "processes"
+	haKeyBackups   = "backups"
+)
+
+// ErrLeadershipLost is returned by Run when this supervisor was active and
+// another one took the lease. Its components are stopped by then; the init
+// system restarts it and it comes back as the standby.
+var ErrLeadershipLost = errors.New("lost the high-availability lease to another supervisor")
+
+// haState runs leader election between an active and a standby supervisor.
+// The standby serves IPC and replicates the active's catalogs, but attaches to
+// no external service and starts no component until it holds the lease.
+type haState struct {
+	mu        sync.Mutex
+	backend   ha.Backend
+	elector   *ha.Elector
+	activated bool               // took over at least once
+	ready     bool               // startServices returned, so the process table is worth replicating
+	replica   *ha.ProcessReplica // process table of the active, as last replicated
+	backups   string             // hash of the backup catalog last published or applied
+
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	replCancel context.CancelFunc
+	replWG     sync.WaitGroup
+
+	demoted     chan struct{} // closed when this supervisor was active and lost the lease
+	demotedOnce sync.Once
+}
+
+// startHA starts campaigning for the lease in the background
+func (s *Supervisor) startHA() {
+	h := &s.ha
+	h.demoted = make(chan struct{})
+	ctx, cancel := context.WithCancel(s.ctx)
+	replCtx, replCancel := context.WithCancel(ctx)
+	h.cancel, h.replCancel = cancel, replCancel
+
+	h.wg.Add(1)
+	go s.runHA(ctx, replCtx)
+	log.Printf("⏳ Waiting for the %s leadership lease before attaching to external services", s.config.HA.Backend)
+}
+
+// runHA connects to the backend, retrying until it is reachable, then runs the
+// election and the replication loop until the supervisor stops
+func (s *Supervisor) runHA(ctx, replCtx context.Context) {
+	h := &s.ha
+	defer h.wg.Done()
+
+	cfg := *s.config.HA
+	ttl := cfg.TTL()
+	node := s.nodeName()
+
+	var backend ha.Backend
+	for failing := false; ; failing = true {
+		openCtx, cancel := context.WithTimeout(ctx, ttl)
+		b, err := ha.Open(openCtx, cfg, node)
+		cancel()
+		if err == nil {
+			backend = b
+			break
+		}
+		if !failing {
+			log.Printf("⚠️ HA backend unavailable, retrying: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(ttl / 3):
+		}
+	}
+	defer backend.Close()
+
+	elector := ha.NewElector(backend, node, ttl, s.onRoleChange)
+	h.mu.Lock()
+	h.backend, h.elector = backend, elector
+	// 종료가 이미 시작되었으면 복제를 띄우지 않음 (pauseReplication과 경합 방지)
+	if replCtx.Err() == nil {
+		h.replWG.Add(1)
+		go s.runReplication(replCtx, backend, ttl/3)
+	}
+	h.mu.Unlock()
+
+	elector.Run(ctx)
+	h.replWG.Wait()
+}
+
+// onRoleChange is called from the election loop, so slow work runs in goroutines
+func (s *Supervisor) onRoleChange(role ha.Role) {
+	h := &s.ha
+	node := s.nodeName()
+
+	h.mu.Lock()
+	activated := h.activated
+	if role == ha.RoleActive {
+		h.activated = true
+	}
+	h.mu.Unlock()
+
+	if role == ha.RoleActive {
+		go s.publishEvent(ipc.Event{Type: ipc.EventHAPromoted, Component: "supervisor", Message: node + " is the active supervisor"})
+		if !activated {
+			go s.activate()
+		}
+		return
+	}
+	if !activated {
+		return
+	}
+
+	// 다른 노드가 이미 컴포넌트를 시작했을 수 있으므로 이 노드는 종료하고 standby로 다시 시작
+	log.Printf("🚨 %s is no longer the active supervisor, shutting down", node)
+	go s.publishEvent(ipc.Event{Type: ipc.EventHADemoted, Component: "supervisor", Message: node + " lost the leadership lease"})
+	h.demotedOnce.Do(func() { close(h.demoted) })
+}
+
+// activate takes over as the active supervisor: it reads the latest replica,
+// marks backups the previous active left unfinished as failed, then attaches to
+// the external services and starts the components like a standalone supervisor
+func (s *Supervisor) activate() {
+	h := &s.ha
+	log.Printf("👑 Taking over as the active supervisor")
+
+	h.mu.Lock()
+	backend := h.backend
+	h.mu.Unlock()
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	if err := s.fetchReplica(ctx, backend); err != nil {
+		log.Printf("⚠️ Taking over without the latest replica: %v", err)
+	}
+	cancel()
+
+	s.backupCatalogMutex.Lock()
+	unfinished := false
+	for _, backup := range s.backups {
+		if backup.Status == "creating" {
+			backup.Status = "failed"
+			unfinished = true
+		}
+	}
+	s.backupCatalogMutex.Unlock()
+	if unfinished {
+		s.saveBackupCatalog()
+	}
+
+	if err := s.startServices(); err != nil {
+		log.Printf("❌ Failed to take over as the active supervisor: %v", err)
+	}
+
+	h.mu.Lock()
+	h.ready = true
+	h.mu.Unlock()
+}
+
+// runReplication publishes the catalogs while active and copies them while standby
+func (s *Supervisor) runReplication(ctx context.Context, backend ha.Backend, interval time.Duration) {
+	defer s.ha.replWG.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	failing := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := s.replicate(ctx, backend)
+		// 저장소 장애는 복구될 때까지 한 번만 기록
+		if err != nil && !failing {
+			log.Printf("⚠️ HA replication failed: %v", err)
+		} else if err == nil && failing {
+			log.Printf("🔁 HA replication recovered")
+		}
+		failing = err != nil
+	}
+}
+
+// replicate runs one replication round for the current role
+func (s *Supervisor) replicate(ctx context.Context, backend ha.Backend) error {
+	h := &s.ha
+	h.mu.Lock()
+	elector, ready := h.elector, h.ready
+	h.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if elector.Status().Role == ha.RoleActive {
+		if !ready {
+			return nil
+		}
+		return s.publishReplica(ctx, backend)
+	}
+	return s.fetchReplica(ctx, backend)
+}
+
+// publishReplica writes the process table every round and the backup catalog
+// when it changed
+func (s *Supervisor) publishReplica(ctx context.Context, backend ha.Backend) error {
+	h := &s.ha
+	processes, err := json.Marshal(ha.ProcessReplica{
+		Node:      s.nodeName(),
+		UpdatedAt: time.Now(),
+		Processes: s.processManager.GetProcessList(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := backend.Put(ctx, haKeyProcesses, processes); err != nil {
+		return fmt.Errorf("failed to replicate processes: %w", err)
+	}
+
+	s.backupCatalogMutex.Lock()
+	catalog, err := json.Marshal(backupCatalog{Version: 1, Backups: s.sortedBackups()})
+	s.backupCatalogMutex.Unlock()
+	if err != nil {
+		return err
+	}
+	hash := catalogHash(catalog)
+	h.mu.Lock()
+	unchanged := hash == h.backups
+	h.mu.Unlock()
+	if unchanged {
+		return nil
+	}
+	if err := backend.Put(ctx, haKeyBackups, catalog); err != nil {
+		return fmt.Errorf("failed to replicate backup catalog: %w", err)
+	}
+	h.mu.Lock()
+	h.backups = hash
+	h.mu.Unlock()
+	return nil
+}
+
+// fetchReplica copies the active's process table and, when it changed, its
+// backup catalog, which replaces the local one
+func (s *Supervisor) fetchReplica(ctx context.Context, backend ha.Backend) error {
+	h := &s.ha
+	data, err := backend.Get(ctx, haKeyProcesses)
+	if err != nil {
+		return fmt.Errorf("failed to read replicated processes: %w", err)
+	}
+	if data != nil {
+		var replica ha.ProcessReplica
+		if err := json.Unmarshal(data, &replica); err != nil {
+			return fmt.Errorf("invalid replicated processes: %w", err)
+		}
+		h.mu.Lock()
+		h.replica = &replica
+		h.mu.Unlock()
+	}
+
+	data, err = backend.Get(ctx, haKeyBackups)
+	if err != nil {
+		return fmt.Errorf("failed to read replicated backup catalog: %w", err)
+	}
+	if data == nil {
+		return nil
+	}
+	hash := catalogHash(data)
+	h.mu.Lock()
+	unchanged := hash == h.backups
+	h.mu.Unlock()
+	if unchanged {
+		return nil
+	}
+	if err := s.applyBackupReplica(data); err != nil {
+		return err
+	}
+	h.mu.Lock()
+	h.backups = hash
+	h.mu.Unlock()
+	return nil
+}
+
+// applyBackupReplica replaces the known backups with the active's catalog and saves it
+func (s *Supervisor) applyBackupReplica(data []byte) error {
+	var catalog backupCatalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return fmt.Errorf("invalid replicated backup catalog: %w", err)
+	}
+
+	s.backupCatalogMutex.Lock()
+	for id := range s.backups {
+		delete(s.backups, id)
+	}
+	for _, backup := range catalog.Backups {
+		if backup != nil && backup.ID != "" {
+			s.backups[backup.ID] = backup
+		}
+	}
+	s.backupCatalogMutex.Unlock()
+
+	s.saveBackupCatalog()
+	return nil
+}
+
+func catalogHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// keepStopped reports whether a component was stopped on purpose on the
+// previous active supervisor, so taking over does not start it again
+func (s *Supervisor) keepStopped(name string) bool {
+	h := &s.ha
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.replica == nil {
+		return false
+	}
+	for _, p := range h.replica.Processes {
+		if p.Name == name {
+			return p.Status == string(process.StateStopped)
+		}
+	}
+	return false
+}
+
+// pauseReplication stops publishing the process table before the components
+// are stopped, so the standby does not mistake the shutdown for stops on purpose
+func (s *Supervisor) pauseReplication() {
+	h := &s.ha
+	h.mu.Lock()
+	cancel := h.replCancel
+	h.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	h.replWG.Wait()
+}
+
+// stopHA releases the lease once the components are stopped, so the standby
+// takes over without waiting for the lease to expire
+func (s *Supervisor) stopHA() {
+	h := &s.ha
+	if h.cancel == nil {
+		return
+	}
+	h.cancel()
+	h.wg.Wait()
+}
+
+// handleHAStatus returns this node's role, the leader and, on the standby, the
+// process table replicated from the active
+func (s *Supervisor) handleHAStatus(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	cfg := s.config.HA
+	if cfg == nil {
+		return ipc.NewResponse(msg.ID, false, nil, "high availability is not configured")
+	}
+
+	report := ha.Report{Backend: cfg.Backend, Group: cfg.GroupName(), LeaseTTL: cfg.TTL().String()}
+	h := &s.ha
+	h.mu.Lock()
+	elector, replica := h.elector, h.replica
+	h.mu.Unlock()
+
+	if elector != nil {
+		report.Status = elector.Status()
+	} else {
+		report.Status = ha.Status{Node: s.nodeName(), Role: ha.RoleStandby, Error: "connecting to the ha backend"}
+	}
+	if report.Role == ha.RoleStandby {
+		report.Replica = replica
+	}
+	return ipc.NewResponse(msg.ID, true, report, "")
+}
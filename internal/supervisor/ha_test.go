@@ -0,0 +1,105 @@
+package supervisor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ha"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/process"
+)
+
+// memBackend keeps replicated state in memory; election is not used here
+type memBackend struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	puts map[string]int
+}
+
+func newMemBackend() *memBackend {
+	return &memBackend{data: make(map[string][]byte), puts: make(map[string]int)}
+}
+
+func (b *memBackend) Acquire(ctx context.Context) (bool, error)  { return true, nil }
+func (b *memBackend) Release(ctx context.Context) error          { return nil }
+func (b *memBackend) Leader(ctx context.Context) (string, error) { return "", nil }
+func (b *memBackend) Close() error                               { return nil }
+
+func (b *memBackend) Put(ctx context.Context, key string, data []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data[key] = data
+	b.puts[key]++
+	return nil
+}
+
+func (b *memBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.data[key], nil
+}
+
+func TestHAReplication(t *testing.T) {
+	dir := t.TempDir()
+	backend := newMemBackend()
+	ctx := context.Background()
+
+	active := &Supervisor{
+		config:         &Config{ClusterNode: "node-a", BackupCatalog: filepath.Join(dir, "a.json")},
+		processManager: process.NewManager(nil, nil),
+		backups: map[string]*BackupInfo{
+			"b1": {ID: "b1", Status: "completed", Created: time.Now().Add(-time.Hour)},
+			"b2": {ID: "b2", Status: "creating", Created: time.Now()},
+		},
+	}
+	if err := active.processManager.RegisterProcess(&process.ProcessConfig{Name: "data-consumer", Type: process.TypeInternal, Command: "true"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := active.publishReplica(ctx, backend); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if backend.puts[haKeyProcesses] != 2 || backend.puts[haKeyBackups] != 1 {
+		t.Errorf("puts = %v, want the unchanged backup catalog written once", backend.puts)
+	}
+
+	standby := &Supervisor{
+		config:  &Config{ClusterNode: "node-b", BackupCatalog: filepath.Join(dir, "b.json"), HA: &ha.Config{Backend: "nats", URL: "nats://nats-ha:4222"}},
+		backups: map[string]*BackupInfo{"stale": {ID: "stale", Status: "completed"}},
+	}
+	if err := standby.fetchReplica(ctx, backend); err != nil {
+		t.Fatal(err)
+	}
+
+	// 백업 카탈로그는 active의 것으로 바뀌고 파일에도 기록됨
+	if len(standby.backups) != 2 || standby.backups["b1"] == nil || standby.backups["stale"] != nil {
+		t.Errorf("standby backups = %v", standby.backups)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b.json")); err != nil {
+		t.Errorf("backup catalog not saved: %v", err)
+	}
+
+	// active에서 멈춰 있던 컴포넌트는 이어받아도 시작하지 않음
+	if !standby.keepStopped("data-consumer") || standby.keepStopped("api") {
+		t.Errorf("keepStopped: data-consumer %t, api %t", standby.keepStopped("data-consumer"), standby.keepStopped("api"))
+	}
+
+	resp := standby.handleHAStatus(nil, &ipc.Message{ID: "1", Type: ipc.MessageTypeHAStatus})
+	report, ok := resp.Data.(ha.Report)
+	if !resp.Success || !ok {
+		t.Fatalf("response = %+v", resp)
+	}
+	if report.Role != ha.RoleStandby || report.Node != "node-b" || report.Replica == nil || report.Replica.Node != "node-a" {
+		t.Errorf("report = %+v", report)
+	}
+
+	if resp := active.handleHAStatus(nil, &ipc.Message{ID: "2"}); resp.Success {
+		t.Errorf("ha_status without ha config succeeded: %+v", resp)
+	}
+}
@@ -0,0 +1,81 @@
+package supervisor
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/process"
+)
+
+// healthCheckFor returns the probe for a component: the configured one if any,
+// otherwise a default for components with a well-known endpoint
+func (s *Supervisor) healthCheckFor(name string) *process.HealthCheck {
+	if hc, ok := s.config.HealthChecks[name]; ok {
+		return &hc
+	}
+
+	switch name {
+	case "api":
+		apiPort := os.Getenv("API_PORT")
+		if apiPort == "" {
+			apiPort = "8020"
+		}
+		return &process.HealthCheck{
+			Type:   process.ProbeHTTP,
+			URL:    fmt.Sprintf("http://localhost:%s/api/health", apiPort),
+			Action: process.ActionRestart,
+		}
+	case "postgresql":
+		return tcpHealthCheck(s.config.PostgreSQLPort)
+	case "nats":
+		return tcpHealthCheck(s.config.NATSPort)
+	case "seaweedfs":
+		return tcpHealthCheck(s.config.SeaweedFSPort)
+	}
+
+	return nil
+}
+
+// tcpHealthCheck 외부 서비스는 재시작 대신 알림
+func tcpHealthCheck(port int) *process.HealthCheck {
+	return &process.HealthCheck{
+		Type:    process.ProbeTCP,
+		Address: fmt.Sprintf("localhost:%d", port),
+		Action:  process.ActionAlert,
+	}
+}
+
+// handleHealthAlert is called when a probe with the alert action crosses its threshold
+func (s *Supervisor) handleHealthAlert(name string, result ipc.ProbeResult) {
+	log.Printf("🚨 Health alert: %s is unhealthy after %d failed %s probes: %s",
+		name, result.Failures, result.Type, result.Message)
+}
+
+// aggregateProbeHealth folds per-process probe results into the system health
+func aggregateProbeHealth(health *ipc.SystemHealth, processes []ipc.ProcessInfo) {
+	for _, proc := range processes {
+		if proc.Health == nil {
+			continue
+		}
+
+		state := "healthy"
+		switch {
+		case !proc.Health.Healthy:
+			state = "unhealthy"
+		case proc.Health.Degraded:
+			state = "degraded"
+		}
+
+		// 포트 체크에서 이미 unhealthy인 경우 유지
+		if health.Components[proc.Name] != "unhealthy" {
+			health.Components[proc.Name] = state
+		}
+
+		if state != "healthy" {
+			health.Status = "degraded"
+			health.Errors = append(health.Errors, fmt.Sprintf("%s: %s", proc.Name, proc.Health.Message))
+		}
+	}
+}
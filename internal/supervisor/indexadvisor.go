@@ -0,0 +1,88 @@
+package supervisor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// defaultIndexMinCalls is how often a filter must have been used before an
+// index is suggested for it
+const defaultIndexMinCalls = 100
+
+// indexMinCalls reads min_calls from the request
+func indexMinCalls(data map[string]interface{}) int64 {
+	if n, ok := data["min_calls"].(float64); ok && n >= 0 {
+		return int64(n)
+	}
+	return defaultIndexMinCalls
+}
+
+// handleDBIndexAdvise suggests JSONB indexes from the recorded filters and
+// slow queries, with their estimated benefit
+func (s *Supervisor) handleDBIndexAdvise(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	advice, err := database.AdviseIndexes(db, indexMinCalls(msg.Data))
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, advice, "")
+}
+
+// handleDBIndexApply creates suggested indexes, named in names or all of them.
+// The suggestions are computed again here so only advised statements are run.
+func (s *Supervisor) handleDBIndexApply(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	all, _ := msg.Data["all"].(bool)
+	names := map[string]bool{}
+	if list, ok := msg.Data["names"].([]interface{}); ok {
+		for _, item := range list {
+			if name, ok := item.(string); ok && name != "" {
+				names[name] = true
+			}
+		}
+	}
+	if !all && len(names) == 0 {
+		return ipc.NewResponse(msg.ID, false, nil, "index names or all required")
+	}
+
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	advice, err := database.AdviseIndexes(db, indexMinCalls(msg.Data))
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	var selected []database.IndexSuggestion
+	for _, suggestion := range advice.Suggestions {
+		if all || names[suggestion.Name] {
+			selected = append(selected, suggestion)
+			delete(names, suggestion.Name)
+		}
+	}
+	for name := range names {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("%s is not a current suggestion (run db indexes advise)", name))
+	}
+
+	results := make([]map[string]interface{}, 0, len(selected))
+	for _, suggestion := range selected {
+		// 한 번에 하나씩 만듦 (인덱스 생성은 디스크와 CPU를 많이 씀)
+		start := time.Now()
+		result := map[string]interface{}{"name": suggestion.Name, "table": suggestion.Table, "sql": suggestion.SQL}
+		if err := database.CreateAdvisedIndex(db, suggestion); err != nil {
+			result["error"] = err.Error()
+		}
+		result["seconds"] = time.Since(start).Seconds()
+		results = append(results, result)
+	}
+	return ipc.NewResponse(msg.ID, true, results, "")
+}
@@ -0,0 +1,501 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/tmidb/tmidb-core/internal/config"
+	"github.com/tmidb/tmidb-core/internal/dataconsumer"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// JetStream provisioning settings
+const (
+	eventsStream = "TMIDB_EVENTS"
+
+	streamReconcileRetry = 10 * time.Second
+	streamRequestTimeout = 10 * time.Second
+
+	// A consumer with more undelivered messages than this is reported as lagging,
+	// and one that has pending messages but no delivery for consumerStallAfter as stalled
+	consumerLagWarn    = 10000
+	consumerStallAfter = 5 * time.Minute
+)
+
+// StreamSpec declares a JetStream stream and the durable consumers on it
+type StreamSpec struct {
+	Name      string         `json:"name"`
+	Subjects  []string       `json:"subjects"`
+	Storage   string         `json:"storage,omitempty"` // file (default) or memory
+	Replicas  int            `json:"replicas,omitempty"`
+	MaxAge    time.Duration  `json:"max_age"`
+	MaxBytes  int64          `json:"max_bytes,omitempty"`
+	Consumers []ConsumerSpec `json:"consumers,omitempty"`
+}
+
+// ConsumerSpec declares a durable pull consumer
+type ConsumerSpec struct {
+	Durable       string        `json:"durable"`
+	FilterSubject string        `json:"filter_subject,omitempty"`
+	AckWait       time.Duration `json:"ack_wait"`
+	MaxDeliver    int           `json:"max_deliver,omitempty"`
+}
+
+// streamSpecJSON max_age를 "168h" 형식 문자열로 직렬화
+type streamSpecJSON struct {
+	*streamSpecAlias
+	MaxAge string `json:"max_age,omitempty"`
+}
+
+type streamSpecAlias StreamSpec
+
+// MarshalJSON encodes durations as strings
+func (s StreamSpec) MarshalJSON() ([]byte, error) {
+	aux := streamSpecJSON{streamSpecAlias: (*streamSpecAlias)(&s)}
+	if s.MaxAge > 0 {
+		aux.MaxAge = s.MaxAge.String()
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON decodes durations from strings
+func (s *StreamSpec) UnmarshalJSON(data []byte) error {
+	aux := streamSpecJSON{streamSpecAlias: (*streamSpecAlias)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.MaxAge != "" {
+		maxAge, err := time.ParseDuration(aux.MaxAge)
+		if err != nil {
+			return fmt.Errorf("invalid max_age for stream %s: %w", s.Name, err)
+		}
+		s.MaxAge = maxAge
+	}
+	return nil
+}
+
+// consumerSpecJSON ack_wait을 "30s" 형식 문자열로 직렬화
+type consumerSpecJSON struct {
+	*consumerSpecAlias
+	AckWait string `json:"ack_wait,omitempty"`
+}
+
+type consumerSpecAlias ConsumerSpec
+
+// MarshalJSON encodes durations as strings
+func (c ConsumerSpec) MarshalJSON() ([]byte, error) {
+	aux := consumerSpecJSON{consumerSpecAlias: (*consumerSpecAlias)(&c)}
+	if c.AckWait > 0 {
+		aux.AckWait = c.AckWait.String()
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON decodes durations from strings
+func (c *ConsumerSpec) UnmarshalJSON(data []byte) error {
+	aux := consumerSpecJSON{consumerSpecAlias: (*consumerSpecAlias)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.AckWait != "" {
+		ackWait, err := time.ParseDuration(aux.AckWait)
+		if err != nil {
+			return fmt.Errorf("invalid ack_wait for consumer %s: %w", c.Durable, err)
+		}
+		c.AckWait = ackWait
+	}
+	return nil
+}
+
+// defaultStreams are provisioned when nats_streams is not set: the ingest
+// pipeline, its dead-letter stream and the supervisor event history.
+// The data-consumer adds its per-category ingest_<category> consumers itself.
+func defaultStreams() []StreamSpec {
+	return []StreamSpec{
+		{
+			Name:     dataconsumer.IngestStream,
+			Subjects: []string{dataconsumer.IngestSubjectPrefix + ".>"},
+			MaxAge:   7 * 24 * time.Hour,
+		},
+		{
+			Name:     dataconsumer.DeadLetterStream,
+			Subjects: []string{dataconsumer.DeadLetterSubjectPrefix + ".>"},
+			MaxAge:   30 * 24 * time.Hour,
+		},
+		{
+			Name:     eventsStream,
+			Subjects: []string{eventSubjectPrefix + ">"},
+			MaxAge:   7 * 24 * time.Hour,
+		},
+	}
+}
+
+// streamSpecs returns the configured streams, or the defaults when none are configured
+func (s *Supervisor) streamSpecs() []StreamSpec {
+	if s.config.NATSStreams != nil {
+		return s.config.NATSStreams
+	}
+	return defaultStreams()
+}
+
+// validateStreamSpecs checks names, subjects and storage before anything is sent to NATS
+func validateStreamSpecs(specs []StreamSpec) error {
+	names := make(map[string]bool)
+	for _, spec := range specs {
+		if spec.Name == "" {
+			return errors.New("nats_streams: stream name is required")
+		}
+		if names[spec.Name] {
+			return fmt.Errorf("nats_streams: duplicate stream %s", spec.Name)
+		}
+		names[spec.Name] = true
+
+		if len(spec.Subjects) == 0 {
+			return fmt.Errorf("nats_streams: stream %s has no subjects", spec.Name)
+		}
+		if _, err := streamStorage(spec.Storage); err != nil {
+			return fmt.Errorf("nats_streams: stream %s: %w", spec.Name, err)
+		}
+
+		durables := make(map[string]bool)
+		for _, consumer := range spec.Consumers {
+			if consumer.Durable == "" {
+				return fmt.Errorf("nats_streams: stream %s has a consumer without a durable name", spec.Name)
+			}
+			if durables[consumer.Durable] {
+				return fmt.Errorf("nats_streams: duplicate consumer %s on stream %s", consumer.Durable, spec.Name)
+			}
+			durables[consumer.Durable] = true
+		}
+	}
+	return nil
+}
+
+// streamStorage maps the configured storage name to the JetStream storage type
+func streamStorage(storage string) (jetstream.StorageType, error) {
+	switch storage {
+	case "", "file":
+		return jetstream.FileStorage, nil
+	case "memory":
+		return jetstream.MemoryStorage, nil
+	}
+	return 0, fmt.Errorf("unknown storage %q (expected file or memory)", storage)
+}
+
+// streamConfig converts a spec to the JetStream stream configuration
+func (spec StreamSpec) streamConfig() (jetstream.StreamConfig, error) {
+	storage, err := streamStorage(spec.Storage)
+	if err != nil {
+		return jetstream.StreamConfig{}, err
+	}
+	cfg := jetstream.StreamConfig{
+		Name:     spec.Name,
+		Subjects: spec.Subjects,
+		Storage:  storage,
+		Replicas: spec.Replicas,
+		MaxAge:   spec.MaxAge,
+		MaxBytes: spec.MaxBytes,
+	}
+	if cfg.MaxBytes == 0 {
+		cfg.MaxBytes = -1
+	}
+	return cfg, nil
+}
+
+// consumerConfig converts a spec to the JetStream consumer configuration
+func (spec ConsumerSpec) consumerConfig() jetstream.ConsumerConfig {
+	return jetstream.ConsumerConfig{
+		Durable:       spec.Durable,
+		FilterSubject: spec.FilterSubject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       spec.AckWait,
+		MaxDeliver:    spec.MaxDeliver,
+	}
+}
+
+// streamProvisioner remembers the outcome of the last reconciliation for `nats streams`
+type streamProvisioner struct {
+	mu         sync.Mutex
+	running    bool
+	reconciled time.Time
+	errors     map[string]string // stream name → last reconcile error
+}
+
+// reconcileStreams creates every declared stream and consumer, updating the ones whose
+// configuration drifted. Streams that are not declared are left alone.
+func reconcileStreams(ctx context.Context, js jetstream.JetStream, specs []StreamSpec) map[string]string {
+	failures := make(map[string]string)
+	for _, spec := range specs {
+		cfg, err := spec.streamConfig()
+		if err != nil {
+			failures[spec.Name] = err.Error()
+			continue
+		}
+		stream, err := js.CreateOrUpdateStream(ctx, cfg)
+		if err != nil {
+			failures[spec.Name] = err.Error()
+			continue
+		}
+		for _, consumer := range spec.Consumers {
+			if _, err := stream.CreateOrUpdateConsumer(ctx, consumer.consumerConfig()); err != nil {
+				failures[spec.Name] = fmt.Sprintf("consumer %s: %v", consumer.Durable, err)
+				break
+			}
+		}
+	}
+	return failures
+}
+
+// provisionStreams reconciles the declared streams in the background, retrying
+// until NATS accepts all of them. Only one reconciliation runs at a time.
+func (s *Supervisor) provisionStreams() {
+	s.streams.mu.Lock()
+	if s.streams.running {
+		s.streams.mu.Unlock()
+		return
+	}
+	s.streams.running = true
+	s.streams.mu.Unlock()
+
+	go func() {
+		defer func() {
+			s.streams.mu.Lock()
+			s.streams.running = false
+			s.streams.mu.Unlock()
+		}()
+
+		for {
+			err := s.reconcileStreamsOnce()
+			if err == nil {
+				return
+			}
+			log.Printf("⏳ JetStream provisioning will retry: %v", err)
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-time.After(streamReconcileRetry):
+			}
+		}
+	}()
+}
+
+// reconcileStreamsOnce connects to NATS and reconciles the declared streams once
+func (s *Supervisor) reconcileStreamsOnce() error {
+	specs := s.streamSpecs()
+	if err := validateStreamSpecs(specs); err != nil {
+		return err
+	}
+
+	nc, js, err := connectJetStream()
+	if err != nil {
+		return err
+	}
+	defer nc.Close()
+
+	ctx, cancel := context.WithTimeout(s.ctx, streamRequestTimeout*time.Duration(len(specs)+1))
+	defer cancel()
+	failures := reconcileStreams(ctx, js, specs)
+
+	s.streams.mu.Lock()
+	s.streams.reconciled = time.Now()
+	s.streams.errors = failures
+	s.streams.mu.Unlock()
+
+	if len(failures) > 0 {
+		names := make([]string, 0, len(failures))
+		for name := range failures {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("%d of %d streams failed (%s: %s)", len(failures), len(specs), names[0], failures[names[0]])
+	}
+	log.Printf("📦 JetStream streams provisioned (%d)", len(specs))
+	return nil
+}
+
+// connectJetStream opens a short-lived connection to the NATS server the components use
+func connectJetStream() (*nats.Conn, jetstream.JetStream, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	nc, err := nats.Connect(cfg.NatsURL, nats.Name("tmidb-supervisor-streams"), nats.Timeout(streamRequestTimeout), nats.NoReconnect())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to NATS at %s: %w", cfg.NatsURL, err)
+	}
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return nil, nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+	return nc, js, nil
+}
+
+// consumerHealth classifies a consumer from its backlog and last delivery
+func consumerHealth(info *jetstream.ConsumerInfo, now time.Time) string {
+	if info.NumPending == 0 && info.NumAckPending == 0 {
+		return "healthy"
+	}
+	last := info.Delivered.Last
+	if last == nil || now.Sub(*last) > consumerStallAfter {
+		return "stalled"
+	}
+	if info.NumPending > consumerLagWarn {
+		return "lagging"
+	}
+	return "healthy"
+}
+
+// consumerStatus summarizes a consumer for `nats streams`
+func consumerStatus(info *jetstream.ConsumerInfo, now time.Time) map[string]interface{} {
+	status := map[string]interface{}{
+		"name":           info.Name,
+		"filter_subject": info.Config.FilterSubject,
+		"pending":        info.NumPending,
+		"ack_pending":    info.NumAckPending,
+		"redelivered":    info.NumRedelivered,
+		"waiting":        info.NumWaiting,
+		"delivered_seq":  info.Delivered.Stream,
+		"ack_floor_seq":  info.AckFloor.Stream,
+		"health":         consumerHealth(info, now),
+		"max_deliver":    info.Config.MaxDeliver,
+		"ack_wait":       info.Config.AckWait.String(),
+		"push_bound":     info.PushBound,
+		"created":        info.Created,
+	}
+	if info.Delivered.Last != nil {
+		status["last_delivered"] = *info.Delivered.Last
+	}
+	return status
+}
+
+// handleNATSStreams reports every stream with its consumers' lag and health,
+// and whether the declared streams were provisioned
+func (s *Supervisor) handleNATSStreams(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	filter, _ := msg.Data["stream"].(string)
+
+	declared := make(map[string]bool)
+	for _, spec := range s.streamSpecs() {
+		declared[spec.Name] = true
+	}
+
+	s.streams.mu.Lock()
+	reconciled := s.streams.reconciled
+	failures := make(map[string]string, len(s.streams.errors))
+	for name, err := range s.streams.errors {
+		failures[name] = err
+	}
+	s.streams.mu.Unlock()
+
+	nc, js, err := connectJetStream()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer nc.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), streamRequestTimeout)
+	defer cancel()
+
+	now := time.Now()
+	found := make(map[string]bool)
+	var streams []map[string]interface{}
+
+	lister := js.ListStreams(ctx)
+	for info := range lister.Info() {
+		name := info.Config.Name
+		found[name] = true
+		if filter != "" && name != filter {
+			continue
+		}
+
+		stream := map[string]interface{}{
+			"name":      name,
+			"subjects":  info.Config.Subjects,
+			"storage":   info.Config.Storage.String(),
+			"replicas":  info.Config.Replicas,
+			"max_age":   info.Config.MaxAge.String(),
+			"messages":  info.State.Msgs,
+			"bytes":     info.State.Bytes,
+			"first_seq": info.State.FirstSeq,
+			"last_seq":  info.State.LastSeq,
+			"managed":   declared[name],
+		}
+		if !info.State.LastTime.IsZero() {
+			stream["last_message"] = info.State.LastTime
+		}
+		if failure, ok := failures[name]; ok {
+			stream["error"] = failure
+		}
+
+		var consumers []map[string]interface{}
+		if handle, err := js.Stream(ctx, name); err != nil {
+			stream["consumers_error"] = err.Error()
+		} else {
+			consumerLister := handle.ListConsumers(ctx)
+			for consumer := range consumerLister.Info() {
+				consumers = append(consumers, consumerStatus(consumer, now))
+			}
+			if err := consumerLister.Err(); err != nil {
+				stream["consumers_error"] = err.Error()
+			}
+		}
+		sort.Slice(consumers, func(i, j int) bool {
+			return consumers[i]["name"].(string) < consumers[j]["name"].(string)
+		})
+		stream["consumers"] = consumers
+
+		streams = append(streams, stream)
+	}
+	if err := lister.Err(); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to list streams: %v", err))
+	}
+
+	if filter != "" && !found[filter] {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("stream not found: %s", filter))
+	}
+
+	// 선언되었지만 아직 만들어지지 않은 스트림도 표시
+	var missing []map[string]interface{}
+	for _, spec := range s.streamSpecs() {
+		if found[spec.Name] || (filter != "" && spec.Name != filter) {
+			continue
+		}
+		entry := map[string]interface{}{"name": spec.Name, "subjects": spec.Subjects}
+		if failure, ok := failures[spec.Name]; ok {
+			entry["error"] = failure
+		}
+		missing = append(missing, entry)
+	}
+
+	sort.Slice(streams, func(i, j int) bool {
+		return streams[i]["name"].(string) < streams[j]["name"].(string)
+	})
+
+	result := map[string]interface{}{
+		"streams": streams,
+		"missing": missing,
+	}
+	if !reconciled.IsZero() {
+		result["reconciled_at"] = reconciled
+	}
+	return ipc.NewResponse(msg.ID, true, result, "")
+}
+
+// handleNATSReconcile re-applies the declared streams and consumers now
+func (s *Supervisor) handleNATSReconcile(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	if err := s.reconcileStreamsOnce(); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
+		"streams": len(s.streamSpecs()),
+	}, "")
+}
@@ -0,0 +1,87 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+func TestStreamSpecJSON(t *testing.T) {
+	var spec StreamSpec
+	data := `{"name":"AUDIT","subjects":["audit.>"],"max_age":"72h","consumers":[{"durable":"archiver","ack_wait":"45s","max_deliver":3}]}`
+	if err := json.Unmarshal([]byte(data), &spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec.MaxAge != 72*time.Hour || spec.Consumers[0].AckWait != 45*time.Second || spec.Consumers[0].MaxDeliver != 3 {
+		t.Errorf("decoded spec = %+v", spec)
+	}
+
+	encoded, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded StreamSpec
+	if err := json.Unmarshal(encoded, &decoded); err != nil || decoded.MaxAge != spec.MaxAge || decoded.Consumers[0].AckWait != spec.Consumers[0].AckWait {
+		t.Errorf("round trip = %s, %v", encoded, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"name":"X","max_age":"a week"}`), &spec); err == nil {
+		t.Error("invalid max_age accepted")
+	}
+}
+
+func TestValidateStreamSpecs(t *testing.T) {
+	if err := validateStreamSpecs(defaultStreams()); err != nil {
+		t.Errorf("default streams rejected: %v", err)
+	}
+
+	bad := map[string][]StreamSpec{
+		"missing name":       {{Subjects: []string{"a.>"}}},
+		"no subjects":        {{Name: "A"}},
+		"duplicate stream":   {{Name: "A", Subjects: []string{"a.>"}}, {Name: "A", Subjects: []string{"b.>"}}},
+		"unknown storage":    {{Name: "A", Subjects: []string{"a.>"}, Storage: "disk"}},
+		"anonymous consumer": {{Name: "A", Subjects: []string{"a.>"}, Consumers: []ConsumerSpec{{}}}},
+		"duplicate consumer": {{Name: "A", Subjects: []string{"a.>"}, Consumers: []ConsumerSpec{{Durable: "c"}, {Durable: "c"}}}},
+	}
+	for name, specs := range bad {
+		if err := validateStreamSpecs(specs); err == nil {
+			t.Errorf("%s accepted", name)
+		}
+	}
+}
+
+func TestStreamConfig(t *testing.T) {
+	cfg, err := StreamSpec{Name: "A", Subjects: []string{"a.>"}, Storage: "memory"}.streamConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Storage != jetstream.MemoryStorage || cfg.MaxBytes != -1 {
+		t.Errorf("stream config = %+v", cfg)
+	}
+}
+
+func TestConsumerHealth(t *testing.T) {
+	now := time.Now()
+	recent := now.Add(-time.Minute)
+	old := now.Add(-time.Hour)
+
+	tests := []struct {
+		name string
+		info jetstream.ConsumerInfo
+		want string
+	}{
+		{"caught up", jetstream.ConsumerInfo{}, "healthy"},
+		{"small backlog", jetstream.ConsumerInfo{NumPending: 10, Delivered: jetstream.SequenceInfo{Last: &recent}}, "healthy"},
+		{"large backlog", jetstream.ConsumerInfo{NumPending: consumerLagWarn + 1, Delivered: jetstream.SequenceInfo{Last: &recent}}, "lagging"},
+		{"no recent delivery", jetstream.ConsumerInfo{NumPending: 10, Delivered: jetstream.SequenceInfo{Last: &old}}, "stalled"},
+		{"never delivered", jetstream.ConsumerInfo{NumPending: 10}, "stalled"},
+		{"unacked only", jetstream.ConsumerInfo{NumAckPending: 5, Delivered: jetstream.SequenceInfo{Last: &old}}, "stalled"},
+	}
+	for _, tt := range tests {
+		if got := consumerHealth(&tt.info, now); got != tt.want {
+			t.Errorf("%s: health = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
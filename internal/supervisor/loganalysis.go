@@ -0,0 +1,373 @@
+package supervisor
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// Log analysis settings
+const (
+	logAnalysisDefaultHours = 24
+	logAnalysisMaxPatterns  = 10
+	logPatternMaxLength     = 200
+
+	logSpikeMinErrors = 10  // 이보다 적은 버킷은 급증으로 보지 않음
+	logSpikeFactor    = 3.0 // 평균 대비 배수
+	logSpikeStddev    = 3.0 // 평균 + N * 표준편차
+	logBucketCount    = 48  // 분석 구간을 나누는 버킷 수
+)
+
+// Message normalization rules used to cluster similar errors
+var logPatternRules = []struct {
+	re          *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), "<uuid>"},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`\b0x[0-9a-fA-F]+\b`), "<hex>"},
+	{regexp.MustCompile(`"[^"]*"`), `"<str>"`},
+	{regexp.MustCompile(`'[^']*'`), `'<str>'`},
+	{regexp.MustCompile(`\b\d+(\.\d+)?(ms|s|µs|ns|m|h|MB|KB|GB|B)?\b`), "<n>"},
+	{regexp.MustCompile(`\s+`), " "},
+}
+
+// logPattern groups error messages that normalize to the same text
+type logPattern struct {
+	Pattern    string    `json:"pattern"`
+	Count      int       `json:"count"`
+	Components []string  `json:"components"`
+	FirstSeen  time.Time `json:"first_seen"`
+	LastSeen   time.Time `json:"last_seen"`
+	Example    string    `json:"example"`
+
+	components map[string]bool
+}
+
+// logAnalysisSummary is the summary block rendered by `tmidb-cli diagnose logs`
+type logAnalysisSummary struct {
+	Total       int                       `json:"total"`
+	Errors      int                       `json:"errors"`
+	Warnings    int                       `json:"warnings"`
+	TimeRange   string                    `json:"time_range"`
+	ErrorRate   float64                   `json:"error_rate"`
+	WarningRate float64                   `json:"warning_rate"`
+	Components  map[string]*componentLogs `json:"components"`
+}
+
+// componentLogs holds per-component counters
+type componentLogs struct {
+	Total    int `json:"total"`
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+
+	buckets []int // 버킷별 에러 수
+}
+
+// logAnalysis is the response of MessageTypeDiagnoseLogs
+type logAnalysis struct {
+	Summary            logAnalysisSummary `json:"summary"`
+	ErrorPatterns      []*logPattern      `json:"error_patterns"`
+	Anomalies          []string           `json:"anomalies"`
+	RecommendedActions []string           `json:"recommended_actions"`
+}
+
+// logAnalyzer accumulates entries within a time window
+type logAnalyzer struct {
+	since, until time.Time
+	bucketSize   time.Duration
+
+	summary  logAnalysisSummary
+	patterns map[string]*logPattern
+}
+
+// handleDiagnoseLogs analyzes component logs over the requested number of hours
+func (s *Supervisor) handleDiagnoseLogs(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	hours := float64(logAnalysisDefaultHours)
+	if h, ok := msg.Data["hours"].(float64); ok && h > 0 {
+		hours = h
+	}
+
+	components := logComponents
+	if c, ok := msg.Data["component"].(string); ok && c != "" && c != "all" {
+		components = []string{c}
+	}
+
+	window := time.Duration(hours * float64(time.Hour))
+	analysis, err := s.analyzeLogs(components, window)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to analyze logs: %v", err))
+	}
+
+	return ipc.NewResponse(msg.ID, true, analysis, "")
+}
+
+// analyzeLogs scans current and rotated log files of the given components
+func (s *Supervisor) analyzeLogs(components []string, window time.Duration) (*logAnalysis, error) {
+	a := newLogAnalyzer(time.Now(), window)
+
+	for _, component := range components {
+		a.summary.Components[component] = &componentLogs{buckets: make([]int, logBucketCount)}
+
+		for _, path := range s.logManager.LogFiles(component, a.since) {
+			if err := a.scanFile(component, path); err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+
+	return a.result(), nil
+}
+
+func newLogAnalyzer(now time.Time, window time.Duration) *logAnalyzer {
+	return &logAnalyzer{
+		since:      now.Add(-window),
+		until:      now,
+		bucketSize: window / logBucketCount,
+		summary: logAnalysisSummary{
+			Components: make(map[string]*componentLogs),
+		},
+		patterns: make(map[string]*logPattern),
+	}
+}
+
+// scanFile reads one (optionally gzip-compressed) log file
+func (a *logAnalyzer) scanFile(component, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if filepath.Ext(path) == ".gz" {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if entry, ok := parseLogLine(component, strings.TrimSpace(scanner.Text())); ok {
+			a.add(component, entry)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// add records a single entry if it falls within the window
+func (a *logAnalyzer) add(component string, entry ipc.LogEntry) {
+	if entry.Timestamp.Before(a.since) || entry.Timestamp.After(a.until) {
+		return
+	}
+
+	stats, ok := a.summary.Components[component]
+	if !ok {
+		stats = &componentLogs{buckets: make([]int, logBucketCount)}
+		a.summary.Components[component] = stats
+	}
+
+	a.summary.Total++
+	stats.Total++
+
+	level := strings.ToUpper(entry.Level)
+	switch level {
+	case "ERROR", "FATAL":
+		a.summary.Errors++
+		stats.Errors++
+		if a.bucketSize > 0 {
+			bucket := int(entry.Timestamp.Sub(a.since) / a.bucketSize)
+			if bucket >= logBucketCount {
+				bucket = logBucketCount - 1
+			}
+			stats.buckets[bucket]++
+		}
+	case "WARN", "WARNING":
+		a.summary.Warnings++
+		stats.Warnings++
+		return
+	default:
+		return
+	}
+
+	key := normalizeLogMessage(entry.Message)
+	pattern, ok := a.patterns[key]
+	if !ok {
+		pattern = &logPattern{
+			Pattern:    key,
+			FirstSeen:  entry.Timestamp,
+			LastSeen:   entry.Timestamp,
+			Example:    entry.Message,
+			components: make(map[string]bool),
+		}
+		a.patterns[key] = pattern
+	}
+
+	pattern.Count++
+	pattern.components[component] = true
+	if entry.Timestamp.Before(pattern.FirstSeen) {
+		pattern.FirstSeen = entry.Timestamp
+	}
+	if entry.Timestamp.After(pattern.LastSeen) {
+		pattern.LastSeen = entry.Timestamp
+	}
+}
+
+// result builds the final analysis
+func (a *logAnalyzer) result() *logAnalysis {
+	summary := a.summary
+	summary.TimeRange = fmt.Sprintf("%s ~ %s",
+		a.since.Format("2006-01-02 15:04:05"), a.until.Format("2006-01-02 15:04:05"))
+	if summary.Total > 0 {
+		summary.ErrorRate = float64(summary.Errors) / float64(summary.Total) * 100
+		summary.WarningRate = float64(summary.Warnings) / float64(summary.Total) * 100
+	}
+
+	analysis := &logAnalysis{
+		Summary:            summary,
+		ErrorPatterns:      a.topPatterns(),
+		Anomalies:          a.detectAnomalies(),
+		RecommendedActions: make([]string, 0),
+	}
+	analysis.RecommendedActions = recommendLogActions(analysis)
+
+	return analysis
+}
+
+// topPatterns returns the most frequent error patterns
+func (a *logAnalyzer) topPatterns() []*logPattern {
+	patterns := make([]*logPattern, 0, len(a.patterns))
+	for _, p := range a.patterns {
+		for component := range p.components {
+			p.Components = append(p.Components, component)
+		}
+		sort.Strings(p.Components)
+		patterns = append(patterns, p)
+	}
+
+	sort.Slice(patterns, func(i, j int) bool {
+		if patterns[i].Count != patterns[j].Count {
+			return patterns[i].Count > patterns[j].Count
+		}
+		return patterns[i].Pattern < patterns[j].Pattern
+	})
+
+	if len(patterns) > logAnalysisMaxPatterns {
+		patterns = patterns[:logAnalysisMaxPatterns]
+	}
+	return patterns
+}
+
+// detectAnomalies finds error spikes and silent components
+func (a *logAnalyzer) detectAnomalies() []string {
+	anomalies := make([]string, 0)
+
+	components := make([]string, 0, len(a.summary.Components))
+	for component := range a.summary.Components {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+
+	for _, component := range components {
+		stats := a.summary.Components[component]
+		if stats.Total == 0 {
+			anomalies = append(anomalies, fmt.Sprintf("%s: no log entries in the analyzed period", component))
+			continue
+		}
+
+		for _, bucket := range errorSpikes(stats.buckets) {
+			start := a.since.Add(time.Duration(bucket) * a.bucketSize)
+			anomalies = append(anomalies, fmt.Sprintf("%s: error spike of %d errors between %s and %s",
+				component, stats.buckets[bucket],
+				start.Format("01-02 15:04"), start.Add(a.bucketSize).Format("15:04")))
+		}
+	}
+
+	return anomalies
+}
+
+// errorSpikes returns bucket indexes whose count is far above the mean
+func errorSpikes(buckets []int) []int {
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	sum := 0.0
+	for _, c := range buckets {
+		sum += float64(c)
+	}
+	mean := sum / float64(len(buckets))
+
+	variance := 0.0
+	for _, c := range buckets {
+		variance += (float64(c) - mean) * (float64(c) - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(buckets)))
+
+	var spikes []int
+	for i, c := range buckets {
+		count := float64(c)
+		if c >= logSpikeMinErrors && count >= mean*logSpikeFactor && count >= mean+logSpikeStddev*stddev {
+			spikes = append(spikes, i)
+		}
+	}
+
+	return spikes
+}
+
+// recommendLogActions suggests follow-ups based on the analysis
+func recommendLogActions(analysis *logAnalysis) []string {
+	actions := make([]string, 0)
+
+	if len(analysis.ErrorPatterns) > 0 {
+		top := analysis.ErrorPatterns[0]
+		actions = append(actions, fmt.Sprintf("Investigate the most frequent error (%d occurrences in %s): %s",
+			top.Count, strings.Join(top.Components, ", "), top.Example))
+	}
+
+	components := make([]string, 0, len(analysis.Summary.Components))
+	for component, stats := range analysis.Summary.Components {
+		if stats.Total > 0 && float64(stats.Errors)/float64(stats.Total)*100 >= logErrorWarn {
+			components = append(components, component)
+		}
+	}
+	sort.Strings(components)
+	for _, component := range components {
+		actions = append(actions, fmt.Sprintf("Review %s errors with 'tmidb-cli logs %s'", component, component))
+	}
+
+	if len(analysis.Anomalies) > 0 {
+		actions = append(actions, "Correlate error spikes with deployments, restarts or load changes at those times")
+	}
+
+	return actions
+}
+
+// normalizeLogMessage replaces variable parts (ids, numbers, addresses) so
+// that repeated messages cluster into one pattern
+func normalizeLogMessage(message string) string {
+	normalized := strings.TrimSpace(message)
+	for _, rule := range logPatternRules {
+		normalized = rule.re.ReplaceAllString(normalized, rule.replacement)
+	}
+
+	if runes := []rune(normalized); len(runes) > logPatternMaxLength {
+		normalized = string(runes[:logPatternMaxLength]) + "..."
+	}
+	return normalized
+}
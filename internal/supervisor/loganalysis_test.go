@@ -0,0 +1,54 @@
+package supervisor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+func TestNormalizeLogMessage(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{"connection to 10.0.0.1:5432 refused", "connection to 10.0.0.2:6432 refused"},
+		{"request 7f1c2a9e-1b2c-4d5e-8f90-123456789abc failed after 120ms", "request 00000000-0000-0000-0000-000000000000 failed after 3s"},
+		{`user "alice" not found`, `user "bob" not found`},
+	}
+
+	for _, tt := range tests {
+		if got, want := normalizeLogMessage(tt.a), normalizeLogMessage(tt.b); got != want {
+			t.Errorf("normalizeLogMessage(%q) = %q, want same as %q (%q)", tt.a, got, tt.b, want)
+		}
+	}
+}
+
+func TestLogAnalyzer(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	a := newLogAnalyzer(now, 48*time.Hour)
+	a.summary.Components["api"] = &componentLogs{buckets: make([]int, logBucketCount)}
+
+	// 버킷마다 1개의 에러, 마지막 버킷에만 급증
+	for i := 0; i < logBucketCount; i++ {
+		ts := a.since.Add(time.Duration(i)*time.Hour + time.Minute)
+		a.add("api", ipc.LogEntry{Level: "ERROR", Message: "query failed: timeout after 5s", Timestamp: ts})
+		a.add("api", ipc.LogEntry{Level: "INFO", Message: "ok", Timestamp: ts})
+	}
+	for i := 0; i < 30; i++ {
+		a.add("api", ipc.LogEntry{Level: "ERROR", Message: "dial tcp 10.0.0.1:4222: refused", Timestamp: now.Add(-time.Minute)})
+	}
+	a.add("api", ipc.LogEntry{Level: "WARN", Message: "slow", Timestamp: now.Add(-time.Minute)})
+	a.add("api", ipc.LogEntry{Level: "ERROR", Message: "too old", Timestamp: now.Add(-72 * time.Hour)})
+
+	result := a.result()
+
+	if result.Summary.Total != 127 || result.Summary.Errors != 78 || result.Summary.Warnings != 1 {
+		t.Errorf("summary = %+v", result.Summary)
+	}
+	if len(result.ErrorPatterns) != 2 || result.ErrorPatterns[0].Count != logBucketCount {
+		t.Fatalf("patterns = %+v", result.ErrorPatterns)
+	}
+	if len(result.Anomalies) != 1 {
+		t.Errorf("anomalies = %v, want one spike", result.Anomalies)
+	}
+}
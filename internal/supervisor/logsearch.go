@@ -0,0 +1,82 @@
+package supervisor
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/logger"
+)
+
+// handleSearchLogs handles get_logs requests with "search": true. Unlike the
+// plain request it scans rotated and compressed files and returns a page of
+// matching entries, newest first.
+func (s *Supervisor) handleSearchLogs(msg *ipc.Message) *ipc.Response {
+	query, err := searchQueryFromData(msg.Data)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	result, err := s.logManager.Search(query)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to search logs: %v", err))
+	}
+
+	return ipc.NewResponse(msg.ID, true, result, "")
+}
+
+// searchQueryFromData builds a search query from get_logs parameters.
+// since/until accept RFC3339 strings or unix seconds.
+func searchQueryFromData(data map[string]interface{}) (logger.SearchQuery, error) {
+	var query logger.SearchQuery
+
+	query.Component, _ = data["component"].(string)
+	query.Level, _ = data["level"].(string)
+
+	var err error
+	if query.Since, err = searchTime(data["since"]); err != nil {
+		return query, fmt.Errorf("invalid since: %v", err)
+	}
+	if query.Until, err = searchTime(data["until"]); err != nil {
+		return query, fmt.Errorf("invalid until: %v", err)
+	}
+
+	if grep, ok := data["grep"].(string); ok && grep != "" {
+		if query.Pattern, err = regexp.Compile(grep); err != nil {
+			return query, fmt.Errorf("invalid grep pattern: %v", err)
+		}
+	}
+
+	if fields, ok := data["fields"].(map[string]interface{}); ok {
+		query.Fields = make(map[string]string, len(fields))
+		for key, value := range fields {
+			query.Fields[key] = fmt.Sprint(value)
+		}
+	}
+
+	if offset, ok := data["offset"].(float64); ok {
+		query.Offset = int(offset)
+	}
+	if limit, ok := data["limit"].(float64); ok {
+		query.Limit = int(limit)
+	}
+
+	return query, nil
+}
+
+func searchTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case nil:
+		return time.Time{}, nil
+	case float64:
+		return time.Unix(int64(v), 0), nil
+	case string:
+		if v == "" {
+			return time.Time{}, nil
+		}
+		return time.Parse(time.RFC3339, v)
+	default:
+		return time.Time{}, fmt.Errorf("unsupported value %v", v)
+	}
+}
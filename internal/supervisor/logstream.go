@@ -0,0 +1,309 @@
+package supervisor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// logTailPollInterval is how often followed log files are checked for new data
+const logTailPollInterval = 250 * time.Millisecond
+
+// logComponents lists the components whose logs are followed for "all"
+var logComponents = []string{"api", "data-manager", "data-consumer", "postgresql", "nats", "seaweedfs"}
+
+// handleLogStream handles log stream requests
+func (s *Supervisor) handleLogStream(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	component, ok := msg.Data["component"].(string)
+	if !ok || component == "" {
+		return ipc.NewResponse(msg.ID, false, nil, "component name required")
+	}
+
+	action, ok := msg.Data["action"].(string)
+	if !ok {
+		action = "start"
+	}
+
+	switch action {
+	case "start":
+		lines := 0
+		if l, ok := msg.Data["lines"].(float64); ok && l > 0 {
+			lines = int(l)
+		}
+
+		bufferSize := 0
+		if b, ok := msg.Data["buffer"].(float64); ok && b > 0 {
+			bufferSize = int(b)
+		}
+
+		components := []string{component}
+		if component == "all" {
+			components = logComponents
+		}
+
+		// Create log stream for this connection; the IPC server keeps the
+		// connection open and drains the stream once this response is sent
+		stream := s.ipcServer.CreateLogStream(conn.ID, component, bufferSize)
+		go s.followLogs(stream, components, lines)
+
+		return ipc.NewResponse(msg.ID, true, map[string]interface{}{
+			"status":     "streaming",
+			"components": components,
+		}, "")
+	case "stop":
+		s.ipcServer.RemoveLogStream(conn.ID)
+		return ipc.NewResponse(msg.ID, true, map[string]string{"status": "stopped"}, "")
+	default:
+		return ipc.NewResponse(msg.ID, false, nil, "invalid action")
+	}
+}
+
+// followLogs feeds a log stream with recent history and then live entries
+// tailed from each component's log file until the stream is closed
+func (s *Supervisor) followLogs(stream *ipc.LogStream, components []string, lines int) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-stream.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// Send recent history first (oldest to newest)
+	if lines > 0 {
+		var recent []ipc.LogEntry
+		if len(components) > 1 {
+			recent, _ = s.readAllComponentLogs(lines)
+		} else {
+			logDir := fmt.Sprintf("%s/%s", s.config.LogDir, components[0])
+			recent, _ = s.readRecentLogsFromDir(logDir, components[0], lines)
+		}
+
+		for i := len(recent) - 1; i >= 0; i-- {
+			if err := stream.Send(ctx, recent[i]); err != nil {
+				return
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, component := range components {
+		wg.Add(1)
+		go func(component string) {
+			defer wg.Done()
+			s.tailComponentLog(ctx, component, stream)
+		}(component)
+	}
+	wg.Wait()
+}
+
+// tailComponentLog follows a component log file, handling rotation and truncation
+func (s *Supervisor) tailComponentLog(ctx context.Context, component string, stream *ipc.LogStream) {
+	tailer := newLogTailer(s.logManager.LogFilePath(component))
+	defer tailer.close()
+
+	// Only entries written after the stream starts are followed
+	tailer.open(true)
+
+	ticker := time.NewTicker(logTailPollInterval)
+	defer ticker.Stop()
+
+	drain := func() bool {
+		for {
+			line, err := tailer.readLine()
+			if err != nil {
+				if err != io.EOF {
+					log.Printf("❌ Error reading log file for %s: %v", component, err)
+				}
+				return true
+			}
+
+			entry, ok := parseLogLine(component, line)
+			if !ok || !s.logManager.IsStreamEnabled(entry.Process) {
+				continue
+			}
+
+			// Blocks while the stream buffer is full (backpressure)
+			if err := stream.Send(ctx, entry); err != nil {
+				return false
+			}
+		}
+	}
+
+	for {
+		if !drain() {
+			return
+		}
+
+		switch tailer.check() {
+		case tailRotated:
+			// Read whatever was written to the old file before the rename,
+			// then continue with the new file from the beginning
+			if !drain() {
+				return
+			}
+			tailer.close()
+			tailer.open(false)
+		case tailTruncated:
+			tailer.rewind()
+		case tailAppeared:
+			tailer.open(false)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tailState describes what happened to a followed file since the last check
+type tailState int
+
+const (
+	tailUnchanged tailState = iota
+	tailRotated
+	tailTruncated
+	tailAppeared
+)
+
+// logTailer reads appended lines from a log file across rotations
+type logTailer struct {
+	path    string
+	file    *os.File
+	reader  *bufio.Reader
+	info    os.FileInfo
+	offset  int64
+	partial string
+}
+
+// newLogTailer creates a tailer for the given path
+func newLogTailer(path string) *logTailer {
+	return &logTailer{path: path}
+}
+
+// open opens the file, optionally positioned at its end
+func (t *logTailer) open(fromEnd bool) {
+	file, err := os.Open(t.path)
+	if err != nil {
+		return // 파일이 아직 없으면 다음 확인 때 다시 시도
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return
+	}
+
+	t.offset = 0
+	if fromEnd {
+		if offset, err := file.Seek(0, io.SeekEnd); err == nil {
+			t.offset = offset
+		}
+	}
+
+	t.file = file
+	t.info = info
+	t.reader = bufio.NewReader(file)
+	t.partial = ""
+}
+
+// close closes the current file handle
+func (t *logTailer) close() {
+	if t.file != nil {
+		t.file.Close()
+		t.file = nil
+		t.reader = nil
+	}
+}
+
+// rewind restarts reading from the beginning of a truncated file
+func (t *logTailer) rewind() {
+	if t.file == nil {
+		return
+	}
+	if _, err := t.file.Seek(0, io.SeekStart); err != nil {
+		return
+	}
+	t.reader.Reset(t.file)
+	t.offset = 0
+	t.partial = ""
+}
+
+// readLine returns the next complete line; incomplete trailing data is kept
+// until the writer finishes the line
+func (t *logTailer) readLine() (string, error) {
+	if t.reader == nil {
+		return "", io.EOF
+	}
+
+	line, err := t.reader.ReadString('\n')
+	t.offset += int64(len(line))
+	if err != nil {
+		t.partial += line
+		return "", err
+	}
+
+	line = t.partial + line
+	t.partial = ""
+	return strings.TrimSpace(line), nil
+}
+
+// check compares the open handle with the file currently at path
+func (t *logTailer) check() tailState {
+	info, err := os.Stat(t.path)
+	if err != nil {
+		return tailUnchanged // 로테이션 중이거나 아직 생성되지 않음
+	}
+
+	if t.file == nil {
+		return tailAppeared
+	}
+
+	if !os.SameFile(t.info, info) {
+		return tailRotated
+	}
+
+	if info.Size() < t.offset {
+		return tailTruncated
+	}
+
+	return tailUnchanged
+}
+
+// parseLogLine converts a log file line into a log entry; lines that are not
+// JSON (e.g. raw external service output) are wrapped as INFO entries
+func parseLogLine(component, line string) (ipc.LogEntry, bool) {
+	if line == "" {
+		return ipc.LogEntry{}, false
+	}
+
+	var entry ipc.LogEntry
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		return ipc.LogEntry{
+			Process:   component,
+			Level:     "INFO",
+			Message:   line,
+			Timestamp: time.Now(),
+		}, true
+	}
+
+	if entry.Process == "" {
+		entry.Process = component
+	}
+
+	return entry, true
+}
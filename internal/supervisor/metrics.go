@@ -0,0 +1,147 @@
+package supervisor
+
+import (
+	"github.com/tmidb/tmidb-core/internal/metrics"
+)
+
+// newMetricsServer creates the /metrics exporter for the configured address
+func (s *Supervisor) newMetricsServer() *metrics.Server {
+	return metrics.NewServer(s.config.MetricsAddr, s.newMetricsRegistry())
+}
+
+// newMetricsRegistry supervisor 상태를 노출하는 메트릭 레지스트리 생성
+func (s *Supervisor) newMetricsRegistry() *metrics.Registry {
+	registry := metrics.NewRegistry()
+	registry.Register(metrics.CollectorFunc(s.collectProcessMetrics))
+	registry.Register(metrics.CollectorFunc(s.collectIPCMetrics))
+	registry.Register(metrics.CollectorFunc(s.collectLogMetrics))
+	registry.Register(metrics.CollectorFunc(s.collectBackupMetrics))
+	registry.Register(metrics.CollectorFunc(s.collectSystemMetrics))
+	return registry
+}
+
+// collectProcessMetrics 컴포넌트별 프로세스 메트릭
+func (s *Supervisor) collectProcessMetrics() []metrics.Family {
+	up := metrics.Family{
+		Name: "tmidb_process_up",
+		Help: "Whether the component process is running (1) or not (0).",
+		Type: metrics.TypeGauge,
+	}
+	cpu := metrics.Family{
+		Name: "tmidb_process_cpu_percent",
+		Help: "CPU usage of the component process in percent.",
+		Type: metrics.TypeGauge,
+	}
+	memory := metrics.Family{
+		Name: "tmidb_process_memory_bytes",
+		Help: "Resident memory of the component process in bytes.",
+		Type: metrics.TypeGauge,
+	}
+	uptime := metrics.Family{
+		Name: "tmidb_process_uptime_seconds",
+		Help: "Seconds since the component process was started.",
+		Type: metrics.TypeGauge,
+	}
+	restarts := metrics.Family{
+		Name: "tmidb_process_restarts_total",
+		Help: "Number of automatic restarts of the component process.",
+		Type: metrics.TypeCounter,
+	}
+
+	for _, proc := range s.processManager.GetProcessList() {
+		labels := map[string]string{"process": proc.Name, "type": proc.Type}
+
+		running := 0.0
+		if proc.Status == "running" {
+			running = 1
+		}
+
+		up.Samples = append(up.Samples, metrics.Sample{Labels: labels, Value: running})
+		cpu.Samples = append(cpu.Samples, metrics.Sample{Labels: labels, Value: proc.CPU})
+		memory.Samples = append(memory.Samples, metrics.Sample{Labels: labels, Value: float64(proc.Memory)})
+		uptime.Samples = append(uptime.Samples, metrics.Sample{Labels: labels, Value: proc.Uptime.Seconds()})
+		restarts.Samples = append(restarts.Samples, metrics.Sample{Labels: labels, Value: float64(proc.Restarts)})
+	}
+
+	return []metrics.Family{up, cpu, memory, uptime, restarts}
+}
+
+// collectIPCMetrics IPC 서버 메트릭
+func (s *Supervisor) collectIPCMetrics() []metrics.Family {
+	return []metrics.Family{
+		metrics.NewGauge("tmidb_ipc_connections", "Number of open IPC client connections.",
+			float64(s.ipcServer.GetConnectionCount()), nil),
+		metrics.NewGauge("tmidb_ipc_log_streams", "Number of active log follow streams.",
+			float64(s.ipcServer.GetLogStreamCount()), nil),
+	}
+}
+
+// collectLogMetrics 로그 처리량 메트릭
+func (s *Supervisor) collectLogMetrics() []metrics.Family {
+	lines := metrics.Family{
+		Name: "tmidb_log_lines_total",
+		Help: "Log lines written per component and level.",
+		Type: metrics.TypeCounter,
+	}
+	bytes := metrics.Family{
+		Name: "tmidb_log_bytes_total",
+		Help: "Log bytes written per component and level.",
+		Type: metrics.TypeCounter,
+	}
+
+	for _, stat := range s.logManager.GetLogStats() {
+		labels := map[string]string{"component": stat.Component, "level": stat.Level}
+		lines.Samples = append(lines.Samples, metrics.Sample{Labels: labels, Value: float64(stat.Lines)})
+		bytes.Samples = append(bytes.Samples, metrics.Sample{Labels: labels, Value: float64(stat.Bytes)})
+	}
+
+	return []metrics.Family{lines, bytes}
+}
+
+// collectBackupMetrics 백업 작업 메트릭
+func (s *Supervisor) collectBackupMetrics() []metrics.Family {
+	progress := metrics.Family{
+		Name: "tmidb_backup_progress_percent",
+		Help: "Progress of backup jobs in percent.",
+		Type: metrics.TypeGauge,
+	}
+	size := metrics.Family{
+		Name: "tmidb_backup_size_bytes",
+		Help: "Size of completed backup archives in bytes.",
+		Type: metrics.TypeGauge,
+	}
+
+	inProgress := 0
+	for id, p := range s.backupProgress {
+		if p.Status == "creating" {
+			inProgress++
+		}
+		progress.Samples = append(progress.Samples, metrics.Sample{
+			Labels: map[string]string{"backup": id, "status": p.Status},
+			Value:  p.Percent,
+		})
+	}
+
+	for id, b := range s.backups {
+		size.Samples = append(size.Samples, metrics.Sample{
+			Labels: map[string]string{"backup": id, "name": b.Name},
+			Value:  float64(b.Size),
+		})
+	}
+
+	return []metrics.Family{
+		progress,
+		size,
+		metrics.NewGauge("tmidb_backup_jobs_running", "Number of backup jobs currently running.",
+			float64(inProgress), nil),
+	}
+}
+
+// collectSystemMetrics 호스트 리소스 메트릭
+func (s *Supervisor) collectSystemMetrics() []metrics.Family {
+	return []metrics.Family{
+		metrics.NewGauge("tmidb_system_cpu_percent", "Host CPU usage in percent.", s.getCPUUsage(), nil),
+		metrics.NewGauge("tmidb_system_memory_percent", "Host memory usage in percent.", s.getMemoryUsage(), nil),
+		metrics.NewGauge("tmidb_system_disk_percent", "Disk usage of the data volume in percent.", s.getDiskUsage(), nil),
+	}
+}
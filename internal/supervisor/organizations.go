@@ -0,0 +1,134 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/config"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/storage"
+	"github.com/tmidb/tmidb-core/internal/usage"
+)
+
+// handleOrgList lists organizations with their user, category, target and token counts
+func (s *Supervisor) handleOrgList(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	orgs, err := database.ListOrganizations(db)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to list organizations: %v", err))
+	}
+	return ipc.NewResponse(msg.ID, true, orgs, "")
+}
+
+// handleOrgCreate creates an empty organization
+func (s *Supervisor) handleOrgCreate(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	name, _ := msg.Data["name"].(string)
+
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	org, err := database.CreateOrganization(db, name)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	log.Printf("🏢 Organization %s (%s) created by %s", org.Name, org.OrgID, ipcActor(conn))
+	return ipc.NewResponse(msg.ID, true, org, "")
+}
+
+// handleOrgDelete deletes an organization with its users, tokens, schemas and
+// data, then removes its attachment files from storage
+func (s *Supervisor) handleOrgDelete(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	ref, _ := msg.Data["org"].(string)
+	if ref == "" {
+		return ipc.NewResponse(msg.ID, false, nil, "organization id or name required")
+	}
+
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	actor := ipcActor(conn)
+	deletion, err := database.DeleteOrganization(db, ref, actor)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	log.Printf("🗑️ Organization %s (%s) deleted by %s", deletion.Name, deletion.OrgID, actor)
+
+	// The rows are gone either way; files that could not be removed are only reported
+	failed := 0
+	if len(deletion.StoragePaths) > 0 {
+		cfg, err := config.Load()
+		if err != nil || cfg.SeaweedFSFilerURL == "" {
+			failed = len(deletion.StoragePaths)
+		} else {
+			failed = deleteStoredFiles(storage.NewFilerClient(cfg.SeaweedFSFilerURL), deletion.StoragePaths)
+		}
+	}
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
+		"organization":         deletion,
+		"storage_failed_files": failed,
+	}, "")
+}
+
+// handleOrgUsage reports API requests, ingest volume and rate for a period,
+// and optionally the data each organization stores now
+func (s *Supervisor) handleOrgUsage(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	fromText, _ := msg.Data["from"].(string)
+	toText, _ := msg.Data["to"].(string)
+	from, to, err := usage.ParsePeriod(fromText, toText, time.Now())
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	orgRef, _ := msg.Data["org"].(string)
+	report, err := database.GetOrgUsage(db, orgRef, from, to)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to get usage: %v", err))
+	}
+	if withStorage, _ := msg.Data["storage"].(bool); withStorage {
+		for i := range report {
+			// Deleted organizations keep their usage history but store nothing
+			if report[i].Name == "" {
+				continue
+			}
+			if report[i].Storage, err = database.GetStorageUsage(db, report[i].OrgID); err != nil {
+				return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to measure storage of %s: %v", report[i].Name, err))
+			}
+		}
+	}
+	return ipc.NewResponse(msg.ID, true, report, "")
+}
+
+// deleteStoredFiles removes attachment files and returns how many could not be removed
+func deleteStoredFiles(filer *storage.FilerClient, paths []string) int {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	failed := 0
+	for _, path := range paths {
+		if err := filer.Delete(ctx, path); err != nil {
+			log.Printf("⚠️ Failed to delete attachment file %s: %v", path, err)
+			failed++
+		}
+	}
+	return failed
+}
@@ -0,0 +1,452 @@
+package supervisor
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/tmidb/tmidb-core/internal/config"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/sysinfo"
+)
+
+// Performance diagnostic settings
+const (
+	perfSampleInterval  = 1 * time.Second
+	perfDefaultDuration = 30 * time.Second
+	perfMaxDuration     = 10 * time.Minute
+	perfResultRetention = 1 * time.Hour
+	perfResultWait      = 5 * time.Second // 결과 요청 시 수집 완료를 기다리는 최대 시간
+	perfProbeTimeout    = 2 * time.Second
+
+	perfCPUAvgWarn    = 80.0
+	perfCPUMaxWarn    = 95.0
+	perfMemGrowthWarn = 1.5              // 첫 샘플 대비 증가 배수
+	perfMemGrowthMin  = 50 * 1024 * 1024 // 이보다 작은 증가는 무시
+)
+
+// perfLatencyWarn is the p99 response time above which a probe is a bottleneck
+var perfLatencyWarn = map[string]float64{
+	"api":        500,
+	"postgresql": 100,
+	"nats":       50,
+}
+
+// componentPerformance holds aggregated samples for one component
+type componentPerformance struct {
+	CPUAvg      float64 `json:"cpu_avg"`
+	CPUMax      float64 `json:"cpu_max"`
+	MemAvg      float64 `json:"mem_avg"`
+	MemMax      float64 `json:"mem_max"`
+	ResponseAvg float64 `json:"response_avg"` // ms
+	ResponseP99 float64 `json:"response_p99"` // ms
+	Samples     int     `json:"samples"`
+	Errors      int     `json:"errors,omitempty"`
+}
+
+// performanceBottleneck is a detected performance problem
+type performanceBottleneck struct {
+	Component      string `json:"component"`
+	Issue          string `json:"issue"`
+	Impact         string `json:"impact"`
+	Recommendation string `json:"recommendation"`
+}
+
+// performanceSummary summarizes a sampling run
+type performanceSummary struct {
+	Duration string `json:"duration"`
+	Samples  int    `json:"samples"`
+	Score    int    `json:"score"`
+}
+
+// performanceResult is the rendered result of a performance diagnostic
+type performanceResult struct {
+	Summary      performanceSummary               `json:"summary"`
+	Components   map[string]*componentPerformance `json:"components"`
+	Bottlenecks  []performanceBottleneck          `json:"bottlenecks"`
+	Optimization []string                         `json:"optimization"`
+}
+
+// performanceDiagnostic tracks a sampling run started by MessageTypeDiagnosePerformance
+type performanceDiagnostic struct {
+	ID        string        `json:"id"`
+	Status    string        `json:"status"` // "running", "completed", "failed"
+	StartTime time.Time     `json:"start_time"`
+	EndTime   *time.Time    `json:"end_time,omitempty"`
+	Duration  time.Duration `json:"-"`
+	Error     string        `json:"error,omitempty"`
+
+	*performanceResult
+
+	done chan struct{}
+}
+
+// performanceSeries collects raw samples for one component
+type performanceSeries struct {
+	cpu      []float64
+	mem      []float64
+	latency  []float64
+	errors   int
+	lastCPU  time.Duration
+	lastTime time.Time
+}
+
+// handleDiagnosePerformance starts a background sampling run
+func (s *Supervisor) handleDiagnosePerformance(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	duration := perfDefaultDuration
+	if d, ok := msg.Data["duration"].(float64); ok && d > 0 {
+		duration = time.Duration(d * float64(time.Second))
+	}
+	if duration < perfSampleInterval {
+		duration = perfSampleInterval
+	}
+	if duration > perfMaxDuration {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("duration must not exceed %v", perfMaxDuration))
+	}
+
+	diag := &performanceDiagnostic{
+		ID:        fmt.Sprintf("perf-%d", time.Now().UnixMilli()),
+		Status:    "running",
+		StartTime: time.Now(),
+		Duration:  duration,
+		done:      make(chan struct{}),
+	}
+
+	s.diagnosticsMutex.Lock()
+	s.pruneDiagnostics()
+	s.diagnostics[diag.ID] = diag
+	s.diagnosticsMutex.Unlock()
+
+	go s.runPerformanceDiagnostic(diag)
+
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
+		"id":       diag.ID,
+		"status":   diag.Status,
+		"duration": duration.Seconds(),
+	}, "")
+}
+
+// handleDiagnoseResult returns a stored diagnostic result
+func (s *Supervisor) handleDiagnoseResult(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	id, ok := msg.Data["id"].(string)
+	if !ok || id == "" {
+		return ipc.NewResponse(msg.ID, false, nil, "diagnostic id required")
+	}
+
+	s.diagnosticsMutex.RLock()
+	diag, exists := s.diagnostics[id]
+	s.diagnosticsMutex.RUnlock()
+
+	if !exists {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("diagnostic not found: %s", id))
+	}
+
+	// CLI는 duration 만큼 기다린 뒤 요청하므로 마지막 샘플 처리를 잠시 기다려준다
+	select {
+	case <-diag.done:
+	case <-time.After(perfResultWait):
+	}
+
+	s.diagnosticsMutex.RLock()
+	snapshot := *diag
+	s.diagnosticsMutex.RUnlock()
+
+	return ipc.NewResponse(msg.ID, true, snapshot, "")
+}
+
+// pruneDiagnostics removes finished results older than perfResultRetention.
+// Caller must hold diagnosticsMutex.
+func (s *Supervisor) pruneDiagnostics() {
+	for id, diag := range s.diagnostics {
+		if diag.EndTime != nil && time.Since(*diag.EndTime) > perfResultRetention {
+			delete(s.diagnostics, id)
+		}
+	}
+}
+
+// runPerformanceDiagnostic samples processes and probes until the duration elapses
+func (s *Supervisor) runPerformanceDiagnostic(diag *performanceDiagnostic) {
+	defer close(diag.done)
+
+	ctx, cancel := context.WithTimeout(s.ctx, diag.Duration)
+	defer cancel()
+
+	probes := s.newPerformanceProbes()
+	defer probes.close()
+
+	series := make(map[string]*performanceSeries)
+	getSeries := func(name string) *performanceSeries {
+		if _, ok := series[name]; !ok {
+			series[name] = &performanceSeries{}
+		}
+		return series[name]
+	}
+
+	ticker := time.NewTicker(perfSampleInterval)
+	defer ticker.Stop()
+
+	samples := 0
+	for {
+		s.samplePerformance(ctx, probes, getSeries)
+		samples++
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			s.finishPerformanceDiagnostic(diag, series, samples)
+			return
+		}
+	}
+}
+
+// samplePerformance records one sample for every process and probe
+func (s *Supervisor) samplePerformance(ctx context.Context, probes *performanceProbes, getSeries func(string) *performanceSeries) {
+	now := time.Now()
+	for _, proc := range s.processManager.GetProcessList() {
+		if proc.PID <= 0 {
+			continue
+		}
+
+		ps := getSeries(proc.Name)
+		ps.mem = append(ps.mem, float64(s.getProcessMemoryUsage(proc.PID)))
+
+		// 누적 CPU 시간 차이로 구간 CPU 사용률 계산
+		if cpu, err := sysinfo.ProcessCPUTime(proc.PID); err == nil {
+			if !ps.lastTime.IsZero() && cpu >= ps.lastCPU {
+				elapsed := now.Sub(ps.lastTime).Seconds()
+				if elapsed > 0 {
+					ps.cpu = append(ps.cpu, cpuPercent(cpu-ps.lastCPU, elapsed))
+				}
+			}
+			ps.lastCPU = cpu
+			ps.lastTime = now
+		}
+	}
+
+	for name, probe := range probes.probes {
+		ps := getSeries(name)
+		latency, err := probe(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				ps.errors++
+			}
+			continue
+		}
+		ps.latency = append(ps.latency, float64(latency.Microseconds())/1000)
+	}
+}
+
+// finishPerformanceDiagnostic aggregates the series and stores the result
+func (s *Supervisor) finishPerformanceDiagnostic(diag *performanceDiagnostic, series map[string]*performanceSeries, samples int) {
+	result := &performanceResult{
+		Components:   make(map[string]*componentPerformance, len(series)),
+		Bottlenecks:  make([]performanceBottleneck, 0),
+		Optimization: make([]string, 0),
+	}
+
+	names := make([]string, 0, len(series))
+	for name := range series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		ps := series[name]
+		perf := &componentPerformance{
+			Samples: max(len(ps.mem), len(ps.latency)),
+			Errors:  ps.errors,
+		}
+		perf.CPUAvg, perf.CPUMax = avgMax(ps.cpu)
+		perf.MemAvg, perf.MemMax = avgMax(ps.mem)
+		perf.ResponseAvg, _ = avgMax(ps.latency)
+		perf.ResponseP99 = percentile(ps.latency, 99)
+		result.Components[name] = perf
+
+		result.Bottlenecks = append(result.Bottlenecks, detectBottlenecks(name, ps, perf)...)
+	}
+
+	seen := make(map[string]bool)
+	for _, b := range result.Bottlenecks {
+		if !seen[b.Recommendation] {
+			seen[b.Recommendation] = true
+			result.Optimization = append(result.Optimization, b.Recommendation)
+		}
+	}
+
+	score := 100 - 15*len(result.Bottlenecks)
+	if score < 0 {
+		score = 0
+	}
+
+	endTime := time.Now()
+	result.Summary = performanceSummary{
+		Duration: endTime.Sub(diag.StartTime).Round(time.Second).String(),
+		Samples:  samples,
+		Score:    score,
+	}
+
+	s.diagnosticsMutex.Lock()
+	diag.performanceResult = result
+	diag.Status = "completed"
+	diag.EndTime = &endTime
+	s.diagnosticsMutex.Unlock()
+}
+
+// detectBottlenecks compares aggregated samples against thresholds
+func detectBottlenecks(name string, ps *performanceSeries, perf *componentPerformance) []performanceBottleneck {
+	var bottlenecks []performanceBottleneck
+
+	if perf.CPUAvg >= perfCPUAvgWarn {
+		bottlenecks = append(bottlenecks, performanceBottleneck{
+			Component:      name,
+			Issue:          fmt.Sprintf("sustained high CPU usage (%.1f%% avg)", perf.CPUAvg),
+			Impact:         "requests queue up and latency increases",
+			Recommendation: fmt.Sprintf("Profile %s or scale it out to spread the load", name),
+		})
+	} else if perf.CPUMax >= perfCPUMaxWarn {
+		bottlenecks = append(bottlenecks, performanceBottleneck{
+			Component:      name,
+			Issue:          fmt.Sprintf("CPU spikes up to %.1f%%", perf.CPUMax),
+			Impact:         "intermittent latency spikes",
+			Recommendation: fmt.Sprintf("Check %s logs for periodic heavy jobs", name),
+		})
+	}
+
+	if len(ps.mem) >= 2 {
+		first, last := ps.mem[0], ps.mem[len(ps.mem)-1]
+		if first > 0 && last >= first*perfMemGrowthWarn && last-first >= perfMemGrowthMin {
+			bottlenecks = append(bottlenecks, performanceBottleneck{
+				Component:      name,
+				Issue:          fmt.Sprintf("memory grew from %.0f MB to %.0f MB", first/1024/1024, last/1024/1024),
+				Impact:         "possible memory leak; the process may be OOM-killed",
+				Recommendation: fmt.Sprintf("Monitor %s memory over a longer run and inspect heap usage", name),
+			})
+		}
+	}
+
+	if limit, ok := perfLatencyWarn[name]; ok && perf.ResponseP99 > limit {
+		bottlenecks = append(bottlenecks, performanceBottleneck{
+			Component:      name,
+			Issue:          fmt.Sprintf("slow responses (p99 %.2fms > %.0fms)", perf.ResponseP99, limit),
+			Impact:         "clients observe high tail latency",
+			Recommendation: fmt.Sprintf("Investigate %s load and slow operations", name),
+		})
+	}
+
+	if ps.errors > 0 {
+		bottlenecks = append(bottlenecks, performanceBottleneck{
+			Component:      name,
+			Issue:          fmt.Sprintf("%d failed probes", ps.errors),
+			Impact:         "requests to the component fail intermittently",
+			Recommendation: fmt.Sprintf("Run 'tmidb-cli diagnose all' to check %s connectivity", name),
+		})
+	}
+
+	return bottlenecks
+}
+
+// performanceProbes measures response latency of the API and backing services
+type performanceProbes struct {
+	probes  map[string]func(context.Context) (time.Duration, error)
+	closers []func()
+}
+
+func (p *performanceProbes) close() {
+	for _, c := range p.closers {
+		c()
+	}
+}
+
+// newPerformanceProbes creates latency probes, skipping services that cannot be reached
+func (s *Supervisor) newPerformanceProbes() *performanceProbes {
+	p := &performanceProbes{
+		probes: make(map[string]func(context.Context) (time.Duration, error)),
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		// 잘못된 설정으로는 접속할 곳을 알 수 없음 (diagnose가 원인을 보여 줌)
+		return p
+	}
+
+	apiURL := fmt.Sprintf("http://localhost:%s/api/health", cfg.APIPort)
+	httpClient := &http.Client{Timeout: perfProbeTimeout}
+	p.probes["api"] = func(ctx context.Context) (time.Duration, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+		if err != nil {
+			return 0, err
+		}
+		start := time.Now()
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 500 {
+			return 0, fmt.Errorf("health check returned %s", resp.Status)
+		}
+		return time.Since(start), nil
+	}
+
+	if db, err := sql.Open("postgres", cfg.DatabaseURL); err == nil {
+		db.SetMaxOpenConns(1)
+		p.closers = append(p.closers, func() { db.Close() })
+		p.probes["postgresql"] = func(ctx context.Context) (time.Duration, error) {
+			ctx, cancel := context.WithTimeout(ctx, perfProbeTimeout)
+			defer cancel()
+			start := time.Now()
+			var one int
+			if err := db.QueryRowContext(ctx, "SELECT 1").Scan(&one); err != nil {
+				return 0, err
+			}
+			return time.Since(start), nil
+		}
+	}
+
+	if nc, err := nats.Connect(cfg.NatsURL, nats.Timeout(perfProbeTimeout)); err == nil {
+		p.closers = append(p.closers, nc.Close)
+		p.probes["nats"] = func(ctx context.Context) (time.Duration, error) {
+			return nc.RTT()
+		}
+	}
+
+	return p
+}
+
+// avgMax returns the mean and maximum of values
+func avgMax(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	sum, maxValue := 0.0, math.Inf(-1)
+	for _, v := range values {
+		sum += v
+		maxValue = math.Max(maxValue, v)
+	}
+
+	return sum / float64(len(values)), maxValue
+}
+
+// percentile returns the nearest-rank p-th percentile of values
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
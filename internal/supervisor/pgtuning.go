@@ -0,0 +1,279 @@
+package supervisor
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/tmidb/tmidb-core/internal/sysinfo"
+)
+
+// postgresDataDir is the PostgreSQL data directory managed by the supervisor
+const postgresDataDir = "/data/postgresql"
+
+// postgresTuningFile is rendered into the data directory and included at the
+// end of postgresql.conf, so its settings win over the defaults from initdb
+const postgresTuningFile = "tmidb-tuning.conf"
+
+const postgresTuningInclude = "include_if_exists = '" + postgresTuningFile + "'\t# managed by tmidb-supervisor"
+
+const (
+	mb = int64(1024 * 1024)
+	gb = 1024 * mb
+)
+
+// PostgresProfile describes the resources PostgreSQL is tuned for
+type PostgresProfile struct {
+	Name           string `json:"name"`
+	Memory         int64  `json:"memory"` // bytes
+	CPUs           int    `json:"cpus"`
+	MaxConnections int    `json:"max_connections"`
+}
+
+// postgresProfiles are the named sizing profiles
+var postgresProfiles = map[string]PostgresProfile{
+	"small":  {Name: "small", Memory: 1 * gb, CPUs: 2, MaxConnections: 50},
+	"medium": {Name: "medium", Memory: 4 * gb, CPUs: 4, MaxConnections: 100},
+	"large":  {Name: "large", Memory: 16 * gb, CPUs: 8, MaxConnections: 200},
+}
+
+// minPostgresMemory is the smallest explicit memory target accepted
+const minPostgresMemory = 256 * mb
+
+// ParsePostgresProfile reads a postgres_profile value: a profile name (small,
+// medium, large), "auto" to size for this machine, or an explicit memory
+// target such as "8GB" or "512MB". An empty value returns nil, leaving
+// postgresql.conf unmanaged.
+func ParsePostgresProfile(value string) (*PostgresProfile, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if profile, ok := postgresProfiles[strings.ToLower(value)]; ok {
+		return &profile, nil
+	}
+
+	var memory int64
+	if strings.EqualFold(value, "auto") {
+		var err error
+		if memory, err = availableMemory(); err != nil {
+			return nil, fmt.Errorf("failed to detect memory for auto profile: %w", err)
+		}
+	} else {
+		var err error
+		if memory, err = parseMemorySize(value); err != nil {
+			return nil, fmt.Errorf("invalid postgres_profile %q: use small, medium, large, auto or a memory target such as 8GB", value)
+		}
+	}
+	if memory < minPostgresMemory {
+		return nil, fmt.Errorf("postgres_profile memory must be at least %s", formatMemorySize(minPostgresMemory))
+	}
+	return &PostgresProfile{Name: value, Memory: memory, CPUs: runtime.NumCPU(), MaxConnections: 100}, nil
+}
+
+// parseMemorySize reads sizes like "512MB", "8GB" or "8G"
+func parseMemorySize(value string) (int64, error) {
+	upper := strings.ToUpper(strings.TrimSpace(value))
+	units := []struct {
+		suffix string
+		size   int64
+	}{{"TB", 1024 * gb}, {"GB", gb}, {"MB", mb}, {"T", 1024 * gb}, {"G", gb}, {"M", mb}}
+	for _, unit := range units {
+		if number, ok := strings.CutSuffix(upper, unit.suffix); ok {
+			n, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid size %q", value)
+			}
+			return int64(n * float64(unit.size)), nil
+		}
+	}
+	return 0, fmt.Errorf("size %q needs a unit (MB, GB, TB)", value)
+}
+
+// formatMemorySize writes a size the way postgresql.conf expects it (kB, MB or GB)
+func formatMemorySize(bytes int64) string {
+	switch {
+	case bytes >= gb && bytes%gb == 0:
+		return fmt.Sprintf("%dGB", bytes/gb)
+	case bytes >= mb:
+		return fmt.Sprintf("%dMB", bytes/mb)
+	default:
+		return fmt.Sprintf("%dkB", bytes/1024)
+	}
+}
+
+// availableMemory returns the cgroup memory limit if one is set, otherwise the
+// machine's total memory
+func availableMemory() (int64, error) {
+	if data, err := os.ReadFile("/sys/fs/cgroup/memory.max"); err == nil {
+		if limit, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err == nil && limit > 0 {
+			return limit, nil
+		}
+	}
+
+	mem, err := sysinfo.SystemMemory()
+	if err != nil {
+		return 0, err
+	}
+	return int64(mem.Total), nil
+}
+
+// postgresSetting is one rendered postgresql.conf line
+type postgresSetting struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Settings derives postgresql.conf settings for the profile, following the
+// usual PostgreSQL and timescaledb-tune guidance
+func (p *PostgresProfile) Settings() []postgresSetting {
+	cpus := max(p.CPUs, 1)
+	sharedBuffers := p.Memory / 4
+	workMem := max((p.Memory-sharedBuffers)/int64(p.MaxConnections*3), 4*mb)
+	maintenanceWorkMem := min(p.Memory/16, 2*gb)
+	walBuffers := min(sharedBuffers/32, 16*mb)
+
+	// WAL sizes grow with the instance so that checkpoints are not forced by WAL volume
+	minWAL, maxWAL := 256*mb, 1*gb
+	switch {
+	case p.Memory >= 16*gb:
+		minWAL, maxWAL = 2*gb, 16*gb
+	case p.Memory >= 4*gb:
+		minWAL, maxWAL = 1*gb, 4*gb
+	}
+
+	backgroundWorkers := 8
+	if cpus >= 8 {
+		backgroundWorkers = 16
+	}
+
+	return []postgresSetting{
+		{"max_connections", strconv.Itoa(p.MaxConnections)},
+		{"shared_buffers", formatMemorySize(sharedBuffers)},
+		{"effective_cache_size", formatMemorySize(p.Memory * 3 / 4)},
+		{"work_mem", formatMemorySize(workMem)},
+		{"maintenance_work_mem", formatMemorySize(maintenanceWorkMem)},
+		{"wal_buffers", formatMemorySize(walBuffers)},
+		{"min_wal_size", formatMemorySize(minWAL)},
+		{"max_wal_size", formatMemorySize(maxWAL)},
+		{"checkpoint_completion_target", "0.9"},
+		{"wal_compression", "on"},
+		{"random_page_cost", "1.1"},
+		{"effective_io_concurrency", "200"},
+		{"default_statistics_target", "100"},
+		{"max_worker_processes", strconv.Itoa(backgroundWorkers + cpus + 3)},
+		{"max_parallel_workers", strconv.Itoa(cpus)},
+		{"max_parallel_workers_per_gather", strconv.Itoa(max(cpus/2, 1))},
+		{"max_locks_per_transaction", "256"},
+		{"shared_preload_libraries", "'timescaledb'"},
+		{"timescaledb.max_background_workers", strconv.Itoa(backgroundWorkers)},
+	}
+}
+
+// renderPostgresTuning renders the managed settings file
+func renderPostgresTuning(p *PostgresProfile) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Generated by tmidb-supervisor from postgres_profile %q\n", p.Name)
+	fmt.Fprintf(&buf, "# (%s memory, %d CPUs). Changes here are overwritten; use\n", formatMemorySize(p.Memory), p.CPUs)
+	buf.WriteString("# 'tmidb-cli config set postgres_profile <profile>' instead.\n\n")
+	for _, setting := range p.Settings() {
+		fmt.Fprintf(&buf, "%s = %s\n", setting.Name, setting.Value)
+	}
+	return buf.Bytes()
+}
+
+// writePostgresTuning renders the profile into dataDir and makes postgresql.conf
+// include it. A nil profile removes the rendered file. It reports whether
+// anything changed, in which case PostgreSQL must be restarted to apply it.
+// Nothing is written before initdb has created the data directory.
+func writePostgresTuning(dataDir string, p *PostgresProfile) (bool, error) {
+	var rendered []byte
+	if p != nil {
+		rendered = renderPostgresTuning(p)
+	}
+	return writeManagedConf(dataDir, postgresTuningFile, postgresTuningInclude, rendered)
+}
+
+// writeManagedConf writes a supervisor-managed settings file into dataDir and
+// appends its include line to postgresql.conf. Nil content removes the file
+// (the include_if_exists line stays and is harmless). It reports whether
+// anything changed and does nothing before initdb has run.
+func writeManagedConf(dataDir, file, include string, content []byte) (bool, error) {
+	confPath := filepath.Join(dataDir, "postgresql.conf")
+	conf, err := os.ReadFile(confPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read postgresql.conf: %w", err)
+	}
+
+	path := filepath.Join(dataDir, file)
+	if content == nil {
+		if err := os.Remove(path); err != nil {
+			if os.IsNotExist(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to remove %s: %w", file, err)
+		}
+		return true, nil
+	}
+
+	// Files are written in place so they keep the owner PostgreSQL runs as
+	changed := false
+	if !bytes.Contains(conf, []byte(include)) {
+		line := "\n" + include + "\n"
+		if len(conf) > 0 && conf[len(conf)-1] != '\n' {
+			line = "\n" + line
+		}
+		if err := appendFile(confPath, line); err != nil {
+			return false, fmt.Errorf("failed to update postgresql.conf: %w", err)
+		}
+		changed = true
+	}
+
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, content) {
+		return changed, nil
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	return true, nil
+}
+
+// appendFile appends text to an existing file
+func appendFile(path, text string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// applyPostgresTuning renders the configured profile before PostgreSQL starts.
+// If PostgreSQL is already running the new settings apply on its next restart.
+func (s *Supervisor) applyPostgresTuning() (bool, error) {
+	profile, err := ParsePostgresProfile(s.config.PostgresProfile)
+	if err != nil {
+		return false, err
+	}
+	changed, err := writePostgresTuning(postgresDataDir, profile)
+	if err != nil {
+		return false, err
+	}
+	if changed && profile != nil {
+		log.Printf("🐘 PostgreSQL tuned for profile %s (%s memory, %d CPUs)", profile.Name, formatMemorySize(profile.Memory), profile.CPUs)
+	} else if changed {
+		log.Println("🐘 PostgreSQL tuning removed")
+	}
+	return changed, nil
+}
@@ -0,0 +1,86 @@
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParsePostgresProfile(t *testing.T) {
+	if p, err := ParsePostgresProfile(""); p != nil || err != nil {
+		t.Errorf("empty profile = %v, %v", p, err)
+	}
+	p, err := ParsePostgresProfile("Medium")
+	if err != nil || p.Memory != 4*gb || p.MaxConnections != 100 {
+		t.Errorf("medium = %+v, %v", p, err)
+	}
+	if p, err := ParsePostgresProfile("1.5GB"); err != nil || p.Memory != 1536*mb {
+		t.Errorf("1.5GB = %+v, %v", p, err)
+	}
+	for _, bad := range []string{"huge", "8", "128MB", "-1GB"} {
+		if _, err := ParsePostgresProfile(bad); err == nil {
+			t.Errorf("ParsePostgresProfile(%q) accepted", bad)
+		}
+	}
+}
+
+func TestPostgresProfileSettings(t *testing.T) {
+	profile := postgresProfiles["medium"]
+	settings := map[string]string{}
+	for _, s := range profile.Settings() {
+		settings[s.Name] = s.Value
+	}
+	want := map[string]string{
+		"shared_buffers":                     "1GB",
+		"effective_cache_size":               "3GB",
+		"work_mem":                           "10MB",
+		"maintenance_work_mem":               "256MB",
+		"wal_buffers":                        "16MB",
+		"max_wal_size":                       "4GB",
+		"max_worker_processes":               "15",
+		"max_parallel_workers_per_gather":    "2",
+		"timescaledb.max_background_workers": "8",
+	}
+	for name, value := range want {
+		if settings[name] != value {
+			t.Errorf("%s = %q, want %q", name, settings[name], value)
+		}
+	}
+}
+
+func TestWritePostgresTuning(t *testing.T) {
+	dir := t.TempDir()
+	profile := postgresProfiles["small"]
+
+	// Nothing is written before initdb has run
+	if changed, err := writePostgresTuning(dir, &profile); changed || err != nil {
+		t.Fatalf("before initdb: %v, %v", changed, err)
+	}
+
+	confPath := filepath.Join(dir, "postgresql.conf")
+	if err := os.WriteFile(confPath, []byte("max_connections = 100"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := writePostgresTuning(dir, &profile); !changed || err != nil {
+		t.Fatalf("first render: %v, %v", changed, err)
+	}
+	if changed, err := writePostgresTuning(dir, &profile); changed || err != nil {
+		t.Errorf("unchanged render: %v, %v", changed, err)
+	}
+	conf, _ := os.ReadFile(confPath)
+	if strings.Count(string(conf), postgresTuningInclude) != 1 || !strings.HasPrefix(string(conf), "max_connections = 100\n") {
+		t.Errorf("postgresql.conf = %q", conf)
+	}
+	tuning, _ := os.ReadFile(filepath.Join(dir, postgresTuningFile))
+	if !strings.Contains(string(tuning), "shared_buffers = 256MB\n") {
+		t.Errorf("tuning file = %s", tuning)
+	}
+
+	if changed, err := writePostgresTuning(dir, nil); !changed || err != nil {
+		t.Errorf("remove: %v, %v", changed, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, postgresTuningFile)); !os.IsNotExist(err) {
+		t.Error("tuning file not removed")
+	}
+}
@@ -0,0 +1,189 @@
+package supervisor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/profiling"
+)
+
+const (
+	// defaultProfilingPort is the supervisor's pprof port when profiling_port is not configured
+	defaultProfilingPort = 6060
+	// defaultProfileDir is used when profile_dir is not configured
+	defaultProfileDir = "./profiles"
+	// maxProfiles is how many stored profiles are kept per component
+	maxProfiles = 20
+)
+
+// profilingComponents are the processes with a pprof endpoint, in port order
+var profilingComponents = []string{"supervisor", "api", "data-manager", "data-consumer"}
+
+func (s *Supervisor) profileDir() string {
+	if s.config.ProfileDir != "" {
+		return s.config.ProfileDir
+	}
+	return defaultProfileDir
+}
+
+// profilingAddr returns the loopback address of a component's pprof endpoint
+func (s *Supervisor) profilingAddr(component string) (string, error) {
+	index := slices.Index(profilingComponents, component)
+	if index < 0 {
+		return "", fmt.Errorf("component %s has no pprof endpoint (use %s)", component, strings.Join(profilingComponents, ", "))
+	}
+	port := s.config.ProfilingPort
+	if port == 0 {
+		port = defaultProfilingPort
+	}
+	return fmt.Sprintf("127.0.0.1:%d", port+index), nil
+}
+
+// startProfiling generates the endpoint token and opens the supervisor's own endpoint
+func (s *Supervisor) startProfiling() {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		log.Printf("⚠️ Failed to generate pprof token, profiling disabled: %v", err)
+		return
+	}
+	s.profilingToken = hex.EncodeToString(buf)
+
+	addr, _ := s.profilingAddr("supervisor")
+	server, err := profiling.Start(addr, s.profilingToken)
+	if err != nil {
+		// 컴포넌트의 엔드포인트는 그대로 사용할 수 있음
+		log.Printf("⚠️ Failed to start pprof endpoint: %v", err)
+		return
+	}
+	s.profilingServer = server
+	log.Printf("🔬 pprof endpoint listening on %s/debug/pprof/", server.Addr())
+}
+
+// profilingEnv tells an internal component where to open its pprof endpoint
+func (s *Supervisor) profilingEnv(component string) map[string]string {
+	if s.profilingToken == "" {
+		return nil
+	}
+	addr, err := s.profilingAddr(component)
+	if err != nil {
+		return nil
+	}
+	return map[string]string{
+		profiling.EnvAddr:  addr,
+		profiling.EnvToken: s.profilingToken,
+	}
+}
+
+// ProfileInfo describes a profile fetched by diagnose profile
+type ProfileInfo struct {
+	Component string    `json:"component"`
+	Type      string    `json:"type"`
+	Duration  string    `json:"duration,omitempty"`
+	Time      time.Time `json:"time"`
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	Data      []byte    `json:"data,omitempty"`
+}
+
+// fetchProfile collects a profile from a component and stores it in the profile directory
+func (s *Supervisor) fetchProfile(ctx context.Context, component, profileType string, duration time.Duration) (*ProfileInfo, error) {
+	if s.profilingToken == "" {
+		return nil, fmt.Errorf("profiling is disabled: set \"profiling\": true in the supervisor config and restart")
+	}
+	addr, err := s.profilingAddr(component)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := profiling.Path(profileType, duration); err != nil {
+		return nil, err
+	}
+	if profileType != "cpu" && profileType != "trace" {
+		duration = 0
+	} else if duration <= 0 {
+		duration = profiling.DefaultDuration
+	}
+
+	started := time.Now()
+	data, err := profiling.Fetch(ctx, addr, s.profilingToken, profileType, duration)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", component, err)
+	}
+
+	if err := os.MkdirAll(s.profileDir(), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	ext := ".pb.gz"
+	if profileType == "trace" {
+		ext = ".trace"
+	}
+	name := fmt.Sprintf("%s-%s-%s%s", component, profileType, started.Format("20060102-150405"), ext)
+	path := filepath.Join(s.profileDir(), name)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write profile: %w", err)
+	}
+	s.pruneProfiles(component)
+
+	info := &ProfileInfo{
+		Component: component,
+		Type:      profileType,
+		Time:      started,
+		Path:      path,
+		Size:      int64(len(data)),
+		Data:      data,
+	}
+	if duration > 0 {
+		info.Duration = duration.String()
+	}
+	return info, nil
+}
+
+// pruneProfiles removes the oldest stored profiles of a component beyond maxProfiles
+func (s *Supervisor) pruneProfiles(component string) {
+	matches, err := filepath.Glob(filepath.Join(s.profileDir(), component+"-*"))
+	if err != nil || len(matches) <= maxProfiles {
+		return
+	}
+	// 이름에 시각이 들어 있어 이름순이 시간순
+	sort.Strings(matches)
+	for _, path := range matches[:len(matches)-maxProfiles] {
+		if err := os.Remove(path); err != nil {
+			log.Printf("⚠️ Failed to remove old profile %s: %v", path, err)
+		}
+	}
+}
+
+// handleDiagnoseProfile fetches a profile from a component and returns it with its stored path
+func (s *Supervisor) handleDiagnoseProfile(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	component, _ := msg.Data["component"].(string)
+	if component == "" {
+		return ipc.NewResponse(msg.ID, false, nil, "component is required")
+	}
+	profileType, _ := msg.Data["type"].(string)
+	if profileType == "" {
+		profileType = "heap"
+	}
+	var duration time.Duration
+	if seconds, ok := msg.Data["duration"].(float64); ok && seconds > 0 {
+		duration = time.Duration(seconds * float64(time.Second))
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, profiling.MaxDuration+30*time.Second)
+	defer cancel()
+
+	info, err := s.fetchProfile(ctx, component, profileType, duration)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	log.Printf("🔬 Stored %s profile of %s at %s (%d bytes)", profileType, component, info.Path, info.Size)
+	return ipc.NewResponse(msg.ID, true, info, "")
+}
@@ -0,0 +1,79 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tmidb/tmidb-core/internal/profiling"
+)
+
+func TestProfilingEnv(t *testing.T) {
+	s := &Supervisor{config: &Config{ProfilingPort: 7000}}
+	if env := s.profilingEnv("api"); env != nil {
+		t.Fatalf("profiling disabled, got env %v", env)
+	}
+
+	s.profilingToken = "secret"
+	env := s.profilingEnv("data-consumer")
+	if env[profiling.EnvAddr] != "127.0.0.1:7003" || env[profiling.EnvToken] != "secret" {
+		t.Fatalf("env = %v", env)
+	}
+	if _, err := s.profilingAddr("postgresql"); err == nil {
+		t.Fatal("postgresql has no pprof endpoint")
+	}
+}
+
+func TestFetchProfile(t *testing.T) {
+	// 비어 있는 포트 확보
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	dir := t.TempDir()
+	s := &Supervisor{config: &Config{ProfilingPort: port, ProfileDir: dir}}
+
+	if _, err := s.fetchProfile(context.Background(), "supervisor", "heap", 0); err == nil || !strings.Contains(err.Error(), "disabled") {
+		t.Fatalf("expected disabled error, got %v", err)
+	}
+
+	s.startProfiling()
+	if s.profilingServer == nil {
+		t.Fatal("pprof endpoint did not start")
+	}
+	defer s.profilingServer.Stop()
+
+	// 오래된 프로파일은 maxProfiles개만 남김
+	for i := 0; i < maxProfiles; i++ {
+		name := fmt.Sprintf("supervisor-heap-20000101-0000%02d.pb.gz", i)
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("old"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	info, err := s.fetchProfile(context.Background(), "supervisor", "heap", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size == 0 || int64(len(info.Data)) != info.Size || info.Duration != "" {
+		t.Fatalf("unexpected profile info: size %d, data %d, duration %q", info.Size, len(info.Data), info.Duration)
+	}
+	if data, err := os.ReadFile(info.Path); err != nil || len(data) != len(info.Data) {
+		t.Fatalf("stored profile: %d bytes, %v", len(data), err)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "supervisor-*"))
+	if len(matches) != maxProfiles {
+		t.Fatalf("%d profiles kept, want %d", len(matches), maxProfiles)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "supervisor-heap-20000101-000000.pb.gz")); !os.IsNotExist(err) {
+		t.Fatal("oldest profile should have been pruned")
+	}
+}
@@ -0,0 +1,238 @@
+package supervisor
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+const (
+	// defaultRollingTimeout bounds how long one component may take to become healthy
+	defaultRollingTimeout = 60 * time.Second
+	// rollingSettleTime is how long a component without a probe must stay running
+	rollingSettleTime = 3 * time.Second
+	// rollingPollInterval is how often component status is checked while waiting
+	rollingPollInterval = 500 * time.Millisecond
+)
+
+// RollingRestartStep tracks one component of a rolling restart
+type RollingRestartStep struct {
+	Component string     `json:"component"`
+	Status    string     `json:"status"` // "pending", "restarting", "waiting", "healthy", "failed", "skipped"
+	Message   string     `json:"message,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+}
+
+// RollingRestartProgress tracks a rolling restart operation
+type RollingRestartProgress struct {
+	ID        string               `json:"id"`
+	Status    string               `json:"status"` // "running", "completed", "failed"
+	Steps     []RollingRestartStep `json:"steps"`
+	StartTime time.Time            `json:"start_time"`
+	EndTime   *time.Time           `json:"end_time,omitempty"`
+	Error     string               `json:"error,omitempty"`
+}
+
+// rollingRestarts holds the progress of rolling restarts started over IPC
+type rollingRestarts struct {
+	mu       sync.Mutex
+	progress map[string]*RollingRestartProgress
+	active   string
+}
+
+func (s *Supervisor) handleRollingRestart(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	components, _ := msg.Data["components"].([]interface{})
+	if len(components) == 0 {
+		return ipc.NewResponse(msg.ID, false, nil, "components parameter required")
+	}
+
+	timeout := defaultRollingTimeout
+	if seconds, ok := msg.Data["timeout"].(float64); ok && seconds > 0 {
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+
+	progress := &RollingRestartProgress{
+		ID:        fmt.Sprintf("rolling-%d", time.Now().UnixNano()),
+		Status:    "running",
+		StartTime: time.Now(),
+	}
+	for _, c := range components {
+		name, ok := c.(string)
+		if !ok || name == "" {
+			return ipc.NewResponse(msg.ID, false, nil, "components must be a list of names")
+		}
+		if _, err := s.processManager.GetProcessStatus(name); err != nil {
+			return ipc.NewResponse(msg.ID, false, nil, err.Error())
+		}
+		progress.Steps = append(progress.Steps, RollingRestartStep{Component: name, Status: "pending"})
+	}
+
+	s.upgrades.mu.Lock()
+	upgrading := s.upgrades.active
+	s.upgrades.mu.Unlock()
+	if upgrading != "" {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("upgrade %s is in progress", upgrading))
+	}
+
+	s.rolling.mu.Lock()
+	if s.rolling.active != "" {
+		active := s.rolling.active
+		s.rolling.mu.Unlock()
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("rolling restart %s is already in progress", active))
+	}
+	if s.rolling.progress == nil {
+		s.rolling.progress = make(map[string]*RollingRestartProgress)
+	}
+	s.rolling.progress[progress.ID] = progress
+	s.rolling.active = progress.ID
+	s.rolling.mu.Unlock()
+
+	go s.performRollingRestart(progress, timeout)
+
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
+		"id": progress.ID,
+	}, "")
+}
+
+func (s *Supervisor) handleRollingRestartStatus(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	id, _ := msg.Data["id"].(string)
+	if id == "" {
+		return ipc.NewResponse(msg.ID, false, nil, "rolling restart id is required")
+	}
+
+	s.rolling.mu.Lock()
+	defer s.rolling.mu.Unlock()
+
+	progress, exists := s.rolling.progress[id]
+	if !exists {
+		return ipc.NewResponse(msg.ID, false, nil, "rolling restart not found")
+	}
+
+	// 진행 중인 작업과 공유하지 않도록 복사본 반환
+	snapshot := *progress
+	snapshot.Steps = append([]RollingRestartStep(nil), progress.Steps...)
+	return ipc.NewResponse(msg.ID, true, snapshot, "")
+}
+
+// performRollingRestart restarts components one at a time and stops at the first
+// component that does not become healthy, leaving the remaining ones untouched
+func (s *Supervisor) performRollingRestart(progress *RollingRestartProgress, timeout time.Duration) {
+	log.Printf("🔄 Starting rolling restart %s (%d components)", progress.ID, len(progress.Steps))
+
+	var failure error
+	for i := range progress.Steps {
+		name := progress.Steps[i].Component
+
+		if failure != nil {
+			s.setRollingStep(progress, i, "skipped", "")
+			continue
+		}
+
+		s.setRollingStep(progress, i, "restarting", "")
+		restartedAt := time.Now()
+		if err := s.processManager.RestartProcess(name); err != nil {
+			failure = fmt.Errorf("failed to restart %s: %v", name, err)
+			s.setRollingStep(progress, i, "failed", err.Error())
+			continue
+		}
+
+		s.setRollingStep(progress, i, "waiting", "waiting for component to report healthy")
+		if err := s.waitComponentHealthy(name, restartedAt, timeout); err != nil {
+			failure = fmt.Errorf("%s did not become healthy: %v", name, err)
+			s.setRollingStep(progress, i, "failed", err.Error())
+			continue
+		}
+
+		s.setRollingStep(progress, i, "healthy", fmt.Sprintf("healthy after %s", time.Since(restartedAt).Round(time.Second)))
+		log.Printf("✅ Rolling restart: %s is healthy", name)
+	}
+
+	s.rolling.mu.Lock()
+	now := time.Now()
+	progress.EndTime = &now
+	if failure != nil {
+		progress.Status = "failed"
+		progress.Error = failure.Error()
+	} else {
+		progress.Status = "completed"
+	}
+	s.rolling.active = ""
+	s.rolling.mu.Unlock()
+
+	if failure != nil {
+		log.Printf("❌ Rolling restart %s aborted: %v", progress.ID, failure)
+	} else {
+		log.Printf("✅ Rolling restart %s completed", progress.ID)
+	}
+}
+
+func (s *Supervisor) setRollingStep(progress *RollingRestartProgress, i int, status, message string) {
+	s.rolling.mu.Lock()
+	defer s.rolling.mu.Unlock()
+
+	step := &progress.Steps[i]
+	step.Status = status
+	step.Message = message
+	switch status {
+	case "healthy", "failed", "skipped":
+		now := time.Now()
+		step.EndTime = &now
+	}
+}
+
+// waitComponentHealthy polls the process until it reports healthy or the timeout expires
+func (s *Supervisor) waitComponentHealthy(name string, since time.Time, timeout time.Duration) error {
+	probed := s.healthCheckFor(name) != nil
+	deadline := time.Now().Add(timeout)
+
+	for {
+		info, err := s.processManager.GetProcessStatus(name)
+		if err != nil {
+			return err
+		}
+
+		healthy, err := rollingStepHealthy(info, since, probed, time.Now())
+		if err != nil {
+			return err
+		}
+		if healthy {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s (status: %s)", timeout, info.Status)
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		case <-time.After(rollingPollInterval):
+		}
+	}
+}
+
+// rollingStepHealthy decides whether a restarted component may be considered healthy.
+// Components with a probe need a passing probe taken after the restart; others only
+// need to stay running for rollingSettleTime.
+func rollingStepHealthy(info *ipc.ProcessInfo, since time.Time, probed bool, now time.Time) (bool, error) {
+	switch info.Status {
+	case "running":
+	case "error", "stopped":
+		return false, fmt.Errorf("component is %s", info.Status)
+	default:
+		return false, nil
+	}
+
+	if !probed {
+		return now.Sub(info.StartTime) >= rollingSettleTime, nil
+	}
+
+	probe := info.Health
+	if probe == nil || probe.CheckedAt.Before(since) {
+		return false, nil
+	}
+	return probe.Healthy && !probe.Degraded, nil
+}
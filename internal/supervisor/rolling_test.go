@@ -0,0 +1,37 @@
+package supervisor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+func TestRollingStepHealthy(t *testing.T) {
+	since := time.Now()
+	now := since.Add(10 * time.Second)
+
+	cases := []struct {
+		name    string
+		info    ipc.ProcessInfo
+		probed  bool
+		healthy bool
+		wantErr bool
+	}{
+		{"starting", ipc.ProcessInfo{Status: "starting"}, false, false, false},
+		{"crashed", ipc.ProcessInfo{Status: "error"}, false, false, true},
+		{"settling", ipc.ProcessInfo{Status: "running", StartTime: now.Add(-time.Second)}, false, false, false},
+		{"settled", ipc.ProcessInfo{Status: "running", StartTime: since}, false, true, false},
+		{"no probe yet", ipc.ProcessInfo{Status: "running"}, true, false, false},
+		{"stale probe", ipc.ProcessInfo{Status: "running", Health: &ipc.ProbeResult{Healthy: true, CheckedAt: since.Add(-time.Second)}}, true, false, false},
+		{"failing probe", ipc.ProcessInfo{Status: "running", Health: &ipc.ProbeResult{CheckedAt: now}}, true, false, false},
+		{"degraded", ipc.ProcessInfo{Status: "running", Health: &ipc.ProbeResult{Healthy: true, Degraded: true, CheckedAt: now}}, true, false, false},
+		{"passing probe", ipc.ProcessInfo{Status: "running", Health: &ipc.ProbeResult{Healthy: true, CheckedAt: now}}, true, true, false},
+	}
+	for _, tc := range cases {
+		healthy, err := rollingStepHealthy(&tc.info, since, tc.probed, now)
+		if healthy != tc.healthy || (err != nil) != tc.wantErr {
+			t.Errorf("%s: healthy = %v, err = %v", tc.name, healthy, err)
+		}
+	}
+}
@@ -0,0 +1,389 @@
+package supervisor
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+const (
+	// EnvSecretsKey holds the secrets key when secrets_key is not configured
+	EnvSecretsKey = "TMIDB_SECRETS_KEY"
+	// defaultSecretsKeyFile is created on first use when neither secrets_key nor the env var is set
+	defaultSecretsKeyFile = "./config/secrets.key"
+
+	secretPrefix = "enc:v1:"
+	secretMask   = "********"
+)
+
+// secretComponents are the components secrets can be handed to
+var secretComponents = []string{"api", "data-manager", "data-consumer"}
+
+// secretNamePattern limits secret names to environment variable names
+var secretNamePattern = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+
+// Secret is a config value encrypted at rest. It is decrypted only when it is
+// handed to a component as an environment variable of the same name.
+type Secret struct {
+	Value      string    `json:"value"`                // enc:v1:<base64 nonce+ciphertext>
+	Components []string  `json:"components,omitempty"` // components that receive it (empty: all internal components)
+	Updated    time.Time `json:"updated"`
+}
+
+// handedTo reports whether the secret is handed to component
+func (s *Secret) handedTo(component string) bool {
+	return len(s.Components) == 0 || slices.Contains(s.Components, component)
+}
+
+// loadSecretsKey resolves the key secrets are encrypted with. secrets_key is
+// "env:NAME", "file:PATH" or "exec:COMMAND" (a KMS or vault client printing the
+// key); unset uses $TMIDB_SECRETS_KEY or ./config/secrets.key. With create, a
+// missing key file is generated.
+func loadSecretsKey(source string, create bool) ([]byte, error) {
+	if source == "" {
+		if os.Getenv(EnvSecretsKey) != "" {
+			source = "env:" + EnvSecretsKey
+		} else {
+			source = "file:" + defaultSecretsKeyFile
+		}
+	}
+
+	kind, ref, _ := strings.Cut(source, ":")
+	var material string
+	switch kind {
+	case "env":
+		material = os.Getenv(ref)
+		if material == "" {
+			return nil, fmt.Errorf("secrets key variable %s is not set", ref)
+		}
+	case "file":
+		data, err := os.ReadFile(ref)
+		if os.IsNotExist(err) && create {
+			data, err = createSecretsKeyFile(ref)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secrets key: %w", err)
+		}
+		if info, err := os.Stat(ref); err == nil && info.Mode().Perm()&0077 != 0 {
+			log.Printf("⚠️ Secrets key %s is readable by other users (mode %v)", ref, info.Mode().Perm())
+		}
+		material = string(data)
+	case "exec":
+		out, err := exec.Command("sh", "-c", ref).Output()
+		if err != nil {
+			return nil, fmt.Errorf("secrets key command failed: %w", err)
+		}
+		material = string(out)
+	default:
+		return nil, fmt.Errorf("invalid secrets_key %q (use env:NAME, file:PATH or exec:COMMAND)", source)
+	}
+
+	material = strings.TrimSpace(material)
+	if material == "" {
+		return nil, fmt.Errorf("secrets key from %s is empty", source)
+	}
+	key := sha256.Sum256([]byte(material))
+	return key[:], nil
+}
+
+// createSecretsKeyFile writes a random key readable only by the supervisor's user
+func createSecretsKeyFile(path string) ([]byte, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	data := []byte(hex.EncodeToString(raw) + "\n")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return nil, err
+	}
+	log.Printf("🔑 Generated secrets key %s", path)
+	return data, nil
+}
+
+func secretAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptSecret seals a value with AES-256-GCM; the name is authenticated so a
+// value cannot be moved to another secret
+func encryptSecret(key []byte, name, plaintext string) (string, error) {
+	aead, err := secretAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(name))
+	return secretPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptSecret opens a value written by encryptSecret
+func decryptSecret(key []byte, name, value string) (string, error) {
+	encoded, ok := strings.CutPrefix(value, secretPrefix)
+	if !ok {
+		return "", fmt.Errorf("secret %s is not encrypted", name)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("secret %s is corrupt: %w", name, err)
+	}
+	aead, err := secretAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("secret %s is corrupt", name)
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("cannot decrypt secret %s (wrong secrets key?)", name)
+	}
+	return string(plain), nil
+}
+
+// maskedSecrets is how secrets appear in config get, list and export
+func maskedSecrets(secrets map[string]*Secret) map[string]interface{} {
+	masked := make(map[string]interface{}, len(secrets))
+	for name := range secrets {
+		masked[name] = secretMask
+	}
+	return masked
+}
+
+// onlyMasked reports whether a "secrets" value sent back by config import or
+// edit is just the masked form, which leaves the secrets unchanged
+func onlyMasked(value interface{}) bool {
+	secrets, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for _, v := range secrets {
+		if v != secretMask {
+			return false
+		}
+	}
+	return true
+}
+
+// secretEnv decrypts the secrets handed to component. A secret that cannot be
+// decrypted is left out and logged, so the component fails on the missing value
+// rather than on a wrong one.
+func (s *Supervisor) secretEnv(component string) map[string]string {
+	secrets := s.config.Secrets
+	if len(secrets) == 0 {
+		return nil
+	}
+	key, err := loadSecretsKey(s.config.SecretsKey, false)
+	if err != nil {
+		log.Printf("⚠️ Secrets not passed to %s: %v", component, err)
+		return nil
+	}
+
+	env := make(map[string]string)
+	for name, secret := range secrets {
+		if secret == nil || !secret.handedTo(component) {
+			continue
+		}
+		value, err := decryptSecret(key, name, secret.Value)
+		if err != nil {
+			log.Printf("⚠️ %v", err)
+			continue
+		}
+		env[name] = value
+	}
+	return env
+}
+
+// componentEnv is the environment an internal component is started with
+func (s *Supervisor) componentEnv(component string) map[string]string {
+	env := s.profilingEnv(component)
+	secrets := s.secretEnv(component)
+	if len(secrets) == 0 {
+		return env
+	}
+	if env == nil {
+		env = make(map[string]string, len(secrets))
+	}
+	for k, v := range secrets {
+		env[k] = v
+	}
+	return env
+}
+
+// refreshSecretEnv hands the current secrets to the registered components and
+// returns those that must be restarted to see them
+func (s *Supervisor) refreshSecretEnv(components []string) []string {
+	updated := []string{}
+	for _, component := range components {
+		if err := s.processManager.SetProcessEnv(component, s.componentEnv(component)); err != nil {
+			// 아직 등록되지 않은 컴포넌트는 시작할 때 받음
+			continue
+		}
+		updated = append(updated, component)
+	}
+	return updated
+}
+
+// redactSecretEnv hides the values of configured secrets in a component environment
+func (s *Supervisor) redactSecretEnv(env map[string]string) map[string]string {
+	redacted := make(map[string]string, len(env))
+	for k, v := range env {
+		if _, secret := s.config.Secrets[k]; secret {
+			v = "<redacted>"
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
+// handleConfigSecretSet encrypts a secret into the config file and hands it to
+// the components that use it (after their next restart)
+func (s *Supervisor) handleConfigSecretSet(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	name, _ := msg.Data["name"].(string)
+	value, _ := msg.Data["value"].(string)
+	if !secretNamePattern.MatchString(name) {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("invalid secret name %q (use an environment variable name such as POSTGRES_PASSWORD)", name))
+	}
+	if value == "" {
+		return ipc.NewResponse(msg.ID, false, nil, "secret value is required")
+	}
+
+	var components []string
+	if list, ok := msg.Data["components"].([]interface{}); ok {
+		for _, item := range list {
+			component, _ := item.(string)
+			if !slices.Contains(secretComponents, component) {
+				return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("unknown component %q (valid: %s)", component, strings.Join(secretComponents, ", ")))
+			}
+			components = append(components, component)
+		}
+	}
+
+	key, err := loadSecretsKey(s.config.SecretsKey, true)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	sealed, err := encryptSecret(key, name, value)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to encrypt secret: %v", err))
+	}
+
+	s.configMutex.Lock()
+	previous := s.config.Secrets[name]
+	secrets := make(map[string]*Secret, len(s.config.Secrets)+1)
+	for k, v := range s.config.Secrets {
+		secrets[k] = v
+	}
+	secrets[name] = &Secret{Value: sealed, Components: components, Updated: time.Now()}
+	s.config.Secrets = secrets
+	s.configMutex.Unlock()
+
+	// 이전에 받던 컴포넌트에서도 빠지거나 바뀌므로 모두 갱신
+	affected := secretTargets(secrets[name])
+	if previous != nil {
+		affected = mergeComponents(affected, secretTargets(previous))
+	}
+	restart := s.refreshSecretEnv(affected)
+
+	log.Printf("🔐 Secret %s set by %s", name, ipcActor(conn))
+	return s.persistConfig(msg, map[string]interface{}{
+		"name":          name,
+		"components":    affected,
+		"needs_restart": restart,
+	})
+}
+
+// handleConfigSecretDelete removes a secret from the config file
+func (s *Supervisor) handleConfigSecretDelete(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	name, _ := msg.Data["name"].(string)
+
+	s.configMutex.Lock()
+	secret, ok := s.config.Secrets[name]
+	if !ok {
+		s.configMutex.Unlock()
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("secret %s not found", name))
+	}
+	secrets := make(map[string]*Secret, len(s.config.Secrets))
+	for k, v := range s.config.Secrets {
+		if k != name {
+			secrets[k] = v
+		}
+	}
+	s.config.Secrets = secrets
+	s.configMutex.Unlock()
+
+	restart := s.refreshSecretEnv(secretTargets(secret))
+
+	log.Printf("🔐 Secret %s deleted by %s", name, ipcActor(conn))
+	return s.persistConfig(msg, map[string]interface{}{
+		"name":          name,
+		"needs_restart": restart,
+	})
+}
+
+// handleConfigSecretList lists the secrets without their values
+func (s *Supervisor) handleConfigSecretList(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	names := make([]string, 0, len(s.config.Secrets))
+	for name := range s.config.Secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		secret := s.config.Secrets[name]
+		if secret == nil {
+			continue
+		}
+		list = append(list, map[string]interface{}{
+			"name":       name,
+			"value":      secretMask,
+			"components": secretTargets(secret),
+			"updated":    secret.Updated,
+		})
+	}
+	return ipc.NewResponse(msg.ID, true, list, "")
+}
+
+// secretTargets lists the components a secret is handed to
+func secretTargets(secret *Secret) []string {
+	if secret == nil || len(secret.Components) == 0 {
+		return secretComponents
+	}
+	return secret.Components
+}
+
+func mergeComponents(a, b []string) []string {
+	merged := append([]string(nil), a...)
+	for _, component := range b {
+		if !slices.Contains(merged, component) {
+			merged = append(merged, component)
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
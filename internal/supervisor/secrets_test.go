@@ -0,0 +1,121 @@
+package supervisor
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSecretEncryption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.key")
+	if _, err := loadSecretsKey("file:"+path, false); err == nil {
+		t.Fatal("missing key file accepted without create")
+	}
+	key, err := loadSecretsKey("file:"+path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("key file not created with mode 0600: %v %v", info, err)
+	}
+	again, err := loadSecretsKey("file:"+path, true)
+	if err != nil || !bytes.Equal(key, again) {
+		t.Fatal("key file not reused")
+	}
+
+	sealed, err := encryptSecret(key, "POSTGRES_PASSWORD", "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sealed, secretPrefix) || strings.Contains(sealed, "hunter2") {
+		t.Fatalf("sealed value %q", sealed)
+	}
+	if plain, err := decryptSecret(key, "POSTGRES_PASSWORD", sealed); err != nil || plain != "hunter2" {
+		t.Fatalf("decrypt = %q, %v", plain, err)
+	}
+
+	// 다른 이름으로 옮기거나 다른 키로 열 수 없음
+	if _, err := decryptSecret(key, "TMIDB_PASSWORD", sealed); err == nil {
+		t.Error("value decrypted under another name")
+	}
+	t.Setenv("TEST_SECRETS_KEY", "other key")
+	other, err := loadSecretsKey("env:TEST_SECRETS_KEY", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decryptSecret(other, "POSTGRES_PASSWORD", sealed); err == nil {
+		t.Error("value decrypted with another key")
+	}
+
+	if _, err := loadSecretsKey("vault:x", false); err == nil {
+		t.Error("unknown key source accepted")
+	}
+}
+
+func TestSecretEnv(t *testing.T) {
+	t.Setenv(EnvSecretsKey, "test key")
+	key, err := loadSecretsKey("", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seal := func(name, value string) string {
+		sealed, err := encryptSecret(key, name, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sealed
+	}
+
+	s := &Supervisor{config: &Config{Secrets: map[string]*Secret{
+		"POSTGRES_PASSWORD": {Value: seal("POSTGRES_PASSWORD", "pg")},
+		"SMTP_PASSWORD":     {Value: seal("SMTP_PASSWORD", "smtp"), Components: []string{"api"}},
+	}}}
+
+	if env := s.secretEnv("api"); env["POSTGRES_PASSWORD"] != "pg" || env["SMTP_PASSWORD"] != "smtp" {
+		t.Errorf("api env = %v", env)
+	}
+	if env := s.secretEnv("data-consumer"); env["POSTGRES_PASSWORD"] != "pg" || env["SMTP_PASSWORD"] != "" {
+		t.Errorf("data-consumer env = %v", env)
+	}
+
+	redacted := s.redactSecretEnv(map[string]string{"POSTGRES_PASSWORD": "pg", "LOG_LEVEL": "debug"})
+	if redacted["POSTGRES_PASSWORD"] != "<redacted>" || redacted["LOG_LEVEL"] != "debug" {
+		t.Errorf("redacted = %v", redacted)
+	}
+}
+
+func TestCandidateConfigSecrets(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Secrets = map[string]*Secret{"POSTGRES_PASSWORD": {Value: secretPrefix + "x"}}
+
+	// export/edit에서 돌아온 가려진 값은 무시
+	values := map[string]interface{}{
+		"log_level": "DEBUG",
+		"secrets":   map[string]interface{}{"POSTGRES_PASSWORD": secretMask},
+	}
+	candidate, err := candidateConfig(cfg, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if candidate.LogLevel != "DEBUG" || candidate.Secrets["POSTGRES_PASSWORD"].Value != secretPrefix+"x" {
+		t.Errorf("candidate = %+v", candidate)
+	}
+	if _, ok := values["secrets"]; !ok {
+		t.Error("caller's values modified")
+	}
+
+	// 평문을 넣으려는 시도는 거부
+	_, err = candidateConfig(cfg, map[string]interface{}{
+		"secrets": map[string]interface{}{"POSTGRES_PASSWORD": "plain"},
+	})
+	if err == nil {
+		t.Error("plaintext secret accepted through config import")
+	}
+
+	cfg.Secrets["BROKEN"] = &Secret{Value: "plain"}
+	if errs, _ := validateConfig(cfg); !strings.Contains(strings.Join(errs, "\n"), "Secret BROKEN is not encrypted") {
+		t.Errorf("unencrypted secret not reported: %v", errs)
+	}
+}
@@ -0,0 +1,61 @@
+package supervisor
+
+import (
+	"log"
+
+	"github.com/tmidb/tmidb-core/internal/config"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// handleSetupInit completes the initial setup non-interactively: it creates the
+// organization, the admin user and the admin API token, or returns the existing
+// ones when the same setup has already been applied
+func (s *Supervisor) handleSetupInit(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	req := database.SetupRequest{}
+	req.OrgName, _ = msg.Data["org_name"].(string)
+	req.Username, _ = msg.Data["username"].(string)
+	req.Password, _ = msg.Data["password"].(string)
+	if err := req.Validate(); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	// The admin API token is stored encrypted with the API's key
+	cfg, err := config.Load()
+	if err == nil {
+		err = database.InitCrypto(cfg.EncryptionKey)
+	}
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, "failed to load encryption key: "+err.Error())
+	}
+
+	result, err := database.RunInitialSetup(db, req)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	if result.Created {
+		log.Printf("🏁 Initial setup completed by %s: organization %s, admin %s", ipcActor(conn), result.OrgName, result.Username)
+	}
+	return ipc.NewResponse(msg.ID, true, result, "")
+}
+
+// handleSetupStatus reports whether the initial setup is done and when it locks
+func (s *Supervisor) handleSetupStatus(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	state, err := database.GetSetupState(db)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, "failed to read setup state: "+err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, state, "")
+}
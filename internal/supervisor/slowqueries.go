@@ -0,0 +1,58 @@
+package supervisor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// handleDiagnoseSlowQueries returns the slowest recorded queries, optionally with
+// the plan of their slowest run
+func (s *Supervisor) handleDiagnoseSlowQueries(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	data := msg.Data
+	sort, _ := data["sort"].(string)
+	if sort == "" {
+		sort = "total"
+	}
+	if !slices.Contains(database.SlowQuerySorts, sort) {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("invalid sort %q (use %s)", sort, strings.Join(database.SlowQuerySorts, ", ")))
+	}
+	limit := 20
+	if l, ok := data["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+	explain, _ := data["explain"].(bool)
+
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	queries, err := database.ListSlowQueries(db, sort, limit)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to read slow queries: %v", err))
+	}
+	if explain {
+		database.ExplainSlowQueries(db, queries)
+	}
+	return ipc.NewResponse(msg.ID, true, queries, "")
+}
+
+// handleDiagnoseSlowQueriesReset clears the slow query log
+func (s *Supervisor) handleDiagnoseSlowQueriesReset(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	deleted, err := database.ResetSlowQueries(db)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to reset slow queries: %v", err))
+	}
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{"deleted": deleted}, "")
+}
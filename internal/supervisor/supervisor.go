@@ -13,20 +13,32 @@ import (
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"archive/tar"
 	"compress/gzip"
 	"crypto/sha256"
+	"crypto/tls"
 	"encoding/hex"
 
+	"github.com/tmidb/tmidb-core/internal/alerting"
+	"github.com/tmidb/tmidb-core/internal/cluster"
+	"github.com/tmidb/tmidb-core/internal/discovery"
+	"github.com/tmidb/tmidb-core/internal/grpcapi"
+	"github.com/tmidb/tmidb-core/internal/ha"
 	"github.com/tmidb/tmidb-core/internal/ipc"
 	"github.com/tmidb/tmidb-core/internal/logger"
+	"github.com/tmidb/tmidb-core/internal/metrics"
 	"github.com/tmidb/tmidb-core/internal/process"
+	"github.com/tmidb/tmidb-core/internal/profiling"
+	"github.com/tmidb/tmidb-core/internal/sysinfo"
+	"github.com/tmidb/tmidb-core/internal/version"
 )
 
 // Supervisor manages all tmiDB components and external services
@@ -36,6 +48,12 @@ type Supervisor struct {
 	ipcServer      *ipc.Server
 	logManager     *logger.Manager
 	processManager *process.Manager
+	metricsServer  *metrics.Server
+	grpcServer     *grpcapi.Server
+
+	// pprof endpoints (token is shared with the internal components)
+	profilingServer *profiling.Server
+	profilingToken  string
 
 	// External services
 	postgresql *exec.Cmd
@@ -43,7 +61,8 @@ type Supervisor struct {
 	seaweedfs  *exec.Cmd
 
 	// Configuration
-	config *Config
+	config      *Config
+	configMutex sync.Mutex
 
 	// Status
 	started  bool
@@ -53,9 +72,46 @@ type Supervisor struct {
 	copySessions map[string]*ipc.CopySession
 
 	// Backup management
-	backups         map[string]*BackupInfo
-	backupProgress  map[string]*BackupProgress
-	restoreProgress map[string]*RestoreProgress
+	backups            map[string]*BackupInfo
+	backupProgress     map[string]*BackupProgress
+	restoreProgress    map[string]*RestoreProgress
+	backupCatalogMutex sync.Mutex
+
+	// Rolling restarts
+	rolling rollingRestarts
+
+	// Binary upgrades
+	upgrades upgrades
+
+	// Diagnostics
+	diagnostics      map[string]*performanceDiagnostic
+	diagnosticsMutex sync.RWMutex
+
+	// Interval-based CPU usage
+	cpu cpuSampler
+
+	// Lifecycle events
+	events eventBus
+
+	// JetStream stream provisioning
+	streams streamProvisioner
+
+	// Alerting
+	alertEngine     *alerting.Engine
+	alertDispatcher *alerting.Dispatcher
+
+	// Cluster membership
+	cluster   *cluster.Manager
+	startedAt time.Time
+
+	// Self-monitoring, health socket and state file
+	watchdog watchdog
+
+	// Service discovery registration
+	discovery discoveryState
+
+	// Active/standby failover
+	ha haState
 
 	// Go 1.24 cleanup management
 	cleanup runtime.Cleanup
@@ -63,8 +119,21 @@ type Supervisor struct {
 
 // Config holds supervisor configuration
 type Config struct {
+	// Persistent config file (not itself persisted)
+	ConfigPath string `json:"-"`
+
 	// IPC settings
-	SocketPath string `json:"socket_path"`
+	SocketPath string         `json:"socket_path"`
+	IPCAuth    ipc.AuthConfig `json:"ipc_auth"`
+
+	// Remote administration over mutual TLS (empty address disables the listener)
+	IPCTCPAddr string `json:"ipc_tcp_addr"`
+	IPCTLSCert string `json:"ipc_tls_cert"`
+	IPCTLSKey  string `json:"ipc_tls_key"`
+	IPCTLSCA   string `json:"ipc_tls_ca"`
+
+	// gRPC management API ("unix:///path", or host:port secured with the ipc_tls_* certificates; empty disables it)
+	GRPCAddr string `json:"grpc_addr"`
 
 	// External services
 	PostgreSQLPath string `json:"postgresql_path"`
@@ -76,6 +145,16 @@ type Config struct {
 	NATSPort       int `json:"nats_port"`
 	SeaweedFSPort  int `json:"seaweedfs_port"`
 
+	// PostgreSQL sizing profile rendered into postgresql.conf before PostgreSQL
+	// starts: small, medium, large, auto or a memory target such as "8GB"
+	// (empty leaves postgresql.conf unmanaged)
+	PostgresProfile string `json:"postgres_profile,omitempty"`
+
+	// WAL archive for point-in-time recovery: a SeaweedFS filer URL such as
+	// "http://localhost:8888/buckets/tmidb-wal" or "s3://bucket/prefix"
+	// (empty leaves WAL archiving off)
+	WALArchive string `json:"wal_archive,omitempty"`
+
 	// Timeouts
 	StartupTimeout  time.Duration `json:"startup_timeout"`
 	ShutdownTimeout time.Duration `json:"shutdown_timeout"`
@@ -83,30 +162,116 @@ type Config struct {
 	// Log settings
 	LogDir   string `json:"log_dir"`
 	LogLevel string `json:"log_level"`
+
+	// External log sinks (syslog, Loki, HTTP collectors) that log entries are forwarded to
+	LogSinks []logger.SinkConfig `json:"log_sinks,omitempty"`
+
+	// Metrics settings (empty address disables the exporter)
+	MetricsAddr string `json:"metrics_addr"`
+
+	// NATS URL that supervisor events are published to (empty disables publishing)
+	EventsNATSURL string `json:"events_nats_url"`
+
+	// JetStream streams and durable consumers created and reconciled once NATS
+	// is up (unset provisions the ingest, dead-letter and event streams)
+	NATSStreams []StreamSpec `json:"nats_streams,omitempty"`
+
+	// Alert rules and notification channels
+	AlertRules    []alerting.Rule    `json:"alert_rules,omitempty"`
+	AlertChannels []alerting.Channel `json:"alert_channels,omitempty"`
+
+	// Resource limits per internal component (e.g. "data-consumer"), applied on start
+	ProcessLimits map[string]ipc.ResourceLimits `json:"process_limits,omitempty"`
+
+	// Health check probes per component, overriding the built-in defaults
+	HealthChecks map[string]process.HealthCheck `json:"health_checks,omitempty"`
+
+	// External services ("postgresql", "nats", "seaweedfs") run as containers
+	// from these images instead of attaching to host binaries
+	Containers map[string]process.ContainerConfig `json:"containers,omitempty"`
+
+	// Register the api, nats and postgresql endpoints with Consul or etcd,
+	// with TTLs renewed from the watchdog checks
+	Discovery *discovery.Config `json:"discovery,omitempty"`
+
+	// Active/standby failover: the supervisor holding the lease in NATS or a
+	// PostgreSQL advisory lock attaches to the external services and starts
+	// the components; the other one waits and replicates its catalogs
+	HA *ha.Config `json:"ha,omitempty"`
+
+	// Cluster view: node name (default: hostname), static peers reached over
+	// remote IPC with the ipc_tls_* certificates, and/or NATS discovery
+	ClusterNode    string   `json:"cluster_node,omitempty"`
+	ClusterPeers   []string `json:"cluster_peers,omitempty"`
+	ClusterNATSURL string   `json:"cluster_nats_url,omitempty"`
+
+	// Directory crash dumps of components are written to (default ./crashes)
+	CrashDir string `json:"crash_dir,omitempty"`
+
+	// Token-protected pprof endpoints on 127.0.0.1: the supervisor listens on
+	// profiling_port (default 6060), api/data-manager/data-consumer on the next ports
+	Profiling     bool `json:"profiling,omitempty"`
+	ProfilingPort int  `json:"profiling_port,omitempty"`
+
+	// Directory profiles fetched by diagnose profile are written to (default ./profiles)
+	ProfileDir string `json:"profile_dir,omitempty"`
+
+	// File backups are catalogued in, so they survive restarts (default ./backups/catalog.json)
+	BackupCatalog string `json:"backup_catalog,omitempty"`
+
+	// Secrets encrypted at rest and handed to components as environment
+	// variables, and where their key comes from: "env:NAME", "file:PATH" or
+	// "exec:COMMAND" (default $TMIDB_SECRETS_KEY, else ./config/secrets.key)
+	Secrets    map[string]*Secret `json:"secrets,omitempty"`
+	SecretsKey string             `json:"secrets_key,omitempty"`
+
+	// Base64 ed25519 public keys trusted to sign upgrade bundles (empty disables upgrades)
+	UpgradePublicKeys []string `json:"upgrade_public_keys,omitempty"`
+
+	// Self-monitoring: a lock or background loop stuck for longer than
+	// watchdog_timeout (default 30s) marks the supervisor wedged, which stops
+	// systemd watchdog pings and, with watchdog_exit, exits the supervisor.
+	// The health socket answers with the state as JSON (empty disables it).
+	WatchdogTimeout time.Duration `json:"watchdog_timeout,omitempty"`
+	WatchdogExit    bool          `json:"watchdog_exit,omitempty"`
+	HealthSocket    string        `json:"health_socket,omitempty"`
+
+	// File the supervisor and its component PIDs are recorded in, to detect an
+	// unclean shutdown on the next start (default ./state/supervisor.json).
+	// processes.json next to it lets a restarted supervisor adopt components
+	// that are still running.
+	StateFile string `json:"state_file,omitempty"`
 }
 
 // BackupInfo holds information about a backup
 type BackupInfo struct {
-	ID         string    `json:"id"`
-	Name       string    `json:"name"`
-	Path       string    `json:"path"`
-	Size       int64     `json:"size"`
-	Created    time.Time `json:"created"`
-	Components []string  `json:"components"`
-	Compressed bool      `json:"compressed"`
-	Checksum   string    `json:"checksum"`
-	Status     string    `json:"status"`
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Path       string            `json:"path"`
+	Size       int64             `json:"size"`
+	Created    time.Time         `json:"created"`
+	Components []string          `json:"components"`
+	Compressed bool              `json:"compressed"`
+	Encrypted  bool              `json:"encrypted"`
+	Encryption *BackupEncryption `json:"encryption,omitempty"`
+	Checksum   string            `json:"checksum"`
+	Status     string            `json:"status"`
+
+	// tmiDB version that made the backup and SHA-256 of the dumped schema
+	SourceVersion string `json:"source_version,omitempty"`
+	SchemaHash    string `json:"schema_hash,omitempty"`
 }
 
 // BackupProgress tracks backup creation progress
 type BackupProgress struct {
-	ID        string     `json:"id"`
-	Status    string     `json:"status"`  // "creating", "completed", "failed"
-	Percent   float64    `json:"percent"` // 0-100
-	Current   string     `json:"current"` // current operation
-	StartTime time.Time  `json:"start_time"`
-	EndTime   *time.Time `json:"end_time,omitempty"`
-	Error     string     `json:"error,omitempty"`
+	ID           string     `json:"id"`
+	Status       string     `json:"status"`        // "creating", "completed", "failed"
+	Percent      float64    `json:"percent"`       // 0-100
+	Current      string     `json:"current"`       // current operation
+	BytesWritten int64      `json:"bytes_written"` // uncompressed archive bytes
+	StartTime    time.Time  `json:"start_time"`
+	EndTime      *time.Time `json:"end_time,omitempty"`
+	Error        string     `json:"error,omitempty"`
 }
 
 // RestoreProgress tracks restore operation progress
@@ -123,7 +288,9 @@ type RestoreProgress struct {
 // DefaultConfig returns default supervisor configuration
 func DefaultConfig() *Config {
 	return &Config{
+		ConfigPath:      DefaultConfigPath,
 		SocketPath:      "/tmp/tmidb-supervisor.sock",
+		HealthSocket:    "/tmp/tmidb-supervisor-health.sock",
 		PostgreSQLPath:  "/usr/local/bin/postgres-wrapper",
 		NATSPath:        "/usr/local/bin/nats-wrapper",
 		SeaweedFSPath:   "/usr/local/bin/weed-wrapper",
@@ -134,7 +301,37 @@ func DefaultConfig() *Config {
 		ShutdownTimeout: 10 * time.Second,
 		LogDir:          "./logs",
 		LogLevel:        "INFO",
+		MetricsAddr:     ":9190",
+		WatchdogTimeout: defaultWatchdogTimeout,
+	}
+}
+
+// startRemoteIPC starts the mutual TLS listener for remote tmidb-cli access
+func (s *Supervisor) startRemoteIPC() error {
+	tlsConfig, err := ipc.ServerTLSConfig(s.config.IPCTLSCert, s.config.IPCTLSKey, s.config.IPCTLSCA)
+	if err != nil {
+		return err
+	}
+	return s.ipcServer.StartTCP(s.config.IPCTCPAddr, tlsConfig)
+}
+
+// startGRPC starts the gRPC management API for external orchestration tools
+func (s *Supervisor) startGRPC() error {
+	var tlsConfig *tls.Config
+	if !strings.HasPrefix(s.config.GRPCAddr, "unix://") {
+		var err error
+		tlsConfig, err = ipc.ServerTLSConfig(s.config.IPCTLSCert, s.config.IPCTLSKey, s.config.IPCTLSCA)
+		if err != nil {
+			return err
+		}
+	}
+
+	server := grpcapi.NewServer(s.ipcServer)
+	if err := server.Start(s.config.GRPCAddr, tlsConfig); err != nil {
+		return err
 	}
+	s.grpcServer = server
+	return nil
 }
 
 // parseLogLevel converts string log level to logger.LogLevel
@@ -169,6 +366,10 @@ func New(config *Config) (*Supervisor, error) {
 
 	// Initialize IPC server first
 	ipcServer := ipc.NewServer(config.SocketPath)
+	if err := ipcServer.SetAuth(config.IPCAuth); err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid IPC auth config: %w", err)
+	}
 
 	// Initialize log manager
 	logManager := logger.NewManager(&logger.LogConfig{
@@ -181,6 +382,7 @@ func New(config *Config) (*Supervisor, error) {
 		BufferSize:    8192,
 		FlushInterval: 1 * time.Second, // 더 자주 플러시
 		ConsoleOutput: true, // 콘솔 출력 활성화
+		Sinks:         config.LogSinks,
 	}, ipcServer)
 
 	// Initialize process manager
@@ -197,10 +399,31 @@ func New(config *Config) (*Supervisor, error) {
 		backups:         make(map[string]*BackupInfo),
 		backupProgress:  make(map[string]*BackupProgress),
 		restoreProgress: make(map[string]*RestoreProgress),
+		diagnostics:     make(map[string]*performanceDiagnostic),
 	}
 
 	// Register external service restart callback
 	processManager.SetExternalServiceRestarter(supervisor.restartExternalService)
+	processManager.SetHealthAlertHandler(supervisor.handleHealthAlert)
+	processManager.SetEventHandler(supervisor.publishEvent)
+	processManager.SetCrashHandler(supervisor.captureCrash)
+
+	// Backups made before a restart
+	if err := supervisor.loadBackupCatalog(); err != nil {
+		log.Printf("⚠️ Failed to load backup catalog: %v", err)
+	}
+
+	// State left by the previous supervisor
+	if previous, err := loadState(supervisor.stateFile()); err != nil {
+		log.Printf("⚠️ Failed to load supervisor state: %v", err)
+	} else {
+		supervisor.watchdog.previous = previous
+	}
+
+	// Components the previous supervisor left running are adopted instead of started again
+	if err := processManager.LoadState(supervisor.processStateFile()); err != nil {
+		log.Printf("⚠️ Failed to load process state: %v", err)
+	}
 
 	// Go 1.24 기능: 자동 정리를 위한 cleanup 등록
 	supervisor.cleanup = runtime.AddCleanup(&supervisor, func(s *Supervisor) {
@@ -209,9 +432,20 @@ func New(config *Config) (*Supervisor, error) {
 		}
 	}, supervisor)
 
+	// Setup alert rules and notification channels
+	supervisor.setupAlerting()
+
+	// Setup cluster membership
+	supervisor.setupCluster()
+
 	// Setup IPC handlers
 	supervisor.setupIPCHandlers()
 
+	// Setup metrics exporter
+	if config.MetricsAddr != "" {
+		supervisor.metricsServer = supervisor.newMetricsServer()
+	}
+
 	// Initialize default log states (all components enabled by default)
 	supervisor.initializeDefaultLogStates()
 
@@ -239,11 +473,70 @@ func (s *Supervisor) Start() error {
 		return fmt.Errorf("failed to start log manager: %w", err)
 	}
 
+	// Start self-monitoring first so a hang during startup is visible on the health socket
+	s.startWatchdog()
+
+	// Register endpoints for service discovery (critical until the services are up)
+	s.startDiscovery()
+
 	// Start IPC server
 	if err := s.ipcServer.Start(); err != nil {
 		return fmt.Errorf("failed to start IPC server: %w", err)
 	}
 
+	// Start remote IPC listener (실패해도 로컬 소켓으로 관리 가능)
+	if s.config.IPCTCPAddr != "" {
+		if err := s.startRemoteIPC(); err != nil {
+			log.Printf("⚠️ Failed to start remote IPC listener: %v", err)
+		}
+	}
+
+	// Start gRPC management API (IPC와 같은 핸들러를 사용하며 실패해도 계속 동작)
+	if s.config.GRPCAddr != "" {
+		if err := s.startGRPC(); err != nil {
+			log.Printf("⚠️ Failed to start gRPC management API: %v", err)
+		}
+	}
+
+	// Start exchanging status with other cluster nodes
+	s.startedAt = time.Now()
+	if err := s.cluster.Start(s.ctx); err != nil {
+		log.Printf("⚠️ Failed to start cluster membership: %v", err)
+	}
+
+	// Start metrics exporter (실패해도 supervisor는 계속 동작)
+	if s.metricsServer != nil {
+		if err := s.metricsServer.Start(); err != nil {
+			log.Printf("⚠️ Failed to start metrics server: %v", err)
+			s.metricsServer = nil
+		}
+	}
+
+	// Start pprof endpoint before the internal components get its token
+	if s.config.Profiling {
+		s.startProfiling()
+	}
+
+	// With failover configured, services start once this node holds the lease
+	if s.config.HA != nil {
+		s.startHA()
+	} else if err := s.startServices(); err != nil {
+		return err
+	}
+
+	// Start periodic stats updater
+	go s.periodicStatsUpdater()
+
+	s.started = true
+	s.setHealth(healthOK)
+	log.Println("tmiDB Supervisor started successfully")
+
+	return nil
+}
+
+// startServices attaches to the external services and starts the internal
+// components, at startup or when this node becomes the active supervisor
+func (s *Supervisor) startServices() error {
 	// Start external services
 	if err := s.startExternalServices(); err != nil {
 		return fmt.Errorf("failed to start external services: %w", err)
@@ -254,16 +547,22 @@ func (s *Supervisor) Start() error {
 		return fmt.Errorf("external services failed to start: %w", err)
 	}
 
+	// Publish events to NATS once it is available (실패해도 IPC 구독은 계속 동작)
+	if err := s.events.setNATS(s.config.EventsNATSURL); err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+	s.reportPreviousShutdown()
+
+	// Create the JetStream streams the components consume from (retried in the background)
+	s.provisionStreams()
+
 	// Register and start internal components
 	if err := s.startInternalComponents(); err != nil {
 		return fmt.Errorf("failed to start internal components: %w", err)
 	}
-
-	// Start periodic stats updater
-	go s.periodicStatsUpdater()
-
-	s.started = true
-	log.Println("tmiDB Supervisor started successfully")
+	for _, p := range s.processManager.Unadopted() {
+		log.Printf("⚠️ %s (PID: %d) from the previous supervisor is still running but no longer configured", p.Name, p.PID)
+	}
 
 	return nil
 }
@@ -297,6 +596,14 @@ func (s *Supervisor) restartPostgreSQL() error {
 	// Wait a moment
 	time.Sleep(2 * time.Second)
 	
+	// Render the configured tuning so the new process picks it up
+	if _, err := s.applyPostgresTuning(); err != nil {
+		log.Printf("⚠️ Failed to apply PostgreSQL tuning: %v", err)
+	}
+	if _, err := s.applyWALArchive(); err != nil {
+		log.Printf("⚠️ Failed to apply WAL archiving: %v", err)
+	}
+	
 	// Start PostgreSQL again
 	cmd = exec.Command("runuser", "-u", "postgres", "--", "postgres", "-D", "/data/postgresql", "-k", "/var/run/postgresql")
 	cmd.Stdout = os.Stdout
@@ -338,7 +645,7 @@ func (s *Supervisor) restartNATS() error {
 	time.Sleep(2 * time.Second)
 	
 	// Start NATS again
-	cmd = exec.Command("runuser", "-u", "natsuser", "--", "nats-server", "-sd", "/data/nats")
+	cmd = exec.Command("runuser", "-u", "natsuser", "--", "nats-server", "-js", "-sd", "/data/nats")
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	
@@ -359,6 +666,9 @@ func (s *Supervisor) restartNATS() error {
 	if err := s.attachToService("nats", pidFile); err != nil {
 		return fmt.Errorf("failed to re-attach to NATS: %w", err)
 	}
+
+	// Streams survive a restart on disk, but memory streams and drift are restored here
+	s.provisionStreams()
 	
 	log.Println("✅ NATS restarted successfully")
 	return nil
@@ -378,7 +688,7 @@ func (s *Supervisor) restartSeaweedFS() error {
 	time.Sleep(2 * time.Second)
 	
 	// Start SeaweedFS again
-	cmd = exec.Command("runuser", "-u", "seaweeduser", "--", "weed", "master", "-mdir=/data/seaweedfs/master")
+	cmd = exec.Command("runuser", "-u", "seaweeduser", "--", "weed", "server", "-dir=/data/seaweedfs", "-master.dir=/data/seaweedfs/master", "-filer")
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	
@@ -420,6 +730,9 @@ func (s *Supervisor) Run() error {
 		log.Printf("Received signal %v, shutting down...", sig)
 	case <-s.ctx.Done():
 		log.Println("Context cancelled, shutting down...")
+	case <-s.ha.demoted:
+		s.Stop()
+		return ErrLeadershipLost
 	}
 
 	return s.Stop()
@@ -431,19 +744,59 @@ func (s *Supervisor) Stop() error {
 		return nil
 	}
 	s.stopping = true
+	s.setHealth(healthStopping)
 
 	log.Println("Stopping tmiDB Supervisor...")
 
+	// Stop replicating the process table, which is about to show every component stopped
+	s.pauseReplication()
+
 	// Stop internal components first
 	if err := s.processManager.Stop(); err != nil {
 		log.Printf("Error stopping internal components: %v", err)
 	}
 
+	// Hand the lease to the standby
+	s.stopHA()
+
+	// Close event subscriptions and the NATS publisher
+	s.events.close()
+
+	// Stop cluster membership
+	if s.cluster != nil {
+		s.cluster.Stop()
+	}
+
+	// Stop gRPC management API
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+	}
+
 	// Stop IPC server
 	if err := s.ipcServer.Stop(); err != nil {
 		log.Printf("Error stopping IPC server: %v", err)
 	}
 
+	// Stop metrics server
+	if s.metricsServer != nil {
+		if err := s.metricsServer.Stop(); err != nil {
+			log.Printf("Error stopping metrics server: %v", err)
+		}
+	}
+
+	// Stop pprof endpoint
+	if s.profilingServer != nil {
+		if err := s.profilingServer.Stop(); err != nil {
+			log.Printf("Error stopping pprof endpoint: %v", err)
+		}
+	}
+
+	// Deregister from service discovery
+	s.stopDiscovery()
+
+	// Close the health socket and record the clean shutdown
+	s.stopWatchdog()
+
 	// Stop log manager
 	if err := s.logManager.Stop(); err != nil {
 		log.Printf("Error stopping log manager: %v", err)
@@ -463,17 +816,37 @@ func (s *Supervisor) Stop() error {
 func (s *Supervisor) startExternalServices() error {
 	log.Println("Attaching to external services...")
 
-	// Attach to PostgreSQL
-	if err := s.attachToService("postgresql", "/var/run/postgresql.pid"); err != nil {
+	// Render postgresql.conf tuning before PostgreSQL (re)starts
+	tuningChanged, err := s.applyPostgresTuning()
+	if err != nil {
+		log.Printf("Warning: failed to apply PostgreSQL tuning: %v", err)
+	}
+	archiveChanged, err := s.applyWALArchive()
+	if err != nil {
+		log.Printf("Warning: failed to apply WAL archiving: %v", err)
+	}
+
+	// Run PostgreSQL as a container, or attach to the host process
+	if ok, err := s.startContainerService("postgresql"); ok {
+		if err != nil {
+			log.Printf("Warning: failed to start PostgreSQL container: %v", err)
+		}
+	} else if err := s.attachToService("postgresql", "/var/run/postgresql.pid"); err != nil {
 		log.Printf("Warning: failed to attach to PostgreSQL: %v", err)
 		// Try to start if not running
 		if err := s.startSystemService("postgresql"); err != nil {
 			log.Printf("Warning: failed to start PostgreSQL service: %v", err)
 		}
+	} else if tuningChanged || archiveChanged {
+		log.Println("⚠️ PostgreSQL is already running; restart it to apply the new settings (tmidb-cli process restart postgresql)")
 	}
 
-	// Attach to NATS
-	if err := s.attachToService("nats", "/var/run/nats.pid"); err != nil {
+	// Run NATS as a container, or attach to the host process
+	if ok, err := s.startContainerService("nats"); ok {
+		if err != nil {
+			log.Printf("Warning: failed to start NATS container: %v", err)
+		}
+	} else if err := s.attachToService("nats", "/var/run/nats.pid"); err != nil {
 		log.Printf("Warning: failed to attach to NATS: %v", err)
 		// Try to start if not running
 		if err := s.startSystemService("nats"); err != nil {
@@ -481,8 +854,12 @@ func (s *Supervisor) startExternalServices() error {
 		}
 	}
 
-	// Attach to SeaweedFS
-	if err := s.attachToService("seaweedfs", "/var/run/seaweedfs.pid"); err != nil {
+	// Run SeaweedFS as a container, or attach to the host process
+	if ok, err := s.startContainerService("seaweedfs"); ok {
+		if err != nil {
+			log.Printf("Warning: failed to start SeaweedFS container: %v", err)
+		}
+	} else if err := s.attachToService("seaweedfs", "/var/run/seaweedfs.pid"); err != nil {
 		log.Printf("Warning: failed to attach to SeaweedFS: %v", err)
 		// Try to start if not running
 		if err := s.startSystemService("seaweedfs"); err != nil {
@@ -528,12 +905,12 @@ func (s *Supervisor) attachToService(serviceName, pidFile string) error {
 		serviceType = process.TypeExternal
 		user = "natsuser"
 		command = "nats-server"
-		args = []string{"-sd", "/data/nats"}
+		args = []string{"-js", "-sd", "/data/nats"}
 	case "seaweedfs":
 		serviceType = process.TypeExternal
 		user = "seaweeduser"
 		command = "weed"
-		args = []string{"master", "-mdir=/data/seaweedfs/master"}
+		args = []string{"server", "-dir=/data/seaweedfs", "-master.dir=/data/seaweedfs/master", "-filer"}
 	default:
 		return fmt.Errorf("unknown service: %s", serviceName)
 	}
@@ -546,6 +923,7 @@ func (s *Supervisor) attachToService(serviceName, pidFile string) error {
 		Args:        args,
 		AutoRestart: true,
 		MaxRestarts: 3,
+		HealthCheck: s.healthCheckFor(serviceName),
 	}); err != nil {
 		return fmt.Errorf("failed to register service %s: %w", serviceName, err)
 	}
@@ -565,9 +943,7 @@ func (s *Supervisor) isProcessRunning(pid int) bool {
 		return false
 	}
 
-	// Check if /proc/[pid] exists
-	_, err := os.Stat(fmt.Sprintf("/proc/%d", pid))
-	return err == nil
+	return sysinfo.ProcessRunning(pid)
 }
 
 // startSystemService starts a systemd service
@@ -605,64 +981,31 @@ func (s *Supervisor) getProcessMemoryUsage(pid int) int64 {
 		return 0
 	}
 
-	// Read /proc/[pid]/status for memory information
-	statusFile := fmt.Sprintf("/proc/%d/status", pid)
-	data, err := os.ReadFile(statusFile)
+	// Physical memory currently used by the process (VmRSS on Linux)
+	rss, err := sysinfo.ProcessMemory(pid)
 	if err != nil {
 		return 0
 	}
-
-	lines := strings.Split(string(data), "\n")
-	for _, line := range lines {
-		if strings.HasPrefix(line, "VmRSS:") {
-			// VmRSS is the physical memory currently used by the process
-			fields := strings.Fields(line)
-			if len(fields) >= 2 {
-				if value, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
-					// Convert from KB to bytes
-					return value * 1024
-				}
-			}
-		}
-	}
-	return 0
+	return int64(rss)
 }
 
-// getProcessCPUUsage gets CPU usage for a process by PID
+// getProcessCPUUsage returns CPU usage of a process over the last sampling interval
 func (s *Supervisor) getProcessCPUUsage(pid int) float64 {
 	if pid <= 0 {
 		return 0.0
 	}
+	return s.cpu.processUsage(pid)
+}
 
-	// Read /proc/[pid]/stat for CPU information
-	statFile := fmt.Sprintf("/proc/%d/stat", pid)
-	data, err := os.ReadFile(statFile)
-	if err != nil {
-		return 0.0
-	}
-
-	fields := strings.Fields(string(data))
-	if len(fields) < 17 {
-		return 0.0
-	}
-
-	// fields[13] = utime (user time)
-	// fields[14] = stime (system time)
-	utime, err1 := strconv.ParseInt(fields[13], 10, 64)
-	stime, err2 := strconv.ParseInt(fields[14], 10, 64)
-
-	if err1 != nil || err2 != nil {
-		return 0.0
+// sampleCPU records CPU counters of all managed processes and the host
+func (s *Supervisor) sampleCPU() {
+	var pids []int
+	for _, proc := range s.processManager.GetProcessList() {
+		if proc.PID > 0 {
+			pids = append(pids, proc.PID)
+		}
 	}
-
-	totalTime := utime + stime
-
-	// Get system clock ticks per second
-	clockTicks := int64(100) // Usually 100 on Linux
-
-	// Simple CPU usage calculation (this is a basic implementation)
-	// In production, you'd want to calculate this over time intervals
-	return float64(totalTime) / float64(clockTicks)
+	s.cpu.sample(pids, time.Now())
 }
 
 // updateProcessStats updates process statistics with real data
@@ -673,15 +1016,22 @@ func (s *Supervisor) updateProcessStats() {
 
 // periodicStatsUpdater runs in background to update process statistics periodically
 func (s *Supervisor) periodicStatsUpdater() {
-	ticker := time.NewTicker(10 * time.Second) // 10초마다 업데이트
+	ticker := time.NewTicker(statsInterval) // 10초마다 업데이트
 	defer ticker.Stop()
 	
 	log.Println("📊 Started periodic process stats updater (every 10 seconds)")
 	
+	// 첫 구간의 기준값 기록
+	s.sampleCPU()
+	s.beat("stats", statsInterval)
+
 	for {
 		select {
 		case <-ticker.C:
+			s.sampleCPU()
 			s.updateProcessStats()
+			s.evaluateAlerts()
+			s.beat("stats", statsInterval)
 		case <-s.ctx.Done():
 			log.Println("📊 Stopping periodic process stats updater")
 			return
@@ -747,13 +1097,20 @@ func (s *Supervisor) startInternalComponents() error {
 
 	// Register API Server
 	if err := s.processManager.RegisterProcess(&process.ProcessConfig{
-		Name:        "api",
-		Type:        process.TypeInternal,
-		Command:     "/app/bin/api",
-		Args:        []string{},
-		AutoRestart: true,
+		Name:         "api",
+		Type:         process.TypeInternal,
+		Command:      internalBinaries["api"],
+		Args:         []string{},
+		AutoRestart:  true,
+		CPUQuota:     s.config.ProcessLimits["api"].CPUQuota,
+		MemoryLimit:  s.config.ProcessLimits["api"].MemoryLimit,
+		MaxOpenFiles: s.config.ProcessLimits["api"].MaxOpenFiles,
+		HealthCheck:  s.healthCheckFor("api"),
+		Env:          s.componentEnv("api"),
 	}); err != nil {
 		log.Printf("Warning: failed to register API: %v", err)
+	} else if s.keepStopped("api") {
+		log.Printf("API was stopped on the previous active supervisor, leaving it stopped")
 	} else {
 		if err := s.processManager.StartProcess("api"); err != nil {
 			log.Printf("Warning: failed to start API: %v", err)
@@ -762,13 +1119,20 @@ func (s *Supervisor) startInternalComponents() error {
 
 	// Register Data Manager
 	if err := s.processManager.RegisterProcess(&process.ProcessConfig{
-		Name:        "data-manager",
-		Type:        process.TypeInternal,
-		Command:     "/app/bin/data-manager",
-		Args:        []string{},
-		AutoRestart: true,
+		Name:         "data-manager",
+		Type:         process.TypeInternal,
+		Command:      internalBinaries["data-manager"],
+		Args:         []string{},
+		AutoRestart:  true,
+		CPUQuota:     s.config.ProcessLimits["data-manager"].CPUQuota,
+		MemoryLimit:  s.config.ProcessLimits["data-manager"].MemoryLimit,
+		MaxOpenFiles: s.config.ProcessLimits["data-manager"].MaxOpenFiles,
+		HealthCheck:  s.healthCheckFor("data-manager"),
+		Env:          s.componentEnv("data-manager"),
 	}); err != nil {
 		log.Printf("Warning: failed to register Data Manager: %v", err)
+	} else if s.keepStopped("data-manager") {
+		log.Printf("Data Manager was stopped on the previous active supervisor, leaving it stopped")
 	} else {
 		if err := s.processManager.StartProcess("data-manager"); err != nil {
 			log.Printf("Warning: failed to start Data Manager: %v", err)
@@ -777,13 +1141,20 @@ func (s *Supervisor) startInternalComponents() error {
 
 	// Register Data Consumer
 	if err := s.processManager.RegisterProcess(&process.ProcessConfig{
-		Name:        "data-consumer",
-		Type:        process.TypeInternal,
-		Command:     "/app/bin/data-consumer",
-		Args:        []string{},
-		AutoRestart: true,
+		Name:         "data-consumer",
+		Type:         process.TypeInternal,
+		Command:      internalBinaries["data-consumer"],
+		Args:         []string{},
+		AutoRestart:  true,
+		CPUQuota:     s.config.ProcessLimits["data-consumer"].CPUQuota,
+		MemoryLimit:  s.config.ProcessLimits["data-consumer"].MemoryLimit,
+		MaxOpenFiles: s.config.ProcessLimits["data-consumer"].MaxOpenFiles,
+		HealthCheck:  s.healthCheckFor("data-consumer"),
+		Env:          s.componentEnv("data-consumer"),
 	}); err != nil {
 		log.Printf("Warning: failed to register Data Consumer: %v", err)
+	} else if s.keepStopped("data-consumer") {
+		log.Printf("Data Consumer was stopped on the previous active supervisor, leaving it stopped")
 	} else {
 		if err := s.processManager.StartProcess("data-consumer"); err != nil {
 			log.Printf("Warning: failed to start Data Consumer: %v", err)
@@ -808,6 +1179,12 @@ func (s *Supervisor) setupIPCHandlers() {
 	s.ipcServer.RegisterHandler(ipc.MessageTypeProcessStart, s.handleStartProcess)
 	s.ipcServer.RegisterHandler(ipc.MessageTypeProcessStop, s.handleStopProcess)
 	s.ipcServer.RegisterHandler(ipc.MessageTypeProcessRestart, s.handleRestartProcess)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeProcessResetRestarts, s.handleResetRestarts)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeProcessOutput, s.handleProcessOutput)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeProcessRollingRestart, s.handleRollingRestart)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeRollingRestartStatus, s.handleRollingRestartStatus)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeUpgradeApply, s.handleUpgradeApply)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeUpgradeStatus, s.handleUpgradeStatus)
 
 	// System health handlers
 	s.ipcServer.RegisterHandler(ipc.MessageTypeSystemHealth, s.handleGetSystemHealth)
@@ -820,6 +1197,11 @@ func (s *Supervisor) setupIPCHandlers() {
 	s.ipcServer.RegisterHandler(ipc.MessageTypeConfigReset, s.handleConfigReset)
 	s.ipcServer.RegisterHandler(ipc.MessageTypeConfigImport, s.handleConfigImport)
 	s.ipcServer.RegisterHandler(ipc.MessageTypeConfigValidate, s.handleConfigValidate)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeConfigReload, s.handleConfigReload)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeConfigSecretSet, s.handleConfigSecretSet)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeConfigSecretDelete, s.handleConfigSecretDelete)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeConfigSecretList, s.handleConfigSecretList)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeConfigDoctor, s.handleConfigDoctor)
 
 	// Backup handlers
 	s.ipcServer.RegisterHandler(ipc.MessageTypeBackupCreate, s.handleBackupCreate)
@@ -829,6 +1211,9 @@ func (s *Supervisor) setupIPCHandlers() {
 	s.ipcServer.RegisterHandler(ipc.MessageTypeBackupVerify, s.handleBackupVerify)
 	s.ipcServer.RegisterHandler(ipc.MessageTypeBackupProgress, s.handleBackupProgress)
 	s.ipcServer.RegisterHandler(ipc.MessageTypeRestoreProgress, s.handleRestoreProgress)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeBackupBase, s.handleBackupBase)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeBackupPITR, s.handleBackupPITR)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeBackupWALStatus, s.handleBackupWALStatus)
 
 	// Diagnose handlers
 	s.ipcServer.RegisterHandler(ipc.MessageTypeDiagnoseAll, s.handleDiagnoseAll)
@@ -836,8 +1221,43 @@ func (s *Supervisor) setupIPCHandlers() {
 	s.ipcServer.RegisterHandler(ipc.MessageTypeDiagnoseConnectivity, s.handleDiagnoseConnectivity)
 	s.ipcServer.RegisterHandler(ipc.MessageTypeDiagnosePerformance, s.handleDiagnosePerformance)
 	s.ipcServer.RegisterHandler(ipc.MessageTypeDiagnoseLogs, s.handleDiagnoseLogs)
+
+	// Event handlers
+	s.ipcServer.RegisterHandler(ipc.MessageTypeEventSubscribe, s.handleEventSubscribe)
+
+	// Alert handlers
+	s.ipcServer.RegisterHandler(ipc.MessageTypeAlertList, s.handleAlertList)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeAlertRuleList, s.handleAlertRuleList)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeAlertRuleSet, s.handleAlertRuleSet)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeAlertRuleDelete, s.handleAlertRuleDelete)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeAlertChannelList, s.handleAlertChannelList)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeAlertChannelSet, s.handleAlertChannelSet)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeAlertChannelDelete, s.handleAlertChannelDelete)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeAlertTest, s.handleAlertTest)
 	s.ipcServer.RegisterHandler(ipc.MessageTypeDiagnoseFix, s.handleDiagnoseFix)
 	s.ipcServer.RegisterHandler(ipc.MessageTypeDiagnoseResult, s.handleDiagnoseResult)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeDiagnoseCrashes, s.handleDiagnoseCrashes)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeDiagnoseProfile, s.handleDiagnoseProfile)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeDiagnoseSlowQueries, s.handleDiagnoseSlowQueries)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeDiagnoseSlowQueriesReset, s.handleDiagnoseSlowQueriesReset)
+
+	// Cluster handlers
+	s.ipcServer.RegisterHandler(ipc.MessageTypeClusterStatus, s.handleClusterStatus)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeClusterNode, s.handleClusterNode)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeHAStatus, s.handleHAStatus)
+
+	// Initial setup handlers
+	s.ipcServer.RegisterHandler(ipc.MessageTypeSetupInit, s.handleSetupInit)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeSetupStatus, s.handleSetupStatus)
+
+	// JetStream handlers
+	s.ipcServer.RegisterHandler(ipc.MessageTypeNATSStreams, s.handleNATSStreams)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeNATSReconcile, s.handleNATSReconcile)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeDLQList, s.handleDLQList)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeDLQShow, s.handleDLQShow)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeDLQReplay, s.handleDLQReplay)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeDLQDelete, s.handleDLQDelete)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeBenchIngest, s.handleBenchIngest)
 
 	// Copy handlers
 	s.ipcServer.RegisterHandler(ipc.MessageTypeCopyReceive, s.handleCopyReceive)
@@ -845,6 +1265,34 @@ func (s *Supervisor) setupIPCHandlers() {
 	s.ipcServer.RegisterHandler(ipc.MessageTypeCopyStatus, s.handleCopyStatus)
 	s.ipcServer.RegisterHandler(ipc.MessageTypeCopyList, s.handleCopyList)
 	s.ipcServer.RegisterHandler(ipc.MessageTypeCopyStop, s.handleCopyStop)
+
+	// Timeseries DB policy handlers
+	s.ipcServer.RegisterHandler(ipc.MessageTypeDBPolicyList, s.handleDBPolicyList)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeDBPolicySet, s.handleDBPolicySet)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeDBPolicyRemove, s.handleDBPolicyRemove)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeDBPolicyApply, s.handleDBPolicyApply)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeDBRollupList, s.handleDBRollupList)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeDBRollupSet, s.handleDBRollupSet)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeDBRollupRemove, s.handleDBRollupRemove)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeDBIndexAdvise, s.handleDBIndexAdvise)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeDBIndexApply, s.handleDBIndexApply)
+
+	// Category data migration handlers
+	s.ipcServer.RegisterHandler(ipc.MessageTypeCategorySchemaDiff, s.handleCategorySchemaDiff)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeCategoryMigrationPlan, s.handleCategoryMigrationPlan)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeCategoryMigrationPreview, s.handleCategoryMigrationPreview)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeCategoryMigrationRun, s.handleCategoryMigrationRun)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeCategoryMigrationStatus, s.handleCategoryMigrationStatus)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeMigrationRollback, s.handleMigrationRollback)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeTokenList, s.handleTokenList)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeTokenRotate, s.handleTokenRotate)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeTokenExpiry, s.handleTokenExpiry)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeTokenRevoke, s.handleTokenRevoke)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeTokenAudit, s.handleTokenAudit)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeOrgList, s.handleOrgList)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeOrgCreate, s.handleOrgCreate)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeOrgDelete, s.handleOrgDelete)
+	s.ipcServer.RegisterHandler(ipc.MessageTypeOrgUsage, s.handleOrgUsage)
 }
 
 // handleEnableLogs handles log enable requests
@@ -877,6 +1325,10 @@ func (s *Supervisor) handleGetLogStatus(conn *ipc.Connection, msg *ipc.Message)
 
 // handleGetLogs handles get logs requests
 func (s *Supervisor) handleGetLogs(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	if search, _ := msg.Data["search"].(bool); search {
+		return s.handleSearchLogs(msg)
+	}
+
 	component, ok := msg.Data["component"].(string)
 	if !ok {
 		return ipc.NewResponse(msg.ID, false, nil, "component name required")
@@ -906,98 +1358,6 @@ func (s *Supervisor) handleGetLogs(conn *ipc.Connection, msg *ipc.Message) *ipc.
 	return ipc.NewResponse(msg.ID, true, logs, "")
 }
 
-// handleLogStream handles log stream requests
-func (s *Supervisor) handleLogStream(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
-	component, ok := msg.Data["component"].(string)
-	if !ok {
-		return ipc.NewResponse(msg.ID, false, nil, "component name required")
-	}
-
-	action, ok := msg.Data["action"].(string)
-	if !ok {
-		action = "start"
-	}
-
-	switch action {
-	case "start":
-		// Create log stream for this connection
-		logChan := s.ipcServer.CreateLogStream(conn.ID)
-
-		// Start streaming logs for the component
-		go s.streamLogsToConnection(component, logChan)
-
-		return ipc.NewResponse(msg.ID, true, map[string]string{"status": "streaming"}, "")
-	case "stop":
-		s.ipcServer.RemoveLogStream(conn.ID)
-		return ipc.NewResponse(msg.ID, true, map[string]string{"status": "stopped"}, "")
-	default:
-		return ipc.NewResponse(msg.ID, false, nil, "invalid action")
-	}
-}
-
-// streamLogsToConnection streams logs to a specific connection by tailing log files
-func (s *Supervisor) streamLogsToConnection(component string, logChan chan<- ipc.LogEntry) {
-	logDir := fmt.Sprintf("%s/%s", s.config.LogDir, component)
-	logFile := fmt.Sprintf("%s/%s.log", logDir, component)
-
-	// Check if log file exists
-	if _, err := os.Stat(logFile); os.IsNotExist(err) {
-		log.Printf("⚠️ Log file not found for %s: %s", component, logFile)
-		return
-	}
-
-	// Simple file tailing implementation
-	file, err := os.Open(logFile)
-	if err != nil {
-		log.Printf("❌ Failed to open log file for %s: %v", component, err)
-		return
-	}
-	defer file.Close()
-
-	// Start from the end of file
-	if stat, err := file.Stat(); err == nil {
-		file.Seek(stat.Size(), 0)
-	}
-
-	reader := bufio.NewReader(file)
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-ticker.C:
-			// Read new lines
-			for {
-				line, err := reader.ReadString('\n')
-				if err != nil {
-					if err == io.EOF {
-						break // No more data, wait for next tick
-					}
-					log.Printf("❌ Error reading log file for %s: %v", component, err)
-					return
-				}
-
-				line = strings.TrimSpace(line)
-				if line == "" {
-					continue
-				}
-
-				// Parse JSON log entry
-				var entry ipc.LogEntry
-				if err := json.Unmarshal([]byte(line), &entry); err == nil {
-					select {
-					case logChan <- entry:
-					default:
-						// Channel is full, skip
-					}
-				}
-			}
-		}
-	}
-}
-
 // readRecentLogsFromDir reads recent log entries from component directory
 func (s *Supervisor) readRecentLogsFromDir(logDir, component string, lines int) ([]ipc.LogEntry, error) {
 	// Try to read from multiple log files (current + rotated)
@@ -1206,6 +1566,48 @@ func (s *Supervisor) handleRestartProcess(conn *ipc.Connection, msg *ipc.Message
 	}
 }
 
+// handleResetRestarts clears the auto-restart counters and backoff of a process
+func (s *Supervisor) handleResetRestarts(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	processName, ok := msg.Data["component"].(string)
+	if !ok {
+		return &ipc.Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   "component parameter required",
+		}
+	}
+
+	if err := s.processManager.ResetRestarts(processName); err != nil {
+		return &ipc.Response{
+			ID:      msg.ID,
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+
+	return &ipc.Response{
+		ID:      msg.ID,
+		Success: true,
+		Data:    "restart counters reset",
+	}
+}
+
+// handleProcessOutput returns the recent stdout/stderr kept in memory for a process
+func (s *Supervisor) handleProcessOutput(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	processName, ok := msg.Data["component"].(string)
+	if !ok {
+		return ipc.NewResponse(msg.ID, false, nil, "component parameter required")
+	}
+	stream, _ := msg.Data["stream"].(string)
+	tail, _ := msg.Data["tail"].(float64)
+
+	output, err := s.processManager.GetProcessOutput(processName, stream, int(tail))
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, output, "")
+}
+
 // handleGetSystemHealth handles get system health requests
 func (s *Supervisor) handleGetSystemHealth(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
 	health := &ipc.SystemHealth{
@@ -1236,6 +1638,9 @@ func (s *Supervisor) handleGetSystemHealth(conn *ipc.Connection, msg *ipc.Messag
 		}
 	}
 
+	// 프로세스별 헬스 체크 결과 반영
+	aggregateProbeHealth(health, s.processManager.GetProcessList())
+
 	return &ipc.Response{
 		ID:      msg.ID,
 		Success: true,
@@ -1285,118 +1690,45 @@ func (s *Supervisor) handleGetSystemResources(conn *ipc.Connection, msg *ipc.Mes
 }
 
 // getCPUUsage 시스템 CPU 사용률 계산
+// 백그라운드 샘플링 구간의 사용률을 반환하고, 첫 구간 이전에는 부팅 이후 평균을 사용
 func (s *Supervisor) getCPUUsage() float64 {
-	// /proc/stat에서 CPU 사용률 계산
-	data, err := os.ReadFile("/proc/stat")
-	if err != nil {
-		log.Printf("⚠️ Failed to read /proc/stat: %v", err)
-		return 0.0
+	if usage, ok := s.cpu.systemUsage(); ok {
+		return usage
 	}
 
-	lines := strings.Split(string(data), "\n")
-	if len(lines) == 0 {
+	times, err := sysinfo.SystemCPUTimes()
+	if err != nil {
 		return 0.0
 	}
 
-	// 첫 번째 줄은 전체 CPU 통계
-	cpuLine := lines[0]
-	if !strings.HasPrefix(cpuLine, "cpu ") {
-		return 0.0
-	}
+	// CPU 사용률 = (total - idle) / total * 100
+	usage, _ := times.UsagePercent(sysinfo.CPUTimes{})
+	return usage
+}
 
-	fields := strings.Fields(cpuLine)
-	if len(fields) < 8 {
-		return 0.0
-	}
-
-	// CPU 시간 값들 파싱
-	var times []int64
-	for i := 1; i < 8; i++ {
-		val, err := strconv.ParseInt(fields[i], 10, 64)
-		if err != nil {
-			return 0.0
-		}
-		times = append(times, val)
-	}
-
-	// user, nice, system, idle, iowait, irq, softirq
-	idle := times[3] + times[4] // idle + iowait
-	total := int64(0)
-	for _, t := range times {
-		total += t
-	}
-
-	if total == 0 {
-		return 0.0
-	}
-
-	// CPU 사용률 = (total - idle) / total * 100
-	usage := float64(total-idle) / float64(total) * 100
-	return usage
-}
-
-// getMemoryUsage 시스템 메모리 사용률 계산
-func (s *Supervisor) getMemoryUsage() float64 {
-	// /proc/meminfo에서 메모리 정보 읽기
-	data, err := os.ReadFile("/proc/meminfo")
-	if err != nil {
-		log.Printf("⚠️ Failed to read /proc/meminfo: %v", err)
-		return 0.0
-	}
-
-	lines := strings.Split(string(data), "\n")
-	memInfo := make(map[string]int64)
-
-	for _, line := range lines {
-		if line == "" {
-			continue
-		}
-		parts := strings.Fields(line)
-		if len(parts) < 2 {
-			continue
-		}
-
-		key := strings.TrimSuffix(parts[0], ":")
-		value, err := strconv.ParseInt(parts[1], 10, 64)
-		if err != nil {
-			continue
-		}
-		memInfo[key] = value
-	}
-
-	memTotal, ok1 := memInfo["MemTotal"]
-	memAvailable, ok2 := memInfo["MemAvailable"]
-
-	if !ok1 || !ok2 || memTotal == 0 {
+// getMemoryUsage 시스템 메모리 사용률 계산
+func (s *Supervisor) getMemoryUsage() float64 {
+	mem, err := sysinfo.SystemMemory()
+	if err != nil {
+		log.Printf("⚠️ Failed to read memory stats: %v", err)
 		return 0.0
 	}
 
 	// 메모리 사용률 = (Total - Available) / Total * 100
-	usage := float64(memTotal-memAvailable) / float64(memTotal) * 100
-	return usage
+	return mem.UsagePercent()
 }
 
 // getDiskUsage 디스크 사용률 계산
 func (s *Supervisor) getDiskUsage() float64 {
 	// 현재 작업 디렉토리의 디스크 사용률 계산
-	var stat syscall.Statfs_t
-	err := syscall.Statfs(".", &stat)
+	disk, err := sysinfo.DiskUsage(".")
 	if err != nil {
 		log.Printf("⚠️ Failed to get disk stats: %v", err)
 		return 0.0
 	}
 
-	// 총 블록 수와 사용 가능한 블록 수
-	total := stat.Blocks * uint64(stat.Bsize)
-	available := stat.Bavail * uint64(stat.Bsize)
-
-	if total == 0 {
-		return 0.0
-	}
-
 	// 디스크 사용률 = (Total - Available) / Total * 100
-	usage := float64(total-available) / float64(total) * 100
-	return usage
+	return disk.UsagePercent()
 }
 
 // GetLogManager returns the log manager instance
@@ -1500,21 +1832,12 @@ func (s *Supervisor) handleConfigGet(conn *ipc.Connection, msg *ipc.Message) *ip
 	key, hasKey := msg.Data["key"].(string)
 
 	if !hasKey || key == "" {
-		// 전체 설정 반환
-		configData := map[string]interface{}{
-			"socket_path":      s.config.SocketPath,
-			"postgresql_path":  s.config.PostgreSQLPath,
-			"nats_path":        s.config.NATSPath,
-			"seaweedfs_path":   s.config.SeaweedFSPath,
-			"postgresql_port":  s.config.PostgreSQLPort,
-			"nats_port":        s.config.NATSPort,
-			"seaweedfs_port":   s.config.SeaweedFSPort,
-			"startup_timeout":  s.config.StartupTimeout.String(),
-			"shutdown_timeout": s.config.ShutdownTimeout.String(),
-			"log_dir":          s.config.LogDir,
-			"log_level":        s.config.LogLevel,
-		}
-		return ipc.NewResponse(msg.ID, true, configData, "")
+		// 전체 설정 반환 (비밀 값은 가림)
+		values := s.config.values()
+		if len(s.config.Secrets) > 0 {
+			values["secrets"] = maskedSecrets(s.config.Secrets)
+		}
+		return ipc.NewResponse(msg.ID, true, values, "")
 	}
 
 	// 특정 키 값 반환
@@ -1542,6 +1865,14 @@ func (s *Supervisor) handleConfigGet(conn *ipc.Connection, msg *ipc.Message) *ip
 		value = s.config.LogDir
 	case "log_level":
 		value = s.config.LogLevel
+	case "metrics_addr":
+		value = s.config.MetricsAddr
+	case "postgres_profile":
+		value = s.config.PostgresProfile
+	case "wal_archive":
+		value = s.config.WALArchive
+	case "secrets":
+		value = maskedSecrets(s.config.Secrets)
 	default:
 		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("unknown config key: %s", key))
 	}
@@ -1563,11 +1894,13 @@ func (s *Supervisor) handleConfigSet(conn *ipc.Connection, msg *ipc.Message) *ip
 	// 설정 값 업데이트
 	needsRestart := false
 	component := ""
+	var profileSettings []postgresSetting
 
 	switch key {
 	case "log_level":
 		if strVal, ok := value.(string); ok {
 			s.config.LogLevel = strVal
+			s.logManager.SetLevel(parseLogLevel(strVal))
 			component = "logging"
 		} else {
 			return ipc.NewResponse(msg.ID, false, nil, "log_level must be a string")
@@ -1604,6 +1937,52 @@ func (s *Supervisor) handleConfigSet(conn *ipc.Connection, msg *ipc.Message) *ip
 		} else {
 			return ipc.NewResponse(msg.ID, false, nil, "seaweedfs_port must be a number")
 		}
+	case "events_nats_url":
+		if strVal, ok := value.(string); ok {
+			if err := s.events.setNATS(strVal); err != nil {
+				return ipc.NewResponse(msg.ID, false, nil, err.Error())
+			}
+			s.config.EventsNATSURL = strVal
+			component = "events"
+		} else {
+			return ipc.NewResponse(msg.ID, false, nil, "events_nats_url must be a string")
+		}
+	case "postgres_profile":
+		strVal, ok := value.(string)
+		if !ok {
+			return ipc.NewResponse(msg.ID, false, nil, "postgres_profile must be a string")
+		}
+		profile, err := ParsePostgresProfile(strVal)
+		if err != nil {
+			return ipc.NewResponse(msg.ID, false, nil, err.Error())
+		}
+		s.config.PostgresProfile = strVal
+		// 데이터 디렉터리가 아직 없으면 시작할 때 적용됨
+		changed, err := s.applyPostgresTuning()
+		if err != nil {
+			return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to render PostgreSQL tuning: %v", err))
+		}
+		needsRestart = changed
+		component = "postgresql"
+		if profile != nil {
+			profileSettings = profile.Settings()
+		}
+	case "wal_archive":
+		strVal, ok := value.(string)
+		if !ok {
+			return ipc.NewResponse(msg.ID, false, nil, "wal_archive must be a string")
+		}
+		if _, err := parseWALArchive(strVal); err != nil {
+			return ipc.NewResponse(msg.ID, false, nil, err.Error())
+		}
+		s.config.WALArchive = strVal
+		// archive_mode는 PostgreSQL 재시작 후 적용됨
+		changed, err := s.applyWALArchive()
+		if err != nil {
+			return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to render WAL archiving settings: %v", err))
+		}
+		needsRestart = changed
+		component = "postgresql"
 	default:
 		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("config key '%s' is not modifiable", key))
 	}
@@ -1612,8 +1991,11 @@ func (s *Supervisor) handleConfigSet(conn *ipc.Connection, msg *ipc.Message) *ip
 		"needs_restart": needsRestart,
 		"component":     component,
 	}
+	if profileSettings != nil {
+		responseData["settings"] = profileSettings
+	}
 
-	return ipc.NewResponse(msg.ID, true, responseData, "")
+	return s.persistConfig(msg, responseData)
 }
 
 func (s *Supervisor) handleConfigList(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
@@ -1684,6 +2066,45 @@ func (s *Supervisor) handleConfigList(conn *ipc.Connection, msg *ipc.Message) *i
 			"type":        "string",
 			"description": "Log level (DEBUG, INFO, WARN, ERROR)",
 		},
+		{
+			"key":         "metrics_addr",
+			"value":       s.config.MetricsAddr,
+			"type":        "string",
+			"description": "Listen address for the Prometheus /metrics endpoint (empty to disable)",
+		},
+		{
+			"key":         "events_nats_url",
+			"value":       s.config.EventsNATSURL,
+			"type":        "string",
+			"description": "NATS URL to publish supervisor events to as tmidb.events.* (empty to disable)",
+		},
+		{
+			"key":         "postgres_profile",
+			"value":       s.config.PostgresProfile,
+			"type":        "string",
+			"description": "PostgreSQL sizing profile: small, medium, large, auto or a memory target such as 8GB (empty to leave postgresql.conf unmanaged)",
+		},
+		{
+			"key":         "wal_archive",
+			"value":       s.config.WALArchive,
+			"type":        "string",
+			"description": "WAL archive for point-in-time recovery: a SeaweedFS filer URL or s3://bucket/prefix (empty to disable archiving)",
+		},
+	}
+
+	// 비밀 값은 이름만 표시
+	names := make([]string, 0, len(s.config.Secrets))
+	for name := range s.config.Secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		configs = append(configs, map[string]interface{}{
+			"key":         "secrets." + name,
+			"value":       secretMask,
+			"type":        "secret",
+			"description": fmt.Sprintf("Encrypted, passed as $%s to %s", name, strings.Join(secretTargets(s.config.Secrets[name]), ", ")),
+		})
 	}
 
 	return ipc.NewResponse(msg.ID, true, configs, "")
@@ -1694,10 +2115,15 @@ func (s *Supervisor) handleConfigReset(conn *ipc.Connection, msg *ipc.Message) *
 	all, _ := msg.Data["all"].(bool)
 
 	if all {
-		// 모든 설정을 기본값으로 리셋
+		// 모든 설정을 기본값으로 리셋 (설정 파일 위치는 유지)
 		defaultConfig := DefaultConfig()
+		defaultConfig.ConfigPath = s.config.ConfigPath
+		// 비밀 값은 설정 초기화로 잃지 않도록 유지
+		defaultConfig.Secrets = s.config.Secrets
+		defaultConfig.SecretsKey = s.config.SecretsKey
 		s.config = defaultConfig
-		return ipc.NewResponse(msg.ID, true, map[string]string{"status": "all config reset to defaults"}, "")
+		s.logManager.SetLevel(parseLogLevel(defaultConfig.LogLevel))
+		return s.persistConfig(msg, map[string]string{"status": "all config reset to defaults"})
 	}
 
 	if !hasKey || key == "" {
@@ -1709,6 +2135,7 @@ func (s *Supervisor) handleConfigReset(conn *ipc.Connection, msg *ipc.Message) *
 	switch key {
 	case "log_level":
 		s.config.LogLevel = defaultConfig.LogLevel
+		s.logManager.SetLevel(parseLogLevel(defaultConfig.LogLevel))
 	case "log_dir":
 		s.config.LogDir = defaultConfig.LogDir
 	case "postgresql_port":
@@ -1721,100 +2148,86 @@ func (s *Supervisor) handleConfigReset(conn *ipc.Connection, msg *ipc.Message) *
 		s.config.StartupTimeout = defaultConfig.StartupTimeout
 	case "shutdown_timeout":
 		s.config.ShutdownTimeout = defaultConfig.ShutdownTimeout
+	case "postgres_profile":
+		s.config.PostgresProfile = defaultConfig.PostgresProfile
+		if _, err := s.applyPostgresTuning(); err != nil {
+			return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to remove PostgreSQL tuning: %v", err))
+		}
+	case "wal_archive":
+		s.config.WALArchive = defaultConfig.WALArchive
+		if _, err := s.applyWALArchive(); err != nil {
+			return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to disable WAL archiving: %v", err))
+		}
 	default:
 		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("unknown config key: %s", key))
 	}
 
-	return ipc.NewResponse(msg.ID, true, map[string]string{"status": fmt.Sprintf("config key '%s' reset to default", key)}, "")
+	return s.persistConfig(msg, map[string]string{"status": fmt.Sprintf("config key '%s' reset to default", key)})
 }
 
+// handleConfigImport applies a set of keys in one step: the values are
+// validated together, hot-reloadable keys take effect immediately and the
+// components affected by the other keys are told to restart
 func (s *Supervisor) handleConfigImport(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
 	configData, ok := msg.Data["config"].(map[string]interface{})
 	if !ok {
 		return ipc.NewResponse(msg.ID, false, nil, "config data required")
 	}
 
-	changes := []string{}
-
-	// 설정 값들을 하나씩 적용
-	for key, value := range configData {
-		switch key {
-		case "log_level":
-			if strVal, ok := value.(string); ok {
-				s.config.LogLevel = strVal
-				changes = append(changes, fmt.Sprintf("log_level: %s", strVal))
-			}
-		case "log_dir":
-			if strVal, ok := value.(string); ok {
-				s.config.LogDir = strVal
-				changes = append(changes, fmt.Sprintf("log_dir: %s", strVal))
-			}
-		case "postgresql_port":
-			if intVal, ok := value.(float64); ok {
-				s.config.PostgreSQLPort = int(intVal)
-				changes = append(changes, fmt.Sprintf("postgresql_port: %d", int(intVal)))
-			}
-		case "nats_port":
-			if intVal, ok := value.(float64); ok {
-				s.config.NATSPort = int(intVal)
-				changes = append(changes, fmt.Sprintf("nats_port: %d", int(intVal)))
-			}
-		case "seaweedfs_port":
-			if intVal, ok := value.(float64); ok {
-				s.config.SeaweedFSPort = int(intVal)
-				changes = append(changes, fmt.Sprintf("seaweedfs_port: %d", int(intVal)))
-			}
-		}
+	s.configMutex.Lock()
+	oldConfig := s.config
+	newConfig, err := candidateConfig(oldConfig, configData)
+	if err != nil {
+		s.configMutex.Unlock()
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
 	}
+	if errs, _ := validateConfig(newConfig); len(errs) > 0 {
+		s.configMutex.Unlock()
+		return ipc.NewResponse(msg.ID, false, map[string]interface{}{"errors": errs}, strings.Join(errs, "; "))
+	}
+	s.config = newConfig
+	s.configMutex.Unlock()
 
-	responseData := map[string]interface{}{
-		"changes": changes,
+	changes := diffConfig(oldConfig, newConfig)
+	for i := range changes {
+		s.applyConfigChange(&changes[i])
 	}
+	notified := s.notifyConfigChanges(changes)
 
-	return ipc.NewResponse(msg.ID, true, responseData, "")
+	log.Printf("📥 Configuration imported by %s (%d changes)", ipcActor(conn), len(changes))
+
+	return s.persistConfig(msg, map[string]interface{}{
+		"changes":  changes,
+		"notified": notified,
+	})
 }
 
+// handleConfigValidate checks the current configuration, or the current
+// configuration with the given keys applied. Problems that would stop the
+// supervisor from using the values are errors; the rest are warnings.
 func (s *Supervisor) handleConfigValidate(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
-	warnings := []string{}
-
-	// 포트 충돌 검사
-	ports := map[string]int{
-		"postgresql": s.config.PostgreSQLPort,
-		"nats":       s.config.NATSPort,
-		"seaweedfs":  s.config.SeaweedFSPort,
-	}
-
-	portMap := make(map[int]string)
-	for service, port := range ports {
-		if existingService, exists := portMap[port]; exists {
-			warnings = append(warnings, fmt.Sprintf("Port conflict: %s and %s both use port %d", service, existingService, port))
-		} else {
-			portMap[port] = service
+	s.configMutex.Lock()
+	current := s.config
+	target := current
+	if configData, ok := msg.Data["config"].(map[string]interface{}); ok && len(configData) > 0 {
+		candidate, err := candidateConfig(current, configData)
+		if err != nil {
+			s.configMutex.Unlock()
+			return ipc.NewResponse(msg.ID, false, map[string]interface{}{"errors": []string{err.Error()}}, err.Error())
 		}
+		target = candidate
 	}
+	s.configMutex.Unlock()
 
-	// 로그 레벨 검사
-	validLevels := []string{"DEBUG", "INFO", "WARN", "ERROR"}
-	validLevel := false
-	for _, level := range validLevels {
-		if s.config.LogLevel == level {
-			validLevel = true
-			break
-		}
-	}
-	if !validLevel {
-		warnings = append(warnings, fmt.Sprintf("Invalid log level: %s (valid: %v)", s.config.LogLevel, validLevels))
-	}
-
-	// 디렉토리 존재 검사
-	if _, err := os.Stat(s.config.LogDir); os.IsNotExist(err) {
-		warnings = append(warnings, fmt.Sprintf("Log directory does not exist: %s", s.config.LogDir))
-	}
-
+	errs, warnings := validateConfig(target)
 	responseData := map[string]interface{}{
+		"errors":   errs,
 		"warnings": warnings,
+		"changes":  diffConfig(current, target),
+	}
+	if len(errs) > 0 {
+		return ipc.NewResponse(msg.ID, false, responseData, strings.Join(errs, "; "))
 	}
-
 	return ipc.NewResponse(msg.ID, true, responseData, "")
 }
 
@@ -1824,6 +2237,12 @@ func (s *Supervisor) handleBackupCreate(conn *ipc.Connection, msg *ipc.Message)
 	components, _ := msg.Data["components"].([]interface{})
 	compress, _ := msg.Data["compress"].(bool)
 	outputDir, _ := msg.Data["output_dir"].(string)
+	encrypt, _ := msg.Data["encrypt"].(bool)
+	passphrase, _ := msg.Data["passphrase"].(string)
+
+	if encrypt && passphrase == "" {
+		return ipc.NewResponse(msg.ID, false, nil, "passphrase is required for encrypted backups")
+	}
 
 	if name == "" {
 		name = fmt.Sprintf("tmidb-backup-%s", time.Now().Format("20060102-150405"))
@@ -1848,6 +2267,9 @@ func (s *Supervisor) handleBackupCreate(conn *ipc.Connection, msg *ipc.Message)
 	} else {
 		backupPath = filepath.Join(outputDir, name+".tar")
 	}
+	if encrypt {
+		backupPath += backupEncSuffix
+	}
 
 	// 백업 정보 생성
 	backup := &BackupInfo{
@@ -1856,8 +2278,10 @@ func (s *Supervisor) handleBackupCreate(conn *ipc.Connection, msg *ipc.Message)
 		Path:       backupPath,
 		Created:    time.Now(),
 		Components: s.parseComponents(components),
-		Compressed: compress,
-		Status:     "creating",
+		Compressed:    compress,
+		Encrypted:     encrypt,
+		Status:        "creating",
+		SourceVersion: version.Version,
 	}
 
 	// 진행 상황 추적 생성
@@ -1871,9 +2295,10 @@ func (s *Supervisor) handleBackupCreate(conn *ipc.Connection, msg *ipc.Message)
 
 	s.backups[backupID] = backup
 	s.backupProgress[backupID] = progress
+	s.saveBackupCatalog()
 
 	// 백그라운드에서 백업 수행
-	go s.performBackup(backupID)
+	go s.performBackup(backupID, passphrase)
 
 	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
 		"id":   backupID,
@@ -1885,6 +2310,10 @@ func (s *Supervisor) handleBackupCreate(conn *ipc.Connection, msg *ipc.Message)
 func (s *Supervisor) handleBackupRestore(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
 	backup, _ := msg.Data["backup"].(string)
 	components, _ := msg.Data["components"].([]interface{})
+	passphrase, _ := msg.Data["passphrase"].(string)
+	org, _ := msg.Data["org"].(string)
+	category, _ := msg.Data["category"].(string)
+	preview, _ := msg.Data["preview"].(bool)
 
 	if backup == "" {
 		return ipc.NewResponse(msg.ID, false, nil, "backup is required")
@@ -1893,9 +2322,37 @@ func (s *Supervisor) handleBackupRestore(conn *ipc.Connection, msg *ipc.Message)
 	// 백업 ID 또는 경로로 백업 파일 경로 결정
 	var backupPath string
 
-	// 먼저 ID로 찾기
-	if info, exists := s.backups[backup]; exists {
+	var selected []string
+	if len(components) > 0 {
+		selected = s.parseComponents(components)
+	}
+
+	// 조직/카테고리 단위 복원은 데이터베이스만 대상
+	if org == "" && (category != "" || preview) {
+		return ipc.NewResponse(msg.ID, false, nil, "category and preview require an organization")
+	}
+	if org != "" {
+		if len(selected) > 1 || (len(selected) == 1 && selected[0] != "database") {
+			return ipc.NewResponse(msg.ID, false, nil, "an organization or category restore only restores the database")
+		}
+		selected = []string{"database"}
+	}
+
+	// 먼저 카탈로그에서 찾기 (컴포넌트 목록을 알 수 있음)
+	if info := s.findBackup(backup); info != nil {
 		backupPath = info.Path
+		if info.Status != "completed" {
+			return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("backup %s is %s", info.ID, info.Status))
+		}
+		if selected == nil {
+			selected = info.Components
+		}
+		for _, name := range selected {
+			if !slices.Contains(info.Components, name) {
+				return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("backup %s does not contain %s (has %s)",
+					info.ID, name, strings.Join(info.Components, ", ")))
+			}
+		}
 	} else {
 		// 파일 경로로 직접 복원
 		if _, err := os.Stat(backup); os.IsNotExist(err) {
@@ -1903,6 +2360,18 @@ func (s *Supervisor) handleBackupRestore(conn *ipc.Connection, msg *ipc.Message)
 		}
 		backupPath = backup
 	}
+	if selected == nil {
+		selected = s.parseComponents(nil)
+	}
+
+	scope := RestoreScope{Org: org, Category: category}
+	if preview {
+		result, err := previewScopedRestore(backupPath, passphrase, scope)
+		if err != nil {
+			return ipc.NewResponse(msg.ID, false, nil, err.Error())
+		}
+		return ipc.NewResponse(msg.ID, true, result, "")
+	}
 
 	// 복원 ID 생성
 	restoreID := fmt.Sprintf("restore-%d", time.Now().Unix())
@@ -1919,7 +2388,11 @@ func (s *Supervisor) handleBackupRestore(conn *ipc.Connection, msg *ipc.Message)
 	s.restoreProgress[restoreID] = progress
 
 	// 백그라운드에서 복원 수행
-	go s.performRestore(restoreID, backupPath, s.parseComponents(components))
+	if org != "" {
+		go s.performScopedRestore(restoreID, backupPath, passphrase, scope)
+	} else {
+		go s.performRestore(restoreID, backupPath, passphrase, selected)
+	}
 
 	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
 		"id": restoreID,
@@ -1929,16 +2402,27 @@ func (s *Supervisor) handleBackupRestore(conn *ipc.Connection, msg *ipc.Message)
 func (s *Supervisor) handleBackupList(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
 	var backupList []interface{}
 
-	// 메모리의 백업 목록
-	for _, backup := range s.backups {
+	// 카탈로그의 백업 목록 (최신순, 파일이 지워졌으면 missing)
+	for _, backup := range s.sortedBackups() {
+		status := backup.Status
+		if status == "completed" {
+			if _, err := os.Stat(backup.Path); os.IsNotExist(err) {
+				status = "missing"
+			}
+		}
 		backupList = append(backupList, map[string]interface{}{
-			"id":         backup.ID,
-			"name":       backup.Name,
-			"created":    backup.Created.Format("2006-01-02 15:04:05"),
-			"size":       backup.Size,
-			"components": backup.Components,
-			"compressed": backup.Compressed,
-			"status":     backup.Status,
+			"id":             backup.ID,
+			"name":           backup.Name,
+			"path":           backup.Path,
+			"created":        backup.Created.Format("2006-01-02 15:04:05"),
+			"size":           backup.Size,
+			"components":     backup.Components,
+			"compressed":     backup.Compressed,
+			"encrypted":      backup.Encrypted,
+			"checksum":       backup.Checksum,
+			"source_version": backup.SourceVersion,
+			"schema_hash":    backup.SchemaHash,
+			"status":         status,
 		})
 	}
 
@@ -1946,7 +2430,8 @@ func (s *Supervisor) handleBackupList(conn *ipc.Connection, msg *ipc.Message) *i
 	backupDir := "./backups"
 	if files, err := os.ReadDir(backupDir); err == nil {
 		for _, file := range files {
-			if !file.IsDir() && (strings.HasSuffix(file.Name(), ".tar") || strings.HasSuffix(file.Name(), ".tar.gz")) {
+			baseName := strings.TrimSuffix(file.Name(), backupEncSuffix)
+			if !file.IsDir() && (strings.HasSuffix(baseName, ".tar") || strings.HasSuffix(baseName, ".tar.gz")) {
 				filePath := filepath.Join(backupDir, file.Name())
 
 				// 이미 메모리에 있는 백업인지 확인
@@ -1962,12 +2447,15 @@ func (s *Supervisor) handleBackupList(conn *ipc.Connection, msg *ipc.Message) *i
 					if info, err := file.Info(); err == nil {
 						backupList = append(backupList, map[string]interface{}{
 							"id":         file.Name(),
-							"name":       strings.TrimSuffix(file.Name(), filepath.Ext(file.Name())),
+							"name":       strings.TrimSuffix(baseName, filepath.Ext(baseName)),
+							"path":       filePath,
 							"created":    info.ModTime().Format("2006-01-02 15:04:05"),
 							"size":       info.Size(),
 							"components": []string{"unknown"},
-							"compressed": strings.HasSuffix(file.Name(), ".gz"),
-							"status":     "completed",
+							"compressed": strings.HasSuffix(baseName, ".gz"),
+							"encrypted":  baseName != file.Name(),
+							"checksum":   readBackupChecksum(filePath),
+							"status":     "uncatalogued",
 						})
 					}
 				}
@@ -1984,9 +2472,9 @@ func (s *Supervisor) handleBackupDelete(conn *ipc.Connection, msg *ipc.Message)
 		return ipc.NewResponse(msg.ID, false, nil, "backup id is required")
 	}
 
-	// 메모리에서 백업 정보 찾기
-	backup, exists := s.backups[backupID]
-	if !exists {
+	// 카탈로그에서 백업 정보 찾기 (ID, 경로 또는 파일명)
+	backup := s.findBackup(backupID)
+	if backup == nil {
 		// 파일명으로 찾기
 		backupPath := filepath.Join("./backups", backupID)
 		if _, err := os.Stat(backupPath); os.IsNotExist(err) {
@@ -1997,32 +2485,40 @@ func (s *Supervisor) handleBackupDelete(conn *ipc.Connection, msg *ipc.Message)
 		if err := os.Remove(backupPath); err != nil {
 			return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to delete backup file: %v", err))
 		}
+		os.Remove(backupPath + backupChecksumSuffix)
 
 		return ipc.NewResponse(msg.ID, true, nil, "")
 	}
 
-	// 파일 삭제
-	if err := os.Remove(backup.Path); err != nil {
+	// 파일 삭제 (이미 없어진 파일은 카탈로그에서만 제거)
+	if err := os.Remove(backup.Path); err != nil && !os.IsNotExist(err) {
 		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to delete backup file: %v", err))
 	}
+	os.Remove(backup.Path + backupChecksumSuffix)
 
-	// 메모리에서 제거
-	delete(s.backups, backupID)
-	delete(s.backupProgress, backupID)
+	// 카탈로그에서 제거
+	delete(s.backups, backup.ID)
+	delete(s.backupProgress, backup.ID)
+	s.saveBackupCatalog()
 
 	return ipc.NewResponse(msg.ID, true, nil, "")
 }
 
 func (s *Supervisor) handleBackupVerify(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
 	backup, _ := msg.Data["backup"].(string)
+	passphrase, _ := msg.Data["passphrase"].(string)
 	if backup == "" {
 		return ipc.NewResponse(msg.ID, false, nil, "backup is required")
 	}
 
+	deep, _ := msg.Data["deep"].(bool)
+	testRestore, _ := msg.Data["test_restore"].(bool)
+
 	// 백업 파일 경로 결정
-	var backupPath string
-	if info, exists := s.backups[backup]; exists {
+	var backupPath, checksum string
+	if info := s.findBackup(backup); info != nil {
 		backupPath = info.Path
+		checksum = info.Checksum
 	} else {
 		backupPath = backup
 	}
@@ -2032,8 +2528,16 @@ func (s *Supervisor) handleBackupVerify(conn *ipc.Connection, msg *ipc.Message)
 		return ipc.NewResponse(msg.ID, false, nil, "backup file not found")
 	}
 
-	// 백업 검증 수행
-	result := s.verifyBackup(backupPath)
+	// 백업 검증 수행 (test restore는 deep 검증의 일부)
+	var result map[string]interface{}
+	if deep || testRestore {
+		if checksum == "" {
+			checksum = readBackupChecksum(backupPath)
+		}
+		result = s.verifyBackupDeep(backupPath, passphrase, checksum, testRestore)
+	} else {
+		result = s.verifyBackup(backupPath, passphrase)
+	}
 
 	return ipc.NewResponse(msg.ID, true, result, "")
 }
@@ -2067,14 +2571,6 @@ func (s *Supervisor) handleRestoreProgress(conn *ipc.Connection, msg *ipc.Messag
 }
 
 // Diagnose handlers (stub implementations)
-func (s *Supervisor) handleDiagnoseAll(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
-	return &ipc.Response{
-		ID:      msg.ID,
-		Success: false,
-		Error:   "comprehensive diagnostics not yet implemented",
-	}
-}
-
 func (s *Supervisor) handleDiagnoseComponent(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
 	return &ipc.Response{
 		ID:      msg.ID,
@@ -2107,22 +2603,6 @@ func (s *Supervisor) handleDiagnoseConnectivity(conn *ipc.Connection, msg *ipc.M
 	}
 }
 
-func (s *Supervisor) handleDiagnosePerformance(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
-	return &ipc.Response{
-		ID:      msg.ID,
-		Success: false,
-		Error:   "performance diagnostics not yet implemented",
-	}
-}
-
-func (s *Supervisor) handleDiagnoseLogs(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
-	return &ipc.Response{
-		ID:      msg.ID,
-		Success: false,
-		Error:   "log analysis not yet implemented",
-	}
-}
-
 func (s *Supervisor) handleDiagnoseFix(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
 	return &ipc.Response{
 		ID:      msg.ID,
@@ -2131,14 +2611,6 @@ func (s *Supervisor) handleDiagnoseFix(conn *ipc.Connection, msg *ipc.Message) *
 	}
 }
 
-func (s *Supervisor) handleDiagnoseResult(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
-	return &ipc.Response{
-		ID:      msg.ID,
-		Success: false,
-		Error:   "diagnostic results not yet implemented",
-	}
-}
-
 // Copy 관련 핸들러들
 func (s *Supervisor) handleCopyReceive(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
 	port := 8080 // 기본 포트
@@ -2159,20 +2631,48 @@ func (s *Supervisor) handleCopyReceive(conn *ipc.Connection, msg *ipc.Message) *
 		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to create directory: %v", err))
 	}
 
+	// TLS 설정 (인증서 미지정 시 자체 서명 인증서 생성)
+	tlsOpts := parseCopyTLSOptions(msg.Data)
+	var tlsConfig *tls.Config
+	var fingerprint string
+	if tlsOpts.Enabled {
+		var err error
+		tlsConfig, fingerprint, err = copyServerTLSConfig(tlsOpts)
+		if err != nil {
+			return ipc.NewResponse(msg.ID, false, nil, err.Error())
+		}
+	}
+
+	// 일회용 세션 토큰
+	token, _ := msg.Data["token"].(string)
+	if requireToken, _ := msg.Data["require_token"].(bool); requireToken && token == "" {
+		var err error
+		if token, err = generateCopyToken(); err != nil {
+			return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to generate session token: %v", err))
+		}
+	}
+
 	// 포트가 사용 가능한지 확인
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
 		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("port %d is not available: %v", port, err))
 	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
 
 	// 세션 생성
 	session := &ipc.CopySession{
-		ID:        sessionID,
-		Mode:      "receive",
-		Status:    "listening",
-		Port:      port,
-		Path:      path,
-		StartTime: time.Now(),
+		ID:          sessionID,
+		Mode:        "receive",
+		Status:      "listening",
+		Port:        port,
+		Path:        path,
+		TLS:         tlsConfig != nil,
+		Fingerprint: fingerprint,
+		TokenAuth:   token != "",
+		Token:       token,
+		StartTime:   time.Now(),
 	}
 
 	// 세션 저장
@@ -2182,9 +2682,12 @@ func (s *Supervisor) handleCopyReceive(conn *ipc.Connection, msg *ipc.Message) *
 	go s.handleFileReceiver(sessionID, listener)
 
 	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
-		"id":   sessionID,
-		"port": port,
-		"path": path,
+		"id":              sessionID,
+		"port":            port,
+		"path":            path,
+		"tls":             session.TLS,
+		"tls_fingerprint": fingerprint,
+		"token":           token,
 	}, "")
 }
 
@@ -2204,6 +2707,9 @@ func (s *Supervisor) handleCopySend(conn *ipc.Connection, msg *ipc.Message) *ipc
 		targetPort = int(p)
 	}
 
+	token, _ := msg.Data["token"].(string)
+	tlsOpts := parseCopyTLSOptions(msg.Data)
+
 	// 파일 존재 확인
 	fileInfo, err := os.Stat(filePath)
 	if err != nil {
@@ -2213,23 +2719,34 @@ func (s *Supervisor) handleCopySend(conn *ipc.Connection, msg *ipc.Message) *ipc
 	// 세션 ID 생성
 	sessionID := fmt.Sprintf("send-%d-%s", time.Now().Unix(), filepath.Base(filePath))
 
+	var tlsConfig *tls.Config
+	if tlsOpts.Enabled {
+		if tlsConfig, err = copyClientTLSConfig(targetHost, tlsOpts); err != nil {
+			return ipc.NewResponse(msg.ID, false, nil, err.Error())
+		}
+	}
+
 	// 세션 생성
 	session := &ipc.CopySession{
-		ID:         sessionID,
-		Mode:       "send",
-		Status:     "connecting",
-		Path:       filePath,
-		TargetHost: targetHost,
-		TargetPort: targetPort,
-		FileSize:   fileInfo.Size(),
-		StartTime:  time.Now(),
+		ID:          sessionID,
+		Mode:        "send",
+		Status:      "connecting",
+		Path:        filePath,
+		TargetHost:  targetHost,
+		TargetPort:  targetPort,
+		FileSize:    fileInfo.Size(),
+		TLS:         tlsConfig != nil,
+		Fingerprint: tlsOpts.Fingerprint,
+		TokenAuth:   token != "",
+		Token:       token,
+		StartTime:   time.Now(),
 	}
 
 	// 세션 저장
 	s.copySessions[sessionID] = session
 
 	// 백그라운드에서 파일 전송 처리
-	go s.handleFileSender(sessionID)
+	go s.handleFileSender(sessionID, tlsConfig)
 
 	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
 		"id":        sessionID,
@@ -2307,6 +2824,15 @@ func (s *Supervisor) handleFileReceiver(sessionID string, listener net.Listener)
 			return
 		}
 
+		// 토큰 확인 (TLS 핸드셰이크도 여기서 수행됨)
+		if err := authenticateCopySender(conn, session.Token); err != nil {
+			log.Printf("⚠️ Copy receiver %s: rejected %s: %v", sessionID, conn.RemoteAddr(), err)
+			conn.Close()
+			continue
+		}
+
+		// 일회용 토큰은 사용 후 폐기
+		session.Token = ""
 		session.Status = "connected"
 		log.Printf("Copy receiver %s: client connected", sessionID)
 
@@ -2317,7 +2843,7 @@ func (s *Supervisor) handleFileReceiver(sessionID string, listener net.Listener)
 }
 
 // 파일 전송 처리
-func (s *Supervisor) handleFileSender(sessionID string) {
+func (s *Supervisor) handleFileSender(sessionID string, tlsConfig *tls.Config) {
 	session, exists := s.copySessions[sessionID]
 	if !exists {
 		return
@@ -2326,7 +2852,14 @@ func (s *Supervisor) handleFileSender(sessionID string) {
 	log.Printf("Copy sender %s: connecting to %s:%d", sessionID, session.TargetHost, session.TargetPort)
 
 	// 대상 서버에 연결
-	conn, err := net.Dial("tcp", fmt.Sprintf("%s:%d", session.TargetHost, session.TargetPort))
+	address := net.JoinHostPort(session.TargetHost, strconv.Itoa(session.TargetPort))
+	var conn net.Conn
+	var err error
+	if tlsConfig != nil {
+		conn, err = tls.Dial("tcp", address, tlsConfig)
+	} else {
+		conn, err = net.Dial("tcp", address)
+	}
 	if err != nil {
 		session.Status = "failed"
 		session.Error = fmt.Sprintf("connection failed: %v", err)
@@ -2335,64 +2868,19 @@ func (s *Supervisor) handleFileSender(sessionID string) {
 	}
 	defer conn.Close()
 
-	session.Status = "connected"
-	log.Printf("Copy sender %s: connected to target", sessionID)
-
-	// 파일 전송
-	s.sendFile(sessionID, conn)
-}
-
-// 실제 파일 수신 구현 (간단한 버전)
-func (s *Supervisor) receiveFile(sessionID string, conn net.Conn) {
-	defer conn.Close()
-
-	session, exists := s.copySessions[sessionID]
-	if !exists {
-		return
-	}
-
-	session.Status = "transferring"
-
-	// 간단한 프로토콜: 파일명 길이(4바이트) + 파일명 + 파일 크기(8바이트) + 파일 데이터
-	// 실제 구현에서는 더 복잡한 프로토콜 사용
-
-	// 시뮬레이션을 위해 잠시 대기
-	time.Sleep(2 * time.Second)
-
-	session.Status = "completed"
-	session.EndTime = time.Now()
-	session.Transferred = session.FileSize
-
-	log.Printf("Copy receiver %s: file received successfully", sessionID)
-}
-
-// 실제 파일 전송 구현 (간단한 버전)
-func (s *Supervisor) sendFile(sessionID string, conn net.Conn) {
-	session, exists := s.copySessions[sessionID]
-	if !exists {
-		return
-	}
-
-	session.Status = "transferring"
-
-	// 파일 열기
-	file, err := os.Open(session.Path)
-	if err != nil {
+	if err := presentCopyToken(conn, session.Token); err != nil {
 		session.Status = "failed"
-		session.Error = fmt.Sprintf("failed to open file: %v", err)
+		session.Error = err.Error()
 		session.EndTime = time.Now()
 		return
 	}
-	defer file.Close()
+	session.Token = ""
 
-	// 시뮬레이션을 위해 잠시 대기
-	time.Sleep(2 * time.Second)
-
-	session.Status = "completed"
-	session.EndTime = time.Now()
-	session.Transferred = session.FileSize
+	session.Status = "connected"
+	log.Printf("Copy sender %s: connected to target", sessionID)
 
-	log.Printf("Copy sender %s: file sent successfully", sessionID)
+	// 파일 전송
+	s.sendFile(sessionID, conn)
 }
 
 // parseComponents converts interface{} slice to string slice for backup components
@@ -2411,13 +2899,18 @@ func (s *Supervisor) parseComponents(components []interface{}) []string {
 }
 
 // performBackup executes the backup operation in background
-func (s *Supervisor) performBackup(backupID string) {
+func (s *Supervisor) performBackup(backupID, passphrase string) {
 	backup := s.backups[backupID]
 	progress := s.backupProgress[backupID]
 	if backup == nil || progress == nil {
 		return
 	}
 
+	// 성공/실패 여부와 관계없이 마지막에 이벤트 발행
+	defer s.emitBackupEvent(backup, progress)
+	// 최종 상태를 카탈로그에 기록
+	defer s.saveBackupCatalog()
+
 	defer func() {
 		if r := recover(); r != nil {
 			progress.Status = "failed"
@@ -2431,7 +2924,8 @@ func (s *Supervisor) performBackup(backupID string) {
 	var writer io.Writer
 	var file *os.File
 	var gzWriter *gzip.Writer
-	var tarWriter *tar.Writer
+	var encWriter io.WriteCloser
+	var tarWriter *manifestWriter
 
 	// 파일 생성
 	var err error
@@ -2447,13 +2941,32 @@ func (s *Supervisor) performBackup(backupID string) {
 	defer file.Close()
 
 	writer = file
+	if backup.Encrypted {
+		var encryption *BackupEncryption
+		encWriter, encryption, err = newEncryptWriter(file, passphrase)
+		if err != nil {
+			progress.Status = "failed"
+			progress.Error = fmt.Sprintf("failed to initialize encryption: %v", err)
+			backup.Status = "failed"
+			now := time.Now()
+			progress.EndTime = &now
+			return
+		}
+		backup.Encryption = encryption
+		writer = encWriter
+		defer encWriter.Close()
+	}
+
 	if backup.Compressed {
-		gzWriter = gzip.NewWriter(file)
+		gzWriter = gzip.NewWriter(writer)
 		writer = gzWriter
 		defer gzWriter.Close()
 	}
 
-	tarWriter = tar.NewWriter(writer)
+	tarWriter = &manifestWriter{
+		Writer:   tar.NewWriter(&progressWriter{w: writer, progress: progress}),
+		manifest: newBackupManifest(),
+	}
 	defer tarWriter.Close()
 
 	// 백업 수행
@@ -2462,7 +2975,7 @@ func (s *Supervisor) performBackup(backupID string) {
 		progress.Current = fmt.Sprintf("Backing up %s", component)
 		progress.Percent = float64(i) / float64(totalSteps) * 100
 
-		if err := s.backupComponent(component, tarWriter); err != nil {
+		if err := s.backupComponent(component, tarWriter, progress); err != nil {
 			progress.Status = "failed"
 			progress.Error = fmt.Sprintf("failed to backup %s: %v", component, err)
 			backup.Status = "failed"
@@ -2472,6 +2985,38 @@ func (s *Supervisor) performBackup(backupID string) {
 		}
 	}
 
+	// 마지막 엔트리로 매니페스트 기록 (deep verify에서 비교)
+	if err := tarWriter.writeManifest(); err != nil {
+		progress.Status = "failed"
+		progress.Error = fmt.Sprintf("failed to write backup manifest: %v", err)
+		backup.Status = "failed"
+		now := time.Now()
+		progress.EndTime = &now
+		return
+	}
+	if database, ok := tarWriter.manifest.Components["database"]; ok {
+		backup.SchemaHash = database.SchemaHash
+	}
+
+	// 크기와 체크섬 계산 전에 tar, gzip, 암호화 스트림을 모두 flush
+	finalizers := []io.Closer{tarWriter}
+	if gzWriter != nil {
+		finalizers = append(finalizers, gzWriter)
+	}
+	if encWriter != nil {
+		finalizers = append(finalizers, encWriter)
+	}
+	for _, closer := range finalizers {
+		if err := closer.Close(); err != nil {
+			progress.Status = "failed"
+			progress.Error = fmt.Sprintf("failed to finalize backup: %v", err)
+			backup.Status = "failed"
+			now := time.Now()
+			progress.EndTime = &now
+			return
+		}
+	}
+
 	// 백업 완료
 	progress.Current = "Finalizing backup"
 	progress.Percent = 100
@@ -2487,14 +3032,17 @@ func (s *Supervisor) performBackup(backupID string) {
 
 	if checksum, err := s.calculateChecksum(backup.Path); err == nil {
 		backup.Checksum = checksum
+		if err := writeBackupChecksum(backup.Path, checksum); err != nil {
+			log.Printf("⚠️ Failed to write checksum file for %s: %v", backup.Path, err)
+		}
 	}
 }
 
 // backupComponent backs up a specific component
-func (s *Supervisor) backupComponent(component string, tarWriter *tar.Writer) error {
+func (s *Supervisor) backupComponent(component string, tarWriter *manifestWriter, progress *BackupProgress) error {
 	switch component {
 	case "database":
-		return s.backupDatabase(tarWriter)
+		return s.backupDatabase(tarWriter, progress)
 	case "config":
 		return s.backupConfig(tarWriter)
 	case "files":
@@ -2504,35 +3052,8 @@ func (s *Supervisor) backupComponent(component string, tarWriter *tar.Writer) er
 	}
 }
 
-// backupDatabase backs up PostgreSQL database
-func (s *Supervisor) backupDatabase(tarWriter *tar.Writer) error {
-	// PostgreSQL 덤프 생성
-	cmd := exec.Command("pg_dump", "-h", "localhost", "-p", "5432", "-U", "postgres", "tmidb")
-	cmd.Env = append(os.Environ(), "PGPASSWORD=postgres")
-
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("pg_dump failed: %v", err)
-	}
-
-	// TAR 헤더 생성
-	header := &tar.Header{
-		Name:    "database/tmidb.sql",
-		Mode:    0644,
-		Size:    int64(len(output)),
-		ModTime: time.Now(),
-	}
-
-	if err := tarWriter.WriteHeader(header); err != nil {
-		return err
-	}
-
-	_, err = tarWriter.Write(output)
-	return err
-}
-
 // backupConfig backs up configuration files
-func (s *Supervisor) backupConfig(tarWriter *tar.Writer) error {
+func (s *Supervisor) backupConfig(tarWriter archiveWriter) error {
 	// 설정을 JSON으로 내보내기
 	configData := map[string]interface{}{
 		"socket_path":     s.config.SocketPath,
@@ -2565,7 +3086,7 @@ func (s *Supervisor) backupConfig(tarWriter *tar.Writer) error {
 }
 
 // backupFiles backs up important files and directories
-func (s *Supervisor) backupFiles(tarWriter *tar.Writer) error {
+func (s *Supervisor) backupFiles(tarWriter archiveWriter) error {
 	// 로그 디렉터리 백업
 	if err := s.addDirectoryToTar(s.config.LogDir, "files/logs", tarWriter); err != nil {
 		return fmt.Errorf("failed to backup logs: %v", err)
@@ -2582,7 +3103,7 @@ func (s *Supervisor) backupFiles(tarWriter *tar.Writer) error {
 }
 
 // addDirectoryToTar recursively adds a directory to tar archive
-func (s *Supervisor) addDirectoryToTar(srcDir, destDir string, tarWriter *tar.Writer) error {
+func (s *Supervisor) addDirectoryToTar(srcDir, destDir string, tarWriter archiveWriter) error {
 	return filepath.Walk(srcDir, func(file string, fi os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -2639,7 +3160,7 @@ func (s *Supervisor) calculateChecksum(filePath string) (string, error) {
 }
 
 // performRestore executes the restore operation in background
-func (s *Supervisor) performRestore(restoreID, backupPath string, components []string) {
+func (s *Supervisor) performRestore(restoreID, backupPath, passphrase string, components []string) {
 	progress := s.restoreProgress[restoreID]
 	if progress == nil {
 		return
@@ -2654,8 +3175,8 @@ func (s *Supervisor) performRestore(restoreID, backupPath string, components []s
 		}
 	}()
 
-	// 백업 파일 열기
-	file, err := os.Open(backupPath)
+	// 백업 파일 열기 (암호화/압축 자동 감지)
+	archive, err := openBackupArchive(backupPath, passphrase)
 	if err != nil {
 		progress.Status = "failed"
 		progress.Error = fmt.Sprintf("failed to open backup file: %v", err)
@@ -2663,27 +3184,9 @@ func (s *Supervisor) performRestore(restoreID, backupPath string, components []s
 		progress.EndTime = &now
 		return
 	}
-	defer file.Close()
+	defer archive.Close()
 
-	var reader io.Reader = file
-	var gzReader *gzip.Reader
-	var tarReader *tar.Reader
-
-	// 압축 파일인지 확인
-	if strings.HasSuffix(backupPath, ".gz") {
-		gzReader, err = gzip.NewReader(file)
-		if err != nil {
-			progress.Status = "failed"
-			progress.Error = fmt.Sprintf("failed to open gzip reader: %v", err)
-			now := time.Now()
-			progress.EndTime = &now
-			return
-		}
-		defer gzReader.Close()
-		reader = gzReader
-	}
-
-	tarReader = tar.NewReader(reader)
+	tarReader := tar.NewReader(archive)
 
 	// 복원 수행
 	totalSteps := len(components)
@@ -2691,7 +3194,7 @@ func (s *Supervisor) performRestore(restoreID, backupPath string, components []s
 		progress.Current = fmt.Sprintf("Restoring %s", component)
 		progress.Percent = float64(i) / float64(totalSteps) * 100
 
-		if err := s.restoreComponent(component, tarReader, backupPath); err != nil {
+		if err := s.restoreComponent(component, tarReader, backupPath, passphrase); err != nil {
 			progress.Status = "failed"
 			progress.Error = fmt.Sprintf("failed to restore %s: %v", component, err)
 			now := time.Now()
@@ -2709,25 +3212,15 @@ func (s *Supervisor) performRestore(restoreID, backupPath string, components []s
 }
 
 // restoreComponent restores a specific component from backup
-func (s *Supervisor) restoreComponent(component string, tarReader *tar.Reader, backupPath string) error {
+func (s *Supervisor) restoreComponent(component string, tarReader *tar.Reader, backupPath, passphrase string) error {
 	// TAR 파일을 다시 열어야 함 (이미 읽은 상태이므로)
-	file, err := os.Open(backupPath)
+	archive, err := openBackupArchive(backupPath, passphrase)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	defer archive.Close()
 
-	var reader io.Reader = file
-	if strings.HasSuffix(backupPath, ".gz") {
-		gzReader, err := gzip.NewReader(file)
-		if err != nil {
-			return err
-		}
-		defer gzReader.Close()
-		reader = gzReader
-	}
-
-	newTarReader := tar.NewReader(reader)
+	newTarReader := tar.NewReader(archive)
 
 	switch component {
 	case "database":
@@ -2741,46 +3234,6 @@ func (s *Supervisor) restoreComponent(component string, tarReader *tar.Reader, b
 	}
 }
 
-// restoreDatabase restores PostgreSQL database from backup
-func (s *Supervisor) restoreDatabase(tarReader *tar.Reader) error {
-	// TAR 파일에서 database/tmidb.sql 찾기
-	for {
-		header, err := tarReader.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return err
-		}
-
-		if header.Name == "database/tmidb.sql" {
-			// 임시 파일로 SQL 저장
-			tmpFile, err := os.CreateTemp("", "restore-*.sql")
-			if err != nil {
-				return err
-			}
-			defer os.Remove(tmpFile.Name())
-			defer tmpFile.Close()
-
-			if _, err := io.Copy(tmpFile, tarReader); err != nil {
-				return err
-			}
-
-			// PostgreSQL 복원 실행
-			cmd := exec.Command("psql", "-h", "localhost", "-p", "5432", "-U", "postgres", "-d", "tmidb", "-f", tmpFile.Name())
-			cmd.Env = append(os.Environ(), "PGPASSWORD=postgres")
-
-			if output, err := cmd.CombinedOutput(); err != nil {
-				return fmt.Errorf("psql failed: %v, output: %s", err, output)
-			}
-
-			return nil
-		}
-	}
-
-	return fmt.Errorf("database backup not found in archive")
-}
-
 // restoreConfig restores configuration from backup
 func (s *Supervisor) restoreConfig(tarReader *tar.Reader) error {
 	// TAR 파일에서 config/supervisor.json 찾기
@@ -2861,7 +3314,7 @@ func (s *Supervisor) restoreFiles(tarReader *tar.Reader) error {
 }
 
 // verifyBackup verifies the integrity and contents of a backup file
-func (s *Supervisor) verifyBackup(backupPath string) map[string]interface{} {
+func (s *Supervisor) verifyBackup(backupPath, passphrase string) map[string]interface{} {
 	result := map[string]interface{}{
 		"status":     "valid",
 		"integrity":  "valid",
@@ -2871,36 +3324,25 @@ func (s *Supervisor) verifyBackup(backupPath string) map[string]interface{} {
 
 	var errors []string
 
-	// 파일 존재 및 읽기 가능 확인
-	file, err := os.Open(backupPath)
+	// 파일 열기 (암호화된 백업은 복호화, 압축은 자동 감지)
+	archive, err := openBackupArchive(backupPath, passphrase)
 	if err != nil {
-		errors = append(errors, fmt.Sprintf("Cannot open file: %v", err))
+		errors = append(errors, fmt.Sprintf("Cannot open backup: %v", err))
 		result["status"] = "invalid"
 		result["integrity"] = "invalid"
 		result["errors"] = errors
 		return result
 	}
-	defer file.Close()
+	defer archive.Close()
 
-	var reader io.Reader = file
-	var gzReader *gzip.Reader
-
-	// 압축 파일 처리
-	if strings.HasSuffix(backupPath, ".gz") {
-		gzReader, err = gzip.NewReader(file)
-		if err != nil {
-			errors = append(errors, fmt.Sprintf("Invalid gzip format: %v", err))
-			result["status"] = "invalid"
-			result["integrity"] = "invalid"
-			result["errors"] = errors
-			return result
-		}
-		defer gzReader.Close()
-		reader = gzReader
+	result["compressed"] = archive.Compressed
+	result["encrypted"] = archive.Encryption != nil
+	if archive.Encryption != nil {
+		result["encryption"] = archive.Encryption
 	}
 
 	// TAR 아카이브 검증
-	tarReader := tar.NewReader(reader)
+	tarReader := tar.NewReader(archive)
 	components := make(map[string]interface{})
 
 	for {
@@ -2911,7 +3353,7 @@ func (s *Supervisor) verifyBackup(backupPath string) map[string]interface{} {
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("TAR read error: %v", err))
 			result["integrity"] = "invalid"
-			continue
+			break
 		}
 
 		// 컴포넌트별 검증
@@ -2929,6 +3371,12 @@ func (s *Supervisor) verifyBackup(backupPath string) map[string]interface{} {
 		}
 	}
 
+	// 남은 데이터까지 읽어 암호화 청크 전체의 인증 태그를 확인
+	if _, err := io.Copy(io.Discard, archive); err != nil {
+		errors = append(errors, fmt.Sprintf("Archive read error: %v", err))
+		result["integrity"] = "invalid"
+	}
+
 	result["components"] = components
 
 	if len(errors) > 0 {
@@ -0,0 +1,167 @@
+package supervisor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/config"
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+)
+
+// ipcActor names the client making a change for the token audit log
+func ipcActor(conn *ipc.Connection) string {
+	switch {
+	case conn.TokenName != "":
+		return "cli:" + conn.TokenName
+	case conn.CertName != "":
+		return "cli:" + conn.CertName
+	case conn.Peer != nil:
+		return fmt.Sprintf("cli:uid=%d", conn.Peer.UID)
+	default:
+		return "cli"
+	}
+}
+
+// tokenRef reads the token a request refers to
+func tokenRef(data map[string]interface{}) (database.TokenRef, error) {
+	ref := database.TokenRef{}
+	ref.TokenID, _ = data["token_id"].(string)
+	ref.Kind, _ = data["kind"].(string)
+	ref.OrgID, _ = data["org_id"].(string)
+	if ref.TokenID == "" {
+		return ref, fmt.Errorf("token id required")
+	}
+	return ref, nil
+}
+
+// handleTokenList lists API and user access tokens, optionally for one organization
+func (s *Supervisor) handleTokenList(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	orgID, _ := msg.Data["org_id"].(string)
+	tokens, err := database.ListTokens(db, orgID)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to list tokens: %v", err))
+	}
+	return ipc.NewResponse(msg.ID, true, tokens, "")
+}
+
+// handleTokenRotate issues a replacement token and lets the old one work for
+// the grace period
+func (s *Supervisor) handleTokenRotate(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	ref, err := tokenRef(msg.Data)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	graceText, _ := msg.Data["grace_period"].(string)
+	if graceText == "" {
+		graceText = "24h"
+	}
+	grace, err := database.ParseTokenDuration(graceText)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+	// API tokens are also stored encrypted, so the new one needs the API's key
+	if ref.Kind == database.TokenKindAPI {
+		cfg, err := config.Load()
+		if err == nil {
+			err = database.InitCrypto(cfg.EncryptionKey)
+		}
+		if err != nil {
+			return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to load encryption key: %v", err))
+		}
+	}
+
+	rotated, err := database.RotateToken(db, ref, grace, ipcActor(conn))
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, rotated, "")
+}
+
+// handleTokenExpiry sets, extends or clears a token's expiry
+func (s *Supervisor) handleTokenExpiry(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	ref, err := tokenRef(msg.Data)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	var expiresAt *time.Time
+	if at, _ := msg.Data["expires_at"].(string); at != "" {
+		parsed, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			return ipc.NewResponse(msg.ID, false, nil, "invalid expires_at, use RFC3339")
+		}
+		expiresAt = &parsed
+	}
+	var extendBy time.Duration
+	if extend, _ := msg.Data["extend_by"].(string); extend != "" {
+		if extendBy, err = database.ParseTokenDuration(extend); err != nil {
+			return ipc.NewResponse(msg.ID, false, nil, err.Error())
+		}
+	}
+	if clear, _ := msg.Data["clear"].(bool); expiresAt == nil && extendBy == 0 && !clear {
+		return ipc.NewResponse(msg.ID, false, nil, "one of expires_at, extend_by or clear is required")
+	}
+
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	expiresAt, err = database.SetTokenExpiry(db, ref, expiresAt, extendBy, ipcActor(conn))
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{"token_id": ref.TokenID, "expires_at": expiresAt}, "")
+}
+
+// handleTokenRevoke disables a token immediately
+func (s *Supervisor) handleTokenRevoke(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	ref, err := tokenRef(msg.Data)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	reason, _ := msg.Data["reason"].(string)
+
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	if err := database.RevokeToken(db, ref, reason, ipcActor(conn)); err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{"token_id": ref.TokenID, "is_active": false}, "")
+}
+
+// handleTokenAudit returns token audit log entries, newest first
+func (s *Supervisor) handleTokenAudit(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	db, err := openPolicyDB()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	defer db.Close()
+
+	orgID, _ := msg.Data["org_id"].(string)
+	tokenID, _ := msg.Data["token_id"].(string)
+	limit, _ := msg.Data["limit"].(float64)
+	entries, err := database.GetTokenAuditLog(db, orgID, tokenID, int(limit))
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to read token audit log: %v", err))
+	}
+	return ipc.NewResponse(msg.ID, true, entries, "")
+}
@@ -0,0 +1,384 @@
+package supervisor
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/upgrade"
+)
+
+// internalBinaries maps internal components to the binary the supervisor runs
+var internalBinaries = map[string]string{
+	"api":           "/app/bin/api",
+	"data-manager":  "/app/bin/data-manager",
+	"data-consumer": "/app/bin/data-consumer",
+}
+
+// upgradeOrder is the order components are swapped and restarted in during an
+// upgrade: the data path first, so the API that clients talk to only switches
+// once the components behind it run the new version
+var upgradeOrder = []string{"data-manager", "data-consumer", "api"}
+
+const (
+	// upgradeStagedSuffix marks a verified binary waiting to be swapped in
+	upgradeStagedSuffix = ".upgrade"
+	// upgradePreviousSuffix keeps the replaced binary until the upgrade succeeds
+	upgradePreviousSuffix = ".prev"
+)
+
+// UpgradeStep tracks one component of an upgrade
+type UpgradeStep struct {
+	Component string     `json:"component"`
+	Status    string     `json:"status"` // "pending", "installing", "restarting", "waiting", "healthy", "failed", "skipped", "rolled_back"
+	Message   string     `json:"message,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+}
+
+// UpgradeProgress tracks an upgrade operation
+type UpgradeProgress struct {
+	ID        string        `json:"id"`
+	Version   string        `json:"version"`
+	Bundle    string        `json:"bundle"`
+	Status    string        `json:"status"` // "running", "completed", "rolled_back", "failed"
+	Steps     []UpgradeStep `json:"steps"`
+	StartTime time.Time     `json:"start_time"`
+	EndTime   *time.Time    `json:"end_time,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// upgrades holds the progress of upgrades started over IPC
+type upgrades struct {
+	mu       sync.Mutex
+	progress map[string]*UpgradeProgress
+	active   string
+	latest   string
+}
+
+func (s *Supervisor) handleUpgradeApply(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	bundlePath, _ := msg.Data["bundle"].(string)
+	if bundlePath == "" {
+		return ipc.NewResponse(msg.ID, false, nil, "bundle parameter required")
+	}
+
+	timeout := defaultRollingTimeout
+	if seconds, ok := msg.Data["timeout"].(float64); ok && seconds > 0 {
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+
+	s.configMutex.Lock()
+	trusted, err := parseUpgradeKeys(s.config.UpgradePublicKeys)
+	s.configMutex.Unlock()
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	progress := &UpgradeProgress{
+		ID:        fmt.Sprintf("upgrade-%d", time.Now().UnixNano()),
+		Bundle:    bundlePath,
+		Status:    "running",
+		StartTime: time.Now(),
+	}
+
+	// 검증하는 동안 다른 업그레이드가 같은 파일에 쓰지 않도록 먼저 자리를 잡음
+	s.upgrades.mu.Lock()
+	if s.upgrades.active != "" {
+		active := s.upgrades.active
+		s.upgrades.mu.Unlock()
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("upgrade %s is already in progress", active))
+	}
+	s.rolling.mu.Lock()
+	rollingActive := s.rolling.active
+	s.rolling.mu.Unlock()
+	if rollingActive != "" {
+		s.upgrades.mu.Unlock()
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("rolling restart %s is in progress", rollingActive))
+	}
+	s.upgrades.active = progress.ID
+	s.upgrades.mu.Unlock()
+
+	manifest, err := stageUpgradeBundle(bundlePath, trusted)
+	if err != nil {
+		s.upgrades.mu.Lock()
+		s.upgrades.active = ""
+		s.upgrades.mu.Unlock()
+		log.Printf("❌ Upgrade bundle %s rejected: %v", bundlePath, err)
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	progress.Version = manifest.Version
+	for _, name := range upgradeOrder {
+		if slices.Contains(manifest.Components(), name) {
+			progress.Steps = append(progress.Steps, UpgradeStep{Component: name, Status: "pending"})
+		}
+	}
+
+	s.upgrades.mu.Lock()
+	if s.upgrades.progress == nil {
+		s.upgrades.progress = make(map[string]*UpgradeProgress)
+	}
+	s.upgrades.progress[progress.ID] = progress
+	s.upgrades.latest = progress.ID
+	s.upgrades.mu.Unlock()
+
+	go s.performUpgrade(progress, timeout)
+
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
+		"id":         progress.ID,
+		"version":    manifest.Version,
+		"components": manifest.Components(),
+	}, "")
+}
+
+func (s *Supervisor) handleUpgradeStatus(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	s.upgrades.mu.Lock()
+	defer s.upgrades.mu.Unlock()
+
+	// id가 없으면 가장 최근 업그레이드
+	id, _ := msg.Data["id"].(string)
+	if id == "" {
+		id = s.upgrades.latest
+	}
+	progress, exists := s.upgrades.progress[id]
+	if !exists {
+		return ipc.NewResponse(msg.ID, false, nil, "upgrade not found")
+	}
+
+	// 진행 중인 작업과 공유하지 않도록 복사본 반환
+	snapshot := *progress
+	snapshot.Steps = append([]UpgradeStep(nil), progress.Steps...)
+	return ipc.NewResponse(msg.ID, true, snapshot, "")
+}
+
+// parseUpgradeKeys decodes the configured upgrade_public_keys
+func parseUpgradeKeys(keys []string) ([]ed25519.PublicKey, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("upgrades are disabled: no upgrade_public_keys configured")
+	}
+	trusted := make([]ed25519.PublicKey, 0, len(keys))
+	for i, key := range keys {
+		parsed, err := upgrade.ParsePublicKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("upgrade_public_keys[%d]: %v", i, err)
+		}
+		trusted = append(trusted, parsed)
+	}
+	return trusted, nil
+}
+
+// stageUpgradeBundle verifies the bundle and writes each binary next to the
+// one it replaces, so the swap is a rename within one directory
+func stageUpgradeBundle(path string, trusted []ed25519.PublicKey) (*upgrade.Manifest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer file.Close()
+
+	return upgrade.Extract(file, trusted, func(component string) (string, error) {
+		target, ok := internalBinaries[component]
+		if !ok {
+			return "", fmt.Errorf("bundle contains unknown component %q", component)
+		}
+		return target + upgradeStagedSuffix, nil
+	})
+}
+
+// performUpgrade swaps and restarts components one at a time. When a component
+// does not come back healthy every component swapped so far is put back on its
+// previous binary and restarted, and the remaining components are left untouched.
+func (s *Supervisor) performUpgrade(progress *UpgradeProgress, timeout time.Duration) {
+	log.Printf("⬆️ Starting upgrade %s to %s (%d components)", progress.ID, progress.Version, len(progress.Steps))
+
+	var failure error
+	var installed []int
+	for i := range progress.Steps {
+		name := progress.Steps[i].Component
+		target := internalBinaries[name]
+
+		if failure != nil {
+			os.Remove(target + upgradeStagedSuffix)
+			s.setUpgradeStep(progress, i, "skipped", "")
+			continue
+		}
+
+		// 의존하는 서비스가 내려가 있으면 새 버전이 건강해질 수 없으므로 시작 전에 중단
+		if err := s.upgradeDependenciesRunning(name); err != nil {
+			failure = fmt.Errorf("cannot upgrade %s: %v", name, err)
+			os.Remove(target + upgradeStagedSuffix)
+			s.setUpgradeStep(progress, i, "failed", err.Error())
+			continue
+		}
+
+		s.setUpgradeStep(progress, i, "installing", "")
+		if err := installUpgradeBinary(target); err != nil {
+			failure = fmt.Errorf("failed to install %s: %v", name, err)
+			os.Remove(target + upgradeStagedSuffix)
+			s.setUpgradeStep(progress, i, "failed", err.Error())
+			continue
+		}
+		installed = append(installed, i)
+
+		s.setUpgradeStep(progress, i, "restarting", "")
+		restartedAt := time.Now()
+		if err := s.processManager.RestartProcess(name); err != nil {
+			failure = fmt.Errorf("failed to restart %s: %v", name, err)
+			s.setUpgradeStep(progress, i, "failed", err.Error())
+			continue
+		}
+
+		s.setUpgradeStep(progress, i, "waiting", "waiting for component to report healthy")
+		if err := s.waitComponentHealthy(name, restartedAt, timeout); err != nil {
+			failure = fmt.Errorf("%s did not become healthy: %v", name, err)
+			s.setUpgradeStep(progress, i, "failed", err.Error())
+			continue
+		}
+
+		s.setUpgradeStep(progress, i, "healthy", fmt.Sprintf("healthy after %s", time.Since(restartedAt).Round(time.Second)))
+		log.Printf("✅ Upgrade: %s is healthy on %s", name, progress.Version)
+	}
+
+	status := "completed"
+	if failure != nil {
+		status = "rolled_back"
+		if err := s.rollbackUpgrade(progress, installed, timeout); err != nil {
+			status = "failed"
+			failure = fmt.Errorf("%v; rollback failed: %v", failure, err)
+		}
+	} else {
+		for _, i := range installed {
+			os.Remove(internalBinaries[progress.Steps[i].Component] + upgradePreviousSuffix)
+		}
+	}
+
+	s.upgrades.mu.Lock()
+	now := time.Now()
+	progress.EndTime = &now
+	progress.Status = status
+	if failure != nil {
+		progress.Error = failure.Error()
+	}
+	s.upgrades.active = ""
+	s.upgrades.mu.Unlock()
+
+	data := map[string]interface{}{
+		"id":      progress.ID,
+		"version": progress.Version,
+		"status":  status,
+	}
+	if failure != nil {
+		log.Printf("❌ Upgrade %s %s: %v", progress.ID, status, failure)
+		s.emitEvent(ipc.EventUpgradeFailed, "supervisor", failure.Error(), data)
+	} else {
+		log.Printf("✅ Upgrade %s to %s completed", progress.ID, progress.Version)
+		s.emitEvent(ipc.EventUpgradeComplete, "supervisor", fmt.Sprintf("upgraded to %s", progress.Version), data)
+	}
+}
+
+// rollbackUpgrade restores the previous binaries of the installed steps in
+// reverse order and restarts them
+func (s *Supervisor) rollbackUpgrade(progress *UpgradeProgress, installed []int, timeout time.Duration) error {
+	var errs []error
+	for j := len(installed) - 1; j >= 0; j-- {
+		i := installed[j]
+		name := progress.Steps[i].Component
+		log.Printf("↩️ Upgrade: rolling back %s", name)
+
+		if err := restoreUpgradeBinary(internalBinaries[name]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", name, err))
+			s.setUpgradeStep(progress, i, "failed", fmt.Sprintf("rollback failed: %v", err))
+			continue
+		}
+
+		restartedAt := time.Now()
+		err := s.processManager.RestartProcess(name)
+		if err == nil {
+			err = s.waitComponentHealthy(name, restartedAt, timeout)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", name, err))
+			s.setUpgradeStep(progress, i, "failed", fmt.Sprintf("previous binary restored but not healthy: %v", err))
+			continue
+		}
+
+		message := "previous binary restored"
+		if progress.Steps[i].Status == "failed" {
+			message = progress.Steps[i].Message + "; " + message
+		}
+		s.setUpgradeStep(progress, i, "rolled_back", message)
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Supervisor) setUpgradeStep(progress *UpgradeProgress, i int, status, message string) {
+	s.upgrades.mu.Lock()
+	defer s.upgrades.mu.Unlock()
+
+	step := &progress.Steps[i]
+	step.Status = status
+	step.Message = message
+	switch status {
+	case "healthy", "failed", "skipped", "rolled_back":
+		now := time.Now()
+		step.EndTime = &now
+	}
+}
+
+// upgradeDependenciesRunning checks that the external services a component
+// connects to are running. Services the supervisor does not manage are ignored.
+func (s *Supervisor) upgradeDependenciesRunning(name string) error {
+	services := make([]string, 0, len(serviceDependents))
+	for service, dependents := range serviceDependents {
+		if slices.Contains(dependents, name) {
+			services = append(services, service)
+		}
+	}
+	sort.Strings(services)
+
+	for _, service := range services {
+		info, err := s.processManager.GetProcessStatus(service)
+		if err != nil {
+			continue
+		}
+		if info.Status != "running" {
+			return fmt.Errorf("dependency %s is %s", service, info.Status)
+		}
+	}
+	return nil
+}
+
+// installUpgradeBinary keeps the current binary as target.prev and renames the
+// staged binary over target. The rename is atomic, so target always exists.
+func installUpgradeBinary(target string) error {
+	staged := target + upgradeStagedSuffix
+	previous := target + upgradePreviousSuffix
+
+	if _, err := os.Stat(staged); err != nil {
+		return fmt.Errorf("staged binary missing: %w", err)
+	}
+	os.Remove(previous)
+	if err := os.Link(target, previous); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to keep previous binary: %w", err)
+	}
+	if err := os.Rename(staged, target); err != nil {
+		os.Remove(previous)
+		return fmt.Errorf("failed to swap binary: %w", err)
+	}
+	return nil
+}
+
+// restoreUpgradeBinary puts target.prev back in place of target
+func restoreUpgradeBinary(target string) error {
+	previous := target + upgradePreviousSuffix
+	if _, err := os.Stat(previous); err != nil {
+		return fmt.Errorf("previous binary missing: %w", err)
+	}
+	return os.Rename(previous, target)
+}
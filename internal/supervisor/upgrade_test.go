@@ -0,0 +1,57 @@
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInstallAndRestoreUpgradeBinary(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "api")
+	if err := os.WriteFile(target, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := installUpgradeBinary(target); err == nil {
+		t.Fatal("install without a staged binary must fail")
+	}
+
+	if err := os.WriteFile(target+upgradeStagedSuffix, []byte("new"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := installUpgradeBinary(target); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "new" {
+		t.Fatalf("target = %q after install", data)
+	}
+	if data, _ := os.ReadFile(target + upgradePreviousSuffix); string(data) != "old" {
+		t.Fatalf("previous = %q after install", data)
+	}
+	if _, err := os.Stat(target + upgradeStagedSuffix); !os.IsNotExist(err) {
+		t.Fatal("staged binary should be gone after install")
+	}
+
+	if err := restoreUpgradeBinary(target); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "old" {
+		t.Fatalf("target = %q after restore", data)
+	}
+	if err := restoreUpgradeBinary(target); err == nil {
+		t.Fatal("restore without a previous binary must fail")
+	}
+}
+
+func TestParseUpgradeKeys(t *testing.T) {
+	if _, err := parseUpgradeKeys(nil); err == nil {
+		t.Fatal("expected upgrades to be disabled without keys")
+	}
+	if _, err := parseUpgradeKeys([]string{"not-a-key"}); err == nil {
+		t.Fatal("expected an error for an invalid key")
+	}
+	keys, err := parseUpgradeKeys([]string{"11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo="})
+	if err != nil || len(keys) != 1 {
+		t.Fatalf("keys = %v, err = %v", keys, err)
+	}
+}
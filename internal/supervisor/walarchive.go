@@ -0,0 +1,917 @@
+package supervisor
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/user"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/config"
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/storage"
+	"github.com/tmidb/tmidb-core/internal/sysinfo"
+)
+
+// postgresArchiveFile holds the archive_command/restore_command settings and
+// postgresRecoveryFile the recovery target of a running point-in-time restore.
+// Both are included from postgresql.conf like the tuning file.
+const (
+	postgresArchiveFile  = "tmidb-archive.conf"
+	postgresRecoveryFile = "tmidb-recovery.conf"
+
+	postgresArchiveInclude  = "include_if_exists = '" + postgresArchiveFile + "'\t# managed by tmidb-supervisor"
+	postgresRecoveryInclude = "include_if_exists = '" + postgresRecoveryFile + "'\t# managed by tmidb-supervisor"
+)
+
+// defaultFilerURL is used for s3:// archives when SEAWEEDFS_FILER_URL is not set
+const defaultFilerURL = "http://localhost:8888"
+
+// archiveURLPattern keeps archive URLs safe to embed in archive_command
+var archiveURLPattern = regexp.MustCompile(`^https?://[A-Za-z0-9.:\-\[\]]+(/[A-Za-z0-9._\-]+)+$`)
+
+// walFileName matches the names PostgreSQL passes as %f: segments, timeline
+// history files, backup history files and partial segments
+var walFileName = regexp.MustCompile(`^[0-9A-F]{8}(\.history|[0-9A-F]{16}(\.[0-9A-F]{8}\.backup|\.partial)?)$`)
+
+// baseLabelPattern keeps base backup labels usable as filer path segments
+var baseLabelPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// walJobs allows one base backup or point-in-time restore at a time
+var walJobs sync.Mutex
+
+// walArchive is a WAL archive location on a SeaweedFS filer
+type walArchive struct {
+	filerURL string // e.g. http://localhost:8888
+	path     string // e.g. /buckets/tmidb-wal
+}
+
+// parseWALArchive reads a wal_archive value: a filer URL such as
+// http://localhost:8888/buckets/tmidb-wal, or s3://bucket/prefix for a bucket
+// of the SeaweedFS S3 gateway (stored under /buckets on the filer). An empty
+// value returns nil, leaving WAL archiving off.
+func parseWALArchive(value string) (*walArchive, error) {
+	value = strings.TrimRight(strings.TrimSpace(value), "/")
+	if value == "" {
+		return nil, nil
+	}
+
+	if bucket, ok := strings.CutPrefix(value, "s3://"); ok {
+		filerURL := os.Getenv("SEAWEEDFS_FILER_URL")
+		if filerURL == "" {
+			filerURL = defaultFilerURL
+		}
+		value = strings.TrimRight(filerURL, "/") + "/buckets/" + bucket
+	}
+
+	if !archiveURLPattern.MatchString(value) {
+		return nil, fmt.Errorf("invalid wal_archive %q: use a filer URL such as http://localhost:8888/buckets/tmidb-wal or s3://bucket/prefix", value)
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wal_archive %q: %w", value, err)
+	}
+	for _, segment := range strings.Split(strings.Trim(u.Path, "/"), "/") {
+		if segment == "." || segment == ".." {
+			return nil, fmt.Errorf("invalid wal_archive %q: relative path segment", value)
+		}
+	}
+	return &walArchive{filerURL: u.Scheme + "://" + u.Host, path: u.Path}, nil
+}
+
+func (a *walArchive) String() string {
+	return a.filerURL + a.path
+}
+
+func (a *walArchive) client() *storage.FilerClient {
+	return storage.NewFilerClient(a.filerURL)
+}
+
+func (a *walArchive) walPath(name string) string {
+	return path.Join(a.path, "wal", name)
+}
+
+func (a *walArchive) basePath(label string) string {
+	return path.Join(a.path, "base", label, "base.tar.gz")
+}
+
+func (a *walArchive) indexPath() string {
+	return path.Join(a.path, "base", "index.json")
+}
+
+// renderWALArchive renders the archiving settings. exe is the supervisor
+// binary that PostgreSQL runs to push and fetch segments.
+func renderWALArchive(a *walArchive, exe string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Generated by tmidb-supervisor from wal_archive %q.\n", a.String())
+	buf.WriteString("# Changes here are overwritten; use 'tmidb-cli config set wal_archive <url>' instead.\n\n")
+	fmt.Fprintf(&buf, "wal_level = replica\n")
+	fmt.Fprintf(&buf, "archive_mode = on\n")
+	fmt.Fprintf(&buf, "archive_command = '%s wal-push %s %%p %%f'\n", exe, a.String())
+	fmt.Fprintf(&buf, "archive_timeout = 60\n")
+	fmt.Fprintf(&buf, "restore_command = '%s wal-fetch %s %%f %%p'\n", exe, a.String())
+	return buf.Bytes()
+}
+
+// renderRecoveryTarget renders the settings for recovering up to target
+func renderRecoveryTarget(target time.Time) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("# Written by tmidb-supervisor for a point-in-time restore; removed once recovery completes.\n\n")
+	fmt.Fprintf(&buf, "recovery_target_time = '%s'\n", target.Format("2006-01-02 15:04:05.999999-07:00"))
+	buf.WriteString("recovery_target_inclusive = on\n")
+	buf.WriteString("recovery_target_action = 'promote'\n")
+	return buf.Bytes()
+}
+
+// applyWALArchive renders the archiving settings for the configured archive.
+// Enabling or disabling archiving takes effect when PostgreSQL restarts.
+func (s *Supervisor) applyWALArchive() (bool, error) {
+	archive, err := parseWALArchive(s.config.WALArchive)
+	if err != nil {
+		return false, err
+	}
+
+	var rendered []byte
+	if archive != nil {
+		exe, err := os.Executable()
+		if err != nil {
+			return false, fmt.Errorf("failed to locate supervisor binary for archive_command: %w", err)
+		}
+		if strings.ContainsAny(exe, " '\"\\") {
+			return false, fmt.Errorf("supervisor binary path %q cannot be used in archive_command", exe)
+		}
+		rendered = renderWALArchive(archive, exe)
+	}
+
+	changed, err := writeManagedConf(postgresDataDir, postgresArchiveFile, postgresArchiveInclude, rendered)
+	if err != nil {
+		return false, err
+	}
+	if changed && archive != nil {
+		log.Printf("🗄️ PostgreSQL WAL archiving to %s", archive)
+	} else if changed {
+		log.Println("🗄️ PostgreSQL WAL archiving disabled")
+	}
+	return changed, nil
+}
+
+// RunWALCommand runs the helpers PostgreSQL calls from archive_command and
+// restore_command:
+//
+//	tmidb-supervisor wal-push <archive> <path> <name>
+//	tmidb-supervisor wal-fetch <archive> <name> <path>
+func RunWALCommand(args []string) error {
+	if len(args) != 4 {
+		return fmt.Errorf("usage: %s <archive> <file> <file>", args[0])
+	}
+	archive, err := parseWALArchive(args[1])
+	if err != nil {
+		return err
+	}
+	if archive == nil {
+		return errors.New("archive is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	switch args[0] {
+	case "wal-push":
+		return pushWAL(ctx, archive, args[2], args[3])
+	case "wal-fetch":
+		return fetchWAL(ctx, archive, args[2], args[3])
+	default:
+		return fmt.Errorf("unknown command %s", args[0])
+	}
+}
+
+// pushWAL uploads one WAL file. A file that is already archived with the same
+// content succeeds (PostgreSQL retries after a crash); different content fails
+// so that an archive shared by mistake is never overwritten.
+func pushWAL(ctx context.Context, archive *walArchive, src, name string) error {
+	if !walFileName.MatchString(name) {
+		return fmt.Errorf("unexpected WAL file name %q", name)
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	client := archive.client()
+	remotePath := archive.walPath(name)
+	existing, err := client.Get(ctx, remotePath)
+	if err == nil {
+		defer existing.Body.Close()
+		hash := sha256.New()
+		if _, err := io.Copy(hash, existing.Body); err != nil {
+			return fmt.Errorf("failed to read archived %s: %w", name, err)
+		}
+		if local := sha256.Sum256(data); bytes.Equal(hash.Sum(nil), local[:]) {
+			return nil
+		}
+		return fmt.Errorf("%s is already archived with different content", name)
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return err
+	}
+
+	return client.Put(ctx, remotePath, bytes.NewReader(data), int64(len(data)), "application/octet-stream")
+}
+
+// fetchWAL downloads one WAL file. A missing file is reported as an error,
+// which PostgreSQL treats as the end of the archive.
+func fetchWAL(ctx context.Context, archive *walArchive, name, dst string) error {
+	if !walFileName.MatchString(name) {
+		return fmt.Errorf("unexpected WAL file name %q", name)
+	}
+	object, err := archive.client().Get(ctx, archive.walPath(name))
+	if err != nil {
+		return err
+	}
+	defer object.Body.Close()
+
+	tmp := dst + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(file, object.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && object.Size > 0 && n != object.Size {
+		err = fmt.Errorf("short read: %d of %d bytes", n, object.Size)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to fetch %s: %w", name, err)
+	}
+	return os.Rename(tmp, dst)
+}
+
+// BaseBackup is a physical base backup stored next to the archived WAL
+type BaseBackup struct {
+	Label      string    `json:"label"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Size       int64     `json:"size"` // compressed bytes
+}
+
+// readBaseBackups reads the base backup index (oldest first)
+func readBaseBackups(ctx context.Context, archive *walArchive) ([]BaseBackup, error) {
+	object, err := archive.client().Get(ctx, archive.indexPath())
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer object.Body.Close()
+
+	var backups []BaseBackup
+	if err := json.NewDecoder(object.Body).Decode(&backups); err != nil {
+		return nil, fmt.Errorf("invalid base backup index: %w", err)
+	}
+	return backups, nil
+}
+
+// writeBaseBackups replaces the base backup index
+func writeBaseBackups(ctx context.Context, archive *walArchive, backups []BaseBackup) error {
+	data, err := json.MarshalIndent(backups, "", "  ")
+	if err != nil {
+		return err
+	}
+	return archive.client().Put(ctx, archive.indexPath(), bytes.NewReader(data), int64(len(data)), "application/json")
+}
+
+// baseBackupFor picks the newest base backup that finished at or before
+// target, since recovery can only stop after the backup became consistent.
+// An explicit label selects that backup instead.
+func baseBackupFor(backups []BaseBackup, target time.Time, label string) (*BaseBackup, error) {
+	var chosen *BaseBackup
+	for i := range backups {
+		backup := &backups[i]
+		if label != "" {
+			if backup.Label == label {
+				chosen = backup
+				break
+			}
+			continue
+		}
+		if !backup.FinishedAt.After(target) && (chosen == nil || backup.FinishedAt.After(chosen.FinishedAt)) {
+			chosen = backup
+		}
+	}
+
+	switch {
+	case chosen == nil && label != "":
+		return nil, fmt.Errorf("base backup %s not found in the archive", label)
+	case chosen == nil:
+		return nil, fmt.Errorf("no base backup finished before %s; create one with 'tmidb-cli backup base'", target.Format(time.RFC3339))
+	case chosen.FinishedAt.After(target):
+		return nil, fmt.Errorf("base backup %s finished at %s, after the recovery target", chosen.Label, chosen.FinishedAt.Format(time.RFC3339))
+	}
+	return chosen, nil
+}
+
+// countingReader reports the bytes read through it
+type countingReader struct {
+	r      io.Reader
+	onRead func(n int)
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.onRead(n)
+	return n, err
+}
+
+// handleBackupBase starts a base backup into the WAL archive
+func (s *Supervisor) handleBackupBase(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	archive, err := parseWALArchive(s.config.WALArchive)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	if archive == nil {
+		return ipc.NewResponse(msg.ID, false, nil, "WAL archiving is not configured (tmidb-cli config set wal_archive <url>)")
+	}
+	if !walJobs.TryLock() {
+		return ipc.NewResponse(msg.ID, false, nil, "another base backup or point-in-time restore is running")
+	}
+
+	label, _ := msg.Data["label"].(string)
+	if label == "" {
+		label = "base-" + time.Now().UTC().Format("20060102T150405Z")
+	}
+	if !baseLabelPattern.MatchString(label) {
+		walJobs.Unlock()
+		return ipc.NewResponse(msg.ID, false, nil, "label may contain only letters, digits, '-' and '_'")
+	}
+
+	backupID := fmt.Sprintf("basebackup-%d", time.Now().Unix())
+	progress := &BackupProgress{
+		ID:        backupID,
+		Status:    "creating",
+		Current:   "Starting base backup",
+		StartTime: time.Now(),
+	}
+	s.backupProgress[backupID] = progress
+
+	go func() {
+		defer walJobs.Unlock()
+		s.performBaseBackup(archive, label, progress)
+	}()
+
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
+		"id":      backupID,
+		"label":   label,
+		"archive": archive.String(),
+	}, "")
+}
+
+// performBaseBackup streams pg_basebackup into the archive. WAL is not
+// included (-X none): the server waits until the segments the backup needs
+// have been archived, and recovery fetches them from there.
+func (s *Supervisor) performBaseBackup(archive *walArchive, label string, progress *BackupProgress) {
+	info := &BackupInfo{
+		ID:         progress.ID,
+		Name:       label,
+		Path:       archive.String() + "/base/" + label,
+		Created:    progress.StartTime,
+		Components: []string{"database"},
+		Compressed: true,
+		Status:     "creating",
+	}
+	defer s.emitBackupEvent(info, progress)
+
+	fail := func(err error) {
+		progress.Status = "failed"
+		progress.Error = err.Error()
+		info.Status = "failed"
+		now := time.Now()
+		progress.EndTime = &now
+		log.Printf("❌ Base backup %s failed: %v", label, err)
+	}
+
+	ctx := context.Background()
+	backups, err := readBaseBackups(ctx, archive)
+	if err != nil {
+		fail(fmt.Errorf("failed to read base backup index: %w", err))
+		return
+	}
+	for _, backup := range backups {
+		if backup.Label == label {
+			fail(fmt.Errorf("base backup %s already exists", label))
+			return
+		}
+	}
+
+	// Progress is estimated from the size of the data directory without pg_wal
+	estimate := dataDirSize(postgresDataDir)
+
+	cmd := exec.Command("pg_basebackup", "-h", "localhost", "-p", "5432", "-U", "postgres",
+		"-D", "-", "-F", "tar", "-X", "none", "-c", "fast", "-l", label)
+	cmd.Env = append(os.Environ(), "PGPASSWORD=postgres")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		fail(err)
+		return
+	}
+	if err := cmd.Start(); err != nil {
+		fail(fmt.Errorf("failed to start pg_basebackup: %v", err))
+		return
+	}
+
+	// pg_basebackup output -> gzip -> filer upload
+	pr, pw := io.Pipe()
+	go func() {
+		gz := gzip.NewWriter(pw)
+		_, err := io.Copy(gz, &countingReader{r: stdout, onRead: func(n int) {
+			progress.BytesWritten += int64(n)
+			progress.Current = fmt.Sprintf("Streaming base backup (%.1f MB read)", float64(progress.BytesWritten)/(1024*1024))
+			if estimate > 0 {
+				progress.Percent = min(float64(progress.BytesWritten)/float64(estimate)*95, 95)
+			}
+		}})
+		if err == nil {
+			err = gz.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	var uploaded int64
+	uploadErr := archive.client().Put(ctx, archive.basePath(label), &countingReader{r: pr, onRead: func(n int) {
+		uploaded += int64(n)
+	}}, -1, "application/gzip")
+	if uploadErr != nil {
+		// Kill pg_basebackup so it does not block writing to the pipe
+		pr.CloseWithError(uploadErr)
+		cmd.Process.Kill()
+	}
+	waitErr := cmd.Wait()
+
+	if uploadErr != nil || waitErr != nil {
+		archive.client().Delete(ctx, archive.basePath(label))
+		if waitErr != nil {
+			fail(fmt.Errorf("pg_basebackup failed: %v: %s", waitErr, strings.TrimSpace(stderr.String())))
+		} else {
+			fail(uploadErr)
+		}
+		return
+	}
+
+	progress.Current = "Updating base backup index"
+	finished := time.Now()
+	backups = append(backups, BaseBackup{Label: label, StartedAt: progress.StartTime, FinishedAt: finished, Size: uploaded})
+	if err := writeBaseBackups(ctx, archive, backups); err != nil {
+		fail(fmt.Errorf("failed to update base backup index: %w", err))
+		return
+	}
+
+	info.Size = uploaded
+	info.Status = "completed"
+	progress.Current = "Base backup completed"
+	progress.Percent = 100
+	progress.Status = "completed"
+	progress.EndTime = &finished
+	log.Printf("✅ Base backup %s stored in %s (%.1f MB)", label, archive, float64(uploaded)/(1024*1024))
+}
+
+// dataDirSize sums the data directory without pg_wal, which base backups skip
+func dataDirSize(dataDir string) int64 {
+	var total int64
+	filepath.WalkDir(dataDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && d.Name() == "pg_wal" {
+			return filepath.SkipDir
+		}
+		if info, err := d.Info(); err == nil && info.Mode().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
+
+// parseRecoveryTarget reads an RFC 3339 timestamp or a local
+// "2006-01-02 15:04:05" time
+func parseRecoveryTarget(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid target time %q: use RFC 3339 (2024-01-02T15:04:05Z) or 2006-01-02 15:04:05", value)
+}
+
+// handleBackupPITR starts a point-in-time restore of PostgreSQL
+func (s *Supervisor) handleBackupPITR(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	archive, err := parseWALArchive(s.config.WALArchive)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	if archive == nil {
+		return ipc.NewResponse(msg.ID, false, nil, "WAL archiving is not configured (tmidb-cli config set wal_archive <url>)")
+	}
+
+	targetStr, _ := msg.Data["target"].(string)
+	if targetStr == "" {
+		return ipc.NewResponse(msg.ID, false, nil, "target time is required")
+	}
+	target, err := parseRecoveryTarget(targetStr)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	if target.After(time.Now()) {
+		return ipc.NewResponse(msg.ID, false, nil, "target time is in the future")
+	}
+
+	// Pick the base backup up front so bad requests fail immediately
+	label, _ := msg.Data["base"].(string)
+	backups, err := readBaseBackups(context.Background(), archive)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, fmt.Sprintf("failed to read base backup index: %v", err))
+	}
+	base, err := baseBackupFor(backups, target, label)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+
+	if !walJobs.TryLock() {
+		return ipc.NewResponse(msg.ID, false, nil, "another base backup or point-in-time restore is running")
+	}
+
+	restoreID := fmt.Sprintf("pitr-%d", time.Now().Unix())
+	progress := &RestoreProgress{
+		ID:        restoreID,
+		Status:    "restoring",
+		Current:   "Initializing point-in-time restore",
+		StartTime: time.Now(),
+	}
+	s.restoreProgress[restoreID] = progress
+
+	go func() {
+		defer walJobs.Unlock()
+		s.performPITR(archive, base, target, progress)
+	}()
+
+	return ipc.NewResponse(msg.ID, true, map[string]interface{}{
+		"id":     restoreID,
+		"base":   base.Label,
+		"target": target.Format(time.RFC3339),
+	}, "")
+}
+
+// performPITR replaces the data directory with a base backup and replays the
+// archived WAL up to target. The previous data directory is kept next to it.
+func (s *Supervisor) performPITR(archive *walArchive, base *BaseBackup, target time.Time, progress *RestoreProgress) {
+	fail := func(err error) {
+		progress.Status = "failed"
+		progress.Error = err.Error()
+		now := time.Now()
+		progress.EndTime = &now
+		log.Printf("❌ Point-in-time restore failed: %v", err)
+	}
+
+	log.Printf("⏪ Point-in-time restore to %s from base backup %s", target.Format(time.RFC3339), base.Label)
+
+	progress.Current = "Stopping PostgreSQL"
+	if err := s.stopPostgreSQL(postgresDataDir); err != nil {
+		fail(err)
+		return
+	}
+
+	progress.Current = "Moving current data directory aside"
+	progress.Percent = 5
+	previous := postgresDataDir + ".pre-pitr-" + time.Now().Format("20060102-150405")
+	if err := os.Rename(postgresDataDir, previous); err != nil {
+		fail(fmt.Errorf("failed to move data directory: %w", err))
+		return
+	}
+
+	uid, gid, err := postgresOwner()
+	if err == nil {
+		err = os.Mkdir(postgresDataDir, 0700)
+	}
+	if err == nil {
+		err = os.Chown(postgresDataDir, uid, gid)
+	}
+	if err == nil {
+		err = s.downloadBaseBackup(archive, base, postgresDataDir, uid, gid, progress)
+	}
+	if err == nil {
+		err = writeRecoverySignal(postgresDataDir, target, uid, gid)
+	}
+	if err != nil {
+		fail(fmt.Errorf("%v (previous data directory kept at %s)", err, previous))
+		return
+	}
+
+	progress.Current = "Starting PostgreSQL in recovery"
+	progress.Percent = 50
+	if _, ok := s.config.Containers["postgresql"]; ok {
+		// The container was stopped above and mounts the restored data directory
+		err = s.processManager.StartProcess("postgresql")
+	} else {
+		err = s.restartPostgreSQL()
+	}
+	if err != nil {
+		fail(fmt.Errorf("%v (previous data directory kept at %s)", err, previous))
+		return
+	}
+
+	if err := s.waitForRecovery(base, target, progress); err != nil {
+		fail(fmt.Errorf("%v (previous data directory kept at %s)", err, previous))
+		return
+	}
+
+	// The target is no longer needed once promoted; PostgreSQL removes recovery.signal itself
+	if _, err := writeManagedConf(postgresDataDir, postgresRecoveryFile, postgresRecoveryInclude, nil); err != nil {
+		log.Printf("⚠️ Failed to remove %s: %v", postgresRecoveryFile, err)
+	}
+
+	progress.Current = fmt.Sprintf("Recovered to %s; previous data kept at %s", target.Format(time.RFC3339), previous)
+	progress.Percent = 100
+	progress.Status = "completed"
+	now := time.Now()
+	progress.EndTime = &now
+	log.Printf("✅ Point-in-time restore to %s completed (previous data directory: %s)", target.Format(time.RFC3339), previous)
+}
+
+// stopPostgreSQL stops watching PostgreSQL so it is not auto-restarted, then
+// asks the postmaster for a fast shutdown and waits for it to exit
+func (s *Supervisor) stopPostgreSQL(dataDir string) error {
+	s.processManager.StopProcess("postgresql")
+
+	data, err := os.ReadFile(filepath.Join(dataDir, "postmaster.pid"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read postmaster.pid: %w", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(strings.SplitN(string(data), "\n", 2)[0]))
+	if err != nil {
+		return fmt.Errorf("invalid postmaster.pid: %w", err)
+	}
+
+	if err := sysinfo.Signal(pid, syscall.SIGINT); err != nil {
+		if errors.Is(err, syscall.ESRCH) {
+			return nil
+		}
+		return fmt.Errorf("failed to stop PostgreSQL: %w", err)
+	}
+	for i := 0; i < 120; i++ {
+		if !s.isProcessRunning(pid) {
+			return nil
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	return fmt.Errorf("PostgreSQL (PID %d) did not stop within 60s", pid)
+}
+
+// postgresOwner returns the uid and gid PostgreSQL runs as
+func postgresOwner() (int, int, error) {
+	u, err := user.Lookup("postgres")
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to look up postgres user: %w", err)
+	}
+	uid, _ := strconv.Atoi(u.Uid)
+	gid, _ := strconv.Atoi(u.Gid)
+	return uid, gid, nil
+}
+
+// downloadBaseBackup extracts a base backup into dataDir, owned by uid/gid
+func (s *Supervisor) downloadBaseBackup(archive *walArchive, base *BaseBackup, dataDir string, uid, gid int, progress *RestoreProgress) error {
+	object, err := archive.client().Get(context.Background(), archive.basePath(base.Label))
+	if err != nil {
+		return fmt.Errorf("failed to download base backup %s: %w", base.Label, err)
+	}
+	defer object.Body.Close()
+
+	size := object.Size
+	if size <= 0 {
+		size = base.Size
+	}
+	var read int64
+	body := &countingReader{r: object.Body, onRead: func(n int) {
+		read += int64(n)
+		progress.Current = fmt.Sprintf("Restoring base backup %s (%.1f MB)", base.Label, float64(read)/(1024*1024))
+		if size > 0 {
+			progress.Percent = 5 + min(float64(read)/float64(size), 1)*40
+		}
+	}}
+
+	gz, err := gzip.NewReader(body)
+	if err != nil {
+		return fmt.Errorf("invalid base backup %s: %w", base.Label, err)
+	}
+	if err := extractTar(tar.NewReader(gz), dataDir, uid, gid); err != nil {
+		return fmt.Errorf("failed to extract base backup %s: %w", base.Label, err)
+	}
+	return nil
+}
+
+// extractTar unpacks a pg_basebackup tar stream into dir. Entries that would
+// land outside dir are rejected.
+func extractTar(tr *tar.Reader, dir string, uid, gid int) error {
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.Clean(header.Name)
+		if name == "." {
+			continue
+		}
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("unsafe path %q in archive", header.Name)
+		}
+		target := filepath.Join(dir, name)
+		mode := os.FileMode(header.Mode).Perm()
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0700); err != nil {
+				return err
+			}
+			os.Chmod(target, mode)
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(file, tr)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			// Tablespace links in pg_tblspc
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		default:
+			continue
+		}
+
+		if err := os.Lchown(target, uid, gid); err != nil {
+			return err
+		}
+	}
+}
+
+// writeRecoverySignal sets the recovery target and creates recovery.signal
+func writeRecoverySignal(dataDir string, target time.Time, uid, gid int) error {
+	// Drop the archive settings captured in the backup; the current ones are rendered on start
+	for _, file := range []string{postgresArchiveFile, postgresRecoveryFile} {
+		os.Remove(filepath.Join(dataDir, file))
+	}
+	if _, err := writeManagedConf(dataDir, postgresRecoveryFile, postgresRecoveryInclude, renderRecoveryTarget(target)); err != nil {
+		return err
+	}
+	signal := filepath.Join(dataDir, "recovery.signal")
+	if err := os.WriteFile(signal, nil, 0600); err != nil {
+		return err
+	}
+	for _, file := range []string{signal, filepath.Join(dataDir, postgresRecoveryFile)} {
+		if err := os.Chown(file, uid, gid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForRecovery reports replay progress until PostgreSQL has been promoted.
+// PostgreSQL shuts down by itself if the archive ends before the target.
+func (s *Supervisor) waitForRecovery(base *BaseBackup, target time.Time, progress *RestoreProgress) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %v", err)
+	}
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	span := target.Sub(base.StartedAt).Seconds()
+	for {
+		if _, err := os.Stat(filepath.Join(postgresDataDir, "postmaster.pid")); os.IsNotExist(err) {
+			return errors.New("PostgreSQL stopped during recovery; the archive may end before the target time, see the postgresql log")
+		}
+
+		var inRecovery bool
+		var replayed sql.NullTime
+		err := db.QueryRow("SELECT pg_is_in_recovery(), pg_last_xact_replay_timestamp()").Scan(&inRecovery, &replayed)
+		switch {
+		case err != nil:
+			// Connections are refused until recovery reaches a consistent state
+			progress.Current = "Replaying WAL (waiting for a consistent state)"
+		case !inRecovery:
+			return nil
+		case replayed.Valid:
+			progress.Current = fmt.Sprintf("Replaying WAL (at %s)", replayed.Time.Format(time.RFC3339))
+			if span > 0 {
+				done := replayed.Time.Sub(base.StartedAt).Seconds() / span
+				progress.Percent = 50 + min(max(done, 0), 1)*45
+			}
+		default:
+			progress.Current = "Replaying WAL"
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// handleBackupWALStatus reports the archive location, the archiver statistics
+// and the base backups that can be restored from
+func (s *Supervisor) handleBackupWALStatus(conn *ipc.Connection, msg *ipc.Message) *ipc.Response {
+	archive, err := parseWALArchive(s.config.WALArchive)
+	if err != nil {
+		return ipc.NewResponse(msg.ID, false, nil, err.Error())
+	}
+	if archive == nil {
+		return ipc.NewResponse(msg.ID, true, map[string]interface{}{"enabled": false}, "")
+	}
+
+	result := map[string]interface{}{
+		"enabled": true,
+		"archive": archive.String(),
+	}
+
+	backups, err := readBaseBackups(context.Background(), archive)
+	if err != nil {
+		result["base_backups_error"] = err.Error()
+	}
+	result["base_backups"] = backups
+
+	if db, err := openPolicyDB(); err != nil {
+		result["archiver_error"] = err.Error()
+	} else {
+		defer db.Close()
+		var mode string
+		var archived, failed int64
+		var lastArchived, lastFailed sql.NullString
+		var lastArchivedAt, lastFailedAt sql.NullTime
+		err := db.QueryRow(`
+			SELECT current_setting('archive_mode'), archived_count, last_archived_wal, last_archived_time,
+				failed_count, last_failed_wal, last_failed_time
+			FROM pg_stat_archiver`).Scan(&mode, &archived, &lastArchived, &lastArchivedAt, &failed, &lastFailed, &lastFailedAt)
+		if err != nil {
+			result["archiver_error"] = err.Error()
+		} else {
+			archiver := map[string]interface{}{
+				"archive_mode":   mode,
+				"archived_count": archived,
+				"failed_count":   failed,
+			}
+			if lastArchived.Valid {
+				archiver["last_archived_wal"] = lastArchived.String
+				archiver["last_archived_time"] = lastArchivedAt.Time
+			}
+			if lastFailed.Valid {
+				archiver["last_failed_wal"] = lastFailed.String
+				archiver["last_failed_time"] = lastFailedAt.Time
+			}
+			result["archiver"] = archiver
+		}
+	}
+
+	return ipc.NewResponse(msg.ID, true, result, "")
+}
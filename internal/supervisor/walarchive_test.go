@@ -0,0 +1,143 @@
+package supervisor
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseWALArchive(t *testing.T) {
+	if a, err := parseWALArchive(""); a != nil || err != nil {
+		t.Errorf("empty archive = %v, %v", a, err)
+	}
+
+	a, err := parseWALArchive("http://filer:8888/buckets/tmidb-wal/")
+	if err != nil || a.filerURL != "http://filer:8888" || a.path != "/buckets/tmidb-wal" {
+		t.Errorf("filer URL = %+v, %v", a, err)
+	}
+
+	t.Setenv("SEAWEEDFS_FILER_URL", "http://seaweed:8888")
+	a, err = parseWALArchive("s3://backups/tmidb")
+	if err != nil || a.String() != "http://seaweed:8888/buckets/backups/tmidb" {
+		t.Errorf("s3 archive = %+v, %v", a, err)
+	}
+
+	for _, bad := range []string{"http://filer:8888", "ftp://filer/wal", "http://filer/wal'; rm -rf /", "http://filer/a/../b", "/data/wal"} {
+		if _, err := parseWALArchive(bad); err == nil {
+			t.Errorf("parseWALArchive(%q) accepted", bad)
+		}
+	}
+}
+
+func TestBaseBackupFor(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 2, 0, 0, 0, time.UTC) }
+	backups := []BaseBackup{
+		{Label: "b1", StartedAt: day(1), FinishedAt: day(1).Add(time.Hour)},
+		{Label: "b2", StartedAt: day(2), FinishedAt: day(2).Add(time.Hour)},
+		{Label: "b3", StartedAt: day(3), FinishedAt: day(3).Add(time.Hour)},
+	}
+
+	if b, err := baseBackupFor(backups, day(3), ""); err != nil || b.Label != "b2" {
+		t.Errorf("newest before target = %+v, %v", b, err)
+	}
+	if _, err := baseBackupFor(backups, day(1), ""); err == nil {
+		t.Error("target before every base backup accepted")
+	}
+	if b, err := baseBackupFor(backups, day(4), "b1"); err != nil || b.Label != "b1" {
+		t.Errorf("explicit base = %+v, %v", b, err)
+	}
+	if _, err := baseBackupFor(backups, day(2), "b3"); err == nil {
+		t.Error("base backup finished after target accepted")
+	}
+}
+
+// fakeFiler stores files in memory like the SeaweedFS filer HTTP API
+func fakeFiler(t *testing.T) *httptest.Server {
+	var mu sync.Mutex
+	files := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			files[r.URL.Path] = data
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			data, ok := files[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(data)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPushFetchWAL(t *testing.T) {
+	archive, err := parseWALArchive(fakeFiler(t).URL + "/buckets/wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+	name := "000000010000000000000003"
+	src := filepath.Join(dir, name)
+	os.WriteFile(src, []byte("segment"), 0600)
+
+	if err := pushWAL(ctx, archive, src, name); err != nil {
+		t.Fatalf("push: %v", err)
+	}
+	// Pushing the same content again succeeds, different content does not
+	if err := pushWAL(ctx, archive, src, name); err != nil {
+		t.Errorf("repeated push: %v", err)
+	}
+	os.WriteFile(src, []byte("other"), 0600)
+	if err := pushWAL(ctx, archive, src, name); err == nil {
+		t.Error("push over different content accepted")
+	}
+	if err := pushWAL(ctx, archive, src, "../../etc/passwd"); err == nil {
+		t.Error("push with unexpected name accepted")
+	}
+
+	dst := filepath.Join(dir, "RECOVERYXLOG")
+	if err := fetchWAL(ctx, archive, name, dst); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if data, _ := os.ReadFile(dst); string(data) != "segment" {
+		t.Errorf("fetched %q", data)
+	}
+	if err := fetchWAL(ctx, archive, "000000010000000000000004", dst); err == nil {
+		t.Error("fetch of missing segment succeeded")
+	}
+}
+
+func TestExtractTarRejectsUnsafePaths(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	tw.WriteHeader(&tar.Header{Name: "PG_VERSION", Mode: 0600, Size: 3, Typeflag: tar.TypeReg})
+	tw.Write([]byte("15\n"))
+	tw.WriteHeader(&tar.Header{Name: "../escape", Mode: 0600, Size: 1, Typeflag: tar.TypeReg})
+	tw.Write([]byte("x"))
+	tw.Close()
+
+	dir := t.TempDir()
+	err := extractTar(tar.NewReader(&buf), dir, os.Getuid(), os.Getgid())
+	if err == nil || !strings.Contains(err.Error(), "unsafe path") {
+		t.Errorf("extract = %v, want unsafe path error", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "PG_VERSION")); string(data) != "15\n" {
+		t.Errorf("PG_VERSION = %q", data)
+	}
+}
@@ -0,0 +1,537 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/ipc"
+	"github.com/tmidb/tmidb-core/internal/version"
+)
+
+const (
+	// defaultWatchdogTimeout is used when watchdog_timeout is not configured
+	defaultWatchdogTimeout = 30 * time.Second
+	// defaultStateFile is used when state_file is not configured
+	defaultStateFile = "./state/supervisor.json"
+	// statsInterval is how often periodicStatsUpdater runs
+	statsInterval = 10 * time.Second
+	// healthWriteTimeout bounds how long a health socket client may take to read the answer
+	healthWriteTimeout = time.Second
+)
+
+// Supervisor states reported on the health socket and in the state file
+const (
+	healthStarting = "starting"
+	healthOK       = "ok"
+	healthWedged   = "wedged"
+	healthStopping = "stopping"
+)
+
+// HealthStatus is the one-line JSON answer of the health socket
+type HealthStatus struct {
+	Status     string               `json:"status"` // starting, ok, wedged, stopping
+	Reason     string               `json:"reason,omitempty"`
+	PID        int                  `json:"pid"`
+	Version    string               `json:"version"`
+	Uptime     string               `json:"uptime"`
+	LastCheck  time.Time            `json:"last_check,omitempty"`
+	Heartbeats map[string]time.Time `json:"heartbeats,omitempty"`
+	Systemd    bool                 `json:"systemd"` // sending sd_notify watchdog pings
+}
+
+// SupervisorState is written to state_file after every successful watchdog
+// check, so the next supervisor knows whether the last one shut down cleanly
+// and which component processes it left behind
+type SupervisorState struct {
+	PID           int            `json:"pid"`
+	Version       string         `json:"version"`
+	StartedAt     time.Time      `json:"started_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	Status        string         `json:"status"`
+	CleanShutdown bool           `json:"clean_shutdown"`
+	Processes     []StateProcess `json:"processes"`
+}
+
+// StateProcess is a component process recorded in the state file
+type StateProcess struct {
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Status    string    `json:"status"`
+	PID       int       `json:"pid"`
+	StartTime time.Time `json:"start_time,omitempty"`
+}
+
+// heartbeat is the last time a background loop reported progress
+type heartbeat struct {
+	last     time.Time
+	interval time.Duration
+}
+
+// watchdog is the self-monitoring state of the supervisor. The health socket
+// only reads this struct, so it keeps answering while the rest is stuck.
+type watchdog struct {
+	mu        sync.Mutex
+	status    string
+	reason    string
+	lastCheck time.Time
+	beats     map[string]heartbeat
+	systemd   bool
+	ready     bool // Start has finished
+	startedAt time.Time
+
+	// probe started by an earlier check that has not returned yet
+	pending      chan []ipc.ProcessInfo
+	pendingSince time.Time
+
+	listener net.Listener
+	previous *SupervisorState
+}
+
+func (s *Supervisor) watchdogTimeout() time.Duration {
+	if s.config.WatchdogTimeout > 0 {
+		return s.config.WatchdogTimeout
+	}
+	return defaultWatchdogTimeout
+}
+
+func (s *Supervisor) stateFile() string {
+	if s.config.StateFile != "" {
+		return s.config.StateFile
+	}
+	return defaultStateFile
+}
+
+// processStateFile is where the process manager records the components it
+// started, next to the state file
+func (s *Supervisor) processStateFile() string {
+	return filepath.Join(filepath.Dir(s.stateFile()), "processes.json")
+}
+
+// startWatchdog opens the health socket and starts the self-monitoring loop
+func (s *Supervisor) startWatchdog() {
+	w := &s.watchdog
+	w.mu.Lock()
+	w.status = healthStarting
+	w.startedAt = time.Now()
+	w.mu.Unlock()
+
+	if s.config.HealthSocket != "" {
+		if err := s.startHealthSocket(s.config.HealthSocket); err != nil {
+			log.Printf("⚠️ Failed to start health socket: %v", err)
+		}
+	}
+
+	// systemd이 WatchdogSec=의 절반마다 핑을 기대함
+	interval := s.watchdogTimeout() / 3
+	if sd := sdWatchdogTimeout(); sd > 0 {
+		w.mu.Lock()
+		w.systemd = true
+		w.mu.Unlock()
+		if sd/2 < interval {
+			interval = sd / 2
+		}
+		log.Printf("🐕 systemd watchdog enabled (WatchdogSec=%s)", sd)
+	}
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	go s.runWatchdog(interval)
+}
+
+// runWatchdog checks the supervisor every interval until it stops
+func (s *Supervisor) runWatchdog(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if s.healthStatus().Status == healthStopping {
+				return
+			}
+			s.checkWatchdog(time.Now())
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// beat records that the background loop name is making progress. A loop that
+// has not beaten for interval plus watchdog_timeout marks the supervisor wedged.
+func (s *Supervisor) beat(name string, interval time.Duration) {
+	w := &s.watchdog
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.beats == nil {
+		w.beats = make(map[string]heartbeat)
+	}
+	w.beats[name] = heartbeat{last: time.Now(), interval: interval}
+}
+
+// checkWatchdog probes the locks every IPC request needs and the heartbeats of
+// the background loops. While everything responds within watchdog_timeout it
+// pings the systemd watchdog and saves the state file; otherwise the
+// supervisor is marked wedged.
+func (s *Supervisor) checkWatchdog(now time.Time) {
+	timeout := s.watchdogTimeout()
+	w := &s.watchdog
+
+	// 이전 점검의 프로브가 아직 막혀 있으면 새로 띄우지 않고 그것을 기다림
+	if w.pending == nil {
+		probe := make(chan []ipc.ProcessInfo, 1)
+		go func() {
+			s.configMutex.Lock()
+			s.configMutex.Unlock()
+			probe <- s.processManager.GetProcessList()
+		}()
+		w.pending, w.pendingSince = probe, now
+	}
+
+	var reasons []string
+	var processes []ipc.ProcessInfo
+	done := false
+	if wait := timeout - now.Sub(w.pendingSince); wait > 0 {
+		select {
+		case processes = <-w.pending:
+			done = true
+		case <-time.After(wait):
+		}
+	} else {
+		select {
+		case processes = <-w.pending:
+			done = true
+		default:
+		}
+	}
+	if done {
+		w.pending = nil
+	} else {
+		reasons = append(reasons, fmt.Sprintf("config or process table lock held for over %s", timeout))
+	}
+
+	w.mu.Lock()
+	names := make([]string, 0, len(w.beats))
+	for name := range w.beats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		beat := w.beats[name]
+		if late := now.Sub(beat.last); late > beat.interval+timeout {
+			reasons = append(reasons, fmt.Sprintf("%s has not run for %s", name, late.Round(time.Second)))
+		}
+	}
+	w.mu.Unlock()
+
+	if len(reasons) > 0 {
+		s.markWedged(strings.Join(reasons, "; "))
+		return
+	}
+	s.markHealthy(now)
+	s.updateDiscovery(processes)
+
+	if err := s.saveState(processes, false); err != nil {
+		log.Printf("⚠️ Failed to save supervisor state: %v", err)
+	}
+}
+
+// markWedged reports a stuck supervisor once per episode: it stops the systemd
+// watchdog pings, writes a goroutine dump and, with watchdog_exit, exits so the
+// init system restarts the supervisor
+func (s *Supervisor) markWedged(reason string) {
+	w := &s.watchdog
+	w.mu.Lock()
+	first := w.status != healthWedged
+	if w.status == healthStopping {
+		// 종료 중에는 프로세스 정지가 락을 오래 잡을 수 있음
+		first = false
+	} else {
+		w.status, w.reason = healthWedged, reason
+	}
+	w.mu.Unlock()
+	if !first {
+		return
+	}
+
+	log.Printf("🚨 Supervisor is wedged: %s", reason)
+	sdNotify("STATUS=wedged: " + reason)
+	s.dumpGoroutines(reason)
+	// 이벤트 버스가 막혀 있을 수 있으므로 기다리지 않음
+	go s.publishEvent(ipc.Event{Type: ipc.EventSupervisorWedged, Component: "supervisor", Message: reason})
+
+	if s.config.WatchdogExit {
+		log.Printf("🚨 Exiting so the init system restarts the supervisor (watchdog_exit)")
+		os.Exit(2)
+	}
+}
+
+// markHealthy clears a wedged state and pings the systemd watchdog
+func (s *Supervisor) markHealthy(now time.Time) {
+	w := &s.watchdog
+	w.mu.Lock()
+	recovered := w.status == healthWedged
+	if recovered {
+		w.status, w.reason = healthStarting, ""
+		if w.ready {
+			w.status = healthOK
+		}
+	}
+	w.lastCheck = now
+	systemd := w.systemd
+	w.mu.Unlock()
+
+	if recovered {
+		log.Println("✅ Supervisor recovered from wedged state")
+		sdNotify("STATUS=")
+	}
+	if systemd {
+		if _, err := sdNotify("WATCHDOG=1"); err != nil {
+			log.Printf("⚠️ Failed to ping systemd watchdog: %v", err)
+		}
+	}
+}
+
+// setHealth moves the supervisor to a lifecycle state and tells systemd about it
+func (s *Supervisor) setHealth(status string) {
+	w := &s.watchdog
+	w.mu.Lock()
+	w.status, w.reason = status, ""
+	if status == healthOK {
+		w.ready = true
+	}
+	w.mu.Unlock()
+
+	switch status {
+	case healthOK:
+		sdNotify(fmt.Sprintf("READY=1\nMAINPID=%d", os.Getpid()))
+	case healthStopping:
+		sdNotify("STOPPING=1")
+	}
+}
+
+// stopWatchdog closes the health socket and records the clean shutdown
+func (s *Supervisor) stopWatchdog() {
+	w := &s.watchdog
+	w.mu.Lock()
+	listener := w.listener
+	w.listener = nil
+	w.mu.Unlock()
+	if listener != nil {
+		listener.Close()
+		os.Remove(s.config.HealthSocket)
+	}
+
+	if err := s.saveState(nil, true); err != nil {
+		log.Printf("⚠️ Failed to save supervisor state: %v", err)
+	}
+}
+
+// healthStatus returns the current self-monitoring state without touching any
+// other supervisor lock
+func (s *Supervisor) healthStatus() HealthStatus {
+	w := &s.watchdog
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	status := HealthStatus{
+		Status:    w.status,
+		Reason:    w.reason,
+		PID:       os.Getpid(),
+		Version:   version.Version,
+		LastCheck: w.lastCheck,
+		Systemd:   w.systemd,
+	}
+	if status.Status == "" {
+		status.Status = healthStarting
+	}
+	if !w.startedAt.IsZero() {
+		status.Uptime = time.Since(w.startedAt).Round(time.Second).String()
+	}
+	if len(w.beats) > 0 {
+		status.Heartbeats = make(map[string]time.Time, len(w.beats))
+		for name, beat := range w.beats {
+			status.Heartbeats[name] = beat.last
+		}
+	}
+	return status
+}
+
+// startHealthSocket listens on a unix socket that answers every connection
+// with the HealthStatus as one line of JSON
+func (s *Supervisor) startHealthSocket(path string) error {
+	// 이전 실행이 남긴 소켓 파일 정리
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale health socket: %w", err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+
+	s.watchdog.mu.Lock()
+	s.watchdog.listener = listener
+	s.watchdog.mu.Unlock()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.SetWriteDeadline(time.Now().Add(healthWriteTimeout))
+				json.NewEncoder(conn).Encode(s.healthStatus())
+			}()
+		}
+	}()
+
+	log.Printf("🩺 Health socket listening on %s", path)
+	return nil
+}
+
+// CheckHealth reads the HealthStatus from the health socket of a running supervisor
+func CheckHealth(path string, timeout time.Duration) (*HealthStatus, error) {
+	conn, err := net.DialTimeout("unix", path, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(timeout))
+
+	var status HealthStatus
+	if err := json.NewDecoder(conn).Decode(&status); err != nil {
+		return nil, fmt.Errorf("invalid health socket answer: %w", err)
+	}
+	return &status, nil
+}
+
+// saveState atomically replaces the state file
+func (s *Supervisor) saveState(processes []ipc.ProcessInfo, clean bool) error {
+	health := s.healthStatus()
+	s.watchdog.mu.Lock()
+	startedAt := s.watchdog.startedAt
+	s.watchdog.mu.Unlock()
+	state := SupervisorState{
+		PID:           health.PID,
+		Version:       health.Version,
+		StartedAt:     startedAt,
+		UpdatedAt:     time.Now(),
+		Status:        health.Status,
+		CleanShutdown: clean,
+		Processes:     []StateProcess{},
+	}
+	for _, p := range processes {
+		state.Processes = append(state.Processes, StateProcess{
+			Name:      p.Name,
+			Type:      p.Type,
+			Status:    p.Status,
+			PID:       p.PID,
+			StartTime: p.StartTime,
+		})
+	}
+	sort.Slice(state.Processes, func(i, j int) bool { return state.Processes[i].Name < state.Processes[j].Name })
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := s.stateFile()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// loadState reads the state file left by the previous supervisor (nil if there is none)
+func loadState(path string) (*SupervisorState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var state SupervisorState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// reportPreviousShutdown warns when the previous supervisor did not stop cleanly
+func (s *Supervisor) reportPreviousShutdown() {
+	previous := s.watchdog.previous
+	if previous == nil || previous.CleanShutdown {
+		return
+	}
+
+	message := fmt.Sprintf("previous supervisor (pid %d) did not shut down cleanly; last seen %s as %s",
+		previous.PID, previous.UpdatedAt.Format(time.RFC3339), previous.Status)
+	log.Printf("⚠️ %s", message)
+	s.publishEvent(ipc.Event{
+		Type:      ipc.EventSupervisorUnclean,
+		Component: "supervisor",
+		Message:   message,
+		Data: map[string]interface{}{
+			"pid":        previous.PID,
+			"updated_at": previous.UpdatedAt,
+			"processes":  previous.Processes,
+		},
+	})
+}
+
+// dumpGoroutines writes the stacks of all goroutines to a crash dump of the
+// supervisor itself, so the lock it is stuck on can be found afterwards
+func (s *Supervisor) dumpGoroutines(reason string) {
+	now := time.Now()
+	id := fmt.Sprintf("supervisor-%s-%d", now.Format("20060102-150405"), os.Getpid())
+	dir := filepath.Join(s.crashDir(), id)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		log.Printf("⚠️ Failed to create crash dump directory for the supervisor: %v", err)
+		return
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, "goroutines.txt"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		log.Printf("⚠️ Failed to write goroutine dump: %v", err)
+		return
+	}
+	pprof.Lookup("goroutine").WriteTo(f, 2)
+	f.Close()
+
+	dump := &CrashDump{
+		ID:        id,
+		Component: "supervisor",
+		Time:      now,
+		PID:       os.Getpid(),
+		Error:     "wedged: " + reason,
+		Uptime:    s.healthStatus().Uptime,
+		Command:   os.Args[0],
+		Args:      os.Args[1:],
+		Dir:       dir,
+		Files:     []string{crashSummaryFile, "goroutines.txt"},
+	}
+	summary, _ := json.MarshalIndent(dump, "", "  ")
+	if err := os.WriteFile(filepath.Join(dir, crashSummaryFile), summary, 0600); err != nil {
+		log.Printf("⚠️ Failed to write crash summary %s: %v", id, err)
+		return
+	}
+
+	log.Printf("💥 Goroutine dump of the supervisor written to %s", dir)
+	s.pruneCrashDumps("supervisor")
+}
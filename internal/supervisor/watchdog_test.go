@@ -0,0 +1,151 @@
+package supervisor
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/process"
+)
+
+func TestSdNotify(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if sent, err := sdNotify("READY=1"); sent || err != nil {
+		t.Fatalf("sdNotify without NOTIFY_SOCKET = %v, %v", sent, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets unavailable: %v", err)
+	}
+	defer conn.Close()
+	t.Setenv("NOTIFY_SOCKET", path)
+
+	if sent, err := sdNotify("WATCHDOG=1"); !sent || err != nil {
+		t.Fatalf("sdNotify = %v, %v", sent, err)
+	}
+	buf := make([]byte, 64)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(buf[:n]); got != "WATCHDOG=1" {
+		t.Errorf("received %q", got)
+	}
+}
+
+func TestSdWatchdogTimeout(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "20000000")
+	t.Setenv("WATCHDOG_PID", "")
+	if got := sdWatchdogTimeout(); got != 20*time.Second {
+		t.Errorf("sdWatchdogTimeout() = %s, want 20s", got)
+	}
+
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+	if got := sdWatchdogTimeout(); got != 0 {
+		t.Errorf("watchdog meant for another process: got %s", got)
+	}
+
+	t.Setenv("WATCHDOG_USEC", "")
+	if got := sdWatchdogTimeout(); got != 0 {
+		t.Errorf("no WatchdogSec: got %s", got)
+	}
+}
+
+func newWatchdogSupervisor(t *testing.T) *Supervisor {
+	t.Setenv("NOTIFY_SOCKET", "")
+	dir := t.TempDir()
+	return &Supervisor{
+		config: &Config{
+			WatchdogTimeout: 100 * time.Millisecond,
+			CrashDir:        filepath.Join(dir, "crashes"),
+			StateFile:       filepath.Join(dir, "state", "supervisor.json"),
+			HealthSocket:    filepath.Join(dir, "health.sock"),
+		},
+		processManager: process.NewManager(nil, nil),
+	}
+}
+
+func TestCheckWatchdog(t *testing.T) {
+	s := newWatchdogSupervisor(t)
+	s.setHealth(healthOK)
+
+	s.checkWatchdog(time.Now())
+	if status := s.healthStatus(); status.Status != healthOK || status.LastCheck.IsZero() {
+		t.Fatalf("healthy supervisor reported %+v", status)
+	}
+	state, err := loadState(s.config.StateFile)
+	if err != nil || state == nil {
+		t.Fatalf("state file not written: %v", err)
+	}
+	if state.CleanShutdown || state.Status != healthOK {
+		t.Errorf("running state = %+v", state)
+	}
+
+	// 락을 잡은 채 멈춘 경우
+	s.configMutex.Lock()
+	s.checkWatchdog(time.Now())
+	status := s.healthStatus()
+	if status.Status != healthWedged || !strings.Contains(status.Reason, "lock held") {
+		t.Fatalf("blocked config lock reported %+v", status)
+	}
+	dumps, err := s.listCrashDumps("supervisor")
+	if err != nil || len(dumps) != 1 {
+		t.Fatalf("goroutine dump not written: %v %v", dumps, err)
+	}
+	if _, err := os.Stat(filepath.Join(dumps[0].Dir, "goroutines.txt")); err != nil {
+		t.Error(err)
+	}
+
+	s.configMutex.Unlock()
+	for len(s.watchdog.pending) == 0 {
+		time.Sleep(time.Millisecond) // 막혀 있던 프로브가 끝날 때까지
+	}
+	s.checkWatchdog(time.Now())
+	if status := s.healthStatus(); status.Status != healthOK {
+		t.Fatalf("released lock reported %+v", status)
+	}
+
+	// 멈춘 백그라운드 루프
+	s.beat("stats", time.Second)
+	s.checkWatchdog(time.Now().Add(2 * time.Second))
+	if status := s.healthStatus(); status.Status != healthWedged || !strings.Contains(status.Reason, "stats has not run") {
+		t.Fatalf("stale heartbeat reported %+v", status)
+	}
+}
+
+func TestHealthSocket(t *testing.T) {
+	s := newWatchdogSupervisor(t)
+	if err := s.startHealthSocket(s.config.HealthSocket); err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+
+	status, err := CheckHealth(s.config.HealthSocket, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != healthStarting || status.PID != os.Getpid() {
+		t.Errorf("starting supervisor reported %+v", status)
+	}
+
+	s.setHealth(healthOK)
+	if status, err = CheckHealth(s.config.HealthSocket, time.Second); err != nil || status.Status != healthOK {
+		t.Errorf("ready supervisor reported %+v, %v", status, err)
+	}
+
+	s.setHealth(healthStopping)
+	s.stopWatchdog()
+	if _, err := os.Stat(s.config.HealthSocket); !os.IsNotExist(err) {
+		t.Errorf("health socket left behind: %v", err)
+	}
+	state, err := loadState(s.config.StateFile)
+	if err != nil || state == nil || !state.CleanShutdown {
+		t.Errorf("clean shutdown not recorded: %+v, %v", state, err)
+	}
+}
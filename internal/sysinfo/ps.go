@@ -0,0 +1,80 @@
+package sysinfo
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// psProcess ps로 프로세스의 RSS와 CPU 시간을 조회 (/proc이 없는 유닉스용)
+func psProcess(pid int) (rss uint64, cpu time.Duration, err error) {
+	out, err := exec.Command("ps", "-o", "rss=,time=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("ps -p %d: %w", pid, err)
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected ps output %q", out)
+	}
+	kb, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected ps rss %q", fields[0])
+	}
+	cpu, err = parsePSTime(fields[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return kb * 1024, cpu, nil
+}
+
+// psTotalCPUTime 모든 프로세스의 CPU 시간 합계 (이미 끝난 프로세스의 시간은 빠짐)
+func psTotalCPUTime() (time.Duration, error) {
+	out, err := exec.Command("ps", "-A", "-o", "time=").Output()
+	if err != nil {
+		return 0, fmt.Errorf("ps -A: %w", err)
+	}
+	var total time.Duration
+	for _, field := range strings.Fields(string(out)) {
+		d, err := parsePSTime(field)
+		if err != nil {
+			return 0, err
+		}
+		total += d
+	}
+	return total, nil
+}
+
+// parsePSTime ps의 CPU 시간 "[dd-][hh:]mm:ss[.cc]"
+func parsePSTime(s string) (time.Duration, error) {
+	var days int64
+	rest := s
+	if d, r, ok := strings.Cut(s, "-"); ok {
+		n, err := strconv.ParseInt(d, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid cpu time %q", s)
+		}
+		days, rest = n, r
+	}
+
+	parts := strings.Split(rest, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid cpu time %q", s)
+	}
+	seconds, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cpu time %q", s)
+	}
+	total := time.Duration(days)*24*time.Hour + time.Duration(seconds*float64(time.Second))
+	unit := time.Minute
+	for i := len(parts) - 2; i >= 0; i-- {
+		n, err := strconv.ParseInt(parts[i], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid cpu time %q", s)
+		}
+		total += time.Duration(n) * unit
+		unit *= 60
+	}
+	return total, nil
+}
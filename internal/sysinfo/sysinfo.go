@@ -0,0 +1,59 @@
+// Package sysinfo 운영체제별 프로세스·CPU·메모리·디스크 정보 조회.
+//
+// 리눅스는 /proc, macOS는 sysctl과 ps, 윈도우는 Win32 API를 사용하며 빌드 태그로
+// 구현을 고른다. 슈퍼바이저와 프로세스 관리자는 /proc이나 syscall.Statfs를 직접
+// 읽지 않고 이 패키지를 통해 조회한다. 지원하지 않는 항목은 ErrUnsupported를 반환한다.
+package sysinfo
+
+import (
+	"errors"
+	"runtime"
+	"time"
+)
+
+// ErrUnsupported 현재 운영체제에서 제공하지 않는 정보
+var ErrUnsupported = errors.New("not supported on " + runtime.GOOS)
+
+// Memory 호스트 메모리 (바이트)
+type Memory struct {
+	Total     uint64 `json:"total"`
+	Available uint64 `json:"available"` // 스왑 없이 새 프로세스가 쓸 수 있는 양
+}
+
+// UsagePercent 사용 중인 메모리 비율
+func (m Memory) UsagePercent() float64 {
+	if m.Total == 0 || m.Available > m.Total {
+		return 0
+	}
+	return float64(m.Total-m.Available) / float64(m.Total) * 100
+}
+
+// Disk 경로가 속한 파일 시스템 크기 (바이트)
+type Disk struct {
+	Total     uint64 `json:"total"`
+	Available uint64 `json:"available"` // root가 아닌 사용자가 쓸 수 있는 양
+}
+
+// UsagePercent 사용 중인 디스크 비율
+func (d Disk) UsagePercent() float64 {
+	if d.Total == 0 || d.Available > d.Total {
+		return 0
+	}
+	return float64(d.Total-d.Available) / float64(d.Total) * 100
+}
+
+// CPUTimes 부팅 이후 호스트 전체 CPU 시간 (모든 코어 합계). 두 시점의 차이로 사용률을 계산한다
+type CPUTimes struct {
+	Busy  time.Duration
+	Total time.Duration
+}
+
+// UsagePercent prev 이후 구간의 CPU 사용률 (0-100)
+func (t CPUTimes) UsagePercent(prev CPUTimes) (float64, bool) {
+	total := t.Total - prev.Total
+	busy := t.Busy - prev.Busy
+	if total <= 0 || busy < 0 {
+		return 0, false
+	}
+	return min(float64(busy)/float64(total)*100, 100), true
+}
@@ -0,0 +1,88 @@
+//go:build darwin
+
+package sysinfo
+
+import (
+	"runtime"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// ProcessRunning PID의 프로세스가 있는지 (시그널 0)
+func ProcessRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := unix.Kill(pid, 0)
+	return err == nil || err == unix.EPERM
+}
+
+// ProcessMemory 프로세스가 사용 중인 물리 메모리 (ps의 rss, 바이트)
+func ProcessMemory(pid int) (uint64, error) {
+	rss, _, err := psProcess(pid)
+	return rss, err
+}
+
+// ProcessCPUTime 프로세스가 사용한 CPU 시간 (ps의 time)
+func ProcessCPUTime(pid int) (time.Duration, error) {
+	_, cpu, err := psProcess(pid)
+	return cpu, err
+}
+
+// ProcessStartTime 프로세스 시작 시각을 나타내는 값 (kern.proc.pid의 p_starttime, 마이크로초).
+// 같은 PID라도 값이 다르면 다른 프로세스이며, 운영체제마다 단위가 다르므로 비교에만 쓴다
+func ProcessStartTime(pid int) (uint64, error) {
+	info, err := unix.SysctlKinfoProc("kern.proc.pid", pid)
+	if err != nil {
+		return 0, err
+	}
+	start := info.Proc.P_starttime
+	return uint64(start.Sec)*1_000_000 + uint64(start.Usec), nil
+}
+
+// SystemCPUTimes macOS는 cgo 없이 호스트 CPU 카운터를 읽을 수 없으므로, 실행 중인 모든
+// 프로세스의 CPU 시간 합계를 사용 시간으로, 부팅 후 경과 시간 × 코어 수를 전체 시간으로 근사한다.
+// 구간 사이에 끝난 프로세스가 있으면 그 구간은 사용률을 계산하지 않는다
+func SystemCPUTimes() (CPUTimes, error) {
+	boot, err := unix.SysctlTimeval("kern.boottime")
+	if err != nil {
+		return CPUTimes{}, err
+	}
+	busy, err := psTotalCPUTime()
+	if err != nil {
+		return CPUTimes{}, err
+	}
+	uptime := time.Since(time.Unix(boot.Unix()))
+	return CPUTimes{Busy: busy, Total: uptime * time.Duration(runtime.NumCPU())}, nil
+}
+
+// SystemMemory hw.memsize와 여유 페이지 (free + speculative)
+func SystemMemory() (Memory, error) {
+	total, err := unix.SysctlUint64("hw.memsize")
+	if err != nil {
+		return Memory{}, err
+	}
+	pageSize, err := unix.SysctlUint32("hw.pagesize")
+	if err != nil {
+		return Memory{}, err
+	}
+	free, err := unix.SysctlUint32("vm.page_free_count")
+	if err != nil {
+		return Memory{}, err
+	}
+	speculative, _ := unix.SysctlUint32("vm.page_speculative_count")
+	return Memory{Total: total, Available: (uint64(free) + uint64(speculative)) * uint64(pageSize)}, nil
+}
+
+// DiskUsage path가 속한 파일 시스템 크기
+func DiskUsage(path string) (Disk, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return Disk{}, err
+	}
+	return Disk{
+		Total:     stat.Blocks * uint64(stat.Bsize),
+		Available: stat.Bavail * uint64(stat.Bsize),
+	}, nil
+}
@@ -0,0 +1,184 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// clockTicksPerSecond USER_HZ (리눅스에서는 100)
+const clockTicksPerSecond = 100
+
+// ticks 클럭 틱을 시간으로 변환
+func ticks(n int64) time.Duration {
+	return time.Duration(n) * time.Second / clockTicksPerSecond
+}
+
+// ProcessRunning PID의 프로세스가 있는지 (/proc/<pid>)
+func ProcessRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	_, err := os.Stat(fmt.Sprintf("/proc/%d", pid))
+	return err == nil
+}
+
+// procStat /proc/<pid>/stat에서 comm 뒤의 필드들 (fields[0]이 3번째 필드 state)
+func procStat(pid int) ([]string, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return nil, err
+	}
+	// comm 필드에 공백이나 괄호가 있을 수 있으므로 마지막 ')' 이후부터 파싱
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return nil, fmt.Errorf("unexpected /proc/%d/stat format", pid)
+	}
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 20 {
+		return nil, fmt.Errorf("unexpected /proc/%d/stat format", pid)
+	}
+	return fields, nil
+}
+
+// ProcessMemory 프로세스가 사용 중인 물리 메모리 (VmRSS, 바이트)
+func ProcessMemory(pid int) (uint64, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(line, "VmRSS:"); ok {
+			fields := strings.Fields(rest)
+			if len(fields) == 0 {
+				break
+			}
+			kb, err := strconv.ParseUint(fields[0], 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			return kb * 1024, nil
+		}
+	}
+	// 커널 스레드 등은 VmRSS가 없음
+	return 0, nil
+}
+
+// ProcessCPUTime 프로세스가 사용한 CPU 시간 (utime+stime)
+func ProcessCPUTime(pid int) (time.Duration, error) {
+	fields, err := procStat(pid)
+	if err != nil {
+		return 0, err
+	}
+	// fields[11] = utime, fields[12] = stime
+	utime, err := strconv.ParseInt(fields[11], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	stime, err := strconv.ParseInt(fields[12], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return ticks(utime + stime), nil
+}
+
+// ProcessStartTime 프로세스 시작 시각을 나타내는 값 (/proc/<pid>/stat의 starttime, 부팅 후 클럭 틱).
+// 같은 PID라도 값이 다르면 다른 프로세스이며, 운영체제마다 단위가 다르므로 비교에만 쓴다
+func ProcessStartTime(pid int) (uint64, error) {
+	fields, err := procStat(pid)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(fields[19], 10, 64)
+}
+
+// SystemCPUTimes /proc/stat의 cpu 합계 줄
+func SystemCPUTimes() (CPUTimes, error) {
+	data, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return CPUTimes{}, err
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	busy, total, ok := parseCPULine(line)
+	if !ok {
+		return CPUTimes{}, fmt.Errorf("unexpected /proc/stat format")
+	}
+	return CPUTimes{Busy: ticks(busy), Total: ticks(total)}, nil
+}
+
+// parseCPULine "cpu user nice system idle iowait irq softirq ..." 에서 사용/전체 틱
+func parseCPULine(line string) (busy, total int64, ok bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 8 || fields[0] != "cpu" {
+		return 0, 0, false
+	}
+
+	var idle int64
+	for i := 1; i < 8; i++ {
+		val, err := strconv.ParseInt(fields[i], 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		total += val
+		// idle + iowait
+		if i == 4 || i == 5 {
+			idle += val
+		}
+	}
+	return total - idle, total, true
+}
+
+// SystemMemory /proc/meminfo의 MemTotal과 MemAvailable
+func SystemMemory() (Memory, error) {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return Memory{}, err
+	}
+	defer file.Close()
+
+	var mem Memory
+	var haveTotal, haveAvailable bool
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			mem.Total, haveTotal = kb*1024, true
+		case "MemAvailable:":
+			mem.Available, haveAvailable = kb*1024, true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Memory{}, err
+	}
+	if !haveTotal || !haveAvailable {
+		return Memory{}, fmt.Errorf("MemTotal or MemAvailable not found in /proc/meminfo")
+	}
+	return mem, nil
+}
+
+// DiskUsage path가 속한 파일 시스템 크기
+func DiskUsage(path string) (Disk, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return Disk{}, err
+	}
+	return Disk{
+		Total:     stat.Blocks * uint64(stat.Bsize),
+		Available: stat.Bavail * uint64(stat.Bsize),
+	}, nil
+}
@@ -0,0 +1,16 @@
+package sysinfo
+
+import "testing"
+
+func TestParseCPULine(t *testing.T) {
+	busy, total, ok := parseCPULine("cpu  100 5 50 800 20 3 2 0 0 0")
+	if !ok || busy != 160 || total != 980 {
+		t.Errorf("got busy=%d total=%d ok=%v", busy, total, ok)
+	}
+
+	for _, line := range []string{"", "cpu0 1 2 3 4 5 6 7", "cpu 1 2 x 4 5 6 7", "cpu 1 2 3"} {
+		if _, _, ok := parseCPULine(line); ok {
+			t.Errorf("%q: expected parse failure", line)
+		}
+	}
+}
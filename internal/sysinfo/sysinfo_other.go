@@ -0,0 +1,49 @@
+//go:build unix && !linux && !darwin
+
+package sysinfo
+
+import (
+	"syscall"
+	"time"
+)
+
+// ProcessRunning PID의 프로세스가 있는지 (시그널 0)
+func ProcessRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// ProcessMemory 프로세스가 사용 중인 물리 메모리 (ps의 rss, 바이트)
+func ProcessMemory(pid int) (uint64, error) {
+	rss, _, err := psProcess(pid)
+	return rss, err
+}
+
+// ProcessCPUTime 프로세스가 사용한 CPU 시간 (ps의 time)
+func ProcessCPUTime(pid int) (time.Duration, error) {
+	_, cpu, err := psProcess(pid)
+	return cpu, err
+}
+
+// ProcessStartTime 이 플랫폼에서는 PID 재사용을 구분하지 않음
+func ProcessStartTime(pid int) (uint64, error) {
+	return 0, ErrUnsupported
+}
+
+// SystemCPUTimes 이 플랫폼에서는 호스트 CPU 시간을 제공하지 않음
+func SystemCPUTimes() (CPUTimes, error) {
+	return CPUTimes{}, ErrUnsupported
+}
+
+// SystemMemory 이 플랫폼에서는 호스트 메모리를 제공하지 않음
+func SystemMemory() (Memory, error) {
+	return Memory{}, ErrUnsupported
+}
+
+// DiskUsage 이 플랫폼에서는 디스크 사용량을 제공하지 않음
+func DiskUsage(path string) (Disk, error) {
+	return Disk{}, ErrUnsupported
+}
@@ -0,0 +1,82 @@
+package sysinfo
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParsePSTime(t *testing.T) {
+	cases := map[string]time.Duration{
+		"0:00.50":     500 * time.Millisecond,
+		"01:02":       time.Minute + 2*time.Second,
+		"1:02:03":     time.Hour + 2*time.Minute + 3*time.Second,
+		"2-01:00:00":  49 * time.Hour,
+		"10:05.25":    10*time.Minute + 5250*time.Millisecond,
+		"1-00:00:0.5": 24*time.Hour + 500*time.Millisecond,
+	}
+	for in, want := range cases {
+		got, err := parsePSTime(in)
+		if err != nil || got != want {
+			t.Errorf("parsePSTime(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"", "12", "1:2:3:4", "x:00", "a-00:00"} {
+		if _, err := parsePSTime(in); err == nil {
+			t.Errorf("parsePSTime(%q): expected error", in)
+		}
+	}
+}
+
+func TestCurrentProcess(t *testing.T) {
+	pid := os.Getpid()
+	if !ProcessRunning(pid) {
+		t.Fatal("current process not reported as running")
+	}
+	if ProcessRunning(0) {
+		t.Error("pid 0 reported as running")
+	}
+
+	if rss, err := ProcessMemory(pid); err != nil || rss == 0 {
+		t.Errorf("ProcessMemory = %d, %v", rss, err)
+	}
+	if _, err := ProcessCPUTime(pid); err != nil {
+		t.Errorf("ProcessCPUTime: %v", err)
+	}
+
+	start, err := ProcessStartTime(pid)
+	if errors.Is(err, ErrUnsupported) {
+		return
+	}
+	if err != nil || start == 0 {
+		t.Errorf("ProcessStartTime = %d, %v", start, err)
+	}
+	if again, _ := ProcessStartTime(pid); again != start {
+		t.Errorf("start time changed: %d != %d", again, start)
+	}
+}
+
+func TestHostStats(t *testing.T) {
+	mem, err := SystemMemory()
+	if errors.Is(err, ErrUnsupported) {
+		t.Skip("host stats not supported")
+	}
+	if err != nil || mem.Total == 0 || mem.Available > mem.Total {
+		t.Errorf("SystemMemory = %+v, %v", mem, err)
+	}
+	if usage := mem.UsagePercent(); usage < 0 || usage > 100 {
+		t.Errorf("memory usage = %f", usage)
+	}
+
+	disk, err := DiskUsage(".")
+	if err != nil || disk.Total == 0 || disk.Available > disk.Total {
+		t.Errorf("DiskUsage = %+v, %v", disk, err)
+	}
+
+	times, err := SystemCPUTimes()
+	if err != nil || times.Total <= 0 || times.Busy > times.Total {
+		t.Errorf("SystemCPUTimes = %+v, %v", times, err)
+	}
+}
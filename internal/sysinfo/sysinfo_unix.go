@@ -0,0 +1,10 @@
+//go:build unix
+
+package sysinfo
+
+import "syscall"
+
+// Signal 프로세스에 시그널을 보냄 (0은 프로세스가 있는지만 확인)
+func Signal(pid int, sig syscall.Signal) error {
+	return syscall.Kill(pid, sig)
+}
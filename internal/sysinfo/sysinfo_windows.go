@@ -0,0 +1,186 @@
+//go:build windows
+
+package sysinfo
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	kernel32                 = windows.NewLazySystemDLL("kernel32.dll")
+	procGetSystemTimes       = kernel32.NewProc("GetSystemTimes")
+	procGlobalMemoryStatusEx = kernel32.NewProc("GlobalMemoryStatusEx")
+	procGetProcessMemoryInfo = kernel32.NewProc("K32GetProcessMemoryInfo")
+)
+
+// stillActive GetExitCodeProcess가 실행 중인 프로세스에 돌려주는 값 (STILL_ACTIVE)
+const stillActive = 259
+
+// processMemoryCounters PROCESS_MEMORY_COUNTERS
+type processMemoryCounters struct {
+	CB                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
+}
+
+// memoryStatusEx MEMORYSTATUSEX
+type memoryStatusEx struct {
+	Length               uint32
+	MemoryLoad           uint32
+	TotalPhys            uint64
+	AvailPhys            uint64
+	TotalPageFile        uint64
+	AvailPageFile        uint64
+	TotalVirtual         uint64
+	AvailVirtual         uint64
+	AvailExtendedVirtual uint64
+}
+
+// filetimeDuration 100ns 단위 FILETIME 구간을 시간으로 변환
+func filetimeDuration(ft windows.Filetime) time.Duration {
+	return time.Duration(uint64(ft.HighDateTime)<<32|uint64(ft.LowDateTime)) * 100
+}
+
+// openProcess 조회 권한으로 프로세스 핸들을 엶
+func openProcess(pid int) (windows.Handle, error) {
+	return windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+}
+
+// ProcessRunning PID의 프로세스가 아직 끝나지 않았는지
+func ProcessRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	h, err := openProcess(pid)
+	if err != nil {
+		// 다른 사용자의 프로세스는 열 수 없지만 존재함
+		return err == windows.ERROR_ACCESS_DENIED
+	}
+	defer windows.CloseHandle(h)
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
+
+// ProcessMemory 프로세스의 작업 집합 크기 (바이트)
+func ProcessMemory(pid int) (uint64, error) {
+	h, err := openProcess(pid)
+	if err != nil {
+		return 0, err
+	}
+	defer windows.CloseHandle(h)
+
+	var counters processMemoryCounters
+	counters.CB = uint32(unsafe.Sizeof(counters))
+	if r, _, err := procGetProcessMemoryInfo.Call(uintptr(h), uintptr(unsafe.Pointer(&counters)), uintptr(counters.CB)); r == 0 {
+		return 0, err
+	}
+	return uint64(counters.WorkingSetSize), nil
+}
+
+// processTimes GetProcessTimes
+func processTimes(pid int) (creation, kernel, user windows.Filetime, err error) {
+	h, err := openProcess(pid)
+	if err != nil {
+		return
+	}
+	defer windows.CloseHandle(h)
+
+	var exit windows.Filetime
+	err = windows.GetProcessTimes(h, &creation, &exit, &kernel, &user)
+	return
+}
+
+// ProcessCPUTime 프로세스가 사용한 CPU 시간 (커널 + 사용자)
+func ProcessCPUTime(pid int) (time.Duration, error) {
+	_, kernel, user, err := processTimes(pid)
+	if err != nil {
+		return 0, err
+	}
+	return filetimeDuration(kernel) + filetimeDuration(user), nil
+}
+
+// ProcessStartTime 프로세스 시작 시각을 나타내는 값 (생성 시각 FILETIME).
+// 같은 PID라도 값이 다르면 다른 프로세스이며, 운영체제마다 단위가 다르므로 비교에만 쓴다
+func ProcessStartTime(pid int) (uint64, error) {
+	creation, _, _, err := processTimes(pid)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(creation.HighDateTime)<<32 | uint64(creation.LowDateTime), nil
+}
+
+// SystemCPUTimes GetSystemTimes (커널 시간에는 유휴 시간이 포함됨)
+func SystemCPUTimes() (CPUTimes, error) {
+	var idle, kernel, user windows.Filetime
+	if r, _, err := procGetSystemTimes.Call(
+		uintptr(unsafe.Pointer(&idle)),
+		uintptr(unsafe.Pointer(&kernel)),
+		uintptr(unsafe.Pointer(&user)),
+	); r == 0 {
+		return CPUTimes{}, err
+	}
+	total := filetimeDuration(kernel) + filetimeDuration(user)
+	return CPUTimes{Busy: total - filetimeDuration(idle), Total: total}, nil
+}
+
+// SystemMemory GlobalMemoryStatusEx의 전체/사용 가능 물리 메모리
+func SystemMemory() (Memory, error) {
+	var status memoryStatusEx
+	status.Length = uint32(unsafe.Sizeof(status))
+	if r, _, err := procGlobalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&status))); r == 0 {
+		return Memory{}, err
+	}
+	return Memory{Total: status.TotalPhys, Available: status.AvailPhys}, nil
+}
+
+// DiskUsage path가 속한 볼륨 크기
+func DiskUsage(path string) (Disk, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return Disk{}, err
+	}
+	var available, total, free uint64
+	if err := windows.GetDiskFreeSpaceEx(p, &available, &total, &free); err != nil {
+		return Disk{}, err
+	}
+	return Disk{Total: total, Available: available}, nil
+}
+
+// Signal 윈도우에는 시그널이 없으므로 SIGTERM과 SIGKILL은 프로세스를 바로 종료하고,
+// 0은 프로세스가 있는지만 확인한다
+func Signal(pid int, sig syscall.Signal) error {
+	switch sig {
+	case 0:
+		if !ProcessRunning(pid) {
+			return syscall.ESRCH
+		}
+		return nil
+	case syscall.SIGTERM, syscall.SIGKILL:
+		h, err := windows.OpenProcess(windows.PROCESS_TERMINATE, false, uint32(pid))
+		if err != nil {
+			if err == windows.ERROR_INVALID_PARAMETER {
+				return syscall.ESRCH
+			}
+			return err
+		}
+		defer windows.CloseHandle(h)
+		return windows.TerminateProcess(h, 1)
+	default:
+		return ErrUnsupported
+	}
+}
@@ -0,0 +1,322 @@
+// Package upgrade는 컴포넌트 바이너리 업그레이드 번들을 만들고 검증합니다.
+//
+// 번들은 tar.gz 파일입니다. 맨 앞에 manifest.json과 그 ed25519 서명 manifest.sig가
+// 오고, 그 뒤에 매니페스트에 적힌 바이너리들(bin/<component>)이 옵니다. 매니페스트에는
+// 바이너리마다 SHA-256이 들어 있으므로 서명 하나로 번들 전체가 보호됩니다.
+package upgrade
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 번들 안의 매니페스트와 서명 파일 이름
+const (
+	ManifestName  = "manifest.json"
+	SignatureName = "manifest.sig"
+)
+
+// maxManifestSize 매니페스트와 서명의 최대 크기 (잘못된 번들이 메모리를 다 쓰지 않도록)
+const maxManifestSize = 1 << 20
+
+// ErrUntrusted 서명이 신뢰하는 키 중 어느 것과도 맞지 않음
+var ErrUntrusted = errors.New("bundle signature does not match any trusted key")
+
+// File 번들에 들어 있는 바이너리 하나
+type File struct {
+	Component string `json:"component"`
+	Path      string `json:"path"` // 번들 안 경로 (bin/<component>)
+	SHA256    string `json:"sha256"`
+	Size      int64  `json:"size"`
+}
+
+// Manifest 번들 내용. 서명 대상입니다.
+type Manifest struct {
+	Version   string    `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Files     []File    `json:"files"`
+}
+
+// Components 번들에 들어 있는 컴포넌트 이름
+func (m *Manifest) Components() []string {
+	names := make([]string, len(m.Files))
+	for i, f := range m.Files {
+		names[i] = f.Component
+	}
+	return names
+}
+
+// GenerateKey 새 서명 키 쌍을 base64로 반환합니다
+func GenerateKey() (publicKey, privateKey string, err error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(pub), base64.StdEncoding.EncodeToString(priv), nil
+}
+
+// ParsePublicKey base64 공개 키를 읽습니다
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(data) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid upgrade public key")
+	}
+	return ed25519.PublicKey(data), nil
+}
+
+// ParsePrivateKey base64 개인 키를 읽습니다
+func ParsePrivateKey(s string) (ed25519.PrivateKey, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(data) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid upgrade private key")
+	}
+	return ed25519.PrivateKey(data), nil
+}
+
+// Create는 컴포넌트 이름 → 바이너리 경로의 바이너리들로 서명된 번들을 w에 씁니다
+func Create(w io.Writer, version string, binaries map[string]string, key ed25519.PrivateKey) (*Manifest, error) {
+	if len(binaries) == 0 {
+		return nil, fmt.Errorf("no binaries to bundle")
+	}
+
+	components := make([]string, 0, len(binaries))
+	for component := range binaries {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+
+	manifest := &Manifest{Version: version, CreatedAt: time.Now().UTC()}
+	for _, component := range components {
+		sum, size, err := hashFile(binaries[component])
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, File{
+			Component: component,
+			Path:      "bin/" + component,
+			SHA256:    sum,
+			Size:      size,
+		})
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	signature := ed25519.Sign(key, manifestData)
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, entry := range []struct {
+		name string
+		data []byte
+	}{
+		{ManifestName, manifestData},
+		{SignatureName, []byte(base64.StdEncoding.EncodeToString(signature))},
+	} {
+		if err := writeTarEntry(tw, entry.name, 0644, int64(len(entry.data)), bytes.NewReader(entry.data)); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range manifest.Files {
+		file, err := os.Open(binaries[f.Component])
+		if err != nil {
+			return nil, err
+		}
+		err = writeTarEntry(tw, f.Path, 0755, f.Size, file)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func writeTarEntry(tw *tar.Writer, name string, mode int64, size int64, r io.Reader) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    mode,
+		Size:    size,
+		ModTime: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := io.Copy(tw, r); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func hashFile(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, file)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// Extract는 번들의 서명을 신뢰하는 키로 검증한 뒤 바이너리를 dest(component)가
+// 돌려주는 경로에 실행 권한으로 씁니다. 해시가 맞지 않거나 매니페스트에 없는 파일이
+// 있으면 이미 쓴 파일을 지우고 오류를 반환합니다.
+func Extract(r io.Reader, trusted []ed25519.PublicKey, dest func(component string) (string, error)) (*Manifest, error) {
+	if len(trusted) == 0 {
+		return nil, fmt.Errorf("no trusted upgrade keys configured")
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a gzip bundle: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	// 매니페스트와 서명이 먼저 와야 바이너리를 쓰기 전에 검증할 수 있음
+	manifestData, err := readSmallEntry(tr, ManifestName)
+	if err != nil {
+		return nil, err
+	}
+	signatureData, err := readSmallEntry(tr, SignatureName)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signatureData)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", SignatureName, err)
+	}
+	if !verify(manifestData, signature, trusted) {
+		return nil, ErrUntrusted
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ManifestName, err)
+	}
+	if len(manifest.Files) == 0 {
+		return nil, fmt.Errorf("bundle contains no binaries")
+	}
+	expected := make(map[string]File, len(manifest.Files))
+	for _, f := range manifest.Files {
+		expected[f.Path] = f
+	}
+
+	var written []string
+	cleanup := func() {
+		for _, path := range written {
+			os.Remove(path)
+		}
+	}
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			cleanup()
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		f, ok := expected[header.Name]
+		if !ok {
+			cleanup()
+			return nil, fmt.Errorf("bundle entry %s is not in the manifest", header.Name)
+		}
+		delete(expected, header.Name)
+
+		path, err := dest(f.Component)
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		written = append(written, path)
+		if err := writeVerified(path, tr, f); err != nil {
+			cleanup()
+			return nil, err
+		}
+	}
+
+	if len(expected) > 0 {
+		cleanup()
+		missing := make([]string, 0, len(expected))
+		for path := range expected {
+			missing = append(missing, path)
+		}
+		sort.Strings(missing)
+		return nil, fmt.Errorf("bundle is missing %s", strings.Join(missing, ", "))
+	}
+	return &manifest, nil
+}
+
+func verify(message, signature []byte, trusted []ed25519.PublicKey) bool {
+	for _, key := range trusted {
+		if ed25519.Verify(key, message, signature) {
+			return true
+		}
+	}
+	return false
+}
+
+func readSmallEntry(tr *tar.Reader, name string) ([]byte, error) {
+	header, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	if header.Name != name {
+		return nil, fmt.Errorf("bundle must start with %s and %s", ManifestName, SignatureName)
+	}
+	if header.Size > maxManifestSize {
+		return nil, fmt.Errorf("%s is too large", name)
+	}
+	return io.ReadAll(io.LimitReader(tr, maxManifestSize))
+}
+
+// writeVerified는 바이너리를 쓰면서 크기와 해시를 확인합니다
+func writeVerified(path string, r io.Reader, f File) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to stage %s: %w", f.Component, err)
+	}
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, h), io.LimitReader(r, f.Size+1))
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stage %s: %w", f.Component, err)
+	}
+
+	if size != f.Size || hex.EncodeToString(h.Sum(nil)) != f.SHA256 {
+		return fmt.Errorf("checksum mismatch for %s", f.Path)
+	}
+	return nil
+}
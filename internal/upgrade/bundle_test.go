@@ -0,0 +1,138 @@
+package upgrade
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testBundle(t *testing.T, dir string) ([]byte, ed25519.PublicKey) {
+	t.Helper()
+	pubText, privText, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ParsePublicKey(pubText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv, err := ParsePrivateKey(privText)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binaries := map[string]string{}
+	for _, component := range []string{"api", "data-consumer"} {
+		path := filepath.Join(dir, component)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\necho "+component+"\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		binaries[component] = path
+	}
+
+	var buf bytes.Buffer
+	manifest, err := Create(&buf, "1.2.0", binaries, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(manifest.Components(), ","); got != "api,data-consumer" {
+		t.Fatalf("components = %s", got)
+	}
+	return buf.Bytes(), pub
+}
+
+func TestExtract(t *testing.T) {
+	dir := t.TempDir()
+	bundle, pub := testBundle(t, dir)
+
+	out := t.TempDir()
+	dest := func(component string) (string, error) { return filepath.Join(out, component+".new"), nil }
+
+	manifest, err := Extract(bytes.NewReader(bundle), []ed25519.PublicKey{pub}, dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Version != "1.2.0" || len(manifest.Files) != 2 {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "api.new"))
+	if err != nil || !strings.Contains(string(data), "echo api") {
+		t.Fatalf("api binary not extracted: %q, %v", data, err)
+	}
+	if info, _ := os.Stat(filepath.Join(out, "api.new")); info.Mode().Perm()&0100 == 0 {
+		t.Error("extracted binary is not executable")
+	}
+
+	// 다른 키로는 거부
+	otherText, _, _ := GenerateKey()
+	other, _ := ParsePublicKey(otherText)
+	if _, err := Extract(bytes.NewReader(bundle), []ed25519.PublicKey{other}, dest); !errors.Is(err, ErrUntrusted) {
+		t.Fatalf("expected ErrUntrusted, got %v", err)
+	}
+	if _, err := Extract(bytes.NewReader(bundle), nil, dest); err == nil {
+		t.Fatal("expected an error without trusted keys")
+	}
+}
+
+func TestExtractRejectsTamperedBinary(t *testing.T) {
+	dir := t.TempDir()
+	bundle, pub := testBundle(t, dir)
+
+	// 서명은 그대로 두고 바이너리 하나만 바꾼 번들
+	tampered := rewriteBundle(t, bundle, func(name string, data []byte) []byte {
+		if name == "bin/data-consumer" {
+			return bytes.Replace(data, []byte("echo"), []byte("evil"), 1)
+		}
+		return data
+	})
+
+	out := t.TempDir()
+	_, err := Extract(bytes.NewReader(tampered), []ed25519.PublicKey{pub}, func(component string) (string, error) {
+		return filepath.Join(out, component+".new"), nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected a checksum mismatch, got %v", err)
+	}
+
+	// 먼저 쓴 바이너리도 지워져야 함
+	if entries, _ := os.ReadDir(out); len(entries) != 0 {
+		t.Fatalf("staged files left behind: %v", entries)
+	}
+}
+
+func rewriteBundle(t *testing.T, bundle []byte, edit func(name string, data []byte) []byte) []byte {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(bundle))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		data = edit(header.Name, data)
+		header.Size = int64(len(data))
+		tw.WriteHeader(header)
+		tw.Write(data)
+	}
+	tw.Close()
+	gw.Close()
+	return buf.Bytes()
+}
@@ -0,0 +1,100 @@
+package usage
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+	"github.com/tmidb/tmidb-core/internal/query"
+)
+
+// filterKey는 필터 사용 횟수를 모으는 단위입니다 (조직, 카테고리, 데이터 경로, 인덱스 식 종류)
+type filterKey struct {
+	orgID    string
+	category string
+	path     string
+	kind     string
+}
+
+// FilterRecorder는 데이터 API 필터가 어떤 JSONB 경로를 얼마나 자주 거르는지 모아 filter_usage 테이블에 더합니다.
+// 인덱스 추천(db indexes advise)이 이 기록을 카테고리 스키마와 맞춰 봅니다.
+type FilterRecorder struct {
+	write func(key filterKey, calls int64, seen time.Time) error
+
+	mu      sync.Mutex
+	pending map[filterKey]int64
+}
+
+// NewFilterRecorder는 db의 filter_usage 테이블에 기록하는 FilterRecorder를 만듭니다
+func NewFilterRecorder(db *sql.DB) *FilterRecorder {
+	return newFilterRecorder(func(key filterKey, calls int64, seen time.Time) error {
+		return database.AddFilterUsage(db, key.orgID, key.category, key.path, key.kind, calls, seen)
+	})
+}
+
+func newFilterRecorder(write func(key filterKey, calls int64, seen time.Time) error) *FilterRecorder {
+	return &FilterRecorder{write: write, pending: make(map[filterKey]int64)}
+}
+
+// Record는 조회 한 번에 쓰인 필터 중 인덱스를 쓸 수 있는 조건을 셉니다
+func (r *FilterRecorder) Record(orgID, category string, q *query.Query) {
+	if r == nil || q == nil || orgID == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, cond := range q.Conditions {
+		kind, ok := cond.IndexKind()
+		if !ok {
+			continue
+		}
+		r.pending[filterKey{orgID: orgID, category: category, path: strings.Join(cond.Field.Path, "."), kind: kind}]++
+	}
+}
+
+// Flush는 모은 횟수를 DB에 더합니다. 실패한 몫은 다음 Flush에서 다시 시도합니다.
+func (r *FilterRecorder) Flush() error {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = make(map[filterKey]int64)
+	r.mu.Unlock()
+
+	now := time.Now()
+	var firstErr error
+	for key, calls := range pending {
+		if err := r.write(key, calls, now); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			r.mu.Lock()
+			r.pending[key] += calls
+			r.mu.Unlock()
+		}
+	}
+	return firstErr
+}
+
+// Start는 interval마다 Flush합니다. ctx가 끝나면 마지막으로 한 번 더 Flush하고 멈춥니다.
+func (r *FilterRecorder) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				if err := r.Flush(); err != nil {
+					log.Printf("⚠️ 필터 사용 기록 실패: %v", err)
+				}
+				return
+			case <-ticker.C:
+				if err := r.Flush(); err != nil {
+					log.Printf("⚠️ 필터 사용 기록 실패 (다음 주기에 다시 시도): %v", err)
+				}
+			}
+		}
+	}()
+}
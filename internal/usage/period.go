@@ -0,0 +1,35 @@
+package usage
+
+import (
+	"fmt"
+	"time"
+)
+
+// ParsePeriod는 사용량 보고 기간을 읽습니다. from, to는 RFC3339 시각이나 날짜(2006-01-02, UTC 자정)이고,
+// 비어 있으면 이번 달(UTC) 1일부터 지금까지입니다.
+func ParsePeriod(fromText, toText string, now time.Time) (from, to time.Time, err error) {
+	now = now.UTC()
+	from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to = now
+	if fromText != "" {
+		if from, err = parseTime(fromText); err != nil {
+			return from, to, fmt.Errorf("invalid from: %v", err)
+		}
+	}
+	if toText != "" {
+		if to, err = parseTime(toText); err != nil {
+			return from, to, fmt.Errorf("invalid to: %v", err)
+		}
+	}
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("from must be before to")
+	}
+	return from, to, nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,121 @@
+// Package usage는 조직별 API 요청 수와 수집량을 모아 과금과 용량 관리에 쓸 수 있도록 DB에 기록합니다.
+package usage
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/database"
+)
+
+// FlushInterval은 메모리에 모은 사용량을 DB에 더하는 기본 간격입니다
+const FlushInterval = 30 * time.Second
+
+// Counts는 한 조직의 한 시간 동안의 사용량입니다
+type Counts struct {
+	APIRequests     int64
+	IngestedRecords int64
+	IngestedBytes   int64
+}
+
+func (c *Counts) add(o Counts) {
+	c.APIRequests += o.APIRequests
+	c.IngestedRecords += o.IngestedRecords
+	c.IngestedBytes += o.IngestedBytes
+}
+
+// bucket은 사용량을 모으는 단위 (조직, UTC 시각의 정시)
+type bucket struct {
+	orgID string
+	hour  time.Time
+}
+
+// Recorder는 요청마다 DB에 쓰지 않도록 사용량을 메모리에 모았다가 주기적으로 시간 단위 행에 더합니다.
+// 여러 API 인스턴스와 데이터 컨슈머가 같은 행에 더하므로 값은 모든 인스턴스의 합계입니다.
+type Recorder struct {
+	write func(orgID string, hour time.Time, c Counts) error
+	now   func() time.Time
+
+	mu      sync.Mutex
+	pending map[bucket]Counts
+}
+
+// NewRecorder는 db의 org_usage 테이블에 기록하는 Recorder를 만듭니다
+func NewRecorder(db *sql.DB) *Recorder {
+	return newRecorder(func(orgID string, hour time.Time, c Counts) error {
+		return database.AddOrgUsage(db, orgID, hour, c.APIRequests, c.IngestedRecords, c.IngestedBytes)
+	})
+}
+
+func newRecorder(write func(orgID string, hour time.Time, c Counts) error) *Recorder {
+	return &Recorder{write: write, now: time.Now, pending: make(map[bucket]Counts)}
+}
+
+// AddRequests는 조직의 API 요청 수를 더합니다
+func (r *Recorder) AddRequests(orgID string, n int64) {
+	r.add(orgID, Counts{APIRequests: n})
+}
+
+// AddIngested는 조직이 저장한 레코드 수와 본문 크기를 더합니다
+func (r *Recorder) AddIngested(orgID string, records, bytes int64) {
+	r.add(orgID, Counts{IngestedRecords: records, IngestedBytes: bytes})
+}
+
+func (r *Recorder) add(orgID string, c Counts) {
+	if r == nil || orgID == "" {
+		return
+	}
+	b := bucket{orgID: orgID, hour: r.now().UTC().Truncate(time.Hour)}
+	r.mu.Lock()
+	counts := r.pending[b]
+	counts.add(c)
+	r.pending[b] = counts
+	r.mu.Unlock()
+}
+
+// Flush는 모은 사용량을 DB에 더합니다. 실패한 몫은 다음 Flush에서 다시 시도합니다.
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = make(map[bucket]Counts)
+	r.mu.Unlock()
+
+	var firstErr error
+	for b, c := range pending {
+		if err := r.write(b.orgID, b.hour, c); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			r.mu.Lock()
+			counts := r.pending[b]
+			counts.add(c)
+			r.pending[b] = counts
+			r.mu.Unlock()
+		}
+	}
+	return firstErr
+}
+
+// Start는 interval마다 Flush합니다. ctx가 끝나면 마지막으로 한 번 더 Flush하고 멈춥니다.
+func (r *Recorder) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				if err := r.Flush(); err != nil {
+					log.Printf("⚠️ 사용량 기록 실패: %v", err)
+				}
+				return
+			case <-ticker.C:
+				if err := r.Flush(); err != nil {
+					log.Printf("⚠️ 사용량 기록 실패 (다음 주기에 다시 시도): %v", err)
+				}
+			}
+		}
+	}()
+}
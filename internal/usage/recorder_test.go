@@ -0,0 +1,96 @@
+package usage
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tmidb/tmidb-core/internal/query"
+)
+
+func TestRecorderFlush(t *testing.T) {
+	written := make(map[bucket]Counts)
+	fail := true
+	r := newRecorder(func(orgID string, hour time.Time, c Counts) error {
+		if fail {
+			return errors.New("db down")
+		}
+		written[bucket{orgID, hour}] = c
+		return nil
+	})
+	now := time.Date(2026, 3, 1, 10, 59, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+
+	r.AddRequests("org-a", 1)
+	r.AddIngested("org-a", 10, 2048)
+	r.AddRequests("", 1)
+	if err := r.Flush(); err == nil {
+		t.Fatal("expected flush error")
+	}
+
+	// 실패한 몫은 다음 Flush에 합쳐짐
+	fail = false
+	r.AddRequests("org-a", 2)
+	now = now.Add(time.Minute)
+	r.AddRequests("org-a", 1)
+	if err := r.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	ten := bucket{"org-a", time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)}
+	eleven := bucket{"org-a", time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC)}
+	if got := written[ten]; got != (Counts{APIRequests: 3, IngestedRecords: 10, IngestedBytes: 2048}) {
+		t.Errorf("10:00 bucket = %+v", got)
+	}
+	if got := written[eleven]; got != (Counts{APIRequests: 1}) {
+		t.Errorf("11:00 bucket = %+v", got)
+	}
+	if len(written) != 2 {
+		t.Errorf("unexpected buckets: %v", written)
+	}
+}
+
+func TestFilterRecorder(t *testing.T) {
+	written := make(map[filterKey]int64)
+	r := newFilterRecorder(func(key filterKey, calls int64, seen time.Time) error {
+		written[key] += calls
+		return nil
+	})
+
+	q, err := query.Parse([]string{"data.vitals.bp>120", "ward=ICU", "name~kim", "updated_at>2026-01-01"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Record("org-a", "vitals", q)
+	r.Record("org-a", "vitals", q)
+	r.Record("", "vitals", q)
+	if err := r.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[filterKey]int64{
+		{"org-a", "vitals", "vitals.bp", query.IndexNumber}: 2,
+		{"org-a", "vitals", "ward", query.IndexText}:        2,
+	}
+	if !reflect.DeepEqual(written, want) {
+		t.Errorf("written = %v, want %v", written, want)
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 30, 0, 0, time.UTC)
+	from, to, err := ParsePeriod("", "", now)
+	if err != nil || !from.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(now) {
+		t.Errorf("default period = %v - %v, %v", from, to, err)
+	}
+	from, to, err = ParsePeriod("2026-02-01", "2026-03-01T00:00:00Z", now)
+	if err != nil || !from.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("explicit period = %v - %v, %v", from, to, err)
+	}
+	for _, tc := range [][2]string{{"yesterday", ""}, {"2026-03-10", "2026-03-01"}} {
+		if _, _, err := ParsePeriod(tc[0], tc[1], now); err == nil {
+			t.Errorf("ParsePeriod(%q, %q) accepted", tc[0], tc[1])
+		}
+	}
+}
@@ -0,0 +1,5 @@
+// Package version tmiDB 빌드 버전 정보
+package version
+
+// Version 빌드 시 -ldflags "-X github.com/tmidb/tmidb-core/internal/version.Version=<버전>"으로 지정
+var Version = "dev"
@@ -0,0 +1,205 @@
+// Package webhook은 조직 webhook의 이벤트 종류, 본문 서명과 재시도 간격을 정의합니다.
+// 이벤트 적재와 전송은 data-manager의 webhook 디스패처가 담당합니다.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// 이벤트 종류
+const (
+	EventTargetCreated   = "target.created"
+	EventTargetUpdated   = "target.updated"
+	EventTargetDeleted   = "target.deleted"
+	EventSchemaPublished = "schema.published"
+	EventListenerMatched = "listener.matched"
+)
+
+// Events 구독할 수 있는 이벤트 종류
+var Events = []string{EventTargetCreated, EventTargetUpdated, EventTargetDeleted, EventSchemaPublished, EventListenerMatched}
+
+// 전송 요청 헤더
+const (
+	HeaderEvent     = "X-Tmidb-Event"
+	HeaderDelivery  = "X-Tmidb-Delivery"
+	HeaderTimestamp = "X-Tmidb-Timestamp"
+	HeaderSignature = "X-Tmidb-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+// 재시도 설정
+const (
+	MaxAttempts = 8
+	BaseBackoff = 30 * time.Second
+	MaxBackoff  = time.Hour
+)
+
+// 전송 상태
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed" // 재시도를 모두 소진함
+)
+
+// Statuses 전송 기록을 거를 수 있는 상태
+var Statuses = []string{StatusPending, StatusDelivered, StatusFailed}
+
+// Envelope는 webhook으로 보내는 본문입니다.
+type Envelope struct {
+	ID         string          `json:"id"` // 이벤트 키 (재전송해도 같음, 수신자는 이 값으로 중복을 거름)
+	Event      string          `json:"event"`
+	OrgID      string          `json:"org_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// ErrBlockedAddress는 webhook이 내부망 주소를 가리킬 때의 오류입니다
+var ErrBlockedAddress = errors.New("webhook url must not point to a loopback, private, link-local or unspecified address")
+
+// blockedPrefixes 주소 분류 메서드로 거르지 못하는 내부 대역 ("this network", 통신사 NAT 공유 대역)
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// ValidateURL은 webhook 주소가 http(s) URL이고 localhost나 내부망 IP를 직접 가리키지 않는지 확인합니다.
+// 호스트 이름이 내부망 주소로 풀리는 경우는 NewClient의 연결 단계에서 거릅니다.
+func ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an http(s) url")
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return checkAddr(addr)
+	}
+	return nil
+}
+
+// checkAddr는 전송하면 안 되는 주소(루프백, 사설, 링크 로컬, ULA, 미지정, 멀티캐스트)면 오류를 반환합니다
+func checkAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+	blocked := !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast()
+	for _, prefix := range blockedPrefixes {
+		blocked = blocked || prefix.Contains(addr)
+	}
+	if blocked {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, addr)
+	}
+	return nil
+}
+
+// NewClient는 webhook과 리스너 webhook 동작을 보낼 HTTP 클라이언트를 만듭니다.
+// DNS로 푼 뒤 실제로 연결할 IP를 확인하므로 ValidateURL을 통과한 이름이 내부망 주소로 풀려도 연결하지 않고,
+// 리다이렉트는 따라가지 않아 3xx 응답은 실패로 기록됩니다. 프록시를 거치면 연결 대상을 확인할 수 없으므로
+// 환경 변수의 프록시 설정은 쓰지 않습니다.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			return checkAddr(addrPort.Addr())
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// ValidateEvents는 구독할 이벤트 종류를 확인하고 중복을 제거한 목록을 반환합니다
+func ValidateEvents(events []string) ([]string, error) {
+	if len(events) == 0 {
+		return nil, fmt.Errorf("at least one event type is required (%s)", strings.Join(Events, ", "))
+	}
+	var result []string
+	for _, e := range events {
+		if !slices.Contains(Events, e) {
+			return nil, fmt.Errorf("unknown event type %q (%s)", e, strings.Join(Events, ", "))
+		}
+		if !slices.Contains(result, e) {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+// NewSecret은 서명에 쓸 임의의 비밀값을 생성합니다
+func NewSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign은 "<timestamp>.<본문>"의 HMAC-SHA256 서명 헤더 값을 반환합니다.
+// 타임스탬프를 함께 서명하므로 수신자는 오래된 요청의 재생을 거부할 수 있습니다.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify는 수신한 요청의 서명을 확인합니다 (수신자 구현과 테스트용).
+// tolerance가 0보다 크면 그보다 오래된 타임스탬프는 거부합니다.
+func Verify(secret, timestampHeader, signature string, body []byte, tolerance time.Duration, now time.Time) error {
+	unix, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp")
+	}
+	ts := time.Unix(unix, 0)
+	if tolerance > 0 && (now.Sub(ts) > tolerance || ts.Sub(now) > tolerance) {
+		return fmt.Errorf("timestamp outside tolerance")
+	}
+	if !hmac.Equal([]byte(Sign(secret, ts, body)), []byte(signature)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// Backoff는 attempts번 실패한 뒤 다음 시도까지 기다릴 시간입니다 (30초부터 두 배씩, 최대 1시간)
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	d := BaseBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= MaxBackoff {
+			return MaxBackoff
+		}
+	}
+	return d
+}
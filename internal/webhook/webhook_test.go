@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"event":"target.created"}`)
+	sig := Sign("secret", now, body)
+
+	// 수신자가 같은 방식으로 계산할 수 있도록 형식 고정: "sha256=" + hex(HMAC-SHA256("<unix>.<body>"))
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); sig != want {
+		t.Fatalf("Sign = %q, want %q", sig, want)
+	}
+
+	ts := strconv.FormatInt(now.Unix(), 10)
+	if err := Verify("secret", ts, sig, body, 5*time.Minute, now.Add(time.Minute)); err != nil {
+		t.Errorf("valid signature rejected: %v", err)
+	}
+	if err := Verify("other", ts, sig, body, 0, now); err == nil {
+		t.Error("wrong secret accepted")
+	}
+	if err := Verify("secret", ts, sig, []byte(`{"event":"target.deleted"}`), 0, now); err == nil {
+		t.Error("modified body accepted")
+	}
+	if err := Verify("secret", ts, sig, body, 5*time.Minute, now.Add(10*time.Minute)); err == nil {
+		t.Error("stale timestamp accepted")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{4, 4 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{20, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestValidateEvents(t *testing.T) {
+	got, err := ValidateEvents([]string{EventTargetCreated, EventSchemaPublished, EventTargetCreated})
+	if err != nil || len(got) != 2 {
+		t.Errorf("ValidateEvents = %v, %v", got, err)
+	}
+	if _, err := ValidateEvents(nil); err == nil {
+		t.Error("empty event list accepted")
+	}
+	if _, err := ValidateEvents([]string{"target.*"}); err == nil {
+		t.Error("unknown event accepted")
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		blocked bool
+	}{
+		{"https://hooks.example.com/tmidb", false},
+		{"http://203.0.113.10:8080/hook", false},
+		{"http://localhost:9000/hook", true},
+		{"http://api.localhost/hook", true},
+		{"http://127.0.0.1/hook", true},
+		{"http://10.0.0.5/hook", true},
+		{"http://172.16.3.4/hook", true},
+		{"http://192.168.1.1/hook", true},
+		{"http://169.254.169.254/latest/meta-data", true},
+		{"http://100.100.100.200/", true},
+		{"http://0.0.0.0:8080/", true},
+		{"http://[::1]/hook", true},
+		{"http://[fd00::1]/hook", true},
+		{"http://[fe80::1]/hook", true},
+		{"http://[::ffff:127.0.0.1]/hook", true},
+	}
+	for _, tt := range tests {
+		err := ValidateURL(tt.url)
+		if blocked := errors.Is(err, ErrBlockedAddress); blocked != tt.blocked || (!tt.blocked && err != nil) {
+			t.Errorf("ValidateURL(%s) = %v, want blocked %t", tt.url, err, tt.blocked)
+		}
+	}
+	if err := ValidateURL("ftp://example.com"); err == nil {
+		t.Error("ftp url accepted")
+	}
+}
+
+func TestNewClientBlocksInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback server")
+	}))
+	defer server.Close()
+
+	// 클라이언트는 ValidateURL과 별개로 연결할 IP를 확인함 (내부망으로 풀리는 이름도 같은 단계에서 거부)
+	client := NewClient(5 * time.Second)
+	if _, err := client.Post(server.URL, "application/json", nil); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("POST %s: %v, want ErrBlockedAddress", server.URL, err)
+	}
+
+	if client.CheckRedirect(nil, nil) != http.ErrUseLastResponse {
+		t.Error("redirects are followed")
+	}
+}